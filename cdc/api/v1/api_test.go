@@ -90,6 +90,20 @@ func (p *mockStatusProvider) GetAllTaskStatuses(ctx context.Context, changefeedI
 	return args.Get(0).(map[model.CaptureID]*model.TaskStatus), args.Error(1)
 }
 
+func (p *mockStatusProvider) GetTableProgresses(ctx context.Context, changefeedID model.ChangeFeedID) (
+	[]*model.TableProgress, error,
+) {
+	args := p.Called(ctx)
+	return args.Get(0).([]*model.TableProgress), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetSkippedDDLs(ctx context.Context, changefeedID model.ChangeFeedID) (
+	[]*model.SkippedDDL, error,
+) {
+	args := p.Called(ctx)
+	return args.Get(0).([]*model.SkippedDDL), args.Error(1)
+}
+
 func (p *mockStatusProvider) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
 	args := p.Called(ctx)
 	return args.Get(0).([]*model.ProcInfoSnap), args.Error(1)
@@ -15,7 +15,6 @@ package v1
 
 import (
 	"context"
-	"net/url"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -29,6 +28,8 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/transformer"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -100,6 +101,7 @@ func verifyCreateChangefeedConfig(
 	if changefeedConfig.SinkConfig != nil {
 		replicaConfig.Sink = changefeedConfig.SinkConfig
 	}
+	replicaConfig.Sink.ResolveKafkaSinkV2()
 	if len(changefeedConfig.IgnoreTxnStartTs) != 0 {
 		replicaConfig.Filter.IgnoreTxnStartTs = changefeedConfig.IgnoreTxnStartTs
 	}
@@ -107,7 +109,7 @@ func verifyCreateChangefeedConfig(
 		replicaConfig.Filter.Rules = changefeedConfig.FilterRules
 	}
 	// verify replicaConfig
-	sinkURIParsed, err := url.Parse(changefeedConfig.SinkURI)
+	sinkURIParsed, err := sink.ParseSinkURI(changefeedConfig.SinkURI)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
@@ -115,6 +117,9 @@ func verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, err
 	}
+	if err := transformer.ResolveChecksum(replicaConfig); err != nil {
+		return nil, err
+	}
 
 	captureInfos, err := ctrl.GetCaptures(ctx)
 	if err != nil {
@@ -237,6 +242,9 @@ func VerifyUpdateChangefeedConfig(ctx context.Context,
 			newInfo.SinkURI, newInfo.Config); err != nil {
 			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
 		}
+		if err := transformer.ResolveChecksum(newInfo.Config); err != nil {
+			return nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
 	}
 
 	if !diff.Changed(oldInfo, newInfo) {
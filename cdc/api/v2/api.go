@@ -62,7 +62,21 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.GET("/:changefeed_id/meta_info", changefeedOwnerMiddleware, api.getChangeFeedMetaInfo)
 	changefeedGroup.POST("/:changefeed_id/resume", changefeedOwnerMiddleware, api.resumeChangefeed)
 	changefeedGroup.POST("/:changefeed_id/pause", changefeedOwnerMiddleware, api.pauseChangefeed)
+	changefeedGroup.POST("/:changefeed_id/credentials", changefeedOwnerMiddleware, api.rotateCredentials)
+	changefeedGroup.POST("/:changefeed_id/cutover", changefeedOwnerMiddleware, api.cutOverSink)
+	changefeedGroup.GET("/:changefeed_id/tables", changefeedOwnerMiddleware, api.listTableProgresses)
+	changefeedGroup.POST("/:changefeed_id/tables/:table_id/pause", changefeedOwnerMiddleware, api.pauseTable)
+	changefeedGroup.POST("/:changefeed_id/tables/:table_id/resume", changefeedOwnerMiddleware, api.resumeTable)
 	changefeedGroup.GET("/:changefeed_id/status", changefeedOwnerMiddleware, api.status)
+	changefeedGroup.GET("/:changefeed_id/skipped_ddls", changefeedOwnerMiddleware, api.listSkippedDDLs)
+	changefeedGroup.POST("/:changefeed_id/savepoints", changefeedOwnerMiddleware, api.createSavepoint)
+
+	// savepoint apis
+	savepointGroup := v2.Group("/savepoints")
+	savepointGroup.Use(controllerMiddleware)
+	savepointGroup.GET("", api.listSavepoints)
+	savepointGroup.GET("/:savepoint_name", api.getSavepoint)
+	savepointGroup.DELETE("/:savepoint_name", api.deleteSavepoint)
 
 	// capture apis
 	captureGroup := v2.Group("/captures")
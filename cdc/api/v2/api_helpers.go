@@ -16,7 +16,6 @@ package v2
 import (
 	"context"
 	"crypto/tls"
-	"net/url"
 	"strings"
 	"time"
 
@@ -34,6 +33,8 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/transformer"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/r3labs/diff"
@@ -66,6 +67,17 @@ type APIV2Helpers interface {
 		kvStorage tidbkv.Storage,
 	) (*model.ChangeFeedInfo, error)
 
+	// dryRunCreateChangefeedConfig runs the checks of verifyCreateChangefeedConfig
+	// without any side effects, and returns the report of the checks
+	dryRunCreateChangefeedConfig(
+		ctx context.Context,
+		cfg *ChangefeedConfig,
+		pdClient pd.Client,
+		ctrl controller.Controller,
+		ensureGCServiceID string,
+		kvStorage tidbkv.Storage,
+	) *ChangefeedDryRunReport
+
 	// verifyUpdateChangefeedConfig verifies the changefeed update config,
 	// and returns a pair of valid changefeedInfo & upstreamInfo
 	verifyUpdateChangefeedConfig(
@@ -132,9 +144,111 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	ensureGCServiceID string,
 	kvStorage tidbkv.Storage,
 ) (*model.ChangeFeedInfo, error) {
+	if err := verifyChangefeedIdentity(ctx, cfg, ctrl); err != nil {
+		return nil, err
+	}
+	if err := completeChangefeedStartTs(ctx, cfg, pdClient); err != nil {
+		return nil, err
+	}
+	if err := ensureChangefeedStartTsSafety(ctx, cfg, pdClient, ensureGCServiceID); err != nil {
+		return nil, err
+	}
+	replicaCfg, err := verifyChangefeedReplicaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := verifyChangefeedTables(cfg, replicaCfg, kvStorage); err != nil {
+		return nil, err
+	}
+
+	// verify sink
+	if err := validator.Validate(ctx,
+		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
+		cfg.SinkURI, replicaCfg); err != nil {
+		return nil, err
+	}
+
+	return &model.ChangeFeedInfo{
+		UpstreamID:     pdClient.GetClusterID(ctx),
+		Namespace:      cfg.Namespace,
+		ID:             cfg.ID,
+		SinkURI:        cfg.SinkURI,
+		CreateTime:     time.Now(),
+		StartTs:        cfg.StartTs,
+		TargetTs:       cfg.TargetTs,
+		Config:         replicaCfg,
+		State:          model.StateNormal,
+		CreatorVersion: version.ReleaseVersion,
+		Epoch:          owner.GenerateChangefeedEpoch(ctx, pdClient),
+	}, nil
+}
+
+// dryRunCreateChangefeedConfig runs the same checks as verifyCreateChangefeedConfig,
+// but it doesn't stop at the first failed check, the results of all checks are
+// returned in the report. The service GC safepoint is removed after it's checked.
+func (APIV2HelpersImpl) dryRunCreateChangefeedConfig(
+	ctx context.Context,
+	cfg *ChangefeedConfig,
+	pdClient pd.Client,
+	ctrl controller.Controller,
+	ensureGCServiceID string,
+	kvStorage tidbkv.Storage,
+) *ChangefeedDryRunReport {
+	report := &ChangefeedDryRunReport{Passed: true}
+	err := verifyChangefeedIdentity(ctx, cfg, ctrl)
+	if err == nil {
+		err = completeChangefeedStartTs(ctx, cfg, pdClient)
+	}
+	var replicaCfg *config.ReplicaConfig
+	if err == nil {
+		replicaCfg, err = verifyChangefeedReplicaConfig(cfg)
+	}
+	report.Namespace = cfg.Namespace
+	report.ID = cfg.ID
+	report.StartTs = cfg.StartTs
+	report.TargetTs = cfg.TargetTs
+	report.addCheck(DryRunCheckChangefeedConfig, err)
+	if err != nil {
+		report.skipChecks(DryRunCheckGCSafepoint, DryRunCheckTables, DryRunCheckSink)
+		return report
+	}
+
+	changefeedID := model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID}
+	err = ensureChangefeedStartTsSafety(ctx, cfg, pdClient, ensureGCServiceID)
+	report.addCheck(DryRunCheckGCSafepoint, err)
+	if err == nil {
+		if err := gc.UndoEnsureChangefeedStartTsSafety(
+			ctx, pdClient, ensureGCServiceID, changefeedID); err != nil {
+			log.Warn("failed to remove the service GC safepoint of the dry-run changefeed",
+				zap.String("namespace", cfg.Namespace),
+				zap.String("changefeed", cfg.ID),
+				zap.Error(err))
+		}
+	}
+
+	tables, err := verifyChangefeedTables(cfg, replicaCfg, kvStorage)
+	if tables != nil {
+		report.IneligibleTables = toAPITableNames(tables.ineligible)
+		report.EligibleTables = toAPITableNames(tables.eligible)
+	}
+	report.addCheck(DryRunCheckTables, err)
+
+	// the sink is created and closed by the validator, so the connectivity,
+	// the permissions of the topics or the buckets and the schema registry
+	// are checked without starting the replication.
+	err = validator.Validate(ctx, changefeedID, cfg.SinkURI, replicaCfg)
+	report.addCheck(DryRunCheckSink, err)
+	return report
+}
+
+// verifyChangefeedIdentity verifies the sink URI, the ID and the namespace
+// of the changefeed to be created, the changefeed must not exist.
+func verifyChangefeedIdentity(
+	ctx context.Context, cfg *ChangefeedConfig, ctrl controller.Controller,
+) error {
 	// verify sinkURI
 	if cfg.SinkURI == "" {
-		return nil, cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(
 			"sink_uri is empty, cannot create a changefeed without sink_uri")
 	}
 
@@ -143,7 +257,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		cfg.ID = uuid.New().String()
 	}
 	if err := model.ValidateChangefeedID(cfg.ID); err != nil {
-		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+		return cerror.ErrAPIInvalidParam.GenWithStack(
 			"invalid changefeed_id: %s", cfg.ID)
 	}
 	if cfg.Namespace == "" {
@@ -151,30 +265,42 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	}
 
 	if err := model.ValidateNamespace(cfg.Namespace); err != nil {
-		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+		return cerror.ErrAPIInvalidParam.GenWithStack(
 			"invalid namespace: %s", cfg.Namespace)
 	}
 
 	exists, err := ctrl.IsChangefeedExists(ctx,
 		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID})
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
-		return nil, err
+		return err
 	}
 	if exists {
-		return nil, cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(cfg.ID)
+		return cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(cfg.ID)
 	}
+	return nil
+}
 
-	// verify start ts
+// completeChangefeedStartTs sets the start ts to the current ts if it's not set.
+func completeChangefeedStartTs(
+	ctx context.Context, cfg *ChangefeedConfig, pdClient pd.Client,
+) error {
 	if cfg.StartTs == 0 {
 		ts, logical, err := pdClient.GetTS(ctx)
 		if err != nil {
-			return nil, cerror.ErrPDEtcdAPIError.GenWithStackByArgs(
+			return cerror.ErrPDEtcdAPIError.GenWithStackByArgs(
 				"fail to get ts from pd client")
 		}
 		cfg.StartTs = oracle.ComposeTS(ts, logical)
 	}
+	return nil
+}
 
-	// Ensure the start ts is valid in the next 3600 seconds, aka 1 hour
+// ensureChangefeedStartTsSafety ensures the start ts is valid in the next
+// 3600 seconds, aka 1 hour.
+func ensureChangefeedStartTsSafety(
+	ctx context.Context, cfg *ChangefeedConfig,
+	pdClient pd.Client, ensureGCServiceID string,
+) error {
 	const ensureTTL = 60 * 60
 	if err := gc.EnsureChangefeedStartTsSafety(
 		ctx,
@@ -183,11 +309,16 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
 		ensureTTL, cfg.StartTs); err != nil {
 		if !cerror.ErrStartTsBeforeGC.Equal(err) {
-			return nil, cerror.ErrPDEtcdAPIError.Wrap(err)
+			return cerror.ErrPDEtcdAPIError.Wrap(err)
 		}
-		return nil, err
+		return err
 	}
+	return nil
+}
 
+// verifyChangefeedReplicaConfig verifies the target ts and the replica config,
+// and returns the internal replica config.
+func verifyChangefeedReplicaConfig(cfg *ChangefeedConfig) (*config.ReplicaConfig, error) {
 	// verify target ts
 	if cfg.TargetTs > 0 && cfg.TargetTs <= cfg.StartTs {
 		return nil, cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(
@@ -196,9 +327,10 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 
 	// fill replicaConfig
 	replicaCfg := cfg.ReplicaConfig.ToInternalReplicaConfig()
+	replicaCfg.Sink.ResolveKafkaSinkV2()
 
 	// verify replicaConfig
-	sinkURIParsed, err := url.Parse(cfg.SinkURI)
+	sinkURIParsed, err := sink.ParseSinkURI(cfg.SinkURI)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
@@ -206,48 +338,45 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, err
 	}
+	// pin the WASM module of the transformer, so that all the captures run
+	// the same one.
+	if err := transformer.ResolveChecksum(replicaCfg); err != nil {
+		return nil, err
+	}
+	return replicaCfg, nil
+}
+
+// verifiedTables is the tables which are verified by the filter rules.
+type verifiedTables struct {
+	ineligible []model.TableName
+	eligible   []model.TableName
+}
 
+// verifyChangefeedTables verifies the filter rules against the schemas at the
+// start ts, the ineligible tables are rejected unless they're forced to be
+// replicated or ignored.
+func verifyChangefeedTables(
+	cfg *ChangefeedConfig, replicaCfg *config.ReplicaConfig, kvStorage tidbkv.Storage,
+) (*verifiedTables, error) {
 	f, err := filter.NewFilter(replicaCfg, "")
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
-	tableInfos, ineligibleTables, _, err := entry.VerifyTables(f, kvStorage, cfg.StartTs)
+	tableInfos, ineligibleTables, eligibleTables, err := entry.VerifyTables(f, kvStorage, cfg.StartTs)
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
+	tables := &verifiedTables{ineligible: ineligibleTables, eligible: eligibleTables}
 	err = f.Verify(tableInfos)
 	if err != nil {
-		return nil, errors.Cause(err)
+		return tables, errors.Cause(err)
 	}
 	if !replicaCfg.ForceReplicate && !cfg.ReplicaConfig.IgnoreIneligibleTable {
-		if err != nil {
-			return nil, err
-		}
 		if len(ineligibleTables) != 0 {
-			return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
+			return tables, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
 		}
 	}
-
-	// verify sink
-	if err := validator.Validate(ctx,
-		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
-		cfg.SinkURI, replicaCfg); err != nil {
-		return nil, err
-	}
-
-	return &model.ChangeFeedInfo{
-		UpstreamID:     pdClient.GetClusterID(ctx),
-		Namespace:      cfg.Namespace,
-		ID:             cfg.ID,
-		SinkURI:        cfg.SinkURI,
-		CreateTime:     time.Now(),
-		StartTs:        cfg.StartTs,
-		TargetTs:       cfg.TargetTs,
-		Config:         replicaCfg,
-		State:          model.StateNormal,
-		CreatorVersion: version.ReleaseVersion,
-		Epoch:          owner.GenerateChangefeedEpoch(ctx, pdClient),
-	}, nil
+	return tables, nil
 }
 
 // verifyUpstream verifies the upstream config before updating a changefeed
@@ -307,6 +436,11 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 	if cfg.ReplicaConfig != nil {
 		configUpdated = true
 		newInfo.Config = cfg.ReplicaConfig.ToInternalReplicaConfig()
+		// the kafka sink is kept if it's not set, instead of being resolved
+		// by the default of this server again.
+		if newInfo.Config.Sink.EnableKafkaSinkV2 == nil && oldInfo.Config.Sink != nil {
+			newInfo.Config.Sink.EnableKafkaSinkV2 = oldInfo.Config.Sink.EnableKafkaSinkV2
+		}
 	}
 	if cfg.SinkURI != "" {
 		sinkURIUpdated = true
@@ -346,7 +480,7 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		if sinkURIUpdated {
 			sinkURI = newInfo.SinkURI
 		}
-		sinkURIParsed, err := url.Parse(sinkURI)
+		sinkURIParsed, err := sink.ParseSinkURI(sinkURI)
 		if err != nil {
 			return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
 		}
@@ -354,6 +488,9 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		if err != nil {
 			return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
 		}
+		if err := transformer.ResolveChecksum(newInfo.Config); err != nil {
+			return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+		}
 
 		if err := validator.Validate(ctx,
 			model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
@@ -57,6 +57,20 @@ func (mr *MockAPIV2HelpersMockRecorder) createTiStore(pdAddrs, credential interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createTiStore", reflect.TypeOf((*MockAPIV2Helpers)(nil).createTiStore), pdAddrs, credential)
 }
 
+// dryRunCreateChangefeedConfig mocks base method.
+func (m *MockAPIV2Helpers) dryRunCreateChangefeedConfig(ctx context.Context, cfg *ChangefeedConfig, pdClient client.Client, ctrl controller.Controller, ensureGCServiceID string, kvStorage kv.Storage) *ChangefeedDryRunReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "dryRunCreateChangefeedConfig", ctx, cfg, pdClient, ctrl, ensureGCServiceID, kvStorage)
+	ret0, _ := ret[0].(*ChangefeedDryRunReport)
+	return ret0
+}
+
+// dryRunCreateChangefeedConfig indicates an expected call of dryRunCreateChangefeedConfig.
+func (mr *MockAPIV2HelpersMockRecorder) dryRunCreateChangefeedConfig(ctx, cfg, pdClient, ctrl, ensureGCServiceID, kvStorage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "dryRunCreateChangefeedConfig", reflect.TypeOf((*MockAPIV2Helpers)(nil).dryRunCreateChangefeedConfig), ctx, cfg, pdClient, ctrl, ensureGCServiceID, kvStorage)
+}
+
 // getEtcdClient mocks base method.
 func (m *MockAPIV2Helpers) getEtcdClient(pdAddrs []string, tlsConfig *tls.Config) (*v3.Client, error) {
 	m.ctrl.T.Helper()
@@ -15,6 +15,9 @@ package v2
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,6 +29,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestVerifyCreateChangefeedConfig(t *testing.T) {
@@ -65,6 +69,8 @@ func TestVerifyCreateChangefeedConfig(t *testing.T) {
 	require.NotEqual(t, "", cfInfo.ID)
 	require.Equal(t, model.DefaultNamespace, cfInfo.Namespace)
 	require.NotEqual(t, 0, cfInfo.Epoch)
+	// the kafka sink is resolved by the default of the server.
+	require.NotNil(t, cfInfo.Config.Sink.EnableKafkaSinkV2)
 
 	// invalid changefeed id or namespace id
 	cfg.ID = "abdc/sss"
@@ -116,6 +122,91 @@ func TestVerifyCreateChangefeedConfig(t *testing.T) {
 	require.Error(t, cerror.ErrOldValueNotEnabled, err)
 }
 
+func TestVerifyChangefeedReplicaConfigTransformer(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "transformer.wasm")
+	require.NoError(t, os.WriteFile(file, []byte("module"), 0o600))
+	cfg := &ChangefeedConfig{SinkURI: "blackhole://", ReplicaConfig: GetDefaultReplicaConfig()}
+	cfg.ReplicaConfig.Sink.Transformer = &TransformerConfig{WASMFile: file}
+
+	// the module is pinned by its checksum.
+	replicaCfg, err := verifyChangefeedReplicaConfig(cfg)
+	require.NoError(t, err)
+	checksum := replicaCfg.Sink.Transformer.WASMChecksum
+	require.Len(t, checksum, 64)
+
+	// the module doesn't match the checksum given by the user.
+	cfg.ReplicaConfig.Sink.Transformer.WASMChecksum = strings.Repeat("0", 64)
+	_, err = verifyChangefeedReplicaConfig(cfg)
+	require.ErrorContains(t, err, "doesn't match the wasm-sha256")
+	cfg.ReplicaConfig.Sink.Transformer.WASMChecksum = checksum
+	_, err = verifyChangefeedReplicaConfig(cfg)
+	require.NoError(t, err)
+
+	// the module doesn't exist on the server.
+	cfg.ReplicaConfig.Sink.Transformer.WASMFile = filepath.Join(t.TempDir(), "missing.wasm")
+	_, err = verifyChangefeedReplicaConfig(cfg)
+	require.ErrorContains(t, err, "ErrTransformerFailed")
+}
+
+func TestDryRunCreateChangefeedConfig(t *testing.T) {
+	ctx := context.Background()
+	pdClient := &mockPDClient{}
+	helper := entry.NewSchemaTestHelper(t)
+	helper.Tk().MustExec("use test;")
+	helper.Tk().MustExec("create table t1(id int)")
+	storage := helper.Storage()
+	ctrl := mock_controller.NewMockController(gomock.NewController(t))
+	h := &APIV2HelpersImpl{}
+
+	// the other checks are skipped if the changefeed config is invalid.
+	cfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
+	report := h.dryRunCreateChangefeedConfig(ctx, cfg, pdClient, ctrl, "en", storage)
+	require.False(t, report.Passed)
+	require.Len(t, report.Checks, 4)
+	require.Equal(t, DryRunCheckChangefeedConfig, report.Checks[0].Name)
+	require.Equal(t, DryRunCheckFailed, report.Checks[0].Status)
+	require.Contains(t, report.Checks[0].Error, "sink_uri is empty")
+	for _, check := range report.Checks[1:] {
+		require.Equal(t, DryRunCheckSkipped, check.Status)
+	}
+
+	// the failed checks don't stop the others, the start ts is before the
+	// GC safepoint of the mock PD client, and the table is ineligible.
+	ver, err := storage.CurrentVersion(oracle.GlobalTxnScope)
+	require.NoError(t, err)
+	cfg.SinkURI = "blackhole://"
+	cfg.StartTs = ver.Ver
+	ctrl.EXPECT().IsChangefeedExists(gomock.Any(), gomock.Any()).Return(false, nil)
+	report = h.dryRunCreateChangefeedConfig(ctx, cfg, pdClient, ctrl, "en", storage)
+	require.False(t, report.Passed)
+	require.NotEqual(t, "", report.ID)
+	require.Equal(t, ver.Ver, report.StartTs)
+	require.Len(t, report.Checks, 4)
+	require.Equal(t, DryRunCheck{
+		Name: DryRunCheckChangefeedConfig, Status: DryRunCheckPassed,
+	}, report.Checks[0])
+	require.Equal(t, DryRunCheckGCSafepoint, report.Checks[1].Name)
+	require.Equal(t, DryRunCheckFailed, report.Checks[1].Status)
+	require.Contains(t, report.Checks[1].Error, "ErrStartTsBeforeGC")
+	require.Equal(t, DryRunCheckTables, report.Checks[2].Name)
+	require.Equal(t, DryRunCheckFailed, report.Checks[2].Status)
+	require.Contains(t, report.Checks[2].Error, "ErrTableIneligible")
+	require.Equal(t, DryRunCheck{
+		Name: DryRunCheckSink, Status: DryRunCheckPassed,
+	}, report.Checks[3])
+	require.Len(t, report.IneligibleTables, 1)
+	require.Equal(t, "t1", report.IneligibleTables[0].Table)
+
+	cfg.StartTs = 0
+	cfg.ReplicaConfig.IgnoreIneligibleTable = true
+	ctrl.EXPECT().IsChangefeedExists(gomock.Any(), gomock.Any()).Return(false, nil)
+	report = h.dryRunCreateChangefeedConfig(ctx, cfg, pdClient, ctrl, "en", storage)
+	require.True(t, report.Passed)
+	require.Len(t, report.Checks, 4)
+}
+
 func TestVerifyUpdateChangefeedConfig(t *testing.T) {
 	ctx := context.Background()
 	cfg := &ChangefeedConfig{}
@@ -177,4 +268,12 @@ func TestVerifyUpdateChangefeedConfig(t *testing.T) {
 	cfg.ReplicaConfig.ForceReplicate = true
 	newCfInfo, newUpInfo, err = h.verifyUpdateChangefeedConfig(ctx, cfg, oldInfo, oldUpInfo, storage, 0)
 	require.Error(t, cerror.ErrOldValueNotEnabled, err)
+
+	// the kafka sink of the changefeed is kept if it's not set.
+	cfg.ReplicaConfig.ForceReplicate = false
+	cfg.ReplicaConfig.Sink.EnableKafkaSinkV2 = nil
+	oldInfo.Config.Sink.EnableKafkaSinkV2 = util.AddressOf(true)
+	newCfInfo, _, err = h.verifyUpdateChangefeedConfig(ctx, cfg, oldInfo, oldUpInfo, storage, 0)
+	require.NoError(t, err)
+	require.True(t, newCfInfo.Config.Sink.KafkaSinkV2Enabled())
 }
@@ -66,6 +66,8 @@ type mockStatusProvider struct {
 	changefeedInfo     *model.ChangeFeedInfo
 	processors         []*model.ProcInfoSnap
 	taskStatus         map[model.CaptureID]*model.TaskStatus
+	tableProgresses    []*model.TableProgress
+	skippedDDLs        []*model.SkippedDDL
 	changefeedInfos    map[model.ChangeFeedID]*model.ChangeFeedInfo
 	changefeedStatuses map[model.ChangeFeedID]*model.ChangeFeedStatusForAPI
 	err                error
@@ -104,6 +106,22 @@ func (m *mockStatusProvider) GetAllTaskStatuses(
 	return m.taskStatus, m.err
 }
 
+// GetTableProgresses returns a list of mock table progresses.
+func (m *mockStatusProvider) GetTableProgresses(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableProgress, error) {
+	return m.tableProgresses, m.err
+}
+
+// GetSkippedDDLs returns a list of mock skipped DDLs.
+func (m *mockStatusProvider) GetSkippedDDLs(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.SkippedDDL, error) {
+	return m.skippedDDLs, m.err
+}
+
 // GetAllChangeFeedInfo returns a list of mock changefeed info.
 func (m *mockStatusProvider) GetAllChangeFeedInfo(_ context.Context) (
 	map[model.ChangeFeedID]*model.ChangeFeedInfo,
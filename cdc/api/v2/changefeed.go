@@ -15,12 +15,16 @@ package v2
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/api"
@@ -44,10 +48,16 @@ const (
 	apiOpVarChangefeedID = "changefeed_id"
 	// apiOpVarNamespace is the key of changefeed namespace in HTTP API
 	apiOpVarNamespace = "namespace"
+	// apiOpVarDryRun is the key of the dry-run mode of changefeed creation in HTTP API
+	apiOpVarDryRun = "dry_run"
+	// apiOpVarTableID is the key of table ID in HTTP API
+	apiOpVarTableID = "table_id"
 )
 
 // createChangefeed handles create changefeed request,
-// it returns the changefeed's changefeedInfo that it just created
+// it returns the changefeed's changefeedInfo that it just created.
+// In the dry-run mode, the changefeed is validated but not created,
+// and the report of the checks is returned.
 // CreateChangefeed creates a changefeed
 // @Summary Create changefeed
 // @Description create a new changefeed
@@ -55,6 +65,7 @@ const (
 // @Accept json
 // @Produce json
 // @Param changefeed body ChangefeedConfig true "changefeed config"
+// @Param dry_run query bool false "validate the changefeed without creating it"
 // @Success 200 {object} ChangeFeedInfo
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v2/changefeeds [post]
@@ -62,10 +73,21 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 	cfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
 
-	if err := c.BindJSON(&cfg); err != nil {
+	// the body is kept in the context, it's bound again if the changefeed
+	// is created from a savepoint.
+	if err := c.ShouldBindBodyWith(&cfg, binding.JSON); err != nil {
 		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
 		return
 	}
+	dryRun := false
+	if value := c.Query(apiOpVarDryRun); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid dry_run: %s", value))
+			return
+		}
+	}
 	if len(cfg.PDAddrs) == 0 {
 		up, err := getCaptureDefaultUpstream(h.capture)
 		if err != nil {
@@ -85,6 +107,13 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	}
 	defer pdClient.Close()
 
+	if cfg.Savepoint != "" {
+		if cfg, err = h.applySavepoint(c, cfg, pdClient); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
 	// verify tables todo: del kvstore
 	kvStorage, err := h.helpers.createTiStore(cfg.PDAddrs, credential)
 	if err != nil {
@@ -97,6 +126,23 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		report := h.helpers.dryRunCreateChangefeedConfig(
+			ctx,
+			cfg,
+			pdClient,
+			ctrl,
+			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
+			kvStorage)
+		report.addCheck(DryRunCheckRunningImport, h.checkRunningImport(ctx, cfg, credential))
+		log.Info("Dry run changefeed creation",
+			zap.String("namespace", report.Namespace),
+			zap.String("changefeed", report.ID),
+			zap.Bool("passed", report.Passed))
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
 	// We should not close kvStorage since all kvStorage in cdc is the same one.
 	// defer kvStorage.Close()
 	// TODO: We should get a kvStorage from upstream instead of creating a new one
@@ -182,6 +228,22 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		nil, true))
 }
 
+// checkRunningImport checks if there is running import tasks on the
+// upstream cluster of the changefeed.
+func (h *OpenAPIV2) checkRunningImport(
+	ctx context.Context, cfg *ChangefeedConfig, credential *security.Credential,
+) error {
+	tlsCfg, err := credential.ToTLSConfig()
+	if err != nil {
+		return err
+	}
+	cli, err := h.helpers.getEtcdClient(cfg.PDAddrs, tlsCfg)
+	if err != nil {
+		return err
+	}
+	return hasRunningImport(ctx, cli)
+}
+
 // hasRunningImport checks if there is running import tasks on the
 // upstream cluster.
 func hasRunningImport(ctx context.Context, cli *clientv3.Client) error {
@@ -352,21 +414,9 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	toAPIModelFunc := func(tbls []model.TableName) []TableName {
-		var apiModles []TableName
-		for _, tbl := range tbls {
-			apiModles = append(apiModles, TableName{
-				Schema:      tbl.Schema,
-				Table:       tbl.Table,
-				TableID:     tbl.TableID,
-				IsPartition: tbl.IsPartition,
-			})
-		}
-		return apiModles
-	}
 	tables := &Tables{
-		IneligibleTables: toAPIModelFunc(ineligibleTables),
-		EligibleTables:   toAPIModelFunc(eligibleTables),
+		IneligibleTables: toAPITableNames(ineligibleTables),
+		EligibleTables:   toAPITableNames(eligibleTables),
 	}
 	c.JSON(http.StatusOK, tables)
 }
@@ -376,6 +426,7 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 // Can only update a changefeed's: TargetTs, SinkURI,
 // ReplicaConfig, PDAddrs, CAPath, CertPath, KeyPath,
 // SyncPointEnabled, SyncPointInterval
+// Only the dispatchers can be updated while the changefeed is running.
 // UpdateChangefeed updates a changefeed
 // @Summary Update a changefeed
 // @Description Update a changefeed
@@ -405,8 +456,14 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 		return
 	}
 
+	// the dispatchers of a running changefeed can be updated without
+	// restarting it, the sinks rebuild their routers once they see the
+	// updated changefeed info.
+	running := false
 	switch oldCfInfo.State {
 	case model.StateStopped, model.StateFailed:
+	case model.StateNormal, model.StateWarning:
+		running = true
 	default:
 		_ = c.Error(
 			cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
@@ -473,17 +530,55 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 		return
 	}
 
+	upstreamChanged := !reflect.DeepEqual(newUpInfo, OldUpInfo)
+	if running && (!newCfInfo.OnlyDispatchRulesChanged(oldCfInfo) || upstreamChanged) {
+		_ = c.Error(
+			cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+				"can only update changefeed config when it is stopped or failed, " +
+					"only the dispatchers can be updated when it is running",
+			),
+		)
+		return
+	}
+
 	log.Info("New ChangeFeed and Upstream Info",
 		zap.String("changefeedInfo", newCfInfo.String()),
 		zap.Any("upstreamInfo", newUpInfo))
 
-	err = h.capture.GetEtcdClient().
-		UpdateChangefeedAndUpstream(ctx, newUpInfo, newCfInfo, changefeedID)
+	// the changes made by the owner since the old changefeed info is read are
+	// kept, and the update is refused if the changefeed is resumed meanwhile.
+	updatedCfInfo := newCfInfo
+	err = h.capture.GetEtcdClient().UpdateChangefeedAndUpstream(ctx, newUpInfo, changefeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			updated, err := newCfInfo.Clone()
+			if err != nil {
+				return nil, false, errors.Trace(err)
+			}
+			updated.State = info.State
+			updated.Error = info.Error
+			updated.Warning = info.Warning
+			updated.AdminJobType = info.AdminJobType
+			updated.Epoch = info.Epoch
+			updated.PausedTables = info.PausedTables
+			switch info.State {
+			case model.StateStopped, model.StateFailed:
+			case model.StateNormal, model.StateWarning:
+				if !updated.OnlyDispatchRulesChanged(info) || upstreamChanged {
+					return nil, false, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+						"the changefeed is changed during the update, please retry")
+				}
+			default:
+				return nil, false, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+					"can only update changefeed config when it is stopped or failed")
+			}
+			updatedCfInfo = updated
+			return updated, true, nil
+		})
 	if err != nil {
 		_ = c.Error(errors.Trace(err))
 		return
 	}
-	c.JSON(http.StatusOK, toAPIModel(newCfInfo,
+	c.JSON(http.StatusOK, toAPIModel(updatedCfInfo,
 		cfStatus.ResolvedTs, cfStatus.CheckpointTs, nil, true))
 }
 
@@ -821,6 +916,291 @@ func (h *OpenAPIV2) pauseChangefeed(c *gin.Context) {
 	c.JSON(http.StatusOK, &EmptyResponse{})
 }
 
+// rotateCredentials handles rotate changefeed credentials request.
+// @Summary Rotate the credentials of a changefeed
+// @Description Rotate the sink credentials of a changefeed without restarting it
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Param credentials body ChangefeedCredentials true "sink credentials"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/credentials [post]
+func (h *OpenAPIV2) rotateCredentials(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	credentials := &ChangefeedCredentials{}
+	if err := c.BindJSON(credentials); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+
+	if _, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// the running processors and the owner recreate their sinks once they
+	// see the changefeed info with the new credentials.
+	err := h.capture.GetEtcdClient().UpdateChangeFeedInfo(ctx, changefeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			info.Namespace = changefeedID.Namespace
+			info.ID = changefeedID.ID
+			if err := credentials.applyTo(info); err != nil {
+				return nil, false, err
+			}
+			return info, true, nil
+		})
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("changefeed credentials are rotated",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// cutOverSink handles cut over changefeed sink request.
+// @Summary Cut over a changefeed to its mirror
+// @Description Replace the sink-uri of a changefeed with its mirror without restarting it
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/cutover [post]
+func (h *OpenAPIV2) cutOverSink(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	if _, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// the checkpoint of the changefeed advances without waiting for the
+	// mirror, so it's cut over only after the mirror catches up. The table
+	// sinks of the mirror start from the checkpoint of the changefeed, so the
+	// mirror may have skipped some events once it failed or its table sinks
+	// were restarted while it fell behind, it's never cut over then.
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = h.capture.GetEtcdClient().UpdateChangeFeedInfo(ctx, changefeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			info.Namespace = changefeedID.Namespace
+			info.ID = changefeedID.ID
+			if err := info.CutOverToMirror(); err != nil {
+				return nil, false, err
+			}
+			if status.MirrorIncomplete {
+				return nil, false, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+					"the mirror may have lost events, it failed or restarted " +
+						"before catching up with the changefeed")
+			}
+			if status.MirrorCheckpointTs < status.CheckpointTs {
+				return nil, false, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+					"the mirror hasn't caught up with the changefeed, "+
+						"the checkpoint of the mirror is %d and the one of the changefeed is %d",
+					status.MirrorCheckpointTs, status.CheckpointTs))
+			}
+			return info, true, nil
+		})
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("changefeed is cut over to the mirror",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// pauseTable handles pause table request.
+// @Summary Pause a table of a changefeed
+// @Description Pause the replication of a table without stopping the changefeed
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param table_id path integer true "table_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/{table_id}/pause [post]
+func (h *OpenAPIV2) pauseTable(c *gin.Context) {
+	h.setTablePaused(c, true)
+}
+
+// resumeTable handles resume table request.
+// @Summary Resume a table of a changefeed
+// @Description Resume the replication of a paused table
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param table_id path integer true "table_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables/{table_id}/resume [post]
+func (h *OpenAPIV2) resumeTable(c *gin.Context) {
+	h.setTablePaused(c, false)
+}
+
+func (h *OpenAPIV2) setTablePaused(c *gin.Context, paused bool) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	tableID, err := strconv.ParseInt(c.Param(apiOpVarTableID), 10, 64)
+	if err != nil || tableID <= 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid table_id: %s",
+			c.Param(apiOpVarTableID)))
+		return
+	}
+
+	if _, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// the running processors pause or resume the table once they see the
+	// changefeed info with the new paused tables.
+	err = h.capture.GetEtcdClient().UpdateChangeFeedInfo(ctx, changefeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			info.Namespace = changefeedID.Namespace
+			info.ID = changefeedID.ID
+			return info, info.SetTablePaused(tableID, paused), nil
+		})
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("table replication is paused or resumed",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Int64("tableID", tableID),
+		zap.Bool("paused", paused))
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// listTableProgresses lists the replication progresses of the tables.
+// @Summary List the table progresses of a changefeed
+// @Description List the checkpoint, resolved ts, pending events and sink flush latency of each table span
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {array} TableProgress
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables [get]
+func (h *OpenAPIV2) listTableProgresses(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	progresses, err := h.capture.StatusProvider().GetTableProgresses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := &ListResponse[TableProgress]{
+		Total: len(progresses),
+		Items: make([]TableProgress, 0, len(progresses)),
+	}
+	for _, p := range progresses {
+		progress := TableProgress{
+			TableID:            p.Span.TableID,
+			Span:               p.Span.String(),
+			CaptureID:          p.CaptureID,
+			CheckpointTs:       p.Checkpoint.CheckpointTs,
+			ResolvedTs:         p.Checkpoint.ResolvedTs,
+			PendingEventCount:  p.Stats.PendingEventCount,
+			SinkFlushLatencyMs: p.Stats.SinkFlushLatencyMs,
+		}
+		// the lag is unknown until the stats are collected from the processor.
+		if p.Stats.CurrentTs > p.Checkpoint.CheckpointTs {
+			progress.CheckpointLagMs = oracle.ExtractPhysical(p.Stats.CurrentTs) -
+				oracle.ExtractPhysical(p.Checkpoint.CheckpointTs)
+		}
+		resp.Items = append(resp.Items, progress)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// listSkippedDDLs lists the DDLs recently skipped by the filter of a changefeed.
+// @Summary List the skipped DDLs of a changefeed
+// @Description List the DDLs recently skipped by the filter of a changefeed, such as by the ddl-deny-list
+// @Tags changefeed,v2
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {array} SkippedDDL
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/skipped_ddls [get]
+func (h *OpenAPIV2) listSkippedDDLs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	ddls, err := h.capture.StatusProvider().GetSkippedDDLs(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := &ListResponse[SkippedDDL]{
+		Total: len(ddls),
+		Items: make([]SkippedDDL, 0, len(ddls)),
+	}
+	for _, ddl := range ddls {
+		resp.Items = append(resp.Items, SkippedDDL{
+			StartTs:  ddl.StartTs,
+			CommitTs: ddl.CommitTs,
+			Type:     ddl.Type,
+			Schema:   ddl.Table.Schema,
+			Table:    ddl.Table.Table,
+			Query:    ddl.Query,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *OpenAPIV2) status(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -852,6 +1232,7 @@ func (h *OpenAPIV2) status(c *gin.Context) {
 			Addr:    info.Error.Addr,
 			Code:    info.Error.Code,
 			Message: info.Error.Message,
+			Class:   info.Error.Class,
 		}
 	}
 	var lastWarning *RunningError
@@ -862,6 +1243,7 @@ func (h *OpenAPIV2) status(c *gin.Context) {
 			Addr:    info.Warning.Addr,
 			Code:    info.Warning.Code,
 			Message: info.Warning.Message,
+			Class:   info.Warning.Class,
 		}
 	}
 
@@ -890,6 +1272,7 @@ func toAPIModel(
 			Addr:    info.Error.Addr,
 			Code:    info.Error.Code,
 			Message: info.Error.Message,
+			Class:   info.Error.Class,
 		}
 	}
 
@@ -915,11 +1298,26 @@ func toAPIModel(
 		State:          info.State,
 		Error:          runningError,
 		CreatorVersion: info.CreatorVersion,
+		PausedTables:   info.PausedTables,
 		CheckpointTs:   checkpointTs,
 		ResolvedTs:     resolvedTs,
 		CheckpointTime: model.JSONTime(oracle.GetTimeFromTS(checkpointTs)),
 		TaskStatus:     taskStatus,
 	}
+	if maskSinkURI && apiInfoModel.Config.Sink != nil {
+		for _, target := range apiInfoModel.Config.Sink.Targets {
+			target.SinkURI, err = util.MaskSinkURI(target.SinkURI)
+			if err != nil {
+				log.Error("failed to mask sink URI", zap.Error(err))
+			}
+		}
+		if mirror := apiInfoModel.Config.Sink.Mirror; mirror != nil {
+			mirror.SinkURI, err = util.MaskSinkURI(mirror.SinkURI)
+			if err != nil {
+				log.Error("failed to mask sink URI", zap.Error(err))
+			}
+		}
+	}
 	return apiInfoModel
 }
 
@@ -15,6 +15,8 @@ package v2
 
 import (
 	"encoding/json"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -25,6 +27,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/integrity"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/util"
 )
 
@@ -76,12 +79,165 @@ func getDefaultVerifyTableConfig() *VerifyTableConfig {
 	}
 }
 
+// The names of the checks in the changefeed dry-run.
+const (
+	// DryRunCheckChangefeedConfig checks the ID, the start ts, the target ts
+	// and the replica config of the changefeed.
+	DryRunCheckChangefeedConfig = "changefeed-config"
+	// DryRunCheckGCSafepoint checks the start ts isn't garbage collected.
+	DryRunCheckGCSafepoint = "gc-safepoint"
+	// DryRunCheckTables checks the filter rules against the current schemas.
+	DryRunCheckTables = "tables"
+	// DryRunCheckSink checks the sink by creating and closing it.
+	DryRunCheckSink = "sink"
+	// DryRunCheckRunningImport checks there is no running lightning or restore task.
+	DryRunCheckRunningImport = "running-import"
+)
+
+// The status of the checks in the changefeed dry-run.
+const (
+	DryRunCheckPassed  = "passed"
+	DryRunCheckFailed  = "failed"
+	DryRunCheckSkipped = "skipped"
+)
+
+// DryRunCheck is the result of a check in the changefeed dry-run.
+type DryRunCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ChangefeedDryRunReport is the report of creating a changefeed in the
+// dry-run mode, the changefeed is validated but not created.
+type ChangefeedDryRunReport struct {
+	Namespace        string        `json:"namespace"`
+	ID               string        `json:"id"`
+	StartTs          uint64        `json:"start_ts"`
+	TargetTs         uint64        `json:"target_ts"`
+	Passed           bool          `json:"passed"`
+	Checks           []DryRunCheck `json:"checks"`
+	IneligibleTables []TableName   `json:"ineligible_tables,omitempty"`
+	EligibleTables   []TableName   `json:"eligible_tables,omitempty"`
+}
+
+func (r *ChangefeedDryRunReport) addCheck(name string, err error) {
+	check := DryRunCheck{Name: name, Status: DryRunCheckPassed}
+	if err != nil {
+		check.Status = DryRunCheckFailed
+		check.Error = err.Error()
+		r.Passed = false
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+func (r *ChangefeedDryRunReport) skipChecks(names ...string) {
+	for _, name := range names {
+		r.Checks = append(r.Checks, DryRunCheck{Name: name, Status: DryRunCheckSkipped})
+	}
+	r.Passed = false
+}
+
+func toAPITableNames(tables []model.TableName) []TableName {
+	var names []TableName
+	for _, tbl := range tables {
+		names = append(names, TableName{
+			Schema:      tbl.Schema,
+			Table:       tbl.Table,
+			TableID:     tbl.TableID,
+			IsPartition: tbl.IsPartition,
+		})
+	}
+	return names
+}
+
 // ResumeChangefeedConfig is used by resume changefeed api
 type ResumeChangefeedConfig struct {
 	PDConfig
 	OverwriteCheckpointTs uint64 `json:"overwrite_checkpoint_ts"`
 }
 
+// ChangefeedCredentials is used by the rotate credentials api, only the
+// non-empty credentials are replaced.
+type ChangefeedCredentials struct {
+	// User and Password are the credentials of the MySQL and TiDB sinks,
+	// or the SASL credentials of the Kafka sink.
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	// AccessKey, SecretAccessKey and SessionToken are the credentials of
+	// the S3 storage sink.
+	AccessKey       string `json:"access_key,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	SessionToken    string `json:"session_token,omitempty"`
+}
+
+// applyTo replaces the credentials in the sink URI and the sink configs of
+// the changefeed info.
+func (c *ChangefeedCredentials) applyTo(info *model.ChangeFeedInfo) error {
+	sinkURI, err := sink.ParseSinkURI(info.SinkURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	hasUser := c.User != "" || c.Password != ""
+	hasAccessKey := c.AccessKey != "" || c.SecretAccessKey != "" || c.SessionToken != ""
+	if !hasUser && !hasAccessKey {
+		return cerror.ErrAPIInvalidParam.GenWithStack("no credentials are provided")
+	}
+	switch {
+	case sink.IsMySQLCompatibleScheme(scheme) && !hasAccessKey:
+		user := sinkURI.User.Username()
+		password, _ := sinkURI.User.Password()
+		if c.User != "" {
+			user = c.User
+		}
+		if c.Password != "" {
+			password = c.Password
+		}
+		sinkURI.User = url.UserPassword(user, password)
+	case (scheme == sink.KafkaScheme || scheme == sink.KafkaSSLScheme) && !hasAccessKey:
+		// the credentials are replaced where they're set, the configs are
+		// used if they're set in neither the sink URI nor the configs.
+		query := sinkURI.Query()
+		if query.Has("sasl-user") || query.Has("sasl-password") {
+			if c.User != "" {
+				query.Set("sasl-user", c.User)
+			}
+			if c.Password != "" {
+				query.Set("sasl-password", c.Password)
+			}
+			sinkURI.RawQuery = query.Encode()
+			break
+		}
+		if info.Config.Sink.KafkaConfig == nil {
+			info.Config.Sink.KafkaConfig = &config.KafkaConfig{}
+		}
+		if c.User != "" {
+			info.Config.Sink.KafkaConfig.SASLUser = util.AddressOf(c.User)
+		}
+		if c.Password != "" {
+			info.Config.Sink.KafkaConfig.SASLPassword = util.AddressOf(c.Password)
+		}
+	case scheme == sink.S3Scheme && !hasUser:
+		query := sinkURI.Query()
+		if c.AccessKey != "" {
+			query.Set("access-key", c.AccessKey)
+		}
+		if c.SecretAccessKey != "" {
+			query.Set("secret-access-key", c.SecretAccessKey)
+		}
+		if c.SessionToken != "" {
+			query.Set("session-token", c.SessionToken)
+		}
+		sinkURI.RawQuery = query.Encode()
+	default:
+		return cerror.ErrAPIInvalidParam.GenWithStack(
+			"the credentials can't be rotated for the %s sink", scheme)
+	}
+	info.SinkURI = sinkURI.String()
+	return nil
+}
+
 // PDConfig is a configuration used to connect to pd
 type PDConfig struct {
 	PDAddrs       []string `json:"pd_addrs,omitempty"`
@@ -131,6 +287,9 @@ type ChangefeedConfig struct {
 	TargetTs      uint64         `json:"target_ts"`
 	SinkURI       string         `json:"sink_uri"`
 	ReplicaConfig *ReplicaConfig `json:"replica_config"`
+	// Savepoint is the name of the savepoint which the changefeed is created
+	// from, the changefeed starts from the checkpoint of the savepoint.
+	Savepoint string `json:"savepoint,omitempty"`
 	PDConfig
 }
 
@@ -173,6 +332,22 @@ func (d *JSONDuration) UnmarshalJSON(b []byte) error {
 	}
 }
 
+// toDuration returns the wrapped duration, or nil if d is nil.
+func (d *JSONDuration) toDuration() *time.Duration {
+	if d == nil {
+		return nil
+	}
+	return &d.duration
+}
+
+// toJSONDuration wraps the duration, or returns nil if d is nil.
+func toJSONDuration(d *time.Duration) *JSONDuration {
+	if d == nil {
+		return nil
+	}
+	return &JSONDuration{*d}
+}
+
 // ReplicaConfig is a duplicate of  config.ReplicaConfig
 type ReplicaConfig struct {
 	MemoryQuota           uint64 `json:"memory_quota"`
@@ -186,6 +361,8 @@ type ReplicaConfig struct {
 
 	SyncPointInterval  *JSONDuration `json:"sync_point_interval,omitempty" swaggertype:"string"`
 	SyncPointRetention *JSONDuration `json:"sync_point_retention,omitempty" swaggertype:"string"`
+	LagSLO             *JSONDuration `json:"lag_slo,omitempty" swaggertype:"string"`
+	DelayDuration      *JSONDuration `json:"delay_duration,omitempty" swaggertype:"string"`
 
 	Filter     *FilterConfig              `json:"filter"`
 	Mounter    *MounterConfig             `json:"mounter"`
@@ -193,6 +370,9 @@ type ReplicaConfig struct {
 	Consistent *ConsistentConfig          `json:"consistent,omitempty"`
 	Scheduler  *ChangefeedSchedulerConfig `json:"scheduler"`
 	Integrity  *IntegrityConfig           `json:"integrity"`
+	Snapshot   *SnapshotConfig            `json:"snapshot,omitempty"`
+
+	RestartStrategy *RestartStrategyConfig `json:"restart_strategy,omitempty"`
 }
 
 // ToInternalReplicaConfig coverts *v2.ReplicaConfig into *config.ReplicaConfig
@@ -217,6 +397,12 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 	if c.SyncPointRetention != nil {
 		res.SyncPointRetention = &c.SyncPointRetention.duration
 	}
+	if c.LagSLO != nil {
+		res.LagSLO = &c.LagSLO.duration
+	}
+	if c.DelayDuration != nil {
+		res.DelayDuration = &c.DelayDuration.duration
+	}
 	res.BDRMode = c.BDRMode
 
 	if c.Filter != nil {
@@ -251,11 +437,22 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				efs[i] = ef.ToInternalEventFilterRule()
 			}
 		}
+		var ddlAllowList, ddlDenyList []bf.EventType
+		for _, et := range c.Filter.DDLAllowList {
+			ddlAllowList = append(ddlAllowList, bf.EventType(et))
+		}
+		for _, et := range c.Filter.DDLDenyList {
+			ddlDenyList = append(ddlDenyList, bf.EventType(et))
+		}
 		res.Filter = &config.FilterConfig{
 			Rules:                 c.Filter.Rules,
 			MySQLReplicationRules: mySQLReplicationRules,
 			IgnoreTxnStartTs:      c.Filter.IgnoreTxnStartTs,
 			EventFilters:          efs,
+			SamplingRate:          c.Filter.SamplingRate,
+			IgnoreLocalOrigin:     c.Filter.IgnoreLocalOrigin,
+			DDLAllowList:          ddlAllowList,
+			DDLDenyList:           ddlDenyList,
 		}
 	}
 	if c.Consistent != nil {
@@ -265,16 +462,24 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			FlushIntervalInMs: c.Consistent.FlushIntervalInMs,
 			Storage:           c.Consistent.Storage,
 			UseFileBackend:    c.Consistent.UseFileBackend,
+			FlushWorkerNum:    c.Consistent.FlushWorkerNum,
+			FlushConcurrency:  c.Consistent.FlushConcurrency,
+			Compression:       c.Consistent.Compression,
+			EncryptionKey:     c.Consistent.EncryptionKey,
 		}
 	}
 	if c.Sink != nil {
 		var dispatchRules []*config.DispatchRule
 		for _, rule := range c.Sink.DispatchRules {
 			dispatchRules = append(dispatchRules, &config.DispatchRule{
-				Matcher:        rule.Matcher,
-				DispatcherRule: "",
-				PartitionRule:  rule.PartitionRule,
-				TopicRule:      rule.TopicRule,
+				Matcher:           rule.Matcher,
+				DispatcherRule:    "",
+				PartitionRule:     rule.PartitionRule,
+				TopicRule:         rule.TopicRule,
+				PartitionPlugin:   rule.PartitionPlugin,
+				KeyTemplate:       rule.KeyTemplate,
+				TopicPartitionNum: rule.TopicPartitionNum,
+				TopicConfigs:      rule.TopicConfigs,
 			})
 		}
 		var columnSelectors []*config.ColumnSelector
@@ -284,6 +489,130 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				Columns: selector.Columns,
 			})
 		}
+		var columnMaskers []*config.ColumnMasker
+		for _, masker := range c.Sink.ColumnMaskers {
+			columnMaskers = append(columnMaskers, &config.ColumnMasker{
+				Matcher:    masker.Matcher,
+				Columns:    masker.Columns,
+				Mask:       masker.Mask,
+				Value:      masker.Value,
+				KeepPrefix: masker.KeepPrefix,
+				KeepSuffix: masker.KeepSuffix,
+			})
+		}
+		var computedColumns []*config.ComputedColumn
+		for _, column := range c.Sink.ComputedColumns {
+			computedColumns = append(computedColumns, &config.ComputedColumn{
+				Matcher: column.Matcher,
+				Name:    column.Name,
+				Value:   column.Value,
+			})
+		}
+		var targets []*config.SinkTarget
+		for _, target := range c.Sink.Targets {
+			targets = append(targets, &config.SinkTarget{
+				Matcher:  target.Matcher,
+				SinkURI:  target.SinkURI,
+				Protocol: target.Protocol,
+			})
+		}
+		var mirror *config.SinkMirror
+		if c.Sink.Mirror != nil {
+			mirror = &config.SinkMirror{
+				SinkURI:           c.Sink.Mirror.SinkURI,
+				Protocol:          c.Sink.Mirror.Protocol,
+				EnableKafkaSinkV2: c.Sink.Mirror.EnableKafkaSinkV2,
+			}
+		}
+		var tableRoutes []*config.TableRoute
+		for _, route := range c.Sink.TableRoutes {
+			var columns []*config.ColumnRoute
+			for _, column := range route.Columns {
+				columns = append(columns, &config.ColumnRoute{
+					Source: column.Source,
+					Target: column.Target,
+				})
+			}
+			tableRoutes = append(tableRoutes, &config.TableRoute{
+				Matcher:       route.Matcher,
+				TargetSchema:  route.TargetSchema,
+				TargetTable:   route.TargetTable,
+				Columns:       columns,
+				SourceColumns: route.SourceColumns,
+			})
+		}
+		var transformer *config.TransformerConfig
+		if c.Sink.Transformer != nil {
+			transformer = &config.TransformerConfig{
+				Matcher:      c.Sink.Transformer.Matcher,
+				WASMFile:     c.Sink.Transformer.WASMFile,
+				WASMChecksum: c.Sink.Transformer.WASMChecksum,
+			}
+		}
+		var typeMappings []*config.TypeMapping
+		for _, mapping := range c.Sink.TypeMappings {
+			typeMappings = append(typeMappings, &config.TypeMapping{
+				Protocols: mapping.Protocols,
+				Source:    mapping.Source,
+				Target:    mapping.Target,
+			})
+		}
+		var temporalEncoding *config.TemporalEncodingConfig
+		if c.Sink.TemporalEncoding != nil {
+			temporalEncoding = &config.TemporalEncodingConfig{
+				Protocols:      c.Sink.TemporalEncoding.Protocols,
+				Mode:           c.Sink.TemporalEncoding.Mode,
+				SourceTimeZone: c.Sink.TemporalEncoding.SourceTimeZone,
+				TimeZone:       c.Sink.TemporalEncoding.TimeZone,
+			}
+		}
+		var messageHeaders *config.MessageHeadersConfig
+		if c.Sink.MessageHeaders != nil {
+			messageHeaders = &config.MessageHeadersConfig{
+				EnableMetadata: c.Sink.MessageHeaders.EnableMetadata,
+				StaticHeaders:  c.Sink.MessageHeaders.StaticHeaders,
+			}
+		}
+		var bootstrap *config.BootstrapConfig
+		if c.Sink.Bootstrap != nil {
+			bootstrap = &config.BootstrapConfig{
+				Enable:         c.Sink.Bootstrap.Enable,
+				SnapshotMarker: c.Sink.Bootstrap.SnapshotMarker,
+			}
+		}
+		var tableMetrics *config.TableMetricsConfig
+		if c.Sink.TableMetrics != nil {
+			tableMetrics = &config.TableMetricsConfig{
+				Enable:    c.Sink.TableMetrics.Enable,
+				MaxTables: c.Sink.TableMetrics.MaxTables,
+			}
+		}
+		var schemaRegistryConfig *config.SchemaRegistryConfig
+		if c.Sink.SchemaRegistryConfig != nil {
+			schemaRegistryConfig = &config.SchemaRegistryConfig{
+				BasicAuthUser:       c.Sink.SchemaRegistryConfig.BasicAuthUser,
+				BasicAuthPassword:   c.Sink.SchemaRegistryConfig.BasicAuthPassword,
+				BearerToken:         c.Sink.SchemaRegistryConfig.BearerToken,
+				CA:                  c.Sink.SchemaRegistryConfig.CA,
+				Cert:                c.Sink.SchemaRegistryConfig.Cert,
+				Key:                 c.Sink.SchemaRegistryConfig.Key,
+				SubjectNameStrategy: c.Sink.SchemaRegistryConfig.SubjectNameStrategy,
+				SubjectPrefix:       c.Sink.SchemaRegistryConfig.SubjectPrefix,
+				SubjectSuffix:       c.Sink.SchemaRegistryConfig.SubjectSuffix,
+				Provider:            c.Sink.SchemaRegistryConfig.Provider,
+				GlueRegistryName:    c.Sink.SchemaRegistryConfig.GlueRegistryName,
+				GlueRegion:          c.Sink.SchemaRegistryConfig.GlueRegion,
+				ApicurioGroupID:     c.Sink.SchemaRegistryConfig.ApicurioGroupID,
+			}
+		}
+		var tableSinkBuffer *config.TableSinkBufferConfig
+		if c.Sink.TableSinkBuffer != nil {
+			tableSinkBuffer = &config.TableSinkBufferConfig{
+				Quota:     c.Sink.TableSinkBuffer.Quota,
+				Policy:    c.Sink.TableSinkBuffer.Policy,
+				DiskQuota: c.Sink.TableSinkBuffer.DiskQuota,
+			}
+		}
 		var csvConfig *config.CSVConfig
 		if c.Sink.CSVConfig != nil {
 			csvConfig = &config.CSVConfig{
@@ -292,6 +621,16 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				NullString:           c.Sink.CSVConfig.NullString,
 				IncludeCommitTs:      c.Sink.CSVConfig.IncludeCommitTs,
 				BinaryEncodingMethod: c.Sink.CSVConfig.BinaryEncodingMethod,
+				IncludeHeader:        c.Sink.CSVConfig.IncludeHeader,
+				OutputSchemaSidecar:  c.Sink.CSVConfig.OutputSchemaSidecar,
+				QuotingPolicy:        c.Sink.CSVConfig.QuotingPolicy,
+				EscapeChar:           c.Sink.CSVConfig.EscapeChar,
+			}
+			for _, r := range c.Sink.CSVConfig.ColumnRules {
+				csvConfig.ColumnRules = append(csvConfig.ColumnRules, &config.CSVColumnRule{
+					Matcher: r.Matcher,
+					Columns: r.Columns,
+				})
 			}
 		}
 		var kafkaConfig *config.KafkaConfig
@@ -312,9 +651,13 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			if c.Sink.KafkaConfig.LargeMessageHandle != nil {
 				oldConfig := c.Sink.KafkaConfig.LargeMessageHandle
 				largeMessageHandle = &config.LargeMessageHandleConfig{
-					LargeMessageHandleOption: oldConfig.LargeMessageHandleOption,
-					ClaimCheckStorageURI:     oldConfig.ClaimCheckStorageURI,
-					ClaimCheckCompression:    oldConfig.ClaimCheckCompression,
+					LargeMessageHandleOption:     oldConfig.LargeMessageHandleOption,
+					ClaimCheckStorageURI:         oldConfig.ClaimCheckStorageURI,
+					ClaimCheckCompression:        oldConfig.ClaimCheckCompression,
+					ClaimCheckBareMessage:        oldConfig.ClaimCheckBareMessage,
+					ClaimCheckRetention:          oldConfig.ClaimCheckRetention,
+					ClaimCheckPresignedURLExpiry: oldConfig.ClaimCheckPresignedURLExpiry,
+					LargeMessageCompression:      oldConfig.LargeMessageCompression,
 				}
 			}
 
@@ -347,6 +690,11 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				SASLOAuthScopes:              c.Sink.KafkaConfig.SASLOAuthScopes,
 				SASLOAuthGrantType:           c.Sink.KafkaConfig.SASLOAuthGrantType,
 				SASLOAuthAudience:            c.Sink.KafkaConfig.SASLOAuthAudience,
+				SASLOAuthAssertionKey:        c.Sink.KafkaConfig.SASLOAuthAssertionKey,
+				SASLOAuthAssertionKeyID:      c.Sink.KafkaConfig.SASLOAuthAssertionKeyID,
+				SASLAWSRegion:                c.Sink.KafkaConfig.SASLAWSRegion,
+				SASLAWSRoleARN:               c.Sink.KafkaConfig.SASLAWSRoleARN,
+				SASLAWSRoleSessionName:       c.Sink.KafkaConfig.SASLAWSRoleSessionName,
 				EnableTLS:                    c.Sink.KafkaConfig.EnableTLS,
 				CA:                           c.Sink.KafkaConfig.CA,
 				Cert:                         c.Sink.KafkaConfig.Cert,
@@ -354,10 +702,38 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				InsecureSkipVerify:           c.Sink.KafkaConfig.InsecureSkipVerify,
 				CodecConfig:                  codeConfig,
 				LargeMessageHandle:           largeMessageHandle,
+				DeleteTopicsOnRemove:         c.Sink.KafkaConfig.DeleteTopicsOnRemove,
+				DNSSRVDiscovery:              c.Sink.KafkaConfig.DNSSRVDiscovery,
+				EnableKafkaTransactions:      c.Sink.KafkaConfig.EnableKafkaTransactions,
+				DeadLetterTopic:              c.Sink.KafkaConfig.DeadLetterTopic,
+				DeadLetterStorageURI:         c.Sink.KafkaConfig.DeadLetterStorageURI,
+				DDLTopic:                     c.Sink.KafkaConfig.DDLTopic,
+				EventHubs:                    c.Sink.KafkaConfig.EventHubs,
+				EventHubsConnectionString:    c.Sink.KafkaConfig.EventHubsConnectionString,
+				Linger:                       c.Sink.KafkaConfig.Linger,
+				BatchBytes:                   c.Sink.KafkaConfig.BatchBytes,
+				MaxInflight:                  c.Sink.KafkaConfig.MaxInflight,
+				CompressionLevel:             c.Sink.KafkaConfig.CompressionLevel,
+				TopicConfigs:                 c.Sink.KafkaConfig.TopicConfigs,
 			}
 		}
 		var mysqlConfig *config.MySQLConfig
 		if c.Sink.MySQLConfig != nil {
+			var ddlTranslation *config.DDLTranslationConfig
+			if c.Sink.MySQLConfig.DDLTranslation != nil {
+				var rules []*config.DDLTranslationRule
+				for _, r := range c.Sink.MySQLConfig.DDLTranslation.Rules {
+					rules = append(rules, &config.DDLTranslationRule{
+						Pattern:     r.Pattern,
+						Replacement: r.Replacement,
+						Skip:        r.Skip,
+					})
+				}
+				ddlTranslation = &config.DDLTranslationConfig{
+					Dialect: c.Sink.MySQLConfig.DDLTranslation.Dialect,
+					Rules:   rules,
+				}
+			}
 			mysqlConfig = &config.MySQLConfig{
 				WorkerCount:                  c.Sink.MySQLConfig.WorkerCount,
 				MaxTxnRow:                    c.Sink.MySQLConfig.MaxTxnRow,
@@ -374,15 +750,113 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				EnableBatchDML:               c.Sink.MySQLConfig.EnableBatchDML,
 				EnableMultiStatement:         c.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: c.Sink.MySQLConfig.EnableCachePreparedStatement,
+				ConflictStrategy:             c.Sink.MySQLConfig.ConflictStrategy,
+				ConflictTimestampColumn:      c.Sink.MySQLConfig.ConflictTimestampColumn,
+				EnableAsyncDDL:               c.Sink.MySQLConfig.EnableAsyncDDL,
+				EnableBatchUpsert:            c.Sink.MySQLConfig.EnableBatchUpsert,
+				DMLMaxOpenConns:              c.Sink.MySQLConfig.DMLMaxOpenConns,
+				MetaMaxOpenConns:             c.Sink.MySQLConfig.MetaMaxOpenConns,
+				PoolHealthCheckInterval:      c.Sink.MySQLConfig.PoolHealthCheckInterval,
+				DDLTranslation:               ddlTranslation,
 			}
 		}
 		var cloudStorageConfig *config.CloudStorageConfig
 		if c.Sink.CloudStorageConfig != nil {
+			var tableOverrides []*config.CloudStorageTableOverride
+			for _, o := range c.Sink.CloudStorageConfig.TableOverrides {
+				tableOverrides = append(tableOverrides, &config.CloudStorageTableOverride{
+					Matcher:       o.Matcher,
+					FlushInterval: o.FlushInterval,
+					FileSize:      o.FileSize,
+				})
+			}
+			var redshiftConfig *config.RedshiftConfig
+			if r := c.Sink.CloudStorageConfig.RedshiftConfig; r != nil {
+				redshiftConfig = &config.RedshiftConfig{
+					ClusterIdentifier: r.ClusterIdentifier,
+					WorkgroupName:     r.WorkgroupName,
+					Database:          r.Database,
+					DBUser:            r.DBUser,
+					SecretARN:         r.SecretARN,
+					IAMRole:           r.IAMRole,
+					Region:            r.Region,
+					Schema:            r.Schema,
+				}
+			}
 			cloudStorageConfig = &config.CloudStorageConfig{
-				WorkerCount:    c.Sink.CloudStorageConfig.WorkerCount,
-				FlushInterval:  c.Sink.CloudStorageConfig.FlushInterval,
-				FileSize:       c.Sink.CloudStorageConfig.FileSize,
-				OutputColumnID: c.Sink.CloudStorageConfig.OutputColumnID,
+				WorkerCount:          c.Sink.CloudStorageConfig.WorkerCount,
+				FlushInterval:        c.Sink.CloudStorageConfig.FlushInterval,
+				FileSize:             c.Sink.CloudStorageConfig.FileSize,
+				OutputColumnID:       c.Sink.CloudStorageConfig.OutputColumnID,
+				FileNameTemplate:     c.Sink.CloudStorageConfig.FileNameTemplate,
+				OutputManifest:       c.Sink.CloudStorageConfig.OutputManifest,
+				OutputCompleteMarker: c.Sink.CloudStorageConfig.OutputCompleteMarker,
+				ParquetCompression:   c.Sink.CloudStorageConfig.ParquetCompression,
+				ParquetRowGroupSize:  c.Sink.CloudStorageConfig.ParquetRowGroupSize,
+				Compression:          c.Sink.CloudStorageConfig.Compression,
+				TableOverrides:       tableOverrides,
+				RedshiftConfig:       redshiftConfig,
+				EnableDeltaLake:      c.Sink.CloudStorageConfig.EnableDeltaLake,
+			}
+		}
+
+		var clickhouseConfig *config.ClickHouseConfig
+		if c.Sink.ClickHouseConfig != nil {
+			clickhouseConfig = &config.ClickHouseConfig{
+				Engine:        c.Sink.ClickHouseConfig.Engine,
+				VersionColumn: c.Sink.ClickHouseConfig.VersionColumn,
+				SignColumn:    c.Sink.ClickHouseConfig.SignColumn,
+				WorkerCount:   c.Sink.ClickHouseConfig.WorkerCount,
+				MaxBatchRows:  c.Sink.ClickHouseConfig.MaxBatchRows,
+			}
+		}
+
+		var elasticsearchConfig *config.ElasticsearchConfig
+		if c.Sink.ElasticsearchConfig != nil {
+			var indexRules []*config.ElasticsearchIndexRule
+			for _, r := range c.Sink.ElasticsearchConfig.IndexRules {
+				indexRules = append(indexRules, &config.ElasticsearchIndexRule{
+					Matcher: r.Matcher,
+					Index:   r.Index,
+				})
+			}
+			elasticsearchConfig = &config.ElasticsearchConfig{
+				Index:        c.Sink.ElasticsearchConfig.Index,
+				IndexRules:   indexRules,
+				WorkerCount:  c.Sink.ElasticsearchConfig.WorkerCount,
+				MaxBatchRows: c.Sink.ElasticsearchConfig.MaxBatchRows,
+			}
+		}
+
+		var redisConfig *config.RedisConfig
+		if c.Sink.RedisConfig != nil {
+			var keyRules []*config.RedisKeyRule
+			for _, r := range c.Sink.RedisConfig.KeyRules {
+				keyRules = append(keyRules, &config.RedisKeyRule{
+					Matcher: r.Matcher,
+					Key:     r.Key,
+				})
+			}
+			redisConfig = &config.RedisConfig{
+				Mode:         c.Sink.RedisConfig.Mode,
+				ValueFormat:  c.Sink.RedisConfig.ValueFormat,
+				Key:          c.Sink.RedisConfig.Key,
+				KeyRules:     keyRules,
+				Channel:      c.Sink.RedisConfig.Channel,
+				WorkerCount:  c.Sink.RedisConfig.WorkerCount,
+				MaxBatchRows: c.Sink.RedisConfig.MaxBatchRows,
+			}
+		}
+
+		var webhookConfig *config.WebhookConfig
+		if c.Sink.WebhookConfig != nil {
+			webhookConfig = &config.WebhookConfig{
+				Headers:         c.Sink.WebhookConfig.Headers,
+				WorkerCount:     c.Sink.WebhookConfig.WorkerCount,
+				MaxBatchRows:    c.Sink.WebhookConfig.MaxBatchRows,
+				MaxRetries:      c.Sink.WebhookConfig.MaxRetries,
+				RetryBackoff:    c.Sink.WebhookConfig.RetryBackoff,
+				MaxRetryBackoff: c.Sink.WebhookConfig.MaxRetryBackoff,
 			}
 		}
 
@@ -391,18 +865,39 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			Protocol:                         c.Sink.Protocol,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
+			ColumnMaskers:                    columnMaskers,
+			ComputedColumns:                  computedColumns,
+			Targets:                          targets,
+			Mirror:                           mirror,
+			TableRoutes:                      tableRoutes,
+			Transformer:                      transformer,
+			TypeMappings:                     typeMappings,
+			TemporalEncoding:                 temporalEncoding,
 			SchemaRegistry:                   c.Sink.SchemaRegistry,
+			SchemaRegistryConfig:             schemaRegistryConfig,
 			EncoderConcurrency:               c.Sink.EncoderConcurrency,
 			Terminator:                       c.Sink.Terminator,
 			DateSeparator:                    c.Sink.DateSeparator,
 			EnablePartitionSeparator:         c.Sink.EnablePartitionSeparator,
 			FileIndexWidth:                   c.Sink.FileIndexWidth,
+			PartitionLayout:                  c.Sink.PartitionLayout,
 			EnableKafkaSinkV2:                c.Sink.EnableKafkaSinkV2,
 			OnlyOutputUpdatedColumns:         c.Sink.OnlyOutputUpdatedColumns,
 			DeleteOnlyOutputHandleKeyColumns: c.Sink.DeleteOnlyOutputHandleKeyColumns,
+			OutputPhysicalTime:               c.Sink.OutputPhysicalTime,
+			CanalJSONFlat:                    c.Sink.CanalJSONFlat,
+			DeleteAsTombstone:                c.Sink.DeleteAsTombstone,
+			MessageHeaders:                   messageHeaders,
+			Bootstrap:                        bootstrap,
+			TableMetrics:                     tableMetrics,
+			TableSinkBuffer:                  tableSinkBuffer,
 			KafkaConfig:                      kafkaConfig,
 			MySQLConfig:                      mysqlConfig,
 			CloudStorageConfig:               cloudStorageConfig,
+			ClickHouseConfig:                 clickhouseConfig,
+			ElasticsearchConfig:              elasticsearchConfig,
+			RedisConfig:                      redisConfig,
+			WebhookConfig:                    webhookConfig,
 			SafeMode:                         c.Sink.SafeMode,
 		}
 
@@ -429,6 +924,23 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			CorruptionHandleLevel: c.Integrity.CorruptionHandleLevel,
 		}
 	}
+	if c.Snapshot != nil {
+		res.Snapshot = &config.SnapshotConfig{
+			Enable:    c.Snapshot.Enable,
+			ChunkSize: c.Snapshot.ChunkSize,
+			RateLimit: c.Snapshot.RateLimit,
+		}
+	}
+	if c.RestartStrategy != nil {
+		res.RestartStrategy = &config.RestartStrategyConfig{
+			MaxRetries:        c.RestartStrategy.MaxRetries,
+			InitialBackoff:    c.RestartStrategy.InitialBackoff.toDuration(),
+			MaxBackoff:        c.RestartStrategy.MaxBackoff.toDuration(),
+			BackoffMultiplier: c.RestartStrategy.BackoffMultiplier,
+			MaxElapsedTime:    c.RestartStrategy.MaxElapsedTime.toDuration(),
+			ResetWindow:       c.RestartStrategy.ResetWindow.toDuration(),
+		}
+	}
 	return res
 }
 
@@ -455,6 +967,14 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		res.SyncPointRetention = &JSONDuration{*cloned.SyncPointRetention}
 	}
 
+	if cloned.LagSLO != nil {
+		res.LagSLO = &JSONDuration{*cloned.LagSLO}
+	}
+
+	if cloned.DelayDuration != nil {
+		res.DelayDuration = &JSONDuration{*cloned.DelayDuration}
+	}
+
 	if cloned.Filter != nil {
 		var mySQLReplicationRules *MySQLReplicationRules
 		if c.Filter.MySQLReplicationRules != nil {
@@ -489,20 +1009,35 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			}
 		}
 
+		var ddlAllowList, ddlDenyList []string
+		for _, et := range cloned.Filter.DDLAllowList {
+			ddlAllowList = append(ddlAllowList, string(et))
+		}
+		for _, et := range cloned.Filter.DDLDenyList {
+			ddlDenyList = append(ddlDenyList, string(et))
+		}
 		res.Filter = &FilterConfig{
 			MySQLReplicationRules: mySQLReplicationRules,
 			Rules:                 cloned.Filter.Rules,
 			IgnoreTxnStartTs:      cloned.Filter.IgnoreTxnStartTs,
 			EventFilters:          efs,
+			SamplingRate:          cloned.Filter.SamplingRate,
+			IgnoreLocalOrigin:     cloned.Filter.IgnoreLocalOrigin,
+			DDLAllowList:          ddlAllowList,
+			DDLDenyList:           ddlDenyList,
 		}
 	}
 	if cloned.Sink != nil {
 		var dispatchRules []*DispatchRule
 		for _, rule := range cloned.Sink.DispatchRules {
 			dispatchRules = append(dispatchRules, &DispatchRule{
-				Matcher:       rule.Matcher,
-				PartitionRule: rule.PartitionRule,
-				TopicRule:     rule.TopicRule,
+				Matcher:           rule.Matcher,
+				PartitionRule:     rule.PartitionRule,
+				TopicRule:         rule.TopicRule,
+				PartitionPlugin:   rule.PartitionPlugin,
+				KeyTemplate:       rule.KeyTemplate,
+				TopicPartitionNum: rule.TopicPartitionNum,
+				TopicConfigs:      rule.TopicConfigs,
 			})
 		}
 		var columnSelectors []*ColumnSelector
@@ -512,6 +1047,130 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var columnMaskers []*ColumnMasker
+		for _, masker := range cloned.Sink.ColumnMaskers {
+			columnMaskers = append(columnMaskers, &ColumnMasker{
+				Matcher:    masker.Matcher,
+				Columns:    masker.Columns,
+				Mask:       masker.Mask,
+				Value:      masker.Value,
+				KeepPrefix: masker.KeepPrefix,
+				KeepSuffix: masker.KeepSuffix,
+			})
+		}
+		var computedColumns []*ComputedColumn
+		for _, column := range cloned.Sink.ComputedColumns {
+			computedColumns = append(computedColumns, &ComputedColumn{
+				Matcher: column.Matcher,
+				Name:    column.Name,
+				Value:   column.Value,
+			})
+		}
+		var targets []*SinkTarget
+		for _, target := range cloned.Sink.Targets {
+			targets = append(targets, &SinkTarget{
+				Matcher:  target.Matcher,
+				SinkURI:  target.SinkURI,
+				Protocol: target.Protocol,
+			})
+		}
+		var mirror *SinkMirror
+		if cloned.Sink.Mirror != nil {
+			mirror = &SinkMirror{
+				SinkURI:           cloned.Sink.Mirror.SinkURI,
+				Protocol:          cloned.Sink.Mirror.Protocol,
+				EnableKafkaSinkV2: cloned.Sink.Mirror.EnableKafkaSinkV2,
+			}
+		}
+		var tableRoutes []*TableRoute
+		for _, route := range cloned.Sink.TableRoutes {
+			var columns []*ColumnRoute
+			for _, column := range route.Columns {
+				columns = append(columns, &ColumnRoute{
+					Source: column.Source,
+					Target: column.Target,
+				})
+			}
+			tableRoutes = append(tableRoutes, &TableRoute{
+				Matcher:       route.Matcher,
+				TargetSchema:  route.TargetSchema,
+				TargetTable:   route.TargetTable,
+				Columns:       columns,
+				SourceColumns: route.SourceColumns,
+			})
+		}
+		var transformer *TransformerConfig
+		if cloned.Sink.Transformer != nil {
+			transformer = &TransformerConfig{
+				Matcher:      cloned.Sink.Transformer.Matcher,
+				WASMFile:     cloned.Sink.Transformer.WASMFile,
+				WASMChecksum: cloned.Sink.Transformer.WASMChecksum,
+			}
+		}
+		var typeMappings []*TypeMapping
+		for _, mapping := range cloned.Sink.TypeMappings {
+			typeMappings = append(typeMappings, &TypeMapping{
+				Protocols: mapping.Protocols,
+				Source:    mapping.Source,
+				Target:    mapping.Target,
+			})
+		}
+		var temporalEncoding *TemporalEncodingConfig
+		if cloned.Sink.TemporalEncoding != nil {
+			temporalEncoding = &TemporalEncodingConfig{
+				Protocols:      cloned.Sink.TemporalEncoding.Protocols,
+				Mode:           cloned.Sink.TemporalEncoding.Mode,
+				SourceTimeZone: cloned.Sink.TemporalEncoding.SourceTimeZone,
+				TimeZone:       cloned.Sink.TemporalEncoding.TimeZone,
+			}
+		}
+		var messageHeaders *MessageHeadersConfig
+		if cloned.Sink.MessageHeaders != nil {
+			messageHeaders = &MessageHeadersConfig{
+				EnableMetadata: cloned.Sink.MessageHeaders.EnableMetadata,
+				StaticHeaders:  cloned.Sink.MessageHeaders.StaticHeaders,
+			}
+		}
+		var bootstrap *BootstrapConfig
+		if cloned.Sink.Bootstrap != nil {
+			bootstrap = &BootstrapConfig{
+				Enable:         cloned.Sink.Bootstrap.Enable,
+				SnapshotMarker: cloned.Sink.Bootstrap.SnapshotMarker,
+			}
+		}
+		var tableMetrics *TableMetricsConfig
+		if cloned.Sink.TableMetrics != nil {
+			tableMetrics = &TableMetricsConfig{
+				Enable:    cloned.Sink.TableMetrics.Enable,
+				MaxTables: cloned.Sink.TableMetrics.MaxTables,
+			}
+		}
+		var schemaRegistryConfig *SchemaRegistryConfig
+		if cloned.Sink.SchemaRegistryConfig != nil {
+			schemaRegistryConfig = &SchemaRegistryConfig{
+				BasicAuthUser:       cloned.Sink.SchemaRegistryConfig.BasicAuthUser,
+				BasicAuthPassword:   cloned.Sink.SchemaRegistryConfig.BasicAuthPassword,
+				BearerToken:         cloned.Sink.SchemaRegistryConfig.BearerToken,
+				CA:                  cloned.Sink.SchemaRegistryConfig.CA,
+				Cert:                cloned.Sink.SchemaRegistryConfig.Cert,
+				Key:                 cloned.Sink.SchemaRegistryConfig.Key,
+				SubjectNameStrategy: cloned.Sink.SchemaRegistryConfig.SubjectNameStrategy,
+				SubjectPrefix:       cloned.Sink.SchemaRegistryConfig.SubjectPrefix,
+				SubjectSuffix:       cloned.Sink.SchemaRegistryConfig.SubjectSuffix,
+				Provider:            cloned.Sink.SchemaRegistryConfig.Provider,
+				GlueRegistryName:    cloned.Sink.SchemaRegistryConfig.GlueRegistryName,
+				GlueRegion:          cloned.Sink.SchemaRegistryConfig.GlueRegion,
+				ApicurioGroupID:     cloned.Sink.SchemaRegistryConfig.ApicurioGroupID,
+			}
+		}
+		var tableSinkBuffer *TableSinkBufferConfig
+		if cloned.Sink.TableSinkBuffer != nil {
+			tableSinkBuffer = &TableSinkBufferConfig{
+				Quota:     cloned.Sink.TableSinkBuffer.Quota,
+				Policy:    cloned.Sink.TableSinkBuffer.Policy,
+				DiskQuota: cloned.Sink.TableSinkBuffer.DiskQuota,
+			}
+		}
 		var csvConfig *CSVConfig
 		if cloned.Sink.CSVConfig != nil {
 			csvConfig = &CSVConfig{
@@ -520,6 +1179,16 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				NullString:           cloned.Sink.CSVConfig.NullString,
 				IncludeCommitTs:      cloned.Sink.CSVConfig.IncludeCommitTs,
 				BinaryEncodingMethod: cloned.Sink.CSVConfig.BinaryEncodingMethod,
+				IncludeHeader:        cloned.Sink.CSVConfig.IncludeHeader,
+				OutputSchemaSidecar:  cloned.Sink.CSVConfig.OutputSchemaSidecar,
+				QuotingPolicy:        cloned.Sink.CSVConfig.QuotingPolicy,
+				EscapeChar:           cloned.Sink.CSVConfig.EscapeChar,
+			}
+			for _, r := range cloned.Sink.CSVConfig.ColumnRules {
+				csvConfig.ColumnRules = append(csvConfig.ColumnRules, &CSVColumnRule{
+					Matcher: r.Matcher,
+					Columns: r.Columns,
+				})
 			}
 		}
 		var kafkaConfig *KafkaConfig
@@ -540,9 +1209,13 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			if cloned.Sink.KafkaConfig.LargeMessageHandle != nil {
 				oldConfig := cloned.Sink.KafkaConfig.LargeMessageHandle
 				largeMessageHandle = &LargeMessageHandleConfig{
-					LargeMessageHandleOption: oldConfig.LargeMessageHandleOption,
-					ClaimCheckStorageURI:     oldConfig.ClaimCheckStorageURI,
-					ClaimCheckCompression:    oldConfig.ClaimCheckCompression,
+					LargeMessageHandleOption:     oldConfig.LargeMessageHandleOption,
+					ClaimCheckStorageURI:         oldConfig.ClaimCheckStorageURI,
+					ClaimCheckCompression:        oldConfig.ClaimCheckCompression,
+					ClaimCheckBareMessage:        oldConfig.ClaimCheckBareMessage,
+					ClaimCheckRetention:          oldConfig.ClaimCheckRetention,
+					ClaimCheckPresignedURLExpiry: oldConfig.ClaimCheckPresignedURLExpiry,
+					LargeMessageCompression:      oldConfig.LargeMessageCompression,
 				}
 			}
 
@@ -575,6 +1248,11 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				SASLOAuthScopes:              cloned.Sink.KafkaConfig.SASLOAuthScopes,
 				SASLOAuthGrantType:           cloned.Sink.KafkaConfig.SASLOAuthGrantType,
 				SASLOAuthAudience:            cloned.Sink.KafkaConfig.SASLOAuthAudience,
+				SASLOAuthAssertionKey:        cloned.Sink.KafkaConfig.SASLOAuthAssertionKey,
+				SASLOAuthAssertionKeyID:      cloned.Sink.KafkaConfig.SASLOAuthAssertionKeyID,
+				SASLAWSRegion:                cloned.Sink.KafkaConfig.SASLAWSRegion,
+				SASLAWSRoleARN:               cloned.Sink.KafkaConfig.SASLAWSRoleARN,
+				SASLAWSRoleSessionName:       cloned.Sink.KafkaConfig.SASLAWSRoleSessionName,
 				EnableTLS:                    cloned.Sink.KafkaConfig.EnableTLS,
 				CA:                           cloned.Sink.KafkaConfig.CA,
 				Cert:                         cloned.Sink.KafkaConfig.Cert,
@@ -582,10 +1260,38 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				InsecureSkipVerify:           cloned.Sink.KafkaConfig.InsecureSkipVerify,
 				CodecConfig:                  codeConfig,
 				LargeMessageHandle:           largeMessageHandle,
+				DeleteTopicsOnRemove:         cloned.Sink.KafkaConfig.DeleteTopicsOnRemove,
+				DNSSRVDiscovery:              cloned.Sink.KafkaConfig.DNSSRVDiscovery,
+				EnableKafkaTransactions:      cloned.Sink.KafkaConfig.EnableKafkaTransactions,
+				DeadLetterTopic:              cloned.Sink.KafkaConfig.DeadLetterTopic,
+				DeadLetterStorageURI:         cloned.Sink.KafkaConfig.DeadLetterStorageURI,
+				DDLTopic:                     cloned.Sink.KafkaConfig.DDLTopic,
+				EventHubs:                    cloned.Sink.KafkaConfig.EventHubs,
+				EventHubsConnectionString:    cloned.Sink.KafkaConfig.EventHubsConnectionString,
+				Linger:                       cloned.Sink.KafkaConfig.Linger,
+				BatchBytes:                   cloned.Sink.KafkaConfig.BatchBytes,
+				MaxInflight:                  cloned.Sink.KafkaConfig.MaxInflight,
+				CompressionLevel:             cloned.Sink.KafkaConfig.CompressionLevel,
+				TopicConfigs:                 cloned.Sink.KafkaConfig.TopicConfigs,
 			}
 		}
 		var mysqlConfig *MySQLConfig
 		if cloned.Sink.MySQLConfig != nil {
+			var ddlTranslation *DDLTranslationConfig
+			if cloned.Sink.MySQLConfig.DDLTranslation != nil {
+				var rules []*DDLTranslationRule
+				for _, r := range cloned.Sink.MySQLConfig.DDLTranslation.Rules {
+					rules = append(rules, &DDLTranslationRule{
+						Pattern:     r.Pattern,
+						Replacement: r.Replacement,
+						Skip:        r.Skip,
+					})
+				}
+				ddlTranslation = &DDLTranslationConfig{
+					Dialect: cloned.Sink.MySQLConfig.DDLTranslation.Dialect,
+					Rules:   rules,
+				}
+			}
 			mysqlConfig = &MySQLConfig{
 				WorkerCount:                  cloned.Sink.MySQLConfig.WorkerCount,
 				MaxTxnRow:                    cloned.Sink.MySQLConfig.MaxTxnRow,
@@ -602,35 +1308,154 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				EnableBatchDML:               cloned.Sink.MySQLConfig.EnableBatchDML,
 				EnableMultiStatement:         cloned.Sink.MySQLConfig.EnableMultiStatement,
 				EnableCachePreparedStatement: cloned.Sink.MySQLConfig.EnableCachePreparedStatement,
+				ConflictStrategy:             cloned.Sink.MySQLConfig.ConflictStrategy,
+				ConflictTimestampColumn:      cloned.Sink.MySQLConfig.ConflictTimestampColumn,
+				EnableAsyncDDL:               cloned.Sink.MySQLConfig.EnableAsyncDDL,
+				EnableBatchUpsert:            cloned.Sink.MySQLConfig.EnableBatchUpsert,
+				DMLMaxOpenConns:              cloned.Sink.MySQLConfig.DMLMaxOpenConns,
+				MetaMaxOpenConns:             cloned.Sink.MySQLConfig.MetaMaxOpenConns,
+				PoolHealthCheckInterval:      cloned.Sink.MySQLConfig.PoolHealthCheckInterval,
+				DDLTranslation:               ddlTranslation,
 			}
 		}
 		var cloudStorageConfig *CloudStorageConfig
 		if cloned.Sink.CloudStorageConfig != nil {
+			var tableOverrides []*CloudStorageTableOverride
+			for _, o := range cloned.Sink.CloudStorageConfig.TableOverrides {
+				tableOverrides = append(tableOverrides, &CloudStorageTableOverride{
+					Matcher:       o.Matcher,
+					FlushInterval: o.FlushInterval,
+					FileSize:      o.FileSize,
+				})
+			}
+			var redshiftConfig *RedshiftConfig
+			if r := cloned.Sink.CloudStorageConfig.RedshiftConfig; r != nil {
+				redshiftConfig = &RedshiftConfig{
+					ClusterIdentifier: r.ClusterIdentifier,
+					WorkgroupName:     r.WorkgroupName,
+					Database:          r.Database,
+					DBUser:            r.DBUser,
+					SecretARN:         r.SecretARN,
+					IAMRole:           r.IAMRole,
+					Region:            r.Region,
+					Schema:            r.Schema,
+				}
+			}
 			cloudStorageConfig = &CloudStorageConfig{
-				WorkerCount:    cloned.Sink.CloudStorageConfig.WorkerCount,
-				FlushInterval:  cloned.Sink.CloudStorageConfig.FlushInterval,
-				FileSize:       cloned.Sink.CloudStorageConfig.FileSize,
-				OutputColumnID: cloned.Sink.CloudStorageConfig.OutputColumnID,
+				WorkerCount:          cloned.Sink.CloudStorageConfig.WorkerCount,
+				FlushInterval:        cloned.Sink.CloudStorageConfig.FlushInterval,
+				FileSize:             cloned.Sink.CloudStorageConfig.FileSize,
+				OutputColumnID:       cloned.Sink.CloudStorageConfig.OutputColumnID,
+				FileNameTemplate:     cloned.Sink.CloudStorageConfig.FileNameTemplate,
+				OutputManifest:       cloned.Sink.CloudStorageConfig.OutputManifest,
+				OutputCompleteMarker: cloned.Sink.CloudStorageConfig.OutputCompleteMarker,
+				ParquetCompression:   cloned.Sink.CloudStorageConfig.ParquetCompression,
+				ParquetRowGroupSize:  cloned.Sink.CloudStorageConfig.ParquetRowGroupSize,
+				Compression:          cloned.Sink.CloudStorageConfig.Compression,
+				TableOverrides:       tableOverrides,
+				RedshiftConfig:       redshiftConfig,
+				EnableDeltaLake:      cloned.Sink.CloudStorageConfig.EnableDeltaLake,
+			}
+		}
+
+		var clickhouseConfig *ClickHouseConfig
+		if cloned.Sink.ClickHouseConfig != nil {
+			clickhouseConfig = &ClickHouseConfig{
+				Engine:        cloned.Sink.ClickHouseConfig.Engine,
+				VersionColumn: cloned.Sink.ClickHouseConfig.VersionColumn,
+				SignColumn:    cloned.Sink.ClickHouseConfig.SignColumn,
+				WorkerCount:   cloned.Sink.ClickHouseConfig.WorkerCount,
+				MaxBatchRows:  cloned.Sink.ClickHouseConfig.MaxBatchRows,
+			}
+		}
+
+		var elasticsearchConfig *ElasticsearchConfig
+		if cloned.Sink.ElasticsearchConfig != nil {
+			var indexRules []*ElasticsearchIndexRule
+			for _, r := range cloned.Sink.ElasticsearchConfig.IndexRules {
+				indexRules = append(indexRules, &ElasticsearchIndexRule{
+					Matcher: r.Matcher,
+					Index:   r.Index,
+				})
+			}
+			elasticsearchConfig = &ElasticsearchConfig{
+				Index:        cloned.Sink.ElasticsearchConfig.Index,
+				IndexRules:   indexRules,
+				WorkerCount:  cloned.Sink.ElasticsearchConfig.WorkerCount,
+				MaxBatchRows: cloned.Sink.ElasticsearchConfig.MaxBatchRows,
+			}
+		}
+
+		var redisConfig *RedisConfig
+		if cloned.Sink.RedisConfig != nil {
+			var keyRules []*RedisKeyRule
+			for _, r := range cloned.Sink.RedisConfig.KeyRules {
+				keyRules = append(keyRules, &RedisKeyRule{
+					Matcher: r.Matcher,
+					Key:     r.Key,
+				})
+			}
+			redisConfig = &RedisConfig{
+				Mode:         cloned.Sink.RedisConfig.Mode,
+				ValueFormat:  cloned.Sink.RedisConfig.ValueFormat,
+				Key:          cloned.Sink.RedisConfig.Key,
+				KeyRules:     keyRules,
+				Channel:      cloned.Sink.RedisConfig.Channel,
+				WorkerCount:  cloned.Sink.RedisConfig.WorkerCount,
+				MaxBatchRows: cloned.Sink.RedisConfig.MaxBatchRows,
+			}
+		}
+
+		var webhookConfig *WebhookConfig
+		if cloned.Sink.WebhookConfig != nil {
+			webhookConfig = &WebhookConfig{
+				Headers:         cloned.Sink.WebhookConfig.Headers,
+				WorkerCount:     cloned.Sink.WebhookConfig.WorkerCount,
+				MaxBatchRows:    cloned.Sink.WebhookConfig.MaxBatchRows,
+				MaxRetries:      cloned.Sink.WebhookConfig.MaxRetries,
+				RetryBackoff:    cloned.Sink.WebhookConfig.RetryBackoff,
+				MaxRetryBackoff: cloned.Sink.WebhookConfig.MaxRetryBackoff,
 			}
 		}
 
 		res.Sink = &SinkConfig{
 			Protocol:                         cloned.Sink.Protocol,
 			SchemaRegistry:                   cloned.Sink.SchemaRegistry,
+			SchemaRegistryConfig:             schemaRegistryConfig,
 			DispatchRules:                    dispatchRules,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
+			ColumnMaskers:                    columnMaskers,
+			ComputedColumns:                  computedColumns,
+			Targets:                          targets,
+			Mirror:                           mirror,
+			TableRoutes:                      tableRoutes,
+			Transformer:                      transformer,
+			TypeMappings:                     typeMappings,
+			TemporalEncoding:                 temporalEncoding,
 			EncoderConcurrency:               cloned.Sink.EncoderConcurrency,
 			Terminator:                       cloned.Sink.Terminator,
 			DateSeparator:                    cloned.Sink.DateSeparator,
 			EnablePartitionSeparator:         cloned.Sink.EnablePartitionSeparator,
 			FileIndexWidth:                   cloned.Sink.FileIndexWidth,
+			PartitionLayout:                  cloned.Sink.PartitionLayout,
 			EnableKafkaSinkV2:                cloned.Sink.EnableKafkaSinkV2,
 			OnlyOutputUpdatedColumns:         cloned.Sink.OnlyOutputUpdatedColumns,
 			DeleteOnlyOutputHandleKeyColumns: cloned.Sink.DeleteOnlyOutputHandleKeyColumns,
+			OutputPhysicalTime:               cloned.Sink.OutputPhysicalTime,
+			CanalJSONFlat:                    cloned.Sink.CanalJSONFlat,
+			DeleteAsTombstone:                cloned.Sink.DeleteAsTombstone,
+			MessageHeaders:                   messageHeaders,
+			Bootstrap:                        bootstrap,
+			TableMetrics:                     tableMetrics,
+			TableSinkBuffer:                  tableSinkBuffer,
 			KafkaConfig:                      kafkaConfig,
 			MySQLConfig:                      mysqlConfig,
 			CloudStorageConfig:               cloudStorageConfig,
+			ClickHouseConfig:                 clickhouseConfig,
+			ElasticsearchConfig:              elasticsearchConfig,
+			RedisConfig:                      redisConfig,
+			WebhookConfig:                    webhookConfig,
 			SafeMode:                         cloned.Sink.SafeMode,
 		}
 
@@ -645,6 +1470,10 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			FlushIntervalInMs: cloned.Consistent.FlushIntervalInMs,
 			Storage:           cloned.Consistent.Storage,
 			UseFileBackend:    cloned.Consistent.UseFileBackend,
+			FlushWorkerNum:    cloned.Consistent.FlushWorkerNum,
+			FlushConcurrency:  cloned.Consistent.FlushConcurrency,
+			Compression:       cloned.Consistent.Compression,
+			EncryptionKey:     cloned.Consistent.EncryptionKey,
 		}
 	}
 	if cloned.Mounter != nil {
@@ -667,6 +1496,25 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		}
 	}
 
+	if cloned.Snapshot != nil {
+		res.Snapshot = &SnapshotConfig{
+			Enable:    cloned.Snapshot.Enable,
+			ChunkSize: cloned.Snapshot.ChunkSize,
+			RateLimit: cloned.Snapshot.RateLimit,
+		}
+	}
+
+	if cloned.RestartStrategy != nil {
+		res.RestartStrategy = &RestartStrategyConfig{
+			MaxRetries:        cloned.RestartStrategy.MaxRetries,
+			InitialBackoff:    toJSONDuration(cloned.RestartStrategy.InitialBackoff),
+			MaxBackoff:        toJSONDuration(cloned.RestartStrategy.MaxBackoff),
+			BackoffMultiplier: cloned.RestartStrategy.BackoffMultiplier,
+			MaxElapsedTime:    toJSONDuration(cloned.RestartStrategy.MaxElapsedTime),
+			ResetWindow:       toJSONDuration(cloned.RestartStrategy.ResetWindow),
+		}
+	}
+
 	return res
 }
 
@@ -679,9 +1527,13 @@ func GetDefaultReplicaConfig() *ReplicaConfig {
 // This is a duplicate of config.FilterConfig
 type FilterConfig struct {
 	*MySQLReplicationRules
-	Rules            []string          `json:"rules,omitempty"`
-	IgnoreTxnStartTs []uint64          `json:"ignore_txn_start_ts,omitempty"`
-	EventFilters     []EventFilterRule `json:"event_filters,omitempty"`
+	Rules             []string          `json:"rules,omitempty"`
+	IgnoreTxnStartTs  []uint64          `json:"ignore_txn_start_ts,omitempty"`
+	EventFilters      []EventFilterRule `json:"event_filters,omitempty"`
+	SamplingRate      float64           `json:"sampling_rate,omitempty"`
+	IgnoreLocalOrigin bool              `json:"ignore_local_origin,omitempty"`
+	DDLAllowList      []string          `json:"ddl_allow_list,omitempty"`
+	DDLDenyList       []string          `json:"ddl_deny_list,omitempty"`
 }
 
 // MounterConfig represents mounter config for a changefeed
@@ -700,6 +1552,7 @@ type EventFilterRule struct {
 	IgnoreUpdateNewValueExpr string `json:"ignore_update_new_value_expr"`
 	IgnoreUpdateOldValueExpr string `json:"ignore_update_old_value_expr"`
 	IgnoreDeleteValueExpr    string `json:"ignore_delete_value_expr"`
+	KeepValueExpr            string `json:"keep_value_expr"`
 }
 
 // ToInternalEventFilterRule converts EventFilterRule to *config.EventFilterRule
@@ -711,6 +1564,7 @@ func (e EventFilterRule) ToInternalEventFilterRule() *config.EventFilterRule {
 		IgnoreUpdateNewValueExpr: e.IgnoreUpdateNewValueExpr,
 		IgnoreUpdateOldValueExpr: e.IgnoreUpdateOldValueExpr,
 		IgnoreDeleteValueExpr:    e.IgnoreDeleteValueExpr,
+		KeepValueExpr:            e.KeepValueExpr,
 	}
 	if len(e.IgnoreEvent) != 0 {
 		res.IgnoreEvent = make([]bf.EventType, len(e.IgnoreEvent))
@@ -728,6 +1582,7 @@ func ToAPIEventFilterRule(er *config.EventFilterRule) EventFilterRule {
 		IgnoreUpdateNewValueExpr: er.IgnoreUpdateNewValueExpr,
 		IgnoreUpdateOldValueExpr: er.IgnoreUpdateOldValueExpr,
 		IgnoreDeleteValueExpr:    er.IgnoreDeleteValueExpr,
+		KeepValueExpr:            er.KeepValueExpr,
 	}
 	if len(er.Matcher) != 0 {
 		res.Matcher = make([]string, len(er.Matcher))
@@ -770,50 +1625,139 @@ type Table struct {
 // SinkConfig represents sink config for a changefeed
 // This is a duplicate of config.SinkConfig
 type SinkConfig struct {
-	Protocol                         *string             `json:"protocol,omitempty"`
-	SchemaRegistry                   *string             `json:"schema_registry,omitempty"`
-	CSVConfig                        *CSVConfig          `json:"csv,omitempty"`
-	DispatchRules                    []*DispatchRule     `json:"dispatchers,omitempty"`
-	ColumnSelectors                  []*ColumnSelector   `json:"column_selectors,omitempty"`
-	TxnAtomicity                     *string             `json:"transaction_atomicity,omitempty"`
-	EncoderConcurrency               *int                `json:"encoder_concurrency,omitempty"`
-	Terminator                       *string             `json:"terminator,omitempty"`
-	DateSeparator                    *string             `json:"date_separator,omitempty"`
-	EnablePartitionSeparator         *bool               `json:"enable_partition_separator,omitempty"`
-	FileIndexWidth                   *int                `json:"file_index_width,omitempty"`
-	EnableKafkaSinkV2                *bool               `json:"enable_kafka_sink_v2,omitempty"`
-	OnlyOutputUpdatedColumns         *bool               `json:"only_output_updated_columns,omitempty"`
-	DeleteOnlyOutputHandleKeyColumns *bool               `json:"delete_only_output_handle_key_columns"`
-	SafeMode                         *bool               `json:"safe_mode,omitempty"`
-	KafkaConfig                      *KafkaConfig        `json:"kafka_config,omitempty"`
-	MySQLConfig                      *MySQLConfig        `json:"mysql_config,omitempty"`
-	CloudStorageConfig               *CloudStorageConfig `json:"cloud_storage_config,omitempty"`
+	Protocol                         *string                 `json:"protocol,omitempty"`
+	SchemaRegistry                   *string                 `json:"schema_registry,omitempty"`
+	SchemaRegistryConfig             *SchemaRegistryConfig   `json:"schema_registry_config,omitempty"`
+	CSVConfig                        *CSVConfig              `json:"csv,omitempty"`
+	DispatchRules                    []*DispatchRule         `json:"dispatchers,omitempty"`
+	ColumnSelectors                  []*ColumnSelector       `json:"column_selectors,omitempty"`
+	ColumnMaskers                    []*ColumnMasker         `json:"column_maskers,omitempty"`
+	ComputedColumns                  []*ComputedColumn       `json:"computed_columns,omitempty"`
+	Targets                          []*SinkTarget           `json:"targets,omitempty"`
+	Mirror                           *SinkMirror             `json:"mirror,omitempty"`
+	TableRoutes                      []*TableRoute           `json:"table_routes,omitempty"`
+	Transformer                      *TransformerConfig      `json:"transformer,omitempty"`
+	TypeMappings                     []*TypeMapping          `json:"type_mappings,omitempty"`
+	TemporalEncoding                 *TemporalEncodingConfig `json:"temporal_encoding,omitempty"`
+	TxnAtomicity                     *string                 `json:"transaction_atomicity,omitempty"`
+	EncoderConcurrency               *int                    `json:"encoder_concurrency,omitempty"`
+	Terminator                       *string                 `json:"terminator,omitempty"`
+	DateSeparator                    *string                 `json:"date_separator,omitempty"`
+	EnablePartitionSeparator         *bool                   `json:"enable_partition_separator,omitempty"`
+	FileIndexWidth                   *int                    `json:"file_index_width,omitempty"`
+	PartitionLayout                  *string                 `json:"partition_layout,omitempty"`
+	EnableKafkaSinkV2                *bool                   `json:"enable_kafka_sink_v2,omitempty"`
+	OnlyOutputUpdatedColumns         *bool                   `json:"only_output_updated_columns,omitempty"`
+	DeleteOnlyOutputHandleKeyColumns *bool                   `json:"delete_only_output_handle_key_columns"`
+	OutputPhysicalTime               *bool                   `json:"output_physical_time,omitempty"`
+	CanalJSONFlat                    *bool                   `json:"canal_json_flat,omitempty"`
+	DeleteAsTombstone                *bool                   `json:"delete_as_tombstone,omitempty"`
+	MessageHeaders                   *MessageHeadersConfig   `json:"message_headers,omitempty"`
+	Bootstrap                        *BootstrapConfig        `json:"bootstrap,omitempty"`
+	TableMetrics                     *TableMetricsConfig     `json:"table_metrics,omitempty"`
+	TableSinkBuffer                  *TableSinkBufferConfig  `json:"table_sink_buffer,omitempty"`
+	SafeMode                         *bool                   `json:"safe_mode,omitempty"`
+	KafkaConfig                      *KafkaConfig            `json:"kafka_config,omitempty"`
+	MySQLConfig                      *MySQLConfig            `json:"mysql_config,omitempty"`
+	CloudStorageConfig               *CloudStorageConfig     `json:"cloud_storage_config,omitempty"`
+	ClickHouseConfig                 *ClickHouseConfig       `json:"clickhouse_config,omitempty"`
+	ElasticsearchConfig              *ElasticsearchConfig    `json:"elasticsearch_config,omitempty"`
+	RedisConfig                      *RedisConfig            `json:"redis_config,omitempty"`
+	WebhookConfig                    *WebhookConfig          `json:"webhook_config,omitempty"`
+}
+
+// SchemaRegistryConfig represents the provider, authentication, TLS and subject naming
+// of the schema registry.
+// This is the same as config.SchemaRegistryConfig
+type SchemaRegistryConfig struct {
+	BasicAuthUser       *string `json:"basic_auth_user,omitempty"`
+	BasicAuthPassword   *string `json:"basic_auth_password,omitempty"`
+	BearerToken         *string `json:"bearer_token,omitempty"`
+	CA                  *string `json:"ca,omitempty"`
+	Cert                *string `json:"cert,omitempty"`
+	Key                 *string `json:"key,omitempty"`
+	SubjectNameStrategy *string `json:"subject_name_strategy,omitempty"`
+	SubjectPrefix       *string `json:"subject_prefix,omitempty"`
+	SubjectSuffix       *string `json:"subject_suffix,omitempty"`
+	Provider            *string `json:"provider,omitempty"`
+	GlueRegistryName    *string `json:"glue_registry_name,omitempty"`
+	GlueRegion          *string `json:"glue_region,omitempty"`
+	ApicurioGroupID     *string `json:"apicurio_group_id,omitempty"`
+}
+
+// MessageHeadersConfig represents the headers attached to the MQ messages.
+// This is the same as config.MessageHeadersConfig
+type MessageHeadersConfig struct {
+	EnableMetadata *bool             `json:"enable_metadata,omitempty"`
+	StaticHeaders  map[string]string `json:"static_headers,omitempty"`
+}
+
+// BootstrapConfig represents the bootstrap messages sent to the MQ system.
+// This is the same as config.BootstrapConfig
+type BootstrapConfig struct {
+	Enable         *bool `json:"enable,omitempty"`
+	SnapshotMarker *bool `json:"snapshot_marker,omitempty"`
+}
+
+// TableMetricsConfig represents the per-table labeling of the sink metrics.
+// This is the same as config.TableMetricsConfig
+type TableMetricsConfig struct {
+	Enable    *bool `json:"enable,omitempty"`
+	MaxTables *int  `json:"max_tables,omitempty"`
+}
+
+// TableSinkBufferConfig represents the quota of the events buffered by the
+// table sinks of a changefeed.
+// This is the same as config.TableSinkBufferConfig
+type TableSinkBufferConfig struct {
+	Quota     *uint64 `json:"quota,omitempty"`
+	Policy    *string `json:"policy,omitempty"`
+	DiskQuota *uint64 `json:"disk_quota,omitempty"`
 }
 
 // CSVConfig denotes the csv config
 // This is the same as config.CSVConfig
 type CSVConfig struct {
-	Delimiter            string `json:"delimiter"`
-	Quote                string `json:"quote"`
-	NullString           string `json:"null"`
-	IncludeCommitTs      bool   `json:"include_commit_ts"`
-	BinaryEncodingMethod string `json:"binary_encoding_method"`
+	Delimiter            string           `json:"delimiter"`
+	Quote                string           `json:"quote"`
+	NullString           string           `json:"null"`
+	IncludeCommitTs      bool             `json:"include_commit_ts"`
+	BinaryEncodingMethod string           `json:"binary_encoding_method"`
+	IncludeHeader        string           `json:"include_header,omitempty"`
+	OutputSchemaSidecar  bool             `json:"output_schema_sidecar"`
+	QuotingPolicy        string           `json:"quoting_policy"`
+	EscapeChar           string           `json:"escape_char"`
+	ColumnRules          []*CSVColumnRule `json:"column_rules,omitempty"`
+}
+
+// CSVColumnRule represents the csv columns of the tables matched by the matcher
+type CSVColumnRule struct {
+	Matcher []string `json:"matcher"`
+	Columns []string `json:"columns"`
 }
 
 // LargeMessageHandleConfig denotes the large message handling config
 // This is the same as config.LargeMessageHandleConfig
 type LargeMessageHandleConfig struct {
-	LargeMessageHandleOption string `json:"large_message_handle_option"`
-	ClaimCheckStorageURI     string `json:"claim_check_storage_uri"`
-	ClaimCheckCompression    string `json:"claim_check_compression"`
+	LargeMessageHandleOption     string `json:"large_message_handle_option"`
+	ClaimCheckStorageURI         string `json:"claim_check_storage_uri"`
+	ClaimCheckCompression        string `json:"claim_check_compression"`
+	ClaimCheckBareMessage        bool   `json:"claim_check_bare_message"`
+	ClaimCheckRetention          string `json:"claim_check_retention,omitempty"`
+	ClaimCheckPresignedURLExpiry string `json:"claim_check_presigned_url_expiry,omitempty"`
+	LargeMessageCompression      string `json:"large_message_compression,omitempty"`
 }
 
 // DispatchRule represents partition rule for a table
 // This is a duplicate of config.DispatchRule
 type DispatchRule struct {
-	Matcher       []string `json:"matcher,omitempty"`
-	PartitionRule string   `json:"partition"`
-	TopicRule     string   `json:"topic"`
+	Matcher           []string          `json:"matcher,omitempty"`
+	PartitionRule     string            `json:"partition"`
+	TopicRule         string            `json:"topic"`
+	PartitionPlugin   string            `json:"partition_plugin,omitempty"`
+	KeyTemplate       string            `json:"key_template,omitempty"`
+	TopicPartitionNum int32             `json:"topic_partition_num,omitempty"`
+	TopicConfigs      map[string]string `json:"topic_configs,omitempty"`
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -823,6 +1767,86 @@ type ColumnSelector struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
+// ColumnMasker represents a masking rule of the columns in the matched tables.
+// This is a duplicate of config.ColumnMasker
+type ColumnMasker struct {
+	Matcher    []string `json:"matcher,omitempty"`
+	Columns    []string `json:"columns,omitempty"`
+	Mask       string   `json:"mask"`
+	Value      string   `json:"value,omitempty"`
+	KeepPrefix int      `json:"keep_prefix,omitempty"`
+	KeepSuffix int      `json:"keep_suffix,omitempty"`
+}
+
+// ComputedColumn represents a column appended to the rows of the matched tables.
+// This is a duplicate of config.ComputedColumn
+type ComputedColumn struct {
+	Matcher []string `json:"matcher,omitempty"`
+	Name    string   `json:"name"`
+	Value   string   `json:"value"`
+}
+
+// SinkTarget represents a downstream the events of the matched tables are
+// written to.
+// This is a duplicate of config.SinkTarget
+type SinkTarget struct {
+	Matcher  []string `json:"matcher"`
+	SinkURI  string   `json:"sink_uri"`
+	Protocol *string  `json:"protocol,omitempty"`
+}
+
+// SinkMirror represents the downstream the events of the sink-uri are written
+// to as well.
+// This is a duplicate of config.SinkMirror
+type SinkMirror struct {
+	SinkURI           string  `json:"sink_uri"`
+	Protocol          *string `json:"protocol,omitempty"`
+	EnableKafkaSinkV2 *bool   `json:"enable_kafka_sink_v2,omitempty"`
+}
+
+// TableRoute renames the matched tables in the downstream.
+// This is a duplicate of config.TableRoute
+type TableRoute struct {
+	Matcher       []string       `json:"matcher"`
+	TargetSchema  string         `json:"target_schema,omitempty"`
+	TargetTable   string         `json:"target_table,omitempty"`
+	Columns       []*ColumnRoute `json:"columns,omitempty"`
+	SourceColumns bool           `json:"source_columns,omitempty"`
+}
+
+// ColumnRoute renames a column in the downstream.
+// This is a duplicate of config.ColumnRoute
+type ColumnRoute struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// TransformerConfig represents a WASM module transforming the rows of the
+// matched tables.
+// This is a duplicate of config.TransformerConfig
+type TransformerConfig struct {
+	Matcher      []string `json:"matcher,omitempty"`
+	WASMFile     string   `json:"wasm_file"`
+	WASMChecksum string   `json:"wasm_sha256,omitempty"`
+}
+
+// TypeMapping overrides the encoding of the columns of the source type.
+// This is a duplicate of config.TypeMapping
+type TypeMapping struct {
+	Protocols []string `json:"protocols,omitempty"`
+	Source    string   `json:"source"`
+	Target    string   `json:"target"`
+}
+
+// TemporalEncodingConfig represents the encoding of the temporal columns.
+// This is a duplicate of config.TemporalEncodingConfig
+type TemporalEncodingConfig struct {
+	Protocols      []string `json:"protocols,omitempty"`
+	Mode           *string  `json:"mode,omitempty"`
+	SourceTimeZone *string  `json:"source_time_zone,omitempty"`
+	TimeZone       *string  `json:"time_zone,omitempty"`
+}
+
 // ConsistentConfig represents replication consistency config for a changefeed
 // This is a duplicate of config.ConsistentConfig
 type ConsistentConfig struct {
@@ -831,6 +1855,10 @@ type ConsistentConfig struct {
 	FlushIntervalInMs int64  `json:"flush_interval"`
 	Storage           string `json:"storage,omitempty"`
 	UseFileBackend    bool   `json:"use_file_backend"`
+	FlushWorkerNum    int    `json:"flush_worker_num"`
+	FlushConcurrency  int    `json:"flush_concurrency"`
+	Compression       string `json:"compression,omitempty"`
+	EncryptionKey     string `json:"encryption_key,omitempty"`
 }
 
 // ChangefeedSchedulerConfig is per changefeed scheduler settings.
@@ -852,6 +1880,25 @@ type IntegrityConfig struct {
 	CorruptionHandleLevel string `json:"corruption_handle_level"`
 }
 
+// SnapshotConfig represents the initial snapshot config for a changefeed.
+// This is a duplicate of config.SnapshotConfig
+type SnapshotConfig struct {
+	Enable    *bool `json:"enable,omitempty"`
+	ChunkSize *int  `json:"chunk_size,omitempty"`
+	RateLimit *int  `json:"rate_limit,omitempty"`
+}
+
+// RestartStrategyConfig represents the restart strategy of a changefeed.
+// This is a duplicate of config.RestartStrategyConfig
+type RestartStrategyConfig struct {
+	MaxRetries        *int          `json:"max_retries,omitempty"`
+	InitialBackoff    *JSONDuration `json:"initial_backoff,omitempty" swaggertype:"string"`
+	MaxBackoff        *JSONDuration `json:"max_backoff,omitempty" swaggertype:"string"`
+	BackoffMultiplier *float64      `json:"backoff_multiplier,omitempty"`
+	MaxElapsedTime    *JSONDuration `json:"max_elapsed_time,omitempty" swaggertype:"string"`
+	ResetWindow       *JSONDuration `json:"reset_window,omitempty" swaggertype:"string"`
+}
+
 // EtcdData contains key/value pair of etcd data
 type EtcdData struct {
 	Key   string `json:"key,omitempty"`
@@ -882,6 +1929,7 @@ type ChangeFeedInfo struct {
 	State          model.FeedState    `json:"state,omitempty"`
 	Error          *RunningError      `json:"error,omitempty"`
 	CreatorVersion string             `json:"creator_version,omitempty"`
+	PausedTables   []model.TableID    `json:"paused_tables,omitempty"`
 
 	ResolvedTs     uint64                    `json:"resolved_ts"`
 	CheckpointTs   uint64                    `json:"checkpoint_ts"`
@@ -896,6 +1944,7 @@ type RunningError struct {
 	Addr    string     `json:"addr"`
 	Code    string     `json:"code"`
 	Message string     `json:"message"`
+	Class   string     `json:"class,omitempty"`
 }
 
 // toCredential generates a security.Credential from a PDConfig
@@ -949,6 +1998,34 @@ type ProcessorDetail struct {
 	Tables []int64 `json:"table_ids"`
 }
 
+// TableProgress is the replication progress of a table span.
+type TableProgress struct {
+	TableID      int64  `json:"table_id"`
+	Span         string `json:"span"`
+	CaptureID    string `json:"capture_id"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	// CheckpointLagMs is the lag of the checkpoint when the stats are
+	// collected from the processor, it's 0 if the stats aren't collected.
+	CheckpointLagMs int64 `json:"checkpoint_lag_ms"`
+	// PendingEventCount is the number of events which are written to the
+	// sink but not flushed yet.
+	PendingEventCount uint64 `json:"pending_event_count"`
+	// SinkFlushLatencyMs is the moving average of the latency of flushing
+	// events to the sink.
+	SinkFlushLatencyMs uint64 `json:"sink_flush_latency_ms"`
+}
+
+// SkippedDDL is a DDL skipped by the filter of a changefeed.
+type SkippedDDL struct {
+	StartTs  uint64 `json:"start_ts"`
+	CommitTs uint64 `json:"commit_ts"`
+	Type     string `json:"type"`
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Query    string `json:"query"`
+}
+
 // Liveness is the liveness status of a capture.
 // Liveness can only be changed from alive to stopping, and no way back.
 type Liveness int32
@@ -1011,6 +2088,11 @@ type KafkaConfig struct {
 	SASLOAuthScopes              []string                  `json:"sasl_oauth_scopes,omitempty"`
 	SASLOAuthGrantType           *string                   `json:"sasl_oauth_grant_type,omitempty"`
 	SASLOAuthAudience            *string                   `json:"sasl_oauth_audience,omitempty"`
+	SASLOAuthAssertionKey        *string                   `json:"sasl_oauth_assertion_key,omitempty"`
+	SASLOAuthAssertionKeyID      *string                   `json:"sasl_oauth_assertion_key_id,omitempty"`
+	SASLAWSRegion                *string                   `json:"sasl_aws_region,omitempty"`
+	SASLAWSRoleARN               *string                   `json:"sasl_aws_role_arn,omitempty"`
+	SASLAWSRoleSessionName       *string                   `json:"sasl_aws_role_session_name,omitempty"`
 	EnableTLS                    *bool                     `json:"enable_tls,omitempty"`
 	CA                           *string                   `json:"ca,omitempty"`
 	Cert                         *string                   `json:"cert,omitempty"`
@@ -1018,33 +2100,150 @@ type KafkaConfig struct {
 	InsecureSkipVerify           *bool                     `json:"insecure_skip_verify,omitempty"`
 	CodecConfig                  *CodecConfig              `json:"codec_config,omitempty"`
 	LargeMessageHandle           *LargeMessageHandleConfig `json:"large_message_handle,omitempty"`
+	DeleteTopicsOnRemove         *bool                     `json:"delete_topics_on_remove,omitempty"`
+	DNSSRVDiscovery              *bool                     `json:"dns_srv_discovery,omitempty"`
+	EnableKafkaTransactions      *bool                     `json:"enable_kafka_transactions,omitempty"`
+	DeadLetterTopic              *string                   `json:"dead_letter_topic,omitempty"`
+	DeadLetterStorageURI         *string                   `json:"dead_letter_storage_uri,omitempty"`
+	DDLTopic                     *string                   `json:"ddl_topic,omitempty"`
+	EventHubs                    *bool                     `json:"event_hubs,omitempty"`
+	EventHubsConnectionString    *string                   `json:"event_hubs_connection_string,omitempty"`
+	Linger                       *string                   `json:"linger,omitempty"`
+	BatchBytes                   *int                      `json:"batch_bytes,omitempty"`
+	MaxInflight                  *int                      `json:"max_inflight,omitempty"`
+	CompressionLevel             *int                      `json:"compression_level,omitempty"`
+	TopicConfigs                 map[string]string         `json:"topic_configs,omitempty"`
 }
 
 // MySQLConfig represents a MySQL sink configuration
 type MySQLConfig struct {
-	WorkerCount                  *int    `json:"worker_count,omitempty"`
-	MaxTxnRow                    *int    `json:"max_txn_row,omitempty"`
-	MaxMultiUpdateRowSize        *int    `json:"max_multi_update_row_size,omitempty"`
-	MaxMultiUpdateRowCount       *int    `json:"max_multi_update_row_count,omitempty"`
-	TiDBTxnMode                  *string `json:"tidb_txn_mode,omitempty"`
-	SSLCa                        *string `json:"ssl_ca,omitempty"`
-	SSLCert                      *string `json:"ssl_cert,omitempty"`
-	SSLKey                       *string `json:"ssl_key,omitempty"`
-	TimeZone                     *string `json:"time_zone,omitempty"`
-	WriteTimeout                 *string `json:"write_timeout,omitempty"`
-	ReadTimeout                  *string `json:"read_timeout,omitempty"`
-	Timeout                      *string `json:"timeout,omitempty"`
-	EnableBatchDML               *bool   `json:"enable_batch_dml,omitempty"`
-	EnableMultiStatement         *bool   `json:"enable_multi_statement,omitempty"`
-	EnableCachePreparedStatement *bool   `json:"enable_cache_prepared_statement,omitempty"`
+	WorkerCount                  *int                  `json:"worker_count,omitempty"`
+	MaxTxnRow                    *int                  `json:"max_txn_row,omitempty"`
+	MaxMultiUpdateRowSize        *int                  `json:"max_multi_update_row_size,omitempty"`
+	MaxMultiUpdateRowCount       *int                  `json:"max_multi_update_row_count,omitempty"`
+	TiDBTxnMode                  *string               `json:"tidb_txn_mode,omitempty"`
+	SSLCa                        *string               `json:"ssl_ca,omitempty"`
+	SSLCert                      *string               `json:"ssl_cert,omitempty"`
+	SSLKey                       *string               `json:"ssl_key,omitempty"`
+	TimeZone                     *string               `json:"time_zone,omitempty"`
+	WriteTimeout                 *string               `json:"write_timeout,omitempty"`
+	ReadTimeout                  *string               `json:"read_timeout,omitempty"`
+	Timeout                      *string               `json:"timeout,omitempty"`
+	EnableBatchDML               *bool                 `json:"enable_batch_dml,omitempty"`
+	EnableMultiStatement         *bool                 `json:"enable_multi_statement,omitempty"`
+	EnableCachePreparedStatement *bool                 `json:"enable_cache_prepared_statement,omitempty"`
+	ConflictStrategy             *string               `json:"conflict_strategy,omitempty"`
+	ConflictTimestampColumn      *string               `json:"conflict_timestamp_column,omitempty"`
+	EnableAsyncDDL               *bool                 `json:"enable_async_ddl,omitempty"`
+	EnableBatchUpsert            *bool                 `json:"enable_batch_upsert,omitempty"`
+	DMLMaxOpenConns              *int                  `json:"dml_max_open_conns,omitempty"`
+	MetaMaxOpenConns             *int                  `json:"meta_max_open_conns,omitempty"`
+	PoolHealthCheckInterval      *string               `json:"pool_health_check_interval,omitempty"`
+	DDLTranslation               *DDLTranslationConfig `json:"ddl_translation,omitempty"`
+}
+
+// DDLTranslationConfig represents how the DDLs are rewritten for the
+// downstream which isn't TiDB
+type DDLTranslationConfig struct {
+	Dialect *string               `json:"dialect,omitempty"`
+	Rules   []*DDLTranslationRule `json:"rules,omitempty"`
+}
+
+// DDLTranslationRule rewrites or skips the DDLs matched by the pattern
+type DDLTranslationRule struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	Skip        bool   `json:"skip"`
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
 type CloudStorageConfig struct {
-	WorkerCount    *int    `json:"worker_count,omitempty"`
-	FlushInterval  *string `json:"flush_interval,omitempty"`
-	FileSize       *int    `json:"file_size,omitempty"`
-	OutputColumnID *bool   `json:"output_column_id,omitempty"`
+	WorkerCount          *int    `json:"worker_count,omitempty"`
+	FlushInterval        *string `json:"flush_interval,omitempty"`
+	FileSize             *int    `json:"file_size,omitempty"`
+	OutputColumnID       *bool   `json:"output_column_id,omitempty"`
+	FileNameTemplate     *string `json:"file_name_template,omitempty"`
+	OutputManifest       *bool   `json:"output_manifest,omitempty"`
+	OutputCompleteMarker *bool   `json:"output_complete_marker,omitempty"`
+
+	ParquetCompression  *string `json:"parquet_compression,omitempty"`
+	ParquetRowGroupSize *int    `json:"parquet_row_group_size,omitempty"`
+	Compression         *string `json:"compression,omitempty"`
+
+	TableOverrides  []*CloudStorageTableOverride `json:"table_overrides,omitempty"`
+	RedshiftConfig  *RedshiftConfig              `json:"redshift_config,omitempty"`
+	EnableDeltaLake *bool                        `json:"enable_delta_lake,omitempty"`
+}
+
+// CloudStorageTableOverride overrides the flush interval and file size of the
+// matched tables
+type CloudStorageTableOverride struct {
+	Matcher       []string `json:"matcher"`
+	FlushInterval *string  `json:"flush_interval,omitempty"`
+	FileSize      *int     `json:"file_size,omitempty"`
+}
+
+// RedshiftConfig represents the Amazon Redshift cluster which the data files
+// of the cloud storage sink are loaded into
+type RedshiftConfig struct {
+	ClusterIdentifier *string `json:"cluster_identifier,omitempty"`
+	WorkgroupName     *string `json:"workgroup_name,omitempty"`
+	Database          *string `json:"database,omitempty"`
+	DBUser            *string `json:"db_user,omitempty"`
+	SecretARN         *string `json:"secret_arn,omitempty"`
+	IAMRole           *string `json:"iam_role,omitempty"`
+	Region            *string `json:"region,omitempty"`
+	Schema            *string `json:"schema,omitempty"`
+}
+
+// ClickHouseConfig represents a ClickHouse sink configuration
+type ClickHouseConfig struct {
+	Engine        *string `json:"engine,omitempty"`
+	VersionColumn *string `json:"version_column,omitempty"`
+	SignColumn    *string `json:"sign_column,omitempty"`
+	WorkerCount   *int    `json:"worker_count,omitempty"`
+	MaxBatchRows  *int    `json:"max_batch_rows,omitempty"`
+}
+
+// ElasticsearchConfig represents an Elasticsearch sink configuration
+type ElasticsearchConfig struct {
+	Index        *string                   `json:"index,omitempty"`
+	IndexRules   []*ElasticsearchIndexRule `json:"index_rules,omitempty"`
+	WorkerCount  *int                      `json:"worker_count,omitempty"`
+	MaxBatchRows *int                      `json:"max_batch_rows,omitempty"`
+}
+
+// ElasticsearchIndexRule represents the index of the tables matched by the matcher
+type ElasticsearchIndexRule struct {
+	Matcher []string `json:"matcher"`
+	Index   string   `json:"index"`
+}
+
+// RedisConfig represents a Redis sink configuration
+type RedisConfig struct {
+	Mode         *string         `json:"mode,omitempty"`
+	ValueFormat  *string         `json:"value_format,omitempty"`
+	Key          *string         `json:"key,omitempty"`
+	KeyRules     []*RedisKeyRule `json:"key_rules,omitempty"`
+	Channel      *string         `json:"channel,omitempty"`
+	WorkerCount  *int            `json:"worker_count,omitempty"`
+	MaxBatchRows *int            `json:"max_batch_rows,omitempty"`
+}
+
+// RedisKeyRule represents the key of the rows of the tables matched by the matcher
+type RedisKeyRule struct {
+	Matcher []string `json:"matcher"`
+	Key     string   `json:"key"`
+}
+
+// WebhookConfig represents an HTTP webhook sink configuration
+type WebhookConfig struct {
+	Headers         map[string]string `json:"headers,omitempty"`
+	WorkerCount     *int              `json:"worker_count,omitempty"`
+	MaxBatchRows    *int              `json:"max_batch_rows,omitempty"`
+	MaxRetries      *int              `json:"max_retries,omitempty"`
+	RetryBackoff    *string           `json:"retry_backoff,omitempty"`
+	MaxRetryBackoff *string           `json:"max_retry_backoff,omitempty"`
 }
 
 // ChangefeedStatus holds common information of a changefeed in cdc
@@ -1055,3 +2254,24 @@ type ChangefeedStatus struct {
 	LastError    *RunningError `json:"last_error,omitempty"`
 	LastWarning  *RunningError `json:"last_warning,omitempty"`
 }
+
+// SavepointConfig is used by the create savepoint api
+type SavepointConfig struct {
+	Name string `json:"name"`
+	// GCTTL is the TTL in seconds of the service GC safepoint of the savepoint,
+	// the changefeeds must be created from the savepoint before it expires.
+	GCTTL int64 `json:"gc_ttl,omitempty"`
+}
+
+// Savepoint is a named snapshot of the checkpoint of a changefeed
+type Savepoint struct {
+	Namespace    string         `json:"namespace"`
+	Name         string         `json:"name"`
+	ChangefeedID string         `json:"changefeed_id"`
+	UpstreamID   uint64         `json:"upstream_id"`
+	CheckpointTs uint64         `json:"checkpoint_ts"`
+	SinkURI      string         `json:"sink_uri"`
+	CreateTime   time.Time      `json:"create_time"`
+	ExpireTime   time.Time      `json:"expire_time"`
+	Config       *ReplicaConfig `json:"config,omitempty"`
+}
@@ -0,0 +1,287 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/util"
+	pd "github.com/tikv/pd/client"
+	"go.uber.org/zap"
+)
+
+const (
+	// apiOpVarSavepointName is the key of savepoint name in HTTP API
+	apiOpVarSavepointName = "savepoint_name"
+	// defaultSavepointGCTTL is the default TTL of the service GC safepoint
+	// of a savepoint, aka 24 hours.
+	defaultSavepointGCTTL = 24 * 60 * 60
+)
+
+// createSavepoint handles create savepoint request
+// @Summary Create a savepoint of a changefeed
+// @Description Take a named savepoint of the checkpoint of a changefeed, new changefeeds can be created from it
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "default"
+// @Param savepoint body SavepointConfig true "savepoint config"
+// @Success 200 {object} Savepoint
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/savepoints [post]
+func (h *OpenAPIV2) createSavepoint(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(apiOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := &SavepointConfig{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if err := model.ValidateChangefeedID(cfg.Name); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid savepoint name: %s",
+			cfg.Name))
+		return
+	}
+	if cfg.GCTTL < 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid gc_ttl: %d", cfg.GCTTL))
+		return
+	}
+	if cfg.GCTTL == 0 {
+		cfg.GCTTL = defaultSavepointGCTTL
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	savepoint := &model.Savepoint{
+		Namespace:    namespace,
+		Name:         cfg.Name,
+		ChangefeedID: changefeedID.ID,
+		UpstreamID:   info.UpstreamID,
+		CheckpointTs: status.CheckpointTs,
+		SinkURI:      info.SinkURI,
+		CreateTime:   now,
+		ExpireTime:   now.Add(time.Duration(cfg.GCTTL) * time.Second),
+		Config:       info.Config,
+	}
+	// the savepoint is saved before its service GC safepoint is set, so the
+	// service GC safepoint of an existing savepoint is never overwritten.
+	etcdClient := h.capture.GetEtcdClient()
+	if err := etcdClient.CreateSavepoint(ctx, savepoint); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = h.withUpstreamConfig(ctx, &UpstreamConfig{ID: info.UpstreamID},
+		func(ctx context.Context, pdClient pd.Client) error {
+			return gc.EnsureSavepointSafety(ctx, pdClient,
+				etcdClient.GetEnsureGCServiceID(gc.EnsureGCServiceSavepoint),
+				namespace, cfg.Name, cfg.GCTTL, status.CheckpointTs)
+		})
+	if err != nil {
+		if err := etcdClient.DeleteSavepoint(ctx, namespace, cfg.Name); err != nil {
+			log.Warn("failed to delete the savepoint",
+				zap.String("namespace", namespace),
+				zap.String("savepoint", cfg.Name),
+				zap.Error(err))
+		}
+		_ = c.Error(err)
+		return
+	}
+	log.Info("savepoint created",
+		zap.String("namespace", namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("savepoint", cfg.Name),
+		zap.Uint64("checkpointTs", status.CheckpointTs))
+	c.JSON(http.StatusOK, toAPISavepoint(savepoint))
+}
+
+// listSavepoints lists all savepoints of a namespace
+// @Summary List savepoints
+// @Description list all savepoints of a namespace
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param namespace query string false "default"
+// @Success 200 {array} Savepoint
+// @Failure 500 {object} model.HTTPError
+// @Router /api/v2/savepoints [get]
+func (h *OpenAPIV2) listSavepoints(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	savepoints, err := h.capture.GetEtcdClient().GetSavepoints(ctx, namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	items := make([]Savepoint, 0, len(savepoints))
+	for _, savepoint := range savepoints {
+		items = append(items, *toAPISavepoint(savepoint))
+	}
+	c.JSON(http.StatusOK, &ListResponse[Savepoint]{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+// getSavepoint gets a savepoint by its name
+// @Summary Get savepoint
+// @Description get the detail of a savepoint
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param savepoint_name path string true "savepoint_name"
+// @Param namespace query string false "default"
+// @Success 200 {object} Savepoint
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/savepoints/{savepoint_name} [get]
+func (h *OpenAPIV2) getSavepoint(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	savepoint, err := h.capture.GetEtcdClient().GetSavepoint(ctx,
+		namespace, c.Param(apiOpVarSavepointName))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPISavepoint(savepoint))
+}
+
+// deleteSavepoint deletes a savepoint and its service GC safepoint
+// @Summary Remove a savepoint
+// @Description Remove a savepoint and its service GC safepoint
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param savepoint_name path string true "savepoint_name"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/savepoints/{savepoint_name} [delete]
+func (h *OpenAPIV2) deleteSavepoint(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	name := c.Param(apiOpVarSavepointName)
+	etcdClient := h.capture.GetEtcdClient()
+	savepoint, err := etcdClient.GetSavepoint(ctx, namespace, name)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = h.withUpstreamConfig(ctx, &UpstreamConfig{ID: savepoint.UpstreamID},
+		func(ctx context.Context, pdClient pd.Client) error {
+			return gc.RemoveSavepointSafety(ctx, pdClient,
+				etcdClient.GetEnsureGCServiceID(gc.EnsureGCServiceSavepoint),
+				namespace, name)
+		})
+	if err != nil {
+		// the service GC safepoint is removed by PD after the TTL expires.
+		log.Warn("failed to remove the service GC safepoint of the savepoint",
+			zap.String("namespace", namespace),
+			zap.String("savepoint", name),
+			zap.Error(err))
+	}
+	if err := etcdClient.DeleteSavepoint(ctx, namespace, name); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// applySavepoint makes the changefeed to be created start from the checkpoint
+// of the savepoint. The replica config of the savepoint is the base of the
+// replica config in the request, so only the changed items need to be set.
+func (h *OpenAPIV2) applySavepoint(
+	c *gin.Context, cfg *ChangefeedConfig, pdClient pd.Client,
+) (*ChangefeedConfig, error) {
+	ctx := c.Request.Context()
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	savepoint, err := h.capture.GetEtcdClient().GetSavepoint(ctx, namespace, cfg.Savepoint)
+	if err != nil {
+		return nil, err
+	}
+	if savepoint.IsExpired(time.Now()) {
+		return nil, cerror.ErrSavepointExpired.GenWithStackByArgs(
+			savepoint.Name, savepoint.ExpireTime)
+	}
+	if upstreamID := pdClient.GetClusterID(ctx); upstreamID != savepoint.UpstreamID {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+			"the upstream %d is different from the upstream %d of the savepoint %s",
+			upstreamID, savepoint.UpstreamID, savepoint.Name)
+	}
+	if cfg.StartTs != 0 && cfg.StartTs != savepoint.CheckpointTs {
+		return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+			"start_ts %d is different from the checkpoint ts %d of the savepoint %s",
+			cfg.StartTs, savepoint.CheckpointTs, savepoint.Name)
+	}
+
+	newCfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
+	if savepoint.Config != nil {
+		newCfg.ReplicaConfig = ToAPIReplicaConfig(savepoint.Config)
+	}
+	if err := c.ShouldBindBodyWith(newCfg, binding.JSON); err != nil {
+		return nil, cerror.WrapError(cerror.ErrAPIInvalidParam, err)
+	}
+	newCfg.PDConfig = cfg.PDConfig
+	newCfg.StartTs = savepoint.CheckpointTs
+	return newCfg, nil
+}
+
+func toAPISavepoint(savepoint *model.Savepoint) *Savepoint {
+	sinkURI, err := util.MaskSinkURI(savepoint.SinkURI)
+	if err != nil {
+		log.Error("failed to mask sink URI", zap.Error(err))
+	}
+	var replicaConfig *ReplicaConfig
+	if savepoint.Config != nil {
+		replicaConfig = ToAPIReplicaConfig(savepoint.Config)
+	}
+	return &Savepoint{
+		Namespace:    savepoint.Namespace,
+		Name:         savepoint.Name,
+		ChangefeedID: savepoint.ChangefeedID,
+		UpstreamID:   savepoint.UpstreamID,
+		CheckpointTs: savepoint.CheckpointTs,
+		SinkURI:      sinkURI,
+		CreateTime:   savepoint.CreateTime,
+		ExpireTime:   savepoint.ExpireTime,
+		Config:       replicaConfig,
+	}
+}
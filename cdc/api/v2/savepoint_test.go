@@ -0,0 +1,251 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSavepoint(t *testing.T) {
+	create := testCase{url: "/api/v2/changefeeds/%s/savepoints?namespace=abc", method: "POST"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	statusProvider := &mockStatusProvider{}
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsController().Return(true).AnyTimes()
+
+	// case 1: invalid savepoint name
+	validID := changeFeedID.ID
+	body, err := json.Marshal(&SavepointConfig{Name: "@^Invalid"})
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), create.method,
+		fmt.Sprintf(create.url, validID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: changefeed not exists
+	body, err = json.Marshal(&SavepointConfig{Name: "before-migration"})
+	require.Nil(t, err)
+	statusProvider.err = cerrors.ErrChangeFeedNotExists.GenWithStackByArgs(validID)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		fmt.Sprintf(create.url, validID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 3: savepoint already exists
+	statusProvider.err = nil
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{
+		ID:      validID,
+		SinkURI: "kafka://127.0.0.1:9092/test?sasl-password=secret",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	statusProvider.changefeedStatus = &model.ChangeFeedStatusForAPI{CheckpointTs: 100}
+	etcdClient.EXPECT().CreateSavepoint(gomock.Any(), gomock.Any()).
+		Return(cerrors.ErrSavepointAlreadyExists.GenWithStackByArgs("before-migration")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		fmt.Sprintf(create.url, validID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrSavepointAlreadyExists")
+
+	// case 4: success
+	etcdClient.EXPECT().CreateSavepoint(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, savepoint *model.Savepoint) error {
+			require.Equal(t, "abc", savepoint.Namespace)
+			require.Equal(t, "before-migration", savepoint.Name)
+			require.Equal(t, validID, savepoint.ChangefeedID)
+			require.Equal(t, uint64(100), savepoint.CheckpointTs)
+			require.Equal(t, statusProvider.changefeedInfo.SinkURI, savepoint.SinkURI)
+			require.Equal(t, time.Duration(defaultSavepointGCTTL)*time.Second,
+				savepoint.ExpireTime.Sub(savepoint.CreateTime))
+			return nil
+		}).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		fmt.Sprintf(create.url, validID), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := Savepoint{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), resp.CheckpointTs)
+	require.Equal(t, "kafka://127.0.0.1:9092/test?sasl-password=xxxxx", resp.SinkURI)
+}
+
+func TestDeleteSavepoint(t *testing.T) {
+	remove := testCase{url: "/api/v2/savepoints/%s", method: "DELETE"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsController().Return(true).AnyTimes()
+
+	// case 1: savepoint not exists
+	etcdClient.EXPECT().GetSavepoint(gomock.Any(), model.DefaultNamespace, "not-exists").
+		Return(nil, cerrors.ErrSavepointNotExists.GenWithStackByArgs("not-exists")).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), remove.method,
+		fmt.Sprintf(remove.url, "not-exists"), nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrSavepointNotExists")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 2: success
+	etcdClient.EXPECT().GetSavepoint(gomock.Any(), model.DefaultNamespace, "before-migration").
+		Return(&model.Savepoint{Name: "before-migration"}, nil).Times(1)
+	etcdClient.EXPECT().DeleteSavepoint(gomock.Any(), model.DefaultNamespace, "before-migration").
+		Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), remove.method,
+		fmt.Sprintf(remove.url, "before-migration"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestListSavepoints(t *testing.T) {
+	list := testCase{url: "/api/v2/savepoints?namespace=abc", method: "GET"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsController().Return(true).AnyTimes()
+
+	etcdClient.EXPECT().GetSavepoints(gomock.Any(), "abc").
+		Return([]*model.Savepoint{
+			{Namespace: "abc", Name: "savepoint1", CheckpointTs: 100},
+			{Namespace: "abc", Name: "savepoint2", CheckpointTs: 200},
+		}, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[Savepoint]{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 2, resp.Total)
+	require.Equal(t, "savepoint2", resp.Items[1].Name)
+	require.Equal(t, uint64(200), resp.Items[1].CheckpointTs)
+}
+
+func TestApplySavepoint(t *testing.T) {
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t)))
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Filter.Rules = []string{"test.*"}
+	savepoint := &model.Savepoint{
+		Namespace:    model.DefaultNamespace,
+		Name:         "before-migration",
+		UpstreamID:   123,
+		CheckpointTs: 100,
+		ExpireTime:   time.Now().Add(time.Hour),
+		Config:       replicaConfig,
+	}
+	etcdClient.EXPECT().GetSavepoint(gomock.Any(), model.DefaultNamespace, "before-migration").
+		Return(savepoint, nil).AnyTimes()
+
+	apply := func(body string) (*ChangefeedConfig, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		cfg := &ChangefeedConfig{}
+		if err := json.Unmarshal([]byte(body), cfg); err != nil {
+			return nil, err
+		}
+		cfg.PDAddrs = []string{"http://127.0.0.1:2379"}
+		return apiV2.applySavepoint(c, cfg, &mockPDClient{})
+	}
+
+	// the replica config of the savepoint is the base of the config in the request.
+	cfg, err := apply(`{"sink_uri":"kafka://127.0.0.1:9093/test","savepoint":"before-migration",` +
+		`"replica_config":{"force_replicate":true}}`)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), cfg.StartTs)
+	require.Equal(t, "kafka://127.0.0.1:9093/test", cfg.SinkURI)
+	require.Equal(t, []string{"http://127.0.0.1:2379"}, cfg.PDAddrs)
+	require.True(t, cfg.ReplicaConfig.ForceReplicate)
+	require.Equal(t, []string{"test.*"}, cfg.ReplicaConfig.Filter.Rules)
+
+	// the start ts conflicts with the checkpoint ts of the savepoint.
+	_, err = apply(`{"savepoint":"before-migration","start_ts":99}`)
+	require.Regexp(t, "start_ts 99 is different from the checkpoint ts 100", err)
+
+	// the upstream is different.
+	savepoint.UpstreamID = 1
+	_, err = apply(`{"savepoint":"before-migration"}`)
+	require.Regexp(t, "is different from the upstream 1 of the savepoint", err)
+
+	// the savepoint is expired.
+	savepoint.ExpireTime = time.Now().Add(-time.Hour)
+	_, err = apply(`{"savepoint":"before-migration"}`)
+	require.True(t, cerrors.ErrSavepointExpired.Equal(err))
+}
@@ -41,10 +41,18 @@ import (
 	"go.uber.org/zap"
 )
 
+// cdcWriteSourceMask is the mask of the TiCDC write source in the txn source,
+// the TiCDC sinks set the write source to the origin of the written rows.
+const cdcWriteSourceMask = 0xff
+
 type baseKVEntry struct {
 	StartTs uint64
 	// Commit or resolved TS
 	CRTs uint64
+	// OriginID is the TiCDC write source of the transaction.
+	OriginID uint64
+	// IsSnapshot marks the row scanned from the initial snapshot.
+	IsSnapshot bool
 
 	PhysicalTableID int64
 	RecordID        kv.Handle
@@ -156,12 +164,19 @@ func (m *mounter) unmarshalAndMountRowChanged(ctx context.Context, raw *model.Ra
 	baseInfo := baseKVEntry{
 		StartTs:         raw.StartTs,
 		CRTs:            raw.CRTs,
+		OriginID:        raw.TxnSource & cdcWriteSourceMask,
+		IsSnapshot:      raw.Snapshot,
 		PhysicalTableID: physicalTableID,
 		Delete:          raw.OpType == model.OpTypeDelete,
 	}
 	// When async commit is enabled, the commitTs of DMLs may be equals with DDL finishedTs.
 	// A DML whose commitTs is equal to a DDL finishedTs should use the schema info before the DDL.
-	snap, err := m.schemaStorage.GetSnapshot(ctx, raw.CRTs-1)
+	// The snapshot rows are read at their CRTs, after the DDLs finished at it.
+	schemaTs := raw.CRTs - 1
+	if raw.Snapshot {
+		schemaTs = raw.CRTs
+	}
+	snap, err := m.schemaStorage.GetSnapshot(ctx, schemaTs)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -375,7 +390,7 @@ func datum2Column(
 				zap.String("column", colInfo.Name.String()))
 		}
 
-		defaultValue := GetDDLDefaultDefinition(colInfo)
+		defaultValue := model.GetDDLDefaultDefinition(colInfo)
 		offset := tableInfo.RowColumnsOffset[colID]
 		rawCols[offset] = colDatums
 		cols[offset] = &model.Column{
@@ -594,6 +609,8 @@ func (m *mounter) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntry, d
 
 		IndexColumns:        tableInfo.IndexColumnsOffset,
 		ApproximateDataSize: dataSize,
+		OriginID:            row.OriginID,
+		IsSnapshot:          row.IsSnapshot,
 	}, rawRow, nil
 }
 
@@ -742,16 +759,6 @@ func getDefaultOrZeroValue(col *timodel.ColumnInfo) (types.Datum, any, int, stri
 	return d, v, size, warn, err
 }
 
-// GetDDLDefaultDefinition returns the default definition of a column.
-func GetDDLDefaultDefinition(col *timodel.ColumnInfo) interface{} {
-	defaultValue := col.GetDefaultValue()
-	if defaultValue == nil {
-		defaultValue = col.GetOriginDefaultValue()
-	}
-	defaultDatum := types.NewDatum(defaultValue)
-	return defaultDatum.GetValue()
-}
-
 // DecodeTableID decodes the raw key to a table ID
 func DecodeTableID(key []byte) (model.TableID, error) {
 	_, physicalTableID, err := decodeTableID(key)
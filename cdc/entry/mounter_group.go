@@ -18,10 +18,13 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/integrity"
+	"github.com/pingcap/tiflow/pkg/transformer"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -38,6 +41,9 @@ type mounterGroup struct {
 	inputCh       chan *model.PolymorphicEvent
 	tz            *time.Location
 	filter        filter.Filter
+	masker        *filter.ColumnMasker
+	appender      *filter.ColumnAppender
+	transformer   transformer.Transformer
 	integrity     *integrity.Config
 
 	workerNum int
@@ -56,6 +62,9 @@ func NewMounterGroup(
 	schemaStorage SchemaStorage,
 	workerNum int,
 	filter filter.Filter,
+	masker *filter.ColumnMasker,
+	appender *filter.ColumnAppender,
+	transformer transformer.Transformer,
 	tz *time.Location,
 	changefeedID model.ChangeFeedID,
 	integrity *integrity.Config,
@@ -67,6 +76,9 @@ func NewMounterGroup(
 		schemaStorage: schemaStorage,
 		inputCh:       make(chan *model.PolymorphicEvent, defaultInputChanSize),
 		filter:        filter,
+		masker:        masker,
+		appender:      appender,
+		transformer:   transformer,
 		tz:            tz,
 
 		integrity: integrity,
@@ -105,7 +117,16 @@ func (m *mounterGroup) Run(ctx context.Context, _ ...chan<- error) error {
 
 func (m *mounterGroup) WaitForReady(_ context.Context) {}
 
-func (m *mounterGroup) Close() {}
+func (m *mounterGroup) Close() {
+	if m.transformer != nil {
+		if err := m.transformer.Close(); err != nil {
+			log.Warn("failed to close the transformer",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Error(err))
+		}
+	}
+}
 
 func (m *mounterGroup) runWorker(ctx context.Context) error {
 	mounter := NewMounter(m.schemaStorage, m.changefeedID, m.tz, m.filter, m.integrity)
@@ -122,6 +143,31 @@ func (m *mounterGroup) runWorker(ctx context.Context) error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+			// The columns are masked before the row is written to the sink and
+			// the redo log, so the original values never leave TiCDC.
+			if m.masker != nil && pEvent.Row != nil {
+				if err := m.masker.MaskRowChangedEvent(pEvent.Row); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			// The computed columns are appended after the columns are masked,
+			// so they are never masked.
+			if m.appender != nil && pEvent.Row != nil {
+				if err := m.appender.AppendRowChangedEvent(pEvent.Row); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			// The row is transformed at last, so the transformer sees the
+			// masked values and the computed columns.
+			if m.transformer != nil && pEvent.Row != nil {
+				keep, err := m.transformer.Transform(ctx, pEvent.Row)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if !keep {
+					pEvent.Row = nil
+				}
+			}
 			pEvent.MarkFinished()
 		}
 	}
@@ -1293,13 +1293,14 @@ func assembleRowEvent(regionID uint64, entry *cdcpb.Event_Row) (model.RegionFeed
 	revent := model.RegionFeedEvent{
 		RegionID: regionID,
 		Val: &model.RawKVEntry{
-			OpType:   opType,
-			Key:      entry.Key,
-			Value:    entry.GetValue(),
-			StartTs:  entry.StartTs,
-			CRTs:     entry.CommitTs,
-			RegionID: regionID,
-			OldValue: entry.GetOldValue(),
+			OpType:    opType,
+			Key:       entry.Key,
+			Value:     entry.GetValue(),
+			StartTs:   entry.StartTs,
+			CRTs:      entry.CommitTs,
+			RegionID:  regionID,
+			OldValue:  entry.GetOldValue(),
+			TxnSource: entry.GetTxnSource(),
 		},
 	}
 
@@ -144,6 +144,28 @@ func TestAssembleRowEvent(t *testing.T) {
 				RegionID: 4,
 			},
 		},
+	}, {
+		regionID: 5,
+		entry: &cdcpb.Event_Row{
+			StartTs:   1,
+			CommitTs:  2,
+			Key:       []byte("k4"),
+			Value:     []byte("v4"),
+			OpType:    cdcpb.Event_Row_PUT,
+			TxnSource: 3,
+		},
+		expected: model.RegionFeedEvent{
+			RegionID: 5,
+			Val: &model.RawKVEntry{
+				OpType:    model.OpTypePut,
+				StartTs:   1,
+				CRTs:      2,
+				Key:       []byte("k4"),
+				Value:     []byte("v4"),
+				RegionID:  5,
+				TxnSource: 3,
+			},
+		},
 	}, {
 		regionID: 2,
 		entry: &cdcpb.Event_Row{
@@ -15,8 +15,10 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/url"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
@@ -51,6 +53,20 @@ func (c ChangeFeedID) String() string {
 	return c.Namespace + "/" + c.ID
 }
 
+// MirrorSinkID returns the ID of the sinks of the mirror of the changefeed.
+// The sinks of a changefeed must have distinct IDs, since the kafka client and
+// transactional IDs, the metric labels and the claim-check probe files are
+// named after them. The suffix can't be a part of a changefeed ID.
+func (c ChangeFeedID) MirrorSinkID() ChangeFeedID {
+	return ChangeFeedID{Namespace: c.Namespace, ID: c.ID + "_mirror"}
+}
+
+// TargetSinkID returns the ID of the sinks of the i-th sink target of the
+// changefeed, see MirrorSinkID.
+func (c ChangeFeedID) TargetSinkID(i int) ChangeFeedID {
+	return ChangeFeedID{Namespace: c.Namespace, ID: fmt.Sprintf("%s_target_%d", c.ID, i)}
+}
+
 // DefaultChangeFeedID returns `ChangeFeedID` with default namespace
 func DefaultChangeFeedID(id string) ChangeFeedID {
 	return ChangeFeedID{
@@ -174,6 +190,10 @@ type ChangeFeedInfo struct {
 	CreatorVersion string `json:"creator-version"`
 	// Epoch is the epoch of a changefeed, changes on every restart.
 	Epoch uint64 `json:"epoch"`
+	// PausedTables are the IDs of the tables whose replication is paused,
+	// their table sinks and pullers are frozen while the other tables of
+	// the changefeed are replicated.
+	PausedTables []TableID `json:"paused-tables,omitempty"`
 }
 
 const changeFeedIDMaxLen = 128
@@ -222,6 +242,20 @@ func (info *ChangeFeedInfo) String() (str string) {
 	if err != nil {
 		log.Error("failed to marshal changefeed info", zap.Error(err))
 	}
+	if clone.Config != nil && clone.Config.Sink != nil {
+		for _, target := range clone.Config.Sink.Targets {
+			target.SinkURI, err = util.MaskSinkURI(target.SinkURI)
+			if err != nil {
+				log.Error("failed to marshal changefeed info", zap.Error(err))
+			}
+		}
+		if mirror := clone.Config.Sink.Mirror; mirror != nil {
+			mirror.SinkURI, err = util.MaskSinkURI(mirror.SinkURI)
+			if err != nil {
+				log.Error("failed to marshal changefeed info", zap.Error(err))
+			}
+		}
+	}
 
 	str, err = clone.Marshal()
 	if err != nil {
@@ -283,6 +317,111 @@ func (info *ChangeFeedInfo) Clone() (*ChangeFeedInfo, error) {
 	return cloned, err
 }
 
+// SinkCredentialsChanged returns true if the credentials of the sink are
+// different from the old ones. The credentials are in the sink URI, and in
+// the SASL configs of the Kafka sink.
+func (info *ChangeFeedInfo) SinkCredentialsChanged(old *ChangeFeedInfo) bool {
+	if info.SinkURI != old.SinkURI {
+		return true
+	}
+	user, password := info.kafkaSASLCredentials()
+	oldUser, oldPassword := old.kafkaSASLCredentials()
+	return user != oldUser || password != oldPassword
+}
+
+// CutOverToMirror replaces the sink-uri with the mirror, and the protocol and
+// the kafka sink version of the mirror are used by the changefeed from then on.
+// The running changefeed recreates its sinks like rotating the credentials.
+func (info *ChangeFeedInfo) CutOverToMirror() error {
+	if info.Config == nil || info.Config.Sink == nil || info.Config.Sink.Mirror == nil {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"the changefeed has no mirror to cut over to")
+	}
+	mirrorConfig := info.Config.SinkMirrorConfig()
+	info.SinkURI = info.Config.Sink.Mirror.SinkURI
+	info.Config.Sink.Protocol = mirrorConfig.Sink.Protocol
+	info.Config.Sink.EnableKafkaSinkV2 = mirrorConfig.Sink.EnableKafkaSinkV2
+	info.Config.Sink.Mirror = nil
+	return nil
+}
+
+// DispatchRulesChanged returns true if the dispatch rules of the sink are
+// different from the old ones.
+func (info *ChangeFeedInfo) DispatchRulesChanged(old *ChangeFeedInfo) bool {
+	rules, oldRules := info.dispatchRules(), old.dispatchRules()
+	if len(rules) == 0 && len(oldRules) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(rules, oldRules)
+}
+
+// OnlyDispatchRulesChanged returns true if nothing but the dispatch rules are
+// different from the old changefeed info, such changes can be applied to a
+// running changefeed without restarting it.
+func (info *ChangeFeedInfo) OnlyDispatchRulesChanged(old *ChangeFeedInfo) bool {
+	cloned, err := info.Clone()
+	if err != nil {
+		return false
+	}
+	if cloned.Config != nil && cloned.Config.Sink != nil {
+		cloned.Config.Sink.DispatchRules = old.dispatchRules()
+	}
+	newData, err := cloned.Marshal()
+	if err != nil {
+		return false
+	}
+	oldData, err := old.Marshal()
+	if err != nil {
+		return false
+	}
+	return newData == oldData
+}
+
+func (info *ChangeFeedInfo) dispatchRules() []*config.DispatchRule {
+	if info.Config == nil || info.Config.Sink == nil {
+		return nil
+	}
+	return info.Config.Sink.DispatchRules
+}
+
+// IsTablePaused returns true if the replication of the table is paused.
+func (info *ChangeFeedInfo) IsTablePaused(tableID TableID) bool {
+	for _, id := range info.PausedTables {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTablePaused pauses or resumes the replication of the table, it returns
+// false if the table is already in the state.
+func (info *ChangeFeedInfo) SetTablePaused(tableID TableID, paused bool) bool {
+	if info.IsTablePaused(tableID) == paused {
+		return false
+	}
+	if paused {
+		info.PausedTables = append(info.PausedTables, tableID)
+		return true
+	}
+	tables := make([]TableID, 0, len(info.PausedTables)-1)
+	for _, id := range info.PausedTables {
+		if id != tableID {
+			tables = append(tables, id)
+		}
+	}
+	info.PausedTables = tables
+	return true
+}
+
+func (info *ChangeFeedInfo) kafkaSASLCredentials() (user string, password string) {
+	if info.Config == nil || info.Config.Sink == nil || info.Config.Sink.KafkaConfig == nil {
+		return "", ""
+	}
+	kafkaConfig := info.Config.Sink.KafkaConfig
+	return util.GetOrZero(kafkaConfig.SASLUser), util.GetOrZero(kafkaConfig.SASLPassword)
+}
+
 // VerifyAndComplete verifies changefeed info and may fill in some fields.
 // If a required field is not provided, return an error.
 // If some necessary filed is missing but can use a default value, fill in it.
@@ -318,7 +457,7 @@ func (info *ChangeFeedInfo) VerifyAndComplete() {
 // the protocol. Since we utilize a common changefeed configuration template,
 // certain fields may not be utilized for certain protocols.
 func (info *ChangeFeedInfo) RmUnusedFields() {
-	uri, err := url.Parse(info.SinkURI)
+	uri, err := sink.ParseSinkURI(info.SinkURI)
 	if err != nil {
 		log.Warn(
 			"failed to parse the sink uri",
@@ -327,12 +466,19 @@ func (info *ChangeFeedInfo) RmUnusedFields() {
 		)
 		return
 	}
+	// the fields may be used by the sink targets or the mirror, which can be
+	// other kinds of downstreams.
+	if len(info.Config.Sink.Targets) > 0 || info.Config.Sink.Mirror != nil {
+		return
+	}
 	if !sink.IsMQScheme(uri.Scheme) {
 		info.rmMQOnlyFields()
 	} else {
 		// remove schema registry for MQ downstream with
-		// protocol other than avro
-		if util.GetOrZero(info.Config.Sink.Protocol) != config.ProtocolAvro.String() {
+		// protocol other than avro and json-schema
+		protocol := util.GetOrZero(info.Config.Sink.Protocol)
+		if protocol != config.ProtocolAvro.String() &&
+			protocol != config.ProtocolJSONSchema.String() {
 			info.Config.Sink.SchemaRegistry = nil
 		}
 	}
@@ -365,6 +511,7 @@ func (info *ChangeFeedInfo) rmStorageOnlyFields() {
 	info.Config.Sink.DateSeparator = nil
 	info.Config.Sink.EnablePartitionSeparator = nil
 	info.Config.Sink.FileIndexWidth = nil
+	info.Config.Sink.PartitionLayout = nil
 	info.Config.Sink.CloudStorageConfig = nil
 }
 
@@ -458,7 +605,7 @@ func (info *ChangeFeedInfo) fixState() {
 }
 
 func (info *ChangeFeedInfo) fixMySQLSinkProtocol() {
-	uri, err := url.Parse(info.SinkURI)
+	uri, err := sink.ParseSinkURI(info.SinkURI)
 	if err != nil {
 		log.Warn("parse sink URI failed", zap.Error(err))
 		// SAFETY: It is safe to ignore this unresolvable sink URI here,
@@ -488,7 +635,7 @@ func (info *ChangeFeedInfo) fixMySQLSinkProtocol() {
 }
 
 func (info *ChangeFeedInfo) fixEnableOldValue() {
-	uri, err := url.Parse(info.SinkURI)
+	uri, err := sink.ParseSinkURI(info.SinkURI)
 	if err != nil {
 		// this is impossible to happen, since the changefeed registered successfully.
 		log.Warn("parse sink URI failed", zap.Error(err))
@@ -500,7 +647,7 @@ func (info *ChangeFeedInfo) fixEnableOldValue() {
 }
 
 func (info *ChangeFeedInfo) fixMQSinkProtocol() {
-	uri, err := url.Parse(info.SinkURI)
+	uri, err := sink.ParseSinkURI(info.SinkURI)
 	if err != nil {
 		log.Warn("parse sink URI failed", zap.Error(err))
 		return
@@ -553,7 +700,7 @@ func (info *ChangeFeedInfo) updateSinkURIAndConfigProtocol(uri *url.URL, newProt
 
 // DownstreamType returns the type of the downstream.
 func (info *ChangeFeedInfo) DownstreamType() (DownstreamType, error) {
-	uri, err := url.Parse(info.SinkURI)
+	uri, err := sink.ParseSinkURI(info.SinkURI)
 	if err != nil {
 		return Unknown, errors.Trace(err)
 	}
@@ -608,4 +755,9 @@ func (t DownstreamType) String() string {
 type ChangeFeedStatusForAPI struct {
 	ResolvedTs   uint64 `json:"resolved-ts"`
 	CheckpointTs uint64 `json:"checkpoint-ts"`
+	// MirrorCheckpointTs is the checkpoint of the mirror of the changefeed,
+	// it's 0 if the changefeed has no mirror.
+	MirrorCheckpointTs uint64 `json:"mirror-checkpoint-ts,omitempty"`
+	// MirrorIncomplete is true if the mirror may have lost events.
+	MirrorIncomplete bool `json:"mirror-incomplete,omitempty"`
 }
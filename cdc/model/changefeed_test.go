@@ -28,6 +28,17 @@ import (
 	"github.com/tikv/client-go/v2/oracle"
 )
 
+func TestSinkIDs(t *testing.T) {
+	t.Parallel()
+
+	id := DefaultChangeFeedID("test")
+	require.Equal(t, ChangeFeedID{Namespace: DefaultNamespace, ID: "test_mirror"}, id.MirrorSinkID())
+	require.Equal(t, ChangeFeedID{Namespace: DefaultNamespace, ID: "test_target_1"}, id.TargetSinkID(1))
+	// the sink IDs never collide with the other changefeeds.
+	require.Error(t, ValidateChangefeedID(id.MirrorSinkID().ID))
+	require.Error(t, ValidateChangefeedID(id.TargetSinkID(0).ID))
+}
+
 func TestRmUnusedField(t *testing.T) {
 	t.Parallel()
 	const (
@@ -939,6 +950,110 @@ func TestChangeFeedInfoClone(t *testing.T) {
 	require.True(t, info.Config.EnableOldValue)
 }
 
+func TestSinkCredentialsChanged(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{
+		SinkURI: "kafka://127.0.0.1:9092/topic",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	cloned, err := info.Clone()
+	require.NoError(t, err)
+	require.False(t, cloned.SinkCredentialsChanged(info))
+
+	cloned.Config.Sink.KafkaConfig = &config.KafkaConfig{SASLUser: util.AddressOf("user")}
+	require.True(t, cloned.SinkCredentialsChanged(info))
+	info.Config.Sink.KafkaConfig = &config.KafkaConfig{SASLUser: util.AddressOf("user")}
+	require.False(t, cloned.SinkCredentialsChanged(info))
+	cloned.Config.Sink.KafkaConfig.SASLPassword = util.AddressOf("password")
+	require.True(t, cloned.SinkCredentialsChanged(info))
+
+	cloned, err = info.Clone()
+	require.NoError(t, err)
+	cloned.SinkURI = "kafka://127.0.0.1:9092/topic?sasl-password=password"
+	require.True(t, cloned.SinkCredentialsChanged(info))
+	require.True(t, info.SinkCredentialsChanged(&ChangeFeedInfo{SinkURI: info.SinkURI}))
+}
+
+func TestCutOverToMirror(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{
+		SinkURI: "kafka://127.0.0.1:9092/topic?protocol=canal-json",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	require.Error(t, info.CutOverToMirror())
+
+	info.Config.Sink.Protocol = util.AddressOf("canal-json")
+	info.Config.Sink.Mirror = &config.SinkMirror{
+		SinkURI:           "kafka://127.0.0.1:9093/topic?protocol=avro",
+		EnableKafkaSinkV2: util.AddressOf(true),
+	}
+	old, err := info.Clone()
+	require.NoError(t, err)
+	require.NoError(t, info.CutOverToMirror())
+	require.Equal(t, "kafka://127.0.0.1:9093/topic?protocol=avro", info.SinkURI)
+	require.Equal(t, "avro", util.GetOrZero(info.Config.Sink.Protocol))
+	require.True(t, info.Config.Sink.KafkaSinkV2Enabled())
+	require.Nil(t, info.Config.Sink.Mirror)
+	// the sinks of the running changefeed are recreated.
+	require.True(t, info.SinkCredentialsChanged(old))
+}
+
+func TestSetTablePaused(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{}
+	require.False(t, info.IsTablePaused(1))
+	require.True(t, info.SetTablePaused(1, true))
+	require.True(t, info.SetTablePaused(2, true))
+	require.False(t, info.SetTablePaused(1, true))
+	require.True(t, info.IsTablePaused(1))
+	require.Equal(t, []TableID{1, 2}, info.PausedTables)
+
+	// the paused tables are persisted with the changefeed info.
+	cloned, err := info.Clone()
+	require.NoError(t, err)
+	require.Equal(t, []TableID{1, 2}, cloned.PausedTables)
+
+	require.True(t, info.SetTablePaused(1, false))
+	require.False(t, info.SetTablePaused(3, false))
+	require.False(t, info.IsTablePaused(1))
+	require.Equal(t, []TableID{2}, info.PausedTables)
+}
+
+func TestDispatchRulesChanged(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{
+		SinkURI: "kafka://127.0.0.1:9092/topic",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	cloned, err := info.Clone()
+	require.NoError(t, err)
+	require.False(t, cloned.DispatchRulesChanged(info))
+	require.True(t, cloned.OnlyDispatchRulesChanged(info))
+	cloned.Config.Sink.DispatchRules = []*config.DispatchRule{}
+	require.False(t, cloned.DispatchRulesChanged(info))
+
+	cloned.Config.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, PartitionRule: "table", TopicRule: "{schema}"},
+	}
+	require.True(t, cloned.DispatchRulesChanged(info))
+	require.True(t, cloned.OnlyDispatchRulesChanged(info))
+
+	cloned.Config.Sink.DispatchRules[0].PartitionRule = "ts"
+	require.True(t, cloned.OnlyDispatchRulesChanged(info))
+	cloned.Config.MemoryQuota++
+	require.False(t, cloned.OnlyDispatchRulesChanged(info))
+
+	cloned, err = info.Clone()
+	require.NoError(t, err)
+	cloned.SinkURI = "kafka://127.0.0.1:9092/topic2"
+	require.False(t, cloned.DispatchRulesChanged(info))
+	require.False(t, cloned.OnlyDispatchRulesChanged(info))
+}
+
 func TestChangefeedInfoStringer(t *testing.T) {
 	t.Parallel()
 
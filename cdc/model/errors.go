@@ -26,6 +26,8 @@ type RunningError struct {
 	Addr    string    `json:"addr"`
 	Code    string    `json:"code"`
 	Message string    `json:"message"`
+	// Class is the class of the error if it comes from the sink.
+	Class string `json:"class,omitempty"`
 }
 
 // IsChangefeedUnRetryableError return true if a running error contains a changefeed not retry error.
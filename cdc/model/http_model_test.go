@@ -26,10 +26,10 @@ func TestChangefeedCommonInfoMarshalJSON(t *testing.T) {
 	t.Parallel()
 
 	runningErr := &RunningError{
-		time.Now(),
-		"",
-		string(errors.ErrProcessorUnknown.RFCCode()),
-		errors.ErrProcessorUnknown.GetMsg(),
+		Time:    time.Now(),
+		Addr:    "",
+		Code:    string(errors.ErrProcessorUnknown.RFCCode()),
+		Message: errors.ErrProcessorUnknown.GetMsg(),
 	}
 	cfInfo := &ChangefeedCommonInfo{
 		ID:           "test",
@@ -52,10 +52,10 @@ func TestChangefeedDetailMarshalJSON(t *testing.T) {
 	t.Parallel()
 
 	runningErr := &RunningError{
-		time.Now(),
-		"",
-		string(errors.ErrProcessorUnknown.RFCCode()),
-		errors.ErrProcessorUnknown.GetMsg(),
+		Time:    time.Now(),
+		Addr:    "",
+		Code:    string(errors.ErrProcessorUnknown.RFCCode()),
+		Message: errors.ErrProcessorUnknown.GetMsg(),
 	}
 	cfDetail := &ChangefeedDetail{
 		ID:           "test",
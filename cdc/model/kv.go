@@ -91,6 +91,14 @@ type RawKVEntry struct {
 
 	// Additional debug info
 	RegionID uint64 `msg:"region_id"`
+
+	// TxnSource is the source of the transaction, the TiCDC write source is
+	// encoded in its lowest 8 bits if the row is written by a TiCDC sink.
+	TxnSource uint64 `msg:"txn_source"`
+
+	// Snapshot marks the row scanned from the initial snapshot of the table,
+	// it's a put committed at the start ts of the changefeed.
+	Snapshot bool `msg:"snapshot"`
 }
 
 func (v *RawKVEntry) String() string {
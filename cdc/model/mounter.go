@@ -18,6 +18,7 @@ import (
 	"math"
 
 	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/types"
 	"go.uber.org/zap"
 )
@@ -224,3 +225,13 @@ func (r ResolvedTs) Greater(r1 ResolvedTs) bool {
 func (r ResolvedTs) Equal(r1 ResolvedTs) bool {
 	return r == r1
 }
+
+// GetDDLDefaultDefinition returns the default definition of a column.
+func GetDDLDefaultDefinition(col *timodel.ColumnInfo) interface{} {
+	defaultValue := col.GetDefaultValue()
+	if defaultValue == nil {
+		defaultValue = col.GetOriginDefaultValue()
+	}
+	defaultDatum := types.NewDatum(defaultValue)
+	return defaultDatum.GetValue()
+}
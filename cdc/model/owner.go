@@ -96,6 +96,13 @@ type TaskPosition struct {
 	Error *RunningError `json:"error"`
 	// Warning when module error happens
 	Warning *RunningError `json:"warning"`
+	// MirrorCheckpointTs is the min checkpoint of the mirror of the tables
+	// replicated by the capture, it's 0 if the changefeed has no mirror.
+	MirrorCheckpointTs uint64 `json:"mirror-checkpoint-ts,omitempty"`
+	// MirrorIncomplete is true once the mirror of a table replicated by the
+	// capture may have lost events, e.g. it fails or its table sink starts
+	// after the checkpoint of the mirror.
+	MirrorIncomplete bool `json:"mirror-incomplete,omitempty"`
 }
 
 // Marshal returns the json marshal format of a TaskStatus
@@ -120,9 +127,11 @@ func (tp *TaskPosition) String() string {
 // Clone returns a deep clone of TaskPosition
 func (tp *TaskPosition) Clone() *TaskPosition {
 	ret := &TaskPosition{
-		CheckPointTs: tp.CheckPointTs,
-		ResolvedTs:   tp.ResolvedTs,
-		Count:        tp.Count,
+		CheckPointTs:       tp.CheckPointTs,
+		ResolvedTs:         tp.ResolvedTs,
+		Count:              tp.Count,
+		MirrorCheckpointTs: tp.MirrorCheckpointTs,
+		MirrorIncomplete:   tp.MirrorIncomplete,
 	}
 	if tp.Error != nil {
 		ret.Error = &RunningError{
@@ -247,6 +256,29 @@ func (ts *TaskStatus) Clone() *TaskStatus {
 	return &clone
 }
 
+// TableProgress records the replication progress of a table span, the stats
+// are collected from the processor periodically.
+//
+// Only used in API.
+type TableProgress struct {
+	Span       tablepb.Span
+	CaptureID  CaptureID
+	Checkpoint tablepb.Checkpoint
+	Stats      tablepb.Stats
+}
+
+// SkippedDDL is a DDL which isn't replicated to the downstream because it's
+// skipped by the filter of the changefeed, such as by the ddl-deny-list.
+//
+// Only used in API.
+type SkippedDDL struct {
+	StartTs  uint64
+	CommitTs uint64
+	Type     string
+	Table    TableName
+	Query    string
+}
+
 // TableID is the ID of the table
 type TableID = tablepb.TableID
 
@@ -279,6 +311,14 @@ type ChangeFeedStatus struct {
 	// TODO: remove this filed after we don't use ChangeFeedStatus to
 	// control processor. This is too ambiguous.
 	AdminJobType AdminJobType `json:"admin-job-type"`
+	// MirrorCheckpointTs is the checkpoint of the mirror of the changefeed,
+	// the mirror has received all the events before it. It's persisted, so
+	// the table sinks of the mirror that start after it are known to skip
+	// some events.
+	MirrorCheckpointTs uint64 `json:"mirror-checkpoint-ts,omitempty"`
+	// MirrorIncomplete is true once the mirror may have lost events, the
+	// changefeed can't be cut over to the mirror anymore.
+	MirrorIncomplete bool `json:"mirror-incomplete,omitempty"`
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
@@ -0,0 +1,64 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Savepoint is a named snapshot of the checkpoint of a changefeed. A new
+// changefeed can be created from a savepoint, it replicates the changes
+// after the checkpoint, so the sink of a changefeed can be migrated without
+// losing or duplicating any data.
+type Savepoint struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// ChangefeedID is the ID of the changefeed which the savepoint is taken from.
+	ChangefeedID string    `json:"changefeed-id"`
+	UpstreamID   uint64    `json:"upstream-id"`
+	CheckpointTs uint64    `json:"checkpoint-ts"`
+	SinkURI      string    `json:"sink-uri"`
+	CreateTime   time.Time `json:"create-time"`
+	// ExpireTime is the time when the service GC safepoint of the savepoint
+	// expires, the changefeeds can't be created from the savepoint after it.
+	ExpireTime time.Time `json:"expire-time"`
+
+	Config *config.ReplicaConfig `json:"config"`
+}
+
+// Marshal returns the json marshal format of a Savepoint
+func (s *Savepoint) Marshal() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return string(data), nil
+}
+
+// Unmarshal unmarshals into *Savepoint from json marshal byte slice
+func (s *Savepoint) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, s)
+	return errors.Annotatef(cerror.WrapError(cerror.ErrUnmarshalFailed, err),
+		"unmarshal data: %v", data)
+}
+
+// IsExpired returns true if the savepoint is expired at the given time.
+func (s *Savepoint) IsExpired(now time.Time) bool {
+	return !s.ExpireTime.IsZero() && now.After(s.ExpireTime)
+}
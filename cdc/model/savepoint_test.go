@@ -0,0 +1,55 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSavepointMarshal(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	savepoint := &Savepoint{
+		Namespace:    DefaultNamespace,
+		Name:         "before-migration",
+		ChangefeedID: "test",
+		UpstreamID:   1,
+		CheckpointTs: 100,
+		SinkURI:      "kafka://127.0.0.1:9092/test",
+		CreateTime:   now,
+		ExpireTime:   now.Add(time.Hour),
+		Config:       config.GetDefaultReplicaConfig(),
+	}
+	data, err := savepoint.Marshal()
+	require.NoError(t, err)
+
+	decoded := &Savepoint{}
+	require.NoError(t, decoded.Unmarshal([]byte(data)))
+	require.Equal(t, savepoint.Name, decoded.Name)
+	require.Equal(t, savepoint.CheckpointTs, decoded.CheckpointTs)
+	require.Equal(t, savepoint.SinkURI, decoded.SinkURI)
+	require.True(t, savepoint.ExpireTime.Equal(decoded.ExpireTime))
+	require.Equal(t, savepoint.Config, decoded.Config)
+
+	require.False(t, decoded.IsExpired(now))
+	require.True(t, decoded.IsExpired(now.Add(2*time.Hour)))
+	require.False(t, (&Savepoint{}).IsExpired(now))
+
+	require.Error(t, decoded.Unmarshal([]byte("invalid")))
+}
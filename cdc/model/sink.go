@@ -45,6 +45,9 @@ const (
 	MessageTypeDDL
 	// MessageTypeResolved is resolved type of message key
 	MessageTypeResolved
+	// MessageTypeBootstrap is the type of the message carrying the schema of
+	// a table, which is sent before the rows of the table.
+	MessageTypeBootstrap
 )
 
 // ColumnFlagType is for encapsulating the flag operations for different flags.
@@ -328,8 +331,14 @@ type RowChangedEvent struct {
 
 	// SplitTxn marks this RowChangedEvent as the first line of a new txn.
 	SplitTxn bool `json:"-" msg:"-"`
+	// IsSnapshot marks the row scanned from the initial snapshot of the table,
+	// which is sent as an INSERT event before the incremental changes.
+	IsSnapshot bool `json:"-" msg:"-"`
 	// ReplicatingTs is ts when a table starts replicating events to downstream.
 	ReplicatingTs Ts `json:"-" msg:"-"`
+	// OriginID is the source ID of the TiDB cluster where the row is written
+	// originally, zero means the row is written in the upstream directly.
+	OriginID uint64 `json:"-" msg:"-"`
 }
 
 // GetCommitTs returns the commit timestamp of this event.
@@ -352,6 +361,30 @@ func (r *RowChangedEvent) IsUpdate() bool {
 	return len(r.PreColumns) != 0 && len(r.Columns) != 0
 }
 
+// IsHandleKeyUpdated returns true if the event is an update event and any of
+// its handle key columns is changed. The columns and the pre-columns are
+// matched by their positions, and a handle key column is treated as changed
+// if its pre-column is missing.
+func (r *RowChangedEvent) IsHandleKeyUpdated() bool {
+	if !r.IsUpdate() {
+		return false
+	}
+	for i, col := range r.Columns {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		if i >= len(r.PreColumns) || r.PreColumns[i] == nil {
+			return true
+		}
+		preValue := r.PreColumns[i].Value
+		if (preValue == nil) != (col.Value == nil) ||
+			ColumnValueString(preValue) != ColumnValueString(col.Value) {
+			return true
+		}
+	}
+	return false
+}
+
 // PrimaryKeyColumnNames return all primary key's name
 func (r *RowChangedEvent) PrimaryKeyColumnNames() []string {
 	var result []string
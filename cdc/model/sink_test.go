@@ -14,6 +14,7 @@
 package model
 
 import (
+	"fmt"
 	"testing"
 
 	timodel "github.com/pingcap/tidb/parser/model"
@@ -105,6 +106,51 @@ func TestRowChangedEventFuncs(t *testing.T) {
 	require.True(t, deleteRow.IsDelete())
 }
 
+func TestRowChangedEventIsHandleKeyUpdated(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(preValues, values []interface{}) *RowChangedEvent {
+		row := &RowChangedEvent{}
+		for i, v := range preValues {
+			row.PreColumns = append(row.PreColumns, &Column{Name: fmt.Sprintf("c%d", i), Value: v})
+		}
+		for i, v := range values {
+			row.Columns = append(row.Columns, &Column{Name: fmt.Sprintf("c%d", i), Value: v})
+		}
+		if len(row.PreColumns) > 0 {
+			row.PreColumns[0].Flag = HandleKeyFlag
+		}
+		if len(row.Columns) > 0 {
+			row.Columns[0].Flag = HandleKeyFlag
+		}
+		return row
+	}
+
+	cases := []struct {
+		row     *RowChangedEvent
+		updated bool
+	}{
+		// not update events.
+		{newRow(nil, []interface{}{1, 2}), false},
+		{newRow([]interface{}{1, 2}, nil), false},
+		// only the other columns are changed.
+		{newRow([]interface{}{1, 2}, []interface{}{1, 3}), false},
+		{newRow([]interface{}{[]byte("a"), 2}, []interface{}{[]byte("a"), 3}), false},
+		// the handle key is changed.
+		{newRow([]interface{}{1, 2}, []interface{}{2, 2}), true},
+		{newRow([]interface{}{[]byte("a"), 2}, []interface{}{[]byte("b"), 2}), true},
+		{newRow([]interface{}{nil, 2}, []interface{}{"null", 2}), true},
+	}
+	for i, c := range cases {
+		require.Equal(t, c.updated, c.row.IsHandleKeyUpdated(), "case %d", i)
+	}
+
+	// the handle key is treated as changed if its pre-column is missing.
+	row := newRow([]interface{}{1, 2}, []interface{}{1, 2})
+	row.PreColumns[0] = nil
+	require.True(t, row.IsHandleKeyUpdated())
+}
+
 func TestColumnValueString(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/factory"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -37,6 +38,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	redoCfg "github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -84,9 +86,22 @@ type changefeed struct {
 	schema    *schemaWrap4Owner
 	ddlSink   DDLSink
 	ddlPuller puller.DDLPuller
+	// sinkInfo is the changefeed info whose sink credentials and dispatch
+	// rules are used by ddlSink.
+	sinkInfo *model.ChangeFeedInfo
+	// skippedDDLs is kept when the changefeed is restarted, so the skipped
+	// DDLs are still available in the API.
+	skippedDDLs *skippedDDLs
+	// pausedDDLs are the DDLs held by ddlSink whose tables have been paused,
+	// they're retried once any of the tables is resumed.
+	pausedDDLs map[*model.DDLEvent]struct{}
 	// The changefeed will start a backend goroutine in the function `initialize`
 	// for DDLPuller and redo manager. `wg` is used to manage this backend goroutine.
 	wg sync.WaitGroup
+	// cleanupWg manages the goroutines cleaning up the resources of the removed
+	// changefeed, which outlive the changefeed. It's shared by the changefeeds
+	// of an owner, which waits for them before it exits.
+	cleanupWg *sync.WaitGroup
 
 	// state related fields
 	initialized bool
@@ -159,10 +174,12 @@ func newChangefeed(
 		barriers:         newBarriers(),
 		feedStateManager: newFeedStateManager(up),
 		upstream:         up,
+		skippedDDLs:      &skippedDDLs{},
 
 		errCh:     make(chan error, defaultErrChSize),
 		warningCh: make(chan error, defaultErrChSize),
 		cancel:    func() {},
+		cleanupWg: &sync.WaitGroup{},
 
 		newDDLPuller:          puller.NewDDLPuller,
 		newSink:               newDDLSink,
@@ -332,6 +349,9 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	if err := c.initialize(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	c.rotateSinkCredentials()
+	c.updateDispatchRules()
+	c.pauseIncompatibleTables()
 
 	select {
 	case err := <-c.errCh:
@@ -537,6 +557,7 @@ LOOP2:
 	if err != nil {
 		return errors.Trace(err)
 	}
+	c.schema.skippedDDLs = c.skippedDDLs
 
 	cancelCtx, cancel := cdcContext.WithCancel(ctx)
 	c.cancel = cancel
@@ -559,6 +580,7 @@ LOOP2:
 		}
 	})
 	c.ddlSink.run(cancelCtx)
+	c.sinkInfo = c.state.Info
 
 	c.ddlPuller, err = c.newDDLPuller(cancelCtx,
 		c.state.Info.Config,
@@ -685,6 +707,8 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 	// Must clean redo manager before calling cancel, otherwise
 	// the manager can be closed internally.
 	c.cleanupRedoManager(ctx)
+	c.cleanupTopics(ctx)
+	c.pausedDDLs = nil
 	c.cleanupChangefeedServiceGCSafePoints(ctx)
 
 	c.cancel()
@@ -719,6 +743,7 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 	c.cleanupMetrics()
 	c.schema = nil
 	c.barriers = nil
+	c.sinkInfo = nil
 	c.initialized = false
 	c.isReleased = true
 
@@ -788,6 +813,89 @@ func (c *changefeed) cleanupRedoManager(ctx context.Context) {
 	}
 }
 
+// cleanupTopicsTimeout is the timeout of deleting the topics of a removed
+// changefeed in the background, including the retries.
+const cleanupTopicsTimeout = time.Minute
+
+const (
+	cleanupTopicsBackoffBaseDelayInMs = 500
+	cleanupTopicsBackoffMaxDelayInMs  = 10 * 1000
+)
+
+// cleanup the topics auto-created by the changefeed if it is removed
+// and `delete-topics-on-remove` is enabled.
+func (c *changefeed) cleanupTopics(ctx cdcContext.Context) {
+	if !c.isRemoved {
+		return
+	}
+	if c.state == nil || c.state.Info == nil || c.state.Info.Config == nil ||
+		c.state.Info.Config.Sink == nil {
+		log.Warn("changefeed is removed, but state is not complete", zap.Any("state", c.state))
+		return
+	}
+	kafkaConfig := c.state.Info.Config.Sink.KafkaConfig
+	if kafkaConfig == nil || !util.GetOrZero(kafkaConfig.DeleteTopicsOnRemove) {
+		return
+	}
+
+	// The tables are unknown if the changefeed is not running,
+	// only the default topic is deleted in this case.
+	var tables []model.TableName
+	if c.schema != nil {
+		c.schema.GetLastSnapshot().IterTables(true, func(tblInfo *model.TableInfo) {
+			if !c.schema.shouldIgnoreTable(tblInfo) {
+				tables = append(tables, tblInfo.TableName)
+			}
+		})
+	}
+
+	// Deleting topics may take a long time, it must not block the owner. It's
+	// canceled once the owner exits, and the owner waits for it if it's closed.
+	id := c.id
+	sinkURI := c.state.Info.SinkURI
+	replicaConfig := c.state.Info.Config.Clone()
+	etcdClient := ctx.GlobalVars().EtcdClient
+	cleanupCtx, cancel := context.WithTimeout(ctx, cleanupTopicsTimeout)
+	c.cleanupWg.Add(1)
+	go func() {
+		defer c.cleanupWg.Done()
+		defer cancel()
+		err := retry.Do(cleanupCtx, func() error {
+			infos, err := etcdClient.GetAllChangeFeedInfo(cleanupCtx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			others := make([]*model.ChangeFeedInfo, 0, len(infos))
+			for otherID, info := range infos {
+				if otherID != id {
+					others = append(others, info)
+				}
+			}
+			err = factory.CleanupTopics(cleanupCtx, id, sinkURI, replicaConfig, tables, others)
+			if err != nil {
+				log.Warn("cleanup topics failed, retry later",
+					zap.String("namespace", id.Namespace),
+					zap.String("changefeed", id.ID),
+					zap.Error(err))
+			}
+			return err
+		}, retry.WithBackoffBaseDelay(cleanupTopicsBackoffBaseDelayInMs),
+			retry.WithBackoffMaxDelay(cleanupTopicsBackoffMaxDelayInMs),
+			retry.WithTotalRetryDuratoin(cleanupTopicsTimeout),
+			retry.WithIsRetryableErr(cerror.IsRetryableError))
+		if err != nil {
+			log.Error("cleanup topics failed, the topics must be deleted manually",
+				zap.String("namespace", id.Namespace),
+				zap.String("changefeed", id.ID),
+				zap.Error(err))
+			return
+		}
+		log.Info("cleanup topics successfully",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID))
+	}()
+}
+
 func (c *changefeed) cleanupChangefeedServiceGCSafePoints(ctx cdcContext.Context) {
 	if !c.isRemoved {
 		return
@@ -945,6 +1053,10 @@ func (c *changefeed) updateStatus(checkpointTs, minTableBarrierTs model.Ts) {
 			if status == nil {
 				return nil, changed, nil
 			}
+			// the mirror starts from the checkpoint before it's updated.
+			if c.updateMirrorStatus(status) {
+				changed = true
+			}
 			if status.CheckpointTs != checkpointTs {
 				status.CheckpointTs = checkpointTs
 				changed = true
@@ -957,6 +1069,42 @@ func (c *changefeed) updateStatus(checkpointTs, minTableBarrierTs model.Ts) {
 		})
 }
 
+// updateMirrorStatus persists the checkpoint of the mirror and whether it
+// may have lost events. The mirror starts from the checkpoint of the
+// changefeed, and it's marked as incomplete once it fails or any of its table
+// sinks starts after the persisted checkpoint of the mirror, the mark is kept
+// until the changefeed runs without the mirror.
+func (c *changefeed) updateMirrorStatus(status *model.ChangeFeedStatus) bool {
+	if c.state.Info == nil || c.state.Info.Config.Sink.Mirror == nil {
+		changed := status.MirrorCheckpointTs != 0 || status.MirrorIncomplete
+		status.MirrorCheckpointTs = 0
+		status.MirrorIncomplete = false
+		return changed
+	}
+	changed := false
+	if status.MirrorCheckpointTs == 0 {
+		status.MirrorCheckpointTs = status.CheckpointTs
+		changed = true
+	}
+	if status.MirrorIncomplete {
+		return changed
+	}
+	if c.isMirrorIncomplete() {
+		log.Warn("The mirror of the changefeed may have lost events, "+
+			"it can't be cut over to anymore",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("mirrorCheckpointTs", status.MirrorCheckpointTs))
+		status.MirrorIncomplete = true
+		return true
+	}
+	if checkpointTs := c.mirrorCheckpointTs(); checkpointTs > status.MirrorCheckpointTs {
+		status.MirrorCheckpointTs = checkpointTs
+		changed = true
+	}
+	return changed
+}
+
 func (c *changefeed) Close(ctx cdcContext.Context) {
 	startTime := time.Now()
 	c.releaseResources(ctx)
@@ -1001,6 +1149,112 @@ func (c *changefeed) checkUpstream() (skip bool, err error) {
 	return
 }
 
+// rotateSinkCredentials notifies the DDL sink if the sink credentials are
+// rotated while the changefeed is running.
+func (c *changefeed) rotateSinkCredentials() {
+	if c.sinkInfo == nil || !c.state.Info.SinkCredentialsChanged(c.sinkInfo) {
+		return
+	}
+	log.Info("sink credentials are rotated",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID))
+	c.ddlSink.rotateCredentials(c.state.Info)
+	c.sinkInfo = c.state.Info
+}
+
+// updateDispatchRules notifies the DDL sink if the dispatch rules are updated
+// while the changefeed is running.
+func (c *changefeed) updateDispatchRules() {
+	if c.sinkInfo == nil || !c.state.Info.DispatchRulesChanged(c.sinkInfo) {
+		return
+	}
+	log.Info("dispatch rules are updated",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID))
+	c.ddlSink.updateDispatchRules(c.state.Info)
+	c.sinkInfo = c.state.Info
+}
+
+// pauseIncompatibleTables pauses the tables whose new schemas changed by DDLs
+// are rejected by the downstream, instead of failing the whole changefeed.
+// A held DDL is kept pending, so the barrier of its tables stays at it, and
+// it's written again once any of its tables is resumed.
+func (c *changefeed) pauseIncompatibleTables() {
+	for _, ddl := range c.ddlSink.getIncompatibleDDLs() {
+		tableIDs := getRelatedPhysicalTableIDs(ddl)
+		paused := true
+		for _, tableID := range tableIDs {
+			if !c.state.Info.IsTablePaused(tableID) {
+				paused = false
+				break
+			}
+		}
+		if paused {
+			if c.pausedDDLs == nil {
+				c.pausedDDLs = make(map[*model.DDLEvent]struct{})
+			}
+			c.pausedDDLs[ddl] = struct{}{}
+			continue
+		}
+		if _, ok := c.pausedDDLs[ddl]; ok {
+			delete(c.pausedDDLs, ddl)
+			c.ddlSink.retryDDLEvent(ddl)
+			continue
+		}
+		log.Warn("pause the table since its new schema is incompatible with the downstream",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.String("table", ddl.TableInfo.TableName.String()),
+			zap.Int64s("tableIDs", tableIDs))
+		c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			if info == nil {
+				return nil, false, nil
+			}
+			changed := false
+			for _, tableID := range tableIDs {
+				if info.SetTablePaused(tableID, true) {
+					changed = true
+				}
+			}
+			return info, changed, nil
+		})
+	}
+}
+
+// mirrorCheckpointTs returns the min checkpoint of the mirror reported by the
+// processors. It's 0 if the changefeed has no mirror, the mirror of the DDL
+// sink has failed, or any processor hasn't reported it yet.
+func (c *changefeed) mirrorCheckpointTs() model.Ts {
+	if c.state.Info == nil || c.state.Info.Config.Sink.Mirror == nil ||
+		c.ddlSink == nil || c.ddlSink.isMirrorFailed() {
+		return 0
+	}
+	var checkpointTs model.Ts
+	for _, position := range c.state.TaskPositions {
+		if position.MirrorCheckpointTs == 0 {
+			return 0
+		}
+		if checkpointTs == 0 || position.MirrorCheckpointTs < checkpointTs {
+			checkpointTs = position.MirrorCheckpointTs
+		}
+	}
+	return checkpointTs
+}
+
+// isMirrorIncomplete returns true if the mirror of the DDL sink has failed or
+// any processor reports that the mirror of its tables may have lost events.
+func (c *changefeed) isMirrorIncomplete() bool {
+	if c.ddlSink != nil && c.ddlSink.isMirrorFailed() {
+		return true
+	}
+	for _, position := range c.state.TaskPositions {
+		if position.MirrorIncomplete {
+			return true
+		}
+	}
+	return false
+}
+
 // tickDownstreamObserver checks whether needs to trigger tick of downstream
 // observer, if needed run it in an independent goroutine with 5s timeout.
 func (c *changefeed) tickDownstreamObserver(ctx context.Context) {
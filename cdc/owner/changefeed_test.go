@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/pingcap/errors"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/entry"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/redo"
@@ -94,6 +96,13 @@ type mockDDLSink struct {
 		sync.Mutex
 		checkpointTs  model.Ts
 		currentTables []*model.TableInfo
+		rotatedInfo   *model.ChangeFeedInfo
+		updatedInfo   *model.ChangeFeedInfo
+		// incompatibleDDLs are the held DDLs, retriedDDL is the last one
+		// retried.
+		incompatibleDDLs []*model.DDLEvent
+		retriedDDL       *model.DDLEvent
+		mirrorFailed     bool
 	}
 	syncPoint    model.Ts
 	syncPointHis []model.Ts
@@ -133,6 +142,37 @@ func (m *mockDDLSink) emitSyncPoint(ctx context.Context, checkpointTs uint64) er
 	return nil
 }
 
+func (m *mockDDLSink) rotateCredentials(info *model.ChangeFeedInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.rotatedInfo = info
+}
+
+func (m *mockDDLSink) updateDispatchRules(info *model.ChangeFeedInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.updatedInfo = info
+}
+
+func (m *mockDDLSink) getIncompatibleDDLs() []*model.DDLEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mu.incompatibleDDLs
+}
+
+func (m *mockDDLSink) retryDDLEvent(ddl *model.DDLEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.incompatibleDDLs = nil
+	m.mu.retriedDDL = ddl
+}
+
+func (m *mockDDLSink) isMirrorFailed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mu.mirrorFailed
+}
+
 func (m *mockDDLSink) emitCheckpointTs(ts uint64, tables []*model.TableInfo) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -283,6 +323,139 @@ func TestInitialize(t *testing.T) {
 	require.Equal(t, cf.state.Status.CheckpointTs, ctx.ChangefeedVars().Info.StartTs)
 }
 
+func TestPauseIncompatibleTables(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	// pre check and initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	ctx.GlobalVars().EtcdClient = &etcd.CDCEtcdClientImpl{}
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	mockDDLSink := cf.ddlManager.ddlSink.(*mockDDLSink)
+	ddl := &model.DDLEvent{
+		CommitTs: 2,
+		Query:    "alter table t modify column a varchar(10)",
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t", TableID: 1},
+			TableInfo: &timodel.TableInfo{
+				ID: 1,
+				Partition: &timodel.PartitionInfo{
+					Enable:      true,
+					Definitions: []timodel.PartitionDefinition{{ID: 2}, {ID: 3}},
+				},
+			},
+		},
+	}
+	mockDDLSink.mu.incompatibleDDLs = []*model.DDLEvent{ddl}
+
+	// the tables are paused and the DDL is kept pending.
+	cf.pauseIncompatibleTables()
+	tester.MustApplyPatches()
+	require.Equal(t, []model.TableID{1, 2, 3}, cf.state.Info.PausedTables)
+	cf.pauseIncompatibleTables()
+	tester.MustApplyPatches()
+	require.Nil(t, mockDDLSink.mu.retriedDDL)
+	require.Equal(t, []*model.DDLEvent{ddl}, mockDDLSink.mu.incompatibleDDLs)
+	require.False(t, ddl.Done.Load())
+
+	// the DDL is retried once a table is resumed.
+	cf.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		return info, info.SetTablePaused(2, false), nil
+	})
+	tester.MustApplyPatches()
+	cf.pauseIncompatibleTables()
+	tester.MustApplyPatches()
+	require.Equal(t, ddl, mockDDLSink.mu.retriedDDL)
+	require.Empty(t, mockDDLSink.mu.incompatibleDDLs)
+	require.Equal(t, []model.TableID{1, 3}, cf.state.Info.PausedTables)
+	require.False(t, ddl.Done.Load())
+}
+
+func TestMirrorCheckpointTs(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	// pre check and initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	cf.state.TaskPositions = map[model.CaptureID]*model.TaskPosition{
+		"capture-1": {MirrorCheckpointTs: 10},
+		"capture-2": {MirrorCheckpointTs: 8},
+	}
+	// the changefeed has no mirror.
+	require.Zero(t, cf.mirrorCheckpointTs())
+
+	cf.state.Info.Config.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://"}
+	require.Equal(t, model.Ts(8), cf.mirrorCheckpointTs())
+
+	// a processor hasn't reported the checkpoint of the mirror.
+	cf.state.TaskPositions["capture-3"] = &model.TaskPosition{}
+	require.Zero(t, cf.mirrorCheckpointTs())
+	delete(cf.state.TaskPositions, "capture-3")
+
+	// the mirror of the DDL sink fails.
+	cf.ddlSink.(*mockDDLSink).mu.mirrorFailed = true
+	require.Zero(t, cf.mirrorCheckpointTs())
+}
+
+func TestUpdateMirrorStatus(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	// pre check and initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	// the changefeed has no mirror.
+	status := &model.ChangeFeedStatus{CheckpointTs: 10}
+	require.False(t, cf.updateMirrorStatus(status))
+	require.Zero(t, status.MirrorCheckpointTs)
+
+	// the mirror starts from the checkpoint of the changefeed.
+	cf.state.Info.Config.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://"}
+	cf.state.TaskPositions = map[model.CaptureID]*model.TaskPosition{
+		"capture-1": {},
+	}
+	require.True(t, cf.updateMirrorStatus(status))
+	require.Equal(t, uint64(10), status.MirrorCheckpointTs)
+	require.False(t, cf.updateMirrorStatus(status))
+
+	// the checkpoint of the mirror advances.
+	cf.state.TaskPositions["capture-1"].MirrorCheckpointTs = 20
+	require.True(t, cf.updateMirrorStatus(status))
+	require.Equal(t, uint64(20), status.MirrorCheckpointTs)
+
+	// a table sink of the mirror restarts after the checkpoint of the mirror,
+	// the mark is kept after the checkpoint of the mirror jumps forward.
+	cf.state.TaskPositions["capture-1"].MirrorIncomplete = true
+	require.True(t, cf.updateMirrorStatus(status))
+	require.True(t, status.MirrorIncomplete)
+	cf.state.TaskPositions["capture-1"] = &model.TaskPosition{MirrorCheckpointTs: 30}
+	require.False(t, cf.updateMirrorStatus(status))
+	require.True(t, status.MirrorIncomplete)
+	require.Equal(t, uint64(20), status.MirrorCheckpointTs)
+
+	// the mark is cleared once the mirror is removed.
+	cf.state.Info.Config.Sink.Mirror = nil
+	require.True(t, cf.updateMirrorStatus(status))
+	require.False(t, status.MirrorIncomplete)
+	require.Zero(t, status.MirrorCheckpointTs)
+
+	// the mirror of the DDL sink fails.
+	cf.state.Info.Config.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://"}
+	cf.ddlSink.(*mockDDLSink).mu.mirrorFailed = true
+	require.True(t, cf.updateMirrorStatus(status))
+	require.True(t, status.MirrorIncomplete)
+}
+
 func TestChangefeedHandleError(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	cf, captures, tester := createChangefeed4Test(ctx, t)
@@ -590,6 +763,51 @@ func testChangefeedReleaseResource(
 	}
 }
 
+func TestCleanupTopicsUnderOwnerLifecycle(t *testing.T) {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	ctx := cdcContext.NewContext(baseCtx, &cdcContext.GlobalVars{
+		EtcdClient: etcdClient,
+	})
+
+	id := model.DefaultChangeFeedID("test-cleanup-topics")
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID, id)
+	state.Info = &model.ChangeFeedInfo{
+		SinkURI: "kafka://127.0.0.1:9092/test",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	state.Info.Config.Sink.KafkaConfig = &config.KafkaConfig{
+		DeleteTopicsOnRemove: util.AddressOf(true),
+	}
+	cf := newChangefeed(id, state, nil, config.NewDefaultSchedulerConfig())
+	cf.isRemoved = true
+
+	// The cleanup is retried if it fails.
+	calls := make(chan struct{}, 16)
+	etcdClient.EXPECT().GetAllChangeFeedInfo(gomock.Any()).
+		DoAndReturn(func(context.Context) (map[model.ChangeFeedID]*model.ChangeFeedInfo, error) {
+			calls <- struct{}{}
+			return nil, errors.New("etcd is unavailable")
+		}).MinTimes(2)
+	cf.cleanupTopics(ctx)
+	<-calls
+	<-calls
+
+	// The cleanup exits once the owner exits.
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		cf.cleanupWg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "cleanup topics is not canceled with the owner")
+	}
+}
+
 func TestBarrierAdvance(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		ctx := cdcContext.NewBackendContext4Test(true)
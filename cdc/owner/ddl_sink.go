@@ -36,6 +36,9 @@ import (
 
 const (
 	defaultErrChSize = 1024
+	// asyncDDLCheckInterval is the interval to check whether the DDL executed
+	// by the sink in the background has finished.
+	asyncDDLCheckInterval = time.Second
 )
 
 // DDLSink is a wrapper of the `Sink` interface for the owner
@@ -53,6 +56,22 @@ type DDLSink interface {
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx context.Context, ddl *model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx context.Context, checkpointTs uint64) error
+	// rotateCredentials recreates the sink with the sink credentials in the
+	// changefeed info before the next checkpoint or DDL event is written.
+	rotateCredentials(info *model.ChangeFeedInfo)
+	// updateDispatchRules updates the dispatch rules of the sink with the ones
+	// in the changefeed info before the next checkpoint or DDL event is written.
+	updateDispatchRules(info *model.ChangeFeedInfo)
+	// getIncompatibleDDLs returns the DDL events whose new schemas are
+	// rejected by the downstream, they're held and never finished until
+	// retryDDLEvent is called after their tables are resumed.
+	getIncompatibleDDLs() []*model.DDLEvent
+	// retryDDLEvent releases the held DDL event, so it's written again the
+	// next time it's emitted.
+	retryDDLEvent(ddl *model.DDLEvent)
+	// isMirrorFailed returns true if the mirror of the sink has failed, the
+	// DDL events and the checkpoints aren't written to it anymore.
+	isMirrorFailed() bool
 	// close the ddlsink, cancel running goroutine.
 	close(ctx context.Context) error
 }
@@ -66,6 +85,18 @@ type ddlSinkImpl struct {
 		sync.Mutex
 		checkpointTs  model.Ts
 		currentTables []*model.TableInfo
+		// info is replaced if the sink credentials are rotated, and
+		// credentialsRotated is set until the sink is recreated.
+		info               *model.ChangeFeedInfo
+		credentialsRotated bool
+		// dispatchRulesUpdated is set until the dispatch rules of the sink
+		// are updated.
+		dispatchRulesUpdated bool
+		// incompatibleDDLs are the DDL events held since the new schemas of
+		// their tables are incompatible with the downstream.
+		incompatibleDDLs []*model.DDLEvent
+		// mirrorFailed is set once the mirror of the sink fails.
+		mirrorFailed bool
 	}
 	// ddlSentTsMap is used to check whether a ddl event in a ddl job has been
 	// sent to `ddlCh` successfully.
@@ -82,7 +113,6 @@ type ddlSinkImpl struct {
 	wg     sync.WaitGroup
 
 	changefeedID model.ChangeFeedID
-	info         *model.ChangeFeedInfo
 
 	sinkRetry     *retry.ErrorRetry
 	reportError   func(err error)
@@ -100,12 +130,12 @@ func newDDLSink(
 		cancel:          func() {},
 
 		changefeedID: changefeedID,
-		info:         info,
 
 		sinkRetry:     retry.NewDefaultErrorRetry(),
 		reportError:   reportError,
 		reportWarning: reportWarning,
 	}
+	res.mu.info = info
 	return res
 }
 
@@ -115,22 +145,31 @@ func ddlSinkInitializer(ctx context.Context, a *ddlSinkImpl) error {
 	log.Info("Try to create ddlSink based on sink",
 		zap.String("namespace", a.changefeedID.Namespace),
 		zap.String("changefeed", a.changefeedID.ID))
-	s, err := factory.New(ctx, a.changefeedID, a.info.SinkURI, a.info.Config)
+	info := a.getInfo()
+	s, err := factory.New(ctx, a.changefeedID, info.SinkURI, info.Config)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	a.sink = s
 
-	if !util.GetOrZero(a.info.Config.EnableSyncPoint) {
+	if !util.GetOrZero(info.Config.EnableSyncPoint) {
 		return nil
 	}
 	return nil
 }
 
+func (s *ddlSinkImpl) getInfo() *model.ChangeFeedInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.info
+}
+
 func (s *ddlSinkImpl) makeSyncPointStoreReady(ctx context.Context) error {
-	if util.GetOrZero(s.info.Config.EnableSyncPoint) && s.syncPointStore == nil {
+	info := s.getInfo()
+	if util.GetOrZero(info.Config.EnableSyncPoint) && s.syncPointStore == nil {
 		syncPointStore, err := syncpointstore.NewSyncPointStore(
-			ctx, s.changefeedID, s.info.SinkURI, util.GetOrZero(s.info.Config.SyncPointRetention))
+			ctx, s.changefeedID, info.SinkURI, info.Config,
+			util.GetOrZero(info.Config.SyncPointRetention))
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -147,6 +186,32 @@ func (s *ddlSinkImpl) makeSyncPointStoreReady(ctx context.Context) error {
 }
 
 func (s *ddlSinkImpl) makeSinkReady(ctx context.Context) error {
+	s.mu.Lock()
+	recreate := s.mu.credentialsRotated
+	dispatchRulesUpdated := s.mu.dispatchRulesUpdated
+	s.mu.credentialsRotated = false
+	s.mu.dispatchRulesUpdated = false
+	info := s.mu.info
+	s.mu.Unlock()
+	// the recreated sink uses the new dispatch rules anyway.
+	if dispatchRulesUpdated && !recreate && s.sink != nil {
+		if updater, ok := s.sink.(ddlsink.DispatchRulesUpdater); ok {
+			if err := updater.UpdateDispatchRules(info.Config); err != nil {
+				log.Warn("ddl sink fails to update the dispatch rules",
+					zap.String("namespace", s.changefeedID.Namespace),
+					zap.String("changefeed", s.changefeedID.ID),
+					zap.Error(err))
+				recreate = true
+			}
+		}
+	}
+	if recreate && s.sink != nil {
+		log.Info("ddl sink is recreated with the new changefeed info",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID))
+		s.sink.Close()
+		s.sink = nil
+	}
 	if s.sink == nil {
 		if err := s.sinkInitHandler(ctx, s); err != nil {
 			log.Warn("ddl sink initialize failed",
@@ -197,7 +262,7 @@ func (s *ddlSinkImpl) writeCheckpointTs(ctx context.Context, lastCheckpointTs *m
 		s.mu.Unlock()
 
 		if err = s.makeSinkReady(ctx); err == nil {
-			err = s.sink.WriteCheckpointTs(ctx, checkpointTs, tables)
+			err = s.handleMirrorError(s.sink.WriteCheckpointTs(ctx, checkpointTs, tables))
 		}
 		if err == nil {
 			*lastCheckpointTs = checkpointTs
@@ -220,6 +285,25 @@ func (s *ddlSinkImpl) writeDDLEvent(ctx context.Context, ddl *model.DDLEvent) er
 			failpoint.Inject("InjectChangefeedDDLError", func() {
 				err = cerror.ErrExecDDLFailed.GenWithStackByArgs()
 			})
+			if err == nil {
+				err = s.waitAsyncDDL(ctx)
+			}
+			err = s.handleMirrorError(err)
+		}
+		// Only the table changed by the DDL is paused if its new schema is
+		// rejected, so the DDL is held instead of being retried.
+		if cerror.ErrAvroIncompatibleSchema.Equal(err) &&
+			ddl.TableInfo != nil && ddl.TableInfo.TableInfo != nil {
+			log.Warn("DDL is held since the new schema is incompatible with the downstream",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Any("DDL", ddl),
+				zap.Error(err))
+			s.reportWarning(err)
+			s.mu.Lock()
+			s.mu.incompatibleDDLs = append(s.mu.incompatibleDDLs, ddl)
+			s.mu.Unlock()
+			return nil
 		}
 		if err != nil {
 			log.Error("Execute DDL failed",
@@ -239,6 +323,29 @@ func (s *ddlSinkImpl) writeDDLEvent(ctx context.Context, ddl *model.DDLEvent) er
 	return s.retrySinkActionWithErrorReport(ctx, doWrite)
 }
 
+// waitAsyncDDL waits for the DDL executed by the sink in the background, so
+// the DDL is done and the checkpoint passes it only after it finishes. The DDL
+// is written again if it fails.
+func (s *ddlSinkImpl) waitAsyncDDL(ctx context.Context) error {
+	asyncSink, ok := s.sink.(ddlsink.AsyncDDLSink)
+	if !ok {
+		return nil
+	}
+	ticker := time.NewTicker(asyncDDLCheckInterval)
+	defer ticker.Stop()
+	for {
+		running, err := asyncSink.IsDDLRunning()
+		if err != nil || !running {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
 func (s *ddlSinkImpl) run(ctx context.Context) {
 	ctx, s.cancel = context.WithCancel(ctx)
 
@@ -369,6 +476,76 @@ func (s *ddlSinkImpl) emitSyncPoint(ctx context.Context, checkpointTs uint64) (e
 	}
 }
 
+func (s *ddlSinkImpl) rotateCredentials(info *model.ChangeFeedInfo) {
+	s.mu.Lock()
+	s.mu.info = info
+	s.mu.credentialsRotated = true
+	s.mu.Unlock()
+	// the sync point store is only used by the caller goroutine, so it can be
+	// closed here and recreated by the next sync point.
+	if s.syncPointStore != nil {
+		if err := s.syncPointStore.Close(); err != nil {
+			log.Warn("close sync point store failed",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Error(err))
+		}
+		s.syncPointStore = nil
+	}
+}
+
+func (s *ddlSinkImpl) updateDispatchRules(info *model.ChangeFeedInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.info = info
+	s.mu.dispatchRulesUpdated = true
+}
+
+func (s *ddlSinkImpl) getIncompatibleDDLs() []*model.DDLEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*model.DDLEvent(nil), s.mu.incompatibleDDLs...)
+}
+
+func (s *ddlSinkImpl) retryDDLEvent(ddl *model.DDLEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, held := range s.mu.incompatibleDDLs {
+		if held == ddl {
+			s.mu.incompatibleDDLs = append(s.mu.incompatibleDDLs[:i], s.mu.incompatibleDDLs[i+1:]...)
+			break
+		}
+	}
+	delete(s.ddlSentTsMap, ddl)
+	log.Info("DDL is retried since its table is resumed",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Any("DDL", ddl))
+}
+
+// handleMirrorError reports the error of the mirror of the sink as a warning,
+// the write is successful since the mirror is given up by the sink.
+func (s *ddlSinkImpl) handleMirrorError(err error) error {
+	if !cerror.IsSinkMirrorError(err) {
+		return err
+	}
+	log.Warn("The mirror of the ddl sink fails, it's given up",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Error(err))
+	s.reportWarning(err)
+	s.mu.Lock()
+	s.mu.mirrorFailed = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *ddlSinkImpl) isMirrorFailed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.mirrorFailed
+}
+
 func (s *ddlSinkImpl) close(ctx context.Context) (err error) {
 	s.cancel()
 	s.wg.Wait()
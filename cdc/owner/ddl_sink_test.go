@@ -21,8 +21,10 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/stretchr/testify/require"
@@ -93,6 +95,53 @@ func TestCheckpoint(t *testing.T) {
 	require.Nil(t, waitCheckpointGrowingUp(mSink, 10))
 }
 
+type mockDispatchRulesSink struct {
+	mockSink
+	rules atomic.Value
+}
+
+func (m *mockDispatchRulesSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	m.rules.Store(cfg.Sink.DispatchRules)
+	return nil
+}
+
+func TestUpdateDispatchRules(t *testing.T) {
+	mSink := &mockDispatchRulesSink{}
+	var inits int32
+	ddlSink := newDDLSink(model.DefaultChangeFeedID("changefeed-test"),
+		&model.ChangeFeedInfo{}, func(err error) {}, func(err error) {})
+	ddlSink.(*ddlSinkImpl).sinkInitHandler = func(ctx context.Context, s *ddlSinkImpl) error {
+		atomic.AddInt32(&inits, 1)
+		s.sink = mSink
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	ddlSink.emitCheckpointTs(1, nil)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&mSink.checkpointTs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	info := &model.ChangeFeedInfo{Config: config.GetDefaultReplicaConfig()}
+	info.Config.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "{schema}"},
+	}
+	ddlSink.updateDispatchRules(info)
+	ddlSink.emitCheckpointTs(10, nil)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&mSink.checkpointTs) == 10
+	}, 5*time.Second, 10*time.Millisecond)
+	// the rules are updated in place without recreating the sink.
+	require.Equal(t, info.Config.Sink.DispatchRules, mSink.rules.Load())
+	require.Equal(t, int32(1), atomic.LoadInt32(&inits))
+}
+
 func TestExecDDLEvents(t *testing.T) {
 	ddlSink, mSink := newDDLSink4Test(func(err error) {}, func(err error) {})
 
@@ -557,3 +606,174 @@ func TestAddSpecialComment(t *testing.T) {
 		})
 	}, "invalid ddlQuery statement size")
 }
+
+func TestExecIncompatibleDDL(t *testing.T) {
+	var warnings int32
+	ddlSink, mSink := newDDLSink4Test(func(err error) {
+		require.FailNow(t, "the incompatible DDL isn't an error", err)
+	}, func(err error) {
+		require.True(t, cerror.ErrAvroIncompatibleSchema.Equal(err))
+		atomic.AddInt32(&warnings, 1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	mSink.ddlError = cerror.ErrAvroIncompatibleSchema.GenWithStackByArgs("test.t-value")
+	ddl := &model.DDLEvent{
+		CommitTs: 2,
+		Query:    "alter table t modify column a varchar(10)",
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t", TableID: 1},
+			TableInfo: &timodel.TableInfo{ID: 1},
+		},
+	}
+	// the DDL is held instead of being retried.
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		require.False(t, done)
+		return len(ddlSink.getIncompatibleDDLs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, ddl, ddlSink.getIncompatibleDDLs()[0])
+	require.Equal(t, int32(1), atomic.LoadInt32(&warnings))
+
+	// the DDL is kept pending until it's retried.
+	done, err := ddlSink.emitDDLEvent(ctx, ddl)
+	require.NoError(t, err)
+	require.False(t, done)
+	require.False(t, ddl.Done.Load())
+
+	// the DDL is written again once its table is resumed.
+	mSink.ddlError = nil
+	ddlSink.retryDDLEvent(ddl)
+	require.Empty(t, ddlSink.getIncompatibleDDLs())
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&warnings))
+}
+
+func TestExecDDLMirrorFailed(t *testing.T) {
+	var warnings int32
+	ddlSink, mSink := newDDLSink4Test(func(err error) {
+		require.FailNow(t, "the failure of the mirror isn't an error", err)
+	}, func(err error) {
+		require.True(t, cerror.IsSinkMirrorError(err))
+		atomic.AddInt32(&warnings, 1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	// the DDL is executed since it's written to the primary sink.
+	mSink.ddlError = cerror.WrapError(cerror.ErrSinkMirrorFailed, errors.New("mirror fails"))
+	ddl := &model.DDLEvent{CommitTs: 2, Query: "create table t(id int primary key)"}
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&warnings))
+	require.True(t, ddlSink.isMirrorFailed())
+}
+
+type mockAsyncSink struct {
+	*mockSink
+	mu      sync.Mutex
+	writes  int
+	running bool
+	err     error
+}
+
+func (m *mockAsyncSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+	m.running = true
+	return nil
+}
+
+func (m *mockAsyncSink) IsDDLRunning() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.err; err != nil {
+		m.err = nil
+		m.running = false
+		return false, err
+	}
+	return m.running, nil
+}
+
+func TestExecAsyncDDL(t *testing.T) {
+	var warnings int32
+	ddlSink, mSink := newDDLSink4Test(func(err error) {
+		require.FailNow(t, "the failed async DDL is retried", err)
+	}, func(err error) {
+		atomic.AddInt32(&warnings, 1)
+	})
+	asyncSink := &mockAsyncSink{mockSink: mSink}
+	// retry the failed DDL in a second.
+	ddlSink.(*ddlSinkImpl).sinkRetry = retry.NewErrorRetry(time.Minute, time.Minute, 1, 2)
+	ddlSink.(*ddlSinkImpl).sinkInitHandler = func(ctx context.Context, s *ddlSinkImpl) error {
+		s.sink = asyncSink
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+	ddlSink.run(ctx)
+
+	ddl := &model.DDLEvent{
+		CommitTs: 2,
+		Query:    "alter table t add index idx(a)",
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t"},
+		},
+	}
+	// the DDL isn't done while it's running in the background.
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		require.False(t, done)
+		asyncSink.mu.Lock()
+		defer asyncSink.mu.Unlock()
+		return asyncSink.writes == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the DDL is written again once it fails.
+	asyncSink.mu.Lock()
+	asyncSink.err = errors.New("async DDL fails")
+	asyncSink.mu.Unlock()
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		require.False(t, done)
+		asyncSink.mu.Lock()
+		defer asyncSink.mu.Unlock()
+		return asyncSink.writes == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&warnings))
+
+	asyncSink.mu.Lock()
+	asyncSink.running = false
+	asyncSink.mu.Unlock()
+	require.Eventually(t, func() bool {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.NoError(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+}
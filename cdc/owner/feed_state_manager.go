@@ -15,12 +15,14 @@ package owner
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -32,13 +34,14 @@ import (
 const (
 	// When errors occurred, and we need to do backoff, we start an exponential backoff
 	// with an interval from 10s to 30min (10s, 20s, 40s, 80s, 160s, 320s,
-	//	 600s, 600s, ...).
+	//	 600s, 600s, ...) by default, which can be changed by the restart
+	// strategy of the changefeed.
 	// To avoid thunderherd, a random factor is also added.
-	defaultBackoffInitInterval        = 10 * time.Second
-	defaultBackoffMaxInterval         = 10 * time.Minute
-	defaultBackoffMaxElapsedTime      = 30 * time.Minute
+	defaultBackoffInitInterval        = config.DefaultRestartInitialBackoff
+	defaultBackoffMaxInterval         = config.DefaultRestartMaxBackoff
+	defaultBackoffMaxElapsedTime      = config.DefaultRestartMaxElapsedTime
 	defaultBackoffRandomizationFactor = 0.1
-	defaultBackoffMultiplier          = 2.0
+	defaultBackoffMultiplier          = config.DefaultRestartBackoffMultiplier
 
 	// If all states recorded in window are 'normal', it can be assumed that the changefeed
 	// is running steady. And then if we enter a state other than normal at next tick,
@@ -63,6 +66,13 @@ type feedStateManager struct {
 	lastRetryCheckpointTs model.Ts                    // checkpoint ts of last retry
 	backoffInterval       time.Duration               // the interval for restarting a changefeed in 'error' state
 	errBackoff            *backoff.ExponentialBackOff // an exponential backoff for restarting a changefeed
+
+	// restartStrategy is the restart strategy applied to errBackoff.
+	restartStrategy *config.RestartStrategyConfig
+	// retries is the number of restarts since the retry is reset.
+	retries int
+	// lastAbnormalTime is the last time the changefeed is not in normal state.
+	lastAbnormalTime time.Time
 }
 
 // newFeedStateManager creates feedStateManager and initialize the exponential backoff
@@ -87,9 +97,36 @@ func (m *feedStateManager) resetErrRetry() {
 	m.errBackoff.Reset()
 	m.backoffInterval = m.errBackoff.NextBackOff()
 	m.lastErrorRetryTime = time.Unix(0, 0)
+	m.retries = 0
 }
 
-// isChangefeedStable check if there are states other than 'normal' in this sliding window.
+// applyRestartStrategy applies the restart strategy of the changefeed to the
+// backoff if it's changed, the changefeed retries from scratch after that.
+func (m *feedStateManager) applyRestartStrategy(cfg *config.ReplicaConfig) {
+	if cfg == nil || reflect.DeepEqual(m.restartStrategy, cfg.RestartStrategy) {
+		return
+	}
+	strategy := cfg.RestartStrategy
+	m.errBackoff.InitialInterval = strategy.GetInitialBackoff()
+	m.errBackoff.MaxInterval = strategy.GetMaxBackoff()
+	m.errBackoff.Multiplier = strategy.GetBackoffMultiplier()
+	// backoff never stops if the MaxElapsedTime is zero.
+	m.errBackoff.MaxElapsedTime = strategy.GetMaxElapsedTime()
+	m.restartStrategy = strategy
+	m.resetErrRetry()
+	log.Info("changefeed restart strategy is applied",
+		zap.String("namespace", m.state.ID.Namespace),
+		zap.String("changefeed", m.state.ID.ID),
+		zap.Int("maxRetries", strategy.GetMaxRetries()),
+		zap.Duration("initialBackoff", m.errBackoff.InitialInterval),
+		zap.Duration("maxBackoff", m.errBackoff.MaxInterval),
+		zap.Float64("backoffMultiplier", m.errBackoff.Multiplier),
+		zap.Duration("maxElapsedTime", m.errBackoff.MaxElapsedTime),
+		zap.Duration("resetWindow", strategy.GetResetWindow()))
+}
+
+// isChangefeedStable check if there are states other than 'normal' in this sliding window,
+// and the changefeed has been normal for the reset window of the restart strategy.
 func (m *feedStateManager) isChangefeedStable() bool {
 	for _, val := range m.stateHistory {
 		if val != model.StateNormal {
@@ -97,7 +134,7 @@ func (m *feedStateManager) isChangefeedStable() bool {
 		}
 	}
 
-	return true
+	return time.Since(m.lastAbnormalTime) >= m.restartStrategy.GetResetWindow()
 }
 
 // shiftStateWindow shift the sliding window
@@ -107,6 +144,9 @@ func (m *feedStateManager) shiftStateWindow(state model.FeedState) {
 	}
 
 	m.stateHistory[defaultStateWindowSize-1] = state
+	if state != model.StateNormal {
+		m.lastAbnormalTime = time.Now()
+	}
 }
 
 func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adminJobPending bool) {
@@ -114,6 +154,7 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 	m.checkAndInitLastRetryCheckpointTs(state.Status)
 
 	m.state = state
+	m.applyRestartStrategy(state.Info.Config)
 	m.shouldBeRunning = true
 	defer func() {
 		if !m.shouldBeRunning {
@@ -144,6 +185,18 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 			m.shouldBeRunning = false
 			return
 		}
+		// fail the changefeed quickly if it keeps failing after restarts,
+		// which is usually caused by an invalid config.
+		if maxRetries := m.restartStrategy.GetMaxRetries(); maxRetries > 0 && m.retries >= maxRetries {
+			log.Warn("The changefeed won't be restarted "+
+				"as it has been restarted for the max retries",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.Int("maxRetries", maxRetries))
+			m.shouldBeRunning = false
+			m.patchState(model.StateFailed)
+			return
+		}
 		// retry the changefeed
 		oldBackoffInterval := m.backoffInterval
 		m.backoffInterval = m.errBackoff.NextBackOff()
@@ -164,6 +217,7 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 		}
 
 		m.lastErrorRetryTime = time.Now()
+		m.retries++
 		if m.state.Status != nil {
 			m.lastRetryCheckpointTs = m.state.Status.CheckpointTs
 		}
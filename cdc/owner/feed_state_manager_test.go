@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 	pd "github.com/tikv/pd/client"
 )
@@ -686,6 +687,62 @@ func TestBackoffNeverStops(t *testing.T) {
 	}
 }
 
+func TestRestartStrategy(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	// the restart strategy overrides the long backoff.
+	manager := newFeedStateManager4Test(time.Hour, time.Hour, 0, 1.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{
+			RestartStrategy: &config.RestartStrategyConfig{
+				MaxRetries:        util.AddressOf(2),
+				InitialBackoff:    util.AddressOf(100 * time.Millisecond),
+				MaxBackoff:        util.AddressOf(100 * time.Millisecond),
+				BackoffMultiplier: util.AddressOf(1.0),
+				MaxElapsedTime:    util.AddressOf(time.Duration(0)),
+			},
+		}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, 100*time.Millisecond, manager.errBackoff.InitialInterval)
+
+	for i := 1; i <= 3; i++ {
+		require.True(t, manager.ShouldRunning())
+		state.PatchTaskPosition(ctx.GlobalVars().CaptureInfo.ID,
+			func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+				return &model.TaskPosition{Error: &model.RunningError{
+					Addr:    ctx.GlobalVars().CaptureInfo.AdvertiseAddr,
+					Code:    "[CDC:ErrEtcdSessionDone]",
+					Message: "fake error for test",
+				}}, true, nil
+			})
+		tester.MustApplyPatches()
+		manager.Tick(state)
+		tester.MustApplyPatches()
+		require.Equal(t, model.StatePending, state.Info.State)
+
+		time.Sleep(100 * time.Millisecond)
+		manager.Tick(state)
+		tester.MustApplyPatches()
+		if i < 3 {
+			require.Equal(t, model.StateWarning, state.Info.State)
+		}
+	}
+	// the changefeed is failed after it's restarted for the max retries.
+	require.Equal(t, model.StateFailed, state.Info.State)
+	require.False(t, manager.ShouldRunning())
+}
+
 func TestUpdateChangefeedEpoch(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	// Set a long backoff time
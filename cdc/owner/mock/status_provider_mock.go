@@ -140,6 +140,36 @@ func (mr *MockStatusProviderMockRecorder) GetProcessors(ctx interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProcessors", reflect.TypeOf((*MockStatusProvider)(nil).GetProcessors), ctx)
 }
 
+// GetSkippedDDLs mocks base method.
+func (m *MockStatusProvider) GetSkippedDDLs(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.SkippedDDL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSkippedDDLs", ctx, changefeedID)
+	ret0, _ := ret[0].([]*model.SkippedDDL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSkippedDDLs indicates an expected call of GetSkippedDDLs.
+func (mr *MockStatusProviderMockRecorder) GetSkippedDDLs(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkippedDDLs", reflect.TypeOf((*MockStatusProvider)(nil).GetSkippedDDLs), ctx, changefeedID)
+}
+
+// GetTableProgresses mocks base method.
+func (m *MockStatusProvider) GetTableProgresses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTableProgresses", ctx, changefeedID)
+	ret0, _ := ret[0].([]*model.TableProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTableProgresses indicates an expected call of GetTableProgresses.
+func (mr *MockStatusProviderMockRecorder) GetTableProgresses(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTableProgresses", reflect.TypeOf((*MockStatusProvider)(nil).GetTableProgresses), ctx, changefeedID)
+}
+
 // IsChangefeedOwner mocks base method.
 func (m *MockStatusProvider) IsChangefeedOwner(ctx context.Context, id model.ChangeFeedID) (bool, error) {
 	m.ctrl.T.Helper()
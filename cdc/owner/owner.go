@@ -118,6 +118,9 @@ type ownerImpl struct {
 		cfg *config.SchedulerConfig,
 	) *changefeed
 	cfg *config.SchedulerConfig
+	// cleanupWg manages the goroutines cleaning up the resources of the
+	// removed changefeeds.
+	cleanupWg sync.WaitGroup
 }
 
 // NewOwner creates a new Owner
@@ -174,6 +177,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 				up = o.upstreamManager.AddUpstream(upstreamInfo)
 			}
 			cfReactor = o.newChangefeed(changefeedID, changefeedState, up, o.cfg)
+			cfReactor.cleanupWg = &o.cleanupWg
 			o.changefeeds[changefeedID] = cfReactor
 		}
 		ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
@@ -199,6 +203,7 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		for _, reactor := range o.changefeeds {
 			reactor.Close(ctx)
 		}
+		o.cleanupWg.Wait()
 		return state, cerror.ErrReactorFinished.GenWithStackByArgs()
 	}
 
@@ -473,6 +478,8 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			}
 			ret[cfID].ResolvedTs = cfReactor.resolvedTs
 			ret[cfID].CheckpointTs = cfReactor.state.Status.CheckpointTs
+			ret[cfID].MirrorCheckpointTs = cfReactor.state.Status.MirrorCheckpointTs
+			ret[cfID].MirrorIncomplete = cfReactor.state.Status.MirrorIncomplete
 		}
 		query.Data = ret
 	case QueryAllChangeFeedInfo:
@@ -514,6 +521,27 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			return errors.Trace(err)
 		}
 		query.Data = ret
+	case QueryTableProgresses:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		provider := cfReactor.GetInfoProvider()
+		if provider == nil {
+			// The scheduler has not been initialized yet.
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		ret, err := provider.GetTableProgresses()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		query.Data = ret
+	case QuerySkippedDDLs:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok || cfReactor.state == nil {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = cfReactor.skippedDDLs.list()
 	case QueryProcessors:
 		var ret []*model.ProcInfoSnap
 		for cfID, cfReactor := range o.changefeeds {
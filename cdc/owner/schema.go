@@ -32,12 +32,43 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxSkippedDDLs is the number of the recently skipped DDLs kept for the API.
+const maxSkippedDDLs = 100
+
+// skippedDDLs records the DDLs recently skipped by the filter of a changefeed,
+// it's only accessed in the owner goroutine.
+type skippedDDLs struct {
+	ddls []*model.SkippedDDL
+}
+
+func (s *skippedDDLs) add(event *model.DDLEvent) {
+	if len(s.ddls) >= maxSkippedDDLs {
+		s.ddls = append(s.ddls[:0], s.ddls[1:]...)
+	}
+	s.ddls = append(s.ddls, &model.SkippedDDL{
+		StartTs:  event.StartTs,
+		CommitTs: event.CommitTs,
+		Type:     event.Type.String(),
+		Table:    event.TableInfo.TableName,
+		Query:    event.Query,
+	})
+}
+
+// list returns the skipped DDLs in the order they're skipped.
+func (s *skippedDDLs) list() []*model.SkippedDDL {
+	res := make([]*model.SkippedDDL, len(s.ddls))
+	copy(res, s.ddls)
+	return res
+}
+
 type schemaWrap4Owner struct {
 	entry.SchemaStorage
 	filter                      filter.Filter
 	config                      *config.ReplicaConfig
 	id                          model.ChangeFeedID
 	metricIgnoreDDLEventCounter prometheus.Counter
+	// skippedDDLs is nil if the skipped DDLs aren't recorded.
+	skippedDDLs *skippedDDLs
 }
 
 func newSchemaWrap4Owner(
@@ -263,6 +294,9 @@ func (s *schemaWrap4Owner) filterDDLEvents(ddlEvents []*model.DDLEvent) ([]*mode
 				zap.Uint64("startTs", event.StartTs),
 				zap.Uint64("commitTs", event.CommitTs),
 			)
+			if s.skippedDDLs != nil && !s.isDDLOfIgnoredTable(event) {
+				s.skippedDDLs.add(event)
+			}
 			continue
 		}
 		res = append(res, event)
@@ -270,6 +304,22 @@ func (s *schemaWrap4Owner) filterDDLEvents(ddlEvents []*model.DDLEvent) ([]*mode
 	return res, nil
 }
 
+// isDDLOfIgnoredTable returns true if the DDL is of the tables or the schemas
+// which aren't replicated by the changefeed.
+func (s *schemaWrap4Owner) isDDLOfIgnoredTable(event *model.DDLEvent) bool {
+	switch event.Type {
+	case timodel.ActionCreateSchema, timodel.ActionDropSchema,
+		timodel.ActionModifySchemaCharsetAndCollate:
+		return s.filter.ShouldIgnoreSchema(event.TableInfo.TableName.Schema)
+	case timodel.ActionRenameTable:
+		return s.filter.ShouldIgnoreTable(
+			event.PreTableInfo.TableName.Schema, event.PreTableInfo.TableName.Table)
+	default:
+		return s.filter.ShouldIgnoreTable(
+			event.TableInfo.TableName.Schema, event.TableInfo.TableName.Table)
+	}
+}
+
 func (s *schemaWrap4Owner) shouldIgnoreTable(t *model.TableInfo) bool {
 	schemaName := t.TableName.Schema
 	tableName := t.TableName.Table
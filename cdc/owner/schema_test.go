@@ -20,6 +20,7 @@ import (
 	"sort"
 	"testing"
 
+	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
@@ -654,3 +655,60 @@ func TestBuildIgnoredDDLJob(t *testing.T) {
 	require.Nil(t, err)
 	require.Len(t, events, 0)
 }
+
+func TestRecordSkippedDDLs(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test.*"}
+	cfg.Filter.DDLDenyList = []bf.EventType{bf.DropTable, bf.TruncateTable}
+	f, err := filter.NewFilter(cfg, "")
+	require.Nil(t, err)
+	schema, err := newSchemaWrap4Owner(helper.Storage(), ver.Ver,
+		cfg, dummyChangeFeedID, f)
+	require.Nil(t, err)
+	schema.skippedDDLs = &skippedDDLs{}
+	ctx := context.Background()
+
+	for _, c := range []struct {
+		query   string
+		skipped bool
+	}{
+		{"create table test.tb1(id int primary key)", false},
+		{"truncate table test.tb1", true},
+		{"create database test2", false},
+		{"create table test2.tb1(id int primary key)", false},
+		// the DDLs of the ignored tables aren't recorded.
+		{"drop table test2.tb1", false},
+		{"drop table test.tb1", true},
+	} {
+		job := helper.DDL2Job(c.query)
+		schema.AdvanceResolvedTs(job.BinlogInfo.FinishedTS - 1)
+		events, err := schema.BuildDDLEvents(ctx, job)
+		require.Nil(t, err)
+		if c.skipped {
+			require.Len(t, events, 0)
+		}
+		require.Nil(t, schema.HandleDDLJob(job))
+	}
+	ddls := schema.skippedDDLs.list()
+	require.Len(t, ddls, 2)
+	require.Equal(t, "truncate table", ddls[0].Type)
+	require.Equal(t, "test", ddls[0].Table.Schema)
+	require.Equal(t, "tb1", ddls[0].Table.Table)
+	require.Equal(t, "DROP TABLE `test`.`tb1`", ddls[1].Query)
+
+	// only the recently skipped DDLs are kept.
+	for i := 0; i < maxSkippedDDLs; i++ {
+		schema.skippedDDLs.add(&model.DDLEvent{
+			StartTs:   uint64(i),
+			TableInfo: &model.TableInfo{},
+		})
+	}
+	ddls = schema.skippedDDLs.list()
+	require.Len(t, ddls, maxSkippedDDLs)
+	require.Equal(t, uint64(0), ddls[0].StartTs)
+}
@@ -39,6 +39,14 @@ type StatusProvider interface {
 	// GetAllTaskStatuses returns the task statuses for the specified changefeed.
 	GetAllTaskStatuses(ctx context.Context, changefeedID model.ChangeFeedID) (map[model.CaptureID]*model.TaskStatus, error)
 
+	// GetTableProgresses returns the replication progresses of all table
+	// spans of the specified changefeed.
+	GetTableProgresses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableProgress, error)
+
+	// GetSkippedDDLs returns the DDLs recently skipped by the filter of the
+	// specified changefeed.
+	GetSkippedDDLs(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.SkippedDDL, error)
+
 	// GetProcessors returns the statuses of all processors
 	GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error)
 
@@ -69,6 +77,10 @@ const (
 	QueryHealth
 	// QueryOwner is the type of query changefeed owner
 	QueryOwner = 6
+	// QueryTableProgresses is the type of query table progresses.
+	QueryTableProgresses = 7
+	// QuerySkippedDDLs is the type of query skipped DDLs.
+	QuerySkippedDDLs = 8
 )
 
 // Query wraps query command and return results.
@@ -153,6 +165,32 @@ func (p *ownerStatusProvider) GetAllTaskStatuses(ctx context.Context,
 	return query.Data.(map[model.CaptureID]*model.TaskStatus), nil
 }
 
+func (p *ownerStatusProvider) GetTableProgresses(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableProgress, error) {
+	query := &Query{
+		Tp:           QueryTableProgresses,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*model.TableProgress), nil
+}
+
+func (p *ownerStatusProvider) GetSkippedDDLs(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.SkippedDDL, error) {
+	query := &Query{
+		Tp:           QuerySkippedDDLs,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*model.SkippedDDL), nil
+}
+
 func (p *ownerStatusProvider) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
 	query := &Query{
 		Tp: QueryProcessors,
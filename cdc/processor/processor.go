@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -41,6 +42,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/transformer"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -75,6 +77,9 @@ type processor struct {
 	sourceManager component[*sourcemanager.SourceManager]
 
 	sinkManager component[*sinkmanager.SinkManager]
+	// sinkInfo is the changefeed info whose sink credentials and dispatch
+	// rules are used by the sink manager.
+	sinkInfo *model.ChangeFeedInfo
 
 	initialized bool
 
@@ -149,7 +154,7 @@ func (p *processor) AddTableSpan(
 					// start table with ResolvedTs in redoDMLManager.
 					p.redo.r.StartTable(span, checkpoint.ResolvedTs)
 				}
-				if err := p.sinkManager.r.StartTable(span, startTs); err != nil {
+				if err := p.sinkManager.r.StartTable(span, p.sinkStartTs(startTs)); err != nil {
 					return false, errors.Trace(err)
 				}
 			}
@@ -189,16 +194,40 @@ func (p *processor) AddTableSpan(
 			zap.Bool("isPrepare", isPrepare))
 	}
 
+	p.sinkManager.r.UpdateMirrorCheckpointTs(p.changefeed.Status.MirrorCheckpointTs)
 	p.sinkManager.r.AddTable(
-		span, startTs, p.changefeed.Info.TargetTs)
+		span, p.sinkStartTs(startTs), p.changefeed.Info.TargetTs)
 	if p.redo.r.Enabled() {
 		p.redo.r.AddTable(span, startTs)
 	}
-	p.sourceManager.r.AddTable(span, p.getTableName(ctx, span.TableID), startTs)
+	p.sourceManager.r.AddTable(span, p.getTableName(ctx, span.TableID), startTs,
+		p.snapshotConfig(startTs))
 
 	return true, nil
 }
 
+// snapshotConfig returns the config of the initial snapshot of a table which
+// starts at the startTs, it's nil if the table doesn't need the snapshot.
+// Only the tables replicated from the start ts of the changefeed are
+// snapshotted, the tables created later are replicated from their creation.
+func (p *processor) snapshotConfig(startTs model.Ts) *config.SnapshotConfig {
+	cfg := p.changefeed.Info.Config.Snapshot
+	if !cfg.Enabled() || startTs != p.changefeed.Info.StartTs {
+		return nil
+	}
+	return cfg
+}
+
+// sinkStartTs returns the start ts of the table sink. The snapshot rows are
+// committed at the startTs, so the table sink starts right before it to
+// receive them.
+func (p *processor) sinkStartTs(startTs model.Ts) model.Ts {
+	if p.snapshotConfig(startTs) != nil {
+		return startTs - 1
+	}
+	return startTs
+}
+
 // RemoveTableSpan implements TableExecutor interface.
 func (p *processor) RemoveTableSpan(span tablepb.Span) bool {
 	if !p.checkReadyForMessages() {
@@ -354,9 +383,11 @@ func (p *processor) getStatsFromSourceManagerAndSinkManager(
 	now := p.upstream.PDClock.CurrentTime()
 
 	stats := tablepb.Stats{
-		RegionCount: pullerStats.RegionCount,
-		CurrentTs:   oracle.ComposeTS(oracle.GetPhysical(now), 0),
-		BarrierTs:   sinkStats.BarrierTs,
+		RegionCount:        pullerStats.RegionCount,
+		CurrentTs:          oracle.ComposeTS(oracle.GetPhysical(now), 0),
+		BarrierTs:          sinkStats.BarrierTs,
+		PendingEventCount:  sinkStats.PendingEvents,
+		SinkFlushLatencyMs: uint64(sinkStats.FlushLatency.Milliseconds()),
 		StageCheckpoints: map[string]tablepb.Checkpoint{
 			"puller-ingress": {
 				CheckpointTs: pullerStats.CheckpointTsIngress,
@@ -522,6 +553,7 @@ func (p *processor) handleErr(err error) error {
 				Addr:    p.captureInfo.AdvertiseAddr,
 				Code:    code,
 				Message: err.Error(),
+				Class:   sinkErrorClass(err),
 			}
 			return position, true, nil
 		})
@@ -560,11 +592,21 @@ func (p *processor) handleWarnings() {
 				Addr:    p.captureInfo.AdvertiseAddr,
 				Code:    code,
 				Message: err.Error(),
+				Class:   sinkErrorClass(err),
 			}
 			return position, true, nil
 		})
 }
 
+// sinkErrorClass returns the class of the error if it comes from the sink.
+func sinkErrorClass(err error) string {
+	var sinkErr tablesink.SinkInternalError
+	if cerror.As(err, &sinkErr) {
+		return string(sinkErr.Class())
+	}
+	return ""
+}
+
 func (p *processor) tick(ctx cdcContext.Context) error {
 	if !p.checkChangefeedNormal() {
 		return cerror.ErrAdminStopProcessor.GenWithStackByArgs()
@@ -582,6 +624,10 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 	if err := p.lazyInit(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	p.rotateSinkCredentials()
+	p.updateDispatchRules()
+	p.pauseTables(ctx)
+	p.updateMirrorCheckpointTs()
 
 	barrier, err := p.agent.Tick(ctx)
 	if err != nil {
@@ -596,6 +642,96 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 	return nil
 }
 
+// rotateSinkCredentials notifies the sink manager if the sink credentials are
+// rotated while the changefeed is running.
+func (p *processor) rotateSinkCredentials() {
+	if p.sinkInfo == nil || p.sinkManager.r == nil ||
+		!p.changefeed.Info.SinkCredentialsChanged(p.sinkInfo) {
+		return
+	}
+	log.Info("sink credentials are rotated",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID))
+	p.sinkManager.r.RotateCredentials(p.changefeed.Info)
+	p.sinkInfo = p.changefeed.Info
+}
+
+// updateDispatchRules notifies the sink manager if the dispatch rules are
+// updated while the changefeed is running.
+func (p *processor) updateDispatchRules() {
+	if p.sinkInfo == nil || p.sinkManager.r == nil ||
+		!p.changefeed.Info.DispatchRulesChanged(p.sinkInfo) {
+		return
+	}
+	log.Info("dispatch rules are updated",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID))
+	p.sinkManager.r.UpdateDispatchRules(p.changefeed.Info)
+	p.sinkInfo = p.changefeed.Info
+}
+
+// pauseTables pauses or resumes the tables once the paused tables of the
+// changefeed are changed while it's running, the tables added later are
+// paused in the same way.
+func (p *processor) pauseTables(ctx context.Context) {
+	if p.sinkManager.r == nil {
+		return
+	}
+	for _, span := range p.sinkManager.r.GetAllCurrentTableSpans() {
+		paused := p.changefeed.Info.IsTablePaused(span.TableID)
+		if paused == p.sinkManager.r.IsTablePaused(span) {
+			continue
+		}
+		if paused {
+			p.sinkManager.r.PauseTable(span)
+			p.sourceManager.r.PauseTable(span)
+		} else {
+			p.sourceManager.r.ResumeTable(span, p.getTableName(ctx, span.TableID))
+			p.sinkManager.r.ResumeTable(span)
+		}
+	}
+}
+
+// updateMirrorCheckpointTs reports the checkpoint of the mirror of the tables
+// in the task position, so the changefeed can be cut over to the mirror once
+// it catches up. The processor without any table is caught up all the time.
+// Whether the mirror may have lost events is reported as well, so the owner
+// persists it.
+func (p *processor) updateMirrorCheckpointTs() {
+	if p.sinkManager.r == nil {
+		return
+	}
+	var checkpointTs model.Ts
+	incomplete := false
+	if p.changefeed.Info.Config.Sink.Mirror != nil {
+		p.sinkManager.r.UpdateMirrorCheckpointTs(p.changefeed.Status.MirrorCheckpointTs)
+		var ok bool
+		if checkpointTs, ok = p.sinkManager.r.GetMirrorCheckpointTs(); !ok &&
+			p.sinkManager.r.GetAllCurrentTableSpansCount() == 0 {
+			checkpointTs = p.changefeed.Status.CheckpointTs
+		}
+		incomplete = p.sinkManager.r.IsMirrorIncomplete()
+	}
+	position := p.changefeed.TaskPositions[p.captureInfo.ID]
+	if position == nil || (position.MirrorCheckpointTs == checkpointTs &&
+		position.MirrorIncomplete == incomplete) {
+		return
+	}
+	p.changefeed.PatchTaskPosition(p.captureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				position = &model.TaskPosition{}
+			}
+			if position.MirrorCheckpointTs == checkpointTs &&
+				position.MirrorIncomplete == incomplete {
+				return position, false, nil
+			}
+			position.MirrorCheckpointTs = checkpointTs
+			position.MirrorIncomplete = incomplete
+			return position, true, nil
+		})
+}
+
 // checkChangefeedNormal checks if the changefeed is runnable.
 func (p *processor) checkChangefeedNormal() bool {
 	// check the state in this tick, make sure that the admin job type of the changefeed is not stopped
@@ -644,6 +780,13 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// the source ID is required by the filter to ignore the rows of local origin.
+	sourceID, err := pdutil.GetSourceID(prcCtx, p.upstream.PDClient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.changefeed.Info.Config.Sink.TiDBSourceID = sourceID
+
 	p.filter, err = filter.NewFilter(p.changefeed.Info.Config, util.GetTimeZoneName(tz))
 	if err != nil {
 		return errors.Trace(err)
@@ -656,19 +799,33 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 	p.ddlHandler.changefeedID = p.changefeedID
 	p.ddlHandler.spawn(prcCtx)
 
+	masker, err := filter.NewColumnMasker(p.changefeed.Info.Config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	appender, err := filter.NewColumnAppender(p.changefeed.Info.Config,
+		config.GetGlobalServerConfig().ClusterID, p.changefeedID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rowTransformer, err := transformer.New(prcCtx, p.changefeed.Info.Config)
+	if err != nil {
+		// the module is read from this capture, which may not have it or
+		// have another one than the changefeed is created with.
+		log.Warn("failed to load the transformer on the capture",
+			zap.String("capture", p.captureInfo.ID),
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.Error(err))
+		return errors.Trace(err)
+	}
 	p.mg.r = entry.NewMounterGroup(p.ddlHandler.r.schemaStorage,
 		p.changefeed.Info.Config.Mounter.WorkerNum,
-		p.filter, tz, p.changefeedID, p.changefeed.Info.Config.Integrity)
+		p.filter, masker, appender, rowTransformer, tz, p.changefeedID, p.changefeed.Info.Config.Integrity)
 	p.mg.name = "MounterGroup"
 	p.mg.changefeedID = p.changefeedID
 	p.mg.spawn(prcCtx)
 
-	sourceID, err := pdutil.GetSourceID(prcCtx, p.upstream.PDClient)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	p.changefeed.Info.Config.Sink.TiDBSourceID = sourceID
-
 	p.redo.r, err = redo.NewDMLManager(prcCtx, p.changefeedID, p.changefeed.Info.Config.Consistent)
 	if err != nil {
 		return err
@@ -699,6 +856,7 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 	p.sinkManager.name = "SinkManager"
 	p.sinkManager.changefeedID = p.changefeedID
 	p.sinkManager.spawn(prcCtx)
+	p.sinkInfo = p.changefeed.Info
 
 	// Bind them so that sourceManager can notify sinkManager.r.
 	p.sourceManager.r.OnResolve(p.sinkManager.r.UpdateReceivedSorterResolvedTs)
@@ -984,8 +1142,8 @@ func (p *processor) WriteDebugInfo(w io.Writer) error {
 		state, _ := p.sinkManager.r.GetTableState(span)
 		stats := p.sinkManager.r.GetTableStats(span)
 		// TODO: add table name.
-		fmt.Fprintf(w, "span: %s, resolvedTs: %d, checkpointTs: %d, state: %s\n",
-			&span, stats.ResolvedTs, stats.CheckpointTs, state)
+		fmt.Fprintf(w, "span: %s, resolvedTs: %d, checkpointTs: %d, state: %s, backpressure: %.2f\n",
+			&span, stats.ResolvedTs, stats.CheckpointTs, state, stats.BackpressureScore)
 	}
 
 	return nil
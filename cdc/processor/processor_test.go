@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -751,3 +752,14 @@ func TestProcessorDostNotStuckInInit(t *testing.T) {
 	require.Nil(t, p.Close())
 	tester.MustApplyPatches()
 }
+
+func TestSinkErrorClass(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", sinkErrorClass(errors.New("puller error")))
+	err := tablesink.NewSinkInternalError(cerror.ErrKafkaInvalidConfig.GenWithStackByArgs())
+	require.Equal(t, "fatal", sinkErrorClass(errors.Trace(err)))
+	code, ok := cerror.RFCCode(errors.Trace(err))
+	require.True(t, ok)
+	require.Equal(t, cerror.ErrKafkaInvalidConfig.RFCCode(), code)
+}
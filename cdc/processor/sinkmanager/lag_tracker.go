@@ -0,0 +1,136 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// lagTrackInterval is the interval of observing the checkpoint lag of the tables.
+	lagTrackInterval = time.Second
+	// lagSLOWarningInterval is the min interval between two lag SLO warnings,
+	// so the warning of the changefeed isn't overwritten too frequently.
+	lagSLOWarningInterval = time.Minute
+	// maxLaggingTablesInWarning is the max number of tables listed in a warning.
+	maxLaggingTablesInWarning = 10
+)
+
+// tableLagTracker observes the checkpoint lag of the tables into the histogram,
+// and generates a warning if any table lags behind the lag SLO.
+type tableLagTracker struct {
+	changefeedID model.ChangeFeedID
+	// slo is the lag SLO of the tables, zero means it's not set.
+	slo time.Duration
+	// lastWarningTime is the time of the last lag SLO warning.
+	lastWarningTime time.Time
+	// tables are the names of the tables observed in the last round, the
+	// metrics of the tables which aren't observed anymore are removed.
+	tables map[string]struct{}
+
+	metricLagSLO        prometheus.Gauge
+	metricSLOViolations prometheus.Counter
+}
+
+func newTableLagTracker(changefeedID model.ChangeFeedID, slo *time.Duration) *tableLagTracker {
+	t := &tableLagTracker{
+		changefeedID: changefeedID,
+		tables:       make(map[string]struct{}),
+		metricLagSLO: tableLagSLO.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricSLOViolations: tableLagSLOViolations.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+	if slo != nil {
+		t.slo = *slo
+	}
+	t.metricLagSLO.Set(t.slo.Seconds())
+	return t
+}
+
+// track observes the checkpoint lag of the tables, which is keyed by the table
+// names. A warning is returned if some tables lag behind the lag SLO and no
+// warning has been returned in the last lagSLOWarningInterval.
+func (t *tableLagTracker) track(now time.Time, lags map[string]time.Duration) error {
+	for name := range t.tables {
+		if _, ok := lags[name]; !ok {
+			tableCheckpointLag.DeleteLabelValues(
+				t.changefeedID.Namespace, t.changefeedID.ID, name)
+			delete(t.tables, name)
+		}
+	}
+
+	var laggingTables []string
+	for name, lag := range lags {
+		t.tables[name] = struct{}{}
+		tableCheckpointLag.
+			WithLabelValues(t.changefeedID.Namespace, t.changefeedID.ID, name).
+			Observe(lag.Seconds())
+		if t.slo > 0 && lag > t.slo {
+			laggingTables = append(laggingTables, name)
+		}
+	}
+	if len(laggingTables) == 0 {
+		return nil
+	}
+	t.metricSLOViolations.Add(float64(len(laggingTables)))
+	if now.Sub(t.lastWarningTime) < lagSLOWarningInterval {
+		return nil
+	}
+	t.lastWarningTime = now
+
+	// list the most lagging tables first.
+	sort.Slice(laggingTables, func(i, j int) bool {
+		li, lj := lags[laggingTables[i]], lags[laggingTables[j]]
+		if li != lj {
+			return li > lj
+		}
+		return laggingTables[i] < laggingTables[j]
+	})
+	tables := make([]string, 0, maxLaggingTablesInWarning+1)
+	for i, name := range laggingTables {
+		if i == maxLaggingTablesInWarning {
+			tables = append(tables,
+				fmt.Sprintf("and %d more", len(laggingTables)-maxLaggingTablesInWarning))
+			break
+		}
+		tables = append(tables, fmt.Sprintf("%s(lag %s)", name, lags[name].Round(time.Second)))
+	}
+	err := cerror.ErrTableLagSLOExceeded.GenWithStackByArgs(t.slo, strings.Join(tables, ", "))
+	log.Warn("some tables lag behind the lag SLO",
+		zap.String("namespace", t.changefeedID.Namespace),
+		zap.String("changefeed", t.changefeedID.ID),
+		zap.Duration("lagSLO", t.slo),
+		zap.Int("laggingTables", len(laggingTables)),
+		zap.Error(err))
+	return err
+}
+
+// close removes the metrics of the changefeed.
+func (t *tableLagTracker) close() {
+	tableCheckpointLag.DeletePartialMatch(prometheus.Labels{
+		"namespace": t.changefeedID.Namespace, "changefeed": t.changefeedID.ID,
+	})
+	tableLagSLO.DeleteLabelValues(t.changefeedID.Namespace, t.changefeedID.ID)
+	tableLagSLOViolations.DeleteLabelValues(t.changefeedID.Namespace, t.changefeedID.ID)
+}
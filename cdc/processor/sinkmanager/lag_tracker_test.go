@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableLagTrackerWithoutSLO(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("lag-tracker-without-slo")
+	tracker := newTableLagTracker(changefeedID, nil)
+	defer tracker.close()
+
+	err := tracker.track(time.Now(), map[string]time.Duration{"test.t1": time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, 1, testutil.CollectAndCount(tableCheckpointLag.MustCurryWith(
+		map[string]string{"namespace": changefeedID.Namespace, "changefeed": changefeedID.ID})))
+}
+
+func TestTableLagTrackerWithSLO(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("lag-tracker-with-slo")
+	tracker := newTableLagTracker(changefeedID, util.AddressOf(10*time.Second))
+	defer tracker.close()
+	require.Equal(t, float64(10), testutil.ToFloat64(tracker.metricLagSLO))
+
+	now := time.Now()
+	err := tracker.track(now, map[string]time.Duration{
+		"test.t1": time.Second,
+		"test.t2": 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	err = tracker.track(now, map[string]time.Duration{
+		"test.t1": time.Second,
+		"test.t2": 20 * time.Second,
+		"test.t3": 30 * time.Second,
+	})
+	require.True(t, cerror.ErrTableLagSLOExceeded.Equal(err))
+	require.Contains(t, err.Error(), "test.t3(lag 30s), test.t2(lag 20s)")
+	require.Equal(t, float64(2), testutil.ToFloat64(tracker.metricSLOViolations))
+
+	// the warning isn't reported again in the lagSLOWarningInterval.
+	err = tracker.track(now.Add(time.Second), map[string]time.Duration{
+		"test.t2": 20 * time.Second,
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(3), testutil.ToFloat64(tracker.metricSLOViolations))
+	// the metrics of the tables which aren't observed anymore are removed.
+	require.Equal(t, 1, testutil.CollectAndCount(tableCheckpointLag.MustCurryWith(
+		map[string]string{"namespace": changefeedID.Namespace, "changefeed": changefeedID.ID})))
+
+	err = tracker.track(now.Add(lagSLOWarningInterval), map[string]time.Duration{
+		"test.t2": 20 * time.Second,
+	})
+	require.True(t, cerror.ErrTableLagSLOExceeded.Equal(err))
+}
+
+func TestTableLagTrackerListsLimitedTables(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("lag-tracker-limited-tables")
+	tracker := newTableLagTracker(changefeedID, util.AddressOf(time.Second))
+	defer tracker.close()
+
+	lags := make(map[string]time.Duration)
+	for i := 0; i < maxLaggingTablesInWarning+2; i++ {
+		lags["test.t"+strconv.Itoa(i)] = time.Minute
+	}
+	err := tracker.track(time.Now(), lags)
+	require.True(t, cerror.ErrTableLagSLOExceeded.Equal(err))
+	require.Contains(t, err.Error(), "and 2 more")
+}
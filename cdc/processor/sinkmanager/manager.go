@@ -16,7 +16,9 @@ package sinkmanager
 import (
 	"context"
 	"math"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -33,6 +35,7 @@ import (
 	tablesinkmetrics "github.com/pingcap/tiflow/cdc/sink/metrics/tablesink"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/errorutil"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -52,6 +55,9 @@ const (
 	cleanTableMinEvents = 128
 	maxRetryDuration    = 30 * time.Minute
 	errGCInterval       = 10 * time.Minute
+	// maxBackpressureScore is the backpressure score of a table sink, no more
+	// sink tasks are generated for the table sink until its score drops.
+	maxBackpressureScore = 1.0
 )
 
 // TableStats of a table sink.
@@ -59,6 +65,14 @@ type TableStats struct {
 	CheckpointTs model.Ts
 	ResolvedTs   model.Ts
 	BarrierTs    model.Ts
+	// BackpressureScore is the backpressure of the table sink in [0, 1].
+	BackpressureScore float64
+	// PendingEvents is the number of events which are written to the
+	// backend sink but not flushed yet.
+	PendingEvents uint64
+	// FlushLatency is the moving average of the latency of writing events
+	// to the backend sink.
+	FlushLatency time.Duration
 }
 
 // SinkManager is the implementation of SinkManager.
@@ -66,6 +80,16 @@ type SinkManager struct {
 	changefeedID model.ChangeFeedID
 
 	changefeedInfo *model.ChangeFeedInfo
+	// rotatedInfo is the changefeed info with the rotated sink credentials,
+	// the sink factory is recreated by it when credentialsRotated is notified.
+	rotatedInfo struct {
+		sync.Mutex
+		info *model.ChangeFeedInfo
+	}
+	credentialsRotated chan struct{}
+	// delayDuration is the delay-duration of the changefeed, it's kept here
+	// because changefeedInfo can be replaced while the sink manager is running.
+	delayDuration time.Duration
 
 	// up is the upstream and used to get the current pd time.
 	up *upstream.Upstream
@@ -91,6 +115,15 @@ type SinkManager struct {
 
 	// tableSinks is a map from tableID to tableSink.
 	tableSinks spanz.SyncMap
+	// mirrorCheckpointTs is the min checkpoint of the mirror of the tables
+	// observed by backgroundTrackLag, it's 0 if no table writes to a mirror.
+	mirrorCheckpointTs atomic.Uint64
+	// persistedMirrorCheckpointTs is the checkpoint of the mirror persisted by
+	// the owner, the mirror has received all the events before it.
+	persistedMirrorCheckpointTs atomic.Uint64
+	// mirrorIncomplete is true once the mirror may have lost events of any
+	// table, because it fails or a table sink starts after its checkpoint.
+	mirrorIncomplete atomic.Bool
 
 	// sinkWorkers used to pull data from source manager.
 	sinkWorkers []*sinkWorker
@@ -100,6 +133,9 @@ type SinkManager struct {
 	// sinkMemQuota is used to control the total memory usage of the table sink.
 	sinkMemQuota *memquota.MemQuota
 	sinkRetry    *retry.ErrorRetry
+	// sinkBufferQuota limits the events buffered by the table sinks before
+	// they're resolved.
+	sinkBufferQuota *tablesink.BufferQuota
 	// redoWorkers used to pull data from source manager.
 	redoWorkers []*redoWorker
 	// redoTaskChan is used to send tasks to redoWorkers.
@@ -121,7 +157,8 @@ type SinkManager struct {
 	wg sync.WaitGroup
 
 	// Metric for table sink.
-	metricsTableSinkTotalRows prometheus.Counter
+	metricsTableSinkTotalRows     prometheus.Counter
+	metricsTableSinkBackpressured prometheus.Counter
 }
 
 // New creates a new sink manager.
@@ -136,6 +173,7 @@ func New(
 	m := &SinkManager{
 		changefeedID:   changefeedID,
 		changefeedInfo: changefeedInfo,
+		delayDuration:  util.GetOrZero(changefeedInfo.Config.DelayDuration),
 		up:             up,
 		schemaStorage:  schemaStorage,
 		sourceManager:  sourceManager,
@@ -145,9 +183,12 @@ func New(
 		sinkTaskChan:        make(chan *sinkTask),
 		sinkWorkerAvailable: make(chan struct{}, 1),
 		sinkRetry:           retry.NewDefaultErrorRetry(),
+		credentialsRotated:  make(chan struct{}, 1),
 
 		metricsTableSinkTotalRows: tablesinkmetrics.TotalRowsCountCounter.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricsTableSinkBackpressured: tableSinkBackpressured.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 	}
 
 	if redoDMLMgr != nil && redoDMLMgr.Enabled() {
@@ -167,6 +208,16 @@ func New(
 		m.redoMemQuota = memquota.NewMemQuota(changefeedID, 0, "redo")
 	}
 
+	m.sinkBufferQuota = tablesink.NewBufferQuota(changefeedID, changefeedInfo.Config.Sink.TableSinkBuffer,
+		util.GetOrZero(changefeedInfo.Config.Sink.TxnAtomicity).ShouldSplitTxn())
+	// The buffered events are spilled to the pebble instances of the sort
+	// engine if the disk policy is used.
+	if sourceManager != nil && sourceManager.CanSpill() {
+		m.sinkBufferQuota.SetSpillStoreCreator(func(span tablepb.Span) tablesink.SpillStore {
+			return sourceManager.NewSpillStore(span)
+		})
+	}
+
 	m.ready = make(chan struct{})
 
 	return m
@@ -191,10 +242,16 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 
 	gcErrors := make(chan error, 16)
 	sinkFactoryErrors := make(chan error, 16)
+	mirrorErrors := make(chan error, 16)
 	sinkErrors := make(chan error, 16)
 	redoErrors := make(chan error, 16)
 
 	m.backgroundGC(gcErrors)
+	var lagWarnings chan<- error
+	if len(warnings) > 0 {
+		lagWarnings = warnings[0]
+	}
+	m.backgroundTrackLag(lagWarnings)
 	if m.sinkEg == nil {
 		var sinkCtx context.Context
 		m.sinkEg, sinkCtx = errgroup.WithContext(m.managerCtx)
@@ -244,7 +301,7 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 
 	// SinkManager will restart some internal modules if necessasry.
 	for {
-		if err := m.initSinkFactory(sinkFactoryErrors); err != nil {
+		if err := m.initSinkFactory(sinkFactoryErrors, mirrorErrors); err != nil {
 			select {
 			case <-m.managerCtx.Done():
 			case sinkFactoryErrors <- err:
@@ -260,13 +317,41 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 			return errors.Trace(err)
 		case err = <-redoErrors:
 			return errors.Trace(err)
+		case <-m.credentialsRotated:
+			m.rotateSinkFactory()
+			sinkFactoryErrors = make(chan error, 16)
+			mirrorErrors = make(chan error, 16)
+			continue
+		case err = <-mirrorErrors:
+			// The mirror is given up by the table sinks once it fails, so the
+			// changefeed keeps running and the error is reported as a warning.
+			m.mirrorIncomplete.Store(true)
+			log.Warn("Sink manager mirror sink fails",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Error(err))
+			if len(warnings) > 0 {
+				select {
+				case <-m.managerCtx.Done():
+				case warnings[0] <- cerror.WrapError(cerror.ErrSinkMirrorFailed, err):
+				}
+			}
+			continue
 		case err = <-sinkFactoryErrors:
+			// The class is kept in the error, so it can be reported with the
+			// warnings or the error of the processor.
+			sinkErr := tablesink.NewSinkInternalError(err)
+			err = sinkErr
+			sinkErrorCount.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID,
+				string(sinkErr.Class())).Inc()
 			log.Warn("Sink manager backend sink fails",
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
+				zap.String("class", string(sinkErr.Class())),
 				zap.Error(err))
 			m.clearSinkFactory()
 			sinkFactoryErrors = make(chan error, 16)
+			mirrorErrors = make(chan error, 16)
 
 			start := time.Now()
 			log.Info("Sink manager is closing all table sinks",
@@ -284,7 +369,8 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 		}
 
 		// If the error is retryable, we should retry to re-establish the internal resources.
-		if !cerror.IsChangefeedUnRetryableError(err) && errors.Cause(err) != context.Canceled {
+		// Only the fatal errors fail the changefeed.
+		if errorutil.ClassifySinkError(err).Retryable() && !cerror.Is(err, context.Canceled) {
 			select {
 			case <-m.managerCtx.Done():
 			case warnings[0] <- err:
@@ -304,7 +390,7 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 	}
 }
 
-func (m *SinkManager) initSinkFactory(errCh chan error) error {
+func (m *SinkManager) initSinkFactory(errCh chan error, mirrorErrCh chan error) error {
 	m.sinkFactoryMu.Lock()
 	defer m.sinkFactoryMu.Unlock()
 	if m.sinkFactory != nil {
@@ -322,7 +408,7 @@ func (m *SinkManager) initSinkFactory(errCh chan error) error {
 		return errors.Trace(err)
 	}
 
-	if m.sinkFactory, err = factory.New(m.managerCtx, m.changefeedID, uri, cfg, errCh); err == nil {
+	if m.sinkFactory, err = factory.New(m.managerCtx, m.changefeedID, uri, cfg, errCh, mirrorErrCh); err == nil {
 		log.Info("Sink manager inits sink factory success",
 			zap.String("namespace", m.changefeedID.Namespace),
 			zap.String("changefeed", m.changefeedID.ID))
@@ -346,6 +432,80 @@ func (m *SinkManager) clearSinkFactory() {
 	}
 }
 
+// RotateCredentials recreates the sink factory with the sink credentials in
+// the changefeed info, so the rotated credentials are used without restarting
+// the changefeed.
+func (m *SinkManager) RotateCredentials(info *model.ChangeFeedInfo) {
+	m.rotatedInfo.Lock()
+	m.rotatedInfo.info = info
+	m.rotatedInfo.Unlock()
+	select {
+	case m.credentialsRotated <- struct{}{}:
+	default:
+	}
+}
+
+// UpdateDispatchRules rebuilds the event routers of the sink with the dispatch
+// rules in the changefeed info, the table sinks and the connections to the
+// downstream are kept. The sink factory is recreated like rotating the
+// credentials if the rules can't be updated in place.
+func (m *SinkManager) UpdateDispatchRules(info *model.ChangeFeedInfo) {
+	// the pending rotation recreates the sink factory with the new rules.
+	m.rotatedInfo.Lock()
+	m.rotatedInfo.info = info
+	m.rotatedInfo.Unlock()
+
+	m.sinkFactoryMu.Lock()
+	var err error
+	if m.sinkFactory != nil {
+		err = m.sinkFactory.UpdateDispatchRules(info.Config)
+	}
+	if err == nil {
+		m.changefeedInfo = info
+	}
+	m.sinkFactoryMu.Unlock()
+	if err != nil {
+		log.Warn("Sink manager fails to update the dispatch rules, recreate the sink factory",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Error(err))
+		m.RotateCredentials(info)
+	}
+}
+
+// rotateSinkFactory detaches the table sinks so that no more events are written
+// into them, and closes them after their pending events are flushed by the old
+// sink factory. Then the sink factory is recreated with the rotated credentials
+// in the changefeed info, and the table sinks are recreated from their
+// checkpoints when the next sink tasks come.
+func (m *SinkManager) rotateSinkFactory() {
+	m.rotatedInfo.Lock()
+	info := m.rotatedInfo.info
+	m.rotatedInfo.Unlock()
+
+	start := time.Now()
+	log.Info("Sink manager is rotating the sink credentials",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID))
+	// No table sink can be created by the old sink factory during the rotation.
+	m.sinkFactoryMu.Lock()
+	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
+		value.(*tableSinkWrapper).detachAndCloseTableSink()
+		m.sinkMemQuota.ClearTable(span)
+		return true
+	})
+	if m.sinkFactory != nil {
+		m.sinkFactory.Close()
+		m.sinkFactory = nil
+	}
+	m.changefeedInfo = info
+	m.sinkFactoryMu.Unlock()
+	log.Info("Sink manager has rotated the sink credentials",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Duration("cost", time.Since(start)))
+}
+
 func (m *SinkManager) startSinkWorkers(ctx context.Context, eg *errgroup.Group, splitTxn bool, enableOldValue bool) {
 	for i := 0; i < sinkWorkerNum; i++ {
 		w := newSinkWorker(m.changefeedID, m.sourceManager,
@@ -427,6 +587,93 @@ func (m *SinkManager) backgroundGC(errors chan<- error) {
 	}()
 }
 
+// backgroundTrackLag observes the checkpoint lag of the tables and the mirror
+// periodically, the lag SLO warnings are sent to warnings if it's not nil.
+func (m *SinkManager) backgroundTrackLag(warnings chan<- error) {
+	tracker := newTableLagTracker(m.changefeedID, m.changefeedInfo.Config.LagSLO)
+	delay := m.delayDuration
+	ticker := time.NewTicker(lagTrackInterval)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer ticker.Stop()
+		defer tracker.close()
+		defer mirrorCheckpointLag.DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+		for {
+			select {
+			case <-m.managerCtx.Done():
+				return
+			case <-ticker.C:
+				now := m.up.PDClock.CurrentTime()
+				lags := make(map[string]time.Duration)
+				var mirrorLag time.Duration
+				var mirrorCheckpointTs model.Ts
+				hasMirror := false
+				m.tableSinks.Range(func(span tablepb.Span, value any) bool {
+					wrapper := value.(*tableSinkWrapper)
+					if wrapper.getState() != tablepb.TableStateReplicating {
+						return true
+					}
+					checkpointTs := wrapper.getCheckpointTs().ResolvedMark()
+					// the events are held back by the delay-duration on purpose,
+					// so it isn't counted in the lag.
+					lag := now.Sub(oracle.GetTimeFromTS(checkpointTs)) - delay
+					// the lag of a table is the max lag of its spans.
+					name := m.tableName(span.TableID)
+					if lag > lags[name] {
+						lags[name] = lag
+					}
+					if wrapper.isMirrorFailed() {
+						m.mirrorIncomplete.Store(true)
+					}
+					if mirrorTs, ok := wrapper.getMirrorCheckpointTs(); ok {
+						if !hasMirror || mirrorTs.ResolvedMark() < mirrorCheckpointTs {
+							mirrorCheckpointTs = mirrorTs.ResolvedMark()
+						}
+						hasMirror = true
+						lag := now.Sub(oracle.GetTimeFromTS(mirrorTs.ResolvedMark())) - delay
+						if lag > mirrorLag {
+							mirrorLag = lag
+						}
+					}
+					return true
+				})
+				if hasMirror {
+					mirrorCheckpointLag.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID).
+						Set(mirrorLag.Seconds())
+				}
+				m.mirrorCheckpointTs.Store(mirrorCheckpointTs)
+				if err := tracker.track(now, lags); err != nil && warnings != nil {
+					select {
+					case warnings <- err:
+					case <-m.managerCtx.Done():
+					}
+				}
+			}
+		}
+	}()
+}
+
+// tableName returns the name of the table, the table ID is used if the table
+// isn't found in the schema storage.
+func (m *SinkManager) tableName(tableID model.TableID) string {
+	if tableName, ok := m.physicalTableName(tableID); ok {
+		return tableName.String()
+	}
+	return strconv.FormatInt(tableID, 10)
+}
+
+// physicalTableName returns the name of the physical table, false is returned
+// if the table isn't found in the schema storage.
+func (m *SinkManager) physicalTableName(tableID model.TableID) (model.TableName, bool) {
+	if snap := m.schemaStorage.GetLastSnapshot(); snap != nil {
+		if tableInfo, ok := snap.PhysicalTableByID(tableID); ok {
+			return tableInfo.TableName, true
+		}
+	}
+	return model.TableName{}, false
+}
+
 func (m *SinkManager) getUpperBound(tableSinkUpperBoundTs model.Ts) engine.Position {
 	schemaTs := m.schemaStorage.ResolvedTs()
 	if schemaTs != math.MaxUint64 && tableSinkUpperBoundTs > schemaTs+1 {
@@ -436,9 +683,31 @@ func (m *SinkManager) getUpperBound(tableSinkUpperBoundTs model.Ts) engine.Posit
 	return engine.Position{StartTs: tableSinkUpperBoundTs - 1, CommitTs: tableSinkUpperBoundTs}
 }
 
+// getSinkUpperBound is like getUpperBound, but it's only used by the table sink
+// tasks. The events committed in the last delay-duration are held back, while
+// the redo logs are still written without delay.
+func (m *SinkManager) getSinkUpperBound(tableSinkUpperBoundTs model.Ts) engine.Position {
+	if delay := m.delayDuration; delay > 0 {
+		delayedTs := oracle.GoTimeToTS(m.up.PDClock.CurrentTime().Add(-delay))
+		if tableSinkUpperBoundTs > delayedTs {
+			tableSinkUpperBoundTs = delayedTs
+		}
+	}
+	return m.getUpperBound(tableSinkUpperBoundTs)
+}
+
 // generateSinkTasks generates tasks to fetch data from the source manager.
 func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 	dispatchTasks := func() error {
+		// The events buffered by the table sinks exceed the quota, so no more
+		// events are read until the buffered ones are flushed.
+		if m.sinkBufferQuota.Blocking() {
+			log.Debug("Table sink buffer quota is exceeded, skip generating sink tasks",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Uint64("bufferedBytes", m.sinkBufferQuota.Used()))
+			return nil
+		}
 		tables := make([]*tableSinkWrapper, 0, sinkWorkerNum)
 		progs := make([]*progress, 0, sinkWorkerNum)
 
@@ -485,17 +754,36 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 			tableSink := tables[i]
 			slowestTableProgress := progs[i]
 			lowerBound := slowestTableProgress.nextLowerBoundPos
-			upperBound := m.getUpperBound(tableSink.getUpperBoundTs())
+			upperBound := m.getSinkUpperBound(tableSink.getUpperBoundTs())
 			// The table has no available progress.
 			if lowerBound.Compare(upperBound) >= 0 {
 				m.sinkProgressHeap.push(slowestTableProgress)
 				continue
 			}
+			// The table is paused, so no more events are read for it
+			// until it's resumed.
+			if tableSink.isPaused() {
+				m.sinkProgressHeap.push(slowestTableProgress)
+				continue
+			}
 			// The table hasn't been attached to a sink.
 			if !tableSink.initTableSink() {
 				m.sinkProgressHeap.push(slowestTableProgress)
 				continue
 			}
+			// The table sink can't keep up with the events, so no more events
+			// are read for it until the pending events are flushed, otherwise
+			// the memory quota may be exhausted by a slow table.
+			if score := tableSink.getBackpressureScore(); score >= maxBackpressureScore {
+				log.Debug("Table sink is backpressured, skip it",
+					zap.String("namespace", m.changefeedID.Namespace),
+					zap.String("changefeed", m.changefeedID.ID),
+					zap.Stringer("span", &tableSink.span),
+					zap.Float64("backpressureScore", score))
+				m.metricsTableSinkBackpressured.Inc()
+				m.sinkProgressHeap.push(slowestTableProgress)
+				continue
+			}
 
 			// No available memory, skip this round directly.
 			if !m.sinkMemQuota.TryAcquire(requestMemSize) {
@@ -511,7 +799,7 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 			t := &sinkTask{
 				span:          tableSink.span,
 				lowerBound:    lowerBound,
-				getUpperBound: m.getUpperBound,
+				getUpperBound: m.getSinkUpperBound,
 				tableSink:     tableSink,
 				callback: func(lastWrittenPos engine.Position) {
 					p := &progress{
@@ -753,14 +1041,32 @@ func (m *SinkManager) UpdateBarrierTs(
 
 // AddTable adds a table(TableSink) to the sink manager.
 func (m *SinkManager) AddTable(span tablepb.Span, startTs model.Ts, targetTs model.Ts) {
-	sinkWrapper := newTableSinkWrapper(
+	var sinkWrapper *tableSinkWrapper
+	sinkWrapper = newTableSinkWrapper(
 		m.changefeedID,
 		span,
 		func() tablesink.TableSink {
 			if m.sinkFactoryMu.TryLock() {
 				defer m.sinkFactoryMu.Unlock()
 				if m.sinkFactory != nil {
-					return m.sinkFactory.CreateTableSink(m.changefeedID, span, startTs, m.metricsTableSinkTotalRows)
+					sinkFactory := m.sinkFactory
+					if sinkFactory.HasTargets() {
+						// the table sink is created once the table is found in
+						// the schema storage, so it's routed by the table name.
+						tableName, ok := m.physicalTableName(span.TableID)
+						if !ok {
+							return nil
+						}
+						sinkFactory = sinkFactory.ForTable(tableName)
+					}
+					tableSink := sinkFactory.CreateTableSink(m.changefeedID, span, startTs,
+						m.sinkBufferQuota, m.metricsTableSinkTotalRows)
+					if _, ok := tableSink.(*tablesink.MirrorTableSink); ok {
+						// the events are written from the checkpoint of the
+						// table, the lock of the wrapper is held by initTableSink.
+						m.checkMirrorStartTs(span, sinkWrapper.tableSinkCheckpointTs.Ts)
+					}
+					return tableSink
 				}
 			}
 			return nil
@@ -873,6 +1179,44 @@ func (m *SinkManager) RemoveTable(span tablepb.Span) {
 	}
 }
 
+// PauseTable freezes the table sink, no more events are sent to it until
+// the table is resumed. The events already sent to it are still flushed.
+func (m *SinkManager) PauseTable(span tablepb.Span) {
+	m.setTablePaused(span, true)
+}
+
+// ResumeTable sends the events to the paused table sink again.
+func (m *SinkManager) ResumeTable(span tablepb.Span) {
+	m.setTablePaused(span, false)
+}
+
+// IsTablePaused returns true if the table sink is paused.
+func (m *SinkManager) IsTablePaused(span tablepb.Span) bool {
+	value, ok := m.tableSinks.Load(span)
+	if !ok {
+		return false
+	}
+	return value.(*tableSinkWrapper).isPaused()
+}
+
+func (m *SinkManager) setTablePaused(span tablepb.Span, paused bool) {
+	value, ok := m.tableSinks.Load(span)
+	if !ok {
+		log.Warn("Table sink not found when pausing or resuming table",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Bool("paused", paused))
+		return
+	}
+	value.(*tableSinkWrapper).paused.Store(paused)
+	log.Info("Table sink is paused or resumed",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Bool("paused", paused))
+}
+
 // GetAllCurrentTableSpans returns all spans in the sinkManager.
 func (m *SinkManager) GetAllCurrentTableSpans() []tablepb.Span {
 	var spans []tablepb.Span
@@ -951,10 +1295,54 @@ func (m *SinkManager) GetTableStats(span tablepb.Span) TableStats {
 			zap.Any("checkpointTs", checkpointTs),
 			zap.Uint64("barrierTs", tableSink.barrierTs.Load()))
 	}
+	sinkStats := tableSink.getTableSinkStats()
 	return TableStats{
-		CheckpointTs: checkpointTs.ResolvedMark(),
-		ResolvedTs:   resolvedTs,
-		BarrierTs:    tableSink.barrierTs.Load(),
+		CheckpointTs:      checkpointTs.ResolvedMark(),
+		ResolvedTs:        resolvedTs,
+		BarrierTs:         tableSink.barrierTs.Load(),
+		BackpressureScore: tableSink.getBackpressureScore(),
+		PendingEvents:     sinkStats.PendingEvents,
+		FlushLatency:      sinkStats.FlushLatency,
+	}
+}
+
+// GetMirrorCheckpointTs returns the min checkpoint of the mirror of the
+// replicating tables, false is returned if no table writes to a mirror.
+func (m *SinkManager) GetMirrorCheckpointTs() (model.Ts, bool) {
+	checkpointTs := m.mirrorCheckpointTs.Load()
+	return checkpointTs, checkpointTs != 0
+}
+
+// UpdateMirrorCheckpointTs updates the checkpoint of the mirror persisted by
+// the owner, the table sinks of the mirror that start after it may skip
+// some events.
+func (m *SinkManager) UpdateMirrorCheckpointTs(checkpointTs model.Ts) {
+	m.persistedMirrorCheckpointTs.Store(checkpointTs)
+}
+
+// IsMirrorIncomplete returns true if the mirror may have lost events of any
+// table, the changefeed can't be cut over to it then.
+func (m *SinkManager) IsMirrorIncomplete() bool {
+	return m.mirrorIncomplete.Load()
+}
+
+// checkMirrorStartTs marks the mirror as incomplete if the table sink of the
+// mirror starts after the persisted checkpoint of the mirror, the events in
+// between are never written to the mirror. The checkpoint is 0 before the
+// owner persists it, the mirror starts along with the changefeed then.
+func (m *SinkManager) checkMirrorStartTs(span tablepb.Span, startTs model.Ts) {
+	checkpointTs := m.persistedMirrorCheckpointTs.Load()
+	if checkpointTs == 0 || startTs <= checkpointTs {
+		return
+	}
+	if !m.mirrorIncomplete.Swap(true) {
+		log.Warn("The table sink of the mirror starts after the checkpoint of the mirror, "+
+			"the mirror may have lost events",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Uint64("startTs", startTs),
+			zap.Uint64("mirrorCheckpointTs", checkpointTs))
 	}
 }
 
@@ -990,6 +1378,8 @@ func (m *SinkManager) Close() {
 	if m.eventCache != nil {
 		m.eventCache.clear()
 	}
+	tableSinkBackpressured.DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	m.sinkBufferQuota.Close()
 
 	log.Info("Closed sink manager",
 		zap.String("namespace", m.changefeedID.Namespace),
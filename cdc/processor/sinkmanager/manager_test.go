@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func getChangefeedInfo() *model.ChangeFeedInfo {
@@ -152,7 +153,7 @@ func TestRemoveTable(t *testing.T) {
 	manager.schemaStorage.AdvanceResolvedTs(5)
 	// Check all the events are sent to sink and record the memory usage.
 	require.Eventually(t, func() bool {
-		return manager.sinkMemQuota.GetUsedBytes() == 904
+		return manager.sinkMemQuota.GetUsedBytes() == 936
 	}, 5*time.Second, 10*time.Millisecond)
 
 	// Call this function times to test the idempotence.
@@ -202,6 +203,98 @@ func TestGenerateTableSinkTaskWithBarrierTs(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestRotateCredentials(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, _, e := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	span := spanz.TableIDToComparableSpan(1)
+	manager.AddTable(span, 1, 100)
+	addTableAndAddEventsToSortEngine(t, e, span)
+	manager.UpdateBarrierTs(2, nil)
+	manager.UpdateReceivedSorterResolvedTs(span, 5)
+	manager.schemaStorage.AdvanceResolvedTs(5)
+	require.NoError(t, manager.StartTable(span, 0))
+
+	value, ok := manager.tableSinks.Load(span)
+	require.True(t, ok)
+	wrapper := value.(*tableSinkWrapper)
+	require.Eventually(t, func() bool {
+		return wrapper.getCheckpointTs().ResolvedMark() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	wrapper.tableSinkMu.RLock()
+	oldTableSink := wrapper.tableSink
+	wrapper.tableSinkMu.RUnlock()
+
+	// the table sink is recreated by the new sink factory, and the
+	// replication goes on from its checkpoint.
+	rotatedInfo := getChangefeedInfo()
+	rotatedInfo.SinkURI = "blackhole://?rotated=true"
+	manager.RotateCredentials(rotatedInfo)
+	manager.UpdateBarrierTs(4, nil)
+	require.Eventually(t, func() bool {
+		wrapper.tableSinkMu.RLock()
+		recreated := wrapper.tableSink != nil && wrapper.tableSink != oldTableSink
+		wrapper.tableSinkMu.RUnlock()
+		return recreated && wrapper.getCheckpointTs().ResolvedMark() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestUpdateDispatchRules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, _, e := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	span := spanz.TableIDToComparableSpan(1)
+	manager.AddTable(span, 1, 100)
+	addTableAndAddEventsToSortEngine(t, e, span)
+	manager.UpdateBarrierTs(2, nil)
+	manager.UpdateReceivedSorterResolvedTs(span, 5)
+	manager.schemaStorage.AdvanceResolvedTs(5)
+	require.NoError(t, manager.StartTable(span, 0))
+
+	value, ok := manager.tableSinks.Load(span)
+	require.True(t, ok)
+	wrapper := value.(*tableSinkWrapper)
+	require.Eventually(t, func() bool {
+		return wrapper.getCheckpointTs().ResolvedMark() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	wrapper.tableSinkMu.RLock()
+	oldTableSink := wrapper.tableSink
+	wrapper.tableSinkMu.RUnlock()
+
+	// the table sink is kept after the dispatch rules are updated.
+	updatedInfo := getChangefeedInfo()
+	updatedInfo.Config.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "{schema}"},
+	}
+	manager.UpdateDispatchRules(updatedInfo)
+	manager.UpdateBarrierTs(4, nil)
+	require.Eventually(t, func() bool {
+		return wrapper.getCheckpointTs().ResolvedMark() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	wrapper.tableSinkMu.RLock()
+	require.Equal(t, oldTableSink, wrapper.tableSink)
+	wrapper.tableSinkMu.RUnlock()
+	manager.sinkFactoryMu.Lock()
+	require.Equal(t, updatedInfo, manager.changefeedInfo)
+	manager.sinkFactoryMu.Unlock()
+}
+
 func TestGenerateTableSinkTaskWithResolvedTs(t *testing.T) {
 	t.Parallel()
 
@@ -233,6 +326,33 @@ func TestGenerateTableSinkTaskWithResolvedTs(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestGetSinkUpperBoundWithDelay(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	delay := 10 * time.Minute
+	changefeedInfo.Config.DelayDuration = &delay
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+
+	// The events committed in the last delay-duration are held back.
+	now := time.Now()
+	nowTs := oracle.GoTimeToTS(now)
+	upperBound := manager.getSinkUpperBound(nowTs)
+	require.Less(t, upperBound.CommitTs, nowTs)
+	require.LessOrEqual(t, upperBound.CommitTs, oracle.GoTimeToTS(now.Add(-delay).Add(time.Minute)))
+	require.Equal(t, nowTs, manager.getUpperBound(nowTs).CommitTs)
+
+	// The older events aren't affected.
+	oldTs := oracle.GoTimeToTS(now.Add(-time.Hour))
+	require.Equal(t, manager.getUpperBound(oldTs), manager.getSinkUpperBound(oldTs))
+}
+
 func TestGetTableStatsToReleaseMemQuota(t *testing.T) {
 	t.Parallel()
 
@@ -338,7 +458,7 @@ func TestSinkManagerRunWithErrors(t *testing.T) {
 
 	span := spanz.TableIDToComparableSpan(1)
 
-	source.AddTable(span, "test", 100)
+	source.AddTable(span, "test", 100, nil)
 	manager.AddTable(span, 100, math.MaxUint64)
 	manager.StartTable(span, 100)
 	source.Add(span, model.NewResolvedPolymorphicEvent(0, 101))
@@ -356,3 +476,39 @@ func TestSinkManagerRunWithErrors(t *testing.T) {
 		log.Panic("must get an error instead of a timeout")
 	}
 }
+
+func TestMirrorIncomplete(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	changefeedInfo.Config.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://"}
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+	manager.UpdateMirrorCheckpointTs(2)
+
+	initTableSink := func(span tablepb.Span, startTs model.Ts) {
+		manager.AddTable(span, startTs, 100)
+		value, ok := manager.tableSinks.Load(span)
+		require.True(t, ok)
+		wrapper := value.(*tableSinkWrapper)
+		require.Eventually(t, func() bool {
+			return wrapper.initTableSink()
+		}, 5*time.Second, 10*time.Millisecond)
+		_, ok = wrapper.getMirrorCheckpointTs()
+		require.True(t, ok)
+	}
+
+	// the table sink of the mirror starts at the checkpoint of the mirror.
+	initTableSink(spanz.TableIDToComparableSpan(1), 2)
+	require.False(t, manager.IsMirrorIncomplete())
+
+	// the events before the start of the table sink are never written to
+	// the mirror.
+	initTableSink(spanz.TableIDToComparableSpan(2), 3)
+	require.True(t, manager.IsMirrorIncomplete())
+}
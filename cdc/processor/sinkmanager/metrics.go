@@ -50,6 +50,65 @@ var (
 		// type includes hit and miss.
 		[]string{"namespace", "changefeed", "type"})
 
+	// tableCheckpointLag is the checkpoint lag of the tables, its percentiles
+	// can be compared with the lag SLO of the changefeed.
+	tableCheckpointLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "table_checkpoint_lag",
+			Help:      "Bucketed histogram of the checkpoint lag of the tables (s).",
+			Buckets:   lagBucket(),
+		}, []string{"namespace", "changefeed", "table"})
+
+	// tableLagSLO is the lag SLO of the changefeed, zero means it's not set.
+	tableLagSLO = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "table_lag_slo",
+			Help:      "the lag SLO of the tables of the changefeed (s)",
+		}, []string{"namespace", "changefeed"})
+
+	// tableLagSLOViolations counts the tables which lag behind the lag SLO,
+	// a table is counted once per check.
+	tableLagSLOViolations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "table_lag_slo_violations",
+			Help:      "The number of times the tables lag behind the lag SLO",
+		}, []string{"namespace", "changefeed"})
+
+	// mirrorCheckpointLag is the max checkpoint lag of the mirror of the tables,
+	// the changefeed can be cut over to the mirror once it's caught up.
+	mirrorCheckpointLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "mirror_checkpoint_lag",
+			Help:      "The max checkpoint lag of the mirror of the tables (s)",
+		}, []string{"namespace", "changefeed"})
+
+	// tableSinkBackpressured counts the times the sink tasks of the tables are
+	// skipped because the table sinks are backpressured.
+	tableSinkBackpressured = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "table_sink_backpressured",
+			Help:      "The number of times the sink tasks are skipped due to the backpressure of the table sinks",
+		}, []string{"namespace", "changefeed"})
+
+	// sinkErrorCount counts the errors from the sink by the class.
+	sinkErrorCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sinkmanager",
+			Name:      "sink_errors",
+			Help:      "The number of errors from the sink by the class",
+		}, []string{"namespace", "changefeed", "class"})
+
 	// outputEventCount is the metric that counts events output by the sorter.
 	outputEventCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "ticdc",
@@ -59,9 +118,22 @@ var (
 	}, []string{"namespace", "changefeed", "type"})
 )
 
+func lagBucket() []float64 {
+	buckets := prometheus.LinearBuckets(0.5, 0.5, 20)
+	buckets = append(buckets, prometheus.LinearBuckets(11, 1, 10)...)
+	buckets = append(buckets, prometheus.ExponentialBuckets(40, 2, 10)...)
+	return buckets
+}
+
 // InitMetrics registers all metrics in this file.
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(RedoEventCache)
 	registry.MustRegister(RedoEventCacheAccess)
 	registry.MustRegister(outputEventCount)
+	registry.MustRegister(tableCheckpointLag)
+	registry.MustRegister(tableLagSLO)
+	registry.MustRegister(tableLagSLOViolations)
+	registry.MustRegister(mirrorCheckpointLag)
+	registry.MustRegister(tableSinkBackpressured)
+	registry.MustRegister(sinkErrorCount)
 }
@@ -26,8 +26,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/oracle"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -120,6 +122,9 @@ func (w *sinkWorker) handleTasks(ctx context.Context, taskChan <-chan *sinkTask)
 func (w *sinkWorker) handleTask(ctx context.Context, task *sinkTask) (finalErr error) {
 	// We need to use a new batch ID for each task.
 	batchID.Add(1)
+	ctx, span := tracing.StartSpan(ctx, "sinkmanager.sinkTask", w.changefeedID,
+		attribute.Int64("tableID", task.span.TableID))
+	defer func() { tracing.EndSpan(span, finalErr) }()
 	advancer := newTableSinkAdvancer(task, w.splitTxn, w.sinkMemQuota, requestMemSize)
 	// The task is finished and some required memory isn't used.
 	defer advancer.cleanup()
@@ -151,6 +156,7 @@ func (w *sinkWorker) handleTask(ctx context.Context, task *sinkTask) (finalErr e
 		// Collect metrics.
 		w.metricRedoEventCacheMiss.Add(float64(allEventSize))
 		w.metricOutputEventCountKV.Add(float64(allEventCount))
+		span.SetAttributes(attribute.Int("events", allEventCount))
 
 		// If eventCache is nil, update sorter commit ts and range event count.
 		if w.eventCache == nil {
@@ -181,11 +187,18 @@ func (w *sinkWorker) handleTask(ctx context.Context, task *sinkTask) (finalErr e
 		if finalErr == nil {
 			task.callback(advancer.lastPos)
 		} else {
-			switch errors.Cause(finalErr).(type) {
+			switch sinkErr := errors.Cause(finalErr).(type) {
 			// If it's a warning, close the table sink and wait all pending
 			// events have been reported. Then we can continue the table
 			// at the checkpoint position.
 			case tablesink.SinkInternalError:
+				sinkErrorCount.WithLabelValues(w.changefeedID.Namespace, w.changefeedID.ID,
+					string(sinkErr.Class())).Inc()
+				// The fatal errors can't be recovered by restarting the table
+				// sink, so the changefeed is failed.
+				if !sinkErr.Retryable() {
+					break
+				}
 				task.tableSink.closeAndClearTableSink()
 				// After the table sink is cleared all pending events are sent out or dropped.
 				// So we can re-add the table into sinkMemQuota.
@@ -34,7 +34,7 @@ import (
 
 // testEventSize is the size of a test event.
 // It is used to calculate the memory quota.
-const testEventSize = 226
+const testEventSize = 234
 
 //nolint:unparam
 func genPolymorphicEventWithNilRow(startTs,
@@ -64,6 +64,9 @@ type tableSinkWrapper struct {
 	// receivedSorterResolvedTs is the resolved ts received from the sorter.
 	// We use this to advance the redo log.
 	receivedSorterResolvedTs atomic.Uint64
+	// paused indicates no more events are sent to the table sink until
+	// the table is resumed.
+	paused atomic.Bool
 
 	// replicateTs is the ts that the table sink has started to replicate.
 	replicateTs    model.Ts
@@ -204,10 +207,57 @@ func (t *tableSinkWrapper) getCheckpointTs() model.ResolvedTs {
 	return t.tableSinkCheckpointTs
 }
 
+// getMirrorCheckpointTs returns the checkpoint of the mirror of the table sink,
+// false is returned if the table sink doesn't write to a mirror.
+func (t *tableSinkWrapper) getMirrorCheckpointTs() (model.ResolvedTs, bool) {
+	t.tableSinkMu.RLock()
+	defer t.tableSinkMu.RUnlock()
+	mirror, ok := t.tableSink.(*tablesink.MirrorTableSink)
+	if !ok {
+		return model.ResolvedTs{}, false
+	}
+	return mirror.GetMirrorCheckpointTs(), true
+}
+
+// isMirrorFailed returns true if the table sink writes to a mirror and the
+// mirror has failed.
+func (t *tableSinkWrapper) isMirrorFailed() bool {
+	t.tableSinkMu.RLock()
+	defer t.tableSinkMu.RUnlock()
+	mirror, ok := t.tableSink.(*tablesink.MirrorTableSink)
+	return ok && mirror.IsMirrorFailed()
+}
+
+// getBackpressureScore returns the backpressure score of the table sink,
+// it's 0 if the table sink hasn't been attached.
+func (t *tableSinkWrapper) getBackpressureScore() float64 {
+	t.tableSinkMu.RLock()
+	defer t.tableSinkMu.RUnlock()
+	if t.tableSink == nil {
+		return 0
+	}
+	return t.tableSink.GetBackpressureScore()
+}
+
+// getTableSinkStats returns the statistic of the table sink, it's empty if
+// the table sink hasn't been attached.
+func (t *tableSinkWrapper) getTableSinkStats() tablesink.Stats {
+	t.tableSinkMu.RLock()
+	defer t.tableSinkMu.RUnlock()
+	if t.tableSink == nil {
+		return tablesink.Stats{}
+	}
+	return t.tableSink.GetStats()
+}
+
 func (t *tableSinkWrapper) getReceivedSorterResolvedTs() model.Ts {
 	return t.receivedSorterResolvedTs.Load()
 }
 
+func (t *tableSinkWrapper) isPaused() bool {
+	return t.paused.Load()
+}
+
 func (t *tableSinkWrapper) getState() tablepb.TableState {
 	return t.state.Load()
 }
@@ -326,6 +376,27 @@ func (t *tableSinkWrapper) doTableSinkClear() {
 	t.tableSink = nil
 }
 
+// detachAndCloseTableSink detaches the table sink before closing it, so the
+// events aren't written into the closing table sink, instead the sink task
+// fails and the table sink is restarted from the checkpoint.
+func (t *tableSinkWrapper) detachAndCloseTableSink() {
+	t.tableSinkMu.Lock()
+	tableSink := t.tableSink
+	t.tableSink = nil
+	t.tableSinkMu.Unlock()
+	if tableSink == nil {
+		return
+	}
+	tableSink.Close()
+
+	t.tableSinkMu.Lock()
+	defer t.tableSinkMu.Unlock()
+	checkpointTs := tableSink.GetCheckpointTs()
+	if t.tableSinkCheckpointTs.Less(checkpointTs) {
+		t.tableSinkCheckpointTs = checkpointTs
+	}
+}
+
 // When the attached sink fail, there can be some events that have already been
 // committed at downstream but we don't know. So we need to update `replicateTs`
 // of the table so that we can re-send those events later.
@@ -82,7 +82,7 @@ func createTableSinkWrapper(
 	sink := newMockSink()
 	innerTableSink := tablesink.New[*model.RowChangedEvent](
 		changefeedID, span, model.Ts(0),
-		sink, &dmlsink.RowChangeEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.RowChangeEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 	wrapper := newTableSinkWrapper(
 		changefeedID,
 		span,
@@ -200,7 +200,7 @@ func TestConvertRowChangedEventsWhenEnableOldValue(t *testing.T) {
 	result, size, err := convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(result))
-	require.Equal(t, uint64(224), size)
+	require.Equal(t, uint64(232), size)
 }
 
 func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
@@ -253,7 +253,7 @@ func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
 	result, size, err := convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(result))
-	require.Equal(t, uint64(224), size)
+	require.Equal(t, uint64(232), size)
 
 	// Update non-handle key.
 	columns = []*model.Column{
@@ -299,7 +299,7 @@ func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
 	result, size, err = convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(result))
-	require.Equal(t, uint64(224), size)
+	require.Equal(t, uint64(232), size)
 }
 
 func TestGetUpperBoundTs(t *testing.T) {
@@ -0,0 +1,106 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"math"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble/encoding"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+)
+
+// SpillStore stores the values spilled from the memory by a table sink in the
+// pebble instance of the sort engine. It has its own uniqueID, so the values
+// never conflict with the sorted events.
+type SpillStore struct {
+	db       *pebble.DB
+	uniqueID uint32
+	tableID  uint64
+
+	mu     sync.Mutex
+	seq    uint64
+	closed bool
+}
+
+// NewSpillStore creates a SpillStore of the given span, which reuses the
+// pebble instance storing the events of the span.
+func (s *EventSorter) NewSpillStore(span tablepb.Span) *SpillStore {
+	return &SpillStore{
+		db:       s.dbs[getDB(span, len(s.dbs))],
+		uniqueID: genUniqueID(),
+		tableID:  uint64(span.TableID),
+	}
+}
+
+// Put adds a value with the given commitTs. The values are ordered by
+// commitTs and then the order they're put.
+func (s *SpillStore) Put(commitTs model.Ts, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("spill store is cleaned")
+	}
+	s.seq++
+	key := encoding.EncodeTsKey(s.uniqueID, s.tableID, commitTs, s.seq)
+	return s.db.Set(key, value, &pebble.WriteOptions{Sync: false})
+}
+
+// Take removes and returns the values whose commitTs is less than or equal to
+// the upperBound.
+func (s *SpillStore) Take(upperBound model.Ts) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, errors.New("spill store is cleaned")
+	}
+
+	// Pebble's iterator range is left-included but right-excluded.
+	start := encoding.EncodeTsKey(s.uniqueID, s.tableID, 0)
+	end := encoding.EncodeTsKey(s.uniqueID, s.tableID, upperBound+1)
+	if upperBound == math.MaxUint64 {
+		end = encoding.EncodeTsKey(s.uniqueID, s.tableID+1, 0)
+	}
+	iter := s.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	var values [][]byte
+	for valid := iter.First(); valid; valid = iter.Next() {
+		values = append(values, append([]byte{}, iter.Value()...))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if err := s.db.DeleteRange(start, end, &pebble.WriteOptions{Sync: false}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return values, nil
+}
+
+// Clean removes all values of the store, it can't be used after cleaned.
+func (s *SpillStore) Clean() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	start := encoding.EncodeTsKey(s.uniqueID, s.tableID, 0)
+	end := encoding.EncodeTsKey(s.uniqueID, s.tableID+1, 0)
+	return errors.Trace(s.db.DeleteRange(start, end, &pebble.WriteOptions{Sync: false}))
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillStore(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, nil)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := New(cf, []*pebble.DB{db})
+	defer s.Close()
+
+	span := spanz.TableIDToComparableSpan(1)
+	store := s.NewSpillStore(span)
+	other := s.NewSpillStore(span)
+
+	require.Nil(t, store.Put(2, []byte("a")))
+	require.Nil(t, store.Put(2, []byte("b")))
+	require.Nil(t, store.Put(3, []byte("c")))
+	require.Nil(t, store.Put(5, []byte("d")))
+	require.Nil(t, other.Put(1, []byte("x")))
+
+	values, err := store.Take(1)
+	require.Nil(t, err)
+	require.Empty(t, values)
+
+	// The values are ordered by commitTs and the order they're put.
+	values, err = store.Take(3)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("a"), []byte("b"), []byte("c")}, values)
+	values, err = store.Take(3)
+	require.Nil(t, err)
+	require.Empty(t, values)
+
+	require.Nil(t, store.Clean())
+	require.Nil(t, store.Clean())
+	require.Error(t, store.Put(6, []byte("e")))
+	_, err = store.Take(6)
+	require.Error(t, err)
+
+	// The values of other stores aren't affected.
+	values, err = other.Take(10)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("x")}, values)
+}
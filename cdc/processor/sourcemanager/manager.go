@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble"
 	pullerwrapper "github.com/pingcap/tiflow/cdc/processor/sourcemanager/puller"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
@@ -39,6 +40,7 @@ type pullerWrapperCreator func(
 	tableName string,
 	startTs model.Ts,
 	bdrMode bool,
+	snapshot *config.SnapshotConfig,
 ) pullerwrapper.Wrapper
 
 type tablePullers struct {
@@ -119,15 +121,25 @@ func newSourceManager(
 }
 
 // AddTable adds a table to the source manager. Start puller and register table to the engine.
-func (m *SourceManager) AddTable(span tablepb.Span, tableName string, startTs model.Ts) {
+// The existing rows of the table are scanned at the startTs before pulling the changes
+// if the snapshot config is not nil.
+func (m *SourceManager) AddTable(
+	span tablepb.Span, tableName string, startTs model.Ts, snapshot *config.SnapshotConfig,
+) {
 	// Add table to the engine first, so that the engine can receive the events from the puller.
 	m.engine.AddTable(span, startTs)
 
 	if m.multiplexing {
+		if snapshot != nil {
+			log.Warn("the snapshot of the table is skipped in the multiplexing mode",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Stringer("span", &span))
+		}
 		return
 	}
 
-	p := m.tablePullers.pullerWrapperCreator(m.changefeedID, span, tableName, startTs, m.bdrMode)
+	p := m.tablePullers.pullerWrapperCreator(m.changefeedID, span, tableName, startTs, m.bdrMode, snapshot)
 	p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan)
 	m.tablePullers.Store(span, p)
 }
@@ -145,6 +157,32 @@ func (m *SourceManager) RemoveTable(span tablepb.Span) {
 	m.engine.RemoveTable(span)
 }
 
+// PauseTable stops pulling the changes of a table, the events which are
+// already received are kept in the engine.
+func (m *SourceManager) PauseTable(span tablepb.Span) {
+	if m.multiplexing {
+		return
+	}
+
+	if wrapper, ok := m.tablePullers.Load(span); ok {
+		wrapper.(pullerwrapper.Wrapper).Close()
+	}
+}
+
+// ResumeTable restarts pulling the changes of a paused table from the
+// resolved ts received by the engine. The changes received twice are
+// deduplicated by the engine since they're sorted by the same keys.
+func (m *SourceManager) ResumeTable(span tablepb.Span, tableName string) {
+	if m.multiplexing {
+		return
+	}
+
+	startTs := m.engine.GetStatsByTable(span).ReceivedMaxResolvedTs
+	p := m.tablePullers.pullerWrapperCreator(m.changefeedID, span, tableName, startTs, m.bdrMode, nil)
+	p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan)
+	m.tablePullers.Store(span, p)
+}
+
 // OnResolve just wrap the engine's OnResolve method.
 func (m *SourceManager) OnResolve(action func(tablepb.Span, model.Ts)) {
 	m.engine.OnResolve(action)
@@ -185,6 +223,20 @@ func (m *SourceManager) GetTableSorterStats(span tablepb.Span) engine.TableStats
 	return m.engine.GetStatsByTable(span)
 }
 
+// CanSpill returns true if the sort engine can store the events spilled from
+// the memory by the table sinks.
+func (m *SourceManager) CanSpill() bool {
+	_, ok := m.engine.(*epebble.EventSorter)
+	return ok
+}
+
+// NewSpillStore creates a store on the pebble instances of the sort engine for
+// the events spilled from the memory by the table sink of the given span.
+// It must be called only if CanSpill returns true.
+func (m *SourceManager) NewSpillStore(span tablepb.Span) *epebble.SpillStore {
+	return m.engine.(*epebble.EventSorter).NewSpillStore(span)
+}
+
 // Run implements util.Runnable.
 func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
 	if m.multiplexing {
@@ -15,8 +15,11 @@ package puller
 
 import (
 	"context"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -24,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/upstream"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -48,6 +52,10 @@ type WrapperImpl struct {
 	p          puller.Puller
 	startTs    model.Ts
 	bdrMode    bool
+	// snapshot is the config of the initial snapshot, the existing rows of
+	// the table are scanned at the startTs before pulling the changes if
+	// it's not nil.
+	snapshot *config.SnapshotConfig
 
 	// cancel is used to cancel the puller when remove or close the table.
 	cancel context.CancelFunc
@@ -62,6 +70,7 @@ func NewPullerWrapper(
 	tableName string,
 	startTs model.Ts,
 	bdrMode bool,
+	snapshot *config.SnapshotConfig,
 ) Wrapper {
 	return &WrapperImpl{
 		changefeed: changefeed,
@@ -69,6 +78,7 @@ func NewPullerWrapper(
 		tableName:  tableName,
 		startTs:    startTs,
 		bdrMode:    bdrMode,
+		snapshot:   snapshot,
 	}
 }
 
@@ -113,6 +123,14 @@ func (n *WrapperImpl) Start(
 	// Use errgroup to ensure all sub goroutines can exit without calling Close.
 	n.eg, ctx = errgroup.WithContext(ctx)
 	n.eg.Go(func() error {
+		// the puller starts after the snapshot is scanned, so the resolved
+		// ts of the table is kept at the startTs during the scan.
+		if n.snapshot != nil {
+			if err := n.scanSnapshot(ctx, up, eventSortEngine); err != nil {
+				errorHandler(err)
+				return err
+			}
+		}
 		err := n.p.Run(ctx)
 		errorHandler(err)
 		return err
@@ -133,6 +151,37 @@ func (n *WrapperImpl) Start(
 	})
 }
 
+func (n *WrapperImpl) scanSnapshot(
+	ctx context.Context, up *upstream.Upstream, eventSortEngine engine.SortEngine,
+) error {
+	log.Info("puller starts to scan the snapshot of the table",
+		zap.String("namespace", n.changefeed.Namespace),
+		zap.String("changefeed", n.changefeed.ID),
+		zap.Stringer("span", &n.span),
+		zap.Uint64("snapshotTs", n.startTs))
+	start := time.Now()
+	rows, err := scanSnapshot(ctx, up.KVStorage, n.span, n.startTs, n.snapshot,
+		func(events ...*model.PolymorphicEvent) {
+			eventSortEngine.Add(n.span, events...)
+		})
+	if err != nil {
+		log.Warn("puller fails to scan the snapshot of the table",
+			zap.String("namespace", n.changefeed.Namespace),
+			zap.String("changefeed", n.changefeed.ID),
+			zap.Stringer("span", &n.span),
+			zap.Int("rows", rows),
+			zap.Error(err))
+		return errors.Trace(err)
+	}
+	log.Info("puller finishes scanning the snapshot of the table",
+		zap.String("namespace", n.changefeed.Namespace),
+		zap.String("changefeed", n.changefeed.ID),
+		zap.Stringer("span", &n.span),
+		zap.Int("rows", rows),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
 // GetStats returns the puller stats.
 func (n *WrapperImpl) GetStats() puller.Stats {
 	return n.p.Stats()
@@ -0,0 +1,118 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"golang.org/x/time/rate"
+)
+
+// scanSnapshot scans the existing rows of the span at the ts, and outputs
+// them as the puts committed at the ts. The rows are scanned chunk by chunk
+// in the order of their keys, each chunk is read by a new iterator which
+// starts from the key following the last chunk. It returns the number of
+// the scanned rows.
+func scanSnapshot(
+	ctx context.Context,
+	storage tidbkv.Storage,
+	span tablepb.Span,
+	ts model.Ts,
+	cfg *config.SnapshotConfig,
+	output func(events ...*model.PolymorphicEvent),
+) (int, error) {
+	// the keys of the span are encoded in the comparable format.
+	_, startKey, err := codec.DecodeBytes(span.StartKey, nil)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	_, endKey, err := codec.DecodeBytes(span.EndKey, nil)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	chunkSize := cfg.GetChunkSize()
+	var limiter *rate.Limiter
+	if rateLimit := cfg.GetRateLimit(); rateLimit > 0 {
+		burst := rateLimit
+		if burst < chunkSize {
+			burst = chunkSize
+		}
+		limiter = rate.NewLimiter(rate.Limit(rateLimit), burst)
+	}
+
+	snap := storage.GetSnapshot(tidbkv.NewVersion(ts))
+	snap.SetOption(tidbkv.Priority, tidbkv.PriorityLow)
+	scanned := 0
+	for {
+		events, nextKey, err := scanChunk(snap, startKey, endKey, ts, chunkSize)
+		if err != nil {
+			return scanned, err
+		}
+		if limiter != nil && len(events) > 0 {
+			if err := limiter.WaitN(ctx, len(events)); err != nil {
+				return scanned, errors.Trace(err)
+			}
+		}
+		output(events...)
+		scanned += len(events)
+		if nextKey == nil {
+			return scanned, nil
+		}
+		startKey = nextKey
+
+		select {
+		case <-ctx.Done():
+			return scanned, errors.Trace(ctx.Err())
+		default:
+		}
+	}
+}
+
+// scanChunk reads at most chunkSize rows from the startKey, the key of the
+// next chunk is returned if there are more rows, otherwise it's nil.
+func scanChunk(
+	snap tidbkv.Snapshot, startKey, endKey []byte, ts model.Ts, chunkSize int,
+) ([]*model.PolymorphicEvent, []byte, error) {
+	iter, err := snap.Iter(startKey, endKey)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer iter.Close()
+
+	events := make([]*model.PolymorphicEvent, 0, chunkSize)
+	for iter.Valid() {
+		if len(events) == chunkSize {
+			return events, iter.Key().Clone(), nil
+		}
+		events = append(events, model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType:   model.OpTypePut,
+			Key:      iter.Key().Clone(),
+			Value:    append([]byte(nil), iter.Value()...),
+			StartTs:  ts,
+			CRTs:     ts,
+			Snapshot: true,
+		}))
+		if err := iter.Next(); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return events, nil, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"testing"
+
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestScanSnapshot(t *testing.T) {
+	t.Parallel()
+
+	store, err := mockstore.NewMockStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	ctx := context.Background()
+	tableID := int64(100)
+	writeRows := func(tableID int64, handles ...int64) {
+		txn, err := store.Begin()
+		require.NoError(t, err)
+		for _, handle := range handles {
+			key := tablecodec.EncodeRowKeyWithHandle(tableID, tidbkv.IntHandle(handle))
+			require.NoError(t, txn.Set(key, []byte{byte(handle)}))
+		}
+		require.NoError(t, txn.Commit(ctx))
+	}
+	writeRows(tableID, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	// the rows of the other tables aren't scanned.
+	writeRows(tableID+1, 0)
+	ver, err := store.CurrentVersion(oracle.GlobalTxnScope)
+	require.NoError(t, err)
+	// the rows committed after the snapshot ts aren't scanned.
+	writeRows(tableID, 10)
+
+	var events []*model.PolymorphicEvent
+	chunks := 0
+	cfg := &config.SnapshotConfig{
+		Enable:    util.AddressOf(true),
+		ChunkSize: util.AddressOf(3),
+		RateLimit: util.AddressOf(1000),
+	}
+	rows, err := scanSnapshot(ctx, store, spanz.TableIDToComparableSpan(tableID), ver.Ver, cfg,
+		func(chunk ...*model.PolymorphicEvent) {
+			chunks++
+			events = append(events, chunk...)
+		})
+	require.NoError(t, err)
+	require.Equal(t, 10, rows)
+	require.Equal(t, 4, chunks)
+	require.Len(t, events, 10)
+	snap := store.GetSnapshot(ver)
+	for i, event := range events {
+		key := tablecodec.EncodeRowKeyWithHandle(tableID, tidbkv.IntHandle(int64(i)))
+		require.Equal(t, []byte(key), event.RawKV.Key)
+		value, err := snap.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, value, event.RawKV.Value)
+		require.Equal(t, model.OpTypePut, event.RawKV.OpType)
+		require.Equal(t, ver.Ver, event.CRTs)
+		require.Equal(t, ver.Ver, event.StartTs)
+		require.True(t, event.RawKV.Snapshot)
+	}
+}
@@ -67,6 +67,7 @@ type readerConfig struct {
 	uri                url.URL
 	useExternalStorage bool
 	workerNums         int
+	encryptionKey      string
 }
 
 type reader struct {
@@ -130,6 +131,10 @@ func downLoadAndSortFiles(ctx context.Context, cfg *readerConfig) ([]io.ReadClos
 	if err != nil {
 		return nil, err
 	}
+	fileCodec, err := redo.NewFileCodec(redo.CompressionNone, cfg.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
 
 	limit := make(chan struct{}, cfg.workerNums)
 	eg, eCtx := errgroup.WithContext(ctx)
@@ -148,7 +153,7 @@ func downLoadAndSortFiles(ctx context.Context, cfg *readerConfig) ([]io.ReadClos
 		sortedFileNames = append(sortedFileNames, getSortedFileName(fileName))
 		eg.Go(func() error {
 			defer func() { <-limit }()
-			return sortAndWriteFile(ctx, extStorage, fileName, cfg)
+			return sortAndWriteFile(ctx, extStorage, fileCodec, fileName, cfg)
 		})
 	}
 	if err := eg.Wait(); err != nil {
@@ -228,7 +233,7 @@ func readAllFromBuffer(buf []byte) (logHeap, error) {
 // to local storage.
 func sortAndWriteFile(
 	egCtx context.Context,
-	extStorage storage.ExternalStorage,
+	extStorage storage.ExternalStorage, fileCodec *redo.FileCodec,
 	fileName string, cfg *readerConfig,
 ) error {
 	sortedName := getSortedFileName(fileName)
@@ -247,6 +252,9 @@ func sortAndWriteFile(
 	if err != nil {
 		return cerror.WrapError(cerror.ErrExternalStorageAPI, err)
 	}
+	if fileContent, err = fileCodec.Decode(fileContent); err != nil {
+		return err
+	}
 	if len(fileContent) == 0 {
 		log.Warn("download file is empty", zap.String("file", fileName))
 		return nil
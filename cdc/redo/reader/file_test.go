@@ -18,6 +18,9 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/log"
@@ -77,3 +80,53 @@ func TestFileReaderRead(t *testing.T) {
 		require.NoError(t, r.Close())
 	}
 }
+
+func TestFileReaderReadEncoded(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+	key := strings.Repeat("ab", 32)
+	cfg := &readerConfig{
+		dir:                t.TempDir(),
+		startTs:            10,
+		endTs:              12,
+		fileType:           redo.RedoRowLogFileType,
+		uri:                *uri,
+		useExternalStorage: true,
+		encryptionKey:      key,
+	}
+	genLogFile(ctx, t, dir, redo.RedoRowLogFileType, cfg.startTs, cfg.endTs+2)
+
+	// Encode the log file like the writers do before uploading it.
+	fileCodec, err := redo.NewFileCodec(redo.CompressionZstd, key)
+	require.NoError(t, err)
+	files, err := filepath.Glob(filepath.Join(dir, "*"+redo.LogEXT))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	data, err = fileCodec.Encode(data)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(files[0], data, redo.DefaultFileMode))
+
+	readers, err := newReaders(ctx, cfg)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(readers))
+	for _, commitTs := range []uint64{11, 12} {
+		log, err := readers[0].Read()
+		require.NoError(t, err)
+		require.EqualValues(t, commitTs, log.RedoRow.Row.CommitTs)
+	}
+	_, err = readers[0].Read()
+	require.ErrorIs(t, err, io.EOF)
+	require.NoError(t, readers[0].Close())
+
+	// The encrypted log files can't be read without the key.
+	cfg.dir = t.TempDir()
+	cfg.encryptionKey = ""
+	_, err = newReaders(ctx, cfg)
+	require.ErrorContains(t, err, "encryption key isn't set")
+}
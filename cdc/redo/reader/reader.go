@@ -81,6 +81,11 @@ type LogReaderConfig struct {
 	// will load the file to memory first then write the sorted file to disk
 	// the memory used is WorkerNums * defaultMaxLogSize (64 * megabyte) total
 	WorkerNums int
+
+	// EncryptionKey is the hex encoded AES key or a secret reference to it,
+	// which is required if the redo logs are encrypted. The compression of the
+	// redo logs is detected automatically.
+	EncryptionKey string
 }
 
 // LogReader implement RedoLogReader interface
@@ -151,6 +156,7 @@ func (l *LogReader) runRowReader(egCtx context.Context) error {
 		uri:                l.cfg.URI,
 		useExternalStorage: l.cfg.UseExternalStorage,
 		workerNums:         l.cfg.WorkerNums,
+		encryptionKey:      l.cfg.EncryptionKey,
 	}
 	return l.runReader(egCtx, rowCfg)
 }
@@ -165,6 +171,7 @@ func (l *LogReader) runDDLReader(egCtx context.Context) error {
 		uri:                l.cfg.URI,
 		useExternalStorage: l.cfg.UseExternalStorage,
 		workerNums:         l.cfg.WorkerNums,
+		encryptionKey:      l.cfg.EncryptionKey,
 	}
 	return l.runReader(egCtx, ddlCfg)
 }
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/fsutil"
 	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/uber-go/atomic"
@@ -65,6 +66,7 @@ type Writer struct {
 	bw              *pioutil.PageWriter
 	uint64buf       []byte
 	storage         storage.ExternalStorage
+	codec           *redo.FileCodec
 	sync.RWMutex
 	uuidGenerator uuid.Generator
 	allocator     *fsutil.FileAllocator
@@ -83,13 +85,20 @@ func NewFileWriter(
 		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
 	}
 
-	var extStorage storage.ExternalStorage
+	var (
+		extStorage storage.ExternalStorage
+		codec      *redo.FileCodec
+	)
 	if cfg.UseExternalStorage {
 		var err error
 		extStorage, err = redo.InitExternalStorage(ctx, cfg.URI)
 		if err != nil {
 			return nil, err
 		}
+		codec, err = redo.NewFileCodec(cfg.Compression, cfg.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	op := &writer.LogWriterOptions{}
@@ -102,6 +111,7 @@ func NewFileWriter(
 		op:        op,
 		uint64buf: make([]byte, 8),
 		storage:   extStorage,
+		codec:     codec,
 
 		metricFsyncDuration: common.RedoFsyncDurationHistogram.
 			WithLabelValues(cfg.ChangeFeedID.Namespace, cfg.ChangeFeedID.ID),
@@ -436,9 +446,13 @@ func (w *Writer) writeToS3(ctx context.Context, name string) error {
 	if err != nil {
 		return errors.WrapError(errors.ErrRedoFileOp, err)
 	}
+	if fileData, err = w.codec.Encode(fileData); err != nil {
+		return err
+	}
 
 	// Key in s3: aws.String(rs.options.Prefix + name), prefix should be changefeed name
-	err = w.storage.WriteFile(ctx, filepath.Base(name), fileData)
+	err = util.WriteFileConcurrently(ctx, w.storage,
+		filepath.Base(name), fileData, w.cfg.FlushConcurrency)
 	if err != nil {
 		return errors.WrapError(errors.ErrExternalStorageAPI, err)
 	}
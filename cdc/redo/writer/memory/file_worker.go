@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -87,6 +88,7 @@ type fileWorkerGroup struct {
 	workerNum int
 
 	extStorage    storage.ExternalStorage
+	codec         *redo.FileCodec
 	uuidGenerator uuid.Generator
 
 	pool    sync.Pool
@@ -99,7 +101,7 @@ type fileWorkerGroup struct {
 
 func newFileWorkerGroup(
 	cfg *writer.LogWriterConfig, workerNum int,
-	extStorage storage.ExternalStorage,
+	extStorage storage.ExternalStorage, codec *redo.FileCodec,
 	opts ...writer.Option,
 ) *fileWorkerGroup {
 	if workerNum <= 0 {
@@ -116,6 +118,7 @@ func newFileWorkerGroup(
 		op:            op,
 		workerNum:     workerNum,
 		extStorage:    extStorage,
+		codec:         codec,
 		uuidGenerator: uuid.NewGenerator(),
 		pool: sync.Pool{
 			New: func() interface{} {
@@ -169,8 +172,13 @@ func (f *fileWorkerGroup) bgFlushFileCache(egCtx context.Context) error {
 		case <-egCtx.Done():
 			return errors.Trace(egCtx.Err())
 		case file := <-f.flushCh:
+			data, err := f.codec.Encode(file.data)
+			if err != nil {
+				return errors.Trace(err)
+			}
 			start := time.Now()
-			err := f.extStorage.WriteFile(egCtx, file.filename, file.data)
+			err = util.WriteFileConcurrently(egCtx, f.extStorage,
+				file.filename, data, f.cfg.FlushConcurrency)
 			f.metricFlushAllDuration.Observe(time.Since(start).Seconds())
 			if err != nil {
 				return errors.Trace(err)
@@ -264,7 +272,8 @@ func (f *fileWorkerGroup) flushAll(egCtx context.Context) error {
 	case f.flushCh <- file:
 	}
 
-	// wait all files flushed
+	// Wait all files flushed. The files are flushed by several workers out of
+	// order, but the meta is only advanced after all of them are flushed.
 	for _, file := range f.files {
 		err := file.waitFlushed(egCtx)
 		if err != nil {
@@ -30,7 +30,7 @@ var (
 	defaultEncodingOutputChanSize = 2048
 	// Maximum allocated memory is flushWorkerNum*maxLogSize, which is
 	// `8*64MB = 512MB` by default.
-	defaultFlushWorkerNum = 8
+	defaultFlushWorkerNum = redo.DefaultFlushWorkerNum
 )
 
 var _ writer.RedoLogWriter = (*memoryLogWriter)(nil)
@@ -62,6 +62,10 @@ func NewLogWriter(
 	if err != nil {
 		return nil, err
 	}
+	codec, err := redo.NewFileCodec(cfg.Compression, cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
 
 	eg, ctx := errgroup.WithContext(ctx)
 	lwCtx, lwCancel := context.WithCancel(ctx)
@@ -75,7 +79,7 @@ func NewLogWriter(
 	eg.Go(func() error {
 		return lw.encodeWorkers.Run(lwCtx)
 	})
-	lw.fileWorkers = newFileWorkerGroup(cfg, defaultFlushWorkerNum, extStorage, opts...)
+	lw.fileWorkers = newFileWorkerGroup(cfg, cfg.FlushWorkerNum, extStorage, codec, opts...)
 	eg.Go(func() error {
 		return lw.fileWorkers.Run(lwCtx, lw.encodeWorkers.outputCh)
 	})
@@ -16,12 +16,14 @@ package memory
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -96,3 +98,98 @@ func testWriteEvents(t *testing.T, events []writer.RedoEvent) {
 	err = lw.FlushLog(ctx)
 	require.ErrorContains(t, err, "redo log writer stopped")
 }
+
+func TestFlushLogWithConcurrentWorkers(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	extStorage, uri, err := util.GetTestExtStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	lwcfg := &writer.LogWriterConfig{
+		ConsistentConfig: config.ConsistentConfig{
+			FlushWorkerNum:   2,
+			FlushConcurrency: 4,
+		},
+		LogType:            redo.RedoRowLogFileType,
+		CaptureID:          "test-capture",
+		ChangeFeedID:       model.DefaultChangeFeedID("test-changefeed"),
+		URI:                *uri,
+		UseExternalStorage: true,
+		MaxLogSizeInBytes:  1024,
+	}
+	lw, err := NewLogWriter(ctx, lwcfg)
+	require.NoError(t, err)
+	require.Equal(t, 2, lw.fileWorkers.workerNum)
+
+	events := make([]writer.RedoEvent, 0, 100)
+	for i := 0; i < 100; i++ {
+		events = append(events, &model.RowChangedEvent{
+			Table:    &model.TableName{Schema: "test", Table: "t", TableID: 11},
+			CommitTs: uint64(i + 1),
+		})
+	}
+	require.NoError(t, lw.WriteEvents(ctx, events...))
+
+	// All the rotated files must be written once FlushLog returns, so the
+	// meta can be advanced safely.
+	require.NoError(t, lw.FlushLog(ctx))
+	var maxCommitTs uint64
+	fileCount := 0
+	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		fileCount++
+		commitTs, _, err := redo.ParseLogFileName(path)
+		require.NoError(t, err)
+		if commitTs > maxCommitTs {
+			maxCommitTs = commitTs
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Greater(t, fileCount, 1)
+	require.Equal(t, uint64(100), maxCommitTs)
+
+	require.ErrorIs(t, lw.Close(), context.Canceled)
+}
+
+func TestWriteEncodedLogs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	extStorage, uri, err := util.GetTestExtStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	key := strings.Repeat("ab", 32)
+	lwcfg := &writer.LogWriterConfig{
+		ConsistentConfig: config.ConsistentConfig{
+			Compression:   redo.CompressionZstd,
+			EncryptionKey: key,
+		},
+		LogType:            redo.RedoDDLLogFileType,
+		CaptureID:          "test-capture",
+		ChangeFeedID:       model.DefaultChangeFeedID("test-changefeed"),
+		URI:                *uri,
+		UseExternalStorage: true,
+		MaxLogSizeInBytes:  10 * redo.Megabyte,
+	}
+	filename := t.Name()
+	lw, err := NewLogWriter(ctx, lwcfg, writer.WithLogFileName(func() string {
+		return filename
+	}))
+	require.NoError(t, err)
+	require.NoError(t, lw.WriteEvents(ctx, &model.DDLEvent{CommitTs: 1, Query: "create table t(id int)"}))
+	require.NoError(t, lw.FlushLog(ctx))
+
+	data, err := extStorage.ReadFile(ctx, filename)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "create table")
+	fileCodec, err := redo.NewFileCodec(redo.CompressionNone, key)
+	require.NoError(t, err)
+	data, err = fileCodec.Decode(data)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "create table")
+
+	require.ErrorIs(t, lw.Close(), context.Canceled)
+}
@@ -26,4 +26,7 @@ type InfoProvider interface {
 
 	// GetTaskStatuses returns the task statuses.
 	GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus, error)
+
+	// GetTableProgresses returns the replication progresses of all table spans.
+	GetTableProgresses() ([]*model.TableProgress, error)
 }
@@ -15,7 +15,9 @@ package v3
 
 import (
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 )
 
 var _ internal.InfoProvider = (*coordinator)(nil)
@@ -48,3 +50,22 @@ func (c *coordinator) GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus,
 	}
 	return tasks, nil
 }
+
+// GetTableProgresses returns the replication progresses of all table spans.
+func (c *coordinator) GetTableProgresses() ([]*model.TableProgress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	progresses := make([]*model.TableProgress, 0, c.replicationM.ReplicationSets().Len())
+	c.replicationM.ReplicationSets().Ascend(
+		func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+			progresses = append(progresses, &model.TableProgress{
+				Span:       span,
+				CaptureID:  rep.Primary,
+				Checkpoint: rep.Checkpoint,
+				Stats:      rep.Stats,
+			})
+			return true
+		})
+	return progresses, nil
+}
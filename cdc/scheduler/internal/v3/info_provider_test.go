@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/keyspan"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
@@ -63,6 +64,42 @@ func TestInfoProvider(t *testing.T) {
 	}, tasks)
 }
 
+func TestInfoProviderTableProgresses(t *testing.T) {
+	t.Parallel()
+
+	coord := newCoordinatorForTest("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+		HeartbeatTick:      math.MaxInt,
+		MaxTaskConcurrency: 1,
+		ChangefeedSettings: config.GetDefaultReplicaConfig().Scheduler,
+	}, redo.NewDisabledMetaManager())
+	stats := tablepb.Stats{CurrentTs: 3, PendingEventCount: 10, SinkFlushLatencyMs: 20}
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:       tablepb.Span{TableID: 2},
+		Primary:    "b",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 2},
+	})
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:       tablepb.Span{TableID: 1},
+		Primary:    "a",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 2},
+		Stats:      stats,
+	})
+
+	var ip internal.InfoProvider = coord
+	progresses, err := ip.GetTableProgresses()
+	require.Nil(t, err)
+	require.Equal(t, []*model.TableProgress{{
+		Span:       tablepb.Span{TableID: 1},
+		CaptureID:  "a",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 2},
+		Stats:      stats,
+	}, {
+		Span:       tablepb.Span{TableID: 2},
+		CaptureID:  "b",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 2},
+	}}, progresses)
+}
+
 func TestInfoProviderIsInitialized(t *testing.T) {
 	t.Parallel()
 
@@ -950,7 +950,11 @@ func (r *ReplicationSet) updateCheckpointAndStats(
 		return errors.ErrInvalidCheckpointTs.GenWithStackByArgs(r.Checkpoint.CheckpointTs,
 			r.Checkpoint.ResolvedTs)
 	}
-	r.Stats = stats
+	// the stats are only collected periodically, keep the last collected
+	// ones if they're absent.
+	if stats.CurrentTs != 0 {
+		r.Stats = stats
+	}
 	return nil
 }
 
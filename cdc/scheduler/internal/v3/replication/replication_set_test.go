@@ -1363,6 +1363,22 @@ func TestReplicationSetRemoveRestart(t *testing.T) {
 	require.True(t, r.hasRemoved())
 }
 
+func TestReplicationSetKeepCollectedStats(t *testing.T) {
+	t.Parallel()
+
+	r := &ReplicationSet{Span: spanz.TableIDToComparableSpan(1)}
+	stats := tablepb.Stats{CurrentTs: 3, PendingEventCount: 10}
+	require.Nil(t, r.updateCheckpointAndStats(
+		tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 2}, stats))
+	require.Equal(t, stats, r.Stats)
+
+	// The stats aren't collected in every heartbeat.
+	require.Nil(t, r.updateCheckpointAndStats(
+		tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 2}, tablepb.Stats{}))
+	require.Equal(t, stats, r.Stats)
+	require.Equal(t, tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 2}, r.Checkpoint)
+}
+
 func TestReplicationSetHeap_Len(t *testing.T) {
 	t.Parallel()
 
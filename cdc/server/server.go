@@ -38,7 +38,9 @@ import (
 	clogutil "github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/security/secret"
 	"github.com/pingcap/tiflow/pkg/tcpserver"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/pingcap/tiflow/pkg/util"
 	p2pProto "github.com/pingcap/tiflow/proto/p2p"
 	pd "github.com/tikv/pd/client"
@@ -339,6 +341,19 @@ func (s *server) run(ctx context.Context) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	shutdownTracing, err := tracing.Init(ctx, config.GetGlobalServerConfig().Tracing)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		// flush the pending spans before exiting.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Warn("shutdown tracing failed", zap.Error(err))
+		}
+	}()
+
 	eg, egCtx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error {
@@ -353,6 +368,12 @@ func (s *server) run(ctx context.Context) (err error) {
 		return kv.RunWorkerPool(egCtx)
 	})
 
+	// refresh the secrets referenced by the sinks, so the rotated ones are
+	// used when the sinks are recreated.
+	eg.Go(func() error {
+		return secret.DefaultResolver().Run(egCtx)
+	})
+
 	eg.Go(func() error {
 		return s.tcpServer.Run(egCtx)
 	})
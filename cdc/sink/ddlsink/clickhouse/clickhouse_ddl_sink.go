@@ -0,0 +1,70 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+	"go.uber.org/zap"
+)
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is the ClickHouse DDL sink. The DDLs of TiDB can't be executed in
+// ClickHouse, so the target tables must be created with the version and sign
+// columns in advance, and the DDLs are skipped.
+type DDLSink struct {
+	changefeedID model.ChangeFeedID
+}
+
+// NewDDLSink creates a ClickHouse DDL sink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*DDLSink, error) {
+	cfg := pclickhouse.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+	return &DDLSink{changefeedID: changefeedID}, nil
+}
+
+// WriteDDLEvent skips the DDL.
+func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	log.Warn("ClickHouse sink skips the DDL, the table must be changed manually",
+		zap.String("namespace", d.changefeedID.Namespace),
+		zap.String("changefeed", d.changefeedID.ID),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("query", ddl.Query))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	return nil
+}
+
+// Close does nothing.
+func (d *DDLSink) Close() {}
@@ -52,7 +52,7 @@ func NewDDLSink(ctx context.Context,
 	sinkURI *url.URL,
 	replicaConfig *config.ReplicaConfig,
 ) (*DDLSink, error) {
-	storage, err := util.GetExternalStorageFromURI(ctx, sinkURI.String())
+	storage, err := cloudstorage.GetExternalStorage(ctx, changefeedID, sinkURI)
 	if err != nil {
 		return nil, err
 	}
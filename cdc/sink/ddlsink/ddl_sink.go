@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 )
 
 // Sink is the interface for sink of DDL events.
@@ -31,3 +32,22 @@ type Sink interface {
 	// Close closes the sink.
 	Close()
 }
+
+// DispatchRulesUpdater is implemented by the DDL sinks which route the events
+// by the dispatch rules, so the rules can be updated while the changefeed is
+// running.
+type DispatchRulesUpdater interface {
+	// UpdateDispatchRules rebuilds the routers of the events by the dispatch
+	// rules in the config, the connections to the downstream are kept.
+	// Note: It must not be called concurrently with the writes.
+	UpdateDispatchRules(cfg *config.ReplicaConfig) error
+}
+
+// AsyncDDLSink is implemented by the DDL sinks which may execute the DDL
+// events in the background, so WriteDDLEvent returns before they finish.
+type AsyncDDLSink interface {
+	// IsDDLRunning returns true if any DDL event is still executed in the
+	// background. The error of a failed one is returned once, the DDL event
+	// must be written again then.
+	IsDDLRunning() (bool, error)
+}
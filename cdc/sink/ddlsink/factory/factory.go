@@ -15,34 +15,113 @@ package factory
 
 import (
 	"context"
-	"net/url"
 	"strings"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/blackhole"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/clickhouse"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/elasticsearch"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/fanout"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/redis"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink/webhook"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
+	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	kafkav2 "github.com/pingcap/tiflow/pkg/sink/kafka/v2"
 	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
-	"github.com/pingcap/tiflow/pkg/util"
 )
 
-// New creates a new ddlsink.Sink by scheme.
+// New creates a new ddlsink.Sink by scheme, the DDLs are written to the sink
+// targets of the tables and the mirror as well if they're configured.
 func New(
 	ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	sinkURIStr string,
 	cfg *config.ReplicaConfig,
 ) (ddlsink.Sink, error) {
-	sinkURI, err := url.Parse(sinkURIStr)
+	s, err := newFanoutSink(ctx, changefeedID, sinkURIStr, cfg)
+	if err != nil || cfg.Sink.Mirror == nil {
+		return s, err
+	}
+	mirror, err := newSink(ctx, changefeedID.MirrorSinkID(), cfg.Sink.Mirror.SinkURI, cfg.SinkMirrorConfig())
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return fanout.NewMirrorDDLSink(s, mirror), nil
+}
+
+func newFanoutSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+) (ddlsink.Sink, error) {
+	if len(cfg.Sink.Targets) == 0 {
+		return newSink(ctx, changefeedID, sinkURIStr, cfg)
+	}
+
+	sinks := make([]ddlsink.Sink, 0, len(cfg.Sink.Targets)+1)
+	closeSinks := func() {
+		for _, s := range sinks {
+			s.Close()
+		}
+	}
+	s, err := newSink(ctx, changefeedID, sinkURIStr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	sinks = append(sinks, s)
+	for i, target := range cfg.Sink.Targets {
+		s, err := newSink(ctx, changefeedID.TargetSinkID(i), target.SinkURI, cfg.SinkTargetConfig(target))
+		if err != nil {
+			closeSinks()
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	fanoutSink, err := fanout.NewDDLSink(cfg, sinks)
+	if err != nil {
+		closeSinks()
+		return nil, err
+	}
+	return fanoutSink, nil
+}
+
+// newSink creates a ddlsink.Sink by scheme, the DDLs are routed by the table
+// routes before they're written to it if there are table routes.
+func newSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+) (ddlsink.Sink, error) {
+	router, err := util.NewTableRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSchemeSink(ctx, changefeedID, sinkURIStr, cfg)
+	if err != nil || router == nil {
+		return s, err
+	}
+	return fanout.NewRoutedDDLSink(changefeedID, s, router), nil
+}
+
+func newSchemeSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+) (ddlsink.Sink, error) {
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
@@ -50,7 +129,7 @@ func New(
 	switch scheme {
 	case sink.KafkaScheme, sink.KafkaSSLScheme:
 		factoryCreator := kafka.NewSaramaFactory
-		if util.GetOrZero(cfg.Sink.EnableKafkaSinkV2) {
+		if cfg.Sink.KafkaSinkV2Enabled() {
 			factoryCreator = kafkav2.NewFactory
 		}
 		return mq.NewKafkaDDLSink(ctx, changefeedID, sinkURI, cfg,
@@ -59,8 +138,17 @@ func New(
 		return blackhole.NewDDLSink(), nil
 	case sink.MySQLSSLScheme, sink.MySQLScheme, sink.TiDBScheme, sink.TiDBSSLScheme:
 		return mysql.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
-	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme:
+	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme,
+		sink.WebHDFSScheme, sink.WebHDFSSSLScheme, sink.HDFSScheme, sink.OSSScheme, sink.COSScheme:
 		return cloudstorage.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.ClickHouseScheme, sink.ClickHouseSSLScheme:
+		return clickhouse.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.ElasticsearchScheme, sink.ElasticsearchSSLScheme:
+		return elasticsearch.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.RedisScheme, sink.RedisSSLScheme:
+		return redis.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
+	case sink.HTTPScheme, sink.HTTPSScheme:
+		return webhook.NewDDLSink(ctx, changefeedID, sinkURI, cfg)
 	case sink.PulsarScheme, sink.PulsarSSLScheme:
 		return mq.NewPulsarDDLSink(ctx, changefeedID, sinkURI, cfg, manager.NewPulsarTopicManager,
 			pulsarConfig.NewCreatorFactory, ddlproducer.NewPulsarProducer)
@@ -69,3 +157,91 @@ func New(
 			cerror.ErrSinkURIInvalid.GenWithStack("the sink scheme (%s) is not supported", scheme)
 	}
 }
+
+// CleanupTopics deletes the topics auto-created by the removed changefeed,
+// it only takes effect for the kafka sink, the kafka sink targets and the
+// kafka mirror.
+func CleanupTopics(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+	tables []model.TableName,
+	otherChangefeeds []*model.ChangeFeedInfo,
+) error {
+	if len(cfg.Sink.Targets) == 0 {
+		err := cleanupTopics(ctx, changefeedID, sinkURIStr, cfg, tables, otherChangefeeds)
+		if err != nil || cfg.Sink.Mirror == nil {
+			return err
+		}
+		return cleanupTopics(ctx, changefeedID.MirrorSinkID(), cfg.Sink.Mirror.SinkURI,
+			cfg.SinkMirrorConfig(), tables, otherChangefeeds)
+	}
+
+	matcher, err := util.NewTargetMatcher(cfg)
+	if err != nil {
+		return err
+	}
+	tablesOfSinks := make([][]model.TableName, len(cfg.Sink.Targets)+1)
+	for _, table := range tables {
+		i := matcher.Match(table.Schema, table.Table) + 1
+		tablesOfSinks[i] = append(tablesOfSinks[i], table)
+	}
+	err = cleanupTopics(ctx, changefeedID, sinkURIStr, cfg, tablesOfSinks[0], otherChangefeeds)
+	if err != nil {
+		return err
+	}
+	if cfg.Sink.Mirror != nil {
+		err := cleanupTopics(ctx, changefeedID.MirrorSinkID(), cfg.Sink.Mirror.SinkURI,
+			cfg.SinkMirrorConfig(), tablesOfSinks[0], otherChangefeeds)
+		if err != nil {
+			return err
+		}
+	}
+	for i, target := range cfg.Sink.Targets {
+		err := cleanupTopics(ctx, changefeedID.TargetSinkID(i), target.SinkURI,
+			cfg.SinkTargetConfig(target), tablesOfSinks[i+1], otherChangefeeds)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cleanupTopics(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+	tables []model.TableName,
+	otherChangefeeds []*model.ChangeFeedInfo,
+) error {
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	switch scheme {
+	case sink.KafkaScheme, sink.KafkaSSLScheme:
+		// the topics are named by the tables in the downstream.
+		router, err := util.NewTableRouter(cfg)
+		if err != nil {
+			return err
+		}
+		if router != nil {
+			routed := make([]model.TableName, 0, len(tables))
+			for _, table := range tables {
+				routed = append(routed, router.RouteTable(table))
+			}
+			tables = routed
+		}
+		factoryCreator := kafka.NewSaramaFactory
+		if cfg.Sink.KafkaSinkV2Enabled() {
+			factoryCreator = kafkav2.NewFactory
+		}
+		return mq.CleanupKafkaTopics(ctx, changefeedID, sinkURI, cfg,
+			tables, otherChangefeeds, factoryCreator)
+	default:
+		return nil
+	}
+}
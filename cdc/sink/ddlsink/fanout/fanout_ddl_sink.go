@@ -0,0 +1,179 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink writes the DDL events and the checkpoints to the downstreams of the
+// tables, which are the sink-uri and the sink targets of the changefeed.
+type DDLSink struct {
+	// sinks[0] is the sink of the sink-uri, and sinks[i+1] is the sink of the
+	// i-th sink target.
+	sinks   []ddlsink.Sink
+	targets []*config.SinkTarget
+	matcher *util.TargetMatcher
+
+	mu sync.Mutex
+	// lastDDL and written record the sinks the last DDL has been written to,
+	// so the DDL isn't written to them again if it's retried.
+	lastDDL *model.DDLEvent
+	written map[int]struct{}
+}
+
+// NewDDLSink creates a DDLSink, the sinks are the sink of the sink-uri and
+// the sinks of the sink targets in cfg in order.
+func NewDDLSink(cfg *config.ReplicaConfig, sinks []ddlsink.Sink) (*DDLSink, error) {
+	if len(sinks) != len(cfg.Sink.Targets)+1 {
+		return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+			"%d sinks are created for %d sink targets", len(sinks), len(cfg.Sink.Targets))
+	}
+	matcher, err := util.NewTargetMatcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &DDLSink{
+		sinks:   sinks,
+		targets: cfg.Sink.Targets,
+		matcher: matcher,
+	}, nil
+}
+
+// WriteDDLEvent writes the DDL to the sinks of the table, and the DDLs of the
+// schemas are written to all sinks which may have the tables of the schemas.
+// The renaming DDLs are written to the sinks of both the old and new tables.
+func (s *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastDDL != ddl {
+		s.lastDDL = ddl
+		s.written = make(map[int]struct{}, len(s.sinks))
+	}
+	for _, i := range s.destinations(ddl) {
+		if _, ok := s.written[i]; ok {
+			continue
+		}
+		if err := s.sinks[i].WriteDDLEvent(ctx, ddl); err != nil {
+			return errors.Trace(err)
+		}
+		s.written[i] = struct{}{}
+	}
+	return nil
+}
+
+func (s *DDLSink) destinations(ddl *model.DDLEvent) []int {
+	if ddl.TableInfo == nil || ddl.TableInfo.TableName.Table == "" {
+		var schema string
+		if ddl.TableInfo != nil {
+			schema = ddl.TableInfo.TableName.Schema
+		}
+		indexes := []int{0}
+		for _, i := range s.matcher.MatchSchema(schema) {
+			indexes = append(indexes, i+1)
+		}
+		return indexes
+	}
+
+	indexes := []int{s.sinkIndex(ddl.TableInfo.TableName)}
+	if pre := ddl.PreTableInfo; pre != nil && pre.TableName.Table != "" {
+		if i := s.sinkIndex(pre.TableName); i != indexes[0] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (s *DDLSink) sinkIndex(table model.TableName) int {
+	return s.matcher.Match(table.Schema, table.Table) + 1
+}
+
+// WriteCheckpointTs writes the checkpoint to all sinks, each of them receives
+// the tables written to it.
+func (s *DDLSink) WriteCheckpointTs(
+	ctx context.Context, ts uint64, tables []*model.TableInfo,
+) error {
+	tablesOfSinks := make([][]*model.TableInfo, len(s.sinks))
+	for _, table := range tables {
+		i := s.sinkIndex(table.TableName)
+		tablesOfSinks[i] = append(tablesOfSinks[i], table)
+	}
+	for i, sink := range s.sinks {
+		if err := sink.WriteCheckpointTs(ctx, ts, tablesOfSinks[i]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// IsDDLRunning implements ddlsink.AsyncDDLSink. The DDL is written again to
+// the sink whose DDL executed in the background fails.
+func (s *DDLSink) IsDDLRunning() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	running := false
+	for i, sink := range s.sinks {
+		asyncSink, ok := sink.(ddlsink.AsyncDDLSink)
+		if !ok {
+			continue
+		}
+		sinkRunning, err := asyncSink.IsDDLRunning()
+		if err != nil {
+			delete(s.written, i)
+			return false, errors.Trace(err)
+		}
+		running = running || sinkRunning
+	}
+	return running, nil
+}
+
+// UpdateDispatchRules implements ddlsink.DispatchRulesUpdater. The sinks are
+// required to be recreated if the sink targets are changed.
+func (s *DDLSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	if !util.SameSinkTargets(s.targets, cfg.Sink.Targets) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("the sink targets are changed")
+	}
+	for i, sink := range s.sinks {
+		updater, ok := sink.(ddlsink.DispatchRulesUpdater)
+		if !ok {
+			continue
+		}
+		sinkConfig := cfg
+		if i > 0 {
+			sinkConfig = cfg.SinkTargetConfig(cfg.Sink.Targets[i-1])
+		}
+		if err := updater.UpdateDispatchRules(sinkConfig); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Close closes all sinks.
+func (s *DDLSink) Close() {
+	for _, sink := range s.sinks {
+		sink.Close()
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	ddls   []*model.DDLEvent
+	tables []*model.TableInfo
+	err    error
+	closed bool
+}
+
+func (s *recordingSink) WriteDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.ddls = append(s.ddls, ddl)
+	return nil
+}
+
+func (s *recordingSink) WriteCheckpointTs(
+	_ context.Context, _ uint64, tables []*model.TableInfo,
+) error {
+	s.tables = tables
+	return nil
+}
+
+func (s *recordingSink) Close() {
+	s.closed = true
+}
+
+// asyncRecordingSink executes the DDLs in the background, runErr is returned
+// once by IsDDLRunning.
+type asyncRecordingSink struct {
+	recordingSink
+	running bool
+	runErr  error
+}
+
+func (s *asyncRecordingSink) IsDDLRunning() (bool, error) {
+	if err := s.runErr; err != nil {
+		s.runErr = nil
+		return false, err
+	}
+	return s.running, nil
+}
+
+func newTestDDLSink(t *testing.T) (*DDLSink, []*recordingSink) {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Targets = []*config.SinkTarget{
+		{Matcher: []string{"test.t1"}, SinkURI: "blackhole://"},
+		{Matcher: []string{"db.*"}, SinkURI: "blackhole://"},
+	}
+	recordingSinks := []*recordingSink{{}, {}, {}}
+	sinks := make([]ddlsink.Sink, 0, len(recordingSinks))
+	for _, s := range recordingSinks {
+		sinks = append(sinks, s)
+	}
+	s, err := NewDDLSink(cfg, sinks)
+	require.NoError(t, err)
+	return s, recordingSinks
+}
+
+func newTableInfo(schema, table string) *model.TableInfo {
+	return &model.TableInfo{TableName: model.TableName{Schema: schema, Table: table}}
+}
+
+func TestWriteDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, sinks := newTestDDLSink(t)
+	defer s.Close()
+
+	createTable := &model.DDLEvent{TableInfo: newTableInfo("test", "t1")}
+	require.NoError(t, s.WriteDDLEvent(ctx, createTable))
+	require.Empty(t, sinks[0].ddls)
+	require.Equal(t, []*model.DDLEvent{createTable}, sinks[1].ddls)
+	require.Empty(t, sinks[2].ddls)
+
+	// the tables matched by no target are written to the sink-uri.
+	createOther := &model.DDLEvent{TableInfo: newTableInfo("test", "t2")}
+	require.NoError(t, s.WriteDDLEvent(ctx, createOther))
+	require.Equal(t, []*model.DDLEvent{createOther}, sinks[0].ddls)
+
+	// the DDLs of the schemas are written to all sinks which may have the
+	// tables of the schemas.
+	createSchema := &model.DDLEvent{TableInfo: newTableInfo("db", "")}
+	require.NoError(t, s.WriteDDLEvent(ctx, createSchema))
+	require.Equal(t, []*model.DDLEvent{createOther, createSchema}, sinks[0].ddls)
+	require.Equal(t, []*model.DDLEvent{createTable}, sinks[1].ddls)
+	require.Equal(t, []*model.DDLEvent{createSchema}, sinks[2].ddls)
+
+	// the renaming DDLs are written to the sinks of the old and new tables.
+	rename := &model.DDLEvent{
+		PreTableInfo: newTableInfo("test", "t1"),
+		TableInfo:    newTableInfo("db", "t1"),
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, rename))
+	require.Equal(t, []*model.DDLEvent{createTable, rename}, sinks[1].ddls)
+	require.Equal(t, []*model.DDLEvent{createSchema, rename}, sinks[2].ddls)
+}
+
+func TestWriteDDLEventRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, sinks := newTestDDLSink(t)
+	defer s.Close()
+
+	rename := &model.DDLEvent{
+		PreTableInfo: newTableInfo("test", "t1"),
+		TableInfo:    newTableInfo("db", "t1"),
+	}
+	sinks[1].err = errors.New("injected error")
+	require.NoError(t, s.WriteDDLEvent(ctx, &model.DDLEvent{TableInfo: newTableInfo("db", "t2")}))
+	require.Error(t, s.WriteDDLEvent(ctx, rename))
+	require.Len(t, sinks[2].ddls, 2)
+
+	// the retried DDL isn't written to the sinks which have received it.
+	sinks[1].err = nil
+	require.NoError(t, s.WriteDDLEvent(ctx, rename))
+	require.Len(t, sinks[1].ddls, 1)
+	require.Len(t, sinks[2].ddls, 2)
+}
+
+func TestWriteCheckpointTs(t *testing.T) {
+	t.Parallel()
+
+	s, sinks := newTestDDLSink(t)
+	t1, t2, t3 := newTableInfo("test", "t1"), newTableInfo("test", "t2"), newTableInfo("db", "t3")
+	require.NoError(t, s.WriteCheckpointTs(context.Background(), 1,
+		[]*model.TableInfo{t1, t2, t3}))
+	require.Equal(t, []*model.TableInfo{t2}, sinks[0].tables)
+	require.Equal(t, []*model.TableInfo{t1}, sinks[1].tables)
+	require.Equal(t, []*model.TableInfo{t3}, sinks[2].tables)
+
+	cfg := config.GetDefaultReplicaConfig()
+	require.Error(t, s.UpdateDispatchRules(cfg))
+
+	s.Close()
+	for _, sink := range sinks {
+		require.True(t, sink.closed)
+	}
+}
+
+func TestIsDDLRunning(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Targets = []*config.SinkTarget{
+		{Matcher: []string{"test.t1"}, SinkURI: "blackhole://"},
+	}
+	primary := &recordingSink{}
+	target := &asyncRecordingSink{running: true}
+	s, err := NewDDLSink(cfg, []ddlsink.Sink{primary, target})
+	require.NoError(t, err)
+	defer s.Close()
+
+	addIndex := &model.DDLEvent{TableInfo: newTableInfo("test", "t1")}
+	require.NoError(t, s.WriteDDLEvent(ctx, addIndex))
+	running, err := s.IsDDLRunning()
+	require.NoError(t, err)
+	require.True(t, running)
+
+	// the DDL is written again to the sink whose DDL fails.
+	target.runErr = errors.New("injected error")
+	_, err = s.IsDDLRunning()
+	require.Error(t, err)
+	require.NoError(t, s.WriteDDLEvent(ctx, addIndex))
+	require.Equal(t, []*model.DDLEvent{addIndex, addIndex}, target.ddls)
+
+	target.running = false
+	running, err = s.IsDDLRunning()
+	require.NoError(t, err)
+	require.False(t, running)
+}
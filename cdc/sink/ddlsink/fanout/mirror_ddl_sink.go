@@ -0,0 +1,134 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*MirrorDDLSink)(nil)
+
+// MirrorDDLSink writes the DDL events and the checkpoints to both the sink of
+// the sink-uri and the sink of the mirror of the changefeed. The mirror is
+// given up once it fails, so it never blocks the changefeed.
+type MirrorDDLSink struct {
+	primary ddlsink.Sink
+	mirror  ddlsink.Sink
+
+	mu sync.Mutex
+	// mirrorFailed is true once the mirror fails, nothing is written to it
+	// anymore.
+	mirrorFailed bool
+}
+
+// NewMirrorDDLSink creates a MirrorDDLSink.
+func NewMirrorDDLSink(primary, mirror ddlsink.Sink) *MirrorDDLSink {
+	return &MirrorDDLSink{primary: primary, mirror: mirror}
+}
+
+// WriteDDLEvent writes the DDL to the primary sink and then the mirror. The
+// error of the mirror is wrapped in ErrSinkMirrorFailed, the DDL is written to
+// the primary sink successfully if it's returned.
+func (s *MirrorDDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if err := s.primary.WriteDDLEvent(ctx, ddl); err != nil {
+		return errors.Trace(err)
+	}
+	return s.writeMirror(func() error { return s.mirror.WriteDDLEvent(ctx, ddl) })
+}
+
+// WriteCheckpointTs writes the checkpoint to both sinks, the error of the
+// mirror is returned in the same way as WriteDDLEvent.
+func (s *MirrorDDLSink) WriteCheckpointTs(
+	ctx context.Context, ts uint64, tables []*model.TableInfo,
+) error {
+	if err := s.primary.WriteCheckpointTs(ctx, ts, tables); err != nil {
+		return errors.Trace(err)
+	}
+	return s.writeMirror(func() error { return s.mirror.WriteCheckpointTs(ctx, ts, tables) })
+}
+
+// writeMirror writes to the mirror if it hasn't failed, the mirror is given
+// up once the write fails.
+func (s *MirrorDDLSink) writeMirror(write func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mirrorFailed {
+		return nil
+	}
+	if err := write(); err != nil {
+		s.mirrorFailed = true
+		return cerror.WrapError(cerror.ErrSinkMirrorFailed, err)
+	}
+	return nil
+}
+
+// MirrorFailed returns true if the mirror has failed.
+func (s *MirrorDDLSink) MirrorFailed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mirrorFailed
+}
+
+// IsDDLRunning implements ddlsink.AsyncDDLSink. The error of the mirror is
+// returned in the same way as WriteDDLEvent.
+func (s *MirrorDDLSink) IsDDLRunning() (bool, error) {
+	if asyncSink, ok := s.primary.(ddlsink.AsyncDDLSink); ok {
+		if running, err := asyncSink.IsDDLRunning(); err != nil || running {
+			return running, errors.Trace(err)
+		}
+	}
+	asyncSink, ok := s.mirror.(ddlsink.AsyncDDLSink)
+	if !ok {
+		return false, nil
+	}
+	running := false
+	err := s.writeMirror(func() (err error) {
+		running, err = asyncSink.IsDDLRunning()
+		return err
+	})
+	return running, err
+}
+
+// UpdateDispatchRules implements ddlsink.DispatchRulesUpdater. The sinks are
+// required to be recreated if the mirror is removed.
+func (s *MirrorDDLSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	if cfg.Sink.Mirror == nil {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("the mirror is changed")
+	}
+	if updater, ok := s.primary.(ddlsink.DispatchRulesUpdater); ok {
+		if err := updater.UpdateDispatchRules(cfg); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if updater, ok := s.mirror.(ddlsink.DispatchRulesUpdater); ok {
+		if err := updater.UpdateDispatchRules(cfg.SinkMirrorConfig()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Close closes both sinks.
+func (s *MirrorDDLSink) Close() {
+	s.primary.Close()
+	s.mirror.Close()
+}
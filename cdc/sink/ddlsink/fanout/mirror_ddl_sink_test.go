@@ -0,0 +1,68 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorDDLSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	primary, mirror := &recordingSink{}, &recordingSink{}
+	s := NewMirrorDDLSink(primary, mirror)
+
+	ddl := &model.DDLEvent{TableInfo: newTableInfo("test", "t1")}
+	require.NoError(t, s.WriteDDLEvent(ctx, ddl))
+	require.Len(t, primary.ddls, 1)
+	require.Len(t, mirror.ddls, 1)
+
+	tables := []*model.TableInfo{newTableInfo("test", "t1")}
+	require.NoError(t, s.WriteCheckpointTs(ctx, 1, tables))
+	require.Equal(t, tables, primary.tables)
+	require.Equal(t, tables, mirror.tables)
+
+	// the mirror is given up once it fails, the primary sink isn't affected.
+	mirror.err = errors.New("injected error")
+	err := s.WriteDDLEvent(ctx, ddl)
+	require.True(t, cerror.IsSinkMirrorError(err))
+	require.True(t, s.MirrorFailed())
+	require.Len(t, primary.ddls, 2)
+
+	mirror.err = nil
+	require.NoError(t, s.WriteDDLEvent(ctx, ddl))
+	require.NoError(t, s.WriteCheckpointTs(ctx, 2, nil))
+	require.Len(t, primary.ddls, 3)
+	require.Len(t, mirror.ddls, 1)
+	require.Nil(t, primary.tables)
+	require.Equal(t, tables, mirror.tables)
+
+	// the sinks must be recreated if the mirror is removed.
+	cfg := config.GetDefaultReplicaConfig()
+	require.Error(t, s.UpdateDispatchRules(cfg))
+	cfg.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://"}
+	require.NoError(t, s.UpdateDispatchRules(cfg))
+
+	s.Close()
+	require.True(t, primary.closed)
+	require.True(t, mirror.closed)
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*RoutedDDLSink)(nil)
+
+// RoutedDDLSink renames the tables and the columns of the DDLs and the
+// checkpoints by the table routes of the changefeed before they're written to
+// the sink.
+type RoutedDDLSink struct {
+	changefeedID model.ChangeFeedID
+	sink         ddlsink.Sink
+	router       *util.TableRouter
+}
+
+// NewRoutedDDLSink creates a RoutedDDLSink.
+func NewRoutedDDLSink(
+	changefeedID model.ChangeFeedID, sink ddlsink.Sink, router *util.TableRouter,
+) *RoutedDDLSink {
+	return &RoutedDDLSink{changefeedID: changefeedID, sink: sink, router: router}
+}
+
+// WriteDDLEvent writes the routed copy of the DDL to the sink, the DDLs which
+// drop or rename the tables merged with others in the downstream are skipped.
+func (s *RoutedDDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	routed, ok, err := s.router.RouteDDL(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		log.Warn("the ddl is skipped because the table is merged with others in the downstream",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.String("query", ddl.Query),
+			zap.Uint64("commitTs", ddl.CommitTs))
+		return nil
+	}
+	if routed != ddl {
+		log.Info("the ddl is routed",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.String("query", ddl.Query),
+			zap.String("routedQuery", routed.Query),
+			zap.Uint64("commitTs", ddl.CommitTs))
+	}
+	return errors.Trace(s.sink.WriteDDLEvent(ctx, routed))
+}
+
+// WriteCheckpointTs writes the checkpoint with the routed tables to the sink.
+func (s *RoutedDDLSink) WriteCheckpointTs(
+	ctx context.Context, ts uint64, tables []*model.TableInfo,
+) error {
+	return errors.Trace(s.sink.WriteCheckpointTs(ctx, ts, s.router.RouteTableInfos(tables)))
+}
+
+// IsDDLRunning implements ddlsink.AsyncDDLSink.
+func (s *RoutedDDLSink) IsDDLRunning() (bool, error) {
+	if asyncSink, ok := s.sink.(ddlsink.AsyncDDLSink); ok {
+		running, err := asyncSink.IsDDLRunning()
+		return running, errors.Trace(err)
+	}
+	return false, nil
+}
+
+// UpdateDispatchRules implements ddlsink.DispatchRulesUpdater.
+func (s *RoutedDDLSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	if updater, ok := s.sink.(ddlsink.DispatchRulesUpdater); ok {
+		return errors.Trace(updater.UpdateDispatchRules(cfg))
+	}
+	return nil
+}
+
+// Close closes the sink.
+func (s *RoutedDDLSink) Close() {
+	s.sink.Close()
+}
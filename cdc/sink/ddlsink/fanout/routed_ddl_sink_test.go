@@ -0,0 +1,76 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fanout
+
+import (
+	"context"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutedDDLSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.TableRoutes = []*config.TableRoute{
+		{Matcher: []string{"shard_*.t_*"}, TargetSchema: "shard", TargetTable: "t"},
+	}
+	router, err := util.NewTableRouter(cfg)
+	require.NoError(t, err)
+	sink := &recordingSink{}
+	s := NewRoutedDDLSink(model.DefaultChangeFeedID("test"), sink, router)
+
+	createTable := &model.DDLEvent{
+		Type:      timodel.ActionCreateTable,
+		Query:     "CREATE TABLE `t_1` (`a` INT)",
+		TableInfo: newTableInfo("shard_1", "t_1"),
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, createTable))
+	require.Len(t, sink.ddls, 1)
+	require.Equal(t, "CREATE TABLE `shard`.`t` (`a` INT)", sink.ddls[0].Query)
+	require.Equal(t, "shard", sink.ddls[0].TableInfo.TableName.Schema)
+	require.Equal(t, "shard_1", createTable.TableInfo.TableName.Schema)
+
+	// the merged table isn't dropped.
+	dropTable := &model.DDLEvent{
+		Type:      timodel.ActionDropTable,
+		Query:     "DROP TABLE `shard_1`.`t_1`",
+		TableInfo: newTableInfo("shard_1", "t_1"),
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, dropTable))
+	require.Len(t, sink.ddls, 1)
+
+	// the DDLs of the other tables are written as is.
+	other := &model.DDLEvent{
+		Type:      timodel.ActionDropTable,
+		Query:     "DROP TABLE `test`.`t1`",
+		TableInfo: newTableInfo("test", "t1"),
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, other))
+	require.Same(t, other, sink.ddls[1])
+
+	tables := []*model.TableInfo{newTableInfo("shard_1", "t_1"), newTableInfo("shard_2", "t_2")}
+	require.NoError(t, s.WriteCheckpointTs(ctx, 1, tables))
+	require.Len(t, sink.tables, 1)
+	require.Equal(t, "t", sink.tables[0].TableName.Table)
+
+	s.Close()
+	require.True(t, sink.closed)
+}
@@ -48,8 +48,9 @@ func (p *PulsarMockProducers) SyncSendMessage(ctx context.Context, topic string,
 	partitionNum int32, message *common.Message,
 ) error {
 	data := &pulsar.ProducerMessage{
-		Payload: message.Value,
-		Key:     message.GetPartitionKey(),
+		Payload:    message.Value,
+		Key:        message.GetPartitionKey(),
+		Properties: common.HeadersToMap(message.Headers),
 	}
 	p.events[topic] = append(p.events[topic], data)
 
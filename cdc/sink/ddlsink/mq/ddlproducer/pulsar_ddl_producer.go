@@ -45,23 +45,45 @@ type pulsarProducers struct {
 	// support multiple topics
 	producers      *lru.Cache
 	producersMutex sync.RWMutex
-	id             model.ChangeFeedID
+	// partitions caches the partition number of the topics
+	partitions sync.Map
+	id         model.ChangeFeedID
 }
 
-// SyncBroadcastMessage pulsar consume all partitions
+// SyncBroadcastMessage sends the message to all partitions of the topic,
+// it's sent to the topic directly if the topic is non-partitioned.
 func (p *pulsarProducers) SyncBroadcastMessage(ctx context.Context, topic string,
 	totalPartitionsNum int32, message *common.Message,
 ) error {
-	// call SyncSendMessage
-	// pulsar consumer all partitions
-	return p.SyncSendMessage(ctx, topic, totalPartitionsNum, message)
+	if totalPartitionsNum <= 0 {
+		return p.send(ctx, topic, message)
+	}
+	for i := int32(0); i < totalPartitionsNum; i++ {
+		if err := p.send(ctx, pulsarConfig.PartitionTopic(topic, i), message); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// SyncSendMessage sends a message
-// partitionNum is not used,pulsar consume all partitions
+// SyncSendMessage sends a message to the partition of the topic,
+// the partition is ignored if the topic is non-partitioned.
 func (p *pulsarProducers) SyncSendMessage(ctx context.Context, topic string,
 	partitionNum int32, message *common.Message,
 ) error {
+	totalPartitionsNum, err := p.getPartitionNum(topic)
+	if err != nil {
+		log.Error("ddl SyncSendMessage get partition number fail",
+			zap.String("topic", topic), zap.Error(err))
+		return err
+	}
+	if totalPartitionsNum > 0 {
+		topic = pulsarConfig.PartitionTopic(topic, partitionNum%totalPartitionsNum)
+	}
+	return p.send(ctx, topic, message)
+}
+
+func (p *pulsarProducers) send(ctx context.Context, topic string, message *common.Message) error {
 	p.wrapperSchemaAndTopic(message)
 
 	producer, err := p.GetProducerByTopic(topic)
@@ -71,8 +93,9 @@ func (p *pulsarProducers) SyncSendMessage(ctx context.Context, topic string,
 	}
 
 	data := &pulsar.ProducerMessage{
-		Payload: message.Value,
-		Key:     message.GetPartitionKey(),
+		Payload:    message.Value,
+		Key:        message.GetPartitionKey(),
+		Properties: common.HeadersToMap(message.Headers),
 	}
 	mID, err := producer.Send(ctx, data)
 	if err != nil {
@@ -86,6 +109,20 @@ func (p *pulsarProducers) SyncSendMessage(ctx context.Context, topic string,
 	return nil
 }
 
+// getPartitionNum returns the partition number of the topic, 0 means the
+// topic is non-partitioned.
+func (p *pulsarProducers) getPartitionNum(topic string) (int32, error) {
+	if partitions, ok := p.partitions.Load(topic); ok {
+		return partitions.(int32), nil
+	}
+	partitionNum, err := pulsarConfig.GetPartitionNum(p.client, topic)
+	if err != nil {
+		return 0, err
+	}
+	p.partitions.Store(topic, partitionNum)
+	return partitionNum, nil
+}
+
 // NewPulsarProducer creates a pulsar producer
 func NewPulsarProducer(
 	ctx context.Context,
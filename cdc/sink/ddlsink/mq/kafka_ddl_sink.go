@@ -22,11 +22,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	dmlmq "github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	tiflowutil "github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
@@ -77,18 +80,36 @@ func NewKafkaDDLSink(
 		return nil, errors.Trace(err)
 	}
 
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// the topics created for the dispatch rules may override the configurations.
+	topicCfg := options.DeriveTopicConfig()
+	topicCfg.Overrides = eventRouter.GetTopicOverrides
 	topicManager, err := util.GetTopicManagerAndTryCreateTopic(
 		ctx,
 		changefeedID,
 		topic,
-		options.DeriveTopicConfig(),
+		topicCfg,
 		adminClient,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	if options.DDLTopic != "" {
+		if options.DDLTopic == topic {
+			return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"ddl-topic should not be the same as the topic %s", topic)
+		}
+		if err := createDDLTopicIfNotExists(ctx, adminClient, options); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	columnSelector, err := filter.NewColumnSelector(replicaConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -103,6 +124,18 @@ func NewKafkaDDLSink(
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
 
+	// The claim-check files are cleaned up by the DDL sink, since it's the only
+	// sink of the changefeed, which knows the checkpoint.
+	claimCheckCleaner, err := dmlmq.NewClaimCheckCleaner(ctx, encoderConfig.LargeMessageHandle, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil && claimCheckCleaner != nil {
+			claimCheckCleaner.Close()
+		}
+	}()
+
 	start := time.Now()
 	log.Info("Try to create a DDL sink producer", zap.Any("options", options))
 	syncProducer, err := factory.SyncProducer(ctx)
@@ -111,7 +144,51 @@ func NewKafkaDDLSink(
 	}
 
 	ddlProducer := producerCreator(ctx, changefeedID, syncProducer)
-	s := newDDLSink(ctx, changefeedID, ddlProducer, adminClient, topicManager, eventRouter, encoderBuilder, protocol)
+	headers := common.NewHeadersBuilder(changefeedID,
+		replicaConfig.Sink.TiDBSourceID, replicaConfig.Sink.MessageHeaders)
+	s := newDDLSink(ctx, changefeedID, ddlProducer, adminClient, topicManager,
+		eventRouter, encoderBuilder, headers, protocol)
+	s.ddlTopic = options.DDLTopic
+	s.columnSelector = columnSelector
+	s.claimCheckCleaner = claimCheckCleaner
 	log.Info("DDL sink producer client created", zap.Duration("duration", time.Since(start)))
 	return s, nil
 }
+
+// createDDLTopicIfNotExists creates the ddl-topic with a single partition, so
+// the DDL events are totally ordered, and it's compacted to retain the latest
+// schema of each table.
+func createDDLTopicIfNotExists(
+	ctx context.Context, admin kafka.ClusterAdminClient, options *kafka.Options,
+) error {
+	topics, err := admin.GetTopicsMeta(ctx, []string{options.DDLTopic}, true)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	if _, ok := topics[options.DDLTopic]; ok {
+		return nil
+	}
+	if !options.AutoCreate {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"`auto-create-topic` is false, and the ddl-topic %s not found", options.DDLTopic)
+	}
+	// the topic configurations are applied, but the ddl-topic is always compacted.
+	configEntries := make(map[string]string, len(options.TopicConfigs)+1)
+	for name, value := range options.TopicConfigs {
+		configEntries[name] = value
+	}
+	configEntries[kafka.TopicCleanupPolicyConfigName] = kafka.TopicCleanupPolicyCompact
+	err = admin.CreateTopic(ctx, &kafka.TopicDetail{
+		Name:              options.DDLTopic,
+		NumPartitions:     1,
+		ReplicationFactor: options.ReplicationFactor,
+		ConfigEntries:     configEntries,
+	}, false)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaCreateTopic, err)
+	}
+	log.Info("Kafka admin client create the ddl topic success",
+		zap.String("topic", options.DDLTopic),
+		zap.Int16("replicationFactor", options.ReplicationFactor))
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"go.uber.org/zap"
+)
+
+// CleanupKafkaTopics deletes the topics auto-created by a removed changefeed.
+// Only the default topic and the topics of the given tables are taken into account,
+// and a topic is kept if any other changefeed may also send events to it.
+// A topic is deleted only if its earliest and latest messages of all partitions
+// carry the metadata headers of the changefeed, so the topics created by the
+// user or written by other producers are never deleted.
+func CleanupKafkaTopics(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	tables []model.TableName,
+	otherChangefeeds []*model.ChangeFeedInfo,
+	factoryCreator kafka.FactoryCreator,
+) error {
+	topic, err := util.GetTopic(sinkURI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options := kafka.NewOptions()
+	if err := options.Apply(changefeedID, sinkURI, replicaConfig); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+	}
+	// The topics are created by the user if auto-create is disabled,
+	// they should never be deleted by TiCDC.
+	if !options.AutoCreate {
+		log.Info("auto-create-topic is disabled, skip cleanup kafka topics",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID))
+		return nil
+	}
+
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	otherRouters := make([]*dispatcher.EventRouter, 0, len(otherChangefeeds))
+	for _, info := range otherChangefeeds {
+		uri, err := sink.ParseSinkURI(info.SinkURI)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+		}
+		if !sink.IsMQScheme(uri.Scheme) {
+			continue
+		}
+		otherTopic, err := util.GetTopic(uri)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		router, err := dispatcher.NewEventRouter(info.Config, otherTopic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		otherRouters = append(otherRouters, router)
+	}
+
+	factory, err := factoryCreator(options, changefeedID)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	adminClient, err := factory.AdminClient(ctx)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	defer adminClient.Close()
+
+	existTopics, err := adminClient.GetAllTopicsMeta(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	candidates := eventRouter.GetActiveTopics(tables)
+	topics := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if _, ok := existTopics[candidate]; !ok {
+			continue
+		}
+		if isTopicShared(candidate, otherRouters) {
+			log.Warn("topic may be used by other changefeeds, skip delete it",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.String("topic", candidate))
+			continue
+		}
+		owned, err := isTopicOwned(ctx, adminClient, candidate, changefeedID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !owned {
+			log.Warn("topic may contain the data not written by the changefeed, skip delete it",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.String("topic", candidate))
+			continue
+		}
+		topics = append(topics, candidate)
+	}
+	if len(topics) == 0 {
+		return nil
+	}
+	sort.Strings(topics)
+
+	if err := adminClient.DeleteTopics(ctx, topics); err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("kafka topics auto-created by the changefeed are deleted",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Strings("topics", topics))
+	return nil
+}
+
+// isTopicOwned returns true if the topic is not empty, and all the boundary
+// messages of the topic are written by the changefeed.
+func isTopicOwned(
+	ctx context.Context,
+	adminClient kafka.ClusterAdminClient,
+	topic string,
+	changefeedID model.ChangeFeedID,
+) (bool, error) {
+	headers, err := adminClient.GetBoundaryMessageHeaders(ctx, topic)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(headers) == 0 {
+		return false, nil
+	}
+	for _, h := range headers {
+		if h[common.HeaderNamespace] != changefeedID.Namespace ||
+			h[common.HeaderChangefeedID] != changefeedID.ID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isTopicShared(topic string, routers []*dispatcher.EventRouter) bool {
+	for _, router := range routers {
+		if router.MatchTopic(topic) {
+			return true
+		}
+	}
+	return false
+}
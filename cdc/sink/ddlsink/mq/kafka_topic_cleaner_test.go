@@ -0,0 +1,105 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/stretchr/testify/require"
+)
+
+type adminFactory struct {
+	kafka.Factory
+	admin kafka.ClusterAdminClient
+}
+
+func (f *adminFactory) AdminClient(_ context.Context) (kafka.ClusterAdminClient, error) {
+	return f.admin, nil
+}
+
+func TestCleanupKafkaTopics(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	admin := kafka.NewClusterAdminClientMockImpl()
+	for _, topic := range []string{"test_t1", "test_t2", "test_t3", "test_t4", "other"} {
+		err := admin.CreateTopic(ctx, &kafka.TopicDetail{
+			Name:              topic,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		}, false)
+		require.NoError(t, err)
+	}
+	id := model.DefaultChangeFeedID("test")
+	owned := map[string]string{
+		common.HeaderNamespace:    id.Namespace,
+		common.HeaderChangefeedID: id.ID,
+	}
+	for _, topic := range []string{kafka.DefaultMockTopicName, "test_t1", "test_t2"} {
+		admin.SetBoundaryMessageHeaders(topic, owned, owned)
+	}
+	// test_t3 contains the messages written by another producer.
+	admin.SetBoundaryMessageHeaders("test_t3", owned, map[string]string{})
+	// test_t4 is empty, it may be created by the user.
+	factoryCreator := func(o *kafka.Options, id model.ChangeFeedID) (kafka.Factory, error) {
+		f, err := kafka.NewMockFactory(o, id)
+		if err != nil {
+			return nil, err
+		}
+		return &adminFactory{Factory: f, admin: admin}, nil
+	}
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/" + kafka.DefaultMockTopicName +
+		"?protocol=open-protocol")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "{schema}_{table}"},
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	// test_t2 is also the default topic of the other changefeed.
+	otherConfig := config.GetDefaultReplicaConfig()
+	others := []*model.ChangeFeedInfo{
+		{SinkURI: "kafka://127.0.0.1:9092/test_t2?protocol=open-protocol", Config: otherConfig},
+		{SinkURI: "blackhole://", Config: otherConfig},
+	}
+
+	tables := []model.TableName{
+		{Schema: "test", Table: "t1"},
+		{Schema: "test", Table: "t2"},
+		{Schema: "test", Table: "t3"},
+		{Schema: "test", Table: "t4"},
+	}
+	err = CleanupKafkaTopics(ctx, id,
+		sinkURI, replicaConfig, tables, others, factoryCreator)
+	require.NoError(t, err)
+
+	topics, err := admin.GetAllTopicsMeta(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, topics, kafka.DefaultMockTopicName)
+	require.NotContains(t, topics, "test_t1")
+	require.Contains(t, topics, "test_t2")
+	require.Contains(t, topics, "test_t3")
+	require.Contains(t, topics, "test_t4")
+	require.Contains(t, topics, "other")
+}
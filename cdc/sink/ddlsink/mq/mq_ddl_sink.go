@@ -21,12 +21,15 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	dmlmq "github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	"go.uber.org/zap"
 )
@@ -34,6 +37,9 @@ import (
 // Assert Sink implementation
 var _ ddlsink.Sink = (*DDLSink)(nil)
 
+// Assert DispatchRulesUpdater implementation
+var _ ddlsink.DispatchRulesUpdater = (*DDLSink)(nil)
+
 // DDLSink is a sink that sends DDL events to the MQ system.
 type DDLSink struct {
 	// id indicates which processor (changefeed) this sink belongs to.
@@ -47,6 +53,9 @@ type DDLSink struct {
 	topicManager manager.TopicManager
 	// encoderBuilder builds encoder for the sink.
 	encoderBuilder codec.RowEventEncoderBuilder
+	// headers attaches the configured headers to the messages, it's nil if
+	// no header is configured.
+	headers *common.HeadersBuilder
 	// producer used to send events to the MQ system.
 	// Usually it is a sync producer.
 	producer ddlproducer.DDLProducer
@@ -54,6 +63,15 @@ type DDLSink struct {
 	statistics *metrics.Statistics
 	// admin is used to query kafka cluster information.
 	admin kafka.ClusterAdminClient
+	// ddlTopic is the topic which all DDL events are sent to with their
+	// schemas, instead of the data topics, it's empty if not configured.
+	ddlTopic string
+	// columnSelector is the column selector of the row events, it's nil if
+	// there is no column selector configured.
+	columnSelector *filter.ColumnSelector
+	// claimCheckCleaner deletes the expired claim-check files, it's nil if
+	// the claim-check retention isn't configured.
+	claimCheckCleaner *dmlmq.ClaimCheckCleaner
 }
 
 func newDDLSink(ctx context.Context,
@@ -63,6 +81,7 @@ func newDDLSink(ctx context.Context,
 	topicManager manager.TopicManager,
 	eventRouter *dispatcher.EventRouter,
 	encoderBuilder codec.RowEventEncoderBuilder,
+	headers *common.HeadersBuilder,
 	protocol config.Protocol,
 ) *DDLSink {
 	return &DDLSink{
@@ -71,15 +90,37 @@ func newDDLSink(ctx context.Context,
 		eventRouter:    eventRouter,
 		topicManager:   topicManager,
 		encoderBuilder: encoderBuilder,
+		headers:        headers,
 		producer:       producer,
 		statistics:     metrics.NewStatistics(ctx, changefeedID, sink.RowSink),
 		admin:          adminClient,
 	}
 }
 
+func (k *DDLSink) columnsSelected(table model.TableName) bool {
+	return k.columnSelector != nil && k.columnSelector.MatchTable(table.Schema, table.Table)
+}
+
 // WriteDDLEvent encodes the DDL event and sends it to the MQ system.
 func (k *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
 	encoder := k.encoderBuilder.Build()
+	// The DDL is rejected if the new schema of the table is incompatible with
+	// the registered one, before any row of the new schema is sent, so the
+	// owner pauses the table instead of the whole changefeed.
+	// The check is skipped for the tables whose columns are selected, since
+	// their row events are not encoded by the schema of the whole table.
+	if checker, ok := encoder.(codec.SchemaCompatibilityChecker); ok &&
+		ddl.TableInfo != nil && ddl.TableInfo.TableName.Table != "" &&
+		!k.columnsSelected(ddl.TableInfo.TableName) {
+		topic := k.eventRouter.GetTopicForTable(
+			ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table)
+		if err := checker.CheckSchemaCompatibility(ctx, topic, ddl); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if k.ddlTopic != "" {
+		return k.writeSchemaChange(ctx, ddl)
+	}
 	msg, err := encoder.EncodeDDLEvent(ddl)
 	if err != nil {
 		return errors.Trace(err)
@@ -92,6 +133,7 @@ func (k *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 			zap.String("changefeed", k.id.ID))
 		return nil
 	}
+	k.headers.Attach(msg)
 
 	topic := k.eventRouter.GetTopicForDDL(ddl)
 	partitionRule := k.eventRouter.GetDLLDispatchRuleByProtocol(k.protocol)
@@ -124,10 +166,33 @@ func (k *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 	return errors.Trace(err)
 }
 
+// writeSchemaChange sends the DDL event with the table definitions before and
+// after it to the ddl-topic, which has a single partition.
+func (k *DDLSink) writeSchemaChange(ctx context.Context, ddl *model.DDLEvent) error {
+	msg, err := encodeSchemaChange(k.protocol, ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	k.headers.Attach(msg)
+	log.Debug("Emit ddl event to the ddl topic",
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("query", ddl.Query),
+		zap.String("topic", k.ddlTopic),
+		zap.String("namespace", k.id.Namespace),
+		zap.String("changefeed", k.id.ID))
+	err = k.statistics.RecordDDLExecution(func() error {
+		return k.producer.SyncSendMessage(ctx, k.ddlTopic, dispatcher.PartitionZero, msg)
+	})
+	return errors.Trace(err)
+}
+
 // WriteCheckpointTs sends the checkpoint ts to the MQ system.
 func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	ts uint64, tables []*model.TableInfo,
 ) error {
+	if k.claimCheckCleaner != nil {
+		k.claimCheckCleaner.UpdateCheckpointTs(ts)
+	}
 	encoder := k.encoderBuilder.Build()
 	msg, err := encoder.EncodeCheckpointEvent(ts)
 	if err != nil {
@@ -136,6 +201,7 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	if msg == nil {
 		return nil
 	}
+	k.headers.Attach(msg)
 	// NOTICE: When there are no tables to replicate,
 	// we need to send checkpoint ts to the default topic.
 	// This will be compatible with the old behavior.
@@ -168,6 +234,21 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	return nil
 }
 
+// UpdateDispatchRules rebuilds the event router by the dispatch rules in the
+// config, the new topics are created when the events are routed to them.
+func (k *DDLSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	eventRouter, err := dispatcher.NewEventRouter(cfg, k.eventRouter.GetDefaultTopic())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	k.eventRouter = eventRouter
+	log.Info("MQ DDL sink has updated the dispatch rules",
+		zap.String("namespace", k.id.Namespace),
+		zap.String("changefeed", k.id.ID),
+		zap.Any("dispatchRules", cfg.Sink.DispatchRules))
+	return nil
+}
+
 // Close closes the sink.
 func (k *DDLSink) Close() {
 	if k.producer != nil {
@@ -179,4 +260,7 @@ func (k *DDLSink) Close() {
 	if k.admin != nil {
 		k.admin.Close()
 	}
+	if k.claimCheckCleaner != nil {
+		k.claimCheckCleaner.Close()
+	}
 }
@@ -23,7 +23,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -138,6 +140,119 @@ func TestWriteDDLEventToZeroPartition(t *testing.T) {
 	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetEvents("mock_topic", 2), 0)
 }
 
+func TestWriteDDLEventWithMessageHeaders(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=canal-json"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MessageHeaders = &config.MessageHeadersConfig{
+		EnableMetadata: util.AddressOf(true),
+		StaticHeaders:  map[string]string{"env": "prod"},
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDDLSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.NoError(t, err)
+
+	ddl := &model.DDLEvent{
+		CommitTs: 417318403368288260,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{
+				Schema: "cdc", Table: "person",
+			},
+		},
+		Query: "create table person(id int, name varchar(32), primary key(id))",
+		Type:  mm.ActionCreateTable,
+	}
+	err = s.WriteDDLEvent(ctx, ddl)
+	require.NoError(t, err)
+	msgs := s.producer.(*ddlproducer.MockDDLProducer).GetEvents("mock_topic", 0)
+	require.Len(t, msgs, 1)
+	require.Equal(t, map[string]string{
+		common.HeaderSchema:       "cdc",
+		common.HeaderTable:        "person",
+		common.HeaderCommitTs:     "417318403368288260",
+		common.HeaderNamespace:    model.DefaultNamespace,
+		common.HeaderChangefeedID: "test",
+		common.HeaderEventType:    common.EventTypeDDL,
+		"env":                     "prod",
+	}, common.HeadersToMap(msgs[0].Headers))
+}
+
+func TestWriteDDLEventToDDLTopic(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1&ddl-topic=cdc-ddl" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&compression=gzip&protocol=canal-json"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDDLSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.NoError(t, err)
+
+	// the ddl topic is created with a single partition and compacted.
+	topics, err := s.admin.GetTopicsMeta(ctx, []string{"cdc-ddl"}, false)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), topics["cdc-ddl"].NumPartitions)
+	policy, err := s.admin.GetTopicConfig(ctx, "cdc-ddl", kafka.TopicCleanupPolicyConfigName)
+	require.NoError(t, err)
+	require.Equal(t, kafka.TopicCleanupPolicyCompact, policy)
+
+	ddl := &model.DDLEvent{
+		CommitTs: 417318403368288260,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{
+				Schema: "cdc", Table: "person",
+			},
+		},
+		Query: "create table person(id int, name varchar(32), primary key(id))",
+		Type:  mm.ActionCreateTable,
+	}
+	err = s.WriteDDLEvent(ctx, ddl)
+	require.NoError(t, err)
+	producer := s.producer.(*ddlproducer.MockDDLProducer)
+	require.Len(t, producer.GetAllEvents(), 1, "Only the ddl topic")
+	msgs := producer.GetEvents("cdc-ddl", 0)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "cdc.person", string(msgs[0].Key))
+
+	// the ddl topic can't be the same as the data topic.
+	uri = fmt.Sprintf("kafka://127.0.0.1:9092/%s?ddl-topic=%s&protocol=canal-json",
+		kafka.DefaultMockTopicName, kafka.DefaultMockTopicName)
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+	_, err = NewKafkaDDLSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.ErrorContains(t, err, "ddl-topic should not be the same as the topic")
+}
+
 func TestWriteCheckpointTsToDefaultTopic(t *testing.T) {
 	t.Parallel()
 
@@ -242,6 +357,57 @@ func TestWriteCheckpointTsToTableTopics(t *testing.T) {
 	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetEvents("cdc_person2", 0), 1)
 }
 
+func TestUpdateDispatchRules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Notice: auto create topic is true. Auto created topic will have 1 partition.
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&compression=gzip" +
+		"&protocol=canal-json&enable-tidb-extension=true"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDDLSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	newConfig := config.GetDefaultReplicaConfig()
+	newConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{
+			Matcher:   []string{"cdc.*"},
+			TopicRule: "{schema}_{table}",
+		},
+	}
+	require.NoError(t, s.UpdateDispatchRules(newConfig))
+
+	checkpointTs := uint64(417318403368288260)
+	tables := []*model.TableInfo{
+		{
+			TableName: model.TableName{
+				Schema: "cdc",
+				Table:  "person",
+			},
+		},
+	}
+	err = s.WriteCheckpointTs(ctx, checkpointTs, tables)
+	require.NoError(t, err)
+	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetAllEvents(),
+		4, "All topics and partitions should be broadcast")
+	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetEvents("cdc_person", 0), 1)
+}
+
 func TestWriteCheckpointTsWhenCanalJsonTiDBExtensionIsDisable(t *testing.T) {
 	t.Parallel()
 
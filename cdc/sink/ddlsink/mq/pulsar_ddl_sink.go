@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
 	tiflowutil "github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
@@ -57,7 +58,7 @@ func NewPulsarDDLSink(
 		return nil, errors.Trace(err)
 	}
 
-	pConfig, err := pulsarConfig.NewPulsarConfig(sinkURI)
+	pConfig, err := pulsarConfig.NewPulsarConfig(sinkURI, replicaConfig.Sink.PulsarConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -98,7 +99,9 @@ func NewPulsarDDLSink(
 		return nil, errors.Trace(err)
 	}
 
-	s := newDDLSink(ctx, changefeedID, p, nil, topicManager, eventRouter, encoderBuilder, protocol)
+	headers := common.NewHeadersBuilder(changefeedID,
+		replicaConfig.Sink.TiDBSourceID, replicaConfig.Sink.MessageHeaders)
+	s := newDDLSink(ctx, changefeedID, p, nil, topicManager, eventRouter, encoderBuilder, headers, protocol)
 
 	return s, nil
 }
@@ -43,7 +43,7 @@ func newPulsarConfig(t *testing.T) (*pulsarConfig.Config, *url.URL) {
 	replicaConfig := config.GetDefaultReplicaConfig()
 	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
 	require.NoError(t, err)
-	c, err := pulsarConfig.NewPulsarConfig(sinkURI)
+	c, err := pulsarConfig.NewPulsarConfig(sinkURI, replicaConfig.Sink.PulsarConfig)
 	require.NoError(t, err)
 	return c, sinkURI
 }
@@ -0,0 +1,92 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"encoding/json"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+)
+
+// schemaChange is the payload of the messages sent to the ddl-topic, it
+// carries the definitions of the table before and after the DDL, so the
+// consumers can track the schemas without parsing the queries.
+type schemaChange struct {
+	CommitTs uint64             `json:"CommitTs"`
+	Type     timodel.ActionType `json:"Type"`
+	Query    string             `json:"Query"`
+	Schema   string             `json:"Schema"`
+	Table    string             `json:"Table,omitempty"`
+	// Before is absent if the table is created by the DDL.
+	Before *cloudstorage.TableDefinition `json:"Before,omitempty"`
+	// After is absent if the table is dropped by the DDL.
+	After *cloudstorage.TableDefinition `json:"After,omitempty"`
+}
+
+func newSchemaChange(ddl *model.DDLEvent) *schemaChange {
+	c := &schemaChange{
+		CommitTs: ddl.CommitTs,
+		Type:     ddl.Type,
+		Query:    ddl.Query,
+	}
+	if ddl.TableInfo == nil {
+		return c
+	}
+	c.Schema = ddl.TableInfo.TableName.Schema
+	c.Table = ddl.TableInfo.TableName.Table
+
+	after := ddl.TableInfo
+	before := ddl.PreTableInfo
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionDropView:
+		// the dropped table is carried by the TableInfo of the DDL.
+		before, after = after, nil
+	}
+	if before != nil && before.TableInfo != nil {
+		c.Before = &cloudstorage.TableDefinition{}
+		c.Before.FromTableInfo(before, before.Version, false)
+	}
+	if after != nil && after.TableInfo != nil {
+		c.After = &cloudstorage.TableDefinition{}
+		c.After.FromTableInfo(after, after.Version, false)
+	}
+	return c
+}
+
+// key is the key of the message, the messages of a table share the key so
+// the latest schema of each table is retained after the topic is compacted.
+func (c *schemaChange) key() string {
+	if c.Table == "" {
+		return c.Schema
+	}
+	return c.Schema + "." + c.Table
+}
+
+// encodeSchemaChange encodes the DDL event into a message of the ddl-topic.
+func encodeSchemaChange(
+	protocol config.Protocol, ddl *model.DDLEvent,
+) (*common.Message, error) {
+	c := newSchemaChange(ddl)
+	value, err := json.Marshal(c)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	return common.NewMsg(protocol, []byte(c.key()), value,
+		ddl.CommitTs, model.MessageTypeDDL, &c.Schema, &c.Table), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"encoding/json"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchemaChangeTableInfo(version uint64, columns ...string) *model.TableInfo {
+	info := &timodel.TableInfo{Name: timodel.NewCIStr("person")}
+	for i, name := range columns {
+		col := &timodel.ColumnInfo{
+			ID:        int64(i + 1),
+			Name:      timodel.NewCIStr(name),
+			FieldType: *types.NewFieldType(mysql.TypeLong),
+		}
+		info.Columns = append(info.Columns, col)
+	}
+	return &model.TableInfo{
+		TableName: model.TableName{Schema: "cdc", Table: "person"},
+		TableInfo: info,
+		Version:   version,
+	}
+}
+
+func TestEncodeSchemaChange(t *testing.T) {
+	t.Parallel()
+
+	ddl := &model.DDLEvent{
+		CommitTs:     200,
+		Type:         timodel.ActionAddColumn,
+		Query:        "alter table person add column age int",
+		PreTableInfo: newSchemaChangeTableInfo(100, "id"),
+		TableInfo:    newSchemaChangeTableInfo(200, "id", "age"),
+	}
+	msg, err := encodeSchemaChange(config.ProtocolCanalJSON, ddl)
+	require.NoError(t, err)
+	require.Equal(t, "cdc.person", string(msg.Key))
+	require.Equal(t, uint64(200), msg.Ts)
+	require.Equal(t, model.MessageTypeDDL, msg.Type)
+
+	c := &schemaChange{}
+	require.NoError(t, json.Unmarshal(msg.Value, c))
+	require.Equal(t, "cdc", c.Schema)
+	require.Equal(t, "person", c.Table)
+	require.Equal(t, ddl.Query, c.Query)
+	require.Equal(t, ddl.Type, c.Type)
+	require.Equal(t, uint64(100), c.Before.TableVersion)
+	require.Len(t, c.Before.Columns, 1)
+	require.Equal(t, uint64(200), c.After.TableVersion)
+	require.Len(t, c.After.Columns, 2)
+	require.Equal(t, "age", c.After.Columns[1].Name)
+
+	// the dropped table is only carried by the definition before the DDL.
+	ddl = &model.DDLEvent{
+		CommitTs:  300,
+		Type:      timodel.ActionDropTable,
+		Query:     "DROP TABLE `cdc`.`person`",
+		TableInfo: newSchemaChangeTableInfo(200, "id", "age"),
+	}
+	msg, err = encodeSchemaChange(config.ProtocolCanalJSON, ddl)
+	require.NoError(t, err)
+	c = &schemaChange{}
+	require.NoError(t, json.Unmarshal(msg.Value, c))
+	require.Len(t, c.Before.Columns, 2)
+	require.Nil(t, c.After)
+
+	// the schema is the key of the DDLs of a database.
+	ddl = &model.DDLEvent{
+		CommitTs: 400,
+		Type:     timodel.ActionCreateSchema,
+		Query:    "CREATE DATABASE `cdc`",
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "cdc"},
+		},
+	}
+	msg, err = encodeSchemaChange(config.ProtocolCanalJSON, ddl)
+	require.NoError(t, err)
+	require.Equal(t, "cdc", string(msg.Key))
+}
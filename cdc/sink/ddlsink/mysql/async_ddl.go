@@ -0,0 +1,193 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// asyncDDL is a DDL executed in the background.
+type asyncDDL struct {
+	ddl  *model.DDLEvent
+	done chan struct{}
+	// err is set before done is closed.
+	err error
+}
+
+// asyncDDLExecutor executes the time-consuming DDLs, such as ADD INDEX, in the
+// background, so the changefeed keeps replicating while they are running.
+// The DDLs depending on the tables of a running DDL wait until it finishes,
+// the DDLs of the unrelated tables are executed directly. The owner waits for
+// the running DDLs before the DDL is done, so the checkpoint never passes a
+// running DDL, and it's executed again if TiCDC restarts.
+type asyncDDLExecutor struct {
+	changefeedID model.ChangeFeedID
+	exec         func(ctx context.Context, ddl *model.DDLEvent) error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// running is the running DDLs keyed by the table they are executed on.
+	running map[model.TableName]*asyncDDL
+	// err is the first error of the failed DDLs, it's reported once.
+	err error
+}
+
+func newAsyncDDLExecutor(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	exec func(ctx context.Context, ddl *model.DDLEvent) error,
+) *asyncDDLExecutor {
+	ctx, cancel := context.WithCancel(ctx)
+	return &asyncDDLExecutor{
+		changefeedID: changefeedID,
+		exec:         exec,
+		ctx:          ctx,
+		cancel:       cancel,
+		running:      make(map[model.TableName]*asyncDDL),
+	}
+}
+
+// canExecAsync returns true if the DDL can be executed in the background,
+// only the DDLs which don't block the DMLs of the table in the downstream
+// are executed asynchronously.
+func canExecAsync(ddl *model.DDLEvent) bool {
+	return ddl.Type == timodel.ActionAddIndex &&
+		ddl.TableInfo != nil && ddl.TableInfo.TableName.Table != ""
+}
+
+// submit executes the DDL in the background.
+func (e *asyncDDLExecutor) submit(ddl *model.DDLEvent) {
+	d := &asyncDDL{ddl: ddl, done: make(chan struct{})}
+	table := ddl.TableInfo.TableName
+	e.mu.Lock()
+	e.running[table] = d
+	e.mu.Unlock()
+
+	log.Info("Execute DDL asynchronously",
+		zap.String("namespace", e.changefeedID.Namespace),
+		zap.String("changefeed", e.changefeedID.ID),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("ddl", ddl.Query))
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		start := time.Now()
+		err := e.exec(e.ctx, ddl)
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		d.err = err
+		close(d.done)
+		if e.running[table] == d {
+			delete(e.running, table)
+		}
+		if err != nil && e.ctx.Err() != nil {
+			// the DDL is executed again after the sink is recreated.
+			return
+		}
+		if err != nil {
+			log.Error("Execute DDL asynchronously failed",
+				zap.String("namespace", e.changefeedID.Namespace),
+				zap.String("changefeed", e.changefeedID.ID),
+				zap.String("ddl", ddl.Query),
+				zap.Duration("duration", time.Since(start)),
+				zap.Error(err))
+			if e.err == nil {
+				e.err = err
+			}
+			return
+		}
+		log.Info("Execute DDL asynchronously succeeded",
+			zap.String("namespace", e.changefeedID.Namespace),
+			zap.String("changefeed", e.changefeedID.ID),
+			zap.String("ddl", ddl.Query),
+			zap.Duration("duration", time.Since(start)))
+	}()
+}
+
+// wait blocks until all the running DDLs the given DDL depends on finish.
+func (e *asyncDDLExecutor) wait(ctx context.Context, ddl *model.DDLEvent) error {
+	for _, d := range e.dependencies(ddl) {
+		log.Info("Wait for the asynchronous DDL to finish",
+			zap.String("namespace", e.changefeedID.Namespace),
+			zap.String("changefeed", e.changefeedID.ID),
+			zap.String("running", d.ddl.Query),
+			zap.String("ddl", ddl.Query))
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-d.done:
+		}
+	}
+	return nil
+}
+
+// dependencies returns the running DDLs which are executed on the tables
+// of the given DDL, the DDLs of the schema or the cluster depend on all
+// the running DDLs of the schema or the cluster.
+func (e *asyncDDLExecutor) dependencies(ddl *model.DDLEvent) []*asyncDDL {
+	var tables []model.TableName
+	if ddl.TableInfo != nil {
+		tables = append(tables, ddl.TableInfo.TableName)
+	}
+	if ddl.PreTableInfo != nil {
+		tables = append(tables, ddl.PreTableInfo.TableName)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var result []*asyncDDL
+	for table, d := range e.running {
+		for _, t := range tables {
+			if t.Schema == "" || (t.Schema == table.Schema && (t.Table == "" || t.Table == table.Table)) {
+				result = append(result, d)
+				break
+			}
+		}
+		// the DDL without table info depends on all the running DDLs.
+		if len(tables) == 0 {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// isRunning returns true if any DDL is running. The error of a failed DDL is
+// returned once, so the DDL is written again by the caller.
+func (e *asyncDDLExecutor) isRunning() (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.err; err != nil {
+		e.err = nil
+		return false, err
+	}
+	return len(e.running) > 0, nil
+}
+
+// close cancels the running DDLs and waits for them to exit.
+func (e *asyncDDLExecutor) close() {
+	e.cancel()
+	e.wg.Wait()
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newAsyncTestDDL(tp timodel.ActionType, schema, table, query string) *model.DDLEvent {
+	return &model.DDLEvent{
+		Type:  tp,
+		Query: query,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: schema, Table: table},
+		},
+	}
+}
+
+func TestAsyncDDLExecutorDependencies(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	e := newAsyncDDLExecutor(ctx, model.DefaultChangeFeedID("test"),
+		func(ctx context.Context, ddl *model.DDLEvent) error {
+			<-release
+			return nil
+		})
+	defer e.close()
+
+	addIndex := newAsyncTestDDL(timodel.ActionAddIndex, "test", "t1",
+		"ALTER TABLE t1 ADD INDEX idx(a)")
+	require.True(t, canExecAsync(addIndex))
+	require.False(t, canExecAsync(newAsyncTestDDL(timodel.ActionAddColumn, "test", "t1",
+		"ALTER TABLE t1 ADD COLUMN b int")))
+	require.NoError(t, e.wait(ctx, addIndex))
+	e.submit(addIndex)
+
+	// the DDLs of the unrelated tables are not blocked.
+	require.NoError(t, e.wait(ctx, newAsyncTestDDL(timodel.ActionAddColumn, "test", "t2",
+		"ALTER TABLE t2 ADD COLUMN b int")))
+	require.NoError(t, e.wait(ctx, newAsyncTestDDL(timodel.ActionCreateSchema, "test2", "",
+		"CREATE DATABASE test2")))
+
+	// the DDLs of the same table or schema wait for the running DDL.
+	for _, ddl := range []*model.DDLEvent{
+		newAsyncTestDDL(timodel.ActionAddColumn, "test", "t1", "ALTER TABLE t1 ADD COLUMN b int"),
+		newAsyncTestDDL(timodel.ActionDropSchema, "test", "", "DROP DATABASE test"),
+	} {
+		waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		require.ErrorIs(t, e.wait(waitCtx, ddl), context.DeadlineExceeded)
+		waitCancel()
+	}
+
+	close(release)
+	require.NoError(t, e.wait(ctx, newAsyncTestDDL(timodel.ActionAddColumn, "test", "t1",
+		"ALTER TABLE t1 ADD COLUMN b int")))
+	e.mu.Lock()
+	require.Empty(t, e.running)
+	e.mu.Unlock()
+}
+
+func TestAsyncDDLExecutorError(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	execErr := errors.New("duplicate entry")
+	e := newAsyncDDLExecutor(ctx, model.DefaultChangeFeedID("test"),
+		func(ctx context.Context, ddl *model.DDLEvent) error {
+			return execErr
+		})
+	defer e.close()
+
+	ddl := newAsyncTestDDL(timodel.ActionAddIndex, "test", "t1",
+		"ALTER TABLE t1 ADD UNIQUE INDEX idx(a)")
+	e.submit(ddl)
+	require.NoError(t, e.wait(ctx, ddl))
+	// the error is reported once, so the DDL is written again.
+	running, err := e.isRunning()
+	require.ErrorIs(t, err, execErr)
+	require.False(t, running)
+	running, err = e.isRunning()
+	require.NoError(t, err)
+	require.False(t, running)
+}
+
+func TestAsyncDDLExecutorClose(t *testing.T) {
+	t.Parallel()
+
+	e := newAsyncDDLExecutor(context.Background(), model.DefaultChangeFeedID("test"),
+		func(ctx context.Context, ddl *model.DDLEvent) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	e.submit(newAsyncTestDDL(timodel.ActionAddIndex, "test", "t1",
+		"ALTER TABLE t1 ADD INDEX idx(a)"))
+	running, err := e.isRunning()
+	require.NoError(t, err)
+	require.True(t, running)
+
+	// the canceled DDL isn't reported as a failure.
+	e.close()
+	e.mu.Lock()
+	require.NoError(t, e.err)
+	e.mu.Unlock()
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"regexp"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// translationRule rewrites or skips the DDLs matched by the pattern.
+type translationRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+	skip        bool
+}
+
+// The DDLs are restored with the TiDB specific syntax wrapped in the special
+// comments by the owner, such as
+//
+//	PRIMARY KEY(`id`) /*T![clustered_index] CLUSTERED */
+//	`id` BIGINT /*T![auto_rand] AUTO_RANDOM(5) */
+//	/*T! SHARD_ROW_ID_BITS=4 */
+//
+// so the built-in rules remove the special comments, and the placement rules
+// are already removed when the DDLs are restored.
+var commonTranslationRules = []translationRule{
+	{pattern: regexp.MustCompile(`(?s)[ \t]*/\*T!.*?\*/`)},
+}
+
+// mariaDBTranslationRules map the syntax of MySQL 8.0 supported by TiDB to the
+// equivalent one of MariaDB.
+var mariaDBTranslationRules = []translationRule{
+	{pattern: regexp.MustCompile(`(?i)\butf8mb4_0900_ai_ci\b`), replacement: "utf8mb4_general_ci"},
+	{pattern: regexp.MustCompile(`(?i)\butf8mb4_0900_bin\b`), replacement: "utf8mb4_bin"},
+	// the invisible indexes are called the ignored indexes in MariaDB.
+	{pattern: regexp.MustCompile(`(?i)(\)|\bALTER\s+INDEX\s+\S+)\s+INVISIBLE\b`), replacement: "$1 IGNORED"},
+	{pattern: regexp.MustCompile(`(?i)(\)|\bALTER\s+INDEX\s+\S+)\s+VISIBLE\b`), replacement: "$1 NOT IGNORED"},
+}
+
+// untranslatableDDLTypes are the DDLs which have no equivalents in the
+// downstreams which aren't TiDB.
+var untranslatableDDLTypes = []timodel.ActionType{
+	// RECOVER TABLE and FLASHBACK TABLE
+	timodel.ActionRecoverTable,
+	timodel.ActionAlterTTLInfo,
+	timodel.ActionAlterTTLRemove,
+}
+
+// ddlTranslator rewrites the DDLs for the downstreams which aren't TiDB.
+type ddlTranslator struct {
+	skipTypes []timodel.ActionType
+	rules     []translationRule
+}
+
+func newDDLTranslator(cfg *config.DDLTranslationConfig) (*ddlTranslator, error) {
+	t := &ddlTranslator{}
+	switch util.GetOrZero(cfg.Dialect) {
+	case "":
+	case config.DDLDialectMySQL:
+		t.skipTypes = untranslatableDDLTypes
+		t.rules = append(t.rules, commonTranslationRules...)
+	case config.DDLDialectMariaDB:
+		t.skipTypes = untranslatableDDLTypes
+		t.rules = append(t.rules, commonTranslationRules...)
+		t.rules = append(t.rules, mariaDBTranslationRules...)
+	default:
+		return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+			"unsupported dialect %s of the ddl-translation", util.GetOrZero(cfg.Dialect))
+	}
+	for _, rule := range cfg.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		t.rules = append(t.rules, translationRule{
+			pattern:     pattern,
+			replacement: rule.Replacement,
+			skip:        rule.Skip,
+		})
+	}
+	return t, nil
+}
+
+// translate returns the query executed in the downstream, false is returned
+// if the DDL is skipped.
+func (t *ddlTranslator) translate(tp timodel.ActionType, query string) (string, bool) {
+	for _, skipType := range t.skipTypes {
+		if tp == skipType {
+			return "", false
+		}
+	}
+	for _, rule := range t.rules {
+		if !rule.pattern.MatchString(query) {
+			continue
+		}
+		if rule.skip {
+			return "", false
+		}
+		query = rule.pattern.ReplaceAllString(query, rule.replacement)
+	}
+	return query, true
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLTranslator(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		dialect  string
+		tp       timodel.ActionType
+		query    string
+		expected string
+		skipped  bool
+	}{
+		{
+			dialect: config.DDLDialectMySQL,
+			tp:      timodel.ActionCreateTable,
+			query: "CREATE TABLE `t` (`id` BIGINT /*T![auto_rand] AUTO_RANDOM(5) */," +
+				"PRIMARY KEY(`id`) /*T![clustered_index] CLUSTERED */) /*T! SHARD_ROW_ID_BITS=4 */",
+			expected: "CREATE TABLE `t` (`id` BIGINT,PRIMARY KEY(`id`))",
+		},
+		{
+			dialect: config.DDLDialectMySQL,
+			tp:      timodel.ActionAlterIndexVisibility,
+			query:   "ALTER TABLE `t` ALTER INDEX `idx` INVISIBLE",
+			// MySQL supports the invisible indexes.
+			expected: "ALTER TABLE `t` ALTER INDEX `idx` INVISIBLE",
+		},
+		{
+			dialect: config.DDLDialectMySQL,
+			tp:      timodel.ActionRecoverTable,
+			query:   "RECOVER TABLE `t`",
+			skipped: true,
+		},
+		{
+			dialect: config.DDLDialectMariaDB,
+			tp:      timodel.ActionCreateTable,
+			query: "CREATE TABLE `t` (`a` INT,KEY `idx`(`a`) INVISIBLE) " +
+				"DEFAULT CHARSET=UTF8MB4 COLLATE=UTF8MB4_0900_AI_CI",
+			expected: "CREATE TABLE `t` (`a` INT,KEY `idx`(`a`) IGNORED) " +
+				"DEFAULT CHARSET=UTF8MB4 COLLATE=utf8mb4_general_ci",
+		},
+		{
+			dialect:  config.DDLDialectMariaDB,
+			tp:       timodel.ActionAlterIndexVisibility,
+			query:    "ALTER TABLE `t` ALTER INDEX `idx` VISIBLE",
+			expected: "ALTER TABLE `t` ALTER INDEX `idx` NOT IGNORED",
+		},
+		{
+			dialect: config.DDLDialectMariaDB,
+			tp:      timodel.ActionAlterTTLInfo,
+			query:   "ALTER TABLE `t` /*T![ttl] TTL = `created_at` + INTERVAL 1 DAY */",
+			skipped: true,
+		},
+		{
+			// no built-in rules are applied without the dialect.
+			tp:       timodel.ActionRecoverTable,
+			query:    "RECOVER TABLE `t`",
+			expected: "RECOVER TABLE `t`",
+		},
+	}
+	for _, c := range cases {
+		translator, err := newDDLTranslator(&config.DDLTranslationConfig{
+			Dialect: util.AddressOf(c.dialect),
+		})
+		require.NoError(t, err)
+		query, ok := translator.translate(c.tp, c.query)
+		require.Equal(t, !c.skipped, ok, c.query)
+		require.Equal(t, c.expected, query, c.query)
+	}
+}
+
+func TestDDLTranslatorRules(t *testing.T) {
+	t.Parallel()
+
+	translator, err := newDDLTranslator(&config.DDLTranslationConfig{
+		Dialect: util.AddressOf(config.DDLDialectMySQL),
+		Rules: []*config.DDLTranslationRule{
+			{Pattern: `(?i)\s+ENGINE\s*=\s*\w+`, Replacement: ""},
+			{Pattern: `(?i)\bCHARSET=(\w+)`, Replacement: "CHARSET=${1} ENGINE=InnoDB"},
+			{Pattern: `(?i)^ALTER TABLE \S+ AUTO_INCREMENT`, Skip: true},
+		},
+	})
+	require.NoError(t, err)
+
+	// the custom rules are applied after the built-in rules.
+	query, ok := translator.translate(timodel.ActionCreateTable,
+		"CREATE TABLE `t` (`a` INT) ENGINE = MyISAM DEFAULT CHARSET=utf8mb4 /*T! SHARD_ROW_ID_BITS=4 */")
+	require.True(t, ok)
+	require.Equal(t, "CREATE TABLE `t` (`a` INT) DEFAULT CHARSET=utf8mb4 ENGINE=InnoDB", query)
+
+	_, ok = translator.translate(timodel.ActionRebaseAutoID, "ALTER TABLE `t` AUTO_INCREMENT = 100")
+	require.False(t, ok)
+
+	_, err = newDDLTranslator(&config.DDLTranslationConfig{Dialect: util.AddressOf("oracle")})
+	require.Error(t, err)
+}
@@ -48,7 +48,10 @@ const (
 var GetDBConnImpl pmysql.Factory = pmysql.CreateMySQLDBConn
 
 // Assert Sink implementation
-var _ ddlsink.Sink = (*DDLSink)(nil)
+var (
+	_ ddlsink.Sink         = (*DDLSink)(nil)
+	_ ddlsink.AsyncDDLSink = (*DDLSink)(nil)
+)
 
 // DDLSink is a sink that writes DDL events to MySQL.
 type DDLSink struct {
@@ -60,6 +63,10 @@ type DDLSink struct {
 	// statistics is the statistics of this sink.
 	// We use it to record the DDL count.
 	statistics *metrics.Statistics
+	// asyncDDL is nil if the async DDL is disabled.
+	asyncDDL *asyncDDLExecutor
+	// translator is nil if the DDLs are executed as they are.
+	translator *ddlTranslator
 }
 
 // NewDDLSink creates a new DDLSink.
@@ -91,6 +98,39 @@ func NewDDLSink(
 		cfg:        cfg,
 		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
 	}
+	if cfg.AsyncDDLEnable {
+		// The DDL jobs keep running in TiDB even if the connection is closed,
+		// so the DDLs executed in the background are not lost if TiCDC restarts.
+		isTiDB, err := pmysql.CheckIsTiDB(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		if isTiDB {
+			m.asyncDDL = newAsyncDDLExecutor(ctx, changefeedID, m.execDDLWithMaxRetries)
+		} else {
+			log.Warn("Async DDL is only supported when the downstream is TiDB, ignore it",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID))
+		}
+	}
+
+	if mysqlConfig := replicaConfig.Sink.MySQLConfig; mysqlConfig != nil && mysqlConfig.DDLTranslation != nil {
+		// The DDLs replicated to TiDB can be executed as they are.
+		isTiDB, err := pmysql.CheckIsTiDB(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		if !isTiDB {
+			m.translator, err = newDDLTranslator(mysqlConfig.DDLTranslation)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			log.Warn("DDL translation is only supported when the downstream isn't TiDB, ignore it",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID))
+		}
+	}
 
 	log.Info("MySQL DDL sink is created",
 		zap.String("namespace", m.id.Namespace),
@@ -100,6 +140,21 @@ func NewDDLSink(
 
 // WriteDDLEvent writes a DDL event to the mysql database.
 func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if m.translator != nil {
+		var ok bool
+		if ddl, ok = m.translate(ddl); !ok {
+			return nil
+		}
+	}
+	if m.asyncDDL != nil {
+		if err := m.asyncDDL.wait(ctx, ddl); err != nil {
+			return errors.Trace(err)
+		}
+		if canExecAsync(ddl) {
+			m.asyncDDL.submit(ddl)
+			return nil
+		}
+	}
 	err := m.execDDLWithMaxRetries(ctx, ddl)
 	// we should not retry changefeed if DDL failed by return an unretryable error.
 	if !errorutil.IsRetryableDDLError(err) {
@@ -136,6 +191,38 @@ func (m *DDLSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent
 		retry.WithIsRetryableErr(errorutil.IsRetryableDDLError))
 }
 
+// translate returns the DDL executed in the downstream, false is returned if
+// the DDL is skipped. The DDL event is shared by the sinks, so a translated
+// copy of it is returned instead of modifying it.
+func (m *DDLSink) translate(ddl *model.DDLEvent) (*model.DDLEvent, bool) {
+	query, ok := m.translator.translate(ddl.Type, ddl.Query)
+	if !ok {
+		log.Info("DDL is skipped by the ddl translation",
+			zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID))
+		return nil, false
+	}
+	if query == ddl.Query {
+		return ddl, true
+	}
+	log.Info("DDL is translated",
+		zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+		zap.String("translated", query),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return &model.DDLEvent{
+		StartTs:      ddl.StartTs,
+		CommitTs:     ddl.CommitTs,
+		Query:        query,
+		TableInfo:    ddl.TableInfo,
+		PreTableInfo: ddl.PreTableInfo,
+		Type:         ddl.Type,
+		Charset:      ddl.Charset,
+		Collate:      ddl.Collate,
+	}, true
+}
+
 // isReorgOrPartitionDDL returns true if given ddl type is reorg ddl or
 // partition ddl.
 func isReorgOrPartitionDDL(t timodel.ActionType) bool {
@@ -239,8 +326,24 @@ func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.Tabl
 	return nil
 }
 
+// IsDDLRunning implements ddlsink.AsyncDDLSink. The error of the DDL executed
+// in the background is classified in the same way as WriteDDLEvent.
+func (m *DDLSink) IsDDLRunning() (bool, error) {
+	if m.asyncDDL == nil {
+		return false, nil
+	}
+	running, err := m.asyncDDL.isRunning()
+	if err != nil && !errorutil.IsRetryableDDLError(err) {
+		return false, cerror.WrapChangefeedUnretryableErr(err)
+	}
+	return running, errors.Trace(err)
+}
+
 // Close closes the database connection.
 func (m *DDLSink) Close() {
+	if m.asyncDDL != nil {
+		m.asyncDDL.close()
+	}
 	if m.statistics != nil {
 		m.statistics.Close()
 	}
@@ -0,0 +1,69 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+	"go.uber.org/zap"
+)
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is the Redis DDL sink. The keys in Redis have no schema,
+// so the DDLs are skipped.
+type DDLSink struct {
+	changefeedID model.ChangeFeedID
+}
+
+// NewDDLSink creates a Redis DDL sink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*DDLSink, error) {
+	cfg := predis.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+	return &DDLSink{changefeedID: changefeedID}, nil
+}
+
+// WriteDDLEvent skips the DDL.
+func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	log.Info("Redis sink skips the DDL",
+		zap.String("namespace", d.changefeedID.Namespace),
+		zap.String("changefeed", d.changefeedID.ID),
+		zap.Uint64("commitTs", ddl.CommitTs),
+		zap.String("query", ddl.Query))
+	return nil
+}
+
+// WriteCheckpointTs does nothing.
+func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	return nil
+}
+
+// Close does nothing.
+func (d *DDLSink) Close() {}
@@ -0,0 +1,166 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchsink
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/hash"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"golang.org/x/sync/errgroup"
+)
+
+// Backend encodes the transactions into a batch and writes the batch into the
+// downstream. Each worker owns a backend, so it's never used concurrently.
+type Backend interface {
+	// Append encodes the transaction into the pending batch, and returns the
+	// size of the encoded transaction, which is limited by the max batch rows.
+	Append(txn *model.SingleTableTxn) (int, error)
+	// Flush writes the pending batch into the downstream and resets it,
+	// it returns the number of the written rows.
+	Flush(ctx context.Context) (int, error)
+	// Close releases the resources of the backend.
+	Close() error
+}
+
+// Assert EventSink[E event.TableEvent] implementation
+var _ dmlsink.EventSink[*model.SingleTableTxn] = (*DMLSink)(nil)
+
+// DMLSink writes the transactions into the downstream by a group of workers.
+// The ready transactions of a worker are collected into a batch, and their
+// callbacks are called only after the batch is flushed.
+type DMLSink struct {
+	changefeedID model.ChangeFeedID
+	// workers defines a group of workers for writing events to the downstream,
+	// the events of a table are always written by the same worker.
+	workers []*worker
+
+	alive struct {
+		sync.RWMutex
+		isDead bool
+	}
+
+	statistics *metrics.Statistics
+
+	cancel func()
+	wg     sync.WaitGroup
+	dead   chan struct{}
+}
+
+// New creates a DMLSink, a worker is started for each backend. The name of
+// the sink is used in the logs.
+func New(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	name string,
+	backends []Backend,
+	maxBatchRows int,
+	errCh chan error,
+) *DMLSink {
+	wgCtx, wgCancel := context.WithCancel(ctx)
+	s := &DMLSink{
+		changefeedID: changefeedID,
+		workers:      make([]*worker, len(backends)),
+		statistics:   metrics.NewStatistics(wgCtx, changefeedID, sink.TxnSink),
+		cancel:       wgCancel,
+		dead:         make(chan struct{}),
+	}
+	for i, backend := range backends {
+		s.workers[i] = newWorker(i, changefeedID, name, backend, maxBatchRows,
+			chann.NewAutoDrainChann[*dmlsink.TxnCallbackableEvent](), s.statistics)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.run(wgCtx)
+
+		s.alive.Lock()
+		s.alive.isDead = true
+		s.alive.Unlock()
+		close(s.dead)
+
+		if err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-wgCtx.Done():
+			case errCh <- err:
+			}
+		}
+	}()
+
+	return s
+}
+
+func (s *DMLSink) run(ctx context.Context) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < len(s.workers); i++ {
+		worker := s.workers[i]
+		eg.Go(func() error {
+			return worker.run(ctx)
+		})
+	}
+	return eg.Wait()
+}
+
+// WriteEvents writes events to the sink.
+func (s *DMLSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTableTxn]) error {
+	s.alive.RLock()
+	defer s.alive.RUnlock()
+	if s.alive.isDead {
+		return errors.Trace(errors.New("dead dmlSink"))
+	}
+
+	hasher := hash.NewPositionInertia()
+	for _, txn := range txns {
+		if txn.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			txn.Callback()
+			continue
+		}
+
+		hasher.Reset()
+		hasher.Write([]byte(txn.Event.Table.Schema), []byte(txn.Event.Table.Table))
+		index := int(hasher.Sum32() % uint32(len(s.workers)))
+		s.statistics.ObserveRows(txn.Event.Rows...)
+		s.workers[index].inputCh.In() <- txn
+	}
+	return nil
+}
+
+// Close closes the sink and the backends.
+func (s *DMLSink) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	for _, worker := range s.workers {
+		worker.close()
+	}
+	if s.statistics != nil {
+		s.statistics.Close()
+	}
+}
+
+// Dead checks whether it's dead or not.
+func (s *DMLSink) Dead() <-chan struct{} {
+	return s.dead
+}
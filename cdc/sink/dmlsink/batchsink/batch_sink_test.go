@@ -0,0 +1,137 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchsink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackend struct {
+	sync.Mutex
+	pending int
+	rows    int
+	batches int
+	err     error
+	closed  bool
+}
+
+func (m *mockBackend) Append(txn *model.SingleTableTxn) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.pending += len(txn.Rows)
+	return len(txn.Rows), nil
+}
+
+func (m *mockBackend) Flush(_ context.Context) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+	if m.err != nil {
+		return 0, m.err
+	}
+	rows := m.pending
+	m.pending = 0
+	m.rows += rows
+	m.batches++
+	return rows, nil
+}
+
+func (m *mockBackend) Close() error {
+	m.Lock()
+	defer m.Unlock()
+	m.closed = true
+	return nil
+}
+
+func newTestRows(commitTs uint64) []*model.RowChangedEvent {
+	table := &model.TableName{Schema: "test", Table: "t"}
+	return []*model.RowChangedEvent{
+		{CommitTs: commitTs, Table: table, Columns: []*model.Column{{Name: "id", Value: 1}}},
+		{CommitTs: commitTs, Table: table, Columns: []*model.Column{{Name: "id", Value: 2}}},
+	}
+}
+
+func TestWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backends := []*mockBackend{{}, {}}
+	errCh := make(chan error, 1)
+	s := New(ctx, model.DefaultChangeFeedID("test"), "test",
+		[]Backend{backends[0], backends[1]}, 4, errCh)
+
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	require.NoError(t, s.WriteEvents(NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&cnt) == 10
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rows := 0
+	for _, backend := range backends {
+		backend.Lock()
+		rows += backend.rows
+		// a batch contains 2 transactions at most.
+		require.True(t, backend.rows == 0 || backend.batches >= 5)
+		backend.Unlock()
+	}
+	require.Equal(t, 20, rows)
+
+	// the events of the stopping tables are dropped.
+	tableStatus.Store(state.TableSinkStopping)
+	require.NoError(t, s.WriteEvents(NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+	require.Equal(t, uint64(20), atomic.LoadUint64(&cnt))
+
+	// the backends are closed with the sink.
+	s.Close()
+	for _, backend := range backends {
+		require.True(t, backend.closed)
+	}
+}
+
+func TestWriteEventsError(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := &mockBackend{err: errors.New("connection refused")}
+	errCh := make(chan error, 1)
+	s := New(ctx, model.DefaultChangeFeedID("test"), "test", []Backend{backend}, 4, errCh)
+	defer s.Close()
+
+	// the callbacks aren't called if the batches fail to be flushed.
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	require.NoError(t, s.WriteEvents(NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+	select {
+	case err := <-errCh:
+		require.ErrorContains(t, err, "connection refused")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error is not reported")
+	}
+	<-s.Dead()
+	require.Error(t, s.WriteEvents(NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+	require.Zero(t, atomic.LoadUint64(&cnt))
+}
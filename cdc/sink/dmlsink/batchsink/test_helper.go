@@ -0,0 +1,50 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchsink
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+)
+
+// NewTestTxns returns 10 transactions whose rows are generated by newRows with
+// the commit ts, cnt is increased when any of them is called back. The table
+// of the transactions is the one of the first row. It's only used for testing.
+func NewTestTxns(
+	cnt *uint64,
+	tableStatus *state.TableSinkState,
+	newRows func(commitTs uint64) []*model.RowChangedEvent,
+) []*dmlsink.TxnCallbackableEvent {
+	txns := make([]*dmlsink.TxnCallbackableEvent, 0, 10)
+	for i := 0; i < 10; i++ {
+		commitTs := uint64(100 + i)
+		rows := newRows(commitTs)
+		txns = append(txns, &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				CommitTs:  commitTs,
+				Table:     rows[0].Table,
+				TableInfo: rows[0].TableInfo,
+				Rows:      rows,
+			},
+			Callback: func() {
+				atomic.AddUint64(cnt, 1)
+			},
+			SinkState: tableStatus,
+		})
+	}
+	return txns
+}
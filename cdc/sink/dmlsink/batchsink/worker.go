@@ -0,0 +1,152 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchsink
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"go.uber.org/zap"
+)
+
+// worker writes the transactions of some tables by its backend. The ready
+// transactions are collected into a batch until the batch is full.
+type worker struct {
+	id           int
+	changefeedID model.ChangeFeedID
+	name         string
+	backend      Backend
+	maxBatchRows int
+	inputCh      *chann.DrainableChann[*dmlsink.TxnCallbackableEvent]
+	statistics   *metrics.Statistics
+	isClosed     uint64
+}
+
+func newWorker(
+	id int,
+	changefeedID model.ChangeFeedID,
+	name string,
+	backend Backend,
+	maxBatchRows int,
+	inputCh *chann.DrainableChann[*dmlsink.TxnCallbackableEvent],
+	statistics *metrics.Statistics,
+) *worker {
+	return &worker{
+		id:           id,
+		changefeedID: changefeedID,
+		name:         name,
+		backend:      backend,
+		maxBatchRows: maxBatchRows,
+		inputCh:      inputCh,
+		statistics:   statistics,
+	}
+}
+
+func (w *worker) run(ctx context.Context) error {
+	log.Info("batch sink worker started",
+		zap.String("sink", w.name),
+		zap.Int("workerID", w.id),
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case txn, ok := <-w.inputCh.Out():
+			if !ok {
+				return nil
+			}
+			size, err := w.backend.Append(txn.Event)
+			if err != nil {
+				return err
+			}
+			callbacks := []func(){txn.Callback}
+			// collect the ready transactions until the batch is full.
+		collect:
+			for size < w.maxBatchRows {
+				select {
+				case txn, ok := <-w.inputCh.Out():
+					if !ok {
+						break collect
+					}
+					n, err := w.backend.Append(txn.Event)
+					if err != nil {
+						return err
+					}
+					size += n
+					callbacks = append(callbacks, txn.Callback)
+				default:
+					break collect
+				}
+			}
+			if err := w.flush(ctx, callbacks); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *worker) flush(ctx context.Context, callbacks []func()) error {
+	start := time.Now()
+	rows := 0
+	err := w.statistics.RecordBatchExecution(func() (int, error) {
+		var err error
+		rows, err = w.backend.Flush(ctx)
+		return rows, err
+	})
+	if err != nil {
+		log.Error("failed to write rows by batch sink",
+			zap.String("sink", w.name),
+			zap.Int("workerID", w.id),
+			zap.String("namespace", w.changefeedID.Namespace),
+			zap.String("changefeed", w.changefeedID.ID),
+			zap.Error(err))
+		return err
+	}
+	log.Debug("write rows by batch sink success",
+		zap.String("sink", w.name),
+		zap.Int("workerID", w.id),
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.Int("rows", rows),
+		zap.Duration("duration", time.Since(start)))
+
+	for _, callback := range callbacks {
+		callback()
+	}
+	return nil
+}
+
+func (w *worker) close() {
+	if !atomic.CompareAndSwapUint64(&w.isClosed, 0, 1) {
+		return
+	}
+	w.inputCh.CloseAndDrain()
+	if err := w.backend.Close(); err != nil {
+		log.Warn("failed to close the backend of batch sink",
+			zap.String("sink", w.name),
+			zap.Int("workerID", w.id),
+			zap.String("namespace", w.changefeedID.Namespace),
+			zap.String("changefeed", w.changefeedID.ID),
+			zap.Error(err))
+	}
+}
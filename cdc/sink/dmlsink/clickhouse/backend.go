@@ -0,0 +1,185 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+)
+
+const (
+	signInsert = 1
+	signDelete = -1
+)
+
+// executor executes the statements in ClickHouse.
+type executor interface {
+	Exec(ctx context.Context, query string, data []byte) error
+}
+
+// Assert Backend implementation
+var _ batchsink.Backend = (*backend)(nil)
+
+// backend writes the batches into ClickHouse, the rows of each table in a
+// batch are written by an INSERT statement in the JSONEachRow format.
+type backend struct {
+	config *pclickhouse.Config
+	client executor
+
+	tables map[model.TableName]*tableBatch
+	order  []*tableBatch
+	rows   int
+}
+
+// tableBatch is the encoded rows of a table in a batch.
+type tableBatch struct {
+	table model.TableName
+	rows  int
+	data  bytes.Buffer
+}
+
+func newBackend(config *pclickhouse.Config, client executor) *backend {
+	return &backend{
+		config: config,
+		client: client,
+		tables: make(map[model.TableName]*tableBatch),
+	}
+}
+
+// Append implements batchsink.Backend.
+func (b *backend) Append(txn *model.SingleTableTxn) (int, error) {
+	table := *txn.Table
+	tb, ok := b.tables[table]
+	if !ok {
+		tb = &tableBatch{table: table}
+		b.tables[table] = tb
+		b.order = append(b.order, tb)
+	}
+	size := 0
+	for _, row := range txn.Rows {
+		n, err := b.encodeRow(&tb.data, row)
+		if err != nil {
+			return 0, err
+		}
+		tb.rows += n
+		size += n
+	}
+	b.rows += size
+	return size, nil
+}
+
+// encodeRow encodes the row change into the rows of ClickHouse, the commit ts
+// is used as the version, and the sign is -1 for the rows which are deleted.
+// It returns the number of the encoded rows.
+func (b *backend) encodeRow(buf *bytes.Buffer, row *model.RowChangedEvent) (int, error) {
+	switch {
+	case row.IsDelete():
+		return 1, b.encodeColumns(buf, row.PreColumns, row.CommitTs, signDelete)
+	case row.IsUpdate() && (b.config.Engine == pclickhouse.EngineCollapsingMergeTree ||
+		row.IsHandleKeyUpdated()):
+		// the old row must be cancelled explicitly before it collapses, and
+		// the row of the old handle key is never replaced by the new one.
+		if err := b.encodeColumns(buf, row.PreColumns, row.CommitTs, signDelete); err != nil {
+			return 0, err
+		}
+		return 2, b.encodeColumns(buf, row.Columns, row.CommitTs, signInsert)
+	default:
+		return 1, b.encodeColumns(buf, row.Columns, row.CommitTs, signInsert)
+	}
+}
+
+// encodeColumns encodes the columns as a JSON object. The values of the binary
+// columns are written as they are, see quoteBinary.
+func (b *backend) encodeColumns(
+	buf *bytes.Buffer, cols []*model.Column, version uint64, sign int,
+) error {
+	values := make(map[string]interface{}, len(cols)+2)
+	for _, col := range cols {
+		// the null columns are filled with the default values by ClickHouse.
+		if col == nil || col.Value == nil {
+			continue
+		}
+		v, ok := col.Value.([]byte)
+		switch {
+		case ok && col.Flag.IsBinary():
+			values[col.Name] = quoteBinary(v)
+		case ok:
+			values[col.Name] = string(v)
+		default:
+			values[col.Name] = col.Value
+		}
+	}
+	values[b.config.VersionColumn] = version
+	values[b.config.SignColumn] = sign
+	data, err := json.Marshal(values)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// quoteBinary quotes the bytes as a JSON string. Unlike json.Marshal, the
+// invalid UTF-8 bytes are not replaced, ClickHouse reads the bytes of JSON
+// strings as they are, so the binary values are inserted without any change.
+func quoteBinary(v []byte) json.RawMessage {
+	buf := make([]byte, 0, len(v)+2)
+	buf = append(buf, '"')
+	for _, c := range v {
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20:
+			buf = append(buf, fmt.Sprintf(`\u%04x`, c)...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
+
+// Flush implements batchsink.Backend.
+func (b *backend) Flush(ctx context.Context) (int, error) {
+	defer b.reset()
+	for _, tb := range b.order {
+		if tb.rows == 0 {
+			continue
+		}
+		query := fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow",
+			pclickhouse.QuoteTable(tb.table.Schema, tb.table.Table))
+		if err := b.client.Exec(ctx, query, tb.data.Bytes()); err != nil {
+			return 0, errors.Annotatef(err, "write rows into table %s", tb.table.String())
+		}
+	}
+	return b.rows, nil
+}
+
+func (b *backend) reset() {
+	b.tables = make(map[model.TableName]*tableBatch)
+	b.order = b.order[:0]
+	b.rows = 0
+}
+
+// Close implements batchsink.Backend.
+func (b *backend) Close() error {
+	return nil
+}
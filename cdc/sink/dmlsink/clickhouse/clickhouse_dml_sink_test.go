@@ -0,0 +1,259 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	pclickhouse "github.com/pingcap/tiflow/pkg/sink/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	sync.Mutex
+	queries []string
+	data    []string
+	err     error
+}
+
+func (m *mockExecutor) Exec(_ context.Context, query string, data []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.queries = append(m.queries, query)
+	m.data = append(m.data, string(data))
+	return nil
+}
+
+func newTestRows(commitTs uint64) []*model.RowChangedEvent {
+	table := &model.TableName{Schema: "test", Table: "t"}
+	return []*model.RowChangedEvent{
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			Columns: []*model.Column{
+				{Name: "id", Value: 1},
+				{Name: "name", Value: []byte("a")},
+				{Name: "age", Value: nil},
+			},
+		},
+		{
+			CommitTs:   commitTs,
+			Table:      table,
+			PreColumns: []*model.Column{{Name: "id", Value: 2}, {Name: "name", Value: "b"}},
+			Columns:    []*model.Column{{Name: "id", Value: 2}, {Name: "name", Value: "c"}},
+		},
+		{
+			CommitTs:   commitTs,
+			Table:      table,
+			PreColumns: []*model.Column{{Name: "id", Value: 3}, {Name: "name", Value: "d"}},
+		},
+	}
+}
+
+func TestEncodeRows(t *testing.T) {
+	t.Parallel()
+
+	cfg := pclickhouse.NewConfig()
+	b := newBackend(cfg, nil)
+	var buf bytes.Buffer
+	rows := 0
+	for _, row := range newTestRows(100) {
+		n, err := b.encodeRow(&buf, row)
+		require.NoError(t, err)
+		rows += n
+	}
+	require.Equal(t, 3, rows)
+	require.Equal(t, `{"_sign":1,"_version":100,"id":1,"name":"a"}
+{"_sign":1,"_version":100,"id":2,"name":"c"}
+{"_sign":-1,"_version":100,"id":3,"name":"d"}
+`, buf.String())
+
+	// the old rows of the updates are cancelled in the collapsing engine.
+	cfg = pclickhouse.NewConfig()
+	cfg.Engine = pclickhouse.EngineCollapsingMergeTree
+	cfg.SignColumn = "sign"
+	b = newBackend(cfg, nil)
+	buf.Reset()
+	rows = 0
+	for _, row := range newTestRows(100) {
+		n, err := b.encodeRow(&buf, row)
+		require.NoError(t, err)
+		rows += n
+	}
+	require.Equal(t, 4, rows)
+	require.Equal(t, `{"_version":100,"id":1,"name":"a","sign":1}
+{"_version":100,"id":2,"name":"b","sign":-1}
+{"_version":100,"id":2,"name":"c","sign":1}
+{"_version":100,"id":3,"name":"d","sign":-1}
+`, buf.String())
+}
+
+func TestEncodeBinaryColumns(t *testing.T) {
+	t.Parallel()
+
+	b := newBackend(pclickhouse.NewConfig(), nil)
+	var buf bytes.Buffer
+	_, err := b.encodeRow(&buf, &model.RowChangedEvent{
+		CommitTs: 100,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		Columns: []*model.Column{
+			{Name: "id", Value: 1},
+			{Name: "data", Value: []byte{0xff, 0xfe, 0x00, '"', 'a'}, Flag: model.BinaryFlag},
+			{Name: "name", Value: []byte("a")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "{\"_sign\":1,\"_version\":100,\"data\":\"\xff\xfe\\u0000\\\"a\",\"id\":1,\"name\":\"a\"}\n",
+		buf.String())
+}
+
+// readJSONString reads the JSON string as ClickHouse does, the bytes which
+// are not escaped are kept as they are.
+func readJSONString(t *testing.T, quoted []byte) []byte {
+	require.True(t, len(quoted) >= 2 && quoted[0] == '"' && quoted[len(quoted)-1] == '"')
+	quoted = quoted[1 : len(quoted)-1]
+	result := make([]byte, 0, len(quoted))
+	for i := 0; i < len(quoted); i++ {
+		if quoted[i] != '\\' {
+			result = append(result, quoted[i])
+			continue
+		}
+		i++
+		switch quoted[i] {
+		case 'b':
+			result = append(result, '\b')
+		case 'f':
+			result = append(result, '\f')
+		case 'n':
+			result = append(result, '\n')
+		case 'r':
+			result = append(result, '\r')
+		case 't':
+			result = append(result, '\t')
+		case 'u':
+			code, err := strconv.ParseUint(string(quoted[i+1:i+5]), 16, 16)
+			require.NoError(t, err)
+			result = utf8.AppendRune(result, rune(code))
+			i += 4
+		default:
+			result = append(result, quoted[i])
+		}
+	}
+	return result
+}
+
+func TestBinaryColumnsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// all the bytes, and the sequences escaped by json.Marshal.
+	values := [][]byte{{}, []byte("<a&b>"), {0xe2, 0x80, 0xa8}, {0xc3, 0x28}}
+	all := make([]byte, 0, 256)
+	for i := 0; i < 256; i++ {
+		all = append(all, byte(i))
+	}
+	values = append(values, all)
+
+	b := newBackend(pclickhouse.NewConfig(), nil)
+	for _, value := range values {
+		var buf bytes.Buffer
+		_, err := b.encodeRow(&buf, &model.RowChangedEvent{
+			CommitTs: 100,
+			Table:    &model.TableName{Schema: "test", Table: "t"},
+			Columns: []*model.Column{
+				{Name: "data", Value: value, Flag: model.BinaryFlag},
+			},
+		})
+		require.NoError(t, err)
+
+		var row map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &row))
+		require.Equal(t, value, readJSONString(t, row["data"]))
+	}
+}
+
+func TestEncodeHandleKeyUpdate(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		CommitTs: 100,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		PreColumns: []*model.Column{
+			{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Value: "a"},
+		},
+		Columns: []*model.Column{
+			{Name: "id", Value: 2, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Value: "a"},
+		},
+	}
+	for _, engine := range []string{
+		pclickhouse.EngineReplacingMergeTree, pclickhouse.EngineCollapsingMergeTree,
+	} {
+		cfg := pclickhouse.NewConfig()
+		cfg.Engine = engine
+		b := newBackend(cfg, nil)
+		var buf bytes.Buffer
+		n, err := b.encodeRow(&buf, row)
+		require.NoError(t, err)
+		require.Equal(t, 2, n, engine)
+		require.Equal(t, `{"_sign":-1,"_version":100,"id":1,"name":"a"}
+{"_sign":1,"_version":100,"id":2,"name":"a"}
+`, buf.String(), engine)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	t.Parallel()
+
+	client := &mockExecutor{}
+	b := newBackend(pclickhouse.NewConfig(), client)
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	for _, txn := range batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows) {
+		n, err := b.Append(txn.Event)
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+	}
+	rows, err := b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 30, rows)
+	require.Equal(t, []string{"INSERT INTO `test`.`t` FORMAT JSONEachRow"}, client.queries)
+	require.Equal(t, 30, strings.Count(client.data[0], "\n"))
+
+	// the batch is reset after it's flushed.
+	rows, err = b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, rows)
+	require.Len(t, client.queries, 1)
+
+	client.err = errors.New("table test.t doesn't exist")
+	_, err = b.Append(batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows)[0].Event)
+	require.NoError(t, err)
+	_, err = b.Flush(context.Background())
+	require.ErrorContains(t, err, "doesn't exist")
+}
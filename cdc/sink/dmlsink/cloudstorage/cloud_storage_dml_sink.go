@@ -15,15 +15,18 @@ package cloudstorage
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"net/url"
 	"sync"
 	"sync/atomic"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	mcloudstorage "github.com/pingcap/tiflow/cdc/sink/metrics/cloudstorage"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/engine/pkg/clock"
@@ -34,6 +37,10 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
 	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/csv"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
+	"github.com/pingcap/tiflow/pkg/sink/deltalake"
+	"github.com/pingcap/tiflow/pkg/sink/redshift"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"golang.org/x/sync/errgroup"
 )
@@ -41,6 +48,10 @@ import (
 const (
 	defaultEncodingConcurrency = 8
 	defaultChannelSize         = 1024
+
+	// preFlightProbeFilePrefix is the prefix of the file which is written to
+	// check whether the storage is writable before the changefeed is created.
+	preFlightProbeFilePrefix = ".pre-flight-probe-"
 )
 
 // Assert EventSink[E event.TableEvent] implementation
@@ -74,6 +85,8 @@ type DMLSink struct {
 	defragmenter *defragmenter
 	// workers defines a group of workers for writing events to external storage.
 	workers []*dmlWorker
+	// storage is the external storage which the data files are written to.
+	storage storage.ExternalStorage
 
 	alive struct {
 		sync.RWMutex
@@ -104,7 +117,7 @@ func NewDMLSink(ctx context.Context,
 	}
 
 	// create an external storage.
-	storage, err := putil.GetExternalStorageFromURI(ctx, sinkURI.String())
+	storage, err := cloudstorage.GetExternalStorage(ctx, changefeedID, sinkURI)
 	if err != nil {
 		return nil, err
 	}
@@ -117,8 +130,9 @@ func NewDMLSink(ctx context.Context,
 		return nil, errors.Trace(err)
 	}
 
-	// get cloud storage file extension according to the specific protocol.
-	ext := util.GetFileExtension(protocol)
+	// get cloud storage file extension according to the specific protocol,
+	// the compressed data files have an additional suffix, such as ".csv.gz".
+	ext := util.GetFileExtension(protocol) + cloudstorage.CompressionExtension(cfg.Compression)
 	// the last param maxMsgBytes is mainly to limit the size of a single message for
 	// batch protocols in mq scenario. In cloud storage sink, we just set it to max int.
 	encoderConfig, err := util.GetEncoderConfig(sinkURI, protocol, replicaConfig, math.MaxInt)
@@ -131,11 +145,14 @@ func NewDMLSink(ctx context.Context,
 	}
 
 	wgCtx, wgCancel := context.WithCancel(ctx)
+	statistics := metrics.NewStatistics(wgCtx, changefeedID, sink.TxnSink).
+		WithTableMetrics(replicaConfig.Sink.TableMetrics)
 	s := &DMLSink{
 		changefeedID:    changefeedID,
 		encodingWorkers: make([]*encodingWorker, defaultEncodingConcurrency),
 		workers:         make([]*dmlWorker, cfg.WorkerCount),
-		statistics:      metrics.NewStatistics(wgCtx, changefeedID, sink.TxnSink),
+		storage:         storage,
+		statistics:      statistics,
 		cancel:          wgCancel,
 		dead:            make(chan struct{}),
 	}
@@ -151,12 +168,42 @@ func NewDMLSink(ctx context.Context,
 	}
 	// create defragmenter.
 	s.defragmenter = newDefragmenter(encodedCh, workerChannels)
+	// the header row is only available for the csv protocol.
+	var encodeHeader func(tableInfo *model.TableInfo, fileIndex uint64) []byte
+	if protocol == config.ProtocolCsv && encoderConfig.OutputHeader {
+		encodeHeader = newCSVHeaderEncoder(encoderConfig)
+	}
+	// the parquet file is built from all the rows of a data file at once.
+	var encodeFile func(tableInfo *model.TableInfo, msgs []*common.Message) ([]byte, error)
+	if protocol == config.ProtocolParquet {
+		fileEncoder, err := parquet.NewFileEncoder(encoderConfig, cfg.ParquetCompression, cfg.ParquetRowGroupSize)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+		}
+		encodeFile = fileEncoder.Encode
+	}
+	// the data files are loaded into redshift after they're written if it's configured.
+	var loader dataFileLoader
+	if replicaConfig.Sink.CloudStorageConfig != nil &&
+		replicaConfig.Sink.CloudStorageConfig.RedshiftConfig != nil {
+		loader, err = redshift.NewLoader(changefeedID, sinkURI,
+			replicaConfig.Sink.CloudStorageConfig.RedshiftConfig, encoderConfig, cfg.Compression)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+		}
+	}
+	// the data files are committed to the delta lake tables if it's enabled,
+	// the committer is shared by the workers to serialize the commits of a table.
+	if replicaConfig.Sink.CloudStorageConfig != nil &&
+		putil.GetOrZero(replicaConfig.Sink.CloudStorageConfig.EnableDeltaLake) {
+		loader = deltalake.NewCommitter(changefeedID, storage, encoderConfig)
+	}
 	// create a group of dml workers.
 	clock := clock.New()
 	for i := 0; i < cfg.WorkerCount; i++ {
 		inputCh := chann.NewAutoDrainChann[eventFragment]()
 		s.workers[i] = newDMLWorker(i, s.changefeedID, storage, cfg, ext,
-			inputCh, clock, s.statistics)
+			inputCh, clock, s.statistics, encodeHeader, encodeFile, loader)
 		workerChannels[i] = inputCh
 	}
 
@@ -243,6 +290,20 @@ func (s *DMLSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTa
 	return nil
 }
 
+// PreFlight writes and deletes a probe file, so the changefeed fails to be
+// created if the storage can't be written, instead of failing when the first
+// data file is flushed.
+func (s *DMLSink) PreFlight(ctx context.Context) error {
+	name := fmt.Sprintf("%s%s-%s", preFlightProbeFilePrefix,
+		s.changefeedID.Namespace, s.changefeedID.ID)
+	if err := putil.ProbeExternalStorage(ctx, s.storage, name); err != nil {
+		return cerror.WrapError(cerror.ErrSinkPreFlightFailed, err,
+			fmt.Sprintf("storage %s isn't writable, please check the bucket, "+
+				"the path and the write permission of the credentials", s.storage.URI()))
+	}
+	return nil
+}
+
 // Close closes the cloud storage sink.
 func (s *DMLSink) Close() {
 	if s.cancel != nil {
@@ -261,9 +322,25 @@ func (s *DMLSink) Close() {
 	if s.statistics != nil {
 		s.statistics.Close()
 	}
+	mcloudstorage.CloudStorageDiskAvailableBytesGauge.
+		DeleteLabelValues(s.changefeedID.Namespace, s.changefeedID.ID)
 }
 
 // Dead checks whether it's dead or not.
 func (s *DMLSink) Dead() <-chan struct{} {
 	return s.dead
 }
+
+// newCSVHeaderEncoder returns the function which encodes the header row of the
+// csv data files, the header is encoded from the table info of the data file,
+// so it's refreshed after the schema of the table changes.
+func newCSVHeaderEncoder(
+	encoderConfig *common.Config,
+) func(tableInfo *model.TableInfo, fileIndex uint64) []byte {
+	return func(tableInfo *model.TableInfo, fileIndex uint64) []byte {
+		if !encoderConfig.FileHasHeader(fileIndex) {
+			return nil
+		}
+		return csv.EncodeHeader(encoderConfig, tableInfo)
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
@@ -298,3 +300,38 @@ func TestCloudStorageWriteEventsWithDateSeparator(t *testing.T) {
 	cancel()
 	s.Close()
 }
+
+type unwritableStorage struct {
+	storage.ExternalStorage
+}
+
+func (s *unwritableStorage) WriteFile(context.Context, string, []byte) error {
+	return errors.New("permission denied")
+}
+
+func TestCloudStoragePreFlight(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parentDir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("file:///%s?flush-interval=2s", parentDir))
+	require.Nil(t, err)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = util.AddressOf(config.ProtocolCsv.String())
+	s, err := NewDMLSink(ctx,
+		model.DefaultChangeFeedID("test"), sinkURI, replicaConfig, make(chan error, 1))
+	require.Nil(t, err)
+	defer s.Close()
+
+	require.Nil(t, s.PreFlight(ctx))
+	// the probe file is deleted after the check.
+	files, err := os.ReadDir(parentDir)
+	require.Nil(t, err)
+	require.Len(t, files, 0)
+
+	s.storage = &unwritableStorage{ExternalStorage: s.storage}
+	err = s.PreFlight(ctx)
+	require.Regexp(t, ".*ErrSinkPreFlightFailed.*isn't writable.*permission denied.*", err)
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -49,6 +50,40 @@ type dmlWorker struct {
 	filePathGenerator *cloudstorage.FilePathGenerator
 	metricWriteBytes  prometheus.Gauge
 	metricFileCount   prometheus.Gauge
+
+	// encodeHeader returns the header written at the beginning of the data file
+	// with the given file index, it's nil if no header is required.
+	encodeHeader func(tableInfo *model.TableInfo, fileIndex uint64) []byte
+	// encodeFile encodes all the messages of a data file at once, it's used by
+	// the file formats which can't be built by concatenating the messages.
+	encodeFile func(tableInfo *model.TableInfo, msgs []*common.Message) ([]byte, error)
+	// loader loads the data files into the downstream warehouse after they're
+	// written, the events are acknowledged after they're loaded. It's nil if
+	// the data files are not loaded.
+	loader dataFileLoader
+
+	// tableOverrides caches the matched table override of each table, the value
+	// is nil if the table doesn't match any one. It's only accessed by the
+	// goroutine which dispatches the flush tasks.
+	tableOverrides map[model.TableName]*cloudstorage.TableOverride
+
+	// openPartitions records the max commit ts of the date partitions which
+	// are written but not marked as complete yet. It's only accessed by the
+	// goroutine which flushes the messages.
+	openPartitions map[datePartition]uint64
+}
+
+// datePartition is the data directory of a table at a date.
+type datePartition struct {
+	table cloudstorage.VersionedTableName
+	date  string
+}
+
+// dataFileLoader loads the data files written to the external storage into
+// the downstream, filePath is relative to the sink URI and size is the size
+// of the data file in bytes.
+type dataFileLoader interface {
+	Load(ctx context.Context, tableInfo *model.TableInfo, filePath string, size int64) error
 }
 
 // dmlTask defines a task containing the tables to be flushed.
@@ -57,9 +92,18 @@ type dmlTask struct {
 }
 
 type singleTableTask struct {
-	size      uint64
-	tableInfo *model.TableInfo
-	msgs      []*common.Message
+	size        uint64
+	tableInfo   *model.TableInfo
+	msgs        []*common.Message
+	minCommitTs uint64
+	maxCommitTs uint64
+}
+
+// flushManifest collects the data files written by a flush, the events of
+// them are acknowledged after the manifest file is written.
+type flushManifest struct {
+	manifest  cloudstorage.Manifest
+	callbacks []func()
 }
 
 func newDMLTask() dmlTask {
@@ -78,6 +122,13 @@ func (t *dmlTask) handleSingleTableEvent(event eventFragment) {
 	}
 
 	v := t.tasks[table]
+	commitTs := event.event.Event.GetCommitTs()
+	if v.minCommitTs == 0 || commitTs < v.minCommitTs {
+		v.minCommitTs = commitTs
+	}
+	if commitTs > v.maxCommitTs {
+		v.maxCommitTs = commitTs
+	}
 	for _, msg := range event.encodedMsgs {
 		v.size += uint64(len(msg.Value))
 	}
@@ -105,6 +156,9 @@ func newDMLWorker(
 	inputCh *chann.DrainableChann[eventFragment],
 	clock clock.Clock,
 	statistics *metrics.Statistics,
+	encodeHeader func(tableInfo *model.TableInfo, fileIndex uint64) []byte,
+	encodeFile func(tableInfo *model.TableInfo, msgs []*common.Message) ([]byte, error),
+	loader dataFileLoader,
 ) *dmlWorker {
 	d := &dmlWorker{
 		id:                id,
@@ -115,6 +169,11 @@ func newDMLWorker(
 		flushNotifyCh:     make(chan dmlTask, 64),
 		statistics:        statistics,
 		filePathGenerator: cloudstorage.NewFilePathGenerator(config, storage, extension, clock),
+		encodeHeader:      encodeHeader,
+		encodeFile:        encodeFile,
+		loader:            loader,
+		tableOverrides:    make(map[model.TableName]*cloudstorage.TableOverride),
+		openPartitions:    make(map[datePartition]uint64),
 		metricWriteBytes: mcloudstorage.CloudStorageWriteBytesGauge.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricFileCount: mcloudstorage.CloudStorageFileCountGauge.
@@ -153,6 +212,10 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 			if atomic.LoadUint64(&d.isClosed) == 1 {
 				return nil
 			}
+			var manifest *flushManifest
+			if d.config.OutputManifest {
+				manifest = &flushManifest{}
+			}
 			for table, task := range task.tasks {
 				if len(task.msgs) == 0 {
 					continue
@@ -168,6 +231,15 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 						zap.Error(err))
 					return errors.Trace(err)
 				}
+				err = d.filePathGenerator.CheckOrWriteSchemaSidecar(ctx, table, task.tableInfo)
+				if err != nil {
+					log.Error("failed to write sidecar schema file to external storage",
+						zap.Int("workerID", d.id),
+						zap.String("namespace", d.changeFeedID.Namespace),
+						zap.String("changefeed", d.changeFeedID.ID),
+						zap.Error(err))
+					return errors.Trace(err)
+				}
 
 				// make sure that `generateDateStr()` is invoked ONLY once before
 				// generating data file path and index file path. Because we don't expect the index
@@ -198,7 +270,7 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 				}
 
 				// then write the data file to external storage.
-				err = d.writeDataFile(ctx, dataFilePath, task)
+				err = d.writeDataFile(ctx, dataFilePath, table, task, manifest)
 				if err != nil {
 					log.Error("failed to write data file to external storage",
 						zap.Int("workerID", d.id),
@@ -208,6 +280,12 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 						zap.Error(err))
 					return errors.Trace(err)
 				}
+				if d.config.OutputCompleteMarker {
+					partition := datePartition{table: table, date: date}
+					if task.maxCommitTs > d.openPartitions[partition] {
+						d.openPartitions[partition] = task.maxCommitTs
+					}
+				}
 
 				log.Debug("write file to storage success", zap.Int("workerID", d.id),
 					zap.String("namespace", d.changeFeedID.Namespace),
@@ -217,8 +295,76 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 					zap.String("path", dataFilePath),
 				)
 			}
+			if manifest != nil {
+				if err := d.writeManifestFile(ctx, manifest); err != nil {
+					return err
+				}
+			}
+			if err := d.writeCompleteMarkers(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeCompleteMarkers writes the complete marker files to the partitions of
+// the past dates, since the data files are only written to the partitions of
+// the current date, all the data files of them have been flushed.
+func (d *dmlWorker) writeCompleteMarkers(ctx context.Context) error {
+	if len(d.openPartitions) == 0 {
+		return nil
+	}
+	currDate := d.filePathGenerator.GenerateDateStr()
+	for partition, maxCommitTs := range d.openPartitions {
+		if partition.date == currDate {
+			continue
+		}
+		marker := cloudstorage.CompleteMarker{MaxCommitTs: maxCommitTs}
+		data, err := marker.Marshal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		markerPath := d.filePathGenerator.GenerateCompleteMarkerPath(partition.table, partition.date)
+		if err := d.storage.WriteFile(ctx, markerPath, data); err != nil {
+			log.Error("failed to write complete marker file to external storage",
+				zap.Int("workerID", d.id),
+				zap.String("namespace", d.changeFeedID.Namespace),
+				zap.String("changefeed", d.changeFeedID.ID),
+				zap.String("path", markerPath),
+				zap.Error(err))
+			return errors.Trace(err)
+		}
+		delete(d.openPartitions, partition)
+	}
+	return nil
+}
+
+// writeManifestFile writes the manifest file of a flush, and then acknowledges
+// the events of the data files listed in it.
+func (d *dmlWorker) writeManifestFile(ctx context.Context, m *flushManifest) error {
+	if len(m.manifest.Files) == 0 {
+		return nil
+	}
+	manifestFilePath := d.filePathGenerator.GenerateManifestFilePath(d.id)
+	data, err := m.manifest.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.storage.WriteFile(ctx, manifestFilePath, data); err != nil {
+		log.Error("failed to write manifest file to external storage",
+			zap.Int("workerID", d.id),
+			zap.String("namespace", d.changeFeedID.Namespace),
+			zap.String("changefeed", d.changeFeedID.ID),
+			zap.String("path", manifestFilePath),
+			zap.Error(err))
+		return errors.Trace(err)
+	}
+	for _, cb := range m.callbacks {
+		if cb != nil {
+			cb()
 		}
 	}
+	return nil
 }
 
 func (d *dmlWorker) writeIndexFile(ctx context.Context, path, content string) error {
@@ -226,19 +372,54 @@ func (d *dmlWorker) writeIndexFile(ctx context.Context, path, content string) er
 	return err
 }
 
-func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *singleTableTask) error {
+// writeDataFile writes the data file of the table. The events are acknowledged
+// after the file is written, or they're acknowledged after the manifest file
+// is written if the manifest is not nil.
+func (d *dmlWorker) writeDataFile(
+	ctx context.Context, path string,
+	table cloudstorage.VersionedTableName,
+	task *singleTableTask, manifest *flushManifest,
+) error {
 	var callbacks []func()
 	buf := bytes.NewBuffer(make([]byte, 0, task.size))
+	if d.encodeHeader != nil {
+		buf.Write(d.encodeHeader(task.tableInfo, d.filePathGenerator.FileIndex(table)))
+	}
 	rowsCnt := 0
 	for _, msg := range task.msgs {
-		d.metricWriteBytes.Add(float64(len(msg.Value)))
 		rowsCnt += msg.GetRowsCount()
-		buf.Write(msg.Value)
+		if d.encodeFile == nil {
+			d.metricWriteBytes.Add(float64(len(msg.Value)))
+			buf.Write(msg.Value)
+		}
 		callbacks = append(callbacks, msg.Callback)
 	}
+	if d.encodeFile != nil {
+		data, err := d.encodeFile(task.tableInfo, task.msgs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.metricWriteBytes.Add(float64(len(data)))
+		buf = bytes.NewBuffer(data)
+	}
+	data, err := cloudstorage.Compress(d.config.Compression, buf.Bytes())
+	if err != nil {
+		return errors.Trace(err)
+	}
 
-	if err := d.statistics.RecordBatchExecution(func() (int, error) {
-		err := d.storage.WriteFile(ctx, path, buf.Bytes())
+	tableName := task.tableInfo.TableName
+	if err := d.statistics.RecordTableExecution(tableName.Schema, tableName.Table, func() (int, error) {
+		failpoint.Inject("CloudStorageSinkWriteDataFileError", func() {
+			// simulate the data file is not written, the table sink should
+			// be recovered without losing the events in the file.
+			log.Info("CloudStorageSinkWriteDataFileError error injected",
+				zap.Int("workerID", d.id),
+				zap.String("namespace", d.changeFeedID.Namespace),
+				zap.String("changefeed", d.changeFeedID.ID),
+				zap.String("path", path))
+			failpoint.Return(0, errors.New("cloud storage sink injected error"))
+		})
+		err := d.storage.WriteFile(ctx, path, data)
 		if err != nil {
 			return 0, err
 		}
@@ -246,8 +427,18 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *single
 	}); err != nil {
 		return err
 	}
+	if d.loader != nil {
+		if err := d.loader.Load(ctx, task.tableInfo, path, int64(len(data))); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	d.metricFileCount.Add(1)
+	if manifest != nil {
+		manifest.manifest.AddFile(path, table, data, rowsCnt, task.minCommitTs, task.maxCommitTs)
+		manifest.callbacks = append(manifest.callbacks, callbacks...)
+		return nil
+	}
 	for _, cb := range callbacks {
 		if cb != nil {
 			cb()
@@ -257,14 +448,109 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *single
 	return nil
 }
 
+// tableOverride returns the table override of the table, nil is returned if
+// there is no matched one.
+func (d *dmlWorker) tableOverride(
+	table cloudstorage.VersionedTableName,
+) *cloudstorage.TableOverride {
+	name := table.TableNameWithPhysicTableID
+	override, ok := d.tableOverrides[name]
+	if !ok {
+		override = d.config.MatchTableOverride(name.Schema, name.Table)
+		d.tableOverrides[name] = override
+	}
+	return override
+}
+
+// flushIntervalOverride returns the table override which overrides the flush
+// interval of the table, nil is returned if the global flush interval is used.
+func (d *dmlWorker) flushIntervalOverride(
+	table cloudstorage.VersionedTableName,
+) *cloudstorage.TableOverride {
+	override := d.tableOverride(table)
+	if override == nil || override.FlushInterval == 0 {
+		return nil
+	}
+	return override
+}
+
+func (d *dmlWorker) fileSize(table cloudstorage.VersionedTableName) int {
+	override := d.tableOverride(table)
+	if override == nil || override.FileSize == 0 {
+		return d.config.FileSize
+	}
+	return override.FileSize
+}
+
+// runOverrideTickers notifies the table overrides whose flush interval elapses
+// by the returned channel, until the context is done.
+func (d *dmlWorker) runOverrideTickers(ctx context.Context) <-chan *cloudstorage.TableOverride {
+	tickCh := make(chan *cloudstorage.TableOverride)
+	for _, override := range d.config.TableOverrides {
+		if override.FlushInterval == 0 {
+			continue
+		}
+		go func(override *cloudstorage.TableOverride) {
+			ticker := time.NewTicker(override.FlushInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					select {
+					case <-ctx.Done():
+						return
+					case tickCh <- override:
+					}
+				}
+			}
+		}(override)
+	}
+	return tickCh
+}
+
+// tryEmitFlushTask emits the tables whose flush interval is overridden by the
+// table override, or the tables using the global flush interval if the table
+// override is nil. The tables are kept in the flush task if the flush notify
+// channel is full.
+func (d *dmlWorker) tryEmitFlushTask(
+	ctx context.Context, flushTask dmlTask, override *cloudstorage.TableOverride,
+) error {
+	task := newDMLTask()
+	for table, t := range flushTask.tasks {
+		if d.flushIntervalOverride(table) == override {
+			task.tasks[table] = t
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case d.flushNotifyCh <- task:
+		log.Debug("flush task is emitted successfully when flush interval exceeds",
+			zap.Int("tablesLength", len(task.tasks)))
+		for table := range task.tasks {
+			delete(flushTask.tasks, table)
+		}
+	default:
+	}
+	return nil
+}
+
 // dispatchFlushTasks dispatches flush tasks in two conditions:
 // 1. the flush interval exceeds the upper limit.
 // 2. the file size exceeds the upper limit.
+// Both the limits can be overridden for the tables matched by the table overrides.
 func (d *dmlWorker) dispatchFlushTasks(ctx context.Context,
 	ch *chann.DrainableChann[eventFragment],
 ) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	flushTask := newDMLTask()
 	ticker := time.NewTicker(d.config.FlushInterval)
+	defer ticker.Stop()
+	overrideTickCh := d.runOverrideTickers(ctx)
 
 	for {
 		select {
@@ -274,14 +560,15 @@ func (d *dmlWorker) dispatchFlushTasks(ctx context.Context,
 			if atomic.LoadUint64(&d.isClosed) == 1 {
 				return nil
 			}
-			select {
-			case <-ctx.Done():
-				return errors.Trace(ctx.Err())
-			case d.flushNotifyCh <- flushTask:
-				log.Debug("flush task is emitted successfully when flush interval exceeds",
-					zap.Int("tablesLength", len(flushTask.tasks)))
-				flushTask = newDMLTask()
-			default:
+			if err := d.tryEmitFlushTask(ctx, flushTask, nil); err != nil {
+				return err
+			}
+		case override := <-overrideTickCh:
+			if atomic.LoadUint64(&d.isClosed) == 1 {
+				return nil
+			}
+			if err := d.tryEmitFlushTask(ctx, flushTask, override); err != nil {
+				return err
 			}
 		case frag, ok := <-ch.Out():
 			if !ok || atomic.LoadUint64(&d.isClosed) == 1 {
@@ -291,7 +578,7 @@ func (d *dmlWorker) dispatchFlushTasks(ctx context.Context,
 			// if the file size exceeds the upper limit, emit the flush task containing the table
 			// as soon as possible.
 			table := frag.versionedTable
-			if flushTask.tasks[table].size >= uint64(d.config.FileSize) {
+			if flushTask.tasks[table].size >= uint64(d.fileSize(table)) {
 				task := flushTask.generateTaskByTable(table)
 				select {
 				case <-ctx.Done():
@@ -14,13 +14,18 @@ package cloudstorage
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"path"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
@@ -53,7 +58,7 @@ func testDMLWorker(ctx context.Context, t *testing.T, dir string) *dmlWorker {
 	statistics := metrics.NewStatistics(ctx, model.DefaultChangeFeedID("dml-worker-test"),
 		sink.TxnSink)
 	d := newDMLWorker(1, model.DefaultChangeFeedID("dml-worker-test"), storage,
-		cfg, ".json", chann.NewAutoDrainChann[eventFragment](), clock.New(), statistics)
+		cfg, ".json", chann.NewAutoDrainChann[eventFragment](), clock.New(), statistics, nil, nil, nil)
 	return d
 }
 
@@ -137,3 +142,318 @@ func TestDMLWorkerRun(t *testing.T) {
 	wg.Wait()
 	fragCh.CloseAndDrain()
 }
+
+func TestDMLWorkerTableOverride(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("file:///tmp/test?flush-interval=1m")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		TableOverrides: []*config.CloudStorageTableOverride{
+			{
+				Matcher:       []string{"test.table1"},
+				FlushInterval: util.AddressOf("2s"),
+				FileSize:      util.AddressOf(1024 * 1024),
+			},
+		},
+	}
+	cfg := cloudstorage.NewConfig()
+	require.NoError(t, cfg.Apply(ctx, sinkURI, replicaConfig))
+	statistics := metrics.NewStatistics(ctx, model.DefaultChangeFeedID("dml-worker-test"),
+		sink.TxnSink)
+	d := newDMLWorker(1, model.DefaultChangeFeedID("dml-worker-test"), nil,
+		cfg, ".json", chann.NewAutoDrainChann[eventFragment](), clock.New(), statistics, nil, nil, nil)
+	defer d.inputCh.CloseAndDrain()
+
+	table1 := cloudstorage.VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{Schema: "test", Table: "table1", TableID: 100},
+	}
+	table2 := cloudstorage.VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{Schema: "test", Table: "table2", TableID: 101},
+	}
+	override := cfg.TableOverrides[0]
+	require.Equal(t, override, d.flushIntervalOverride(table1))
+	require.Nil(t, d.flushIntervalOverride(table2))
+	require.Equal(t, 1024*1024, d.fileSize(table1))
+	require.Equal(t, cfg.FileSize, d.fileSize(table2))
+
+	// only the tables of the table override are emitted.
+	flushTask := newDMLTask()
+	flushTask.tasks[table1] = &singleTableTask{}
+	flushTask.tasks[table2] = &singleTableTask{}
+	require.NoError(t, d.tryEmitFlushTask(ctx, flushTask, override))
+	task := <-d.flushNotifyCh
+	require.Len(t, task.tasks, 1)
+	require.Contains(t, task.tasks, table1)
+	require.Len(t, flushTask.tasks, 1)
+
+	require.NoError(t, d.tryEmitFlushTask(ctx, flushTask, nil))
+	task = <-d.flushNotifyCh
+	require.Len(t, task.tasks, 1)
+	require.Contains(t, task.tasks, table2)
+	require.Len(t, flushTask.tasks, 0)
+}
+
+type mockDataFileLoader struct {
+	paths []string
+	err   error
+}
+
+func (l *mockDataFileLoader) Load(
+	_ context.Context, _ *model.TableInfo, filePath string, _ int64,
+) error {
+	l.paths = append(l.paths, filePath)
+	return l.err
+}
+
+func TestDMLWorkerLoadDataFile(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d := testDMLWorker(ctx, t, t.TempDir())
+	defer d.inputCh.CloseAndDrain()
+	loader := &mockDataFileLoader{}
+	d.loader = loader
+
+	acked := 0
+	task := &singleTableTask{
+		tableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "table1"}},
+		msgs: []*common.Message{{
+			Value:    []byte("I,table1,test,1\n"),
+			Callback: func() { acked++ },
+		}},
+	}
+	table := cloudstorage.VersionedTableName{TableNameWithPhysicTableID: task.tableInfo.TableName}
+	require.NoError(t, d.writeDataFile(ctx, "test/table1/CDC000001.csv", table, task, nil))
+	require.Equal(t, []string{"test/table1/CDC000001.csv"}, loader.paths)
+	require.Equal(t, 1, acked)
+
+	// the events are not acknowledged if the data file fails to be loaded.
+	loader.err = errors.New("load failed")
+	require.ErrorContains(t, d.writeDataFile(ctx, "test/table1/CDC000002.csv",
+		table, task, nil), "load failed")
+	require.Equal(t, 1, acked)
+}
+
+func TestDMLWorkerWriteManifest(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parentDir := t.TempDir()
+	d := testDMLWorker(ctx, t, parentDir)
+	defer d.inputCh.CloseAndDrain()
+
+	acked := 0
+	tableInfo := &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "table1"}}
+	table := cloudstorage.VersionedTableName{TableNameWithPhysicTableID: tableInfo.TableName}
+	flushTask := newDMLTask()
+	for _, commitTs := range []uint64{102, 101, 103} {
+		flushTask.handleSingleTableEvent(eventFragment{
+			versionedTable: table,
+			event: &dmlsink.TxnCallbackableEvent{
+				Event: &model.SingleTableTxn{TableInfo: tableInfo, CommitTs: commitTs},
+			},
+			encodedMsgs: []*common.Message{{
+				Value:    []byte(fmt.Sprintf("I,table1,test,%d\n", commitTs)),
+				Callback: func() { acked++ },
+			}},
+		})
+	}
+	for _, msg := range flushTask.tasks[table].msgs {
+		msg.IncRowsCount()
+	}
+
+	// the events are acknowledged after the manifest file is written.
+	manifest := &flushManifest{}
+	require.NoError(t, d.writeDataFile(ctx, "test/table1/CDC000001.csv",
+		table, flushTask.tasks[table], manifest))
+	require.Equal(t, 0, acked)
+	require.NoError(t, d.writeManifestFile(ctx, manifest))
+	require.Equal(t, 3, acked)
+
+	manifestFiles, err := os.ReadDir(path.Join(parentDir, "manifest"))
+	require.NoError(t, err)
+	require.Len(t, manifestFiles, 1)
+	manifestFilePath := path.Join("manifest", manifestFiles[0].Name())
+	require.True(t, cloudstorage.IsManifestFile(manifestFilePath))
+	content, err := os.ReadFile(path.Join(parentDir, manifestFilePath))
+	require.NoError(t, err)
+	var m cloudstorage.Manifest
+	require.NoError(t, json.Unmarshal(content, &m))
+	require.Len(t, m.Files, 1)
+
+	data, err := os.ReadFile(path.Join(parentDir, "test/table1/CDC000001.csv"))
+	require.NoError(t, err)
+	checksum := sha256.Sum256(data)
+	require.Equal(t, cloudstorage.ManifestFile{
+		Path:        "test/table1/CDC000001.csv",
+		Schema:      "test",
+		Table:       "table1",
+		RowCount:    3,
+		Size:        len(data),
+		MinCommitTs: 101,
+		MaxCommitTs: 103,
+		SHA256:      hex.EncodeToString(checksum[:]),
+	}, m.Files[0])
+
+	// no manifest file is written if no data file is written.
+	require.NoError(t, d.writeManifestFile(ctx, &flushManifest{}))
+	manifestFiles, err = os.ReadDir(path.Join(parentDir, "manifest"))
+	require.NoError(t, err)
+	require.Len(t, manifestFiles, 1)
+}
+
+func TestDMLWorkerWriteCompleteMarkers(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parentDir := t.TempDir()
+	d := testDMLWorker(ctx, t, parentDir)
+	defer d.inputCh.CloseAndDrain()
+	d.config.DateSeparator = config.DateSeparatorDay.String()
+	d.config.OutputCompleteMarker = true
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2023, 3, 9, 23, 59, 0, 0, time.UTC))
+	d.filePathGenerator.SetClock(mockClock)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = d.flushMessages(ctx)
+	}()
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "table1", TableID: 100},
+		Version:   99,
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{ID: 1, Name: timodel.NewCIStr("c1"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+			},
+		},
+	}
+	table := cloudstorage.VersionedTableName{
+		TableNameWithPhysicTableID: tableInfo.TableName,
+		TableInfoVersion:           99,
+	}
+	flushTask := newDMLTask()
+	for _, commitTs := range []uint64{101, 103, 102} {
+		flushTask.handleSingleTableEvent(eventFragment{
+			versionedTable: table,
+			event: &dmlsink.TxnCallbackableEvent{
+				Event: &model.SingleTableTxn{TableInfo: tableInfo, CommitTs: commitTs},
+			},
+			encodedMsgs: []*common.Message{{Value: []byte(`{"c1":1}`)}},
+		})
+	}
+	d.flushNotifyCh <- flushTask
+	partitionDir := path.Join(parentDir, "test/table1/99/2023-03-09")
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path.Join(partitionDir, "CDC000001.json"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the partition of the current date isn't complete.
+	d.flushNotifyCh <- newDMLTask()
+	d.flushNotifyCh <- newDMLTask()
+	_, err := os.Stat(path.Join(partitionDir, "_SUCCESS"))
+	require.True(t, os.IsNotExist(err))
+
+	// the partition is complete after the date changes.
+	mockClock.Set(time.Date(2023, 3, 10, 0, 0, 1, 0, time.UTC))
+	d.flushNotifyCh <- newDMLTask()
+	var content []byte
+	require.Eventually(t, func() bool {
+		content, err = os.ReadFile(path.Join(partitionDir, "_SUCCESS"))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	var marker cloudstorage.CompleteMarker
+	require.NoError(t, json.Unmarshal(content, &marker))
+	require.Equal(t, uint64(103), marker.MaxCommitTs)
+
+	cancel()
+	wg.Wait()
+	require.Empty(t, d.openPartitions)
+}
+
+func TestDMLWorkerWriteHeader(t *testing.T) {
+	t.Parallel()
+
+	newTableInfo := func(version uint64, columns ...string) *model.TableInfo {
+		info := &timodel.TableInfo{ID: 100, Name: timodel.NewCIStr("table1")}
+		for i, name := range columns {
+			info.Columns = append(info.Columns, &timodel.ColumnInfo{
+				ID:        int64(i + 1),
+				Name:      timodel.NewCIStr(name),
+				Offset:    i,
+				FieldType: *types.NewFieldType(mysql.TypeLong),
+				State:     timodel.StatePublic,
+			})
+		}
+		return model.WrapTableInfo(1, "test", version, info)
+	}
+	v1 := newTableInfo(101, "id")
+	v2 := newTableInfo(102, "id", "name")
+
+	for _, policy := range []string{config.CSVHeaderEveryFile, config.CSVHeaderFirstFile} {
+		ctx, cancel := context.WithCancel(context.Background())
+		parentDir := t.TempDir()
+		d := testDMLWorker(ctx, t, parentDir)
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Terminator = util.AddressOf("\n")
+		replicaConfig.Sink.CSVConfig.IncludeHeader = policy
+		sinkURI, err := url.Parse("file:///tmp/test?protocol=csv")
+		require.NoError(t, err)
+		codecConfig := common.NewConfig(config.ProtocolCsv)
+		require.NoError(t, codecConfig.Apply(sinkURI, replicaConfig))
+		d.encodeHeader = newCSVHeaderEncoder(codecConfig)
+
+		// two data files are written for each table version.
+		var contents []string
+		for _, tableInfo := range []*model.TableInfo{v1, v2} {
+			table := cloudstorage.VersionedTableName{
+				TableNameWithPhysicTableID: tableInfo.TableName,
+				TableInfoVersion:           tableInfo.Version,
+			}
+			// the schema file is written by the DDL sink after the schema changes.
+			var def cloudstorage.TableDefinition
+			def.FromTableInfo(tableInfo, tableInfo.Version, false)
+			schemaFilePath, err := def.GenerateSchemaFilePath()
+			require.NoError(t, err)
+			schema, err := def.MarshalWithQuery()
+			require.NoError(t, err)
+			require.NoError(t, d.storage.WriteFile(ctx, schemaFilePath, schema))
+			require.NoError(t, d.filePathGenerator.CheckOrWriteSchema(ctx, table, tableInfo))
+			for i := 0; i < 2; i++ {
+				dataFilePath, err := d.filePathGenerator.GenerateDataFilePath(ctx, table, "")
+				require.NoError(t, err)
+				task := &singleTableTask{
+					tableInfo: tableInfo,
+					msgs:      []*common.Message{{Value: []byte("\"I\",\"table1\",\"test\",1\n")}},
+				}
+				require.NoError(t, d.writeDataFile(ctx, dataFilePath, table, task, nil))
+				content, err := os.ReadFile(path.Join(parentDir, dataFilePath))
+				require.NoError(t, err)
+				contents = append(contents, string(content))
+			}
+		}
+
+		header1 := "\"_tidb_op\",\"_tidb_table\",\"_tidb_schema\",\"id\"\n"
+		header2 := "\"_tidb_op\",\"_tidb_table\",\"_tidb_schema\",\"id\",\"name\"\n"
+		row := "\"I\",\"table1\",\"test\",1\n"
+		if policy == config.CSVHeaderEveryFile {
+			require.Equal(t, []string{header1 + row, header1 + row, header2 + row, header2 + row}, contents)
+		} else {
+			// the header is refreshed in the first data file after the schema changes.
+			require.Equal(t, []string{header1 + row, row, header2 + row, row}, contents)
+		}
+		d.inputCh.CloseAndDrain()
+		cancel()
+	}
+}
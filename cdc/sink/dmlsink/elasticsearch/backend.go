@@ -0,0 +1,180 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	pelasticsearch "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+)
+
+// executor executes the bulk requests in Elasticsearch.
+type executor interface {
+	Bulk(ctx context.Context, data []byte) error
+}
+
+// Assert Backend implementation
+var _ batchsink.Backend = (*backend)(nil)
+
+// backend indexes the batches into Elasticsearch, each batch is written by
+// a bulk request.
+type backend struct {
+	config *pelasticsearch.Config
+	client executor
+
+	data    bytes.Buffer
+	actions int
+	rows    int
+}
+
+// bulkMeta is the metadata line of an action in the bulk request.
+type bulkMeta struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+func newBackend(config *pelasticsearch.Config, client executor) *backend {
+	return &backend{
+		config: config,
+		client: client,
+	}
+}
+
+// Append implements batchsink.Backend, the size of the transaction is the
+// number of the encoded actions.
+func (b *backend) Append(txn *model.SingleTableTxn) (int, error) {
+	index := b.config.IndexName(txn.Table.Schema, txn.Table.Table)
+	size := 0
+	for _, row := range txn.Rows {
+		n, err := b.encodeRow(&b.data, index, row)
+		if err != nil {
+			return 0, err
+		}
+		size += n
+		b.rows++
+	}
+	b.actions += size
+	return size, nil
+}
+
+// encodeRow encodes the row change into the actions of the bulk request, the
+// handle key values are used as the document id. It returns the number of
+// the encoded actions.
+func (b *backend) encodeRow(
+	buf *bytes.Buffer, index string, row *model.RowChangedEvent,
+) (int, error) {
+	if row.IsDelete() {
+		id, err := documentID(row, row.PreColumns)
+		if err != nil {
+			return 0, err
+		}
+		return 1, encodeAction(buf, "delete", index, id, nil)
+	}
+
+	id, err := documentID(row, row.Columns)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	if row.IsUpdate() {
+		// the old document must be deleted if the handle key is changed.
+		oldID, err := documentID(row, row.PreColumns)
+		if err != nil {
+			return 0, err
+		}
+		if oldID != id {
+			if err := encodeAction(buf, "delete", index, oldID, nil); err != nil {
+				return 0, err
+			}
+			n++
+		}
+	}
+	doc := make(map[string]interface{}, len(row.Columns))
+	for _, col := range row.Columns {
+		if col == nil {
+			continue
+		}
+		if v, ok := col.Value.([]byte); ok {
+			doc[col.Name] = string(v)
+		} else {
+			doc[col.Name] = col.Value
+		}
+	}
+	return n + 1, encodeAction(buf, "index", index, id, doc)
+}
+
+// documentID returns the handle key values joined by '_'.
+func documentID(row *model.RowChangedEvent, cols []*model.Column) (string, error) {
+	var values []string
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			values = append(values, model.ColumnValueString(col.Value))
+		}
+	}
+	if len(values) == 0 {
+		return "", errors.Errorf("table %s has no primary key or not null unique key",
+			row.Table.String())
+	}
+	return strings.Join(values, "_"), nil
+}
+
+func encodeAction(
+	buf *bytes.Buffer, action, index, id string, doc map[string]interface{},
+) error {
+	meta, err := json.Marshal(map[string]bulkMeta{action: {Index: index, ID: id}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buf.Write(meta)
+	buf.WriteByte('\n')
+	if doc == nil {
+		return nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// Flush implements batchsink.Backend.
+func (b *backend) Flush(ctx context.Context) (int, error) {
+	defer b.reset()
+	if b.actions == 0 {
+		return 0, nil
+	}
+	if err := b.client.Bulk(ctx, b.data.Bytes()); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return b.rows, nil
+}
+
+func (b *backend) reset() {
+	b.data.Reset()
+	b.actions = 0
+	b.rows = 0
+}
+
+// Close implements batchsink.Backend.
+func (b *backend) Close() error {
+	return nil
+}
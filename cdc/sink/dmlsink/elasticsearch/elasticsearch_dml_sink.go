@@ -0,0 +1,44 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	pelasticsearch "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+)
+
+// NewDMLSink creates an Elasticsearch sink.
+// It indexes the row changes as the documents of Elasticsearch by the bulk API.
+func NewDMLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*batchsink.DMLSink, error) {
+	cfg := pelasticsearch.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+	client := pelasticsearch.NewClient(cfg)
+	backends := make([]batchsink.Backend, cfg.WorkerCount)
+	for i := range backends {
+		backends[i] = newBackend(cfg, client)
+	}
+	return batchsink.New(ctx, changefeedID, "elasticsearch", backends, cfg.MaxBatchRows, errCh), nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	pelasticsearch "github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	sync.Mutex
+	data []string
+	err  error
+}
+
+func (m *mockExecutor) Bulk(_ context.Context, data []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.data = append(m.data, string(data))
+	return nil
+}
+
+func newTestRows(commitTs uint64) []*model.RowChangedEvent {
+	table := &model.TableName{Schema: "test", Table: "t"}
+	pkFlag := model.HandleKeyFlag | model.PrimaryKeyFlag
+	return []*model.RowChangedEvent{
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			Columns: []*model.Column{
+				{Name: "id", Value: 1, Flag: pkFlag},
+				{Name: "name", Value: []byte("a")},
+				{Name: "age", Value: nil},
+			},
+		},
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 2, Flag: pkFlag}, {Name: "name", Value: "b"},
+			},
+			Columns: []*model.Column{
+				{Name: "id", Value: 2, Flag: pkFlag}, {Name: "name", Value: "c"},
+			},
+		},
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 3, Flag: pkFlag}, {Name: "name", Value: "d"},
+			},
+			Columns: []*model.Column{
+				{Name: "id", Value: 4, Flag: pkFlag}, {Name: "name", Value: "d"},
+			},
+		},
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 5, Flag: pkFlag}, {Name: "name", Value: "e"},
+			},
+		},
+	}
+}
+
+func TestEncodeRows(t *testing.T) {
+	t.Parallel()
+
+	b := newBackend(pelasticsearch.NewConfig(), nil)
+	var buf bytes.Buffer
+	actions := 0
+	for _, row := range newTestRows(100) {
+		n, err := b.encodeRow(&buf, "test.t", row)
+		require.NoError(t, err)
+		actions += n
+	}
+	require.Equal(t, 5, actions)
+	require.Equal(t, `{"index":{"_index":"test.t","_id":"1"}}
+{"age":null,"id":1,"name":"a"}
+{"index":{"_index":"test.t","_id":"2"}}
+{"id":2,"name":"c"}
+{"delete":{"_index":"test.t","_id":"3"}}
+{"index":{"_index":"test.t","_id":"4"}}
+{"id":4,"name":"d"}
+{"delete":{"_index":"test.t","_id":"5"}}
+`, buf.String())
+
+	// the tables without handle keys can't be indexed.
+	_, err := b.encodeRow(&buf, "test.t", &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t"},
+		Columns: []*model.Column{{Name: "id", Value: 1}},
+	})
+	require.ErrorContains(t, err, "no primary key")
+}
+
+func TestFlush(t *testing.T) {
+	t.Parallel()
+
+	client := &mockExecutor{}
+	b := newBackend(pelasticsearch.NewConfig(), client)
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	for _, txn := range batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows) {
+		n, err := b.Append(txn.Event)
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+	}
+	rows, err := b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 40, rows)
+	require.Len(t, client.data, 1)
+	require.Equal(t, 80, strings.Count(client.data[0], "\n"))
+
+	// the batch is reset after it's flushed.
+	rows, err = b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, rows)
+	require.Len(t, client.data, 1)
+
+	client.err = errors.New("index_not_found_exception")
+	_, err = b.Append(batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows)[0].Event)
+	require.NoError(t, err)
+	_, err = b.Flush(context.Background())
+	require.ErrorContains(t, err, "index_not_found_exception")
+}
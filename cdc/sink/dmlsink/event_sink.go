@@ -13,6 +13,12 @@
 
 package dmlsink
 
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
 // EventSink is the interface for event sink.
 type EventSink[E TableEvent] interface {
 	// WriteEvents writes events to the sink.
@@ -23,3 +29,20 @@ type EventSink[E TableEvent] interface {
 	// The EventSink meets internal errors and has been dead already.
 	Dead() <-chan struct{}
 }
+
+// PreFlightChecker is implemented by the event sinks which can check the
+// connectivity and the permissions of the downstream, so a misconfigured
+// changefeed fails to be created instead of failing after it's started.
+type PreFlightChecker interface {
+	// PreFlight checks whether the downstream is reachable and writable.
+	PreFlight(ctx context.Context) error
+}
+
+// DispatchRulesUpdater is implemented by the event sinks which route the
+// events by the dispatch rules, so the rules can be updated while the
+// changefeed is running.
+type DispatchRulesUpdater interface {
+	// UpdateDispatchRules rebuilds the routers of the events by the dispatch
+	// rules in the config, the connections to the downstream are kept.
+	UpdateDispatchRules(cfg *config.ReplicaConfig) error
+}
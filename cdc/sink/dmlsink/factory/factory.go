@@ -15,7 +15,6 @@ package factory
 
 import (
 	"context"
-	"net/url"
 	"strings"
 
 	"github.com/pingcap/log"
@@ -23,17 +22,21 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/blackhole"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/clickhouse"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/elasticsearch"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/redis"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/webhook"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
+	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	v2 "github.com/pingcap/tiflow/pkg/sink/kafka/v2"
-	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -45,22 +48,85 @@ import (
 type SinkFactory struct {
 	rowSink dmlsink.EventSink[*model.RowChangedEvent]
 	txnSink dmlsink.EventSink[*model.SingleTableTxn]
+
+	// targets are the sink factories of the sink targets, a table is written
+	// to the first target matching it, or this factory if there is none.
+	targets       []*SinkFactory
+	targetConfigs []*config.SinkTarget
+	matcher       *util.TargetMatcher
+	// mirror is the sink factory of the mirror, the table sinks created by
+	// this factory write the events to the mirror as well.
+	mirror *SinkFactory
+	// router renames the tables and the columns of the rows in the downstream,
+	// it's nil if there is no table route.
+	router *util.TableRouter
+	// sinkID is the ID the sinks of this factory are created with, the sinks
+	// of the mirror and the targets have their own IDs, see MirrorSinkID.
+	sinkID model.ChangeFeedID
 }
 
-// New creates a new SinkFactory by schema.
+// New creates a new SinkFactory by schema, and the sink factories of the sink
+// targets and the mirror if they're configured. The errors of the mirror are
+// sent to mirrorErrCh, so they don't fail the changefeed.
 func New(
 	ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	sinkURIStr string,
 	cfg *config.ReplicaConfig,
 	errCh chan error,
+	mirrorErrCh chan error,
 ) (*SinkFactory, error) {
-	sinkURI, err := url.Parse(sinkURIStr)
+	s, err := newSinkFactory(ctx, changefeedID, sinkURIStr, cfg, errCh)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Sink.Mirror != nil {
+		s.mirror, err = newSinkFactory(ctx, changefeedID.MirrorSinkID(), cfg.Sink.Mirror.SinkURI,
+			cfg.SinkMirrorConfig(), mirrorErrCh)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	if len(cfg.Sink.Targets) == 0 {
+		return s, nil
+	}
+
+	s.matcher, err = util.NewTargetMatcher(cfg)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.targetConfigs = cfg.Sink.Targets
+	for i, target := range cfg.Sink.Targets {
+		f, err := newSinkFactory(ctx, changefeedID.TargetSinkID(i), target.SinkURI,
+			cfg.SinkTargetConfig(target), errCh)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.targets = append(s.targets, f)
+	}
+	return s, nil
+}
+
+func newSinkFactory(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+	errCh chan error,
+) (*SinkFactory, error) {
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
 
-	s := &SinkFactory{}
+	router, err := util.NewTableRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &SinkFactory{router: router, sinkID: changefeedID}
 	schema := strings.ToLower(sinkURI.Scheme)
 	switch schema {
 	case sink.MySQLScheme, sink.MySQLSSLScheme, sink.TiDBScheme, sink.TiDBSSLScheme:
@@ -72,7 +138,7 @@ func New(
 		s.txnSink = txnSink
 	case sink.KafkaScheme, sink.KafkaSSLScheme:
 		factoryCreator := kafka.NewSaramaFactory
-		if util.GetOrZero(cfg.Sink.EnableKafkaSinkV2) {
+		if cfg.Sink.KafkaSinkV2Enabled() {
 			factoryCreator = v2.NewFactory
 		}
 		mqs, err := mq.NewKafkaDMLSink(ctx, changefeedID, sinkURI, cfg, errCh,
@@ -81,12 +147,37 @@ func New(
 			return nil, err
 		}
 		s.rowSink = mqs
-	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme:
+	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme,
+		sink.WebHDFSScheme, sink.WebHDFSSSLScheme, sink.HDFSScheme, sink.OSSScheme, sink.COSScheme:
 		storageSink, err := cloudstorage.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
 		if err != nil {
 			return nil, err
 		}
 		s.txnSink = storageSink
+	case sink.ClickHouseScheme, sink.ClickHouseSSLScheme:
+		clickhouseSink, err := clickhouse.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = clickhouseSink
+	case sink.ElasticsearchScheme, sink.ElasticsearchSSLScheme:
+		elasticsearchSink, err := elasticsearch.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = elasticsearchSink
+	case sink.RedisScheme, sink.RedisSSLScheme:
+		redisSink, err := redis.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = redisSink
+	case sink.HTTPScheme, sink.HTTPSScheme:
+		webhookSink, err := webhook.NewDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = webhookSink
 	case sink.BlackHoleScheme:
 		bs := blackhole.NewDMLSink()
 		s.rowSink = bs
@@ -98,19 +189,59 @@ func New(
 	return s, nil
 }
 
-// CreateTableSink creates a TableSink by schema.
+// HasTargets returns whether there are sink targets, the table sinks must be
+// created by the factories of the tables if so.
+func (s *SinkFactory) HasTargets() bool {
+	return len(s.targets) > 0
+}
+
+// ForTable returns the sink factory the table is written to.
+func (s *SinkFactory) ForTable(table model.TableName) *SinkFactory {
+	if !s.HasTargets() {
+		return s
+	}
+	if i := s.matcher.Match(table.Schema, table.Table); i >= 0 {
+		return s.targets[i]
+	}
+	return s
+}
+
+// CreateTableSink creates a TableSink by schema. The table sink writes the
+// events to the mirror as well if there is a mirror.
 func (s *SinkFactory) CreateTableSink(
 	changefeedID model.ChangeFeedID,
 	span tablepb.Span, startTs model.Ts,
+	bufferQuota *tablesink.BufferQuota,
 	totalRowsCounter prometheus.Counter,
 ) tablesink.TableSink {
-	if s.txnSink != nil {
-		return tablesink.New(changefeedID, span, startTs, s.txnSink,
-			&dmlsink.TxnEventAppender{TableSinkStartTs: startTs}, totalRowsCounter)
+	if s.mirror != nil {
+		// the events of the mirror aren't spilled, so that the spilled events
+		// of the table aren't mixed up in the spill store.
+		return tablesink.NewMirror(
+			s.createTableSink(changefeedID, span, startTs, bufferQuota, totalRowsCounter),
+			s.mirror.createTableSink(changefeedID, span, startTs, nil, totalRowsCounter))
 	}
+	return s.createTableSink(changefeedID, span, startTs, bufferQuota, totalRowsCounter)
+}
 
-	return tablesink.New(changefeedID, span, startTs, s.rowSink,
-		&dmlsink.RowChangeEventAppender{}, totalRowsCounter)
+func (s *SinkFactory) createTableSink(
+	changefeedID model.ChangeFeedID,
+	span tablepb.Span, startTs model.Ts,
+	bufferQuota *tablesink.BufferQuota,
+	totalRowsCounter prometheus.Counter,
+) tablesink.TableSink {
+	var tableSink tablesink.TableSink
+	if s.txnSink != nil {
+		tableSink = tablesink.New(changefeedID, span, startTs, s.txnSink,
+			&dmlsink.TxnEventAppender{TableSinkStartTs: startTs}, bufferQuota, totalRowsCounter)
+	} else {
+		tableSink = tablesink.New(changefeedID, span, startTs, s.rowSink,
+			&dmlsink.RowChangeEventAppender{}, bufferQuota, totalRowsCounter)
+	}
+	if s.router != nil {
+		return tablesink.NewRouted(tableSink, s.router)
+	}
+	return tableSink
 }
 
 // CreateTableSinkForConsumer creates a TableSink by schema for consumer.
@@ -127,11 +258,66 @@ func (s *SinkFactory) CreateTableSinkForConsumer(
 			// IgnoreStartTs is true because the consumer can
 			// **not** get the start ts of the row changed event.
 			&dmlsink.TxnEventAppender{TableSinkStartTs: startTs, IgnoreStartTs: true},
-			totalRowsCounter)
+			nil, totalRowsCounter)
 	}
 
 	return tablesink.New(changefeedID, span, startTs, s.rowSink,
-		&dmlsink.RowChangeEventAppender{}, totalRowsCounter)
+		&dmlsink.RowChangeEventAppender{}, nil, totalRowsCounter)
+}
+
+// PreFlight checks the connectivity and the permissions of the downstream if
+// the sink supports it, the sinks without the pre-flight check always pass.
+func (s *SinkFactory) PreFlight(ctx context.Context) error {
+	var eventSink interface{} = s.rowSink
+	if s.txnSink != nil {
+		eventSink = s.txnSink
+	}
+	if checker, ok := eventSink.(dmlsink.PreFlightChecker); ok {
+		if err := checker.PreFlight(ctx); err != nil {
+			return err
+		}
+	}
+	if s.mirror != nil {
+		if err := s.mirror.PreFlight(ctx); err != nil {
+			return err
+		}
+	}
+	for _, target := range s.targets {
+		if err := target.PreFlight(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateDispatchRules updates the dispatch rules of the sink without recreating
+// it, the sinks which don't route the events by the rules ignore them.
+// The sink factory must be recreated if the sink targets or the mirror are
+// changed.
+func (s *SinkFactory) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	if !util.SameSinkTargets(s.targetConfigs, cfg.Sink.Targets) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("the sink targets are changed")
+	}
+	if (s.mirror != nil) != (cfg.Sink.Mirror != nil) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("the mirror is changed")
+	}
+	if updater, ok := s.rowSink.(dmlsink.DispatchRulesUpdater); ok {
+		if err := updater.UpdateDispatchRules(cfg); err != nil {
+			return err
+		}
+	}
+	if s.mirror != nil {
+		if err := s.mirror.UpdateDispatchRules(cfg.SinkMirrorConfig()); err != nil {
+			return err
+		}
+	}
+	for i, target := range s.targets {
+		if err := target.UpdateDispatchRules(
+			cfg.SinkTargetConfig(cfg.Sink.Targets[i])); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Close closes the sink.
@@ -145,4 +331,10 @@ func (s *SinkFactory) Close() {
 	if s.txnSink != nil {
 		s.txnSink.Close()
 	}
+	if s.mirror != nil {
+		s.mirror.Close()
+	}
+	for _, target := range s.targets {
+		target.Close()
+	}
 }
@@ -23,10 +23,13 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	mcloudstorage "github.com/pingcap/tiflow/cdc/sink/metrics/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
@@ -85,8 +88,106 @@ func TestSinkFactory(t *testing.T) {
 	require.NotNil(t, sinkFactory.rowSink)
 
 	tableSink := sinkFactory.CreateTableSink(model.DefaultChangeFeedID("1"),
-		spanz.TableIDToComparableSpan(1), 0, prometheus.NewCounter(prometheus.CounterOpts{}))
+		spanz.TableIDToComparableSpan(1), 0, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 	require.NotNil(t, tableSink, "table sink can be created")
 
 	sinkFactory.Close()
 }
+
+func TestSinkFactoryTargets(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Targets = []*config.SinkTarget{
+		{Matcher: []string{"test.t1"}, SinkURI: "blackhole://"},
+		{Matcher: []string{"test.*"}, SinkURI: "blackhole://"},
+	}
+	sinkFactory, err := New(ctx, model.DefaultChangeFeedID("1"), "blackhole://", cfg,
+		make(chan error, 1), make(chan error, 1))
+	require.NoError(t, err)
+	defer sinkFactory.Close()
+
+	require.True(t, sinkFactory.HasTargets())
+	require.Same(t, sinkFactory.targets[0], sinkFactory.ForTable(model.TableName{Schema: "test", Table: "t1"}))
+	require.Same(t, sinkFactory.targets[1], sinkFactory.ForTable(model.TableName{Schema: "test", Table: "t2"}))
+	require.Same(t, sinkFactory, sinkFactory.ForTable(model.TableName{Schema: "db", Table: "t1"}))
+	require.False(t, sinkFactory.targets[0].HasTargets())
+	require.NoError(t, sinkFactory.PreFlight(ctx))
+
+	// the sink factory must be recreated if the sink targets are changed.
+	require.NoError(t, sinkFactory.UpdateDispatchRules(cfg))
+	require.Error(t, sinkFactory.UpdateDispatchRules(config.GetDefaultReplicaConfig()))
+}
+
+func TestSinkFactoryMirror(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Mirror = &config.SinkMirror{SinkURI: "blackhole://mirror"}
+	sinkFactory, err := New(ctx, model.DefaultChangeFeedID("1"), "blackhole://", cfg,
+		make(chan error, 1), make(chan error, 1))
+	require.NoError(t, err)
+	defer sinkFactory.Close()
+
+	require.NotNil(t, sinkFactory.mirror)
+	tableSink := sinkFactory.CreateTableSink(model.DefaultChangeFeedID("1"),
+		spanz.TableIDToComparableSpan(1), 0, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.IsType(t, &tablesink.MirrorTableSink{}, tableSink)
+	require.NoError(t, sinkFactory.PreFlight(ctx))
+
+	// the sink factory must be recreated if the mirror is removed.
+	require.NoError(t, sinkFactory.UpdateDispatchRules(cfg))
+	require.Error(t, sinkFactory.UpdateDispatchRules(config.GetDefaultReplicaConfig()))
+}
+
+func TestSinkFactorySinkIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Protocol = util.AddressOf(config.ProtocolCsv.String())
+	cfg.Sink.Mirror = &config.SinkMirror{SinkURI: fmt.Sprintf("file:///%s/mirror", dir)}
+	cfg.Sink.Targets = []*config.SinkTarget{
+		{Matcher: []string{"test.*"}, SinkURI: fmt.Sprintf("file:///%s/target", dir)},
+	}
+	changefeedID := model.DefaultChangeFeedID("sink-ids")
+	sinkFactory, err := New(ctx, changefeedID, fmt.Sprintf("file:///%s/primary", dir), cfg,
+		make(chan error, 1), make(chan error, 1))
+	require.NoError(t, err)
+	defer sinkFactory.Close()
+
+	// the kafka transactional IDs and the claim-check probe files of the
+	// sinks are named after the sink IDs.
+	require.Equal(t, changefeedID, sinkFactory.sinkID)
+	require.Equal(t, changefeedID.MirrorSinkID(), sinkFactory.mirror.sinkID)
+	require.Equal(t, changefeedID.TargetSinkID(0), sinkFactory.targets[0].sinkID)
+
+	// the sinks don't share the metrics, so closing one of them doesn't
+	// delete the metrics of the others.
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(mcloudstorage.CloudStorageWriteBytesGauge)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	labels := make(map[string]struct{})
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "changefeed" {
+					labels[label.GetValue()] = struct{}{}
+				}
+			}
+		}
+	}
+	require.Contains(t, labels, changefeedID.ID)
+	require.Contains(t, labels, changefeedID.MirrorSinkID().ID)
+	require.Contains(t, labels, changefeedID.TargetSinkID(0).ID)
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxFlushBatchSize is the max batch size of the flush worker when the
+	// broker is slow.
+	maxFlushBatchSize = 8 * flushBatchSize
+	// maxFlushInterval is the max interval of the flush worker when the
+	// broker is slow.
+	maxFlushInterval = 10 * flushInterval
+	// flushBatchSizeStep and flushIntervalStep are the additive increments
+	// of the batch size and the interval.
+	flushBatchSizeStep = flushBatchSize / 4
+	flushIntervalStep  = flushInterval / 3
+	// targetSendLatency is the latency of sending a message, above which the
+	// broker is considered slow.
+	targetSendLatency = 5 * time.Millisecond
+	// sendLatencySmoothing is the weight of the latest latency in the moving
+	// average of the send latency.
+	sendLatencySmoothing = 0.2
+)
+
+// batchController adapts the batch size and the interval of the flush worker
+// to the latency of sending messages to the broker by AIMD. The batches grow
+// additively while the broker is slow, so more rows are sent in fewer
+// messages, and shrink multiplicatively back to the defaults once the broker
+// catches up, so the messages are not delayed.
+type batchController struct {
+	// sendLatency is the moving average of the send latency in nanoseconds,
+	// it's updated by the sending goroutine.
+	sendLatency atomic.Int64
+
+	// The following fields are only accessed by the batching goroutine.
+	batchSize int
+	interval  time.Duration
+}
+
+func newBatchController() *batchController {
+	return &batchController{
+		batchSize: flushBatchSize,
+		interval:  flushInterval,
+	}
+}
+
+// observe records the latency of sending a message.
+func (c *batchController) observe(latency time.Duration) {
+	last := c.sendLatency.Load()
+	if last == 0 {
+		c.sendLatency.Store(int64(latency))
+		return
+	}
+	c.sendLatency.Store(int64(
+		sendLatencySmoothing*float64(latency) + (1-sendLatencySmoothing)*float64(last)))
+}
+
+// next adjusts and returns the batch size and the interval of the next batch.
+func (c *batchController) next() (int, time.Duration) {
+	if time.Duration(c.sendLatency.Load()) > targetSendLatency {
+		c.batchSize += flushBatchSizeStep
+		c.interval += flushIntervalStep
+	} else {
+		c.batchSize /= 2
+		c.interval /= 2
+	}
+	if c.batchSize < flushBatchSize {
+		c.batchSize = flushBatchSize
+	} else if c.batchSize > maxFlushBatchSize {
+		c.batchSize = maxFlushBatchSize
+	}
+	if c.interval < flushInterval {
+		c.interval = flushInterval
+	} else if c.interval > maxFlushInterval {
+		c.interval = maxFlushInterval
+	}
+	return c.batchSize, c.interval
+}
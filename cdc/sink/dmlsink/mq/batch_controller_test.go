@@ -0,0 +1,64 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchController(t *testing.T) {
+	t.Parallel()
+
+	c := newBatchController()
+	size, interval := c.next()
+	require.Equal(t, flushBatchSize, size, "no latency is observed")
+	require.Equal(t, flushInterval, interval)
+
+	// The batches grow additively while the broker is slow.
+	c.observe(100 * time.Millisecond)
+	size, interval = c.next()
+	require.Equal(t, flushBatchSize+flushBatchSizeStep, size)
+	require.Equal(t, flushInterval+flushIntervalStep, interval)
+	for i := 0; i < 100; i++ {
+		size, interval = c.next()
+	}
+	require.Equal(t, maxFlushBatchSize, size)
+	require.Equal(t, maxFlushInterval, interval)
+
+	// The batches shrink multiplicatively once the broker catches up.
+	for i := 0; i < 20; i++ {
+		c.observe(time.Microsecond)
+	}
+	size, interval = c.next()
+	require.Equal(t, maxFlushBatchSize/2, size)
+	require.Equal(t, maxFlushInterval/2, interval)
+	for i := 0; i < 10; i++ {
+		size, interval = c.next()
+	}
+	require.Equal(t, flushBatchSize, size)
+	require.Equal(t, flushInterval, interval)
+}
+
+func TestBatchControllerObserve(t *testing.T) {
+	t.Parallel()
+
+	c := newBatchController()
+	c.observe(10 * time.Millisecond)
+	require.Equal(t, int64(10*time.Millisecond), c.sendLatency.Load())
+	c.observe(20 * time.Millisecond)
+	require.Equal(t, int64(12*time.Millisecond), c.sendLatency.Load())
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// bootstrapMessageType is the value of the Type field of the bootstrap
+// messages, which tells them from the messages of the protocol.
+const bootstrapMessageType = "BOOTSTRAP"
+
+// bootstrapMessage is the payload of the bootstrap message of a table, it
+// carries the schema of the table so the stateless consumers can initialize
+// the table without querying TiDB.
+type bootstrapMessage struct {
+	Type   string `json:"Type"`
+	Schema string `json:"Schema"`
+	Table  string `json:"Table"`
+	// SnapshotTs is the snapshot marker, the rows of the table committed
+	// after it follow the message. It's absent if the marker is disabled.
+	SnapshotTs      uint64                        `json:"SnapshotTs,omitempty"`
+	TableDefinition *cloudstorage.TableDefinition `json:"TableDefinition"`
+}
+
+// bootstrapper builds the bootstrap message of each table before the first
+// row of the table is sent.
+type bootstrapper struct {
+	protocol       config.Protocol
+	snapshotMarker bool
+
+	mu sync.Mutex
+	// sent are the logical IDs of the tables whose bootstrap messages are sent.
+	sent map[model.TableID]struct{}
+}
+
+// newBootstrapper returns a bootstrapper, it returns nil if the bootstrap
+// messages are disabled.
+func newBootstrapper(
+	protocol config.Protocol, cfg *config.BootstrapConfig,
+) *bootstrapper {
+	if cfg == nil || !util.GetOrZero(cfg.Enable) {
+		return nil
+	}
+	return &bootstrapper{
+		protocol:       protocol,
+		snapshotMarker: util.GetOrZero(cfg.SnapshotMarker),
+		sent:           make(map[model.TableID]struct{}),
+	}
+}
+
+// shouldSend returns true if the bootstrap message of the table of the row
+// isn't sent yet, and the table is marked as bootstrapped.
+func (b *bootstrapper) shouldSend(row *model.RowChangedEvent) bool {
+	if b == nil || row.TableInfo == nil || row.TableInfo.TableInfo == nil {
+		return false
+	}
+	// the partitions of a table share the bootstrap message.
+	tableID := row.TableInfo.TableName.TableID
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.sent[tableID]; ok {
+		return false
+	}
+	b.sent[tableID] = struct{}{}
+	return true
+}
+
+// reset forgets the sent bootstrap messages, so they're sent again before the
+// next row of each table.
+func (b *bootstrapper) reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = make(map[model.TableID]struct{})
+}
+
+// encode encodes the bootstrap message of the table of the row.
+func (b *bootstrapper) encode(row *model.RowChangedEvent) (*common.Message, error) {
+	info := row.TableInfo
+	def := &cloudstorage.TableDefinition{}
+	def.FromTableInfo(info, info.Version, false)
+	m := &bootstrapMessage{
+		Type:            bootstrapMessageType,
+		Schema:          info.TableName.Schema,
+		Table:           info.TableName.Table,
+		TableDefinition: def,
+	}
+	if b.snapshotMarker {
+		m.SnapshotTs = row.ReplicatingTs
+		if m.SnapshotTs == 0 {
+			m.SnapshotTs = row.CommitTs - 1
+		}
+	}
+	value, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	key := []byte(m.Schema + "." + m.Table)
+	return common.NewMsg(b.protocol, key, value, m.SnapshotTs,
+		model.MessageTypeBootstrap, &m.Schema, &m.Table), nil
+}
@@ -17,10 +17,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"hash/crc32"
 	"time"
 
 	"github.com/klauspost/compress/snappy"
 	"github.com/pierrec/lz4"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -33,6 +36,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// claimCheckProbeFilePrefix is the prefix of the file which is written to check
+// whether the claim-check storage is writable.
+const claimCheckProbeFilePrefix = ".claim-check-probe-"
+
 // ClaimCheck manage send message to the claim-check external storage.
 type ClaimCheck struct {
 	storage storage.ExternalStorage
@@ -40,6 +47,12 @@ type ClaimCheck struct {
 	compression  string
 	changefeedID model.ChangeFeedID
 
+	// bareMessage is true if only the location pointer message should be sent to the MQ.
+	bareMessage bool
+	// presignedURLExpiry is how long the presigned URL in the bare message is
+	// valid, the URL isn't presigned if it's 0.
+	presignedURLExpiry time.Duration
+
 	// metricSendMessageDuration tracks the time duration
 	// cost on send messages to the claim check external storage.
 	metricSendMessageDuration prometheus.Observer
@@ -52,31 +65,99 @@ func NewClaimCheck(ctx context.Context, config *config.LargeMessageHandleConfig,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := checkStorageWritable(ctx, storage, changefeedID); err != nil {
+		return nil, errors.Trace(err)
+	}
+	presignedURLExpiry, _ := config.ClaimCheckPresignedURLExpiryDuration()
+	if presignedURLExpiry > 0 && !util.IsPresignSupported(storage) {
+		return nil, errors.ErrInvalidReplicaConfig.GenWithStack(
+			"claim-check-presigned-url-expiry is set, but presigning the URL isn't supported by the storage %s",
+			storage.URI())
+	}
 
 	log.Info("claim-check enabled",
 		zap.String("namespace", changefeedID.Namespace),
 		zap.String("changefeed", changefeedID.ID),
-		zap.String("storageURI", config.ClaimCheckStorageURI),
-		zap.String("compression", config.ClaimCheckCompression))
+		zap.String("storageURI", storage.URI()),
+		zap.String("compression", config.ClaimCheckCompression),
+		zap.Bool("bareMessage", config.ClaimCheckBareMessage),
+		zap.Duration("presignedURLExpiry", presignedURLExpiry))
 
 	return &ClaimCheck{
 		changefeedID:              changefeedID,
 		storage:                   storage,
 		compression:               config.ClaimCheckCompression,
+		bareMessage:               config.ClaimCheckBareMessage,
+		presignedURLExpiry:        presignedURLExpiry,
 		metricSendMessageDuration: mq.ClaimCheckSendMessageDuration.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricSendMessageCount:    mq.ClaimCheckSendMessageCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 	}, nil
 }
 
+// checkStorageWritable writes and deletes a probe file, so the changefeed fails
+// to be created if the claim-check storage can't be written, instead of failing
+// when the first large message is sent.
+func checkStorageWritable(
+	ctx context.Context, storage storage.ExternalStorage, changefeedID model.ChangeFeedID,
+) error {
+	name := fmt.Sprintf("%s%s-%s", claimCheckProbeFilePrefix, changefeedID.Namespace, changefeedID.ID)
+	if err := util.ProbeExternalStorage(ctx, storage, name); err != nil {
+		return errors.WrapError(errors.ErrClaimCheckStorageUnwritable, err, storage.URI())
+	}
+	return nil
+}
+
 // WriteMessage write message to the claim check external storage.
 func (c *ClaimCheck) WriteMessage(ctx context.Context, message *common.Message) error {
+	_, err := c.writeMessage(ctx, message)
+	return errors.Trace(err)
+}
+
+// BareMessage returns true if only the location pointer message should be sent to the MQ.
+func (c *ClaimCheck) BareMessage() bool {
+	return c.bareMessage
+}
+
+// WriteBareMessage write message to the claim check external storage,
+// and return the bare location message which should be sent to the MQ.
+func (c *ClaimCheck) WriteBareMessage(ctx context.Context, message *common.Message) (*common.Message, error) {
+	data, err := c.writeMessage(ctx, message)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	m := common.ClaimCheckBareMessage{
+		Location: message.ClaimCheckFileName,
+		Size:     len(data),
+		Checksum: crc32.ChecksumIEEE(data),
+	}
+	if c.presignedURLExpiry > 0 {
+		m.URL, err = util.PresignGetURL(ctx, c.storage, message.ClaimCheckFileName, c.presignedURLExpiry)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		m.StorageURI = c.storage.URI()
+	}
+	value, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := common.NewMsg(message.Protocol, nil, value, message.Ts, message.Type, message.Schema, message.Table)
+	result.Callback = message.Callback
+	result.SetRowsCount(message.GetRowsCount())
+	return result, nil
+}
+
+func (c *ClaimCheck) writeMessage(ctx context.Context, message *common.Message) ([]byte, error) {
 	m := common.ClaimCheckMessage{
 		Key:   message.Key,
 		Value: message.Value,
 	}
 	data, err := json.Marshal(m)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	switch c.compression {
 	case config.CompressionSnappy:
@@ -85,7 +166,7 @@ func (c *ClaimCheck) WriteMessage(ctx context.Context, message *common.Message)
 		var buf bytes.Buffer
 		writer := lz4.NewWriter(&buf)
 		if _, err := writer.Write(data); err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if err := writer.Close(); err != nil {
 			log.Warn("claim-check: close lz4 writer failed", zap.Error(err))
@@ -94,14 +175,22 @@ func (c *ClaimCheck) WriteMessage(ctx context.Context, message *common.Message)
 	default:
 	}
 
+	failpoint.Inject("ClaimCheckWriteMessageError", func() {
+		log.Info("ClaimCheckWriteMessageError error injected",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("filename", message.ClaimCheckFileName))
+		failpoint.Return(nil, errors.New("claim-check injected error"))
+	})
+
 	start := time.Now()
 	err = c.storage.WriteFile(ctx, message.ClaimCheckFileName, data)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	c.metricSendMessageDuration.Observe(time.Since(start).Seconds())
 	c.metricSendMessageCount.Inc()
-	return nil
+	return data, nil
 }
 
 // Close the claim check by clean up the metrics.
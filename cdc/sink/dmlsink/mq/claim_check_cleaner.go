@@ -0,0 +1,181 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/mq"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	// claimCheckCleanupInterval is the interval of cleaning up the expired
+	// claim-check files.
+	claimCheckCleanupInterval = 10 * time.Minute
+	// claimCheckDateLayout is the layout of the date directories of the
+	// claim-check files.
+	claimCheckDateLayout = "2006-01-02"
+)
+
+// ClaimCheckCleaner deletes the expired files from the claim-check storage in
+// the background. The files are organized by the date they're written, so the
+// files of a date are deleted once the whole day is older than both the
+// retention and the checkpoint of the changefeed, then all of them have been
+// sent to the MQ. The claim-check storage shouldn't be shared by changefeeds.
+type ClaimCheckCleaner struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	retention    time.Duration
+	checkpointTs atomic.Uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	metricStoredBytes      prometheus.Gauge
+	metricDeletedFileCount prometheus.Counter
+}
+
+// NewClaimCheckCleaner creates a ClaimCheckCleaner and starts it, nil is
+// returned if the claim-check files should never be deleted.
+func NewClaimCheckCleaner(
+	ctx context.Context, config *config.LargeMessageHandleConfig, changefeedID model.ChangeFeedID,
+) (*ClaimCheckCleaner, error) {
+	retention, ok := config.ClaimCheckRetentionDuration()
+	if !ok {
+		return nil, nil
+	}
+	storage, err := util.GetExternalStorageFromURI(ctx, config.ClaimCheckStorageURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := newClaimCheckCleaner(storage, retention, changefeedID)
+
+	log.Info("claim-check cleaner enabled",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("storageURI", storage.URI()),
+		zap.Duration("retention", retention))
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run(ctx)
+	}()
+	return c, nil
+}
+
+func newClaimCheckCleaner(
+	storage storage.ExternalStorage, retention time.Duration, changefeedID model.ChangeFeedID,
+) *ClaimCheckCleaner {
+	return &ClaimCheckCleaner{
+		changefeedID:           changefeedID,
+		storage:                storage,
+		retention:              retention,
+		cancel:                 func() {},
+		metricStoredBytes:      mq.ClaimCheckStoredBytes.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricDeletedFileCount: mq.ClaimCheckDeletedFileCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// UpdateCheckpointTs updates the checkpoint of the changefeed, the files
+// written after it are never deleted.
+func (c *ClaimCheckCleaner) UpdateCheckpointTs(ts uint64) {
+	c.checkpointTs.Store(ts)
+}
+
+func (c *ClaimCheckCleaner) run(ctx context.Context) {
+	ticker := time.NewTicker(claimCheckCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.cleanup(ctx, time.Now()); err != nil {
+				log.Warn("claim-check: clean up the expired files failed",
+					zap.String("namespace", c.changefeedID.Namespace),
+					zap.String("changefeed", c.changefeedID.ID),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+// cleanup deletes the files of the dates which end before the deadline, and
+// updates the size of the rest files.
+func (c *ClaimCheckCleaner) cleanup(ctx context.Context, now time.Time) error {
+	checkpointTs := c.checkpointTs.Load()
+	if checkpointTs == 0 {
+		return nil
+	}
+	deadline := now.Add(-c.retention)
+	if checkpoint := oracle.GetTimeFromTS(checkpointTs); checkpoint.Before(deadline) {
+		deadline = checkpoint
+	}
+
+	var (
+		toRemoveFiles []string
+		storedBytes   int64
+	)
+	err := c.storage.WalkDir(ctx, nil, func(path string, size int64) error {
+		path = strings.TrimPrefix(path, "/")
+		if dir, _, ok := strings.Cut(path, "/"); ok {
+			date, err := time.ParseInLocation(claimCheckDateLayout, dir, time.Local)
+			if err == nil && !date.AddDate(0, 0, 1).After(deadline) {
+				toRemoveFiles = append(toRemoveFiles, path)
+				return nil
+			}
+		}
+		storedBytes += size
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := util.DeleteFilesInExtStorage(ctx, c.storage, toRemoveFiles); err != nil {
+		return errors.Trace(err)
+	}
+	c.metricStoredBytes.Set(float64(storedBytes))
+	c.metricDeletedFileCount.Add(float64(len(toRemoveFiles)))
+	if len(toRemoveFiles) > 0 {
+		log.Info("claim-check: the expired files are deleted",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Int("count", len(toRemoveFiles)),
+			zap.Time("deadline", deadline))
+	}
+	return nil
+}
+
+// Close stops the cleaner and cleans up the metrics.
+func (c *ClaimCheckCleaner) Close() {
+	c.cancel()
+	c.wg.Wait()
+	mq.ClaimCheckStoredBytes.DeleteLabelValues(c.changefeedID.Namespace, c.changefeedID.ID)
+	mq.ClaimCheckDeletedFileCount.DeleteLabelValues(c.changefeedID.Namespace, c.changefeedID.ID)
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestClaimCheckCleaner(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+t.TempDir())
+	require.NoError(t, err)
+	files := map[string]string{
+		"2023-01-01/test-t-1-1.json": "1",
+		"2023-01-02/test-t-2-2.json": "22",
+		"2023-01-03/test-t-3-3.json": "333",
+		"2023-01-04/test-t-4-4.json": "4444",
+		"other/test-t-5-5.json":      "55555",
+	}
+	for name, data := range files {
+		require.NoError(t, storage.WriteFile(ctx, name, []byte(data)))
+	}
+
+	c := newClaimCheckCleaner(storage, 24*time.Hour, model.DefaultChangeFeedID("test"))
+	defer c.Close()
+	now := time.Date(2023, 1, 4, 12, 0, 0, 0, time.Local)
+	exists := func(name string) bool {
+		ok, err := storage.FileExists(ctx, name)
+		require.NoError(t, err)
+		return ok
+	}
+
+	// nothing is deleted before the checkpoint is known.
+	require.NoError(t, c.cleanup(ctx, now))
+	require.True(t, exists("2023-01-01/test-t-1-1.json"))
+
+	// the files are kept until the whole day is older than the checkpoint.
+	c.UpdateCheckpointTs(oracle.GoTimeToTS(time.Date(2023, 1, 2, 12, 0, 0, 0, time.Local)))
+	require.NoError(t, c.cleanup(ctx, now))
+	require.False(t, exists("2023-01-01/test-t-1-1.json"))
+	require.True(t, exists("2023-01-02/test-t-2-2.json"))
+	require.Equal(t, float64(2+3+4+5), testutil.ToFloat64(c.metricStoredBytes))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.metricDeletedFileCount))
+
+	// the files are kept until the whole day is older than the retention.
+	c.UpdateCheckpointTs(oracle.GoTimeToTS(now))
+	require.NoError(t, c.cleanup(ctx, now))
+	require.False(t, exists("2023-01-02/test-t-2-2.json"))
+	require.True(t, exists("2023-01-03/test-t-3-3.json"))
+	require.True(t, exists("2023-01-04/test-t-4-4.json"))
+	require.True(t, exists("other/test-t-5-5.json"))
+	require.Equal(t, float64(3+4+5), testutil.ToFloat64(c.metricStoredBytes))
+	require.Equal(t, float64(2), testutil.ToFloat64(c.metricDeletedFileCount))
+}
+
+func TestNewClaimCheckCleaner(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	largeMessageHandle := config.NewDefaultLargeMessageHandleConfig()
+	largeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	largeMessageHandle.ClaimCheckStorageURI = "file://" + t.TempDir()
+	changefeedID := model.DefaultChangeFeedID("test")
+
+	// the files are never deleted if the retention isn't configured.
+	c, err := NewClaimCheckCleaner(ctx, largeMessageHandle, changefeedID)
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	largeMessageHandle.ClaimCheckRetention = "72h"
+	c, err = NewClaimCheckCleaner(ctx, largeMessageHandle, changefeedID)
+	require.NoError(t, err)
+	require.Equal(t, 72*time.Hour, c.retention)
+	c.Close()
+}
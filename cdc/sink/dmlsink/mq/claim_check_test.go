@@ -0,0 +1,125 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"hash/crc32"
+	"os"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimCheckWriteBareMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	largeMessageHandle := config.NewDefaultLargeMessageHandleConfig()
+	largeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	largeMessageHandle.ClaimCheckStorageURI = "file://" + t.TempDir()
+	largeMessageHandle.ClaimCheckBareMessage = true
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	claimCheck, err := NewClaimCheck(ctx, largeMessageHandle, changefeedID)
+	require.NoError(t, err)
+	defer claimCheck.Close()
+	require.True(t, claimCheck.BareMessage())
+
+	var called bool
+	message := common.NewMsg(config.ProtocolOpen, []byte("key"), []byte("value"),
+		1, model.MessageTypeRow, nil, nil)
+	message.ClaimCheckFileName = "2023-01-01/test-t-1-1.json"
+	message.Callback = func() { called = true }
+	message.IncRowsCount()
+
+	bareMessage, err := claimCheck.WriteBareMessage(ctx, message)
+	require.NoError(t, err)
+	require.Nil(t, bareMessage.Key)
+	require.Equal(t, 1, bareMessage.GetRowsCount())
+	bareMessage.Callback()
+	require.True(t, called)
+
+	m, err := common.UnmarshalClaimCheckBareMessage(bareMessage.Value)
+	require.NoError(t, err)
+	require.Equal(t, message.ClaimCheckFileName, m.Location)
+	require.Equal(t, claimCheck.storage.URI(), m.StorageURI)
+	require.Empty(t, m.URL)
+
+	data, err := claimCheck.storage.ReadFile(ctx, m.Location)
+	require.NoError(t, err)
+	require.Equal(t, len(data), m.Size)
+	require.Equal(t, crc32.ChecksumIEEE(data), m.Checksum)
+
+	claimCheckMessage, err := common.UnmarshalClaimCheckMessage(data)
+	require.NoError(t, err)
+	require.Equal(t, []byte("key"), claimCheckMessage.Key)
+	require.Equal(t, []byte("value"), claimCheckMessage.Value)
+}
+
+func TestClaimCheckPresignedURLUnsupported(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	largeMessageHandle := config.NewDefaultLargeMessageHandleConfig()
+	largeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	largeMessageHandle.ClaimCheckStorageURI = "file://" + t.TempDir()
+	largeMessageHandle.ClaimCheckBareMessage = true
+	largeMessageHandle.ClaimCheckPresignedURLExpiry = "1h"
+
+	_, err := NewClaimCheck(ctx, largeMessageHandle, model.DefaultChangeFeedID("test"))
+	require.ErrorContains(t, err, "presigning the URL isn't supported")
+}
+
+type unwritableStorage struct {
+	storage.ExternalStorage
+}
+
+func (s *unwritableStorage) WriteFile(context.Context, string, []byte) error {
+	return errors.New("permission denied")
+}
+
+func TestClaimCheckStorageWritable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	largeMessageHandle := config.NewDefaultLargeMessageHandleConfig()
+	largeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionClaimCheck
+	largeMessageHandle.ClaimCheckStorageURI = "file://" + dir
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	claimCheck, err := NewClaimCheck(ctx, largeMessageHandle, changefeedID)
+	require.NoError(t, err)
+	defer claimCheck.Close()
+
+	// the probe file is deleted after the check.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	err = checkStorageWritable(ctx, &unwritableStorage{ExternalStorage: claimCheck.storage}, changefeedID)
+	require.Regexp(t, ".*claim-check storage .* isn't writable.*permission denied.*", err)
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/mq"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DeadLetterQueue diverts the events which can not be encoded to the
+// dead-letter topic or the dead-letter external storage, so the
+// replication can continue.
+type DeadLetterQueue struct {
+	changefeedID model.ChangeFeedID
+	protocol     config.Protocol
+
+	// topic is the dead-letter topic, the dead letters are sent to
+	// its partition 0 by the DML producer of the sink.
+	topic string
+	// storage is the dead-letter external storage, it's used if topic is empty.
+	storage storage.ExternalStorage
+
+	metricDeadLetterMessageCount prometheus.Counter
+}
+
+// NewDeadLetterQueue return a new DeadLetterQueue, exactly one of topic and storageURI should be set.
+func NewDeadLetterQueue(
+	ctx context.Context,
+	topic, storageURI string,
+	protocol config.Protocol,
+	changefeedID model.ChangeFeedID,
+) (*DeadLetterQueue, error) {
+	q := &DeadLetterQueue{
+		changefeedID:                 changefeedID,
+		protocol:                     protocol,
+		topic:                        topic,
+		metricDeadLetterMessageCount: mq.DeadLetterMessageCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+	if topic == "" {
+		storage, err := util.GetExternalStorageFromURI(ctx, storageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		q.storage = storage
+	}
+
+	log.Info("dead-letter queue enabled",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("topic", topic),
+		zap.String("storageURI", storageURI))
+	return q, nil
+}
+
+// Send diverts the dead letter which should be sent to the topic and partition
+// to the dead-letter queue, the callback of the event is called after the dead
+// letter is persisted.
+func (q *DeadLetterQueue) Send(
+	ctx context.Context,
+	producer dmlproducer.DMLProducer,
+	topic string, partition int32,
+	deadLetter *codec.DeadLetter,
+) error {
+	event := deadLetter.Event.Event
+	m := newDeadLetterMessage(event, topic, partition, deadLetter.Err, q.storage != nil)
+	value, err := json.Marshal(m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if q.storage != nil {
+		// the claim-check file name is unique for each event.
+		fileName := common.NewClaimCheckFileName(event)
+		if err := q.storage.WriteFile(ctx, fileName, value); err != nil {
+			return errors.Trace(err)
+		}
+		deadLetter.Event.Callback()
+	} else {
+		message := common.NewMsg(q.protocol, nil, value, event.CommitTs,
+			model.MessageTypeRow, &event.Table.Schema, &event.Table.Table)
+		message.Callback = deadLetter.Event.Callback
+		message.IncRowsCount()
+		if err := producer.AsyncSendMessage(ctx, q.topic, 0, message); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	q.metricDeadLetterMessageCount.Inc()
+	return nil
+}
+
+// Close the dead-letter queue by clean up the metrics.
+func (q *DeadLetterQueue) Close() {
+	mq.DeadLetterMessageCount.DeleteLabelValues(q.changefeedID.Namespace, q.changefeedID.ID)
+}
+
+func newDeadLetterMessage(
+	event *model.RowChangedEvent,
+	topic string, partition int32,
+	encodeErr error, withColumns bool,
+) *common.DeadLetterMessage {
+	m := &common.DeadLetterMessage{
+		Schema:    event.Table.Schema,
+		Table:     event.Table.Table,
+		CommitTs:  event.CommitTs,
+		Topic:     topic,
+		Partition: partition,
+		Error:     encodeErr.Error(),
+	}
+	if code, ok := errors.RFCCode(encodeErr); ok {
+		m.ErrorCode = string(code)
+	}
+
+	handleKeyColumns := event.Columns
+	if event.IsDelete() {
+		handleKeyColumns = event.PreColumns
+	}
+	m.HandleKey = make(map[string]interface{})
+	for _, col := range handleKeyColumns {
+		if col != nil && col.Flag.IsHandleKey() {
+			m.HandleKey[col.Name] = deadLetterColumnValue(col.Value)
+		}
+	}
+
+	if withColumns {
+		m.Columns = deadLetterColumns(event.Columns)
+		m.PreColumns = deadLetterColumns(event.PreColumns)
+	}
+	return m
+}
+
+func deadLetterColumns(cols []*model.Column) map[string]interface{} {
+	if len(cols) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		if col != nil {
+			result[col.Name] = deadLetterColumnValue(col.Value)
+		}
+	}
+	return result
+}
+
+func deadLetterColumnValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return model.ColumnValueString(value)
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeadLetterTestEvent(called *bool) *codec.DeadLetter {
+	return &codec.DeadLetter{
+		Event: &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+				Columns: []*model.Column{
+					{Name: "id", Value: int64(1), Flag: model.HandleKeyFlag},
+					{Name: "col1", Value: "aa"},
+					{Name: "col2", Value: nil},
+				},
+			},
+			Callback: func() { *called = true },
+		},
+		Err: cerror.ErrMessageTooLarge.GenWithStackByArgs(),
+	}
+}
+
+func TestDeadLetterQueueSendToStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	q, err := NewDeadLetterQueue(ctx, "", "file://"+t.TempDir(), config.ProtocolOpen, changefeedID)
+	require.NoError(t, err)
+	defer q.Close()
+
+	var called bool
+	deadLetter := newDeadLetterTestEvent(&called)
+	err = q.Send(ctx, nil, "test", 1, deadLetter)
+	require.NoError(t, err)
+	require.True(t, called)
+
+	fileName := common.NewClaimCheckFileName(deadLetter.Event.Event)
+	data, err := q.storage.ReadFile(ctx, fileName)
+	require.NoError(t, err)
+	m, err := common.UnmarshalDeadLetterMessage(data)
+	require.NoError(t, err)
+	require.Equal(t, "a", m.Schema)
+	require.Equal(t, "b", m.Table)
+	require.Equal(t, uint64(1), m.CommitTs)
+	require.Equal(t, "test", m.Topic)
+	require.Equal(t, int32(1), m.Partition)
+	require.Equal(t, "CDC:ErrMessageTooLarge", m.ErrorCode)
+	require.Equal(t, map[string]interface{}{"id": "1"}, m.HandleKey)
+	require.Equal(t, map[string]interface{}{"id": "1", "col1": "aa", "col2": nil}, m.Columns)
+	require.Nil(t, m.PreColumns)
+}
+
+func TestDeadLetterQueueSendToTopic(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	q, err := NewDeadLetterQueue(ctx, "dlq", "", config.ProtocolOpen, changefeedID)
+	require.NoError(t, err)
+	defer q.Close()
+
+	p := dmlproducer.NewDMLMockProducer(ctx, changefeedID, nil, nil, nil, nil)
+	var called bool
+	err = q.Send(ctx, p, "test", 1, newDeadLetterTestEvent(&called))
+	require.NoError(t, err)
+	require.True(t, called)
+
+	messages := p.(*dmlproducer.MockDMLProducer).GetEvents("dlq", 0)
+	require.Len(t, messages, 1)
+	m, err := common.UnmarshalDeadLetterMessage(messages[0].Value)
+	require.NoError(t, err)
+	require.Equal(t, "test", m.Topic)
+	require.Equal(t, map[string]interface{}{"id": "1"}, m.HandleKey)
+	// the columns are not sent to the dead-letter topic.
+	require.Nil(t, m.Columns)
+}
+
+func TestWorkerDivertsDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id := model.DefaultChangeFeedID("test")
+	// the events are too large to be encoded.
+	encoderConfig := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(100)
+	builder, err := builder.NewRowEventEncoderBuilder(ctx, id, encoderConfig)
+	require.NoError(t, err)
+	p := dmlproducer.NewDMLMockProducer(ctx, id, nil, nil, nil, nil)
+	q, err := NewDeadLetterQueue(ctx, "dlq", "", config.ProtocolCanalJSON, id)
+	require.NoError(t, err)
+	statistics := metrics.NewStatistics(ctx, id, sink.RowSink)
+	encoderGroup := codec.NewEncoderGroup(builder, 4, id, true)
+	worker := newWorker(id, config.ProtocolCanalJSON, p, encoderGroup, nil, nil, q, nil, statistics)
+	defer worker.close()
+
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+	}
+	tableStatus := state.TableSinkSinking
+	count := 16
+	var mu sync.Mutex
+	called := 0
+	for i := 0; i < count; i++ {
+		worker.msgChan.In() <- mqEvent{
+			key: TopicPartitionKey{Topic: "test", Partition: 1},
+			rowEvent: &dmlsink.RowChangeCallbackableEvent{
+				Event: row,
+				Callback: func() {
+					mu.Lock()
+					defer mu.Unlock()
+					called++
+				},
+				SinkState: &tableStatus,
+			},
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = worker.run(ctx)
+	}()
+
+	mp := p.(*dmlproducer.MockDMLProducer)
+	require.Eventually(t, func() bool {
+		return len(mp.GetEvents("dlq", 0)) == count
+	}, 3*time.Second, 100*time.Millisecond)
+	require.Empty(t, mp.GetEvents("test", 1))
+	mu.Lock()
+	require.Equal(t, count, called)
+	mu.Unlock()
+	cancel()
+
+	wg.Wait()
+}
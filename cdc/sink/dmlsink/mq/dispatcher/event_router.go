@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher/topic"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -74,6 +75,9 @@ type EventRouter struct {
 	rules        []struct {
 		partitionDispatcher partition.Dispatcher
 		topicDispatcher     topic.Dispatcher
+		keyTemplate         predis.KeyTemplate
+		topicPartitionNum   int32
+		topicConfigs        map[string]string
 		filter.Filter
 	}
 }
@@ -91,6 +95,9 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 	rules := make([]struct {
 		partitionDispatcher partition.Dispatcher
 		topicDispatcher     topic.Dispatcher
+		keyTemplate         predis.KeyTemplate
+		topicPartitionNum   int32
+		topicConfigs        map[string]string
 		filter.Filter
 	}, 0, len(ruleConfigs))
 
@@ -103,16 +110,35 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 			f = filter.CaseInsensitive(f)
 		}
 
-		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		d, err := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		if err != nil {
+			return nil, err
+		}
 		t, err := getTopicDispatcher(ruleConfig, defaultTopic, util.GetOrZero(cfg.Sink.Protocol))
 		if err != nil {
 			return nil, err
 		}
+		keyTemplate := predis.KeyTemplate(ruleConfig.KeyTemplate)
+		if keyTemplate != "" {
+			if err := keyTemplate.Validate(); err != nil {
+				return nil, err
+			}
+		}
 		rules = append(rules, struct {
 			partitionDispatcher partition.Dispatcher
 			topicDispatcher     topic.Dispatcher
+			keyTemplate         predis.KeyTemplate
+			topicPartitionNum   int32
+			topicConfigs        map[string]string
 			filter.Filter
-		}{partitionDispatcher: d, topicDispatcher: t, Filter: f})
+		}{
+			partitionDispatcher: d,
+			topicDispatcher:     t,
+			keyTemplate:         keyTemplate,
+			topicPartitionNum:   ruleConfig.TopicPartitionNum,
+			topicConfigs:        ruleConfig.TopicConfigs,
+			Filter:              f,
+		})
 	}
 
 	return &EventRouter{
@@ -124,7 +150,7 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 // GetTopicForRowChange returns the target topic for row changes.
 func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) string {
 	topicDispatcher, _ := s.matchDispatcher(row.Table.Schema, row.Table.Table)
-	return topicDispatcher.Substitute(row.Table.Schema, row.Table.Table)
+	return topicDispatcher.SubstituteRow(row)
 }
 
 // GetTopicForDDL returns the target topic for DDL.
@@ -148,6 +174,13 @@ func (s *EventRouter) GetTopicForDDL(ddl *model.DDLEvent) string {
 	return topicDispatcher.Substitute(schema, table)
 }
 
+// GetTopicForTable returns the target topic for the row changes of the table,
+// the default topic is returned if the topic depends on the column values.
+func (s *EventRouter) GetTopicForTable(schema, table string) string {
+	topicDispatcher, _ := s.matchDispatcher(schema, table)
+	return topicDispatcher.Substitute(schema, table)
+}
+
 // GetPartitionForRowChange returns the target partition for row changes.
 func (s *EventRouter) GetPartitionForRowChange(
 	row *model.RowChangedEvent,
@@ -162,6 +195,46 @@ func (s *EventRouter) GetPartitionForRowChange(
 	)
 }
 
+// GetKeyForRowChange returns the key of the message of the row changes
+// rendered by the key template of the matched rule, nil is returned if the
+// rule has no key template, so the key is set by the protocol.
+func (s *EventRouter) GetKeyForRowChange(row *model.RowChangedEvent) ([]byte, error) {
+	var keyTemplate predis.KeyTemplate
+	for _, rule := range s.rules {
+		if rule.MatchTable(row.Table.Schema, row.Table.Table) {
+			keyTemplate = rule.keyTemplate
+			break
+		}
+	}
+	if keyTemplate == "" {
+		return nil, nil
+	}
+	cols := row.Columns
+	if len(cols) == 0 {
+		cols = row.PreColumns
+	}
+	key, err := keyTemplate.Substitute(row.Table, cols)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(key), nil
+}
+
+// GetTopicOverrides returns the partition number and the topic level
+// configurations of the topic, which are overridden by the first rule that
+// overrides them and may produce the topic.
+func (s *EventRouter) GetTopicOverrides(topic string) (int32, map[string]string) {
+	for _, rule := range s.rules {
+		if rule.topicPartitionNum == 0 && len(rule.topicConfigs) == 0 {
+			continue
+		}
+		if rule.topicDispatcher.Match(topic) {
+			return rule.topicPartitionNum, rule.topicConfigs
+		}
+	}
+	return 0, nil
+}
+
 // GetDLLDispatchRuleByProtocol returns the DDL
 // distribution rule according to the protocol.
 func (s *EventRouter) GetDLLDispatchRuleByProtocol(
@@ -175,6 +248,8 @@ func (s *EventRouter) GetDLLDispatchRuleByProtocol(
 
 // GetActiveTopics returns a list of the corresponding topics
 // for the tables that are actively synchronized.
+// Topics which are substituted by column values can not be known
+// in advance, so the default topic is returned for them instead.
 func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	topics := make([]string, 0)
 	topicsMap := make(map[string]bool, len(activeTables))
@@ -202,6 +277,16 @@ func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	return topics
 }
 
+// MatchTopic returns true if the topic may be produced by the event router.
+func (s *EventRouter) MatchTopic(topic string) bool {
+	for _, rule := range s.rules {
+		if rule.topicDispatcher.Match(topic) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetDefaultTopic returns the default topic name.
 func (s *EventRouter) GetDefaultTopic() string {
 	return s.defaultTopic
@@ -225,7 +310,11 @@ func (s *EventRouter) matchDispatcher(
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
 func getPartitionDispatcher(
 	ruleConfig *config.DispatchRule, enableOldValue bool,
-) partition.Dispatcher {
+) (partition.Dispatcher, error) {
+	if ruleConfig.PartitionPlugin != "" {
+		return partition.NewPluginDispatcher(ruleConfig.PartitionPlugin)
+	}
+
 	var (
 		d    partition.Dispatcher
 		rule partitionDispatchRule
@@ -247,7 +336,7 @@ func getPartitionDispatcher(
 		d = partition.NewDefaultDispatcher(enableOldValue)
 	}
 
-	return d
+	return d, nil
 }
 
 // getTopicDispatcher returns the topic dispatcher for a specific topic rule (aka topic expression).
@@ -267,7 +356,7 @@ func getTopicDispatcher(
 			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 		}
 
-		if p == config.ProtocolAvro {
+		if p == config.ProtocolAvro || p == config.ProtocolJSONSchema {
 			err := topicExpr.ValidateForAvro()
 			if err != nil {
 				return nil, err
@@ -279,5 +368,8 @@ func getTopicDispatcher(
 			}
 		}
 	}
+	if len(topicExpr.ColumnNames()) != 0 {
+		return topic.NewColumnTopicDispatcher(topicExpr, defaultTopic), nil
+	}
 	return topic.NewDynamicTopicDispatcher(topicExpr), nil
 }
@@ -96,6 +96,20 @@ func TestEventRouter(t *testing.T) {
 	topicDispatcher, partitionDispatcher = d.matchDispatcher("test_index_value", "test")
 	require.IsType(t, &topic.DynamicTopicDispatcher{}, topicDispatcher)
 	require.IsType(t, &partition.IndexValueDispatcher{}, partitionDispatcher)
+
+	// the partitioner plugin which can't be loaded is rejected.
+	_, err = NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:         []string{"test.*"},
+					PartitionRule:   "table",
+					PartitionPlugin: "/not-exist/partitioner.so",
+				},
+			},
+		},
+	}, "")
+	require.ErrorContains(t, err, "ErrSinkInvalidConfig")
 }
 
 func TestGetActiveTopics(t *testing.T) {
@@ -148,6 +162,67 @@ func TestGetActiveTopics(t *testing.T) {
 	require.Equal(t, []string{"test", "hello_test_table_world", "test_index_value_world", "hello_test", "sbs_table"}, topics)
 }
 
+func TestMatchTopic(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:   []string{"test_table.*"},
+					TopicRule: "hello_{schema}_world",
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+	require.True(t, d.MatchTopic("test"))
+	require.True(t, d.MatchTopic("hello_test_table_world"))
+	require.False(t, d.MatchTopic("hello_world"))
+	require.False(t, d.MatchTopic("test_table"))
+}
+
+func TestGetTopicOverrides(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:   []string{"test_plain.*"},
+					TopicRule: "plain_{schema}",
+				},
+				{
+					Matcher:           []string{"test_table.*"},
+					TopicRule:         "hello_{schema}_world",
+					TopicPartitionNum: 6,
+					TopicConfigs:      map[string]string{"retention.ms": "86400000"},
+				},
+				{
+					Matcher:      []string{"test_compact.*"},
+					TopicRule:    "compact_{schema}_{table}",
+					TopicConfigs: map[string]string{"cleanup.policy": "compact"},
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+
+	partitionNum, configs := d.GetTopicOverrides("hello_test_table_world")
+	require.Equal(t, int32(6), partitionNum)
+	require.Equal(t, map[string]string{"retention.ms": "86400000"}, configs)
+
+	partitionNum, configs = d.GetTopicOverrides("compact_test_compact_t1")
+	require.Equal(t, int32(0), partitionNum)
+	require.Equal(t, map[string]string{"cleanup.policy": "compact"}, configs)
+
+	for _, topic := range []string{"test", "plain_test_plain"} {
+		partitionNum, configs = d.GetTopicOverrides(topic)
+		require.Equal(t, int32(0), partitionNum)
+		require.Nil(t, configs)
+	}
+}
+
 func TestGetTopicForRowChange(t *testing.T) {
 	t.Parallel()
 
@@ -209,6 +284,107 @@ func TestGetTopicForRowChange(t *testing.T) {
 	require.Equal(t, "a_table", topicName)
 }
 
+func TestGetTopicForRowChangeByColumnValue(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:   []string{"test.*"},
+					TopicRule: "events_{tenant_id}",
+				},
+			},
+		},
+	}, "test")
+	require.Nil(t, err)
+	topicDispatcher, _ := d.matchDispatcher("test", "table")
+	require.IsType(t, &topic.ColumnTopicDispatcher{}, topicDispatcher)
+
+	topicName := d.GetTopicForRowChange(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "table"},
+		Columns: []*model.Column{{Name: "tenant_id", Value: int64(42)}},
+	})
+	require.Equal(t, "events_42", topicName)
+
+	// ddls and checkpoints are sent to the default topic.
+	topicName = d.GetTopicForDDL(&model.DDLEvent{
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "table"},
+		},
+	})
+	require.Equal(t, "test", topicName)
+	topics := d.GetActiveTopics([]model.TableName{{Schema: "test", Table: "table"}})
+	require.Equal(t, []string{"test"}, topics)
+
+	require.True(t, d.MatchTopic("events_42"))
+	require.False(t, d.MatchTopic("hello_42"))
+}
+
+func TestGetKeyForRowChange(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "table",
+					KeyTemplate:   "{schema}.{table}:{id}",
+				},
+				{
+					Matcher:     []string{"pk.*"},
+					KeyTemplate: "{pk}",
+				},
+			},
+		},
+	}, "test")
+	require.NoError(t, err)
+
+	columns := []*model.Column{
+		{Name: "id", Value: int64(1), Flag: model.HandleKeyFlag},
+		{Name: "name", Value: "a"},
+	}
+	key, err := d.GetKeyForRowChange(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t1"},
+		Columns: columns,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("test.t1:1"), key)
+
+	// the pre-columns are used by the delete events.
+	key, err = d.GetKeyForRowChange(&model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "pk", Table: "t1"},
+		PreColumns: columns,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), key)
+
+	// the keys of the tables without key template are set by the protocol.
+	key, err = d.GetKeyForRowChange(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "other", Table: "t1"},
+		Columns: columns,
+	})
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	_, err = d.GetKeyForRowChange(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "pk", Table: "t1"},
+		Columns: columns[1:],
+	})
+	require.ErrorContains(t, err, "has no primary key")
+
+	// the key template without placeholder is rejected.
+	_, err = NewEventRouter(&config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{Matcher: []string{"test.*"}, KeyTemplate: "static"},
+			},
+		},
+	}, "test")
+	require.ErrorContains(t, err, "it must contain a placeholder")
+}
+
 func TestGetPartitionForRowChange(t *testing.T) {
 	t.Parallel()
 
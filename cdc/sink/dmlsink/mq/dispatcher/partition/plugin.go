@@ -0,0 +1,89 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"plugin"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// PluginSymbol is the name of the symbol which is looked up in the
+// partitioner plugin, it must be a PartitionFunc.
+const PluginSymbol = "Partition"
+
+// PartitionFunc computes the partition of a row by the schema and table name
+// and the column values, the values are keyed by the column names. Only the
+// builtin types are in the signature, so the plugin doesn't import TiCDC.
+// It's called concurrently, so it must be thread-safe.
+type PartitionFunc = func(schema, table string, values map[string]interface{}, partitionNum int32) int32
+
+// PluginDispatcher is a partition dispatcher which dispatches events by the
+// partitioner of a Go plugin, for the sharding schemes that the builtin
+// dispatchers can't express.
+type PluginDispatcher struct {
+	partition PartitionFunc
+}
+
+// NewPluginDispatcher loads the Go plugin of the path, which is built by
+// `go build -buildmode=plugin` with the same Go version as TiCDC.
+func NewPluginDispatcher(path string) (*PluginDispatcher, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	symbol, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	// the symbol of a function is the function, and the symbol of a variable
+	// is the pointer to the variable.
+	switch f := symbol.(type) {
+	case PartitionFunc:
+		return newPluginDispatcher(f), nil
+	case *PartitionFunc:
+		if *f != nil {
+			return newPluginDispatcher(*f), nil
+		}
+	}
+	return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+		"the symbol %s of the partitioner plugin %s is not a %T", PluginSymbol, path, PartitionFunc(nil))
+}
+
+func newPluginDispatcher(f PartitionFunc) *PluginDispatcher {
+	return &PluginDispatcher{partition: f}
+}
+
+// DispatchRowChangedEvent returns the target partition to which
+// a row changed event should be dispatched.
+func (p *PluginDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
+	columns := row.Columns
+	if len(columns) == 0 {
+		columns = row.PreColumns
+	}
+	values := make(map[string]interface{}, len(columns))
+	for _, col := range columns {
+		if col == nil {
+			continue
+		}
+		values[col.Name] = col.Value
+	}
+	// the partition returned by the plugin may be out of range.
+	partition := p.partition(row.Table.Schema, row.Table.Table, values, partitionNum) % partitionNum
+	if partition < 0 {
+		partition += partitionNum
+	}
+	return partition
+}
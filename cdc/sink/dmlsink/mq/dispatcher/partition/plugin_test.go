@@ -0,0 +1,71 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginDispatcher(t *testing.T) {
+	t.Parallel()
+
+	// dispatches the rows by the tenant, the shifted partitions may be out of range.
+	p := newPluginDispatcher(func(
+		schema, table string, values map[string]interface{}, partitionNum int32,
+	) int32 {
+		require.Equal(t, "test", schema)
+		require.Equal(t, "t1", table)
+		return int32(values["tenant_id"].(int64)) - 2
+	})
+
+	testCases := []struct {
+		row             *model.RowChangedEvent
+		expectPartition int32
+	}{
+		{row: &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "t1"},
+			Columns: []*model.Column{
+				{Name: "id", Value: int64(1)},
+				{Name: "tenant_id", Value: int64(3)},
+			},
+		}, expectPartition: 1},
+		{row: &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "t1"},
+			Columns: []*model.Column{
+				{Name: "id", Value: int64(2)},
+				{Name: "tenant_id", Value: int64(18)},
+			},
+		}, expectPartition: 0},
+		{row: &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "t1"},
+			PreColumns: []*model.Column{
+				{Name: "id", Value: int64(3)},
+				{Name: "tenant_id", Value: int64(1)},
+			},
+		}, expectPartition: 15},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expectPartition, p.DispatchRowChangedEvent(tc.row, 16))
+	}
+}
+
+func TestNewPluginDispatcher(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPluginDispatcher("/not-exist/partitioner.so")
+	require.ErrorContains(t, err, "ErrSinkInvalidConfig")
+}
@@ -15,12 +15,18 @@ package topic
 
 import (
 	"fmt"
+
+	"github.com/pingcap/tiflow/cdc/model"
 )
 
 // Dispatcher is an abstraction for dispatching rows and ddls into different topics.
 type Dispatcher interface {
 	fmt.Stringer
 	Substitute(schema, table string) string
+	// SubstituteRow returns the target topic of the row changed event.
+	SubstituteRow(row *model.RowChangedEvent) string
+	// Match returns true if the topic may be produced by the dispatcher.
+	Match(topic string) bool
 }
 
 // StaticTopicDispatcher is a topic dispatcher which dispatches rows and ddls to the default topic.
@@ -40,6 +46,16 @@ func (s *StaticTopicDispatcher) Substitute(schema, table string) string {
 	return s.defaultTopic
 }
 
+// SubstituteRow returns the default topic.
+func (s *StaticTopicDispatcher) SubstituteRow(row *model.RowChangedEvent) string {
+	return s.defaultTopic
+}
+
+// Match returns true if the topic is the default topic.
+func (s *StaticTopicDispatcher) Match(topic string) bool {
+	return s.defaultTopic == topic
+}
+
 func (s *StaticTopicDispatcher) String() string {
 	return s.defaultTopic
 }
@@ -62,6 +78,53 @@ func (d *DynamicTopicDispatcher) Substitute(schema, table string) string {
 	return d.expression.Substitute(schema, table)
 }
 
+// SubstituteRow converts schema/table name of the row in a topic expression to kafka topic name.
+func (d *DynamicTopicDispatcher) SubstituteRow(row *model.RowChangedEvent) string {
+	return d.expression.Substitute(row.Table.Schema, row.Table.Table)
+}
+
+// Match returns true if the topic can be converted from the topic expression.
+func (d *DynamicTopicDispatcher) Match(topic string) bool {
+	return d.expression.Match(topic)
+}
+
 func (d *DynamicTopicDispatcher) String() string {
 	return string(d.expression)
 }
+
+// ColumnTopicDispatcher is a topic dispatcher which dispatches rows to the
+// target topics according to the column values of the rows. Since ddls
+// do not have column values, they are dispatched to the default topic.
+type ColumnTopicDispatcher struct {
+	expression   Expression
+	defaultTopic string
+}
+
+// NewColumnTopicDispatcher creates a ColumnTopicDispatcher.
+func NewColumnTopicDispatcher(topicExpr Expression, defaultTopic string) *ColumnTopicDispatcher {
+	return &ColumnTopicDispatcher{
+		expression:   topicExpr,
+		defaultTopic: defaultTopic,
+	}
+}
+
+// Substitute returns the default topic, because the column values are unknown.
+func (c *ColumnTopicDispatcher) Substitute(schema, table string) string {
+	return c.defaultTopic
+}
+
+// SubstituteRow converts schema/table name and column values of the row
+// in a topic expression to kafka topic name.
+func (c *ColumnTopicDispatcher) SubstituteRow(row *model.RowChangedEvent) string {
+	return c.expression.SubstituteRow(row)
+}
+
+// Match returns true if the topic is the default topic or
+// can be converted from the topic expression.
+func (c *ColumnTopicDispatcher) Match(topic string) bool {
+	return c.defaultTopic == topic || c.expression.Match(topic)
+}
+
+func (c *ColumnTopicDispatcher) String() string {
+	return string(c.expression)
+}
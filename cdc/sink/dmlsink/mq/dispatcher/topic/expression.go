@@ -17,6 +17,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -31,6 +32,14 @@ var (
 	schemaRE = regexp.MustCompile(`\{schema\}`)
 	// tableRE is used to match substring '{table}' in topic expression
 	tableRE = regexp.MustCompile(`\{table\}`)
+	// placeholderRE is used to match all placeholders in topic expression,
+	// placeholders other than '{schema}' and '{table}' refer to column names.
+	placeholderRE = regexp.MustCompile(`\{([A-Za-z0-9_$]+)\}`)
+	// columnTopicNameRE is used to match a valid topic expression which
+	// contains column placeholders, such as 'events_{tenant_id}'.
+	columnTopicNameRE = regexp.MustCompile(
+		`^([A-Za-z0-9\._\-]|\{[A-Za-z0-9_$]+\})*$`,
+	)
 	// avro has different topic name pattern requirements, '{schema}' and '{table}' placeholders
 	// are necessary
 	avroTopicNameRE = regexp.MustCompile(
@@ -51,14 +60,29 @@ var (
 // See https://github.com/apache/kafka/blob/trunk/clients/src/main/java/org/apache/kafka/common/internals/Topic.java#L35
 const kafkaTopicNameMaxLength = 249
 
+const (
+	schemaPlaceholder = "schema"
+	tablePlaceholder  = "table"
+)
+
 // Expression represent a kafka topic expression.
 // The expression should be in form of: [prefix]{schema}[middle][{table}][suffix]
 // prefix/suffix/middle are optional and should match the regex of [A-Za-z0-9\._\-]*
 // {table} can also be optional.
+// The expression can also contain column placeholders in form of {column_name},
+// such as 'events_{tenant_id}', which are substituted by the column values of
+// the row, in this case {schema} is optional.
 type Expression string
 
 // Validate checks whether a kafka topic name is valid or not.
 func (e Expression) Validate() error {
+	// validate the topic expression which contains column placeholders
+	if len(e.ColumnNames()) != 0 {
+		if ok := columnTopicNameRE.MatchString(string(e)); !ok {
+			return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
+		}
+		return nil
+	}
 	// validate the topic expression
 	if ok := topicNameRE.MatchString(string(e)); !ok {
 		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
@@ -90,7 +114,56 @@ func (e Expression) Substitute(schema, table string) string {
 	// doing the real conversion things
 	topicName := schemaRE.ReplaceAllString(topicExpr, replacedSchema)
 	topicName = tableRE.ReplaceAllString(topicName, replacedTable)
+	return normalizeTopicName(topicName)
+}
+
+// ColumnNames returns the names of the columns referenced by the column
+// placeholders in the topic expression.
+func (e Expression) ColumnNames() []string {
+	var names []string
+	for _, match := range placeholderRE.FindAllStringSubmatch(string(e), -1) {
+		name := match[1]
+		if name == schemaPlaceholder || name == tablePlaceholder {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
 
+// SubstituteRow converts schema/table name and column values of the row in a
+// topic expression to kafka topic name. Column names are matched case-insensitively,
+// the placeholder of a column which is NULL or not found in the row is substituted
+// for 'null', and the special characters in column values are substituted for
+// underscore '_' as in Substitute.
+func (e Expression) SubstituteRow(row *model.RowChangedEvent) string {
+	cols := row.Columns
+	if len(cols) == 0 {
+		cols = row.PreColumns
+	}
+	topicName := placeholderRE.ReplaceAllStringFunc(string(e), func(placeholder string) string {
+		var value string
+		switch name := placeholder[1 : len(placeholder)-1]; name {
+		case schemaPlaceholder:
+			value = row.Table.Schema
+		case tablePlaceholder:
+			value = row.Table.Table
+		default:
+			value = model.ColumnValueString(nil)
+			for _, col := range cols {
+				if col != nil && strings.EqualFold(col.Name, name) {
+					value = model.ColumnValueString(col.Value)
+					break
+				}
+			}
+		}
+		return kafkaForbidRE.ReplaceAllString(value, "_")
+	})
+	return normalizeTopicName(topicName)
+}
+
+// normalizeTopicName makes sure the substituted topic name is a valid kafka topic name.
+func normalizeTopicName(topicName string) string {
 	// topicName will be truncated if it exceed the limit.
 	// And topicName '.' and '..' are also invalid, replace them with '_'.
 	//    See https://github.com/apache/kafka/blob/trunk/clients/src/main/java/org/apache/kafka/common/internals/Topic.java#L46
@@ -105,6 +178,33 @@ func (e Expression) Substitute(schema, table string) string {
 	}
 }
 
+// Match checks whether the topic name can be converted from the topic expression.
+func (e Expression) Match(topicName string) bool {
+	// Substitute only outputs characters in [A-Za-z0-9\._\-] for schema and table.
+	const placeholder = `[A-Za-z0-9\._\-]+`
+	// column values can be empty strings.
+	const columnPlaceholder = `[A-Za-z0-9\._\-]*`
+	var pattern strings.Builder
+	expr := string(e)
+	last := 0
+	for _, loc := range placeholderRE.FindAllStringSubmatchIndex(expr, -1) {
+		pattern.WriteString(regexp.QuoteMeta(expr[last:loc[0]]))
+		switch expr[loc[2]:loc[3]] {
+		case schemaPlaceholder, tablePlaceholder:
+			pattern.WriteString(placeholder)
+		default:
+			pattern.WriteString(columnPlaceholder)
+		}
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(expr[last:]))
+	matched, err := regexp.MatchString("^"+pattern.String()+"$", topicName)
+	if err != nil {
+		return false
+	}
+	return matched
+}
+
 // PulsarValidate checks whether a pulsar topic name is valid or not.
 func (e Expression) PulsarValidate() error {
 	// validate the topic expression
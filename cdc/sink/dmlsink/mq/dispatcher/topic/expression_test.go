@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -111,7 +112,7 @@ func TestSubstituteTopicExpression(t *testing.T) {
 		},
 		{
 			name:       "invalid expression not containing '{schema}', with no prefix and suffix",
-			expression: "{xxx}",
+			expression: "{x x}",
 			schema:     "abc",
 			table:      "",
 			wantErr:    "invalid topic expression",
@@ -119,7 +120,7 @@ func TestSubstituteTopicExpression(t *testing.T) {
 		},
 		{
 			name:       "invalid expressions not containing '{schema}', with prefix",
-			expression: "abc{sch}",
+			expression: "abc{sch-ema}",
 			schema:     "def",
 			table:      "",
 			wantErr:    "invalid topic expression",
@@ -183,7 +184,7 @@ func TestSubstituteTopicExpression(t *testing.T) {
 		},
 		{
 			name:       "invalid expression not containing '{schema}' and '{table}'",
-			expression: "{sch}_{tab}",
+			expression: "{sch}_{tab",
 			schema:     "hello",
 			table:      "world",
 			wantErr:    "invalid topic expression",
@@ -227,6 +228,49 @@ func TestSubstituteTopicExpression(t *testing.T) {
 	}
 }
 
+func TestSubstituteRowTopicExpression(t *testing.T) {
+	t.Parallel()
+
+	row := &model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t1"},
+		Columns: []*model.Column{
+			{Name: "id", Value: int64(1)},
+			{Name: "Tenant_ID", Value: "tenant/a"},
+			{Name: "region", Value: nil},
+		},
+	}
+
+	cases := []struct {
+		expression string
+		expected   string
+	}{
+		{expression: "events_{tenant_id}", expected: "events_tenant_a"},
+		{expression: "{schema}_{table}_{id}", expected: "test_t1_1"},
+		{expression: "events_{region}", expected: "events_null"},
+		{expression: "events_{not_exist}", expected: "events_null"},
+		{expression: "{schema}_{table}", expected: "test_t1"},
+	}
+	for _, tc := range cases {
+		topicExpr := Expression(tc.expression)
+		require.NoError(t, topicExpr.Validate(), tc.expression)
+		require.Equal(t, tc.expected, topicExpr.SubstituteRow(row), tc.expression)
+		require.True(t, topicExpr.Match(tc.expected), tc.expression)
+	}
+
+	// use pre columns for delete events.
+	deleteRow := &model.RowChangedEvent{
+		Table:      row.Table,
+		PreColumns: row.Columns,
+	}
+	require.Equal(t, "events_tenant_a",
+		Expression("events_{tenant_id}").SubstituteRow(deleteRow))
+
+	require.Equal(t, []string{"tenant_id"},
+		Expression("{schema}_{tenant_id}_{table}").ColumnNames())
+	require.Empty(t, Expression("{schema}_{table}").ColumnNames())
+	require.Error(t, Expression("events_{tenant_id}").ValidateForAvro())
+}
+
 // cmd: go test -run='^$' -bench '^(BenchmarkSubstitute)$' github.com/pingcap/tiflow/cdc/sink/dispatcher/topic
 // goos: linux
 // goarch: amd64
@@ -263,3 +307,23 @@ func BenchmarkSubstitute(b *testing.B) {
 		})
 	}
 }
+
+func TestTopicExpressionMatch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expression string
+		topic      string
+		match      bool
+	}{
+		{expression: "{schema}", topic: "abc", match: true},
+		{expression: "hello_{schema}_world", topic: "hello_abc_world", match: true},
+		{expression: "hello_{schema}_world", topic: "hello__world", match: false},
+		{expression: "{schema}.{table}", topic: "abc.def", match: true},
+		{expression: "{schema}.{table}", topic: "abc_def", match: false},
+		{expression: "prefix-{schema}-{table}", topic: "another-abc-def", match: false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.match, Expression(c.expression).Match(c.topic), c)
+	}
+}
@@ -28,6 +28,11 @@ type DMLProducer interface {
 		ctx context.Context, topic string, partition int32, message *common.Message,
 	) error
 
+	// Commit commits the messages sent since the last commit if the producer
+	// is transactional, the messages are visible to the consumers with
+	// `isolation.level=read_committed` only after they are committed.
+	Commit() error
+
 	// Close closes the producer and client(s).
 	Close()
 }
@@ -27,8 +27,9 @@ var _ DMLProducer = (*MockDMLProducer)(nil)
 
 // MockDMLProducer is a mock producer for test.
 type MockDMLProducer struct {
-	mu     sync.Mutex
-	events map[string][]*common.Message
+	mu      sync.Mutex
+	events  map[string][]*common.Message
+	commits int
 
 	asyncProducer kafka.AsyncProducer
 }
@@ -58,11 +59,21 @@ func (m *MockDMLProducer) AsyncSendMessage(_ context.Context, topic string,
 	}
 	m.events[key] = append(m.events[key], message)
 
-	message.Callback()
+	if message.Callback != nil {
+		message.Callback()
+	}
 
 	return nil
 }
 
+// Commit records the number of the commits.
+func (m *MockDMLProducer) Commit() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commits++
+	return nil
+}
+
 // Close do nothing.
 func (m *MockDMLProducer) Close() {
 	if m.asyncProducer != nil {
@@ -88,3 +99,10 @@ func (m *MockDMLProducer) GetEvents(topic string, partition int32) []*common.Mes
 	key := fmt.Sprintf("%s-%d", topic, partition)
 	return m.events[key]
 }
+
+// GetCommitCount returns the number of the commits.
+func (m *MockDMLProducer) GetCommitCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.commits
+}
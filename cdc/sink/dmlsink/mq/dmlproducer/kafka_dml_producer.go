@@ -119,8 +119,17 @@ func (k *kafkaDMLProducer) AsyncSendMessage(
 		k.failpointCh <- errors.New("kafka sink injected error")
 		failpoint.Return(nil)
 	})
-	return k.asyncProducer.AsyncSend(ctx, topic, partition,
-		message.Key, message.Value, message.Callback)
+	return k.asyncProducer.AsyncSend(ctx, topic, partition, message)
+}
+
+func (k *kafkaDMLProducer) Commit() error {
+	k.closedMu.RLock()
+	defer k.closedMu.RUnlock()
+
+	if k.closed {
+		return cerror.ErrKafkaProducerClosed.GenWithStackByArgs()
+	}
+	return k.asyncProducer.CommitTxn()
 }
 
 func (k *kafkaDMLProducer) Close() {
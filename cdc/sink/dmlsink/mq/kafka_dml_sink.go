@@ -25,8 +25,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	tiflowutil "github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
@@ -81,18 +83,26 @@ func NewKafkaDMLSink(
 		return nil, errors.Trace(err)
 	}
 
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// the topics created for the dispatch rules may override the configurations.
+	topicCfg := options.DeriveTopicConfig()
+	topicCfg.Overrides = eventRouter.GetTopicOverrides
 	topicManager, err := util.GetTopicManagerAndTryCreateTopic(
 		ctx,
 		changefeedID,
 		topic,
-		options.DeriveTopicConfig(),
+		topicCfg,
 		adminClient,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	columnSelector, err := filter.NewColumnSelector(replicaConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -127,6 +137,25 @@ func NewKafkaDMLSink(
 		}
 	}
 
+	var deadLetterQueue *DeadLetterQueue
+	if options.DeadLetterTopic != "" || options.DeadLetterStorageURI != "" {
+		if options.DeadLetterTopic == topic {
+			return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"dead-letter-topic should not be the same as the topic %s", topic)
+		}
+		if options.DeadLetterTopic != "" {
+			// make sure the dead-letter topic is created as configured by ticdc.
+			if _, err = topicManager.GetPartitionNum(ctx, options.DeadLetterTopic); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		deadLetterQueue, err = NewDeadLetterQueue(ctx, options.DeadLetterTopic,
+			options.DeadLetterStorageURI, protocol, changefeedID)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+	}
+
 	failpointCh := make(chan error, 1)
 	asyncProducer, err := factory.AsyncProducer(ctx, failpointCh)
 	if err != nil {
@@ -136,9 +165,15 @@ func NewKafkaDMLSink(
 	metricsCollector := factory.MetricsCollector(tiflowutil.RoleProcessor, adminClient)
 	dmlProducer := producerCreator(ctx, changefeedID, asyncProducer, metricsCollector, errCh, failpointCh)
 	concurrency := tiflowutil.GetOrZero(replicaConfig.Sink.EncoderConcurrency)
-	encoderGroup := codec.NewEncoderGroup(encoderBuilder, concurrency, changefeedID)
+	encoderGroup := codec.NewEncoderGroup(encoderBuilder, concurrency, changefeedID,
+		deadLetterQueue != nil)
 	s := newDMLSink(ctx, changefeedID, dmlProducer, adminClient, topicManager,
-		eventRouter, encoderGroup, protocol, claimCheck, claimCheckEncoder, errCh,
+		eventRouter, encoderGroup, protocol, options.EnableTransactions,
+		claimCheck, claimCheckEncoder, deadLetterQueue,
+		common.NewHeadersBuilder(changefeedID,
+			replicaConfig.Sink.TiDBSourceID, replicaConfig.Sink.MessageHeaders),
+		replicaConfig.Sink.TableMetrics, replicaConfig.Sink.Bootstrap, columnSelector,
+		encoderConfig.DeleteAsTombstone, errCh,
 	)
 	log.Info("DML sink producer created",
 		zap.String("namespace", changefeedID.Namespace),
@@ -232,19 +232,17 @@ func (m *kafkaTopicManager) createTopic(
 	}
 
 	start := time.Now()
-	err := m.admin.CreateTopic(ctx, &kafka.TopicDetail{
-		Name:              topicName,
-		NumPartitions:     m.cfg.PartitionNum,
-		ReplicationFactor: m.cfg.ReplicationFactor,
-	}, false)
+	detail := m.cfg.TopicDetail(topicName)
+	err := m.admin.CreateTopic(ctx, detail, false)
 	if err != nil {
 		log.Error(
 			"Kafka admin client create the topic failed",
 			zap.String("namespace", m.changefeedID.Namespace),
 			zap.String("changefeed", m.changefeedID.ID),
 			zap.String("topic", topicName),
-			zap.Int32("partitionNumber", m.cfg.PartitionNum),
-			zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+			zap.Int32("partitionNumber", detail.NumPartitions),
+			zap.Int16("replicationFactor", detail.ReplicationFactor),
+			zap.Any("configs", detail.ConfigEntries),
 			zap.Error(err),
 			zap.Duration("duration", time.Since(start)),
 		)
@@ -256,13 +254,14 @@ func (m *kafkaTopicManager) createTopic(
 		zap.String("namespace", m.changefeedID.Namespace),
 		zap.String("changefeed", m.changefeedID.ID),
 		zap.String("topic", topicName),
-		zap.Int32("partitionNumber", m.cfg.PartitionNum),
-		zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+		zap.Int32("partitionNumber", detail.NumPartitions),
+		zap.Int16("replicationFactor", detail.ReplicationFactor),
+		zap.Any("configs", detail.ConfigEntries),
 		zap.Duration("duration", time.Since(start)),
 	)
-	m.tryUpdatePartitionsAndLogging(topicName, m.cfg.PartitionNum)
+	m.tryUpdatePartitionsAndLogging(topicName, detail.NumPartitions)
 
-	return m.cfg.PartitionNum, nil
+	return detail.NumPartitions, nil
 }
 
 // CreateTopicAndWaitUntilVisible wraps createTopic and waitUntilTopicVisible together.
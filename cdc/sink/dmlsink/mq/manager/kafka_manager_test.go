@@ -108,6 +108,51 @@ func TestCreateTopic(t *testing.T) {
 	)
 }
 
+func TestCreateTopicWithOverrides(t *testing.T) {
+	t.Parallel()
+
+	adminClient := kafka.NewClusterAdminClientMockImpl()
+	defer adminClient.Close()
+	cfg := &kafka.AutoCreateTopicConfig{
+		AutoCreate:        true,
+		PartitionNum:      2,
+		ReplicationFactor: 1,
+		ConfigEntries:     map[string]string{"retention.ms": "604800000"},
+		Overrides: func(topic string) (int32, map[string]string) {
+			if topic == "compacted-topic" {
+				return 4, map[string]string{"cleanup.policy": "compact"}
+			}
+			return 0, nil
+		},
+	}
+
+	ctx := context.Background()
+	manager, err := NewKafkaTopicManager(ctx,
+		model.DefaultChangeFeedID("test"),
+		adminClient, cfg)
+	require.Nil(t, err)
+	defer manager.Close()
+
+	partitionNum, err := manager.CreateTopicAndWaitUntilVisible(ctx, "new-topic")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitionNum)
+	retention, err := adminClient.GetTopicConfig(ctx, "new-topic", "retention.ms")
+	require.Nil(t, err)
+	require.Equal(t, "604800000", retention)
+	_, err = adminClient.GetTopicConfig(ctx, "new-topic", "cleanup.policy")
+	require.Error(t, err)
+
+	partitionNum, err = manager.CreateTopicAndWaitUntilVisible(ctx, "compacted-topic")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+	cleanupPolicy, err := adminClient.GetTopicConfig(ctx, "compacted-topic", "cleanup.policy")
+	require.Nil(t, err)
+	require.Equal(t, "compact", cleanupPolicy)
+	retention, err = adminClient.GetTopicConfig(ctx, "compacted-topic", "retention.ms")
+	require.Nil(t, err)
+	require.Equal(t, "604800000", retention)
+}
+
 func TestCreateTopicWithDelay(t *testing.T) {
 	t.Parallel()
 
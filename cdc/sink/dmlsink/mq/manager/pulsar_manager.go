@@ -18,7 +18,10 @@ import (
 	"sync"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
 	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"go.uber.org/zap"
 )
 
 // PulsarTopicManager is a manager for pulsar topics.
@@ -30,8 +33,10 @@ type PulsarTopicManager func(
 // pulsarTopicManager is a manager for pulsar topics.
 type pulsarTopicManager struct {
 	client     pulsar.Client
-	partitions sync.Map // key : topic, value : partition-name
+	partitions sync.Map // key : topic, value : partition number
 	cfg        *pulsarConfig.Config
+	// admin is nil if the admin URL is not configured.
+	admin *pulsarConfig.AdminClient
 }
 
 // NewPulsarTopicManager creates a new topic manager.
@@ -44,20 +49,73 @@ func NewPulsarTopicManager(
 		cfg:        cfg,
 		partitions: sync.Map{},
 	}
+	if cfg.AdminURL != "" {
+		admin, err := pulsarConfig.NewAdminClient(cfg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		mgr.admin = admin
+	}
 
 	return mgr, nil
 }
 
-// GetPartitionNum spend more time,but no use.
-// Neither synchronous nor asynchronous sending of pulsar will use PartitionNum
-// but this method is used in mq_ddl_sink.go, so an empty implementation is required
+// GetPartitionNum returns the partition number of the topic, 0 means the topic
+// is non-partitioned. The topic is created if it does not exist and the
+// auto-creation is enabled.
 func (m *pulsarTopicManager) GetPartitionNum(ctx context.Context, topic string) (int32, error) {
-	return 0, nil
+	if partitions, ok := m.partitions.Load(topic); ok {
+		return partitions.(int32), nil
+	}
+
+	if m.cfg.AutoCreateTopic {
+		return m.CreateTopicAndWaitUntilVisible(ctx, topic)
+	}
+
+	var (
+		partitionNum int32
+		err          error
+	)
+	if m.admin != nil {
+		partitionNum, err = m.admin.GetPartitionNum(ctx, topic)
+	} else {
+		partitionNum, err = pulsarConfig.GetPartitionNum(m.client, topic)
+	}
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	m.partitions.Store(topic, partitionNum)
+	return partitionNum, nil
 }
 
-// CreateTopicAndWaitUntilVisible no need to create first
+// CreateTopicAndWaitUntilVisible creates the topic with the configured partition
+// number and retention by the admin API, and returns the partition number of the topic.
+// The existing topic is not modified except its retention.
 func (m *pulsarTopicManager) CreateTopicAndWaitUntilVisible(ctx context.Context, topicName string) (int32, error) {
-	return 0, nil
+	if m.admin == nil {
+		return 0, errors.New("pulsar admin url is required to create the topic")
+	}
+	if err := m.admin.CreateTopic(ctx, topicName, m.cfg.TopicPartitionNum); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if m.cfg.TopicRetentionTime > 0 || m.cfg.TopicRetentionSizeMB != nil {
+		retentionSizeMB := int64(-1)
+		if m.cfg.TopicRetentionSizeMB != nil {
+			retentionSizeMB = *m.cfg.TopicRetentionSizeMB
+		}
+		err := m.admin.SetRetention(ctx, topicName, m.cfg.TopicRetentionTime, retentionSizeMB)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	partitionNum, err := m.admin.GetPartitionNum(ctx, topicName)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	log.Info("pulsar topic is ready", zap.String("topic", topicName),
+		zap.Int32("partitionNum", partitionNum))
+	m.partitions.Store(topicName, partitionNum)
+	return partitionNum, nil
 }
 
 // Close
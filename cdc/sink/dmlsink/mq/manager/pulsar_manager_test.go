@@ -15,8 +15,11 @@ package manager
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/pkg/config"
 	pulsarConfig "github.com/pingcap/tiflow/pkg/sink/pulsar"
@@ -33,7 +36,7 @@ func newPulsarConfig(t *testing.T) (*pulsarConfig.Config, *url.URL) {
 	replicaConfig := config.GetDefaultReplicaConfig()
 	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
 	require.NoError(t, err)
-	c, err := pulsarConfig.NewPulsarConfig(sinkURI)
+	c, err := pulsarConfig.NewPulsarConfig(sinkURI, replicaConfig.Sink.PulsarConfig)
 	require.NoError(t, err)
 	return c, sinkURI
 }
@@ -59,3 +62,45 @@ func TestGetPartitionNumMock(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int32(3), pn)
 }
+
+func TestPulsarTopicManagerAutoCreateTopic(t *testing.T) {
+	t.Parallel()
+
+	var created, retention int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/admin/v2/persistent/public/default/test/partitions":
+			created++
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v2/persistent/public/default/test/retention":
+			retention++
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v2/persistent/public/default/test/partitions":
+			_, _ = w.Write([]byte(`{"partitions":3}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg, _ := newPulsarConfig(t)
+	cfg.AdminURL = server.URL
+	cfg.AutoCreateTopic = true
+	cfg.TopicPartitionNum = 3
+	cfg.TopicRetentionTime = time.Hour
+	pm, err := NewPulsarTopicManager(cfg, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		pn, err := pm.GetPartitionNum(ctx, "persistent://public/default/test")
+		require.NoError(t, err)
+		require.Equal(t, int32(3), pn)
+	}
+	// the partition number is cached after the topic is created.
+	require.Equal(t, 1, created)
+	require.Equal(t, 1, retention)
+
+	_, err = pm.GetPartitionNum(ctx, "persistent://public/default/unknown")
+	require.Error(t, err)
+}
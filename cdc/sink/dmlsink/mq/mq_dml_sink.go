@@ -15,9 +15,12 @@ package mq
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
@@ -26,14 +29,21 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"go.uber.org/zap"
 )
 
 // Assert EventSink[E event.TableEvent] implementation
 var _ dmlsink.EventSink[*model.RowChangedEvent] = (*dmlSink)(nil)
 
+// Assert DispatchRulesUpdater implementation
+var _ dmlsink.DispatchRulesUpdater = (*dmlSink)(nil)
+
 // dmlSink is the mq sink.
 // It will send the events to the MQ system.
 type dmlSink struct {
@@ -41,6 +51,39 @@ type dmlSink struct {
 	id model.ChangeFeedID
 	// protocol indicates the protocol used by this sink.
 	protocol config.Protocol
+	// transactional indicates the producer is transactional, a commit marker
+	// is sent after the row events of each WriteEvents call, so every resolved
+	// batch of a table is committed as a whole.
+	transactional bool
+	// txnMu makes sure the row events of a WriteEvents call are consecutive
+	// if the producer is transactional.
+	txnMu sync.Mutex
+	// bootstrapper builds the bootstrap messages of the tables, it's nil if
+	// the bootstrap messages are disabled.
+	bootstrapper *bootstrapper
+	// columnSelector drops the unselected columns before the rows are routed
+	// and encoded, it's nil if there is no column selector configured.
+	columnSelector *filter.ColumnSelector
+	// deleteAsTombstone indicates the delete events are encoded as the
+	// tombstones, the update events changing the handle keys are split, so the
+	// tombstones of the old keys are sent.
+	deleteAsTombstone bool
+
+	// reshardMu blocks WriteEvents while the partition expansion is applied.
+	reshardMu sync.RWMutex
+	// partitions are the partition numbers used to dispatch the events of
+	// the topics. They're only changed when the expansion is applied, even if
+	// the topic manager has refreshed the larger ones.
+	partitions struct {
+		sync.RWMutex
+		nums map[string]int32
+	}
+	// partitionExpanded indicates the partitions of any used topic are
+	// expanded, and the expansion is applied by the next WriteEvents.
+	partitionExpanded atomic.Bool
+	// inflight is the number of the events which are dispatched but not
+	// acknowledged yet.
+	inflight atomic.Int64
 
 	alive struct {
 		sync.RWMutex
@@ -73,26 +116,45 @@ func newDMLSink(
 	eventRouter *dispatcher.EventRouter,
 	encoderGroup codec.EncoderGroup,
 	protocol config.Protocol,
+	transactional bool,
 	claimCheck *ClaimCheck,
 	claimCheckEncoder codec.ClaimCheckLocationEncoder,
+	deadLetterQueue *DeadLetterQueue,
+	headers *common.HeadersBuilder,
+	tableMetrics *config.TableMetricsConfig,
+	bootstrap *config.BootstrapConfig,
+	columnSelector *filter.ColumnSelector,
+	deleteAsTombstone bool,
 	errCh chan error,
 ) *dmlSink {
 	ctx, cancel := context.WithCancel(ctx)
-	statistics := metrics.NewStatistics(ctx, changefeedID, sink.RowSink)
-	worker := newWorker(changefeedID, protocol,
-		producer, encoderGroup, claimCheck, claimCheckEncoder, statistics)
+	statistics := metrics.NewStatistics(ctx, changefeedID, sink.RowSink).
+		WithTableMetrics(tableMetrics)
+	worker := newWorker(changefeedID, protocol, producer, encoderGroup,
+		claimCheck, claimCheckEncoder, deadLetterQueue, headers, statistics)
 
 	s := &dmlSink{
-		id:          changefeedID,
-		protocol:    protocol,
-		adminClient: adminClient,
-		ctx:         ctx,
-		cancel:      cancel,
-		dead:        make(chan struct{}),
+		id:                changefeedID,
+		protocol:          protocol,
+		transactional:     transactional,
+		bootstrapper:      newBootstrapper(protocol, bootstrap),
+		columnSelector:    columnSelector,
+		deleteAsTombstone: deleteAsTombstone,
+		adminClient:       adminClient,
+		ctx:               ctx,
+		cancel:            cancel,
+		dead:              make(chan struct{}),
 	}
 	s.alive.eventRouter = eventRouter
 	s.alive.topicManager = topicManager
 	s.alive.worker = worker
+	s.partitions.nums = make(map[string]int32)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runPartitionExpansionCheck(ctx)
+	}()
 
 	// Spawn a goroutine to send messages by the worker.
 	s.wg.Add(1)
@@ -120,12 +182,28 @@ func newDMLSink(
 // WriteEvents writes events to the sink.
 // This is an asynchronously and thread-safe method.
 func (s *dmlSink) WriteEvents(rows ...*dmlsink.RowChangeCallbackableEvent) error {
+	// Each call writes the events of a resolved batch, so the expansion is
+	// applied between the batches.
+	if s.partitionExpanded.Load() {
+		if err := s.applyPartitionExpansion(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	s.reshardMu.RLock()
+	defer s.reshardMu.RUnlock()
+
 	s.alive.RLock()
 	defer s.alive.RUnlock()
 	if s.alive.isDead {
 		return errors.Trace(errors.New("dead dmlSink"))
 	}
 
+	if s.transactional {
+		s.txnMu.Lock()
+		defer s.txnMu.Unlock()
+	}
+
+	sent := 0
 	for _, row := range rows {
 		if row.GetTableSinkState() != state.TableSinkSinking {
 			// The table where the event comes from is in stopping, so it's safe
@@ -133,21 +211,166 @@ func (s *dmlSink) WriteEvents(rows ...*dmlsink.RowChangeCallbackableEvent) error
 			row.Callback()
 			continue
 		}
-		topic := s.alive.eventRouter.GetTopicForRowChange(row.Event)
-		partitionNum, err := s.alive.topicManager.GetPartitionNum(s.ctx, topic)
-		if err != nil {
+		if s.columnSelector != nil {
+			selected, err := s.columnSelector.SelectRowChangedEvent(row.Event)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// The event may be shared, so it's replaced instead of modified.
+			row = &dmlsink.RowChangeCallbackableEvent{
+				Event:     selected,
+				Callback:  row.Callback,
+				SinkState: row.SinkState,
+			}
+		}
+		events := []*dmlsink.RowChangeCallbackableEvent{row}
+		if s.deleteAsTombstone {
+			events = splitHandleKeyUpdate(row)
+		}
+		for _, event := range events {
+			if err := s.writeEvent(event); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		sent++
+	}
+
+	if s.transactional && sent > 0 {
+		s.alive.worker.msgChan.In() <- mqEvent{commit: true}
+	}
+	return nil
+}
+
+// writeEvent routes the row event and sends it to the worker.
+func (s *dmlSink) writeEvent(row *dmlsink.RowChangeCallbackableEvent) error {
+	topic := s.alive.eventRouter.GetTopicForRowChange(row.Event)
+	partitionNum, err := s.getPartitionNum(topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if s.bootstrapper.shouldSend(row.Event) {
+		if err := s.sendBootstrap(topic, partitionNum, row.Event); err != nil {
 			return errors.Trace(err)
 		}
-		partition := s.alive.eventRouter.GetPartitionForRowChange(row.Event, partitionNum)
-		// This never be blocked because this is an unbounded channel.
+	}
+	partition := s.alive.eventRouter.GetPartitionForRowChange(row.Event, partitionNum)
+	messageKey, err := s.alive.eventRouter.GetKeyForRowChange(row.Event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// This never be blocked because this is an unbounded channel.
+	s.alive.worker.msgChan.In() <- mqEvent{
+		key: TopicPartitionKey{
+			Topic: topic, Partition: partition,
+		},
+		rowEvent: &dmlsink.RowChangeCallbackableEvent{
+			Event:     row.Event,
+			Callback:  s.trackInflight(row.Callback),
+			SinkState: row.SinkState,
+		},
+		messageKey: messageKey,
+	}
+	return nil
+}
+
+// splitHandleKeyUpdate splits the update event changing the handle key into
+// the delete event of the old key and the insert event of the new key, they
+// may be sent to different partitions, so the callback of the update event is
+// called after both of them are sent. The other events are returned as they
+// are.
+func splitHandleKeyUpdate(
+	row *dmlsink.RowChangeCallbackableEvent,
+) []*dmlsink.RowChangeCallbackableEvent {
+	e := row.Event
+	if !e.IsHandleKeyUpdated() {
+		return []*dmlsink.RowChangeCallbackableEvent{row}
+	}
+	// The event may be shared, so it's copied instead of modified.
+	deleteEvent, insertEvent := *e, *e
+	deleteEvent.Columns = nil
+	insertEvent.PreColumns = nil
+	var pending atomic.Int32
+	pending.Store(2)
+	callback := func() {
+		if pending.Add(-1) == 0 {
+			row.Callback()
+		}
+	}
+	return []*dmlsink.RowChangeCallbackableEvent{
+		{Event: &deleteEvent, Callback: callback, SinkState: row.SinkState},
+		{Event: &insertEvent, Callback: callback, SinkState: row.SinkState},
+	}
+}
+
+// sendBootstrap sends the bootstrap message of the table of the row to all
+// the partitions of the topic, before the row is sent.
+func (s *dmlSink) sendBootstrap(
+	topic string, partitionNum int32, row *model.RowChangedEvent,
+) error {
+	for partition := int32(0); partition < partitionNum; partition++ {
+		message, err := s.bootstrapper.encode(row)
+		if err != nil {
+			return err
+		}
 		s.alive.worker.msgChan.In() <- mqEvent{
 			key: TopicPartitionKey{
 				Topic: topic, Partition: partition,
 			},
-			rowEvent: row,
+			bootstrap: message,
 		}
 	}
+	return nil
+}
+
+// PreFlight fetches the brokers of the cluster and the metadata of the default
+// topic, so the unreachable brokers, the failed authentication and the missing
+// topic permissions are reported before the changefeed is created.
+func (s *dmlSink) PreFlight(ctx context.Context) error {
+	brokers, err := s.adminClient.GetAllBrokers(ctx)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkPreFlightFailed, err,
+			"failed to fetch the brokers of the kafka cluster, please check the "+
+				"addresses in the sink uri and the authentication of the kafka sink")
+	}
+	if len(brokers) == 0 {
+		return cerror.ErrSinkPreFlightFailed.GenWithStackByArgs(
+			"no broker is found in the kafka cluster, please check the addresses in the sink uri")
+	}
 
+	s.alive.RLock()
+	topic := s.alive.eventRouter.GetDefaultTopic()
+	s.alive.RUnlock()
+	hint := fmt.Sprintf("failed to fetch the metadata of topic %s, please check whether "+
+		"the topic exists and the kafka user is authorized to describe and write it", topic)
+	topics, err := s.adminClient.GetTopicsMeta(ctx, []string{topic}, false)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkPreFlightFailed, err, hint)
+	}
+	if _, ok := topics[topic]; !ok {
+		return cerror.ErrSinkPreFlightFailed.GenWithStackByArgs(hint)
+	}
+	return nil
+}
+
+// UpdateDispatchRules rebuilds the event router by the dispatch rules in the
+// config. The events written before are sent as they're routed, and the new
+// topics are created when the events are routed to them.
+func (s *dmlSink) UpdateDispatchRules(cfg *config.ReplicaConfig) error {
+	s.alive.RLock()
+	defaultTopic := s.alive.eventRouter.GetDefaultTopic()
+	s.alive.RUnlock()
+	eventRouter, err := dispatcher.NewEventRouter(cfg, defaultTopic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	s.alive.Lock()
+	s.alive.eventRouter = eventRouter
+	s.alive.Unlock()
+	log.Info("MQ sink has updated the dispatch rules",
+		zap.String("namespace", s.id.Namespace),
+		zap.String("changefeed", s.id.ID),
+		zap.Any("dispatchRules", cfg.Sink.DispatchRules))
 	return nil
 }
 
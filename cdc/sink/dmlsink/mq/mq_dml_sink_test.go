@@ -15,17 +15,23 @@ package mq
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"testing"
 	"time"
 
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -106,3 +112,459 @@ func TestWriteEvents(t *testing.T) {
 	require.Len(t, errCh, 0)
 	require.Len(t, s.alive.worker.producer.(*dmlproducer.MockDMLProducer).GetAllEvents(), 3000)
 }
+
+func TestUpdateDispatchRules(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		replicaConfig, errCh, kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+	producer := s.alive.worker.producer.(*dmlproducer.MockDMLProducer)
+	topicEvents := func(topic string) int {
+		count := 0
+		for partition := int32(0); partition < kafka.DefaultMockPartitionNum; partition++ {
+			count += len(producer.GetEvents(topic, partition))
+		}
+		return count
+	}
+
+	tableStatus := state.TableSinkSinking
+	newEvent := func() *dmlsink.RowChangeCallbackableEvent {
+		return &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+				Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		}
+	}
+	require.NoError(t, s.WriteEvents(newEvent()))
+	require.Eventually(t, func() bool {
+		return topicEvents(kafka.DefaultMockTopicName) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The events are routed by the new rules after they're updated.
+	newConfig := config.GetDefaultReplicaConfig()
+	newConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"a.*"}, TopicRule: "{schema}_{table}"},
+	}
+	require.NoError(t, s.UpdateDispatchRules(newConfig))
+	require.NoError(t, s.WriteEvents(newEvent()))
+	require.Eventually(t, func() bool {
+		return topicEvents("a_b") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, topicEvents(kafka.DefaultMockTopicName))
+
+	// The rules are kept if the new ones are invalid.
+	newConfig.Sink.DispatchRules[0].Matcher = []string{"[\\"}
+	require.Error(t, s.UpdateDispatchRules(newConfig))
+	require.Equal(t, "a_b", s.alive.eventRouter.GetTopicForRowChange(newEvent().Event))
+	require.Len(t, errCh, 0)
+}
+
+func TestWriteEventsWithColumnSelector(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	replicaConfig.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"a.b"}, Columns: []string{"col1"}},
+		{Matcher: []string{"a.c"}, Columns: []string{"col2"}},
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		replicaConfig, errCh, kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+
+	tableStatus := state.TableSinkSinking
+	newEvent := func(table string) *dmlsink.RowChangeCallbackableEvent {
+		return &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: table},
+				Columns: []*model.Column{
+					{
+						Name: "col1", Type: mysql.TypeLong, Value: int64(1),
+						Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+					},
+					{Name: "col2", Type: mysql.TypeVarchar, Value: "aa"},
+				},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		}
+	}
+	event := newEvent("b")
+	require.NoError(t, s.WriteEvents(event))
+	producer := s.alive.worker.producer.(*dmlproducer.MockDMLProducer)
+	require.Eventually(t, func() bool {
+		return len(producer.GetAllEvents()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	message := producer.GetAllEvents()[0]
+	require.Contains(t, string(message.Value), "col1")
+	require.NotContains(t, string(message.Value), "col2")
+	// the written event is not modified.
+	require.Len(t, event.Event.Columns, 2)
+	require.NotNil(t, event.Event.Columns[1])
+
+	// the handle key column of the table can't be dropped.
+	require.ErrorContains(t, s.WriteEvents(newEvent("c")), "handle key column col1")
+	require.Len(t, errCh, 0)
+}
+
+func TestWriteEventsWithKeyTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=16" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"a.b"}, PartitionRule: "table", KeyTemplate: "{schema}.{table}:{col1}"},
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		replicaConfig, errCh, kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+
+	tableStatus := state.TableSinkSinking
+	newEvent := func(table string, id int64) *dmlsink.RowChangeCallbackableEvent {
+		return &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: table},
+				Columns: []*model.Column{
+					{
+						Name: "col1", Type: mysql.TypeLong, Value: id,
+						Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+					},
+				},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		}
+	}
+	require.NoError(t, s.WriteEvents(
+		newEvent("b", 1), newEvent("b", 1), newEvent("b", 2), newEvent("c", 3)))
+	producer := s.alive.worker.producer.(*dmlproducer.MockDMLProducer)
+	rows := make(map[string]int)
+	require.Eventually(t, func() bool {
+		rows = make(map[string]int)
+		total := 0
+		for _, message := range producer.GetAllEvents() {
+			rows[string(message.Key)] += message.GetRowsCount()
+			total += message.GetRowsCount()
+		}
+		return total == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	// the rows with different keys are never batched into one message.
+	require.Equal(t, 2, rows["a.b:1"])
+	require.Equal(t, 1, rows["a.b:2"])
+	require.Len(t, rows, 3)
+	require.Len(t, errCh, 0)
+}
+
+func TestSplitHandleKeyUpdate(t *testing.T) {
+	t.Parallel()
+
+	tableStatus := state.TableSinkSinking
+	callbacks := 0
+	newEvent := func(oldID, newID int64) *dmlsink.RowChangeCallbackableEvent {
+		return &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				Table: &model.TableName{Schema: "a", Table: "b"},
+				PreColumns: []*model.Column{
+					{Name: "id", Value: oldID, Flag: model.HandleKeyFlag},
+					{Name: "name", Value: "a"},
+				},
+				Columns: []*model.Column{
+					{Name: "id", Value: newID, Flag: model.HandleKeyFlag},
+					{Name: "name", Value: "b"},
+				},
+			},
+			Callback:  func() { callbacks++ },
+			SinkState: &tableStatus,
+		}
+	}
+
+	// the update event which doesn't change the handle key is not split.
+	row := newEvent(1, 1)
+	events := splitHandleKeyUpdate(row)
+	require.Equal(t, []*dmlsink.RowChangeCallbackableEvent{row}, events)
+
+	row = newEvent(1, 2)
+	events = splitHandleKeyUpdate(row)
+	require.Len(t, events, 2)
+	require.True(t, events[0].Event.IsDelete())
+	require.Equal(t, int64(1), events[0].Event.PreColumns[0].Value)
+	require.True(t, events[1].Event.IsInsert())
+	require.Equal(t, int64(2), events[1].Event.Columns[0].Value)
+	// the written event is not modified.
+	require.True(t, row.Event.IsUpdate())
+
+	// the callback is called after both events are sent.
+	events[1].Callback()
+	require.Equal(t, 0, callbacks)
+	events[0].Callback()
+	require.Equal(t, 1, callbacks)
+}
+
+func TestWriteEventsWithBootstrap(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	replicaConfig.Sink.Bootstrap = &config.BootstrapConfig{
+		Enable:         util.AddressOf(true),
+		SnapshotMarker: util.AddressOf(true),
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	changefeedID := model.DefaultChangeFeedID("test")
+	s, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	defer s.Close()
+
+	tableInfo := model.WrapTableInfo(1, "a", 100, &timodel.TableInfo{
+		ID:   10,
+		Name: timodel.NewCIStr("b"),
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("col1"),
+			FieldType: *types.NewFieldType(mysql.TypeVarchar),
+		}},
+	})
+	tableStatus := state.TableSinkSinking
+	events := make([]*dmlsink.RowChangeCallbackableEvent, 0, 10)
+	for i := 0; i < 10; i++ {
+		events = append(events, &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs:      uint64(200 + i),
+				ReplicatingTs: 150,
+				Table:         &model.TableName{Schema: "a", Table: "b", TableID: 10},
+				TableInfo:     tableInfo,
+				Columns:       []*model.Column{{Name: "col1", Type: mysql.TypeVarchar, Value: "aa"}},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		})
+	}
+	require.NoError(t, s.WriteEvents(events[:5]...))
+	require.NoError(t, s.WriteEvents(events[5:]...))
+	producer := s.alive.worker.producer.(*dmlproducer.MockDMLProducer)
+	require.Eventually(t, func() bool {
+		return len(producer.GetAllEvents()) == 13
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, errCh, 0)
+
+	// the bootstrap message is sent to each partition once, before the rows.
+	for partition := int32(0); partition < 3; partition++ {
+		messages := producer.GetEvents(kafka.DefaultMockTopicName, partition)
+		require.NotEmpty(t, messages)
+		require.Equal(t, model.MessageTypeBootstrap, messages[0].Type)
+		for _, message := range messages[1:] {
+			require.NotEqual(t, model.MessageTypeBootstrap, message.Type)
+		}
+
+		m := &bootstrapMessage{}
+		require.NoError(t, json.Unmarshal(messages[0].Value, m))
+		require.Equal(t, bootstrapMessageType, m.Type)
+		require.Equal(t, "a", m.Schema)
+		require.Equal(t, "b", m.Table)
+		require.Equal(t, uint64(150), m.SnapshotTs)
+		require.Equal(t, uint64(100), m.TableDefinition.TableVersion)
+		require.Len(t, m.TableDefinition.Columns, 1)
+		require.Equal(t, "col1", m.TableDefinition.Columns[0].Name)
+	}
+}
+
+func TestPreFlight(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	changefeedID := model.DefaultChangeFeedID("test")
+	s, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.PreFlight(ctx))
+
+	s.adminClient.(*kafka.ClusterAdminClientMockImpl).DeleteTopic(kafka.DefaultMockTopicName)
+	err = s.PreFlight(ctx)
+	require.Regexp(t, "ErrSinkPreFlightFailed", err)
+	require.ErrorContains(t, err, "failed to fetch the metadata of topic "+kafka.DefaultMockTopicName)
+}
+
+// expandedTopicManager returns the expanded partition number of the topic.
+type expandedTopicManager struct {
+	manager.TopicManager
+	partitionNum int32
+}
+
+func (m *expandedTopicManager) GetPartitionNum(
+	ctx context.Context, topic string,
+) (int32, error) {
+	if m.partitionNum > 0 {
+		return m.partitionNum, nil
+	}
+	return m.TopicManager.GetPartitionNum(ctx, topic)
+}
+
+func TestApplyPartitionExpansion(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{}
+	replicaConfig.Sink.Bootstrap = &config.BootstrapConfig{Enable: util.AddressOf(true)}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	changefeedID := model.DefaultChangeFeedID("test")
+	s, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	defer s.Close()
+	topicManager := &expandedTopicManager{TopicManager: s.alive.topicManager}
+	s.alive.topicManager = topicManager
+
+	tableInfo := model.WrapTableInfo(1, "a", 100, &timodel.TableInfo{
+		ID:   10,
+		Name: timodel.NewCIStr("b"),
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("col1"),
+			FieldType: *types.NewFieldType(mysql.TypeVarchar),
+		}},
+	})
+	tableStatus := state.TableSinkSinking
+	events := make([]*dmlsink.RowChangeCallbackableEvent, 0, 10)
+	for i := 0; i < 10; i++ {
+		events = append(events, &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs:  uint64(200 + i),
+				Table:     &model.TableName{Schema: "a", Table: "b", TableID: 10},
+				TableInfo: tableInfo,
+				Columns:   []*model.Column{{Name: "col1", Type: mysql.TypeVarchar, Value: "aa"}},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		})
+	}
+	require.NoError(t, s.WriteEvents(events[:5]...))
+	producer := s.alive.worker.producer.(*dmlproducer.MockDMLProducer)
+	require.Eventually(t, func() bool {
+		return len(producer.GetAllEvents()) == 8
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, producer.GetEvents(kafka.DefaultMockTopicName, 3))
+
+	// the expansion is detected, but it's applied by the next WriteEvents.
+	topicManager.partitionNum = 4
+	require.NoError(t, s.checkPartitionExpansion(ctx))
+	require.True(t, s.partitionExpanded.Load())
+	partitionNum, err := s.getPartitionNum(kafka.DefaultMockTopicName)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), partitionNum)
+
+	require.NoError(t, s.WriteEvents(events[5:]...))
+	require.False(t, s.partitionExpanded.Load())
+	partitionNum, err = s.getPartitionNum(kafka.DefaultMockTopicName)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), partitionNum)
+	require.Eventually(t, func() bool {
+		return len(producer.GetAllEvents()) == 17
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, errCh, 0)
+
+	// the bootstrap messages are sent again, including the new partition.
+	messages := producer.GetEvents(kafka.DefaultMockTopicName, 3)
+	require.NotEmpty(t, messages)
+	require.Equal(t, model.MessageTypeBootstrap, messages[0].Type)
+	require.Zero(t, s.inflight.Load())
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// partitionExpansionCheckInterval is the interval of comparing the
+	// partition numbers used by the sink with the ones refreshed by the topic
+	// manager in the background.
+	partitionExpansionCheckInterval = time.Minute
+	// inflightCheckInterval is the interval of checking whether all the
+	// dispatched events are acknowledged before the expansion is applied.
+	inflightCheckInterval = 10 * time.Millisecond
+)
+
+// getPartitionNum returns the partition number used to dispatch the events of
+// the topic, it's fixed once the topic is used until the expansion of the
+// topic is applied.
+func (s *dmlSink) getPartitionNum(topic string) (int32, error) {
+	s.partitions.RLock()
+	partitionNum, ok := s.partitions.nums[topic]
+	s.partitions.RUnlock()
+	if ok {
+		return partitionNum, nil
+	}
+
+	partitionNum, err := s.alive.topicManager.GetPartitionNum(s.ctx, topic)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	s.partitions.Lock()
+	defer s.partitions.Unlock()
+	if old, ok := s.partitions.nums[topic]; ok {
+		return old, nil
+	}
+	s.partitions.nums[topic] = partitionNum
+	return partitionNum, nil
+}
+
+// runPartitionExpansionCheck checks whether the partitions of any used topic
+// are expanded periodically, the expansion is applied by the next WriteEvents.
+func (s *dmlSink) runPartitionExpansionCheck(ctx context.Context) {
+	ticker := time.NewTicker(partitionExpansionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.checkPartitionExpansion(ctx); err != nil {
+				log.Warn("MQ sink check the partition expansion failed",
+					zap.String("namespace", s.id.Namespace),
+					zap.String("changefeed", s.id.ID),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+// checkPartitionExpansion marks the expansion to be applied if the partition
+// number of any used topic refreshed by the topic manager is larger.
+func (s *dmlSink) checkPartitionExpansion(ctx context.Context) error {
+	expanded, err := s.expandedPartitionNums(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(expanded) > 0 {
+		s.partitionExpanded.Store(true)
+	}
+	return nil
+}
+
+// expandedPartitionNums returns the latest partition numbers of the expanded
+// topics.
+func (s *dmlSink) expandedPartitionNums(ctx context.Context) (map[string]int32, error) {
+	s.partitions.RLock()
+	used := make(map[string]int32, len(s.partitions.nums))
+	for topic, partitionNum := range s.partitions.nums {
+		used[topic] = partitionNum
+	}
+	s.partitions.RUnlock()
+
+	s.alive.RLock()
+	topicManager := s.alive.topicManager
+	s.alive.RUnlock()
+	expanded := make(map[string]int32)
+	for topic, partitionNum := range used {
+		latest, err := topicManager.GetPartitionNum(ctx, topic)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if latest > partitionNum {
+			expanded[topic] = latest
+		}
+	}
+	return expanded, nil
+}
+
+// applyPartitionExpansion re-shards the expanded topics at the boundary of the
+// resolved batches. It blocks the following WriteEvents and waits until all
+// the events dispatched by the old partition numbers are acknowledged, so the
+// events of a key are never sent to two partitions out of order. The bootstrap
+// messages are sent again, since the new partitions haven't received them.
+func (s *dmlSink) applyPartitionExpansion() error {
+	s.reshardMu.Lock()
+	defer s.reshardMu.Unlock()
+	// the expansion may be applied by another WriteEvents.
+	if !s.partitionExpanded.Load() {
+		return nil
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(inflightCheckInterval)
+	defer ticker.Stop()
+	for s.inflight.Load() > 0 {
+		select {
+		case <-s.ctx.Done():
+			return errors.Trace(s.ctx.Err())
+		case <-s.dead:
+			return errors.Trace(errors.New("dead dmlSink"))
+		case <-ticker.C:
+		}
+	}
+
+	expanded, err := s.expandedPartitionNums(s.ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.partitions.Lock()
+	for topic, partitionNum := range expanded {
+		log.Info("MQ sink applies the partition expansion of the topic",
+			zap.String("namespace", s.id.Namespace),
+			zap.String("changefeed", s.id.ID),
+			zap.String("topic", topic),
+			zap.Int32("oldPartitionNumber", s.partitions.nums[topic]),
+			zap.Int32("newPartitionNumber", partitionNum),
+			zap.Duration("duration", time.Since(start)))
+		s.partitions.nums[topic] = partitionNum
+	}
+	s.partitions.Unlock()
+	if len(expanded) > 0 {
+		s.bootstrapper.reset()
+	}
+	s.partitionExpanded.Store(false)
+	return nil
+}
+
+// trackInflight returns the callback which is called when the event is
+// acknowledged, and counts the event as inflight until then.
+func (s *dmlSink) trackInflight(callback func()) func() {
+	s.inflight.Add(1)
+	return func() {
+		s.inflight.Add(-1)
+		callback()
+	}
+}
@@ -14,10 +14,12 @@
 package mq
 
 import (
+	"bytes"
 	"context"
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
@@ -28,15 +30,19 @@ import (
 	"github.com/pingcap/tiflow/pkg/chann"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/tracing"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
-	// flushBatchSize is the batch size of the flush worker.
+	// flushBatchSize is the default batch size of the flush worker.
 	flushBatchSize = 2048
-	// flushInterval is the interval of the flush worker.
+	// flushInterval is the default interval of the flush worker.
 	// We should not set it too big, otherwise it will cause we wait too long to send the message.
 	flushInterval = 15 * time.Millisecond
 )
@@ -52,6 +58,15 @@ type TopicPartitionKey struct {
 type mqEvent struct {
 	key      TopicPartitionKey
 	rowEvent *dmlsink.RowChangeCallbackableEvent
+	// messageKey is the key of the message of the row event rendered by the
+	// key template, it's nil if the key is set by the protocol.
+	messageKey []byte
+	// commit indicates it's a commit marker instead of a row event, all the
+	// row events before it should be committed by the transactional producer.
+	commit bool
+	// bootstrap is the bootstrap message of a table instead of a row event,
+	// it's sent before the row events after it.
+	bootstrap *common.Message
 }
 
 // worker will send messages to the DML producer on a batch basis.
@@ -65,6 +80,9 @@ type worker struct {
 	msgChan *chann.DrainableChann[mqEvent]
 	// ticker used to force flush the messages when the interval is reached.
 	ticker *time.Ticker
+	// batchController adapts the batch size and the interval to the latency
+	// of sending messages.
+	batchController *batchController
 
 	// claimCheckEncoder is used to encode message which has claim-check location, send to kafka.
 	claimCheckEncoder codec.ClaimCheckLocationEncoder
@@ -74,6 +92,14 @@ type worker struct {
 	// producer is used to send the messages to the Kafka broker.
 	producer dmlproducer.DMLProducer
 
+	// addedFutures is the number of the futures added to the encoder group,
+	// it's only accessed by the encoding goroutine.
+	addedFutures uint64
+	// commitPoints receives the number of the added futures when a commit
+	// marker is met, the sending goroutine commits the transaction once all
+	// these futures are sent.
+	commitPoints chan uint64
+
 	// metricMQWorkerSendMessageDuration tracks the time duration cost on send messages.
 	metricMQWorkerSendMessageDuration prometheus.Observer
 	// metricMQWorkerBatchSize tracks each batch's size.
@@ -84,6 +110,12 @@ type worker struct {
 	statistics *metrics.Statistics
 
 	claimCheck *ClaimCheck
+	// deadLetterQueue receives the events which can not be encoded, it's nil
+	// if the dead-letter queue is disabled.
+	deadLetterQueue *DeadLetterQueue
+	// headers attaches the configured headers to the messages, it's nil if
+	// no header is configured.
+	headers *common.HeadersBuilder
 }
 
 // newWorker creates a new flush worker.
@@ -94,6 +126,8 @@ func newWorker(
 	encoderGroup codec.EncoderGroup,
 	claimCheck *ClaimCheck,
 	claimCheckEncoder codec.ClaimCheckLocationEncoder,
+	deadLetterQueue *DeadLetterQueue,
+	headers *common.HeadersBuilder,
 	statistics *metrics.Statistics,
 ) *worker {
 	w := &worker{
@@ -101,10 +135,14 @@ func newWorker(
 		protocol:                          protocol,
 		msgChan:                           chann.NewAutoDrainChann[mqEvent](),
 		ticker:                            time.NewTicker(flushInterval),
+		batchController:                   newBatchController(),
 		encoderGroup:                      encoderGroup,
 		producer:                          producer,
+		commitPoints:                      make(chan uint64, flushBatchSize),
 		claimCheck:                        claimCheck,
 		claimCheckEncoder:                 claimCheckEncoder,
+		deadLetterQueue:                   deadLetterQueue,
+		headers:                           headers,
 		metricMQWorkerSendMessageDuration: mq.WorkerSendMessageDuration.WithLabelValues(id.Namespace, id.ID),
 		metricMQWorkerBatchSize:           mq.WorkerBatchSize.WithLabelValues(id.Namespace, id.ID),
 		metricMQWorkerBatchDuration:       mq.WorkerBatchDuration.WithLabelValues(id.Namespace, id.ID),
@@ -160,6 +198,18 @@ func (w *worker) nonBatchEncodeRun(ctx context.Context) error {
 					zap.String("changefeed", w.changeFeedID.ID))
 				return nil
 			}
+			if event.commit {
+				if err := w.addCommitPoint(ctx); err != nil {
+					return errors.Trace(err)
+				}
+				continue
+			}
+			if event.bootstrap != nil {
+				if err := w.addBootstrap(ctx, event); err != nil {
+					return errors.Trace(err)
+				}
+				continue
+			}
 			if event.rowEvent.GetTableSinkState() != state.TableSinkSinking {
 				event.rowEvent.Callback()
 				log.Debug("Skip event of stopped table",
@@ -168,9 +218,15 @@ func (w *worker) nonBatchEncodeRun(ctx context.Context) error {
 					zap.Any("event", event))
 				continue
 			}
-			if err := w.encoderGroup.AddEvents(ctx, event.key.Topic, event.key.Partition, event.rowEvent); err != nil {
+			spanCtx, span := tracing.StartSpan(ctx, "mq.dispatch", w.changeFeedID,
+				attribute.Int("events", 1))
+			err := w.encoderGroup.AddEvents(spanCtx, event.key.Topic, event.key.Partition,
+				event.messageKey, event.rowEvent)
+			tracing.EndSpan(span, err)
+			if err != nil {
 				return errors.Trace(err)
 			}
+			w.addedFutures++
 		}
 	}
 }
@@ -182,11 +238,11 @@ func (w *worker) batchEncodeRun(ctx context.Context) (retErr error) {
 		zap.String("changefeed", w.changeFeedID.ID),
 		zap.String("protocol", w.protocol.String()),
 	)
-	// Fixed size of the batch.
-	eventsBuf := make([]mqEvent, flushBatchSize)
+	eventsBuf := make([]mqEvent, maxFlushBatchSize)
 	for {
 		start := time.Now()
-		endIndex, err := w.batch(ctx, eventsBuf, flushInterval)
+		batchSize, interval := w.batchController.next()
+		endIndex, err := w.batch(ctx, eventsBuf[:batchSize], interval)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -194,19 +250,90 @@ func (w *worker) batchEncodeRun(ctx context.Context) (retErr error) {
 			continue
 		}
 
-		w.metricMQWorkerBatchSize.Observe(float64(endIndex))
-		w.metricMQWorkerBatchDuration.Observe(time.Since(start).Seconds())
 		msgs := eventsBuf[:endIndex]
+		// The batch ends with the commit marker or the bootstrap message if
+		// there is one.
+		last := msgs[len(msgs)-1]
+		if last.commit || last.bootstrap != nil {
+			msgs = msgs[:len(msgs)-1]
+		}
+		w.metricMQWorkerBatchSize.Observe(float64(len(msgs)))
+		w.metricMQWorkerBatchDuration.Observe(time.Since(start).Seconds())
+		// the span covers the batching and the dispatching to the encoders,
+		// the encoding and sending of the batch are in the same trace.
+		spanCtx, span := tracing.StartSpan(ctx, "mq.batch", w.changeFeedID,
+			attribute.Int("events", len(msgs)))
 		partitionedRows := w.group(msgs)
 		for key, events := range partitionedRows {
-			if err := w.encoderGroup.AddEvents(ctx, key.Topic, key.Partition, events...); err != nil {
+			if err := w.addEvents(spanCtx, key, events); err != nil {
+				tracing.EndSpan(span, err)
 				return errors.Trace(err)
 			}
 		}
+		tracing.EndSpan(span, nil)
+		if last.bootstrap != nil {
+			if err := w.addBootstrap(ctx, last); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if last.commit {
+			if err := w.addCommitPoint(ctx); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// addEvents adds the events of a partition to the encoder group. The
+// consecutive events with the same message key are encoded together, so the
+// rows with different keys are never batched into one message.
+func (w *worker) addEvents(ctx context.Context, key TopicPartitionKey, events []mqEvent) error {
+	for len(events) > 0 {
+		n := 1
+		for n < len(events) && bytes.Equal(events[n].messageKey, events[0].messageKey) {
+			n++
+		}
+		rows := make([]*dmlsink.RowChangeCallbackableEvent, 0, n)
+		for _, event := range events[:n] {
+			rows = append(rows, event.rowEvent)
+		}
+		err := w.encoderGroup.AddEvents(ctx, key.Topic, key.Partition, events[0].messageKey, rows...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		w.addedFutures++
+		events = events[n:]
+	}
+	return nil
+}
+
+// addBootstrap adds the bootstrap message to the encoder group, it's sent in
+// order with the row events added before and after it.
+func (w *worker) addBootstrap(ctx context.Context, event mqEvent) error {
+	err := w.encoderGroup.AddMessages(ctx, event.key.Topic, event.key.Partition, event.bootstrap)
+	if err != nil {
+		return errors.Trace(err)
 	}
+	w.addedFutures++
+	return nil
+}
+
+// addCommitPoint notifies the sending goroutine to commit the transaction after
+// all the futures added so far are sent.
+func (w *worker) addCommitPoint(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case w.commitPoints <- w.addedFutures:
+	}
+	return nil
 }
 
 // batch collects a batch of messages to be sent to the DML producer.
+// The batch ends with the commit marker if there is one, so the row events
+// after it are not committed together with the ones before it. It also ends
+// with the bootstrap message if there is one, so the message is sent before
+// the row events after it.
 func (w *worker) batch(
 	ctx context.Context, events []mqEvent, flushInterval time.Duration,
 ) (int, error) {
@@ -227,6 +354,11 @@ func (w *worker) batch(
 			events[index] = msg
 			index++
 		}
+		if msg.commit || msg.bootstrap != nil {
+			events[index] = msg
+			index++
+			return index, nil
+		}
 	}
 
 	// Start a new tick to flush the batch.
@@ -246,6 +378,11 @@ func (w *worker) batch(
 				events[index] = msg
 				index++
 			}
+			if msg.commit || msg.bootstrap != nil {
+				events[index] = msg
+				index++
+				return index, nil
+			}
 
 			if index >= max {
 				return index, nil
@@ -259,8 +396,8 @@ func (w *worker) batch(
 // group is responsible for grouping messages by the partition.
 func (w *worker) group(
 	events []mqEvent,
-) map[TopicPartitionKey][]*dmlsink.RowChangeCallbackableEvent {
-	partitionedRows := make(map[TopicPartitionKey][]*dmlsink.RowChangeCallbackableEvent)
+) map[TopicPartitionKey][]mqEvent {
+	partitionedRows := make(map[TopicPartitionKey][]mqEvent)
 	for _, event := range events {
 		// Skip this event when the table is stopping.
 		if event.rowEvent.GetTableSinkState() != state.TableSinkSinking {
@@ -269,9 +406,9 @@ func (w *worker) group(
 			continue
 		}
 		if _, ok := partitionedRows[event.key]; !ok {
-			partitionedRows[event.key] = make([]*dmlsink.RowChangeCallbackableEvent, 0)
+			partitionedRows[event.key] = make([]mqEvent, 0)
 		}
-		partitionedRows[event.key] = append(partitionedRows[event.key], event.rowEvent)
+		partitionedRows[event.key] = append(partitionedRows[event.key], event)
 	}
 	return partitionedRows
 }
@@ -286,7 +423,13 @@ func (w *worker) sendMessages(ctx context.Context) error {
 			DeleteLabelValues(w.changeFeedID.Namespace, w.changeFeedID.ID)
 	}()
 
-	var err error
+	var (
+		err error
+		// sentFutures is the number of the futures whose messages are sent.
+		sentFutures uint64
+		// commitPoints are the commit points which are not reached yet.
+		commitPoints []uint64
+	)
 	inputCh := w.encoderGroup.Output()
 	for {
 		select {
@@ -294,6 +437,11 @@ func (w *worker) sendMessages(ctx context.Context) error {
 			return errors.Trace(ctx.Err())
 		case <-ticker.C:
 			metric.Set(float64(len(inputCh)))
+		case point := <-w.commitPoints:
+			commitPoints = append(commitPoints, point)
+			if commitPoints, err = w.tryCommit(sentFutures, commitPoints); err != nil {
+				return errors.Trace(err)
+			}
 		case future, ok := <-inputCh:
 			if !ok {
 				log.Warn("MQ sink encode output channel closed",
@@ -304,8 +452,41 @@ func (w *worker) sendMessages(ctx context.Context) error {
 			if err = future.Ready(ctx); err != nil {
 				return errors.Trace(err)
 			}
+			failpoint.Inject("MQSinkWorkerFlushError", func() {
+				// simulate the encoded messages are lost since the worker
+				// meets an error before sending them to the producer.
+				log.Info("MQSinkWorkerFlushError error injected",
+					zap.String("namespace", w.changeFeedID.Namespace),
+					zap.String("changefeed", w.changeFeedID.ID))
+				failpoint.Return(errors.New("mq sink worker injected error"))
+			})
+			for _, deadLetter := range future.DeadLetters {
+				if err = w.deadLetterQueue.Send(ctx, w.producer,
+					future.Topic, future.Partition, deadLetter); err != nil {
+					log.Error("send event to the dead-letter queue failed",
+						zap.String("namespace", w.changeFeedID.Namespace),
+						zap.String("changefeed", w.changeFeedID.ID),
+						zap.Error(err))
+					return errors.Trace(err)
+				}
+			}
 			for _, message := range future.Messages {
-				if message.ClaimCheckFileName != "" {
+				if message.ClaimCheckFileName != "" && w.claimCheck.BareMessage() {
+					// send the message to the external storage, only the location pointer
+					// message is sent to the kafka.
+					bareMessage, err := w.claimCheck.WriteBareMessage(ctx, message)
+					if err != nil {
+						log.Error("send message to the external claim check storage failed",
+							zap.String("namespace", w.changeFeedID.Namespace),
+							zap.String("changefeed", w.changeFeedID.ID),
+							zap.String("filename", message.ClaimCheckFileName),
+							zap.Error(err))
+						return errors.Trace(err)
+					}
+					// the origin message is written, so its buffers can be reused.
+					message.Release()
+					message = bareMessage
+				} else if message.ClaimCheckFileName != "" {
 					// send the message to the external storage.
 					if err = w.claimCheck.WriteMessage(ctx, message); err != nil {
 						log.Error("send message to the external claim check storage failed",
@@ -320,30 +501,89 @@ func (w *worker) sendMessages(ctx context.Context) error {
 					if err != nil {
 						return errors.Trace(err)
 					}
+					message.Release()
 					message = locationMessage
 				}
+				if future.Key != nil {
+					message.Key = future.Key
+				}
 				// normal message, just send it to the kafka.
+				w.headers.Attach(message)
+				if originID, ok := future.OriginID(); ok {
+					w.headers.AttachOrigin(message, originID)
+				}
+				w.traceProduce(future.WithSpanContext(ctx), future.Topic, future.Partition, message)
 				start := time.Now()
-				if err = w.statistics.RecordBatchExecution(func() (int, error) {
-					if err := w.producer.AsyncSendMessage(ctx, future.Topic, future.Partition, message); err != nil {
-						return 0, err
-					}
-					return message.GetRowsCount(), nil
-				}); err != nil {
+				if err = w.statistics.RecordTableExecution(
+					util.GetOrZero(message.Schema), util.GetOrZero(message.Table),
+					func() (int, error) {
+						if err := w.producer.AsyncSendMessage(ctx, future.Topic, future.Partition, message); err != nil {
+							return 0, err
+						}
+						return message.GetRowsCount(), nil
+					}); err != nil {
 					return err
 				}
-				w.metricMQWorkerSendMessageDuration.Observe(time.Since(start).Seconds())
+				duration := time.Since(start)
+				w.metricMQWorkerSendMessageDuration.Observe(duration.Seconds())
+				w.batchController.observe(duration)
+			}
+			sentFutures++
+			if commitPoints, err = w.tryCommit(sentFutures, commitPoints); err != nil {
+				return errors.Trace(err)
 			}
 		}
 	}
 }
 
+// traceProduce starts a span which ends when the message is acknowledged by
+// the broker, so the latency of the downstream can be told from the sending.
+func (w *worker) traceProduce(
+	ctx context.Context, topic string, partition int32, message *common.Message,
+) {
+	_, span := tracing.StartSpan(ctx, "mq.produce", w.changeFeedID,
+		attribute.String("topic", topic),
+		attribute.Int64("partition", int64(partition)),
+		attribute.Int("rows", message.GetRowsCount()),
+		attribute.Int("bytes", message.Length()))
+	if !span.IsRecording() {
+		return
+	}
+	callback := message.Callback
+	message.Callback = func() {
+		span.End()
+		if callback != nil {
+			callback()
+		}
+	}
+}
+
+// tryCommit commits the transaction if any commit point is reached, and returns
+// the commit points which are not reached yet. All the reached commit points
+// are committed at once.
+func (w *worker) tryCommit(sentFutures uint64, commitPoints []uint64) ([]uint64, error) {
+	reached := 0
+	for reached < len(commitPoints) && commitPoints[reached] <= sentFutures {
+		reached++
+	}
+	if reached == 0 {
+		return commitPoints, nil
+	}
+	if err := w.producer.Commit(); err != nil {
+		return nil, err
+	}
+	return commitPoints[reached:], nil
+}
+
 func (w *worker) close() {
 	w.msgChan.CloseAndDrain()
 	w.producer.Close()
 	if w.claimCheck != nil {
 		w.claimCheck.Close()
 	}
+	if w.deadLetterQueue != nil {
+		w.deadLetterQueue.Close()
+	}
 
 	mq.WorkerSendMessageDuration.DeleteLabelValues(w.changeFeedID.Namespace, w.changeFeedID.ID)
 	mq.WorkerBatchSize.DeleteLabelValues(w.changeFeedID.Namespace, w.changeFeedID.ID)
@@ -30,6 +30,9 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func newBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlproducer.DMLProducer) {
@@ -42,8 +45,8 @@ func newBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlproduc
 	require.NoError(t, err)
 	encoderConcurrency := 4
 	statistics := metrics.NewStatistics(ctx, id, sink.RowSink)
-	encoderGroup := codec.NewEncoderGroup(builder, encoderConcurrency, id)
-	return newWorker(id, config.ProtocolOpen, p, encoderGroup, nil, nil, statistics), p
+	encoderGroup := codec.NewEncoderGroup(builder, encoderConcurrency, id, false)
+	return newWorker(id, config.ProtocolOpen, p, encoderGroup, nil, nil, nil, nil, statistics), p
 }
 
 func newNonBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlproducer.DMLProducer) {
@@ -57,8 +60,8 @@ func newNonBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlpro
 	require.NoError(t, err)
 	encoderConcurrency := 4
 	statistics := metrics.NewStatistics(ctx, id, sink.RowSink)
-	encoderGroup := codec.NewEncoderGroup(builder, encoderConcurrency, id)
-	return newWorker(id, config.ProtocolOpen, p, encoderGroup, nil, nil, statistics), p
+	encoderGroup := codec.NewEncoderGroup(builder, encoderConcurrency, id, false)
+	return newWorker(id, config.ProtocolOpen, p, encoderGroup, nil, nil, nil, nil, statistics), p
 }
 
 func TestNonBatchEncode_SendMessages(t *testing.T) {
@@ -156,6 +159,110 @@ func TestBatchEncode_Batch(t *testing.T) {
 	require.Equal(t, 512, endIndex)
 }
 
+func TestBatchEncode_CommitMarker(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker, p := newBatchEncodeWorker(ctx, t)
+	defer worker.close()
+	key := TopicPartitionKey{
+		Topic:     "test",
+		Partition: 1,
+	}
+	tableStatus := state.TableSinkSinking
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+	}
+	sendRows := func(count int) {
+		for i := 0; i < count; i++ {
+			worker.msgChan.In() <- mqEvent{
+				key: key,
+				rowEvent: &dmlsink.RowChangeCallbackableEvent{
+					Event:     row,
+					Callback:  func() {},
+					SinkState: &tableStatus,
+				},
+			}
+		}
+		worker.msgChan.In() <- mqEvent{commit: true}
+	}
+	sendRows(3)
+	sendRows(2)
+
+	// The batch ends with the commit marker.
+	batch := make([]mqEvent, 512)
+	endIndex, err := worker.batch(ctx, batch, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 4, endIndex)
+	require.True(t, batch[3].commit)
+
+	sendRows(1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = worker.run(ctx)
+	}()
+
+	// The rows after the first commit marker are sent and committed.
+	mp := p.(*dmlproducer.MockDMLProducer)
+	require.Eventually(t, func() bool {
+		return len(mp.GetAllEvents()) == 3 && mp.GetCommitCount() >= 1
+	}, 3*time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+}
+
+func TestBatchEncode_Bootstrap(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker, _ := newBatchEncodeWorker(ctx, t)
+	defer worker.close()
+	key := TopicPartitionKey{
+		Topic:     "test",
+		Partition: 1,
+	}
+	tableStatus := state.TableSinkSinking
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+	}
+	for i := 0; i < 3; i++ {
+		worker.msgChan.In() <- mqEvent{
+			key: key,
+			rowEvent: &dmlsink.RowChangeCallbackableEvent{
+				Event:     row,
+				Callback:  func() {},
+				SinkState: &tableStatus,
+			},
+		}
+	}
+	bootstrap := &common.Message{Type: model.MessageTypeBootstrap}
+	worker.msgChan.In() <- mqEvent{key: key, bootstrap: bootstrap}
+	worker.msgChan.In() <- mqEvent{
+		key: key,
+		rowEvent: &dmlsink.RowChangeCallbackableEvent{
+			Event:     row,
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		},
+	}
+
+	// The batch ends with the bootstrap message, so it's sent before the
+	// rows after it.
+	batch := make([]mqEvent, 512)
+	endIndex, err := worker.batch(ctx, batch, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 4, endIndex)
+	require.Equal(t, bootstrap, batch[3].bootstrap)
+}
+
 func TestBatchEncode_Group(t *testing.T) {
 	t.Parallel()
 
@@ -247,8 +354,8 @@ func TestBatchEncode_Group(t *testing.T) {
 	// We must ensure that the sequence is not broken.
 	require.LessOrEqual(
 		t,
-		partitionedRows[key1][0].Event.GetCommitTs(), partitionedRows[key1][1].Event.GetCommitTs(),
-		partitionedRows[key1][2].Event.GetCommitTs(),
+		partitionedRows[key1][0].rowEvent.Event.GetCommitTs(), partitionedRows[key1][1].rowEvent.Event.GetCommitTs(),
+		partitionedRows[key1][2].rowEvent.Event.GetCommitTs(),
 	)
 	require.Len(t, partitionedRows[key2], 1)
 	require.Len(t, partitionedRows[key3], 1)
@@ -316,8 +423,8 @@ func TestBatchEncode_GroupWhenTableStopping(t *testing.T) {
 	// We must ensure that the sequence is not broken.
 	require.LessOrEqual(
 		t,
-		partitionedRows[key1][0].Event.GetCommitTs(),
-		partitionedRows[key1][1].Event.GetCommitTs(),
+		partitionedRows[key1][0].rowEvent.Event.GetCommitTs(),
+		partitionedRows[key1][1].rowEvent.Event.GetCommitTs(),
 	)
 }
 
@@ -554,3 +661,28 @@ func TestNonBatchEncodeWorker_Abort(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestTraceProduce(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	defer func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	}()
+
+	w := &worker{changeFeedID: model.DefaultChangeFeedID("test")}
+	acked := false
+	message := common.NewMsg(config.ProtocolOpen, []byte("key"), []byte("value"),
+		0, model.MessageTypeRow, nil, nil)
+	message.Callback = func() { acked = true }
+	w.traceProduce(context.Background(), "topic", 1, message)
+
+	// the span ends when the message is acknowledged.
+	require.Empty(t, exporter.GetSpans())
+	message.Callback()
+	require.True(t, acked)
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "mq.produce", spans[0].Name)
+}
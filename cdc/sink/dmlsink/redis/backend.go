@@ -0,0 +1,160 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+)
+
+// executor executes the commands in Redis.
+type executor interface {
+	Pipeline(ctx context.Context, cmds [][]string) error
+	Close() error
+}
+
+// Assert Backend implementation
+var _ batchsink.Backend = (*backend)(nil)
+
+// backend writes the batches into Redis, the commands of a batch are
+// pipelined. Each backend owns a client since the client isn't thread-safe.
+type backend struct {
+	config *predis.Config
+	client executor
+
+	cmds [][]string
+	rows int
+}
+
+func newBackend(config *predis.Config, client executor) *backend {
+	return &backend{
+		config: config,
+		client: client,
+	}
+}
+
+// Append implements batchsink.Backend.
+func (b *backend) Append(txn *model.SingleTableTxn) (int, error) {
+	key := b.config.MatchKey(txn.Table.Schema, txn.Table.Table)
+	for _, row := range txn.Rows {
+		cmds, err := b.encodeRow(key, row)
+		if err != nil {
+			return 0, err
+		}
+		b.cmds = append(b.cmds, cmds...)
+	}
+	b.rows += len(txn.Rows)
+	return len(txn.Rows), nil
+}
+
+// encodeRow encodes the row change into the commands. In the snapshot mode,
+// the key of the row is set to the new row or deleted; in the invalidate mode,
+// the key of the row is published to the channel. Both the old and new keys
+// are handled if the key of an updated row is changed.
+func (b *backend) encodeRow(
+	key predis.KeyTemplate, row *model.RowChangedEvent,
+) ([][]string, error) {
+	var oldKey, newKey string
+	var err error
+	if len(row.PreColumns) != 0 {
+		if oldKey, err = key.Substitute(row.Table, row.PreColumns); err != nil {
+			return nil, err
+		}
+	}
+	if len(row.Columns) != 0 {
+		if newKey, err = key.Substitute(row.Table, row.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	var cmds [][]string
+	if b.config.Mode == predis.ModeInvalidate {
+		if oldKey != "" {
+			cmds = append(cmds, []string{"PUBLISH", b.config.Channel, oldKey})
+		}
+		if newKey != "" && newKey != oldKey {
+			cmds = append(cmds, []string{"PUBLISH", b.config.Channel, newKey})
+		}
+		return cmds, nil
+	}
+
+	if oldKey != "" && oldKey != newKey {
+		cmds = append(cmds, []string{"DEL", oldKey})
+	}
+	if newKey == "" {
+		return cmds, nil
+	}
+	if b.config.ValueFormat == predis.ValueFormatJSON {
+		values := make(map[string]interface{}, len(row.Columns))
+		for _, col := range row.Columns {
+			if col == nil {
+				continue
+			}
+			if v, ok := col.Value.([]byte); ok {
+				values[col.Name] = string(v)
+			} else {
+				values[col.Name] = col.Value
+			}
+		}
+		data, err := json.Marshal(values)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(cmds, []string{"SET", newKey, string(data)}), nil
+	}
+
+	// the hash is replaced as a whole in a transaction, so the readers never
+	// see the missing key, and the fields of the null columns are absent.
+	hset := []string{"HSET", newKey}
+	for _, col := range row.Columns {
+		if col == nil || col.Value == nil {
+			continue
+		}
+		hset = append(hset, col.Name, model.ColumnValueString(col.Value))
+	}
+	if len(hset) == 2 {
+		return append(cmds, []string{"DEL", newKey}), nil
+	}
+	return append(cmds, []string{"MULTI"}, []string{"DEL", newKey}, hset, []string{"EXEC"}), nil
+}
+
+// Flush implements batchsink.Backend.
+func (b *backend) Flush(ctx context.Context) (int, error) {
+	defer b.reset()
+	if len(b.cmds) == 0 {
+		return b.rows, nil
+	}
+	if err := b.client.Pipeline(ctx, b.cmds); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return b.rows, nil
+}
+
+func (b *backend) reset() {
+	b.cmds = nil
+	b.rows = 0
+}
+
+// Close implements batchsink.Backend.
+func (b *backend) Close() error {
+	if b.client == nil {
+		return nil
+	}
+	return b.client.Close()
+}
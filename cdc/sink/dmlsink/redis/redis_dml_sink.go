@@ -0,0 +1,51 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+)
+
+// NewDMLSink creates a Redis sink.
+// It writes the row snapshots into Redis, or publishes the keys of the changed
+// rows for the cache invalidation, by the pipelined commands.
+func NewDMLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*batchsink.DMLSink, error) {
+	cfg := predis.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+	backends := make([]batchsink.Backend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		client, err := predis.NewClient(ctx, cfg)
+		if err != nil {
+			for _, b := range backends {
+				_ = b.Close()
+			}
+			return nil, err
+		}
+		backends = append(backends, newBackend(cfg, client))
+	}
+	return batchsink.New(ctx, changefeedID, "redis", backends, cfg.MaxBatchRows, errCh), nil
+}
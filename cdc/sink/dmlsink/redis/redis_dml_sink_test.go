@@ -0,0 +1,166 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	predis "github.com/pingcap/tiflow/pkg/sink/redis"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	sync.Mutex
+	cmds   []string
+	err    error
+	closed bool
+}
+
+func (m *mockExecutor) Pipeline(_ context.Context, cmds [][]string) error {
+	m.Lock()
+	defer m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	for _, cmd := range cmds {
+		m.cmds = append(m.cmds, strings.Join(cmd, " "))
+	}
+	return nil
+}
+
+func (m *mockExecutor) Close() error {
+	m.Lock()
+	defer m.Unlock()
+	m.closed = true
+	return nil
+}
+
+func newTestRows(commitTs uint64) []*model.RowChangedEvent {
+	table := &model.TableName{Schema: "test", Table: "t"}
+	pkFlag := model.HandleKeyFlag | model.PrimaryKeyFlag
+	return []*model.RowChangedEvent{
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			Columns: []*model.Column{
+				{Name: "id", Value: 1, Flag: pkFlag},
+				{Name: "name", Value: []byte("a")},
+				{Name: "age", Value: nil},
+			},
+		},
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 2, Flag: pkFlag}, {Name: "name", Value: "b"},
+			},
+			Columns: []*model.Column{
+				{Name: "id", Value: 3, Flag: pkFlag}, {Name: "name", Value: "c"},
+			},
+		},
+		{
+			CommitTs: commitTs,
+			Table:    table,
+			PreColumns: []*model.Column{
+				{Name: "id", Value: 4, Flag: pkFlag}, {Name: "name", Value: "d"},
+			},
+		},
+	}
+}
+
+func encodeTestRows(t *testing.T, cfg *predis.Config) []string {
+	b := newBackend(cfg, nil)
+	var cmds []string
+	for _, row := range newTestRows(100) {
+		rowCmds, err := b.encodeRow(cfg.MatchKey(row.Table.Schema, row.Table.Table), row)
+		require.NoError(t, err)
+		for _, cmd := range rowCmds {
+			cmds = append(cmds, strings.Join(cmd, " "))
+		}
+	}
+	return cmds
+}
+
+func TestEncodeRows(t *testing.T) {
+	t.Parallel()
+
+	cfg := predis.NewConfig()
+	require.Equal(t, []string{
+		"MULTI", "DEL test:t:1", "HSET test:t:1 id 1 name a", "EXEC",
+		"DEL test:t:2",
+		"MULTI", "DEL test:t:3", "HSET test:t:3 id 3 name c", "EXEC",
+		"DEL test:t:4",
+	}, encodeTestRows(t, cfg))
+
+	cfg = predis.NewConfig()
+	cfg.ValueFormat = predis.ValueFormatJSON
+	cfg.Key = "t:{id}"
+	require.Equal(t, []string{
+		`SET t:1 {"age":null,"id":1,"name":"a"}`,
+		"DEL t:2",
+		`SET t:3 {"id":3,"name":"c"}`,
+		"DEL t:4",
+	}, encodeTestRows(t, cfg))
+
+	cfg = predis.NewConfig()
+	cfg.Mode = predis.ModeInvalidate
+	cfg.Channel = "cache"
+	require.Equal(t, []string{
+		"PUBLISH cache test:t:1",
+		"PUBLISH cache test:t:2",
+		"PUBLISH cache test:t:3",
+		"PUBLISH cache test:t:4",
+	}, encodeTestRows(t, cfg))
+}
+
+func TestFlush(t *testing.T) {
+	t.Parallel()
+
+	client := &mockExecutor{}
+	b := newBackend(predis.NewConfig(), client)
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	for _, txn := range batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows) {
+		n, err := b.Append(txn.Event)
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+	}
+	rows, err := b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 30, rows)
+	require.Len(t, client.cmds, 100)
+
+	// the batch is reset after it's flushed.
+	rows, err = b.Flush(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, rows)
+	require.Len(t, client.cmds, 100)
+
+	client.err = errors.New("NOAUTH Authentication required")
+	_, err = b.Append(batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows)[0].Event)
+	require.NoError(t, err)
+	_, err = b.Flush(context.Background())
+	require.ErrorContains(t, err, "NOAUTH")
+
+	// the client is closed with the backend.
+	require.NoError(t, b.Close())
+	require.True(t, client.closed)
+}
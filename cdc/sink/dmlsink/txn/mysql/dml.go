@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 )
 
 // prepareUpdate builds a parametrics UPDATE statement as following
@@ -71,11 +72,14 @@ func prepareUpdate(quoteTable string, preCols, cols []*model.Column, forceReplic
 
 // prepareReplace builds a parametrics REPLACE statement as following
 // sql: `REPLACE INTO `test`.`t` VALUES (?,?,?)`
+// The statement is INSERT, INSERT IGNORE or INSERT ... ON DUPLICATE KEY UPDATE
+// according to the tp, the timestampColumn is only used by the latter one.
 func prepareReplace(
 	quoteTable string,
 	cols []*model.Column,
 	appendPlaceHolder bool,
-	translateToInsert bool,
+	tp sqlmodel.DMLType,
+	timestampColumn string,
 ) (string, []interface{}) {
 	var builder strings.Builder
 	columnNames := make([]string, 0, len(cols))
@@ -92,13 +96,19 @@ func prepareReplace(
 	}
 
 	colList := "(" + buildColumnList(columnNames) + ")"
-	if translateToInsert {
+	switch tp {
+	case sqlmodel.DMLInsert, sqlmodel.DMLInsertOnDuplicateUpdate:
 		builder.WriteString("INSERT INTO " + quoteTable + " " + colList + " VALUES ")
-	} else {
+	case sqlmodel.DMLInsertIgnore:
+		builder.WriteString("INSERT IGNORE INTO " + quoteTable + " " + colList + " VALUES ")
+	default:
 		builder.WriteString("REPLACE INTO " + quoteTable + " " + colList + " VALUES ")
 	}
 	if appendPlaceHolder {
 		builder.WriteString("(" + placeHolder(len(columnNames)) + ")")
+		if tp == sqlmodel.DMLInsertOnDuplicateUpdate {
+			builder.WriteString(sqlmodel.GenOnDuplicateUpdateIfNewer(columnNames, timestampColumn))
+		}
 	}
 
 	return builder.String(), args
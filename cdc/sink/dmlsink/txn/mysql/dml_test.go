@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
 	"github.com/stretchr/testify/require"
 )
 
@@ -713,9 +714,39 @@ func TestMapReplace(t *testing.T) {
 	for _, tc := range testCases {
 		// multiple times to verify the stability of column sequence in query string
 		for i := 0; i < 10; i++ {
-			query, args := prepareReplace(tc.quoteTable, tc.cols, false, false)
+			query, args := prepareReplace(tc.quoteTable, tc.cols, false, sqlmodel.DMLReplace, "")
 			require.Equal(t, tc.expectedQuery, query)
 			require.Equal(t, tc.expectedArgs, args)
 		}
 	}
 }
+
+func TestMapReplaceWithConflictStrategy(t *testing.T) {
+	t.Parallel()
+
+	cols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: 1},
+		{Name: "ts", Type: mysql.TypeTimestamp, Value: "2023-01-01 00:00:00"},
+		{Name: "b", Type: mysql.TypeVarchar, Value: "b"},
+	}
+	testCases := []struct {
+		tp            sqlmodel.DMLType
+		expectedQuery string
+	}{
+		{sqlmodel.DMLReplace, "REPLACE INTO `test`.`t1` (`a`,`ts`,`b`) VALUES (?,?,?)"},
+		{sqlmodel.DMLInsert, "INSERT INTO `test`.`t1` (`a`,`ts`,`b`) VALUES (?,?,?)"},
+		{sqlmodel.DMLInsertIgnore, "INSERT IGNORE INTO `test`.`t1` (`a`,`ts`,`b`) VALUES (?,?,?)"},
+		{
+			sqlmodel.DMLInsertOnDuplicateUpdate,
+			"INSERT INTO `test`.`t1` (`a`,`ts`,`b`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE " +
+				"`a`=IF(VALUES(`ts`) >= `ts`,VALUES(`a`),`a`)," +
+				"`b`=IF(VALUES(`ts`) >= `ts`,VALUES(`b`),`b`)," +
+				"`ts`=IF(VALUES(`ts`) >= `ts`,VALUES(`ts`),`ts`)",
+		},
+	}
+	for _, tc := range testCases {
+		query, args := prepareReplace("`test`.`t1`", cols, true, tc.tp, "ts")
+		require.Equal(t, tc.expectedQuery, query)
+		require.Equal(t, []interface{}{1, "2023-01-01 00:00:00", "b"}, args)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"strings"
 	"time"
 
 	dmysql "github.com/go-sql-driver/mysql"
@@ -61,7 +62,7 @@ const (
 type mysqlBackend struct {
 	workerID    int
 	changefeed  string
-	db          *sql.DB
+	db          *pmysql.Pool
 	cfg         *pmysql.Config
 	dmlMaxRetry uint64
 
@@ -130,8 +131,8 @@ func NewMySQLBackends(
 	// This issue is less likely to occur when the connection pool is larger,
 	// as there are more connections available for use.
 	// Adding an extra connection to the connection pool solves the connection exhaustion issue.
-	db.SetMaxIdleConns(cfg.WorkerCount + 1)
-	db.SetMaxOpenConns(cfg.WorkerCount + 1)
+	// The pool is only used to write the rows, the syncpoints are written by another pool.
+	poolSize := cfg.DMLPoolSize()
 
 	// Inherit the default value of the prepared statement cache from the SinkURI Options
 	cachePrepStmts := cfg.CachePrepStmts
@@ -147,10 +148,10 @@ func NewMySQLBackends(
 		}
 		// if maxPreparedStmtCount == 0,
 		// it means that the prepared statement cache is disabled on serverside.
-		// if maxPreparedStmtCount/(cfg.WorkerCount+1) == 0, for each single connection,
+		// if maxPreparedStmtCount/poolSize == 0, for each single connection,
 		// it means that the prepared statement cache is disabled on clientsize.
 		// Because each connection can not hold at lease one prepared statement.
-		if maxPreparedStmtCount == 0 || maxPreparedStmtCount/(cfg.WorkerCount+1) == 0 {
+		if maxPreparedStmtCount == 0 || maxPreparedStmtCount/poolSize == 0 {
 			cachePrepStmts = false
 		}
 	}
@@ -173,12 +174,13 @@ func NewMySQLBackends(
 		maxAllowedPacket = int64(variable.DefMaxAllowedPacket)
 	}
 
+	pool := pmysql.NewPool(ctx, pmysql.DMLPoolName, db, poolSize, cfg.PoolHealthCheckInterval)
 	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
 		backends = append(backends, &mysqlBackend{
 			workerID:    i,
 			changefeed:  changefeed,
-			db:          db,
+			db:          pool,
 			cfg:         cfg,
 			dmlMaxRetry: defaultDMLMaxRetry,
 			statistics:  statistics,
@@ -225,23 +227,34 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 		s.statistics.ObserveRows(event.Event.Rows...)
 	}
 
-	dmls := s.prepareDMLs()
-	log.Debug("prepare DMLs", zap.Any("rows", s.rows),
-		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
-
-	start := time.Now()
-	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
-		if errors.Cause(err) != context.Canceled {
-			log.Error("execute DMLs failed", zap.Error(err))
+	// the transactions of different origins are executed separately, so the
+	// write source of a downstream transaction is the origin of all its rows.
+	events := s.events
+	for len(events) > 0 {
+		writeSource := s.originOf(events[0].Event)
+		n := 1
+		for n < len(events) && s.originOf(events[n].Event) == writeSource {
+			n++
 		}
-		return errors.Trace(err)
-	}
-	startCallback := time.Now()
-	for _, callback := range dmls.callbacks {
-		callback()
+		dmls := s.prepareDMLs(events[:n])
+		events = events[n:]
+		log.Debug("prepare DMLs", zap.Any("rows", dmls.rowCount),
+			zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+
+		start := time.Now()
+		if err := s.execDMLWithMaxRetries(ctx, dmls, writeSource); err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute DMLs failed", zap.Error(err))
+			}
+			return errors.Trace(err)
+		}
+		startCallback := time.Now()
+		for _, callback := range dmls.callbacks {
+			callback()
+		}
+		s.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
+		s.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
 	}
-	s.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
-	s.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
 
 	// Be friently to GC.
 	for i := 0; i < len(s.events); i++ {
@@ -272,6 +285,29 @@ func (s *mysqlBackend) MaxFlushInterval() time.Duration {
 	return maxFlushInterval
 }
 
+// PreFlight pings the downstream and checks whether the user is granted the
+// privileges to write the rows. The privilege check is skipped if the grants
+// can't be queried, since the downstream may restrict the introspection.
+func (s *mysqlBackend) PreFlight(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return cerror.WrapError(cerror.ErrSinkPreFlightFailed, err,
+			"failed to connect to the downstream, please check the address, "+
+				"the user and the password in the sink uri")
+	}
+	missing, err := pmysql.QueryMissingDMLPrivileges(ctx, s.db.DB)
+	if err != nil {
+		log.Warn("skip the privilege check of the downstream since the grants can't be queried",
+			zap.String("changefeed", s.changefeed), zap.Error(err))
+		return nil
+	}
+	if len(missing) > 0 {
+		return cerror.ErrSinkPreFlightFailed.GenWithStackByArgs(fmt.Sprintf(
+			"the user of the sink uri isn't granted the %s privileges, please grant them "+
+				"on the replicated tables in the downstream", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
 type preparedDMLs struct {
 	startTs         []model.Ts
 	sqls            []string
@@ -363,6 +399,10 @@ func (s *mysqlBackend) groupRowsByType(
 		preAllocateSize = s.cfg.MaxTxnRow
 	}
 
+	// the updated rows are written by INSERT ... ON DUPLICATE KEY UPDATE
+	// together with the inserted rows if batch upsert is enabled.
+	upsert := !spiltUpdate && s.cfg.BatchUpsertEnable
+
 	insertRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
 	updateRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
 	deleteRow := make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -392,13 +432,17 @@ func (s *mysqlBackend) groupRowsByType(
 		}
 
 		if row.IsUpdate() {
-			if spiltUpdate {
-				deleteRow = append(
-					deleteRow,
-					convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete))
-				if len(deleteRow) >= s.cfg.MaxTxnRow {
-					deleteRows = append(deleteRows, deleteRow)
-					deleteRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
+			if spiltUpdate || upsert {
+				// the upserted row overwrites the old row in place
+				// unless the handle key is updated.
+				if (upsert && row.IsHandleKeyUpdated()) || (!upsert && s.needDeleteBeforeInsert(row)) {
+					deleteRow = append(
+						deleteRow,
+						convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete))
+					if len(deleteRow) >= s.cfg.MaxTxnRow {
+						deleteRows = append(deleteRows, deleteRow)
+						deleteRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
+					}
 				}
 				insertRow = append(
 					insertRow,
@@ -472,16 +516,22 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 
 	// handle insert
 	if len(insertRows) > 0 {
+		tp := s.insertDMLType(translateToInsert)
 		for _, rows := range insertRows {
-			if translateToInsert {
-				sql, value := sqlmodel.GenInsertSQL(sqlmodel.DMLInsert, rows...)
-				sqls = append(sqls, sql)
-				values = append(values, value)
-			} else {
-				sql, value := sqlmodel.GenInsertSQL(sqlmodel.DMLReplace, rows...)
-				sqls = append(sqls, sql)
-				values = append(values, value)
+			var (
+				sql   string
+				value []interface{}
+			)
+			switch {
+			case translateToInsert && s.cfg.BatchUpsertEnable:
+				sql, value = sqlmodel.GenInsertSQL(sqlmodel.DMLInsertOnDuplicateUpdate, rows...)
+			case tp == sqlmodel.DMLInsertOnDuplicateUpdate:
+				sql, value = sqlmodel.GenInsertIfNewerSQL(s.cfg.ConflictTimestampColumn, rows...)
+			default:
+				sql, value = sqlmodel.GenInsertSQL(tp, rows...)
 			}
+			sqls = append(sqls, sql)
+			values = append(values, value)
 		}
 	}
 
@@ -510,6 +560,35 @@ func (s *mysqlBackend) genUpdateSQL(rows ...*sqlmodel.RowChange) ([]string, [][]
 	return sqls, values
 }
 
+// insertDMLType returns the type of the DML written for the inserted rows,
+// the conflict strategy takes effect if the rows can't be translated to INSERT.
+func (s *mysqlBackend) insertDMLType(translateToInsert bool) sqlmodel.DMLType {
+	if translateToInsert {
+		return sqlmodel.DMLInsert
+	}
+	switch s.cfg.ConflictStrategy {
+	case pmysql.ConflictStrategyError:
+		return sqlmodel.DMLInsert
+	case pmysql.ConflictStrategyIgnore:
+		return sqlmodel.DMLInsertIgnore
+	case pmysql.ConflictStrategyTimestampWins:
+		return sqlmodel.DMLInsertOnDuplicateUpdate
+	default:
+		return sqlmodel.DMLReplace
+	}
+}
+
+// needDeleteBeforeInsert returns whether the update event which is split into
+// DELETE + INSERT needs the DELETE. It's not needed by the timestamp-wins
+// strategy if the handle key is not updated, otherwise the newer row in the
+// downstream is deleted before the timestamp is compared.
+func (s *mysqlBackend) needDeleteBeforeInsert(row *model.RowChangedEvent) bool {
+	if s.cfg.ConflictStrategy != pmysql.ConflictStrategyTimestampWins {
+		return true
+	}
+	return row.IsHandleKeyUpdated()
+}
+
 func hasHandleKey(cols []*model.Column) bool {
 	for _, col := range cols {
 		if col == nil {
@@ -523,12 +602,12 @@ func hasHandleKey(cols []*model.Column) bool {
 }
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
-func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+func (s *mysqlBackend) prepareDMLs(events []*dmlsink.TxnCallbackableEvent) *preparedDMLs {
 	// TODO: use a sync.Pool to reduce allocations.
 	startTs := make([]uint64, 0, s.rows)
 	sqls := make([]string, 0, s.rows)
 	values := make([][]interface{}, 0, s.rows)
-	callbacks := make([]dmlsink.CallbackFunc, 0, len(s.events))
+	callbacks := make([]dmlsink.CallbackFunc, 0, len(events))
 
 	// translateToInsert control the update and insert behavior
 	// we only translate into insert when old value is enabled and safe mode is disabled
@@ -536,7 +615,7 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 
 	rowCount := 0
 	approximateSize := int64(0)
-	for _, event := range s.events {
+	for _, event := range events {
 		if len(event.Event.Rows) == 0 {
 			continue
 		}
@@ -608,7 +687,7 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			// So we will prepare a DELETE SQL here.
 			// For delete event:
 			// It will be translated directly into a DELETE SQL.
-			if len(row.PreColumns) != 0 {
+			if len(row.PreColumns) != 0 && (len(row.Columns) == 0 || s.needDeleteBeforeInsert(row)) {
 				query, args = prepareDelete(quoteTable, row.PreColumns, s.cfg.ForceReplicate)
 				if query != "" {
 					sqls = append(sqls, query)
@@ -623,9 +702,11 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			// For insert event:
 			// It will be translated directly into a
 			// INSERT(old value is enabled and not in safe mode)
-			// or REPLACE(old value is disabled or in safe mode) SQL.
+			// or REPLACE(old value is disabled or in safe mode) SQL,
+			// the REPLACE is written as the conflict strategy requires.
 			if len(row.Columns) != 0 {
-				query, args = prepareReplace(quoteTable, row.Columns, true /* appendPlaceHolder */, translateToInsert)
+				query, args = prepareReplace(quoteTable, row.Columns, true, /* appendPlaceHolder */
+					s.insertDMLType(translateToInsert), s.cfg.ConflictTimestampColumn)
 				if query != "" {
 					sqls = append(sqls, query)
 					values = append(values, args)
@@ -733,7 +814,9 @@ func (s *mysqlBackend) sequenceExecute(
 	return nil
 }
 
-func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *preparedDMLs) error {
+func (s *mysqlBackend) execDMLWithMaxRetries(
+	pctx context.Context, dmls *preparedDMLs, writeSource uint64,
+) error {
 	if len(dmls.sqls) != len(dmls.values) {
 		log.Panic("unexpected number of sqls and values",
 			zap.Strings("sqls", dmls.sqls),
@@ -783,12 +866,12 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 
 			// we try to set write source for each txn,
 			// so we can use it to trace the data source
-			if err = s.setWriteSource(pctx, tx); err != nil {
+			if err = s.setWriteSource(pctx, tx, writeSource); err != nil {
 				err := logDMLTxnErr(
 					cerror.WrapError(cerror.ErrMySQLTxnError, err),
 					start, s.changefeed,
 					fmt.Sprintf("SET SESSION %s = %d", "tidb_cdc_write_source",
-						s.cfg.SourceID),
+						writeSource),
 					dmls.rowCount, dmls.startTs)
 				if rbErr := tx.Rollback(); rbErr != nil {
 					if errors.Cause(rbErr) != context.Canceled {
@@ -798,6 +881,17 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 				return 0, err
 			}
 
+			failpoint.Inject("MySQLSinkTxnCommitError", func() {
+				// the transaction is rolled back as if the connection is
+				// broken when committing, the whole batch should be retried.
+				log.Info("MySQLSinkTxnCommitError error injected",
+					zap.String("changefeed", s.changefeed))
+				_ = tx.Rollback()
+				failpoint.Return(0, logDMLTxnErr(
+					cerror.WrapError(cerror.ErrMySQLTxnError, errors.Trace(driver.ErrBadConn)),
+					start, s.changefeed, "COMMIT", dmls.rowCount, dmls.startTs))
+			})
+
 			if err = tx.Commit(); err != nil {
 				return 0, logDMLTxnErr(
 					cerror.WrapError(cerror.ErrMySQLTxnError, err),
@@ -872,8 +966,17 @@ func (s *mysqlBackend) setDMLMaxRetry(maxRetry uint64) {
 	s.dmlMaxRetry = maxRetry
 }
 
+// originOf returns the origin of the rows in the transaction, the rows which
+// are written in the upstream directly come from the upstream itself.
+func (s *mysqlBackend) originOf(txn *model.SingleTableTxn) uint64 {
+	if len(txn.Rows) != 0 && txn.Rows[0].OriginID != 0 {
+		return txn.Rows[0].OriginID
+	}
+	return s.cfg.SourceID
+}
+
 // setWriteSource sets write source for the transaction.
-func (s *mysqlBackend) setWriteSource(ctx context.Context, txn *sql.Tx) error {
+func (s *mysqlBackend) setWriteSource(ctx context.Context, txn *sql.Tx, writeSource uint64) error {
 	// we only set write source when donwstream is TiDB and write source is existed.
 	if !s.cfg.IsWriteSourceExisted {
 		return nil
@@ -881,7 +984,7 @@ func (s *mysqlBackend) setWriteSource(ctx context.Context, txn *sql.Tx) error {
 	// downstream is TiDB, set system variables.
 	// We should always try to set this variable, and ignore the error if
 	// downstream does not support this variable, it is by design.
-	query := fmt.Sprintf("SET SESSION %s = %d", "tidb_cdc_write_source", s.cfg.SourceID)
+	query := fmt.Sprintf("SET SESSION %s = %d", "tidb_cdc_write_source", writeSource)
 	_, err := txn.ExecContext(ctx, query)
 	if err != nil {
 		if mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError); ok &&
@@ -177,7 +177,7 @@ func TestPrepareDML(t *testing.T) {
 			Event: &model.SingleTableTxn{Rows: tc.input},
 		}
 		ms.rows = len(tc.input)
-		dmls := ms.prepareDMLs()
+		dmls := ms.prepareDMLs(ms.events)
 		require.Equal(t, tc.expected, dmls)
 	}
 }
@@ -373,6 +373,75 @@ func TestNewMySQLBackendExecDML(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+// Test the transactions of different origins are executed with their own write sources.
+func TestMySQLBackendWriteSourceOfOrigin(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?);INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("SET SESSION tidb_cdc_write_source = 3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.TiDBSourceID = 1
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+	sink.cfg.IsWriteSourceExisted = true
+
+	newTxn := func(startTs uint64, originID uint64, value int) *dmlsink.TxnCallbackableEvent {
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{{
+				StartTs:  startTs,
+				CommitTs: startTs + 1,
+				Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+				Columns: []*model.Column{{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: value,
+				}},
+				OriginID: originID,
+			}}},
+			Callback: func() {},
+		}
+	}
+	// the rows written in the upstream directly come from the upstream itself.
+	_ = sink.OnTxnEvent(newTxn(1, 0, 1))
+	_ = sink.OnTxnEvent(newTxn(3, 1, 2))
+	_ = sink.OnTxnEvent(newTxn(5, 3, 3))
+	require.Nil(t, sink.Flush(context.Background()))
+	require.Nil(t, sink.Close())
+}
+
 func TestExecDMLRollbackErrDatabaseNotExists(t *testing.T) {
 	rows := []*model.RowChangedEvent{
 		{
@@ -1160,7 +1229,7 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 			Event: &model.SingleTableTxn{Rows: tc.input},
 		}
 		ms.rows = len(tc.input)
-		dmls := ms.prepareDMLs()
+		dmls := ms.prepareDMLs(ms.events)
 		require.Equal(t, tc.expected, dmls, tc.name)
 	}
 }
@@ -1633,11 +1702,165 @@ func TestPrepareBatchDMLs(t *testing.T) {
 			Event: &model.SingleTableTxn{Rows: tc.input},
 		}
 		ms.rows = len(tc.input)
-		dmls := ms.prepareDMLs()
+		dmls := ms.prepareDMLs(ms.events)
 		require.Equal(t, tc.expected, dmls)
 	}
 }
 
+func TestPrepareDMLsWithConflictStrategy(t *testing.T) {
+	t.Parallel()
+
+	newColumns := func(id int, ts string) []*model.Column {
+		return []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: id,
+		}, {
+			Name:  "ts",
+			Type:  mysql.TypeTimestamp,
+			Value: ts,
+		}}
+	}
+	table := &model.TableName{Schema: "test", Table: "t"}
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:      1,
+			CommitTs:     2,
+			Table:        table,
+			Columns:      newColumns(1, "2023-01-01 00:00:00"),
+			IndexColumns: [][]int{{0}},
+		},
+		{
+			StartTs:      1,
+			CommitTs:     2,
+			Table:        table,
+			PreColumns:   newColumns(2, "2023-01-01 00:00:00"),
+			Columns:      newColumns(2, "2023-01-02 00:00:00"),
+			IndexColumns: [][]int{{0}},
+		},
+	}
+	const upsert = "INSERT INTO `test`.`t` (`id`,`ts`) VALUES (?,?) ON DUPLICATE KEY UPDATE " +
+		"`id`=IF(VALUES(`ts`) >= `ts`,VALUES(`id`),`id`),`ts`=IF(VALUES(`ts`) >= `ts`,VALUES(`ts`),`ts`)"
+	testCases := []struct {
+		strategy       string
+		batchDMLEnable bool
+		expected       []string
+	}{
+		{
+			strategy: pmysql.ConflictStrategyIgnore,
+			expected: []string{
+				"INSERT IGNORE INTO `test`.`t` (`id`,`ts`) VALUES (?,?)",
+				"DELETE FROM `test`.`t` WHERE `id` = ? LIMIT 1",
+				"INSERT IGNORE INTO `test`.`t` (`id`,`ts`) VALUES (?,?)",
+			},
+		},
+		{
+			strategy: pmysql.ConflictStrategyTimestampWins,
+			expected: []string{upsert, upsert},
+		},
+		{
+			strategy:       pmysql.ConflictStrategyError,
+			batchDMLEnable: true,
+			expected: []string{
+				"DELETE FROM `test`.`t` WHERE (`id` = ?)",
+				"INSERT INTO `test`.`t` (`id`,`ts`) VALUES (?,?),(?,?)",
+			},
+		},
+		{
+			strategy:       pmysql.ConflictStrategyTimestampWins,
+			batchDMLEnable: true,
+			expected: []string{
+				"INSERT INTO `test`.`t` (`id`,`ts`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE " +
+					"`id`=IF(VALUES(`ts`) >= `ts`,VALUES(`id`),`id`),`ts`=IF(VALUES(`ts`) >= `ts`,VALUES(`ts`),`ts`)",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.SafeMode = true
+		ms.cfg.EnableOldValue = true
+		ms.cfg.BatchDMLEnable = tc.batchDMLEnable
+		ms.cfg.ConflictStrategy = tc.strategy
+		ms.cfg.ConflictTimestampColumn = "ts"
+		ms.events = []*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{Rows: rows},
+		}}
+		ms.rows = len(rows)
+		dmls := ms.prepareDMLs(ms.events)
+		require.Equal(t, tc.expected, dmls.sqls, tc.strategy)
+	}
+}
+
+func TestPrepareDMLsWithBatchUpsert(t *testing.T) {
+	t.Parallel()
+
+	newColumns := func(id int, name string) []*model.Column {
+		return []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: id,
+		}, {
+			Name:  "name",
+			Type:  mysql.TypeVarchar,
+			Value: name,
+		}}
+	}
+	table := &model.TableName{Schema: "test", Table: "t"}
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:      1,
+			CommitTs:     2,
+			Table:        table,
+			Columns:      newColumns(1, "a"),
+			IndexColumns: [][]int{{0}},
+		},
+		{
+			StartTs:      1,
+			CommitTs:     2,
+			Table:        table,
+			PreColumns:   newColumns(2, "b"),
+			Columns:      newColumns(2, "bb"),
+			IndexColumns: [][]int{{0}},
+		},
+		{
+			StartTs:      1,
+			CommitTs:     2,
+			Table:        table,
+			PreColumns:   newColumns(3, "c"),
+			Columns:      newColumns(4, "c"),
+			IndexColumns: [][]int{{0}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.SafeMode = false
+	ms.cfg.EnableOldValue = true
+	ms.cfg.BatchDMLEnable = true
+	ms.cfg.BatchUpsertEnable = true
+	ms.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: rows},
+	}}
+	ms.rows = len(rows)
+	dmls := ms.prepareDMLs(ms.events)
+	// the row whose handle key is updated is deleted before upserted.
+	require.Equal(t, []string{
+		"DELETE FROM `test`.`t` WHERE (`id` = ?)",
+		"INSERT INTO `test`.`t` (`id`,`name`) VALUES (?,?),(?,?),(?,?) " +
+			"ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`name`=VALUES(`name`)",
+	}, dmls.sqls)
+	require.Equal(t, [][]interface{}{
+		{3},
+		{1, "a", 2, "bb", 4, "c"},
+	}, dmls.values)
+}
+
 func TestGroupRowsByType(t *testing.T) {
 	ctx := context.Background()
 	ms := newMySQLBackendWithoutDB(ctx)
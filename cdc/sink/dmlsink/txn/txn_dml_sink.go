@@ -154,6 +154,17 @@ func (s *dmlSink) WriteEvents(txnEvents ...*dmlsink.TxnCallbackableEvent) error
 	return nil
 }
 
+// PreFlight checks the downstream by the first backend which supports it, the
+// backends share the same downstream so checking one of them is enough.
+func (s *dmlSink) PreFlight(ctx context.Context) error {
+	for _, w := range s.workers {
+		if checker, ok := w.backend.(dmlsink.PreFlightChecker); ok {
+			return checker.PreFlight(ctx)
+		}
+	}
+	return nil
+}
+
 // Close closes the dmlSink. It won't wait for all pending items backend handled.
 func (s *dmlSink) Close() {
 	if s.cancel != nil {
@@ -23,7 +23,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -33,9 +35,10 @@ type txnWithNotifier struct {
 }
 
 type worker struct {
-	ctx         context.Context
-	changefeed  string
-	workerCount int
+	ctx          context.Context
+	changefeedID model.ChangeFeedID
+	changefeed   string
+	workerCount  int
 
 	ID      int
 	txnCh   *chann.DrainableChann[txnWithNotifier]
@@ -59,9 +62,10 @@ func newWorker(ctx context.Context, changefeedID model.ChangeFeedID,
 ) *worker {
 	wid := fmt.Sprintf("%d", ID)
 	return &worker{
-		ctx:         ctx,
-		changefeed:  fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID),
-		workerCount: workerCount,
+		ctx:          ctx,
+		changefeedID: changefeedID,
+		changefeed:   fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID),
+		workerCount:  workerCount,
 
 		ID:      ID,
 		txnCh:   chann.NewAutoDrainChann[txnWithNotifier](chann.Cap(-1 /*unbounded*/)),
@@ -177,7 +181,12 @@ func (w *worker) doFlush(flushTimeSlice *time.Duration) error {
 			w.metricTxnWorkerFlushDuration.Observe(elapsed.Seconds())
 		}()
 
-		if err := w.backend.Flush(w.ctx); err != nil {
+		ctx, span := tracing.StartSpan(w.ctx, "txn.flush", w.changefeedID,
+			attribute.Int("workerID", w.ID),
+			attribute.Int("txns", len(w.wantMoreCallbacks)))
+		err := w.backend.Flush(ctx)
+		tracing.EndSpan(span, err)
+		if err != nil {
 			log.Warn("Transaction dmlSink backend flush fail",
 				zap.String("changefeedID", w.changefeed),
 				zap.Int("workerID", w.ID),
@@ -0,0 +1,87 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+)
+
+// executor posts the batches to the endpoint.
+type executor interface {
+	Post(ctx context.Context, contentType string, data []byte) error
+}
+
+// Assert Backend implementation
+var _ batchsink.Backend = (*backend)(nil)
+
+// backend posts the batches to the endpoint, the transactions are encoded
+// into a batch, which is posted by a request.
+type backend struct {
+	client      executor
+	encoder     codec.TxnEventEncoder
+	contentType string
+
+	data bytes.Buffer
+	rows int
+}
+
+func newBackend(client executor, encoder codec.TxnEventEncoder, contentType string) *backend {
+	return &backend{
+		client:      client,
+		encoder:     encoder,
+		contentType: contentType,
+	}
+}
+
+// Append implements batchsink.Backend.
+func (b *backend) Append(txn *model.SingleTableTxn) (int, error) {
+	if err := b.encoder.AppendTxnEvent(txn, nil); err != nil {
+		return 0, errors.Trace(err)
+	}
+	rows := 0
+	for _, msg := range b.encoder.Build() {
+		b.data.Write(msg.Value)
+		rows += msg.GetRowsCount()
+	}
+	b.rows += rows
+	return rows, nil
+}
+
+// Flush implements batchsink.Backend.
+func (b *backend) Flush(ctx context.Context) (int, error) {
+	defer b.reset()
+	if b.data.Len() == 0 {
+		return 0, nil
+	}
+	if err := b.client.Post(ctx, b.contentType, b.data.Bytes()); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return b.rows, nil
+}
+
+func (b *backend) reset() {
+	b.data.Reset()
+	b.rows = 0
+}
+
+// Close implements batchsink.Backend.
+func (b *backend) Close() error {
+	return nil
+}
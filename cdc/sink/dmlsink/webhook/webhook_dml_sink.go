@@ -0,0 +1,82 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"math"
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	pwebhook "github.com/pingcap/tiflow/pkg/sink/webhook"
+	putil "github.com/pingcap/tiflow/pkg/util"
+)
+
+// contentTypes is the content types of the requests of the supported protocols.
+var contentTypes = map[config.Protocol]string{
+	config.ProtocolCanalJSON: "application/x-ndjson",
+	config.ProtocolCsv:       "text/csv",
+}
+
+// NewDMLSink creates a webhook sink.
+// It posts the encoded batches of the row changes to the endpoint, the
+// callbacks of the transactions are called only after the endpoint accepts
+// their batches, so the events are delivered at least once.
+func NewDMLSink(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*batchsink.DMLSink, error) {
+	cfg := pwebhook.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	// the batches are encoded in canal-json by default.
+	protocol := config.ProtocolCanalJSON
+	if p := putil.GetOrZero(replicaConfig.Sink.Protocol); p != "" {
+		var err error
+		if protocol, err = config.ParseSinkProtocolFromString(p); err != nil {
+			return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+	}
+	contentType, ok := contentTypes[protocol]
+	if !ok {
+		return nil, cerror.ErrSinkInvalidConfig.GenWithStack(
+			"unsupported protocol %s for webhook sink, it must be %s or %s",
+			protocol, config.ProtocolCanalJSON, config.ProtocolCsv)
+	}
+	// the size of a message isn't limited since a request may contain many messages.
+	encoderConfig, err := util.GetEncoderConfig(sinkURI, protocol, replicaConfig, math.MaxInt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	encoderBuilder, err := builder.NewTxnEventEncoderBuilder(encoderConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	client := pwebhook.NewClient(cfg)
+	backends := make([]batchsink.Backend, cfg.WorkerCount)
+	for i := range backends {
+		backends[i] = newBackend(client, encoderBuilder.Build(), contentType)
+	}
+	return batchsink.New(ctx, changefeedID, "webhook", backends, cfg.MaxBatchRows, errCh), nil
+}
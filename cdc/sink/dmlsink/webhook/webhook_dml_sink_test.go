@@ -0,0 +1,176 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/batchsink"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type mockServer struct {
+	sync.Mutex
+	*httptest.Server
+	contentTypes []string
+	bodies       []string
+	status       int
+}
+
+func newMockServer() *mockServer {
+	s := &mockServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		s.Lock()
+		defer s.Unlock()
+		s.contentTypes = append(s.contentTypes, r.Header.Get("Content-Type"))
+		s.bodies = append(s.bodies, string(data))
+		w.WriteHeader(s.status)
+	}))
+	return s
+}
+
+func (s *mockServer) rows() int {
+	s.Lock()
+	defer s.Unlock()
+	rows := 0
+	for _, body := range s.bodies {
+		rows += strings.Count(body, "\n")
+	}
+	return rows
+}
+
+func newTestRows(commitTs uint64) []*model.RowChangedEvent {
+	table := &model.TableName{Schema: "test", Table: "table1"}
+	tableInfo := &model.TableInfo{
+		TableName: *table,
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{ID: 1, Name: timodel.NewCIStr("c1"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+				{ID: 2, Name: timodel.NewCIStr("c2"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+			},
+		},
+	}
+	rows := make([]*model.RowChangedEvent, 0, 10)
+	for i := 0; i < 10; i++ {
+		rows = append(rows, &model.RowChangedEvent{
+			CommitTs:  commitTs,
+			Table:     table,
+			TableInfo: tableInfo,
+			Columns: []*model.Column{
+				{Name: "c1", Type: mysql.TypeLong, Value: i},
+				{Name: "c2", Type: mysql.TypeVarchar, Value: "hello world"},
+			},
+			ColInfos: []rowcodec.ColInfo{
+				{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+				{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			},
+		})
+	}
+	return rows
+}
+
+func TestWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	for protocol, contentType := range contentTypes {
+		server := newMockServer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sinkURI, err := url.Parse(server.URL + "/events?worker-count=2")
+		require.NoError(t, err)
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = util.AddressOf(protocol.String())
+		errCh := make(chan error, 1)
+		s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, replicaConfig, errCh)
+		require.NoError(t, err)
+
+		var cnt uint64
+		tableStatus := state.TableSinkSinking
+		require.NoError(t, s.WriteEvents(batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+		require.Eventually(t, func() bool {
+			return atomic.LoadUint64(&cnt) == 10
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, 100, server.rows(), protocol)
+		server.Lock()
+		for _, ct := range server.contentTypes {
+			require.Equal(t, contentType, ct)
+		}
+		server.Unlock()
+
+		cancel()
+		s.Close()
+		server.Close()
+	}
+}
+
+func TestWriteEventsError(t *testing.T) {
+	t.Parallel()
+
+	server := newMockServer()
+	defer server.Close()
+	server.status = http.StatusUnauthorized
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	errCh := make(chan error, 1)
+	s, err := NewDMLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI,
+		config.GetDefaultReplicaConfig(), errCh)
+	require.NoError(t, err)
+	defer s.Close()
+
+	// the callbacks aren't called if the batches are rejected.
+	var cnt uint64
+	tableStatus := state.TableSinkSinking
+	require.NoError(t, s.WriteEvents(batchsink.NewTestTxns(&cnt, &tableStatus, newTestRows)...))
+	select {
+	case err := <-errCh:
+		require.ErrorContains(t, err, "status: 401")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error is not reported")
+	}
+	<-s.Dead()
+	require.Zero(t, atomic.LoadUint64(&cnt))
+}
+
+func TestNewDMLSinkInvalidProtocol(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("http://127.0.0.1/?protocol=avro")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = util.AddressOf(config.ProtocolAvro.String())
+	_, err = NewDMLSink(context.Background(), model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig, make(chan error, 1))
+	require.ErrorContains(t, err, "unsupported protocol avro")
+}
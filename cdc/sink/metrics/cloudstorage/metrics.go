@@ -36,10 +36,19 @@ var (
 		Name:      "cloud_storage_file_count",
 		Help:      "Total number of files managed by a cloud storage sink",
 	}, []string{"namespace", "changefeed"})
+
+	// CloudStorageDiskAvailableBytesGauge records the available disk space of the local file system storage.
+	CloudStorageDiskAvailableBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "cloud_storage_disk_available_bytes",
+		Help:      "Available disk space of the local file system used by a cloud storage sink",
+	}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file.
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(CloudStorageWriteBytesGauge)
 	registry.MustRegister(CloudStorageFileCountGauge)
+	registry.MustRegister(CloudStorageDiskAvailableBytesGauge)
 }
@@ -64,6 +64,27 @@ var (
 			Name:      "execution_error",
 			Help:      "Total count of execution errors.",
 		}, []string{"namespace", "changefeed", "type"}) // type is for `sinkType`
+
+	// TableWriteDurationHistogram records the duration of writing the rows of a
+	// table, it's only recorded if the per-table metrics are enabled.
+	TableWriteDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "table_write_duration",
+			Help:      "Bucketed histogram of the duration (s) of writing the rows of a table.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 20), // 1ms~524s
+		}, []string{"namespace", "changefeed", "type", "table"}) // type is for `sinkType`
+
+	// TableWriteRowsCounter is the counter of the written rows of a table,
+	// it's only recorded if the per-table metrics are enabled.
+	TableWriteRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "table_write_rows",
+			Help:      "Total count of the written rows of a table.",
+		}, []string{"namespace", "changefeed", "type", "table"}) // type is for `sinkType`
 )
 
 // InitMetrics registers all metrics in this file.
@@ -72,6 +93,8 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(ExecDDLHistogram)
 	registry.MustRegister(LargeRowSizeHistogram)
 	registry.MustRegister(ExecutionErrorCounter)
+	registry.MustRegister(TableWriteDurationHistogram)
+	registry.MustRegister(TableWriteRowsCounter)
 
 	tablesink.InitMetrics(registry)
 	txn.InitMetrics(registry)
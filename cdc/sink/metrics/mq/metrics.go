@@ -36,7 +36,7 @@ var (
 			Subsystem: "sink",
 			Name:      "mq_worker_batch_size",
 			Help:      "Batch size for MQ worker.",
-			Buckets:   prometheus.ExponentialBuckets(4, 2, 10), // 4 ~ 2048
+			Buckets:   prometheus.ExponentialBuckets(4, 2, 13), // 4 ~ 16384
 		}, []string{"namespace", "changefeed"})
 	// WorkerBatchDuration record the time duration cost on batch messages.
 	WorkerBatchDuration = prometheus.NewHistogramVec(
@@ -66,6 +66,34 @@ var (
 			Name:      "mq_claim_check_send_message_count",
 			Help:      "The total count of messages sent to the external claim-check storage.",
 		}, []string{"namespace", "changefeed"})
+
+	// ClaimCheckStoredBytes records the total size of the files in the claim-check
+	// storage, which is updated when the expired files are cleaned up.
+	ClaimCheckStoredBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mq_claim_check_stored_bytes",
+			Help:      "The total size of the files in the external claim-check storage.",
+		}, []string{"namespace", "changefeed"})
+
+	// ClaimCheckDeletedFileCount records the total count of the expired files deleted from the claim-check storage.
+	ClaimCheckDeletedFileCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mq_claim_check_deleted_file_count",
+			Help:      "The total count of the expired files deleted from the external claim-check storage.",
+		}, []string{"namespace", "changefeed"})
+
+	// DeadLetterMessageCount records the total count of events diverted to the dead-letter queue.
+	DeadLetterMessageCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "mq_dead_letter_message_count",
+			Help:      "The total count of events which can not be encoded and are diverted to the dead-letter queue.",
+		}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -75,6 +103,9 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(WorkerBatchDuration)
 	registry.MustRegister(ClaimCheckSendMessageDuration)
 	registry.MustRegister(ClaimCheckSendMessageCount)
+	registry.MustRegister(ClaimCheckStoredBytes)
+	registry.MustRegister(ClaimCheckDeletedFileCount)
+	registry.MustRegister(DeadLetterMessageCount)
 	codec.InitMetrics(registry)
 	kafka.InitMetrics(registry)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -59,6 +60,22 @@ type Statistics struct {
 	metricRowSizeHis prometheus.Observer
 	// Counter for sink error.
 	metricExecErrCnt prometheus.Counter
+
+	// tableLabeler is nil if the per-table metrics are disabled.
+	tableLabeler *tableLabeler
+}
+
+// WithTableMetrics enables the per-table metrics if they're enabled in the config.
+func (b *Statistics) WithTableMetrics(cfg *config.TableMetricsConfig) *Statistics {
+	if cfg == nil || !util.GetOrZero(cfg.Enable) {
+		return b
+	}
+	maxTables := config.DefaultTableMetricsMaxTables
+	if cfg.MaxTables != nil {
+		maxTables = *cfg.MaxTables
+	}
+	b.tableLabeler = newTableLabeler(maxTables)
+	return b
 }
 
 // ObserveRows stats all received `RowChangedEvent`s.
@@ -83,6 +100,34 @@ func (b *Statistics) RecordBatchExecution(executor func() (int, error)) error {
 	return nil
 }
 
+// RecordTableExecution stats the batch executors of a table like RecordBatchExecution,
+// the duration and rows are recorded by the table if the per-table metrics are enabled.
+// The per-table metrics are skipped if the table of the batch is unknown.
+func (b *Statistics) RecordTableExecution(
+	schema, table string, executor func() (int, error),
+) error {
+	if b.tableLabeler == nil || table == "" {
+		return b.RecordBatchExecution(executor)
+	}
+	start := time.Now()
+	batchSize, err := executor()
+	if err != nil {
+		b.metricExecErrCnt.Inc()
+		return err
+	}
+	b.metricExecBatchHis.Observe(float64(batchSize))
+
+	label := b.tableLabeler.label(schema, table)
+	s := b.sinkType.String()
+	TableWriteDurationHistogram.
+		WithLabelValues(b.changefeedID.Namespace, b.changefeedID.ID, s, label).
+		Observe(time.Since(start).Seconds())
+	TableWriteRowsCounter.
+		WithLabelValues(b.changefeedID.Namespace, b.changefeedID.ID, s, label).
+		Add(float64(batchSize))
+	return nil
+}
+
 // RecordDDLExecution record the time cost of execute ddl
 func (b *Statistics) RecordDDLExecution(executor func() error) error {
 	start := time.Now()
@@ -100,4 +145,13 @@ func (b *Statistics) Close() {
 	ExecBatchHistogram.DeleteLabelValues(b.changefeedID.Namespace, b.changefeedID.ID)
 	LargeRowSizeHistogram.DeleteLabelValues(b.changefeedID.Namespace, b.changefeedID.ID)
 	ExecutionErrorCounter.DeleteLabelValues(b.changefeedID.Namespace, b.changefeedID.ID)
+	if b.tableLabeler != nil {
+		labels := prometheus.Labels{
+			"namespace":  b.changefeedID.Namespace,
+			"changefeed": b.changefeedID.ID,
+			"type":       b.sinkType.String(),
+		}
+		TableWriteDurationHistogram.DeletePartialMatch(labels)
+		TableWriteRowsCounter.DeletePartialMatch(labels)
+	}
 }
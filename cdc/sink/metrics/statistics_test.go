@@ -0,0 +1,86 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableLabeler(t *testing.T) {
+	t.Parallel()
+
+	l := newTableLabeler(2)
+	require.Equal(t, "test.t1", l.label("test", "t1"))
+	require.Equal(t, "test.t2", l.label("test", "t2"))
+	require.Equal(t, OtherTablesLabel, l.label("test", "t3"))
+	// the labeled tables keep their labels.
+	require.Equal(t, "test.t1", l.label("test", "t1"))
+	require.Equal(t, OtherTablesLabel, l.label("test", "t4"))
+}
+
+func TestRecordTableExecution(t *testing.T) {
+	t.Parallel()
+
+	id := model.DefaultChangeFeedID("record-table-execution")
+	statistics := NewStatistics(context.Background(), id, sink.RowSink).
+		WithTableMetrics(&config.TableMetricsConfig{
+			Enable:    util.AddressOf(true),
+			MaxTables: util.AddressOf(1),
+		})
+	defer statistics.Close()
+
+	rows := func(table string) float64 {
+		return testutil.ToFloat64(TableWriteRowsCounter.WithLabelValues(
+			id.Namespace, id.ID, sink.RowSink.String(), table))
+	}
+	execute := func(n int) func() (int, error) {
+		return func() (int, error) { return n, nil }
+	}
+	require.NoError(t, statistics.RecordTableExecution("test", "t1", execute(3)))
+	require.NoError(t, statistics.RecordTableExecution("test", "t2", execute(2)))
+	require.NoError(t, statistics.RecordTableExecution("test", "t3", execute(1)))
+	// the batches of the unknown tables aren't recorded by the table.
+	require.NoError(t, statistics.RecordTableExecution("", "", execute(5)))
+	require.Equal(t, float64(3), rows("test.t1"))
+	require.Equal(t, float64(3), rows(OtherTablesLabel))
+
+	err := statistics.RecordTableExecution("test", "t1", func() (int, error) {
+		return 0, errors.New("injected error")
+	})
+	require.Error(t, err)
+	require.Equal(t, float64(3), rows("test.t1"))
+}
+
+func TestRecordTableExecutionDisabled(t *testing.T) {
+	t.Parallel()
+
+	id := model.DefaultChangeFeedID("record-table-execution-disabled")
+	statistics := NewStatistics(context.Background(), id, sink.TxnSink).
+		WithTableMetrics(&config.TableMetricsConfig{Enable: util.AddressOf(false)})
+	defer statistics.Close()
+
+	require.NoError(t, statistics.RecordTableExecution("test", "t1",
+		func() (int, error) { return 1, nil }))
+	require.Zero(t, testutil.CollectAndCount(TableWriteRowsCounter.MustCurryWith(
+		map[string]string{"namespace": id.Namespace, "changefeed": id.ID})))
+}
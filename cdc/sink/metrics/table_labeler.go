@@ -0,0 +1,54 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+)
+
+// OtherTablesLabel is the table label shared by the tables which exceed the
+// max number of the labeled tables of a changefeed.
+const OtherTablesLabel = "_other"
+
+// tableLabeler assigns the table labels of the per-table metrics. The first
+// maxTables tables are labeled by their names and the others share a label,
+// so the cardinality of the metrics is bounded.
+type tableLabeler struct {
+	maxTables int
+
+	mu     sync.Mutex
+	labels map[string]struct{}
+}
+
+func newTableLabeler(maxTables int) *tableLabeler {
+	return &tableLabeler{
+		maxTables: maxTables,
+		labels:    make(map[string]struct{}),
+	}
+}
+
+// label returns the table label of the table, it's thread-safe.
+func (l *tableLabeler) label(schema, table string) string {
+	name := schema + "." + table
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.labels[name]; ok {
+		return name
+	}
+	if len(l.labels) >= l.maxTables {
+		return OtherTablesLabel
+	}
+	l.labels[name] = struct{}{}
+	return name
+}
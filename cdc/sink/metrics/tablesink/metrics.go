@@ -26,7 +26,40 @@ var TotalRowsCountCounter = prometheus.NewCounterVec(
 		Help:      "The total count of rows that are processed by table sink",
 	}, []string{"namespace", "changefeed"})
 
+// BufferedBytesGauge is the size of the events buffered by the table sinks
+// before they're resolved.
+var BufferedBytesGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sink",
+		Name:      "table_sink_buffered_bytes",
+		Help:      "The size of the events buffered by the table sinks",
+	}, []string{"namespace", "changefeed"})
+
+// SpilledBytesGauge is the size of the events moved to the disk by the table
+// sinks because the buffer quota is exceeded.
+var SpilledBytesGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sink",
+		Name:      "table_sink_spilled_bytes",
+		Help:      "The size of the events moved to the disk by the table sinks",
+	}, []string{"namespace", "changefeed"})
+
+// SpilledRowsCounter is the total count of rows moved to the disk by the table
+// sinks because the buffer quota is exceeded.
+var SpilledRowsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sink",
+		Name:      "table_sink_spilled_rows_count",
+		Help:      "The total count of rows moved to the disk by the table sinks",
+	}, []string{"namespace", "changefeed"})
+
 // InitMetrics registers all metrics in this file.
 func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(TotalRowsCountCounter)
+	registry.MustRegister(BufferedBytesGauge)
+	registry.MustRegister(SpilledBytesGauge)
+	registry.MustRegister(SpilledRowsCounter)
 }
@@ -0,0 +1,201 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tablesink
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	tablesinkmetrics "github.com/pingcap/tiflow/cdc/sink/metrics/tablesink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// BufferQuota is the quota of the events buffered by the table sinks of a
+// changefeed before they're resolved. It's shared by the table sinks and
+// all its methods are thread-safe.
+type BufferQuota struct {
+	changefeedID model.ChangeFeedID
+	// limit is the max size of the buffered events, 0 means unlimited.
+	limit  uint64
+	policy string
+	used   atomic.Uint64
+	// splitTxn is false if the transactions are written atomically, the
+	// buffered events are neither spilled nor block the tables then, since
+	// a partial transaction is only resolved after more events are read.
+	splitTxn bool
+	// diskLimit is the max size of the events spilled to the disk if the
+	// policy is disk, 0 means unlimited.
+	diskLimit uint64
+	diskUsed  atomic.Uint64
+	// newSpillStore creates the store of the spilled events of a table sink,
+	// it's nil if the events can't be spilled to the disk.
+	newSpillStore func(span tablepb.Span) SpillStore
+
+	metricBufferedBytes prometheus.Gauge
+	metricSpilledBytes  prometheus.Gauge
+	metricSpilledRows   prometheus.Counter
+}
+
+// NewBufferQuota creates a BufferQuota by the config, the quota is unlimited
+// if the config is nil. The quota only accounts the buffered events if the
+// transactions can't be split.
+func NewBufferQuota(
+	changefeedID model.ChangeFeedID, cfg *config.TableSinkBufferConfig, splitTxn bool,
+) *BufferQuota {
+	q := &BufferQuota{
+		changefeedID: changefeedID,
+		policy:       config.TableSinkBufferPolicyBlock,
+		splitTxn:     splitTxn,
+		metricBufferedBytes: tablesinkmetrics.BufferedBytesGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricSpilledBytes: tablesinkmetrics.SpilledBytesGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricSpilledRows: tablesinkmetrics.SpilledRowsCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+	if cfg != nil {
+		q.limit = util.GetOrZero(cfg.Quota)
+		q.diskLimit = util.GetOrZero(cfg.DiskQuota)
+		if cfg.Policy != nil {
+			q.policy = *cfg.Policy
+		}
+	}
+	if q.limit != 0 && !splitTxn && q.policy != config.TableSinkBufferPolicyDisk {
+		log.Warn("The table sink buffer quota neither spills nor blocks "+
+			"since the transactions can't be split",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("policy", q.policy))
+	}
+	return q
+}
+
+// Exceeded returns true if the size of the buffered events exceeds the quota.
+func (q *BufferQuota) Exceeded() bool {
+	if q == nil || q.limit == 0 {
+		return false
+	}
+	return q.used.Load() > q.limit
+}
+
+// SetSpillStoreCreator sets the function to create the store of the events
+// spilled to the disk by a table sink, which is required by the disk policy.
+func (q *BufferQuota) SetSpillStoreCreator(fn func(span tablepb.Span) SpillStore) {
+	q.newSpillStore = fn
+}
+
+// Blocking returns true if the tables should stop reading events because
+// the quota is exceeded.
+func (q *BufferQuota) Blocking() bool {
+	if !q.Exceeded() || !q.splitTxn {
+		return false
+	}
+	switch q.policy {
+	case config.TableSinkBufferPolicyBlock:
+		return true
+	case config.TableSinkBufferPolicyDisk:
+		// Fall back to the block policy if the events can't be spilled.
+		return q.newSpillStore == nil || q.diskExceeded()
+	}
+	return false
+}
+
+// spilling returns true if the buffered events should be written before
+// they're resolved because the quota is exceeded.
+func (q *BufferQuota) spilling() bool {
+	return q.Exceeded() && q.splitTxn && q.policy == config.TableSinkBufferPolicySpill
+}
+
+// spillingToDisk returns true if the buffered events should be moved to the
+// disk because the quota is exceeded.
+func (q *BufferQuota) spillingToDisk() bool {
+	return q.Exceeded() && q.policy == config.TableSinkBufferPolicyDisk &&
+		q.newSpillStore != nil && !q.diskExceeded()
+}
+
+func (q *BufferQuota) diskExceeded() bool {
+	return q.diskLimit != 0 && q.diskUsed.Load() >= q.diskLimit
+}
+
+// Used returns the size of the buffered events.
+func (q *BufferQuota) Used() uint64 {
+	if q == nil {
+		return 0
+	}
+	return q.used.Load()
+}
+
+func (q *BufferQuota) acquire(size uint64) {
+	if q == nil {
+		return
+	}
+	q.metricBufferedBytes.Set(float64(q.used.Add(size)))
+}
+
+func (q *BufferQuota) release(size uint64) {
+	if q == nil {
+		return
+	}
+	q.metricBufferedBytes.Set(float64(q.used.Add(^(size - 1))))
+}
+
+// DiskUsed returns the size of the events spilled to the disk.
+func (q *BufferQuota) DiskUsed() uint64 {
+	if q == nil {
+		return 0
+	}
+	return q.diskUsed.Load()
+}
+
+// createSpillStore creates the store of the spilled events of a table sink,
+// it returns nil if the events aren't spilled to the disk.
+func (q *BufferQuota) createSpillStore(span tablepb.Span) SpillStore {
+	if q == nil || q.policy != config.TableSinkBufferPolicyDisk || q.newSpillStore == nil {
+		return nil
+	}
+	return q.newSpillStore(span)
+}
+
+func (q *BufferQuota) acquireDisk(size uint64, rows int) {
+	if q == nil {
+		return
+	}
+	q.metricSpilledBytes.Set(float64(q.diskUsed.Add(size)))
+	q.metricSpilledRows.Add(float64(rows))
+}
+
+func (q *BufferQuota) releaseDisk(size uint64) {
+	if q == nil {
+		return
+	}
+	q.metricSpilledBytes.Set(float64(q.diskUsed.Add(^(size - 1))))
+}
+
+// Close releases the metrics of the quota.
+func (q *BufferQuota) Close() {
+	if q == nil {
+		return
+	}
+	tablesinkmetrics.BufferedBytesGauge.
+		DeleteLabelValues(q.changefeedID.Namespace, q.changefeedID.ID)
+	tablesinkmetrics.SpilledBytesGauge.
+		DeleteLabelValues(q.changefeedID.Namespace, q.changefeedID.ID)
+	tablesinkmetrics.SpilledRowsCounter.
+		DeleteLabelValues(q.changefeedID.Namespace, q.changefeedID.ID)
+}
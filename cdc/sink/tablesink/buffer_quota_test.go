@@ -0,0 +1,100 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tablesink
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferQuota(t *testing.T) {
+	t.Parallel()
+
+	var q *BufferQuota
+	require.False(t, q.Exceeded(), "nil quota is unlimited")
+	require.False(t, q.Blocking())
+	q.acquire(100)
+	q.release(100)
+
+	q = NewBufferQuota(model.DefaultChangeFeedID("unlimited"), nil, true)
+	defer q.Close()
+	q.acquire(1 << 30)
+	require.False(t, q.Exceeded(), "quota is unlimited if it's not set")
+
+	q = NewBufferQuota(model.DefaultChangeFeedID("block"),
+		&config.TableSinkBufferConfig{Quota: util.AddressOf(uint64(100))}, true)
+	defer q.Close()
+	q.acquire(100)
+	require.False(t, q.Exceeded())
+	q.acquire(1)
+	require.True(t, q.Exceeded())
+	require.True(t, q.Blocking(), "block is the default policy")
+	require.False(t, q.spilling())
+	q.release(50)
+	require.Equal(t, uint64(51), q.Used())
+	require.False(t, q.Blocking())
+
+	q = NewBufferQuota(model.DefaultChangeFeedID("spill"),
+		&config.TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(100)),
+			Policy: util.AddressOf(config.TableSinkBufferPolicySpill),
+		}, true)
+	defer q.Close()
+	q.acquire(101)
+	require.False(t, q.Blocking())
+	require.True(t, q.spilling())
+
+	q = NewBufferQuota(model.DefaultChangeFeedID("disk"),
+		&config.TableSinkBufferConfig{
+			Quota:     util.AddressOf(uint64(100)),
+			Policy:    util.AddressOf(config.TableSinkBufferPolicyDisk),
+			DiskQuota: util.AddressOf(uint64(100)),
+		}, true)
+	defer q.Close()
+	q.acquire(101)
+	require.True(t, q.Blocking(), "block if the events can't be spilled")
+	require.False(t, q.spillingToDisk())
+	require.Nil(t, q.createSpillStore(tablepb.Span{}))
+	q.SetSpillStoreCreator(func(tablepb.Span) SpillStore { return nil })
+	require.False(t, q.Blocking())
+	require.True(t, q.spillingToDisk())
+	q.acquireDisk(100, 1)
+	require.True(t, q.Blocking(), "block if the disk quota is exceeded")
+	require.False(t, q.spillingToDisk())
+	q.releaseDisk(1)
+	require.Equal(t, uint64(99), q.DiskUsed())
+	require.False(t, q.Blocking())
+
+	// The quota only accounts the events if the transactions can't be split.
+	for _, policy := range []string{
+		config.TableSinkBufferPolicyBlock, config.TableSinkBufferPolicySpill,
+		config.TableSinkBufferPolicyDisk,
+	} {
+		q = NewBufferQuota(model.DefaultChangeFeedID("atomic"),
+			&config.TableSinkBufferConfig{
+				Quota:  util.AddressOf(uint64(100)),
+				Policy: util.AddressOf(policy),
+			}, false)
+		q.acquire(101)
+		require.True(t, q.Exceeded())
+		require.False(t, q.Blocking(), policy)
+		require.False(t, q.spilling(), policy)
+		q.Close()
+	}
+}
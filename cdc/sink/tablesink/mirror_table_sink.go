@@ -0,0 +1,157 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tablesink
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// Assert TableSink implementation
+var _ TableSink = (*MirrorTableSink)(nil)
+
+// MirrorTableSink writes the events to the table sinks of both the sink-uri and
+// the mirror of the changefeed. They flush the events independently, and the
+// checkpoint is the one of the sink-uri, so a slow or failed mirror never holds
+// the changefeed back. The checkpoint of the mirror is tracked separately, the
+// changefeed can be cut over to the mirror once it catches up.
+type MirrorTableSink struct {
+	primary TableSink
+	mirror  TableSink
+	// mirrorFailed is true once the mirror fails, the events aren't written
+	// to it anymore and it doesn't backpressure the table.
+	mirrorFailed atomic.Bool
+}
+
+// NewMirror creates a MirrorTableSink.
+func NewMirror(primary, mirror TableSink) *MirrorTableSink {
+	return &MirrorTableSink{primary: primary, mirror: mirror}
+}
+
+// AppendRowChangedEvents appends the events to both table sinks. The rows are
+// copied for the mirror, because the sinks may modify the column values.
+func (m *MirrorTableSink) AppendRowChangedEvents(rows ...*model.RowChangedEvent) {
+	m.primary.AppendRowChangedEvents(rows...)
+	if m.mirrorFailed.Load() {
+		return
+	}
+	copied := make([]*model.RowChangedEvent, len(rows))
+	for i, row := range rows {
+		copied[i] = copyRow(row)
+	}
+	m.mirror.AppendRowChangedEvents(copied...)
+}
+
+func copyRow(row *model.RowChangedEvent) *model.RowChangedEvent {
+	copied := *row
+	copied.Columns = copyColumns(row.Columns)
+	copied.PreColumns = copyColumns(row.PreColumns)
+	return &copied
+}
+
+func copyColumns(cols []*model.Column) []*model.Column {
+	if cols == nil {
+		return nil
+	}
+	copied := make([]*model.Column, len(cols))
+	for i, col := range cols {
+		if col != nil {
+			c := *col
+			copied[i] = &c
+		}
+	}
+	return copied
+}
+
+// UpdateResolvedTs writes the resolved events of both table sinks. The error
+// of the mirror isn't returned, the mirror is given up instead, and the error
+// is reported as a warning by the sink manager.
+func (m *MirrorTableSink) UpdateResolvedTs(resolvedTs model.ResolvedTs) error {
+	if err := m.primary.UpdateResolvedTs(resolvedTs); err != nil {
+		return errors.Trace(err)
+	}
+	if m.mirrorFailed.Load() {
+		return nil
+	}
+	if err := m.mirror.UpdateResolvedTs(resolvedTs); err != nil {
+		log.Warn("The mirror of the table sink fails, events aren't written to it anymore",
+			zap.Any("resolvedTs", resolvedTs), zap.Error(err))
+		m.mirrorFailed.Store(true)
+	}
+	return nil
+}
+
+// GetCheckpointTs returns the checkpoint of the table sink of the sink-uri.
+func (m *MirrorTableSink) GetCheckpointTs() model.ResolvedTs {
+	return m.primary.GetCheckpointTs()
+}
+
+// GetMirrorCheckpointTs returns the checkpoint of the table sink of the mirror,
+// it stops advancing once the mirror fails.
+func (m *MirrorTableSink) GetMirrorCheckpointTs() model.ResolvedTs {
+	return m.mirror.GetCheckpointTs()
+}
+
+// IsMirrorFailed returns true if the mirror has failed, the events after its
+// checkpoint are never written to it.
+func (m *MirrorTableSink) IsMirrorFailed() bool {
+	return m.mirrorFailed.Load()
+}
+
+// GetBackpressureScore returns the larger backpressure score of the table sinks,
+// so the events buffered by a slow mirror are bounded too.
+func (m *MirrorTableSink) GetBackpressureScore() float64 {
+	score := m.primary.GetBackpressureScore()
+	if m.mirrorFailed.Load() {
+		return score
+	}
+	if mirror := m.mirror.GetBackpressureScore(); mirror > score {
+		return mirror
+	}
+	return score
+}
+
+// GetStats returns the statistic of the slower table sink.
+func (m *MirrorTableSink) GetStats() Stats {
+	stats := m.primary.GetStats()
+	if m.mirrorFailed.Load() {
+		return stats
+	}
+	mirror := m.mirror.GetStats()
+	if mirror.PendingEvents > stats.PendingEvents {
+		stats.PendingEvents = mirror.PendingEvents
+	}
+	if mirror.FlushLatency > stats.FlushLatency {
+		stats.FlushLatency = mirror.FlushLatency
+	}
+	return stats
+}
+
+// Close closes both table sinks.
+func (m *MirrorTableSink) Close() {
+	m.primary.Close()
+	m.mirror.Close()
+}
+
+// AsyncClose closes both table sinks asynchronously. Returns true if both of
+// them are closed.
+func (m *MirrorTableSink) AsyncClose() bool {
+	primaryClosed := m.primary.AsyncClose()
+	mirrorClosed := m.mirror.AsyncClose()
+	return primaryClosed && mirrorClosed
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tablesink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorTableSink(t *testing.T) {
+	t.Parallel()
+
+	newTableSink := func(sink *mockEventSink) TableSink {
+		return New[*model.SingleTableTxn](
+			model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+			sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+	}
+	primarySink := &mockEventSink{dead: make(chan struct{})}
+	mirrorSink := &mockEventSink{dead: make(chan struct{})}
+	tb := NewMirror(newTableSink(primarySink), newTableSink(mirrorSink))
+
+	rows := getTestRows()
+	rows[0].Columns = []*model.Column{{Name: "a", Value: []byte("1")}}
+	tb.AppendRowChangedEvents(rows...)
+	// the checkpoint only advances to the resolved ts of the flushed events.
+	require.NoError(t, tb.UpdateResolvedTs(model.NewResolvedTs(102)))
+	require.NoError(t, tb.UpdateResolvedTs(model.NewResolvedTs(105)))
+	require.Len(t, primarySink.events, 7)
+	require.Len(t, mirrorSink.events, 7)
+
+	// the mirror receives the copies of the rows.
+	primaryRow := primarySink.events[0].Event.Rows[0]
+	mirrorRow := mirrorSink.events[0].Event.Rows[0]
+	require.Same(t, rows[0], primaryRow)
+	require.NotSame(t, primaryRow, mirrorRow)
+	require.NotSame(t, primaryRow.Columns[0], mirrorRow.Columns[0])
+	require.Equal(t, primaryRow.Columns, mirrorRow.Columns)
+
+	// the checkpoints of the table sinks advance independently, the slow
+	// mirror doesn't hold the checkpoint back.
+	primarySink.acknowledge(105)
+	mirrorSink.acknowledge(102)
+	require.Equal(t, model.NewResolvedTs(105), tb.GetCheckpointTs())
+	require.Equal(t, model.NewResolvedTs(102), tb.GetMirrorCheckpointTs())
+	require.Equal(t, uint64(4), tb.GetStats().PendingEvents)
+
+	mirrorSink.acknowledge(105)
+	require.Equal(t, model.NewResolvedTs(105), tb.GetMirrorCheckpointTs())
+
+	tb.Close()
+	require.True(t, tb.AsyncClose())
+}
+
+func TestMirrorTableSinkMirrorFails(t *testing.T) {
+	t.Parallel()
+
+	newTableSink := func(sink *mockEventSink) TableSink {
+		return New[*model.SingleTableTxn](
+			model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+			sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+	}
+	primarySink := &mockEventSink{dead: make(chan struct{})}
+	mirrorSink := &mockEventSink{dead: make(chan struct{}), err: errors.New("mirror fails")}
+	tb := NewMirror(newTableSink(primarySink), newTableSink(mirrorSink))
+
+	// the error of the mirror isn't returned, and the mirror is given up.
+	tb.AppendRowChangedEvents(getTestRows()...)
+	require.NoError(t, tb.UpdateResolvedTs(model.NewResolvedTs(105)))
+	require.True(t, tb.mirrorFailed.Load())
+	require.Len(t, primarySink.events, 7)
+	require.Empty(t, mirrorSink.events)
+
+	// the primary keeps advancing while the mirror stays behind.
+	tb.AppendRowChangedEvents(getTestRows()...)
+	require.NoError(t, tb.UpdateResolvedTs(model.NewResolvedTs(106)))
+	primarySink.acknowledge(106)
+	require.Equal(t, model.NewResolvedTs(106), tb.GetCheckpointTs())
+	require.Equal(t, model.NewResolvedTs(0), tb.GetMirrorCheckpointTs())
+	require.Zero(t, tb.GetStats().PendingEvents)
+	require.Zero(t, tb.GetBackpressureScore())
+
+	close(mirrorSink.dead)
+	tb.Close()
+	require.True(t, tb.AsyncClose())
+}
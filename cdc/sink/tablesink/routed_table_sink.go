@@ -0,0 +1,54 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tablesink
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/util"
+)
+
+// Assert TableSink implementation
+var _ TableSink = (*RoutedTableSink)(nil)
+
+// RoutedTableSink renames the tables and the columns of the rows by the table
+// routes of the changefeed before they're appended to the table sink. The
+// other methods are delegated to the table sink.
+type RoutedTableSink struct {
+	TableSink
+	router *util.TableRouter
+
+	// lastTableInfo and routedTableInfo cache the routed table info of the
+	// rows, because the rows of a table share the table info until a DDL.
+	lastTableInfo   *model.TableInfo
+	routedTableInfo *model.TableInfo
+}
+
+// NewRouted creates a RoutedTableSink.
+func NewRouted(tableSink TableSink, router *util.TableRouter) *RoutedTableSink {
+	return &RoutedTableSink{TableSink: tableSink, router: router}
+}
+
+// AppendRowChangedEvents appends the routed copies of the rows to the table
+// sink, the rows themselves aren't modified.
+func (r *RoutedTableSink) AppendRowChangedEvents(rows ...*model.RowChangedEvent) {
+	routed := make([]*model.RowChangedEvent, len(rows))
+	for i, row := range rows {
+		if row.TableInfo != r.lastTableInfo {
+			r.lastTableInfo = row.TableInfo
+			r.routedTableInfo = r.router.RouteTableInfo(row.TableInfo)
+		}
+		routed[i] = r.router.RouteRow(row, r.routedTableInfo)
+	}
+	r.TableSink.AppendRowChangedEvents(routed...)
+}
@@ -14,7 +14,10 @@
 package tablesink
 
 import (
+	"time"
+
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errorutil"
 )
 
 // TableSink is the interface for table sink.
@@ -32,6 +35,14 @@ type TableSink interface {
 	// For example, calculating the current progress from the statistics of the table sink.
 	// This is a thread-safe method.
 	GetCheckpointTs() model.ResolvedTs
+	// GetBackpressureScore returns the backpressure of the table sink in [0, 1],
+	// which is calculated from the pending events and the latency of writing
+	// events to the backend sink. 1 means the table sink is fully backpressured.
+	// This is a thread-safe method.
+	GetBackpressureScore() float64
+	// GetStats returns the statistic of the events written to the backend sink.
+	// This is a thread-safe method.
+	GetStats() Stats
 	// Close closes the table sink.
 	// After it returns, no more events will be sent out from this capture.
 	Close()
@@ -39,9 +50,33 @@ type TableSink interface {
 	AsyncClose() bool
 }
 
+// Stats is the statistic of the events written to the backend sink.
+type Stats struct {
+	// PendingEvents is the number of events which are written to the backend
+	// sink but not flushed yet.
+	PendingEvents uint64
+	// FlushLatency is the moving average of the latency of writing events
+	// to the backend sink.
+	FlushLatency time.Duration
+}
+
+// SpillStore stores the events spilled from the memory by a table sink if
+// the buffer quota is exceeded.
+type SpillStore interface {
+	// Put adds the value of an event with the given commitTs. The values are
+	// ordered by commitTs and then the order they're put.
+	Put(commitTs model.Ts, value []byte) error
+	// Take removes and returns the values whose commitTs is less than or equal
+	// to the upperBound.
+	Take(upperBound model.Ts) ([][]byte, error)
+	// Clean removes all values of the store, it can't be used after cleaned.
+	Clean() error
+}
+
 // SinkInternalError means the error comes from sink internal.
 type SinkInternalError struct {
-	err error
+	err   error
+	class errorutil.SinkErrorClass
 }
 
 // Error implements builtin `error` interface.
@@ -49,7 +84,23 @@ func (e SinkInternalError) Error() string {
 	return e.err.Error()
 }
 
-// NewSinkInternalError creates a SinkInternalError.
+// Unwrap returns the underlying error.
+func (e SinkInternalError) Unwrap() error {
+	return e.err
+}
+
+// Class returns the class of the error.
+func (e SinkInternalError) Class() errorutil.SinkErrorClass {
+	return e.class
+}
+
+// Retryable returns whether the error can be retried by restarting the sink.
+func (e SinkInternalError) Retryable() bool {
+	return e.class.Retryable()
+}
+
+// NewSinkInternalError creates a SinkInternalError, the class is decided by
+// the underlying error.
 func NewSinkInternalError(err error) SinkInternalError {
-	return SinkInternalError{err}
+	return SinkInternalError{err: err, class: errorutil.ClassifySinkError(err)}
 }
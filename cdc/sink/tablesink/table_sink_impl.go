@@ -14,17 +14,36 @@
 package tablesink
 
 import (
+	"context"
 	"sort"
+	"sync/atomic"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/model/codec"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/tracing"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
+const (
+	// backpressurePendingEvents is the number of the pending events which makes
+	// the table sink fully backpressured.
+	backpressurePendingEvents = 64 * 1024
+	// backpressureWriteLatency is the latency of writing events to the backend
+	// sink which makes the table sink fully backpressured.
+	backpressureWriteLatency = time.Second
+	// writeLatencySmoothing is the weight of the latest latency in the moving
+	// average of the write latency.
+	writeLatencySmoothing = 0.2
+)
+
 // Assert TableSink implementation
 var (
 	_ TableSink = (*EventTableSink[*model.RowChangedEvent, *dmlsink.RowChangeEventAppender])(nil)
@@ -45,18 +64,52 @@ type EventTableSink[E dmlsink.TableEvent, P dmlsink.Appender[E]] struct {
 	// NOTICE: It is ordered by commitTs.
 	eventBuffer []E
 	state       state.TableSinkState
+	// writeLatency is the moving average of the latency of writing events
+	// to the backend sink, in nanoseconds.
+	writeLatency atomic.Int64
+
+	// bufferQuota is shared by the table sinks of the changefeed, it's nil if
+	// the buffered events aren't accounted.
+	bufferQuota *BufferQuota
+	// bufferedSizes is the size of the buffered events, ordered by commitTs.
+	bufferedSizes []bufferedSize
+	bufferedBytes atomic.Uint64
+	// spillErr is the error of writing the spilled events, it's returned by
+	// the next UpdateResolvedTs.
+	spillErr error
+	// spillStore stores the values of the rows spilled to the disk, it's nil
+	// if the buffered events are never spilled to the disk.
+	spillStore SpillStore
+	// spilledRows are the buffered rows whose values are spilled to the disk,
+	// ordered by commitTs. The values are restored before they're written.
+	spilledRows  []spilledRow
+	spilledBytes atomic.Uint64
 
 	// For dataflow metrics.
 	metricsTableSinkTotalRows prometheus.Counter
 }
 
+// bufferedSize is the size of the buffered events with the same commitTs.
+type bufferedSize struct {
+	commitTs model.Ts
+	size     uint64
+}
+
+// spilledRow is a buffered row whose values are spilled to the disk.
+type spilledRow struct {
+	row  *model.RowChangedEvent
+	size uint64
+}
+
 // New an eventTableSink with given backendSink and event appender.
+// The buffered events are accounted by bufferQuota if it's not nil.
 func New[E dmlsink.TableEvent, P dmlsink.Appender[E]](
 	changefeedID model.ChangeFeedID,
 	span tablepb.Span,
 	startTs model.Ts,
 	backendSink dmlsink.EventSink[E],
 	appender P,
+	bufferQuota *BufferQuota,
 	totalRowsCounter prometheus.Counter,
 ) *EventTableSink[E, P] {
 	return &EventTableSink[E, P]{
@@ -69,24 +122,45 @@ func New[E dmlsink.TableEvent, P dmlsink.Appender[E]](
 		eventAppender:             appender,
 		eventBuffer:               make([]E, 0, 1024),
 		state:                     state.TableSinkSinking,
+		bufferQuota:               bufferQuota,
+		spillStore:                bufferQuota.createSpillStore(span),
 		metricsTableSinkTotalRows: totalRowsCounter,
 	}
 }
 
 // AppendRowChangedEvents appends row changed or txn events to the table sink.
 func (e *EventTableSink[E, P]) AppendRowChangedEvents(rows ...*model.RowChangedEvent) {
-	e.eventBuffer = e.eventAppender.Append(e.eventBuffer, rows...)
 	e.metricsTableSinkTotalRows.Add(float64(len(rows)))
+	// Once some rows are spilled, the following rows are spilled too until
+	// they're resolved, so the spilled rows are always the latest ones.
+	if e.spillStore != nil && (len(e.spilledRows) > 0 || e.bufferQuota.spillingToDisk()) {
+		rows = e.spillToDisk(rows)
+	} else {
+		e.acquireBuffer(rows)
+	}
+	e.eventBuffer = e.eventAppender.Append(e.eventBuffer, rows...)
+	if e.bufferQuota.spilling() {
+		e.spill()
+	}
 }
 
 // UpdateResolvedTs advances the resolved ts of the table sink.
 func (e *EventTableSink[E, P]) UpdateResolvedTs(resolvedTs model.ResolvedTs) error {
+	if e.spillErr != nil {
+		err := e.spillErr
+		e.spillErr = nil
+		return err
+	}
 	// If resolvedTs is not greater than maxResolvedTs,
 	// the flush is unnecessary.
 	if e.maxResolvedTs.EqualOrGreater(resolvedTs) {
 		return nil
 	}
 	e.maxResolvedTs = resolvedTs
+	e.releaseBuffer(resolvedTs.Ts)
+	if err := e.restoreSpilled(resolvedTs.Ts); err != nil {
+		return NewSinkInternalError(err)
+	}
 
 	i := sort.Search(len(e.eventBuffer), func(i int) bool {
 		return e.eventBuffer[i].GetCommitTs() > resolvedTs.Ts
@@ -98,7 +172,7 @@ func (e *EventTableSink[E, P]) UpdateResolvedTs(resolvedTs model.ResolvedTs) err
 		// and re-initialized, we can know it and re-build a table sink.
 		e.progressTracker.addResolvedTs(resolvedTs)
 		if err := e.backendSink.WriteEvents(); err != nil {
-			return SinkInternalError{err}
+			return NewSinkInternalError(err)
 		}
 		return nil
 	}
@@ -121,12 +195,194 @@ func (e *EventTableSink[E, P]) UpdateResolvedTs(resolvedTs model.ResolvedTs) err
 
 	// Do not forget to add the resolvedTs to progressTracker.
 	e.progressTracker.addResolvedTs(resolvedTs)
-	if err := e.backendSink.WriteEvents(resolvedCallbackableEvents...); err != nil {
-		return SinkInternalError{err}
+	_, span := tracing.StartSpan(context.Background(), "tablesink.flush", e.changefeedID,
+		attribute.Int64("tableID", e.span.TableID),
+		attribute.Int("events", len(resolvedCallbackableEvents)))
+	start := time.Now()
+	err := e.backendSink.WriteEvents(resolvedCallbackableEvents...)
+	e.observeWriteLatency(time.Since(start))
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return NewSinkInternalError(err)
+	}
+	return nil
+}
+
+// spill writes all buffered events to the backend sink before they're resolved,
+// so the transaction of the last commitTs is split by a batch resolved ts. It's
+// never called if the transactions can't be split, see BufferQuota.spilling.
+func (e *EventTableSink[E, P]) spill() {
+	if len(e.eventBuffer) == 0 || e.spillErr != nil {
+		return
+	}
+	commitTs := e.eventBuffer[len(e.eventBuffer)-1].GetCommitTs()
+	resolvedTs := model.ResolvedTs{Mode: model.BatchResolvedMode, Ts: commitTs}
+	if e.maxResolvedTs.Ts == commitTs && e.maxResolvedTs.IsBatchMode() {
+		resolvedTs = e.maxResolvedTs.AdvanceBatch()
+	}
+	log.Debug("Spill the buffered events of table sink",
+		zap.String("namespace", e.changefeedID.Namespace),
+		zap.String("changefeed", e.changefeedID.ID),
+		zap.Stringer("span", &e.span),
+		zap.Int("events", len(e.eventBuffer)),
+		zap.Any("resolvedTs", resolvedTs))
+	e.spillErr = e.UpdateResolvedTs(resolvedTs)
+}
+
+// spillToDisk moves the values of the rows to the spill store, and returns the
+// rows to buffer, whose columns are copied without values if they're spilled.
+// The rows are buffered with values if they fail to be spilled.
+func (e *EventTableSink[E, P]) spillToDisk(rows []*model.RowChangedEvent) []*model.RowChangedEvent {
+	buffered := make([]*model.RowChangedEvent, 0, len(rows))
+	total, count := uint64(0), 0
+	for i, row := range rows {
+		if e.spillErr == nil {
+			value, err := codec.MarshalRowAsRedoLog(row, nil)
+			if err == nil {
+				err = e.spillStore.Put(row.CommitTs, value)
+			}
+			if err == nil {
+				stripped := stripValues(row)
+				e.spilledRows = append(e.spilledRows,
+					spilledRow{row: stripped, size: uint64(len(value))})
+				buffered = append(buffered, stripped)
+				total += uint64(len(value))
+				count++
+				continue
+			}
+			log.Warn("Failed to spill the buffered events of table sink",
+				zap.String("namespace", e.changefeedID.Namespace),
+				zap.String("changefeed", e.changefeedID.ID),
+				zap.Stringer("span", &e.span),
+				zap.Error(err))
+			e.spillErr = NewSinkInternalError(errors.Trace(err))
+		}
+		e.acquireBuffer(rows[i : i+1])
+		buffered = append(buffered, row)
 	}
+	e.spilledBytes.Add(total)
+	e.bufferQuota.acquireDisk(total, count)
+	return buffered
+}
+
+// restoreSpilled restores the values of the spilled rows whose commitTs is less
+// than or equal to the given commitTs, since they're going to be written.
+func (e *EventTableSink[E, P]) restoreSpilled(commitTs model.Ts) error {
+	i := sort.Search(len(e.spilledRows), func(i int) bool {
+		return e.spilledRows[i].row.CommitTs > commitTs
+	})
+	if i == 0 {
+		return nil
+	}
+	values, err := e.spillStore.Take(commitTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(values) != i {
+		return errors.Errorf("expect %d spilled rows, but got %d", i, len(values))
+	}
+	size := uint64(0)
+	for j, value := range values {
+		redoLog, _, err := codec.UnmarshalRedoLog(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		row := e.spilledRows[j].row
+		restoreValues(row.Columns, redoLog.RedoRow.Row.Columns)
+		restoreValues(row.PreColumns, redoLog.RedoRow.Row.PreColumns)
+		size += e.spilledRows[j].size
+	}
+	e.spilledRows = append(make([]spilledRow, 0, len(e.spilledRows[i:])), e.spilledRows[i:]...)
+	e.bufferQuota.releaseDisk(releaseBytes(&e.spilledBytes, size))
 	return nil
 }
 
+// stripValues returns a copy of the row whose columns are copied without values.
+func stripValues(row *model.RowChangedEvent) *model.RowChangedEvent {
+	stripped := *row
+	stripped.Columns = stripColumnValues(row.Columns)
+	stripped.PreColumns = stripColumnValues(row.PreColumns)
+	return &stripped
+}
+
+func stripColumnValues(cols []*model.Column) []*model.Column {
+	if cols == nil {
+		return nil
+	}
+	stripped := make([]*model.Column, len(cols))
+	for i, col := range cols {
+		if col != nil {
+			c := *col
+			c.Value = nil
+			stripped[i] = &c
+		}
+	}
+	return stripped
+}
+
+func restoreValues(cols, restored []*model.Column) {
+	for i, col := range cols {
+		if col != nil && i < len(restored) && restored[i] != nil {
+			col.Value = restored[i].Value
+		}
+	}
+}
+
+// acquireBuffer accounts the size of the appended rows.
+func (e *EventTableSink[E, P]) acquireBuffer(rows []*model.RowChangedEvent) {
+	if e.bufferQuota == nil {
+		return
+	}
+	total := uint64(0)
+	for _, row := range rows {
+		size := uint64(row.ApproximateBytes())
+		total += size
+		n := len(e.bufferedSizes)
+		if n > 0 && e.bufferedSizes[n-1].commitTs == row.CommitTs {
+			e.bufferedSizes[n-1].size += size
+		} else {
+			e.bufferedSizes = append(e.bufferedSizes,
+				bufferedSize{commitTs: row.CommitTs, size: size})
+		}
+	}
+	e.bufferedBytes.Add(total)
+	e.bufferQuota.acquire(total)
+}
+
+// releaseBuffer releases the size of the events whose commitTs is less than
+// or equal to the given commitTs, since they're written to the backend sink.
+func (e *EventTableSink[E, P]) releaseBuffer(commitTs model.Ts) {
+	if e.bufferQuota == nil {
+		return
+	}
+	i := sort.Search(len(e.bufferedSizes), func(i int) bool {
+		return e.bufferedSizes[i].commitTs > commitTs
+	})
+	if i == 0 {
+		return
+	}
+	size := uint64(0)
+	for _, s := range e.bufferedSizes[:i] {
+		size += s.size
+	}
+	e.bufferedSizes = append(make([]bufferedSize, 0, len(e.bufferedSizes[i:])), e.bufferedSizes[i:]...)
+	e.bufferQuota.release(releaseBytes(&e.bufferedBytes, size))
+}
+
+// releaseBytes subtracts the size from the bytes and returns the released size,
+// which is capped because the bytes may be released by markAsClosed concurrently.
+func releaseBytes(bytes *atomic.Uint64, size uint64) uint64 {
+	for {
+		used := bytes.Load()
+		if size > used {
+			size = used
+		}
+		if bytes.CompareAndSwap(used, used-size) {
+			return size
+		}
+	}
+}
+
 // GetCheckpointTs returns the checkpoint ts of the table sink.
 func (e *EventTableSink[E, P]) GetCheckpointTs() model.ResolvedTs {
 	if e.state.Load() == state.TableSinkStopping {
@@ -137,6 +393,52 @@ func (e *EventTableSink[E, P]) GetCheckpointTs() model.ResolvedTs {
 	return e.progressTracker.advance()
 }
 
+// GetBackpressureScore returns the backpressure score of the table sink.
+// The score is 0 if there is no pending event, otherwise it's the larger one
+// of the pending events and the write latency relative to their limits.
+func (e *EventTableSink[E, P]) GetBackpressureScore() float64 {
+	// advance the tracker to drop the flushed events from the pending ones.
+	e.progressTracker.advance()
+	pending := e.progressTracker.trackingCount()
+	if pending == 0 {
+		// the write latency is stale if all events are flushed, so the table
+		// sink isn't backpressured until new events are written.
+		return 0
+	}
+	pendingScore := float64(pending) / backpressurePendingEvents
+	latencyScore := float64(e.writeLatency.Load()) / float64(backpressureWriteLatency)
+	score := pendingScore
+	if latencyScore > score {
+		score = latencyScore
+	}
+	if score > 1 {
+		score = 1
+	}
+	return score
+}
+
+// GetStats returns the pending events and the write latency of the table sink.
+func (e *EventTableSink[E, P]) GetStats() Stats {
+	// advance the tracker to drop the flushed events from the pending ones.
+	e.progressTracker.advance()
+	return Stats{
+		PendingEvents: uint64(e.progressTracker.trackingCount()),
+		FlushLatency:  time.Duration(e.writeLatency.Load()),
+	}
+}
+
+// observeWriteLatency updates the moving average of the write latency.
+// It's only called in UpdateResolvedTs, which is not called concurrently.
+func (e *EventTableSink[E, P]) observeWriteLatency(latency time.Duration) {
+	last := e.writeLatency.Load()
+	if last == 0 {
+		e.writeLatency.Store(int64(latency))
+		return
+	}
+	e.writeLatency.Store(int64(
+		writeLatencySmoothing*float64(latency) + (1-writeLatencySmoothing)*float64(last)))
+}
+
 // Close closes the table sink.
 // After it returns, no more events will be sent out from this capture.
 func (e *EventTableSink[E, P]) Close() {
@@ -187,6 +489,18 @@ func (e *EventTableSink[E, P]) markAsClosed() (modified bool) {
 			return
 		}
 		if e.state.CompareAndSwap(currentState, state.TableSinkStopped) {
+			// The buffered events are never written after the table sink is stopped.
+			e.bufferQuota.release(e.bufferedBytes.Swap(0))
+			e.bufferQuota.releaseDisk(e.spilledBytes.Swap(0))
+			if e.spillStore != nil {
+				if err := e.spillStore.Clean(); err != nil {
+					log.Warn("Failed to clean the spilled events of table sink",
+						zap.String("namespace", e.changefeedID.Namespace),
+						zap.String("changefeed", e.changefeedID.ID),
+						zap.Stringer("span", &e.span),
+						zap.Error(err))
+				}
+			}
 			stoppedCheckpointTs := e.GetCheckpointTs()
 			log.Info("Table sink stopped",
 				zap.String("namespace", e.changefeedID.Namespace),
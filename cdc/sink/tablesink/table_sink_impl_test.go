@@ -19,10 +19,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/errorutil"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
@@ -33,9 +39,13 @@ var _ dmlsink.EventSink[*model.SingleTableTxn] = (*mockEventSink)(nil)
 type mockEventSink struct {
 	dead   chan struct{}
 	events []*dmlsink.TxnCallbackableEvent
+	err    error
 }
 
 func (m *mockEventSink) WriteEvents(rows ...*dmlsink.TxnCallbackableEvent) error {
+	if m.err != nil {
+		return m.err
+	}
 	m.events = append(m.events, rows...)
 	return nil
 }
@@ -160,7 +170,7 @@ func TestNewEventTableSink(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	require.Equal(t, model.NewResolvedTs(0), tb.maxResolvedTs, "maxResolvedTs should start from 0")
 	require.NotNil(t, sink, tb.backendSink, "backendSink should be set")
@@ -176,7 +186,7 @@ func TestAppendRowChangedEvents(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	require.Len(t, tb.eventBuffer, 7, "txn event buffer should have 7 txns")
@@ -188,7 +198,7 @@ func TestUpdateResolvedTs(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	// No event will be flushed.
@@ -238,7 +248,7 @@ func TestGetCheckpointTs(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	require.Equal(t, model.NewResolvedTs(0), tb.GetCheckpointTs(), "checkpointTs should be 0")
@@ -269,13 +279,282 @@ func TestGetCheckpointTs(t *testing.T) {
 	require.Equal(t, model.NewResolvedTs(105), tb.GetCheckpointTs(), "checkpointTs should be 105")
 }
 
+func TestGetBackpressureScore(t *testing.T) {
+	t.Parallel()
+
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	tb.AppendRowChangedEvents(getTestRows()...)
+	require.Zero(t, tb.GetBackpressureScore(), "no event is pending")
+
+	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
+	require.Nil(t, err)
+	score := tb.GetBackpressureScore()
+	require.Greater(t, score, float64(0))
+	require.Less(t, score, float64(1))
+
+	// The slow writes make the table sink backpressured.
+	tb.writeLatency.Store(int64(2 * backpressureWriteLatency))
+	require.Equal(t, float64(1), tb.GetBackpressureScore())
+
+	// The backpressure is released after the pending events are flushed.
+	sink.acknowledge(105)
+	require.Zero(t, tb.GetBackpressureScore())
+}
+
+func TestGetStats(t *testing.T) {
+	t.Parallel()
+
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	tb.AppendRowChangedEvents(getTestRows()...)
+	require.Equal(t, Stats{}, tb.GetStats(), "no event is written")
+
+	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
+	require.Nil(t, err)
+	stats := tb.GetStats()
+	require.Equal(t, uint64(len(sink.events)), stats.PendingEvents)
+	require.Greater(t, stats.PendingEvents, uint64(0))
+
+	// The flushed events aren't pending anymore.
+	sink.acknowledge(105)
+	require.Zero(t, tb.GetStats().PendingEvents)
+}
+
+func TestObserveWriteLatency(t *testing.T) {
+	t.Parallel()
+
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	tb.observeWriteLatency(time.Second)
+	require.Equal(t, int64(time.Second), tb.writeLatency.Load())
+	tb.observeWriteLatency(0)
+	require.Equal(t, int64(800*time.Millisecond), tb.writeLatency.Load())
+}
+
+func TestBufferQuotaAccounting(t *testing.T) {
+	t.Parallel()
+
+	quota := NewBufferQuota(model.DefaultChangeFeedID("1"),
+		&config.TableSinkBufferConfig{Quota: util.AddressOf(uint64(1 << 20))}, true)
+	defer quota.Close()
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, quota, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	rows := getTestRows()
+	total := uint64(0)
+	for _, row := range rows {
+		total += uint64(row.ApproximateBytes())
+	}
+	tb.AppendRowChangedEvents(rows...)
+	require.Equal(t, total, quota.Used())
+
+	// The first event is flushed.
+	err := tb.UpdateResolvedTs(model.NewResolvedTs(101))
+	require.Nil(t, err)
+	require.Equal(t, total-uint64(rows[0].ApproximateBytes()), quota.Used())
+
+	// The rest events are released after the table sink is closed.
+	sink.acknowledge(101)
+	tb.Close()
+	require.Zero(t, quota.Used())
+}
+
+func TestBufferQuotaSpill(t *testing.T) {
+	t.Parallel()
+
+	quota := NewBufferQuota(model.DefaultChangeFeedID("1"),
+		&config.TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1)),
+			Policy: util.AddressOf(config.TableSinkBufferPolicySpill),
+		}, true)
+	defer quota.Close()
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, quota, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	// The events are written before they're resolved.
+	rows := getTestRows()
+	tb.AppendRowChangedEvents(rows[:3]...)
+	require.Len(t, tb.eventBuffer, 0)
+	require.Len(t, sink.events, 3)
+	require.Zero(t, quota.Used())
+	require.Equal(t, model.BatchResolvedMode, tb.maxResolvedTs.Mode)
+	require.Equal(t, uint64(102), tb.maxResolvedTs.Ts)
+
+	// The transaction with the same commitTs is split into another batch.
+	tb.AppendRowChangedEvents(rows[3])
+	require.Len(t, sink.events, 4)
+	require.Equal(t, uint64(1), tb.maxResolvedTs.BatchID)
+
+	// The checkpoint never crosses the split transaction.
+	sink.acknowledge(102)
+	require.Equal(t, model.Ts(101), tb.GetCheckpointTs().ResolvedMark())
+}
+
+func TestBufferQuotaLimitsBufferedEvents(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := &model.TableName{Schema: "test", Table: "t1", TableID: 1}
+	rows := make([]*model.RowChangedEvent, 0, 100)
+	for i := 0; i < 100; i++ {
+		rows = append(rows, &model.RowChangedEvent{
+			Table:    tableInfo,
+			CommitTs: uint64(101 + i),
+			StartTs:  uint64(100 + i),
+		})
+	}
+	rowSize := uint64(rows[0].ApproximateBytes())
+	limit := 10 * rowSize
+
+	// appendRows appends the rows one by one like the sink manager, which
+	// stops reading the events and resolves the buffered ones if the tables
+	// are blocked, and returns the max size of the buffered events.
+	appendRows := func(policy string, splitTxn bool) (uint64, *BufferQuota) {
+		quota := NewBufferQuota(model.DefaultChangeFeedID("1"),
+			&config.TableSinkBufferConfig{
+				Quota:  util.AddressOf(limit),
+				Policy: util.AddressOf(policy),
+			}, splitTxn)
+		sink := &mockEventSink{dead: make(chan struct{})}
+		tb := New[*model.SingleTableTxn](
+			model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+			sink, &dmlsink.TxnEventAppender{}, quota, prometheus.NewCounter(prometheus.CounterOpts{}))
+		maxUsed := uint64(0)
+		for i, row := range rows {
+			if quota.Blocking() {
+				require.Nil(t, tb.UpdateResolvedTs(model.NewResolvedTs(rows[i-1].CommitTs)))
+			}
+			tb.AppendRowChangedEvents(row)
+			if quota.Used() > maxUsed {
+				maxUsed = quota.Used()
+			}
+		}
+		return maxUsed, quota
+	}
+
+	for _, policy := range []string{
+		config.TableSinkBufferPolicyBlock, config.TableSinkBufferPolicySpill,
+	} {
+		maxUsed, quota := appendRows(policy, true)
+		require.LessOrEqual(t, maxUsed, limit+rowSize, policy)
+		quota.Close()
+	}
+
+	// The events of the atomic transactions are never limited.
+	maxUsed, quota := appendRows(config.TableSinkBufferPolicySpill, false)
+	defer quota.Close()
+	require.Equal(t, uint64(len(rows))*rowSize, maxUsed)
+}
+
+type mockSpillStore struct {
+	commitTs []model.Ts
+	values   [][]byte
+	cleaned  bool
+}
+
+func (m *mockSpillStore) Put(commitTs model.Ts, value []byte) error {
+	m.commitTs = append(m.commitTs, commitTs)
+	m.values = append(m.values, value)
+	return nil
+}
+
+func (m *mockSpillStore) Take(upperBound model.Ts) ([][]byte, error) {
+	i := sort.Search(len(m.commitTs), func(i int) bool {
+		return m.commitTs[i] > upperBound
+	})
+	values := m.values[:i]
+	m.commitTs, m.values = m.commitTs[i:], m.values[i:]
+	return values, nil
+}
+
+func (m *mockSpillStore) Clean() error {
+	m.cleaned = true
+	m.commitTs, m.values = nil, nil
+	return nil
+}
+
+func TestBufferQuotaSpillToDisk(t *testing.T) {
+	t.Parallel()
+
+	quota := NewBufferQuota(model.DefaultChangeFeedID("1"),
+		&config.TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1)),
+			Policy: util.AddressOf(config.TableSinkBufferPolicyDisk),
+		}, true)
+	defer quota.Close()
+	store := &mockSpillStore{}
+	quota.SetSpillStoreCreator(func(tablepb.Span) SpillStore { return store })
+	sink := &mockEventSink{dead: make(chan struct{})}
+	tb := New[*model.SingleTableTxn](
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
+		sink, &dmlsink.TxnEventAppender{}, quota, prometheus.NewCounter(prometheus.CounterOpts{}))
+
+	rows := getTestRows()
+	for i, row := range rows {
+		row.Columns = []*model.Column{{Name: "a", Value: int64(i)}, {Name: "b"}}
+	}
+	// The first row is buffered in the memory since the quota isn't exceeded.
+	tb.AppendRowChangedEvents(rows[:2]...)
+	require.True(t, quota.Exceeded())
+	require.Len(t, store.values, 0)
+	tb.AppendRowChangedEvents(rows[2:5]...)
+	require.Len(t, store.values, 3)
+	require.NotZero(t, quota.DiskUsed())
+	require.False(t, quota.Blocking())
+
+	// The following rows are spilled once some rows are spilled.
+	err := tb.UpdateResolvedTs(model.NewResolvedTs(102))
+	require.Nil(t, err)
+	require.False(t, quota.Exceeded())
+	tb.AppendRowChangedEvents(rows[5:]...)
+	require.Len(t, store.values, 1+len(rows[5:]))
+
+	// The values are restored before the rows are written.
+	err = tb.UpdateResolvedTs(model.NewResolvedTs(105))
+	require.Nil(t, err)
+	require.Zero(t, quota.DiskUsed())
+	require.Len(t, store.values, 0)
+	i := 0
+	for _, event := range sink.events {
+		for _, row := range event.Event.Rows {
+			require.Equal(t, rows[i].CommitTs, row.CommitTs)
+			require.Equal(t, int64(i), row.Columns[0].Value)
+			require.Nil(t, row.Columns[1].Value)
+			i++
+		}
+	}
+	require.Equal(t, len(rows), i)
+
+	// The spilled rows are cleaned after the table sink is closed.
+	tb.AppendRowChangedEvents(&model.RowChangedEvent{Table: rows[0].Table, CommitTs: 106, StartTs: 104})
+	tb.AppendRowChangedEvents(&model.RowChangedEvent{Table: rows[0].Table, CommitTs: 107, StartTs: 105})
+	require.NotZero(t, quota.DiskUsed())
+	sink.acknowledge(105)
+	tb.Close()
+	require.Zero(t, quota.DiskUsed())
+	require.True(t, store.cleaned)
+}
+
 func TestClose(t *testing.T) {
 	t.Parallel()
 
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
@@ -304,7 +583,7 @@ func TestOperationsAfterClose(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	require.True(t, tb.AsyncClose())
 
@@ -319,7 +598,7 @@ func TestCloseCancellable(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
@@ -349,7 +628,7 @@ func TestCloseReentrant(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
@@ -382,7 +661,7 @@ func TestCheckpointTsFrozenWhenStopping(t *testing.T) {
 	sink := &mockEventSink{dead: make(chan struct{})}
 	tb := New[*model.SingleTableTxn](
 		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1), model.Ts(0),
-		sink, &dmlsink.TxnEventAppender{}, prometheus.NewCounter(prometheus.CounterOpts{}))
+		sink, &dmlsink.TxnEventAppender{}, nil, prometheus.NewCounter(prometheus.CounterOpts{}))
 
 	tb.AppendRowChangedEvents(getTestRows()...)
 	err := tb.UpdateResolvedTs(model.NewResolvedTs(105))
@@ -400,3 +679,18 @@ func TestCheckpointTsFrozenWhenStopping(t *testing.T) {
 	sink.acknowledge(105)
 	require.Equal(t, currentTs, tb.GetCheckpointTs(), "checkpointTs should not be updated")
 }
+
+func TestSinkInternalErrorClass(t *testing.T) {
+	t.Parallel()
+
+	err := NewSinkInternalError(errors.New("connection reset by peer"))
+	require.Equal(t, errorutil.SinkErrorClassTransientNetwork, err.Class())
+	require.True(t, err.Retryable())
+
+	cause := cerror.ErrKafkaInvalidConfig.GenWithStackByArgs()
+	err = NewSinkInternalError(cause)
+	require.Equal(t, errorutil.SinkErrorClassFatal, err.Class())
+	require.False(t, err.Retryable())
+	require.ErrorIs(t, err, cause)
+	require.True(t, cerror.IsChangefeedUnRetryableError(errors.Trace(err)))
+}
@@ -51,7 +51,7 @@ func GetProtocol(protocolStr string) (config.Protocol, error) {
 func GetFileExtension(protocol config.Protocol) string {
 	switch protocol {
 	case config.ProtocolAvro, config.ProtocolCanalJSON, config.ProtocolMaxwell,
-		config.ProtocolOpen:
+		config.ProtocolOpen, config.ProtocolDebezium, config.ProtocolJSONSchema:
 		return ".json"
 	case config.ProtocolCraft:
 		return ".craft"
@@ -59,6 +59,8 @@ func GetFileExtension(protocol config.Protocol) string {
 		return ".canal"
 	case config.ProtocolCsv:
 		return ".csv"
+	case config.ProtocolParquet:
+		return ".parquet"
 	default:
 		return ".unknown"
 	}
@@ -0,0 +1,416 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	timodel "github.com/pingcap/tidb/parser/model"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	schemaPlaceholder = "{schema}"
+	tablePlaceholder  = "{table}"
+)
+
+// mergingTableDDLTypes are the DDLs skipped if the table is merged with other
+// tables in the downstream, because they affect the rows of the other tables.
+var mergingTableDDLTypes = map[timodel.ActionType]struct{}{
+	timodel.ActionDropTable:              {},
+	timodel.ActionTruncateTable:          {},
+	timodel.ActionRenameTable:            {},
+	timodel.ActionDropTablePartition:     {},
+	timodel.ActionTruncateTablePartition: {},
+	timodel.ActionExchangeTablePartition: {},
+	timodel.ActionReorganizePartition:    {},
+}
+
+type tableRoute struct {
+	matcher      tfilter.Filter
+	targetSchema string
+	targetTable  string
+	// columns maps the lower case upstream names to the downstream names.
+	columns map[string]string
+	// sourceColumns is whether the source columns are appended to the rows.
+	sourceColumns bool
+}
+
+func (r *tableRoute) routeSchema(schema string) string {
+	if r.targetSchema == "" {
+		return schema
+	}
+	return strings.ReplaceAll(r.targetSchema, schemaPlaceholder, schema)
+}
+
+func (r *tableRoute) routeTable(schema, table string) string {
+	if r.targetTable == "" {
+		return table
+	}
+	return strings.NewReplacer(schemaPlaceholder, schema, tablePlaceholder, table).
+		Replace(r.targetTable)
+}
+
+func (r *tableRoute) routeColumn(name string) string {
+	if target, ok := r.columns[strings.ToLower(name)]; ok {
+		return target
+	}
+	return name
+}
+
+// mergesTables returns whether the matched tables may be merged into one
+// table in the downstream.
+func (r *tableRoute) mergesTables() bool {
+	return r.targetTable != "" && !strings.Contains(r.targetTable, tablePlaceholder)
+}
+
+// mergesSchemas returns whether the matched schemas may be merged into one
+// schema in the downstream.
+func (r *tableRoute) mergesSchemas() bool {
+	return r.targetSchema != "" && !strings.Contains(r.targetSchema, schemaPlaceholder)
+}
+
+// TableRouter renames the tables and the columns in the downstream by the
+// table routes of the changefeed.
+type TableRouter struct {
+	routes []*tableRoute
+}
+
+// NewTableRouter creates a TableRouter by the table routes in the config, nil
+// is returned if there is no table route.
+func NewTableRouter(cfg *config.ReplicaConfig) (*TableRouter, error) {
+	if len(cfg.Sink.TableRoutes) == 0 {
+		return nil, nil
+	}
+	r := &TableRouter{}
+	for _, route := range cfg.Sink.TableRoutes {
+		tf, err := tfilter.Parse(route.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, route.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		columns := make(map[string]string, len(route.Columns))
+		for _, column := range route.Columns {
+			columns[strings.ToLower(column.Source)] = column.Target
+		}
+		r.routes = append(r.routes, &tableRoute{
+			matcher:       tf,
+			targetSchema:  route.TargetSchema,
+			targetTable:   route.TargetTable,
+			columns:       columns,
+			sourceColumns: route.SourceColumns,
+		})
+	}
+	return r, nil
+}
+
+func (r *TableRouter) match(schema, table string) *tableRoute {
+	for _, route := range r.routes {
+		if route.matcher.MatchTable(schema, table) {
+			return route
+		}
+	}
+	return nil
+}
+
+// matchSchema returns the first route renaming the schema.
+func (r *TableRouter) matchSchema(schema string) *tableRoute {
+	for _, route := range r.routes {
+		if route.targetSchema != "" && route.matcher.MatchSchema(schema) {
+			return route
+		}
+	}
+	return nil
+}
+
+// RouteTable returns the name of the table in the downstream.
+func (r *TableRouter) RouteTable(table model.TableName) model.TableName {
+	route := r.match(table.Schema, table.Table)
+	if route == nil {
+		return table
+	}
+	routed := table
+	routed.Schema = route.routeSchema(table.Schema)
+	routed.Table = route.routeTable(table.Schema, table.Table)
+	return routed
+}
+
+// RouteTableInfo returns a copy of the table info with the names of the table
+// and the columns in the downstream and the source columns if the route has
+// them, the table info is returned as is if no route matches it.
+func (r *TableRouter) RouteTableInfo(info *model.TableInfo) *model.TableInfo {
+	if info == nil {
+		return nil
+	}
+	var route *tableRoute
+	if info.TableName.Table == "" {
+		route = r.matchSchema(info.TableName.Schema)
+	} else {
+		route = r.match(info.TableName.Schema, info.TableName.Table)
+	}
+	if route == nil {
+		return info
+	}
+
+	routed := *info
+	routed.TableName.Schema = route.routeSchema(info.TableName.Schema)
+	if info.TableName.Table == "" {
+		return &routed
+	}
+	routed.TableName.Table = route.routeTable(info.TableName.Schema, info.TableName.Table)
+	if info.TableInfo != nil {
+		routed.TableInfo = info.TableInfo.Clone()
+		routed.TableInfo.Name = timodel.NewCIStr(routed.TableName.Table)
+		for _, col := range routed.TableInfo.Columns {
+			col.Name = timodel.NewCIStr(route.routeColumn(col.Name.O))
+		}
+		for _, idx := range routed.TableInfo.Indices {
+			for _, col := range idx.Columns {
+				col.Name = timodel.NewCIStr(route.routeColumn(col.Name.O))
+			}
+		}
+		if route.sourceColumns {
+			appendSourceColumnInfos(routed.TableInfo)
+			// the offsets and the flags of the columns are computed again.
+			wrapped := model.WrapTableInfo(info.SchemaID, routed.TableName.Schema,
+				info.Version, routed.TableInfo)
+			wrapped.TableName = routed.TableName
+			return wrapped
+		}
+	}
+	return &routed
+}
+
+// RouteRow returns a copy of the row with the names of the table and the
+// columns in the downstream and the source columns if the route has them, the
+// row is returned as is if no route matches it. tableInfo is the routed table info of the row, it's passed in so that
+// the table info is only copied once for the rows of a table.
+func (r *TableRouter) RouteRow(
+	row *model.RowChangedEvent, tableInfo *model.TableInfo,
+) *model.RowChangedEvent {
+	if row.Table == nil {
+		return row
+	}
+	route := r.match(row.Table.Schema, row.Table.Table)
+	if route == nil {
+		return row
+	}
+
+	routed := *row
+	routed.Table = &model.TableName{
+		Schema:      route.routeSchema(row.Table.Schema),
+		Table:       route.routeTable(row.Table.Schema, row.Table.Table),
+		TableID:     row.Table.TableID,
+		IsPartition: row.Table.IsPartition,
+	}
+	routed.TableInfo = tableInfo
+	if len(route.columns) > 0 {
+		routed.Columns = route.routeColumns(row.Columns)
+		routed.PreColumns = route.routeColumns(row.PreColumns)
+	}
+	if route.sourceColumns {
+		appendSourceColumns(&routed, tableInfo, row.Table.Schema, row.Table.Table)
+	}
+	return &routed
+}
+
+func (r *tableRoute) routeColumns(cols []*model.Column) []*model.Column {
+	if cols == nil {
+		return nil
+	}
+	routed := make([]*model.Column, len(cols))
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		c := *col
+		c.Name = r.routeColumn(col.Name)
+		routed[i] = &c
+	}
+	return routed
+}
+
+// RouteTableInfos returns the table infos with the names of the tables in the
+// downstream, the tables merged in the downstream are only returned once. Only
+// the names are routed because they're used to write the checkpoints.
+func (r *TableRouter) RouteTableInfos(infos []*model.TableInfo) []*model.TableInfo {
+	routed := make([]*model.TableInfo, 0, len(infos))
+	seen := make(map[model.TableName]struct{}, len(infos))
+	for _, info := range infos {
+		if name := r.RouteTable(info.TableName); name != info.TableName {
+			copied := *info
+			copied.TableName = name
+			info = &copied
+		}
+		name := model.TableName{Schema: info.TableName.Schema, Table: info.TableName.Table}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		routed = append(routed, info)
+	}
+	return routed
+}
+
+// RouteDDL returns a copy of the DDL whose query and table infos have the
+// names of the tables and the columns in the downstream, the DDL is returned
+// as is if no route matches it. false is returned if the DDL must be skipped,
+// because it drops or renames a table or a schema merged with others in the
+// downstream.
+func (r *TableRouter) RouteDDL(ddl *model.DDLEvent) (*model.DDLEvent, bool, error) {
+	if ddl.TableInfo == nil {
+		return ddl, true, nil
+	}
+	name := ddl.TableInfo.TableName
+	if name.Table == "" {
+		route := r.matchSchema(name.Schema)
+		if route == nil {
+			return ddl, true, nil
+		}
+		if ddl.Type == timodel.ActionDropSchema && route.mergesSchemas() {
+			return nil, false, nil
+		}
+	} else {
+		route := r.match(name.Schema, name.Table)
+		if ddl.PreTableInfo != nil && ddl.PreTableInfo.TableName.Table != "" {
+			pre := ddl.PreTableInfo.TableName
+			if preRoute := r.match(pre.Schema, pre.Table); preRoute != nil {
+				route = preRoute
+			}
+		}
+		if route == nil {
+			return ddl, true, nil
+		}
+		if _, ok := mergingTableDDLTypes[ddl.Type]; ok && route.mergesTables() {
+			return nil, false, nil
+		}
+	}
+
+	query, err := r.routeQuery(ddl)
+	if err != nil {
+		return nil, false, err
+	}
+	return &model.DDLEvent{
+		StartTs:      ddl.StartTs,
+		CommitTs:     ddl.CommitTs,
+		Query:        query,
+		TableInfo:    r.RouteTableInfo(ddl.TableInfo),
+		PreTableInfo: r.RouteTableInfo(ddl.PreTableInfo),
+		Type:         ddl.Type,
+		Charset:      ddl.Charset,
+		Collate:      ddl.Collate,
+	}, true, nil
+}
+
+func (r *TableRouter) routeQuery(ddl *model.DDLEvent) (string, error) {
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, ddl.Charset, ddl.Collate)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	schema := ddl.TableInfo.TableName.Schema
+	switch s := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		s.Name = timodel.NewCIStr(r.routeSchema(s.Name.O))
+	case *ast.AlterDatabaseStmt:
+		s.Name = timodel.NewCIStr(r.routeSchema(s.Name.O))
+	case *ast.DropDatabaseStmt:
+		s.Name = timodel.NewCIStr(r.routeSchema(s.Name.O))
+	default:
+		v := &ddlRouteVisitor{router: r, schema: schema}
+		if table := ddl.TableInfo.TableName.Table; table != "" {
+			v.route = r.match(schema, table)
+		}
+		stmt.Accept(v)
+		if v.route != nil && v.route.sourceColumns {
+			addSourceColumnDefs(stmt)
+		}
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes
+	if err := stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+func (r *TableRouter) routeSchema(schema string) string {
+	if route := r.matchSchema(schema); route != nil {
+		return route.routeSchema(schema)
+	}
+	return schema
+}
+
+// ddlRouteVisitor renames the tables in the DDL, and the columns of the table
+// the DDL changes.
+type ddlRouteVisitor struct {
+	router *TableRouter
+	// schema is the default schema of the tables without the schema.
+	schema string
+	// route is the route of the table the DDL changes.
+	route *tableRoute
+}
+
+func (v *ddlRouteVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch n := in.(type) {
+	case *ast.TableName:
+		v.routeTableName(n)
+		return in, true
+	case *ast.ReferenceDef:
+		// the columns of the foreign keys belong to the referenced table.
+		route := v.routeTableName(n.Table)
+		for _, part := range n.IndexPartSpecifications {
+			if route != nil && part.Column != nil {
+				part.Column.Name = timodel.NewCIStr(route.routeColumn(part.Column.Name.O))
+			}
+		}
+		return in, true
+	case *ast.ColumnName:
+		if v.route != nil {
+			n.Name = timodel.NewCIStr(v.route.routeColumn(n.Name.O))
+		}
+		return in, true
+	}
+	return in, false
+}
+
+func (v *ddlRouteVisitor) routeTableName(n *ast.TableName) *tableRoute {
+	schema := n.Schema.O
+	if schema == "" {
+		schema = v.schema
+	}
+	route := v.router.match(schema, n.Name.O)
+	if route == nil {
+		return nil
+	}
+	n.Schema = timodel.NewCIStr(route.routeSchema(schema))
+	n.Name = timodel.NewCIStr(route.routeTable(schema, n.Name.O))
+	return route
+}
+
+func (v *ddlRouteVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,282 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTableRouter(t *testing.T) *TableRouter {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = false
+	cfg.Sink.TableRoutes = []*config.TableRoute{
+		{
+			Matcher:      []string{"shard_*.t_*"},
+			TargetSchema: "shard",
+			TargetTable:  "t",
+			Columns:      []*config.ColumnRoute{{Source: "A", Target: "b"}},
+		},
+		{Matcher: []string{"test.*"}, TargetTable: "{table}_bak"},
+	}
+	r, err := NewTableRouter(cfg)
+	require.NoError(t, err)
+	return r
+}
+
+func newTestTableInfo(schema, table string) *model.TableInfo {
+	return &model.TableInfo{
+		TableName: model.TableName{Schema: schema, Table: table, TableID: 1},
+		TableInfo: &timodel.TableInfo{
+			Name:    timodel.NewCIStr(table),
+			Columns: []*timodel.ColumnInfo{{Name: timodel.NewCIStr("a")}},
+			Indices: []*timodel.IndexInfo{{
+				Name:    timodel.NewCIStr("idx"),
+				Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("a")}},
+			}},
+		},
+	}
+}
+
+func TestTableRouterRouteRow(t *testing.T) {
+	t.Parallel()
+
+	r := newTestTableRouter(t)
+	require.Equal(t, model.TableName{Schema: "shard", Table: "t", TableID: 1},
+		r.RouteTable(model.TableName{Schema: "SHARD_1", Table: "T_1", TableID: 1}))
+	require.Equal(t, model.TableName{Schema: "test", Table: "t1_bak"},
+		r.RouteTable(model.TableName{Schema: "test", Table: "t1"}))
+	require.Equal(t, model.TableName{Schema: "db", Table: "t1"},
+		r.RouteTable(model.TableName{Schema: "db", Table: "t1"}))
+
+	info := newTestTableInfo("shard_1", "t_1")
+	routedInfo := r.RouteTableInfo(info)
+	require.Equal(t, model.TableName{Schema: "shard", Table: "t", TableID: 1}, routedInfo.TableName)
+	require.Equal(t, "t", routedInfo.Name.O)
+	require.Equal(t, "b", routedInfo.Columns[0].Name.O)
+	require.Equal(t, "b", routedInfo.Indices[0].Columns[0].Name.O)
+	// the table info of the upstream isn't modified.
+	require.Equal(t, "a", info.Columns[0].Name.O)
+	require.Equal(t, "t_1", info.TableName.Table)
+
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "shard_1", Table: "t_1", TableID: 1},
+		TableInfo:  info,
+		Columns:    []*model.Column{{Name: "a", Value: 1}, nil},
+		PreColumns: []*model.Column{{Name: "a", Value: 0}, nil},
+	}
+	routed := r.RouteRow(row, routedInfo)
+	require.Equal(t, &model.TableName{Schema: "shard", Table: "t", TableID: 1}, routed.Table)
+	require.Same(t, routedInfo, routed.TableInfo)
+	require.Equal(t, "b", routed.Columns[0].Name)
+	require.Nil(t, routed.Columns[1])
+	require.Equal(t, "b", routed.PreColumns[0].Name)
+	require.Equal(t, "a", row.Columns[0].Name)
+	require.Equal(t, "shard_1", row.Table.Schema)
+
+	row = &model.RowChangedEvent{Table: &model.TableName{Schema: "db", Table: "t1"}}
+	require.Same(t, row, r.RouteRow(row, nil))
+
+	infos := r.RouteTableInfos([]*model.TableInfo{
+		newTestTableInfo("shard_1", "t_1"),
+		newTestTableInfo("shard_2", "t_2"),
+		newTestTableInfo("db", "t1"),
+	})
+	require.Len(t, infos, 2)
+	require.Equal(t, "shard", infos[0].TableName.Schema)
+	require.Equal(t, "db", infos[1].TableName.Schema)
+}
+
+func TestTableRouterRouteDDL(t *testing.T) {
+	t.Parallel()
+
+	r := newTestTableRouter(t)
+	cases := []struct {
+		tp       timodel.ActionType
+		schema   string
+		table    string
+		preTable string
+		query    string
+		expected string
+		skipped  bool
+	}{
+		{
+			tp:       timodel.ActionCreateTable,
+			schema:   "shard_1",
+			table:    "t_1",
+			query:    "CREATE TABLE `t_1` (`a` INT PRIMARY KEY, `c` INT, KEY `idx`(`a`))",
+			expected: "CREATE TABLE `shard`.`t` (`b` INT PRIMARY KEY,`c` INT,INDEX `idx`(`b`))",
+		},
+		{
+			tp:       timodel.ActionAddColumn,
+			schema:   "shard_1",
+			table:    "t_1",
+			query:    "ALTER TABLE `shard_1`.`t_1` ADD COLUMN `d` INT AFTER `a`",
+			expected: "ALTER TABLE `shard`.`t` ADD COLUMN `d` INT AFTER `b`",
+		},
+		{
+			tp:      timodel.ActionTruncateTable,
+			schema:  "shard_1",
+			table:   "t_1",
+			query:   "TRUNCATE TABLE `shard_1`.`t_1`",
+			skipped: true,
+		},
+		{
+			tp:       timodel.ActionRenameTable,
+			schema:   "shard_1",
+			table:    "t_1_old",
+			preTable: "t_1",
+			query:    "RENAME TABLE `shard_1`.`t_1` TO `shard_1`.`t_1_old`",
+			skipped:  true,
+		},
+		{
+			tp:       timodel.ActionDropTable,
+			schema:   "test",
+			table:    "t1",
+			query:    "DROP TABLE `test`.`t1`",
+			expected: "DROP TABLE `test`.`t1_bak`",
+		},
+		{
+			tp:       timodel.ActionCreateSchema,
+			schema:   "shard_1",
+			query:    "CREATE DATABASE `shard_1`",
+			expected: "CREATE DATABASE `shard`",
+		},
+		{
+			tp:      timodel.ActionDropSchema,
+			schema:  "shard_1",
+			query:   "DROP DATABASE `shard_1`",
+			skipped: true,
+		},
+		{
+			tp:       timodel.ActionCreateTable,
+			schema:   "db",
+			table:    "t1",
+			query:    "create table t1 (a int)",
+			expected: "create table t1 (a int)",
+		},
+	}
+	for _, c := range cases {
+		ddl := &model.DDLEvent{
+			Type:      c.tp,
+			Query:     c.query,
+			TableInfo: &model.TableInfo{TableName: model.TableName{Schema: c.schema, Table: c.table}},
+		}
+		if c.preTable != "" {
+			ddl.PreTableInfo = &model.TableInfo{
+				TableName: model.TableName{Schema: c.schema, Table: c.preTable},
+			}
+		}
+		routed, ok, err := r.RouteDDL(ddl)
+		require.NoError(t, err, c.query)
+		require.Equal(t, !c.skipped, ok, c.query)
+		if c.skipped {
+			continue
+		}
+		require.Equal(t, c.expected, routed.Query, c.query)
+	}
+}
+
+func TestTableRouterSourceColumns(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.TableRoutes = []*config.TableRoute{{
+		Matcher:       []string{"shard_*.t_*"},
+		TargetSchema:  "shard",
+		TargetTable:   "t",
+		SourceColumns: true,
+	}}
+	r, err := NewTableRouter(cfg)
+	require.NoError(t, err)
+
+	idCol := &timodel.ColumnInfo{ID: 1, Name: timodel.NewCIStr("id"), Offset: 0}
+	idCol.FieldType = *types.NewFieldType(mysql.TypeLong)
+	idCol.AddFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	info := model.WrapTableInfo(1, "shard_1", 1, &timodel.TableInfo{
+		ID:         10,
+		Name:       timodel.NewCIStr("t_1"),
+		PKIsHandle: true,
+		Columns:    []*timodel.ColumnInfo{idCol},
+	})
+	routedInfo := r.RouteTableInfo(info)
+	require.Equal(t, model.TableName{Schema: "shard", Table: "t", TableID: 10}, routedInfo.TableName)
+	require.False(t, routedInfo.PKIsHandle)
+	require.Len(t, routedInfo.Columns, 3)
+	require.Equal(t, config.SourceTableColumn, routedInfo.Columns[2].Name.O)
+	require.Len(t, routedInfo.Indices, 1)
+	require.True(t, routedInfo.Indices[0].Primary)
+	require.Len(t, routedInfo.Indices[0].Columns, 3)
+	// the table info of the upstream isn't modified.
+	require.True(t, info.PKIsHandle)
+	require.Len(t, info.Columns, 1)
+
+	row := &model.RowChangedEvent{
+		Table:     &model.TableName{Schema: "shard_1", Table: "t_1", TableID: 10},
+		TableInfo: info,
+		Columns:   []*model.Column{{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag}},
+	}
+	routed := r.RouteRow(row, routedInfo)
+	require.Len(t, row.Columns, 1)
+	require.Len(t, routed.Columns, 3)
+	require.Nil(t, routed.PreColumns)
+	require.Equal(t, config.SourceSchemaColumn, routed.Columns[1].Name)
+	require.Equal(t, []byte("shard_1"), routed.Columns[1].Value)
+	require.Equal(t, config.SourceTableColumn, routed.Columns[2].Name)
+	require.Equal(t, []byte("t_1"), routed.Columns[2].Value)
+	require.True(t, routed.Columns[2].Flag.IsHandleKey())
+	require.Equal(t, [][]int{{0, 1, 2}}, routed.IndexColumns)
+
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query: "CREATE TABLE `t_1` (`id` INT PRIMARY KEY /*T![clustered_index] CLUSTERED */, `a` INT UNIQUE, `b` INT)",
+			expected: "CREATE TABLE `shard`.`t` (`id` INT,`a` INT,`b` INT," +
+				"`_source_schema` VARCHAR(64) CHARACTER SET UTF8MB4 COLLATE utf8mb4_bin NOT NULL," +
+				"`_source_table` VARCHAR(64) CHARACTER SET UTF8MB4 COLLATE utf8mb4_bin NOT NULL," +
+				"PRIMARY KEY(`id`, `_source_schema`, `_source_table`) /*T![clustered_index] CLUSTERED */," +
+				"UNIQUE(`a`, `_source_schema`, `_source_table`))",
+		},
+		{
+			query:    "ALTER TABLE `t_1` ADD UNIQUE KEY `uk`(`b`)",
+			expected: "ALTER TABLE `shard`.`t` ADD UNIQUE `uk`(`b`, `_source_schema`, `_source_table`)",
+		},
+		{
+			query:    "CREATE UNIQUE INDEX `uk` ON `t_1` (`b`)",
+			expected: "CREATE UNIQUE INDEX `uk` ON `shard`.`t` (`b`, `_source_schema`, `_source_table`)",
+		},
+		{
+			query:    "ALTER TABLE `t_1` ADD COLUMN `c` INT",
+			expected: "ALTER TABLE `shard`.`t` ADD COLUMN `c` INT",
+		},
+	}
+	for _, c := range cases {
+		routed, ok, err := r.RouteDDL(&model.DDLEvent{
+			Type:      timodel.ActionCreateTable,
+			Query:     c.query,
+			TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "shard_1", Table: "t_1"}},
+		})
+		require.NoError(t, err, c.query)
+		require.True(t, ok, c.query)
+		require.Equal(t, c.expected, routed.Query, c.query)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/pingcap/tidb/parser/ast"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+// sourceColumnLen is the length of the source columns, which is the max length
+// of the names of the schemas and the tables.
+const sourceColumnLen = 64
+
+// sizeOfEmptyColumn is the size of the column struct.
+const sizeOfEmptyColumn = int(unsafe.Sizeof(model.Column{}))
+
+var sourceColumnNames = []string{config.SourceSchemaColumn, config.SourceTableColumn}
+
+func newSourceFieldType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarchar)
+	ft.SetFlen(sourceColumnLen)
+	ft.SetCharset(mysql.DefaultCharset)
+	ft.SetCollate(mysql.DefaultCollationName)
+	ft.SetFlag(mysql.NotNullFlag)
+	return ft
+}
+
+// appendSourceColumnInfos appends the source columns to the table info, and
+// adds them to the primary key and the unique keys. The primary key which is
+// the handle is changed to a primary index, so it can have the source columns.
+func appendSourceColumnInfos(info *timodel.TableInfo) {
+	var maxColumnID, maxIndexID int64
+	for _, col := range info.Columns {
+		if col.ID > maxColumnID {
+			maxColumnID = col.ID
+		}
+	}
+	for _, idx := range info.Indices {
+		if idx.ID > maxIndexID {
+			maxIndexID = idx.ID
+		}
+	}
+	if info.PKIsHandle {
+		if pk := info.GetPkColInfo(); pk != nil {
+			info.Indices = append(info.Indices, &timodel.IndexInfo{
+				ID:      maxIndexID + 1,
+				Name:    timodel.NewCIStr(mysql.PrimaryKeyName),
+				Table:   info.Name,
+				Columns: []*timodel.IndexColumn{{Name: pk.Name, Offset: pk.Offset, Length: types.UnspecifiedLength}},
+				Unique:  true,
+				Primary: true,
+				State:   timodel.StatePublic,
+				Tp:      timodel.IndexTypeBtree,
+			})
+		}
+		info.PKIsHandle = false
+	}
+
+	offsets := make([]int, 0, len(sourceColumnNames))
+	for i, name := range sourceColumnNames {
+		col := &timodel.ColumnInfo{
+			ID:        maxColumnID + int64(i) + 1,
+			Name:      timodel.NewCIStr(name),
+			Offset:    len(info.Columns),
+			FieldType: *newSourceFieldType(),
+			State:     timodel.StatePublic,
+		}
+		offsets = append(offsets, col.Offset)
+		info.Columns = append(info.Columns, col)
+	}
+	for _, idx := range info.Indices {
+		if !idx.Primary && !idx.Unique {
+			continue
+		}
+		for i, name := range sourceColumnNames {
+			idx.Columns = append(idx.Columns, &timodel.IndexColumn{
+				Name:   timodel.NewCIStr(name),
+				Offset: offsets[i],
+				Length: types.UnspecifiedLength,
+			})
+		}
+	}
+}
+
+// appendSourceColumns appends the source columns to the columns and the
+// pre-columns of the routed row. tableInfo is the routed table info which has
+// the source columns, the flags of the columns are taken from it.
+func appendSourceColumns(row *model.RowChangedEvent, tableInfo *model.TableInfo, schema, table string) {
+	var colInfos []*timodel.ColumnInfo
+	if tableInfo != nil && tableInfo.TableInfo != nil && len(tableInfo.Columns) >= len(sourceColumnNames) {
+		colInfos = tableInfo.Columns[len(tableInfo.Columns)-len(sourceColumnNames):]
+		row.IndexColumns = tableInfo.IndexColumnsOffset
+		// the column infos of the row are kept in line with the columns.
+		if len(row.ColInfos) == len(row.Columns) {
+			rowColInfos := make([]rowcodec.ColInfo, 0, len(row.ColInfos)+len(colInfos))
+			rowColInfos = append(rowColInfos, row.ColInfos...)
+			for _, col := range colInfos {
+				rowColInfos = append(rowColInfos, rowcodec.ColInfo{ID: col.ID, Ft: &col.FieldType})
+			}
+			row.ColInfos = rowColInfos
+		}
+	}
+
+	values := []string{schema, table}
+	sourceColumns := make([]*model.Column, 0, len(sourceColumnNames))
+	for i, name := range sourceColumnNames {
+		col := &model.Column{
+			Name:             name,
+			Type:             mysql.TypeVarchar,
+			Charset:          mysql.DefaultCharset,
+			Value:            []byte(values[i]),
+			ApproximateBytes: sizeOfEmptyColumn + len(values[i]),
+		}
+		if colInfos != nil {
+			col.Flag = tableInfo.ColumnsFlag[colInfos[i].ID]
+		}
+		sourceColumns = append(sourceColumns, col)
+	}
+	if len(row.Columns) > 0 {
+		row.Columns = appendColumns(row.Columns, sourceColumns)
+	}
+	if len(row.PreColumns) > 0 {
+		row.PreColumns = appendColumns(row.PreColumns, sourceColumns)
+	}
+}
+
+// appendColumns appends the columns to a new slice, so the columns of the row
+// in the upstream aren't modified.
+func appendColumns(cols []*model.Column, appended []*model.Column) []*model.Column {
+	res := make([]*model.Column, 0, len(cols)+len(appended))
+	res = append(res, cols...)
+	return append(res, appended...)
+}
+
+// addSourceColumnDefs adds the source columns to the table created by the DDL,
+// and the primary key and the unique keys of the table. The DDLs adding the
+// primary key or the unique keys have the source columns as well.
+func addSourceColumnDefs(stmt ast.StmtNode) {
+	switch s := stmt.(type) {
+	case *ast.CreateTableStmt:
+		if s.ReferTable != nil || s.Select != nil {
+			return
+		}
+		for _, col := range s.Cols {
+			// the keys of the columns are moved to the keys of the table, so
+			// they can have the source columns.
+			options := col.Options[:0]
+			for _, option := range col.Options {
+				constraint := &ast.Constraint{
+					Keys: []*ast.IndexPartSpecification{{Column: col.Name, Length: types.UnspecifiedLength}},
+				}
+				switch option.Tp {
+				case ast.ColumnOptionPrimaryKey:
+					constraint.Tp = ast.ConstraintPrimaryKey
+					if option.PrimaryKeyTp != timodel.PrimaryKeyTypeDefault {
+						constraint.Option = &ast.IndexOption{PrimaryKeyTp: option.PrimaryKeyTp}
+					}
+				case ast.ColumnOptionUniqKey:
+					constraint.Tp = ast.ConstraintUniq
+				default:
+					options = append(options, option)
+					continue
+				}
+				s.Constraints = append(s.Constraints, constraint)
+			}
+			col.Options = options
+		}
+		for _, name := range sourceColumnNames {
+			s.Cols = append(s.Cols, &ast.ColumnDef{
+				Name:    &ast.ColumnName{Name: timodel.NewCIStr(name)},
+				Tp:      newSourceFieldType(),
+				Options: []*ast.ColumnOption{{Tp: ast.ColumnOptionNotNull}},
+			})
+		}
+		for _, constraint := range s.Constraints {
+			addSourceKeys(constraint)
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range s.Specs {
+			if spec.Tp == ast.AlterTableAddConstraint && spec.Constraint != nil {
+				addSourceKeys(spec.Constraint)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		if s.KeyType == ast.IndexKeyTypeUnique {
+			s.IndexPartSpecifications = appendSourceKeys(s.IndexPartSpecifications)
+		}
+	}
+}
+
+func addSourceKeys(constraint *ast.Constraint) {
+	switch constraint.Tp {
+	case ast.ConstraintPrimaryKey, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		constraint.Keys = appendSourceKeys(constraint.Keys)
+	}
+}
+
+func appendSourceKeys(keys []*ast.IndexPartSpecification) []*ast.IndexPartSpecification {
+	for _, key := range keys {
+		if key.Column != nil && isSourceColumnName(key.Column.Name.O) {
+			return keys
+		}
+	}
+	for _, name := range sourceColumnNames {
+		keys = append(keys, &ast.IndexPartSpecification{
+			Column: &ast.ColumnName{Name: timodel.NewCIStr(name)},
+			Length: types.UnspecifiedLength,
+		})
+	}
+	return keys
+}
+
+func isSourceColumnName(name string) bool {
+	for _, sourceName := range sourceColumnNames {
+		if strings.EqualFold(name, sourceName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	putil "github.com/pingcap/tiflow/pkg/util"
+)
+
+// TargetMatcher matches the tables with the sink targets of the changefeed.
+type TargetMatcher struct {
+	matchers []tfilter.Filter
+}
+
+// NewTargetMatcher creates a TargetMatcher by the sink targets in the config.
+func NewTargetMatcher(cfg *config.ReplicaConfig) (*TargetMatcher, error) {
+	m := &TargetMatcher{}
+	for _, target := range cfg.Sink.Targets {
+		tf, err := tfilter.Parse(target.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, target.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		m.matchers = append(m.matchers, tf)
+	}
+	return m, nil
+}
+
+// Match returns the index of the first sink target matching the table, -1 is
+// returned if the table is written to the sink-uri of the changefeed.
+func (m *TargetMatcher) Match(schema, table string) int {
+	for i, matcher := range m.matchers {
+		if matcher.MatchTable(schema, table) {
+			return i
+		}
+	}
+	return -1
+}
+
+// MatchSchema returns the indexes of the sink targets which may have the
+// tables of the schema, the sink-uri of the changefeed is always possible.
+func (m *TargetMatcher) MatchSchema(schema string) []int {
+	var indexes []int
+	for i, matcher := range m.matchers {
+		if matcher.MatchSchema(schema) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// SameSinkTargets returns whether the sink targets route the tables to the
+// same downstreams with the same protocols.
+func SameSinkTargets(a, b []*config.SinkTarget) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].SinkURI != b[i].SinkURI ||
+			putil.GetOrZero(a[i].Protocol) != putil.GetOrZero(b[i].Protocol) ||
+			!sameMatcher(a[i].Matcher, b[i].Matcher) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameMatcher(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
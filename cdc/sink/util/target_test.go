@@ -0,0 +1,68 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetMatcher(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Targets = []*config.SinkTarget{
+		{Matcher: []string{"test.t1"}, SinkURI: "kafka://127.0.0.1:9092/t1"},
+		{Matcher: []string{"test.*", "db.*"}, SinkURI: "s3://bucket/prefix"},
+	}
+	m, err := NewTargetMatcher(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 0, m.Match("test", "t1"))
+	require.Equal(t, -1, m.Match("TEST", "T1"))
+	require.Equal(t, 1, m.Match("test", "t2"))
+	require.Equal(t, 1, m.Match("db", "t1"))
+	require.Equal(t, -1, m.Match("other", "t1"))
+	require.Equal(t, []int{0, 1}, m.MatchSchema("test"))
+	require.Equal(t, []int{1}, m.MatchSchema("db"))
+	require.Empty(t, m.MatchSchema("other"))
+
+	cfg.CaseSensitive = false
+	m, err = NewTargetMatcher(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 0, m.Match("TEST", "T1"))
+
+	cfg.Sink.Targets[0].Matcher = []string{"[test.t1"}
+	_, err = NewTargetMatcher(cfg)
+	require.Error(t, err)
+}
+
+func TestSameSinkTargets(t *testing.T) {
+	t.Parallel()
+
+	targets := []*config.SinkTarget{{Matcher: []string{"test.*"}, SinkURI: "blackhole://"}}
+	require.True(t, SameSinkTargets(nil, nil))
+	require.True(t, SameSinkTargets(targets, []*config.SinkTarget{
+		{Matcher: []string{"test.*"}, SinkURI: "blackhole://"},
+	}))
+	require.False(t, SameSinkTargets(targets, nil))
+	require.False(t, SameSinkTargets(targets, []*config.SinkTarget{
+		{Matcher: []string{"test.*"}, SinkURI: "blackhole://", Protocol: putil.AddressOf("avro")},
+	}))
+	require.False(t, SameSinkTargets(targets, []*config.SinkTarget{
+		{Matcher: []string{"db.*"}, SinkURI: "blackhole://"},
+	}))
+}
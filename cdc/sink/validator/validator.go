@@ -17,13 +17,16 @@ import (
 	"context"
 	"net/url"
 
+	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/factory"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
+	pkafka "github.com/pingcap/tiflow/pkg/sink/kafka"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
 )
 
 // Validate sink if given valid parameters.
@@ -42,6 +45,38 @@ func Validate(ctx context.Context,
 		return err
 	}
 
+	if err := checkKafkaSinkV2Compatibility(uri, cfg); err != nil {
+		return err
+	}
+
+	if cfg.Sink != nil {
+		for _, target := range cfg.Sink.Targets {
+			targetURI, err := preCheckSinkURI(target.SinkURI)
+			if err != nil {
+				return err
+			}
+			err = checkKafkaSinkV2Compatibility(targetURI, cfg.SinkTargetConfig(target))
+			if err != nil {
+				return err
+			}
+		}
+		if cfg.Sink.Mirror != nil {
+			mirrorURI, err := preCheckSinkURI(cfg.Sink.Mirror.SinkURI)
+			if err != nil {
+				return err
+			}
+			// the changefeed is cut over to the mirror later, so the mirror
+			// must be compatible with the sync point as well.
+			if err := checkSyncPointSchemeCompatibility(mirrorURI, cfg); err != nil {
+				return err
+			}
+			err = checkKafkaSinkV2Compatibility(mirrorURI, cfg.SinkMirrorConfig())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if util.GetOrZero(cfg.BDRMode) {
 		err := checkBDRMode(ctx, uri, cfg)
 		if err != nil {
@@ -50,14 +85,36 @@ func Validate(ctx context.Context,
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
-	s, err := factory.New(ctx, changefeedID, sinkURI, cfg, make(chan error))
+	s, err := factory.New(ctx, changefeedID, sinkURI, cfg, make(chan error), make(chan error))
 	if err != nil {
 		cancel()
 		return err
 	}
+	err = s.PreFlight(ctx)
 	cancel()
 	s.Close()
 
+	return err
+}
+
+// checkKafkaSinkV2Compatibility checks if the options of the kafka sink are
+// supported by the Kafka sink v2 when it's enabled.
+func checkKafkaSinkV2Compatibility(
+	uri *url.URL,
+	cfg *config.ReplicaConfig,
+) error {
+	if !sink.IsMQScheme(uri.Scheme) || cfg.Sink == nil ||
+		!cfg.Sink.KafkaSinkV2Enabled() {
+		return nil
+	}
+	ignored, err := pkafka.CheckSinkV2Compatibility(uri, cfg)
+	if err != nil {
+		return err
+	}
+	if len(ignored) > 0 {
+		log.Warn("some options are not supported by Kafka sink v2 and ignored",
+			zap.Strings("options", ignored))
+	}
 	return nil
 }
 
@@ -86,7 +143,7 @@ func preCheckSinkURI(sinkURIStr string) (*url.URL, error) {
 		return nil, cerror.ErrSinkURIInvalid.GenWithStack("sink uri is empty")
 	}
 
-	sinkURI, err := url.Parse(sinkURIStr)
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
@@ -38,7 +38,7 @@ const (
 )
 
 type mysqlSyncPointStore struct {
-	db                     *sql.DB
+	db                     *mysql.Pool
 	clusterID              string
 	syncPointRetention     time.Duration
 	lastCleanSyncPointTime time.Time
@@ -49,10 +49,11 @@ func newMySQLSyncPointStore(
 	ctx context.Context,
 	id model.ChangeFeedID,
 	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
 	syncPointRetention time.Duration,
 ) (SyncPointStore, error) {
 	cfg := mysql.NewConfig()
-	err := cfg.Apply(config.GetGlobalServerConfig().TZ, id, sinkURI, config.GetDefaultReplicaConfig())
+	err := cfg.Apply(config.GetGlobalServerConfig().TZ, id, sinkURI, replicaConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +80,11 @@ func newMySQLSyncPointStore(
 
 	log.Info("Start mysql syncpoint sink")
 
+	// The syncpoints are written by a separate pool, so they don't wait for
+	// the connections writing the rows.
 	return &mysqlSyncPointStore{
-		db:                     syncDB,
+		db: mysql.NewPool(ctx, mysql.MetaPoolName, syncDB,
+			cfg.MetaMaxOpenConns, cfg.PoolHealthCheckInterval),
 		clusterID:              config.GetGlobalServerConfig().ClusterID,
 		syncPointRetention:     syncPointRetention,
 		lastCleanSyncPointTime: time.Now(),
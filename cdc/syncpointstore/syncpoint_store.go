@@ -15,12 +15,13 @@ package syncpointstore
 
 import (
 	"context"
-	"net/url"
 	"strings"
 	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
 )
 
 // SyncPointStore is an abstraction for anything that a changefeed may emit into.
@@ -40,16 +41,17 @@ func NewSyncPointStore(
 	ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	sinkURIStr string,
+	replicaConfig *config.ReplicaConfig,
 	syncPointRetention time.Duration,
 ) (SyncPointStore, error) {
 	// parse sinkURI as a URI
-	sinkURI, err := url.Parse(sinkURIStr)
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
 	switch strings.ToLower(sinkURI.Scheme) {
 	case "mysql", "tidb", "mysql+ssl", "tidb+ssl":
-		return newMySQLSyncPointStore(ctx, changefeedID, sinkURI, syncPointRetention)
+		return newMySQLSyncPointStore(ctx, changefeedID, sinkURI, replicaConfig, syncPointRetention)
 	default:
 		return nil, cerror.ErrSinkURIInvalid.
 			GenWithStack("the sink scheme (%s) is not supported", sinkURI.Scheme)
@@ -501,7 +501,7 @@ func NewConsumer(ctx context.Context, o *consumerOption) (*Consumer, error) {
 	}
 
 	changefeedID := model.DefaultChangeFeedID("kafka-consumer")
-	f, err := eventsinkfactory.New(ctx, changefeedID, o.downstreamURI, config.GetDefaultReplicaConfig(), errChan)
+	f, err := eventsinkfactory.New(ctx, changefeedID, o.downstreamURI, config.GetDefaultReplicaConfig(), errChan, errChan)
 	if err != nil {
 		cancel()
 		return nil, errors.Trace(err)
@@ -604,6 +604,12 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.codecConfig.LargeMessageHandle.EnableClaimCheckBareMessage() {
+		decoder, err = codec.NewClaimCheckBareDecoder(ctx, c.codecConfig, decoder)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	log.Info("start consume claim",
 		zap.String("topic", claim.Topic()), zap.Int32("partition", partition),
@@ -611,10 +617,18 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 
 	eventGroups := make(map[int64]*eventsGroup)
 	for message := range claim.Messages() {
-		if err := decoder.AddKeyValue(message.Key, message.Value); err != nil {
+		value, err := decompressLargeMessage(message)
+		if err != nil {
+			log.Error("decompress the large message failed", zap.Error(err))
+			return errors.Trace(err)
+		}
+		if err := decoder.AddKeyValue(message.Key, value); err != nil {
 			log.Error("add key value to the decoder failed", zap.Error(err))
 			return errors.Trace(err)
 		}
+		// the origin is kept when the rows are written into the downstream, so
+		// the changefeed of the downstream can ignore the rows of its own origin.
+		originID := originOfMessage(message)
 
 		counter := 0
 		for {
@@ -660,6 +674,7 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 						zap.ByteString("value", message.Value),
 						zap.Error(err))
 				}
+				row.OriginID = originID
 
 				if c.eventRouter != nil {
 					target := c.eventRouter.GetPartitionForRowChange(row, c.option.partitionNum)
@@ -963,3 +978,31 @@ func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
 	log.Info("open db success", zap.String("dsn", dsn))
 	return db, nil
 }
+
+// decompressLargeMessage returns the decompressed value of the message if it's
+// compressed by the large message handle, otherwise the value is returned as it is.
+func decompressLargeMessage(message *sarama.ConsumerMessage) ([]byte, error) {
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) == common.HeaderCompression {
+			return common.Decompress(string(header.Value), message.Value)
+		}
+	}
+	return message.Value, nil
+}
+
+// originOfMessage returns the origin of the rows in the message, zero is
+// returned if the message has no valid origin header.
+func originOfMessage(message *sarama.ConsumerMessage) uint64 {
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) == common.HeaderOriginID {
+			originID, err := strconv.ParseUint(string(header.Value), 10, 64)
+			if err != nil {
+				log.Warn("invalid origin header of the message, ignore it",
+					zap.ByteString("origin", header.Value), zap.Error(err))
+				return 0
+			}
+			return originID
+		}
+	}
+	return 0
+}
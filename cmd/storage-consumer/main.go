@@ -122,6 +122,8 @@ type consumer struct {
 	codecCfg        *common.Config
 	externalStorage storage.ExternalStorage
 	fileExtension   string
+	// compression is the compression codec of the data files.
+	compression string
 	// tableDMLIdxMap maintains a map of <dmlPathKey, max file index>
 	tableDMLIdxMap map[cloudstorage.DmlPathKey]uint64
 	// tableTsMap maintains a map of <TableID, max commit ts>
@@ -178,7 +180,12 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 		return nil, err
 	}
 
-	extension := sinkutil.GetFileExtension(protocol)
+	compression := config.FileCompressionNone
+	if replicaConfig.Sink.CloudStorageConfig != nil &&
+		replicaConfig.Sink.CloudStorageConfig.Compression != nil {
+		compression = strings.ToLower(*replicaConfig.Sink.CloudStorageConfig.Compression)
+	}
+	extension := sinkutil.GetFileExtension(protocol) + cloudstorage.CompressionExtension(compression)
 
 	storage, err := putil.GetExternalStorageFromURI(ctx, upstreamURIStr)
 	if err != nil {
@@ -194,6 +201,7 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 		downstreamURIStr,
 		config.GetDefaultReplicaConfig(),
 		errCh,
+		errCh,
 	)
 	if err != nil {
 		log.Error("failed to create event sink factory", zap.Error(err))
@@ -214,6 +222,7 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 		codecCfg:        codecConfig,
 		externalStorage: storage,
 		fileExtension:   extension,
+		compression:     compression,
 		errCh:           errCh,
 		tableDMLIdxMap:  make(map[cloudstorage.DmlPathKey]uint64),
 		tableTsMap:      make(map[model.TableID]model.ResolvedTs),
@@ -267,6 +276,8 @@ func (c *consumer) getNewFiles(
 				// skip handling this file
 				return nil
 			}
+		} else if cloudstorage.IsManifestFile(path) {
+			log.Debug("ignore handling manifest file", zap.String("path", path))
 		} else if strings.HasSuffix(path, c.fileExtension) {
 			err := c.parseDMLFilePath(ctx, path)
 			if err != nil {
@@ -292,6 +303,7 @@ func (c *consumer) emitDMLEvents(
 	ctx context.Context, tableID int64,
 	tableDetail cloudstorage.TableDefinition,
 	pathKey cloudstorage.DmlPathKey,
+	fileIdx uint64,
 	content []byte,
 ) error {
 	var (
@@ -306,7 +318,11 @@ func (c *consumer) emitDMLEvents(
 
 	switch c.codecCfg.Protocol {
 	case config.ProtocolCsv:
-		decoder, err = csv.NewBatchDecoder(ctx, c.codecCfg, tableInfo, content)
+		// only some of the data files have the header row if it's written
+		// into the first data file of each directory.
+		codecCfg := *c.codecCfg
+		codecCfg.OutputHeader = c.codecCfg.FileHasHeader(fileIdx)
+		decoder, err = csv.NewBatchDecoder(ctx, &codecCfg, tableInfo, content)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -419,9 +435,13 @@ func (c *consumer) syncExecDMLEvents(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	content, err = cloudstorage.Decompress(c.compression, content)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	tableID := c.tableIDGenerator.generateFakeTableID(
 		key.Schema, key.Table, key.PartitionNum)
-	err = c.emitDMLEvents(ctx, tableID, tableDef, key, content)
+	err = c.emitDMLEvents(ctx, tableID, tableDef, key, fileIdx, content)
 	if err != nil {
 		return errors.Trace(err)
 	}
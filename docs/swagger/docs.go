@@ -810,6 +810,12 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/v2.ChangefeedConfig"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "validate the changefeed without creating it",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -992,9 +998,9 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/changefeeds/{changefeed_id}/pause": {
+        "/api/v2/changefeeds/{changefeed_id}/credentials": {
             "post": {
-                "description": "Pause a changefeed",
+                "description": "Rotate the sink credentials of a changefeed without restarting it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1005,7 +1011,7 @@ var doc = `{
                     "changefeed",
                     "v2"
                 ],
-                "summary": "Pause a changefeed",
+                "summary": "Rotate the credentials of a changefeed",
                 "parameters": [
                     {
                         "type": "string",
@@ -1019,6 +1025,15 @@ var doc = `{
                         "description": "default",
                         "name": "namespace",
                         "in": "query"
+                    },
+                    {
+                        "description": "sink credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.ChangefeedCredentials"
+                        }
                     }
                 ],
                 "responses": {
@@ -1043,9 +1058,9 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/changefeeds/{changefeed_id}/resume": {
+        "/api/v2/changefeeds/{changefeed_id}/cutover": {
             "post": {
-                "description": "Resume a changefeed",
+                "description": "Replace the sink-uri of a changefeed with its mirror without restarting it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1056,7 +1071,7 @@ var doc = `{
                     "changefeed",
                     "v2"
                 ],
-                "summary": "Resume a changefeed",
+                "summary": "Cut over a changefeed to its mirror",
                 "parameters": [
                     {
                         "type": "string",
@@ -1070,15 +1085,6 @@ var doc = `{
                         "description": "default",
                         "name": "namespace",
                         "in": "query"
-                    },
-                    {
-                        "description": "resume config",
-                        "name": "resumeConfig",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v2.ResumeChangefeedConfig"
-                        }
                     }
                 ],
                 "responses": {
@@ -1103,17 +1109,35 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/health": {
-            "get": {
-                "description": "Check the health status of a TiCDC cluster",
+        "/api/v2/changefeeds/{changefeed_id}/pause": {
+            "post": {
+                "description": "Pause a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "common",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "Check the health status of a TiCDC cluster",
+                "summary": "Pause a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1136,9 +1160,9 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/log": {
+        "/api/v2/changefeeds/{changefeed_id}/resume": {
             "post": {
-                "description": "change TiCDC log level dynamically",
+                "description": "Resume a changefeed",
                 "consumes": [
                     "application/json"
                 ],
@@ -1146,18 +1170,31 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "common",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "Change TiCDC log level",
+                "summary": "Resume a changefeed",
                 "parameters": [
                     {
-                        "description": "log level",
-                        "name": "log_level",
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "resume config",
+                        "name": "resumeConfig",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v2.LogLevelReq"
+                            "$ref": "#/definitions/v2.ResumeChangefeedConfig"
                         }
                     }
                 ],
@@ -1173,13 +1210,19 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/model.HTTPError"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
                     }
                 }
             }
         },
-        "/api/v2/owner/resign": {
+        "/api/v2/changefeeds/{changefeed_id}/savepoints": {
             "post": {
-                "description": "Notify the current controller to resign",
+                "description": "Take a named savepoint of the checkpoint of a changefeed, new changefeeds can be created from it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1187,15 +1230,39 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "owner",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "Notify the controller to resign",
+                "summary": "Create a savepoint of a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "savepoint config",
+                        "name": "savepoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.SavepointConfig"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v2.EmptyResponse"
+                            "$ref": "#/definitions/v2.Savepoint"
                         }
                     },
                     "400": {
@@ -1213,24 +1280,39 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/processors": {
+        "/api/v2/changefeeds/{changefeed_id}/skipped_ddls": {
             "get": {
-                "description": "list all processors in the TiCDC cluster",
+                "description": "List the DDLs recently skipped by the filter of a changefeed, such as by the ddl-deny-list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "processor",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "List processors",
+                "summary": "List the skipped DDLs of a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v2.ProcessorCommonInfo"
+                                "$ref": "#/definitions/v2.SkippedDDL"
                             }
                         }
                     },
@@ -1249,21 +1331,21 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/processors/{changefeed_id}/{capture_id}": {
+        "/api/v2/changefeeds/{changefeed_id}/tables": {
             "get": {
-                "description": "get the detail information of a processor",
+                "description": "List the checkpoint, resolved ts, pending events and sink flush latency of each table span",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "processor",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "Get processor detail information",
+                "summary": "List the table progresses of a changefeed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "changefeed ID",
+                        "description": "changefeed_id",
                         "name": "changefeed_id",
                         "in": "path",
                         "required": true
@@ -1273,20 +1355,74 @@ var doc = `{
                         "description": "default",
                         "name": "namespace",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v2.TableProgress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/tables/{table_id}/pause": {
+            "post": {
+                "description": "Pause the replication of a table without stopping the changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Pause a table of a changefeed",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "capture ID",
-                        "name": "capture_id",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "table_id",
+                        "name": "table_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v2.ProcessorDetail"
+                            "$ref": "#/definitions/v2.EmptyResponse"
                         }
                     },
                     "400": {
@@ -1304,9 +1440,9 @@ var doc = `{
                 }
             }
         },
-        "/api/v2/status": {
-            "get": {
-                "description": "This API is a synchronous interface. If the request is successful,",
+        "/api/v2/changefeeds/{changefeed_id}/tables/{table_id}/resume": {
+            "post": {
+                "description": "Resume the replication of a paused table",
                 "consumes": [
                     "application/json"
                 ],
@@ -1314,15 +1450,37 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "common",
+                    "changefeed",
                     "v2"
                 ],
-                "summary": "Get the status information of a TiCDC node",
+                "summary": "Resume a table of a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "table_id",
+                        "name": "table_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v2.ServerStatus"
+                            "$ref": "#/definitions/v2.EmptyResponse"
                         }
                     },
                     "400": {
@@ -1339,1030 +1497,3127 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "config.CSVConfig": {
-            "type": "object",
-            "properties": {
-                "binary-encoding-method": {
-                    "description": "encoding method of binary type",
-                    "type": "string"
-                },
-                "delimiter": {
-                    "description": "delimiter between fields",
-                    "type": "string"
-                },
-                "include-commit-ts": {
-                    "description": "whether to include commit ts",
-                    "type": "boolean"
-                },
-                "null": {
-                    "description": "representation of null values",
-                    "type": "string"
-                },
-                "quote": {
-                    "description": "quoting character",
-                    "type": "string"
-                }
-            }
         },
-        "config.CloudStorageConfig": {
-            "type": "object",
-            "properties": {
-                "file-size": {
-                    "type": "integer"
-                },
-                "flush-interval": {
-                    "type": "string"
+        "/api/v2/health": {
+            "get": {
+                "description": "Check the health status of a TiCDC cluster",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common",
+                    "v2"
+                ],
+                "summary": "Check the health status of a TiCDC cluster",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/log": {
+            "post": {
+                "description": "change TiCDC log level dynamically",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common",
+                    "v2"
+                ],
+                "summary": "Change TiCDC log level",
+                "parameters": [
+                    {
+                        "description": "log level",
+                        "name": "log_level",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.LogLevelReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/owner/resign": {
+            "post": {
+                "description": "Notify the current controller to resign",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner",
+                    "v2"
+                ],
+                "summary": "Notify the controller to resign",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/processors": {
+            "get": {
+                "description": "list all processors in the TiCDC cluster",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processor",
+                    "v2"
+                ],
+                "summary": "List processors",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v2.ProcessorCommonInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/processors/{changefeed_id}/{capture_id}": {
+            "get": {
+                "description": "get the detail information of a processor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "processor",
+                    "v2"
+                ],
+                "summary": "Get processor detail information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed ID",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "capture ID",
+                        "name": "capture_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ProcessorDetail"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/savepoints": {
+            "get": {
+                "description": "list all savepoints of a namespace",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "List savepoints",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v2.Savepoint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/savepoints/{savepoint_name}": {
+            "get": {
+                "description": "get the detail of a savepoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Get savepoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "savepoint_name",
+                        "name": "savepoint_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Savepoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a savepoint and its service GC safepoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Remove a savepoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "savepoint_name",
+                        "name": "savepoint_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/status": {
+            "get": {
+                "description": "This API is a synchronous interface. If the request is successful,",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "common",
+                    "v2"
+                ],
+                "summary": "Get the status information of a TiCDC node",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ServerStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "config.BootstrapConfig": {
+            "type": "object",
+            "properties": {
+                "enable": {
+                    "description": "Enable sends a bootstrap message with the schema of a table to all the\npartitions of its topic before the first row of the table.",
+                    "type": "boolean"
+                },
+                "snapshot-marker": {
+                    "description": "SnapshotMarker attaches the snapshot ts to the bootstrap messages, the\nrows committed after the ts follow the message.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "config.CSVColumnRule": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.CSVConfig": {
+            "type": "object",
+            "properties": {
+                "binary-encoding-method": {
+                    "description": "encoding method of binary type",
+                    "type": "string"
+                },
+                "column-rules": {
+                    "description": "ColumnRules specifies the columns and their order of the matched tables,\nthe first matched one is used if a table matches several ones.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.CSVColumnRule"
+                    }
+                },
+                "delimiter": {
+                    "description": "delimiter between fields",
+                    "type": "string"
+                },
+                "escape-char": {
+                    "description": "escaping character of the csv columns, it's distinct from the quote",
+                    "type": "string"
+                },
+                "include-commit-ts": {
+                    "description": "whether to include commit ts",
+                    "type": "boolean"
+                },
+                "include-header": {
+                    "description": "IncludeHeader specifies the data files which have a header row with the\ncolumn names, can be none, every-file or first-file, none by default.",
+                    "type": "string"
+                },
+                "null": {
+                    "description": "representation of null values",
+                    "type": "string"
+                },
+                "output-schema-sidecar": {
+                    "description": "whether to output a schema file along with the data files of each table version",
+                    "type": "boolean"
+                },
+                "quote": {
+                    "description": "quoting character",
+                    "type": "string"
+                },
+                "quoting-policy": {
+                    "description": "quoting policy of the csv columns, can be always, minimal or non-numeric",
+                    "type": "string"
+                }
+            }
+        },
+        "config.ClickHouseConfig": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "description": "Engine is the table engine of the target tables, the value can be\n\"replacing-merge-tree\" or \"collapsing-merge-tree\".",
+                    "type": "string"
+                },
+                "max-batch-rows": {
+                    "type": "integer"
+                },
+                "sign-column": {
+                    "description": "SignColumn is the column which is set to 1 for the written rows,\nand -1 for the deleted rows.",
+                    "type": "string"
+                },
+                "version-column": {
+                    "description": "VersionColumn is the column which is set to the commit ts of the rows.",
+                    "type": "string"
+                },
+                "worker-count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.CloudStorageConfig": {
+            "type": "object",
+            "properties": {
+                "compression": {
+                    "description": "Compression is the compression codec of the data files except the parquet ones,\nthe value can be \"none\", \"gzip\" or \"zstd\".",
+                    "type": "string"
+                },
+                "enable-delta-lake": {
+                    "description": "EnableDeltaLake commits the data files to the transaction logs of Delta Lake\ntables after they're written, it's only available for the parquet protocol.",
+                    "type": "boolean"
+                },
+                "file-name-template": {
+                    "description": "FileNameTemplate is the template of the data file names, \"{schema}\",\n\"{table}\", \"{date}\", \"{index}\" and \"{extension}\" in it are replaced by\nthe values of the file. The date is the one of date-separator, which is\nempty if date-separator is none. The file index is padded to\nfile-index-digit digits, and the extension has the leading dot, e.g.\n\".csv\". It must contain \"{index}\" exactly once, the default is\n\"CDC{index}{extension}\".",
+                    "type": "string"
+                },
+                "file-size": {
+                    "type": "integer"
+                },
+                "flush-interval": {
+                    "type": "string"
+                },
+                "output-column-id": {
+                    "type": "boolean"
+                },
+                "output-complete-marker": {
+                    "description": "OutputCompleteMarker writes a _SUCCESS file to a date partition directory\nonce all the data files of the partition are flushed, so the batch jobs\nknow the partition is safe to read. It requires the date-separator.",
+                    "type": "boolean"
+                },
+                "output-manifest": {
+                    "description": "OutputManifest writes a manifest file after each flush, which lists the\ndata files with their row counts, commit ts ranges and checksums.",
+                    "type": "boolean"
+                },
+                "parquet-compression": {
+                    "description": "ParquetCompression is the compression codec of the parquet files,\nthe value can be \"none\", \"snappy\" or \"zstd\".",
+                    "type": "string"
+                },
+                "parquet-row-group-size": {
+                    "description": "ParquetRowGroupSize is the upper limit of the row group size in bytes of the parquet files.",
+                    "type": "integer"
+                },
+                "redshift-config": {
+                    "$ref": "#/definitions/config.RedshiftConfig"
+                },
+                "table-overrides": {
+                    "description": "TableOverrides overrides the flush-interval and file-size of the matched\ntables, the first matched one is used if a table matches several ones.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.CloudStorageTableOverride"
+                    }
+                },
+                "worker-count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.CloudStorageTableOverride": {
+            "type": "object",
+            "properties": {
+                "file-size": {
+                    "type": "integer"
+                },
+                "flush-interval": {
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.CodecConfig": {
+            "type": "object",
+            "properties": {
+                "avro-bigint-unsigned-handling-mode": {
+                    "type": "string"
+                },
+                "avro-decimal-handling-mode": {
+                    "type": "string"
+                },
+                "avro-enable-watermark": {
+                    "type": "boolean"
+                },
+                "enable-tidb-extension": {
+                    "type": "boolean"
+                },
+                "max-batch-size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ColumnMasker": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keep-prefix": {
+                    "description": "KeepPrefix and KeepSuffix are the number of the leading and trailing\ncharacters kept by the partial mask function.",
+                    "type": "integer"
+                },
+                "keep-suffix": {
+                    "type": "integer"
+                },
+                "mask": {
+                    "description": "Mask is the mask function, can be hash, null, fixed or partial.",
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "value": {
+                    "description": "Value is the replacement of the fixed mask function.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.ColumnRoute": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.ColumnSelector": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "Columns are the patterns of the selected columns, such as \"a*\" or \"!b\".\nThe handle key columns are always required to be selected.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.ComputedColumn": {
+            "type": "object",
+            "properties": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name is the name of the appended column, it must not be the name of\nan existing column of the matched tables.",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value is the template of the column value, such as \"us-west-1\" or\n\"{cluster-id}:{id}\". The placeholders \"{schema}\", \"{table}\", \"{commit-ts}\",\n\"{cluster-id}\" and \"{changefeed}\" are replaced by the metadata of the row,\nand the other placeholders such as \"{id}\" are replaced by the values of\nthe columns. The computed column is NULL if any referred column is NULL.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.DDLTranslationConfig": {
+            "type": "object",
+            "properties": {
+                "dialect": {
+                    "description": "Dialect can be \"mysql\" or \"mariadb\", the built-in rules of the dialect\nremove the TiDB specific syntax such as the clustered index options,\nAUTO_RANDOM and the placement rules, and skip the DDLs which can't be\ntranslated. No built-in rules are applied if it's empty.",
+                    "type": "string"
+                },
+                "rules": {
+                    "description": "Rules are applied in order after the built-in rules of the dialect.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.DDLTranslationRule"
+                    }
+                }
+            }
+        },
+        "config.DDLTranslationRule": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "description": "Pattern is the regular expression matched against the DDL query.",
+                    "type": "string"
+                },
+                "replacement": {
+                    "description": "Replacement replaces all the matches of the pattern, \"$1\" and \"${name}\"\nrefer to the submatches.",
+                    "type": "string"
+                },
+                "skip": {
+                    "description": "Skip skips the matched DDLs instead of rewriting them.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "config.DispatchRule": {
+            "type": "object",
+            "properties": {
+                "dispatcher": {
+                    "description": "Deprecated, please use PartitionRule.",
+                    "type": "string"
+                },
+                "key-template": {
+                    "description": "KeyTemplate is the template of the keys of the messages of the matched\ntables, such as \"{schema}.{table}:{id}\", it's independent of the\npartition rule. \"{pk}\" is substituted by the handle key values, and the\nother placeholders except \"{schema}\" and \"{table}\" refer to the columns.\nThe keys are set by the protocol if it's empty.",
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "partition": {
+                    "description": "PartitionRule is an alias added for DispatcherRule to mitigate confusions.\nIn the future release, the DispatcherRule is expected to be removed .",
+                    "type": "string"
+                },
+                "partition-plugin": {
+                    "description": "PartitionPlugin is the path of a Go plugin which computes the partitions,\nthe PartitionRule is ignored if it's set.",
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "topic-configs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "topic-partition-num": {
+                    "description": "TopicPartitionNum and TopicConfigs override the partition number and the\ntopic level configurations, such as \"retention.ms\", of the topics which\nare auto-created for the rule, they're only supported by the kafka sink.",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ElasticsearchConfig": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "description": "Index is the index of the tables which aren't matched by the index rules,\n\"{schema}\" and \"{table}\" are replaced by the schema and table names.",
+                    "type": "string"
+                },
+                "index-rules": {
+                    "description": "IndexRules overrides the index of the matched tables, the first matched\none is used if a table matches several ones.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ElasticsearchIndexRule"
+                    }
+                },
+                "max-batch-rows": {
+                    "type": "integer"
+                },
+                "worker-count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ElasticsearchIndexRule": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.KafkaConfig": {
+            "type": "object",
+            "properties": {
+                "auto-create-topic": {
+                    "type": "boolean"
+                },
+                "batch-bytes": {
+                    "description": "BatchBytes is the max size of a batch sent by the kafka sink v2, it's\ncapped by max-message-bytes.",
+                    "type": "integer"
+                },
+                "ca": {
+                    "type": "string"
+                },
+                "cert": {
+                    "type": "string"
+                },
+                "codec-config": {
+                    "$ref": "#/definitions/config.CodecConfig"
+                },
+                "compression": {
+                    "type": "string"
+                },
+                "compression-level": {
+                    "description": "CompressionLevel is the level of the compression algorithm, it's only\nsupported by the sarama client, and it's rejected if the kafka-go sink\nis used.",
+                    "type": "integer"
+                },
+                "dead-letter-storage-uri": {
+                    "type": "string"
+                },
+                "dead-letter-topic": {
+                    "type": "string"
+                },
+                "ddl-topic": {
+                    "type": "string"
+                },
+                "delete-topics-on-remove": {
+                    "type": "boolean"
+                },
+                "dial-timeout": {
+                    "type": "string"
+                },
+                "dns-srv-discovery": {
+                    "type": "boolean"
+                },
+                "enable-kafka-transactions": {
+                    "type": "boolean"
+                },
+                "enable-tls": {
+                    "type": "boolean"
+                },
+                "event-hubs": {
+                    "description": "EventHubs indicates that the sink writes to the Kafka endpoint of Azure\nEvent Hubs, whose unsupported admin APIs are never called.",
+                    "type": "boolean"
+                },
+                "event-hubs-connection-string": {
+                    "description": "EventHubsConnectionString is the connection string of the Event Hubs\nnamespace, which is used as the password of SASL/PLAIN.",
+                    "type": "string"
+                },
+                "insecure-skip-verify": {
+                    "type": "boolean"
+                },
+                "kafka-client-id": {
+                    "type": "string"
+                },
+                "kafka-version": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "large-message-handle": {
+                    "$ref": "#/definitions/config.LargeMessageHandleConfig"
+                },
+                "linger": {
+                    "description": "Linger is the max time the kafka sink v2 waits for the messages of a\nbatch, such as \"5ms\".",
+                    "type": "string"
+                },
+                "max-inflight": {
+                    "description": "MaxInflight is the max number of the messages sent by the kafka sink v2\nbut not acknowledged yet, unlimited if it's not set.",
+                    "type": "integer"
+                },
+                "max-message-bytes": {
+                    "type": "integer"
+                },
+                "partition-num": {
+                    "type": "integer"
+                },
+                "read-timeout": {
+                    "type": "string"
+                },
+                "replication-factor": {
+                    "type": "integer"
+                },
+                "required-acks": {
+                    "type": "integer"
+                },
+                "sasl-aws-region": {
+                    "type": "string"
+                },
+                "sasl-aws-role-arn": {
+                    "type": "string"
+                },
+                "sasl-aws-role-session-name": {
+                    "type": "string"
+                },
+                "sasl-gssapi-auth-type": {
+                    "type": "string"
+                },
+                "sasl-gssapi-disable-pafxfast": {
+                    "type": "boolean"
+                },
+                "sasl-gssapi-kerberos-config-path": {
+                    "type": "string"
+                },
+                "sasl-gssapi-keytab-path": {
+                    "type": "string"
+                },
+                "sasl-gssapi-password": {
+                    "type": "string"
+                },
+                "sasl-gssapi-realm": {
+                    "type": "string"
+                },
+                "sasl-gssapi-service-name": {
+                    "type": "string"
+                },
+                "sasl-gssapi-user": {
+                    "type": "string"
+                },
+                "sasl-mechanism": {
+                    "type": "string"
+                },
+                "sasl-oauth-assertion-key": {
+                    "type": "string"
+                },
+                "sasl-oauth-assertion-key-id": {
+                    "type": "string"
+                },
+                "sasl-oauth-audience": {
+                    "type": "string"
+                },
+                "sasl-oauth-client-id": {
+                    "type": "string"
+                },
+                "sasl-oauth-client-secret": {
+                    "type": "string"
+                },
+                "sasl-oauth-grant-type": {
+                    "type": "string"
+                },
+                "sasl-oauth-scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sasl-oauth-token-url": {
+                    "type": "string"
+                },
+                "sasl-password": {
+                    "type": "string"
+                },
+                "sasl-user": {
+                    "type": "string"
+                },
+                "topic-configs": {
+                    "description": "TopicConfigs are the topic level configurations of the auto-created\ntopics, such as \"retention.ms\", \"cleanup.policy\" and \"min.insync.replicas\",\nthey can be overridden by the dispatch rules.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "write-timeout": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.LargeMessageHandleConfig": {
+            "type": "object",
+            "properties": {
+                "claim-check-bare-message": {
+                    "description": "ClaimCheckBareMessage indicates that only a tiny pointer message, which contains\nthe storage location, size and checksum of the claim-check file, is sent to the MQ.",
+                    "type": "boolean"
+                },
+                "claim-check-compression": {
+                    "type": "string"
+                },
+                "claim-check-presigned-url-expiry": {
+                    "description": "ClaimCheckPresignedURLExpiry is how long the presigned URL in the bare\nmessage is valid, such as \"24h\". If it's set, the bare message contains a\npresigned URL to get the claim-check file instead of the storage URI, so\nthe consumers don't need the credentials of the storage.",
+                    "type": "string"
+                },
+                "claim-check-retention": {
+                    "description": "ClaimCheckRetention is how long the claim-check files are retained, such\nas \"72h\". The files which are older than it and below the checkpoint of\nthe changefeed are deleted, they are never deleted if it's empty.",
+                    "type": "string"
+                },
+                "claim-check-storage-uri": {
+                    "type": "string"
+                },
+                "large-message-compression": {
+                    "description": "LargeMessageCompression is the codec used to compress the large message,\nit can be lz4 or zstd, and lz4 is used if it's empty.",
+                    "type": "string"
+                },
+                "large-message-handle-option": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.MessageHeadersConfig": {
+            "type": "object",
+            "properties": {
+                "enable-metadata": {
+                    "description": "EnableMetadata attaches the schema, table, commit-ts, changefeed-id and\nevent type of the message as headers.",
+                    "type": "boolean"
+                },
+                "static-headers": {
+                    "description": "StaticHeaders are the user-defined headers attached as they are.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.MySQLConfig": {
+            "type": "object",
+            "properties": {
+                "conflict-strategy": {
+                    "description": "ConflictStrategy decides how the rows conflicting with the existing rows are\nwritten in safe mode, it can be \"overwrite\", \"error\", \"ignore\" or \"timestamp-wins\".",
+                    "type": "string"
+                },
+                "conflict-timestamp-column": {
+                    "description": "ConflictTimestampColumn is the column compared by the \"timestamp-wins\" strategy.",
+                    "type": "string"
+                },
+                "ddl-translation": {
+                    "$ref": "#/definitions/config.DDLTranslationConfig"
+                },
+                "dml-max-open-conns": {
+                    "description": "DMLMaxOpenConns limits the connections of the pool writing the rows, it's\nworker-count + 1 by default, and it can't be less than that.",
+                    "type": "integer"
+                },
+                "enable-async-ddl": {
+                    "description": "EnableAsyncDDL executes the time-consuming DDLs such as ADD INDEX in the\nbackground if the downstream is TiDB, only the DDLs of the same tables wait for them.",
+                    "type": "boolean"
+                },
+                "enable-batch-dml": {
+                    "type": "boolean"
+                },
+                "enable-batch-upsert": {
+                    "description": "EnableBatchUpsert coalesces the inserted and updated rows of a table into\nmulti-row INSERT ... ON DUPLICATE KEY UPDATE statements, it takes effect\nif enable-batch-dml is true.",
+                    "type": "boolean"
+                },
+                "enable-cache-prepared-statement": {
+                    "type": "boolean"
+                },
+                "enable-multi-statement": {
+                    "type": "boolean"
+                },
+                "max-multi-update-row": {
+                    "type": "integer"
+                },
+                "max-multi-update-row-size": {
+                    "type": "integer"
+                },
+                "max-txn-row": {
+                    "type": "integer"
+                },
+                "meta-max-open-conns": {
+                    "description": "MetaMaxOpenConns limits the connections of the pool writing the syncpoints,\nwhich is separated from the pool writing the rows.",
+                    "type": "integer"
+                },
+                "pool-health-check-interval": {
+                    "description": "PoolHealthCheckInterval is the interval of probing the connections of the\npools in the background, the probe is disabled if it's 0s.",
+                    "type": "string"
+                },
+                "read-timeout": {
+                    "type": "string"
+                },
+                "ssl-ca": {
+                    "type": "string"
+                },
+                "ssl-cert": {
+                    "type": "string"
+                },
+                "ssl-key": {
+                    "type": "string"
+                },
+                "tidb-txn-mode": {
+                    "type": "string"
+                },
+                "time-zone": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "string"
+                },
+                "worker-count": {
+                    "type": "integer"
+                },
+                "write-timeout": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.PulsarConfig": {
+            "type": "object",
+            "properties": {
+                "admin-url": {
+                    "description": "AdminURL is the URL of the pulsar admin REST API, such as \"http://127.0.0.1:8080\",\nit's required to create the topics and to set the retention of the topics.",
+                    "type": "string"
+                },
+                "authentication-token": {
+                    "description": "AuthenticationToken is the token used to authenticate to the pulsar cluster.",
+                    "type": "string"
+                },
+                "auto-create-topic": {
+                    "description": "AutoCreateTopic creates the topics which don't exist by the admin API\nbefore sending messages to them.",
+                    "type": "boolean"
+                },
+                "basic-password": {
+                    "type": "string"
+                },
+                "basic-user-name": {
+                    "description": "BasicUserName and BasicPassword are used by the basic authentication.",
+                    "type": "string"
+                },
+                "connection-timeout": {
+                    "description": "ConnectionTimeout is the timeout to establish a TCP connection, such as \"5s\".",
+                    "type": "string"
+                },
+                "oauth2": {
+                    "$ref": "#/definitions/config.PulsarOAuth2"
+                },
+                "operation-timeout": {
+                    "description": "OperationTimeout is the timeout of the producer creation and the other\noperations, which are retried until the timeout is reached, such as \"30s\".",
+                    "type": "string"
+                },
+                "pulsar-producer-cache-size": {
+                    "description": "PulsarProducerCacheSize is the size of the cache of pulsar producers",
+                    "type": "integer"
+                },
+                "tls-certificate-path": {
+                    "type": "string"
+                },
+                "tls-private-key-path": {
+                    "type": "string"
+                },
+                "tls-trust-certs-file-path": {
+                    "type": "string"
+                },
+                "token-from-file": {
+                    "description": "TokenFromFile is the path of the file which contains the authentication token.",
+                    "type": "string"
+                },
+                "topic-partition-num": {
+                    "description": "TopicPartitionNum is the partition number of the auto-created topics,\n0 means the topics are non-partitioned.",
+                    "type": "integer"
+                },
+                "topic-retention-size-mb": {
+                    "description": "TopicRetentionSizeMB is the retention size of the auto-created topics in MB,\n-1 means infinite retention size.",
+                    "type": "integer"
+                },
+                "topic-retention-time": {
+                    "description": "TopicRetentionTime is the retention time of the auto-created topics, such as \"24h\".",
+                    "type": "string"
+                }
+            }
+        },
+        "config.PulsarOAuth2": {
+            "type": "object",
+            "properties": {
+                "oauth2-audience": {
+                    "description": "OAuth2Audience is the URL of the resource server.",
+                    "type": "string"
+                },
+                "oauth2-client-id": {
+                    "type": "string"
+                },
+                "oauth2-issuer-url": {
+                    "description": "OAuth2IssuerURL is the URL of the authorization server.",
+                    "type": "string"
+                },
+                "oauth2-private-key": {
+                    "description": "OAuth2PrivateKey is the path of the private key file of the client credentials.",
+                    "type": "string"
+                },
+                "oauth2-scope": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.RedisConfig": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "Channel is the channel which the keys are published to in the invalidate mode.",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is the key of the rows of the tables which aren't matched by the key\nrules. \"{schema}\", \"{table}\" and \"{pk}\" are replaced by the schema name,\nthe table name and the handle key values, the other placeholders such as\n\"{id}\" are replaced by the values of the columns.",
+                    "type": "string"
+                },
+                "key-rules": {
+                    "description": "KeyRules overrides the key of the matched tables, the first matched\none is used if a table matches several ones.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RedisKeyRule"
+                    }
+                },
+                "max-batch-rows": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "Mode is the way the row changes are written into Redis, the value can be\n\"snapshot\" or \"invalidate\". The rows are written as the values of their\nkeys in the snapshot mode, and the keys of the changed rows are published\nto the channel in the invalidate mode.",
+                    "type": "string"
+                },
+                "value-format": {
+                    "description": "ValueFormat is the format of the rows in the snapshot mode, the value can\nbe \"hash\" or \"json\".",
+                    "type": "string"
+                },
+                "worker-count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.RedisKeyRule": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.RedshiftConfig": {
+            "type": "object",
+            "properties": {
+                "cluster-identifier": {
+                    "description": "ClusterIdentifier is the identifier of the provisioned cluster,\nit's exclusive with WorkgroupName.",
+                    "type": "string"
+                },
+                "database": {
+                    "type": "string"
+                },
+                "db-user": {
+                    "description": "DBUser or SecretARN is used to access the database, the temporary\ncredentials of the IAM identity are used if neither is set.",
+                    "type": "string"
+                },
+                "iam-role": {
+                    "description": "IAMRole is the ARN of the role which is used by COPY to read the data files.",
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "schema": {
+                    "description": "Schema is the schema of the target tables in Redshift,\nthe upstream schema name is used if it's empty.",
+                    "type": "string"
+                },
+                "secret-arn": {
+                    "type": "string"
+                },
+                "workgroup-name": {
+                    "description": "WorkgroupName is the workgroup name of the serverless Redshift.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.SchemaRegistryConfig": {
+            "type": "object",
+            "properties": {
+                "apicurio-group-id": {
+                    "description": "ApicurioGroupID is the artifact group of the schemas in the Apicurio\nRegistry, the default is \"default\".",
+                    "type": "string"
+                },
+                "basic-auth-password": {
+                    "type": "string"
+                },
+                "basic-auth-user": {
+                    "description": "BasicAuthUser and BasicAuthPassword are used by the basic authentication.",
+                    "type": "string"
+                },
+                "bearer-token": {
+                    "description": "BearerToken is used by the bearer token authentication, it can not be\nset with the basic authentication.",
+                    "type": "string"
+                },
+                "ca": {
+                    "description": "CA, Cert and Key are the paths of the TLS files, the TLS is enabled if\nthe CA is set.",
+                    "type": "string"
+                },
+                "cert": {
+                    "type": "string"
+                },
+                "glue-region": {
+                    "type": "string"
+                },
+                "glue-registry-name": {
+                    "description": "GlueRegistryName and GlueRegion are the name and the region of the AWS\nGlue Schema Registry, the credentials of the IAM identity are used.",
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Provider is the provider of the schema registry, the value can be\n\"confluent\", \"glue\" or \"apicurio\", the default is \"confluent\".",
+                    "type": "string"
+                },
+                "subject-name-strategy": {
+                    "description": "SubjectNameStrategy is the naming strategy of the subjects, the value\ncan be \"topic\", \"record\" or \"topic-record\", the default is \"topic\".\nThe subjects of the keys always end with \"-key\" since the key and value\nrecords of a table share the same name.",
+                    "type": "string"
+                },
+                "subject-prefix": {
+                    "description": "SubjectPrefix and SubjectSuffix are the templates prepended and appended\nto the subjects, such as \"{namespace}-{changefeed}.\", so the changefeeds\nof multiple environments can share a registry.",
+                    "type": "string"
+                },
+                "subject-suffix": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.SinkConfig": {
+            "type": "object",
+            "properties": {
+                "bootstrap": {
+                    "$ref": "#/definitions/config.BootstrapConfig"
+                },
+                "canal-json-flat": {
+                    "description": "CanalJSONFlat encodes each row into a flat JSON object, whose columns are\nat the top level and metadata is under the \"_cdc\" key, instead of the nested\ndata arrays. It's only available for the canal-json protocol.",
+                    "type": "boolean"
+                },
+                "clickhouse-config": {
+                    "$ref": "#/definitions/config.ClickHouseConfig"
+                },
+                "cloud-storage-config": {
+                    "$ref": "#/definitions/config.CloudStorageConfig"
+                },
+                "column-maskers": {
+                    "description": "ColumnMaskers is available for all kinds of downstream, the matched columns\nare masked before they are written to the downstream.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ColumnMasker"
+                    }
+                },
+                "column-selectors": {
+                    "description": "ColumnSelectors is only available when the downstream is MQ using the\nopen-protocol, canal-json or avro protocol, only the selected columns of\nthe matched tables are sent to the downstream.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ColumnSelector"
+                    }
+                },
+                "computed-columns": {
+                    "description": "ComputedColumns is available for all kinds of downstream, the computed\ncolumns are appended to the rows of the matched tables before they are\nencoded. The downstream tables must have these columns if the downstream\nis a database.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ComputedColumn"
+                    }
+                },
+                "csv": {
+                    "description": "CSVConfig is only available when the downstream is Storage.",
+                    "$ref": "#/definitions/config.CSVConfig"
+                },
+                "date-separator": {
+                    "description": "DateSeparator is only available when the downstream is Storage.",
+                    "type": "string"
+                },
+                "delete-as-tombstone": {
+                    "description": "DeleteAsTombstone emits the delete events as the tombstones, whose values\nare null and keys are the handle keys, so the compacted topics represent\nthe state of the tables. It's only available for the canal-json and avro\nprotocol, and the update events changing the handle keys are split into\nthe delete and insert events.",
+                    "type": "boolean"
+                },
+                "delete-only-output-handle-key-columns": {
+                    "description": "DeleteOnlyOutputHandleKeyColumns is only available when the downstream is MQ.",
+                    "type": "boolean"
+                },
+                "dispatchers": {
+                    "description": "DispatchRules is only available when the downstream is MQ.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.DispatchRule"
+                    }
+                },
+                "elasticsearch-config": {
+                    "$ref": "#/definitions/config.ElasticsearchConfig"
+                },
+                "enable-kafka-sink-v2": {
+                    "description": "EnableKafkaSinkV2 enabled then the kafka-go sink will be used.\nIt is only available when the downstream is MQ.\nThe default of the server is used if it's not set.",
+                    "type": "boolean"
+                },
+                "enable-partition-separator": {
+                    "description": "EnablePartitionSeparator is only available when the downstream is Storage.",
+                    "type": "boolean"
+                },
+                "encoder-concurrency": {
+                    "description": "EncoderConcurrency is only available when the downstream is MQ.",
+                    "type": "integer"
+                },
+                "file-index-digit": {
+                    "description": "FileIndexWidth is only available when the downstream is Storage",
+                    "type": "integer"
+                },
+                "kafka-config": {
+                    "$ref": "#/definitions/config.KafkaConfig"
+                },
+                "message-headers": {
+                    "$ref": "#/definitions/config.MessageHeadersConfig"
+                },
+                "mirror": {
+                    "$ref": "#/definitions/config.SinkMirror"
+                },
+                "mysql-config": {
+                    "$ref": "#/definitions/config.MySQLConfig"
+                },
+                "only-output-updated-columns": {
+                    "description": "OnlyOutputUpdatedColumns is only available when the downstream is MQ.\nThe open-protocol and canal-json messages only carry the old values of\nthe updated columns. The avro messages of the update events only carry\nthe updated columns and the handle key columns, the other columns are\nnull, and the bitmap of the updated columns is carried in the\n\"_tidb_updated_columns\" field.",
+                    "type": "boolean"
+                },
+                "output-physical-time": {
+                    "description": "OutputPhysicalTime outputs the commit physical time and the ingestion time\nas explicit fields, it's only available for the open-protocol, canal-json and csv.",
+                    "type": "boolean"
+                },
+                "partition-layout": {
+                    "description": "PartitionLayout is only available when the downstream is Storage,\nthe value can be \"default\" or \"hive\".",
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "Protocol is NOT available when the downstream is DB.",
+                    "type": "string"
+                },
+                "pulsar-config": {
+                    "$ref": "#/definitions/config.PulsarConfig"
+                },
+                "redis-config": {
+                    "$ref": "#/definitions/config.RedisConfig"
+                },
+                "safe-mode": {
+                    "description": "SafeMode is only available when the downstream is DB.",
+                    "type": "boolean"
+                },
+                "schema-registry": {
+                    "description": "SchemaRegistry is only available when the downstream is MQ using avro protocol.",
+                    "type": "string"
+                },
+                "schema-registry-config": {
+                    "$ref": "#/definitions/config.SchemaRegistryConfig"
+                },
+                "table-metrics": {
+                    "$ref": "#/definitions/config.TableMetricsConfig"
+                },
+                "table-routes": {
+                    "description": "TableRoutes rename the matched tables and their columns in the\ndownstream, including the tables of the databases and the topics and the\npaths of the MQ and storage sinks. The sink targets match the upstream\nnames, while the dispatchers and the column selectors match the\ndownstream names. A table is renamed by the first route matching it.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.TableRoute"
+                    }
+                },
+                "table-sink-buffer": {
+                    "$ref": "#/definitions/config.TableSinkBufferConfig"
+                },
+                "targets": {
+                    "description": "Targets route the events of the matched tables to other downstreams than\nthe sink-uri, all of them share the puller and the sorter of the\nchangefeed. A table is written to the first target matching it, and the\ntables matched by no target are written to the sink-uri.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.SinkTarget"
+                    }
+                },
+                "temporal-encoding": {
+                    "$ref": "#/definitions/config.TemporalEncodingConfig"
+                },
+                "terminator": {
+                    "description": "Terminator is NOT available when the downstream is DB.",
+                    "type": "string"
+                },
+                "transaction-atomicity": {
+                    "type": "string"
+                },
+                "transformer": {
+                    "$ref": "#/definitions/config.TransformerConfig"
+                },
+                "type-mappings": {
+                    "description": "TypeMappings overrides how the columns of the TiDB types are encoded, such\nas encoding BIT as the integers. It's only available for the avro and\njson-schema protocols.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.TypeMapping"
+                    }
+                },
+                "webhook-config": {
+                    "$ref": "#/definitions/config.WebhookConfig"
+                }
+            }
+        },
+        "config.SinkMirror": {
+            "type": "object",
+            "properties": {
+                "enable-kafka-sink-v2": {
+                    "description": "EnableKafkaSinkV2 overrides the one of the changefeed, so that the\nchangefeed can be migrated from the sarama sink to the kafka-go sink.",
+                    "type": "boolean"
+                },
+                "protocol": {
+                    "description": "Protocol is the protocol of the mirror, the protocol in the sink-uri of\nthe mirror or the protocol of the changefeed is used if it's empty.",
+                    "type": "string"
+                },
+                "sink-uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.SinkTarget": {
+            "type": "object",
+            "properties": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "protocol": {
+                    "description": "Protocol is the protocol of the target, the protocol in the sink-uri of\nthe target or the protocol of the changefeed is used if it's empty.",
+                    "type": "string"
+                },
+                "sink-uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.TableMetricsConfig": {
+            "type": "object",
+            "properties": {
+                "enable": {
+                    "type": "boolean"
+                },
+                "max-tables": {
+                    "description": "MaxTables is the max number of the tables labeled by their names, the\nother tables share a label so the cardinality of the metrics is bounded.",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TableRoute": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "Columns rename the columns of the matched tables, the column names are\ncase-insensitive.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ColumnRoute"
+                    }
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source-columns": {
+                    "description": "SourceColumns appends the _source_schema and _source_table columns with\nthe upstream names to the rows of the matched tables. They're added to\nthe downstream tables and their primary and unique keys by the CREATE\nTABLE DDLs, so the rows of the shards with the same keys don't conflict\nin the merged table.",
+                    "type": "boolean"
+                },
+                "target-schema": {
+                    "description": "TargetSchema is the schema of the tables in the downstream, the upstream\nschema is kept if it's empty.",
+                    "type": "string"
+                },
+                "target-table": {
+                    "description": "TargetTable is the name of the tables in the downstream, the upstream\nname is kept if it's empty.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.TableSinkBufferConfig": {
+            "type": "object",
+            "properties": {
+                "disk-quota": {
+                    "description": "DiskQuota is the max size in bytes of the events moved to the disk of\nall tables if the policy is \"disk\", unset means unlimited.",
+                    "type": "integer"
+                },
+                "policy": {
+                    "description": "Policy is the policy if the quota is exceeded, the value can be\n\"block\", \"spill\" or \"disk\", the default is \"block\".",
+                    "type": "string"
+                },
+                "quota": {
+                    "description": "Quota is the max size in bytes of the buffered events of all tables.",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TemporalEncodingConfig": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode can be \"naive-local\", \"epoch-millis\" or \"rfc3339\", the default is\n\"naive-local\".",
+                    "type": "string"
+                },
+                "protocols": {
+                    "description": "Protocols are the protocols the encoding applies to, it applies to all\nthe supported protocols if it's empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source-time-zone": {
+                    "description": "SourceTimeZone is the timezone the DATETIME values are written in, the\ndefault is the timezone of the TiCDC server.",
+                    "type": "string"
+                },
+                "time-zone": {
+                    "description": "TimeZone is the timezone of the \"naive-local\" and \"rfc3339\" values, the\ndefault is the timezone of the TiCDC server.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.TransformerConfig": {
+            "type": "object",
+            "properties": {
+                "matcher": {
+                    "description": "Matcher matches the tables whose rows are transformed, the rows of all\ntables are transformed if it's empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wasm-file": {
+                    "description": "WASMFile is the path of the WASM module on the TiCDC servers.",
+                    "type": "string"
+                }
+            }
+        },
+        "config.TypeMapping": {
+            "type": "object",
+            "properties": {
+                "protocols": {
+                    "description": "Protocols are the protocols the mapping applies to, it applies to all\nthe supported protocols if it's empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "description": "Source is the TiDB type, the value can be \"bit\", \"tinyint(1)\" or \"json\".",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Target is the encoded type, the value can be \"bytes\" or \"int\" for \"bit\",\n\"int\" or \"bool\" for \"tinyint(1)\", and \"string\" for \"json\".",
+                    "type": "string"
+                }
+            }
+        },
+        "config.WebhookConfig": {
+            "type": "object",
+            "properties": {
+                "headers": {
+                    "description": "Headers are attached to the requests, such as the authorization header.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max-batch-rows": {
+                    "type": "integer"
+                },
+                "max-retries": {
+                    "description": "MaxRetries is the max number of retries of a failed request, the\nrequests are retried with the exponential backoff between RetryBackoff\nand MaxRetryBackoff.",
+                    "type": "integer"
+                },
+                "max-retry-backoff": {
+                    "type": "string"
+                },
+                "retry-backoff": {
+                    "type": "string"
+                },
+                "worker-count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Capture": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_owner": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.CaptureTaskStatus": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "table_ids": {
+                    "description": "Table list, containing tables that processor should process",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "table_operations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.TableOperation"
+                    }
+                }
+            }
+        },
+        "model.ChangefeedCommonInfo": {
+            "type": "object",
+            "properties": {
+                "checkpoint_time": {
+                    "type": "string"
+                },
+                "checkpoint_tso": {
+                    "type": "integer"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.RunningError"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "upstream_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChangefeedConfig": {
+            "type": "object",
+            "properties": {
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "filter_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "force_replicate": {
+                    "description": "if true, force to replicate some ineligible tables",
+                    "type": "boolean",
+                    "default": false
+                },
+                "ignore_ineligible_table": {
+                    "type": "boolean",
+                    "default": false
+                },
+                "ignore_txn_start_ts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "mounter_worker_num": {
+                    "type": "integer",
+                    "default": 16
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "sink_config": {
+                    "$ref": "#/definitions/config.SinkConfig"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "start_ts": {
+                    "type": "integer"
+                },
+                "target_ts": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "timezone used when checking sink uri",
+                    "type": "string",
+                    "default": "system"
+                }
+            }
+        },
+        "model.ChangefeedDetail": {
+            "type": "object",
+            "properties": {
+                "checkpoint_time": {
+                    "type": "string"
+                },
+                "checkpoint_tso": {
+                    "type": "integer"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "creator_version": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.RunningError"
+                },
+                "error_history": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "resolved_ts": {
+                    "type": "integer"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "sort_engine": {
+                    "type": "string"
+                },
+                "start_ts": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "target_ts": {
+                    "type": "integer"
+                },
+                "task_status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CaptureTaskStatus"
+                    }
+                },
+                "upstream_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.HTTPError": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "string"
+                },
+                "error_msg": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MoveTableReq": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProcessorCommonInfo": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProcessorDetail": {
+            "type": "object",
+            "properties": {
+                "table_ids": {
+                    "description": "All table ids that this processor are replicating.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.RunningError": {
+            "type": "object",
+            "properties": {
+                "addr": {
+                    "type": "string"
+                },
+                "class": {
+                    "description": "Class is the class of the error if it comes from the sink.",
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ServerStatus": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "type": "string"
+                },
+                "git_hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_owner": {
+                    "type": "boolean"
+                },
+                "liveness": {
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TableOperation": {
+            "type": "object",
+            "properties": {
+                "boundary_ts": {
+                    "description": "if the operation is a delete operation, BoundaryTs is checkpoint ts\nif the operation is an add operation, BoundaryTs is start ts",
+                    "type": "integer"
+                },
+                "delete": {
+                    "type": "boolean"
+                },
+                "flag": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.BootstrapConfig": {
+            "type": "object",
+            "properties": {
+                "enable": {
+                    "type": "boolean"
+                },
+                "snapshot_marker": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v2.CSVColumnRule": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v2.CSVConfig": {
+            "type": "object",
+            "properties": {
+                "binary_encoding_method": {
+                    "type": "string"
+                },
+                "column_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.CSVColumnRule"
+                    }
+                },
+                "delimiter": {
+                    "type": "string"
+                },
+                "escape_char": {
+                    "type": "string"
+                },
+                "include_commit_ts": {
+                    "type": "boolean"
+                },
+                "include_header": {
+                    "type": "string"
+                },
+                "null": {
+                    "type": "string"
+                },
+                "output_schema_sidecar": {
+                    "type": "boolean"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "quoting_policy": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.Capture": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_owner": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v2.ChangeFeedInfo": {
+            "type": "object",
+            "properties": {
+                "admin_job_type": {
+                    "description": "used for admin job notification, trigger watch event in capture",
+                    "type": "integer"
+                },
+                "checkpoint_time": {
+                    "type": "string"
+                },
+                "checkpoint_ts": {
+                    "type": "integer"
+                },
+                "config": {
+                    "$ref": "#/definitions/v2.ReplicaConfig"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "creator_version": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/v2.RunningError"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "paused_tables": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "resolved_ts": {
+                    "type": "integer"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "start_ts": {
+                    "description": "Start sync at this commit ts if ` + "`" + `StartTs` + "`" + ` is specify or using the CreateTime of changefeed.",
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "target_ts": {
+                    "description": "The ChangeFeed will exits until sync to timestamp TargetTs",
+                    "type": "integer"
+                },
+                "task_status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CaptureTaskStatus"
+                    }
+                },
+                "upstream_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.ChangefeedCommonInfo": {
+            "type": "object",
+            "properties": {
+                "checkpoint_time": {
+                    "type": "string"
+                },
+                "checkpoint_tso": {
+                    "type": "integer"
+                },
+                "error": {
+                    "$ref": "#/definitions/model.RunningError"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "upstream_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.ChangefeedConfig": {
+            "type": "object",
+            "properties": {
+                "ca_path": {
+                    "type": "string"
+                },
+                "cert_allowed_cn": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cert_path": {
+                    "type": "string"
+                },
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "key_path": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pd_addrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "output-column-id": {
-                    "type": "boolean"
+                "replica_config": {
+                    "$ref": "#/definitions/v2.ReplicaConfig"
                 },
-                "worker-count": {
+                "savepoint": {
+                    "description": "Savepoint is the name of the savepoint which the changefeed is created\nfrom, the changefeed starts from the checkpoint of the savepoint.",
+                    "type": "string"
+                },
+                "sink_uri": {
+                    "type": "string"
+                },
+                "start_ts": {
+                    "type": "integer"
+                },
+                "target_ts": {
                     "type": "integer"
                 }
             }
         },
-        "config.CodecConfig": {
+        "v2.ChangefeedCredentials": {
             "type": "object",
             "properties": {
-                "avro-bigint-unsigned-handling-mode": {
+                "access_key": {
                     "type": "string"
                 },
-                "avro-decimal-handling-mode": {
+                "password": {
                     "type": "string"
                 },
-                "avro-enable-watermark": {
-                    "type": "boolean"
+                "secret_access_key": {
+                    "type": "string"
                 },
-                "enable-tidb-extension": {
-                    "type": "boolean"
+                "session_token": {
+                    "type": "string"
                 },
-                "max-batch-size": {
-                    "type": "integer"
+                "user": {
+                    "type": "string"
                 }
             }
         },
-        "config.ColumnSelector": {
+        "v2.ChangefeedSchedulerConfig": {
             "type": "object",
             "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "enable_table_across_nodes": {
+                    "description": "EnableTableAcrossNodes set true to split one table to multiple spans and\ndistribute to multiple TiCDC nodes.",
+                    "type": "boolean"
                 },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "region_threshold": {
+                    "description": "RegionThreshold is the region count threshold of splitting a table.",
+                    "type": "integer"
+                },
+                "write_key_threshold": {
+                    "description": "WriteKeyThreshold is the written keys threshold of splitting a table.",
+                    "type": "integer"
                 }
             }
         },
-        "config.DispatchRule": {
+        "v2.ClickHouseConfig": {
             "type": "object",
             "properties": {
-                "dispatcher": {
-                    "description": "Deprecated, please use PartitionRule.",
+                "engine": {
                     "type": "string"
                 },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "max_batch_rows": {
+                    "type": "integer"
                 },
-                "partition": {
-                    "description": "PartitionRule is an alias added for DispatcherRule to mitigate confusions.\nIn the future release, the DispatcherRule is expected to be removed .",
+                "sign_column": {
                     "type": "string"
                 },
-                "topic": {
+                "version_column": {
                     "type": "string"
+                },
+                "worker_count": {
+                    "type": "integer"
                 }
             }
         },
-        "config.KafkaConfig": {
+        "v2.CloudStorageConfig": {
             "type": "object",
             "properties": {
-                "auto-create-topic": {
-                    "type": "boolean"
-                },
-                "ca": {
-                    "type": "string"
-                },
-                "cert": {
+                "compression": {
                     "type": "string"
                 },
-                "codec-config": {
-                    "$ref": "#/definitions/config.CodecConfig"
+                "enable_delta_lake": {
+                    "type": "boolean"
                 },
-                "compression": {
+                "file_name_template": {
                     "type": "string"
                 },
-                "dial-timeout": {
+                "file_size": {
+                    "type": "integer"
+                },
+                "flush_interval": {
                     "type": "string"
                 },
-                "enable-tls": {
+                "output_column_id": {
                     "type": "boolean"
                 },
-                "insecure-skip-verify": {
+                "output_complete_marker": {
                     "type": "boolean"
                 },
-                "kafka-client-id": {
-                    "type": "string"
-                },
-                "kafka-version": {
-                    "type": "string"
+                "output_manifest": {
+                    "type": "boolean"
                 },
-                "key": {
+                "parquet_compression": {
                     "type": "string"
                 },
-                "large-message-handle": {
-                    "$ref": "#/definitions/config.LargeMessageHandleConfig"
-                },
-                "max-message-bytes": {
+                "parquet_row_group_size": {
                     "type": "integer"
                 },
-                "partition-num": {
-                    "type": "integer"
+                "redshift_config": {
+                    "$ref": "#/definitions/v2.RedshiftConfig"
                 },
-                "read-timeout": {
-                    "type": "string"
+                "table_overrides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.CloudStorageTableOverride"
+                    }
                 },
-                "replication-factor": {
+                "worker_count": {
                     "type": "integer"
-                },
-                "required-acks": {
+                }
+            }
+        },
+        "v2.CloudStorageTableOverride": {
+            "type": "object",
+            "properties": {
+                "file_size": {
                     "type": "integer"
                 },
-                "sasl-gssapi-auth-type": {
-                    "type": "string"
-                },
-                "sasl-gssapi-disable-pafxfast": {
-                    "type": "boolean"
-                },
-                "sasl-gssapi-kerberos-config-path": {
-                    "type": "string"
-                },
-                "sasl-gssapi-keytab-path": {
-                    "type": "string"
-                },
-                "sasl-gssapi-password": {
+                "flush_interval": {
                     "type": "string"
                 },
-                "sasl-gssapi-realm": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v2.CodecConfig": {
+            "type": "object",
+            "properties": {
+                "avro_bigint_unsigned_handling_mode": {
                     "type": "string"
                 },
-                "sasl-gssapi-service-name": {
+                "avro_decimal_handling_mode": {
                     "type": "string"
                 },
-                "sasl-gssapi-user": {
-                    "type": "string"
+                "avro_enable_watermark": {
+                    "type": "boolean"
                 },
-                "sasl-mechanism": {
-                    "type": "string"
+                "enable_tidb_extension": {
+                    "type": "boolean"
                 },
-                "sasl-oauth-audience": {
-                    "type": "string"
+                "max_batch_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.ColumnMasker": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "sasl-oauth-client-id": {
-                    "type": "string"
+                "keep_prefix": {
+                    "type": "integer"
                 },
-                "sasl-oauth-client-secret": {
-                    "type": "string"
+                "keep_suffix": {
+                    "type": "integer"
                 },
-                "sasl-oauth-grant-type": {
+                "mask": {
                     "type": "string"
                 },
-                "sasl-oauth-scopes": {
+                "matcher": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "sasl-oauth-token-url": {
+                "value": {
                     "type": "string"
-                },
-                "sasl-password": {
+                }
+            }
+        },
+        "v2.ColumnRoute": {
+            "type": "object",
+            "properties": {
+                "source": {
                     "type": "string"
                 },
-                "sasl-user": {
+                "target": {
                     "type": "string"
+                }
+            }
+        },
+        "v2.ColumnSelector": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "write-timeout": {
-                    "type": "string"
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "config.LargeMessageHandleConfig": {
+        "v2.ComputedColumn": {
             "type": "object",
             "properties": {
-                "claim-check-compression": {
-                    "type": "string"
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "claim-check-storage-uri": {
+                "name": {
                     "type": "string"
                 },
-                "large-message-handle-option": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "config.MySQLConfig": {
+        "v2.ConsistentConfig": {
             "type": "object",
             "properties": {
-                "enable-batch-dml": {
-                    "type": "boolean"
+                "compression": {
+                    "type": "string"
                 },
-                "enable-cache-prepared-statement": {
-                    "type": "boolean"
+                "encryption_key": {
+                    "type": "string"
                 },
-                "enable-multi-statement": {
-                    "type": "boolean"
+                "flush_concurrency": {
+                    "type": "integer"
                 },
-                "max-multi-update-row": {
+                "flush_interval": {
                     "type": "integer"
                 },
-                "max-multi-update-row-size": {
+                "flush_worker_num": {
                     "type": "integer"
                 },
-                "max-txn-row": {
+                "level": {
+                    "type": "string"
+                },
+                "max_log_size": {
                     "type": "integer"
                 },
-                "read-timeout": {
+                "storage": {
                     "type": "string"
                 },
-                "ssl-ca": {
+                "use_file_backend": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v2.DDLTranslationConfig": {
+            "type": "object",
+            "properties": {
+                "dialect": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.DDLTranslationRule"
+                    }
+                }
+            }
+        },
+        "v2.DDLTranslationRule": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "type": "string"
+                },
+                "replacement": {
                     "type": "string"
                 },
-                "ssl-cert": {
+                "skip": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v2.DispatchRule": {
+            "type": "object",
+            "properties": {
+                "key_template": {
                     "type": "string"
                 },
-                "ssl-key": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "partition": {
                     "type": "string"
                 },
-                "tidb-txn-mode": {
+                "partition_plugin": {
                     "type": "string"
                 },
-                "time-zone": {
+                "topic": {
                     "type": "string"
                 },
-                "timeout": {
+                "topic_configs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "topic_partition_num": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.ElasticsearchConfig": {
+            "type": "object",
+            "properties": {
+                "index": {
                     "type": "string"
                 },
-                "worker-count": {
+                "index_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ElasticsearchIndexRule"
+                    }
+                },
+                "max_batch_rows": {
                     "type": "integer"
                 },
-                "write-timeout": {
+                "worker_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.ElasticsearchIndexRule": {
+            "type": "object",
+            "properties": {
+                "index": {
                     "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "config.PulsarConfig": {
+        "v2.EmptyResponse": {
+            "type": "object"
+        },
+        "v2.EventFilterRule": {
             "type": "object",
             "properties": {
-                "pulsar-producer-cache-size": {
-                    "description": "PulsarProducerCacheSize is the size of the cache of pulsar producers",
-                    "type": "integer"
+                "ignore_delete_value_expr": {
+                    "type": "string"
                 },
-                "tls-certificate-path": {
+                "ignore_event": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ignore_insert_value_expr": {
+                    "description": "sql expression",
                     "type": "string"
                 },
-                "tls-private-key-path": {
+                "ignore_sql": {
+                    "description": "regular expression",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ignore_update_new_value_expr": {
                     "type": "string"
                 },
-                "tls-trust-certs-file-path": {
+                "ignore_update_old_value_expr": {
                     "type": "string"
+                },
+                "keep_value_expr": {
+                    "type": "string"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "config.SinkConfig": {
+        "v2.FilterConfig": {
             "type": "object",
             "properties": {
-                "cloud-storage-config": {
-                    "$ref": "#/definitions/config.CloudStorageConfig"
-                },
-                "column-selectors": {
-                    "description": "ColumnSelectors is Deprecated.",
+                "ddl_allow_list": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/config.ColumnSelector"
+                        "type": "string"
                     }
                 },
-                "csv": {
-                    "description": "CSVConfig is only available when the downstream is Storage.",
-                    "$ref": "#/definitions/config.CSVConfig"
+                "ddl_deny_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "date-separator": {
-                    "description": "DateSeparator is only available when the downstream is Storage.",
-                    "type": "string"
+                "do_dbs": {
+                    "description": "DoDBs is an allowlist of schemas.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "delete-only-output-handle-key-columns": {
-                    "description": "DeleteOnlyOutputHandleKeyColumns is only available when the downstream is MQ.",
-                    "type": "boolean"
+                "do_tables": {
+                    "description": "DoTables is an allowlist of tables.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Table"
+                    }
                 },
-                "dispatchers": {
-                    "description": "DispatchRules is only available when the downstream is MQ.",
+                "event_filters": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/config.DispatchRule"
+                        "$ref": "#/definitions/v2.EventFilterRule"
                     }
                 },
-                "enable-kafka-sink-v2": {
-                    "description": "EnableKafkaSinkV2 enabled then the kafka-go sink will be used.\nIt is only available when the downstream is MQ.",
-                    "type": "boolean"
+                "ignore_dbs": {
+                    "description": "IgnoreDBs is a blocklist of schemas.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "enable-partition-separator": {
-                    "description": "EnablePartitionSeparator is only available when the downstream is Storage.",
+                "ignore_local_origin": {
                     "type": "boolean"
                 },
-                "encoder-concurrency": {
-                    "description": "EncoderConcurrency is only available when the downstream is MQ.",
-                    "type": "integer"
+                "ignore_tables": {
+                    "description": "IgnoreTables is a blocklist of tables.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.Table"
+                    }
                 },
-                "file-index-digit": {
-                    "description": "FileIndexWidth is only available when the downstream is Storage",
-                    "type": "integer"
+                "ignore_txn_start_ts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "kafka-config": {
-                    "$ref": "#/definitions/config.KafkaConfig"
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "mysql-config": {
-                    "$ref": "#/definitions/config.MySQLConfig"
+                "sampling_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "v2.IntegrityConfig": {
+            "type": "object",
+            "properties": {
+                "corruption_handle_level": {
+                    "type": "string"
                 },
-                "only-output-updated-columns": {
-                    "description": "OnlyOutputUpdatedColumns is only available when the downstream is MQ.",
+                "integrity_check_level": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.KafkaConfig": {
+            "type": "object",
+            "properties": {
+                "auto_create_topic": {
                     "type": "boolean"
                 },
-                "protocol": {
-                    "description": "Protocol is NOT available when the downstream is DB.",
+                "batch_bytes": {
+                    "type": "integer"
+                },
+                "ca": {
                     "type": "string"
                 },
-                "pulsar-config": {
-                    "$ref": "#/definitions/config.PulsarConfig"
+                "cert": {
+                    "type": "string"
                 },
-                "safe-mode": {
-                    "description": "SafeMode is only available when the downstream is DB.",
-                    "type": "boolean"
+                "codec_config": {
+                    "$ref": "#/definitions/v2.CodecConfig"
                 },
-                "schema-registry": {
-                    "description": "SchemaRegistry is only available when the downstream is MQ using avro protocol.",
+                "compression": {
                     "type": "string"
                 },
-                "terminator": {
-                    "description": "Terminator is NOT available when the downstream is DB.",
-                    "type": "string"
+                "compression_level": {
+                    "type": "integer"
                 },
-                "transaction-atomicity": {
-                    "type": "string"
-                }
-            }
-        },
-        "model.Capture": {
-            "type": "object",
-            "properties": {
-                "address": {
+                "dead_letter_storage_uri": {
                     "type": "string"
                 },
-                "cluster_id": {
+                "dead_letter_topic": {
                     "type": "string"
                 },
-                "id": {
+                "ddl_topic": {
                     "type": "string"
                 },
-                "is_owner": {
+                "delete_topics_on_remove": {
                     "type": "boolean"
-                }
-            }
-        },
-        "model.CaptureTaskStatus": {
-            "type": "object",
-            "properties": {
-                "capture_id": {
+                },
+                "dial_timeout": {
                     "type": "string"
                 },
-                "table_ids": {
-                    "description": "Table list, containing tables that processor should process",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "dns_srv_discovery": {
+                    "type": "boolean"
                 },
-                "table_operations": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/model.TableOperation"
-                    }
-                }
-            }
-        },
-        "model.ChangefeedCommonInfo": {
-            "type": "object",
-            "properties": {
-                "checkpoint_time": {
+                "enable_kafka_transactions": {
+                    "type": "boolean"
+                },
+                "enable_tls": {
+                    "type": "boolean"
+                },
+                "event_hubs": {
+                    "type": "boolean"
+                },
+                "event_hubs_connection_string": {
                     "type": "string"
                 },
-                "checkpoint_tso": {
-                    "type": "integer"
+                "insecure_skip_verify": {
+                    "type": "boolean"
                 },
-                "error": {
-                    "$ref": "#/definitions/model.RunningError"
+                "kafka_client_id": {
+                    "type": "string"
                 },
-                "id": {
+                "kafka_version": {
                     "type": "string"
                 },
-                "namespace": {
+                "key": {
                     "type": "string"
                 },
-                "state": {
+                "large_message_handle": {
+                    "$ref": "#/definitions/v2.LargeMessageHandleConfig"
+                },
+                "linger": {
                     "type": "string"
                 },
-                "upstream_id": {
+                "max_inflight": {
                     "type": "integer"
-                }
-            }
-        },
-        "model.ChangefeedConfig": {
-            "type": "object",
-            "properties": {
-                "changefeed_id": {
-                    "type": "string"
                 },
-                "filter_rules": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "max_message_bytes": {
+                    "type": "integer"
                 },
-                "force_replicate": {
-                    "description": "if true, force to replicate some ineligible tables",
-                    "type": "boolean",
-                    "default": false
+                "partition_num": {
+                    "type": "integer"
                 },
-                "ignore_ineligible_table": {
-                    "type": "boolean",
-                    "default": false
+                "read_timeout": {
+                    "type": "string"
                 },
-                "ignore_txn_start_ts": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "replication_factor": {
+                    "type": "integer"
                 },
-                "mounter_worker_num": {
-                    "type": "integer",
-                    "default": 16
+                "required_acks": {
+                    "type": "integer"
                 },
-                "namespace": {
+                "sasl_aws_region": {
                     "type": "string"
                 },
-                "sink_config": {
-                    "$ref": "#/definitions/config.SinkConfig"
+                "sasl_aws_role_arn": {
+                    "type": "string"
                 },
-                "sink_uri": {
+                "sasl_aws_role_session_name": {
                     "type": "string"
                 },
-                "start_ts": {
-                    "type": "integer"
+                "sasl_gssapi_auth_type": {
+                    "type": "string"
                 },
-                "target_ts": {
-                    "type": "integer"
+                "sasl_gssapi_disable_pafxfast": {
+                    "type": "boolean"
                 },
-                "timezone": {
-                    "description": "timezone used when checking sink uri",
-                    "type": "string",
-                    "default": "system"
-                }
-            }
-        },
-        "model.ChangefeedDetail": {
-            "type": "object",
-            "properties": {
-                "checkpoint_time": {
+                "sasl_gssapi_kerberos_config_path": {
                     "type": "string"
                 },
-                "checkpoint_tso": {
-                    "type": "integer"
-                },
-                "create_time": {
+                "sasl_gssapi_keytab_path": {
                     "type": "string"
                 },
-                "creator_version": {
+                "sasl_gssapi_password": {
                     "type": "string"
                 },
-                "error": {
-                    "$ref": "#/definitions/model.RunningError"
+                "sasl_gssapi_realm": {
+                    "type": "string"
                 },
-                "error_history": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "sasl_gssapi_service_name": {
+                    "type": "string"
                 },
-                "id": {
+                "sasl_gssapi_user": {
                     "type": "string"
                 },
-                "namespace": {
+                "sasl_mechanism": {
                     "type": "string"
                 },
-                "resolved_ts": {
-                    "type": "integer"
+                "sasl_oauth_assertion_key": {
+                    "type": "string"
                 },
-                "sink_uri": {
+                "sasl_oauth_assertion_key_id": {
                     "type": "string"
                 },
-                "sort_engine": {
+                "sasl_oauth_audience": {
                     "type": "string"
                 },
-                "start_ts": {
-                    "type": "integer"
+                "sasl_oauth_client_id": {
+                    "type": "string"
                 },
-                "state": {
+                "sasl_oauth_client_secret": {
                     "type": "string"
                 },
-                "target_ts": {
-                    "type": "integer"
+                "sasl_oauth_grant_type": {
+                    "type": "string"
                 },
-                "task_status": {
+                "sasl_oauth_scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.CaptureTaskStatus"
+                        "type": "string"
                     }
                 },
-                "upstream_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "model.HTTPError": {
-            "type": "object",
-            "properties": {
-                "error_code": {
+                "sasl_oauth_token_url": {
                     "type": "string"
                 },
-                "error_msg": {
+                "sasl_password": {
                     "type": "string"
-                }
-            }
-        },
-        "model.MoveTableReq": {
-            "type": "object",
-            "properties": {
-                "capture_id": {
+                },
+                "sasl_user": {
                     "type": "string"
                 },
-                "table_id": {
-                    "type": "integer"
+                "topic_configs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "write_timeout": {
+                    "type": "string"
                 }
             }
         },
-        "model.ProcessorCommonInfo": {
+        "v2.LargeMessageHandleConfig": {
             "type": "object",
             "properties": {
-                "capture_id": {
-                    "type": "string"
+                "claim_check_bare_message": {
+                    "type": "boolean"
                 },
-                "changefeed_id": {
+                "claim_check_compression": {
                     "type": "string"
                 },
-                "namespace": {
+                "claim_check_presigned_url_expiry": {
                     "type": "string"
-                }
-            }
-        },
-        "model.ProcessorDetail": {
-            "type": "object",
-            "properties": {
-                "table_ids": {
-                    "description": "All table ids that this processor are replicating.",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                }
-            }
-        },
-        "model.RunningError": {
-            "type": "object",
-            "properties": {
-                "addr": {
+                },
+                "claim_check_retention": {
                     "type": "string"
                 },
-                "code": {
+                "claim_check_storage_uri": {
                     "type": "string"
                 },
-                "message": {
+                "large_message_compression": {
                     "type": "string"
                 },
-                "time": {
+                "large_message_handle_option": {
                     "type": "string"
                 }
             }
         },
-        "model.ServerStatus": {
+        "v2.LogLevelReq": {
             "type": "object",
             "properties": {
-                "cluster_id": {
-                    "type": "string"
-                },
-                "git_hash": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_owner": {
-                    "type": "boolean"
-                },
-                "liveness": {
-                    "type": "integer"
-                },
-                "pid": {
-                    "type": "integer"
-                },
-                "version": {
+                "log_level": {
                     "type": "string"
                 }
             }
         },
-        "model.TableOperation": {
+        "v2.MessageHeadersConfig": {
             "type": "object",
             "properties": {
-                "boundary_ts": {
-                    "description": "if the operation is a delete operation, BoundaryTs is checkpoint ts\nif the operation is an add operation, BoundaryTs is start ts",
-                    "type": "integer"
-                },
-                "delete": {
+                "enable_metadata": {
                     "type": "boolean"
                 },
-                "flag": {
-                    "type": "integer"
-                },
-                "status": {
-                    "type": "integer"
+                "static_headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "v2.CSVConfig": {
+        "v2.MounterConfig": {
             "type": "object",
             "properties": {
-                "binary_encoding_method": {
-                    "type": "string"
-                },
-                "delimiter": {
-                    "type": "string"
-                },
-                "include_commit_ts": {
-                    "type": "boolean"
-                },
-                "null": {
-                    "type": "string"
-                },
-                "quote": {
-                    "type": "string"
+                "worker_num": {
+                    "type": "integer"
                 }
             }
         },
-        "v2.Capture": {
+        "v2.MySQLConfig": {
             "type": "object",
             "properties": {
-                "address": {
+                "conflict_strategy": {
                     "type": "string"
                 },
-                "cluster_id": {
+                "conflict_timestamp_column": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "ddl_translation": {
+                    "$ref": "#/definitions/v2.DDLTranslationConfig"
                 },
-                "is_owner": {
+                "dml_max_open_conns": {
+                    "type": "integer"
+                },
+                "enable_async_ddl": {
                     "type": "boolean"
-                }
-            }
-        },
-        "v2.ChangeFeedInfo": {
-            "type": "object",
-            "properties": {
-                "admin_job_type": {
-                    "description": "used for admin job notification, trigger watch event in capture",
+                },
+                "enable_batch_dml": {
+                    "type": "boolean"
+                },
+                "enable_batch_upsert": {
+                    "type": "boolean"
+                },
+                "enable_cache_prepared_statement": {
+                    "type": "boolean"
+                },
+                "enable_multi_statement": {
+                    "type": "boolean"
+                },
+                "max_multi_update_row_count": {
                     "type": "integer"
                 },
-                "checkpoint_time": {
-                    "type": "string"
+                "max_multi_update_row_size": {
+                    "type": "integer"
                 },
-                "checkpoint_ts": {
+                "max_txn_row": {
                     "type": "integer"
                 },
-                "config": {
-                    "$ref": "#/definitions/v2.ReplicaConfig"
+                "meta_max_open_conns": {
+                    "type": "integer"
                 },
-                "create_time": {
+                "pool_health_check_interval": {
                     "type": "string"
                 },
-                "creator_version": {
+                "read_timeout": {
                     "type": "string"
                 },
-                "error": {
-                    "$ref": "#/definitions/v2.RunningError"
+                "ssl_ca": {
+                    "type": "string"
                 },
-                "id": {
+                "ssl_cert": {
                     "type": "string"
                 },
-                "namespace": {
+                "ssl_key": {
                     "type": "string"
                 },
-                "resolved_ts": {
-                    "type": "integer"
+                "tidb_txn_mode": {
+                    "type": "string"
                 },
-                "sink_uri": {
+                "time_zone": {
                     "type": "string"
                 },
-                "start_ts": {
-                    "description": "Start sync at this commit ts if ` + "`" + `StartTs` + "`" + ` is specify or using the CreateTime of changefeed.",
+                "timeout": {
+                    "type": "string"
+                },
+                "worker_count": {
                     "type": "integer"
                 },
-                "state": {
+                "write_timeout": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.ProcessorCommonInfo": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
                     "type": "string"
                 },
-                "target_ts": {
-                    "description": "The ChangeFeed will exits until sync to timestamp TargetTs",
-                    "type": "integer"
+                "changefeed_id": {
+                    "type": "string"
                 },
-                "task_status": {
+                "namespace": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.ProcessorDetail": {
+            "type": "object",
+            "properties": {
+                "table_ids": {
+                    "description": "All table ids that this processor are replicating.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.CaptureTaskStatus"
+                        "type": "integer"
                     }
-                },
-                "upstream_id": {
-                    "type": "integer"
                 }
             }
         },
-        "v2.ChangefeedCommonInfo": {
+        "v2.RedisConfig": {
             "type": "object",
             "properties": {
-                "checkpoint_time": {
+                "channel": {
                     "type": "string"
                 },
-                "checkpoint_tso": {
-                    "type": "integer"
+                "key": {
+                    "type": "string"
                 },
-                "error": {
-                    "$ref": "#/definitions/model.RunningError"
+                "key_rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.RedisKeyRule"
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "max_batch_rows": {
+                    "type": "integer"
                 },
-                "namespace": {
+                "mode": {
                     "type": "string"
                 },
-                "state": {
+                "value_format": {
                     "type": "string"
                 },
-                "upstream_id": {
+                "worker_count": {
                     "type": "integer"
                 }
             }
         },
-        "v2.ChangefeedConfig": {
+        "v2.RedisKeyRule": {
             "type": "object",
             "properties": {
-                "ca_path": {
+                "key": {
                     "type": "string"
                 },
-                "cert_allowed_cn": {
+                "matcher": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                },
-                "cert_path": {
+                }
+            }
+        },
+        "v2.RedshiftConfig": {
+            "type": "object",
+            "properties": {
+                "cluster_identifier": {
                     "type": "string"
                 },
-                "changefeed_id": {
+                "database": {
                     "type": "string"
                 },
-                "key_path": {
+                "db_user": {
                     "type": "string"
                 },
-                "namespace": {
+                "iam_role": {
                     "type": "string"
                 },
-                "pd_addrs": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "replica_config": {
-                    "$ref": "#/definitions/v2.ReplicaConfig"
+                "region": {
+                    "type": "string"
                 },
-                "sink_uri": {
+                "schema": {
                     "type": "string"
                 },
-                "start_ts": {
-                    "type": "integer"
+                "secret_arn": {
+                    "type": "string"
                 },
-                "target_ts": {
-                    "type": "integer"
+                "workgroup_name": {
+                    "type": "string"
                 }
             }
         },
-        "v2.ChangefeedSchedulerConfig": {
+        "v2.ReplicaConfig": {
             "type": "object",
             "properties": {
-                "enable_table_across_nodes": {
-                    "description": "EnableTableAcrossNodes set true to split one table to multiple spans and\ndistribute to multiple TiCDC nodes.",
+                "bdr_mode": {
                     "type": "boolean"
                 },
-                "region_threshold": {
-                    "description": "RegionThreshold is the region count threshold of splitting a table.",
-                    "type": "integer"
+                "case_sensitive": {
+                    "type": "boolean"
                 },
-                "write_key_threshold": {
-                    "description": "WriteKeyThreshold is the written keys threshold of splitting a table.",
-                    "type": "integer"
-                }
-            }
-        },
-        "v2.CloudStorageConfig": {
-            "type": "object",
-            "properties": {
-                "file_size": {
-                    "type": "integer"
+                "check_gc_safe_point": {
+                    "type": "boolean"
                 },
-                "flush_interval": {
+                "consistent": {
+                    "$ref": "#/definitions/v2.ConsistentConfig"
+                },
+                "delay_duration": {
                     "type": "string"
                 },
-                "output_column_id": {
+                "enable_old_value": {
                     "type": "boolean"
                 },
-                "worker_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "v2.CodecConfig": {
-            "type": "object",
-            "properties": {
-                "avro_bigint_unsigned_handling_mode": {
-                    "type": "string"
+                "enable_sync_point": {
+                    "type": "boolean"
                 },
-                "avro_decimal_handling_mode": {
-                    "type": "string"
+                "filter": {
+                    "$ref": "#/definitions/v2.FilterConfig"
                 },
-                "avro_enable_watermark": {
+                "force_replicate": {
                     "type": "boolean"
                 },
-                "enable_tidb_extension": {
+                "ignore_ineligible_table": {
                     "type": "boolean"
                 },
-                "max_batch_size": {
-                    "type": "integer"
-                }
-            }
-        },
-        "v2.ColumnSelector": {
-            "type": "object",
-            "properties": {
-                "columns": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "v2.ConsistentConfig": {
-            "type": "object",
-            "properties": {
-                "flush_interval": {
-                    "type": "integer"
+                "integrity": {
+                    "$ref": "#/definitions/v2.IntegrityConfig"
                 },
-                "level": {
+                "lag_slo": {
                     "type": "string"
                 },
-                "max_log_size": {
+                "memory_quota": {
                     "type": "integer"
                 },
-                "storage": {
-                    "type": "string"
+                "mounter": {
+                    "$ref": "#/definitions/v2.MounterConfig"
                 },
-                "use_file_backend": {
-                    "type": "boolean"
-                }
-            }
-        },
-        "v2.DispatchRule": {
-            "type": "object",
-            "properties": {
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "restart_strategy": {
+                    "$ref": "#/definitions/v2.RestartStrategyConfig"
                 },
-                "partition": {
+                "scheduler": {
+                    "$ref": "#/definitions/v2.ChangefeedSchedulerConfig"
+                },
+                "sink": {
+                    "$ref": "#/definitions/v2.SinkConfig"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/v2.SnapshotConfig"
+                },
+                "sync_point_interval": {
                     "type": "string"
                 },
-                "topic": {
+                "sync_point_retention": {
                     "type": "string"
                 }
             }
         },
-        "v2.EmptyResponse": {
-            "type": "object"
-        },
-        "v2.EventFilterRule": {
+        "v2.RestartStrategyConfig": {
             "type": "object",
             "properties": {
-                "ignore_delete_value_expr": {
-                    "type": "string"
-                },
-                "ignore_event": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "backoff_multiplier": {
+                    "type": "number"
                 },
-                "ignore_insert_value_expr": {
-                    "description": "sql expression",
+                "initial_backoff": {
                     "type": "string"
                 },
-                "ignore_sql": {
-                    "description": "regular expression",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "ignore_update_new_value_expr": {
+                "max_backoff": {
                     "type": "string"
                 },
-                "ignore_update_old_value_expr": {
+                "max_elapsed_time": {
                     "type": "string"
                 },
-                "matcher": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "max_retries": {
+                    "type": "integer"
+                },
+                "reset_window": {
+                    "type": "string"
                 }
             }
         },
-        "v2.FilterConfig": {
+        "v2.ResumeChangefeedConfig": {
             "type": "object",
             "properties": {
-                "do_dbs": {
-                    "description": "DoDBs is an allowlist of schemas.",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "do_tables": {
-                    "description": "DoTables is an allowlist of tables.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.Table"
-                    }
-                },
-                "event_filters": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.EventFilterRule"
-                    }
+                "ca_path": {
+                    "type": "string"
                 },
-                "ignore_dbs": {
-                    "description": "IgnoreDBs is a blocklist of schemas.",
+                "cert_allowed_cn": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "ignore_tables": {
-                    "description": "IgnoreTables is a blocklist of tables.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v2.Table"
-                    }
+                "cert_path": {
+                    "type": "string"
                 },
-                "ignore_txn_start_ts": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "key_path": {
+                    "type": "string"
                 },
-                "rules": {
+                "overwrite_checkpoint_ts": {
+                    "type": "integer"
+                },
+                "pd_addrs": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -2370,436 +4625,524 @@ var doc = `{
                 }
             }
         },
-        "v2.IntegrityConfig": {
+        "v2.RunningError": {
             "type": "object",
             "properties": {
-                "corruption_handle_level": {
+                "addr": {
                     "type": "string"
                 },
-                "integrity_check_level": {
+                "class": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "time": {
                     "type": "string"
                 }
             }
         },
-        "v2.KafkaConfig": {
+        "v2.Savepoint": {
             "type": "object",
             "properties": {
-                "auto_create_topic": {
-                    "type": "boolean"
-                },
-                "ca": {
+                "changefeed_id": {
                     "type": "string"
                 },
-                "cert": {
-                    "type": "string"
+                "checkpoint_ts": {
+                    "type": "integer"
                 },
-                "codec_config": {
-                    "$ref": "#/definitions/v2.CodecConfig"
+                "config": {
+                    "$ref": "#/definitions/v2.ReplicaConfig"
                 },
-                "compression": {
+                "create_time": {
                     "type": "string"
                 },
-                "dial_timeout": {
+                "expire_time": {
                     "type": "string"
                 },
-                "enable_tls": {
-                    "type": "boolean"
-                },
-                "insecure_skip_verify": {
-                    "type": "boolean"
-                },
-                "kafka_client_id": {
+                "name": {
                     "type": "string"
                 },
-                "kafka_version": {
+                "namespace": {
                     "type": "string"
                 },
-                "key": {
+                "sink_uri": {
                     "type": "string"
                 },
-                "large_message_handle": {
-                    "$ref": "#/definitions/v2.LargeMessageHandleConfig"
-                },
-                "max_message_bytes": {
+                "upstream_id": {
                     "type": "integer"
-                },
-                "partition_num": {
+                }
+            }
+        },
+        "v2.SavepointConfig": {
+            "type": "object",
+            "properties": {
+                "gc_ttl": {
+                    "description": "GCTTL is the TTL in seconds of the service GC safepoint of the savepoint,\nthe changefeeds must be created from the savepoint before it expires.",
                     "type": "integer"
                 },
-                "read_timeout": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.ServerStatus": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
                     "type": "string"
                 },
-                "replication_factor": {
-                    "type": "integer"
-                },
-                "required_acks": {
-                    "type": "integer"
+                "git_hash": {
+                    "type": "string"
                 },
-                "sasl_gssapi_auth_type": {
+                "id": {
                     "type": "string"
                 },
-                "sasl_gssapi_disable_pafxfast": {
+                "is_owner": {
                     "type": "boolean"
                 },
-                "sasl_gssapi_kerberos_config_path": {
-                    "type": "string"
+                "liveness": {
+                    "type": "integer"
                 },
-                "sasl_gssapi_keytab_path": {
-                    "type": "string"
+                "pid": {
+                    "type": "integer"
                 },
-                "sasl_gssapi_password": {
+                "version": {
                     "type": "string"
-                },
-                "sasl_gssapi_realm": {
+                }
+            }
+        },
+        "v2.SchemaRegistryConfig": {
+            "type": "object",
+            "properties": {
+                "apicurio_group_id": {
                     "type": "string"
                 },
-                "sasl_gssapi_service_name": {
+                "basic_auth_password": {
                     "type": "string"
                 },
-                "sasl_gssapi_user": {
+                "basic_auth_user": {
                     "type": "string"
                 },
-                "sasl_mechanism": {
+                "bearer_token": {
                     "type": "string"
                 },
-                "sasl_oauth_audience": {
+                "ca": {
                     "type": "string"
                 },
-                "sasl_oauth_client_id": {
+                "cert": {
                     "type": "string"
                 },
-                "sasl_oauth_client_secret": {
+                "glue_region": {
                     "type": "string"
                 },
-                "sasl_oauth_grant_type": {
+                "glue_registry_name": {
                     "type": "string"
                 },
-                "sasl_oauth_scopes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "key": {
+                    "type": "string"
                 },
-                "sasl_oauth_token_url": {
+                "provider": {
                     "type": "string"
                 },
-                "sasl_password": {
+                "subject_name_strategy": {
                     "type": "string"
                 },
-                "sasl_user": {
+                "subject_prefix": {
                     "type": "string"
                 },
-                "write_timeout": {
+                "subject_suffix": {
                     "type": "string"
                 }
             }
         },
-        "v2.LargeMessageHandleConfig": {
+        "v2.SinkConfig": {
             "type": "object",
             "properties": {
-                "claim_check_compression": {
-                    "type": "string"
+                "bootstrap": {
+                    "$ref": "#/definitions/v2.BootstrapConfig"
                 },
-                "claim_check_storage_uri": {
-                    "type": "string"
+                "canal_json_flat": {
+                    "type": "boolean"
                 },
-                "large_message_handle_option": {
-                    "type": "string"
-                }
-            }
-        },
-        "v2.LogLevelReq": {
-            "type": "object",
-            "properties": {
-                "log_level": {
+                "clickhouse_config": {
+                    "$ref": "#/definitions/v2.ClickHouseConfig"
+                },
+                "cloud_storage_config": {
+                    "$ref": "#/definitions/v2.CloudStorageConfig"
+                },
+                "column_maskers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ColumnMasker"
+                    }
+                },
+                "column_selectors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ColumnSelector"
+                    }
+                },
+                "computed_columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ComputedColumn"
+                    }
+                },
+                "csv": {
+                    "$ref": "#/definitions/v2.CSVConfig"
+                },
+                "date_separator": {
                     "type": "string"
-                }
-            }
-        },
-        "v2.MounterConfig": {
-            "type": "object",
-            "properties": {
-                "worker_num": {
-                    "type": "integer"
-                }
-            }
-        },
-        "v2.MySQLConfig": {
-            "type": "object",
-            "properties": {
-                "enable_batch_dml": {
+                },
+                "delete_as_tombstone": {
                     "type": "boolean"
                 },
-                "enable_cache_prepared_statement": {
+                "delete_only_output_handle_key_columns": {
                     "type": "boolean"
                 },
-                "enable_multi_statement": {
+                "dispatchers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.DispatchRule"
+                    }
+                },
+                "elasticsearch_config": {
+                    "$ref": "#/definitions/v2.ElasticsearchConfig"
+                },
+                "enable_kafka_sink_v2": {
                     "type": "boolean"
                 },
-                "max_multi_update_row_count": {
+                "enable_partition_separator": {
+                    "type": "boolean"
+                },
+                "encoder_concurrency": {
+                    "type": "integer"
+                },
+                "file_index_width": {
                     "type": "integer"
                 },
-                "max_multi_update_row_size": {
-                    "type": "integer"
+                "kafka_config": {
+                    "$ref": "#/definitions/v2.KafkaConfig"
+                },
+                "message_headers": {
+                    "$ref": "#/definitions/v2.MessageHeadersConfig"
+                },
+                "mirror": {
+                    "$ref": "#/definitions/v2.SinkMirror"
+                },
+                "mysql_config": {
+                    "$ref": "#/definitions/v2.MySQLConfig"
+                },
+                "only_output_updated_columns": {
+                    "type": "boolean"
+                },
+                "output_physical_time": {
+                    "type": "boolean"
+                },
+                "partition_layout": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "redis_config": {
+                    "$ref": "#/definitions/v2.RedisConfig"
+                },
+                "safe_mode": {
+                    "type": "boolean"
+                },
+                "schema_registry": {
+                    "type": "string"
+                },
+                "schema_registry_config": {
+                    "$ref": "#/definitions/v2.SchemaRegistryConfig"
                 },
-                "max_txn_row": {
-                    "type": "integer"
+                "table_metrics": {
+                    "$ref": "#/definitions/v2.TableMetricsConfig"
                 },
-                "read_timeout": {
-                    "type": "string"
+                "table_routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.TableRoute"
+                    }
                 },
-                "ssl_ca": {
-                    "type": "string"
+                "table_sink_buffer": {
+                    "$ref": "#/definitions/v2.TableSinkBufferConfig"
                 },
-                "ssl_cert": {
-                    "type": "string"
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.SinkTarget"
+                    }
                 },
-                "ssl_key": {
-                    "type": "string"
+                "temporal_encoding": {
+                    "$ref": "#/definitions/v2.TemporalEncodingConfig"
                 },
-                "tidb_txn_mode": {
+                "terminator": {
                     "type": "string"
                 },
-                "time_zone": {
+                "transaction_atomicity": {
                     "type": "string"
                 },
-                "timeout": {
-                    "type": "string"
+                "transformer": {
+                    "$ref": "#/definitions/v2.TransformerConfig"
                 },
-                "worker_count": {
-                    "type": "integer"
+                "type_mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.TypeMapping"
+                    }
                 },
-                "write_timeout": {
-                    "type": "string"
+                "webhook_config": {
+                    "$ref": "#/definitions/v2.WebhookConfig"
                 }
             }
         },
-        "v2.ProcessorCommonInfo": {
+        "v2.SinkMirror": {
             "type": "object",
             "properties": {
-                "capture_id": {
-                    "type": "string"
+                "enable_kafka_sink_v2": {
+                    "type": "boolean"
                 },
-                "changefeed_id": {
+                "protocol": {
                     "type": "string"
                 },
-                "namespace": {
+                "sink_uri": {
                     "type": "string"
                 }
             }
         },
-        "v2.ProcessorDetail": {
+        "v2.SinkTarget": {
             "type": "object",
             "properties": {
-                "table_ids": {
-                    "description": "All table ids that this processor are replicating.",
+                "matcher": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     }
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "sink_uri": {
+                    "type": "string"
                 }
             }
         },
-        "v2.ReplicaConfig": {
+        "v2.SkippedDDL": {
             "type": "object",
             "properties": {
-                "bdr_mode": {
-                    "type": "boolean"
-                },
-                "case_sensitive": {
-                    "type": "boolean"
-                },
-                "check_gc_safe_point": {
-                    "type": "boolean"
-                },
-                "consistent": {
-                    "$ref": "#/definitions/v2.ConsistentConfig"
-                },
-                "enable_old_value": {
-                    "type": "boolean"
-                },
-                "enable_sync_point": {
-                    "type": "boolean"
-                },
-                "filter": {
-                    "$ref": "#/definitions/v2.FilterConfig"
-                },
-                "force_replicate": {
-                    "type": "boolean"
-                },
-                "ignore_ineligible_table": {
-                    "type": "boolean"
-                },
-                "integrity": {
-                    "$ref": "#/definitions/v2.IntegrityConfig"
-                },
-                "memory_quota": {
+                "commit_ts": {
                     "type": "integer"
                 },
-                "mounter": {
-                    "$ref": "#/definitions/v2.MounterConfig"
+                "query": {
+                    "type": "string"
                 },
-                "scheduler": {
-                    "$ref": "#/definitions/v2.ChangefeedSchedulerConfig"
+                "schema": {
+                    "type": "string"
                 },
-                "sink": {
-                    "$ref": "#/definitions/v2.SinkConfig"
+                "start_ts": {
+                    "type": "integer"
                 },
-                "sync_point_interval": {
+                "table": {
                     "type": "string"
                 },
-                "sync_point_retention": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "v2.ResumeChangefeedConfig": {
+        "v2.SnapshotConfig": {
             "type": "object",
             "properties": {
-                "ca_path": {
-                    "type": "string"
-                },
-                "cert_allowed_cn": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "cert_path": {
-                    "type": "string"
+                "chunk_size": {
+                    "type": "integer"
                 },
-                "key_path": {
-                    "type": "string"
+                "enable": {
+                    "type": "boolean"
                 },
-                "overwrite_checkpoint_ts": {
+                "rate_limit": {
                     "type": "integer"
-                },
-                "pd_addrs": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 }
             }
         },
-        "v2.RunningError": {
+        "v2.Table": {
             "type": "object",
             "properties": {
-                "addr": {
-                    "type": "string"
-                },
-                "code": {
+                "database_name": {
+                    "description": "Schema is the name of the schema (database) containing this table.",
                     "type": "string"
                 },
-                "message": {
+                "table_name": {
+                    "description": "Name is the unqualified table name.",
                     "type": "string"
+                }
+            }
+        },
+        "v2.TableMetricsConfig": {
+            "type": "object",
+            "properties": {
+                "enable": {
+                    "type": "boolean"
                 },
-                "time": {
-                    "type": "string"
+                "max_tables": {
+                    "type": "integer"
                 }
             }
         },
-        "v2.ServerStatus": {
+        "v2.TableProgress": {
             "type": "object",
             "properties": {
-                "cluster_id": {
+                "capture_id": {
                     "type": "string"
                 },
-                "git_hash": {
-                    "type": "string"
+                "checkpoint_lag_ms": {
+                    "description": "CheckpointLagMs is the lag of the checkpoint when the stats are\ncollected from the processor, it's 0 if the stats aren't collected.",
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "checkpoint_ts": {
+                    "type": "integer"
                 },
-                "is_owner": {
-                    "type": "boolean"
+                "pending_event_count": {
+                    "description": "PendingEventCount is the number of events which are written to the\nsink but not flushed yet.",
+                    "type": "integer"
                 },
-                "liveness": {
+                "resolved_ts": {
                     "type": "integer"
                 },
-                "pid": {
+                "sink_flush_latency_ms": {
+                    "description": "SinkFlushLatencyMs is the moving average of the latency of flushing\nevents to the sink.",
                     "type": "integer"
                 },
-                "version": {
+                "span": {
                     "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
                 }
             }
         },
-        "v2.SinkConfig": {
+        "v2.TableRoute": {
             "type": "object",
             "properties": {
-                "cloud_storage_config": {
-                    "$ref": "#/definitions/v2.CloudStorageConfig"
-                },
-                "column_selectors": {
+                "columns": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.ColumnSelector"
+                        "$ref": "#/definitions/v2.ColumnRoute"
                     }
                 },
-                "csv": {
-                    "$ref": "#/definitions/v2.CSVConfig"
-                },
-                "date_separator": {
-                    "type": "string"
-                },
-                "delete_only_output_handle_key_columns": {
-                    "type": "boolean"
-                },
-                "dispatchers": {
+                "matcher": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v2.DispatchRule"
+                        "type": "string"
                     }
                 },
-                "enable_kafka_sink_v2": {
+                "source_columns": {
                     "type": "boolean"
                 },
-                "enable_partition_separator": {
-                    "type": "boolean"
+                "target_schema": {
+                    "type": "string"
                 },
-                "encoder_concurrency": {
+                "target_table": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.TableSinkBufferConfig": {
+            "type": "object",
+            "properties": {
+                "disk_quota": {
                     "type": "integer"
                 },
-                "file_index_width": {
-                    "type": "integer"
+                "policy": {
+                    "type": "string"
                 },
-                "kafka_config": {
-                    "$ref": "#/definitions/v2.KafkaConfig"
+                "quota": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v2.TemporalEncodingConfig": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string"
                 },
-                "mysql_config": {
-                    "$ref": "#/definitions/v2.MySQLConfig"
+                "protocols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "only_output_updated_columns": {
-                    "type": "boolean"
+                "source_time_zone": {
+                    "type": "string"
                 },
-                "protocol": {
+                "time_zone": {
                     "type": "string"
+                }
+            }
+        },
+        "v2.TransformerConfig": {
+            "type": "object",
+            "properties": {
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "safe_mode": {
-                    "type": "boolean"
+                "wasm_file": {
+                    "type": "string"
                 },
-                "schema_registry": {
+                "wasm_sha256": {
                     "type": "string"
+                }
+            }
+        },
+        "v2.TypeMapping": {
+            "type": "object",
+            "properties": {
+                "protocols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "terminator": {
+                "source": {
                     "type": "string"
                 },
-                "transaction_atomicity": {
+                "target": {
                     "type": "string"
                 }
             }
         },
-        "v2.Table": {
+        "v2.WebhookConfig": {
             "type": "object",
             "properties": {
-                "database_name": {
-                    "description": "Schema is the name of the schema (database) containing this table.",
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max_batch_rows": {
+                    "type": "integer"
+                },
+                "max_retries": {
+                    "type": "integer"
+                },
+                "max_retry_backoff": {
                     "type": "string"
                 },
-                "table_name": {
-                    "description": "Name is the unqualified table name.",
+                "retry_backoff": {
                     "type": "string"
+                },
+                "worker_count": {
+                    "type": "integer"
                 }
             }
         }
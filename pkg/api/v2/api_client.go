@@ -29,6 +29,7 @@ type APIV2Interface interface {
 	StatusGetter
 	CapturesGetter
 	ProcessorsGetter
+	SavepointsGetter
 }
 
 // APIV2Client implements APIV1Interface and it is used to interact with cdc owner http api.
@@ -90,6 +91,14 @@ func (c *APIV2Client) Processors() ProcessorInterface {
 	return newProcessors(c)
 }
 
+// Savepoints returns a SavepointInterface abstracting savepoint operations.
+func (c *APIV2Client) Savepoints() SavepointInterface {
+	if c == nil {
+		return nil
+	}
+	return newSavepoints(c)
+}
+
 // NewAPIClient creates a new APIV1Client.
 func NewAPIClient(serverAddr string, credential *security.Credential) (*APIV2Client, error) {
 	c := &rest.Config{}
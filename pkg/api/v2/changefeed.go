@@ -32,6 +32,8 @@ type ChangefeedsGetter interface {
 type ChangefeedInterface interface {
 	// Create creates a changefeed
 	Create(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error)
+	// DryRun validates a changefeed without creating it
+	DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangefeedDryRunReport, error)
 	// VerifyTable verifies table for a changefeed
 	VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error)
 	// Update updates a changefeed
@@ -72,6 +74,18 @@ func (c *changefeeds) Create(ctx context.Context,
 	return result, err
 }
 
+func (c *changefeeds) DryRun(ctx context.Context,
+	cfg *v2.ChangefeedConfig,
+) (*v2.ChangefeedDryRunReport, error) {
+	result := &v2.ChangefeedDryRunReport{}
+	err := c.client.Post().
+		WithURI("changefeeds").
+		WithParam("dry_run", "true").
+		WithBody(cfg).
+		Do(ctx).Into(result)
+	return result, err
+}
+
 func (c *changefeeds) VerifyTable(ctx context.Context,
 	cfg *v2.VerifyTableConfig,
 ) (*v2.Tables, error) {
@@ -102,6 +102,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Delete(ctx, namespace, name inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChangefeedInterface)(nil).Delete), ctx, namespace, name)
 }
 
+// DryRun mocks base method.
+func (m *MockChangefeedInterface) DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangefeedDryRunReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", ctx, cfg)
+	ret0, _ := ret[0].(*v2.ChangefeedDryRunReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockChangefeedInterfaceMockRecorder) DryRun(ctx, cfg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockChangefeedInterface)(nil).DryRun), ctx, cfg)
+}
+
 // Get mocks base method.
 func (m *MockChangefeedInterface) Get(ctx context.Context, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
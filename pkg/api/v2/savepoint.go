@@ -0,0 +1,101 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// SavepointsGetter has a method to return a SavepointInterface.
+type SavepointsGetter interface {
+	Savepoints() SavepointInterface
+}
+
+// SavepointInterface has methods to work with Savepoint items.
+type SavepointInterface interface {
+	// Create creates a savepoint of a changefeed
+	Create(ctx context.Context, cfg *v2.SavepointConfig,
+		namespace string, changefeedID string) (*v2.Savepoint, error)
+	// Delete deletes a savepoint by name
+	Delete(ctx context.Context, namespace string, name string) error
+	// Get gets a savepoint by name
+	Get(ctx context.Context, namespace string, name string) (*v2.Savepoint, error)
+	// List lists all savepoints
+	List(ctx context.Context, namespace string) ([]v2.Savepoint, error)
+}
+
+// savepoints implements SavepointInterface
+type savepoints struct {
+	client rest.CDCRESTInterface
+}
+
+// newSavepoints returns savepoints
+func newSavepoints(c *APIV2Client) *savepoints {
+	return &savepoints{
+		client: c.RESTClient(),
+	}
+}
+
+// Create creates a savepoint of a changefeed
+func (c *savepoints) Create(ctx context.Context,
+	cfg *v2.SavepointConfig, namespace string, changefeedID string,
+) (*v2.Savepoint, error) {
+	result := &v2.Savepoint{}
+	u := fmt.Sprintf("changefeeds/%s/savepoints?namespace=%s", changefeedID, namespace)
+	err := c.client.Post().
+		WithURI(u).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Delete deletes a savepoint
+func (c *savepoints) Delete(ctx context.Context,
+	namespace string, name string,
+) error {
+	u := fmt.Sprintf("savepoints/%s?namespace=%s", name, namespace)
+	return c.client.Delete().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
+// Get gets a savepoint
+func (c *savepoints) Get(ctx context.Context,
+	namespace string, name string,
+) (*v2.Savepoint, error) {
+	result := &v2.Savepoint{}
+	u := fmt.Sprintf("savepoints/%s?namespace=%s", name, namespace)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// List lists all savepoints
+func (c *savepoints) List(ctx context.Context,
+	namespace string,
+) ([]v2.Savepoint, error) {
+	result := &v2.ListResponse[v2.Savepoint]{}
+	err := c.client.Get().
+		WithURI("savepoints?namespace=" + namespace).
+		Do(ctx).
+		Into(result)
+	return result.Items, err
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package applier applies the redo logs of a changefeed to a downstream.
+
+It's used by the `cdc redo apply` command, and can be embedded by disaster
+recovery tools instead of running the command. The redo logs are read from
+RedoApplierConfig.Storage and written to RedoApplierConfig.SinkURI, which can be
+any sink supported by the dmlsink and ddlsink factories. Sinks that need extra
+settings, e.g. the protocol of MQ and cloud storage sinks, take them from
+RedoApplierConfig.ReplicaConfig. Safe mode is always enabled for MySQL
+compatible sinks, because the rows after the checkpoint may have been written.
+
+	ap := applier.NewRedoApplier(&applier.RedoApplierConfig{
+		Storage:       "s3://bucket/redo",
+		SinkURI:       "kafka://127.0.0.1:9092/topic?protocol=canal-json",
+		ReplicaConfig: replicaConfig,
+	})
+	if err := ap.Apply(ctx); err != nil {
+		return err
+	}
+*/
+package applier
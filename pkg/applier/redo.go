@@ -16,6 +16,7 @@ package applier
 import (
 	"context"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pingcap/log"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/prometheus/client_golang/prometheus"
@@ -57,6 +59,17 @@ type RedoApplierConfig struct {
 	SinkURI string
 	Storage string
 	Dir     string
+
+	// ChangefeedID identifies the applier in logs and metrics, it's useful
+	// when several appliers are embedded in the same process. Defaults to
+	// the changefeed named redo-applier in the default namespace.
+	ChangefeedID model.ChangeFeedID
+	// ReplicaConfig is used to create the sinks, e.g. the protocol of MQ and
+	// cloud storage sinks is required. Defaults to the default replica config.
+	ReplicaConfig *config.ReplicaConfig
+	// EncryptionKey is the hex encoded AES key or a secret reference to it,
+	// which is required if the redo logs are encrypted.
+	EncryptionKey string
 }
 
 // RedoApplier implements a redo log applier
@@ -81,15 +94,21 @@ type RedoApplier struct {
 	errCh chan error
 
 	// changefeedID is used to identify the changefeed that this applier belongs to.
-	// not used for now.
 	changefeedID model.ChangeFeedID
 }
 
-// NewRedoApplier creates a new RedoApplier instance
+// NewRedoApplier creates a new RedoApplier instance. The redo logs can be
+// applied to any sink supported by the dmlsink and ddlsink factories, so the
+// applier can be embedded by tools other than the cdc binary.
 func NewRedoApplier(cfg *RedoApplierConfig) *RedoApplier {
+	changefeedID := cfg.ChangefeedID
+	if changefeedID.ID == "" {
+		changefeedID = model.DefaultChangeFeedID(applierChangefeed)
+	}
 	return &RedoApplier{
-		cfg:   cfg,
-		errCh: make(chan error, 1024),
+		cfg:          cfg,
+		errCh:        make(chan error, 1024),
+		changefeedID: changefeedID,
 	}
 }
 
@@ -107,10 +126,29 @@ func (rac *RedoApplierConfig) toLogReaderConfig() (string, *reader.LogReaderConf
 		URI:                *uri,
 		Dir:                rac.Dir,
 		UseExternalStorage: redo.IsExternalStorage(uri.Scheme),
+		EncryptionKey:      rac.EncryptionKey,
 	}
 	return uri.Scheme, cfg, nil
 }
 
+// toSinkURI returns the sink URI with safe-mode enabled, because the rows
+// between the checkpoint and the resolved ts may have been written downstream.
+func (rac *RedoApplierConfig) toSinkURI() (string, error) {
+	sinkURI, err := url.Parse(rac.SinkURI)
+	if err != nil {
+		return "", errors.WrapError(errors.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(strings.ToLower(sinkURI.Scheme)) {
+		return rac.SinkURI, nil
+	}
+	rawQuery := sinkURI.Query()
+	if rawQuery.Get("safe-mode") != "true" {
+		rawQuery.Set("safe-mode", "true")
+		sinkURI.RawQuery = rawQuery.Encode()
+	}
+	return sinkURI.String(), nil
+}
+
 func (ra *RedoApplier) catchError(ctx context.Context) error {
 	for {
 		select {
@@ -123,13 +161,21 @@ func (ra *RedoApplier) catchError(ctx context.Context) error {
 }
 
 func (ra *RedoApplier) initSink(ctx context.Context) (err error) {
-	replicaConfig := config.GetDefaultReplicaConfig()
-	ra.sinkFactory, err = dmlfactory.New(ctx, ra.changefeedID, ra.cfg.SinkURI, replicaConfig, ra.errCh)
+	replicaConfig := ra.cfg.ReplicaConfig
+	if replicaConfig == nil {
+		replicaConfig = config.GetDefaultReplicaConfig()
+	}
+	sinkURI, err := ra.cfg.toSinkURI()
+	if err != nil {
+		return err
+	}
+	ra.sinkFactory, err = dmlfactory.New(ctx, ra.changefeedID, sinkURI, replicaConfig, ra.errCh, ra.errCh)
 	if err != nil {
 		return err
 	}
-	ra.ddlSink, err = ddlfactory.New(ctx, ra.changefeedID, ra.cfg.SinkURI, replicaConfig)
+	ra.ddlSink, err = ddlfactory.New(ctx, ra.changefeedID, sinkURI, replicaConfig)
 	if err != nil {
+		ra.sinkFactory.Close()
 		return err
 	}
 
@@ -166,6 +212,7 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		return err
 	}
 	defer ra.sinkFactory.Close()
+	defer ra.ddlSink.Close()
 
 	shouldApplyDDL := func(row *model.RowChangedEvent, ddl *model.DDLEvent) bool {
 		if ddl == nil {
@@ -307,10 +354,11 @@ func (ra *RedoApplier) applyRow(
 
 	tableID := row.Table.TableID
 	if _, ok := ra.tableSinks[tableID]; !ok {
-		tableSink := ra.sinkFactory.CreateTableSink(
-			model.DefaultChangeFeedID(applierChangefeed),
+		tableSink := ra.sinkFactory.ForTable(*row.Table).CreateTableSink(
+			ra.changefeedID,
 			spanz.TableIDToComparableSpan(tableID),
 			checkpointTs,
+			nil,
 			prometheus.NewCounter(prometheus.CounterOpts{}),
 		)
 		ra.tableSinks[tableID] = tableSink
@@ -436,7 +484,7 @@ func (ra *RedoApplier) Apply(egCtx context.Context) (err error) {
 		return ra.rd.Run(egCtx)
 	})
 
-	ra.memQuota = memquota.NewMemQuota(model.DefaultChangeFeedID(applierChangefeed),
+	ra.memQuota = memquota.NewMemQuota(ra.changefeedID,
 		config.DefaultChangefeedMemoryQuota, "sink")
 	defer ra.memQuota.Close()
 	eg.Go(func() error {
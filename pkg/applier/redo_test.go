@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo/reader"
 	mysqlDDL "github.com/pingcap/tiflow/cdc/sink/ddlsink/mysql"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn"
+	"github.com/pingcap/tiflow/pkg/config"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/stretchr/testify/require"
 )
@@ -272,3 +273,77 @@ func getMockDB(t *testing.T) *sql.DB {
 	mock.ExpectClose()
 	return db
 }
+
+func TestApplyToBlackHoleSink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	redoLogCh := make(chan *model.RowChangedEvent, 1024)
+	ddlEventCh := make(chan *model.DDLEvent, 1024)
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	for _, commitTs := range []uint64{1100, 1200, 2000} {
+		redoLogCh <- &model.RowChangedEvent{
+			StartTs:  commitTs - 10,
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{Name: "a", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			},
+		}
+	}
+	ddlEventCh <- &model.DDLEvent{
+		CommitTs: 1500,
+		Query:    "create table checkpoint(id int)",
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "checkpoint"},
+		},
+		Type: timodel.ActionCreateTable,
+	}
+	close(redoLogCh)
+	close(ddlEventCh)
+
+	cfg := &RedoApplierConfig{
+		SinkURI:       "blackhole://",
+		ChangefeedID:  model.DefaultChangeFeedID("test-embedded-applier"),
+		ReplicaConfig: config.GetDefaultReplicaConfig(),
+	}
+	ap := NewRedoApplier(cfg)
+	require.Nil(t, ap.Apply(ctx))
+	require.Equal(t, uint64(3), ap.appliedLogCount)
+	require.Equal(t, uint64(1), ap.appliedDDLCount)
+	require.Equal(t, cfg.ChangefeedID, ap.changefeedID)
+}
+
+func TestApplierConfigToSinkURI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		sinkURI  string
+		expected string
+	}{
+		{"mysql://root@127.0.0.1:3306?safe-mode=false", "mysql://root@127.0.0.1:3306?safe-mode=true"},
+		{"tidb://root@127.0.0.1:4000", "tidb://root@127.0.0.1:4000?safe-mode=true"},
+		{"mysql://root@127.0.0.1:3306?safe-mode=true", "mysql://root@127.0.0.1:3306?safe-mode=true"},
+		{"kafka://127.0.0.1:9092/topic?protocol=open-protocol", "kafka://127.0.0.1:9092/topic?protocol=open-protocol"},
+		{"blackhole://", "blackhole://"},
+	}
+	for _, c := range cases {
+		cfg := &RedoApplierConfig{SinkURI: c.sinkURI}
+		sinkURI, err := cfg.toSinkURI()
+		require.Nil(t, err)
+		require.Equal(t, c.expected, sinkURI)
+	}
+
+	cfg := &RedoApplierConfig{SinkURI: "mysql://root@127.0.0.1:3306\x7f"}
+	_, err := cfg.toSinkURI()
+	require.Regexp(t, "ErrSinkURIInvalid", err)
+}
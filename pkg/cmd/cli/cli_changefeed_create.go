@@ -16,7 +16,6 @@ package cli
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"strings"
 
 	"github.com/fatih/color"
@@ -30,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/sink"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/spf13/cobra"
 	"github.com/tikv/client-go/v2/oracle"
@@ -108,6 +108,7 @@ type createChangefeedOptions struct {
 	disableGCSafePointCheck bool
 	startTs                 uint64
 	timezone                string
+	dryRun                  bool
 
 	cfg *config.ReplicaConfig
 }
@@ -128,6 +129,7 @@ func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
+	cmd.PersistentFlags().BoolVar(&o.dryRun, "dry-run", false, "Validate the changefeed and print the report without creating it")
 	// we don't support specify these flags below when cdc version >= 6.2.0
 	_ = cmd.PersistentFlags().MarkHidden("tz")
 }
@@ -153,7 +155,7 @@ func (o *createChangefeedOptions) completeReplicaCfg(
 		}
 	}
 
-	uri, err := url.Parse(o.commonChangefeedOptions.sinkURI)
+	uri, err := sink.ParseSinkURI(o.commonChangefeedOptions.sinkURI)
 	if err != nil {
 		return err
 	}
@@ -273,6 +275,10 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 		o.startTs = oracle.ComposeTS(tso.Timestamp, tso.LogicTime)
 	}
 
+	if o.dryRun {
+		return o.runDryRun(ctx, cmd)
+	}
+
 	if !o.commonChangefeedOptions.noConfirm {
 		if err = confirmLargeDataGap(cmd, tso.Timestamp, o.startTs, "create"); err != nil {
 			return err
@@ -355,6 +361,25 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 	return nil
 }
 
+// runDryRun validates the changefeed without creating it and prints the report,
+// the ineligible tables are ignored only if --no-confirm is set.
+func (o *createChangefeedOptions) runDryRun(ctx context.Context, cmd *cobra.Command) error {
+	createChangefeedCfg := o.getChangefeedConfig()
+	createChangefeedCfg.ReplicaConfig.IgnoreIneligibleTable = o.commonChangefeedOptions.noConfirm
+
+	report, err := o.apiClient.Changefeeds().DryRun(ctx, createChangefeedCfg)
+	if err != nil {
+		return err
+	}
+	if err := util.JSONPrint(cmd, report); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return errors.New("dry run of the changefeed failed, see the failed checks in the report")
+	}
+	return nil
+}
+
 // newCmdCreateChangefeed creates the `cli changefeed create` command.
 func newCmdCreateChangefeed(f factory.Factory) *cobra.Command {
 	commonChangefeedOptions := newChangefeedCommonOptions()
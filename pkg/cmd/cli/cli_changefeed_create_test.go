@@ -14,6 +14,9 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -176,6 +179,57 @@ func TestChangefeedCreateCli(t *testing.T) {
 	require.Contains(t, o.validate(cmd).Error(), "creating changefeed with `--sort-dir`")
 }
 
+func TestChangefeedCreateCliDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	args := []string{
+		"--sink-uri=blackhole://",
+		"--changefeed-id=abc",
+		"--dry-run",
+	}
+	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&v2.Tso{
+		Timestamp: time.Now().Unix() * 1000,
+	}, nil).Times(2)
+
+	// the changefeed isn't created even if all checks are passed.
+	f.changefeeds.EXPECT().DryRun(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangefeedDryRunReport, error) {
+			require.Equal(t, "abc", cfg.ID)
+			require.False(t, cfg.ReplicaConfig.IgnoreIneligibleTable)
+			return &v2.ChangefeedDryRunReport{
+				ID:     cfg.ID,
+				Passed: true,
+				Checks: []v2.DryRunCheck{{Name: v2.DryRunCheckSink, Status: v2.DryRunCheckPassed}},
+			}, nil
+		})
+	cmd := newCmdCreateChangefeed(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute())
+	report := &v2.ChangefeedDryRunReport{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), report))
+	require.True(t, report.Passed)
+
+	f.changefeeds.EXPECT().DryRun(gomock.Any(), gomock.Any()).
+		Return(&v2.ChangefeedDryRunReport{
+			ID:     "abc",
+			Passed: false,
+			Checks: []v2.DryRunCheck{{
+				Name: v2.DryRunCheckSink, Status: v2.DryRunCheckFailed, Error: "topic not found",
+			}},
+		}, nil)
+	cmd = new(cobra.Command)
+	o := newCreateChangefeedOptions(newChangefeedCommonOptions())
+	o.addFlags(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+	require.NoError(t, o.complete(f, cmd))
+	err := o.run(context.Background(), cmd)
+	require.Regexp(t, ".*dry run of the changefeed failed.*", err)
+}
+
 func TestChangefeedCreateCliAdjustEnableOldValue(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -25,7 +25,8 @@ import (
 // applyRedoOptions defines flags for the `redo apply` command.
 type applyRedoOptions struct {
 	options
-	sinkURI string
+	sinkURI       string
+	encryptionKey string
 }
 
 // newapplyRedoOptions creates new applyRedoOptions for the `redo apply` command.
@@ -37,6 +38,7 @@ func newapplyRedoOptions() *applyRedoOptions {
 // flags related to template printing to it.
 func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri")
+	cmd.Flags().StringVar(&o.encryptionKey, "encryption-key", "", "hex encoded AES key of the encrypted redo logs, or a secret reference to it, eg, \"${secret:file:/path/to/key}\"")
 	// the possible error returned from MarkFlagRequired is `no such flag`
 	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
 }
@@ -63,9 +65,10 @@ func (o *applyRedoOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
 	cfg := &applier.RedoApplierConfig{
-		Storage: o.storage,
-		SinkURI: o.sinkURI,
-		Dir:     o.dir,
+		Storage:       o.storage,
+		SinkURI:       o.sinkURI,
+		Dir:           o.dir,
+		EncryptionKey: o.encryptionKey,
 	}
 	ap := applier.NewRedoApplier(cfg)
 	err := ap.Apply(ctx)
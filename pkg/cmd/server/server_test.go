@@ -204,6 +204,10 @@ func TestParseCfg(t *testing.T) {
 				AddTableBatchSize:    50,
 			},
 		},
+		Tracing: &config.TracingConfig{
+			Enable:      false,
+			SampleRatio: 0.01,
+		},
 		ClusterID:           "default",
 		MaxMemoryPercentage: config.DefaultMaxMemoryPercentage,
 	}, o.serverConfig)
@@ -353,6 +357,10 @@ check-balance-interval = "10s"
 				AddTableBatchSize:    50,
 			},
 		},
+		Tracing: &config.TracingConfig{
+			Enable:      false,
+			SampleRatio: 0.01,
+		},
 		ClusterID:           "default",
 		MaxMemoryPercentage: config.DefaultMaxMemoryPercentage,
 	}, o.serverConfig)
@@ -490,6 +498,10 @@ cert-allowed-cn = ["dd","ee"]
 				AddTableBatchSize:    50,
 			},
 		},
+		Tracing: &config.TracingConfig{
+			Enable:      false,
+			SampleRatio: 0.01,
+		},
 		ClusterID:           "default",
 		MaxMemoryPercentage: config.DefaultMaxMemoryPercentage,
 	}, o.serverConfig)
@@ -209,7 +209,6 @@ func TestAndWriteExampleReplicaTOML(t *testing.T) {
 		Terminator:                       util.AddressOf("\r\n"),
 		DateSeparator:                    util.AddressOf(config.DateSeparatorDay.String()),
 		EnablePartitionSeparator:         util.AddressOf(true),
-		EnableKafkaSinkV2:                util.AddressOf(false),
 		OnlyOutputUpdatedColumns:         util.AddressOf(false),
 		DeleteOnlyOutputHandleKeyColumns: util.AddressOf(false),
 		Protocol:                         util.AddressOf("open-protocol"),
@@ -235,7 +234,6 @@ func TestAndWriteStorageSinkTOML(t *testing.T) {
 		DateSeparator:            util.AddressOf("day"),
 		EnablePartitionSeparator: util.AddressOf(true),
 		FileIndexWidth:           util.AddressOf(config.DefaultFileIndexWidth),
-		EnableKafkaSinkV2:        util.AddressOf(false),
 		CSVConfig: &config.CSVConfig{
 			Delimiter:            ",",
 			Quote:                "\"",
@@ -57,13 +57,13 @@ const (
     ],
     "enable-partition-separator": true,
     "protocol": "open-protocol",
-	"enable-kafka-sink-v2": false,
 	"only-output-updated-columns": false,
 	"delete-only-output-handle-key-columns": false,
     "large-message-handle": {
       "large-message-handle-option": "none",
       "claim-check-storage-uri": "",
-      "claim-check-compression": ""
+      "claim-check-compression": "",
+      "claim-check-bare-message": false
     }
   },
   "consistent": {
@@ -71,7 +71,11 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": "",
-    "use-file-backend": false
+    "use-file-backend": false,
+    "flush-worker-num": 8,
+    "flush-concurrency": 1,
+    "compression": "none",
+    "encryption-key": ""
   },
   "scheduler": {
     "enable-table-across-nodes": false,
@@ -159,7 +163,13 @@ const (
       "max-task-concurrency": 10,
       "check-balance-interval": 60000000000,
       "add-table-batch-size": 50
-    }
+    },
+    "enable-kafka-sink-v2": false
+  },
+  "tracing": {
+    "enable": false,
+    "endpoint": "",
+    "sample-ratio": 0.01
   },
   "cluster-id": "default",
   "max-memory-percentage": 70
@@ -205,7 +215,10 @@ const (
       "quote": "\"",
       "null": "\\N",
       "include-commit-ts": true,
-      "binary-encoding-method":"base64"
+      "binary-encoding-method":"base64",
+      "output-schema-sidecar": false,
+      "quoting-policy": "",
+      "escape-char": ""
     },
     "date-separator": "month",
     "enable-partition-separator": true,
@@ -252,7 +265,8 @@ const (
       "large-message-handle": {
         "large-message-handle-option": "handle-key-only",
         "claim-check-storage-uri": "",
-        "claim-check-compression": ""
+        "claim-check-compression": "",
+        "claim-check-bare-message": false
       }
     },
     "mysql-config": {
@@ -284,7 +298,11 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": "",
-    "use-file-backend": false
+    "use-file-backend": false,
+    "flush-worker-num": 8,
+    "flush-concurrency": 1,
+    "compression": "none",
+    "encryption-key": ""
   },
   "scheduler": {
     "enable-table-across-nodes": true,
@@ -339,7 +357,10 @@ const (
       "quote": "\"",
       "null": "\\N",
       "include-commit-ts": true,
-      "binary-encoding-method":"base64"
+      "binary-encoding-method":"base64",
+      "output-schema-sidecar": false,
+      "quoting-policy": "",
+      "escape-char": ""
     },
     "terminator": "\r\n",
 	"transaction-atomicity": "",
@@ -386,7 +407,8 @@ const (
       "large-message-handle": {
         "large-message-handle-option": "handle-key-only",
         "claim-check-storage-uri": "",
-        "claim-check-compression": ""
+        "claim-check-compression": "",
+        "claim-check-bare-message": false
       }
     },
     "mysql-config": {
@@ -418,7 +440,11 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": "",
-    "use-file-backend": false
+    "use-file-backend": false,
+    "flush-worker-num": 8,
+    "flush-concurrency": 1,
+    "compression": "none",
+    "encryption-key": ""
   },
   "scheduler": {
     "enable-table-across-nodes": true,
@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/br/pkg/storage"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/security/secret"
 )
 
 // ConsistentConfig represents replication consistency config for a changefeed.
@@ -28,6 +29,17 @@ type ConsistentConfig struct {
 	FlushIntervalInMs int64  `toml:"flush-interval" json:"flush-interval"`
 	Storage           string `toml:"storage" json:"storage"`
 	UseFileBackend    bool   `toml:"use-file-backend" json:"use-file-backend"`
+	// FlushWorkerNum is the number of workers flushing log files concurrently.
+	FlushWorkerNum int `toml:"flush-worker-num" json:"flush-worker-num"`
+	// FlushConcurrency is the number of parts uploaded concurrently for each
+	// log file by S3 multi-part upload, 1 means no multi-part upload.
+	FlushConcurrency int `toml:"flush-concurrency" json:"flush-concurrency"`
+	// Compression is the compression of the log files, none or zstd.
+	Compression string `toml:"compression" json:"compression"`
+	// EncryptionKey is a secret reference to the hex encoded AES key, such as
+	// `${secret:vault:secret/data/ticdc#redo-key}`. The log files are encrypted
+	// before they're written to the storage if it's set.
+	EncryptionKey string `toml:"encryption-key" json:"encryption-key"`
 }
 
 // ValidateAndAdjust validates the consistency config and adjusts it if necessary.
@@ -49,6 +61,39 @@ func (c *ConsistentConfig) ValidateAndAdjust() error {
 				c.FlushIntervalInMs, redo.MinFlushIntervalInMs))
 	}
 
+	if c.FlushWorkerNum == 0 {
+		c.FlushWorkerNum = redo.DefaultFlushWorkerNum
+	}
+	if c.FlushWorkerNum < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.flush-worker-num:%d must be greater than 0",
+				c.FlushWorkerNum))
+	}
+	if c.FlushConcurrency == 0 {
+		c.FlushConcurrency = redo.DefaultFlushConcurrency
+	}
+	if c.FlushConcurrency < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.flush-concurrency:%d must be greater than 0",
+				c.FlushConcurrency))
+	}
+
+	if c.Compression == "" {
+		c.Compression = redo.CompressionNone
+	}
+	if err := redo.ValidateCompression(c.Compression); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.compression:%s must be %s or %s",
+				c.Compression, redo.CompressionNone, redo.CompressionZstd))
+	}
+	// The key is stored in the changefeed info, so it must be a secret
+	// reference instead of the key itself.
+	if c.EncryptionKey != "" && !secret.IsReference(c.EncryptionKey) {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"The consistent.encryption-key must be a secret reference, " +
+				"such as ${secret:vault:secret/data/ticdc#redo-key}")
+	}
+
 	uri, err := storage.ParseRawURL(c.Storage)
 	if err != nil {
 		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
@@ -25,6 +25,11 @@ type DebugConfig struct {
 
 	// Scheduler is the configuration of the two-phase scheduler.
 	Scheduler *SchedulerConfig `toml:"scheduler" json:"scheduler"`
+
+	// EnableKafkaSinkV2 is the default of the changefeeds created on this
+	// server which don't set enable-kafka-sink-v2, the kafka-go sink is used
+	// if it's true.
+	EnableKafkaSinkV2 bool `toml:"enable-kafka-sink-v2" json:"enable-kafka-sink-v2"`
 }
 
 // ValidateAndAdjust validates and adjusts the debug configuration
@@ -14,8 +14,11 @@
 package config
 
 import (
+	"fmt"
+
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
 // FilterConfig represents filter config for a changefeed
@@ -24,6 +27,30 @@ type FilterConfig struct {
 	*filter.MySQLReplicationRules
 	IgnoreTxnStartTs []uint64           `toml:"ignore-txn-start-ts" json:"ignore-txn-start-ts"`
 	EventFilters     []*EventFilterRule `toml:"event-filters" json:"event-filters"`
+	// SamplingRate is the fraction of rows replicated to the downstream, such as
+	// 0.01. The rows are sampled by the hash of their handle keys, so a row is
+	// either always or never replicated. Zero means sampling is disabled.
+	SamplingRate float64 `toml:"sampling-rate" json:"sampling-rate,omitempty"`
+	// IgnoreLocalOrigin ignores the rows which are written by a TiCDC sink and
+	// originally come from the upstream cluster itself, so the changes don't
+	// loop back in the active-active topologies across the MQ systems.
+	IgnoreLocalOrigin bool `toml:"ignore-local-origin" json:"ignore-local-origin,omitempty"`
+	// DDLAllowList only replicates the DDLs of these types to the downstream
+	// if it's not empty, such as ["create table", "alter table"].
+	DDLAllowList []bf.EventType `toml:"ddl-allow-list" json:"ddl-allow-list,omitempty"`
+	// DDLDenyList never replicates the DDLs of these types to the downstream,
+	// such as ["drop table", "truncate table"], it takes precedence over the
+	// ddl-allow-list. The skipped DDLs are still applied to the schemas of
+	// the changefeed.
+	DDLDenyList []bf.EventType `toml:"ddl-deny-list" json:"ddl-deny-list,omitempty"`
+}
+
+func (c *FilterConfig) validate() error {
+	if c.SamplingRate < 0 || c.SamplingRate > 1 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("The sampling-rate:%v must be in the range [0, 1], 0 disables sampling", c.SamplingRate))
+	}
+	return nil
 }
 
 // EventFilterRule is used by sql event filter and expression filter
@@ -37,4 +64,10 @@ type EventFilterRule struct {
 	IgnoreUpdateNewValueExpr string `toml:"ignore-update-new-value-expr" json:"ignore-update-new-value-expr"`
 	IgnoreUpdateOldValueExpr string `toml:"ignore-update-old-value-expr" json:"ignore-update-old-value-expr"`
 	IgnoreDeleteValueExpr    string `toml:"ignore-delete-value-expr" json:"ignore-delete-value-expr"`
+	// KeepValueExpr is a sql expression like the WHERE clause, only the rows
+	// satisfying it are replicated. The old value of a deleted row and the new
+	// value of an inserted row are evaluated, an updated row is kept if either
+	// its old or new value satisfies it, so the rows moving out of the subset
+	// are also replicated.
+	KeepValueExpr string `toml:"keep-value-expr" json:"keep-value-expr"`
 }
@@ -36,6 +36,8 @@ const (
 	minSyncPointInterval = time.Second * 30
 	// minSyncPointRetention is the minimum of SyncPointRetention can be set.
 	minSyncPointRetention = time.Hour * 1
+	// minLagSLO is the minimum of LagSLO can be set.
+	minLagSLO = time.Second * 1
 )
 
 var defaultReplicaConfig = &ReplicaConfig{
@@ -64,7 +66,6 @@ var defaultReplicaConfig = &ReplicaConfig{
 		Terminator:                       util.AddressOf(CRLF),
 		DateSeparator:                    util.AddressOf(DateSeparatorDay.String()),
 		EnablePartitionSeparator:         util.AddressOf(true),
-		EnableKafkaSinkV2:                util.AddressOf(false),
 		OnlyOutputUpdatedColumns:         util.AddressOf(false),
 		DeleteOnlyOutputHandleKeyColumns: util.AddressOf(false),
 		TiDBSourceID:                     1,
@@ -75,6 +76,10 @@ var defaultReplicaConfig = &ReplicaConfig{
 		FlushIntervalInMs: redo.DefaultFlushIntervalInMs,
 		Storage:           "",
 		UseFileBackend:    false,
+		FlushWorkerNum:    redo.DefaultFlushWorkerNum,
+		FlushConcurrency:  redo.DefaultFlushConcurrency,
+		Compression:       redo.CompressionNone,
+		EncryptionKey:     "",
 	},
 	Scheduler: &ChangefeedSchedulerConfig{
 		EnableTableAcrossNodes: false,
@@ -136,6 +141,18 @@ type replicaConfig struct {
 	Scheduler *ChangefeedSchedulerConfig `toml:"scheduler" json:"scheduler"`
 	// Integrity is only available when the downstream is MQ.
 	Integrity *integrity.Config `toml:"integrity" json:"integrity"`
+	// LagSLO is the threshold of the checkpoint lag of every table, a warning
+	// is reported to the changefeed once any table lags behind it.
+	LagSLO *time.Duration `toml:"lag-slo" json:"lag-slo,omitempty"`
+	// DelayDuration holds back the events committed in the last duration from
+	// the sink, so the downstream is a delayed replica which can be used to
+	// recover from misoperations. Only the MySQL sink supports it.
+	DelayDuration *time.Duration `toml:"delay-duration" json:"delay-duration,omitempty"`
+	// Snapshot is the configuration of the initial snapshot of the tables.
+	Snapshot *SnapshotConfig `toml:"snapshot" json:"snapshot,omitempty"`
+	// RestartStrategy is the strategy of restarting the changefeed after it
+	// meets retryable errors.
+	RestartStrategy *RestartStrategyConfig `toml:"restart-strategy" json:"restart-strategy,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -207,6 +224,12 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 		}
 	}
 
+	if c.Filter != nil {
+		if err := c.Filter.validate(); err != nil {
+			return err
+		}
+	}
+
 	if c.Consistent != nil {
 		err := c.Consistent.ValidateAndAdjust()
 		if err != nil {
@@ -233,6 +256,37 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 						minSyncPointRetention.String()))
 		}
 	}
+	if c.LagSLO != nil && *c.LagSLO < minLagSLO {
+		return cerror.ErrInvalidReplicaConfig.
+			FastGenByArgs(
+				fmt.Sprintf("The LagSLO:%s must be larger than %s",
+					c.LagSLO.String(), minLagSLO.String()))
+	}
+	if delay := util.GetOrZero(c.DelayDuration); delay < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The delay-duration:%s must be equal or greater than 0", delay))
+	} else if delay > 0 && !sink.IsMySQLCompatibleScheme(strings.ToLower(sinkURI.Scheme)) {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The delay-duration is only supported by the MySQL sink, but the sink is %s",
+				sinkURI.Scheme))
+	}
+	if c.Snapshot != nil {
+		if err := c.Snapshot.validate(); err != nil {
+			return err
+		}
+		// the redo log is replayed from the checkpoint, it can't restore
+		// the snapshot rows which are scanned rather than pulled.
+		if c.Snapshot.Enabled() && c.Consistent != nil &&
+			redo.IsConsistentEnabled(c.Consistent.Level) {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"the snapshot can't be used when the consistent replication is enabled")
+		}
+	}
+	if c.RestartStrategy != nil {
+		if err := c.RestartStrategy.validate(); err != nil {
+			return err
+		}
+	}
 	if c.MemoryQuota == uint64(0) {
 		c.FixMemoryQuota()
 	}
@@ -243,6 +297,18 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 	if !isSinkCompatibleWithSpanReplication(sinkURI) {
 		c.Scheduler.EnableTableAcrossNodes = false
 	}
+	if c.Sink != nil {
+		for _, target := range c.Sink.Targets {
+			if err := c.validateSinkTarget(target); err != nil {
+				return err
+			}
+		}
+		if c.Sink.Mirror != nil {
+			if err := c.validateSinkMirror(); err != nil {
+				return err
+			}
+		}
+	}
 
 	if c.Integrity != nil {
 		switch strings.ToLower(sinkURI.Scheme) {
@@ -257,11 +323,111 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 		if err := c.Integrity.Validate(); err != nil {
 			return err
 		}
+		// the checksum can't be verified if the columns are masked.
+		if c.Integrity.Enabled() && c.Sink != nil && len(c.Sink.ColumnMaskers) > 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"column maskers can't be used when the integrity check is enabled")
+		}
+		if c.Integrity.Enabled() && c.Sink != nil && len(c.Sink.ComputedColumns) > 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"computed columns can't be used when the integrity check is enabled")
+		}
+		if c.Integrity.Enabled() && c.Sink != nil && c.Sink.Transformer != nil {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"the transformer can't be used when the integrity check is enabled")
+		}
+		// the checksum is calculated by the upstream values, which are not the
+		// decoded values of the mapped types.
+		if c.Integrity.Enabled() && c.Sink != nil && len(c.Sink.TypeMappings) > 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"type mappings can't be used when the integrity check is enabled")
+		}
 	}
 
 	return nil
 }
 
+// validateSinkTarget validates the replica config of the sink target by the
+// sink-uri of it, the settings shared by all downstreams must be compatible.
+func (c *ReplicaConfig) validateSinkTarget(target *SinkTarget) error {
+	return c.validateDownstream("sink target", target.SinkURI, c.SinkTargetConfig(target))
+}
+
+// validateSinkMirror validates the replica config of the mirror in the same
+// way as the sink targets.
+func (c *ReplicaConfig) validateSinkMirror() error {
+	return c.validateDownstream("mirror", c.Sink.Mirror.SinkURI, c.SinkMirrorConfig())
+}
+
+func (c *ReplicaConfig) validateDownstream(kind, uri string, cfg *ReplicaConfig) error {
+	sinkURI, err := sink.ParseSinkURI(uri)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if err := cfg.ValidateAndAdjust(sinkURI); err != nil {
+		return err
+	}
+	// the old values are pulled for all downstreams of the changefeed.
+	if cfg.EnableOldValue != c.EnableOldValue {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(fmt.Sprintf(
+			"the %s %s requires enable-old-value to be %t",
+			kind, sinkURI.Redacted(), cfg.EnableOldValue))
+	}
+	if !isSinkCompatibleWithSpanReplication(sinkURI) {
+		c.Scheduler.EnableTableAcrossNodes = false
+	}
+	return nil
+}
+
+// SinkTargetConfig returns the replica config of the sink target, which is a
+// clone of c with the protocol of the target and without the targets and the
+// mirror. The credentials and the transactions of the kafka config aren't
+// inherited, since they belong to the kafka cluster of the changefeed, they're
+// set in the sink-uri of the target instead.
+func (c *ReplicaConfig) SinkTargetConfig(target *SinkTarget) *ReplicaConfig {
+	cfg := c.downstreamConfig(target.SinkURI, target.Protocol)
+	if cfg.Sink.KafkaConfig != nil {
+		cfg.Sink.KafkaConfig.clearCredentials()
+		cfg.Sink.KafkaConfig.EnableKafkaTransactions = nil
+	}
+	return cfg
+}
+
+// downstreamConfig returns a clone of c with the protocol of the downstream
+// and without the targets and the mirror.
+func (c *ReplicaConfig) downstreamConfig(uri string, protocol *string) *ReplicaConfig {
+	cfg := c.Clone()
+	cfg.Sink.Targets = nil
+	cfg.Sink.Mirror = nil
+	if protocol != nil {
+		cfg.Sink.Protocol = util.AddressOf(*protocol)
+		return cfg
+	}
+	sinkURI, err := sink.ParseSinkURI(uri)
+	if err != nil {
+		return cfg
+	}
+	if protocol := sinkURI.Query().Get(ProtocolKey); protocol != "" {
+		cfg.Sink.Protocol = util.AddressOf(protocol)
+	} else if sink.IsMySQLCompatibleScheme(strings.ToLower(sinkURI.Scheme)) {
+		// the protocol of the changefeed doesn't apply to the databases.
+		cfg.Sink.Protocol = nil
+	}
+	return cfg
+}
+
+// SinkMirrorConfig returns the replica config of the mirror, which is a clone
+// of c with the protocol and the kafka sink version of the mirror. The mirror
+// only receives the tables of the sink-uri, so there are no targets in it.
+func (c *ReplicaConfig) SinkMirrorConfig() *ReplicaConfig {
+	mirror := c.Sink.Mirror
+	cfg := c.downstreamConfig(mirror.SinkURI, mirror.Protocol)
+	if mirror.EnableKafkaSinkV2 != nil {
+		cfg.Sink.EnableKafkaSinkV2 = util.AddressOf(*mirror.EnableKafkaSinkV2)
+	}
+	return cfg
+}
+
 // FixScheduler adjusts scheduler to default value
 func (c *ReplicaConfig) FixScheduler(inheritV66 bool) {
 	if c.Scheduler == nil {
@@ -293,9 +459,14 @@ func (c *ReplicaConfig) AdjustEnableOldValue(scheme, protocol string) {
 		return
 	}
 
+	// The avro protocol needs the old values to output only the updated
+	// columns of the update events.
+	diffMode := protocol == ProtocolAvro.String() &&
+		c.Sink != nil && util.GetOrZero(c.Sink.OnlyOutputUpdatedColumns)
+
 	if c.EnableOldValue {
 		_, ok := ForceDisableOldValueProtocols[protocol]
-		if ok {
+		if ok && !diffMode {
 			log.Warn("Attempting to replicate with old value enabled, but the specified protocol must disable old value. "+
 				"CDC will disable old value and continue.", zap.String("protocol", protocol))
 			c.EnableOldValue = false
@@ -308,6 +479,10 @@ func (c *ReplicaConfig) AdjustEnableOldValue(scheme, protocol string) {
 		log.Warn("Attempting to replicate with old value disabled, but the specified protocol must enable old value. "+
 			"CDC will enable old value and continue.", zap.String("protocol", protocol))
 		c.EnableOldValue = true
+	} else if diffMode {
+		log.Warn("Attempting to replicate with old value disabled, but only-output-updated-columns " +
+			"of the avro protocol requires old value. CDC will enable old value and continue.")
+		c.EnableOldValue = true
 	}
 }
 
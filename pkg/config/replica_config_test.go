@@ -17,12 +17,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/integrity"
+	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -256,6 +258,91 @@ func TestValidateAndAdjust(t *testing.T) {
 	cfg.Integrity.IntegrityCheckLevel = integrity.CheckLevelCorrectness
 	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
 	require.Equal(t, integrity.CheckLevelNone, cfg.Integrity.IntegrityCheckLevel)
+
+	cfg = GetDefaultReplicaConfig()
+	cfg.LagSLO = util.AddressOf(time.Millisecond * 500)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.LagSLO = util.AddressOf(time.Second * 30)
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	// delay-duration is only supported by the MySQL sink.
+	cfg = GetDefaultReplicaConfig()
+	cfg.DelayDuration = util.AddressOf(time.Minute * 30)
+	require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "only supported by the MySQL sink")
+	mysqlURL, err := url.Parse("mysql://root@127.0.0.1:3306")
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateAndAdjust(mysqlURL))
+	cfg.DelayDuration = util.AddressOf(-time.Minute)
+	require.Error(t, cfg.ValidateAndAdjust(mysqlURL))
+
+	cfg = GetDefaultReplicaConfig()
+	cfg.Filter.SamplingRate = 1.5
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Filter.SamplingRate = -0.1
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Filter.SamplingRate = 0.01
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	cfg = GetDefaultReplicaConfig()
+	cfg.Snapshot = &SnapshotConfig{Enable: util.AddressOf(true)}
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, DefaultSnapshotChunkSize, cfg.Snapshot.GetChunkSize())
+	cfg.Snapshot.ChunkSize = util.AddressOf(0)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Snapshot.ChunkSize = util.AddressOf(100)
+	cfg.Snapshot.RateLimit = util.AddressOf(-1)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Snapshot.RateLimit = util.AddressOf(1000)
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.Level = "eventual"
+	cfg.Consistent.Storage = "file:///tmp/redo"
+	require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "consistent replication")
+
+	cfg = GetDefaultReplicaConfig()
+	cfg.RestartStrategy = &RestartStrategyConfig{}
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, DefaultRestartInitialBackoff, cfg.RestartStrategy.GetInitialBackoff())
+	require.Equal(t, DefaultRestartMaxElapsedTime, cfg.RestartStrategy.GetMaxElapsedTime())
+	cfg.RestartStrategy.MaxRetries = util.AddressOf(-1)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.RestartStrategy.MaxRetries = util.AddressOf(3)
+	cfg.RestartStrategy.InitialBackoff = util.AddressOf(time.Minute)
+	cfg.RestartStrategy.MaxBackoff = util.AddressOf(time.Second)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.RestartStrategy.MaxBackoff = util.AddressOf(time.Hour)
+	cfg.RestartStrategy.BackoffMultiplier = util.AddressOf(0.5)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.RestartStrategy.BackoffMultiplier = util.AddressOf(1.5)
+	cfg.RestartStrategy.ResetWindow = util.AddressOf(-time.Second)
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.RestartStrategy.ResetWindow = util.AddressOf(time.Hour)
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	cfg = GetDefaultReplicaConfig()
+	cfg.Consistent.Level = "eventual"
+	cfg.Consistent.Storage = "file:///tmp/redo"
+	cfg.Consistent.FlushWorkerNum = 0
+	cfg.Consistent.FlushConcurrency = 0
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, redo.DefaultFlushWorkerNum, cfg.Consistent.FlushWorkerNum)
+	require.Equal(t, redo.DefaultFlushConcurrency, cfg.Consistent.FlushConcurrency)
+	cfg.Consistent.FlushWorkerNum = -1
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.FlushWorkerNum = 4
+	cfg.Consistent.FlushConcurrency = -1
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.FlushConcurrency = 16
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.Compression = "gzip"
+	require.Error(t, cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.Compression = ""
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, redo.CompressionNone, cfg.Consistent.Compression)
+	cfg.Consistent.Compression = redo.CompressionZstd
+	cfg.Consistent.EncryptionKey = strings.Repeat("ab", 32)
+	require.ErrorContains(t, cfg.ValidateAndAdjust(sinkURL), "secret reference")
+	cfg.Consistent.EncryptionKey = "${secret:vault:secret/data/ticdc#redo-key}"
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
 }
 
 func TestIsSinkCompatibleWithSpanReplication(t *testing.T) {
@@ -370,6 +457,18 @@ func TestAdjustEnableOldValueAndVerifyForceReplicate(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, config.EnableOldValue)
 
+	// avro, `only-output-updated-columns` true, `enable-old-value` is kept or set to true.
+	config.ForceReplicate = false
+	config.Sink.OnlyOutputUpdatedColumns = util.AddressOf(true)
+	err = config.adjustEnableOldValueAndVerifyForceReplicate(sinkURI)
+	require.NoError(t, err)
+	require.True(t, config.EnableOldValue)
+	config.EnableOldValue = true
+	err = config.adjustEnableOldValueAndVerifyForceReplicate(sinkURI)
+	require.NoError(t, err)
+	require.True(t, config.EnableOldValue)
+	config.Sink.OnlyOutputUpdatedColumns = nil
+
 	// csv, `enable-old-value` false, `force-replicate` false, no error
 	config.EnableOldValue = false
 	config.ForceReplicate = false
@@ -387,3 +486,135 @@ func TestAdjustEnableOldValueAndVerifyForceReplicate(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, config.EnableOldValue)
 }
+
+func TestValidateSinkTargets(t *testing.T) {
+	t.Parallel()
+
+	kafkaURL, err := url.Parse("kafka://127.0.0.1:9092/topic?protocol=canal-json")
+	require.NoError(t, err)
+
+	cfg := GetDefaultReplicaConfig()
+	cfg.Scheduler.EnableTableAcrossNodes = true
+	cfg.Sink.Targets = []*SinkTarget{{Matcher: []string{"test.*"}, SinkURI: "blackhole://"}}
+	require.NoError(t, cfg.ValidateAndAdjust(kafkaURL))
+	require.True(t, cfg.Scheduler.EnableTableAcrossNodes)
+
+	// the tables can't be split if any downstream doesn't support it.
+	cfg.Sink.Targets = append(cfg.Sink.Targets, &SinkTarget{
+		Matcher: []string{"db.*"},
+		SinkURI: "mysql://root@127.0.0.1:3306",
+	})
+	require.NoError(t, cfg.ValidateAndAdjust(kafkaURL))
+	require.False(t, cfg.Scheduler.EnableTableAcrossNodes)
+
+	// the csv protocol requires disabling the old values, which are enabled
+	// for the canal-json protocol.
+	cfg.Sink.Targets = []*SinkTarget{{
+		Matcher: []string{"test.*"},
+		SinkURI: "file:///tmp/cdc?protocol=csv",
+	}}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "requires enable-old-value to be false")
+
+	cfg.Sink.Targets = []*SinkTarget{{Matcher: []string{"test.*"}}}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "sink-uri of the sink target")
+}
+
+func TestSinkTargetConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := GetDefaultReplicaConfig()
+	cfg.Sink.Protocol = util.AddressOf("canal-json")
+	cfg.Sink.Targets = []*SinkTarget{{Matcher: []string{"test.*"}, SinkURI: "blackhole://"}}
+
+	targetCfg := cfg.SinkTargetConfig(cfg.Sink.Targets[0])
+	require.Nil(t, targetCfg.Sink.Targets)
+	require.Equal(t, "canal-json", util.GetOrZero(targetCfg.Sink.Protocol))
+	require.Len(t, cfg.Sink.Targets, 1)
+
+	targetCfg = cfg.SinkTargetConfig(&SinkTarget{SinkURI: "kafka://127.0.0.1:9092/t?protocol=avro"})
+	require.Equal(t, "avro", util.GetOrZero(targetCfg.Sink.Protocol))
+
+	targetCfg = cfg.SinkTargetConfig(&SinkTarget{
+		SinkURI:  "kafka://127.0.0.1:9092/t?protocol=avro",
+		Protocol: util.AddressOf("open-protocol"),
+	})
+	require.Equal(t, "open-protocol", util.GetOrZero(targetCfg.Sink.Protocol))
+
+	targetCfg = cfg.SinkTargetConfig(&SinkTarget{SinkURI: "mysql://root@127.0.0.1:3306"})
+	require.Nil(t, targetCfg.Sink.Protocol)
+
+	// the credentials and the transactions of the changefeed aren't inherited.
+	cfg.Sink.KafkaConfig = &KafkaConfig{
+		SASLUser:                util.AddressOf("user"),
+		SASLPassword:            util.AddressOf("password"),
+		SASLMechanism:           util.AddressOf("plain"),
+		CA:                      util.AddressOf("ca.pem"),
+		EnableTLS:               util.AddressOf(true),
+		EnableKafkaTransactions: util.AddressOf(true),
+		MaxInflight:             util.AddressOf(10),
+	}
+	targetCfg = cfg.SinkTargetConfig(&SinkTarget{SinkURI: "kafka://127.0.0.1:9093/t?protocol=avro"})
+	require.Equal(t, &KafkaConfig{
+		EnableTLS:   util.AddressOf(true),
+		MaxInflight: util.AddressOf(10),
+	}, targetCfg.Sink.KafkaConfig)
+	require.Equal(t, "user", util.GetOrZero(cfg.Sink.KafkaConfig.SASLUser))
+	require.True(t, util.GetOrZero(cfg.Sink.KafkaConfig.EnableKafkaTransactions))
+}
+
+func TestValidateSinkMirror(t *testing.T) {
+	t.Parallel()
+
+	kafkaURL, err := url.Parse("kafka://127.0.0.1:9092/topic?protocol=canal-json")
+	require.NoError(t, err)
+
+	cfg := GetDefaultReplicaConfig()
+	cfg.Scheduler.EnableTableAcrossNodes = true
+	cfg.Sink.Mirror = &SinkMirror{
+		SinkURI:           "kafka://127.0.0.1:9093/topic?protocol=canal-json",
+		EnableKafkaSinkV2: util.AddressOf(true),
+	}
+	require.NoError(t, cfg.ValidateAndAdjust(kafkaURL))
+	require.True(t, cfg.Scheduler.EnableTableAcrossNodes)
+
+	// the kafka-go client of the mirror doesn't support the transactions.
+	cfg.Sink.KafkaConfig = &KafkaConfig{EnableKafkaTransactions: util.AddressOf(true)}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "enable-kafka-transactions")
+	cfg.Sink.KafkaConfig = nil
+
+	cfg.Sink.Mirror = &SinkMirror{SinkURI: "mysql://root@127.0.0.1:3306"}
+	require.NoError(t, cfg.ValidateAndAdjust(kafkaURL))
+	require.False(t, cfg.Scheduler.EnableTableAcrossNodes)
+
+	cfg.Sink.Mirror = &SinkMirror{SinkURI: "file:///tmp/cdc?protocol=csv"}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "the mirror file:///tmp/cdc?protocol=csv requires")
+
+	cfg.Sink.Mirror = &SinkMirror{SinkURI: kafkaURL.String()}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "the same as the sink-uri")
+
+	cfg.Sink.Mirror = &SinkMirror{}
+	require.ErrorContains(t, cfg.ValidateAndAdjust(kafkaURL), "sink-uri of the mirror is empty")
+}
+
+func TestSinkMirrorConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := GetDefaultReplicaConfig()
+	cfg.Sink.Protocol = util.AddressOf("canal-json")
+	cfg.Sink.Targets = []*SinkTarget{{Matcher: []string{"test.*"}, SinkURI: "blackhole://"}}
+	cfg.Sink.Mirror = &SinkMirror{
+		SinkURI:           "kafka://127.0.0.1:9092/t?protocol=avro",
+		EnableKafkaSinkV2: util.AddressOf(true),
+	}
+
+	mirrorCfg := cfg.SinkMirrorConfig()
+	require.Nil(t, mirrorCfg.Sink.Targets)
+	require.Nil(t, mirrorCfg.Sink.Mirror)
+	require.Equal(t, "avro", util.GetOrZero(mirrorCfg.Sink.Protocol))
+	require.True(t, mirrorCfg.Sink.KafkaSinkV2Enabled())
+	require.NotNil(t, cfg.Sink.Mirror)
+	require.Nil(t, cfg.Sink.EnableKafkaSinkV2)
+
+	// the targets don't mirror the events.
+	require.Nil(t, cfg.SinkTargetConfig(cfg.Sink.Targets[0]).Sink.Mirror)
+}
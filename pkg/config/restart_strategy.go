@@ -0,0 +1,141 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+const (
+	// DefaultRestartInitialBackoff is the default interval before the first
+	// restart of a changefeed.
+	DefaultRestartInitialBackoff = 10 * time.Second
+	// DefaultRestartMaxBackoff is the default max interval between restarts.
+	DefaultRestartMaxBackoff = 10 * time.Minute
+	// DefaultRestartBackoffMultiplier is the default multiplier of the
+	// interval after each restart.
+	DefaultRestartBackoffMultiplier = 2.0
+	// DefaultRestartMaxElapsedTime is the default max duration a changefeed
+	// keeps restarting before it's failed.
+	DefaultRestartMaxElapsedTime = 30 * time.Minute
+)
+
+// RestartStrategyConfig represents the strategy of restarting a changefeed
+// after it meets retryable errors. The interval between restarts grows
+// exponentially from InitialBackoff to MaxBackoff.
+type RestartStrategyConfig struct {
+	// MaxRetries is the max number of restarts before the changefeed is
+	// failed, zero means unlimited.
+	MaxRetries *int `toml:"max-retries" json:"max-retries,omitempty"`
+	// InitialBackoff is the interval before the first restart.
+	InitialBackoff *time.Duration `toml:"initial-backoff" json:"initial-backoff,omitempty"`
+	// MaxBackoff is the max interval between restarts.
+	MaxBackoff *time.Duration `toml:"max-backoff" json:"max-backoff,omitempty"`
+	// BackoffMultiplier is the multiplier of the interval after each restart.
+	BackoffMultiplier *float64 `toml:"backoff-multiplier" json:"backoff-multiplier,omitempty"`
+	// MaxElapsedTime is the max duration the changefeed keeps restarting
+	// before it's failed, zero means unlimited.
+	MaxElapsedTime *time.Duration `toml:"max-elapsed-time" json:"max-elapsed-time,omitempty"`
+	// ResetWindow is the min duration the changefeed must run normally before
+	// the retries and the backoff are reset.
+	ResetWindow *time.Duration `toml:"reset-window" json:"reset-window,omitempty"`
+}
+
+// GetMaxRetries returns the max number of restarts, zero means unlimited.
+func (c *RestartStrategyConfig) GetMaxRetries() int {
+	if c == nil {
+		return 0
+	}
+	return util.GetOrZero(c.MaxRetries)
+}
+
+// GetInitialBackoff returns the interval before the first restart.
+func (c *RestartStrategyConfig) GetInitialBackoff() time.Duration {
+	if c == nil || c.InitialBackoff == nil {
+		return DefaultRestartInitialBackoff
+	}
+	return *c.InitialBackoff
+}
+
+// GetMaxBackoff returns the max interval between restarts.
+func (c *RestartStrategyConfig) GetMaxBackoff() time.Duration {
+	if c == nil || c.MaxBackoff == nil {
+		return DefaultRestartMaxBackoff
+	}
+	return *c.MaxBackoff
+}
+
+// GetBackoffMultiplier returns the multiplier of the interval.
+func (c *RestartStrategyConfig) GetBackoffMultiplier() float64 {
+	if c == nil || c.BackoffMultiplier == nil {
+		return DefaultRestartBackoffMultiplier
+	}
+	return *c.BackoffMultiplier
+}
+
+// GetMaxElapsedTime returns the max duration the changefeed keeps restarting,
+// zero means unlimited.
+func (c *RestartStrategyConfig) GetMaxElapsedTime() time.Duration {
+	if c == nil || c.MaxElapsedTime == nil {
+		return DefaultRestartMaxElapsedTime
+	}
+	return *c.MaxElapsedTime
+}
+
+// GetResetWindow returns the min duration the changefeed must run normally
+// before the retries are reset, zero means it's not limited by the duration.
+func (c *RestartStrategyConfig) GetResetWindow() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return util.GetOrZero(c.ResetWindow)
+}
+
+func (c *RestartStrategyConfig) validate() error {
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.max-retries:%d must be equal or greater than 0",
+				*c.MaxRetries))
+	}
+	if c.GetInitialBackoff() <= 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.initial-backoff:%s must be greater than 0",
+				c.GetInitialBackoff()))
+	}
+	if c.GetMaxBackoff() < c.GetInitialBackoff() {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.max-backoff:%s must be equal or greater than "+
+				"the initial-backoff:%s", c.GetMaxBackoff(), c.GetInitialBackoff()))
+	}
+	if c.GetBackoffMultiplier() < 1 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.backoff-multiplier:%v must be equal or greater than 1",
+				c.GetBackoffMultiplier()))
+	}
+	if c.GetMaxElapsedTime() < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.max-elapsed-time:%s must be equal or greater than 0",
+				c.GetMaxElapsedTime()))
+	}
+	if c.GetResetWindow() < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The restart-strategy.reset-window:%s must be equal or greater than 0",
+				c.GetResetWindow()))
+	}
+	return nil
+}
@@ -142,6 +142,10 @@ var defaultServerConfig = &ServerConfig{
 
 		Scheduler: NewDefaultSchedulerConfig(),
 	},
+	Tracing: &TracingConfig{
+		Enable:      false,
+		SampleRatio: 0.01,
+	},
 	ClusterID:           "default",
 	MaxMemoryPercentage: DefaultMaxMemoryPercentage,
 }
@@ -172,6 +176,7 @@ type ServerConfig struct {
 	PerTableMemoryQuota uint64          `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
 	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
 	Debug               *DebugConfig    `toml:"debug" json:"debug"`
+	Tracing             *TracingConfig  `toml:"tracing" json:"tracing"`
 	ClusterID           string          `toml:"cluster-id" json:"cluster-id"`
 	MaxMemoryPercentage int             `toml:"max-memory-percentage" json:"max-memory-percentage"`
 }
@@ -284,6 +289,13 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	if err = c.Debug.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
+
+	if c.Tracing == nil {
+		c.Tracing = defaultCfg.Tracing
+	}
+	if err = c.Tracing.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
 	if c.MaxMemoryPercentage >= 100 {
 		log.Warn("server max-memory-percentage must be less than 100, set to default value")
 		c.MaxMemoryPercentage = DefaultMaxMemoryPercentage
@@ -101,6 +101,19 @@ func TestKVClientConfigValidateAndAdjust(t *testing.T) {
 	require.Error(t, conf.ValidateAndAdjust())
 }
 
+func TestTracingConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Tracing
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.Enable = true
+	require.Error(t, conf.ValidateAndAdjust())
+	conf.Endpoint = "127.0.0.1:4317"
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.SampleRatio = 1.5
+	require.Error(t, conf.ValidateAndAdjust())
+}
+
 func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
 	t.Parallel()
 	conf := GetDefaultServerConfig().Clone().Debug.Scheduler
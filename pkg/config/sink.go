@@ -16,10 +16,13 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	filter "github.com/pingcap/tidb/util/table-filter"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -64,10 +67,66 @@ const (
 	// DefaultFileIndexWidth is the default width of file index.
 	DefaultFileIndexWidth = MaxFileIndexWidth
 
+	// FileNamePlaceholderSchema is replaced by the schema name in the file name template.
+	FileNamePlaceholderSchema = "{schema}"
+	// FileNamePlaceholderTable is replaced by the table name in the file name template.
+	FileNamePlaceholderTable = "{table}"
+	// FileNamePlaceholderDate is replaced by the date in the file name template.
+	FileNamePlaceholderDate = "{date}"
+	// FileNamePlaceholderIndex is replaced by the file index in the file name template.
+	FileNamePlaceholderIndex = "{index}"
+	// FileNamePlaceholderExtension is replaced by the file extension in the file name template.
+	FileNamePlaceholderExtension = "{extension}"
+	// DefaultFileNameTemplate is the default template of the data file names.
+	DefaultFileNameTemplate = "CDC" + FileNamePlaceholderIndex + FileNamePlaceholderExtension
+
 	// BinaryEncodingHex encodes binary data to hex string.
 	BinaryEncodingHex = "hex"
 	// BinaryEncodingBase64 encodes binary data to base64 string.
 	BinaryEncodingBase64 = "base64"
+
+	// QuotingPolicyAlways quotes all the csv columns except NULL.
+	QuotingPolicyAlways = "always"
+	// QuotingPolicyMinimal only quotes the csv columns which contain special
+	// characters, such as the delimiter, quote, escape character or line breaks.
+	QuotingPolicyMinimal = "minimal"
+	// QuotingPolicyNonNumeric quotes all the non-numeric csv columns, it's the
+	// default quoting policy.
+	QuotingPolicyNonNumeric = "non-numeric"
+
+	// CSVHeaderNone writes no header row into the csv data files.
+	CSVHeaderNone = "none"
+	// CSVHeaderEveryFile writes the header row at the beginning of each csv
+	// data file.
+	CSVHeaderEveryFile = "every-file"
+	// CSVHeaderFirstFile only writes the header row into the first data file
+	// of each data directory. The data files of a new table version are written
+	// into a new directory, so the header is refreshed after the schema of the
+	// table changes.
+	CSVHeaderFirstFile = "first-file"
+
+	// ParquetCompressionNone writes the parquet files without compression.
+	ParquetCompressionNone = "none"
+	// ParquetCompressionSnappy compresses the parquet files with snappy, it's the
+	// default compression codec.
+	ParquetCompressionSnappy = "snappy"
+	// ParquetCompressionZstd compresses the parquet files with zstd.
+	ParquetCompressionZstd = "zstd"
+
+	// FileCompressionNone writes the data files of the storage sink without
+	// compression, it's the default compression codec.
+	FileCompressionNone = "none"
+	// FileCompressionGzip compresses the data files of the storage sink with gzip.
+	FileCompressionGzip = "gzip"
+	// FileCompressionZstd compresses the data files of the storage sink with zstd.
+	FileCompressionZstd = "zstd"
+
+	// PartitionLayoutDefault names the date directories of the storage sink by
+	// the bare dates, it's the default partition layout.
+	PartitionLayoutDefault = "default"
+	// PartitionLayoutHive names the date directories of the storage sink as
+	// "dt={date}", so they can be mounted as the partitions of Hive tables.
+	PartitionLayoutHive = "hive"
 )
 
 // AtomicityLevel represents the atomicity level of a changefeed.
@@ -104,12 +163,15 @@ var ForceEnableOldValueProtocols = map[string]struct{}{
 	ProtocolCanal.String():     {},
 	ProtocolCanalJSON.String(): {},
 	ProtocolMaxwell.String():   {},
+	ProtocolDebezium.String():  {},
 }
 
 // ForceDisableOldValueProtocols specifies protocols need to be forced to disable old value.
 var ForceDisableOldValueProtocols = map[string]struct{}{
-	ProtocolAvro.String(): {},
-	ProtocolCsv.String():  {},
+	ProtocolAvro.String():       {},
+	ProtocolCsv.String():        {},
+	ProtocolParquet.String():    {},
+	ProtocolJSONSchema.String(): {},
 }
 
 // SinkConfig represents sink config for a changefeed
@@ -122,10 +184,50 @@ type SinkConfig struct {
 	DispatchRules []*DispatchRule `toml:"dispatchers" json:"dispatchers,omitempty"`
 	// CSVConfig is only available when the downstream is Storage.
 	CSVConfig *CSVConfig `toml:"csv" json:"csv,omitempty"`
-	// ColumnSelectors is Deprecated.
+	// ColumnSelectors is only available when the downstream is MQ using the
+	// open-protocol, canal-json or avro protocol, only the selected columns of
+	// the matched tables are sent to the downstream.
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors,omitempty"`
+	// ColumnMaskers is available for all kinds of downstream, the matched columns
+	// are masked before they are written to the downstream.
+	ColumnMaskers []*ColumnMasker `toml:"column-maskers" json:"column-maskers,omitempty"`
+	// ComputedColumns is available for all kinds of downstream, the computed
+	// columns are appended to the rows of the matched tables before they are
+	// encoded. The downstream tables must have these columns if the downstream
+	// is a database.
+	ComputedColumns []*ComputedColumn `toml:"computed-columns" json:"computed-columns,omitempty"`
+	// Targets route the events of the matched tables to other downstreams than
+	// the sink-uri, all of them share the puller and the sorter of the
+	// changefeed. A table is written to the first target matching it, and the
+	// tables matched by no target are written to the sink-uri.
+	Targets []*SinkTarget `toml:"targets" json:"targets,omitempty"`
+	// Mirror writes the events of the sink-uri to another downstream as well,
+	// so that the changefeed can be cut over to it without downtime once it
+	// catches up. The checkpoint of the changefeed is the slower one of them.
+	Mirror *SinkMirror `toml:"mirror" json:"mirror,omitempty"`
+	// TableRoutes rename the matched tables and their columns in the
+	// downstream, including the tables of the databases and the topics and the
+	// paths of the MQ and storage sinks. The sink targets match the upstream
+	// names, while the dispatchers and the column selectors match the
+	// downstream names. A table is renamed by the first route matching it.
+	TableRoutes []*TableRoute `toml:"table-routes" json:"table-routes,omitempty"`
+	// Transformer is available for all kinds of downstream, the rows of the
+	// matched tables are modified or dropped by a WASM module before they are
+	// written to the downstream.
+	Transformer *TransformerConfig `toml:"transformer" json:"transformer,omitempty"`
+	// TypeMappings overrides how the columns of the TiDB types are encoded, such
+	// as encoding BIT as the integers. It's only available for the avro and
+	// json-schema protocols.
+	TypeMappings []*TypeMapping `toml:"type-mappings" json:"type-mappings,omitempty"`
+	// TemporalEncoding specifies how the DATETIME and TIMESTAMP columns are
+	// encoded, the values are the strings in the timezone of the TiCDC server
+	// if it's not set.
+	TemporalEncoding *TemporalEncodingConfig `toml:"temporal-encoding" json:"temporal-encoding,omitempty"`
 	// SchemaRegistry is only available when the downstream is MQ using avro protocol.
 	SchemaRegistry *string `toml:"schema-registry" json:"schema-registry,omitempty"`
+	// SchemaRegistryConfig is the provider, authentication, TLS and subject
+	// naming of the schema registry used by the avro protocol.
+	SchemaRegistryConfig *SchemaRegistryConfig `toml:"schema-registry-config" json:"schema-registry-config,omitempty"`
 	// EncoderConcurrency is only available when the downstream is MQ.
 	EncoderConcurrency *int `toml:"encoder-concurrency" json:"encoder-concurrency,omitempty"`
 	// Terminator is NOT available when the downstream is DB.
@@ -136,28 +238,73 @@ type SinkConfig struct {
 	EnablePartitionSeparator *bool `toml:"enable-partition-separator" json:"enable-partition-separator,omitempty"`
 	// FileIndexWidth is only available when the downstream is Storage
 	FileIndexWidth *int `toml:"file-index-digit,omitempty" json:"file-index-digit,omitempty"`
+	// PartitionLayout is only available when the downstream is Storage,
+	// the value can be "default" or "hive".
+	PartitionLayout *string `toml:"partition-layout" json:"partition-layout,omitempty"`
 
 	// EnableKafkaSinkV2 enabled then the kafka-go sink will be used.
 	// It is only available when the downstream is MQ.
+	// It's set to the default of the server when the changefeed is created.
 	EnableKafkaSinkV2 *bool `toml:"enable-kafka-sink-v2" json:"enable-kafka-sink-v2,omitempty"`
 
 	// OnlyOutputUpdatedColumns is only available when the downstream is MQ.
+	// The open-protocol and canal-json messages only carry the old values of
+	// the updated columns. The avro messages of the update events only carry
+	// the updated columns and the handle key columns, the other columns are
+	// null, and the bitmap of the updated columns is carried in the
+	// "_tidb_updated_columns" field.
 	OnlyOutputUpdatedColumns *bool `toml:"only-output-updated-columns" json:"only-output-updated-columns,omitempty"`
 
 	// DeleteOnlyOutputHandleKeyColumns is only available when the downstream is MQ.
 	DeleteOnlyOutputHandleKeyColumns *bool `toml:"delete-only-output-handle-key-columns" json:"delete-only-output-handle-key-columns,omitempty"`
 
+	// DeleteAsTombstone emits the delete events as the tombstones, whose values
+	// are null and keys are the handle keys, so the compacted topics represent
+	// the state of the tables. It's only available for the canal-json and avro
+	// protocol, and the update events changing the handle keys are split into
+	// the delete and insert events.
+	DeleteAsTombstone *bool `toml:"delete-as-tombstone" json:"delete-as-tombstone,omitempty"`
+
+	// OutputPhysicalTime outputs the commit physical time and the ingestion time
+	// as explicit fields, it's only available for the open-protocol, canal-json and csv.
+	OutputPhysicalTime *bool `toml:"output-physical-time" json:"output-physical-time,omitempty"`
+
+	// CanalJSONFlat encodes each row into a flat JSON object, whose columns are
+	// at the top level and metadata is under the "_cdc" key, instead of the nested
+	// data arrays. It's only available for the canal-json protocol.
+	CanalJSONFlat *bool `toml:"canal-json-flat" json:"canal-json-flat,omitempty"`
+
+	// MessageHeaders is only available when the downstream is MQ, the headers
+	// are attached to every message produced to the MQ system.
+	MessageHeaders *MessageHeadersConfig `toml:"message-headers" json:"message-headers,omitempty"`
+
+	// Bootstrap is only available when the downstream is MQ, the schema of each
+	// table is sent before its rows, so the consumers can initialize without TiDB.
+	Bootstrap *BootstrapConfig `toml:"bootstrap" json:"bootstrap,omitempty"`
+
+	// TableMetrics labels the sink metrics by the tables, so the table which slows
+	// down the sink can be identified. It's only available for the MQ and storage sinks.
+	TableMetrics *TableMetricsConfig `toml:"table-metrics" json:"table-metrics,omitempty"`
+
+	// TableSinkBuffer limits the size of the events buffered by the table sinks
+	// before they're resolved, the buffers are unlimited if it's not set.
+	TableSinkBuffer *TableSinkBufferConfig `toml:"table-sink-buffer" json:"table-sink-buffer,omitempty"`
+
 	// TiDBSourceID is the source ID of the upstream TiDB,
 	// which is used to set the `tidb_cdc_write_source` session variable.
 	// Note: This field is only used internally and only used in the MySQL sink.
 	TiDBSourceID uint64 `toml:"-" json:"-"`
 
 	// SafeMode is only available when the downstream is DB.
-	SafeMode           *bool               `toml:"safe-mode" json:"safe-mode,omitempty"`
-	KafkaConfig        *KafkaConfig        `toml:"kafka-config" json:"kafka-config,omitempty"`
-	PulsarConfig       *PulsarConfig       `toml:"pulsar-config" json:"pulsar-config,omitempty"`
-	MySQLConfig        *MySQLConfig        `toml:"mysql-config" json:"mysql-config,omitempty"`
-	CloudStorageConfig *CloudStorageConfig `toml:"cloud-storage-config" json:"cloud-storage-config,omitempty"`
+	SafeMode            *bool                `toml:"safe-mode" json:"safe-mode,omitempty"`
+	KafkaConfig         *KafkaConfig         `toml:"kafka-config" json:"kafka-config,omitempty"`
+	PulsarConfig        *PulsarConfig        `toml:"pulsar-config" json:"pulsar-config,omitempty"`
+	MySQLConfig         *MySQLConfig         `toml:"mysql-config" json:"mysql-config,omitempty"`
+	CloudStorageConfig  *CloudStorageConfig  `toml:"cloud-storage-config" json:"cloud-storage-config,omitempty"`
+	ClickHouseConfig    *ClickHouseConfig    `toml:"clickhouse-config" json:"clickhouse-config,omitempty"`
+	ElasticsearchConfig *ElasticsearchConfig `toml:"elasticsearch-config" json:"elasticsearch-config,omitempty"`
+	RedisConfig         *RedisConfig         `toml:"redis-config" json:"redis-config,omitempty"`
+	WebhookConfig       *WebhookConfig       `toml:"webhook-config" json:"webhook-config,omitempty"`
 }
 
 // CSVConfig defines a series of configuration items for csv codec.
@@ -172,6 +319,56 @@ type CSVConfig struct {
 	IncludeCommitTs bool `toml:"include-commit-ts" json:"include-commit-ts"`
 	// encoding method of binary type
 	BinaryEncodingMethod string `toml:"binary-encoding-method" json:"binary-encoding-method"`
+	// IncludeHeader specifies the data files which have a header row with the
+	// column names, can be none, every-file or first-file, none by default.
+	IncludeHeader string `toml:"include-header" json:"include-header,omitempty"`
+	// whether to output a schema file along with the data files of each table version
+	OutputSchemaSidecar bool `toml:"output-schema-sidecar" json:"output-schema-sidecar"`
+	// quoting policy of the csv columns, can be always, minimal or non-numeric
+	QuotingPolicy string `toml:"quoting-policy" json:"quoting-policy"`
+	// escaping character of the csv columns, it's distinct from the quote
+	EscapeChar string `toml:"escape-char" json:"escape-char"`
+	// ColumnRules specifies the columns and their order of the matched tables,
+	// the first matched one is used if a table matches several ones.
+	ColumnRules []*CSVColumnRule `toml:"column-rules" json:"column-rules,omitempty"`
+}
+
+// CSVColumnRule represents the csv columns of the tables matched by the matcher.
+// The columns are output in the given order, the other columns are dropped and
+// the columns which don't exist in the table are output as null.
+type CSVColumnRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Columns []string `toml:"columns" json:"columns"`
+}
+
+func (r *CSVColumnRule) validate() error {
+	if len(r.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the csv column rule is empty")
+	}
+	if len(r.Columns) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the columns of the csv column rule %v is empty", r.Matcher)
+	}
+	columns := make(map[string]struct{}, len(r.Columns))
+	for _, col := range r.Columns {
+		name := strings.ToLower(col)
+		if _, ok := columns[name]; ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"duplicated column %s in the csv column rule %v", col, r.Matcher)
+		}
+		columns[name] = struct{}{}
+	}
+	return nil
+}
+
+// HeaderPolicy returns the data files which have a header row, none of them
+// has if include-header is not set.
+func (c *CSVConfig) HeaderPolicy() string {
+	if c.IncludeHeader != "" {
+		return c.IncludeHeader
+	}
+	return CSVHeaderNone
 }
 
 func (c *CSVConfig) validateAndAdjust() error {
@@ -207,6 +404,42 @@ func (c *CSVConfig) validateAndAdjust() error {
 			errors.New("csv config quote and delimiter cannot be the same"))
 	}
 
+	// validate quoting policy
+	switch c.QuotingPolicy {
+	case "", QuotingPolicyAlways, QuotingPolicyMinimal, QuotingPolicyNonNumeric:
+	default:
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New("csv config quoting-policy can only be always, minimal or non-numeric"))
+	}
+
+	// validate header policy
+	switch c.IncludeHeader {
+	case "", CSVHeaderNone, CSVHeaderEveryFile, CSVHeaderFirstFile:
+	default:
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New("csv config include-header can only be none, every-file or first-file"))
+	}
+
+	// validate escape character
+	if len(c.EscapeChar) > 1 {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+			errors.New("csv config escape-char contains more than one character"))
+	}
+	if len(c.EscapeChar) == 1 {
+		if c.EscapeChar[0] == CR || c.EscapeChar[0] == LF {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New("csv config escape-char cannot be line break character"))
+		}
+		if c.EscapeChar == c.Quote {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New("csv config escape-char and quote cannot be the same"))
+		}
+		if strings.Contains(c.Delimiter, c.EscapeChar) {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig,
+				errors.New("csv config escape-char and delimiter cannot be the same"))
+		}
+	}
+
 	// validate binary encoding method
 	switch c.BinaryEncodingMethod {
 	case BinaryEncodingHex, BinaryEncodingBase64:
@@ -215,6 +448,12 @@ func (c *CSVConfig) validateAndAdjust() error {
 			errors.New("csv config binary-encoding-method can only be hex or base64"))
 	}
 
+	for _, r := range c.ColumnRules {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -271,14 +510,547 @@ type DispatchRule struct {
 	// In the future release, the DispatcherRule is expected to be removed .
 	PartitionRule string `toml:"partition" json:"partition"`
 	TopicRule     string `toml:"topic" json:"topic"`
+	// PartitionPlugin is the path of a Go plugin which computes the partitions,
+	// the PartitionRule is ignored if it's set.
+	PartitionPlugin string `toml:"partition-plugin" json:"partition-plugin,omitempty"`
+	// KeyTemplate is the template of the keys of the messages of the matched
+	// tables, such as "{schema}.{table}:{id}", it's independent of the
+	// partition rule. "{pk}" is substituted by the handle key values, and the
+	// other placeholders except "{schema}" and "{table}" refer to the columns.
+	// The keys are set by the protocol if it's empty.
+	KeyTemplate string `toml:"key-template" json:"key-template,omitempty"`
+	// TopicPartitionNum and TopicConfigs override the partition number and the
+	// topic level configurations, such as "retention.ms", of the topics which
+	// are auto-created for the rule, they're only supported by the kafka sink.
+	TopicPartitionNum int32             `toml:"topic-partition-num" json:"topic-partition-num,omitempty"`
+	TopicConfigs      map[string]string `toml:"topic-configs" json:"topic-configs,omitempty"`
+}
+
+// HasTopicOverrides returns true if the configurations of the topics created
+// for the rule are overridden.
+func (r *DispatchRule) HasTopicOverrides() bool {
+	return r.TopicPartitionNum != 0 || len(r.TopicConfigs) != 0
+}
+
+func (r *DispatchRule) validateTopicOverrides() error {
+	if !r.HasTopicOverrides() {
+		return nil
+	}
+	if r.TopicRule == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the topic of the dispatch rule %v is empty, but the topic configurations are overridden",
+			r.Matcher)
+	}
+	if r.TopicPartitionNum < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"topic-partition-num of the dispatch rule %v should be positive, got %d",
+			r.Matcher, r.TopicPartitionNum)
+	}
+	return validateTopicConfigs(r.TopicConfigs)
+}
+
+// validateTopicConfigs checks the names of the topic level configurations,
+// their values are checked by the brokers when the topics are created.
+func validateTopicConfigs(configs map[string]string) error {
+	for name := range configs {
+		if strings.TrimSpace(name) == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the name of the topic configuration is empty")
+		}
+	}
+	return nil
 }
 
 // ColumnSelector represents a column selector for a table.
 type ColumnSelector struct {
 	Matcher []string `toml:"matcher" json:"matcher"`
+	// Columns are the patterns of the selected columns, such as "a*" or "!b".
+	// The handle key columns are always required to be selected.
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+func (c *ColumnSelector) validate() error {
+	if len(c.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the column selector is empty")
+	}
+	if len(c.Columns) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the columns of the column selector %v is empty", c.Matcher)
+	}
+	if _, err := filter.Parse(c.Matcher); err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	if _, err := filter.ParseColumnFilter(c.Columns); err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	return nil
+}
+
+func validateColumnSelectors(selectors []*ColumnSelector, sinkURI *url.URL, protocol string) error {
+	for _, selector := range selectors {
+		if err := selector.validate(); err != nil {
+			return err
+		}
+	}
+	if len(selectors) == 0 || sinkURI == nil {
+		return nil
+	}
+	if !sink.IsMQScheme(sinkURI.Scheme) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"column-selectors is only available for the MQ sinks, but got %s", sinkURI.Scheme)
+	}
+	p, _ := ParseSinkProtocolFromString(protocol)
+	switch p {
+	case ProtocolOpen, ProtocolCanalJSON, ProtocolAvro:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"column-selectors is only available for the open-protocol, canal-json "+
+				"and avro protocols, but got %s", protocol)
+	}
+	return nil
+}
+
+const (
+	// TypeMappingSourceBit is the BIT columns.
+	TypeMappingSourceBit = "bit"
+	// TypeMappingSourceTinyInt1 is the TINYINT(1) columns, which are the BOOL
+	// columns in TiDB.
+	TypeMappingSourceTinyInt1 = "tinyint(1)"
+	// TypeMappingSourceJSON is the JSON columns.
+	TypeMappingSourceJSON = "json"
+
+	// TypeMappingTargetBytes encodes the values as the bytes, the default of BIT.
+	TypeMappingTargetBytes = "bytes"
+	// TypeMappingTargetInt encodes the values as the integers, the default of TINYINT(1).
+	TypeMappingTargetInt = "int"
+	// TypeMappingTargetBool encodes the non-zero values as true and the others as false.
+	TypeMappingTargetBool = "bool"
+	// TypeMappingTargetString encodes the values as the strings, the default of JSON.
+	TypeMappingTargetString = "string"
+)
+
+// typeMappingTargets are the supported targets of each source type.
+var typeMappingTargets = map[string][]string{
+	TypeMappingSourceBit:      {TypeMappingTargetBytes, TypeMappingTargetInt},
+	TypeMappingSourceTinyInt1: {TypeMappingTargetInt, TypeMappingTargetBool},
+	TypeMappingSourceJSON:     {TypeMappingTargetString},
+}
+
+// TypeMapping overrides the encoding of the columns of the source type with
+// the target type.
+type TypeMapping struct {
+	// Protocols are the protocols the mapping applies to, it applies to all
+	// the supported protocols if it's empty.
+	Protocols []string `toml:"protocols" json:"protocols,omitempty"`
+	// Source is the TiDB type, the value can be "bit", "tinyint(1)" or "json".
+	Source string `toml:"source" json:"source"`
+	// Target is the encoded type, the value can be "bytes" or "int" for "bit",
+	// "int" or "bool" for "tinyint(1)", and "string" for "json".
+	Target string `toml:"target" json:"target"`
+}
+
+// MatchProtocol returns whether the mapping applies to the protocol.
+func (m *TypeMapping) MatchProtocol(protocol Protocol) bool {
+	if len(m.Protocols) == 0 {
+		return isTypeMappingProtocol(protocol)
+	}
+	for _, p := range m.Protocols {
+		if strings.EqualFold(p, protocol.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TypeMapping) validate() error {
+	targets, ok := typeMappingTargets[strings.ToLower(m.Source)]
+	if !ok {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the source of the type mapping can be %s, %s or %s, but got %s",
+			TypeMappingSourceBit, TypeMappingSourceTinyInt1, TypeMappingSourceJSON, m.Source)
+	}
+	supported := false
+	for _, target := range targets {
+		if strings.EqualFold(target, m.Target) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the target of the type mapping of %s can be %s, but got %s",
+			m.Source, strings.Join(targets, " or "), m.Target)
+	}
+	for _, p := range m.Protocols {
+		protocol, err := ParseSinkProtocolFromString(p)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		if !isTypeMappingProtocol(protocol) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the type mapping is only available for the avro and json-schema "+
+					"protocols, but got %s", p)
+		}
+	}
+	return nil
+}
+
+func isTypeMappingProtocol(protocol Protocol) bool {
+	return protocol == ProtocolAvro || protocol == ProtocolJSONSchema
+}
+
+func validateTypeMappings(mappings []*TypeMapping, protocol string) error {
+	for _, mapping := range mappings {
+		if err := mapping.validate(); err != nil {
+			return err
+		}
+	}
+	// the mappings listing the protocols may be shared by the changefeeds of
+	// different protocols, only the ones for all protocols are rejected.
+	p, _ := ParseSinkProtocolFromString(protocol)
+	for _, mapping := range mappings {
+		if len(mapping.Protocols) == 0 && !isTypeMappingProtocol(p) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"type-mappings is only available for the avro and json-schema "+
+					"protocols, but got %s", protocol)
+		}
+	}
+	return nil
+}
+
+const (
+	// TemporalEncodingNaiveLocal encodes the values as the strings without the
+	// offset, such as "2023-01-02 15:04:05", in the output timezone.
+	TemporalEncodingNaiveLocal = "naive-local"
+	// TemporalEncodingEpochMillis encodes the values as the milliseconds since
+	// the Unix epoch.
+	TemporalEncodingEpochMillis = "epoch-millis"
+	// TemporalEncodingRFC3339 encodes the values as the RFC 3339 strings with
+	// the offset, such as "2023-01-02T15:04:05+08:00", in the output timezone.
+	TemporalEncodingRFC3339 = "rfc3339"
+)
+
+// TemporalEncodingConfig represents the encoding of the DATETIME and TIMESTAMP
+// columns. The TIMESTAMP values are in the timezone of the TiCDC server, while
+// the DATETIME values have no timezone, so the timezone they're written in
+// must be declared to convert them to the points in time.
+type TemporalEncodingConfig struct {
+	// Protocols are the protocols the encoding applies to, it applies to all
+	// the supported protocols if it's empty.
+	Protocols []string `toml:"protocols" json:"protocols,omitempty"`
+	// Mode can be "naive-local", "epoch-millis" or "rfc3339", the default is
+	// "naive-local".
+	Mode *string `toml:"mode" json:"mode,omitempty"`
+	// SourceTimeZone is the timezone the DATETIME values are written in, the
+	// default is the timezone of the TiCDC server.
+	SourceTimeZone *string `toml:"source-time-zone" json:"source-time-zone,omitempty"`
+	// TimeZone is the timezone of the "naive-local" and "rfc3339" values, the
+	// default is the timezone of the TiCDC server.
+	TimeZone *string `toml:"time-zone" json:"time-zone,omitempty"`
+}
+
+// MatchProtocol returns whether the temporal encoding applies to the protocol.
+func (c *TemporalEncodingConfig) MatchProtocol(protocol Protocol) bool {
+	if len(c.Protocols) == 0 {
+		return isTemporalEncodingProtocol(protocol)
+	}
+	for _, p := range c.Protocols {
+		if strings.EqualFold(p, protocol.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *TemporalEncodingConfig) validate(protocol string) error {
+	switch util.GetOrZero(c.Mode) {
+	case "", TemporalEncodingNaiveLocal, TemporalEncodingEpochMillis, TemporalEncodingRFC3339:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the mode of the temporal-encoding can be %s, %s or %s, but got %s",
+			TemporalEncodingNaiveLocal, TemporalEncodingEpochMillis,
+			TemporalEncodingRFC3339, util.GetOrZero(c.Mode))
+	}
+	for _, tz := range []*string{c.SourceTimeZone, c.TimeZone} {
+		if _, err := util.GetTimezone(util.GetOrZero(tz)); err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+	}
+	for _, p := range c.Protocols {
+		parsed, err := ParseSinkProtocolFromString(p)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		if !isTemporalEncodingProtocol(parsed) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the temporal-encoding is only available for the open-protocol, canal-json, "+
+					"avro, json-schema and csv protocols, but got %s", p)
+		}
+	}
+	// the encoding listing the protocols may be shared by the changefeeds of
+	// different protocols, only the one for all protocols is rejected.
+	p, _ := ParseSinkProtocolFromString(protocol)
+	if len(c.Protocols) == 0 && !isTemporalEncodingProtocol(p) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the temporal-encoding is only available for the open-protocol, canal-json, "+
+				"avro, json-schema and csv protocols, but got %s", protocol)
+	}
+	return nil
+}
+
+func isTemporalEncodingProtocol(protocol Protocol) bool {
+	switch protocol {
+	case ProtocolOpen, ProtocolCanalJSON, ProtocolAvro, ProtocolJSONSchema, ProtocolCsv:
+		return true
+	}
+	return false
+}
+
+const (
+	// ColumnMaskHash replaces the value with the hex encoded SHA-256 hash of it.
+	ColumnMaskHash = "hash"
+	// ColumnMaskNull replaces the value with NULL.
+	ColumnMaskNull = "null"
+	// ColumnMaskFixed replaces the value with a fixed value.
+	ColumnMaskFixed = "fixed"
+	// ColumnMaskPartial replaces the characters of the value with '*',
+	// except the leading and trailing characters to keep.
+	ColumnMaskPartial = "partial"
+)
+
+// ColumnMasker represents a masking rule of the columns in the matched tables.
+type ColumnMasker struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Columns []string `toml:"columns" json:"columns"`
+	// Mask is the mask function, can be hash, null, fixed or partial.
+	Mask string `toml:"mask" json:"mask"`
+	// Value is the replacement of the fixed mask function.
+	Value string `toml:"value" json:"value,omitempty"`
+	// KeepPrefix and KeepSuffix are the number of the leading and trailing
+	// characters kept by the partial mask function.
+	KeepPrefix int `toml:"keep-prefix" json:"keep-prefix,omitempty"`
+	KeepSuffix int `toml:"keep-suffix" json:"keep-suffix,omitempty"`
+}
+
+func (m *ColumnMasker) validate() error {
+	if len(m.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the column masker is empty")
+	}
+	if len(m.Columns) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the columns of the column masker %v is empty", m.Matcher)
+	}
+	switch m.Mask {
+	case ColumnMaskHash, ColumnMaskNull, ColumnMaskFixed:
+	case ColumnMaskPartial:
+		if m.KeepPrefix < 0 || m.KeepSuffix < 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"keep-prefix and keep-suffix of the column masker %v should not be negative",
+				m.Matcher)
+		}
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid mask function %s of the column masker %v, it must be one of %s, %s, %s or %s",
+			m.Mask, m.Matcher, ColumnMaskHash, ColumnMaskNull, ColumnMaskFixed, ColumnMaskPartial)
+	}
+	return nil
+}
+
+// ComputedColumn represents a column appended to the rows of the matched tables.
+type ComputedColumn struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// Name is the name of the appended column, it must not be the name of
+	// an existing column of the matched tables.
+	Name string `toml:"name" json:"name"`
+	// Value is the template of the column value, such as "us-west-1" or
+	// "{cluster-id}:{id}". The placeholders "{schema}", "{table}", "{commit-ts}",
+	// "{cluster-id}" and "{changefeed}" are replaced by the metadata of the row,
+	// and the other placeholders such as "{id}" are replaced by the values of
+	// the columns. The computed column is NULL if any referred column is NULL.
+	Value string `toml:"value" json:"value"`
+}
+
+func (c *ComputedColumn) validate() error {
+	if len(c.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the computed column is empty")
+	}
+	if c.Name == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the name of the computed column %v is empty", c.Matcher)
+	}
+	return nil
+}
+
+// SinkTarget represents a downstream the events of the matched tables are
+// written to. It uses the sink config of the changefeed except the protocol,
+// and the credentials and the transactions of the kafka config.
+type SinkTarget struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	SinkURI string   `toml:"sink-uri" json:"sink-uri"`
+	// Protocol is the protocol of the target, the protocol in the sink-uri of
+	// the target or the protocol of the changefeed is used if it's empty.
+	Protocol *string `toml:"protocol" json:"protocol,omitempty"`
+}
+
+func (t *SinkTarget) validate() error {
+	if len(t.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the sink target is empty")
+	}
+	if _, err := filter.Parse(t.Matcher); err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	if t.SinkURI == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the sink-uri of the sink target %v is empty", t.Matcher)
+	}
+	if _, err := sink.ParseSinkURI(t.SinkURI); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	return nil
+}
+
+// SinkMirror represents the downstream the events of the sink-uri are written
+// to as well. It uses the sink config of the changefeed except the protocol
+// and the kafka sink version.
+type SinkMirror struct {
+	SinkURI string `toml:"sink-uri" json:"sink-uri"`
+	// Protocol is the protocol of the mirror, the protocol in the sink-uri of
+	// the mirror or the protocol of the changefeed is used if it's empty.
+	Protocol *string `toml:"protocol" json:"protocol,omitempty"`
+	// EnableKafkaSinkV2 overrides the one of the changefeed, so that the
+	// changefeed can be migrated from the sarama sink to the kafka-go sink.
+	EnableKafkaSinkV2 *bool `toml:"enable-kafka-sink-v2" json:"enable-kafka-sink-v2,omitempty"`
+}
+
+func (m *SinkMirror) validate(sinkURI *url.URL) error {
+	if m.SinkURI == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack("the sink-uri of the mirror is empty")
+	}
+	if _, err := sink.ParseSinkURI(m.SinkURI); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if sinkURI != nil && m.SinkURI == sinkURI.String() {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the sink-uri of the mirror is the same as the sink-uri of the changefeed")
+	}
+	return nil
+}
+
+// TableRoute renames the matched tables in the downstream. "{schema}" and
+// "{table}" in the target names are replaced with the upstream names, so
+// that the tables of many upstream shards can be merged into one table.
+type TableRoute struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// TargetSchema is the schema of the tables in the downstream, the upstream
+	// schema is kept if it's empty.
+	TargetSchema string `toml:"target-schema" json:"target-schema,omitempty"`
+	// TargetTable is the name of the tables in the downstream, the upstream
+	// name is kept if it's empty.
+	TargetTable string `toml:"target-table" json:"target-table,omitempty"`
+	// Columns rename the columns of the matched tables, the column names are
+	// case-insensitive.
+	Columns []*ColumnRoute `toml:"columns" json:"columns,omitempty"`
+	// SourceColumns appends the _source_schema and _source_table columns with
+	// the upstream names to the rows of the matched tables. They're added to
+	// the downstream tables and their primary and unique keys by the CREATE
+	// TABLE DDLs, so the rows of the shards with the same keys don't conflict
+	// in the merged table.
+	SourceColumns bool `toml:"source-columns" json:"source-columns,omitempty"`
+}
+
+const (
+	// SourceSchemaColumn is the column of the upstream schema of the rows
+	// appended by the table routes with the source columns.
+	SourceSchemaColumn = "_source_schema"
+	// SourceTableColumn is the column of the upstream table of the rows
+	// appended by the table routes with the source columns.
+	SourceTableColumn = "_source_table"
+)
+
+func isSourceColumn(name string) bool {
+	return strings.EqualFold(name, SourceSchemaColumn) || strings.EqualFold(name, SourceTableColumn)
+}
+
+// ColumnRoute renames a column in the downstream.
+type ColumnRoute struct {
+	Source string `toml:"source" json:"source"`
+	Target string `toml:"target" json:"target"`
+}
+
+func (r *TableRoute) validate() error {
+	if len(r.Matcher) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the matcher of the table route is empty")
+	}
+	if _, err := filter.Parse(r.Matcher); err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	if r.TargetSchema == "" && r.TargetTable == "" && len(r.Columns) == 0 && !r.SourceColumns {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the table route %v renames nothing", r.Matcher)
+	}
+	sources := make(map[string]struct{}, len(r.Columns))
+	for _, column := range r.Columns {
+		if column.Source == "" || column.Target == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the source and the target of the column route of %v can't be empty", r.Matcher)
+		}
+		if r.SourceColumns && isSourceColumn(column.Target) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the column %s of the table route %v conflicts with the source columns",
+				column.Source, r.Matcher)
+		}
+		source := strings.ToLower(column.Source)
+		if _, ok := sources[source]; ok {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the column %s of the table route %v is renamed more than once",
+				column.Source, r.Matcher)
+		}
+		sources[source] = struct{}{}
+	}
+	return nil
+}
+
+// TransformerConfig represents a WASM module transforming the rows of the
+// matched tables. The module is sandboxed, it can't access the file system or
+// the network of the TiCDC servers.
+type TransformerConfig struct {
+	// Matcher matches the tables whose rows are transformed, the rows of all
+	// tables are transformed if it's empty.
+	Matcher []string `toml:"matcher" json:"matcher,omitempty"`
+	// WASMFile is the path of the WASM module on the TiCDC servers.
+	WASMFile string `toml:"wasm-file" json:"wasm-file"`
+	// WASMChecksum is the hex encoded SHA-256 of the WASM module. It's set
+	// by the module on the server handling the creation or the update of the
+	// changefeed if it's empty, and every server refuses to run a module
+	// which doesn't match it.
+	WASMChecksum string `toml:"wasm-sha256" json:"wasm-sha256,omitempty"`
+}
+
+var wasmChecksumRegexp = regexp.MustCompile("^[0-9a-f]{64}$")
+
+func (c *TransformerConfig) validate() error {
+	if c.WASMFile == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the wasm-file of the transformer is empty")
+	}
+	if c.WASMChecksum != "" && !wasmChecksumRegexp.MatchString(c.WASMChecksum) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the wasm-sha256 %s of the transformer isn't a lower case hex encoded SHA-256",
+			c.WASMChecksum)
+	}
+	if len(c.Matcher) > 0 {
+		if _, err := filter.Parse(c.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+	}
+	return nil
+}
+
 // CodecConfig represents a MQ codec configuration
 type CodecConfig struct {
 	EnableTiDBExtension            *bool   `toml:"enable-tidb-extension" json:"enable-tidb-extension,omitempty"`
@@ -318,6 +1090,11 @@ type KafkaConfig struct {
 	SASLOAuthScopes              []string                  `toml:"sasl-oauth-scopes" json:"sasl-oauth-scopes,omitempty"`
 	SASLOAuthGrantType           *string                   `toml:"sasl-oauth-grant-type" json:"sasl-oauth-grant-type,omitempty"`
 	SASLOAuthAudience            *string                   `toml:"sasl-oauth-audience" json:"sasl-oauth-audience,omitempty"`
+	SASLOAuthAssertionKey        *string                   `toml:"sasl-oauth-assertion-key" json:"sasl-oauth-assertion-key,omitempty"`
+	SASLOAuthAssertionKeyID      *string                   `toml:"sasl-oauth-assertion-key-id" json:"sasl-oauth-assertion-key-id,omitempty"`
+	SASLAWSRegion                *string                   `toml:"sasl-aws-region" json:"sasl-aws-region,omitempty"`
+	SASLAWSRoleARN               *string                   `toml:"sasl-aws-role-arn" json:"sasl-aws-role-arn,omitempty"`
+	SASLAWSRoleSessionName       *string                   `toml:"sasl-aws-role-session-name" json:"sasl-aws-role-session-name,omitempty"`
 	EnableTLS                    *bool                     `toml:"enable-tls" json:"enable-tls,omitempty"`
 	CA                           *string                   `toml:"ca" json:"ca,omitempty"`
 	Cert                         *string                   `toml:"cert" json:"cert,omitempty"`
@@ -325,6 +1102,69 @@ type KafkaConfig struct {
 	InsecureSkipVerify           *bool                     `toml:"insecure-skip-verify" json:"insecure-skip-verify,omitempty"`
 	CodecConfig                  *CodecConfig              `toml:"codec-config" json:"codec-config,omitempty"`
 	LargeMessageHandle           *LargeMessageHandleConfig `toml:"large-message-handle" json:"large-message-handle,omitempty"`
+	DeleteTopicsOnRemove         *bool                     `toml:"delete-topics-on-remove" json:"delete-topics-on-remove,omitempty"`
+	DNSSRVDiscovery              *bool                     `toml:"dns-srv-discovery" json:"dns-srv-discovery,omitempty"`
+	EnableKafkaTransactions      *bool                     `toml:"enable-kafka-transactions" json:"enable-kafka-transactions,omitempty"`
+	DeadLetterTopic              *string                   `toml:"dead-letter-topic" json:"dead-letter-topic,omitempty"`
+	DeadLetterStorageURI         *string                   `toml:"dead-letter-storage-uri" json:"dead-letter-storage-uri,omitempty"`
+	// DDLTopic is the compacted topic which all DDL events are sent to with
+	// the table definitions before and after them, instead of the data topics.
+	DDLTopic *string `toml:"ddl-topic" json:"ddl-topic,omitempty"`
+
+	// EventHubs indicates that the sink writes to the Kafka endpoint of Azure
+	// Event Hubs, whose unsupported admin APIs are never called.
+	EventHubs *bool `toml:"event-hubs" json:"event-hubs,omitempty"`
+	// EventHubsConnectionString is the connection string of the Event Hubs
+	// namespace, which is used as the password of SASL/PLAIN.
+	EventHubsConnectionString *string `toml:"event-hubs-connection-string" json:"event-hubs-connection-string,omitempty"`
+
+	// Linger is the max time the kafka sink v2 waits for the messages of a
+	// batch, such as "5ms".
+	Linger *string `toml:"linger" json:"linger,omitempty"`
+	// BatchBytes is the max size of a batch sent by the kafka sink v2, it's
+	// capped by max-message-bytes.
+	BatchBytes *int `toml:"batch-bytes" json:"batch-bytes,omitempty"`
+	// MaxInflight is the max number of the messages sent by the kafka sink v2
+	// but not acknowledged yet, unlimited if it's not set.
+	MaxInflight *int `toml:"max-inflight" json:"max-inflight,omitempty"`
+	// CompressionLevel is the level of the compression algorithm, it's only
+	// supported by the sarama client, and it's rejected if the kafka-go sink
+	// is used.
+	CompressionLevel *int `toml:"compression-level" json:"compression-level,omitempty"`
+	// TopicConfigs are the topic level configurations of the auto-created
+	// topics, such as "retention.ms", "cleanup.policy" and "min.insync.replicas",
+	// they can be overridden by the dispatch rules.
+	TopicConfigs map[string]string `toml:"topic-configs" json:"topic-configs,omitempty"`
+}
+
+// clearCredentials removes the SASL and TLS credentials from the kafka config.
+func (k *KafkaConfig) clearCredentials() {
+	k.SASLUser = nil
+	k.SASLPassword = nil
+	k.SASLMechanism = nil
+	k.SASLGssAPIAuthType = nil
+	k.SASLGssAPIKeytabPath = nil
+	k.SASLGssAPIKerberosConfigPath = nil
+	k.SASLGssAPIServiceName = nil
+	k.SASLGssAPIUser = nil
+	k.SASLGssAPIPassword = nil
+	k.SASLGssAPIRealm = nil
+	k.SASLGssAPIDisablePafxfast = nil
+	k.SASLOAuthClientID = nil
+	k.SASLOAuthClientSecret = nil
+	k.SASLOAuthTokenURL = nil
+	k.SASLOAuthScopes = nil
+	k.SASLOAuthGrantType = nil
+	k.SASLOAuthAudience = nil
+	k.SASLOAuthAssertionKey = nil
+	k.SASLOAuthAssertionKeyID = nil
+	k.SASLAWSRegion = nil
+	k.SASLAWSRoleARN = nil
+	k.SASLAWSRoleSessionName = nil
+	k.CA = nil
+	k.Cert = nil
+	k.Key = nil
+	k.EventHubsConnectionString = nil
 }
 
 // PulsarConfig pulsar sink configuration
@@ -335,6 +1175,93 @@ type PulsarConfig struct {
 
 	// PulsarProducerCacheSize is the size of the cache of pulsar producers
 	PulsarProducerCacheSize *int32 `toml:"pulsar-producer-cache-size" json:"pulsar-producer-cache-size,omitempty"`
+
+	// AuthenticationToken is the token used to authenticate to the pulsar cluster.
+	AuthenticationToken *string `toml:"authentication-token" json:"authentication-token,omitempty"`
+	// TokenFromFile is the path of the file which contains the authentication token.
+	TokenFromFile *string `toml:"token-from-file" json:"token-from-file,omitempty"`
+	// BasicUserName and BasicPassword are used by the basic authentication.
+	BasicUserName *string `toml:"basic-user-name" json:"basic-user-name,omitempty"`
+	BasicPassword *string `toml:"basic-password" json:"basic-password,omitempty"`
+	// OAuth2 is used by the OAuth2 client credentials authentication.
+	OAuth2 *PulsarOAuth2 `toml:"oauth2" json:"oauth2,omitempty"`
+
+	// ConnectionTimeout is the timeout to establish a TCP connection, such as "5s".
+	ConnectionTimeout *string `toml:"connection-timeout" json:"connection-timeout,omitempty"`
+	// OperationTimeout is the timeout of the producer creation and the other
+	// operations, which are retried until the timeout is reached, such as "30s".
+	OperationTimeout *string `toml:"operation-timeout" json:"operation-timeout,omitempty"`
+
+	// AdminURL is the URL of the pulsar admin REST API, such as "http://127.0.0.1:8080",
+	// it's required to create the topics and to set the retention of the topics.
+	AdminURL *string `toml:"admin-url" json:"admin-url,omitempty"`
+	// AutoCreateTopic creates the topics which don't exist by the admin API
+	// before sending messages to them.
+	AutoCreateTopic *bool `toml:"auto-create-topic" json:"auto-create-topic,omitempty"`
+	// TopicPartitionNum is the partition number of the auto-created topics,
+	// 0 means the topics are non-partitioned.
+	TopicPartitionNum *int32 `toml:"topic-partition-num" json:"topic-partition-num,omitempty"`
+	// TopicRetentionTime is the retention time of the auto-created topics, such as "24h".
+	TopicRetentionTime *string `toml:"topic-retention-time" json:"topic-retention-time,omitempty"`
+	// TopicRetentionSizeMB is the retention size of the auto-created topics in MB,
+	// -1 means infinite retention size.
+	TopicRetentionSizeMB *int64 `toml:"topic-retention-size-mb" json:"topic-retention-size-mb,omitempty"`
+}
+
+// PulsarOAuth2 is the OAuth2 client credentials configuration of the pulsar sink.
+type PulsarOAuth2 struct {
+	// OAuth2IssuerURL is the URL of the authorization server.
+	OAuth2IssuerURL string `toml:"oauth2-issuer-url" json:"oauth2-issuer-url,omitempty"`
+	// OAuth2Audience is the URL of the resource server.
+	OAuth2Audience string `toml:"oauth2-audience" json:"oauth2-audience,omitempty"`
+	// OAuth2PrivateKey is the path of the private key file of the client credentials.
+	OAuth2PrivateKey string `toml:"oauth2-private-key" json:"oauth2-private-key,omitempty"`
+	OAuth2ClientID   string `toml:"oauth2-client-id" json:"oauth2-client-id,omitempty"`
+	OAuth2Scope      string `toml:"oauth2-scope" json:"oauth2-scope,omitempty"`
+}
+
+func (c *PulsarConfig) validate() error {
+	if c.OAuth2 != nil && (c.OAuth2.OAuth2IssuerURL == "" ||
+		c.OAuth2.OAuth2Audience == "" || c.OAuth2.OAuth2PrivateKey == "") {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"oauth2-issuer-url, oauth2-audience and oauth2-private-key of the pulsar oauth2 should be set")
+	}
+	if (c.BasicUserName == nil) != (c.BasicPassword == nil) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"basic-user-name and basic-password of the pulsar config should be set together")
+	}
+	if err := validatePulsarTimeout("connection-timeout", c.ConnectionTimeout); err != nil {
+		return err
+	}
+	if err := validatePulsarTimeout("operation-timeout", c.OperationTimeout); err != nil {
+		return err
+	}
+	if util.GetOrZero(c.AutoCreateTopic) && util.GetOrZero(c.AdminURL) == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"admin-url of the pulsar config should be set if auto-create-topic is enabled")
+	}
+	if c.TopicPartitionNum != nil && *c.TopicPartitionNum < 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid topic-partition-num %d of the pulsar config, it should not be negative",
+			*c.TopicPartitionNum)
+	}
+	if c.TopicRetentionSizeMB != nil && *c.TopicRetentionSizeMB < -1 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid topic-retention-size-mb %d of the pulsar config, it should not be less than -1",
+			*c.TopicRetentionSizeMB)
+	}
+	return validatePulsarTimeout("topic-retention-time", c.TopicRetentionTime)
+}
+
+func validatePulsarTimeout(name string, timeout *string) error {
+	if timeout == nil {
+		return nil
+	}
+	if d, err := time.ParseDuration(*timeout); err != nil || d <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %s of the pulsar config, it should be a positive duration", name, *timeout)
+	}
+	return nil
 }
 
 // MySQLConfig represents a MySQL sink configuration
@@ -354,6 +1281,157 @@ type MySQLConfig struct {
 	EnableBatchDML               *bool   `toml:"enable-batch-dml" json:"enable-batch-dml,omitempty"`
 	EnableMultiStatement         *bool   `toml:"enable-multi-statement" json:"enable-multi-statement,omitempty"`
 	EnableCachePreparedStatement *bool   `toml:"enable-cache-prepared-statement" json:"enable-cache-prepared-statement,omitempty"`
+	// ConflictStrategy decides how the rows conflicting with the existing rows are
+	// written in safe mode, it can be "overwrite", "error", "ignore" or "timestamp-wins".
+	ConflictStrategy *string `toml:"conflict-strategy" json:"conflict-strategy,omitempty"`
+	// ConflictTimestampColumn is the column compared by the "timestamp-wins" strategy.
+	ConflictTimestampColumn *string `toml:"conflict-timestamp-column" json:"conflict-timestamp-column,omitempty"`
+	// EnableAsyncDDL executes the time-consuming DDLs such as ADD INDEX in the
+	// background if the downstream is TiDB. The checkpoint stays at them until
+	// they finish, and only the DMLs of the same tables are held back.
+	EnableAsyncDDL *bool `toml:"enable-async-ddl" json:"enable-async-ddl,omitempty"`
+	// EnableBatchUpsert coalesces the inserted and updated rows of a table into
+	// multi-row INSERT ... ON DUPLICATE KEY UPDATE statements, it takes effect
+	// if enable-batch-dml is true.
+	EnableBatchUpsert *bool `toml:"enable-batch-upsert" json:"enable-batch-upsert,omitempty"`
+	// DMLMaxOpenConns limits the connections of the pool writing the rows, it's
+	// worker-count + 1 by default, and it can't be less than that.
+	DMLMaxOpenConns *int `toml:"dml-max-open-conns" json:"dml-max-open-conns,omitempty"`
+	// MetaMaxOpenConns limits the connections of the pool writing the syncpoints,
+	// which is separated from the pool writing the rows.
+	MetaMaxOpenConns *int `toml:"meta-max-open-conns" json:"meta-max-open-conns,omitempty"`
+	// PoolHealthCheckInterval is the interval of probing the connections of the
+	// pools in the background, the probe is disabled if it's 0s.
+	PoolHealthCheckInterval *string `toml:"pool-health-check-interval" json:"pool-health-check-interval,omitempty"`
+	// DDLTranslation rewrites the DDLs for the downstreams which aren't TiDB.
+	DDLTranslation *DDLTranslationConfig `toml:"ddl-translation" json:"ddl-translation,omitempty"`
+}
+
+const (
+	// DDLDialectMySQL translates the DDLs for MySQL and the compatible
+	// databases such as Aurora.
+	DDLDialectMySQL = "mysql"
+	// DDLDialectMariaDB translates the DDLs for MariaDB.
+	DDLDialectMariaDB = "mariadb"
+)
+
+// DDLTranslationConfig represents how the DDLs are rewritten before they're
+// executed in the downstream which isn't TiDB.
+type DDLTranslationConfig struct {
+	// Dialect can be "mysql" or "mariadb", the built-in rules of the dialect
+	// remove the TiDB specific syntax such as the clustered index options,
+	// AUTO_RANDOM and the placement rules, and skip the DDLs which can't be
+	// translated. No built-in rules are applied if it's empty.
+	Dialect *string `toml:"dialect" json:"dialect,omitempty"`
+	// Rules are applied in order after the built-in rules of the dialect.
+	Rules []*DDLTranslationRule `toml:"rules" json:"rules,omitempty"`
+}
+
+// DDLTranslationRule rewrites or skips the DDLs matched by the pattern.
+type DDLTranslationRule struct {
+	// Pattern is the regular expression matched against the DDL query.
+	Pattern string `toml:"pattern" json:"pattern"`
+	// Replacement replaces all the matches of the pattern, "$1" and "${name}"
+	// refer to the submatches.
+	Replacement string `toml:"replacement" json:"replacement"`
+	// Skip skips the matched DDLs instead of rewriting them.
+	Skip bool `toml:"skip" json:"skip"`
+}
+
+func (c *DDLTranslationConfig) validate() error {
+	switch util.GetOrZero(c.Dialect) {
+	case "", DDLDialectMySQL, DDLDialectMariaDB:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the dialect of the ddl-translation can be %s or %s, but got %s",
+			DDLDialectMySQL, DDLDialectMariaDB, util.GetOrZero(c.Dialect))
+	}
+	for _, rule := range c.Rules {
+		if rule.Pattern == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the pattern of the ddl-translation rule can't be empty")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+	}
+	return nil
+}
+
+// ClickHouseConfig represents a ClickHouse sink configuration
+type ClickHouseConfig struct {
+	// Engine is the table engine of the target tables, the value can be
+	// "replacing-merge-tree" or "collapsing-merge-tree".
+	Engine *string `toml:"engine" json:"engine,omitempty"`
+	// VersionColumn is the column which is set to the commit ts of the rows.
+	VersionColumn *string `toml:"version-column" json:"version-column,omitempty"`
+	// SignColumn is the column which is set to 1 for the written rows,
+	// and -1 for the deleted rows.
+	SignColumn   *string `toml:"sign-column" json:"sign-column,omitempty"`
+	WorkerCount  *int    `toml:"worker-count" json:"worker-count,omitempty"`
+	MaxBatchRows *int    `toml:"max-batch-rows" json:"max-batch-rows,omitempty"`
+}
+
+// ElasticsearchConfig represents an Elasticsearch sink configuration
+type ElasticsearchConfig struct {
+	// Index is the index of the tables which aren't matched by the index rules,
+	// "{schema}" and "{table}" are replaced by the schema and table names.
+	Index *string `toml:"index" json:"index,omitempty"`
+	// IndexRules overrides the index of the matched tables, the first matched
+	// one is used if a table matches several ones.
+	IndexRules   []*ElasticsearchIndexRule `toml:"index-rules" json:"index-rules,omitempty"`
+	WorkerCount  *int                      `toml:"worker-count" json:"worker-count,omitempty"`
+	MaxBatchRows *int                      `toml:"max-batch-rows" json:"max-batch-rows,omitempty"`
+}
+
+// ElasticsearchIndexRule represents the index of the tables matched by the matcher.
+type ElasticsearchIndexRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Index   string   `toml:"index" json:"index"`
+}
+
+// RedisConfig represents a Redis sink configuration
+type RedisConfig struct {
+	// Mode is the way the row changes are written into Redis, the value can be
+	// "snapshot" or "invalidate". The rows are written as the values of their
+	// keys in the snapshot mode, and the keys of the changed rows are published
+	// to the channel in the invalidate mode.
+	Mode *string `toml:"mode" json:"mode,omitempty"`
+	// ValueFormat is the format of the rows in the snapshot mode, the value can
+	// be "hash" or "json".
+	ValueFormat *string `toml:"value-format" json:"value-format,omitempty"`
+	// Key is the key of the rows of the tables which aren't matched by the key
+	// rules. "{schema}", "{table}" and "{pk}" are replaced by the schema name,
+	// the table name and the handle key values, the other placeholders such as
+	// "{id}" are replaced by the values of the columns.
+	Key *string `toml:"key" json:"key,omitempty"`
+	// KeyRules overrides the key of the matched tables, the first matched
+	// one is used if a table matches several ones.
+	KeyRules []*RedisKeyRule `toml:"key-rules" json:"key-rules,omitempty"`
+	// Channel is the channel which the keys are published to in the invalidate mode.
+	Channel      *string `toml:"channel" json:"channel,omitempty"`
+	WorkerCount  *int    `toml:"worker-count" json:"worker-count,omitempty"`
+	MaxBatchRows *int    `toml:"max-batch-rows" json:"max-batch-rows,omitempty"`
+}
+
+// RedisKeyRule represents the key of the rows of the tables matched by the matcher.
+type RedisKeyRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Key     string   `toml:"key" json:"key"`
+}
+
+// WebhookConfig represents an HTTP webhook sink configuration
+type WebhookConfig struct {
+	// Headers are attached to the requests, such as the authorization header.
+	Headers      map[string]string `toml:"headers" json:"headers,omitempty"`
+	WorkerCount  *int              `toml:"worker-count" json:"worker-count,omitempty"`
+	MaxBatchRows *int              `toml:"max-batch-rows" json:"max-batch-rows,omitempty"`
+	// MaxRetries is the max number of retries of a failed request, the
+	// requests are retried with the exponential backoff between RetryBackoff
+	// and MaxRetryBackoff.
+	MaxRetries      *int    `toml:"max-retries" json:"max-retries,omitempty"`
+	RetryBackoff    *string `toml:"retry-backoff" json:"retry-backoff,omitempty"`
+	MaxRetryBackoff *string `toml:"max-retry-backoff" json:"max-retry-backoff,omitempty"`
 }
 
 // CloudStorageConfig represents a cloud storage sink configuration
@@ -363,6 +1441,411 @@ type CloudStorageConfig struct {
 	FileSize      *int    `toml:"file-size" json:"file-size,omitempty"`
 
 	OutputColumnID *bool `toml:"output-column-id" json:"output-column-id,omitempty"`
+	// FileNameTemplate is the template of the data file names, "{schema}",
+	// "{table}", "{date}", "{index}" and "{extension}" in it are replaced by
+	// the values of the file. The date is the one of date-separator, which is
+	// empty if date-separator is none. The file index is padded to
+	// file-index-digit digits, and the extension has the leading dot, e.g.
+	// ".csv". It must contain "{index}" exactly once, the default is
+	// "CDC{index}{extension}".
+	FileNameTemplate *string `toml:"file-name-template" json:"file-name-template,omitempty"`
+	// OutputManifest writes a manifest file after each flush, which lists the
+	// data files with their row counts, commit ts ranges and checksums.
+	OutputManifest *bool `toml:"output-manifest" json:"output-manifest,omitempty"`
+	// OutputCompleteMarker writes a _SUCCESS file to a date partition directory
+	// once all the data files of the partition are flushed, so the batch jobs
+	// know the partition is safe to read. It requires the date-separator.
+	OutputCompleteMarker *bool `toml:"output-complete-marker" json:"output-complete-marker,omitempty"`
+
+	// ParquetCompression is the compression codec of the parquet files,
+	// the value can be "none", "snappy" or "zstd".
+	ParquetCompression *string `toml:"parquet-compression" json:"parquet-compression,omitempty"`
+	// ParquetRowGroupSize is the upper limit of the row group size in bytes of the parquet files.
+	ParquetRowGroupSize *int `toml:"parquet-row-group-size" json:"parquet-row-group-size,omitempty"`
+	// Compression is the compression codec of the data files except the parquet ones,
+	// the value can be "none", "gzip" or "zstd".
+	Compression *string `toml:"compression" json:"compression,omitempty"`
+
+	// TableOverrides overrides the flush-interval and file-size of the matched
+	// tables, the first matched one is used if a table matches several ones.
+	TableOverrides []*CloudStorageTableOverride `toml:"table-overrides" json:"table-overrides,omitempty"`
+
+	// RedshiftConfig loads the data files into Amazon Redshift after they're written.
+	RedshiftConfig *RedshiftConfig `toml:"redshift-config" json:"redshift-config,omitempty"`
+	// EnableDeltaLake commits the data files to the transaction logs of Delta Lake
+	// tables after they're written, it's only available for the parquet protocol.
+	EnableDeltaLake *bool `toml:"enable-delta-lake" json:"enable-delta-lake,omitempty"`
+}
+
+// CloudStorageTableOverride overrides the flush-interval and file-size of the
+// tables matched by the matcher, the global ones are used if they're not set.
+type CloudStorageTableOverride struct {
+	Matcher       []string `toml:"matcher" json:"matcher"`
+	FlushInterval *string  `toml:"flush-interval" json:"flush-interval,omitempty"`
+	FileSize      *int     `toml:"file-size" json:"file-size,omitempty"`
+}
+
+// RedshiftConfig represents the Amazon Redshift cluster which the data files of
+// the cloud storage sink are loaded into. Each data file is copied into a staging
+// table and merged into the target table by the primary key, in the transaction
+// executed by the Redshift Data API. It's only available for the csv protocol
+// with an s3 sink URI, the target tables should be created in advance.
+type RedshiftConfig struct {
+	// ClusterIdentifier is the identifier of the provisioned cluster,
+	// it's exclusive with WorkgroupName.
+	ClusterIdentifier *string `toml:"cluster-identifier" json:"cluster-identifier,omitempty"`
+	// WorkgroupName is the workgroup name of the serverless Redshift.
+	WorkgroupName *string `toml:"workgroup-name" json:"workgroup-name,omitempty"`
+	Database      *string `toml:"database" json:"database,omitempty"`
+	// DBUser or SecretARN is used to access the database, the temporary
+	// credentials of the IAM identity are used if neither is set.
+	DBUser    *string `toml:"db-user" json:"db-user,omitempty"`
+	SecretARN *string `toml:"secret-arn" json:"secret-arn,omitempty"`
+	// IAMRole is the ARN of the role which is used by COPY to read the data files.
+	IAMRole *string `toml:"iam-role" json:"iam-role,omitempty"`
+	Region  *string `toml:"region" json:"region,omitempty"`
+	// Schema is the schema of the target tables in Redshift,
+	// the upstream schema name is used if it's empty.
+	Schema *string `toml:"schema" json:"schema,omitempty"`
+}
+
+func (c *RedshiftConfig) validate(
+	scheme string, protocol Protocol, terminator string, csvConfig *CSVConfig,
+) error {
+	if scheme != sink.S3Scheme {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the redshift config is only available for the s3 sink URI, but got %s", scheme)
+	}
+	if protocol != ProtocolCsv {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the redshift config is only available for the csv protocol, but got %s", protocol)
+	}
+	// the commit ts is used to find the latest change of each row in a data file.
+	if csvConfig == nil || !csvConfig.IncludeCommitTs {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"include-commit-ts of the csv config should be true if the redshift config is set")
+	}
+	// COPY skips the header row of every data file or none of them.
+	if csvConfig.HeaderPolicy() == CSVHeaderFirstFile {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"include-header of the csv config can't be %s if the redshift config is set",
+			CSVHeaderFirstFile)
+	}
+	// COPY doesn't recognize the carriage return as a part of the line terminator.
+	if terminator != "\n" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the terminator should be \"\\n\" if the redshift config is set")
+	}
+	if (util.GetOrZero(c.ClusterIdentifier) == "") == (util.GetOrZero(c.WorkgroupName) == "") {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"one of cluster-identifier and workgroup-name of the redshift config should be set")
+	}
+	if util.GetOrZero(c.Database) == "" || util.GetOrZero(c.IAMRole) == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"database and iam-role of the redshift config should be set")
+	}
+	return nil
+}
+
+// validateFileNameTemplate checks the file name template of the cloud storage
+// sink. The file index can be parsed from the file names only if the template
+// contains "{index}" exactly once.
+func validateFileNameTemplate(template string) error {
+	if strings.Count(template, FileNamePlaceholderIndex) != 1 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the file-name-template %s should contain %s exactly once",
+			template, FileNamePlaceholderIndex)
+	}
+	if strings.Contains(template, "/") {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the file-name-template %s should not contain \"/\"", template)
+	}
+	rest := strings.NewReplacer(
+		FileNamePlaceholderSchema, "",
+		FileNamePlaceholderTable, "",
+		FileNamePlaceholderDate, "",
+		FileNamePlaceholderIndex, "",
+		FileNamePlaceholderExtension, "",
+	).Replace(template)
+	if strings.ContainsAny(rest, "{}") {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the file-name-template %s contains an unknown placeholder, "+
+				"the placeholders can be %s, %s, %s, %s and %s", template,
+			FileNamePlaceholderSchema, FileNamePlaceholderTable, FileNamePlaceholderDate,
+			FileNamePlaceholderIndex, FileNamePlaceholderExtension)
+	}
+	return nil
+}
+
+// MessageHeaderMetadataPrefix is the key prefix of the metadata headers,
+// it's reserved and can not be used by the static headers.
+const MessageHeaderMetadataPrefix = "ticdc-"
+
+// MessageHeadersConfig represents the headers attached to the MQ messages.
+type MessageHeadersConfig struct {
+	// EnableMetadata attaches the schema, table, commit-ts, changefeed-id and
+	// event type of the message as headers.
+	EnableMetadata *bool `toml:"enable-metadata" json:"enable-metadata,omitempty"`
+	// StaticHeaders are the user-defined headers attached as they are.
+	StaticHeaders map[string]string `toml:"static-headers" json:"static-headers,omitempty"`
+}
+
+func (c *MessageHeadersConfig) validate() error {
+	for key := range c.StaticHeaders {
+		if key == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the key of the static message header is empty")
+		}
+		if strings.HasPrefix(key, MessageHeaderMetadataPrefix) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the static message header %s can not use the reserved prefix %s",
+				key, MessageHeaderMetadataPrefix)
+		}
+	}
+	return nil
+}
+
+// BootstrapConfig represents the bootstrap messages sent to the MQ system.
+type BootstrapConfig struct {
+	// Enable sends a bootstrap message with the schema of a table to all the
+	// partitions of its topic before the first row of the table.
+	Enable *bool `toml:"enable" json:"enable,omitempty"`
+	// SnapshotMarker attaches the snapshot ts to the bootstrap messages, the
+	// rows committed after the ts follow the message.
+	SnapshotMarker *bool `toml:"snapshot-marker" json:"snapshot-marker,omitempty"`
+}
+
+const (
+	// SchemaRegistryProviderConfluent is the Confluent Schema Registry whose
+	// URL is the schema-registry, it's the default provider.
+	SchemaRegistryProviderConfluent = "confluent"
+	// SchemaRegistryProviderGlue is the AWS Glue Schema Registry, the
+	// schema-registry is not used.
+	SchemaRegistryProviderGlue = "glue"
+	// SchemaRegistryProviderApicurio is the Apicurio Registry whose URL is the
+	// schema-registry, the schemas are identified by their global IDs.
+	SchemaRegistryProviderApicurio = "apicurio"
+)
+
+const (
+	// SubjectNameStrategyTopic names the subjects by the topics, such as
+	// "<topic>-value", it's the default strategy.
+	SubjectNameStrategyTopic = "topic"
+	// SubjectNameStrategyRecord names the subjects by the full names of the
+	// records, such as "<namespace>.<schema>.<table>".
+	SubjectNameStrategyRecord = "record"
+	// SubjectNameStrategyTopicRecord names the subjects by both the topics and
+	// the full names of the records, such as "<topic>-<namespace>.<schema>.<table>".
+	SubjectNameStrategyTopicRecord = "topic-record"
+)
+
+// SchemaRegistryConfig represents the provider, authentication, TLS and
+// subject naming of the schema registry used by the avro protocol.
+type SchemaRegistryConfig struct {
+	// Provider is the provider of the schema registry, the value can be
+	// "confluent", "glue" or "apicurio", the default is "confluent".
+	Provider *string `toml:"provider" json:"provider,omitempty"`
+	// GlueRegistryName and GlueRegion are the name and the region of the AWS
+	// Glue Schema Registry, the credentials of the IAM identity are used.
+	GlueRegistryName *string `toml:"glue-registry-name" json:"glue-registry-name,omitempty"`
+	GlueRegion       *string `toml:"glue-region" json:"glue-region,omitempty"`
+	// ApicurioGroupID is the artifact group of the schemas in the Apicurio
+	// Registry, the default is "default".
+	ApicurioGroupID *string `toml:"apicurio-group-id" json:"apicurio-group-id,omitempty"`
+
+	// BasicAuthUser and BasicAuthPassword are used by the basic authentication.
+	BasicAuthUser     *string `toml:"basic-auth-user" json:"basic-auth-user,omitempty"`
+	BasicAuthPassword *string `toml:"basic-auth-password" json:"basic-auth-password,omitempty"`
+	// BearerToken is used by the bearer token authentication, it can not be
+	// set with the basic authentication.
+	BearerToken *string `toml:"bearer-token" json:"bearer-token,omitempty"`
+
+	// CA, Cert and Key are the paths of the TLS files, the TLS is enabled if
+	// the CA is set.
+	CA   *string `toml:"ca" json:"ca,omitempty"`
+	Cert *string `toml:"cert" json:"cert,omitempty"`
+	Key  *string `toml:"key" json:"key,omitempty"`
+
+	// SubjectNameStrategy is the naming strategy of the subjects, the value
+	// can be "topic", "record" or "topic-record", the default is "topic".
+	// The subjects of the keys always end with "-key" since the key and value
+	// records of a table share the same name.
+	SubjectNameStrategy *string `toml:"subject-name-strategy" json:"subject-name-strategy,omitempty"`
+	// SubjectPrefix and SubjectSuffix are the templates prepended and appended
+	// to the subjects, such as "{namespace}-{changefeed}.", so the changefeeds
+	// of multiple environments can share a registry.
+	SubjectPrefix *string `toml:"subject-prefix" json:"subject-prefix,omitempty"`
+	SubjectSuffix *string `toml:"subject-suffix" json:"subject-suffix,omitempty"`
+}
+
+// SubjectTemplatePlaceholders are the placeholders supported by the subject
+// prefix and suffix templates of the schema registry.
+var SubjectTemplatePlaceholders = []string{"{namespace}", "{changefeed}"}
+
+// IsGlue returns true if the schema registry is the AWS Glue Schema Registry.
+func (c *SchemaRegistryConfig) IsGlue() bool {
+	return c != nil && util.GetOrZero(c.Provider) == SchemaRegistryProviderGlue
+}
+
+// IsApicurio returns true if the schema registry is the Apicurio Registry.
+func (c *SchemaRegistryConfig) IsApicurio() bool {
+	return c != nil && util.GetOrZero(c.Provider) == SchemaRegistryProviderApicurio
+}
+
+func (c *SchemaRegistryConfig) validate() error {
+	switch provider := util.GetOrZero(c.Provider); provider {
+	case "", SchemaRegistryProviderConfluent, SchemaRegistryProviderApicurio:
+	case SchemaRegistryProviderGlue:
+		if util.GetOrZero(c.GlueRegistryName) == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"glue-registry-name of the schema registry should be set if the provider is %s",
+				provider)
+		}
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid provider %s of the schema registry, the value can be %s, %s or %s",
+			provider, SchemaRegistryProviderConfluent, SchemaRegistryProviderGlue,
+			SchemaRegistryProviderApicurio)
+	}
+	if (c.BasicAuthUser == nil) != (c.BasicAuthPassword == nil) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"basic-auth-user and basic-auth-password of the schema registry should be set together")
+	}
+	if c.BasicAuthUser != nil && c.BearerToken != nil {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"basic-auth-user and bearer-token of the schema registry can not be set together")
+	}
+	if (c.Cert == nil) != (c.Key == nil) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"cert and key of the schema registry should be set together")
+	}
+	if c.Cert != nil && util.GetOrZero(c.CA) == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"ca of the schema registry should be set if the cert is set")
+	}
+	switch strategy := util.GetOrZero(c.SubjectNameStrategy); strategy {
+	case "", SubjectNameStrategyTopic, SubjectNameStrategyRecord,
+		SubjectNameStrategyTopicRecord:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid subject-name-strategy %s of the schema registry, the value can be %s, %s or %s",
+			strategy, SubjectNameStrategyTopic, SubjectNameStrategyRecord,
+			SubjectNameStrategyTopicRecord)
+	}
+	for _, template := range []*string{c.SubjectPrefix, c.SubjectSuffix} {
+		if err := validateSubjectTemplate(util.GetOrZero(template)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSubjectTemplate(template string) error {
+	rest := template
+	for _, placeholder := range SubjectTemplatePlaceholders {
+		rest = strings.ReplaceAll(rest, placeholder, "")
+	}
+	if strings.ContainsAny(rest, "{}") {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid subject template %s of the schema registry, the placeholders can be %s",
+			template, strings.Join(SubjectTemplatePlaceholders, ", "))
+	}
+	return nil
+}
+
+// DefaultTableMetricsMaxTables is the default max number of the tables which
+// are labeled by their names in the sink metrics of a changefeed.
+const DefaultTableMetricsMaxTables = 100
+
+// TableMetricsConfig represents the per-table labeling of the sink metrics.
+type TableMetricsConfig struct {
+	Enable *bool `toml:"enable" json:"enable,omitempty"`
+	// MaxTables is the max number of the tables labeled by their names, the
+	// other tables share a label so the cardinality of the metrics is bounded.
+	MaxTables *int `toml:"max-tables" json:"max-tables,omitempty"`
+}
+
+func (c *TableMetricsConfig) validate() error {
+	if c.MaxTables != nil && *c.MaxTables <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid table-metrics.max-tables %d, which must be greater than 0",
+			*c.MaxTables)
+	}
+	return nil
+}
+
+const (
+	// TableSinkBufferPolicyBlock stops reading the events of the tables until
+	// the buffered events are flushed if the buffer quota is exceeded.
+	TableSinkBufferPolicyBlock = "block"
+	// TableSinkBufferPolicySpill writes the buffered events to the downstream
+	// before they're resolved if the buffer quota is exceeded, which splits
+	// the transactions.
+	TableSinkBufferPolicySpill = "spill"
+	// TableSinkBufferPolicyDisk moves the buffered events to the disk of the
+	// sort engine if the buffer quota is exceeded, and stops reading the events
+	// of the tables if the disk quota is exceeded too.
+	TableSinkBufferPolicyDisk = "disk"
+)
+
+// TableSinkBufferConfig represents the quota of the events buffered by the
+// table sinks of a changefeed.
+type TableSinkBufferConfig struct {
+	// Quota is the max size in bytes of the buffered events of all tables.
+	Quota *uint64 `toml:"quota" json:"quota,omitempty"`
+	// Policy is the policy if the quota is exceeded, the value can be
+	// "block", "spill" or "disk", the default is "block". The "block" and
+	// "spill" policies can't be used if the transaction-atomicity is "table".
+	Policy *string `toml:"policy" json:"policy,omitempty"`
+	// DiskQuota is the max size in bytes of the events moved to the disk of
+	// all tables if the policy is "disk", unset means unlimited.
+	DiskQuota *uint64 `toml:"disk-quota" json:"disk-quota,omitempty"`
+}
+
+func (c *TableSinkBufferConfig) validate(txnAtomicity AtomicityLevel) error {
+	if c.Quota != nil && *c.Quota == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid table-sink-buffer.quota 0, which must be greater than 0")
+	}
+	if c.Policy != nil {
+		switch *c.Policy {
+		case TableSinkBufferPolicySpill, TableSinkBufferPolicyBlock:
+			// Spilling the buffered events splits the transactions, and
+			// blocking the tables may never resolve a partial transaction.
+			if !txnAtomicity.ShouldSplitTxn() {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"table-sink-buffer.policy %s is incompatible with the transaction-atomicity %s",
+					*c.Policy, txnAtomicity)
+			}
+		case TableSinkBufferPolicyDisk:
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid table-sink-buffer.policy %s, the value can be %s, %s or %s",
+				*c.Policy, TableSinkBufferPolicyBlock, TableSinkBufferPolicySpill,
+				TableSinkBufferPolicyDisk)
+		}
+	}
+	if c.DiskQuota != nil && *c.DiskQuota == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid table-sink-buffer.disk-quota 0, which must be greater than 0")
+	}
+	return nil
+}
+
+// KafkaSinkV2Enabled returns true if the kafka-go sink is used, it's false if
+// enable-kafka-sink-v2 is not set, see ResolveKafkaSinkV2.
+func (s *SinkConfig) KafkaSinkV2Enabled() bool {
+	return util.GetOrZero(s.EnableKafkaSinkV2)
+}
+
+// ResolveKafkaSinkV2 sets enable-kafka-sink-v2 to the default of the server if
+// it's not set. It's called once when the changefeed is created and the value
+// is persisted, so that all the captures use the same kafka sink regardless of
+// their own defaults.
+func (s *SinkConfig) ResolveKafkaSinkV2() {
+	if s.EnableKafkaSinkV2 == nil {
+		s.EnableKafkaSinkV2 = util.AddressOf(GetGlobalServerConfig().Debug.EnableKafkaSinkV2)
+	}
 }
 
 func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
@@ -370,6 +1853,59 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 		return err
 	}
 
+	for _, masker := range s.ColumnMaskers {
+		if err := masker.validate(); err != nil {
+			return err
+		}
+	}
+	for _, column := range s.ComputedColumns {
+		if err := column.validate(); err != nil {
+			return err
+		}
+	}
+	if s.Transformer != nil {
+		if err := s.Transformer.validate(); err != nil {
+			return err
+		}
+	}
+	for _, target := range s.Targets {
+		if err := target.validate(); err != nil {
+			return err
+		}
+	}
+	if s.Mirror != nil {
+		if err := s.Mirror.validate(sinkURI); err != nil {
+			return err
+		}
+	}
+	for _, route := range s.TableRoutes {
+		if err := route.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateColumnSelectors(
+		s.ColumnSelectors, sinkURI, util.GetOrZero(s.Protocol)); err != nil {
+		return err
+	}
+	if err := validateTypeMappings(s.TypeMappings, util.GetOrZero(s.Protocol)); err != nil {
+		return err
+	}
+	if s.TemporalEncoding != nil {
+		if err := s.TemporalEncoding.validate(util.GetOrZero(s.Protocol)); err != nil {
+			return err
+		}
+	}
+	if s.TableSinkBuffer != nil {
+		if err := s.TableSinkBuffer.validate(util.GetOrZero(s.TxnAtomicity)); err != nil {
+			return err
+		}
+	}
+	if s.MySQLConfig != nil && s.MySQLConfig.DDLTranslation != nil {
+		if err := s.MySQLConfig.DDLTranslation.validate(); err != nil {
+			return err
+		}
+	}
+
 	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
 		return nil
 	}
@@ -388,6 +1924,46 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 			rule.PartitionRule = rule.DispatcherRule
 			rule.DispatcherRule = ""
 		}
+		if err := rule.validateTopicOverrides(); err != nil {
+			return err
+		}
+	}
+
+	if s.KafkaConfig != nil {
+		if err := validateTopicConfigs(s.KafkaConfig.TopicConfigs); err != nil {
+			return err
+		}
+		// The topics written by the changefeed are recognized by the metadata
+		// headers of the messages, before they're deleted.
+		if util.GetOrZero(s.KafkaConfig.DeleteTopicsOnRemove) &&
+			(s.MessageHeaders == nil || !util.GetOrZero(s.MessageHeaders.EnableMetadata)) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"delete-topics-on-remove requires message-headers.enable-metadata to be true")
+		}
+	}
+
+	if s.MessageHeaders != nil {
+		if err := s.MessageHeaders.validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.TableMetrics != nil {
+		if err := s.TableMetrics.validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.SchemaRegistryConfig != nil {
+		if err := s.SchemaRegistryConfig.validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.PulsarConfig != nil {
+		if err := s.PulsarConfig.validate(); err != nil {
+			return err
+		}
 	}
 
 	if util.GetOrZero(s.EncoderConcurrency) < 0 {
@@ -395,6 +1971,13 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 			"encoder-concurrency should greater than 0, but got %d", s.EncoderConcurrency)
 	}
 
+	// The kafka-go client doesn't support the transactional producer.
+	if s.KafkaConfig != nil && util.GetOrZero(s.KafkaConfig.EnableKafkaTransactions) &&
+		s.KafkaSinkV2Enabled() {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"enable-kafka-transactions is not supported when enable-kafka-sink-v2 is true")
+	}
+
 	// validate terminator
 	if s.Terminator == nil {
 		s.Terminator = util.AddressOf(CRLF)
@@ -417,6 +2000,14 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 			}
 		}
 
+		switch layout := util.GetOrZero(s.PartitionLayout); layout {
+		case "", PartitionLayoutDefault, PartitionLayoutHive:
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid partition-layout %s, it must be %s or %s",
+				layout, PartitionLayoutDefault, PartitionLayoutHive)
+		}
+
 		// File index width should be in [minFileIndexWidth, maxFileIndexWidth].
 		// In most scenarios, the user does not need to change this configuration,
 		// so the default value of this parameter is not set and just make silent
@@ -429,6 +2020,23 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 		if err := s.CSVConfig.validateAndAdjust(); err != nil {
 			return err
 		}
+
+		if s.CloudStorageConfig != nil && s.CloudStorageConfig.FileNameTemplate != nil {
+			if err := validateFileNameTemplate(*s.CloudStorageConfig.FileNameTemplate); err != nil {
+				return err
+			}
+		}
+		if s.CloudStorageConfig != nil && s.CloudStorageConfig.RedshiftConfig != nil {
+			if err := s.CloudStorageConfig.RedshiftConfig.validate(
+				sinkURI.Scheme, protocol, util.GetOrZero(s.Terminator), s.CSVConfig); err != nil {
+				return err
+			}
+		}
+		if s.CloudStorageConfig != nil && util.GetOrZero(s.CloudStorageConfig.EnableDeltaLake) &&
+			protocol != ProtocolParquet {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"enable-delta-lake is only available for the parquet protocol, but got %s", protocol)
+		}
 	}
 
 	return nil
@@ -529,7 +2137,7 @@ func (s *SinkConfig) applyParameterBySinkURI(sinkURI *url.URL) error {
 func (s *SinkConfig) CheckCompatibilityWithSinkURI(
 	oldSinkConfig *SinkConfig, sinkURIStr string,
 ) error {
-	sinkURI, err := url.Parse(sinkURIStr)
+	sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
@@ -562,8 +2170,14 @@ const (
 	LargeMessageHandleOptionClaimCheck string = "claim-check"
 	// LargeMessageHandleOptionHandleKeyOnly means handling large message by sending only handle key columns.
 	LargeMessageHandleOptionHandleKeyOnly string = "handle-key-only"
+	// LargeMessageHandleOptionCompression means handling large message by compressing the value.
+	LargeMessageHandleOptionCompression string = "compression"
 )
 
+// maxClaimCheckPresignedURLExpiry is the max expiry of the presigned URLs,
+// which is the limit of S3.
+const maxClaimCheckPresignedURLExpiry = 7 * 24 * time.Hour
+
 const (
 	// CompressionNone no compression
 	CompressionNone string = "none"
@@ -571,6 +2185,8 @@ const (
 	CompressionSnappy string = "snappy"
 	// CompressionLZ4 compression using LZ4
 	CompressionLZ4 string = "lz4"
+	// CompressionZstd compression using zstd
+	CompressionZstd string = "zstd"
 )
 
 // LargeMessageHandleConfig is the configuration for handling large message.
@@ -578,6 +2194,23 @@ type LargeMessageHandleConfig struct {
 	LargeMessageHandleOption string `toml:"large-message-handle-option" json:"large-message-handle-option"`
 	ClaimCheckStorageURI     string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
 	ClaimCheckCompression    string `toml:"claim-check-compression" json:"claim-check-compression"`
+	// ClaimCheckBareMessage indicates that only a tiny pointer message, which contains
+	// the storage location, size and checksum of the claim-check file, is sent to the MQ.
+	// The kafka consumer reads the original messages from the claim-check storage. It's
+	// only for the MQ sinks, the storage sinks never write the claim-check files.
+	ClaimCheckBareMessage bool `toml:"claim-check-bare-message" json:"claim-check-bare-message"`
+	// ClaimCheckRetention is how long the claim-check files are retained, such
+	// as "72h". The files which are older than it and below the checkpoint of
+	// the changefeed are deleted, they are never deleted if it's empty.
+	ClaimCheckRetention string `toml:"claim-check-retention" json:"claim-check-retention,omitempty"`
+	// ClaimCheckPresignedURLExpiry is how long the presigned URL in the bare
+	// message is valid, such as "24h". If it's set, the bare message contains a
+	// presigned URL to get the claim-check file instead of the storage URI, so
+	// the consumers don't need the credentials of the storage.
+	ClaimCheckPresignedURLExpiry string `toml:"claim-check-presigned-url-expiry" json:"claim-check-presigned-url-expiry,omitempty"`
+	// LargeMessageCompression is the codec used to compress the large message,
+	// it can be lz4 or zstd, and lz4 is used if it's empty.
+	LargeMessageCompression string `toml:"large-message-compression" json:"large-message-compression,omitempty"`
 }
 
 // NewDefaultLargeMessageHandleConfig return the default LargeMessageHandleConfig.
@@ -590,6 +2223,26 @@ func NewDefaultLargeMessageHandleConfig() *LargeMessageHandleConfig {
 
 // Validate the LargeMessageHandleConfig.
 func (c *LargeMessageHandleConfig) Validate(protocol Protocol, enableTiDBExtension bool) error {
+	if c.ClaimCheckBareMessage && c.LargeMessageHandleOption != LargeMessageHandleOptionClaimCheck {
+		return cerror.ErrInvalidReplicaConfig.GenWithStack(
+			"claim-check-bare-message is set, but large message handle is %s", c.LargeMessageHandleOption)
+	}
+
+	if c.ClaimCheckRetention != "" && c.LargeMessageHandleOption != LargeMessageHandleOptionClaimCheck {
+		return cerror.ErrInvalidReplicaConfig.GenWithStack(
+			"claim-check-retention is set, but large message handle is %s", c.LargeMessageHandleOption)
+	}
+
+	if c.ClaimCheckPresignedURLExpiry != "" && !c.EnableClaimCheckBareMessage() {
+		return cerror.ErrInvalidReplicaConfig.GenWithStack(
+			"claim-check-presigned-url-expiry is set, but claim-check-bare-message isn't enabled")
+	}
+
+	if c.LargeMessageCompression != "" && c.LargeMessageHandleOption != LargeMessageHandleOptionCompression {
+		return cerror.ErrInvalidReplicaConfig.GenWithStack(
+			"large-message-compression is set, but large message handle is %s", c.LargeMessageHandleOption)
+	}
+
 	if c.LargeMessageHandleOption == LargeMessageHandleOptionNone {
 		return nil
 	}
@@ -597,7 +2250,9 @@ func (c *LargeMessageHandleConfig) Validate(protocol Protocol, enableTiDBExtensi
 	switch protocol {
 	case ProtocolOpen:
 	case ProtocolCanalJSON:
-		if !enableTiDBExtension {
+		// the compressed message is decompressed as a whole by the consumer,
+		// so it doesn't depend on the tidb extension fields.
+		if !enableTiDBExtension && c.LargeMessageHandleOption != LargeMessageHandleOptionCompression {
 			return cerror.ErrInvalidReplicaConfig.GenWithStack(
 				"large message handle is set to %s, protocol is %s, but enable-tidb-extension is false",
 				c.LargeMessageHandleOption, protocol.String())
@@ -622,6 +2277,31 @@ func (c *LargeMessageHandleConfig) Validate(protocol Protocol, enableTiDBExtensi
 					"claim-check compression support snappy, lz4, got %s", c.ClaimCheckCompression)
 			}
 		}
+
+		if c.ClaimCheckRetention != "" {
+			if d, err := time.ParseDuration(c.ClaimCheckRetention); err != nil || d < 0 {
+				return cerror.ErrInvalidReplicaConfig.GenWithStack(
+					"claim-check-retention should be a non-negative duration, got %s", c.ClaimCheckRetention)
+			}
+		}
+
+		if c.ClaimCheckPresignedURLExpiry != "" {
+			d, err := time.ParseDuration(c.ClaimCheckPresignedURLExpiry)
+			if err != nil || d <= 0 || d > maxClaimCheckPresignedURLExpiry {
+				return cerror.ErrInvalidReplicaConfig.GenWithStack(
+					"claim-check-presigned-url-expiry should be a positive duration no longer than %s, got %s",
+					maxClaimCheckPresignedURLExpiry, c.ClaimCheckPresignedURLExpiry)
+			}
+		}
+	}
+
+	if c.LargeMessageHandleOption == LargeMessageHandleOptionCompression {
+		switch strings.ToLower(c.LargeMessageCompression) {
+		case "", CompressionLZ4, CompressionZstd:
+		default:
+			return cerror.ErrInvalidReplicaConfig.GenWithStack(
+				"large message compression support lz4, zstd, got %s", c.LargeMessageCompression)
+		}
 	}
 	return nil
 }
@@ -642,6 +2322,54 @@ func (c *LargeMessageHandleConfig) EnableClaimCheck() bool {
 	return c.LargeMessageHandleOption == LargeMessageHandleOptionClaimCheck
 }
 
+// EnableClaimCheckBareMessage returns true if enable claim check and
+// only send the location pointer message to the MQ.
+func (c *LargeMessageHandleConfig) EnableClaimCheckBareMessage() bool {
+	return c.EnableClaimCheck() && c.ClaimCheckBareMessage
+}
+
+// ClaimCheckRetentionDuration returns the retention of the claim-check files,
+// and false if the files should never be deleted.
+func (c *LargeMessageHandleConfig) ClaimCheckRetentionDuration() (time.Duration, bool) {
+	if !c.EnableClaimCheck() || c.ClaimCheckRetention == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(c.ClaimCheckRetention)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// ClaimCheckPresignedURLExpiryDuration returns how long the presigned URL in
+// the bare message is valid, and false if the URL shouldn't be presigned.
+func (c *LargeMessageHandleConfig) ClaimCheckPresignedURLExpiryDuration() (time.Duration, bool) {
+	if !c.EnableClaimCheckBareMessage() || c.ClaimCheckPresignedURLExpiry == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(c.ClaimCheckPresignedURLExpiry)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// EnableCompression returns true if handle large message by compressing the value.
+func (c *LargeMessageHandleConfig) EnableCompression() bool {
+	if c == nil {
+		return false
+	}
+	return c.LargeMessageHandleOption == LargeMessageHandleOptionCompression
+}
+
+// CompressionCodec returns the codec used to compress the large message.
+func (c *LargeMessageHandleConfig) CompressionCodec() string {
+	if c == nil || c.LargeMessageCompression == "" {
+		return CompressionLZ4
+	}
+	return strings.ToLower(c.LargeMessageCompression)
+}
+
 // Disabled returns true if disable large message handle.
 func (c *LargeMessageHandleConfig) Disabled() bool {
 	if c == nil {
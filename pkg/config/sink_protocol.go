@@ -38,6 +38,9 @@ const (
 	ProtocolCraft
 	ProtocolOpen
 	ProtocolCsv
+	ProtocolParquet
+	ProtocolDebezium
+	ProtocolJSONSchema
 )
 
 // IsBatchEncode returns whether the protocol is a batch encoder.
@@ -66,6 +69,12 @@ func ParseSinkProtocolFromString(protocol string) (Protocol, error) {
 		return ProtocolOpen, nil
 	case "csv":
 		return ProtocolCsv, nil
+	case "parquet":
+		return ProtocolParquet, nil
+	case "debezium":
+		return ProtocolDebezium, nil
+	case "json-schema":
+		return ProtocolJSONSchema, nil
 	default:
 		return ProtocolUnknown, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(protocol)
 	}
@@ -90,6 +99,12 @@ func (p Protocol) String() string {
 		return "open-protocol"
 	case ProtocolCsv:
 		return "csv"
+	case ProtocolParquet:
+		return "parquet"
+	case ProtocolDebezium:
+		return "debezium"
+	case ProtocolJSONSchema:
+		return "json-schema"
 	default:
 		panic("unreachable")
 	}
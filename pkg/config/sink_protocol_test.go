@@ -62,6 +62,18 @@ func TestParseSinkProtocolFromString(t *testing.T) {
 			protocol:             "open-protocol",
 			expectedProtocolEnum: ProtocolOpen,
 		},
+		{
+			protocol:             "parquet",
+			expectedProtocolEnum: ProtocolParquet,
+		},
+		{
+			protocol:             "debezium",
+			expectedProtocolEnum: ProtocolDebezium,
+		},
+		{
+			protocol:             "json-schema",
+			expectedProtocolEnum: ProtocolJSONSchema,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -109,6 +121,14 @@ func TestString(t *testing.T) {
 			protocolEnum:     ProtocolOpen,
 			expectedProtocol: "open-protocol",
 		},
+		{
+			protocolEnum:     ProtocolDebezium,
+			expectedProtocol: "debezium",
+		},
+		{
+			protocolEnum:     ProtocolJSONSchema,
+			expectedProtocol: "json-schema",
+		},
 	}
 
 	for _, tc := range testCases {
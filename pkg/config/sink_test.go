@@ -15,7 +15,9 @@ package config
 
 import (
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -343,6 +345,84 @@ func TestValidateAndAdjustCSVConfig(t *testing.T) {
 			},
 			wantErr: "csv config binary-encoding-method can only be hex or base64",
 		},
+		{
+			name: "valid quoting policy and escape character",
+			config: &CSVConfig{
+				Quote:                "\"",
+				Delimiter:            ",",
+				QuotingPolicy:        QuotingPolicyMinimal,
+				EscapeChar:           "\\",
+				BinaryEncodingMethod: BinaryEncodingHex,
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid quoting policy",
+			config: &CSVConfig{
+				Quote:         "\"",
+				Delimiter:     ",",
+				QuotingPolicy: "never",
+			},
+			wantErr: "csv config quoting-policy can only be always, minimal or non-numeric",
+		},
+		{
+			name: "invalid header policy",
+			config: &CSVConfig{
+				Quote:         "\"",
+				Delimiter:     ",",
+				IncludeHeader: "last-file",
+			},
+			wantErr: "csv config include-header can only be none, every-file or first-file",
+		},
+		{
+			name: "escape character has multiple characters",
+			config: &CSVConfig{
+				Quote:      "\"",
+				Delimiter:  ",",
+				EscapeChar: "\\\\",
+			},
+			wantErr: "csv config escape-char contains more than one character",
+		},
+		{
+			name: "escape character and quote are same",
+			config: &CSVConfig{
+				Quote:      "\"",
+				Delimiter:  ",",
+				EscapeChar: "\"",
+			},
+			wantErr: "csv config escape-char and quote cannot be the same",
+		},
+		{
+			name: "escape character and delimiter are same",
+			config: &CSVConfig{
+				Quote:      "\"",
+				Delimiter:  "|",
+				EscapeChar: "|",
+			},
+			wantErr: "csv config escape-char and delimiter cannot be the same",
+		},
+		{
+			name: "column rule without matcher",
+			config: &CSVConfig{
+				Quote:                "\"",
+				Delimiter:            ",",
+				BinaryEncodingMethod: BinaryEncodingBase64,
+				ColumnRules:          []*CSVColumnRule{{Columns: []string{"id"}}},
+			},
+			wantErr: "the matcher of the csv column rule is empty",
+		},
+		{
+			name: "column rule with duplicated columns",
+			config: &CSVConfig{
+				Quote:                "\"",
+				Delimiter:            ",",
+				BinaryEncodingMethod: BinaryEncodingBase64,
+				ColumnRules: []*CSVColumnRule{
+					{Matcher: []string{"test.*"}, Columns: []string{"id", "ID"}},
+				},
+			},
+			wantErr: "duplicated column ID in the csv column rule",
+		},
 	}
 	for _, c := range tests {
 		tc := c
@@ -378,4 +458,789 @@ func TestValidateAndAdjustStorageConfig(t *testing.T) {
 	err = s.ValidateAndAdjust(sinkURI)
 	require.NoError(t, err)
 	require.Equal(t, 16, util.GetOrZero(s.Sink.FileIndexWidth))
+
+	s.Sink.PartitionLayout = util.AddressOf(PartitionLayoutHive)
+	require.NoError(t, s.ValidateAndAdjust(sinkURI))
+	s.Sink.PartitionLayout = util.AddressOf("glue")
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "invalid partition-layout glue")
+}
+
+func TestValidateRedshiftConfig(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *RedshiftConfig {
+		return &RedshiftConfig{
+			ClusterIdentifier: util.AddressOf("cluster"),
+			Database:          util.AddressOf("dev"),
+			IAMRole:           util.AddressOf("arn:aws:iam::123456789012:role/copy"),
+		}
+	}
+	cases := []struct {
+		uri        string
+		terminator string
+		config     func(c *RedshiftConfig)
+		err        string
+	}{
+		{"s3://bucket/prefix?protocol=csv", "\n", func(c *RedshiftConfig) {}, ""},
+		{"file:///tmp/prefix?protocol=csv", "\n", func(c *RedshiftConfig) {}, "s3 sink URI"},
+		{"s3://bucket/prefix?protocol=canal-json", "\n", func(c *RedshiftConfig) {}, "csv protocol"},
+		{"s3://bucket/prefix?protocol=csv", "\r\n", func(c *RedshiftConfig) {}, "terminator"},
+		{"s3://bucket/prefix?protocol=csv", "\n", func(c *RedshiftConfig) {
+			c.WorkgroupName = util.AddressOf("workgroup")
+		}, "cluster-identifier and workgroup-name"},
+		{"s3://bucket/prefix?protocol=csv", "\n", func(c *RedshiftConfig) {
+			c.IAMRole = nil
+		}, "iam-role"},
+		{"s3://bucket/prefix?protocol=csv", "\n", nil, "include-commit-ts"},
+	}
+	for _, c := range cases {
+		sinkURI, err := url.Parse(c.uri)
+		require.NoError(t, err)
+		s := GetDefaultReplicaConfig()
+		s.Sink.Terminator = util.AddressOf(c.terminator)
+		s.Sink.CSVConfig.IncludeCommitTs = c.config != nil
+		redshift := valid()
+		if c.config != nil {
+			c.config(redshift)
+		}
+		s.Sink.CloudStorageConfig = &CloudStorageConfig{RedshiftConfig: redshift}
+		err = s.ValidateAndAdjust(sinkURI)
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err, c.uri)
+		}
+	}
+}
+
+func TestResolveKafkaSinkV2(t *testing.T) {
+	serverConfig := GetGlobalServerConfig()
+	defer StoreGlobalServerConfig(serverConfig)
+	newConfig := serverConfig.Clone()
+	newConfig.Debug.EnableKafkaSinkV2 = true
+	StoreGlobalServerConfig(newConfig)
+
+	// the default of the server isn't used until it's resolved.
+	s := &SinkConfig{}
+	require.False(t, s.KafkaSinkV2Enabled())
+	s.ResolveKafkaSinkV2()
+	require.True(t, s.KafkaSinkV2Enabled())
+
+	s = &SinkConfig{EnableKafkaSinkV2: util.AddressOf(false)}
+	s.ResolveKafkaSinkV2()
+	require.False(t, s.KafkaSinkV2Enabled())
+}
+
+func TestCSVHeaderPolicy(t *testing.T) {
+	t.Parallel()
+
+	c := &CSVConfig{}
+	require.Equal(t, CSVHeaderNone, c.HeaderPolicy())
+	c.IncludeHeader = CSVHeaderEveryFile
+	require.Equal(t, CSVHeaderEveryFile, c.HeaderPolicy())
+	c.IncludeHeader = CSVHeaderFirstFile
+	require.Equal(t, CSVHeaderFirstFile, c.HeaderPolicy())
+	c.IncludeHeader = CSVHeaderNone
+	require.Equal(t, CSVHeaderNone, c.HeaderPolicy())
+
+	// COPY can't skip the header row of the first data files only.
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	s := GetDefaultReplicaConfig()
+	s.Sink.Terminator = util.AddressOf("\n")
+	s.Sink.CSVConfig.IncludeCommitTs = true
+	s.Sink.CSVConfig.IncludeHeader = CSVHeaderFirstFile
+	s.Sink.CloudStorageConfig = &CloudStorageConfig{RedshiftConfig: &RedshiftConfig{
+		ClusterIdentifier: util.AddressOf("cluster"),
+		Database:          util.AddressOf("dev"),
+		IAMRole:           util.AddressOf("arn:aws:iam::123456789012:role/copy"),
+	}}
+	require.ErrorContains(t, s.ValidateAndAdjust(sinkURI), "include-header")
+	s.Sink.CSVConfig.IncludeHeader = CSVHeaderEveryFile
+	require.NoError(t, s.ValidateAndAdjust(sinkURI))
+}
+
+func TestValidateDeltaLakeConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		uri string
+		err string
+	}{
+		{"s3://bucket/prefix?protocol=parquet", ""},
+		{"file:///tmp/prefix?protocol=parquet", ""},
+		{"s3://bucket/prefix?protocol=csv", "parquet protocol"},
+	}
+	for _, c := range cases {
+		sinkURI, err := url.Parse(c.uri)
+		require.NoError(t, err)
+		s := GetDefaultReplicaConfig()
+		s.Sink.Protocol = util.AddressOf(sinkURI.Query().Get("protocol"))
+		s.Sink.CloudStorageConfig = &CloudStorageConfig{EnableDeltaLake: util.AddressOf(true)}
+		err = s.ValidateAndAdjust(sinkURI)
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err, c.uri)
+		}
+	}
+}
+
+func TestValidateFileNameTemplate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		template string
+		err      string
+	}{
+		{"CDC{index}{extension}", ""},
+		{"{schema}.{table}_{date}_part{index}{extension}", ""},
+		{"data{extension}", "exactly once"},
+		{"{index}_{index}{extension}", "exactly once"},
+		{"{date}/{index}{extension}", "should not contain"},
+		{"{db}_{index}{extension}", "unknown placeholder"},
+	}
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	for _, c := range cases {
+		s := GetDefaultReplicaConfig()
+		s.Sink.CloudStorageConfig = &CloudStorageConfig{FileNameTemplate: util.AddressOf(c.template)}
+		err = s.ValidateAndAdjust(sinkURI)
+		if c.err == "" {
+			require.NoError(t, err, c.template)
+		} else {
+			require.ErrorContains(t, err, c.err, c.template)
+		}
+	}
+}
+
+func TestValidateLargeMessageHandleBareMessage(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultLargeMessageHandleConfig()
+	c.ClaimCheckBareMessage = true
+	err := c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "claim-check-bare-message is set")
+
+	c.LargeMessageHandleOption = LargeMessageHandleOptionClaimCheck
+	c.ClaimCheckStorageURI = "file:///tmp/claim-check"
+	c.ClaimCheckCompression = CompressionSnappy
+	err = c.Validate(ProtocolOpen, false)
+	require.NoError(t, err)
+	require.True(t, c.EnableClaimCheckBareMessage())
+}
+
+func TestValidateLargeMessageHandleClaimCheckRetention(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultLargeMessageHandleConfig()
+	c.ClaimCheckRetention = "72h"
+	err := c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "claim-check-retention is set")
+	_, ok := c.ClaimCheckRetentionDuration()
+	require.False(t, ok)
+
+	c.LargeMessageHandleOption = LargeMessageHandleOptionClaimCheck
+	c.ClaimCheckStorageURI = "file:///tmp/claim-check"
+	c.ClaimCheckCompression = CompressionSnappy
+	require.NoError(t, c.Validate(ProtocolOpen, false))
+	retention, ok := c.ClaimCheckRetentionDuration()
+	require.True(t, ok)
+	require.Equal(t, 72*time.Hour, retention)
+
+	c.ClaimCheckRetention = "3 days"
+	err = c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "non-negative duration")
+}
+
+func TestValidateLargeMessageHandleClaimCheckPresignedURLExpiry(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultLargeMessageHandleConfig()
+	c.LargeMessageHandleOption = LargeMessageHandleOptionClaimCheck
+	c.ClaimCheckStorageURI = "s3://bucket/claim-check"
+	c.ClaimCheckCompression = CompressionSnappy
+	c.ClaimCheckPresignedURLExpiry = "24h"
+	err := c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "claim-check-bare-message isn't enabled")
+	_, ok := c.ClaimCheckPresignedURLExpiryDuration()
+	require.False(t, ok)
+
+	c.ClaimCheckBareMessage = true
+	require.NoError(t, c.Validate(ProtocolOpen, false))
+	expiry, ok := c.ClaimCheckPresignedURLExpiryDuration()
+	require.True(t, ok)
+	require.Equal(t, 24*time.Hour, expiry)
+
+	for _, expiry := range []string{"0s", "-1h", "169h", "1 day"} {
+		c.ClaimCheckPresignedURLExpiry = expiry
+		err = c.Validate(ProtocolOpen, false)
+		require.ErrorContains(t, err, "positive duration no longer than", expiry)
+	}
+}
+
+func TestValidateLargeMessageHandleCompression(t *testing.T) {
+	t.Parallel()
+
+	c := NewDefaultLargeMessageHandleConfig()
+	c.LargeMessageCompression = CompressionZstd
+	err := c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "large-message-compression is set")
+
+	c.LargeMessageHandleOption = LargeMessageHandleOptionCompression
+	c.LargeMessageCompression = CompressionSnappy
+	err = c.Validate(ProtocolOpen, false)
+	require.ErrorContains(t, err, "support lz4, zstd")
+
+	// the tidb extension is not required by the compression option.
+	c.LargeMessageCompression = CompressionZstd
+	err = c.Validate(ProtocolCanalJSON, false)
+	require.NoError(t, err)
+	require.True(t, c.EnableCompression())
+	require.Equal(t, CompressionZstd, c.CompressionCodec())
+
+	c.LargeMessageCompression = ""
+	require.Equal(t, CompressionLZ4, c.CompressionCodec())
+
+	err = c.Validate(ProtocolAvro, false)
+	require.ErrorContains(t, err, "it's not supported")
+}
+
+func TestValidateColumnMasker(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		masker *ColumnMasker
+		err    string
+	}{
+		{&ColumnMasker{Columns: []string{"a"}, Mask: ColumnMaskNull}, "matcher"},
+		{&ColumnMasker{Matcher: []string{"test.*"}, Mask: ColumnMaskNull}, "columns"},
+		{&ColumnMasker{Matcher: []string{"test.*"}, Columns: []string{"a"}, Mask: "md5"}, "invalid mask function md5"},
+		{
+			&ColumnMasker{
+				Matcher: []string{"test.*"}, Columns: []string{"a"},
+				Mask: ColumnMaskPartial, KeepPrefix: -1,
+			},
+			"should not be negative",
+		},
+		{
+			&ColumnMasker{
+				Matcher: []string{"test.*"}, Columns: []string{"a"},
+				Mask: ColumnMaskPartial, KeepPrefix: 3, KeepSuffix: 4,
+			},
+			"",
+		},
+	}
+	for _, c := range cases {
+		err := c.masker.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateComputedColumn(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		column *ComputedColumn
+		err    string
+	}{
+		{&ComputedColumn{Name: "region", Value: "us-west-1"}, "matcher"},
+		{&ComputedColumn{Matcher: []string{"test.*"}, Value: "us-west-1"}, "name"},
+		{&ComputedColumn{Matcher: []string{"test.*"}, Name: "region", Value: "us-west-1"}, ""},
+	}
+	for _, c := range cases {
+		err := c.column.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateColumnSelectors(t *testing.T) {
+	t.Parallel()
+
+	selector := &ColumnSelector{Matcher: []string{"test.*"}, Columns: []string{"a*", "!b"}}
+	cases := []struct {
+		uri       string
+		selectors []*ColumnSelector
+		err       string
+	}{
+		{"kafka://127.0.0.1:9092/test?protocol=open-protocol", []*ColumnSelector{selector}, ""},
+		{"kafka://127.0.0.1:9092/test?protocol=canal-json", []*ColumnSelector{selector}, ""},
+		{"kafka+ssl://127.0.0.1:9093/test?protocol=avro", []*ColumnSelector{selector}, ""},
+		{"kafka://127.0.0.1:9092/test?protocol=canal", []*ColumnSelector{selector}, "open-protocol, canal-json and avro"},
+		{"mysql://127.0.0.1:3306/", []*ColumnSelector{selector}, "only available for the MQ sinks"},
+		{"s3://bucket/prefix?protocol=csv", []*ColumnSelector{selector}, "only available for the MQ sinks"},
+		{"mysql://127.0.0.1:3306/", nil, ""},
+		{
+			"kafka://127.0.0.1:9092/test?protocol=open-protocol",
+			[]*ColumnSelector{{Columns: []string{"a"}}},
+			"matcher",
+		},
+		{
+			"kafka://127.0.0.1:9092/test?protocol=open-protocol",
+			[]*ColumnSelector{{Matcher: []string{"test.*"}}},
+			"columns",
+		},
+		{
+			"kafka://127.0.0.1:9092/test?protocol=open-protocol",
+			[]*ColumnSelector{{Matcher: []string{"test.*"}, Columns: []string{"["}}},
+			"ErrSinkInvalidConfig",
+		},
+	}
+	for _, c := range cases {
+		sinkURI, err := url.Parse(c.uri)
+		require.NoError(t, err)
+		err = validateColumnSelectors(c.selectors, sinkURI, sinkURI.Query().Get("protocol"))
+		if c.err == "" {
+			require.NoError(t, err, c.uri)
+		} else {
+			require.ErrorContains(t, err, c.err, c.uri)
+		}
+	}
+}
+
+func TestValidateTypeMappings(t *testing.T) {
+	t.Parallel()
+
+	bitToInt := &TypeMapping{Source: "bit", Target: "int"}
+	cases := []struct {
+		protocol string
+		mappings []*TypeMapping
+		err      string
+	}{
+		{"avro", []*TypeMapping{bitToInt}, ""},
+		{"json-schema", []*TypeMapping{bitToInt, {Source: "tinyint(1)", Target: "bool"}}, ""},
+		{"avro", []*TypeMapping{{Source: "json", Target: "string"}}, ""},
+		{"canal-json", []*TypeMapping{bitToInt}, "only available for the avro and json-schema"},
+		{"", []*TypeMapping{bitToInt}, "only available for the avro and json-schema"},
+		{"", nil, ""},
+		// the mappings of the other protocols are ignored.
+		{"canal-json", []*TypeMapping{{Protocols: []string{"avro"}, Source: "bit", Target: "int"}}, ""},
+		{"avro", []*TypeMapping{{Protocols: []string{"canal-json"}, Source: "bit", Target: "int"}}, "but got canal-json"},
+		{"avro", []*TypeMapping{{Protocols: []string{"unknown"}, Source: "bit", Target: "int"}}, "ErrSinkInvalidConfig"},
+		{"avro", []*TypeMapping{{Source: "date", Target: "int"}}, "the source of the type mapping"},
+		{"avro", []*TypeMapping{{Source: "bit", Target: "bool"}}, "can be bytes or int, but got bool"},
+	}
+	for _, c := range cases {
+		err := validateTypeMappings(c.mappings, c.protocol)
+		if c.err == "" {
+			require.NoError(t, err, c.protocol)
+		} else {
+			require.ErrorContains(t, err, c.err, c.protocol)
+		}
+	}
+
+	mapping := &TypeMapping{Protocols: []string{"AVRO"}, Source: "bit", Target: "int"}
+	require.True(t, mapping.MatchProtocol(ProtocolAvro))
+	require.False(t, mapping.MatchProtocol(ProtocolJSONSchema))
+	require.True(t, bitToInt.MatchProtocol(ProtocolJSONSchema))
+	require.False(t, bitToInt.MatchProtocol(ProtocolCanalJSON))
+}
+
+func TestValidateSinkTarget(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		target *SinkTarget
+		err    string
+	}{
+		{&SinkTarget{Matcher: []string{"test.*"}, SinkURI: "kafka://127.0.0.1:9092/topic"}, ""},
+		{&SinkTarget{SinkURI: "kafka://127.0.0.1:9092/topic"}, "matcher"},
+		{&SinkTarget{Matcher: []string{"[test.*"}, SinkURI: "kafka://127.0.0.1:9092/topic"}, "ErrSinkInvalidConfig"},
+		{&SinkTarget{Matcher: []string{"test.*"}}, "sink-uri"},
+		{&SinkTarget{Matcher: []string{"test.*"}, SinkURI: "kafka://[::1"}, "ErrSinkURIInvalid"},
+	}
+	for _, c := range cases {
+		err := c.target.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateTransformer(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		transformer *TransformerConfig
+		err         string
+	}{
+		{&TransformerConfig{WASMFile: "/tmp/transformer.wasm"}, ""},
+		{&TransformerConfig{Matcher: []string{"test.*"}, WASMFile: "/tmp/transformer.wasm"}, ""},
+		{&TransformerConfig{Matcher: []string{"test.*"}}, "wasm-file"},
+		{&TransformerConfig{Matcher: []string{"[test.*"}, WASMFile: "/tmp/transformer.wasm"}, "ErrSinkInvalidConfig"},
+		{&TransformerConfig{
+			WASMFile:     "/tmp/transformer.wasm",
+			WASMChecksum: strings.Repeat("0a", 32),
+		}, ""},
+		{&TransformerConfig{
+			WASMFile:     "/tmp/transformer.wasm",
+			WASMChecksum: strings.Repeat("0A", 32),
+		}, "wasm-sha256"},
+		{&TransformerConfig{WASMFile: "/tmp/transformer.wasm", WASMChecksum: "0a"}, "wasm-sha256"},
+	}
+	for _, c := range cases {
+		err := c.transformer.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateTemporalEncoding(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		protocol string
+		encoding *TemporalEncodingConfig
+		err      string
+	}{
+		{"canal-json", &TemporalEncodingConfig{Mode: util.AddressOf("epoch-millis")}, ""},
+		{"csv", &TemporalEncodingConfig{
+			Mode:           util.AddressOf("rfc3339"),
+			SourceTimeZone: util.AddressOf("Asia/Shanghai"),
+			TimeZone:       util.AddressOf("UTC"),
+		}, ""},
+		{"avro", &TemporalEncodingConfig{}, ""},
+		{"canal", &TemporalEncodingConfig{}, "only available for the open-protocol"},
+		{"canal", &TemporalEncodingConfig{Protocols: []string{"avro"}}, ""},
+		{"avro", &TemporalEncodingConfig{Protocols: []string{"maxwell"}}, "but got maxwell"},
+		{"avro", &TemporalEncodingConfig{Mode: util.AddressOf("iso8601")}, "but got iso8601"},
+		{"avro", &TemporalEncodingConfig{TimeZone: util.AddressOf("Mars/Olympus")}, "ErrSinkInvalidConfig"},
+	}
+	for _, c := range cases {
+		err := c.encoding.validate(c.protocol)
+		if c.err == "" {
+			require.NoError(t, err, c.protocol)
+		} else {
+			require.ErrorContains(t, err, c.err, c.protocol)
+		}
+	}
+
+	encoding := &TemporalEncodingConfig{Protocols: []string{"csv"}}
+	require.True(t, encoding.MatchProtocol(ProtocolCsv))
+	require.False(t, encoding.MatchProtocol(ProtocolAvro))
+	require.True(t, (&TemporalEncodingConfig{}).MatchProtocol(ProtocolOpen))
+	require.False(t, (&TemporalEncodingConfig{}).MatchProtocol(ProtocolMaxwell))
+}
+
+func TestValidateMessageHeadersConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		headers *MessageHeadersConfig
+		err     string
+	}{
+		{&MessageHeadersConfig{StaticHeaders: map[string]string{"": "a"}}, "is empty"},
+		{&MessageHeadersConfig{StaticHeaders: map[string]string{"ticdc-schema": "a"}}, "reserved prefix"},
+		{&MessageHeadersConfig{StaticHeaders: map[string]string{"env": "prod"}}, ""},
+	}
+	for _, c := range cases {
+		err := c.headers.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateDeleteTopicsOnRemove(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test?protocol=open-protocol")
+	require.NoError(t, err)
+	cfg := GetDefaultReplicaConfig().Sink
+	cfg.KafkaConfig = &KafkaConfig{DeleteTopicsOnRemove: util.AddressOf(true)}
+	require.ErrorContains(t, cfg.validateAndAdjust(sinkURI), "requires message-headers.enable-metadata")
+
+	cfg.MessageHeaders = &MessageHeadersConfig{EnableMetadata: util.AddressOf(true)}
+	require.NoError(t, cfg.validateAndAdjust(sinkURI))
+}
+
+func TestValidateDispatchRuleTopicOverrides(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		rule *DispatchRule
+		err  string
+	}{
+		{&DispatchRule{Matcher: []string{"a.*"}}, ""},
+		{&DispatchRule{Matcher: []string{"a.*"}, TopicPartitionNum: 3}, "topic of the dispatch rule"},
+		{&DispatchRule{Matcher: []string{"a.*"}, TopicRule: "a_{table}", TopicPartitionNum: -1}, "should be positive"},
+		{&DispatchRule{
+			Matcher: []string{"a.*"}, TopicRule: "a_{table}",
+			TopicConfigs: map[string]string{" ": "1"},
+		}, "is empty"},
+		{&DispatchRule{
+			Matcher: []string{"a.*"}, TopicRule: "a_{table}", TopicPartitionNum: 3,
+			TopicConfigs: map[string]string{"retention.ms": "86400000"},
+		}, ""},
+	}
+	for _, c := range cases {
+		err := c.rule.validateTopicOverrides()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateTableMetricsConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		config *TableMetricsConfig
+		err    string
+	}{
+		{&TableMetricsConfig{Enable: util.AddressOf(true), MaxTables: util.AddressOf(0)}, "invalid table-metrics.max-tables"},
+		{&TableMetricsConfig{MaxTables: util.AddressOf(-1)}, "invalid table-metrics.max-tables"},
+		{&TableMetricsConfig{Enable: util.AddressOf(true)}, ""},
+		{&TableMetricsConfig{Enable: util.AddressOf(true), MaxTables: util.AddressOf(10)}, ""},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateTableSinkBufferConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		config       *TableSinkBufferConfig
+		txnAtomicity AtomicityLevel
+		err          string
+	}{
+		{&TableSinkBufferConfig{Quota: util.AddressOf(uint64(0))}, "", "invalid table-sink-buffer.quota"},
+		{&TableSinkBufferConfig{Policy: util.AddressOf("drop")}, "", "invalid table-sink-buffer.policy"},
+		{&TableSinkBufferConfig{Quota: util.AddressOf(uint64(1024))}, "", ""},
+		{&TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1024)),
+			Policy: util.AddressOf(TableSinkBufferPolicySpill),
+		}, noneTxnAtomicity, ""},
+		{&TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1024)),
+			Policy: util.AddressOf(TableSinkBufferPolicySpill),
+		}, tableTxnAtomicity, "incompatible with the transaction-atomicity table"},
+		{&TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1024)),
+			Policy: util.AddressOf(TableSinkBufferPolicyBlock),
+		}, tableTxnAtomicity, "incompatible with the transaction-atomicity table"},
+		{&TableSinkBufferConfig{
+			Quota:  util.AddressOf(uint64(1024)),
+			Policy: util.AddressOf(TableSinkBufferPolicyBlock),
+		}, noneTxnAtomicity, ""},
+		{&TableSinkBufferConfig{
+			Policy:    util.AddressOf(TableSinkBufferPolicyDisk),
+			DiskQuota: util.AddressOf(uint64(0)),
+		}, "", "invalid table-sink-buffer.disk-quota"},
+		{&TableSinkBufferConfig{
+			Quota:     util.AddressOf(uint64(1024)),
+			Policy:    util.AddressOf(TableSinkBufferPolicyDisk),
+			DiskQuota: util.AddressOf(uint64(1 << 20)),
+		}, tableTxnAtomicity, ""},
+	}
+	for _, c := range cases {
+		err := c.config.validate(c.txnAtomicity)
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateSchemaRegistryConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		config *SchemaRegistryConfig
+		err    string
+	}{
+		{&SchemaRegistryConfig{BasicAuthUser: util.AddressOf("root")}, "should be set together"},
+		{&SchemaRegistryConfig{
+			BasicAuthUser:     util.AddressOf("root"),
+			BasicAuthPassword: util.AddressOf("secret"),
+			BearerToken:       util.AddressOf("token"),
+		}, "can not be set together"},
+		{&SchemaRegistryConfig{Cert: util.AddressOf("cert.pem")}, "cert and key"},
+		{&SchemaRegistryConfig{
+			Cert: util.AddressOf("cert.pem"),
+			Key:  util.AddressOf("key.pem"),
+		}, "ca of the schema registry"},
+		{&SchemaRegistryConfig{SubjectNameStrategy: util.AddressOf("table")}, "invalid subject-name-strategy"},
+		{&SchemaRegistryConfig{SubjectPrefix: util.AddressOf("{env}.")}, "invalid subject template"},
+		{&SchemaRegistryConfig{
+			BasicAuthUser:       util.AddressOf("root"),
+			BasicAuthPassword:   util.AddressOf("secret"),
+			CA:                  util.AddressOf("ca.pem"),
+			SubjectNameStrategy: util.AddressOf(SubjectNameStrategyTopicRecord),
+			SubjectPrefix:       util.AddressOf("{namespace}-{changefeed}."),
+			SubjectSuffix:       util.AddressOf("-prod"),
+		}, ""},
+		{&SchemaRegistryConfig{BearerToken: util.AddressOf("token")}, ""},
+		{&SchemaRegistryConfig{Provider: util.AddressOf("azure")}, "invalid provider"},
+		{&SchemaRegistryConfig{
+			Provider: util.AddressOf(SchemaRegistryProviderGlue),
+		}, "glue-registry-name"},
+		{&SchemaRegistryConfig{
+			Provider:         util.AddressOf(SchemaRegistryProviderGlue),
+			GlueRegistryName: util.AddressOf("ticdc"),
+			GlueRegion:       util.AddressOf("us-east-1"),
+		}, ""},
+		{&SchemaRegistryConfig{
+			Provider:        util.AddressOf(SchemaRegistryProviderApicurio),
+			ApicurioGroupID: util.AddressOf("ticdc"),
+			BearerToken:     util.AddressOf("token"),
+		}, ""},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidatePulsarConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		config *PulsarConfig
+		err    string
+	}{
+		{&PulsarConfig{OAuth2: &PulsarOAuth2{OAuth2IssuerURL: "https://issuer"}}, "oauth2-audience"},
+		{&PulsarConfig{BasicUserName: util.AddressOf("root")}, "should be set together"},
+		{&PulsarConfig{ConnectionTimeout: util.AddressOf("5")}, "invalid connection-timeout"},
+		{&PulsarConfig{OperationTimeout: util.AddressOf("-1s")}, "invalid operation-timeout"},
+		{&PulsarConfig{AutoCreateTopic: util.AddressOf(true)}, "admin-url"},
+		{&PulsarConfig{TopicPartitionNum: util.AddressOf(int32(-1))}, "invalid topic-partition-num"},
+		{&PulsarConfig{TopicRetentionSizeMB: util.AddressOf(int64(-2))}, "invalid topic-retention-size-mb"},
+		{&PulsarConfig{TopicRetentionTime: util.AddressOf("1d")}, "invalid topic-retention-time"},
+		{
+			&PulsarConfig{
+				AdminURL:             util.AddressOf("http://127.0.0.1:8080"),
+				AutoCreateTopic:      util.AddressOf(true),
+				TopicPartitionNum:    util.AddressOf(int32(3)),
+				TopicRetentionTime:   util.AddressOf("24h"),
+				TopicRetentionSizeMB: util.AddressOf(int64(-1)),
+			},
+			"",
+		},
+		{
+			&PulsarConfig{
+				OAuth2: &PulsarOAuth2{
+					OAuth2IssuerURL:  "https://issuer",
+					OAuth2Audience:   "audience",
+					OAuth2PrivateKey: "/tmp/key.json",
+				},
+				ConnectionTimeout: util.AddressOf("5s"),
+			},
+			"",
+		},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateDDLTranslationConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		config *DDLTranslationConfig
+		err    string
+	}{
+		{&DDLTranslationConfig{Dialect: util.AddressOf("oracle")}, "dialect of the ddl-translation"},
+		{&DDLTranslationConfig{Rules: []*DDLTranslationRule{{Replacement: "a"}}}, "can't be empty"},
+		{&DDLTranslationConfig{Rules: []*DDLTranslationRule{{Pattern: "(a"}}}, "missing closing )"},
+		{&DDLTranslationConfig{Dialect: util.AddressOf(DDLDialectMySQL)}, ""},
+		{
+			&DDLTranslationConfig{
+				Dialect: util.AddressOf(DDLDialectMariaDB),
+				Rules: []*DDLTranslationRule{
+					{Pattern: `(?i)\s+COMPRESSION='lz4'`},
+					{Pattern: `(?i)^ALTER TABLE \S+ CACHE`, Skip: true},
+				},
+			},
+			"",
+		},
+	}
+	for _, c := range cases {
+		err := c.config.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
+}
+
+func TestValidateTableRoute(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		route *TableRoute
+		err   string
+	}{
+		{&TableRoute{TargetTable: "t"}, "matcher of the table route is empty"},
+		{&TableRoute{Matcher: []string{"shard_*.t_*"}}, "renames nothing"},
+		{
+			&TableRoute{
+				Matcher: []string{"shard_*.t_*"},
+				Columns: []*ColumnRoute{{Source: "a"}},
+			},
+			"can't be empty",
+		},
+		{
+			&TableRoute{
+				Matcher: []string{"shard_*.t_*"},
+				Columns: []*ColumnRoute{{Source: "a", Target: "b"}, {Source: "A", Target: "c"}},
+			},
+			"renamed more than once",
+		},
+		{
+			&TableRoute{
+				Matcher:       []string{"shard_*.t_*"},
+				Columns:       []*ColumnRoute{{Source: "a", Target: "_SOURCE_TABLE"}},
+				SourceColumns: true,
+			},
+			"conflicts with the source columns",
+		},
+		{&TableRoute{Matcher: []string{"shard_*.t_*"}, TargetSchema: "shard", TargetTable: "t"}, ""},
+		{&TableRoute{Matcher: []string{"shard_*.t_*"}, SourceColumns: true}, ""},
+		{&TableRoute{Matcher: []string{"test.*"}, TargetTable: "{table}_bak"}, ""},
+	}
+	for _, c := range cases {
+		err := c.route.validate()
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, c.err)
+		}
+	}
 }
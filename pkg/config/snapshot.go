@@ -0,0 +1,75 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// DefaultSnapshotChunkSize is the default number of rows scanned in a chunk
+// of the initial snapshot.
+const DefaultSnapshotChunkSize = 1024
+
+// SnapshotConfig represents the initial snapshot config for a changefeed.
+type SnapshotConfig struct {
+	// Enable scans the existing rows of the tables at the start-ts of the
+	// changefeed, and sends them to the sink as INSERT events tagged as
+	// snapshot before the incremental changes.
+	Enable *bool `toml:"enable" json:"enable,omitempty"`
+	// ChunkSize is the number of rows scanned in a chunk, the rows of a
+	// table are scanned chunk by chunk in the order of their handles.
+	ChunkSize *int `toml:"chunk-size" json:"chunk-size,omitempty"`
+	// RateLimit is the max number of rows scanned per second of a table,
+	// zero means unlimited.
+	RateLimit *int `toml:"rate-limit" json:"rate-limit,omitempty"`
+}
+
+// Enabled returns whether the initial snapshot is enabled.
+func (c *SnapshotConfig) Enabled() bool {
+	return c != nil && util.GetOrZero(c.Enable)
+}
+
+// GetChunkSize returns the number of rows scanned in a chunk.
+func (c *SnapshotConfig) GetChunkSize() int {
+	if c == nil || c.ChunkSize == nil {
+		return DefaultSnapshotChunkSize
+	}
+	return *c.ChunkSize
+}
+
+// GetRateLimit returns the max number of rows scanned per second.
+func (c *SnapshotConfig) GetRateLimit() int {
+	if c == nil {
+		return 0
+	}
+	return util.GetOrZero(c.RateLimit)
+}
+
+func (c *SnapshotConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.ChunkSize != nil && *c.ChunkSize <= 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The snapshot.chunk-size:%d must be greater than 0", *c.ChunkSize))
+	}
+	if c.RateLimit != nil && *c.RateLimit < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The snapshot.rate-limit:%d must be equal or greater than 0", *c.RateLimit))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/pingcap/tiflow/pkg/errors"
+
+// TracingConfig represents config for the OpenTelemetry tracing of the sink
+// pipeline, the spans are exported to an OTLP collector by gRPC.
+type TracingConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// the address of the OTLP gRPC collector, such as 127.0.0.1:4317
+	Endpoint string `toml:"endpoint" json:"endpoint"`
+	// the ratio of the traces to be sampled, which is in [0, 1]
+	SampleRatio float64 `toml:"sample-ratio" json:"sample-ratio"`
+}
+
+// ValidateAndAdjust validates and adjusts the tracing configuration
+func (c *TracingConfig) ValidateAndAdjust() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"tracing endpoint should not be empty if tracing is enabled")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.ErrInvalidServerOption.GenWithStackByArgs(
+			"tracing sample-ratio should be in [0, 1]")
+	}
+	return nil
+}
@@ -30,6 +30,18 @@ var (
 		"changefeed already exists, %s",
 		errors.RFCCodeText("CDC:ErrChangeFeedAlreadyExists"),
 	)
+	ErrSavepointNotExists = errors.Normalize(
+		"savepoint not exists, %s",
+		errors.RFCCodeText("CDC:ErrSavepointNotExists"),
+	)
+	ErrSavepointAlreadyExists = errors.Normalize(
+		"savepoint already exists, %s",
+		errors.RFCCodeText("CDC:ErrSavepointAlreadyExists"),
+	)
+	ErrSavepointExpired = errors.Normalize(
+		"savepoint %s is expired at %s",
+		errors.RFCCodeText("CDC:ErrSavepointExpired"),
+	)
 	ErrChangeFeedDeletionUnfinished = errors.Normalize(
 		"changefeed exists after deletion, %s",
 		errors.RFCCodeText("CDC:ErrChangeFeedDeletionUnfinished"),
@@ -129,6 +141,22 @@ var (
 		"filter rule is invalid %v",
 		errors.RFCCodeText("CDC:ErrFilterRuleInvalid"),
 	)
+	ErrColumnMaskFailed = errors.Normalize(
+		"column mask failed",
+		errors.RFCCodeText("CDC:ErrColumnMaskFailed"),
+	)
+	ErrColumnSelectorFailed = errors.Normalize(
+		"column selector failed",
+		errors.RFCCodeText("CDC:ErrColumnSelectorFailed"),
+	)
+	ErrComputedColumnFailed = errors.Normalize(
+		"computed column failed",
+		errors.RFCCodeText("CDC:ErrComputedColumnFailed"),
+	)
+	ErrTransformerFailed = errors.Normalize(
+		"transformer failed",
+		errors.RFCCodeText("CDC:ErrTransformerFailed"),
+	)
 
 	// internal errors
 	ErrAdminStopProcessor = errors.Normalize(
@@ -166,6 +194,10 @@ var (
 		"kafka async send message failed",
 		errors.RFCCodeText("CDC:ErrKafkaAsyncSendMessage"),
 	)
+	ErrKafkaTransaction = errors.Normalize(
+		"kafka transaction failed",
+		errors.RFCCodeText("CDC:ErrKafkaTransaction"),
+	)
 	ErrKafkaInvalidPartitionNum = errors.Normalize(
 		"invalid partition num %d",
 		errors.RFCCodeText("CDC:ErrKafkaInvalidPartitionNum"),
@@ -203,6 +235,10 @@ var (
 		"kafka config item not found",
 		errors.RFCCodeText("CDC:ErrKafkaConfigNotFound"),
 	)
+	ErrKafkaBrokerDiscovery = errors.Normalize(
+		"discover kafka brokers failed",
+		errors.RFCCodeText("CDC:ErrKafkaBrokerDiscovery"),
+	)
 	// for pulsar
 	ErrPulsarSendMessage = errors.Normalize(
 		"pulsar send message failed",
@@ -292,6 +328,10 @@ var (
 		"external storage api",
 		errors.RFCCodeText("CDC:ErrS3StorageAPI"),
 	)
+	ErrClaimCheckStorageUnwritable = errors.Normalize(
+		"claim-check storage %s isn't writable",
+		errors.RFCCodeText("CDC:ErrClaimCheckStorageUnwritable"),
+	)
 	ErrStorageInitialize = errors.Normalize(
 		"fail to open storage for redo log",
 		errors.RFCCodeText("CDC:ErrStorageInitialize"),
@@ -353,6 +393,10 @@ var (
 		"schema manager API error",
 		errors.RFCCodeText("CDC:ErrAvroSchemaAPIError"),
 	)
+	ErrAvroIncompatibleSchema = errors.Normalize(
+		"schema of subject %s is incompatible with the schema in the registry",
+		errors.RFCCodeText("CDC:ErrAvroIncompatibleSchema"),
+	)
 	ErrAvroInvalidMessage = errors.Normalize(
 		"avro invalid message format",
 		errors.RFCCodeText("CDC:ErrAvroInvalidMessage"),
@@ -389,6 +433,14 @@ var (
 		"sink config invalid",
 		errors.RFCCodeText("CDC:ErrSinkInvalidConfig"),
 	)
+	ErrSinkPreFlightFailed = errors.Normalize(
+		"sink pre-flight check failed, %s",
+		errors.RFCCodeText("CDC:ErrSinkPreFlightFailed"),
+	)
+	ErrSinkMirrorFailed = errors.Normalize(
+		"the mirror of the sink fails, the events aren't written to it anymore",
+		errors.RFCCodeText("CDC:ErrSinkMirrorFailed"),
+	)
 	ErrCraftCodecInvalidData = errors.Normalize(
 		"craft codec invalid data",
 		errors.RFCCodeText("CDC:ErrCraftCodecInvalidData"),
@@ -409,6 +461,14 @@ var (
 		"csv decode failed",
 		errors.RFCCodeText("CDC:ErrCSVDecodeFailed"),
 	)
+	ErrDebeziumEncodeFailed = errors.Normalize(
+		"debezium encode failed",
+		errors.RFCCodeText("CDC:ErrDebeziumEncodeFailed"),
+	)
+	ErrParquetEncodeFailed = errors.Normalize(
+		"parquet encode failed",
+		errors.RFCCodeText("CDC:ErrParquetEncodeFailed"),
+	)
 	ErrStorageSinkInvalidConfig = errors.Normalize(
 		"storage sink config invalid",
 		errors.RFCCodeText("CDC:ErrStorageSinkInvalidConfig"),
@@ -423,6 +483,10 @@ var (
 		"generate tls config failed",
 		errors.RFCCodeText("CDC:ErrToTLSConfigFailed"),
 	)
+	ErrResolveSecret = errors.Normalize(
+		"resolve secret %s failed",
+		errors.RFCCodeText("CDC:ErrResolveSecret"),
+	)
 	ErrCheckClusterVersionFromPD = errors.Normalize(
 		"failed to request PD %s, please try again later",
 		errors.RFCCodeText("CDC:ErrCheckClusterVersionFromPD"),
@@ -589,6 +653,10 @@ var (
 		"table not found in processor cache",
 		errors.RFCCodeText("CDC:ErrProcessorTableNotFound"),
 	)
+	ErrTableLagSLOExceeded = errors.Normalize(
+		"the checkpoint lag of tables exceeds the lag-slo %s, tables: %s",
+		errors.RFCCodeText("CDC:ErrTableLagSLOExceeded"),
+	)
 	ErrInvalidServerOption = errors.Normalize(
 		"invalid server option",
 		errors.RFCCodeText("CDC:ErrInvalidServerOption"),
@@ -107,10 +107,20 @@ func RFCCode(err error) (errors.RFCErrorCode, bool) {
 	type rfcCoder interface {
 		RFCCode() errors.RFCErrorCode
 	}
+	type unwrapper interface {
+		Unwrap() error
+	}
 	if terr, ok := err.(rfcCoder); ok {
 		return terr.RFCCode(), true
 	}
 	cause := errors.Unwrap(err)
+	if cause == nil {
+		// Some errors only support the standard unwrapping, such as the
+		// sink internal errors.
+		if uerr, ok := err.(unwrapper); ok {
+			cause = uerr.Unwrap()
+		}
+	}
 	if cause == nil {
 		return "", false
 	}
@@ -163,6 +173,42 @@ func IsContextDeadlineExceededError(err error) bool {
 	return errors.Cause(err) == context.DeadlineExceeded
 }
 
+// deadLetterErrors are the errors which indicate that a row changed event
+// can not be encoded, such events can be diverted to the dead-letter queue.
+var deadLetterErrors = []*errors.Error{
+	ErrMessageTooLarge, ErrAvroEncodeFailed, ErrAvroIncompatibleSchema,
+}
+
+// IsDeadLetterError returns true if the event which causes the error can be
+// diverted to the dead-letter queue.
+func IsDeadLetterError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, e := range deadLetterErrors {
+		if e.Equal(err) {
+			return true
+		}
+		if code, ok := RFCCode(err); ok && code == e.RFCCode() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSinkMirrorError returns true if the error comes from the mirror of the
+// sink, the changefeed keeps running without the mirror.
+func IsSinkMirrorError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if ErrSinkMirrorFailed.Equal(err) {
+		return true
+	}
+	code, ok := RFCCode(err)
+	return ok && code == ErrSinkMirrorFailed.RFCCode()
+}
+
 // httpStatusCodeMapping is a mapping from RFC error code to HTTP status code.
 // It does not contain all RFC error codes, only the ones what we think
 // are not just internal errors.
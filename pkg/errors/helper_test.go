@@ -75,6 +75,11 @@ func TestRFCCode(t *testing.T) {
 	rfc, ok = RFCCode(anoErr)
 	require.Equal(t, true, ok)
 	require.Contains(t, rfc, "ErrEtcdTryAgain")
+
+	wrappedErr := fmt.Errorf("wrapped: %w", ErrKafkaInvalidConfig.GenWithStackByArgs())
+	rfc, ok = RFCCode(wrappedErr)
+	require.Equal(t, true, ok)
+	require.Contains(t, rfc, "ErrKafkaInvalidConfig")
 }
 
 func TestIsRetryableError(t *testing.T) {
@@ -175,6 +180,25 @@ func TestIsChangefeedUnRetryableError(t *testing.T) {
 	}
 }
 
+func TestIsDeadLetterError(t *testing.T) {
+	t.Parallel()
+	require.False(t, IsDeadLetterError(nil))
+	require.True(t, IsDeadLetterError(ErrMessageTooLarge.GenWithStackByArgs()))
+	require.True(t, IsDeadLetterError(errors.Trace(ErrAvroIncompatibleSchema.GenWithStackByArgs("t"))))
+	require.True(t, IsDeadLetterError(WrapError(ErrAvroEncodeFailed, errors.New("test"))))
+	require.False(t, IsDeadLetterError(ErrAvroSchemaAPIError.GenWithStackByArgs()))
+	require.False(t, IsDeadLetterError(errors.New("test")))
+}
+
+func TestIsSinkMirrorError(t *testing.T) {
+	t.Parallel()
+	require.False(t, IsSinkMirrorError(nil))
+	require.True(t, IsSinkMirrorError(ErrSinkMirrorFailed.GenWithStackByArgs()))
+	require.True(t, IsSinkMirrorError(errors.Trace(WrapError(ErrSinkMirrorFailed, errors.New("test")))))
+	require.False(t, IsSinkMirrorError(ErrSinkInvalidConfig.GenWithStackByArgs()))
+	require.False(t, IsSinkMirrorError(errors.New("test")))
+}
+
 func TestIsCliUnprintableError(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
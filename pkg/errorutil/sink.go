@@ -0,0 +1,100 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorutil
+
+import (
+	"errors"
+
+	"github.com/Shopify/sarama"
+	gmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/parser/mysql"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+// SinkErrorClass is the class of an error from the sink, which decides how
+// the error is handled.
+type SinkErrorClass string
+
+const (
+	// SinkErrorClassTransientNetwork means the connection to the downstream is
+	// broken or timed out. Unknown errors are also in this class, so they are
+	// retried as before.
+	SinkErrorClassTransientNetwork SinkErrorClass = "transient-network"
+	// SinkErrorClassAuth means the authentication or the authorization by the
+	// downstream fails, which can be recovered after the credentials are
+	// rotated or the privileges are granted.
+	SinkErrorClassAuth SinkErrorClass = "auth"
+	// SinkErrorClassSchemaIncompat means the events don't match the schema of
+	// the downstream, which can be recovered after the schema is fixed.
+	SinkErrorClassSchemaIncompat SinkErrorClass = "schema-incompat"
+	// SinkErrorClassQuota means the downstream throttles the requests or runs
+	// out of resources.
+	SinkErrorClassQuota SinkErrorClass = "quota"
+	// SinkErrorClassFatal means the error can't be recovered by retrying, so
+	// the changefeed should be failed.
+	SinkErrorClassFatal SinkErrorClass = "fatal"
+)
+
+// Retryable returns whether the errors of the class can be retried.
+func (c SinkErrorClass) Retryable() bool {
+	return c != SinkErrorClassFatal
+}
+
+// ClassifySinkError returns the class of an error from the sink.
+func ClassifySinkError(err error) SinkErrorClass {
+	if cerror.IsChangefeedUnRetryableError(err) || cerror.IsChangefeedFastFailError(err) {
+		return SinkErrorClassFatal
+	}
+
+	var mysqlErr *gmysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysql.ErrAccessDenied, mysql.ErrDBaccessDenied,
+			mysql.ErrTableaccessDenied, mysql.ErrSpecificAccessDenied:
+			return SinkErrorClassAuth
+		case mysql.ErrConCount, mysql.ErrTooManyUserConnections,
+			mysql.ErrUserLimitReached:
+			return SinkErrorClassQuota
+		case mysql.ErrNoSuchTable, mysql.ErrBadField, mysql.ErrWrongValueCountOnRow,
+			mysql.ErrBadNull, mysql.ErrDataTooLong, mysql.ErrWarnDataOutOfRange,
+			mysql.ErrTruncatedWrongValueForField:
+			return SinkErrorClassSchemaIncompat
+		}
+	}
+
+	var saramaErr sarama.KError
+	if errors.As(err, &saramaErr) {
+		switch saramaErr {
+		case sarama.ErrSASLAuthenticationFailed, sarama.ErrTopicAuthorizationFailed,
+			sarama.ErrClusterAuthorizationFailed, sarama.ErrTransactionalIDAuthorizationFailed:
+			return SinkErrorClassAuth
+		case sarama.ErrThrottlingQuotaExceeded:
+			return SinkErrorClassQuota
+		}
+	}
+
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) {
+		switch kafkaErr {
+		case kafka.SASLAuthenticationFailed, kafka.TopicAuthorizationFailed,
+			kafka.ClusterAuthorizationFailed, kafka.TransactionalIDAuthorizationFailed:
+			return SinkErrorClassAuth
+		case kafka.ThrottlingQuotaExceeded:
+			return SinkErrorClassQuota
+		}
+	}
+
+	return SinkErrorClassTransientNetwork
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifySinkError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err   error
+		class SinkErrorClass
+	}{
+		{errors.New("raw error"), SinkErrorClassTransientNetwork},
+		{cerror.WrapError(cerror.ErrMySQLTxnError, newMysqlErr(tmysql.ErrAccessDenied, "Access denied")),
+			SinkErrorClassAuth},
+		{cerror.WrapError(cerror.ErrMySQLTxnError, newMysqlErr(tmysql.ErrConCount, "Too many connections")),
+			SinkErrorClassQuota},
+		{cerror.WrapError(cerror.ErrMySQLTxnError, newMysqlErr(tmysql.ErrBadField, "Unknown column")),
+			SinkErrorClassSchemaIncompat},
+		{cerror.WrapError(cerror.ErrMySQLTxnError, newMysqlErr(tmysql.ErrLockDeadlock, "Deadlock found")),
+			SinkErrorClassTransientNetwork},
+		{cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, sarama.ErrTopicAuthorizationFailed),
+			SinkErrorClassAuth},
+		{cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, sarama.ErrThrottlingQuotaExceeded),
+			SinkErrorClassQuota},
+		{cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, kafka.SASLAuthenticationFailed),
+			SinkErrorClassAuth},
+		{cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, kafka.ThrottlingQuotaExceeded),
+			SinkErrorClassQuota},
+		{cerror.ErrKafkaInvalidConfig.GenWithStackByArgs(), SinkErrorClassFatal},
+		{cerror.WrapChangefeedUnretryableErr(errors.New("raw error")), SinkErrorClassFatal},
+		{cerror.ErrGCTTLExceeded.GenWithStackByArgs(), SinkErrorClassFatal},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.class, ClassifySinkError(c.err), c.err.Error())
+		require.Equal(t, c.class != SinkErrorClassFatal, c.class.Retryable())
+	}
+}
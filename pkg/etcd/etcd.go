@@ -59,6 +59,11 @@ func ChangefeedStatusKeyPrefix(clusterID, namespace string) string {
 	return NamespacedPrefix(clusterID, namespace) + ChangefeedStatusKey
 }
 
+// SavepointKeyPrefix is the prefix of savepoint keys
+func SavepointKeyPrefix(clusterID, namespace string) string {
+	return NamespacedPrefix(clusterID, namespace) + savepointKey
+}
+
 // GetEtcdKeyChangeFeedList returns the prefix key of all changefeed config
 func GetEtcdKeyChangeFeedList(clusterID, namespace string) string {
 	return fmt.Sprintf("%s/changefeed/info", NamespacedPrefix(clusterID, namespace))
@@ -143,12 +148,25 @@ type CDCEtcdClient interface {
 		model.ChangeFeedID,
 	) error
 
+	UpdateChangeFeedInfo(ctx context.Context,
+		changeFeedID model.ChangeFeedID,
+		update ChangeFeedInfoUpdater,
+	) error
+
 	UpdateChangefeedAndUpstream(ctx context.Context,
 		upstreamInfo *model.UpstreamInfo,
-		changeFeedInfo *model.ChangeFeedInfo,
 		changeFeedID model.ChangeFeedID,
+		update ChangeFeedInfoUpdater,
 	) error
 
+	CreateSavepoint(ctx context.Context, savepoint *model.Savepoint) error
+
+	GetSavepoint(ctx context.Context, namespace, name string) (*model.Savepoint, error)
+
+	GetSavepoints(ctx context.Context, namespace string) ([]*model.Savepoint, error)
+
+	DeleteSavepoint(ctx context.Context, namespace, name string) error
+
 	PutCaptureInfo(context.Context, *model.CaptureInfo, clientv3.LeaseID) error
 
 	DeleteCaptureInfo(context.Context, model.CaptureID) error
@@ -476,44 +494,97 @@ func (c *CDCEtcdClientImpl) CreateChangefeedInfo(ctx context.Context,
 	return errors.Trace(err)
 }
 
-// UpdateChangefeedAndUpstream updates the changefeed's info and its upstream info into etcd
+// ChangeFeedInfoUpdater updates the latest changefeed info read from etcd, it
+// returns false if the info isn't changed.
+type ChangeFeedInfoUpdater func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error)
+
+// maxUpdateChangeFeedInfoTries is the max tries to update a changefeed info
+// which is changed concurrently.
+const maxUpdateChangeFeedInfoTries = 10
+
+// UpdateChangeFeedInfo updates the changefeed info by the update function.
+// The info is written back by a transaction comparing its mod revision, and
+// the update function is called again with the latest info if it's changed
+// concurrently, so the changes made by the owner, e.g. the state and the
+// admin job, are never overwritten.
+func (c *CDCEtcdClientImpl) UpdateChangeFeedInfo(ctx context.Context,
+	changeFeedID model.ChangeFeedID,
+	update ChangeFeedInfoUpdater,
+) error {
+	return c.updateChangeFeedInfo(ctx, changeFeedID, update, nil)
+}
+
+// UpdateChangefeedAndUpstream updates the changefeed's info by the update
+// function and its upstream info into etcd in a transaction, the changefeed
+// info is updated in the same way as UpdateChangeFeedInfo.
 func (c *CDCEtcdClientImpl) UpdateChangefeedAndUpstream(ctx context.Context,
 	upstreamInfo *model.UpstreamInfo,
-	changeFeedInfo *model.ChangeFeedInfo,
 	changeFeedID model.ChangeFeedID,
+	update ChangeFeedInfoUpdater,
 ) error {
-	infoKey := GetEtcdKeyChangeFeedInfo(c.ClusterID, changeFeedID)
-	changeFeedInfoStr, err := changeFeedInfo.Marshal()
-	if err != nil {
-		return errors.Trace(err)
-	}
 	upstreamKey := CDCKey{
 		Tp:         CDCKeyTypeUpStream,
 		ClusterID:  c.ClusterID,
 		UpstreamID: upstreamInfo.ID,
 		Namespace:  changeFeedID.Namespace,
 	}
-	upstreamKeyStr := upstreamKey.String()
 	upstreamInfoStr, err := upstreamInfo.Marshal()
 	if err != nil {
 		return errors.Trace(err)
 	}
-	opsThen := []clientv3.Op{
-		clientv3.OpPut(infoKey, changeFeedInfoStr),
-		clientv3.OpPut(upstreamKeyStr, string(upstreamInfoStr)),
-	}
+	return c.updateChangeFeedInfo(ctx, changeFeedID, update, []clientv3.Op{
+		clientv3.OpPut(upstreamKey.String(), string(upstreamInfoStr)),
+	})
+}
 
-	resp, err := c.Client.Txn(ctx, txnEmptyCmps, opsThen, TxnEmptyOpsElse)
-	if err != nil {
-		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
-	}
-	if !resp.Succeeded {
-		log.Warn("unexpected etcd transaction failure",
+func (c *CDCEtcdClientImpl) updateChangeFeedInfo(ctx context.Context,
+	changeFeedID model.ChangeFeedID,
+	update ChangeFeedInfoUpdater,
+	extraOps []clientv3.Op,
+) error {
+	key := GetEtcdKeyChangeFeedInfo(c.ClusterID, changeFeedID)
+	for i := 0; i < maxUpdateChangeFeedInfoTries; i++ {
+		resp, err := c.Client.Get(ctx, key)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if resp.Count == 0 {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(key)
+		}
+		info := &model.ChangeFeedInfo{}
+		if err := info.Unmarshal(resp.Kvs[0].Value); err != nil {
+			return errors.Trace(err)
+		}
+		info, changed, err := update(info)
+		if err != nil {
+			return err
+		}
+		if !changed && len(extraOps) == 0 {
+			return nil
+		}
+		value, err := info.Marshal()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		cmps := []clientv3.Cmp{
+			clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision),
+		}
+		opsThen := append([]clientv3.Op{clientv3.OpPut(key, value)}, extraOps...)
+		txnResp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if txnResp.Succeeded {
+			return nil
+		}
+		log.Info("changefeed info is changed concurrently, update it again",
 			zap.String("namespace", changeFeedID.Namespace),
 			zap.String("changefeed", changeFeedID.ID))
-		return cerror.ErrChangefeedUpdateFailedTransaction.GenWithStackByArgs(changeFeedID)
 	}
-	return nil
+	log.Warn("failed to update the changefeed info changed concurrently",
+		zap.String("namespace", changeFeedID.Namespace),
+		zap.String("changefeed", changeFeedID.ID))
+	return cerror.ErrChangefeedUpdateFailedTransaction.GenWithStackByArgs(changeFeedID)
 }
 
 // SaveChangeFeedInfo stores change feed info into etcd
@@ -634,6 +705,98 @@ func (c *CDCEtcdClientImpl) GetUpstreamInfo(ctx context.Context,
 	return info, errors.Trace(err)
 }
 
+// CreateSavepoint saves a savepoint into etcd, it fails if a savepoint with
+// the same name exists.
+func (c *CDCEtcdClientImpl) CreateSavepoint(
+	ctx context.Context, savepoint *model.Savepoint,
+) error {
+	key := CDCKey{
+		Tp:            CDCKeyTypeSavepoint,
+		ClusterID:     c.ClusterID,
+		Namespace:     savepoint.Namespace,
+		SavepointName: savepoint.Name,
+	}
+	keyStr := key.String()
+	value, err := savepoint.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(keyStr), "=", 0),
+	}
+	opsThen := []clientv3.Op{clientv3.OpPut(keyStr, value)}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrSavepointAlreadyExists.GenWithStackByArgs(savepoint.Name)
+	}
+	return nil
+}
+
+// GetSavepoint queries a savepoint by its name.
+func (c *CDCEtcdClientImpl) GetSavepoint(
+	ctx context.Context, namespace, name string,
+) (*model.Savepoint, error) {
+	key := CDCKey{
+		Tp:            CDCKeyTypeSavepoint,
+		ClusterID:     c.ClusterID,
+		Namespace:     namespace,
+		SavepointName: name,
+	}
+	resp, err := c.Client.Get(ctx, key.String())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Count == 0 {
+		return nil, cerror.ErrSavepointNotExists.GenWithStackByArgs(name)
+	}
+	savepoint := &model.Savepoint{}
+	err = savepoint.Unmarshal(resp.Kvs[0].Value)
+	return savepoint, errors.Trace(err)
+}
+
+// GetSavepoints queries all savepoints of a namespace.
+func (c *CDCEtcdClientImpl) GetSavepoints(
+	ctx context.Context, namespace string,
+) ([]*model.Savepoint, error) {
+	resp, err := c.Client.Get(ctx,
+		SavepointKeyPrefix(c.ClusterID, namespace)+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	savepoints := make([]*model.Savepoint, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		savepoint := &model.Savepoint{}
+		if err := savepoint.Unmarshal(kv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		savepoints = append(savepoints, savepoint)
+	}
+	return savepoints, nil
+}
+
+// DeleteSavepoint deletes a savepoint from etcd.
+func (c *CDCEtcdClientImpl) DeleteSavepoint(
+	ctx context.Context, namespace, name string,
+) error {
+	key := CDCKey{
+		Tp:            CDCKeyTypeSavepoint,
+		ClusterID:     c.ClusterID,
+		Namespace:     namespace,
+		SavepointName: name,
+	}
+	resp, err := c.Client.Delete(ctx, key.String())
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Deleted == 0 {
+		return cerror.ErrSavepointNotExists.GenWithStackByArgs(name)
+	}
+	return nil
+}
+
 // GcServiceIDForTest returns the gc service ID for tests
 func GcServiceIDForTest() string {
 	return fmt.Sprintf("ticdc-%s-%d", "default", 0)
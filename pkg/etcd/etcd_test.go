@@ -222,6 +222,49 @@ func TestCreateChangefeed(t *testing.T) {
 	require.True(t, cerror.ErrChangeFeedAlreadyExists.Equal(err))
 }
 
+func TestSavepoint(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	savepoint := &model.Savepoint{
+		Namespace:    model.DefaultNamespace,
+		Name:         "before-migration",
+		ChangefeedID: "test-id",
+		UpstreamID:   1,
+		CheckpointTs: 100,
+		SinkURI:      "kafka://127.0.0.1:9092/test",
+	}
+	err := s.client.CreateSavepoint(ctx, savepoint)
+	require.NoError(t, err)
+	err = s.client.CreateSavepoint(ctx, savepoint)
+	require.True(t, cerror.ErrSavepointAlreadyExists.Equal(err))
+
+	err = s.client.CreateSavepoint(ctx, &model.Savepoint{
+		Namespace: "other", Name: "before-migration",
+	})
+	require.NoError(t, err)
+
+	got, err := s.client.GetSavepoint(ctx, model.DefaultNamespace, "before-migration")
+	require.NoError(t, err)
+	require.Equal(t, savepoint.ChangefeedID, got.ChangefeedID)
+	require.Equal(t, savepoint.CheckpointTs, got.CheckpointTs)
+	require.Equal(t, savepoint.SinkURI, got.SinkURI)
+
+	savepoints, err := s.client.GetSavepoints(ctx, model.DefaultNamespace)
+	require.NoError(t, err)
+	require.Len(t, savepoints, 1)
+	require.Equal(t, "before-migration", savepoints[0].Name)
+
+	err = s.client.DeleteSavepoint(ctx, model.DefaultNamespace, "before-migration")
+	require.NoError(t, err)
+	err = s.client.DeleteSavepoint(ctx, model.DefaultNamespace, "before-migration")
+	require.True(t, cerror.ErrSavepointNotExists.Equal(err))
+	_, err = s.client.GetSavepoint(ctx, model.DefaultNamespace, "before-migration")
+	require.True(t, cerror.ErrSavepointNotExists.Equal(err))
+}
+
 func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	s := &Tester{}
 	s.SetUpTest(t)
@@ -239,7 +282,17 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 		SinkURI:   "blackhole://",
 	}
 
-	err := s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	err := s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			return changeFeedInfo, true, nil
+		})
+	require.True(t, cerror.ErrChangeFeedNotExists.Equal(err))
+	err = s.client.SaveChangeFeedInfo(ctx, &model.ChangeFeedInfo{}, changeFeedID)
+	require.NoError(t, err)
+	err = s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			return changeFeedInfo, true, nil
+		})
 	require.NoError(t, err)
 
 	var upstreamResult *model.UpstreamInfo
@@ -254,6 +307,58 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	require.Equal(t, changeFeedInfo.SinkURI, changefeedResult.SinkURI)
 }
 
+func TestUpdateChangeFeedInfo(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	changeFeedID := model.DefaultChangeFeedID("test-update-cf")
+	err := s.client.SaveChangeFeedInfo(ctx, &model.ChangeFeedInfo{
+		SinkURI: "blackhole://",
+		State:   model.StateNormal,
+	}, changeFeedID)
+	require.NoError(t, err)
+
+	// the info changed concurrently isn't overwritten, the update is applied
+	// to the latest info again.
+	tries := 0
+	err = s.client.UpdateChangeFeedInfo(ctx, changeFeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			tries++
+			if tries == 1 {
+				err := s.client.SaveChangeFeedInfo(ctx, &model.ChangeFeedInfo{
+					SinkURI:      info.SinkURI,
+					State:        model.StateStopped,
+					AdminJobType: model.AdminStop,
+				}, changeFeedID)
+				require.NoError(t, err)
+			}
+			info.SinkURI = "mysql://127.0.0.1:3306/"
+			return info, true, nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, 2, tries)
+	info, err := s.client.GetChangeFeedInfo(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Equal(t, "mysql://127.0.0.1:3306/", info.SinkURI)
+	require.Equal(t, model.StateStopped, info.State)
+	require.Equal(t, model.AdminStop, info.AdminJobType)
+
+	// the error of the update is returned as is.
+	err = s.client.UpdateChangeFeedInfo(ctx, changeFeedID,
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			return nil, false, cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs("test")
+		})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(err))
+
+	err = s.client.UpdateChangeFeedInfo(ctx, model.DefaultChangeFeedID("not-exist"),
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			return info, true, nil
+		})
+	require.True(t, cerror.ErrChangeFeedNotExists.Equal(err))
+}
+
 func TestGetAllCaptureLeases(t *testing.T) {
 	s := &Tester{}
 	s.SetUpTest(t)
@@ -37,6 +37,7 @@ const (
 	// metaVersionKey is the key path for metadata version
 	metaVersionKey = "/meta/meta-version"
 	upstreamKey    = "/upstream"
+	savepointKey   = "/savepoint"
 
 	// DeletionCounterKey is the key path for the counter of deleted keys
 	DeletionCounterKey = metaPrefix + "/meta/ticdc-delete-etcd-key-count"
@@ -63,6 +64,7 @@ const (
 	CDCKeyTypeTaskPosition
 	CDCKeyTypeMetaVersion
 	CDCKeyTypeUpStream
+	CDCKeyTypeSavepoint
 )
 
 // CDCKey represents an etcd key which is defined by TiCDC
@@ -97,6 +99,9 @@ type CDCKey struct {
 	ClusterID    string
 	UpstreamID   model.UpstreamID
 	Namespace    string
+	// SavepointName is the name of a savepoint, it's only used by the
+	// savepoint keys.
+	SavepointName string
 }
 
 // BaseKey is the common prefix of the keys with cluster id in CDC
@@ -159,6 +164,10 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 				return err
 			}
 			k.UpstreamID = id
+		case strings.HasPrefix(key, savepointKey+"/"):
+			k.Tp = CDCKeyTypeSavepoint
+			k.CaptureID = ""
+			k.SavepointName = key[len(savepointKey)+1:]
 		case strings.HasPrefix(key, ChangefeedStatusKey):
 			k.Tp = CDCKeyTypeChangeFeedStatus
 			k.CaptureID = ""
@@ -210,6 +219,9 @@ func (k *CDCKey) String() string {
 		return fmt.Sprintf("%s%s/%d",
 			NamespacedPrefix(k.ClusterID, k.Namespace),
 			upstreamKey, k.UpstreamID)
+	case CDCKeyTypeSavepoint:
+		return NamespacedPrefix(k.ClusterID, k.Namespace) + savepointKey +
+			"/" + k.SavepointName
 	}
 	log.Panic("unreachable")
 	return ""
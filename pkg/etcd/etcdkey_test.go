@@ -105,6 +105,14 @@ func TestEtcdKey(t *testing.T) {
 			Namespace:  model.DefaultNamespace,
 			UpstreamID: 12345,
 		},
+	}, {
+		key: DefaultClusterAndNamespacePrefix + "/savepoint/before-migration",
+		expected: &CDCKey{
+			Tp:            CDCKeyTypeSavepoint,
+			ClusterID:     DefaultCDCClusterID,
+			Namespace:     model.DefaultNamespace,
+			SavepointName: "before-migration",
+		},
 	}, {
 		key: fmt.Sprintf("%s%s", DefaultClusterAndMetaPrefix, metaVersionKey),
 		expected: &CDCKey{
@@ -160,7 +168,7 @@ func TestEtcdKeyParseError(t *testing.T) {
 		}
 	}
 	k := new(CDCKey)
-	k.Tp = CDCKeyTypeUpStream + 1
+	k.Tp = CDCKeyTypeSavepoint + 1
 	require.Panics(t, func() {
 		_ = k.String()
 	})
@@ -66,6 +66,20 @@ func (mr *MockCDCEtcdClientMockRecorder) CreateChangefeedInfo(arg0, arg1, arg2,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChangefeedInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).CreateChangefeedInfo), arg0, arg1, arg2, arg3)
 }
 
+// CreateSavepoint mocks base method.
+func (m *MockCDCEtcdClient) CreateSavepoint(ctx context.Context, savepoint *model.Savepoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavepoint", ctx, savepoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSavepoint indicates an expected call of CreateSavepoint.
+func (mr *MockCDCEtcdClientMockRecorder) CreateSavepoint(ctx, savepoint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavepoint", reflect.TypeOf((*MockCDCEtcdClient)(nil).CreateSavepoint), ctx, savepoint)
+}
+
 // DeleteCaptureInfo mocks base method.
 func (m *MockCDCEtcdClient) DeleteCaptureInfo(arg0 context.Context, arg1 model.CaptureID) error {
 	m.ctrl.T.Helper()
@@ -80,6 +94,20 @@ func (mr *MockCDCEtcdClientMockRecorder) DeleteCaptureInfo(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCaptureInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).DeleteCaptureInfo), arg0, arg1)
 }
 
+// DeleteSavepoint mocks base method.
+func (m *MockCDCEtcdClient) DeleteSavepoint(ctx context.Context, namespace string, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavepoint", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSavepoint indicates an expected call of DeleteSavepoint.
+func (mr *MockCDCEtcdClientMockRecorder) DeleteSavepoint(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavepoint", reflect.TypeOf((*MockCDCEtcdClient)(nil).DeleteSavepoint), ctx, namespace, name)
+}
+
 // GetAllCDCInfo mocks base method.
 func (m *MockCDCEtcdClient) GetAllCDCInfo(ctx context.Context) ([]*mvccpb.KeyValue, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +271,36 @@ func (mr *MockCDCEtcdClientMockRecorder) GetOwnerRevision(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnerRevision", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetOwnerRevision), arg0, arg1)
 }
 
+// GetSavepoint mocks base method.
+func (m *MockCDCEtcdClient) GetSavepoint(ctx context.Context, namespace string, name string) (*model.Savepoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavepoint", ctx, namespace, name)
+	ret0, _ := ret[0].(*model.Savepoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavepoint indicates an expected call of GetSavepoint.
+func (mr *MockCDCEtcdClientMockRecorder) GetSavepoint(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavepoint", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetSavepoint), ctx, namespace, name)
+}
+
+// GetSavepoints mocks base method.
+func (m *MockCDCEtcdClient) GetSavepoints(ctx context.Context, namespace string) ([]*model.Savepoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavepoints", ctx, namespace)
+	ret0, _ := ret[0].([]*model.Savepoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavepoints indicates an expected call of GetSavepoints.
+func (mr *MockCDCEtcdClientMockRecorder) GetSavepoints(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavepoints", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetSavepoints), ctx, namespace)
+}
+
 // GetUpstreamInfo mocks base method.
 func (m *MockCDCEtcdClient) GetUpstreamInfo(ctx context.Context, upstreamID model.UpstreamID, namespace string) (*model.UpstreamInfo, error) {
 	m.ctrl.T.Helper()
@@ -286,16 +344,30 @@ func (mr *MockCDCEtcdClientMockRecorder) SaveChangeFeedInfo(ctx, info, changeFee
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveChangeFeedInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).SaveChangeFeedInfo), ctx, info, changeFeedID)
 }
 
+// UpdateChangeFeedInfo mocks base method.
+func (m *MockCDCEtcdClient) UpdateChangeFeedInfo(ctx context.Context, changeFeedID model.ChangeFeedID, update etcd.ChangeFeedInfoUpdater) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChangeFeedInfo", ctx, changeFeedID, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChangeFeedInfo indicates an expected call of UpdateChangeFeedInfo.
+func (mr *MockCDCEtcdClientMockRecorder) UpdateChangeFeedInfo(ctx, changeFeedID, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangeFeedInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).UpdateChangeFeedInfo), ctx, changeFeedID, update)
+}
+
 // UpdateChangefeedAndUpstream mocks base method.
-func (m *MockCDCEtcdClient) UpdateChangefeedAndUpstream(ctx context.Context, upstreamInfo *model.UpstreamInfo, changeFeedInfo *model.ChangeFeedInfo, changeFeedID model.ChangeFeedID) error {
+func (m *MockCDCEtcdClient) UpdateChangefeedAndUpstream(ctx context.Context, upstreamInfo *model.UpstreamInfo, changeFeedID model.ChangeFeedID, update etcd.ChangeFeedInfoUpdater) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateChangefeedAndUpstream", ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	ret := m.ctrl.Call(m, "UpdateChangefeedAndUpstream", ctx, upstreamInfo, changeFeedID, update)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateChangefeedAndUpstream indicates an expected call of UpdateChangefeedAndUpstream.
-func (mr *MockCDCEtcdClientMockRecorder) UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID interface{}) *gomock.Call {
+func (mr *MockCDCEtcdClientMockRecorder) UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedID, update interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangefeedAndUpstream", reflect.TypeOf((*MockCDCEtcdClient)(nil).UpdateChangefeedAndUpstream), ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangefeedAndUpstream", reflect.TypeOf((*MockCDCEtcdClient)(nil).UpdateChangefeedAndUpstream), ctx, upstreamInfo, changeFeedID, update)
 }
@@ -0,0 +1,200 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	schemaPlaceholder     = "schema"
+	tablePlaceholder      = "table"
+	commitTsPlaceholder   = "commit-ts"
+	clusterIDPlaceholder  = "cluster-id"
+	changefeedPlaceholder = "changefeed"
+)
+
+// placeholderRE is used to match all placeholders in the computed column value.
+var placeholderRE = regexp.MustCompile(`\{([A-Za-z0-9_$-]+)\}`)
+
+// sizeOfEmptyColumn is the size of the column struct.
+const sizeOfEmptyColumn = int(unsafe.Sizeof(model.Column{}))
+
+// computedColumnRule appends a column to the rows of the tables matched by
+// the table matcher.
+type computedColumnRule struct {
+	tableMatcher tfilter.Filter
+	config       *config.ComputedColumn
+}
+
+// ColumnAppender appends the computed columns to the row changed events
+// according to the computed-columns in the sink config. It's safe for
+// concurrent use.
+type ColumnAppender struct {
+	rules      []*computedColumnRule
+	clusterID  string
+	changefeed string
+}
+
+// NewColumnAppender creates a ColumnAppender, nil is returned if there is no
+// computed column configured.
+func NewColumnAppender(
+	cfg *config.ReplicaConfig, clusterID string, changefeedID model.ChangeFeedID,
+) (*ColumnAppender, error) {
+	if cfg.Sink == nil || len(cfg.Sink.ComputedColumns) == 0 {
+		return nil, nil
+	}
+
+	a := &ColumnAppender{
+		clusterID:  clusterID,
+		changefeed: changefeedID.ID,
+	}
+	for _, column := range cfg.Sink.ComputedColumns {
+		tf, err := tfilter.Parse(column.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, column.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		a.rules = append(a.rules, &computedColumnRule{
+			tableMatcher: tf,
+			config:       column,
+		})
+	}
+	return a, nil
+}
+
+// AppendRowChangedEvent appends the computed columns to the columns and the
+// pre-columns of the row in place, the column infos of the row are extended
+// accordingly so that the encoders can get the field types of them.
+func (a *ColumnAppender) AppendRowChangedEvent(row *model.RowChangedEvent) error {
+	copied := false
+	for _, rule := range a.rules {
+		if !rule.tableMatcher.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		if len(row.ColInfos) != 0 {
+			// the column infos are shared by all the rows of the table, so
+			// they're copied before being extended.
+			if !copied {
+				colInfos := make([]rowcodec.ColInfo, len(row.ColInfos), len(row.ColInfos)+len(a.rules))
+				copy(colInfos, row.ColInfos)
+				row.ColInfos = colInfos
+				copied = true
+			}
+			row.ColInfos = append(row.ColInfos, rowcodec.ColInfo{
+				ID: -1,
+				Ft: ComputedColumnFieldType(),
+			})
+		}
+		if len(row.Columns) != 0 {
+			col, err := a.compute(rule.config, row, row.Columns)
+			if err != nil {
+				return err
+			}
+			row.Columns = append(row.Columns, col)
+		}
+		if len(row.PreColumns) != 0 {
+			col, err := a.compute(rule.config, row, row.PreColumns)
+			if err != nil {
+				return err
+			}
+			row.PreColumns = append(row.PreColumns, col)
+		}
+	}
+	return nil
+}
+
+// ComputedColumnFieldType returns the field type of the computed columns,
+// they're always utf8mb4 strings.
+func ComputedColumnFieldType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarchar)
+	ft.SetCharset(mysql.DefaultCharset)
+	ft.SetCollate(mysql.DefaultCollationName)
+	ft.SetFlen(types.UnspecifiedLength)
+	return ft
+}
+
+// compute computes the column by the column values of the row, the column
+// names are matched case-insensitively.
+func (a *ColumnAppender) compute(
+	cfg *config.ComputedColumn, row *model.RowChangedEvent, cols []*model.Column,
+) (*model.Column, error) {
+	for _, col := range cols {
+		if col != nil && strings.EqualFold(col.Name, cfg.Name) {
+			return nil, cerror.ErrComputedColumnFailed.GenWithStack(
+				"computed column %s conflicts with the column of table %s",
+				cfg.Name, row.Table.String())
+		}
+	}
+
+	var err error
+	isNull := false
+	value := placeholderRE.ReplaceAllStringFunc(cfg.Value, func(placeholder string) string {
+		switch name := placeholder[1 : len(placeholder)-1]; name {
+		case schemaPlaceholder:
+			return row.Table.Schema
+		case tablePlaceholder:
+			return row.Table.Table
+		case commitTsPlaceholder:
+			return strconv.FormatUint(row.CommitTs, 10)
+		case clusterIDPlaceholder:
+			return a.clusterID
+		case changefeedPlaceholder:
+			return a.changefeed
+		default:
+			for _, col := range cols {
+				if col == nil || !strings.EqualFold(col.Name, name) {
+					continue
+				}
+				if col.Value == nil {
+					isNull = true
+					return ""
+				}
+				return model.ColumnValueString(col.Value)
+			}
+			err = cerror.ErrComputedColumnFailed.GenWithStack(
+				"column %s referred by computed column %s is not found in table %s",
+				name, cfg.Name, row.Table.String())
+			return ""
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	col := &model.Column{
+		Name:             cfg.Name,
+		Type:             mysql.TypeVarchar,
+		Charset:          mysql.DefaultCharset,
+		ApproximateBytes: sizeOfEmptyColumn,
+	}
+	if !isNull {
+		col.Value = []byte(value)
+		col.ApproximateBytes += len(value)
+	}
+	return col, nil
+}
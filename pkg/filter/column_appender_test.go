@@ -0,0 +1,138 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumnAppender(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	appender, err := NewColumnAppender(cfg, "default", model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	require.Nil(t, appender)
+
+	cfg.Sink.ComputedColumns = []*config.ComputedColumn{
+		{Matcher: []string{"test.t["}, Name: "region", Value: "us-west-1"},
+	}
+	_, err = NewColumnAppender(cfg, "default", model.DefaultChangeFeedID("test"))
+	require.ErrorContains(t, err, "test.t[")
+}
+
+func TestAppendRowChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = false
+	cfg.Sink.ComputedColumns = []*config.ComputedColumn{
+		{Matcher: []string{"test.*"}, Name: "region", Value: "us-west-1"},
+		{
+			Matcher: []string{"test.t1"}, Name: "source",
+			Value: "{cluster-id}/{changefeed}/{schema}.{table}@{commit-ts}",
+		},
+		{Matcher: []string{"test.t1"}, Name: "full_name", Value: "{First_Name} {last_name}"},
+		{Matcher: []string{"other.*"}, Name: "ignored", Value: "x"},
+	}
+	appender, err := NewColumnAppender(cfg, "east", model.DefaultChangeFeedID("cf"))
+	require.NoError(t, err)
+
+	newColumns := func(lastName interface{}) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "first_name", Type: mysql.TypeVarchar, Value: []byte("Ada")},
+			{Name: "last_name", Type: mysql.TypeVarchar, Value: lastName},
+			nil,
+		}
+	}
+	colInfos := make([]rowcodec.ColInfo, 4, 8)
+	row := &model.RowChangedEvent{
+		CommitTs:   42,
+		Table:      &model.TableName{Schema: "TEST", Table: "t1"},
+		PreColumns: newColumns(nil),
+		Columns:    newColumns([]byte("Lovelace")),
+		ColInfos:   colInfos,
+	}
+	require.NoError(t, appender.AppendRowChangedEvent(row))
+	require.Len(t, row.Columns, 7)
+	require.Len(t, row.PreColumns, 7)
+	// the column infos are extended without touching the shared ones.
+	require.Len(t, row.ColInfos, 7)
+	require.Equal(t, rowcodec.ColInfo{}, colInfos[:5][4])
+	for _, colInfo := range row.ColInfos[4:] {
+		require.Equal(t, mysql.TypeVarchar, colInfo.Ft.GetType())
+		require.Equal(t, mysql.DefaultCharset, colInfo.Ft.GetCharset())
+	}
+	for _, columns := range [][]*model.Column{row.PreColumns, row.Columns} {
+		require.Equal(t, "region", columns[4].Name)
+		require.Equal(t, mysql.TypeVarchar, columns[4].Type)
+		require.Equal(t, []byte("us-west-1"), columns[4].Value)
+		require.Equal(t, "source", columns[5].Name)
+		require.Equal(t, []byte("east/cf/TEST.t1@42"), columns[5].Value)
+		require.Equal(t, "full_name", columns[6].Name)
+	}
+	// the computed column is NULL if any referred column is NULL.
+	require.Nil(t, row.PreColumns[6].Value)
+	require.Equal(t, []byte("Ada Lovelace"), row.Columns[6].Value)
+
+	// only the columns of the inserted row are appended.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t2"},
+		Columns: newColumns([]byte("Lovelace")),
+	}
+	require.NoError(t, appender.AppendRowChangedEvent(row))
+	require.Len(t, row.Columns, 5)
+	require.Empty(t, row.PreColumns)
+}
+
+func TestAppendRowChangedEventError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		column *config.ComputedColumn
+		err    string
+	}{
+		{
+			&config.ComputedColumn{Matcher: []string{"*.*"}, Name: "ID", Value: "x"},
+			"conflicts with the column",
+		},
+		{
+			&config.ComputedColumn{Matcher: []string{"*.*"}, Name: "tag", Value: "{unknown}"},
+			"column unknown referred by computed column tag is not found",
+		},
+	}
+	for _, c := range cases {
+		cfg := config.GetDefaultReplicaConfig()
+		cfg.Sink.ComputedColumns = []*config.ComputedColumn{c.column}
+		appender, err := NewColumnAppender(cfg, "default", model.DefaultChangeFeedID("test"))
+		require.NoError(t, err)
+		row := &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "t1"},
+			Columns: []*model.Column{
+				{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			},
+		}
+		err = appender.AppendRowChangedEvent(row)
+		require.True(t, cerror.ErrComputedColumnFailed.Equal(err))
+		require.ErrorContains(t, err, c.err)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// maskChar is the character used by the partial mask function.
+const maskChar = '*'
+
+// columnMaskRule masks the columns of the tables matched by the table matcher.
+type columnMaskRule struct {
+	tableMatcher tfilter.Filter
+	// columns are the lower case names of the masked columns.
+	columns map[string]struct{}
+	config  *config.ColumnMasker
+}
+
+// ColumnMasker masks the column values of the row changed events according to
+// the column-maskers in the sink config. It's safe for concurrent use.
+type ColumnMasker struct {
+	rules []*columnMaskRule
+}
+
+// NewColumnMasker creates a ColumnMasker, nil is returned if there is no
+// column masker configured.
+func NewColumnMasker(cfg *config.ReplicaConfig) (*ColumnMasker, error) {
+	if cfg.Sink == nil || len(cfg.Sink.ColumnMaskers) == 0 {
+		return nil, nil
+	}
+
+	m := &ColumnMasker{}
+	for _, masker := range cfg.Sink.ColumnMaskers {
+		tf, err := tfilter.Parse(masker.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, masker.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		rule := &columnMaskRule{
+			tableMatcher: tf,
+			columns:      make(map[string]struct{}, len(masker.Columns)),
+			config:       masker,
+		}
+		// the column names are case-insensitive.
+		for _, col := range masker.Columns {
+			rule.columns[strings.ToLower(col)] = struct{}{}
+		}
+		m.rules = append(m.rules, rule)
+	}
+	return m, nil
+}
+
+// MaskRowChangedEvent masks the columns and the pre-columns of the row in place.
+func (m *ColumnMasker) MaskRowChangedEvent(row *model.RowChangedEvent) error {
+	for _, rule := range m.rules {
+		if !rule.tableMatcher.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		if err := rule.maskColumns(row.Columns); err != nil {
+			return err
+		}
+		if err := rule.maskColumns(row.PreColumns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *columnMaskRule) maskColumns(columns []*model.Column) error {
+	for _, col := range columns {
+		if col == nil {
+			continue
+		}
+		if _, ok := r.columns[strings.ToLower(col.Name)]; !ok {
+			continue
+		}
+		if err := r.maskColumn(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *columnMaskRule) maskColumn(col *model.Column) error {
+	if r.config.Mask == config.ColumnMaskNull {
+		col.Value = nil
+		return nil
+	}
+	// Only the string columns can be masked by the other mask functions,
+	// since the masked values are strings.
+	if !types.IsTypeChar(col.Type) && !types.IsTypeBlob(col.Type) &&
+		col.Type != mysql.TypeVarString {
+		return cerror.ErrColumnMaskFailed.GenWithStack(
+			"mask function %s can't be applied to column %s of type %s",
+			r.config.Mask, col.Name, types.TypeToStr(col.Type, ""))
+	}
+
+	var value []byte
+	switch v := col.Value.(type) {
+	case nil:
+		// NULL is kept as it is.
+		return nil
+	case []byte:
+		value = v
+	case string:
+		value = []byte(v)
+	default:
+		return cerror.ErrColumnMaskFailed.GenWithStack(
+			"unexpected value type %T of column %s", col.Value, col.Name)
+	}
+
+	switch r.config.Mask {
+	case config.ColumnMaskHash:
+		sum := sha256.Sum256(value)
+		col.Value = []byte(hex.EncodeToString(sum[:]))
+	case config.ColumnMaskFixed:
+		col.Value = []byte(r.config.Value)
+	case config.ColumnMaskPartial:
+		col.Value = maskPartial(value, r.config.KeepPrefix, r.config.KeepSuffix,
+			col.Flag.IsBinary() || !utf8.Valid(value))
+	default:
+		return cerror.ErrColumnMaskFailed.GenWithStack(
+			"unknown mask function %s", r.config.Mask)
+	}
+	return nil
+}
+
+// maskPartial replaces the characters of the value with maskChar except the
+// leading and trailing ones to keep, the bytes are masked instead of the
+// characters if byteWise is true. All the characters are masked if the value
+// is not longer than the characters to keep.
+func maskPartial(value []byte, keepPrefix, keepSuffix int, byteWise bool) []byte {
+	if byteWise {
+		masked := make([]byte, len(value))
+		for i := range value {
+			if len(value) > keepPrefix+keepSuffix &&
+				(i < keepPrefix || i >= len(value)-keepSuffix) {
+				masked[i] = value[i]
+			} else {
+				masked[i] = maskChar
+			}
+		}
+		return masked
+	}
+
+	runes := []rune(string(value))
+	for i := range runes {
+		if len(runes) > keepPrefix+keepSuffix &&
+			(i < keepPrefix || i >= len(runes)-keepSuffix) {
+			continue
+		}
+		runes[i] = maskChar
+	}
+	return []byte(string(runes))
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumnMasker(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	masker, err := NewColumnMasker(cfg)
+	require.NoError(t, err)
+	require.Nil(t, masker)
+
+	cfg.Sink.ColumnMaskers = []*config.ColumnMasker{
+		{Matcher: []string{"test.t["}, Columns: []string{"a"}, Mask: config.ColumnMaskNull},
+	}
+	_, err = NewColumnMasker(cfg)
+	require.ErrorContains(t, err, "test.t[")
+}
+
+func TestMaskRowChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = false
+	cfg.Sink.ColumnMaskers = []*config.ColumnMasker{
+		{Matcher: []string{"test.*"}, Columns: []string{"Phone"}, Mask: config.ColumnMaskNull},
+		{Matcher: []string{"test.t1"}, Columns: []string{"email"}, Mask: config.ColumnMaskHash},
+		{
+			Matcher: []string{"test.t1"}, Columns: []string{"card"},
+			Mask: config.ColumnMaskFixed, Value: "xxx",
+		},
+		{
+			Matcher: []string{"test.t1"}, Columns: []string{"name", "data"},
+			Mask: config.ColumnMaskPartial, KeepPrefix: 1, KeepSuffix: 1,
+		},
+	}
+	masker, err := NewColumnMasker(cfg)
+	require.NoError(t, err)
+
+	newColumns := func() []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "phone", Type: mysql.TypeVarchar, Value: []byte("123456")},
+			{Name: "email", Type: mysql.TypeVarchar, Value: []byte("a@b.c")},
+			{Name: "card", Type: mysql.TypeString, Value: []byte("1234")},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("张三丰")},
+			{Name: "data", Type: mysql.TypeBlob, Value: []byte{0xe5, 0x01, 0x02, 0xff}, Flag: model.BinaryFlag},
+			nil,
+		}
+	}
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "TEST", Table: "t1"},
+		PreColumns: newColumns(),
+		Columns:    newColumns(),
+	}
+	require.NoError(t, masker.MaskRowChangedEvent(row))
+	for _, columns := range [][]*model.Column{row.PreColumns, row.Columns} {
+		require.Equal(t, int64(1), columns[0].Value)
+		require.Nil(t, columns[1].Value)
+		require.Equal(t,
+			[]byte("d648b243a3e817eaa3309e00e183483f2867baadf522099f0c2121770536b25a"),
+			columns[2].Value)
+		require.Equal(t, []byte("xxx"), columns[3].Value)
+		require.Equal(t, []byte("张*丰"), columns[4].Value)
+		require.Equal(t, []byte{0xe5, '*', '*', 0xff}, columns[5].Value)
+	}
+
+	// only the null mask is applied to the other tables.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t2"},
+		Columns: newColumns(),
+	}
+	require.NoError(t, masker.MaskRowChangedEvent(row))
+	require.Nil(t, row.Columns[1].Value)
+	require.Equal(t, []byte("a@b.c"), row.Columns[2].Value)
+	require.Equal(t, []byte("张三丰"), row.Columns[4].Value)
+
+	// NULL is kept as it is.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t1"},
+		Columns: []*model.Column{{Name: "email", Type: mysql.TypeVarchar}},
+	}
+	require.NoError(t, masker.MaskRowChangedEvent(row))
+	require.Nil(t, row.Columns[0].Value)
+
+	// the non-string columns can't be masked except by the null mask.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t1"},
+		Columns: []*model.Column{{Name: "email", Type: mysql.TypeLong, Value: int64(1)}},
+	}
+	require.ErrorContains(t, masker.MaskRowChangedEvent(row),
+		"mask function hash can't be applied to column email")
+}
+
+func TestMaskPartial(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		value      string
+		keepPrefix int
+		keepSuffix int
+		byteWise   bool
+		expected   string
+	}{
+		{"13812345678", 3, 4, false, "138****5678"},
+		{"13812345678", 0, 0, false, "***********"},
+		{"abc", 2, 1, false, "***"},
+		{"", 1, 1, false, ""},
+		{"你好世界", 1, 0, false, "你***"},
+		{"你好", 1, 0, true, "\xe4*****"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected,
+			string(maskPartial([]byte(c.value), c.keepPrefix, c.keepSuffix, c.byteWise)),
+			"value: %s", c.value)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// columnSelectRule selects the columns of the tables matched by the table
+// matcher.
+type columnSelectRule struct {
+	tableMatcher  tfilter.Filter
+	columnMatcher tfilter.ColumnFilter
+}
+
+// ColumnSelector drops the columns which are not selected by the
+// column-selectors in the sink config. The first rule matching the table is
+// applied, and all the columns of the tables matched by no rule are kept.
+// It's safe for concurrent use.
+type ColumnSelector struct {
+	rules []*columnSelectRule
+}
+
+// NewColumnSelector creates a ColumnSelector, nil is returned if there is no
+// column selector configured.
+func NewColumnSelector(cfg *config.ReplicaConfig) (*ColumnSelector, error) {
+	if cfg.Sink == nil || len(cfg.Sink.ColumnSelectors) == 0 {
+		return nil, nil
+	}
+
+	s := &ColumnSelector{}
+	for _, selector := range cfg.Sink.ColumnSelectors {
+		tf, err := tfilter.Parse(selector.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, selector.Matcher)
+		}
+		if !cfg.CaseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		// the column filter is always case-insensitive.
+		cf, err := tfilter.ParseColumnFilter(selector.Columns)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, selector.Columns)
+		}
+		s.rules = append(s.rules, &columnSelectRule{tableMatcher: tf, columnMatcher: cf})
+	}
+	return s, nil
+}
+
+// MatchTable returns whether the columns of the table are selected by any rule.
+func (s *ColumnSelector) MatchTable(schema, table string) bool {
+	for _, rule := range s.rules {
+		if rule.tableMatcher.MatchTable(schema, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectRowChangedEvent returns the row with the unselected columns and
+// pre-columns set to nil, so the positions of the columns are kept. The row is
+// returned as it is if no column is dropped, otherwise a shallow copy of it is
+// returned, since the row may be shared with the other sinks. An error is
+// returned if any handle key column is not selected.
+func (s *ColumnSelector) SelectRowChangedEvent(
+	row *model.RowChangedEvent,
+) (*model.RowChangedEvent, error) {
+	for _, rule := range s.rules {
+		if !rule.tableMatcher.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		columns, err := rule.selectColumns(row, row.Columns)
+		if err != nil {
+			return nil, err
+		}
+		preColumns, err := rule.selectColumns(row, row.PreColumns)
+		if err != nil {
+			return nil, err
+		}
+		if columns == nil && preColumns == nil {
+			return row, nil
+		}
+		selected := *row
+		if columns != nil {
+			selected.Columns = columns
+		}
+		if preColumns != nil {
+			selected.PreColumns = preColumns
+		}
+		return &selected, nil
+	}
+	return row, nil
+}
+
+// selectColumns returns the selected columns, nil is returned if all the
+// columns are selected.
+func (r *columnSelectRule) selectColumns(
+	row *model.RowChangedEvent, columns []*model.Column,
+) ([]*model.Column, error) {
+	var selected []*model.Column
+	for i, col := range columns {
+		if col == nil || r.columnMatcher.MatchColumn(col.Name) {
+			continue
+		}
+		if col.Flag.IsHandleKey() {
+			return nil, cerror.ErrColumnSelectorFailed.GenWithStack(
+				"handle key column %s of table %s is not selected by the column selector",
+				col.Name, row.Table)
+		}
+		if selected == nil {
+			selected = make([]*model.Column, len(columns))
+			copy(selected, columns)
+		}
+		selected[i] = nil
+	}
+	return selected, nil
+}
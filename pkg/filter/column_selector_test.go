@@ -0,0 +1,117 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumnSelector(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	selector, err := NewColumnSelector(cfg)
+	require.NoError(t, err)
+	require.Nil(t, selector)
+
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.t["}, Columns: []string{"a"}},
+	}
+	_, err = NewColumnSelector(cfg)
+	require.ErrorContains(t, err, "test.t[")
+}
+
+func TestSelectRowChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.CaseSensitive = false
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.t1"}, Columns: []string{"*", "!Phone"}},
+		{Matcher: []string{"test.*"}, Columns: []string{"id", "name"}},
+	}
+	selector, err := NewColumnSelector(cfg)
+	require.NoError(t, err)
+
+	newColumns := func() []*model.Column {
+		return []*model.Column{
+			{Name: "id", Value: int64(1), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Value: []byte("a")},
+			{Name: "phone", Value: []byte("123456")},
+			nil,
+		}
+	}
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "TEST", Table: "T1"},
+		PreColumns: newColumns(),
+		Columns:    newColumns(),
+	}
+	selected, err := selector.SelectRowChangedEvent(row)
+	require.NoError(t, err)
+	for _, columns := range [][]*model.Column{selected.PreColumns, selected.Columns} {
+		require.Len(t, columns, 4)
+		require.Equal(t, "id", columns[0].Name)
+		require.Equal(t, "name", columns[1].Name)
+		require.Nil(t, columns[2])
+		require.Nil(t, columns[3])
+	}
+	// the original row is not modified.
+	require.Equal(t, "phone", row.Columns[2].Name)
+	require.Equal(t, "phone", row.PreColumns[2].Name)
+
+	// the insert event has no pre-columns.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t2"},
+		Columns: newColumns(),
+	}
+	selected, err = selector.SelectRowChangedEvent(row)
+	require.NoError(t, err)
+	require.Nil(t, selected.PreColumns)
+	require.Nil(t, selected.Columns[2])
+
+	// the row is returned as it is if all the columns are selected.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t3"},
+		Columns: newColumns()[:2],
+	}
+	selected, err = selector.SelectRowChangedEvent(row)
+	require.NoError(t, err)
+	require.Same(t, row, selected)
+
+	// the tables matched by no rule are not changed.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "other", Table: "t1"},
+		Columns: newColumns(),
+	}
+	selected, err = selector.SelectRowChangedEvent(row)
+	require.NoError(t, err)
+	require.Same(t, row, selected)
+
+	// the handle key columns can't be dropped.
+	cfg.Sink.ColumnSelectors = []*config.ColumnSelector{
+		{Matcher: []string{"test.*"}, Columns: []string{"name"}},
+	}
+	selector, err = NewColumnSelector(cfg)
+	require.NoError(t, err)
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t1"},
+		Columns: newColumns(),
+	}
+	_, err = selector.SelectRowChangedEvent(row)
+	require.ErrorContains(t, err, "handle key column id of table test.t1")
+}
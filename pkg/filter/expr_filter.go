@@ -45,6 +45,7 @@ type dmlExprFilterRule struct {
 	updateOldExprs map[string]expression.Expression // tableName -> expr
 	updateNewExprs map[string]expression.Expression // tableName -> expr
 	deleteExprs    map[string]expression.Expression // tableName -> expr
+	keepExprs      map[string]expression.Expression // tableName -> expr
 
 	tableMatcher tfilter.Filter
 	// All tables in this rule share the same config.
@@ -68,6 +69,7 @@ func newExprFilterRule(
 		updateOldExprs: make(map[string]expression.Expression),
 		updateNewExprs: make(map[string]expression.Expression),
 		deleteExprs:    make(map[string]expression.Expression),
+		keepExprs:      make(map[string]expression.Expression),
 		config:         cfg,
 		tableMatcher:   tf,
 		sessCtx:        sessCtx,
@@ -104,6 +106,12 @@ func (r *dmlExprFilterRule) verify(tableInfos []*model.TableInfo) error {
 		return cerror.ErrExpressionParseFailed.
 			FastGenByArgs(r.config.IgnoreDeleteValueExpr)
 	}
+	_, _, err = p.ParseSQL(completeExpression(r.config.KeepValueExpr))
+	if err != nil {
+		log.Error("failed to parse expression", zap.Error(err))
+		return cerror.ErrExpressionParseFailed.
+			FastGenByArgs(r.config.KeepValueExpr)
+	}
 	// verify expression filter rule.
 	for _, ti := range tableInfos {
 		tableName := ti.TableName.String()
@@ -138,6 +146,13 @@ func (r *dmlExprFilterRule) verify(tableInfos []*model.TableInfo) error {
 			}
 			r.deleteExprs[tableName] = e
 		}
+		if r.config.KeepValueExpr != "" {
+			e, err := r.getSimpleExprOfTable(r.config.KeepValueExpr, ti)
+			if err != nil {
+				return err
+			}
+			r.keepExprs[tableName] = e
+		}
 	}
 	return nil
 }
@@ -148,6 +163,7 @@ func (r *dmlExprFilterRule) resetExpr(tableName string) {
 	delete(r.updateOldExprs, tableName)
 	delete(r.updateNewExprs, tableName)
 	delete(r.deleteExprs, tableName)
+	delete(r.keepExprs, tableName)
 }
 
 // getInsertExprs returns the expression filter to filter INSERT events.
@@ -223,6 +239,24 @@ func (r *dmlExprFilterRule) getDeleteExpr(ti *model.TableInfo) (
 	return r.deleteExprs[tableName], nil
 }
 
+func (r *dmlExprFilterRule) getKeepExpr(ti *model.TableInfo) (
+	expression.Expression, error,
+) {
+	tableName := ti.TableName.String()
+	if r.keepExprs[tableName] != nil {
+		return r.keepExprs[tableName], nil
+	}
+
+	if r.config.KeepValueExpr != "" {
+		expr, err := r.getSimpleExprOfTable(r.config.KeepValueExpr, ti)
+		if err != nil {
+			return nil, err
+		}
+		r.keepExprs[tableName] = expr
+	}
+	return r.keepExprs[tableName], nil
+}
+
 func (r *dmlExprFilterRule) getSimpleExprOfTable(
 	expr string,
 	ti *model.TableInfo,
@@ -265,6 +299,11 @@ func (r *dmlExprFilterRule) shouldSkipDML(
 		r.tables[tableName] = ti.Clone()
 	}
 
+	keep, err := r.keepDMLByExpression(row, rawRow, ti)
+	if err != nil || !keep {
+		return !keep, err
+	}
+
 	switch {
 	case row.IsInsert():
 		exprs, err := r.getInsertExpr(ti)
@@ -335,6 +374,45 @@ func (r *dmlExprFilterRule) skipDMLByExpression(
 	return false, nil
 }
 
+// keepDMLByExpression returns true if the row satisfies the keep expression,
+// or the keep expression isn't set.
+func (r *dmlExprFilterRule) keepDMLByExpression(
+	row *model.RowChangedEvent,
+	rawRow model.RowChangedDatums,
+	ti *model.TableInfo,
+) (bool, error) {
+	expr, err := r.getKeepExpr(ti)
+	if err != nil || expr == nil {
+		return true, err
+	}
+	var rows [][]types.Datum
+	switch {
+	case row.IsInsert():
+		rows = [][]types.Datum{rawRow.RowDatums}
+	case row.IsUpdate():
+		rows = [][]types.Datum{rawRow.PreRowDatums, rawRow.RowDatums}
+	case row.IsDelete():
+		rows = [][]types.Datum{rawRow.PreRowDatums}
+	default:
+		return true, nil
+	}
+	for _, rowData := range rows {
+		if len(rowData) == 0 {
+			continue
+		}
+		// a NULL result is treated as false, like the WHERE clause.
+		v, isNull, err := expr.EvalInt(r.sessCtx, chunk.MutRowFromDatums(rowData).ToRow())
+		if err != nil {
+			log.Error("failed to eval expression", zap.Error(err))
+			return false, errors.Trace(err)
+		}
+		if !isNull && v != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func getColumnFromError(err error) string {
 	if !core.ErrUnknownColumn.Equal(err) {
 		return err.Error()
@@ -350,6 +350,88 @@ func TestShouldSkipDMLBasic(t *testing.T) {
 	}
 }
 
+func TestShouldSkipDMLByKeepExpr(t *testing.T) {
+	helper := newTestHelper(t)
+	defer helper.close()
+	helper.getTk().MustExec("use test;")
+
+	tableInfo := helper.execDDL(
+		"create table test.article(id int primary key, title char(50), status char(10))")
+	f, err := newExprFilter("", &config.FilterConfig{
+		EventFilters: []*config.EventFilterRule{
+			{
+				Matcher:       []string{"test.article"},
+				KeepValueExpr: "status != 'draft'",
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.Nil(t, f.verify([]*model.TableInfo{tableInfo}))
+
+	sessCtx := utils.NewSessionCtx(map[string]string{
+		"time_zone": "System",
+	})
+	cases := []struct {
+		preRow []interface{}
+		row    []interface{}
+		ignore bool
+	}{
+		{ // insert a published article
+			row:    []interface{}{1, "TiCDC", "published"},
+			ignore: false,
+		},
+		{ // insert a draft
+			row:    []interface{}{2, "TiDB", "draft"},
+			ignore: true,
+		},
+		{ // NULL doesn't satisfy the expression
+			row:    []interface{}{3, "TiKV", nil},
+			ignore: true,
+		},
+		{ // delete a draft
+			preRow: []interface{}{2, "TiDB", "draft"},
+			ignore: true,
+		},
+		{ // update a draft
+			preRow: []interface{}{2, "TiDB", "draft"},
+			row:    []interface{}{2, "TiDB 7.1", "draft"},
+			ignore: true,
+		},
+		{ // publish a draft
+			preRow: []interface{}{2, "TiDB", "draft"},
+			row:    []interface{}{2, "TiDB", "published"},
+			ignore: false,
+		},
+		{ // withdraw an article
+			preRow: []interface{}{1, "TiCDC", "published"},
+			row:    []interface{}{1, "TiCDC", "draft"},
+			ignore: false,
+		},
+	}
+	for _, c := range cases {
+		rowDatums, err := utils.AdjustBinaryProtocolForDatum(sessCtx, c.row, tableInfo.Columns)
+		require.Nil(t, err)
+		preRowDatums, err := utils.AdjustBinaryProtocolForDatum(sessCtx, c.preRow, tableInfo.Columns)
+		require.Nil(t, err)
+		row := &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "article"},
+		}
+		if c.row != nil {
+			row.Columns = []*model.Column{{Name: "none"}}
+		}
+		if c.preRow != nil {
+			row.PreColumns = []*model.Column{{Name: "none"}}
+		}
+		rawRow := model.RowChangedDatums{
+			RowDatums:    rowDatums,
+			PreRowDatums: preRowDatums,
+		}
+		ignore, err := f.shouldSkipDML(row, rawRow, tableInfo)
+		require.Nil(t, err)
+		require.Equal(t, c.ignore, ignore, "case: %+v", c)
+	}
+}
+
 // This test case is for testing when there are syntax error
 // or unknown error in the expression the return error type and message
 // are as expected.
@@ -746,6 +828,21 @@ func TestVerify(t *testing.T) {
 			err:    cerror.ErrExpressionParseFailed,
 			errMsg: "There is a syntax error in",
 		},
+		{
+			ddls: []string{
+				"create table test.book(id int primary key, name char(50), status char(10))",
+			},
+			cfg: &config.FilterConfig{
+				EventFilters: []*config.EventFilterRule{
+					{
+						Matcher:       []string{"test.book"},
+						KeepValueExpr: "state != 'draft'",
+					},
+				},
+			},
+			err:    cerror.ErrExpressionColumnNotFound,
+			errMsg: "Cannot find column 'state' from table 'test.book' in",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -91,6 +91,11 @@ type filter struct {
 	sqlEventFilter *sqlEventFilter
 	// ignoreTxnStartTs is used to filter out dml/ddl event by its starsTs.
 	ignoreTxnStartTs []uint64
+	// rowSampler is used to filter out dml event which isn't sampled.
+	rowSampler *rowSampler
+	// localOriginID is used to filter out dml event which comes from the
+	// upstream itself, it's zero if the filtering is disabled.
+	localOriginID uint64
 }
 
 // NewFilter creates a filter.
@@ -112,19 +117,27 @@ func NewFilter(cfg *config.ReplicaConfig, tz string) (Filter, error) {
 	if err != nil {
 		return nil, err
 	}
+	var localOriginID uint64
+	if cfg.Filter.IgnoreLocalOrigin && cfg.Sink != nil {
+		localOriginID = cfg.Sink.TiDBSourceID
+	}
 	return &filter{
 		tableFilter:      f,
 		dmlExprFilter:    dmlExprFilter,
 		sqlEventFilter:   sqlEventFilter,
 		ignoreTxnStartTs: cfg.Filter.IgnoreTxnStartTs,
+		rowSampler:       newRowSampler(cfg.Filter.SamplingRate),
+		localOriginID:    localOriginID,
 	}, nil
 }
 
 // ShouldIgnoreDMLEvent checks if a DML event should be ignore by conditions below:
 // 0. By startTs.
-// 1. By table name.
-// 2. By type.
-// 3. By columns value.
+// 1. By origin.
+// 2. By table name.
+// 3. By type.
+// 4. By sampling of handle key.
+// 5. By columns value.
 func (f *filter) ShouldIgnoreDMLEvent(
 	dml *model.RowChangedEvent,
 	rawRow model.RowChangedDatums,
@@ -134,6 +147,10 @@ func (f *filter) ShouldIgnoreDMLEvent(
 		return true, nil
 	}
 
+	if f.localOriginID != 0 && dml.OriginID == f.localOriginID {
+		return true, nil
+	}
+
 	if f.ShouldIgnoreTable(dml.Table.Schema, dml.Table.Table) {
 		return true, nil
 	}
@@ -145,6 +162,9 @@ func (f *filter) ShouldIgnoreDMLEvent(
 	if ignoreByEventType {
 		return true, nil
 	}
+	if f.rowSampler.shouldSkipDML(dml) {
+		return true, nil
+	}
 	return f.dmlExprFilter.shouldSkipDML(dml, rawRow, ti)
 }
 
@@ -342,3 +342,39 @@ func TestShouldIgnoreDDL(t *testing.T) {
 		}
 	}
 }
+
+func TestShouldIgnoreDMLEventByOrigin(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.TiDBSourceID = 1
+	row := &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t"},
+		Columns: []*model.Column{{Name: "a", Value: 1}},
+	}
+
+	// the rows of local origin are replicated by default.
+	f, err := NewFilter(cfg, "")
+	require.NoError(t, err)
+	row.OriginID = 1
+	ignore, err := f.ShouldIgnoreDMLEvent(row, model.RowChangedDatums{}, nil)
+	require.NoError(t, err)
+	require.False(t, ignore)
+
+	cfg.Filter.IgnoreLocalOrigin = true
+	f, err = NewFilter(cfg, "")
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		originID uint64
+		ignore   bool
+	}{
+		{originID: 0, ignore: false},
+		{originID: 1, ignore: true},
+		{originID: 2, ignore: false},
+	} {
+		row.OriginID = tc.originID
+		ignore, err = f.ShouldIgnoreDMLEvent(row, model.RowChangedDatums{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, tc.ignore, ignore)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"hash/fnv"
+
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// rowSampler keeps a deterministic sample of the rows by the hash of their
+// handle key values, so all the changes of a row are either kept or skipped.
+type rowSampler struct {
+	// threshold is compared with the 32 bits hash of the handle key values,
+	// the rows whose hash is less than it are kept.
+	threshold uint64
+}
+
+// newRowSampler creates a rowSampler, nil is returned if all the rows are kept.
+func newRowSampler(rate float64) *rowSampler {
+	if rate <= 0 || rate >= 1 {
+		return nil
+	}
+	return &rowSampler{threshold: uint64(rate * (1 << 32))}
+}
+
+// shouldSkipDML returns true if the row isn't in the sample. An updated row
+// is kept if either its old or new handle key is sampled, so the rows whose
+// handle key moves out of the sample are also removed from the downstream.
+func (s *rowSampler) shouldSkipDML(row *model.RowChangedEvent) bool {
+	if s == nil {
+		return false
+	}
+	if len(row.PreColumns) != 0 && s.sampled(row.PreColumns) {
+		return false
+	}
+	if len(row.Columns) != 0 && s.sampled(row.Columns) {
+		return false
+	}
+	return true
+}
+
+func (s *rowSampler) sampled(cols []*model.Column) bool {
+	h := fnv.New32a()
+	hasHandleKey := false
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			hasHandleKey = true
+			h.Write([]byte(model.ColumnValueString(col.Value)))
+			h.Write([]byte{0})
+		}
+	}
+	// the table has no handle key, all the column values are hashed.
+	if !hasHandleKey {
+		for _, col := range cols {
+			if col != nil {
+				h.Write([]byte(model.ColumnValueString(col.Value)))
+				h.Write([]byte{0})
+			}
+		}
+	}
+	return uint64(h.Sum32()) < s.threshold
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newSampledRow(pre, post interface{}) *model.RowChangedEvent {
+	row := &model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t"},
+	}
+	if pre != nil {
+		row.PreColumns = []*model.Column{
+			{Name: "id", Value: pre, Flag: model.HandleKeyFlag},
+			{Name: "name", Value: "old"},
+		}
+	}
+	if post != nil {
+		row.Columns = []*model.Column{
+			{Name: "id", Value: post, Flag: model.HandleKeyFlag},
+			{Name: "name", Value: "new"},
+		}
+	}
+	return row
+}
+
+func TestRowSampler(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newRowSampler(0))
+	require.Nil(t, newRowSampler(1))
+	var disabled *rowSampler
+	require.False(t, disabled.shouldSkipDML(newSampledRow(nil, 1)))
+
+	s := newRowSampler(0.1)
+	kept := 0
+	var sampledID, skippedID int64 = -1, -1
+	for i := int64(0); i < 10000; i++ {
+		skip := s.shouldSkipDML(newSampledRow(nil, i))
+		// the sample is deterministic and only depends on the handle key.
+		require.Equal(t, skip, s.shouldSkipDML(newSampledRow(i, nil)))
+		if skip {
+			skippedID = i
+		} else {
+			kept++
+			sampledID = i
+		}
+	}
+	require.InDelta(t, 1000, kept, 150)
+
+	// an updated row is kept if either its old or new handle key is sampled.
+	require.False(t, s.shouldSkipDML(newSampledRow(sampledID, skippedID)))
+	require.False(t, s.shouldSkipDML(newSampledRow(skippedID, sampledID)))
+	require.True(t, s.shouldSkipDML(newSampledRow(skippedID, skippedID)))
+
+	// the non-handle columns don't affect the sample.
+	row := newSampledRow(nil, sampledID)
+	row.Columns[1].Value = "another"
+	require.False(t, s.shouldSkipDML(row))
+}
+
+func TestShouldIgnoreDMLEventBySampling(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.SamplingRate = 0.5
+	f, err := NewFilter(cfg, "")
+	require.NoError(t, err)
+
+	ignored := 0
+	for i := int64(0); i < 1000; i++ {
+		ignore, err := f.ShouldIgnoreDMLEvent(newSampledRow(nil, i), model.RowChangedDatums{}, nil)
+		require.NoError(t, err)
+		if ignore {
+			ignored++
+		}
+	}
+	require.InDelta(t, 500, ignored, 100)
+}
@@ -14,6 +14,8 @@
 package filter
 
 import (
+	"fmt"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
@@ -92,10 +94,46 @@ func verifyIgnoreEvents(types []bf.EventType) error {
 	return nil
 }
 
+// verifyDDLEvents checks the event types of the ddl-allow-list and the
+// ddl-deny-list, which can only be the DDL event types.
+func verifyDDLEvents(name string, types []bf.EventType) error {
+	if err := verifyIgnoreEvents(types); err != nil {
+		return err
+	}
+	for _, et := range types {
+		switch et {
+		case bf.AllDML, bf.InsertEvent, bf.UpdateEvent, bf.DeleteEvent:
+			return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("the %s can't contain the dml event type %s", name, et))
+		}
+	}
+	return nil
+}
+
+// newDDLListFilter creates a binlog filter for the DDLs of all tables, which
+// replicates (bf.Do) or skips (bf.Ignore) the DDLs of the given types.
+func newDDLListFilter(types []bf.EventType, action bf.ActionType) (*bf.BinlogEvent, error) {
+	rule := &bf.BinlogEventRule{
+		SchemaPattern: binlogFilterSchemaPlaceholder,
+		TablePattern:  binlogFilterTablePlaceholder,
+		Events:        types,
+		Action:        action,
+	}
+	res, err := bf.NewBinlogEvent(caseSensitive, []*bf.BinlogEventRule{rule})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, "failed to create binlog event filter")
+	}
+	return res, nil
+}
+
 // sqlEventFilter is a filter that filters DDL/DML event by its type or query.
 type sqlEventFilter struct {
 	p     *parser.Parser
 	rules []*sqlEventRule
+	// ddlAllowList and ddlDenyList filter the DDLs of all tables by their
+	// types, they're nil if the lists are empty.
+	ddlAllowList *bf.BinlogEvent
+	ddlDenyList  *bf.BinlogEvent
 }
 
 func newSQLEventFilter(cfg *config.FilterConfig) (*sqlEventFilter, error) {
@@ -107,6 +145,26 @@ func newSQLEventFilter(cfg *config.FilterConfig) (*sqlEventFilter, error) {
 			return nil, errors.Trace(err)
 		}
 	}
+	if len(cfg.DDLAllowList) > 0 {
+		if err := verifyDDLEvents("ddl-allow-list", cfg.DDLAllowList); err != nil {
+			return nil, err
+		}
+		allowList, err := newDDLListFilter(cfg.DDLAllowList, bf.Do)
+		if err != nil {
+			return nil, err
+		}
+		res.ddlAllowList = allowList
+	}
+	if len(cfg.DDLDenyList) > 0 {
+		if err := verifyDDLEvents("ddl-deny-list", cfg.DDLDenyList); err != nil {
+			return nil, err
+		}
+		denyList, err := newDDLListFilter(cfg.DDLDenyList, bf.Ignore)
+		if err != nil {
+			return nil, err
+		}
+		res.ddlDenyList = denyList
+	}
 	return res, nil
 }
 
@@ -141,6 +199,9 @@ func (f *sqlEventFilter) shouldSkipDDL(ddl *model.DDLEvent) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if skip, err := f.shouldSkipDDLByList(ddl, evenType); skip || err != nil {
+		return skip, err
+	}
 	if evenType == bf.NullEvent {
 		log.Warn("sql event filter unsupported ddl type, do nothing",
 			zap.String("type", ddl.Type.String()),
@@ -161,6 +222,42 @@ func (f *sqlEventFilter) shouldSkipDDL(ddl *model.DDLEvent) (bool, error) {
 	return false, nil
 }
 
+// shouldSkipDDLByList skips the DDL if its type is in the ddl-deny-list, or
+// isn't in the ddl-allow-list.
+func (f *sqlEventFilter) shouldSkipDDLByList(ddl *model.DDLEvent, eventType bf.EventType) (bool, error) {
+	if f.ddlDenyList != nil {
+		action, err := f.ddlDenyList.Filter(
+			binlogFilterSchemaPlaceholder, binlogFilterTablePlaceholder, eventType, ddl.Query)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if action == bf.Ignore {
+			log.Info("DDL is skipped by the ddl-deny-list",
+				zap.String("type", string(eventType)), zap.String("query", ddl.Query))
+			return true, nil
+		}
+	}
+	if f.ddlAllowList != nil {
+		// the DDLs whose types are unknown can't be in the allow list.
+		if eventType == bf.NullEvent {
+			log.Info("DDL is skipped by the ddl-allow-list",
+				zap.String("type", ddl.Type.String()), zap.String("query", ddl.Query))
+			return true, nil
+		}
+		action, err := f.ddlAllowList.Filter(
+			binlogFilterSchemaPlaceholder, binlogFilterTablePlaceholder, eventType, ddl.Query)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if action == bf.Ignore {
+			log.Info("DDL is skipped by the ddl-allow-list",
+				zap.String("type", string(eventType)), zap.String("query", ddl.Query))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // shouldSkipDML skips dml event by its type.
 func (f *sqlEventFilter) shouldSkipDML(event *model.RowChangedEvent) (bool, error) {
 	var et bf.EventType
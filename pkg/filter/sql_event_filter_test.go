@@ -190,6 +190,48 @@ func TestShouldSkipDDL(t *testing.T) {
 	}
 }
 
+func TestShouldSkipDDLByList(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		query string
+		skip  bool
+	}{
+		{"create database test", false},
+		{"create table t1 (a int)", false},
+		{"alter table t1 add column b int", false},
+		{"drop table t1", true},
+		{"truncate table t1", true},
+		{"rename table t1 to t2", true},
+		{"create view v1 as select * from t1", true},
+	}
+	f, err := newSQLEventFilter(&config.FilterConfig{
+		DDLAllowList: []bf.EventType{bf.CreateSchema, bf.CreateTable, bf.AlterTable, bf.DropTable},
+		DDLDenyList:  []bf.EventType{bf.DropTable, bf.TruncateTable},
+	})
+	require.NoError(t, err)
+	for _, c := range cases {
+		ddl := &model.DDLEvent{
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "t1"},
+			},
+			Query: c.query,
+		}
+		skip, err := f.shouldSkipDDL(ddl)
+		require.NoError(t, err)
+		require.Equal(t, c.skip, skip, "case: %+v", c)
+	}
+
+	_, err = newSQLEventFilter(&config.FilterConfig{
+		DDLDenyList: []bf.EventType{bf.AllDML},
+	})
+	require.ErrorContains(t, err, "can't contain the dml event type")
+	_, err = newSQLEventFilter(&config.FilterConfig{
+		DDLAllowList: []bf.EventType{"drop everything"},
+	})
+	require.True(t, errors.ErrorEqual(err, cerror.ErrInvalidIgnoreEventType))
+}
+
 func TestShouldSkipDML(t *testing.T) {
 	t.Parallel()
 	type innerCase struct {
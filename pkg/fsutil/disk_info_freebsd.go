@@ -52,3 +52,12 @@ func GetDiskInfo(dir string) (*DiskInfo, error) {
 
 	return info, nil
 }
+
+// GetAvailableSpace returns the available space of the given directory in bytes.
+func GetAvailableSpace(dir string) (uint64, error) {
+	fs := syscall.Statfs_t{}
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return 0, cerror.WrapError(cerror.ErrGetDiskInfo, err)
+	}
+	return uint64(fs.Bavail) * uint64(fs.Bsize), nil
+}
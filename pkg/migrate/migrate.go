@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	pd "github.com/tikv/pd/client"
 	clientV3 "go.etcd.io/etcd/client/v3"
@@ -347,7 +348,7 @@ func maskChangefeedInfo(data []byte) string {
 	}
 	sinkURI, ok := oldConfig["sink-uri"]
 	if ok {
-		sinkURIParsed, err := url.Parse(sinkURI.(string))
+		sinkURIParsed, err := sink.ParseSinkURI(sinkURI.(string))
 		if err != nil {
 			log.Error("failed to parse sink URI", zap.Error(err))
 		}
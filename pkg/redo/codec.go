@@ -0,0 +1,203 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security/secret"
+)
+
+const (
+	// CompressionNone writes the log files without compression.
+	CompressionNone = "none"
+	// CompressionZstd compresses the log files with zstd.
+	CompressionZstd = "zstd"
+)
+
+const (
+	codecFlagZstd byte = 1 << iota
+	codecFlagAESGCM
+)
+
+// codecMagic is the beginning of the encoded log files. The last byte of a
+// frame size written by EncodeFrameSize is either 0 or 0x80|padding, so the
+// plain log files never start with the magic.
+var codecMagic = []byte{'T', 'i', 'C', 'D', 'C', 'R', 'L', 0xff}
+
+// FileCodec compresses and encrypts the content of the log files before they
+// are written to the external storage, and decodes them after they're read.
+// An encoded file is the magic, a byte of flags, the nonce if it's encrypted
+// by AES-GCM, and the payload. The plain files are still readable.
+type FileCodec struct {
+	compression string
+	aead        cipher.AEAD
+}
+
+// NewFileCodec creates a FileCodec. The encryption key is the hex encoded
+// AES-128, AES-192 or AES-256 key, or a secret reference to it, the files are
+// not encrypted if it's empty.
+func NewFileCodec(compression string, encryptionKey string) (*FileCodec, error) {
+	if err := ValidateCompression(compression); err != nil {
+		return nil, err
+	}
+	c := &FileCodec{compression: compression}
+	if encryptionKey == "" {
+		return c, nil
+	}
+
+	encryptionKey, err := secret.Resolve(encryptionKey)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
+	key, err := parseEncryptionKey(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
+	if c.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
+	return c, nil
+}
+
+// ValidateCompression checks the compression of the log files.
+func ValidateCompression(compression string) error {
+	switch compression {
+	case "", CompressionNone, CompressionZstd:
+		return nil
+	default:
+		return errors.WrapError(errors.ErrRedoConfigInvalid,
+			errors.Errorf("unsupported compression %s, it should be %s or %s",
+				compression, CompressionNone, CompressionZstd))
+	}
+}
+
+// ValidateEncryptionKey checks the encryption key of the log files, the
+// secret references are not resolved.
+func ValidateEncryptionKey(encryptionKey string) error {
+	if encryptionKey == "" || secret.IsReference(encryptionKey) {
+		return nil
+	}
+	_, err := parseEncryptionKey(encryptionKey)
+	return err
+}
+
+func parseEncryptionKey(encryptionKey string) ([]byte, error) {
+	key, err := hex.DecodeString(encryptionKey)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid,
+			errors.New("the encryption key should be hex encoded"))
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid,
+			errors.Errorf("the encryption key should be 16, 24 or 32 bytes, but it's %d bytes",
+				len(key)))
+	}
+}
+
+// Encode compresses and encrypts the content of a log file, the content is
+// returned as it is if neither is enabled.
+func (c *FileCodec) Encode(data []byte) ([]byte, error) {
+	if c == nil || (c.compression != CompressionZstd && c.aead == nil) {
+		return data, nil
+	}
+
+	var flags byte
+	if c.compression == CompressionZstd {
+		var buf bytes.Buffer
+		w, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		data = buf.Bytes()
+		flags |= codecFlagZstd
+	}
+	if c.aead != nil {
+		flags |= codecFlagAESGCM
+	}
+
+	header := make([]byte, 0, len(codecMagic)+1)
+	header = append(append(header, codecMagic...), flags)
+	if c.aead == nil {
+		return append(header, data...), nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	result := make([]byte, 0, len(header)+len(nonce)+len(data)+c.aead.Overhead())
+	result = append(append(result, header...), nonce...)
+	// The header is authenticated, so the flags can't be tampered with.
+	return c.aead.Seal(result, nonce, data, header), nil
+}
+
+// Decode decrypts and decompresses the content of a log file encoded by
+// Encode, the content of a plain log file is returned as it is.
+func (c *FileCodec) Decode(data []byte) ([]byte, error) {
+	if len(data) <= len(codecMagic) || !bytes.HasPrefix(data, codecMagic) {
+		return data, nil
+	}
+	header := data[:len(codecMagic)+1]
+	flags := header[len(codecMagic)]
+	data = data[len(header):]
+
+	if flags&codecFlagAESGCM != 0 {
+		if c == nil || c.aead == nil {
+			return nil, errors.WrapError(errors.ErrRedoConfigInvalid,
+				errors.New("the redo log is encrypted, but the encryption key isn't set"))
+		}
+		nonceSize := c.aead.NonceSize()
+		if len(data) < nonceSize {
+			return nil, errors.WrapError(errors.ErrRedoFileOp,
+				errors.New("the encrypted redo log is truncated"))
+		}
+		var err error
+		data, err = c.aead.Open(nil, data[:nonceSize], data[nonceSize:], header)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp,
+				errors.Annotate(err, "failed to decrypt the redo log, the encryption key may be wrong"))
+		}
+	}
+	if flags&codecFlagZstd != 0 {
+		r, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		defer r.Close()
+		if data, err = io.ReadAll(r); err != nil {
+			return nil, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+	}
+	return data, nil
+}
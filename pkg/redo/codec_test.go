@@ -0,0 +1,125 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCodec(t *testing.T) {
+	t.Parallel()
+
+	key := strings.Repeat("0f", 32)
+	data := bytes.Repeat([]byte("redo log content "), 1024)
+	cases := []struct {
+		compression string
+		key         string
+	}{
+		{"", ""},
+		{CompressionNone, ""},
+		{CompressionZstd, ""},
+		{CompressionNone, key},
+		{CompressionZstd, key},
+		{CompressionZstd, strings.Repeat("0f", 16)},
+	}
+	for _, c := range cases {
+		codec, err := NewFileCodec(c.compression, c.key)
+		require.NoError(t, err)
+		encoded, err := codec.Encode(data)
+		require.NoError(t, err)
+		if c.compression != CompressionZstd && c.key == "" {
+			require.Equal(t, data, encoded)
+		} else {
+			require.True(t, bytes.HasPrefix(encoded, codecMagic))
+		}
+		if c.key != "" {
+			require.NotContains(t, string(encoded), "redo log content")
+		}
+		if c.compression == CompressionZstd {
+			require.Less(t, len(encoded), len(data)/10)
+		}
+
+		decoded, err := codec.Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	// The plain log files are returned as they are.
+	plain := make([]byte, 16)
+	lenField, _ := encodeFrameSizeForTest(5)
+	binary.LittleEndian.PutUint64(plain, lenField)
+	codec, err := NewFileCodec(CompressionZstd, key)
+	require.NoError(t, err)
+	decoded, err := codec.Decode(plain)
+	require.NoError(t, err)
+	require.Equal(t, plain, decoded)
+
+	// The encrypted files can't be decoded without the right key.
+	encoded, err := codec.Encode(data)
+	require.NoError(t, err)
+	noKey, err := NewFileCodec("", "")
+	require.NoError(t, err)
+	_, err = noKey.Decode(encoded)
+	require.ErrorContains(t, err, "encryption key isn't set")
+	wrongKey, err := NewFileCodec("", strings.Repeat("f0", 32))
+	require.NoError(t, err)
+	_, err = wrongKey.Decode(encoded)
+	require.ErrorContains(t, err, "failed to decrypt")
+
+	// The flags are authenticated.
+	encoded[len(codecMagic)] &^= codecFlagZstd
+	_, err = codec.Decode(encoded)
+	require.ErrorContains(t, err, "failed to decrypt")
+}
+
+func TestFileCodecKey(t *testing.T) {
+	t.Parallel()
+
+	keyFile := filepath.Join(t.TempDir(), "redo-key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(strings.Repeat("ab", 32)+"\n"), 0o600))
+	ref := "${secret:file:" + keyFile + "}"
+	require.NoError(t, ValidateEncryptionKey(ref))
+	codec, err := NewFileCodec(CompressionNone, ref)
+	require.NoError(t, err)
+	require.NotNil(t, codec.aead)
+
+	require.NoError(t, ValidateEncryptionKey(""))
+	require.NoError(t, ValidateEncryptionKey(strings.Repeat("ab", 24)))
+	require.ErrorContains(t, ValidateEncryptionKey("not-hex"), "hex encoded")
+	require.ErrorContains(t, ValidateEncryptionKey("abcd"), "16, 24 or 32 bytes")
+	_, err = NewFileCodec(CompressionNone, "${secret:file:"+keyFile+".missing}")
+	require.ErrorContains(t, err, "ErrRedoConfigInvalid")
+
+	require.ErrorContains(t, ValidateCompression("gzip"), "unsupported compression")
+	_, err = NewFileCodec("gzip", "")
+	require.ErrorContains(t, err, "unsupported compression")
+}
+
+// encodeFrameSizeForTest is the same as writer.EncodeFrameSize, which can't be
+// imported here.
+func encodeFrameSizeForTest(dataBytes int) (lenField uint64, padBytes int) {
+	lenField = uint64(dataBytes)
+	padBytes = (8 - (dataBytes % 8)) % 8
+	if padBytes != 0 {
+		lenField |= uint64(0x80|padBytes) << 56
+	}
+	return lenField, padBytes
+}
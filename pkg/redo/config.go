@@ -46,6 +46,12 @@ const (
 	DefaultFlushIntervalInMs = 2000
 	// MinFlushIntervalInMs is the minimum flush interval for redo log.
 	MinFlushIntervalInMs = 50
+	// DefaultFlushWorkerNum is the default number of workers flushing redo log
+	// files concurrently.
+	DefaultFlushWorkerNum = 8
+	// DefaultFlushConcurrency is the default number of parts uploaded
+	// concurrently for each redo log file, 1 means no multi-part upload.
+	DefaultFlushConcurrency = 1
 
 	// DefaultFileMode is the default mode when operation files
 	DefaultFileMode = 0o644
@@ -127,6 +133,10 @@ const (
 	consistentStorageAzblob ConsistentStorage = "azblob"
 	// consistentStorageAzure is an alias of Azure Blob storage.
 	consistentStorageAzure ConsistentStorage = "azure"
+	// consistentStorageOSS is an Alibaba Cloud OSS storage.
+	consistentStorageOSS ConsistentStorage = "oss"
+	// consistentStorageCOS is a Tencent Cloud COS storage.
+	consistentStorageCOS ConsistentStorage = "cos"
 	// consistentStorageFile is an external storage based on local files and
 	// will only be used for testing.
 	consistentStorageFile ConsistentStorage = "file"
@@ -145,8 +155,8 @@ func IsValidConsistentStorage(scheme string) bool {
 func IsExternalStorage(scheme string) bool {
 	switch ConsistentStorage(scheme) {
 	case consistentStorageS3, consistentStorageGCS, consistentStorageGS,
-		consistentStorageAzblob, consistentStorageAzure, consistentStorageFile,
-		consistentStorageNoop:
+		consistentStorageAzblob, consistentStorageAzure, consistentStorageOSS,
+		consistentStorageCOS, consistentStorageFile, consistentStorageNoop:
 		return true
 	default:
 		return false
@@ -49,13 +49,21 @@ func NewDefaultErrorRetry() *ErrorRetry {
 		defaultBackoffMaxInS)
 }
 
-// NewErrorRetry creates a new ErrorRetry.
+// NewErrorRetry creates a new ErrorRetry. The backoff is in range
+// [backoffBase, backoffMax) seconds, it's always backoffBase seconds if
+// backoffMax isn't greater than backoffBase.
 func NewErrorRetry(
 	maxRetryDuration time.Duration,
 	errGCInterval time.Duration,
 	backoffBase int64,
 	backoffMax int64,
 ) *ErrorRetry {
+	if backoffBase < 0 {
+		backoffBase = 0
+	}
+	if backoffMax <= backoffBase {
+		backoffMax = backoffBase + 1
+	}
 	return &ErrorRetry{
 		maxRetryDuration: maxRetryDuration,
 		errGCInterval:    errGCInterval,
@@ -89,8 +97,8 @@ func (r *ErrorRetry) GetRetryBackoff(err error) (time.Duration, error) {
 	r.lastInternalError = err
 	r.lastErrorRetryTime = time.Now()
 
-	// interval is in range [defaultBackoffBaseInS, defaultBackoffMaxInS)
+	// interval is in range [backoffBase, backoffMax)
 	interval := time.Second * time.Duration(
-		rand.Int63n(defaultBackoffMaxInS-defaultBackoffBaseInS)+defaultBackoffBaseInS)
+		rand.Int63n(r.backoffMax-r.backoffBase)+r.backoffBase)
 	return interval, nil
 }
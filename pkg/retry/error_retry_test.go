@@ -37,3 +37,28 @@ func TestGetRetryBackoff(t *testing.T) {
 	require.NoError(t, err)
 	require.Less(t, time.Since(r.firstRetryTime), elapsedTime)
 }
+
+func TestGetRetryBackoffInRange(t *testing.T) {
+	t.Parallel()
+
+	r := NewErrorRetry(time.Minute, time.Minute, 2, 4)
+	for i := 0; i < 100; i++ {
+		backoff, err := r.GetRetryBackoff(errors.New("test"))
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, backoff, 2*time.Second)
+		require.Less(t, backoff, 4*time.Second)
+	}
+
+	// the backoff is always the base if the max isn't greater than it.
+	for _, backoffMax := range []int64{1, 3} {
+		r = NewErrorRetry(time.Minute, time.Minute, 3, backoffMax)
+		backoff, err := r.GetRetryBackoff(errors.New("test"))
+		require.NoError(t, err)
+		require.Equal(t, 3*time.Second, backoff)
+	}
+
+	// the retry is exhausted after the max retry duration.
+	r = NewErrorRetry(0, time.Minute, 1, 2)
+	_, err := r.GetRetryBackoff(errors.New("test"))
+	require.Error(t, err)
+}
@@ -37,6 +37,9 @@ const (
 	GSSAPIMechanism SASLMechanism = sarama.SASLTypeGSSAPI
 	// OAuthMechanism means the SASL mechanism is OAuth2.
 	OAuthMechanism SASLMechanism = sarama.SASLTypeOAuth
+	// AWSMSKIAMMechanism means the SASL mechanism is the IAM authentication
+	// of Amazon MSK.
+	AWSMSKIAMMechanism SASLMechanism = "AWS_MSK_IAM"
 )
 
 // SASLMechanismFromString converts the string to SASL mechanism.
@@ -52,6 +55,8 @@ func SASLMechanismFromString(s string) (SASLMechanism, error) {
 		return GSSAPIMechanism, nil
 	case "oauthbearer":
 		return OAuthMechanism, nil
+	case "aws_msk_iam":
+		return AWSMSKIAMMechanism, nil
 	default:
 		return UnknownMechanism, errors.Errorf("unknown %s SASL mechanism", s)
 	}
@@ -64,6 +69,23 @@ type SASL struct {
 	SASLMechanism SASLMechanism
 	GSSAPI        GSSAPI
 	OAuth2        OAuth2
+	AWSMSKIAM     AWSMSKIAM
+}
+
+// AWSMSKIAM holds necessary parameters to support the IAM authentication of
+// Amazon MSK. The credentials are resolved by the AWS default credential chain,
+// and the role is assumed by them if RoleARN is set.
+type AWSMSKIAM struct {
+	// Region is the region of the MSK cluster, it's resolved by the AWS
+	// default config if it's empty.
+	Region          string
+	RoleARN         string
+	RoleSessionName string
+}
+
+// IsEnable checks whether the IAM authentication of Amazon MSK is configured.
+func (a *AWSMSKIAM) IsEnable() bool {
+	return len(a.Region) > 0 || len(a.RoleARN) > 0 || len(a.RoleSessionName) > 0
 }
 
 // OAuth2 holds necessary parameters to support sasl-oauth2.
@@ -74,6 +96,11 @@ type OAuth2 struct {
 	Scopes       []string
 	GrantType    string
 	Audience     string
+	// AssertionKeyPath is the path of the private key which signs the JWT
+	// client assertions, which are used instead of the client secret.
+	AssertionKeyPath string
+	// AssertionKeyID is the key ID in the header of the JWT client assertions.
+	AssertionKeyID string
 }
 
 // Validate validates the parameters of OAuth2.
@@ -82,9 +109,12 @@ func (o *OAuth2) Validate() error {
 	if len(o.ClientID) == 0 {
 		return errors.New("OAuth2 client id is empty")
 	}
-	if len(o.ClientSecret) == 0 {
+	if len(o.ClientSecret) == 0 && len(o.AssertionKeyPath) == 0 {
 		return errors.New("OAuth2 client secret is empty")
 	}
+	if len(o.ClientSecret) != 0 && len(o.AssertionKeyPath) != 0 {
+		return errors.New("OAuth2 client secret and assertion key can't be both set")
+	}
 	if len(o.TokenURL) == 0 {
 		return errors.New("OAuth2 token url is empty")
 	}
@@ -97,9 +127,11 @@ func (o *OAuth2) SetDefault() {
 }
 
 // IsEnable checks whether the OAuth2 is enabled.
-// One of values of ClientID, ClientSecret and TokenURL is not empty means enabled.
+// One of values of ClientID, ClientSecret, AssertionKeyPath and TokenURL is
+// not empty means enabled.
 func (o *OAuth2) IsEnable() bool {
-	return len(o.ClientID) > 0 || len(o.ClientSecret) > 0 || len(o.TokenURL) > 0
+	return len(o.ClientID) > 0 || len(o.ClientSecret) > 0 ||
+		len(o.AssertionKeyPath) > 0 || len(o.TokenURL) > 0
 }
 
 // GSSAPIAuthType defines the type of GSSAPI authentication.
@@ -74,6 +74,16 @@ func TestSASLMechanismFromString(t *testing.T) {
 			s:                 "GSSAPI",
 			expectedMechanism: "GSSAPI",
 		},
+		{
+			name:              "lower case aws_msk_iam mechanism",
+			s:                 "aws_msk_iam",
+			expectedMechanism: "AWS_MSK_IAM",
+		},
+		{
+			name:              "upper case AWS_MSK_IAM mechanism",
+			s:                 "AWS_MSK_IAM",
+			expectedMechanism: "AWS_MSK_IAM",
+		},
 	}
 	for _, test := range tests {
 		test := test
@@ -0,0 +1,159 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/pingcap/errors"
+)
+
+// fileProvider reads the secret from a file, the ref is the path of the file.
+// The trailing newlines of the file are trimmed.
+type fileProvider struct{}
+
+func (p *fileProvider) Fetch(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// vaultProvider reads the secret from HashiCorp Vault by its HTTP API, the
+// ref is like `<path>#<key>`, such as `secret/data/ticdc#password`. Both the
+// KV version 1 and 2 secrets engines are supported. The Vault server is set
+// by the standard environment variables VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE.
+type vaultProvider struct {
+	client *http.Client
+	// getenv is replaced in tests.
+	getenv func(string) string
+}
+
+func newVaultProvider() *vaultProvider {
+	return &vaultProvider{client: &http.Client{}, getenv: os.Getenv}
+}
+
+func (p *vaultProvider) Fetch(ctx context.Context, ref string) (string, error) {
+	addr := p.getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || key == "" {
+		return "", errors.Errorf("the key of the vault secret %s is not set", ref)
+	}
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("X-Vault-Token", p.getenv("VAULT_TOKEN"))
+	if namespace := p.getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("vault responds %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Trace(err)
+	}
+	data := secret.Data
+	// the data of the KV version 2 secrets engine is nested with its metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("key %s is not found in the vault secret %s", key, path)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// awsProvider reads the secret from AWS Secrets Manager, the ref is the ID
+// of the secret, such as `ticdc/kafka`. If the ref is like `<id>#<key>`, the
+// secret is parsed as a JSON object and the value of the key is returned.
+// The credentials and the region are set by the default AWS configs.
+type awsProvider struct {
+	once    sync.Once
+	client  secretsmanageriface.SecretsManagerAPI
+	initErr error
+}
+
+func (p *awsProvider) Fetch(ctx context.Context, ref string) (string, error) {
+	p.once.Do(func() {
+		if p.client != nil {
+			return
+		}
+		sess, err := session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			p.initErr = errors.Trace(err)
+			return
+		}
+		p.client = secretsmanager.New(sess)
+	})
+	if p.initErr != nil {
+		return "", p.initErr
+	}
+
+	id, key, hasKey := strings.Cut(ref, "#")
+	output, err := p.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	value := aws.StringValue(output.SecretString)
+	if !hasKey {
+		return value, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", errors.Annotatef(err, "the aws secret %s is not a JSON object", id)
+	}
+	field, ok := fields[key]
+	if !ok {
+		return "", errors.Errorf("key %s is not found in the aws secret %s", key, id)
+	}
+	return fmt.Sprint(field), nil
+}
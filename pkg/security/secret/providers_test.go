@@ -0,0 +1,116 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultProvider(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/ticdc":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"pass-v2"},"metadata":{"version":1}}}`))
+		case "/v1/kv/ticdc":
+			_, _ = w.Write([]byte(`{"data":{"password":"pass-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	env := map[string]string{"VAULT_ADDR": server.URL, "VAULT_TOKEN": "token"}
+	p := &vaultProvider{client: server.Client(), getenv: func(key string) string {
+		return env[key]
+	}}
+	ctx := context.Background()
+
+	secret, err := p.Fetch(ctx, "secret/data/ticdc#password")
+	require.NoError(t, err)
+	require.Equal(t, "pass-v2", secret)
+	secret, err = p.Fetch(ctx, "kv/ticdc#password")
+	require.NoError(t, err)
+	require.Equal(t, "pass-v1", secret)
+
+	_, err = p.Fetch(ctx, "secret/data/ticdc")
+	require.ErrorContains(t, err, "key of the vault secret")
+	_, err = p.Fetch(ctx, "secret/data/ticdc#user")
+	require.ErrorContains(t, err, "key user is not found")
+	_, err = p.Fetch(ctx, "secret/data/unknown#password")
+	require.ErrorContains(t, err, "vault responds 404")
+
+	env["VAULT_TOKEN"] = "invalid"
+	_, err = p.Fetch(ctx, "secret/data/ticdc#password")
+	require.ErrorContains(t, err, "permission denied")
+
+	delete(env, "VAULT_ADDR")
+	_, err = p.Fetch(ctx, "secret/data/ticdc#password")
+	require.ErrorContains(t, err, "VAULT_ADDR is not set")
+}
+
+type mockSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+	secrets map[string]string
+}
+
+func (m *mockSecretsManager) GetSecretValueWithContext(
+	_ aws.Context, input *secretsmanager.GetSecretValueInput, _ ...request.Option,
+) (*secretsmanager.GetSecretValueOutput, error) {
+	secret, ok := m.secrets[aws.StringValue(input.SecretId)]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(secret)}, nil
+}
+
+func TestAWSProvider(t *testing.T) {
+	t.Parallel()
+
+	p := &awsProvider{client: &mockSecretsManager{secrets: map[string]string{
+		"ticdc/mysql": "pass",
+		"ticdc/kafka": `{"user":"root","password":"pass"}`,
+	}}}
+	ctx := context.Background()
+
+	secret, err := p.Fetch(ctx, "ticdc/mysql")
+	require.NoError(t, err)
+	require.Equal(t, "pass", secret)
+	secret, err = p.Fetch(ctx, "ticdc/kafka#password")
+	require.NoError(t, err)
+	require.Equal(t, "pass", secret)
+
+	_, err = p.Fetch(ctx, "ticdc/kafka#token")
+	require.ErrorContains(t, err, "key token is not found")
+	_, err = p.Fetch(ctx, "ticdc/mysql#password")
+	require.ErrorContains(t, err, "not a JSON object")
+	_, err = p.Fetch(ctx, "ticdc/unknown")
+	require.ErrorContains(t, err, "secret not found")
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// The secret references are like `${secret:<provider>:<ref>}`, for example,
+	// `${secret:file:/etc/ticdc/password}` or `${secret:vault:secret/data/ticdc#password}`.
+	referencePrefix = "${secret:"
+	referenceSuffix = "}"
+
+	// defaultRefreshInterval is the interval to fetch the resolved secrets again,
+	// so the rotated secrets are used by the sinks created later.
+	defaultRefreshInterval = 5 * time.Minute
+	// fetchTimeout is the timeout of fetching a secret from the provider.
+	fetchTimeout = 10 * time.Second
+)
+
+// Provider fetches the secrets from an external secret store.
+type Provider interface {
+	// Fetch returns the secret of the reference, whose format depends on
+	// the provider.
+	Fetch(ctx context.Context, ref string) (string, error)
+}
+
+// IsReference returns true if the value is a secret reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, referencePrefix) && strings.HasSuffix(value, referenceSuffix)
+}
+
+// parseReference splits the secret reference into the provider and the ref.
+func parseReference(value string) (provider string, ref string, err error) {
+	body := strings.TrimSuffix(strings.TrimPrefix(value, referencePrefix), referenceSuffix)
+	provider, ref, ok := strings.Cut(body, ":")
+	if !ok || provider == "" || ref == "" {
+		return "", "", cerror.ErrResolveSecret.GenWithStack(
+			"invalid secret reference %s, it should be like ${secret:<provider>:<ref>}", value)
+	}
+	return provider, ref, nil
+}
+
+// Resolver resolves the secret references by the providers. The resolved
+// secrets are cached and refreshed periodically by Run.
+type Resolver struct {
+	providers       map[string]Provider
+	refreshInterval time.Duration
+
+	mu sync.RWMutex
+	// values are the resolved secrets by the references.
+	values map[string]string
+	// files are the paths of the files which the secrets are written to.
+	files map[string]string
+}
+
+// NewResolver creates a Resolver with the providers by their names.
+func NewResolver(providers map[string]Provider, refreshInterval time.Duration) *Resolver {
+	return &Resolver{
+		providers:       providers,
+		refreshInterval: refreshInterval,
+		values:          make(map[string]string),
+		files:           make(map[string]string),
+	}
+}
+
+// Resolve returns the secret if the value is a secret reference,
+// otherwise the value is returned as it is.
+func (r *Resolver) Resolve(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	r.mu.RLock()
+	secret, ok := r.values[value]
+	r.mu.RUnlock()
+	if ok {
+		return secret, nil
+	}
+
+	secret, err := r.fetch(value)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	r.values[value] = secret
+	r.mu.Unlock()
+	return secret, nil
+}
+
+// ResolveFile writes the secret to a file which is only readable by the
+// current user and returns its path if the value is a secret reference,
+// otherwise the value is returned as it is. It's used by the configs which
+// only accept the file paths, such as the TLS keys.
+func (r *Resolver) ResolveFile(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	secret, err := r.Resolve(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(value))
+	path := filepath.Join(os.TempDir(), "ticdc-secret-"+hex.EncodeToString(sum[:8]))
+	if err := os.WriteFile(path, []byte(secret), 0o600); err != nil {
+		return "", cerror.WrapError(cerror.ErrResolveSecret, err, value)
+	}
+	r.mu.Lock()
+	r.files[value] = path
+	r.mu.Unlock()
+	return path, nil
+}
+
+func (r *Resolver) fetch(value string) (string, error) {
+	name, ref, err := parseReference(value)
+	if err != nil {
+		return "", err
+	}
+	provider, ok := r.providers[name]
+	if !ok {
+		return "", cerror.ErrResolveSecret.GenWithStack(
+			"unknown secret provider %s of %s", name, value)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	secret, err := provider.Fetch(ctx, ref)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrResolveSecret, err, value)
+	}
+	return secret, nil
+}
+
+// Run refreshes the resolved secrets periodically until the context is done.
+// The stale secret is kept if it fails to be fetched.
+func (r *Resolver) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+			r.refresh()
+		}
+	}
+}
+
+func (r *Resolver) refresh() {
+	r.mu.RLock()
+	refs := make([]string, 0, len(r.values))
+	for ref := range r.values {
+		refs = append(refs, ref)
+	}
+	r.mu.RUnlock()
+
+	for _, ref := range refs {
+		secret, err := r.fetch(ref)
+		if err != nil {
+			log.Warn("refresh secret failed, the stale one is used",
+				zap.String("reference", ref), zap.Error(err))
+			continue
+		}
+		r.mu.Lock()
+		changed := r.values[ref] != secret
+		r.values[ref] = secret
+		path, hasFile := r.files[ref]
+		r.mu.Unlock()
+		if !changed {
+			continue
+		}
+		log.Info("secret is rotated", zap.String("reference", ref))
+		if hasFile {
+			if err := os.WriteFile(path, []byte(secret), 0o600); err != nil {
+				log.Warn("write rotated secret to file failed",
+					zap.String("reference", ref), zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+}
+
+var (
+	defaultResolverOnce sync.Once
+	defaultResolver     *Resolver
+)
+
+// DefaultResolver returns the process-wide Resolver with the file, vault
+// and aws providers.
+func DefaultResolver() *Resolver {
+	defaultResolverOnce.Do(func() {
+		defaultResolver = NewResolver(map[string]Provider{
+			"file":  &fileProvider{},
+			"vault": newVaultProvider(),
+			"aws":   &awsProvider{},
+		}, defaultRefreshInterval)
+	})
+	return defaultResolver
+}
+
+// Resolve resolves the value by the default Resolver.
+func Resolve(value string) (string, error) {
+	return DefaultResolver().Resolve(value)
+}
+
+// ResolveFile resolves the value into a file by the default Resolver.
+func ResolveFile(value string) (string, error) {
+	return DefaultResolver().ResolveFile(value)
+}
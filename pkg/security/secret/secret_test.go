@@ -0,0 +1,158 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	fetched int
+}
+
+func (p *mockProvider) Fetch(_ context.Context, ref string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetched++
+	secret, ok := p.secrets[ref]
+	if !ok {
+		return "", errors.Errorf("secret %s not found", ref)
+	}
+	return secret, nil
+}
+
+func (p *mockProvider) set(ref, secret string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets[ref] = secret
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockProvider{secrets: map[string]string{"kafka#password": "pass"}}
+	r := NewResolver(map[string]Provider{"mock": provider}, time.Minute)
+
+	// the plain values are returned as they are.
+	for _, value := range []string{"", "pass", "${secret:", "$secret:mock:kafka#password}"} {
+		resolved, err := r.Resolve(value)
+		require.NoError(t, err)
+		require.Equal(t, value, resolved)
+	}
+
+	resolved, err := r.Resolve("${secret:mock:kafka#password}")
+	require.NoError(t, err)
+	require.Equal(t, "pass", resolved)
+	// the resolved secret is cached.
+	_, err = r.Resolve("${secret:mock:kafka#password}")
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.fetched)
+
+	for _, value := range []string{
+		"${secret:mock}",
+		"${secret::kafka}",
+		"${secret:mock:}",
+		"${secret:unknown:kafka#password}",
+		"${secret:mock:mysql#password}",
+	} {
+		_, err := r.Resolve(value)
+		require.Regexp(t, ".*ErrResolveSecret.*", err, value)
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockProvider{secrets: map[string]string{"kafka#key": "key"}}
+	r := NewResolver(map[string]Provider{"mock": provider}, time.Minute)
+
+	path, err := r.ResolveFile("/etc/ticdc/kafka.key")
+	require.NoError(t, err)
+	require.Equal(t, "/etc/ticdc/kafka.key", path)
+
+	path, err = r.ResolveFile("${secret:mock:kafka#key}")
+	require.NoError(t, err)
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "key", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// the file is rewritten if the secret is rotated.
+	provider.set("kafka#key", "rotated")
+	r.refresh()
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "rotated", string(data))
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	provider := &mockProvider{secrets: map[string]string{"kafka#password": "pass"}}
+	r := NewResolver(map[string]Provider{"mock": provider}, 10*time.Millisecond)
+	_, err := r.Resolve("${secret:mock:kafka#password}")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.ErrorIs(t, r.Run(ctx), context.Canceled)
+	}()
+
+	provider.set("kafka#password", "rotated")
+	require.Eventually(t, func() bool {
+		resolved, err := r.Resolve("${secret:mock:kafka#password}")
+		return err == nil && resolved == "rotated"
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	// the stale secret is kept if it fails to be fetched.
+	provider.mu.Lock()
+	delete(provider.secrets, "kafka#password")
+	provider.mu.Unlock()
+	r.refresh()
+	resolved, err := r.Resolve("${secret:mock:kafka#password}")
+	require.NoError(t, err)
+	require.Equal(t, "rotated", resolved)
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(path, []byte("pass\n"), 0o600))
+	p := &fileProvider{}
+	secret, err := p.Fetch(context.Background(), path)
+	require.NoError(t, err)
+	require.Equal(t, "pass", secret)
+
+	_, err = p.Fetch(context.Background(), filepath.Join(t.TempDir(), "not-exist"))
+	require.Error(t, err)
+}
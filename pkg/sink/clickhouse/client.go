@@ -0,0 +1,83 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// Client executes the statements by the HTTP interface of ClickHouse.
+type Client struct {
+	endpoint   string
+	user       string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client by the config.
+func NewClient(config *Config) *Client {
+	return &Client{
+		endpoint:   config.Endpoint,
+		user:       config.User,
+		password:   config.Password,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Exec executes the query, the data of the INSERT query is sent as the body.
+func (c *Client) Exec(ctx context.Context, query string, data []byte) error {
+	params := url.Values{}
+	params.Set("query", query)
+	// parse the datetime values in any format of MySQL.
+	params.Set("date_time_input_format", "best_effort")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.endpoint+"/?"+params.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.user != "" {
+		req.Header.Set("X-ClickHouse-User", c.user)
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("clickhouse query failed, status: %d, body: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return errors.Trace(err)
+}
+
+// QuoteName quotes the name of a database, table or column.
+func QuoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// QuoteTable quotes the name of a table with its database.
+func QuoteTable(schema, table string) string {
+	return fmt.Sprintf("%s.%s", QuoteName(schema), QuoteName(table))
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientExec(t *testing.T) {
+	t.Parallel()
+
+	var query, user, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		user = r.Header.Get("X-ClickHouse-User")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if body == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Code: 62. DB::Exception: Empty query.\n"))
+		}
+	}))
+	defer server.Close()
+
+	cfg := NewConfig()
+	cfg.Endpoint = server.URL
+	cfg.User = "default"
+	client := NewClient(cfg)
+
+	ctx := context.Background()
+	insert := "INSERT INTO " + QuoteTable("test", "t`1") + " FORMAT JSONEachRow"
+	require.NoError(t, client.Exec(ctx, insert, []byte("{\"id\":1}\n")))
+	require.Equal(t, "INSERT INTO `test`.`t\\`1` FORMAT JSONEachRow", query)
+	require.Equal(t, "default", user)
+	require.Equal(t, "{\"id\":1}\n", body)
+
+	err := client.Exec(ctx, insert, nil)
+	require.ErrorContains(t, err, "status: 400")
+	require.ErrorContains(t, err, "Empty query.")
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imdario/mergo"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// EngineReplacingMergeTree keeps the row with the largest version column
+	// of each sorting key, the deleted rows are the ones whose sign column is -1.
+	EngineReplacingMergeTree = "replacing-merge-tree"
+	// EngineCollapsingMergeTree collapses the pairs of rows whose sign columns
+	// are 1 and -1, the old rows are cancelled by the rows whose sign column is -1.
+	EngineCollapsingMergeTree = "collapsing-merge-tree"
+
+	defaultVersionColumn = "_version"
+	defaultSignColumn    = "_sign"
+	defaultWorkerCount   = 8
+	defaultMaxBatchRows  = 10000
+	defaultTimeout       = time.Minute
+
+	defaultHTTPPort  = "8123"
+	defaultHTTPSPort = "8443"
+
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max batch rows.
+	maxMaxBatchRows = 1000000
+)
+
+type urlConfig struct {
+	Engine        *string `form:"engine"`
+	VersionColumn *string `form:"version-column"`
+	SignColumn    *string `form:"sign-column"`
+	WorkerCount   *int    `form:"worker-count"`
+	MaxBatchRows  *int    `form:"max-batch-rows"`
+	Timeout       *string `form:"timeout"`
+}
+
+// Config is the configs for the ClickHouse sink.
+type Config struct {
+	// Endpoint is the URL of the HTTP interface, such as "http://127.0.0.1:8123".
+	Endpoint string
+	User     string
+	Password string
+
+	Engine        string
+	VersionColumn string
+	SignColumn    string
+	WorkerCount   int
+	// MaxBatchRows is the max number of rows written by an INSERT statement.
+	MaxBatchRows int
+	Timeout      time.Duration
+}
+
+// NewConfig returns the default ClickHouse sink config.
+func NewConfig() *Config {
+	return &Config{
+		Engine:        EngineReplacingMergeTree,
+		VersionColumn: defaultVersionColumn,
+		SignColumn:    defaultSignColumn,
+		WorkerCount:   defaultWorkerCount,
+		MaxBatchRows:  defaultMaxBatchRows,
+		Timeout:       defaultTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrSinkURIInvalid.GenWithStack("fail to open ClickHouse sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsClickHouseScheme(scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStack(
+			"can't create ClickHouse sink with unsupported scheme: %s", scheme)
+	}
+	c.Endpoint = endpoint(scheme, sinkURI.Host)
+	c.User = sinkURI.User.Username()
+	c.Password, _ = sinkURI.User.Password()
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	urlParameter, err := mergeConfig(replicaConfig, urlParameter)
+	if err != nil {
+		return err
+	}
+	if urlParameter.Engine != nil {
+		switch engine := strings.ToLower(*urlParameter.Engine); engine {
+		case EngineReplacingMergeTree, EngineCollapsingMergeTree:
+			c.Engine = engine
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid engine %s, it must be %s or %s",
+				*urlParameter.Engine, EngineReplacingMergeTree, EngineCollapsingMergeTree)
+		}
+	}
+	if urlParameter.VersionColumn != nil && *urlParameter.VersionColumn != "" {
+		c.VersionColumn = *urlParameter.VersionColumn
+	}
+	if urlParameter.SignColumn != nil && *urlParameter.SignColumn != "" {
+		c.SignColumn = *urlParameter.SignColumn
+	}
+	if err := getPositiveInt("worker-count", urlParameter.WorkerCount,
+		maxWorkerCount, &c.WorkerCount); err != nil {
+		return err
+	}
+	if err := getPositiveInt("max-batch-rows", urlParameter.MaxBatchRows,
+		maxMaxBatchRows, &c.MaxBatchRows); err != nil {
+		return err
+	}
+	if urlParameter.Timeout != nil {
+		timeout, err := time.ParseDuration(*urlParameter.Timeout)
+		if err != nil || timeout <= 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid timeout %s, it must be a positive duration", *urlParameter.Timeout)
+		}
+		c.Timeout = timeout
+	}
+	return nil
+}
+
+func mergeConfig(
+	replicaConfig *config.ReplicaConfig,
+	urlParameters *urlConfig,
+) (*urlConfig, error) {
+	dest := &urlConfig{}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.ClickHouseConfig != nil {
+		cConfig := replicaConfig.Sink.ClickHouseConfig
+		dest.Engine = cConfig.Engine
+		dest.VersionColumn = cConfig.VersionColumn
+		dest.SignColumn = cConfig.SignColumn
+		dest.WorkerCount = cConfig.WorkerCount
+		dest.MaxBatchRows = cConfig.MaxBatchRows
+	}
+	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	return dest, nil
+}
+
+// endpoint returns the URL of the HTTP interface, the default port is used
+// if the host doesn't contain one.
+func endpoint(scheme, host string) string {
+	protocol, port := "http", defaultHTTPPort
+	if scheme == sink.ClickHouseSSLScheme {
+		protocol, port = "https", defaultHTTPSPort
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, port)
+	}
+	return protocol + "://" + host
+}
+
+func getPositiveInt(name string, value *int, upperLimit int, target *int) error {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	if c <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %d, which must be greater than 0", name, c)
+	}
+	if c > upperLimit {
+		log.Warn(name+" too large",
+			zap.Int("original", c), zap.Int("override", upperLimit))
+		c = upperLimit
+	}
+	*target = c
+	return nil
+}
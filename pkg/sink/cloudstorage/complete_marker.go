@@ -0,0 +1,55 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+// completeMarkerFileName is the name of the marker file written to a date
+// partition directory, it's skipped by the readers which ignore the files
+// prefixed with an underscore, such as Spark and Hive.
+const completeMarkerFileName = "_SUCCESS"
+
+// CompleteMarker is written to a date partition directory of a table once all
+// the data files of the partition are flushed. The data files are always
+// written to the directory of the current date, so a partition of a past date
+// is never written again after its files are flushed.
+type CompleteMarker struct {
+	// MaxCommitTs is the max commit ts of the events in the partition.
+	MaxCommitTs uint64 `json:"MaxCommitTs"`
+}
+
+// Marshal marshals the complete marker.
+func (m *CompleteMarker) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, marshalPrefix, marshalIndent)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// IsCompleteMarkerFile checks whether the file is a complete marker file.
+func IsCompleteMarkerFile(filePath string) bool {
+	return path.Base(filePath) == completeMarkerFileName
+}
+
+// GenerateCompleteMarkerPath generates the path of the complete marker file
+// of the date partition of the table.
+func (f *FilePathGenerator) GenerateCompleteMarkerPath(tbl VersionedTableName, date string) string {
+	return path.Join(f.generateDataDirPath(tbl, date), completeMarkerFileName)
+}
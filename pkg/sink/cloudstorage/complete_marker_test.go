@@ -0,0 +1,49 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/engine/pkg/clock"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCompleteMarkerPath(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	f := testFilePathGenerator(ctx, t, t.TempDir())
+	f.config.DateSeparator = config.DateSeparatorDay.String()
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2023, 3, 9, 23, 59, 59, 0, time.UTC))
+	f.SetClock(mockClock)
+
+	table := VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{Schema: "test", Table: "table1"},
+		TableInfoVersion:           5,
+	}
+	f.versionMap[table] = table.TableInfoVersion
+	path := f.GenerateCompleteMarkerPath(table, f.GenerateDateStr())
+	require.Equal(t, "test/table1/5/2023-03-09/_SUCCESS", path)
+	require.True(t, IsCompleteMarkerFile(path))
+
+	require.False(t, IsCompleteMarkerFile("test/table1/5/2023-03-09/CDC000001.csv"))
+}
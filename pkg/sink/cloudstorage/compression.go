@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// CompressionExtension returns the suffix which is appended to the extension of
+// the data files compressed by the compression codec, such as ".gz" for gzip.
+func CompressionExtension(compression string) string {
+	switch compression {
+	case config.FileCompressionGzip:
+		return ".gz"
+	case config.FileCompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// Compress compresses the content of a data file with the compression codec,
+// the content is returned as it is if the codec is none.
+func Compress(compression string, data []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+		err error
+	)
+	switch compression {
+	case config.FileCompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case config.FileCompressionZstd:
+		w, err = zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+		}
+	default:
+		return data, nil
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	if err = w.Close(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the content of a data file compressed by Compress.
+func Decompress(compression string, data []byte) ([]byte, error) {
+	var r io.Reader
+	switch compression {
+	case config.FileCompressionGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+		defer gr.Close()
+		r = gr
+	case config.FileCompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return data, nil
+	}
+
+	result, err := io.ReadAll(r)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	return result, nil
+}
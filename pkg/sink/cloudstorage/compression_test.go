@@ -0,0 +1,54 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte(`"Insert","t","test",1,"hello world"`+"\n"), 1024)
+	cases := []struct {
+		compression string
+		extension   string
+	}{
+		{config.FileCompressionNone, ""},
+		{config.FileCompressionGzip, ".gz"},
+		{config.FileCompressionZstd, ".zst"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.extension, CompressionExtension(c.compression))
+
+		compressed, err := Compress(c.compression, data)
+		require.NoError(t, err)
+		if c.compression == config.FileCompressionNone {
+			require.Equal(t, data, compressed)
+		} else {
+			require.Less(t, len(compressed), len(data))
+		}
+
+		decompressed, err := Decompress(c.compression, compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+	}
+
+	_, err := Decompress(config.FileCompressionGzip, data)
+	require.Error(t, err)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/imdario/mergo"
 	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	psink "github.com/pingcap/tiflow/pkg/sink"
@@ -48,6 +49,10 @@ const (
 	minFileSize = 1024 * 1024
 	// the upper limit of file size
 	maxFileSize = 512 * 1024 * 1024
+	// defaultParquetRowGroupSize is the default value of parquet-row-group-size.
+	defaultParquetRowGroupSize = 64 * 1024 * 1024
+	// the lower limit of parquet row group size.
+	minParquetRowGroupSize = 1024 * 1024
 )
 
 type urlConfig struct {
@@ -62,9 +67,28 @@ type Config struct {
 	FlushInterval            time.Duration
 	FileSize                 int
 	FileIndexWidth           int
+	FileNameTemplate         string
 	DateSeparator            string
+	PartitionLayout          string
 	EnablePartitionSeparator bool
 	OutputColumnID           bool
+	OutputSchemaSidecar      bool
+	OutputManifest           bool
+	OutputCompleteMarker     bool
+	ParquetCompression       string
+	ParquetRowGroupSize      int
+	Compression              string
+	TableOverrides           []*TableOverride
+}
+
+// TableOverride overrides the flush interval and the file size of the tables
+// matched by the table matcher.
+type TableOverride struct {
+	TableMatcher tfilter.Filter
+	// FlushInterval is zero if it's not overridden.
+	FlushInterval time.Duration
+	// FileSize is zero if it's not overridden.
+	FileSize int
 }
 
 // NewConfig returns the default cloud storage sink config.
@@ -73,6 +97,11 @@ func NewConfig() *Config {
 		WorkerCount:   defaultWorkerCount,
 		FlushInterval: defaultFlushInterval,
 		FileSize:      defaultFileSize,
+
+		FileNameTemplate:    config.DefaultFileNameTemplate,
+		ParquetCompression:  config.ParquetCompressionSnappy,
+		ParquetRowGroupSize: defaultParquetRowGroupSize,
+		Compression:         config.FileCompressionNone,
 	}
 }
 
@@ -113,10 +142,44 @@ func (c *Config) Apply(
 	}
 
 	c.DateSeparator = util.GetOrZero(replicaConfig.Sink.DateSeparator)
+	c.PartitionLayout = util.GetOrZero(replicaConfig.Sink.PartitionLayout)
 	c.EnablePartitionSeparator = util.GetOrZero(replicaConfig.Sink.EnablePartitionSeparator)
 	c.FileIndexWidth = util.GetOrZero(replicaConfig.Sink.FileIndexWidth)
 	if replicaConfig.Sink.CloudStorageConfig != nil {
 		c.OutputColumnID = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.OutputColumnID)
+		if template := util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.FileNameTemplate); template != "" {
+			c.FileNameTemplate = template
+		}
+		c.OutputManifest = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.OutputManifest)
+		c.OutputCompleteMarker = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.OutputCompleteMarker)
+		err = c.applyParquetConfig(replicaConfig.Sink.CloudStorageConfig)
+		if err != nil {
+			return err
+		}
+		err = c.applyCompression(replicaConfig.Sink.CloudStorageConfig.Compression,
+			util.GetOrZero(replicaConfig.Sink.Protocol))
+		if err != nil {
+			return err
+		}
+		err = c.applyTableOverrides(replicaConfig.Sink.CloudStorageConfig.TableOverrides,
+			replicaConfig.CaseSensitive)
+		if err != nil {
+			return err
+		}
+	}
+	// the sidecar schema file is only available for the csv protocol, since its
+	// name may conflict with the data files of the json based protocols.
+	if replicaConfig.Sink.CSVConfig != nil &&
+		util.GetOrZero(replicaConfig.Sink.Protocol) == config.ProtocolCsv.String() {
+		c.OutputSchemaSidecar = replicaConfig.Sink.CSVConfig.OutputSchemaSidecar
+	}
+
+	// the partitions are completed by the date, so the marker can't be written
+	// if the data files aren't separated by the date.
+	if c.OutputCompleteMarker &&
+		(c.DateSeparator == "" || c.DateSeparator == config.DateSeparatorNone.String()) {
+		return cerror.ErrStorageSinkInvalidConfig.GenWithStack(
+			"output-complete-marker requires the date-separator to be year, month or day")
 	}
 
 	if c.FileIndexWidth < config.MinFileIndexWidth || c.FileIndexWidth > config.MaxFileIndexWidth {
@@ -126,6 +189,95 @@ func (c *Config) Apply(
 	return nil
 }
 
+func (c *Config) applyParquetConfig(cfg *config.CloudStorageConfig) error {
+	if cfg.ParquetCompression != nil {
+		switch compression := strings.ToLower(*cfg.ParquetCompression); compression {
+		case config.ParquetCompressionNone, config.ParquetCompressionSnappy,
+			config.ParquetCompressionZstd:
+			c.ParquetCompression = compression
+		default:
+			return cerror.ErrStorageSinkInvalidConfig.GenWithStack(
+				"invalid parquet-compression %s, it must be one of %s, %s or %s",
+				*cfg.ParquetCompression, config.ParquetCompressionNone,
+				config.ParquetCompressionSnappy, config.ParquetCompressionZstd)
+		}
+	}
+	if cfg.ParquetRowGroupSize != nil {
+		size := *cfg.ParquetRowGroupSize
+		if size < minParquetRowGroupSize {
+			log.Warn("parquet-row-group-size is too small",
+				zap.Int("original", size), zap.Int("override", minParquetRowGroupSize))
+			size = minParquetRowGroupSize
+		}
+		c.ParquetRowGroupSize = size
+	}
+	return nil
+}
+
+func (c *Config) applyCompression(compression *string, protocol string) error {
+	if compression == nil {
+		return nil
+	}
+	switch codec := strings.ToLower(*compression); codec {
+	case "", config.FileCompressionNone:
+		return nil
+	case config.FileCompressionGzip, config.FileCompressionZstd:
+		// the parquet files are compressed by parquet-compression.
+		if protocol == config.ProtocolParquet.String() {
+			return cerror.ErrStorageSinkInvalidConfig.GenWithStack(
+				"compression %s is not supported by the parquet protocol, "+
+					"please use parquet-compression instead", *compression)
+		}
+		c.Compression = codec
+		return nil
+	default:
+		return cerror.ErrStorageSinkInvalidConfig.GenWithStack(
+			"invalid compression %s, it must be one of %s, %s or %s",
+			*compression, config.FileCompressionNone,
+			config.FileCompressionGzip, config.FileCompressionZstd)
+	}
+}
+
+func (c *Config) applyTableOverrides(
+	overrides []*config.CloudStorageTableOverride, caseSensitive bool,
+) error {
+	for _, o := range overrides {
+		if len(o.Matcher) == 0 {
+			return cerror.ErrStorageSinkInvalidConfig.GenWithStack(
+				"the matcher of the table override is empty")
+		}
+		tableMatcher, err := tfilter.Parse(o.Matcher)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+		}
+		if !caseSensitive {
+			tableMatcher = tfilter.CaseInsensitive(tableMatcher)
+		}
+		override := &TableOverride{TableMatcher: tableMatcher}
+		// The overridden values are limited in the same ranges as the global ones.
+		values := &urlConfig{FlushInterval: o.FlushInterval, FileSize: o.FileSize}
+		if err = getFlushInterval(values, &override.FlushInterval); err != nil {
+			return err
+		}
+		if err = getFileSize(values, &override.FileSize); err != nil {
+			return err
+		}
+		c.TableOverrides = append(c.TableOverrides, override)
+	}
+	return nil
+}
+
+// MatchTableOverride returns the first table override which matches the table,
+// nil is returned if there is no matched one.
+func (c *Config) MatchTableOverride(schema, table string) *TableOverride {
+	for _, o := range c.TableOverrides {
+		if o.TableMatcher.MatchTable(schema, table) {
+			return o
+		}
+	}
+	return nil
+}
+
 func mergeConfig(
 	replicaConfig *config.ReplicaConfig,
 	urlParameters *urlConfig,
@@ -164,3 +164,119 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, 33554432, c.FileSize)
 	require.Equal(t, "2m2s", c.FlushInterval.String())
 }
+
+func TestApplyParquetConfig(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=parquet")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	c := NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, config.ParquetCompressionSnappy, c.ParquetCompression)
+	require.Equal(t, defaultParquetRowGroupSize, c.ParquetRowGroupSize)
+
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		ParquetCompression:  aws.String("ZSTD"),
+		ParquetRowGroupSize: aws.Int(1024),
+	}
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, config.ParquetCompressionZstd, c.ParquetCompression)
+	require.Equal(t, minParquetRowGroupSize, c.ParquetRowGroupSize)
+
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		ParquetCompression: aws.String("lz4"),
+	}
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "invalid parquet-compression lz4")
+}
+
+func TestApplyTableOverrides(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv&flush-interval=10s")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.CaseSensitive = false
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		TableOverrides: []*config.CloudStorageTableOverride{
+			{Matcher: []string{"test.hot*"}, FileSize: aws.Int(256 * 1024 * 1024)},
+			{Matcher: []string{"test.*"}, FlushInterval: aws.String("1s")},
+		},
+	}
+	c := NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, c.FlushInterval)
+	require.Len(t, c.TableOverrides, 2)
+
+	override := c.MatchTableOverride("TEST", "hot_orders")
+	require.Equal(t, c.TableOverrides[0], override)
+	require.Equal(t, time.Duration(0), override.FlushInterval)
+	require.Equal(t, 256*1024*1024, override.FileSize)
+
+	// the flush interval is limited to the lower limit.
+	override = c.MatchTableOverride("test", "dim")
+	require.Equal(t, c.TableOverrides[1], override)
+	require.Equal(t, minFlushInterval, override.FlushInterval)
+	require.Equal(t, 0, override.FileSize)
+
+	require.Nil(t, c.MatchTableOverride("other", "t"))
+
+	replicaConfig.Sink.CloudStorageConfig.TableOverrides = []*config.CloudStorageTableOverride{
+		{FlushInterval: aws.String("1s")},
+	}
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "the matcher of the table override is empty")
+}
+
+func TestApplyCompression(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	c := NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, config.FileCompressionNone, c.Compression)
+
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		Compression: aws.String("GZIP"),
+	}
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, config.FileCompressionGzip, c.Compression)
+
+	replicaConfig.Sink.CloudStorageConfig.Compression = aws.String("lz4")
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "invalid compression lz4")
+
+	// the parquet files are compressed by parquet-compression.
+	replicaConfig.Sink.Protocol = aws.String(config.ProtocolParquet.String())
+	replicaConfig.Sink.CloudStorageConfig.Compression = aws.String("zstd")
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "not supported by the parquet protocol")
+}
+
+func TestApplyCompleteMarker(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DateSeparator = aws.String(config.DateSeparatorDay.String())
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		OutputCompleteMarker: aws.Bool(true),
+	}
+	c := NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.True(t, c.OutputCompleteMarker)
+
+	// the partitions can't be completed without the date separator.
+	replicaConfig.Sink.DateSeparator = aws.String(config.DateSeparatorNone.String())
+	c = NewConfig()
+	err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "output-complete-marker requires the date-separator")
+}
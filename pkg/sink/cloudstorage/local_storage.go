@@ -0,0 +1,325 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	goerrors "errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	mcloudstorage "github.com/pingcap/tiflow/cdc/sink/metrics/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/fsutil"
+	psink "github.com/pingcap/tiflow/pkg/sink"
+	putil "github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// reservedDiskSpace is the disk space kept free by the local storage,
+	// writes are blocked until there is enough space beyond it.
+	reservedDiskSpace = 64 * 1024 * 1024
+	// diskSpaceCheckInterval is the interval to check the available disk space
+	// when the writes are blocked because of insufficient disk space.
+	diskSpaceCheckInterval = 5 * time.Second
+
+	localFilePerm = 0o644
+	localDirPerm  = 0o755
+
+	// tempFileSuffix is appended to the name of the target file, and followed
+	// by a random number, to name the temporary file.
+	tempFileSuffix = ".tmp."
+	// staleTempFileAge is the age of the temporary files which are removed
+	// as stale ones when the storage is created.
+	staleTempFileAge = time.Minute
+)
+
+var tempFileRegex = regexp.MustCompile(`\.tmp\.\d+$`)
+
+// GetExternalStorage creates the external storage for the cloud storage sink.
+// For the local file system, the storage is wrapped to guarantee the files are
+// durable once written, and to block the writes rather than lose data when the
+// disk is full. The temporary files left by the last run are removed.
+func GetExternalStorage(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+) (storage.ExternalStorage, error) {
+	s, err := putil.GetExternalStorageFromURI(ctx, sinkURI.String())
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(sinkURI.Scheme) != psink.FileScheme {
+		return s, nil
+	}
+	backend, err := storage.ParseBackend(sinkURI.String(), nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	base := backend.GetLocal().GetPath()
+	removeStaleTempFiles(changefeedID, base)
+	return newLocalStorage(changefeedID, base, s), nil
+}
+
+// localStorage wraps the local file system storage. It fsyncs the file before
+// renaming it to the target path, and fsyncs the parent directory after that.
+type localStorage struct {
+	storage.ExternalStorage
+
+	changefeedID model.ChangeFeedID
+	base         string
+	// used for test purpose.
+	getAvailableSpace func(dir string) (uint64, error)
+
+	metricAvailableSpace prometheus.Gauge
+}
+
+func newLocalStorage(
+	changefeedID model.ChangeFeedID, base string, s storage.ExternalStorage,
+) *localStorage {
+	return &localStorage{
+		ExternalStorage:   s,
+		changefeedID:      changefeedID,
+		base:              base,
+		getAvailableSpace: fsutil.GetAvailableSpace,
+		metricAvailableSpace: mcloudstorage.CloudStorageDiskAvailableBytesGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// WriteFile writes the data to the file atomically and durably. If the disk
+// is full, it blocks until there is enough space or the context is canceled.
+func (s *localStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	path := filepath.Join(s.base, name)
+	for {
+		if err := s.waitForSpace(ctx, uint64(len(data))); err != nil {
+			return err
+		}
+		err := writeFileDurably(path, data)
+		if err == nil {
+			return nil
+		}
+		if !goerrors.Is(errors.Cause(err), syscall.ENOSPC) {
+			return errors.Trace(err)
+		}
+		log.Warn("no space left on device when writing file, retry later",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.String("path", path))
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-time.After(diskSpaceCheckInterval):
+		}
+	}
+}
+
+// waitForSpace blocks until the available disk space is enough to write
+// the given size of data.
+func (s *localStorage) waitForSpace(ctx context.Context, size uint64) error {
+	warned := false
+	for {
+		available, err := s.getAvailableSpace(s.base)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.metricAvailableSpace.Set(float64(available))
+		if available >= size+reservedDiskSpace {
+			if warned {
+				log.Info("disk space is enough, resume writing files",
+					zap.String("namespace", s.changefeedID.Namespace),
+					zap.String("changefeed", s.changefeedID.ID),
+					zap.Uint64("available", available))
+			}
+			return nil
+		}
+		if !warned {
+			log.Warn("disk space is insufficient, writing files is blocked",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.String("path", s.base),
+				zap.Uint64("available", available),
+				zap.Uint64("required", size+reservedDiskSpace))
+			warned = true
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-time.After(diskSpaceCheckInterval):
+		}
+	}
+}
+
+// Create creates a writer of the file, the data is written to a temporary file
+// which is renamed to the target path once the writer is closed, the same as
+// WriteFile. The writes are blocked if the disk is full.
+func (s *localStorage) Create(_ context.Context, name string) (storage.ExternalFileWriter, error) {
+	path := filepath.Join(s.base, name)
+	tmpFile, err := createTempFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &localFileWriter{storage: s, path: path, tmpFile: tmpFile}, nil
+}
+
+// localFileWriter writes the data to a temporary file, and renames it to the
+// target path after it's synced when the writer is closed.
+type localFileWriter struct {
+	storage *localStorage
+	path    string
+	tmpFile *os.File
+}
+
+// Write implements storage.ExternalFileWriter.
+func (w *localFileWriter) Write(ctx context.Context, p []byte) (int, error) {
+	if err := w.storage.waitForSpace(ctx, uint64(len(p))); err != nil {
+		return 0, err
+	}
+	n, err := w.tmpFile.Write(p)
+	if err != nil {
+		removeTempFile(w.tmpFile)
+		return n, errors.Trace(err)
+	}
+	return n, nil
+}
+
+// Close implements storage.ExternalFileWriter.
+func (w *localFileWriter) Close(_ context.Context) error {
+	return commitTempFile(w.tmpFile, w.path)
+}
+
+// writeFileDurably writes the data to a temporary file and renames it to the
+// target path after it's synced, so that a crash never leaves a partial file.
+func writeFileDurably(path string, data []byte) error {
+	tmpFile, err := createTempFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		removeTempFile(tmpFile)
+		return errors.Trace(err)
+	}
+	return commitTempFile(tmpFile, path)
+}
+
+// createTempFile creates the temporary file in the directory of the path.
+func createTempFile(path string) (*os.File, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, localDirPerm); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+tempFileSuffix+"*")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tmpFile, nil
+}
+
+// commitTempFile syncs the temporary file and renames it to the path, the
+// temporary file is removed if it fails.
+func commitTempFile(tmpFile *os.File, path string) (err error) {
+	defer func() {
+		if err != nil {
+			removeTempFile(tmpFile)
+		}
+	}()
+	if err = tmpFile.Chmod(localFilePerm); err != nil {
+		return errors.Trace(err)
+	}
+	if err = tmpFile.Sync(); err != nil {
+		return errors.Trace(err)
+	}
+	if err = tmpFile.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		return errors.Trace(err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func removeTempFile(tmpFile *os.File) {
+	_ = tmpFile.Close()
+	_ = os.Remove(tmpFile.Name())
+}
+
+// removeStaleTempFiles removes the temporary files left by the writes which
+// are interrupted by a crash. The files modified recently are kept, since they
+// may be written by other processes sharing the directory.
+func removeStaleTempFiles(changefeedID model.ChangeFeedID, base string) {
+	deadline := time.Now().Add(-staleTempFileAge)
+	removed := 0
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !tempFileRegex.MatchString(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.ModTime().After(deadline) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		log.Warn("remove stale temporary files failed",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("path", base),
+			zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		log.Info("stale temporary files are removed",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("path", base),
+			zap.Int("count", removed))
+	}
+}
+
+// syncDir fsyncs the directory to make sure the rename is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer d.Close()
+	return errors.Trace(d.Sync())
+}
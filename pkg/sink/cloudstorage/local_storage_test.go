@@ -0,0 +1,156 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorageWriteFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("file:///%s?protocol=csv", dir))
+	require.NoError(t, err)
+
+	s, err := GetExternalStorage(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+	require.IsType(t, &localStorage{}, s)
+
+	name := "test/table1/434248585957900289/CDC000001.csv"
+	err = s.WriteFile(ctx, name, []byte("hello"))
+	require.NoError(t, err)
+	// overwrite the existing file.
+	err = s.WriteFile(ctx, name, []byte("world"))
+	require.NoError(t, err)
+
+	data, err := s.ReadFile(ctx, name)
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), data)
+
+	// no temporary file is left.
+	entries, err := os.ReadDir(filepath.Dir(filepath.Join(dir, name)))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "CDC000001.csv", entries[0].Name())
+}
+
+func TestLocalStorageBlockOnDiskFull(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("file:///%s?protocol=csv", dir))
+	require.NoError(t, err)
+
+	s, err := GetExternalStorage(context.Background(), model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+	ls := s.(*localStorage)
+	ls.getAvailableSpace = func(string) (uint64, error) {
+		return reservedDiskSpace, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = ls.WriteFile(ctx, "test/table1/CDC000001.csv", []byte("hello"))
+	require.ErrorIs(t, errors.Cause(err), context.DeadlineExceeded)
+
+	// the file is not written.
+	_, err = os.Stat(filepath.Join(dir, "test/table1/CDC000001.csv"))
+	require.True(t, os.IsNotExist(err))
+
+	ls.getAvailableSpace = func(string) (uint64, error) {
+		return 0, errors.New("statfs failed")
+	}
+	err = ls.WriteFile(context.Background(), "test/table1/CDC000001.csv", []byte("hello"))
+	require.ErrorContains(t, err, "statfs failed")
+}
+
+func TestLocalStorageCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	sinkURI, err := url.Parse(fmt.Sprintf("file:///%s?protocol=csv", dir))
+	require.NoError(t, err)
+	s, err := GetExternalStorage(ctx, model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+
+	name := "test/table1/434248585957900289/CDC000001.csv"
+	path := filepath.Join(dir, name)
+	writer, err := s.Create(ctx, name)
+	require.NoError(t, err)
+	_, err = writer.Write(ctx, []byte("hello "))
+	require.NoError(t, err)
+	_, err = writer.Write(ctx, []byte("world"))
+	require.NoError(t, err)
+
+	// the file is invisible until the writer is closed.
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, writer.Close(ctx))
+
+	data, err := s.ReadFile(ctx, name)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello world"), data)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "CDC000001.csv", entries[0].Name())
+}
+
+func TestLocalStorageRemoveStaleTempFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tableDir := filepath.Join(dir, "test/table1/434248585957900289")
+	require.NoError(t, os.MkdirAll(tableDir, localDirPerm))
+	files := map[string]time.Time{
+		"CDC000001.csv":                time.Now().Add(-time.Hour),
+		"CDC000002.csv.tmp.1234567":    time.Now().Add(-time.Hour),
+		"CDC000003.csv.tmp.7654321":    time.Now(),
+		"CDC000004.csv.tmp.not-random": time.Now().Add(-time.Hour),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(tableDir, name)
+		require.NoError(t, os.WriteFile(path, []byte("hello"), localFilePerm))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	sinkURI, err := url.Parse(fmt.Sprintf("file:///%s?protocol=csv", dir))
+	require.NoError(t, err)
+	_, err = GetExternalStorage(context.Background(), model.DefaultChangeFeedID("test"), sinkURI)
+	require.NoError(t, err)
+
+	// only the stale temporary file is removed.
+	entries, err := os.ReadDir(tableDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.Equal(t, []string{
+		"CDC000001.csv", "CDC000003.csv.tmp.7654321", "CDC000004.csv.tmp.not-random",
+	}, names)
+}
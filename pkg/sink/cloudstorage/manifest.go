@@ -0,0 +1,91 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The manifest files are stored in the following path:
+// manifest/CDC_{unixNano}_{workerID}.json
+const manifestFileNameFormat = "manifest/CDC_%019d_%d.json"
+
+var manifestRE = regexp.MustCompile(`^manifest/CDC_\d{19}_\d+\.json$`)
+
+// IsManifestFile checks whether the file is a manifest file.
+func IsManifestFile(path string) bool {
+	return manifestRE.MatchString(path)
+}
+
+// Manifest lists the data files written by a flush of a dml worker. It's
+// written after all the data files are written and before their events are
+// acknowledged, so the downstream loaders can regard a data file which isn't
+// listed in any manifest as a partially written one.
+type Manifest struct {
+	Files []ManifestFile `json:"Files"`
+}
+
+// ManifestFile describes a data file in the manifest.
+type ManifestFile struct {
+	// Path is relative to the sink URI.
+	Path        string `json:"Path"`
+	Schema      string `json:"Schema"`
+	Table       string `json:"Table"`
+	RowCount    int    `json:"RowCount"`
+	Size        int    `json:"Size"`
+	MinCommitTs uint64 `json:"MinCommitTs"`
+	MaxCommitTs uint64 `json:"MaxCommitTs"`
+	// SHA256 is the hex encoded checksum of the file content, which is
+	// computed after the file is compressed.
+	SHA256 string `json:"SHA256"`
+}
+
+// AddFile adds the data file to the manifest.
+func (m *Manifest) AddFile(
+	path string, table VersionedTableName, data []byte,
+	rowCount int, minCommitTs, maxCommitTs uint64,
+) {
+	checksum := sha256.Sum256(data)
+	m.Files = append(m.Files, ManifestFile{
+		Path:        path,
+		Schema:      table.TableNameWithPhysicTableID.Schema,
+		Table:       table.TableNameWithPhysicTableID.Table,
+		RowCount:    rowCount,
+		Size:        len(data),
+		MinCommitTs: minCommitTs,
+		MaxCommitTs: maxCommitTs,
+		SHA256:      hex.EncodeToString(checksum[:]),
+	})
+}
+
+// Marshal marshals the manifest.
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, marshalPrefix, marshalIndent)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// GenerateManifestFilePath generates the path of the manifest file written by
+// the worker. The paths are ordered by the time they're generated.
+func (f *FilePathGenerator) GenerateManifestFilePath(workerID int) string {
+	return fmt.Sprintf(manifestFileNameFormat, f.clock.Now().UnixNano(), workerID)
+}
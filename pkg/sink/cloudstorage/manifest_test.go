@@ -0,0 +1,41 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/engine/pkg/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateManifestFilePath(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	f := testFilePathGenerator(ctx, t, t.TempDir())
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Unix(1700000000, 123))
+	f.SetClock(mockClock)
+	path := f.GenerateManifestFilePath(3)
+	require.Equal(t, "manifest/CDC_1700000000000000123_3.json", path)
+	require.True(t, IsManifestFile(path))
+
+	require.False(t, IsManifestFile("manifest/table1/1/CDC000001.json"))
+	require.False(t, IsManifestFile("test/manifest/CDC_1700000000000000123_3.json"))
+}
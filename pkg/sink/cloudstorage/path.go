@@ -33,10 +33,10 @@ import (
 )
 
 const (
-	// 3 is the length of "CDC", and the file number contains
-	// at least 6 digits (e.g. CDC000001.csv).
-	minFileNamePrefixLen = 3 + config.MinFileIndexWidth
 	defaultIndexFileName = "meta/CDC.index"
+	// hiveDatePrefix is the prefix of the date directories in the hive
+	// partition layout, e.g. dt=2024-05-01.
+	hiveDatePrefix = "dt="
 
 	// The following constants are used to generate file paths.
 	schemaFileNameFormat = "schema_%d_%010d.json"
@@ -46,6 +46,9 @@ const (
 	// The table schema is stored in the following path:
 	// <schema>/<table>/meta/schema_{tableVersion}_{checksum}.json
 	tableSchemaPrefix = "%s/%s/meta/"
+	// The sidecar schema file is stored along with the data files:
+	// <schema>/<table>/<tableVersion>/schema.json
+	schemaSidecarFileName = "schema.json"
 )
 
 var schemaRE = regexp.MustCompile(`meta/schema_\d+_\d{10}\.json$`)
@@ -135,6 +138,8 @@ type FilePathGenerator struct {
 
 	hasher     *hash.PositionInertia
 	versionMap map[VersionedTableName]uint64
+	// sidecarMap records the tables whose sidecar schema file is written.
+	sidecarMap map[VersionedTableName]struct{}
 }
 
 // NewFilePathGenerator creates a FilePathGenerator.
@@ -152,6 +157,7 @@ func NewFilePathGenerator(
 		fileIndex:  make(map[VersionedTableName]*indexWithDate),
 		hasher:     hash.NewPositionInertia(),
 		versionMap: make(map[VersionedTableName]uint64),
+		sidecarMap: make(map[VersionedTableName]struct{}),
 	}
 }
 
@@ -239,13 +245,61 @@ func (f *FilePathGenerator) CheckOrWriteSchema(
 	return f.storage.WriteFile(ctx, tblSchemaFile, encodedDetail)
 }
 
+// CheckOrWriteSchemaSidecar checks whether the sidecar schema file exists in the
+// data directory of the table version and writes it if necessary. It should be
+// called after CheckOrWriteSchema, since the version of the data directory is
+// determined there.
+func (f *FilePathGenerator) CheckOrWriteSchemaSidecar(
+	ctx context.Context,
+	table VersionedTableName,
+	tableInfo *model.TableInfo,
+) error {
+	if !f.config.OutputSchemaSidecar {
+		return nil
+	}
+	if _, ok := f.sidecarMap[table]; ok {
+		return nil
+	}
+
+	sidecarPath := f.GenerateSchemaSidecarPath(table)
+	exist, err := f.storage.FileExists(ctx, sidecarPath)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		var def TableDefinition
+		def.FromTableInfo(tableInfo, f.versionMap[table], f.config.OutputColumnID)
+		encodedDetail, err := def.MarshalWithQuery()
+		if err != nil {
+			return err
+		}
+		if err := f.storage.WriteFile(ctx, sidecarPath, encodedDetail); err != nil {
+			return err
+		}
+	}
+	f.sidecarMap[table] = struct{}{}
+	return nil
+}
+
+// GenerateSchemaSidecarPath generates the path of the sidecar schema file,
+// which is located in the data directory of the table version.
+func (f *FilePathGenerator) GenerateSchemaSidecarPath(tbl VersionedTableName) string {
+	return path.Join(
+		tbl.TableNameWithPhysicTableID.Schema,
+		tbl.TableNameWithPhysicTableID.Table,
+		fmt.Sprintf("%d", f.versionMap[tbl]),
+		schemaSidecarFileName,
+	)
+}
+
 // SetClock is used for unit test
 func (f *FilePathGenerator) SetClock(clock clock.Clock) {
 	f.clock = clock
 }
 
 // GenerateDateStr generates a date string base on current time
-// and the date-separator configuration item.
+// and the date-separator configuration item. The date string is
+// prefixed with "dt=" if the hive partition layout is used.
 func (f *FilePathGenerator) GenerateDateStr() string {
 	var dateStr string
 
@@ -260,6 +314,9 @@ func (f *FilePathGenerator) GenerateDateStr() string {
 	default:
 	}
 
+	if len(dateStr) != 0 && f.config.PartitionLayout == config.PartitionLayoutHive {
+		dateStr = hiveDatePrefix + dateStr
+	}
 	return dateStr
 }
 
@@ -282,6 +339,15 @@ func (f *FilePathGenerator) GenerateDataFilePath(
 	return path.Join(dir, name), nil
 }
 
+// FileIndex returns the index of the last data file path generated for the
+// table, it's 0 if no data file path is generated.
+func (f *FilePathGenerator) FileIndex(tbl VersionedTableName) uint64 {
+	if idx, ok := f.fileIndex[tbl]; ok {
+		return idx.index
+	}
+	return 0
+}
+
 func (f *FilePathGenerator) generateDataDirPath(tbl VersionedTableName, date string) string {
 	var elems []string
 
@@ -323,7 +389,31 @@ func (f *FilePathGenerator) generateDataFileName(
 		f.fileIndex[tbl].index = 0
 	}
 	f.fileIndex[tbl].index++
-	return generateDataFileName(f.fileIndex[tbl].index, f.extension, f.config.FileIndexWidth), nil
+	return f.formatDataFileName(tbl, date, f.fileIndex[tbl].index), nil
+}
+
+// formatDataFileName formats the data file name by the file name template.
+func (f *FilePathGenerator) formatDataFileName(
+	tbl VersionedTableName, date string, index uint64,
+) string {
+	prefix, suffix := f.dataFileNameAffixes(tbl, date)
+	indexFmt := "%0" + strconv.Itoa(f.config.FileIndexWidth) + "d"
+	return prefix + fmt.Sprintf(indexFmt, index) + suffix
+}
+
+// dataFileNameAffixes returns the parts of the data file name before and after
+// the file index, which are rendered from the file name template.
+func (f *FilePathGenerator) dataFileNameAffixes(
+	tbl VersionedTableName, date string,
+) (string, string) {
+	replacer := strings.NewReplacer(
+		config.FileNamePlaceholderSchema, tbl.TableNameWithPhysicTableID.Schema,
+		config.FileNamePlaceholderTable, tbl.TableNameWithPhysicTableID.Table,
+		config.FileNamePlaceholderDate, strings.TrimPrefix(date, hiveDatePrefix),
+		config.FileNamePlaceholderExtension, f.extension,
+	)
+	prefix, suffix, _ := strings.Cut(f.config.FileNameTemplate, config.FileNamePlaceholderIndex)
+	return replacer.Replace(prefix), replacer.Replace(suffix)
 }
 
 func (f *FilePathGenerator) getNextFileIdxFromIndexFile(
@@ -343,14 +433,14 @@ func (f *FilePathGenerator) getNextFileIdxFromIndexFile(
 		return 0, err
 	}
 	fileName := strings.TrimSuffix(string(data), "\n")
-	maxFileIdx, err := f.fetchIndexFromFileName(fileName)
+	maxFileIdx, err := f.fetchIndexFromFileName(tbl, date, fileName)
 	if err != nil {
 		return 0, err
 	}
 
 	lastFilePath := path.Join(
-		f.generateDataDirPath(tbl, date),                                       // file dir
-		generateDataFileName(maxFileIdx, f.extension, f.config.FileIndexWidth), // file name
+		f.generateDataDirPath(tbl, date),            // file dir
+		f.formatDataFileName(tbl, date, maxFileIdx), // file name
 	)
 	var lastFileExists, lastFileIsEmpty bool
 	lastFileExists, err = f.storage.FileExists(ctx, lastFilePath)
@@ -383,19 +473,22 @@ func (f *FilePathGenerator) getNextFileIdxFromIndexFile(
 	return fileIdx, nil
 }
 
-func (f *FilePathGenerator) fetchIndexFromFileName(fileName string) (uint64, error) {
+func (f *FilePathGenerator) fetchIndexFromFileName(
+	tbl VersionedTableName, date, fileName string,
+) (uint64, error) {
 	var fileIdx uint64
 	var err error
 
-	if len(fileName) < minFileNamePrefixLen+len(f.extension) ||
-		!strings.HasPrefix(fileName, "CDC") ||
-		!strings.HasSuffix(fileName, f.extension) {
+	// the file number contains at least 6 digits (e.g. CDC000001.csv).
+	prefix, suffix := f.dataFileNameAffixes(tbl, date)
+	if len(fileName) < len(prefix)+config.MinFileIndexWidth+len(suffix) ||
+		!strings.HasPrefix(fileName, prefix) ||
+		!strings.HasSuffix(fileName, suffix) {
 		return 0, errors.WrapError(errors.ErrStorageSinkInvalidFileName,
 			fmt.Errorf("'%s' is a invalid file name", fileName))
 	}
 
-	extIdx := strings.Index(fileName, f.extension)
-	fileIdxStr := fileName[3:extIdx]
+	fileIdxStr := fileName[len(prefix) : len(fileName)-len(suffix)]
 	if fileIdx, err = strconv.ParseUint(fileIdxStr, 10, 64); err != nil {
 		return 0, errors.WrapError(errors.ErrStorageSinkInvalidFileName, err)
 	}
@@ -99,7 +99,8 @@ func (d *DmlPathKey) GenerateDMLFilePath(
 // ParseDMLFilePath parses the dml file path and returns the max file index.
 // DML file path pattern is as follows:
 // {schema}/{table}/{table-version-separator}/{partition-separator}/{date-separator}/, where
-// partition-separator and date-separator could be empty.
+// partition-separator and date-separator could be empty, and date-separator is
+// prefixed with "dt=" in the hive partition layout.
 // DML file name pattern is as follows: CDC{num}.extension.
 func (d *DmlPathKey) ParseDMLFilePath(dateSeparator, path string) (uint64, error) {
 	var partitionNum int64
@@ -109,11 +110,11 @@ func (d *DmlPathKey) ParseDMLFilePath(dateSeparator, path string) (uint64, error
 	case config.DateSeparatorNone.String():
 		str += `(\d{4})*`
 	case config.DateSeparatorYear.String():
-		str += `(\d{4})\/`
+		str += `((?:dt=)?\d{4})\/`
 	case config.DateSeparatorMonth.String():
-		str += `(\d{4}-\d{2})\/`
+		str += `((?:dt=)?\d{4}-\d{2})\/`
 	case config.DateSeparatorDay.String():
-		str += `(\d{4}-\d{2}-\d{2})\/`
+		str += `((?:dt=)?\d{4}-\d{2}-\d{2})\/`
 	}
 	str += `CDC(\d+).\w+`
 	pathRE, err := regexp.Compile(str)
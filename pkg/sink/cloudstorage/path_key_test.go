@@ -82,6 +82,21 @@ func TestDmlPathKey(t *testing.T) {
 				Date:         "2023-05-09",
 			},
 		},
+		{
+			index:          11,
+			fileIndexWidth: 6,
+			extension:      ".json",
+			path:           "schema1/table1/123456/dt=2023-05-09/CDC000011.json",
+			dmlkey: DmlPathKey{
+				SchemaPathKey: SchemaPathKey{
+					Schema:       "schema1",
+					Table:        "table1",
+					TableVersion: 123456,
+				},
+				PartitionNum: 0,
+				Date:         "dt=2023-05-09",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -15,11 +15,15 @@ package cloudstorage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"testing"
 	"time"
 
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/engine/pkg/clock"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -143,6 +147,26 @@ func TestGenerateDataFilePath(t *testing.T) {
 	path, err = f.GenerateDataFilePath(ctx, table, date)
 	require.NoError(t, err)
 	require.Equal(t, "test/table1/5/2023-01-01/CDC000002.json", path)
+
+	// date-separator: day, partition-layout: hive
+	mockClock = clock.NewMock()
+	f = testFilePathGenerator(ctx, t, dir)
+	f.versionMap[table] = table.TableInfoVersion
+	f.config.DateSeparator = config.DateSeparatorDay.String()
+	f.config.PartitionLayout = config.PartitionLayoutHive
+	f.clock = mockClock
+	mockClock.Set(time.Date(2023, 1, 2, 0, 0, 20, 0, time.UTC))
+	date = f.GenerateDateStr()
+	require.Equal(t, "dt=2023-01-02", date)
+	path, err = f.GenerateDataFilePath(ctx, table, date)
+	require.NoError(t, err)
+	require.Equal(t, "test/table1/5/dt=2023-01-02/CDC000001.json", path)
+	require.Equal(t, "test/table1/5/dt=2023-01-02/meta/CDC.index",
+		f.GenerateIndexFilePath(table, date))
+
+	// the hive partition layout doesn't take effect without date-separator.
+	f.config.DateSeparator = config.DateSeparatorNone.String()
+	require.Equal(t, "", f.GenerateDateStr())
 }
 
 func TestFetchIndexFromFileName(t *testing.T) {
@@ -153,6 +177,12 @@ func TestFetchIndexFromFileName(t *testing.T) {
 
 	dir := t.TempDir()
 	f := testFilePathGenerator(ctx, t, dir)
+	table := VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{
+			Schema: "test",
+			Table:  "table1",
+		},
+	}
 	testCases := []struct {
 		fileName string
 		wantErr  string
@@ -184,7 +214,7 @@ func TestFetchIndexFromFileName(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		_, err := f.fetchIndexFromFileName(tc.fileName)
+		_, err := f.fetchIndexFromFileName(table, "", tc.fileName)
 		if len(tc.wantErr) != 0 {
 			require.Contains(t, err.Error(), tc.wantErr)
 		} else {
@@ -193,6 +223,54 @@ func TestFetchIndexFromFileName(t *testing.T) {
 	}
 }
 
+func TestGenerateDataFilePathWithFileNameTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	table := VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{
+			Schema: "test",
+			Table:  "table1",
+		},
+		TableInfoVersion: 5,
+	}
+	dir := t.TempDir()
+	f := testFilePathGenerator(ctx, t, dir)
+	f.versionMap[table] = table.TableInfoVersion
+	f.config.FileNameTemplate = "{schema}.{table}_{date}_part{index}{extension}"
+	f.config.DateSeparator = config.DateSeparatorDay.String()
+	f.config.PartitionLayout = config.PartitionLayoutHive
+	mockClock := clock.NewMock()
+	f.clock = mockClock
+	mockClock.Set(time.Date(2023, 3, 9, 23, 59, 59, 0, time.UTC))
+	date := f.GenerateDateStr()
+	path, err := f.GenerateDataFilePath(ctx, table, date)
+	require.NoError(t, err)
+	require.Equal(t, "test/table1/5/dt=2023-03-09/test.table1_2023-03-09_part000001.json", path)
+
+	// the file index is recovered from the index file.
+	indexFilePath := f.GenerateIndexFilePath(table, date)
+	err = f.storage.WriteFile(ctx, indexFilePath, []byte("test.table1_2023-03-09_part000005.json\n"))
+	require.NoError(t, err)
+	err = f.storage.WriteFile(ctx, "test/table1/5/dt=2023-03-09/test.table1_2023-03-09_part000005.json",
+		[]byte("test"))
+	require.NoError(t, err)
+	f = testFilePathGenerator(ctx, t, dir)
+	f.versionMap[table] = table.TableInfoVersion
+	f.config.FileNameTemplate = "{schema}.{table}_{date}_part{index}{extension}"
+	f.config.DateSeparator = config.DateSeparatorDay.String()
+	f.config.PartitionLayout = config.PartitionLayoutHive
+	f.clock = mockClock
+	path, err = f.GenerateDataFilePath(ctx, table, date)
+	require.NoError(t, err)
+	require.Equal(t, "test/table1/5/dt=2023-03-09/test.table1_2023-03-09_part000006.json", path)
+
+	_, err = f.fetchIndexFromFileName(table, date, "CDC000001.json")
+	require.ErrorContains(t, err, "filename in storage sink is invalid")
+}
+
 func TestGenerateDataFilePathWithIndexFile(t *testing.T) {
 	t.Parallel()
 
@@ -275,3 +353,52 @@ func TestIsSchemaFile(t *testing.T) {
 			"testCase: %s, path: %v", tt.name, tt.path)
 	}
 }
+
+func TestCheckOrWriteSchemaSidecar(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	table := VersionedTableName{
+		TableNameWithPhysicTableID: model.TableName{
+			Schema: "test",
+			Table:  "table1",
+		},
+		TableInfoVersion: 5,
+	}
+	tableInfo := &model.TableInfo{
+		TableName: table.TableNameWithPhysicTableID,
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("table1"),
+			Columns: []*timodel.ColumnInfo{
+				{
+					Name:      timodel.NewCIStr("id"),
+					FieldType: *types.NewFieldType(mysql.TypeLong),
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	f := testFilePathGenerator(ctx, t, dir)
+	f.versionMap[table] = table.TableInfoVersion
+	// the sidecar schema file is not written if it's disabled.
+	require.NoError(t, f.CheckOrWriteSchemaSidecar(ctx, table, tableInfo))
+	sidecarPath := f.GenerateSchemaSidecarPath(table)
+	require.Equal(t, "test/table1/5/schema.json", sidecarPath)
+	exist, err := f.storage.FileExists(ctx, sidecarPath)
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	f.config.OutputSchemaSidecar = true
+	require.NoError(t, f.CheckOrWriteSchemaSidecar(ctx, table, tableInfo))
+	data, err := f.storage.ReadFile(ctx, sidecarPath)
+	require.NoError(t, err)
+	var def TableDefinition
+	require.NoError(t, json.Unmarshal(data, &def))
+	require.Equal(t, uint64(5), def.TableVersion)
+	require.Equal(t, "table1", def.Table)
+	require.Len(t, def.Columns, 1)
+	require.Equal(t, "id", def.Columns[0].Name)
+}
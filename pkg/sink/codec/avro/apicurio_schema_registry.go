@@ -0,0 +1,365 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+// defaultApicurioGroupID is the artifact group used by the Apicurio serdes if
+// the group is not specified.
+const defaultApicurioGroupID = "default"
+
+// apicurioSchemaManager is the SchemaManager of the Apicurio Registry, each
+// subject is an artifact in the group, and the schemas are identified by the
+// global IDs of the artifact versions.
+type apicurioSchemaManager struct {
+	// apiURL is the URL of the core v2 API of the registry.
+	apiURL     string
+	groupID    string
+	schemaType string
+
+	credential    *security.Credential
+	authorization string
+
+	cache *schemaCache
+}
+
+type apicurioArtifactMetaData struct {
+	GroupID  string `json:"groupId"`
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	GlobalID int64  `json:"globalId"`
+}
+
+type apicurioRuleViolationError struct {
+	Message string `json:"message"`
+	Causes  []struct {
+		Description string `json:"description"`
+		Context     string `json:"context"`
+	} `json:"causes"`
+}
+
+// newApicurioSchemaManager creates the schema manager of the Apicurio
+// Registry, and tests connectivity to the registry.
+func newApicurioSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	registryConfig *config.SchemaRegistryConfig,
+	schemaType string,
+) (SchemaManager, error) {
+	apiURL := strings.TrimRight(registryURL, "/") + "/apis/registry/v2"
+	credential, authorization := registryCredential(registryConfig)
+	httpCli, err := httputil.NewClient(credential)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"/system/info", nil)
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := httpCli.Do(req)
+	if err != nil {
+		log.Error("Test connection to Apicurio Registry failed", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	defer resp.Body.Close()
+
+	text, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Reading response from Apicurio Registry failed", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Unexpected response from Apicurio Registry",
+			zap.Int("status", resp.StatusCode), zap.ByteString("response", text))
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Unexpected response from Apicurio Registry, status = %d", resp.StatusCode)
+	}
+
+	groupID := util.GetOrZero(registryConfig.ApicurioGroupID)
+	if groupID == "" {
+		groupID = defaultApicurioGroupID
+	}
+	log.Info("Successfully tested connectivity to Apicurio Registry",
+		zap.String("registryURL", registryURL),
+		zap.String("groupID", groupID))
+
+	return &apicurioSchemaManager{
+		apiURL:        apiURL,
+		groupID:       groupID,
+		schemaType:    schemaType,
+		credential:    credential,
+		authorization: authorization,
+		cache:         newSchemaCache(),
+	}, nil
+}
+
+func (m *apicurioSchemaManager) artifactURL(schemaSubject string) string {
+	return m.apiURL + "/groups/" + url.PathEscape(m.groupID) +
+		"/artifacts/" + url.PathEscape(schemaSubject)
+}
+
+// Register a schema as a version of the artifact named by the subject, which
+// is created if it doesn't exist. Re-registering the latest schema returns
+// the same version.
+func (m *apicurioSchemaManager) Register(
+	ctx context.Context,
+	schemaSubject string,
+	schema string,
+) (schemaID, error) {
+	buffer := new(bytes.Buffer)
+	err := json.Compact(buffer, []byte(schema))
+	if err != nil {
+		log.Error("Could not compact schema", zap.Error(err))
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	payload := buffer.Bytes()
+	uri := m.apiURL + "/groups/" + url.PathEscape(m.groupID) +
+		"/artifacts?ifExists=RETURN_OR_UPDATE&canonical=true"
+	log.Info("Registering schema",
+		zap.String("uri", uri),
+		zap.String("artifactID", schemaSubject),
+		zap.ByteString("payload", payload))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Registry-ArtifactId", schemaSubject)
+	req.Header.Add("X-Registry-ArtifactType", m.schemaType)
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
+	if err != nil {
+		return schemaID{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read response from Apicurio Registry", zap.Error(err))
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// 409 for the schema violating the compatibility rule of the artifact.
+		log.Error("Failed to register schema to Apicurio Registry, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", uri),
+			zap.ByteString("requestBody", payload),
+			zap.ByteString("responseBody", body))
+		if resp.StatusCode == http.StatusConflict {
+			return schemaID{}, cerror.ErrAvroIncompatibleSchema.GenWithStackByArgs(schemaSubject)
+		}
+		return schemaID{}, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
+	}
+
+	var metaData apicurioArtifactMetaData
+	if err := json.Unmarshal(body, &metaData); err != nil {
+		log.Error("Failed to parse result from Apicurio Registry", zap.Error(err))
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if metaData.GlobalID <= 0 {
+		return schemaID{}, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Illegal global ID returned from Apicurio Registry %d", metaData.GlobalID)
+	}
+
+	log.Info("Registered schema to Apicurio Registry successfully",
+		zap.String("artifactID", schemaSubject),
+		zap.String("version", metaData.Version),
+		zap.Int64("globalID", metaData.GlobalID))
+	return schemaID{apicurioGlobalID: metaData.GlobalID}, nil
+}
+
+// Lookup the cached schema entry first, if not found, fetch it by the global
+// ID from the registry.
+func (m *apicurioSchemaManager) Lookup(
+	ctx context.Context,
+	schemaSubject string,
+	schemaID schemaID,
+) (*goavro.Codec, error) {
+	entry, exists := m.cache.get(schemaSubject)
+	if exists && entry.schemaID == schemaID {
+		return entry.codec, nil
+	}
+
+	log.Info("Avro schema lookup cache miss",
+		zap.String("key", schemaSubject),
+		zap.Int64("globalID", schemaID.apicurioGlobalID))
+	uri := m.apiURL + "/ids/globalIds/" + strconv.FormatInt(schemaID.apicurioGlobalID, 10)
+	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add("Accept", "application/json")
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read response from Apicurio Registry", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Failed to query schema from Apicurio Registry, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", uri),
+			zap.ByteString("responseBody", body))
+		return nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Failed to query schema from Apicurio Registry, status = %d", resp.StatusCode)
+	}
+
+	cacheEntry := new(schemaCacheEntry)
+	cacheEntry.codec, err = goavro.NewCodec(string(body))
+	if err != nil {
+		log.Error("Creating Avro codec failed", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	cacheEntry.schemaID = schemaID
+	m.cache.put(schemaSubject, cacheEntry)
+	return cacheEntry.codec, nil
+}
+
+// GetCachedOrRegister checks if the suitable Avro schema has been cached.
+// If not, a new schema is generated, registered and cached.
+func (m *apicurioSchemaManager) GetCachedOrRegister(
+	ctx context.Context,
+	schemaSubject string,
+	tableVersion uint64,
+	schemaGen SchemaGenerator,
+) (*goavro.Codec, []byte, error) {
+	return getCachedOrRegister(ctx, m, m.cache, m.schemaType, schemaSubject, tableVersion, schemaGen)
+}
+
+// CheckCompatibility tests the schema against the rules of the artifact named
+// by the subject, the reasons are returned if it's incompatible. A schema is
+// always compatible if the artifact doesn't exist.
+func (m *apicurioSchemaManager) CheckCompatibility(
+	ctx context.Context,
+	schemaSubject string,
+	schema string,
+) (bool, []string, error) {
+	buffer := new(bytes.Buffer)
+	if err := json.Compact(buffer, []byte(schema)); err != nil {
+		log.Error("Could not compact schema", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	uri := m.artifactURL(schemaSubject) + "/test"
+	req, err := http.NewRequestWithContext(ctx, "PUT", uri, bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Registry-ArtifactType", m.schemaType)
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read response from Apicurio Registry", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return true, nil, nil
+	case http.StatusConflict:
+		var violation apicurioRuleViolationError
+		if err := json.Unmarshal(body, &violation); err != nil {
+			log.Error("Failed to parse result from Apicurio Registry", zap.Error(err))
+			return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		}
+		reasons := make([]string, 0, len(violation.Causes))
+		for _, cause := range violation.Causes {
+			reasons = append(reasons, cause.Description)
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, violation.Message)
+		}
+		return false, reasons, nil
+	}
+	log.Error("Failed to check schema compatibility in Apicurio Registry, HTTP error",
+		zap.Int("status", resp.StatusCode),
+		zap.String("uri", uri),
+		zap.ByteString("responseBody", body))
+	return false, nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+		"Failed to check schema compatibility in Apicurio Registry, status = %d",
+		resp.StatusCode)
+}
+
+// ClearRegistry deletes the artifact named by the subject and all its versions.
+func (m *apicurioSchemaManager) ClearRegistry(ctx context.Context, schemaSubject string) error {
+	uri := m.artifactURL(schemaSubject)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", uri, nil)
+	if err != nil {
+		log.Error("Could not construct request for clearRegistry", zap.String("uri", uri))
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		log.Info("Clearing Apicurio Registry successful",
+			zap.String("artifactID", schemaSubject))
+		return nil
+	case http.StatusNotFound:
+		log.Info("Apicurio Registry already cleaned",
+			zap.String("artifactID", schemaSubject))
+		return nil
+	}
+
+	log.Error("Error when clearing Apicurio Registry", zap.Int("status", resp.StatusCode))
+	return cerror.ErrAvroSchemaAPIError.GenWithStack(
+		"Error when clearing Apicurio Registry, status = %d",
+		resp.StatusCode)
+}
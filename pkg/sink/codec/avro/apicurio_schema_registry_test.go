@@ -0,0 +1,175 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// mockApicurioRegistry is an Apicurio Registry keeping the artifacts of a
+// group in memory.
+type mockApicurioRegistry struct {
+	mu        sync.Mutex
+	groupID   string
+	artifacts map[string][]int64
+	schemas   map[int64]string
+	nextID    int64
+	// incompatible makes the new versions violate the compatibility rule.
+	incompatible bool
+}
+
+func (r *mockApicurioRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groupPrefix := "/apis/registry/v2/groups/" + r.groupID + "/artifacts"
+	path := req.URL.Path
+	switch {
+	case req.Method == "GET" && path == "/apis/registry/v2/system/info":
+		_, _ = w.Write([]byte(`{"name":"Apicurio Registry"}`))
+	case req.Method == "POST" && path == groupPrefix:
+		body, _ := io.ReadAll(req.Body)
+		artifactID := req.Header.Get("X-Registry-ArtifactId")
+		versions := r.artifacts[artifactID]
+		if len(versions) == 0 || r.schemas[versions[len(versions)-1]] != string(body) {
+			if len(versions) > 0 && r.incompatible {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			r.nextID++
+			r.schemas[r.nextID] = string(body)
+			r.artifacts[artifactID] = append(versions, r.nextID)
+		}
+		versions = r.artifacts[artifactID]
+		_ = json.NewEncoder(w).Encode(apicurioArtifactMetaData{
+			GroupID:  r.groupID,
+			ID:       artifactID,
+			Version:  strconv.Itoa(len(versions)),
+			GlobalID: versions[len(versions)-1],
+		})
+	case req.Method == "GET" && strings.HasPrefix(path, "/apis/registry/v2/ids/globalIds/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(path, "/apis/registry/v2/ids/globalIds/"), 10, 64)
+		schema, ok := r.schemas[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(schema))
+	case req.Method == "PUT" && strings.HasSuffix(path, "/test"):
+		artifactID := strings.TrimSuffix(strings.TrimPrefix(path, groupPrefix+"/"), "/test")
+		if _, ok := r.artifacts[artifactID]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.incompatible {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Incompatible artifact",` +
+				`"causes":[{"description":"field1 changed","context":"/fields/0"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == "DELETE" && strings.HasPrefix(path, groupPrefix+"/"):
+		artifactID := strings.TrimPrefix(path, groupPrefix+"/")
+		if _, ok := r.artifacts[artifactID]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(r.artifacts, artifactID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestApicurioSchemaRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry := &mockApicurioRegistry{
+		groupID:   "ticdc",
+		artifacts: make(map[string][]int64),
+		schemas:   make(map[int64]string),
+	}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manager, err := NewSchemaManager(ctx, server.URL, &config.SchemaRegistryConfig{
+		Provider:        util.AddressOf(config.SchemaRegistryProviderApicurio),
+		ApicurioGroupID: util.AddressOf("ticdc"),
+	})
+	require.NoError(t, err)
+
+	schema := `{"type":"record","name":"test","fields":[{"name":"field1","type":"string"}]}`
+	schemaGen := func() (string, error) { return schema, nil }
+	subject := "cdctest-value"
+
+	compatible, _, err := manager.CheckCompatibility(ctx, subject, schema)
+	require.NoError(t, err)
+	require.True(t, compatible)
+
+	codec, header, err := manager.GetCachedOrRegister(ctx, subject, 1, schemaGen)
+	require.NoError(t, err)
+	require.Len(t, header, 1+apicurioGlobalIDLen)
+	require.Equal(t, magicByte, header[0])
+
+	id, data, err := extractApicurioSchemaIDAndBinaryData(
+		append(append([]byte{}, header...), 1))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, data)
+	require.Equal(t, int64(1), id.apicurioGlobalID)
+
+	// re-registering the same schema returns the same version.
+	id2, err := manager.Register(ctx, subject, schema)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	codec2, err := manager.Lookup(ctx, "another-subject", id)
+	require.NoError(t, err)
+	require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
+
+	schema = `{"type":"record","name":"test","fields":[{"name":"field1","type":"string"},` +
+		`{"name":"field2","type":["null","string"],"default":null}]}`
+	_, header2, err := manager.GetCachedOrRegister(ctx, subject, 2, schemaGen)
+	require.NoError(t, err)
+	require.NotEqual(t, header, header2)
+
+	registry.mu.Lock()
+	registry.incompatible = true
+	registry.mu.Unlock()
+	schema = `{"type":"record","name":"test","fields":[{"name":"field1","type":"long"}]}`
+	compatible, reasons, err := manager.CheckCompatibility(ctx, subject, schema)
+	require.NoError(t, err)
+	require.False(t, compatible)
+	require.Equal(t, []string{"field1 changed"}, reasons)
+	_, _, err = manager.GetCachedOrRegister(ctx, subject, 3, schemaGen)
+	require.True(t, cerror.ErrAvroIncompatibleSchema.Equal(err))
+
+	require.NoError(t, manager.ClearRegistry(ctx, subject))
+	require.NoError(t, manager.ClearRegistry(ctx, subject))
+}
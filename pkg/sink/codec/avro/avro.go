@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/linkedin/goavro/v2"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -33,8 +34,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -42,7 +45,8 @@ import (
 // BatchEncoder converts the events to binary Avro data
 type BatchEncoder struct {
 	namespace string
-	schemaM   *SchemaManager
+	schemaM   SchemaManager
+	subjects  subjectNamer
 	result    []*common.Message
 
 	config *common.Config
@@ -68,9 +72,9 @@ func (r *avroEncodeInput) Swap(i, j int) {
 
 type avroEncodeResult struct {
 	data []byte
-	// schemaID is encoded into the avro message, consumer should use this to fetch the schema.
-	// it's the global schema id for all schema in the registry.
-	schemaID int
+	// header is prepended to the avro message, it carries the schema ID which
+	// the consumer should use to fetch the schema from the registry.
+	header []byte
 }
 
 func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.RowChangedEvent) ([]byte, error) {
@@ -85,7 +89,7 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.Row
 		columns:  cols,
 		colInfos: colInfos,
 	}
-	avroCodec, schemaID, err := a.getKeySchemaCodec(ctx, topic, e.Table, e.TableInfo.Version, keyColumns)
+	avroCodec, header, err := a.getKeySchemaCodec(ctx, topic, e.Table, e.TableInfo.Version, keyColumns)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -103,8 +107,8 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.Row
 	}
 
 	result := &avroEncodeResult{
-		data:     bin,
-		schemaID: schemaID,
+		data:   bin,
+		header: header,
 	}
 	data, err := result.toEnvelope()
 	if err != nil {
@@ -113,13 +117,53 @@ func (a *BatchEncoder) encodeKey(ctx context.Context, topic string, e *model.Row
 	return data, nil
 }
 
-func topicName2SchemaSubjects(topicName, subjectSuffix string) string {
-	return topicName + subjectSuffix
+// subjectNamer names the subjects of the schemas in the schema registry by
+// the subject name strategy, the zero value names the subjects by the topics.
+type subjectNamer struct {
+	strategy string
+	prefix   string
+	suffix   string
+}
+
+func newSubjectNamer(
+	changefeedID model.ChangeFeedID, registryConfig *config.SchemaRegistryConfig,
+) subjectNamer {
+	if registryConfig == nil {
+		return subjectNamer{}
+	}
+	replacer := strings.NewReplacer(
+		"{namespace}", changefeedID.Namespace, "{changefeed}", changefeedID.ID)
+	return subjectNamer{
+		strategy: util.GetOrZero(registryConfig.SubjectNameStrategy),
+		prefix:   replacer.Replace(util.GetOrZero(registryConfig.SubjectPrefix)),
+		suffix:   replacer.Replace(util.GetOrZero(registryConfig.SubjectSuffix)),
+	}
+}
+
+// subject returns the subject of the key or value schema of the table, the
+// schemaSuffix is keySchemaSuffix or valueSchemaSuffix.
+func (a *BatchEncoder) subject(topic string, tableName *model.TableName, schemaSuffix string) string {
+	recordName := getAvroNamespace(a.namespace, tableName.Schema) + "." + sanitizeName(tableName.Table)
+	var subject string
+	switch a.subjects.strategy {
+	case config.SubjectNameStrategyRecord:
+		subject = recordName
+	case config.SubjectNameStrategyTopicRecord:
+		subject = topic + "-" + recordName
+	default:
+		subject = topic + schemaSuffix
+	}
+	// the key and value records of a table share the same name.
+	if a.subjects.strategy != "" && a.subjects.strategy != config.SubjectNameStrategyTopic &&
+		schemaSuffix == keySchemaSuffix {
+		subject += keySchemaSuffix
+	}
+	return a.subjects.prefix + subject + a.subjects.suffix
 }
 
 func (a *BatchEncoder) getValueSchemaCodec(
 	ctx context.Context, topic string, tableName *model.TableName, tableVersion uint64, input *avroEncodeInput,
-) (*goavro.Codec, int, error) {
+) (*goavro.Codec, []byte, error) {
 	schemaGen := func() (string, error) {
 		schema, err := a.value2AvroSchema(tableName, input)
 		if err != nil {
@@ -129,17 +173,17 @@ func (a *BatchEncoder) getValueSchemaCodec(
 		return schema, nil
 	}
 
-	subject := topicName2SchemaSubjects(topic, valueSchemaSuffix)
-	avroCodec, schemaID, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
+	subject := a.subject(topic, tableName, valueSchemaSuffix)
+	avroCodec, header, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
 	if err != nil {
-		return nil, 0, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
-	return avroCodec, schemaID, nil
+	return avroCodec, header, nil
 }
 
 func (a *BatchEncoder) getKeySchemaCodec(
 	ctx context.Context, topic string, tableName *model.TableName, tableVersion uint64, keyColumns *avroEncodeInput,
-) (*goavro.Codec, int, error) {
+) (*goavro.Codec, []byte, error) {
 	schemaGen := func() (string, error) {
 		schema, err := a.key2AvroSchema(tableName, keyColumns)
 		if err != nil {
@@ -149,12 +193,12 @@ func (a *BatchEncoder) getKeySchemaCodec(
 		return schema, nil
 	}
 
-	subject := topicName2SchemaSubjects(topic, keySchemaSuffix)
-	avroCodec, schemaID, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
+	subject := a.subject(topic, tableName, keySchemaSuffix)
+	avroCodec, header, err := a.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
 	if err != nil {
-		return nil, 0, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
-	return avroCodec, schemaID, nil
+	return avroCodec, header, nil
 }
 
 func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *model.RowChangedEvent) ([]byte, error) {
@@ -170,7 +214,7 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *model.R
 		return nil, nil
 	}
 
-	avroCodec, schemaID, err := a.getValueSchemaCodec(ctx, topic, e.Table, e.TableInfo.Version, input)
+	avroCodec, header, err := a.getValueSchemaCodec(ctx, topic, e.Table, e.TableInfo.Version, input)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -180,6 +224,9 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *model.R
 		log.Error("avro: converting value to native failed", zap.Error(err))
 		return nil, errors.Trace(err)
 	}
+	if a.config.OnlyOutputUpdatedColumns {
+		native[tidbUpdatedColumns] = a.dropNotUpdatedColumns(native, input, e)
+	}
 	if a.config.EnableTiDBExtension {
 		native = a.nativeValueWithExtension(native, e)
 	}
@@ -191,8 +238,8 @@ func (a *BatchEncoder) encodeValue(ctx context.Context, topic string, e *model.R
 	}
 
 	result := &avroEncodeResult{
-		data:     bin,
-		schemaID: schemaID,
+		data:   bin,
+		header: header,
 	}
 	data, err := result.toEnvelope()
 	if err != nil {
@@ -301,6 +348,118 @@ func (a *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	return nil, nil
 }
 
+// CheckSchemaCompatibility checks whether the key and value schemas of the
+// table changed by the DDL are compatible with the latest ones registered for
+// the topic, so the incompatible DDL is reported before the rows of the new
+// schema are encoded.
+func (a *BatchEncoder) CheckSchemaCompatibility(
+	ctx context.Context, topic string, ddl *model.DDLEvent,
+) error {
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionDropSchema,
+		timodel.ActionCreateView, timodel.ActionDropView:
+		return nil
+	}
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil {
+		return nil
+	}
+	topic = sanitizeTopic(topic)
+	tableName := &ddl.TableInfo.TableName
+	value, key := tableInfo2AvroEncodeInputs(ddl.TableInfo,
+		a.config.ComputedColumns(tableName.Schema, tableName.Table))
+
+	schema, err := a.value2AvroSchema(tableName, value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subject := a.subject(topic, tableName, valueSchemaSuffix)
+	if err := a.checkSchemaCompatibility(ctx, subject, schema, ddl); err != nil {
+		return err
+	}
+	if len(key.columns) == 0 {
+		return nil
+	}
+	schema, err = a.key2AvroSchema(tableName, key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	subject = a.subject(topic, tableName, keySchemaSuffix)
+	return a.checkSchemaCompatibility(ctx, subject, schema, ddl)
+}
+
+func (a *BatchEncoder) checkSchemaCompatibility(
+	ctx context.Context, subject string, schema string, ddl *model.DDLEvent,
+) error {
+	// the schema is registered in the canonical form by the codec.
+	avroCodec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	compatible, reasons, err := a.schemaM.CheckCompatibility(ctx, subject, avroCodec.Schema())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if compatible {
+		return nil
+	}
+	log.Warn("avro schema changed by DDL is incompatible with the registry",
+		zap.String("subject", subject),
+		zap.String("table", ddl.TableInfo.TableName.String()),
+		zap.String("query", ddl.Query),
+		zap.Strings("reasons", reasons))
+	return cerror.ErrAvroIncompatibleSchema.GenWithStack(
+		"schema of subject %s changed by DDL %q of table %s is incompatible "+
+			"with the schema in the registry, reasons: %s",
+		subject, ddl.Query, ddl.TableInfo.TableName.String(), strings.Join(reasons, "; "))
+}
+
+// tableInfo2AvroEncodeInputs returns the value and key columns of the table,
+// which are the same as the ones of the row changed events of the table, the
+// computed columns are appended to the value columns.
+func tableInfo2AvroEncodeInputs(
+	tableInfo *model.TableInfo, computedColumns []string,
+) (*avroEncodeInput, *avroEncodeInput) {
+	value := &avroEncodeInput{
+		columns:  make([]*model.Column, len(tableInfo.RowColumnsOffset)),
+		colInfos: make([]rowcodec.ColInfo, len(tableInfo.RowColumnsOffset)),
+	}
+	_, _, extendColumnInfos := tableInfo.GetRowColInfos()
+	for idx, colInfo := range tableInfo.Columns {
+		if !model.IsColCDCVisible(colInfo) {
+			continue
+		}
+		offset := tableInfo.RowColumnsOffset[colInfo.ID]
+		value.columns[offset] = &model.Column{
+			Name:    colInfo.Name.O,
+			Type:    colInfo.GetType(),
+			Charset: colInfo.GetCharset(),
+			Default: model.GetDDLDefaultDefinition(colInfo),
+			Flag:    tableInfo.ColumnsFlag[colInfo.ID],
+		}
+		value.colInfos[offset] = extendColumnInfos[idx]
+	}
+	for _, name := range computedColumns {
+		value.columns = append(value.columns, &model.Column{
+			Name:    name,
+			Type:    mysql.TypeVarchar,
+			Charset: mysql.DefaultCharset,
+		})
+		value.colInfos = append(value.colInfos, rowcodec.ColInfo{
+			ID: -1,
+			Ft: filter.ComputedColumnFieldType(),
+		})
+	}
+
+	key := &avroEncodeInput{}
+	for i, col := range value.columns {
+		if col.Flag.IsHandleKey() {
+			key.columns = append(key.columns, col)
+			key.colInfos = append(key.colInfos, value.colInfos[i])
+		}
+	}
+	return value, key
+}
+
 // Build Messages
 func (a *BatchEncoder) Build() (messages []*common.Message) {
 	result := a.result
@@ -338,6 +497,45 @@ func (a *BatchEncoder) nativeValueWithExtension(
 	return native
 }
 
+// dropNotUpdatedColumns sets the not updated columns of the update event to
+// null except the handle key columns, and returns the bitmap of the updated
+// columns, the i-th bit of which is set if the i-th column of the value schema
+// is updated. The bitmap is null for the other events.
+func (a *BatchEncoder) dropNotUpdatedColumns(
+	native map[string]interface{},
+	input *avroEncodeInput,
+	e *model.RowChangedEvent,
+) interface{} {
+	if !e.IsUpdate() || len(e.PreColumns) != len(input.columns) {
+		return nil
+	}
+	var bitmap []byte
+	i := 0
+	for idx, col := range input.columns {
+		if col == nil {
+			continue
+		}
+		if i%8 == 0 {
+			bitmap = append(bitmap, 0)
+		}
+		if codec.IsColumnUpdated(e.PreColumns[idx], col) {
+			bitmap[i/8] |= 1 << (i % 8)
+		} else if !col.Flag.IsHandleKey() {
+			native[sanitizeName(col.Name)] = nil
+		}
+		i++
+	}
+	return goavro.Union("bytes", bitmap)
+}
+
+// isNullable returns whether the field of the column is nullable, all the
+// columns except the handle key ones are nullable if only the updated columns
+// are output.
+func (a *BatchEncoder) isNullable(col *model.Column) bool {
+	return col.Flag.IsNullable() ||
+		(a.config.OnlyOutputUpdatedColumns && !col.Flag.IsHandleKey())
+}
+
 type avroSchemaTop struct {
 	Tp        string                   `json:"type"`
 	Name      string                   `json:"name"`
@@ -350,6 +548,9 @@ const (
 	tidbOp           = "_tidb_op"
 	tidbCommitTs     = "_tidb_commit_ts"
 	tidbPhysicalTime = "_tidb_commit_physical_time"
+	// tidbUpdatedColumns is the bitmap of the updated columns of the update
+	// events, it's only output if only the updated columns are output.
+	tidbUpdatedColumns = "_tidb_updated_columns"
 
 	// row level checksum related fields
 	tidbRowLevelChecksum = "_tidb_row_level_checksum"
@@ -568,6 +769,9 @@ func (a *BatchEncoder) columns2AvroSchema(
 		Fields:    nil,
 	}
 	for i, col := range input.columns {
+		if col == nil {
+			continue
+		}
 		avroType, err := a.columnToAvroSchema(col, input.colInfos[i].Ft)
 		if err != nil {
 			return nil, err
@@ -585,14 +789,14 @@ func (a *BatchEncoder) columns2AvroSchema(
 		// goavro doesn't support set default value for logical type
 		// https://github.com/linkedin/goavro/issues/202
 		if _, ok := avroType.(avroLogicalTypeSchema); ok {
-			if col.Flag.IsNullable() {
+			if a.isNullable(col) {
 				field["type"] = []interface{}{"null", avroType}
 				field["default"] = nil
 			} else {
 				field["type"] = avroType
 			}
 		} else {
-			if col.Flag.IsNullable() {
+			if a.isNullable(col) {
 				// https://stackoverflow.com/questions/22938124/avro-field-default-values
 				if defaultValue == nil {
 					field["type"] = []interface{}{"null", avroType}
@@ -625,6 +829,13 @@ func (a *BatchEncoder) value2AvroSchema(
 		return "", err
 	}
 
+	if a.config.OnlyOutputUpdatedColumns {
+		top.Fields = append(top.Fields, map[string]interface{}{
+			"name":    tidbUpdatedColumns,
+			"type":    []interface{}{"null", "bytes"},
+			"default": nil,
+		})
+	}
 	if a.config.EnableTiDBExtension {
 		top = a.schemaWithExtension(top)
 	}
@@ -671,7 +882,7 @@ func (a *BatchEncoder) columns2AvroData(
 		}
 
 		// https: //pkg.go.dev/github.com/linkedin/goavro/v2#Union
-		if col.Flag.IsNullable() {
+		if a.isNullable(col) {
 			ret[sanitizeName(col.Name)] = goavro.Union(str, data)
 		} else {
 			ret[sanitizeName(col.Name)] = data
@@ -682,11 +893,33 @@ func (a *BatchEncoder) columns2AvroData(
 	return ret, nil
 }
 
+// mappedAvroType returns the avro type the column is encoded as by the type
+// mappings, it's empty if the column is encoded as its default type.
+func (a *BatchEncoder) mappedAvroType(col *model.Column, ft *types.FieldType) string {
+	switch {
+	case col.Type == mysql.TypeBit:
+		if a.config.TypeMappings[config.TypeMappingSourceBit] == config.TypeMappingTargetInt {
+			return "long"
+		}
+	case col.Type == mysql.TypeTiny && ft.GetFlen() == 1:
+		if a.config.TypeMappings[config.TypeMappingSourceTinyInt1] == config.TypeMappingTargetBool {
+			return "boolean"
+		}
+	}
+	return ""
+}
+
 func (a *BatchEncoder) columnToAvroSchema(
 	col *model.Column,
 	ft *types.FieldType,
 ) (interface{}, error) {
 	tt := getTiDBTypeFromColumn(col)
+	if t := a.mappedAvroType(col, ft); t != "" {
+		return avroSchema{
+			Type:       t,
+			Parameters: map[string]string{tidbType: tt},
+		}, nil
+	}
 	switch col.Type {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24:
 		// BOOL/TINYINT/SMALLINT/MEDIUMINT
@@ -798,7 +1031,21 @@ func (a *BatchEncoder) columnToAvroSchema(
 			Type:       "string",
 			Parameters: map[string]string{tidbType: tt},
 		}, nil
-	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		if a.config.TemporalEncoder.EpochMillis() {
+			return avroLogicalTypeSchema{
+				avroSchema: avroSchema{
+					Type:       "long",
+					Parameters: map[string]string{tidbType: tt},
+				},
+				LogicalType: "timestamp-millis",
+			}, nil
+		}
+		return avroSchema{
+			Type:       "string",
+			Parameters: map[string]string{tidbType: tt},
+		}, nil
+	case mysql.TypeDate, mysql.TypeDuration:
 		return avroSchema{
 			Type:       "string",
 			Parameters: map[string]string{tidbType: tt},
@@ -822,6 +1069,30 @@ func (a *BatchEncoder) columnToAvroData(
 		return nil, "null", nil
 	}
 
+	switch a.mappedAvroType(col, ft) {
+	case "long":
+		if v, ok := col.Value.(string); ok {
+			n, err := types.BinaryLiteral(v).ToInt(nil)
+			if err != nil {
+				return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+			}
+			return int64(n), "long", nil
+		}
+		return int64(col.Value.(uint64)), "long", nil
+	case "boolean":
+		if v, ok := col.Value.(string); ok {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, "", cerror.WrapError(cerror.ErrAvroEncodeFailed, err)
+			}
+			return n != 0, "boolean", nil
+		}
+		if col.Flag.IsUnsigned() {
+			return col.Value.(uint64) != 0, "boolean", nil
+		}
+		return col.Value.(int64) != 0, "boolean", nil
+	}
+
 	switch col.Type {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24:
 		if v, ok := col.Value.(string); ok {
@@ -955,7 +1226,13 @@ func (a *BatchEncoder) columnToAvroData(
 		return setVar.Name, "string", nil
 	case mysql.TypeJSON:
 		return col.Value.(string), "string", nil
-	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		value := a.config.TemporalEncoder.Encode(col)
+		if v, ok := value.(int64); ok {
+			return v, "long.timestamp-millis", nil
+		}
+		return value.(string), "string", nil
+	case mysql.TypeDate, mysql.TypeDuration:
 		return col.Value.(string), "string", nil
 	case mysql.TypeYear:
 		if v, ok := col.Value.(string); ok {
@@ -978,6 +1255,18 @@ const (
 	// https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format
 	magicByte = uint8(0)
 
+	// glue avro wire format, the header version byte is followed by the
+	// compression byte and the 16 bytes UUID of the schema version.
+	// https://github.com/awslabs/aws-glue-schema-registry
+	glueHeaderVersionByte  = uint8(3)
+	glueCompressionNone    = uint8(0)
+	glueSchemaVersionIDLen = 16
+
+	// apicurio avro wire format, the magic byte is followed by the 8 bytes
+	// global ID of the schema.
+	// https://www.apicur.io/registry/docs/apicurio-registry/2.4.x/getting-started/assembly-using-kafka-client-serdes.html
+	apicurioGlobalIDLen = 8
+
 	// avro does not send ddl and checkpoint message, the following 2 field is used to distinguish
 	// TiCDC DDL event and checkpoint event, only used for testing purpose, not for production
 	ddlByte        = uint8(1)
@@ -989,7 +1278,33 @@ const (
 // -and-ksqldb-viewing-kafka-messages-bytes-as-hex/
 func (r *avroEncodeResult) toEnvelope() ([]byte, error) {
 	buf := new(bytes.Buffer)
-	data := []interface{}{magicByte, int32(r.schemaID), r.data}
+	data := []interface{}{r.header, r.data}
+	for _, v := range data {
+		err := binary.Write(buf, binary.BigEndian, v)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrAvroToEnvelopeError, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// getMessageHeader returns the header of the messages whose schema is
+// identified by the schemaID, in the wire format of the registry of the schema.
+func getMessageHeader(id schemaID) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	var data []interface{}
+	switch {
+	case id.glueSchemaID != "":
+		versionID, err := uuid.Parse(id.glueSchemaID)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrAvroToEnvelopeError, err)
+		}
+		data = []interface{}{glueHeaderVersionByte, glueCompressionNone, versionID[:]}
+	case id.apicurioGlobalID != 0:
+		data = []interface{}{magicByte, id.apicurioGlobalID}
+	default:
+		data = []interface{}{magicByte, id.confluentSchemaID}
+	}
 	for _, v := range data {
 		err := binary.Write(buf, binary.BigEndian, v)
 		if err != nil {
@@ -1002,7 +1317,8 @@ func (r *avroEncodeResult) toEnvelope() ([]byte, error) {
 type batchEncoderBuilder struct {
 	namespace string
 	config    *common.Config
-	schemaM   *SchemaManager
+	schemaM   SchemaManager
+	subjects  subjectNamer
 }
 
 const (
@@ -1015,7 +1331,8 @@ func NewBatchEncoderBuilder(ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	config *common.Config,
 ) (codec.RowEventEncoderBuilder, error) {
-	schemaM, err := NewAvroSchemaManager(ctx, config.AvroSchemaRegistry, nil)
+	schemaM, err := NewSchemaManager(
+		ctx, config.AvroSchemaRegistry, config.AvroSchemaRegistryConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -1024,16 +1341,23 @@ func NewBatchEncoderBuilder(ctx context.Context,
 		namespace: changefeedID.Namespace,
 		config:    config,
 		schemaM:   schemaM,
+		subjects:  newSubjectNamer(changefeedID, config.AvroSchemaRegistryConfig),
 	}, nil
 }
 
 // Build an AvroEventBatchEncoder.
 func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
-	return NewAvroEncoder(b.namespace, b.schemaM, b.config)
+	encoder := newBatchEncoder(b.namespace, b.schemaM, b.config)
+	encoder.subjects = b.subjects
+	return encoder
 }
 
 // NewAvroEncoder return a avro encoder.
-func NewAvroEncoder(namespace string, schemaM *SchemaManager, config *common.Config) codec.RowEventEncoder {
+func NewAvroEncoder(namespace string, schemaM SchemaManager, config *common.Config) codec.RowEventEncoder {
+	return newBatchEncoder(namespace, schemaM, config)
+}
+
+func newBatchEncoder(namespace string, schemaM SchemaManager, config *common.Config) *BatchEncoder {
 	return &BatchEncoder{
 		namespace: namespace,
 		schemaM:   schemaM,
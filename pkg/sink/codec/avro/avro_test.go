@@ -24,12 +24,17 @@ import (
 	"time"
 
 	"github.com/linkedin/goavro/v2"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -609,6 +614,92 @@ func TestColumnToAvroData(t *testing.T) {
 	}
 }
 
+func TestColumnTypeMappings(t *testing.T) {
+	t.Parallel()
+
+	encoder := NewAvroEncoder("namespace", nil, &common.Config{
+		AvroDecimalHandlingMode:        "precise",
+		AvroBigintUnsignedHandlingMode: "long",
+		TypeMappings: map[string]string{
+			config.TypeMappingSourceBit:      config.TypeMappingTargetInt,
+			config.TypeMappingSourceTinyInt1: config.TypeMappingTargetBool,
+		},
+	}).(*BatchEncoder)
+
+	bitFt := types.NewFieldType(mysql.TypeBit)
+	bitFt.SetFlen(9)
+	boolFt := types.NewFieldType(mysql.TypeTiny)
+	boolFt.SetFlen(1)
+	tinyFt := types.NewFieldType(mysql.TypeTiny)
+	tinyFt.SetFlen(4)
+	cases := []struct {
+		col          model.Column
+		ft           *types.FieldType
+		expectedType string
+		expectedData interface{}
+	}{
+		{model.Column{Name: "bit", Type: mysql.TypeBit, Value: uint64(257)}, bitFt, "long", int64(257)},
+		{model.Column{Name: "bit", Type: mysql.TypeBit, Value: "\x01\x01"}, bitFt, "long", int64(257)},
+		{model.Column{Name: "bool", Type: mysql.TypeTiny, Value: int64(2)}, boolFt, "boolean", true},
+		{model.Column{Name: "bool", Type: mysql.TypeTiny, Value: "0"}, boolFt, "boolean", false},
+		// only the TINYINT(1) columns are mapped.
+		{model.Column{Name: "tinyint", Type: mysql.TypeTiny, Value: int64(2)}, tinyFt, "int", int32(2)},
+	}
+	for _, c := range cases {
+		schema, err := encoder.columnToAvroSchema(&c.col, c.ft)
+		require.NoError(t, err)
+		require.Equal(t, c.expectedType, schema.(avroSchema).Type)
+		data, str, err := encoder.columnToAvroData(&c.col, c.ft)
+		require.NoError(t, err)
+		require.Equal(t, c.expectedType, str)
+		require.Equal(t, c.expectedData, data)
+	}
+
+	// the BIT columns are encoded as the bytes by default.
+	encoder = NewAvroEncoder("namespace", nil, &common.Config{}).(*BatchEncoder)
+	data, str, err := encoder.columnToAvroData(&cases[0].col, bitFt)
+	require.NoError(t, err)
+	require.Equal(t, "bytes", str)
+	require.Equal(t, []byte{1, 1}, data)
+}
+
+func TestColumnTemporalEncoding(t *testing.T) {
+	t.Parallel()
+
+	temporal, err := common.NewTemporalEncoder(&config.TemporalEncodingConfig{
+		Mode: util.AddressOf(config.TemporalEncodingEpochMillis),
+	}, time.UTC)
+	require.NoError(t, err)
+	encoder := NewAvroEncoder("namespace", nil, &common.Config{
+		AvroDecimalHandlingMode:        "precise",
+		AvroBigintUnsignedHandlingMode: "long",
+		TemporalEncoder:                temporal,
+	}).(*BatchEncoder)
+
+	col := &model.Column{Name: "ts", Type: mysql.TypeTimestamp, Value: "2023-01-02 00:04:05"}
+	ft := types.NewFieldType(mysql.TypeTimestamp)
+	schema, err := encoder.columnToAvroSchema(col, ft)
+	require.NoError(t, err)
+	require.Equal(t, avroLogicalTypeSchema{
+		avroSchema: avroSchema{
+			Type:       "long",
+			Parameters: map[string]string{"tidb_type": "TIMESTAMP"},
+		},
+		LogicalType: "timestamp-millis",
+	}, schema)
+	data, str, err := encoder.columnToAvroData(col, ft)
+	require.NoError(t, err)
+	require.Equal(t, "long.timestamp-millis", str)
+	require.Equal(t, time.Date(2023, 1, 2, 0, 4, 5, 0, time.UTC).UnixMilli(), data)
+
+	// the DATE columns are not encoded as the milliseconds.
+	col = &model.Column{Name: "date", Type: mysql.TypeDate, Value: "2023-01-02"}
+	data, str, err = encoder.columnToAvroData(col, types.NewFieldType(mysql.TypeDate))
+	require.NoError(t, err)
+	require.Equal(t, "string", str)
+	require.Equal(t, "2023-01-02", data)
+}
+
 func indentJSON(j string) string {
 	var buf bytes.Buffer
 	_ = json.Indent(&buf, []byte(j), "", "  ")
@@ -870,9 +961,11 @@ func TestAvroEnvelope(t *testing.T) {
 	bin, err := avroCodec.BinaryFromNative(nil, testNativeData)
 	require.NoError(t, err)
 
+	header, err := getMessageHeader(schemaID{confluentSchemaID: 7})
+	require.NoError(t, err)
 	res := avroEncodeResult{
-		data:     bin,
-		schemaID: 7,
+		data:   bin,
+		header: header,
 	}
 
 	evlp, err := res.toEnvelope()
@@ -972,3 +1065,178 @@ func TestArvoAppendRowChangedEventWithCallback(t *testing.T) {
 		require.Equal(t, expected, count, "expected one callback be called")
 	}
 }
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, common.NewConfig(config.ProtocolAvro))
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+
+	newColumn := func(id int64, name string, tp byte, flag uint) *timodel.ColumnInfo {
+		ft := types.NewFieldType(tp)
+		ft.SetFlag(flag)
+		return &timodel.ColumnInfo{
+			ID:        id,
+			Name:      timodel.NewCIStr(name),
+			Offset:    int(id - 1),
+			State:     timodel.StatePublic,
+			FieldType: *ft,
+		}
+	}
+	newDDL := func(version uint64, query string, cols ...*timodel.ColumnInfo) *model.DDLEvent {
+		return &model.DDLEvent{
+			Query: query,
+			Type:  timodel.ActionAddColumn,
+			TableInfo: model.WrapTableInfo(1, "test", version, &timodel.TableInfo{
+				ID:         100,
+				Name:       timodel.NewCIStr("t"),
+				Columns:    cols,
+				PKIsHandle: true,
+			}),
+		}
+	}
+	id := newColumn(1, "id", mysql.TypeLong, mysql.PriKeyFlag|mysql.NotNullFlag)
+	name := newColumn(2, "name", mysql.TypeVarchar, 0)
+
+	// nothing is registered yet.
+	topic := "test.t"
+	ddl := newDDL(1, "create table t(id int primary key, name varchar(32))", id, name)
+	require.NoError(t, encoder.CheckSchemaCompatibility(ctx, topic, ddl))
+
+	value, _ := tableInfo2AvroEncodeInputs(ddl.TableInfo, nil)
+	schema, err := encoder.value2AvroSchema(&ddl.TableInfo.TableName, value)
+	require.NoError(t, err)
+	avroCodec, err := goavro.NewCodec(schema)
+	require.NoError(t, err)
+	_, err = encoder.schemaM.Register(ctx, sanitizeTopic(topic)+valueSchemaSuffix, avroCodec.Schema())
+	require.NoError(t, err)
+
+	// a nullable column has the NULL default.
+	ddl = newDDL(2, "alter table t add column age int", id, name,
+		newColumn(3, "age", mysql.TypeLong, 0))
+	require.NoError(t, encoder.CheckSchemaCompatibility(ctx, topic, ddl))
+
+	ddl = newDDL(3, "alter table t add column age int not null", id, name,
+		newColumn(3, "age", mysql.TypeLong, mysql.NotNullFlag))
+	err = encoder.CheckSchemaCompatibility(ctx, topic, ddl)
+	require.True(t, cerror.ErrAvroIncompatibleSchema.Equal(err))
+	require.ErrorContains(t, err, "test.t")
+	require.ErrorContains(t, err, "READER_FIELD_MISSING_DEFAULT_VALUE, age")
+
+	// the schema isn't changed by dropping the table.
+	ddl.Type = timodel.ActionDropTable
+	require.NoError(t, encoder.CheckSchemaCompatibility(ctx, topic, ddl))
+}
+
+func TestSubjectNameStrategy(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	tableName := &model.TableName{Schema: "db", Table: "t"}
+	cases := []struct {
+		config *config.SchemaRegistryConfig
+		key    string
+		value  string
+	}{
+		{nil, "topic-key", "topic-value"},
+		{&config.SchemaRegistryConfig{
+			SubjectNameStrategy: util.AddressOf(config.SubjectNameStrategyTopic),
+		}, "topic-key", "topic-value"},
+		{&config.SchemaRegistryConfig{
+			SubjectNameStrategy: util.AddressOf(config.SubjectNameStrategyRecord),
+		}, "default.db.t-key", "default.db.t"},
+		{&config.SchemaRegistryConfig{
+			SubjectNameStrategy: util.AddressOf(config.SubjectNameStrategyTopicRecord),
+		}, "topic-default.db.t-key", "topic-default.db.t"},
+		{&config.SchemaRegistryConfig{
+			SubjectPrefix: util.AddressOf("{namespace}-{changefeed}."),
+			SubjectSuffix: util.AddressOf("-prod"),
+		}, "default-test.topic-key-prod", "default-test.topic-value-prod"},
+	}
+	for _, c := range cases {
+		encoder := newBatchEncoder(model.DefaultNamespace, nil, &common.Config{})
+		encoder.subjects = newSubjectNamer(changefeedID, c.config)
+		require.Equal(t, c.key, encoder.subject("topic", tableName, keySchemaSuffix))
+		require.Equal(t, c.value, encoder.subject("topic", tableName, valueSchemaSuffix))
+	}
+}
+
+func TestAvroEncodeComputedColumns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.ComputedColumns = []*config.ComputedColumn{
+		{Matcher: []string{"test.*"}, Name: "region", Value: "us-west-1"},
+	}
+	tableMatcher, err := tfilter.Parse([]string{"test.*"})
+	require.NoError(t, err)
+	codecConfig := common.NewConfig(config.ProtocolAvro)
+	codecConfig.ComputedColumnRules = []*common.ComputedColumnRule{
+		{TableMatcher: tableMatcher, Name: "region"},
+	}
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, codecConfig)
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+
+	ft := types.NewFieldType(mysql.TypeLong)
+	ft.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	tableInfo := model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		ID:   100,
+		Name: timodel.NewCIStr("t"),
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("id"),
+			FieldType: *ft,
+			State:     timodel.StatePublic,
+		}},
+		PKIsHandle: true,
+	})
+	_, _, colInfos := tableInfo.GetRowColInfos()
+	row := &model.RowChangedEvent{
+		CommitTs:  1,
+		Table:     &tableInfo.TableName,
+		TableInfo: tableInfo,
+		Columns: []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  tableInfo.ColumnsFlag[1],
+			Value: int64(1),
+		}},
+		ColInfos: colInfos,
+	}
+	appender, err := filter.NewColumnAppender(replicaConfig, "default", model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	require.NoError(t, appender.AppendRowChangedEvent(row))
+
+	topic := "test.t"
+	bin, err := encoder.encodeValue(ctx, topic, row)
+	require.NoError(t, err)
+	schemaID, data, err := extractSchemaIDAndBinaryData(bin)
+	require.NoError(t, err)
+	avroValueCodec, err := encoder.schemaM.Lookup(ctx, topic, schemaID)
+	require.NoError(t, err)
+	res, _, err := avroValueCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+	require.Equal(t, "us-west-1", res.(map[string]interface{})["region"])
+
+	// the schema derived from the table info has the computed columns too.
+	value, _ := tableInfo2AvroEncodeInputs(tableInfo, codecConfig.ComputedColumns("test", "t"))
+	schema, err := encoder.value2AvroSchema(&tableInfo.TableName, value)
+	require.NoError(t, err)
+	var parsed struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(schema), &parsed))
+	require.Len(t, parsed.Fields, 2)
+	require.Equal(t, "region", parsed.Fields[1].Name)
+	require.NoError(t, encoder.CheckSchemaCompatibility(ctx, topic, &model.DDLEvent{
+		Query:     "alter table t comment 'x'",
+		Type:      timodel.ActionModifyTableComment,
+		TableInfo: tableInfo,
+	}))
+}
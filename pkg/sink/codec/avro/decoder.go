@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"hash/crc32"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -40,7 +42,7 @@ type decoder struct {
 	topic  string
 	sc     *stmtctx.StatementContext
 
-	schemaM *SchemaManager
+	schemaM SchemaManager
 
 	key   []byte
 	value []byte
@@ -49,7 +51,7 @@ type decoder struct {
 // NewDecoder return an avro decoder
 func NewDecoder(
 	config *common.Config,
-	schemaM *SchemaManager,
+	schemaM SchemaManager,
 	topic string,
 	tz *time.Location,
 ) codec.RowEventDecoder {
@@ -83,7 +85,7 @@ func (d *decoder) HasNext() (model.MessageType, bool, error) {
 		return model.MessageTypeUnknown, false, errors.ErrAvroInvalidMessage.FastGenByArgs()
 	}
 	switch d.value[0] {
-	case magicByte:
+	case magicByte, glueHeaderVersionByte:
 		return model.MessageTypeRow, true, nil
 	case ddlByte:
 		return model.MessageTypeDDL, true, nil
@@ -180,29 +182,30 @@ func assembleEvent(keyMap, valueMap, schema map[string]interface{}, isDelete boo
 			return nil, errors.New("schema field should be a map")
 		}
 
-		// `tidbOp` is the first extension field in the schema,
-		// it's not real columns, so break here.
+		// `tidbUpdatedColumns` or `tidbOp` is the first extension field in
+		// the schema, it's not real columns, so break here.
 		colName := field["name"].(string)
-		if colName == tidbOp {
+		if colName == tidbUpdatedColumns || colName == tidbOp {
 			break
 		}
 
 		// query the field to get `tidbType`, and get the mysql type from it.
-		var holder map[string]interface{}
+		var typeInfo map[string]interface{}
 		switch ty := field["type"].(type) {
 		case []interface{}:
 			if m, ok := ty[0].(map[string]interface{}); ok {
-				holder = m["connect.parameters"].(map[string]interface{})
+				typeInfo = m
 			} else if m, ok := ty[1].(map[string]interface{}); ok {
-				holder = m["connect.parameters"].(map[string]interface{})
+				typeInfo = m
 			} else {
 				log.Panic("type info is anything else", zap.Any("typeInfo", field["type"]))
 			}
 		case map[string]interface{}:
-			holder = ty["connect.parameters"].(map[string]interface{})
+			typeInfo = ty
 		default:
 			log.Panic("type info is anything else", zap.Any("typeInfo", field["type"]))
 		}
+		holder := typeInfo["connect.parameters"].(map[string]interface{})
 		tidbType := holder["tidb_type"].(string)
 
 		mysqlType := mysqlTypeFromTiDBType(tidbType)
@@ -216,7 +219,7 @@ func assembleEvent(keyMap, valueMap, schema map[string]interface{}, isDelete boo
 		if !ok {
 			return nil, errors.New("value not found")
 		}
-		value, err := getColumnValue(value, holder, mysqlType)
+		value, err := getColumnValue(value, typeInfo, holder, mysqlType)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -230,6 +233,8 @@ func assembleEvent(keyMap, valueMap, schema map[string]interface{}, isDelete boo
 		columns = append(columns, col)
 	}
 
+	columns = dropNotUpdatedColumns(columns, valueMap)
+
 	// "namespace.schema"
 	namespace := schema["namespace"].(string)
 	schemaName := strings.Split(namespace, ".")[1]
@@ -260,6 +265,29 @@ func assembleEvent(keyMap, valueMap, schema map[string]interface{}, isDelete boo
 	return event, nil
 }
 
+// dropNotUpdatedColumns drops the columns which are not updated according to
+// the bitmap of the updated columns, the handle key columns are always kept.
+func dropNotUpdatedColumns(
+	columns []*model.Column, valueMap map[string]interface{},
+) []*model.Column {
+	union, ok := valueMap[tidbUpdatedColumns].(map[string]interface{})
+	if !ok {
+		return columns
+	}
+	bitmap, ok := union["bytes"].([]byte)
+	if !ok {
+		return columns
+	}
+	result := columns[:0]
+	for i, col := range columns {
+		updated := i/8 < len(bitmap) && bitmap[i/8]&(1<<(i%8)) != 0
+		if updated || col.Flag.IsHandleKey() {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
 func isCorrupted(valueMap map[string]interface{}) bool {
 	o, ok := valueMap[tidbCorrupted]
 	if !ok {
@@ -289,8 +317,10 @@ func extractExpectedChecksum(valueMap map[string]interface{}) (uint64, bool, err
 }
 
 // value is an interface, need to convert it to the real value with the help of type info.
-// holder has the value's column info.
-func getColumnValue(value interface{}, holder map[string]interface{}, mysqlType byte) (interface{}, error) {
+// typeInfo is the avro schema of the value, and holder has the value's column info.
+func getColumnValue(
+	value interface{}, typeInfo, holder map[string]interface{}, mysqlType byte,
+) (interface{}, error) {
 	switch t := value.(type) {
 	// for nullable columns, the value is encoded as a map with one pair.
 	// key is the encoded type, value is the encoded value, only care about the value here.
@@ -329,6 +359,17 @@ func getColumnValue(value interface{}, holder map[string]interface{}, mysqlType
 		case nil:
 			value = nil
 		}
+	case mysql.TypeNewDecimal:
+		// decimal type is encoded as the decimal logical type if
+		// decimalHandlingMode set to precise, it's decoded as *big.Rat,
+		// convert it to string with the scale of the logical type.
+		if v, ok := value.(*big.Rat); ok {
+			scale, ok := typeInfo["scale"].(float64)
+			if !ok {
+				return nil, errors.New("scale of the decimal logical type not found")
+			}
+			value = v.FloatString(int(scale))
+		}
 	}
 	return value, nil
 }
@@ -376,25 +417,55 @@ func (d *decoder) NextDDLEvent() (*model.DDLEvent, error) {
 // return the schema ID and the encoded binary data
 // schemaID can be used to fetch the corresponding schema from schema registry,
 // which should be used to decode the binary data.
-func extractSchemaIDAndBinaryData(data []byte) (int, []byte, error) {
-	if len(data) < 5 {
-		return 0, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+func extractSchemaIDAndBinaryData(data []byte) (schemaID, []byte, error) {
+	if len(data) < 1 {
+		return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
 	}
-	if data[0] != magicByte {
-		return 0, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+	switch data[0] {
+	case magicByte:
+		if len(data) < 5 {
+			return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+		}
+		return schemaID{confluentSchemaID: int32(binary.BigEndian.Uint32(data[1:5]))}, data[5:], nil
+	case glueHeaderVersionByte:
+		headerLen := 2 + glueSchemaVersionIDLen
+		if len(data) < headerLen || data[1] != glueCompressionNone {
+			return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+		}
+		id, err := uuid.FromBytes(data[2:headerLen])
+		if err != nil {
+			return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+		}
+		return schemaID{glueSchemaID: id.String()}, data[headerLen:], nil
 	}
-	return int(binary.BigEndian.Uint32(data[1:5])), data[5:], nil
+	return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
 }
 
-func decodeRawBytes(
-	ctx context.Context, schemaM *SchemaManager, data []byte, topic string,
+// extractApicurioSchemaIDAndBinaryData is extractSchemaIDAndBinaryData of the
+// messages whose schemas are registered to the Apicurio Registry, since their
+// magic byte is the same as the confluent one but followed by a longer ID.
+func extractApicurioSchemaIDAndBinaryData(data []byte) (schemaID, []byte, error) {
+	headerLen := 1 + apicurioGlobalIDLen
+	if len(data) < headerLen || data[0] != magicByte {
+		return schemaID{}, nil, errors.ErrAvroInvalidMessage.FastGenByArgs()
+	}
+	return schemaID{apicurioGlobalID: int64(binary.BigEndian.Uint64(data[1:headerLen]))},
+		data[headerLen:], nil
+}
+
+func (d *decoder) decodeRawBytes(
+	ctx context.Context, data []byte,
 ) (map[string]interface{}, map[string]interface{}, error) {
-	schemaID, binary, err := extractSchemaIDAndBinaryData(data)
+	extract := extractSchemaIDAndBinaryData
+	if d.config.AvroSchemaRegistryConfig.IsApicurio() {
+		extract = extractApicurioSchemaIDAndBinaryData
+	}
+	schemaID, binary, err := extract(data)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	codec, err := schemaM.Lookup(ctx, topic, schemaID)
+	codec, err := d.schemaM.Lookup(ctx, d.topic, schemaID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -420,13 +491,13 @@ func decodeRawBytes(
 func (d *decoder) decodeKey(ctx context.Context) (map[string]interface{}, map[string]interface{}, error) {
 	data := d.key
 	d.key = nil
-	return decodeRawBytes(ctx, d.schemaM, data, d.topic)
+	return d.decodeRawBytes(ctx, data)
 }
 
 func (d *decoder) decodeValue(ctx context.Context) (map[string]interface{}, map[string]interface{}, error) {
 	data := d.value
 	d.value = nil
-	return decodeRawBytes(ctx, d.schemaM, data, d.topic)
+	return d.decodeRawBytes(ctx, data)
 }
 
 // calculate the checksum value, and compare it with the expected one, return error if not identical.
@@ -15,11 +15,15 @@ package avro
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
 
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -78,6 +82,168 @@ func TestDecodeEvent(t *testing.T) {
 	decodedEvent, err := decoder.NextRowChangedEvent()
 	require.NoError(t, err)
 	require.NotNil(t, decodedEvent)
+
+	// the decimal value is decoded from the decimal logical type with the
+	// scale of the column, which is 0 by default.
+	for _, col := range decodedEvent.Columns {
+		switch col.Name {
+		case "decimal":
+			require.Equal(t, "129012", col.Value)
+		case "decimalnullable":
+			require.Nil(t, col.Value)
+		}
+	}
+}
+
+func TestDecodeScaledDecimal(t *testing.T) {
+	config := &common.Config{
+		MaxMessageBytes:                1024 * 1024,
+		EnableTiDBExtension:            true,
+		AvroDecimalHandlingMode:        "precise",
+		AvroBigintUnsignedHandlingMode: "long",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, config)
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+
+	// the decimal is encoded as a scaled *big.Rat of the decimal logical type.
+	ft := types.NewFieldType(mysql.TypeNewDecimal)
+	ft.SetFlen(10)
+	ft.SetDecimal(3)
+	values := []string{"12345.678", "-0.050", "0.000"}
+	event := &model.RowChangedEvent{
+		CommitTs:  417318403368288260,
+		Table:     &model.TableName{Schema: "testdb", Table: "decimal"},
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "testdb", Table: "decimal"}},
+		Columns: []*model.Column{
+			{Name: "id", Value: int64(1), Type: mysql.TypeLong, Flag: model.HandleKeyFlag},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, IsPKHandle: true, Ft: types.NewFieldType(mysql.TypeLong)},
+		},
+	}
+	for i, value := range values {
+		event.Columns = append(event.Columns, &model.Column{
+			Name: fmt.Sprintf("d%d", i), Value: value, Type: mysql.TypeNewDecimal,
+		})
+		event.ColInfos = append(event.ColInfos, rowcodec.ColInfo{ID: int64(i + 2), Ft: ft})
+	}
+
+	topic := "avro-test-topic"
+	err = encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+	require.NoError(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	schemaM, err := NewAvroSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+	require.NoError(t, err)
+	tz, err := util.GetLocalTimezone()
+	require.NoError(t, err)
+	decoder := NewDecoder(config, schemaM, topic, tz)
+	err = decoder.AddKeyValue(messages[0].Key, messages[0].Value)
+	require.NoError(t, err)
+	_, exist, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, exist)
+	decodedEvent, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	// the values are decoded with the scale of the column.
+	decoded := make(map[string]interface{})
+	for _, col := range decodedEvent.Columns {
+		decoded[col.Name] = col.Value
+	}
+	for i, value := range values {
+		require.Equal(t, value, decoded[fmt.Sprintf("d%d", i)])
+	}
+}
+
+func TestDecodeEventOnlyUpdatedColumns(t *testing.T) {
+	config := &common.Config{
+		MaxMessageBytes:                1024 * 1024,
+		EnableTiDBExtension:            true,
+		AvroDecimalHandlingMode:        "precise",
+		AvroBigintUnsignedHandlingMode: "long",
+		OnlyOutputUpdatedColumns:       true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	encoder, err := SetupEncoderAndSchemaRegistry4Testing(ctx, config)
+	defer TeardownEncoderAndSchemaRegistry4Testing()
+	require.NoError(t, err)
+	require.NotNil(t, encoder)
+
+	// only the float column is updated.
+	event := newLargeEvent()
+	event.PreColumns = make([]*model.Column, 0, len(event.Columns))
+	for _, col := range event.Columns {
+		pre := *col
+		if pre.Name == "float" {
+			pre.Value = nil
+		}
+		event.PreColumns = append(event.PreColumns, &pre)
+	}
+
+	topic := "avro-test-topic"
+	bin, err := encoder.encodeValue(ctx, topic, event)
+	require.NoError(t, err)
+	schemaID, data, err := extractSchemaIDAndBinaryData(bin)
+	require.NoError(t, err)
+	avroValueCodec, err := encoder.schemaM.Lookup(ctx, topic, schemaID)
+	require.NoError(t, err)
+	res, _, err := avroValueCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+	native := res.(map[string]interface{})
+	require.Equal(t, int32(1), native["id"])
+	require.Equal(t, map[string]interface{}{"float": float32(3.14)}, native["float"])
+	require.Nil(t, native["tiny"])
+	for i, col := range event.Columns {
+		if col.Name == "float" {
+			bitmap := native[tidbUpdatedColumns].(map[string]interface{})["bytes"].([]byte)
+			require.Len(t, bitmap, (len(event.Columns)+7)/8)
+			require.Equal(t, byte(1<<(i%8)), bitmap[i/8])
+		}
+	}
+
+	err = encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+	require.NoError(t, err)
+	message := encoder.Build()[0]
+
+	schemaM, err := NewAvroSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+	require.NoError(t, err)
+	tz, err := util.GetLocalTimezone()
+	require.NoError(t, err)
+	decoder := NewDecoder(config, schemaM, topic, tz)
+	err = decoder.AddKeyValue(message.Key, message.Value)
+	require.NoError(t, err)
+	_, exist, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, exist)
+	decodedEvent, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	// the not updated columns are dropped.
+	require.Len(t, decodedEvent.Columns, 2)
+	require.Equal(t, "id", decodedEvent.Columns[0].Name)
+	require.Equal(t, "float", decodedEvent.Columns[1].Name)
+
+	// the bitmap is null for the insert events.
+	event.PreColumns = nil
+	bin, err = encoder.encodeValue(ctx, topic, event)
+	require.NoError(t, err)
+	_, data, err = extractSchemaIDAndBinaryData(bin)
+	require.NoError(t, err)
+	res, _, err = avroValueCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+	native = res.(map[string]interface{})
+	require.Nil(t, native[tidbUpdatedColumns])
+	require.Equal(t, map[string]interface{}{"int": int32(1)}, native["tiny"])
 }
 
 func TestDecodeDDLEvent(t *testing.T) {
@@ -0,0 +1,247 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const defaultGluePollInterval = 500 * time.Millisecond
+
+// glueSchemaManager is the SchemaManager of the AWS Glue Schema Registry,
+// each subject is the name of a schema in the registry, whose versions are
+// identified by the UUIDs.
+type glueSchemaManager struct {
+	registryName string
+	schemaType   string
+	client       glueiface.GlueAPI
+	// pollInterval is the interval to poll the status of the schema versions
+	// which are pending on the compatibility check.
+	pollInterval time.Duration
+
+	cache *schemaCache
+}
+
+// newGlueSchemaManagerWithConfig creates the schema manager of the AWS Glue
+// Schema Registry, and checks the registry exists.
+func newGlueSchemaManagerWithConfig(
+	ctx context.Context,
+	registryConfig *config.SchemaRegistryConfig,
+	schemaType string,
+) (SchemaManager, error) {
+	awsConfig := aws.NewConfig()
+	if region := util.GetOrZero(registryConfig.GlueRegion); region != "" {
+		awsConfig = awsConfig.WithRegion(region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	return newGlueSchemaManager(
+		ctx, glue.New(sess), util.GetOrZero(registryConfig.GlueRegistryName), schemaType)
+}
+
+func newGlueSchemaManager(
+	ctx context.Context, client glueiface.GlueAPI, registryName string, schemaType string,
+) (*glueSchemaManager, error) {
+	_, err := client.GetRegistryWithContext(ctx, &glue.GetRegistryInput{
+		RegistryId: &glue.RegistryId{RegistryName: aws.String(registryName)},
+	})
+	if err != nil {
+		log.Error("Test connection to Glue Schema Registry failed",
+			zap.String("registryName", registryName), zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	log.Info("Successfully tested connectivity to Glue Schema Registry",
+		zap.String("registryName", registryName))
+
+	return &glueSchemaManager{
+		registryName: registryName,
+		schemaType:   schemaType,
+		client:       client,
+		pollInterval: defaultGluePollInterval,
+		cache:        newSchemaCache(),
+	}, nil
+}
+
+func (m *glueSchemaManager) schemaIDOf(schemaSubject string) *glue.SchemaId {
+	return &glue.SchemaId{
+		RegistryName: aws.String(m.registryName),
+		SchemaName:   aws.String(schemaSubject),
+	}
+}
+
+// Register a schema as a version of the schema named by the subject, which
+// is created with the backward compatibility if it doesn't exist.
+// Re-registering an existing schema returns the same version.
+func (m *glueSchemaManager) Register(
+	ctx context.Context,
+	schemaSubject string,
+	schema string,
+) (schemaID, error) {
+	var versionID, status string
+	resp, err := m.client.RegisterSchemaVersionWithContext(ctx, &glue.RegisterSchemaVersionInput{
+		SchemaId:         m.schemaIDOf(schemaSubject),
+		SchemaDefinition: aws.String(schema),
+	})
+	if isGlueEntityNotFound(err) {
+		log.Info("Creating schema in Glue Schema Registry",
+			zap.String("registryName", m.registryName),
+			zap.String("schemaName", schemaSubject))
+		created, err := m.client.CreateSchemaWithContext(ctx, &glue.CreateSchemaInput{
+			RegistryId:       &glue.RegistryId{RegistryName: aws.String(m.registryName)},
+			SchemaName:       aws.String(schemaSubject),
+			DataFormat:       aws.String(m.schemaType),
+			Compatibility:    aws.String(glue.CompatibilityBackward),
+			SchemaDefinition: aws.String(schema),
+		})
+		if err != nil {
+			log.Error("Failed to create schema in Glue Schema Registry", zap.Error(err))
+			return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		}
+		versionID = aws.StringValue(created.SchemaVersionId)
+		status = aws.StringValue(created.SchemaVersionStatus)
+	} else if err != nil {
+		log.Error("Failed to register schema to Glue Schema Registry", zap.Error(err))
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	} else {
+		versionID = aws.StringValue(resp.SchemaVersionId)
+		status = aws.StringValue(resp.Status)
+	}
+
+	// the compatibility of the new versions is checked asynchronously.
+	for status == glue.SchemaVersionStatusPending {
+		select {
+		case <-ctx.Done():
+			return schemaID{}, errors.Trace(ctx.Err())
+		case <-time.After(m.pollInterval):
+		}
+		version, err := m.client.GetSchemaVersionWithContext(ctx, &glue.GetSchemaVersionInput{
+			SchemaVersionId: aws.String(versionID),
+		})
+		if err != nil {
+			log.Error("Failed to get schema version from Glue Schema Registry", zap.Error(err))
+			return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		}
+		status = aws.StringValue(version.Status)
+	}
+	if status != glue.SchemaVersionStatusAvailable {
+		log.Error("Failed to register schema to Glue Schema Registry",
+			zap.String("schemaName", schemaSubject),
+			zap.String("schemaVersionID", versionID),
+			zap.String("status", status))
+		if status == glue.SchemaVersionStatusFailure {
+			return schemaID{}, cerror.ErrAvroIncompatibleSchema.GenWithStackByArgs(schemaSubject)
+		}
+		return schemaID{}, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Unexpected status %s of schema version %s in Glue Schema Registry", status, versionID)
+	}
+
+	log.Info("Registered schema to Glue Schema Registry successfully",
+		zap.String("schemaName", schemaSubject),
+		zap.String("schemaVersionID", versionID))
+	return schemaID{glueSchemaID: versionID}, nil
+}
+
+// Lookup the cached schema entry first, if not found, fetch it by the
+// version ID from the registry.
+func (m *glueSchemaManager) Lookup(
+	ctx context.Context,
+	schemaSubject string,
+	schemaID schemaID,
+) (*goavro.Codec, error) {
+	entry, exists := m.cache.get(schemaSubject)
+	if exists && entry.schemaID == schemaID {
+		return entry.codec, nil
+	}
+
+	log.Info("Avro schema lookup cache miss",
+		zap.String("key", schemaSubject),
+		zap.String("schemaVersionID", schemaID.glueSchemaID))
+	version, err := m.client.GetSchemaVersionWithContext(ctx, &glue.GetSchemaVersionInput{
+		SchemaVersionId: aws.String(schemaID.glueSchemaID),
+	})
+	if err != nil {
+		log.Error("Failed to get schema version from Glue Schema Registry", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+
+	cacheEntry := new(schemaCacheEntry)
+	cacheEntry.codec, err = goavro.NewCodec(aws.StringValue(version.SchemaDefinition))
+	if err != nil {
+		log.Error("Creating Avro codec failed", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	cacheEntry.schemaID = schemaID
+	m.cache.put(schemaSubject, cacheEntry)
+	return cacheEntry.codec, nil
+}
+
+// GetCachedOrRegister checks if the suitable Avro schema has been cached.
+// If not, a new schema is generated, registered and cached.
+func (m *glueSchemaManager) GetCachedOrRegister(
+	ctx context.Context,
+	schemaSubject string,
+	tableVersion uint64,
+	schemaGen SchemaGenerator,
+) (*goavro.Codec, []byte, error) {
+	return getCachedOrRegister(ctx, m, m.cache, m.schemaType, schemaSubject, tableVersion, schemaGen)
+}
+
+// CheckCompatibility always returns true, since the Glue Schema Registry
+// can't check the compatibility without registering the schema, the
+// incompatible schemas are rejected when they're registered.
+func (m *glueSchemaManager) CheckCompatibility(
+	_ context.Context,
+	_ string,
+	_ string,
+) (bool, []string, error) {
+	return true, nil, nil
+}
+
+// ClearRegistry deletes the schema named by the subject and all its versions.
+func (m *glueSchemaManager) ClearRegistry(ctx context.Context, schemaSubject string) error {
+	_, err := m.client.DeleteSchemaWithContext(ctx, &glue.DeleteSchemaInput{
+		SchemaId: m.schemaIDOf(schemaSubject),
+	})
+	if err != nil && !isGlueEntityNotFound(err) {
+		log.Error("Error when clearing Glue Schema Registry", zap.Error(err))
+		return cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	log.Info("Clearing Glue Schema Registry successful",
+		zap.String("schemaName", schemaSubject))
+	return nil
+}
+
+func isGlueEntityNotFound(err error) bool {
+	aerr, ok := errors.Cause(err).(awserr.Error) // nolint:errorlint
+	return ok && aerr.Code() == glue.ErrCodeEntityNotFoundException
+}
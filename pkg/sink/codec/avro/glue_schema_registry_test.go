@@ -0,0 +1,180 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/google/uuid"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGlueVersion struct {
+	id         string
+	definition string
+	status     string
+}
+
+// mockGlueClient is a Glue Schema Registry keeping the versions in memory,
+// the new versions of the existing schemas are pending before they're got.
+type mockGlueClient struct {
+	glueiface.GlueAPI
+
+	mu       sync.Mutex
+	schemas  map[string][]*mockGlueVersion
+	versions map[string]*mockGlueVersion
+	// incompatible makes the new versions fail the compatibility check.
+	incompatible bool
+}
+
+func newMockGlueClient() *mockGlueClient {
+	return &mockGlueClient{
+		schemas:  make(map[string][]*mockGlueVersion),
+		versions: make(map[string]*mockGlueVersion),
+	}
+}
+
+func (c *mockGlueClient) GetRegistryWithContext(
+	_ aws.Context, input *glue.GetRegistryInput, _ ...request.Option,
+) (*glue.GetRegistryOutput, error) {
+	return &glue.GetRegistryOutput{RegistryName: input.RegistryId.RegistryName}, nil
+}
+
+func (c *mockGlueClient) addVersion(name, definition, status string) *mockGlueVersion {
+	version := &mockGlueVersion{
+		id:         uuid.New().String(),
+		definition: definition,
+		status:     status,
+	}
+	c.schemas[name] = append(c.schemas[name], version)
+	c.versions[version.id] = version
+	return version
+}
+
+func (c *mockGlueClient) CreateSchemaWithContext(
+	_ aws.Context, input *glue.CreateSchemaInput, _ ...request.Option,
+) (*glue.CreateSchemaOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	version := c.addVersion(
+		*input.SchemaName, *input.SchemaDefinition, glue.SchemaVersionStatusAvailable)
+	return &glue.CreateSchemaOutput{
+		SchemaVersionId:     aws.String(version.id),
+		SchemaVersionStatus: aws.String(version.status),
+	}, nil
+}
+
+func (c *mockGlueClient) RegisterSchemaVersionWithContext(
+	_ aws.Context, input *glue.RegisterSchemaVersionInput, _ ...request.Option,
+) (*glue.RegisterSchemaVersionOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	versions, ok := c.schemas[*input.SchemaId.SchemaName]
+	if !ok {
+		return nil, awserr.New(glue.ErrCodeEntityNotFoundException, "schema not found", nil)
+	}
+	for _, version := range versions {
+		if version.definition == *input.SchemaDefinition {
+			return &glue.RegisterSchemaVersionOutput{
+				SchemaVersionId: aws.String(version.id),
+				Status:          aws.String(version.status),
+			}, nil
+		}
+	}
+	version := c.addVersion(
+		*input.SchemaId.SchemaName, *input.SchemaDefinition, glue.SchemaVersionStatusPending)
+	return &glue.RegisterSchemaVersionOutput{
+		SchemaVersionId: aws.String(version.id),
+		Status:          aws.String(version.status),
+	}, nil
+}
+
+func (c *mockGlueClient) GetSchemaVersionWithContext(
+	_ aws.Context, input *glue.GetSchemaVersionInput, _ ...request.Option,
+) (*glue.GetSchemaVersionOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	version, ok := c.versions[*input.SchemaVersionId]
+	if !ok {
+		return nil, awserr.New(glue.ErrCodeEntityNotFoundException, "version not found", nil)
+	}
+	if version.status == glue.SchemaVersionStatusPending {
+		version.status = glue.SchemaVersionStatusAvailable
+		if c.incompatible {
+			version.status = glue.SchemaVersionStatusFailure
+		}
+	}
+	return &glue.GetSchemaVersionOutput{
+		SchemaVersionId:  aws.String(version.id),
+		SchemaDefinition: aws.String(version.definition),
+		Status:           aws.String(version.status),
+	}, nil
+}
+
+func TestGlueSchemaRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client := newMockGlueClient()
+	manager, err := newGlueSchemaManager(ctx, client, "ticdc", schemaTypeAvro)
+	require.NoError(t, err)
+	manager.pollInterval = time.Millisecond
+
+	schema := `{"type":"record","name":"test","fields":[{"name":"field1","type":"string"}]}`
+	schemaGen := func() (string, error) { return schema, nil }
+	subject := "cdctest-value"
+
+	codec, header, err := manager.GetCachedOrRegister(ctx, subject, 1, schemaGen)
+	require.NoError(t, err)
+	require.Len(t, header, 2+glueSchemaVersionIDLen)
+	require.Equal(t, glueHeaderVersionByte, header[0])
+	require.Equal(t, glueCompressionNone, header[1])
+
+	id, data, err := extractSchemaIDAndBinaryData(
+		append(append([]byte{}, header...), 1))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, data)
+	require.Contains(t, client.versions, id.glueSchemaID)
+
+	// re-registering the same schema returns the same version.
+	id2, err := manager.Register(ctx, subject, schema)
+	require.NoError(t, err)
+	require.Equal(t, id, id2)
+
+	codec2, err := manager.Lookup(ctx, subject, id)
+	require.NoError(t, err)
+	require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
+
+	// the new version is pending until its compatibility is checked.
+	schema = `{"type":"record","name":"test","fields":[{"name":"field1","type":"string"},` +
+		`{"name":"field2","type":["null","string"],"default":null}]}`
+	_, header2, err := manager.GetCachedOrRegister(ctx, subject, 2, schemaGen)
+	require.NoError(t, err)
+	require.NotEqual(t, header, header2)
+
+	client.incompatible = true
+	schema = `{"type":"record","name":"test","fields":[{"name":"field1","type":"long"}]}`
+	_, _, err = manager.GetCachedOrRegister(ctx, subject, 3, schemaGen)
+	require.True(t, cerror.ErrAvroIncompatibleSchema.Equal(err))
+}
@@ -0,0 +1,321 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// jsonSchemaDraft is the draft of the JSON schemas, which is supported by
+// all the schema registries.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchemaEncoder converts the events to plain JSON values whose schemas
+// are registered in the schema registry, the values are prepended with the
+// same header as the avro messages to carry their schema IDs.
+type JSONSchemaEncoder struct {
+	// avro shares the schema registry, the subject naming and the column
+	// conversion of the avro protocol, which outputs the same columns.
+	avro *BatchEncoder
+}
+
+type jsonSchemaTop struct {
+	Schema               string                     `json:"$schema"`
+	Title                string                     `json:"title"`
+	Type                 string                     `json:"type"`
+	Properties           map[string]*jsonSchemaType `json:"properties"`
+	Required             []string                   `json:"required,omitempty"`
+	AdditionalProperties bool                       `json:"additionalProperties"`
+}
+
+type jsonSchemaType struct {
+	// Type is a type name or the list of the type name and "null".
+	Type            interface{} `json:"type"`
+	ContentEncoding string      `json:"contentEncoding,omitempty"`
+	TiDBType        string      `json:"tidb_type,omitempty"`
+}
+
+// NewJSONSchemaEncoder return a json-schema encoder.
+func NewJSONSchemaEncoder(
+	namespace string, schemaM SchemaManager, config *common.Config,
+) codec.RowEventEncoder {
+	return &JSONSchemaEncoder{avro: newBatchEncoder(namespace, schemaM, config)}
+}
+
+// AppendRowChangedEvent appends a row change event to the encoder
+// NOTE: the encoder can only store one RowChangedEvent!
+func (j *JSONSchemaEncoder) AppendRowChangedEvent(
+	ctx context.Context,
+	topic string,
+	e *model.RowChangedEvent,
+	callback func(),
+) error {
+	topic = sanitizeTopic(topic)
+
+	key, err := j.encodeKey(ctx, topic, e)
+	if err != nil {
+		log.Error("json-schema encoding key failed", zap.Error(err))
+		return errors.Trace(err)
+	}
+
+	value, err := j.encodeValue(ctx, topic, e)
+	if err != nil {
+		log.Error("json-schema encoding value failed", zap.Error(err))
+		return errors.Trace(err)
+	}
+
+	message := common.NewMsg(
+		config.ProtocolJSONSchema,
+		key,
+		value,
+		e.CommitTs,
+		model.MessageTypeRow,
+		&e.Table.Schema,
+		&e.Table.Table,
+	)
+	message.Callback = callback
+	message.IncRowsCount()
+
+	if message.Length() > j.avro.config.MaxMessageBytes {
+		log.Warn("Single message is too large for json-schema",
+			zap.Int("maxMessageBytes", j.avro.config.MaxMessageBytes),
+			zap.Int("length", message.Length()),
+			zap.Any("table", e.Table))
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs(message.Length())
+	}
+
+	j.avro.result = append(j.avro.result, message)
+	return nil
+}
+
+// EncodeCheckpointEvent is no-op, the json-schema protocol doesn't send the
+// checkpoint events.
+func (j *JSONSchemaEncoder) EncodeCheckpointEvent(_ uint64) (*common.Message, error) {
+	return nil, nil
+}
+
+// EncodeDDLEvent is no-op, the schemas of the tables are registered when
+// their rows are encoded.
+func (j *JSONSchemaEncoder) EncodeDDLEvent(_ *model.DDLEvent) (*common.Message, error) {
+	return nil, nil
+}
+
+// Build Messages
+func (j *JSONSchemaEncoder) Build() []*common.Message {
+	return j.avro.Build()
+}
+
+func (j *JSONSchemaEncoder) encodeKey(
+	ctx context.Context, topic string, e *model.RowChangedEvent,
+) ([]byte, error) {
+	cols, colInfos := e.HandleKeyColInfos()
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	input := &avroEncodeInput{columns: cols, colInfos: colInfos}
+	return j.encode(ctx, j.avro.subject(topic, e.Table, keySchemaSuffix),
+		e.Table, e.TableInfo.Version, input, nil)
+}
+
+func (j *JSONSchemaEncoder) encodeValue(
+	ctx context.Context, topic string, e *model.RowChangedEvent,
+) ([]byte, error) {
+	// the deletes are tombstones like the avro protocol.
+	if e.IsDelete() || len(e.Columns) == 0 {
+		return nil, nil
+	}
+	input := &avroEncodeInput{columns: e.Columns, colInfos: e.ColInfos}
+	return j.encode(ctx, j.avro.subject(topic, e.Table, valueSchemaSuffix),
+		e.Table, e.TableInfo.Version, input, e)
+}
+
+// encode registers the schema of the columns to the subject and encodes them
+// with the header, the TiDB extension fields are appended if e is not nil.
+func (j *JSONSchemaEncoder) encode(
+	ctx context.Context,
+	subject string,
+	tableName *model.TableName,
+	tableVersion uint64,
+	input *avroEncodeInput,
+	e *model.RowChangedEvent,
+) ([]byte, error) {
+	withExtension := e != nil && j.avro.config.EnableTiDBExtension
+	schemaGen := func() (string, error) {
+		schema, err := j.columns2JSONSchema(tableName, input, withExtension)
+		if err != nil {
+			log.Error("json-schema: generating schema failed", zap.Error(err))
+			return "", errors.Trace(err)
+		}
+		return schema, nil
+	}
+	_, header, err := j.avro.schemaM.GetCachedOrRegister(ctx, subject, tableVersion, schemaGen)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	value := make(map[string]interface{}, len(input.columns))
+	for i, col := range input.columns {
+		if col == nil {
+			continue
+		}
+		data, err := j.columnToJSONData(col, input.colInfos[i].Ft)
+		if err != nil {
+			log.Error("json-schema: converting column to JSON failed", zap.Error(err))
+			return nil, errors.Trace(err)
+		}
+		value[col.Name] = data
+	}
+	if withExtension {
+		value[tidbOp] = getOperation(e)
+		value[tidbCommitTs] = e.CommitTs
+		value[tidbPhysicalTime] = oracle.ExtractPhysical(e.CommitTs)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	result := &avroEncodeResult{data: data, header: header}
+	return result.toEnvelope()
+}
+
+func (j *JSONSchemaEncoder) columns2JSONSchema(
+	tableName *model.TableName,
+	input *avroEncodeInput,
+	withExtension bool,
+) (string, error) {
+	top := &jsonSchemaTop{
+		Schema: jsonSchemaDraft,
+		Title: getAvroNamespace(j.avro.namespace, tableName.Schema) + "." +
+			sanitizeName(tableName.Table),
+		Type:       "object",
+		Properties: make(map[string]*jsonSchemaType, len(input.columns)),
+	}
+	for i, col := range input.columns {
+		if col == nil {
+			continue
+		}
+		avroType, err := j.avro.columnToAvroSchema(col, input.colInfos[i].Ft)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		field := avroType2JSONSchemaType(avroType)
+		if col.Flag.IsNullable() {
+			field.Type = []interface{}{field.Type, "null"}
+		}
+		top.Properties[col.Name] = field
+		top.Required = append(top.Required, col.Name)
+	}
+	if withExtension {
+		top.Properties[tidbOp] = &jsonSchemaType{Type: "string"}
+		top.Properties[tidbCommitTs] = &jsonSchemaType{Type: "integer"}
+		top.Properties[tidbPhysicalTime] = &jsonSchemaType{Type: "integer"}
+	}
+
+	str, err := json.Marshal(top)
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrAvroMarshalFailed, err)
+	}
+	log.Info("json-schema: row to schema", zap.ByteString("schema", str))
+	return string(str), nil
+}
+
+// avroType2JSONSchemaType converts the avro type of a column to the JSON
+// schema type, the bytes are encoded in base64 by encoding/json.
+func avroType2JSONSchemaType(avroType interface{}) *jsonSchemaType {
+	switch t := avroType.(type) {
+	case avroLogicalTypeSchema:
+		// the milliseconds are output as the JSON integers, and the precise
+		// decimals are output as the JSON numbers.
+		if t.LogicalType == "timestamp-millis" {
+			return &jsonSchemaType{Type: "integer", TiDBType: t.Parameters[tidbType]}
+		}
+		return &jsonSchemaType{Type: "number", TiDBType: t.Parameters[tidbType]}
+	case avroSchema:
+		result := &jsonSchemaType{TiDBType: t.Parameters[tidbType]}
+		switch t.Type {
+		case "int", "long":
+			result.Type = "integer"
+		case "float", "double":
+			result.Type = "number"
+		case "boolean":
+			result.Type = "boolean"
+		case "bytes":
+			result.Type = "string"
+			result.ContentEncoding = "base64"
+		default:
+			result.Type = "string"
+		}
+		return result
+	}
+	return &jsonSchemaType{Type: "string"}
+}
+
+func (j *JSONSchemaEncoder) columnToJSONData(
+	col *model.Column, ft *types.FieldType,
+) (interface{}, error) {
+	if col.Value != nil && col.Type == mysql.TypeNewDecimal &&
+		j.avro.config.AvroDecimalHandlingMode == common.DecimalHandlingModePrecise {
+		return json.Number(col.Value.(string)), nil
+	}
+	data, _, err := j.avro.columnToAvroData(col, ft)
+	return data, err
+}
+
+type jsonSchemaEncoderBuilder struct {
+	namespace string
+	config    *common.Config
+	schemaM   SchemaManager
+	subjects  subjectNamer
+}
+
+// NewJSONSchemaEncoderBuilder creates a json-schema encoder builder.
+func NewJSONSchemaEncoderBuilder(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	config *common.Config,
+) (codec.RowEventEncoderBuilder, error) {
+	schemaM, err := NewJSONSchemaManager(
+		ctx, config.AvroSchemaRegistry, config.AvroSchemaRegistryConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &jsonSchemaEncoderBuilder{
+		namespace: changefeedID.Namespace,
+		config:    config,
+		schemaM:   schemaM,
+		subjects:  newSubjectNamer(changefeedID, config.AvroSchemaRegistryConfig),
+	}, nil
+}
+
+// Build a JSONSchemaEncoder.
+func (b *jsonSchemaEncoderBuilder) Build() codec.RowEventEncoder {
+	encoder := newBatchEncoder(b.namespace, b.schemaM, b.config)
+	encoder.subjects = b.subjects
+	return &JSONSchemaEncoder{avro: encoder}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestJSONSchemaEncode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	startHTTPInterceptForTestingRegistry()
+	defer stopHTTPInterceptForTestingRegistry()
+	schemaM, err := NewJSONSchemaManager(ctx, "http://127.0.0.1:8081", nil)
+	require.NoError(t, err)
+
+	codecConfig := common.NewConfig(config.ProtocolJSONSchema)
+	codecConfig.EnableTiDBExtension = true
+	codecConfig.AvroDecimalHandlingMode = common.DecimalHandlingModePrecise
+	encoder := NewJSONSchemaEncoder(model.DefaultNamespace, schemaM, codecConfig).(*JSONSchemaEncoder)
+
+	tableName := model.TableName{Schema: "test", Table: "t"}
+	row := &model.RowChangedEvent{
+		CommitTs:  417318403368288260,
+		Table:     &tableName,
+		TableInfo: &model.TableInfo{TableName: tableName, Version: 1},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("tidb"), Flag: model.NullableFlag},
+			{Name: "price", Type: mysql.TypeNewDecimal, Value: "12.50", Flag: model.NullableFlag},
+			{Name: "data", Type: mysql.TypeBlob, Value: []byte{1, 2}, Flag: model.NullableFlag | model.BinaryFlag},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, IsPKHandle: true, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 3, Ft: types.NewFieldType(mysql.TypeNewDecimal)},
+			{ID: 4, Ft: types.NewFieldType(mysql.TypeBlob)},
+		},
+	}
+
+	input := &avroEncodeInput{columns: row.Columns, colInfos: row.ColInfos}
+	schema, err := encoder.columns2JSONSchema(&tableName, input, true)
+	require.NoError(t, err)
+	var top jsonSchemaTop
+	require.NoError(t, json.Unmarshal([]byte(schema), &top))
+	require.Equal(t, jsonSchemaDraft, top.Schema)
+	require.Equal(t, "default.test.t", top.Title)
+	require.Equal(t, []string{"id", "name", "price", "data"}, top.Required)
+	require.Equal(t, "integer", top.Properties["id"].Type)
+	require.Equal(t, []interface{}{"string", "null"}, top.Properties["name"].Type)
+	require.Equal(t, []interface{}{"number", "null"}, top.Properties["price"].Type)
+	require.Equal(t, "base64", top.Properties["data"].ContentEncoding)
+	require.Equal(t, "integer", top.Properties[tidbCommitTs].Type)
+
+	err = encoder.AppendRowChangedEvent(ctx, "test.t", row, nil)
+	require.NoError(t, err)
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	require.Equal(t, config.ProtocolJSONSchema, msgs[0].Protocol)
+
+	keyID, key, err := extractSchemaIDAndBinaryData(msgs[0].Key)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(key))
+	valueID, value, err := extractSchemaIDAndBinaryData(msgs[0].Value)
+	require.NoError(t, err)
+	require.NotEqual(t, keyID, valueID)
+	require.JSONEq(t, fmt.Sprintf(`{"id":1,"name":"tidb","price":12.50,"data":"AQI=",`+
+		`"_tidb_op":"c","_tidb_commit_ts":%d,"_tidb_commit_physical_time":%d}`,
+		row.CommitTs, oracle.ExtractPhysical(row.CommitTs)), string(value))
+
+	// the deletes are tombstones.
+	row.PreColumns, row.Columns = row.Columns, nil
+	err = encoder.AppendRowChangedEvent(ctx, "test.t", row, nil)
+	require.NoError(t, err)
+	msgs = encoder.Build()
+	require.Len(t, msgs, 1)
+	_, key, err = extractSchemaIDAndBinaryData(msgs[0].Key)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(key))
+	require.Nil(t, msgs[0].Value)
+}
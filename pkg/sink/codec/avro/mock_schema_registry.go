@@ -90,6 +90,60 @@ func startHTTPInterceptForTestingRegistry() {
 			return httpmock.NewJsonResponse(200, &respData)
 		})
 
+	// The compatibility is checked in the backward mode, the new schema is
+	// incompatible if it adds a field without default or changes the type of
+	// a field.
+	httpmock.RegisterResponder("POST",
+		`=~^http://127.0.0.1:8081/compatibility/subjects/(.+)/versions/latest`,
+		func(req *http.Request) (*http.Response, error) {
+			subject, err := httpmock.GetSubmatch(req, 1)
+			if err != nil {
+				return nil, err
+			}
+			var reqData registerRequest
+			if err := json.NewDecoder(req.Body).Decode(&reqData); err != nil {
+				return nil, err
+			}
+			registry.mu.Lock()
+			item, exists := registry.subjects[subject]
+			registry.mu.Unlock()
+			if !exists {
+				return httpmock.NewStringResponse(404, "Subject not found"), nil
+			}
+
+			type field struct {
+				Name    string          `json:"name"`
+				Type    json.RawMessage `json:"type"`
+				Default json.RawMessage `json:"default"`
+			}
+			var oldSchema, newSchema struct {
+				Fields []field `json:"fields"`
+			}
+			if err := json.Unmarshal([]byte(item.content), &oldSchema); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal([]byte(reqData.Schema), &newSchema); err != nil {
+				return nil, err
+			}
+			oldFields := make(map[string]field, len(oldSchema.Fields))
+			for _, f := range oldSchema.Fields {
+				oldFields[f.Name] = f
+			}
+			respData := compatibilityResponse{IsCompatible: true}
+			for _, f := range newSchema.Fields {
+				old, ok := oldFields[f.Name]
+				if !ok && f.Default == nil {
+					respData.Messages = append(respData.Messages,
+						"READER_FIELD_MISSING_DEFAULT_VALUE, "+f.Name)
+				} else if ok && string(old.Type) != string(f.Type) {
+					respData.Messages = append(respData.Messages,
+						"TYPE_MISMATCH, "+f.Name)
+				}
+			}
+			respData.IsCompatible = len(respData.Messages) == 0
+			return httpmock.NewJsonResponse(200, &respData)
+		})
+
 	httpmock.RegisterResponder("GET", `=~^http://127.0.0.1:8081/schemas/ids/(.+)`,
 		func(req *http.Request) (*http.Response, error) {
 			id, err := httpmock.GetSubmatchAsInt(req, 1)
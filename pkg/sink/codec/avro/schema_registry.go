@@ -16,6 +16,7 @@ package avro
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -29,22 +30,65 @@ import (
 	"github.com/linkedin/goavro/v2"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/httputil"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
 
 // SchemaManager is used to register Avro Schemas to the Registry server,
 // look up local cache according to the table's name, and fetch from the Registry
 // in cache the local cache entry is missing.
-type SchemaManager struct {
+type SchemaManager interface {
+	// Register a schema in schema registry, no cache.
+	Register(ctx context.Context, schemaSubject string, schema string) (schemaID, error)
+	// Lookup the cached schema entry first, if not found, fetch it from the registry.
+	// It's only available for the avro schemas.
+	Lookup(ctx context.Context, schemaSubject string, schemaID schemaID) (*goavro.Codec, error)
+	// GetCachedOrRegister returns the cached schema of the table version, or
+	// generates, registers and caches a new one. The header of the messages
+	// encoded by the schema is returned with its codec, which is nil for the
+	// JSON schemas.
+	GetCachedOrRegister(
+		ctx context.Context, schemaSubject string, tableVersion uint64, schemaGen SchemaGenerator,
+	) (*goavro.Codec, []byte, error)
+	// CheckCompatibility checks whether the schema is compatible with the
+	// latest schema of the subject, the reasons are returned if it's incompatible.
+	CheckCompatibility(ctx context.Context, schemaSubject string, schema string) (bool, []string, error)
+	// ClearRegistry clears the subject in the registry. Should be idempotent.
+	ClearRegistry(ctx context.Context, schemaSubject string) error
+}
+
+const (
+	// schemaTypeAvro and schemaTypeJSON are the types of the schemas, which are
+	// the same as the schema types of the Confluent Schema Registry, the data
+	// formats of the Glue Schema Registry and the artifact types of the
+	// Apicurio Registry.
+	schemaTypeAvro = "AVRO"
+	schemaTypeJSON = "JSON"
+)
+
+// schemaID is the unique identifier of a schema in schema registry, only the
+// field of the registry which the schema is registered to is set.
+type schemaID struct {
+	confluentSchemaID int32
+	glueSchemaID      string
+	apicurioGlobalID  int64
+}
+
+// confluentSchemaManager is the SchemaManager of the Confluent Schema Registry.
+type confluentSchemaManager struct {
 	registryURL string
+	schemaType  string
 
-	credential *security.Credential // placeholder, currently always nil
+	credential *security.Credential
+	// authorization is the value of the Authorization header of the requests,
+	// it's empty if the registry requires no authentication.
+	authorization string
 
-	cacheRWLock sync.RWMutex
-	cache       map[string]*schemaCacheEntry
+	cache *schemaCache
 }
 
 type schemaCacheEntry struct {
@@ -54,40 +98,126 @@ type schemaCacheEntry struct {
 	// schemaID is the unique identifier of a schema in schema registry.
 	// for each message should carry this id to allow the decoder fetch the corresponding schema
 	// decoder use it as the cache key.
-	schemaID int
+	schemaID schemaID
+	// header is the header of the messages encoded by the schema, it carries the schemaID.
+	header []byte
 	// codec is associated with the schemaID, used to decode the message
 	codec *goavro.Codec
 }
 
+// schemaCache caches the schemas by their subjects.
+type schemaCache struct {
+	sync.RWMutex
+	entries map[string]*schemaCacheEntry
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{entries: make(map[string]*schemaCacheEntry, 1)}
+}
+
+func (c *schemaCache) get(schemaSubject string) (*schemaCacheEntry, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	entry, exists := c.entries[schemaSubject]
+	return entry, exists
+}
+
+func (c *schemaCache) put(schemaSubject string, entry *schemaCacheEntry) {
+	c.Lock()
+	defer c.Unlock()
+	c.entries[schemaSubject] = entry
+}
+
 type registerRequest struct {
 	Schema string `json:"schema"`
-	// Commented out for compatibility with Confluent 5.4.x
-	// SchemaType string `json:"schemaType"`
+	// SchemaType is omitted for the avro schemas for compatibility with
+	// Confluent 5.4.x, which only supports the avro schemas.
+	SchemaType string `json:"schemaType,omitempty"`
 }
 
 type registerResponse struct {
 	SchemaID int `json:"id"`
 }
 
+type compatibilityResponse struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages"`
+}
+
 type lookupResponse struct {
 	Name     string `json:"name"`
 	SchemaID int    `json:"id"`
 	Schema   string `json:"schema"`
 }
 
+// NewSchemaManager creates the schema manager of the avro schemas by the
+// registry provider, the registryURL is the URL of the Confluent Schema
+// Registry or the Apicurio Registry.
+func NewSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	registryConfig *config.SchemaRegistryConfig,
+) (SchemaManager, error) {
+	return newSchemaManager(ctx, registryURL, registryConfig, schemaTypeAvro)
+}
+
+// NewJSONSchemaManager creates the schema manager of the JSON schemas by the
+// registry provider like NewSchemaManager.
+func NewJSONSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	registryConfig *config.SchemaRegistryConfig,
+) (SchemaManager, error) {
+	return newSchemaManager(ctx, registryURL, registryConfig, schemaTypeJSON)
+}
+
+func newSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	registryConfig *config.SchemaRegistryConfig,
+	schemaType string,
+) (SchemaManager, error) {
+	if registryConfig.IsGlue() {
+		return newGlueSchemaManagerWithConfig(ctx, registryConfig, schemaType)
+	}
+	if registryConfig.IsApicurio() {
+		return newApicurioSchemaManager(ctx, registryURL, registryConfig, schemaType)
+	}
+	return newConfluentSchemaManager(ctx, registryURL, registryConfig, schemaType)
+}
+
 // NewAvroSchemaManager create schema managers,
-// and test connectivity to the schema registry
+// and test connectivity to the schema registry.
+// The registryConfig can be nil if the registry requires no authentication or TLS.
 func NewAvroSchemaManager(
 	ctx context.Context,
 	registryURL string,
-	credential *security.Credential,
-) (*SchemaManager, error) {
+	registryConfig *config.SchemaRegistryConfig,
+) (SchemaManager, error) {
+	return newConfluentSchemaManager(ctx, registryURL, registryConfig, schemaTypeAvro)
+}
+
+func newConfluentSchemaManager(
+	ctx context.Context,
+	registryURL string,
+	registryConfig *config.SchemaRegistryConfig,
+	schemaType string,
+) (SchemaManager, error) {
 	registryURL = strings.TrimRight(registryURL, "/")
+	credential, authorization := registryCredential(registryConfig)
 	httpCli, err := httputil.NewClient(credential)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	resp, err := httpCli.Get(ctx, registryURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", registryURL, nil)
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := httpCli.Do(req)
 	if err != nil {
 		log.Error("Test connection to Schema Registry failed", zap.Error(err))
 		return nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
@@ -112,32 +242,67 @@ func NewAvroSchemaManager(
 		zap.String("registryURL", registryURL),
 	)
 
-	return &SchemaManager{
-		registryURL: registryURL,
-		cache:       make(map[string]*schemaCacheEntry, 1),
+	return &confluentSchemaManager{
+		registryURL:   registryURL,
+		schemaType:    schemaType,
+		credential:    credential,
+		authorization: authorization,
+		cache:         newSchemaCache(),
 	}, nil
 }
 
+// registryCredential returns the TLS credential and the value of the
+// Authorization header of the schema registry by its config.
+func registryCredential(
+	registryConfig *config.SchemaRegistryConfig,
+) (*security.Credential, string) {
+	if registryConfig == nil {
+		return nil, ""
+	}
+	var credential *security.Credential
+	if util.GetOrZero(registryConfig.CA) != "" {
+		credential = &security.Credential{
+			CAPath:   util.GetOrZero(registryConfig.CA),
+			CertPath: util.GetOrZero(registryConfig.Cert),
+			KeyPath:  util.GetOrZero(registryConfig.Key),
+		}
+	}
+	var authorization string
+	if registryConfig.BasicAuthUser != nil {
+		auth := util.GetOrZero(registryConfig.BasicAuthUser) + ":" +
+			util.GetOrZero(registryConfig.BasicAuthPassword)
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+	} else if registryConfig.BearerToken != nil {
+		authorization = "Bearer " + util.GetOrZero(registryConfig.BearerToken)
+	}
+	return credential, authorization
+}
+
+func (m *confluentSchemaManager) newRegisterRequest(schema string) *registerRequest {
+	req := &registerRequest{Schema: schema}
+	if m.schemaType != schemaTypeAvro {
+		req.SchemaType = m.schemaType
+	}
+	return req
+}
+
 // Register a schema in schema registry, no cache
-func (m *SchemaManager) Register(
+func (m *confluentSchemaManager) Register(
 	ctx context.Context,
 	schemaSubject string,
 	schema string,
-) (int, error) {
+) (schemaID, error) {
 	// The Schema Registry expects the JSON to be without newline characters
 	buffer := new(bytes.Buffer)
 	err := json.Compact(buffer, []byte(schema))
 	if err != nil {
 		log.Error("Could not compact schema", zap.Error(err))
-		return 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
-	}
-	reqBody := registerRequest{
-		Schema: buffer.String(),
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
-	payload, err := json.Marshal(&reqBody)
+	payload, err := json.Marshal(m.newRegisterRequest(buffer.String()))
 	if err != nil {
 		log.Error("Could not marshal request to the Registry", zap.Error(err))
-		return 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 	uri := m.registryURL + "/subjects/" + url.QueryEscape(schemaSubject) + "/versions"
 	log.Info("Registering schema", zap.String("uri", uri), zap.ByteString("payload", payload))
@@ -145,7 +310,7 @@ func (m *SchemaManager) Register(
 	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
 	if err != nil {
 		log.Error("Failed to NewRequestWithContext", zap.Error(err))
-		return 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 	req.Header.Add(
 		"Accept",
@@ -153,16 +318,16 @@ func (m *SchemaManager) Register(
 			"application/json",
 	)
 	req.Header.Add("Content-Type", "application/vnd.schemaregistry.v1+json")
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
 	if err != nil {
-		return 0, err
+		return schemaID{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error("Failed to read response from Registry", zap.Error(err))
-		return 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 
 	if resp.StatusCode != 200 {
@@ -176,7 +341,10 @@ func (m *SchemaManager) Register(
 			zap.ByteString("requestBody", payload),
 			zap.ByteString("responseBody", body),
 		)
-		return 0, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
+		if resp.StatusCode == http.StatusConflict {
+			return schemaID{}, cerror.ErrAvroIncompatibleSchema.GenWithStackByArgs(schemaSubject)
+		}
+		return schemaID{}, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs()
 	}
 
 	var jsonResp registerResponse
@@ -184,11 +352,11 @@ func (m *SchemaManager) Register(
 
 	if err != nil {
 		log.Error("Failed to parse result from Registry", zap.Error(err))
-		return 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		return schemaID{}, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
 	}
 
 	if jsonResp.SchemaID == 0 {
-		return 0, cerror.ErrAvroSchemaAPIError.GenWithStack(
+		return schemaID{}, cerror.ErrAvroSchemaAPIError.GenWithStack(
 			"Illegal schema ID returned from Registry %d",
 			jsonResp.SchemaID,
 		)
@@ -199,31 +367,96 @@ func (m *SchemaManager) Register(
 		zap.String("uri", uri),
 		zap.ByteString("body", body))
 
-	return jsonResp.SchemaID, nil
+	return schemaID{confluentSchemaID: int32(jsonResp.SchemaID)}, nil
+}
+
+// CheckCompatibility checks whether the schema is compatible with the latest
+// schema of the subject by the compatibility mode of the Registry, the reasons
+// are returned if it's incompatible. A schema is always compatible if nothing
+// has been registered in the subject.
+func (m *confluentSchemaManager) CheckCompatibility(
+	ctx context.Context,
+	schemaSubject string,
+	schema string,
+) (bool, []string, error) {
+	buffer := new(bytes.Buffer)
+	err := json.Compact(buffer, []byte(schema))
+	if err != nil {
+		log.Error("Could not compact schema", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	payload, err := json.Marshal(m.newRegisterRequest(buffer.String()))
+	if err != nil {
+		log.Error("Could not marshal request to the Registry", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	uri := m.registryURL + "/compatibility/subjects/" + url.QueryEscape(schemaSubject) +
+		"/versions/latest?verbose=true"
+	log.Debug("Checking schema compatibility", zap.String("uri", uri))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(payload))
+	if err != nil {
+		log.Error("Failed to NewRequestWithContext", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	req.Header.Add(
+		"Accept",
+		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
+			"application/json",
+	)
+	req.Header.Add("Content-Type", "application/vnd.schemaregistry.v1+json")
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read response from Registry", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+
+	// 404 means the subject or its versions are not found.
+	if resp.StatusCode == http.StatusNotFound {
+		return true, nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Failed to check schema compatibility, HTTP error",
+			zap.Int("status", resp.StatusCode),
+			zap.String("uri", uri),
+			zap.ByteString("responseBody", body))
+		return false, nil, cerror.ErrAvroSchemaAPIError.GenWithStack(
+			"Failed to check schema compatibility, HTTP error %d", resp.StatusCode)
+	}
+
+	var jsonResp compatibilityResponse
+	if err := json.Unmarshal(body, &jsonResp); err != nil {
+		log.Error("Failed to parse result from Registry", zap.Error(err))
+		return false, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	}
+	return jsonResp.IsCompatible, jsonResp.Messages, nil
 }
 
 // Lookup the cached schema entry first, if not found, fetch from the Registry server.
-func (m *SchemaManager) Lookup(
+func (m *confluentSchemaManager) Lookup(
 	ctx context.Context,
 	schemaSubject string,
-	schemaID int,
+	schemaID schemaID,
 ) (*goavro.Codec, error) {
-	m.cacheRWLock.RLock()
-	entry, exists := m.cache[schemaSubject]
+	entry, exists := m.cache.get(schemaSubject)
 	if exists && entry.schemaID == schemaID {
 		log.Debug("Avro schema lookup cache hit",
 			zap.String("key", schemaSubject),
-			zap.Int("schemaID", entry.schemaID))
-		m.cacheRWLock.RUnlock()
+			zap.Int32("schemaID", entry.schemaID.confluentSchemaID))
 		return entry.codec, nil
 	}
-	m.cacheRWLock.RUnlock()
 
 	log.Info("Avro schema lookup cache miss",
 		zap.String("key", schemaSubject),
-		zap.Int("schemaID", schemaID))
+		zap.Int32("schemaID", schemaID.confluentSchemaID))
 
-	uri := m.registryURL + "/schemas/ids/" + strconv.Itoa(schemaID)
+	uri := m.registryURL + "/schemas/ids/" + strconv.Itoa(int(schemaID.confluentSchemaID))
 	log.Debug("Querying for latest schema", zap.String("uri", uri))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
@@ -237,7 +470,7 @@ func (m *SchemaManager) Lookup(
 			"application/json",
 	)
 
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +495,7 @@ func (m *SchemaManager) Lookup(
 	if resp.StatusCode == 404 {
 		log.Warn("Specified schema not found in Registry",
 			zap.String("key", schemaSubject),
-			zap.Int("schemaID", schemaID))
+			zap.Int32("schemaID", schemaID.confluentSchemaID))
 		return nil, cerror.ErrAvroSchemaAPIError.GenWithStackByArgs(
 			"Schema not found in Registry",
 		)
@@ -283,12 +516,10 @@ func (m *SchemaManager) Lookup(
 	}
 	cacheEntry.schemaID = schemaID
 
-	m.cacheRWLock.Lock()
-	m.cache[schemaSubject] = cacheEntry
-	m.cacheRWLock.Unlock()
+	m.cache.put(schemaSubject, cacheEntry)
 
 	log.Info("Avro schema lookup successful with cache miss",
-		zap.Int("schemaID", cacheEntry.schemaID),
+		zap.Int32("schemaID", cacheEntry.schemaID.confluentSchemaID),
 		zap.String("schema", cacheEntry.codec.Schema()))
 
 	return cacheEntry.codec, nil
@@ -302,22 +533,33 @@ type SchemaGenerator func() (string, error)
 // If not, a new schema is generated, registered and cached.
 // Re-registering an existing schema shall return the same id(and version), so even if the
 // cache is out-of-sync with schema registry, we could reload it.
-func (m *SchemaManager) GetCachedOrRegister(
+func (m *confluentSchemaManager) GetCachedOrRegister(
 	ctx context.Context,
 	schemaSubject string,
 	tableVersion uint64,
 	schemaGen SchemaGenerator,
-) (*goavro.Codec, int, error) {
-	m.cacheRWLock.RLock()
-	if entry, exists := m.cache[schemaSubject]; exists && entry.tableVersion == tableVersion {
+) (*goavro.Codec, []byte, error) {
+	return getCachedOrRegister(ctx, m, m.cache, m.schemaType, schemaSubject, tableVersion, schemaGen)
+}
+
+// getCachedOrRegister is the GetCachedOrRegister shared by the schema managers,
+// the schemas of the schemaType are registered by m and cached in cache.
+func getCachedOrRegister(
+	ctx context.Context,
+	m SchemaManager,
+	cache *schemaCache,
+	schemaType string,
+	schemaSubject string,
+	tableVersion uint64,
+	schemaGen SchemaGenerator,
+) (*goavro.Codec, []byte, error) {
+	if entry, exists := cache.get(schemaSubject); exists && entry.tableVersion == tableVersion {
 		log.Debug("Avro schema GetCachedOrRegister cache hit",
 			zap.String("key", schemaSubject),
 			zap.Uint64("tableVersion", tableVersion),
-			zap.Int("schemaID", entry.schemaID))
-		m.cacheRWLock.RUnlock()
-		return entry.codec, entry.schemaID, nil
+			zap.Any("schemaID", entry.schemaID))
+		return entry.codec, entry.header, nil
 	}
-	m.cacheRWLock.RUnlock()
 
 	log.Info("Avro schema lookup cache miss",
 		zap.String("key", schemaSubject),
@@ -325,42 +567,49 @@ func (m *SchemaManager) GetCachedOrRegister(
 
 	schema, err := schemaGen()
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	codec, err := goavro.NewCodec(schema)
-	if err != nil {
-		log.Error("GetCachedOrRegister: Could not make goavro codec", zap.Error(err))
-		return nil, 0, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+	var codec *goavro.Codec
+	if schemaType == schemaTypeAvro {
+		codec, err = goavro.NewCodec(schema)
+		if err != nil {
+			log.Error("GetCachedOrRegister: Could not make goavro codec", zap.Error(err))
+			return nil, nil, cerror.WrapError(cerror.ErrAvroSchemaAPIError, err)
+		}
+		schema = codec.Schema()
 	}
 
-	id, err := m.Register(ctx, schemaSubject, codec.Schema())
+	id, err := m.Register(ctx, schemaSubject, schema)
 	if err != nil {
 		log.Error("GetCachedOrRegister: Could not register schema", zap.Error(err))
-		return nil, 0, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
+	}
+	header, err := getMessageHeader(id)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
 	}
 
 	cacheEntry := new(schemaCacheEntry)
 	cacheEntry.codec = codec
 	cacheEntry.schemaID = id
+	cacheEntry.header = header
 	cacheEntry.tableVersion = tableVersion
 
-	m.cacheRWLock.Lock()
-	m.cache[schemaSubject] = cacheEntry
-	m.cacheRWLock.Unlock()
+	cache.put(schemaSubject, cacheEntry)
 
 	log.Info("Avro schema GetCachedOrRegister successful with cache miss",
 		zap.Uint64("tableVersion", cacheEntry.tableVersion),
-		zap.Int("schemaID", cacheEntry.schemaID),
-		zap.String("schema", cacheEntry.codec.Schema()))
+		zap.Any("schemaID", cacheEntry.schemaID),
+		zap.String("schema", schema))
 
-	return codec, id, nil
+	return codec, header, nil
 }
 
 // ClearRegistry clears the Registry subject for the given table. Should be idempotent.
 // Exported for testing.
 // NOT USED for now, reserved for future use.
-func (m *SchemaManager) ClearRegistry(ctx context.Context, schemaSubject string) error {
+func (m *confluentSchemaManager) ClearRegistry(ctx context.Context, schemaSubject string) error {
 	uri := m.registryURL + "/subjects/" + url.QueryEscape(schemaSubject)
 	req, err := http.NewRequestWithContext(ctx, "DELETE", uri, nil)
 	if err != nil {
@@ -372,7 +621,7 @@ func (m *SchemaManager) ClearRegistry(ctx context.Context, schemaSubject string)
 		"application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, "+
 			"application/json",
 	)
-	resp, err := httpRetry(ctx, m.credential, req)
+	resp, err := httpRetry(ctx, m.credential, m.authorization, req)
 	if err != nil {
 		return err
 	}
@@ -401,6 +650,7 @@ func (m *SchemaManager) ClearRegistry(ctx context.Context, schemaSubject string)
 func httpRetry(
 	ctx context.Context,
 	credential *security.Credential,
+	authorization string,
 	r *http.Request,
 ) (*http.Response, error) {
 	var (
@@ -413,6 +663,9 @@ func httpRetry(
 	expBackoff.MaxInterval = time.Second * 30
 	httpCli, err := httputil.NewClient(credential)
 
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
 	if r.Body != nil {
 		data, err = io.ReadAll(r.Body)
 		_ = r.Body.Close()
@@ -22,6 +22,9 @@ import (
 	"time"
 
 	"github.com/linkedin/goavro/v2"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,7 +47,7 @@ func TestSchemaRegistry(t *testing.T) {
 	err = manager.ClearRegistry(ctx, topic)
 	require.NoError(t, err)
 
-	_, err = manager.Lookup(ctx, topic, 1)
+	_, err = manager.Lookup(ctx, topic, schemaID{confluentSchemaID: 1})
 	require.Regexp(t, `.*not\sfound.*`, err)
 
 	codec, err := goavro.NewCodec(`{
@@ -60,10 +63,10 @@ func TestSchemaRegistry(t *testing.T) {
      }`)
 	require.NoError(t, err)
 
-	schemaID, err := manager.Register(ctx, topic, codec.Schema())
+	id, err := manager.Register(ctx, topic, codec.Schema())
 	require.NoError(t, err)
 
-	codec2, err := manager.Lookup(ctx, topic, schemaID)
+	codec2, err := manager.Lookup(ctx, topic, id)
 	require.NoError(t, err)
 	require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
 
@@ -87,10 +90,10 @@ func TestSchemaRegistry(t *testing.T) {
           ]
      }`)
 	require.NoError(t, err)
-	schemaID, err = manager.Register(ctx, topic, codec.Schema())
+	id, err = manager.Register(ctx, topic, codec.Schema())
 	require.NoError(t, err)
 
-	codec2, err = manager.Lookup(ctx, topic, schemaID)
+	codec2, err = manager.Lookup(ctx, topic, id)
 	require.NoError(t, err)
 	require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
 }
@@ -107,6 +110,34 @@ func TestSchemaRegistryBad(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestRegistryCredential(t *testing.T) {
+	t.Parallel()
+
+	credential, authorization := registryCredential(nil)
+	require.Nil(t, credential)
+	require.Empty(t, authorization)
+
+	credential, authorization = registryCredential(&config.SchemaRegistryConfig{
+		BasicAuthUser:     util.AddressOf("root"),
+		BasicAuthPassword: util.AddressOf("secret"),
+	})
+	require.Nil(t, credential)
+	require.Equal(t, "Basic cm9vdDpzZWNyZXQ=", authorization)
+
+	credential, authorization = registryCredential(&config.SchemaRegistryConfig{
+		BearerToken: util.AddressOf("token"),
+		CA:          util.AddressOf("ca.pem"),
+		Cert:        util.AddressOf("cert.pem"),
+		Key:         util.AddressOf("key.pem"),
+	})
+	require.Equal(t, &security.Credential{
+		CAPath:   "ca.pem",
+		CertPath: "cert.pem",
+		KeyPath:  "key.pem",
+	}, credential)
+	require.Equal(t, "Bearer token", authorization)
+}
+
 func TestSchemaRegistryIdempotent(t *testing.T) {
 	startHTTPInterceptForTestingRegistry()
 	defer stopHTTPInterceptForTestingRegistry()
@@ -143,11 +174,11 @@ func TestSchemaRegistryIdempotent(t *testing.T) {
      }`)
 	require.NoError(t, err)
 
-	id := 0
+	var id schemaID
 	for i := 0; i < 20; i++ {
 		id1, err := manager.Register(ctx, topic, codec.Schema())
 		require.NoError(t, err)
-		require.True(t, id == 0 || id == id1)
+		require.True(t, id == schemaID{} || id == id1)
 		id = id1
 	}
 }
@@ -187,9 +218,11 @@ func TestGetCachedOrRegister(t *testing.T) {
 	}
 	topic := "cdctest"
 
-	codec, id, err := manager.GetCachedOrRegister(ctx, topic, 1, schemaGen)
+	codec, header, err := manager.GetCachedOrRegister(ctx, topic, 1, schemaGen)
 	require.NoError(t, err)
-	require.Greater(t, id, 0)
+	id, _, err := extractSchemaIDAndBinaryData(header)
+	require.NoError(t, err)
+	require.Greater(t, id.confluentSchemaID, int32(0))
 	require.NotNil(t, codec)
 	require.Equal(t, 1, called)
 
@@ -232,14 +265,16 @@ func TestGetCachedOrRegister(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < 100; j++ {
-				codec, id, err := manager.GetCachedOrRegister(
+				codec, header, err := manager.GetCachedOrRegister(
 					ctx,
 					topic,
 					uint64(finalI),
 					schemaGen,
 				)
 				require.NoError(t, err)
-				require.Greater(t, id, 0)
+				id, _, err := extractSchemaIDAndBinaryData(header)
+				require.NoError(t, err)
+				require.Greater(t, id.confluentSchemaID, int32(0))
 				require.NotNil(t, codec)
 			}
 		}()
@@ -259,7 +294,7 @@ func TestHTTPRetry(t *testing.T) {
 		"POST", "http://127.0.0.1:8081/may-fail", bytes.NewReader(payload))
 	require.NoError(t, err)
 
-	resp, err := httpRetry(ctx, nil, req)
+	resp, err := httpRetry(ctx, nil, "", req)
 	require.NoError(t, err)
 	require.Equal(t, 200, resp.StatusCode)
 	_ = resp.Body.Close()
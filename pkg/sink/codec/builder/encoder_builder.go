@@ -25,8 +25,10 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/craft"
 	"github.com/pingcap/tiflow/pkg/sink/codec/csv"
+	"github.com/pingcap/tiflow/pkg/sink/codec/debezium"
 	"github.com/pingcap/tiflow/pkg/sink/codec/maxwell"
 	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
 )
 
 // NewRowEventEncoderBuilder returns an RowEventEncoderBuilder
@@ -48,6 +50,10 @@ func NewRowEventEncoderBuilder(
 		return canal.NewJSONRowEventEncoderBuilder(c), nil
 	case config.ProtocolCraft:
 		return craft.NewBatchEncoderBuilder(c), nil
+	case config.ProtocolDebezium:
+		return debezium.NewBatchEncoderBuilder(changefeedID, c)
+	case config.ProtocolJSONSchema:
+		return avro.NewJSONSchemaEncoderBuilder(ctx, changefeedID, c)
 
 	default:
 		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(c.Protocol)
@@ -63,6 +69,8 @@ func NewTxnEventEncoderBuilder(
 		return csv.NewTxnEventEncoderBuilder(c), nil
 	case config.ProtocolCanalJSON:
 		return canal.NewJSONTxnEventEncoderBuilder(c), nil
+	case config.ProtocolParquet:
+		return parquet.NewTxnEventEncoderBuilder(c), nil
 	default:
 		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(c.Protocol)
 	}
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/golang/protobuf/proto" // nolint:staticcheck
 	"github.com/pingcap/errors"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	canal "github.com/pingcap/tiflow/proto/canal"
 	"golang.org/x/text/encoding"
@@ -43,6 +45,9 @@ const (
 
 type canalEntryBuilder struct {
 	bytesDecoder *encoding.Decoder // default charset is ISO-8859-1
+	// temporal encodes the DATETIME and TIMESTAMP values of the canal-json
+	// messages, they're encoded as they're if it's nil.
+	temporal *common.TemporalEncoder
 }
 
 // newCanalEntryBuilder creates a new canalEntryBuilder
@@ -52,6 +57,13 @@ func newCanalEntryBuilder() *canalEntryBuilder {
 	}
 }
 
+// newCanalJSONEntryBuilder creates a canalEntryBuilder of the canal-json messages.
+func newCanalJSONEntryBuilder(config *common.Config) *canalEntryBuilder {
+	builder := newCanalEntryBuilder()
+	builder.temporal = config.TemporalEncoder
+	return builder
+}
+
 // build the header of a canal entry
 func (b *canalEntryBuilder) buildHeader(commitTs uint64, schema string, table string, eventType canal.EventType, rowCount int) *canal.Header {
 	t := convertToCanalTs(commitTs)
@@ -105,6 +117,11 @@ func (b *canalEntryBuilder) formatValue(value interface{}, javaType internal.Jav
 			result = string(v)
 		default:
 			// JavaSQLTypeBLOB
+			// ISO-8859-1 keeps ASCII as is, so skip the decoder for it.
+			if isASCII(v) {
+				result = string(v)
+				break
+			}
 			decoded, err := b.bytesDecoder.Bytes(v)
 			if err != nil {
 				return "", err
@@ -117,6 +134,15 @@ func (b *canalEntryBuilder) formatValue(value interface{}, javaType internal.Jav
 	return result, nil
 }
 
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // build the Column in the canal RowData
 // see https://github.com/alibaba/canal/blob/b54bea5e3337c9597c427a53071d214ff04628d1/parse/src/main/java/com/alibaba/otter/canal/parse/inbound/mysql/dbsync/LogEventConvert.java#L756-L872
 func (b *canalEntryBuilder) buildColumn(c *model.Column, colName string, updated bool) (*canal.Column, error) {
@@ -388,20 +414,46 @@ func trimUnsignedFromMySQLType(mysqlType string) string {
 	return strings.TrimSuffix(mysqlType, " unsigned")
 }
 
+// mysqlTypes are the MySQL types of the columns indexed by the type, the
+// unsigned flag and the binary flag, so they aren't built for each column.
+var mysqlTypes [256][2][2]string
+
+func init() {
+	for tp := 0; tp < len(mysqlTypes); tp++ {
+		for _, unsigned := range []bool{false, true} {
+			for _, binary := range []bool{false, true} {
+				mysqlTypes[tp][boolToIndex(unsigned)][boolToIndex(binary)] =
+					newMySQLType(byte(tp), unsigned, binary)
+			}
+		}
+	}
+}
+
+func boolToIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func getMySQLType(c *model.Column) string {
-	mysqlType := types.TypeStr(c.Type)
+	return mysqlTypes[c.Type][boolToIndex(c.Flag.IsUnsigned())][boolToIndex(c.Flag.IsBinary())]
+}
+
+func newMySQLType(tp byte, unsigned, binary bool) string {
+	mysqlType := types.TypeStr(tp)
 	// make `mysqlType` representation keep the same as the canal official implementation
-	mysqlType = withUnsigned4MySQLType(mysqlType, c.Flag.IsUnsigned())
+	mysqlType = withUnsigned4MySQLType(mysqlType, unsigned)
 
-	if !c.Flag.IsBinary() {
+	if !binary {
 		return mysqlType
 	}
 
-	if types.IsTypeBlob(c.Type) {
+	if types.IsTypeBlob(tp) {
 		return strings.Replace(mysqlType, "text", "blob", 1)
 	}
 
-	if types.IsTypeChar(c.Type) {
+	if types.IsTypeChar(tp) {
 		return strings.Replace(mysqlType, "char", "binary", 1)
 	}
 
@@ -0,0 +1,137 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// flatMetaKey is the key of the metadata in the flat canal-json messages.
+const flatMetaKey = "_cdc"
+
+// newFlatJSONMessageForDML encodes the row into a flat JSON object, which is
+// easier to be ingested by the systems such as Druid and Rockset. The columns
+// of the row are at the top level, the columns of the deleted row are used for
+// the delete event. The metadata and the old values of the updated row are
+// under the "_cdc" key, e.g.
+//
+//	{"id":"1","name":"a","_cdc":{"database":"test","table":"t","type":"UPDATE",...,"old":{"name":"b"}}}
+func newFlatJSONMessageForDML(
+	builder *canalEntryBuilder,
+	e *model.RowChangedEvent,
+	config *common.Config,
+	messageTooLarge bool,
+	cache *tableEncodeCache,
+) (*bytes.Buffer, error) {
+	cache = cache.forRow(e)
+	isDelete := e.IsDelete()
+
+	onlyHandleKey := messageTooLarge
+	if isDelete && config.DeleteOnlyHandleKeyColumns {
+		onlyHandleKey = true
+	}
+
+	columns := e.Columns
+	if isDelete {
+		columns = e.PreColumns
+	}
+
+	out := &jwriter.Writer{}
+	out.RawByte('{')
+	written, err := writeColumnFields(columns, false, onlyHandleKey, nil, true, out, builder, cache)
+	if err != nil {
+		return nil, err
+	}
+	if written {
+		out.RawByte(',')
+	}
+	out.String(flatMetaKey)
+	out.RawString(":{")
+	out.RawString("\"database\":")
+	out.String(e.Table.Schema)
+	out.RawString(",\"table\":")
+	out.String(e.Table.Table)
+	out.RawString(",\"type\":")
+	out.String(eventTypeString(e))
+	out.RawString(",\"pkNames\":")
+	pkNames := cache.primaryKeyColumnNames(e)
+	if pkNames == nil {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for i, name := range pkNames {
+			if i > 0 {
+				out.RawByte(',')
+			}
+			out.String(name)
+		}
+		out.RawByte(']')
+	}
+	out.RawString(",\"es\":")
+	out.Int64(convertToCanalTs(e.CommitTs))
+	out.RawString(",\"ts\":")
+	out.Int64(time.Now().UnixMilli())
+	out.RawString(",\"commitTs\":")
+	out.Uint64(e.CommitTs)
+	if config.OutputPhysicalTime {
+		out.RawString(",\"commitPhysicalTime\":")
+		out.Int64(oracle.ExtractPhysical(e.CommitTs))
+		out.RawString(",\"ingestionTime\":")
+		out.Int64(time.Now().UnixMilli())
+	}
+	if e.IsSnapshot {
+		out.RawString(",\"isSnapshot\":true")
+	}
+
+	if e.IsUpdate() {
+		var newColsMap map[string]*model.Column
+		if config.OnlyOutputUpdatedColumns {
+			newColsMap = make(map[string]*model.Column, len(e.Columns))
+			for _, col := range e.Columns {
+				if col != nil {
+					newColsMap[col.Name] = col
+				}
+			}
+		}
+		out.RawString(",\"old\":{")
+		if _, err := writeColumnFields(e.PreColumns, config.OnlyOutputUpdatedColumns,
+			onlyHandleKey, newColsMap, true, out, builder, cache); err != nil {
+			return nil, err
+		}
+		out.RawByte('}')
+	} else if !isDelete && !e.IsInsert() {
+		log.Panic("unreachable event type", zap.Any("event", e))
+	}
+
+	if messageTooLarge {
+		if config.LargeMessageHandle.HandleKeyOnly() {
+			out.RawString(",\"onlyHandleKey\":true")
+		}
+		if config.LargeMessageHandle.EnableClaimCheck() {
+			out.RawString(",\"claimCheckLocation\":")
+			out.String(common.NewClaimCheckFileName(e))
+		}
+	}
+	out.RawByte('}')
+	out.RawByte('}')
+	return dumpJSONWriter(out)
+}
@@ -56,6 +56,10 @@ type JSONMessage struct {
 	ExecutionTime int64 `json:"es"`
 	// officially the timestamp of building the message, in milliseconds since Epoch.
 	BuildTime int64 `json:"ts"`
+	// the physical time of the commit ts and the time of building the message,
+	// in milliseconds since Epoch, only output if output-physical-time is set.
+	CommitPhysicalTime int64 `json:"commitPhysicalTime,omitempty"`
+	IngestionTime      int64 `json:"ingestionTime,omitempty"`
 	// SQL that generated the change event, DDL or Query
 	Query string `json:"sql"`
 	// only works for INSERT / UPDATE / DELETE events, records each column's java representation type.
@@ -135,6 +139,9 @@ type tidbExtension struct {
 	WatermarkTs        uint64 `json:"watermarkTs,omitempty"`
 	OnlyHandleKey      bool   `json:"onlyHandleKey,omitempty"`
 	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
+	CommitPhysicalTime int64  `json:"commitPhysicalTime,omitempty"`
+	IngestionTime      int64  `json:"ingestionTime,omitempty"`
+	IsSnapshot         bool   `json:"isSnapshot,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
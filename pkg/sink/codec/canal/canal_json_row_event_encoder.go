@@ -14,6 +14,7 @@
 package canal
 
 import (
+	"bytes"
 	"context"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -35,6 +37,7 @@ func fillColumns(columns []*model.Column,
 	newColumnMap map[string]*model.Column,
 	out *jwriter.Writer,
 	builder *canalEntryBuilder,
+	cache *tableEncodeCache,
 ) error {
 	if len(columns) == 0 {
 		out.RawString("null")
@@ -42,7 +45,27 @@ func fillColumns(columns []*model.Column,
 	}
 	out.RawByte('[')
 	out.RawByte('{')
-	isFirst := true
+	if _, err := writeColumnFields(columns, onlyOutputUpdatedColumn, onlyHandleKeyColumn,
+		newColumnMap, true, out, builder, cache); err != nil {
+		return err
+	}
+	out.RawByte('}')
+	out.RawByte(']')
+	return nil
+}
+
+// writeColumnFields writes the columns as the fields of a JSON object, the
+// braces of the object aren't written. It returns whether any field is written.
+func writeColumnFields(columns []*model.Column,
+	onlyOutputUpdatedColumn bool,
+	onlyHandleKeyColumn bool,
+	newColumnMap map[string]*model.Column,
+	isFirst bool,
+	out *jwriter.Writer,
+	builder *canalEntryBuilder,
+	cache *tableEncodeCache,
+) (bool, error) {
+	written := false
 	for _, col := range columns {
 		if col != nil {
 			// column equal, do not output it
@@ -57,14 +80,14 @@ func fillColumns(columns []*model.Column,
 			} else {
 				out.RawByte(',')
 			}
-			mysqlType := getMySQLType(col)
+			mysqlType := cache.getMySQLType(col)
 			javaType, err := getJavaSQLType(col, mysqlType)
 			if err != nil {
-				return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+				return written, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 			}
-			value, err := builder.formatValue(col.Value, javaType)
+			value, err := builder.formatValue(builder.temporal.Encode(col), javaType)
 			if err != nil {
-				return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+				return written, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 			}
 			out.String(col.Name)
 			out.RawByte(':')
@@ -73,11 +96,10 @@ func fillColumns(columns []*model.Column,
 			} else {
 				out.String(value)
 			}
+			written = true
 		}
 	}
-	out.RawByte('}')
-	out.RawByte(']')
-	return nil
+	return written, nil
 }
 
 func newJSONMessageForDML(
@@ -85,7 +107,12 @@ func newJSONMessageForDML(
 	e *model.RowChangedEvent,
 	config *common.Config,
 	messageTooLarge bool,
-) ([]byte, error) {
+	cache *tableEncodeCache,
+) (*bytes.Buffer, error) {
+	if config.CanalJSONFlat {
+		return newFlatJSONMessageForDML(builder, e, config, messageTooLarge, cache)
+	}
+	cache = cache.forRow(e)
 	isDelete := e.IsDelete()
 
 	onlyHandleKey := messageTooLarge
@@ -93,8 +120,6 @@ func newJSONMessageForDML(
 		onlyHandleKey = true
 	}
 
-	mysqlTypeMap := make(map[string]string, len(e.Columns))
-
 	out := &jwriter.Writer{}
 	out.RawByte('{')
 	{
@@ -115,7 +140,7 @@ func newJSONMessageForDML(
 	{
 		const prefix string = ",\"pkNames\":"
 		out.RawString(prefix)
-		pkNames := e.PrimaryKeyColumnNames()
+		pkNames := cache.primaryKeyColumnNames(e)
 		if pkNames == nil {
 			out.RawString("null")
 		} else {
@@ -149,16 +174,23 @@ func newJSONMessageForDML(
 		out.RawString(prefix)
 		out.Int64(time.Now().UnixMilli()) // ignored by both Canal Adapter and Flink
 	}
+	if config.OutputPhysicalTime {
+		out.RawString(",\"commitPhysicalTime\":")
+		out.Int64(oracle.ExtractPhysical(e.CommitTs))
+		out.RawString(",\"ingestionTime\":")
+		out.Int64(time.Now().UnixMilli())
+	}
 	{
 		const prefix string = ",\"sql\":"
 		out.RawString(prefix)
 		out.String("")
 	}
+	// the columns of sqlType and mysqlType
+	columns := e.PreColumns
+	if !isDelete {
+		columns = e.Columns
+	}
 	{
-		columns := e.PreColumns
-		if !isDelete {
-			columns = e.Columns
-		}
 		const prefix string = ",\"sqlType\":"
 		out.RawString(prefix)
 		emptyColumn := true
@@ -173,7 +205,7 @@ func newJSONMessageForDML(
 				} else {
 					out.RawByte(',')
 				}
-				mysqlType := getMySQLType(col)
+				mysqlType := cache.getMySQLType(col)
 				javaType, err := getJavaSQLType(col, mysqlType)
 				if err != nil {
 					return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
@@ -181,7 +213,6 @@ func newJSONMessageForDML(
 				out.String(col.Name)
 				out.RawByte(':')
 				out.Int32(int32(javaType))
-				mysqlTypeMap[col.Name] = mysqlType
 			}
 		}
 		if emptyColumn {
@@ -193,35 +224,34 @@ func newJSONMessageForDML(
 	{
 		const prefix string = ",\"mysqlType\":"
 		out.RawString(prefix)
-		if mysqlTypeMap == nil {
-			out.RawString(`null`)
-		} else {
-			out.RawByte('{')
-			isFirst := true
-			for typeKey, typeValue := range mysqlTypeMap {
-				if isFirst {
-					isFirst = false
-				} else {
-					out.RawByte(',')
-				}
-				out.String(typeKey)
-				out.RawByte(':')
-				out.String(typeValue)
+		out.RawByte('{')
+		isFirst := true
+		for _, col := range columns {
+			if col == nil || (onlyHandleKey && !col.Flag.IsHandleKey()) {
+				continue
 			}
-			out.RawByte('}')
+			if isFirst {
+				isFirst = false
+			} else {
+				out.RawByte(',')
+			}
+			out.String(col.Name)
+			out.RawByte(':')
+			out.String(cache.getMySQLType(col))
 		}
+		out.RawByte('}')
 	}
 
 	if e.IsDelete() {
 		out.RawString(",\"old\":null")
 		out.RawString(",\"data\":")
-		if err := fillColumns(e.PreColumns, false, onlyHandleKey, nil, out, builder); err != nil {
+		if err := fillColumns(e.PreColumns, false, onlyHandleKey, nil, out, builder, cache); err != nil {
 			return nil, err
 		}
 	} else if e.IsInsert() {
 		out.RawString(",\"old\":null")
 		out.RawString(",\"data\":")
-		if err := fillColumns(e.Columns, false, onlyHandleKey, nil, out, builder); err != nil {
+		if err := fillColumns(e.Columns, false, onlyHandleKey, nil, out, builder, cache); err != nil {
 			return nil, err
 		}
 	} else if e.IsUpdate() {
@@ -229,15 +259,17 @@ func newJSONMessageForDML(
 		if config.OnlyOutputUpdatedColumns {
 			newColsMap = make(map[string]*model.Column, len(e.Columns))
 			for _, col := range e.Columns {
-				newColsMap[col.Name] = col
+				if col != nil {
+					newColsMap[col.Name] = col
+				}
 			}
 		}
 		out.RawString(",\"old\":")
-		if err := fillColumns(e.PreColumns, config.OnlyOutputUpdatedColumns, onlyHandleKey, newColsMap, out, builder); err != nil {
+		if err := fillColumns(e.PreColumns, config.OnlyOutputUpdatedColumns, onlyHandleKey, newColsMap, out, builder, cache); err != nil {
 			return nil, err
 		}
 		out.RawString(",\"data\":")
-		if err := fillColumns(e.Columns, false, onlyHandleKey, nil, out, builder); err != nil {
+		if err := fillColumns(e.Columns, false, onlyHandleKey, nil, out, builder, cache); err != nil {
 			return nil, err
 		}
 	} else {
@@ -251,6 +283,10 @@ func newJSONMessageForDML(
 		out.RawString("\"commitTs\":")
 		out.Uint64(e.CommitTs)
 
+		if e.IsSnapshot {
+			out.RawString(",\"isSnapshot\":true")
+		}
+
 		// only send handle key may happen in 2 cases:
 		// 1. delete event, and set only handle key config. no need to encode `onlyHandleKey` field
 		// 2. event larger than the max message size, and enable large message handle to the `handleKeyOnly`, encode `onlyHandleKey` field
@@ -268,14 +304,68 @@ func newJSONMessageForDML(
 		out.RawByte('}')
 	}
 	out.RawByte('}')
+	return dumpJSONWriter(out)
+}
 
-	value, err := out.BuildBytes()
-	if err != nil {
+// dumpJSONWriter dumps the value into a pooled buffer, and the chunks of
+// the writer are put back to the pool of easyjson.
+func dumpJSONWriter(out *jwriter.Writer) (*bytes.Buffer, error) {
+	if out.Error != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, out.Error)
+	}
+	value := common.GetBuffer()
+	if _, err := out.DumpTo(value); err != nil {
+		common.PutBuffer(value)
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
 	return value, nil
 }
 
+// tableEncodeCache caches the parts of the messages which only depend on the
+// table, so they're computed once for the rows of the same table in a batch.
+// It's reset once the table info of the rows is changed.
+type tableEncodeCache struct {
+	tableInfo  *model.TableInfo
+	pkNames    []string
+	mysqlTypes map[string]string
+}
+
+// forRow returns the cache which is valid for the row, or nil if the row
+// can't be cached. The methods of a nil cache compute the results directly.
+func (c *tableEncodeCache) forRow(e *model.RowChangedEvent) *tableEncodeCache {
+	if c == nil || e.TableInfo == nil {
+		return nil
+	}
+	if c.tableInfo != e.TableInfo {
+		c.tableInfo = e.TableInfo
+		c.pkNames = nil
+		c.mysqlTypes = make(map[string]string, len(e.Columns))
+	}
+	return c
+}
+
+func (c *tableEncodeCache) primaryKeyColumnNames(e *model.RowChangedEvent) []string {
+	if c == nil {
+		return e.PrimaryKeyColumnNames()
+	}
+	if c.pkNames == nil {
+		c.pkNames = e.PrimaryKeyColumnNames()
+	}
+	return c.pkNames
+}
+
+func (c *tableEncodeCache) getMySQLType(col *model.Column) string {
+	if c == nil {
+		return getMySQLType(col)
+	}
+	mysqlType, ok := c.mysqlTypes[col.Name]
+	if !ok {
+		mysqlType = getMySQLType(col)
+		c.mysqlTypes[col.Name] = mysqlType
+	}
+	return mysqlType
+}
+
 func eventTypeString(e *model.RowChangedEvent) string {
 	if e.IsDelete() {
 		return "DELETE"
@@ -297,7 +387,7 @@ type JSONRowEventEncoder struct {
 // newJSONRowEventEncoder creates a new JSONRowEventEncoder
 func newJSONRowEventEncoder(config *common.Config) codec.RowEventEncoder {
 	encoder := &JSONRowEventEncoder{
-		builder:  newCanalEntryBuilder(),
+		builder:  newCanalJSONEntryBuilder(config),
 		messages: make([]*common.Message, 0, 1),
 
 		config: config,
@@ -363,14 +453,60 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	value, err := newJSONMessageForDML(c.builder, e, c.config, false)
+	return c.appendRowChangedEvent(e, callback, nil)
+}
+
+// AppendRowChangedEvents implements the RowEventBatchEncoder interface, the
+// parts of the messages which only depend on the table are computed once.
+func (c *JSONRowEventEncoder) AppendRowChangedEvents(
+	_ context.Context,
+	_ string,
+	events []*model.RowChangedEvent,
+	callbacks []func(),
+) (int, error) {
+	cache := &tableEncodeCache{}
+	for i, e := range events {
+		if err := c.appendRowChangedEvent(e, callbacks[i], cache); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+func (c *JSONRowEventEncoder) appendRowChangedEvent(
+	e *model.RowChangedEvent,
+	callback func(),
+	cache *tableEncodeCache,
+) error {
+	var (
+		key    []byte
+		keyBuf *bytes.Buffer
+	)
+	if c.config.DeleteAsTombstone {
+		var err error
+		if keyBuf, err = newJSONHandleKey(e); err != nil {
+			return errors.Trace(err)
+		}
+		key = keyBuf.Bytes()
+		if e.IsDelete() {
+			m := common.NewMsg(config.ProtocolCanalJSON, key, nil, e.CommitTs,
+				model.MessageTypeRow, &e.Table.Schema, &e.Table.Table)
+			m.SetPooledKey(keyBuf)
+			m.Callback = callback
+			m.IncRowsCount()
+			c.messages = append(c.messages, m)
+			return nil
+		}
+	}
+
+	value, err := newJSONMessageForDML(c.builder, e, c.config, false, cache)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
 	m := &common.Message{
-		Key:      nil,
-		Value:    value,
+		Key:      key,
+		Value:    value.Bytes(),
 		Ts:       e.CommitTs,
 		Schema:   &e.Table.Schema,
 		Table:    &e.Table.Table,
@@ -378,6 +514,8 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 		Protocol: config.ProtocolCanalJSON,
 		Callback: callback,
 	}
+	m.SetPooledKey(keyBuf)
+	m.SetPooledValue(value)
 	m.IncRowsCount()
 
 	originLength := m.Length()
@@ -392,11 +530,12 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 		}
 
 		if c.config.LargeMessageHandle.HandleKeyOnly() {
-			value, err = newJSONMessageForDML(c.builder, e, c.config, true)
+			value, err = newJSONMessageForDML(c.builder, e, c.config, true, cache)
 			if err != nil {
 				return cerror.ErrMessageTooLarge.GenWithStackByArgs()
 			}
-			m.Value = value
+			m.Value = value.Bytes()
+			m.SetPooledValue(value)
 			length := m.Length()
 			if length > c.config.MaxMessageBytes {
 				log.Error("Single message is still too large for canal-json only encode handle-key columns",
@@ -417,21 +556,84 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 			m.Event = e
 			m.ClaimCheckFileName = common.NewClaimCheckFileName(e)
 		}
+
+		if c.config.LargeMessageHandle.EnableCompression() {
+			codec := c.config.LargeMessageHandle.CompressionCodec()
+			if err = common.CompressLargeMessage(m, codec); err != nil {
+				return errors.Trace(err)
+			}
+			length := m.Length()
+			if length > c.config.MaxMessageBytes {
+				log.Error("Single message is still too large for canal-json after compression",
+					zap.Int("maxMessageBytes", c.config.MaxMessageBytes),
+					zap.Int("originLength", originLength),
+					zap.Int("length", length),
+					zap.String("compression", codec),
+					zap.Any("table", e.Table))
+				return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+			}
+			log.Warn("Single message is too large for canal-json, compress the value",
+				zap.Int("maxMessageBytes", c.config.MaxMessageBytes),
+				zap.Int("originLength", originLength),
+				zap.Int("length", length),
+				zap.Any("table", e.Table))
+		}
 	}
 
 	c.messages = append(c.messages, m)
 	return nil
 }
 
+// jsonHandleKey is the key of the messages if the delete events are encoded
+// as the tombstones, so all the messages of a row have the same key.
+type jsonHandleKey struct {
+	Database string            `json:"database"`
+	Table    string            `json:"table"`
+	Data     map[string]string `json:"data"`
+}
+
+// newJSONHandleKey returns the key of the message of the row, which consists
+// of the table and the handle key values of the row. It's written into a
+// pooled buffer.
+func newJSONHandleKey(e *model.RowChangedEvent) (*bytes.Buffer, error) {
+	cols := e.Columns
+	if e.IsDelete() {
+		cols = e.PreColumns
+	}
+	key := jsonHandleKey{
+		Database: e.Table.Schema,
+		Table:    e.Table.Table,
+		Data:     make(map[string]string),
+	}
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			key.Data[col.Name] = model.ColumnValueString(col.Value)
+		}
+	}
+	if len(key.Data) == 0 {
+		return nil, cerror.ErrCanalEncodeFailed.GenWithStack(
+			"table %s has no handle key for the tombstone", e.Table)
+	}
+	buf := common.GetBuffer()
+	if err := json.NewEncoder(buf).Encode(key); err != nil {
+		common.PutBuffer(buf)
+		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+	}
+	// trim the newline appended by the encoder.
+	buf.Truncate(buf.Len() - 1)
+	return buf, nil
+}
+
 // NewClaimCheckLocationMessage implements the ClaimCheckLocationEncoder interface
 func (c *JSONRowEventEncoder) NewClaimCheckLocationMessage(origin *common.Message) (*common.Message, error) {
-	value, err := newJSONMessageForDML(c.builder, origin.Event, c.config, true)
+	value, err := newJSONMessageForDML(c.builder, origin.Event, c.config, true, nil)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
 
-	result := common.NewMsg(config.ProtocolCanalJSON, nil, value, 0, model.MessageTypeRow, nil, nil)
+	result := common.NewMsg(config.ProtocolCanalJSON, origin.Key, value.Bytes(), 0, model.MessageTypeRow, nil, nil)
 	result.Callback = origin.Callback
+	result.SetPooledValue(value)
 	result.IncRowsCount()
 
 	length := result.Length()
@@ -484,15 +686,5 @@ func shouldIgnoreColumn(col *model.Column,
 	newColumnMap map[string]*model.Column,
 ) bool {
 	newCol, ok := newColumnMap[col.Name]
-	if ok && newCol != nil {
-		// sql type is not equal
-		if newCol.Type != col.Type {
-			return false
-		}
-		// value equal
-		if codec.IsColumnValueEqual(newCol.Value, col.Value) {
-			return true
-		}
-	}
-	return false
+	return ok && newCol != nil && !codec.IsColumnUpdated(col, newCol)
 }
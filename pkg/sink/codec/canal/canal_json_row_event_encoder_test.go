@@ -16,13 +16,16 @@ package canal
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"golang.org/x/text/encoding/charmap"
 )
 
@@ -46,11 +49,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	encoder, ok := e.(*JSONRowEventEncoder)
 	require.True(t, ok)
 
-	data, err := newJSONMessageForDML(encoder.builder, testCaseInsert, encoder.config, false)
+	data, err := newJSONMessageForDML(encoder.builder, testCaseInsert, encoder.config, false, nil)
 	require.NoError(t, err)
 
 	var msg canalJSONMessageInterface = &JSONMessage{}
-	err = json.Unmarshal(data, msg)
+	err = json.Unmarshal(data.Bytes(), msg)
 	require.NoError(t, err)
 
 	jsonMsg, ok := msg.(*JSONMessage)
@@ -97,11 +100,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 		require.Equal(t, item.expectedEncodedValue, obtainedValue)
 	}
 
-	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false)
+	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false, nil)
 	require.NoError(t, err)
 
 	jsonMsg = &JSONMessage{}
-	err = json.Unmarshal(data, jsonMsg)
+	err = json.Unmarshal(data.Bytes(), jsonMsg)
 	require.NoError(t, err)
 
 	require.NotNil(t, jsonMsg.Data)
@@ -117,11 +120,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 		require.Contains(t, jsonMsg.Old[0], col.Name)
 	}
 
-	data, err = newJSONMessageForDML(encoder.builder, testCaseDelete, encoder.config, false)
+	data, err = newJSONMessageForDML(encoder.builder, testCaseDelete, encoder.config, false, nil)
 	require.NoError(t, err)
 
 	jsonMsg = &JSONMessage{}
-	err = json.Unmarshal(data, jsonMsg)
+	err = json.Unmarshal(data.Bytes(), jsonMsg)
 	require.NoError(t, err)
 	require.NotNil(t, jsonMsg.Data)
 	require.Nil(t, jsonMsg.Old)
@@ -131,11 +134,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 		require.Contains(t, jsonMsg.Data[0], col.Name)
 	}
 
-	data, err = newJSONMessageForDML(encoder.builder, testCaseDelete, &common.Config{DeleteOnlyHandleKeyColumns: true}, false)
+	data, err = newJSONMessageForDML(encoder.builder, testCaseDelete, &common.Config{DeleteOnlyHandleKeyColumns: true}, false, nil)
 	require.NoError(t, err)
 
 	jsonMsg = &JSONMessage{}
-	err = json.Unmarshal(data, jsonMsg)
+	err = json.Unmarshal(data.Bytes(), jsonMsg)
 	require.NoError(t, err)
 	require.NotNil(t, jsonMsg.Data)
 	require.Nil(t, jsonMsg.Old)
@@ -160,11 +163,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 
 	encoder, ok = e.(*JSONRowEventEncoder)
 	require.True(t, ok)
-	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false)
+	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false, nil)
 	require.NoError(t, err)
 
 	withExtension := &canalJSONMessageWithTiDBExtension{}
-	err = json.Unmarshal(data, withExtension)
+	err = json.Unmarshal(data.Bytes(), withExtension)
 	require.NoError(t, err)
 
 	require.NotNil(t, withExtension.Extensions)
@@ -172,11 +175,11 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 
 	encoder, ok = e.(*JSONRowEventEncoder)
 	require.True(t, ok)
-	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false)
+	data, err = newJSONMessageForDML(encoder.builder, testCaseUpdate, encoder.config, false, nil)
 	require.NoError(t, err)
 
 	withExtension = &canalJSONMessageWithTiDBExtension{}
-	err = json.Unmarshal(data, withExtension)
+	err = json.Unmarshal(data.Bytes(), withExtension)
 	require.NoError(t, err)
 	require.Equal(t, 0, len(withExtension.JSONMessage.Old[0]))
 
@@ -216,6 +219,41 @@ func TestNewCanalJSONMessageHandleKeyOnly4LargeMessage(t *testing.T) {
 	}
 }
 
+func TestNewCanalJSONMessageCompression4LargeMessage(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionCompression
+	codecConfig.MaxMessageBytes = 500
+	encoder := newJSONRowEventEncoder(codecConfig)
+
+	event := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte(strings.Repeat("a", 1024)),
+			Flag:  model.HandleKeyFlag,
+		}},
+	}
+	err := encoder.AppendRowChangedEvent(context.Background(), "", event, func() {})
+	require.NoError(t, err)
+
+	message := encoder.Build()[0]
+	require.True(t, message.Compressed())
+	require.LessOrEqual(t, message.Length(), 500)
+	require.Equal(t, map[string]string{common.HeaderCompression: config.CompressionLZ4},
+		common.HeadersToMap(message.Headers))
+
+	value, err := common.Decompress(config.CompressionLZ4, message.Value)
+	require.NoError(t, err)
+	var decoded canalJSONMessageWithTiDBExtension
+	err = json.Unmarshal(value, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("a", 1024), decoded.Data[0]["col1"])
+}
+
 func TestNewCanalJSONMessageFromDDL(t *testing.T) {
 	t.Parallel()
 
@@ -533,3 +571,213 @@ func TestMaxMessageBytes(t *testing.T) {
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 }
+
+func TestNewCanalJSONMessageWithPhysicalTime(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.OutputPhysicalTime = true
+	encoder := newJSONRowEventEncoder(codecConfig)
+
+	before := time.Now().UnixMilli()
+	err := encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, func() {})
+	require.NoError(t, err)
+	message := encoder.Build()[0]
+
+	var decoded canalJSONMessageWithTiDBExtension
+	err = json.Unmarshal(message.Value, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, oracle.ExtractPhysical(testCaseInsert.CommitTs), decoded.CommitPhysicalTime)
+	require.GreaterOrEqual(t, decoded.IngestionTime, before)
+	require.LessOrEqual(t, decoded.IngestionTime, time.Now().UnixMilli())
+	// the TiDB extension is not output since it's disabled.
+	require.Nil(t, decoded.Extensions)
+	require.NotContains(t, string(message.Value), "_tidb")
+
+	codecConfig.EnableTiDBExtension = true
+	encoder = newJSONRowEventEncoder(codecConfig)
+	err = encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, func() {})
+	require.NoError(t, err)
+	message = encoder.Build()[0]
+
+	decoded = canalJSONMessageWithTiDBExtension{}
+	err = json.Unmarshal(message.Value, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, testCaseInsert.CommitTs, decoded.Extensions.CommitTs)
+	require.Equal(t, oracle.ExtractPhysical(testCaseInsert.CommitTs), decoded.CommitPhysicalTime)
+}
+
+func TestNewCanalJSONMessageWithSnapshot(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	encoder := newJSONRowEventEncoder(codecConfig)
+
+	snapshotRow := *testCaseInsert
+	snapshotRow.IsSnapshot = true
+	for _, row := range []*model.RowChangedEvent{testCaseInsert, &snapshotRow} {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, func() {})
+		require.NoError(t, err)
+		message := encoder.Build()[0]
+
+		var decoded canalJSONMessageWithTiDBExtension
+		err = json.Unmarshal(message.Value, &decoded)
+		require.NoError(t, err)
+		require.Equal(t, row.IsSnapshot, decoded.Extensions.IsSnapshot)
+	}
+}
+
+func TestNewCanalJSONMessageWithSelectedColumns(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.OnlyOutputUpdatedColumns = true
+	encoder := newJSONRowEventEncoder(codecConfig)
+
+	// the unselected columns are set to nil by the column selector.
+	row := *testCaseUpdate
+	row.Columns = append([]*model.Column(nil), testCaseUpdate.Columns...)
+	row.PreColumns = append([]*model.Column(nil), testCaseUpdate.PreColumns...)
+	dropped := row.Columns[1].Name
+	row.Columns[1], row.PreColumns[1] = nil, nil
+	err := encoder.AppendRowChangedEvent(context.Background(), "", &row, func() {})
+	require.NoError(t, err)
+	message := encoder.Build()[0]
+
+	var decoded JSONMessage
+	require.NoError(t, json.Unmarshal(message.Value, &decoded))
+	require.Len(t, decoded.Data, 1)
+	require.Len(t, decoded.Data[0], len(row.Columns)-1)
+	require.NotContains(t, decoded.Data[0], dropped)
+	require.NotContains(t, decoded.MySQLType, dropped)
+}
+
+func TestNewCanalJSONMessageDeleteAsTombstone(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.DeleteAsTombstone = true
+	encoder := newJSONRowEventEncoder(codecConfig)
+
+	ctx := context.Background()
+	for _, row := range []*model.RowChangedEvent{testCaseInsert, testCaseUpdate, testCaseDelete} {
+		require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", row, func() {}))
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 3)
+	// all the messages of the row have the same key.
+	expectedKey := `{"database":"cdc","table":"person","data":{"tinyint":"127"}}`
+	for _, message := range messages {
+		require.Equal(t, expectedKey, string(message.Key))
+		require.Equal(t, 1, message.GetRowsCount())
+	}
+	require.NotNil(t, messages[0].Value)
+	require.NotNil(t, messages[1].Value)
+	// the delete event is encoded as the tombstone.
+	require.Nil(t, messages[2].Value)
+
+	// the table without handle key can't be encoded as the tombstone.
+	row := *testCaseDelete
+	row.PreColumns = []*model.Column{{Name: "a", Value: int64(1)}}
+	err := encoder.AppendRowChangedEvent(ctx, "", &row, func() {})
+	require.ErrorContains(t, err, "has no handle key")
+}
+
+func TestAppendRowChangedEvents(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	encoder := newJSONRowEventEncoder(codecConfig)
+	batchEncoder := newJSONRowEventEncoder(codecConfig).(*JSONRowEventEncoder)
+
+	// the rows are copied since the test cases are shared by the tests.
+	tableInfo := &model.TableInfo{}
+	var events []*model.RowChangedEvent
+	var callbacks []func()
+	ctx := context.Background()
+	for _, row := range []*model.RowChangedEvent{testCaseInsert, testCaseUpdate, testCaseDelete} {
+		e := *row
+		e.TableInfo = tableInfo
+		events = append(events, &e)
+		callbacks = append(callbacks, func() {})
+		require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", &e, nil))
+	}
+	appended, err := batchEncoder.AppendRowChangedEvents(ctx, "", events, callbacks)
+	require.NoError(t, err)
+	require.Equal(t, len(events), appended)
+
+	// the batch encoding is the same as encoding the rows one by one.
+	expected := encoder.Build()
+	actual := batchEncoder.Build()
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		var expectedMsg, actualMsg JSONMessage
+		require.NoError(t, json.Unmarshal(expected[i].Value, &expectedMsg))
+		require.NoError(t, json.Unmarshal(actual[i].Value, &actualMsg))
+		expectedMsg.BuildTime, actualMsg.BuildTime = 0, 0
+		require.Equal(t, expectedMsg, actualMsg)
+		require.NotNil(t, actual[i].Callback)
+	}
+}
+
+func BenchmarkCanalJSONRowEventEncoder(b *testing.B) {
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	encoder := newJSONRowEventEncoder(codecConfig)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := encoder.AppendRowChangedEvent(ctx, "", testCaseUpdate, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, m := range encoder.Build() {
+			m.Release()
+		}
+	}
+}
+
+func TestNewCanalJSONFlatMessage4DML(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.CanalJSONFlat = true
+	codecConfig.OnlyOutputUpdatedColumns = true
+	builder := newCanalEntryBuilder()
+
+	for _, e := range []*model.RowChangedEvent{testCaseInsert, testCaseUpdate, testCaseDelete} {
+		value, err := newJSONMessageForDML(builder, e, codecConfig, false, nil)
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(value.Bytes(), &decoded))
+		require.Len(t, decoded, len(testColumns)+1)
+		require.Equal(t, "127", decoded["tinyint"])
+
+		meta := decoded[flatMetaKey].(map[string]interface{})
+		require.Equal(t, "cdc", meta["database"])
+		require.Equal(t, "person", meta["table"])
+		require.Equal(t, eventTypeString(e), meta["type"])
+		require.Equal(t, []interface{}{"tinyint"}, meta["pkNames"])
+		require.Equal(t, float64(convertToCanalTs(e.CommitTs)), meta["es"])
+		require.NotContains(t, meta, "commitPhysicalTime")
+		if e.IsUpdate() {
+			// all the columns are not changed.
+			require.Equal(t, map[string]interface{}{}, meta["old"])
+		} else {
+			require.NotContains(t, meta, "old")
+		}
+	}
+
+	// only the handle key columns are encoded for the large message.
+	codecConfig.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionHandleKeyOnly
+	value, err := newJSONMessageForDML(builder, testCaseInsert, codecConfig, true, nil)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(value.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+	require.Equal(t, true, decoded[flatMetaKey].(map[string]interface{})["onlyHandleKey"])
+}
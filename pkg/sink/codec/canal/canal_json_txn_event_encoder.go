@@ -49,22 +49,26 @@ func (j *JSONTxnEventEncoder) AppendTxnEvent(
 	txn *model.SingleTableTxn,
 	callback func(),
 ) error {
+	// the rows of a txn belong to the same table.
+	cache := &tableEncodeCache{}
 	for _, row := range txn.Rows {
-		value, err := newJSONMessageForDML(j.builder, row, j.config, false)
+		value, err := newJSONMessageForDML(j.builder, row, j.config, false, cache)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		length := len(value) + common.MaxRecordOverhead
+		length := value.Len() + common.MaxRecordOverhead
 		// For single message that is longer than max-message-bytes, do not send it.
 		if length > j.config.MaxMessageBytes {
 			log.Warn("Single message is too large for canal-json",
 				zap.Int("maxMessageBytes", j.config.MaxMessageBytes),
 				zap.Int("length", length),
 				zap.Any("table", row.Table))
+			common.PutBuffer(value)
 			return cerror.ErrMessageTooLarge.GenWithStackByArgs()
 		}
-		j.valueBuf.Write(value)
+		j.valueBuf.Write(value.Bytes())
 		j.valueBuf.Write(j.terminator)
+		common.PutBuffer(value)
 		j.batchSize++
 	}
 	j.callback = callback
@@ -97,7 +101,7 @@ func (j *JSONTxnEventEncoder) Build() []*common.Message {
 // newJSONTxnEventEncoder creates a new JSONTxnEventEncoder
 func newJSONTxnEventEncoder(config *common.Config) codec.TxnEventEncoder {
 	encoder := &JSONTxnEventEncoder{
-		builder:    newCanalEntryBuilder(),
+		builder:    newCanalJSONEntryBuilder(config),
 		valueBuf:   &bytes.Buffer{},
 		terminator: []byte(config.Terminator),
 
@@ -0,0 +1,166 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"context"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/pierrec/lz4"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// claimCheckReadTimeout is the timeout of reading a claim-check file.
+const claimCheckReadTimeout = 10 * time.Second
+
+// claimCheckBareDecoder decodes the bare claim-check messages, which only carry
+// the location of the claim-check files. The original messages are read from
+// the claim-check storage and decoded by the decoder of the protocol, and the
+// other messages are decoded by the decoder of the protocol directly.
+type claimCheckBareDecoder struct {
+	RowEventDecoder
+
+	// storage is the claim-check storage, it's nil if the storage URI isn't
+	// set, then the files are read by the URI or the URL in the messages.
+	storage     storage.ExternalStorage
+	compression string
+	httpClient  *http.Client
+}
+
+// NewClaimCheckBareDecoder wraps the decoder of the protocol to decode the bare
+// claim-check messages.
+func NewClaimCheckBareDecoder(
+	ctx context.Context, codecConfig *common.Config, decoder RowEventDecoder,
+) (RowEventDecoder, error) {
+	d := &claimCheckBareDecoder{
+		RowEventDecoder: decoder,
+		compression:     codecConfig.LargeMessageHandle.ClaimCheckCompression,
+		httpClient:      &http.Client{Timeout: claimCheckReadTimeout},
+	}
+	if storageURI := codecConfig.LargeMessageHandle.ClaimCheckStorageURI; storageURI != "" {
+		storage, err := util.GetExternalStorageFromURI(ctx, storageURI)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		d.storage = storage
+	}
+	return d, nil
+}
+
+// AddKeyValue implements the RowEventDecoder interface
+func (d *claimCheckBareDecoder) AddKeyValue(key, value []byte) error {
+	message, ok := parseClaimCheckBareMessage(key, value)
+	if !ok {
+		return d.RowEventDecoder.AddKeyValue(key, value)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), claimCheckReadTimeout)
+	defer cancel()
+	data, err := d.readFile(ctx, message)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCodecDecode, err)
+	}
+	if len(data) != message.Size || crc32.ChecksumIEEE(data) != message.Checksum {
+		return cerror.ErrCodecDecode.GenWithStack(
+			"claim-check file %s is corrupted, expect size %d and checksum %d, but got size %d and checksum %d",
+			message.Location, message.Size, message.Checksum, len(data), crc32.ChecksumIEEE(data))
+	}
+	data, err = d.decompress(data)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCodecDecode, err)
+	}
+	claimCheckM, err := common.UnmarshalClaimCheckMessage(data)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCodecDecode, err)
+	}
+	return d.RowEventDecoder.AddKeyValue(claimCheckM.Key, claimCheckM.Value)
+}
+
+// parseClaimCheckBareMessage returns the bare claim-check message and true if
+// the value is one. The bare messages have no key, and the values are JSON
+// objects with the location of the claim-check files.
+func parseClaimCheckBareMessage(key, value []byte) (*common.ClaimCheckBareMessage, bool) {
+	if len(key) != 0 || !bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) {
+		return nil, false
+	}
+	message, err := common.UnmarshalClaimCheckBareMessage(value)
+	if err != nil || message.Location == "" || (message.StorageURI == "" && message.URL == "") {
+		return nil, false
+	}
+	return message, true
+}
+
+// readFile reads the claim-check file from the presigned URL, or from the
+// claim-check storage.
+func (d *claimCheckBareDecoder) readFile(
+	ctx context.Context, message *common.ClaimCheckBareMessage,
+) ([]byte, error) {
+	if d.storage == nil && message.URL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, message.URL, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resp, err := d.httpClient.Do(req)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("get claim-check file %s failed, status: %s",
+				message.Location, resp.Status)
+		}
+		data, err := io.ReadAll(resp.Body)
+		return data, errors.Trace(err)
+	}
+
+	extStorage := d.storage
+	if extStorage == nil {
+		if message.StorageURI == "" {
+			return nil, errors.Errorf("claim-check storage of file %s is unknown", message.Location)
+		}
+		var err error
+		extStorage, err = util.GetExternalStorageFromURI(ctx, message.StorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The storage of the messages is the same in most cases.
+		d.storage = extStorage
+	}
+	data, err := extStorage.ReadFile(ctx, message.Location)
+	return data, errors.Trace(err)
+}
+
+// decompress decompresses the claim-check file compressed by the sink.
+func (d *claimCheckBareDecoder) decompress(data []byte) ([]byte, error) {
+	switch d.compression {
+	case config.CompressionSnappy:
+		result, err := snappy.Decode(nil, data)
+		return result, errors.Trace(err)
+	case config.CompressionLZ4:
+		result, err := io.ReadAll(lz4.NewReader(bytes.NewReader(data)))
+		return result, errors.Trace(err)
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"encoding/json"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRowEventDecoder records the keys and values added to it.
+type mockRowEventDecoder struct {
+	RowEventDecoder
+	keys   [][]byte
+	values [][]byte
+}
+
+func (d *mockRowEventDecoder) AddKeyValue(key, value []byte) error {
+	d.keys = append(d.keys, key)
+	d.values = append(d.values, value)
+	return nil
+}
+
+func (d *mockRowEventDecoder) HasNext() (model.MessageType, bool, error) {
+	return model.MessageTypeRow, len(d.values) > 0, nil
+}
+
+func TestClaimCheckBareDecoder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionClaimCheck,
+		ClaimCheckStorageURI:     "file:///" + dir,
+		ClaimCheckCompression:    config.CompressionSnappy,
+		ClaimCheckBareMessage:    true,
+	}
+	mockDecoder := &mockRowEventDecoder{}
+	decoder, err := NewClaimCheckBareDecoder(context.Background(), codecConfig, mockDecoder)
+	require.NoError(t, err)
+
+	// the messages which aren't bare are decoded directly.
+	require.NoError(t, decoder.AddKeyValue([]byte("key"), []byte(`{"location":"a"}`)))
+	require.NoError(t, decoder.AddKeyValue(nil, []byte(`{"id":1,"type":"INSERT"}`)))
+	require.Len(t, mockDecoder.values, 2)
+	require.Equal(t, []byte(`{"id":1,"type":"INSERT"}`), mockDecoder.values[1])
+
+	// the original message is read from the claim-check storage.
+	data, err := json.Marshal(common.ClaimCheckMessage{
+		Key:   []byte("origin-key"),
+		Value: []byte(`{"id":2,"type":"UPDATE"}`),
+	})
+	require.NoError(t, err)
+	data = snappy.Encode(nil, data)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test-t-1.json"), data, 0o644))
+	bare := common.ClaimCheckBareMessage{
+		StorageURI: "s3://bucket/prefix",
+		Location:   "test-t-1.json",
+		Size:       len(data),
+		Checksum:   crc32.ChecksumIEEE(data),
+	}
+	value, err := json.Marshal(bare)
+	require.NoError(t, err)
+	require.NoError(t, decoder.AddKeyValue(nil, value))
+	require.Len(t, mockDecoder.values, 3)
+	require.Equal(t, []byte("origin-key"), mockDecoder.keys[2])
+	require.Equal(t, []byte(`{"id":2,"type":"UPDATE"}`), mockDecoder.values[2])
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+
+	// the corrupted file is rejected.
+	bare.Checksum++
+	value, err = json.Marshal(bare)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, value)
+	require.ErrorContains(t, err, "claim-check file test-t-1.json is corrupted")
+	require.Len(t, mockDecoder.values, 3)
+}
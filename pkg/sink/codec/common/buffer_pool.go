@@ -0,0 +1,77 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	// defaultBufferSize is the initial capacity of the pooled buffers.
+	defaultBufferSize = 1024
+	// maxPooledBufferSize is the max capacity of the buffers put back to the
+	// pool, the larger ones are left to the GC to avoid holding the memory.
+	maxPooledBufferSize = 1024 * 1024
+)
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, defaultBufferSize))
+	},
+}
+
+// GetBuffer returns an empty buffer from the pool, which should be put back
+// by PutBuffer or set to a message once it's not used.
+func GetBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// PutBuffer puts the buffer back to the pool, the buffer and the bytes
+// returned by it must not be used after that.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// SetPooledKey sets the buffer of the pool which holds the Key of the
+// message, it's put back to the pool when the message is released. The
+// previous buffer is put back immediately, so it must not be referenced by
+// the message any more.
+func (m *Message) SetPooledKey(buf *bytes.Buffer) {
+	PutBuffer(m.pooledKey)
+	m.pooledKey = buf
+}
+
+// SetPooledValue sets the buffer of the pool which holds the Value of the
+// message, it's put back to the pool when the message is released. The
+// previous buffer is put back immediately, so it must not be referenced by
+// the message any more.
+func (m *Message) SetPooledValue(buf *bytes.Buffer) {
+	PutBuffer(m.pooledValue)
+	m.pooledValue = buf
+}
+
+// Release puts the pooled buffers back to the pool, it's called after the
+// message is sent, and the Key and Value must not be used after that.
+func (m *Message) Release() {
+	PutBuffer(m.pooledKey)
+	PutBuffer(m.pooledValue)
+	m.pooledKey = nil
+	m.pooledValue = nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferPool(t *testing.T) {
+	t.Parallel()
+
+	buf := GetBuffer()
+	require.Zero(t, buf.Len())
+	buf.WriteString("value")
+
+	key := GetBuffer()
+	key.WriteString("key")
+
+	m := NewMsg(config.ProtocolCanalJSON, key.Bytes(), buf.Bytes(), 1, model.MessageTypeRow, nil, nil)
+	m.SetPooledKey(key)
+	m.SetPooledValue(buf)
+	require.Equal(t, key, m.pooledKey)
+	require.Equal(t, buf, m.pooledValue)
+	// the previous buffer is replaced.
+	other := GetBuffer()
+	m.SetPooledValue(other)
+	require.Equal(t, other, m.pooledValue)
+	require.Equal(t, key, m.pooledKey)
+	m.Release()
+	require.Nil(t, m.pooledKey)
+	require.Nil(t, m.pooledValue)
+	// releasing the message again is a no-op.
+	m.Release()
+
+	// the buffers got from the pool are empty.
+	for i := 0; i < 10; i++ {
+		buf = GetBuffer()
+		require.Zero(t, buf.Len())
+		buf.WriteString("value")
+		PutBuffer(buf)
+	}
+	// the buffers larger than the limit are not pooled.
+	PutBuffer(bytes.NewBuffer(make([]byte, 0, maxPooledBufferSize+1)))
+	PutBuffer(nil)
+	for i := 0; i < 10; i++ {
+		require.LessOrEqual(t, GetBuffer().Cap(), maxPooledBufferSize)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// HeaderCompression marks the value of the message is compressed by the
+// large message handle, the header value is the compression codec.
+const HeaderCompression = config.MessageHeaderMetadataPrefix + "compression"
+
+// CompressLargeMessage compresses the value of the large message by the codec,
+// and marks it in the headers of the message.
+func CompressLargeMessage(m *Message, codec string) error {
+	value, err := Compress(codec, m.Value)
+	if err != nil {
+		return err
+	}
+	m.Value = value
+	m.Headers = append(m.Headers, MessageHeader{Key: HeaderCompression, Value: []byte(codec)})
+	return nil
+}
+
+// Compressed returns true if the value of the message is compressed by the
+// large message handle.
+func (m *Message) Compressed() bool {
+	for _, header := range m.Headers {
+		if header.Key == HeaderCompression {
+			return true
+		}
+	}
+	return false
+}
+
+// Compress compresses the data by the codec, which can be lz4 or zstd.
+func Compress(codec string, data []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+		err error
+	)
+	switch codec {
+	case config.CompressionLZ4:
+		w = lz4.NewWriter(&buf)
+	case config.CompressionZstd:
+		w, err = zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+		}
+	default:
+		return nil, cerror.ErrEncodeFailed.GenWithStackByArgs(
+			"unsupported compression codec " + codec)
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	if err = w.Close(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the data compressed by Compress.
+func Decompress(codec string, data []byte) ([]byte, error) {
+	var r io.Reader
+	switch codec {
+	case config.CompressionLZ4:
+		r = lz4.NewReader(bytes.NewReader(data))
+	case config.CompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"unsupported compression codec " + codec)
+	}
+
+	result, err := io.ReadAll(r)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("tidb"), 1024)
+	for _, codec := range []string{config.CompressionLZ4, config.CompressionZstd} {
+		m := &Message{Value: data}
+		require.False(t, m.Compressed())
+		err := CompressLargeMessage(m, codec)
+		require.NoError(t, err)
+		require.True(t, m.Compressed())
+		require.Less(t, len(m.Value), len(data))
+
+		value, err := Decompress(codec, m.Value)
+		require.NoError(t, err)
+		require.Equal(t, data, value)
+	}
+
+	_, err := Compress(config.CompressionSnappy, data)
+	require.Error(t, err)
+	_, err = Decompress(config.CompressionSnappy, data)
+	require.Error(t, err)
+}
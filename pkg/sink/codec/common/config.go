@@ -16,11 +16,14 @@ package common
 import (
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/imdario/mergo"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -48,8 +51,17 @@ type Config struct {
 
 	// avro only
 	AvroSchemaRegistry             string
+	AvroSchemaRegistryConfig       *config.SchemaRegistryConfig
 	AvroDecimalHandlingMode        string
 	AvroBigintUnsignedHandlingMode string
+	// TypeMappings maps the TiDB types to the types they're encoded as, which
+	// are the type mappings applying to the protocol, only for avro and json-schema.
+	TypeMappings map[string]string
+
+	// TemporalEncoder encodes the DATETIME and TIMESTAMP values, it's nil if
+	// they're encoded as the strings in the timezone of the TiCDC server.
+	// Only for open-protocol, canal-json, avro, json-schema and csv.
+	TemporalEncoder *TemporalEncoder
 
 	// EnableWatermarkEvent set to true, avro encode DDL and checkpoint event
 	// and send to the downstream kafka, they cannot be consumed by the confluent official consumer
@@ -64,9 +76,40 @@ type Config struct {
 	IncludeCommitTs      bool
 	Terminator           string
 	BinaryEncodingMethod string
-
-	// for open protocol
+	OutputHeader         bool
+	QuotingPolicy        string
+	EscapeChar           string
+	// HeaderInFirstFileOnly is true if only the first data file of each data
+	// directory has the header row, see FileHasHeader.
+	HeaderInFirstFileOnly bool
+	// CSVColumnRules specifies the csv columns of the matched tables.
+	CSVColumnRules []*CSVColumnRule
+
+	// ComputedColumnRules are the computed columns appended to the rows of
+	// the matched tables, they're added to the headers and the schemas
+	// derived from the table infos.
+	ComputedColumnRules []*ComputedColumnRule
+
+	// OnlyOutputUpdatedColumns drops the not updated columns of the update
+	// events, only for open-protocol, canal-json and avro.
 	OnlyOutputUpdatedColumns bool
+
+	// OpenProtocolVersion is the version of the open-protocol messages, the
+	// version 2 messages carry the column types, charsets and the full old
+	// values of the rows.
+	OpenProtocolVersion int
+
+	// OutputPhysicalTime set to true, the commit physical time and the ingestion time
+	// are output as explicit fields, only for open-protocol, canal-json and csv.
+	OutputPhysicalTime bool
+
+	// CanalJSONFlat set to true, each row is encoded into a flat JSON object
+	// instead of the nested data arrays, only for canal-json.
+	CanalJSONFlat bool
+
+	// DeleteAsTombstone set to true, the delete events are encoded as the
+	// tombstones keyed by the handle keys, only for canal-json and avro.
+	DeleteAsTombstone bool
 }
 
 // NewConfig return a Config for codec
@@ -86,6 +129,7 @@ func NewConfig(protocol config.Protocol) *Config {
 		AvroEnableWatermark:            false,
 
 		OnlyOutputUpdatedColumns:   false,
+		OpenProtocolVersion:        OpenProtocolVersion1,
 		DeleteOnlyHandleKeyColumns: false,
 		LargeMessageHandle:         config.NewDefaultLargeMessageHandleConfig(),
 	}
@@ -98,6 +142,18 @@ const (
 	codecOPTAvroSchemaRegistry             = "schema-registry"
 
 	codecOPTOnlyOutputUpdatedColumns = "only-output-updated-columns"
+	codecOPTOutputPhysicalTime       = "output-physical-time"
+	codecOPTCanalJSONFlat            = "canal-json-flat"
+	codecOPTDeleteAsTombstone        = "delete-as-tombstone"
+	codecOPTOpenProtocolVersion      = "version"
+)
+
+const (
+	// OpenProtocolVersion1 is the default version of the open-protocol messages.
+	OpenProtocolVersion1 = 1
+	// OpenProtocolVersion2 is the version of the self-describing open-protocol
+	// messages, which carry the column types and the old values of the rows.
+	OpenProtocolVersion2 = 2
 )
 
 const (
@@ -125,6 +181,10 @@ type urlConfig struct {
 
 	AvroSchemaRegistry       string `form:"schema-registry"`
 	OnlyOutputUpdatedColumns *bool  `form:"only-output-updated-columns"`
+	OutputPhysicalTime       *bool  `form:"output-physical-time"`
+	CanalJSONFlat            *bool  `form:"canal-json-flat"`
+	DeleteAsTombstone        *bool  `form:"delete-as-tombstone"`
+	OpenProtocolVersion      string `form:"version"`
 }
 
 // Apply fill the Config
@@ -168,9 +228,10 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 	if urlParameter.AvroSchemaRegistry != "" {
 		c.AvroSchemaRegistry = urlParameter.AvroSchemaRegistry
 	}
-	if c.Protocol == config.ProtocolAvro && replicaConfig.ForceReplicate {
+	if (c.Protocol == config.ProtocolAvro || c.Protocol == config.ProtocolJSONSchema) &&
+		replicaConfig.ForceReplicate {
 		return cerror.ErrCodecInvalidConfig.GenWithStack(
-			`force-replicate must be disabled, when using avro protocol`)
+			`force-replicate must be disabled, when using %s protocol`, c.Protocol)
 	}
 
 	if replicaConfig.Sink != nil {
@@ -181,10 +242,41 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 			c.NullString = replicaConfig.Sink.CSVConfig.NullString
 			c.IncludeCommitTs = replicaConfig.Sink.CSVConfig.IncludeCommitTs
 			c.BinaryEncodingMethod = replicaConfig.Sink.CSVConfig.BinaryEncodingMethod
+			headerPolicy := replicaConfig.Sink.CSVConfig.HeaderPolicy()
+			c.OutputHeader = headerPolicy != config.CSVHeaderNone
+			c.HeaderInFirstFileOnly = headerPolicy == config.CSVHeaderFirstFile
+			c.QuotingPolicy = replicaConfig.Sink.CSVConfig.QuotingPolicy
+			c.EscapeChar = replicaConfig.Sink.CSVConfig.EscapeChar
+			rules, err := newCSVColumnRules(replicaConfig.Sink.CSVConfig.ColumnRules,
+				replicaConfig.CaseSensitive)
+			if err != nil {
+				return err
+			}
+			c.CSVColumnRules = rules
 		}
 		if replicaConfig.Sink.KafkaConfig != nil {
 			c.LargeMessageHandle = replicaConfig.Sink.KafkaConfig.LargeMessageHandle
 		}
+		c.AvroSchemaRegistryConfig = replicaConfig.Sink.SchemaRegistryConfig
+		rules, err := newComputedColumnRules(replicaConfig.Sink.ComputedColumns,
+			replicaConfig.CaseSensitive)
+		if err != nil {
+			return err
+		}
+		c.ComputedColumnRules = rules
+		c.TypeMappings = newTypeMappings(replicaConfig.Sink.TypeMappings, c.Protocol)
+		if replicaConfig.Sink.TemporalEncoding != nil &&
+			replicaConfig.Sink.TemporalEncoding.MatchProtocol(c.Protocol) {
+			// the TIMESTAMP values are in the timezone of the TiCDC server.
+			tz, err := util.GetTimezone(config.GetGlobalServerConfig().TZ)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			c.TemporalEncoder, err = NewTemporalEncoder(replicaConfig.Sink.TemporalEncoding, tz)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
 		if c.LargeMessageHandle.HandleKeyOnly() && replicaConfig.ForceReplicate {
 			return cerror.ErrCodecInvalidConfig.GenWithStack(
 				`force-replicate must be disabled, when the large message handle option is set to "handle-key-only"`)
@@ -193,12 +285,38 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 	if urlParameter.OnlyOutputUpdatedColumns != nil {
 		c.OnlyOutputUpdatedColumns = *urlParameter.OnlyOutputUpdatedColumns
 	}
+	if urlParameter.OutputPhysicalTime != nil {
+		c.OutputPhysicalTime = *urlParameter.OutputPhysicalTime
+	}
+	if urlParameter.CanalJSONFlat != nil {
+		c.CanalJSONFlat = *urlParameter.CanalJSONFlat
+	}
+	if urlParameter.DeleteAsTombstone != nil {
+		c.DeleteAsTombstone = *urlParameter.DeleteAsTombstone
+	}
+	// the parameter is only parsed for the open-protocol, since it may be a
+	// parameter of the endpoint for the other sinks, such as the webhook sink.
+	if c.Protocol == config.ProtocolOpen && urlParameter.OpenProtocolVersion != "" {
+		version, err := strconv.Atoi(urlParameter.OpenProtocolVersion)
+		if err != nil {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`invalid %s "%s"`, codecOPTOpenProtocolVersion, urlParameter.OpenProtocolVersion)
+		}
+		c.OpenProtocolVersion = version
+	}
 	if c.OnlyOutputUpdatedColumns && !replicaConfig.EnableOldValue {
 		return cerror.ErrCodecInvalidConfig.GenWithStack(
 			`old value must be enabled when configuration "%s" is true.`,
 			codecOPTOnlyOutputUpdatedColumns,
 		)
 	}
+	if c.Protocol == config.ProtocolOpen &&
+		c.OpenProtocolVersion == OpenProtocolVersion2 && !replicaConfig.EnableOldValue {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`old value must be enabled when configuration "%s" is %d.`,
+			codecOPTOpenProtocolVersion, OpenProtocolVersion2,
+		)
+	}
 
 	if replicaConfig.Integrity != nil {
 		c.EnableRowChecksum = replicaConfig.Integrity.Enabled()
@@ -209,9 +327,31 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		return cerror.ErrCodecInvalidConfig.GenWithStack(
 			`force-replicate must be disabled when configuration "delete-only-output-handle-key-columns" is true.`)
 	}
+	// the tombstones of the tables without handle key have no key.
+	if c.DeleteAsTombstone && replicaConfig.ForceReplicate {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`force-replicate must be disabled when configuration "%s" is true.`,
+			codecOPTDeleteAsTombstone)
+	}
 	return nil
 }
 
+// newTypeMappings returns the targets of the source types mapped by the
+// mappings applying to the protocol, the latter ones take precedence.
+func newTypeMappings(mappings []*config.TypeMapping, protocol config.Protocol) map[string]string {
+	var result map[string]string
+	for _, mapping := range mappings {
+		if !mapping.MatchProtocol(protocol) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[strings.ToLower(mapping.Source)] = strings.ToLower(mapping.Target)
+	}
+	return result
+}
+
 func mergeConfig(
 	replicaConfig *config.ReplicaConfig,
 	urlParameters *urlConfig,
@@ -220,6 +360,9 @@ func mergeConfig(
 	if replicaConfig.Sink != nil {
 		dest.AvroSchemaRegistry = util.GetOrZero(replicaConfig.Sink.SchemaRegistry)
 		dest.OnlyOutputUpdatedColumns = replicaConfig.Sink.OnlyOutputUpdatedColumns
+		dest.OutputPhysicalTime = replicaConfig.Sink.OutputPhysicalTime
+		dest.CanalJSONFlat = replicaConfig.Sink.CanalJSONFlat
+		dest.DeleteAsTombstone = replicaConfig.Sink.DeleteAsTombstone
 		if replicaConfig.Sink.KafkaConfig != nil {
 			dest.MaxMessageBytes = replicaConfig.Sink.KafkaConfig.MaxMessageBytes
 			if replicaConfig.Sink.KafkaConfig.CodecConfig != nil {
@@ -254,8 +397,49 @@ func (c *Config) Validate() error {
 			zap.String("protocol", c.Protocol.String()))
 	}
 
+	if c.OutputPhysicalTime &&
+		!(c.Protocol == config.ProtocolOpen ||
+			c.Protocol == config.ProtocolCanalJSON ||
+			c.Protocol == config.ProtocolCsv) {
+		log.Warn("ignore invalid config, "+codecOPTOutputPhysicalTime+
+			" only supports open-protocol/canal-json/csv protocol",
+			zap.Bool("outputPhysicalTime", c.OutputPhysicalTime),
+			zap.String("protocol", c.Protocol.String()))
+	}
+
+	if c.CanalJSONFlat && c.Protocol != config.ProtocolCanalJSON {
+		log.Warn("ignore invalid config, "+codecOPTCanalJSONFlat+
+			" only supports canal-json protocol",
+			zap.Bool("canalJSONFlat", c.CanalJSONFlat),
+			zap.String("protocol", c.Protocol.String()))
+	}
+
+	if c.DeleteAsTombstone &&
+		!(c.Protocol == config.ProtocolCanalJSON || c.Protocol == config.ProtocolAvro) {
+		log.Warn("ignore invalid config, "+codecOPTDeleteAsTombstone+
+			" only supports canal-json/avro protocol",
+			zap.Bool("deleteAsTombstone", c.DeleteAsTombstone),
+			zap.String("protocol", c.Protocol.String()))
+		c.DeleteAsTombstone = false
+	}
+
+	if c.Protocol == config.ProtocolOpen {
+		if c.OpenProtocolVersion != OpenProtocolVersion1 &&
+			c.OpenProtocolVersion != OpenProtocolVersion2 {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be %d or %d`,
+				codecOPTOpenProtocolVersion, OpenProtocolVersion1, OpenProtocolVersion2)
+		}
+		if c.OpenProtocolVersion == OpenProtocolVersion2 && c.OnlyOutputUpdatedColumns {
+			log.Warn("ignore invalid config, " + codecOPTOnlyOutputUpdatedColumns +
+				" is not supported by the open-protocol version 2, " +
+				"the full old values are always output")
+		}
+	}
+
 	if c.Protocol == config.ProtocolAvro {
-		if c.AvroSchemaRegistry == "" {
+		// the AWS Glue Schema Registry is not accessed by the URL.
+		if c.AvroSchemaRegistry == "" && !c.AvroSchemaRegistryConfig.IsGlue() {
 			return cerror.ErrCodecInvalidConfig.GenWithStack(
 				`Avro protocol requires parameter "%s"`,
 				codecOPTAvroSchemaRegistry,
@@ -282,6 +466,13 @@ func (c *Config) Validate() error {
 			)
 		}
 
+		// the checksum can't be verified if some columns are dropped.
+		if c.EnableRowChecksum && c.OnlyOutputUpdatedColumns {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`Avro protocol with row level checksum doesn't support "%s"`,
+				codecOPTOnlyOutputUpdatedColumns)
+		}
+
 		if c.EnableRowChecksum {
 			if !(c.EnableTiDBExtension && c.AvroDecimalHandlingMode == DecimalHandlingModeString &&
 				c.AvroBigintUnsignedHandlingMode == BigintUnsignedHandlingModeString) {
@@ -295,6 +486,35 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Protocol == config.ProtocolJSONSchema {
+		if c.AvroSchemaRegistry == "" && !c.AvroSchemaRegistryConfig.IsGlue() {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`JSON Schema protocol requires parameter "%s"`,
+				codecOPTAvroSchemaRegistry,
+			)
+		}
+
+		if c.AvroDecimalHandlingMode != DecimalHandlingModePrecise &&
+			c.AvroDecimalHandlingMode != DecimalHandlingModeString {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be "%s" or "%s"`,
+				codecOPTAvroDecimalHandlingMode,
+				DecimalHandlingModeString,
+				DecimalHandlingModePrecise,
+			)
+		}
+
+		if c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeLong &&
+			c.AvroBigintUnsignedHandlingMode != BigintUnsignedHandlingModeString {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s value could only be "%s" or "%s"`,
+				codecOPTAvroBigintUnsignedHandlingMode,
+				BigintUnsignedHandlingModeLong,
+				BigintUnsignedHandlingModeString,
+			)
+		}
+	}
+
 	if c.MaxMessageBytes <= 0 {
 		return cerror.ErrCodecInvalidConfig.Wrap(
 			errors.Errorf("invalid max-message-bytes %d", c.MaxMessageBytes),
@@ -316,3 +536,86 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// CSVColumnRule is the csv columns of the tables matched by the table matcher.
+type CSVColumnRule struct {
+	TableMatcher tfilter.Filter
+	Columns      []string
+}
+
+func newCSVColumnRules(
+	rules []*config.CSVColumnRule, caseSensitive bool,
+) ([]*CSVColumnRule, error) {
+	var result []*CSVColumnRule
+	for _, r := range rules {
+		tableMatcher, err := tfilter.Parse(r.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCodecInvalidConfig, err)
+		}
+		if !caseSensitive {
+			tableMatcher = tfilter.CaseInsensitive(tableMatcher)
+		}
+		result = append(result, &CSVColumnRule{
+			TableMatcher: tableMatcher,
+			Columns:      r.Columns,
+		})
+	}
+	return result, nil
+}
+
+// ComputedColumnRule is a computed column of the tables matched by the
+// table matcher.
+type ComputedColumnRule struct {
+	TableMatcher tfilter.Filter
+	Name         string
+}
+
+func newComputedColumnRules(
+	columns []*config.ComputedColumn, caseSensitive bool,
+) ([]*ComputedColumnRule, error) {
+	var result []*ComputedColumnRule
+	for _, column := range columns {
+		tableMatcher, err := tfilter.Parse(column.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCodecInvalidConfig, err)
+		}
+		if !caseSensitive {
+			tableMatcher = tfilter.CaseInsensitive(tableMatcher)
+		}
+		result = append(result, &ComputedColumnRule{
+			TableMatcher: tableMatcher,
+			Name:         column.Name,
+		})
+	}
+	return result, nil
+}
+
+// ComputedColumns returns the names of the computed columns appended to the
+// rows of the table in order, which are the same as the ones appended by
+// the filter.ColumnAppender.
+func (c *Config) ComputedColumns(schema, table string) []string {
+	var names []string
+	for _, r := range c.ComputedColumnRules {
+		if r.TableMatcher.MatchTable(schema, table) {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// FileHasHeader returns whether the csv data file with the given file index
+// has the header row, the file index starts from 1 in each data directory.
+func (c *Config) FileHasHeader(fileIndex uint64) bool {
+	return c.OutputHeader && (!c.HeaderInFirstFileOnly || fileIndex == 1)
+}
+
+// CSVColumns returns the csv columns of the first matched column rule,
+// or nil if the table isn't matched by any rule.
+func (c *Config) CSVColumns(schema, table string) []string {
+	for _, r := range c.CSVColumnRules {
+		if r.TableMatcher.MatchTable(schema, table) {
+			return r.Columns
+		}
+	}
+	return nil
+}
@@ -66,6 +66,11 @@ func TestConfigApplyValidate4EnableRowChecksum(t *testing.T) {
 	err = c.Validate()
 	require.NoError(t, err)
 
+	// avro, the not updated columns can't be dropped if the checksum is enabled.
+	c.OnlyOutputUpdatedColumns = true
+	err = c.Validate()
+	require.ErrorContains(t, err, "only-output-updated-columns")
+
 	// avo, not all requirement satisfied, return error
 	invalidSinkURI := []string{
 		"kafka://127.0.0.1:9092/abc?protocol=avro",
@@ -532,3 +537,200 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, 456, c.MaxBatchSize)
 	require.Equal(t, c.LargeMessageHandle.LargeMessageHandleOption, config.LargeMessageHandleOptionClaimCheck)
 }
+
+func TestApplyOutputPhysicalTime(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.False(t, c.OutputPhysicalTime)
+
+	replicaConfig.Sink.OutputPhysicalTime = aws.Bool(true)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.OutputPhysicalTime)
+
+	// the option can also be set by the sink URI.
+	replicaConfig.Sink.OutputPhysicalTime = nil
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json&output-physical-time=true")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.OutputPhysicalTime)
+}
+
+func TestApplyCSVHeaderPolicy(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	c := NewConfig(config.ProtocolCsv)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.False(t, c.FileHasHeader(1))
+
+	replicaConfig.Sink.CSVConfig.IncludeHeader = config.CSVHeaderEveryFile
+	c = NewConfig(config.ProtocolCsv)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.FileHasHeader(1))
+	require.True(t, c.FileHasHeader(2))
+
+	replicaConfig.Sink.CSVConfig.IncludeHeader = config.CSVHeaderFirstFile
+	c = NewConfig(config.ProtocolCsv)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.FileHasHeader(1))
+	require.False(t, c.FileHasHeader(2))
+}
+
+func TestApplyCanalJSONFlat(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.False(t, c.CanalJSONFlat)
+
+	replicaConfig.Sink.CanalJSONFlat = aws.Bool(true)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.CanalJSONFlat)
+
+	// the option can also be set by the sink URI.
+	replicaConfig.Sink.CanalJSONFlat = nil
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json&canal-json-flat=true")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.CanalJSONFlat)
+}
+
+func TestApplyDeleteAsTombstone(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DeleteAsTombstone = aws.Bool(true)
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+	require.True(t, c.DeleteAsTombstone)
+
+	// the option is ignored by the other protocols.
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=open-protocol")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+	require.False(t, c.DeleteAsTombstone)
+
+	// the tables without handle key can't be replicated.
+	replicaConfig.ForceReplicate = true
+	c = NewConfig(config.ProtocolCanalJSON)
+	err = c.Apply(sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "force-replicate must be disabled")
+}
+
+func TestApplyJSONSchemaProtocol(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=json-schema")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolJSONSchema)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), `JSON Schema protocol requires parameter "schema-registry"`)
+
+	replicaConfig.Sink.SchemaRegistry = util.AddressOf("http://127.0.0.1:8081")
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+
+	// the AWS Glue Schema Registry is not accessed by the URL.
+	replicaConfig.Sink.SchemaRegistry = nil
+	replicaConfig.Sink.SchemaRegistryConfig = &config.SchemaRegistryConfig{
+		Provider:         util.AddressOf(config.SchemaRegistryProviderGlue),
+		GlueRegistryName: util.AddressOf("ticdc"),
+	}
+	c = NewConfig(config.ProtocolJSONSchema)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+
+	replicaConfig.ForceReplicate = true
+	err = c.Apply(sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "force-replicate must be disabled, when using json-schema protocol")
+}
+
+func TestApplyTypeMappings(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.TypeMappings = []*config.TypeMapping{
+		{Source: "BIT", Target: "int"},
+		{Protocols: []string{"json-schema"}, Source: "tinyint(1)", Target: "bool"},
+	}
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=avro&schema-registry=http://127.0.0.1:8081")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolAvro)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.Equal(t, map[string]string{config.TypeMappingSourceBit: config.TypeMappingTargetInt}, c.TypeMappings)
+
+	c = NewConfig(config.ProtocolJSONSchema)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.Equal(t, map[string]string{
+		config.TypeMappingSourceBit:      config.TypeMappingTargetInt,
+		config.TypeMappingSourceTinyInt1: config.TypeMappingTargetBool,
+	}, c.TypeMappings)
+
+	// the mappings don't apply to the other protocols.
+	c = NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.Nil(t, c.TypeMappings)
+}
+
+func TestApplyOpenProtocolVersion(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=open-protocol")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+	require.Equal(t, OpenProtocolVersion1, c.OpenProtocolVersion)
+
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=open-protocol&version=2")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.NoError(t, c.Validate())
+	require.Equal(t, OpenProtocolVersion2, c.OpenProtocolVersion)
+
+	// the old values are required by the version 2.
+	replicaConfig.EnableOldValue = false
+	c = NewConfig(config.ProtocolOpen)
+	require.ErrorContains(t, c.Apply(sinkURI, replicaConfig), "old value must be enabled")
+
+	replicaConfig.EnableOldValue = true
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/abc?protocol=open-protocol&version=3")
+	require.NoError(t, err)
+	c = NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.ErrorContains(t, c.Validate(), "version value could only be 1 or 2")
+}
+
+func TestApplyVersionParameterOfOtherProtocols(t *testing.T) {
+	t.Parallel()
+
+	// the version parameter isn't parsed for the other protocols.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?protocol=canal-json&version=v1")
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolCanalJSON)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.Equal(t, OpenProtocolVersion1, c.OpenProtocolVersion)
+}
@@ -14,6 +14,7 @@
 package common
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"path"
@@ -54,13 +55,23 @@ type Message struct {
 
 	// PartitionKey for pulsar, route messages to one or different partitions
 	PartitionKey *string
+
+	// Headers are attached to the message when it's produced to the MQ system.
+	Headers []MessageHeader
+
+	// pooledKey and pooledValue are put back to the buffer pool when the
+	// message is released.
+	pooledKey   *bytes.Buffer
+	pooledValue *bytes.Buffer
 }
 
-// Length returns the expected size of the Kafka message
-// We didn't append any `Headers` when send the message, so ignore the calculations related to it.
-// If `ProducerMessage` Headers fields used, this method should also adjust.
+// Length returns the expected size of the Kafka message, including the `Headers`.
 func (m *Message) Length() int {
-	return len(m.Key) + len(m.Value) + MaxRecordOverhead
+	length := len(m.Key) + len(m.Value) + MaxRecordOverhead
+	for _, header := range m.Headers {
+		length += len(header.Key) + len(header.Value) + 2*binary.MaxVarintLen32
+	}
+	return length
 }
 
 // PhysicalTime returns physical time part of Ts in time.Time
@@ -172,6 +183,59 @@ func UnmarshalClaimCheckMessage(data []byte) (*ClaimCheckMessage, error) {
 	return &m, err
 }
 
+// ClaimCheckBareMessage is the pointer message sent to the MQ when the claim-check
+// bare message is enabled. It carries nothing about the event itself, consumers are
+// expected to always fetch the original message from the claim-check storage.
+type ClaimCheckBareMessage struct {
+	// StorageURI is the URI of the claim-check external storage, it's empty if
+	// the URL is presigned.
+	StorageURI string `json:"storageURI,omitempty"`
+	// URL is the presigned URL to get the file without the credentials of the
+	// storage, it's only set if claim-check-presigned-url-expiry is set.
+	URL string `json:"url,omitempty"`
+	// Location is the file name of the message in the claim-check storage.
+	Location string `json:"location"`
+	// Size is the length of the file in bytes.
+	Size int `json:"size"`
+	// Checksum is the CRC32 (IEEE) checksum of the file content.
+	Checksum uint32 `json:"checksum"`
+}
+
+// UnmarshalClaimCheckBareMessage unmarshal bytes to ClaimCheckBareMessage.
+func UnmarshalClaimCheckBareMessage(data []byte) (*ClaimCheckBareMessage, error) {
+	var m ClaimCheckBareMessage
+	err := json.Unmarshal(data, &m)
+	return &m, err
+}
+
+// DeadLetterMessage is the message of a row changed event which can not be encoded,
+// it's sent to the dead-letter topic or storage together with the error metadata.
+type DeadLetterMessage struct {
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	CommitTs uint64 `json:"commitTs"`
+	// Topic and Partition are where the event should be sent to.
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	// ErrorCode is the RFC code of the encoding error if it has one.
+	ErrorCode string `json:"errorCode,omitempty"`
+	Error     string `json:"error"`
+	// HandleKey contains the handle key columns of the event.
+	HandleKey map[string]interface{} `json:"handleKey,omitempty"`
+	// Columns and PreColumns contain all the columns of the event, they are only
+	// written to the dead-letter storage, since the event may be too large to be
+	// sent to the dead-letter topic.
+	Columns    map[string]interface{} `json:"columns,omitempty"`
+	PreColumns map[string]interface{} `json:"preColumns,omitempty"`
+}
+
+// UnmarshalDeadLetterMessage unmarshal bytes to DeadLetterMessage.
+func UnmarshalDeadLetterMessage(data []byte) (*DeadLetterMessage, error) {
+	var m DeadLetterMessage
+	err := json.Unmarshal(data, &m)
+	return &m, err
+}
+
 // NewClaimCheckFileName return file name for sent the message to claim check storage.
 // make sure the file name can identify one event uniquely.
 // {date}/{schema}-{table}-{commitTs}-{startTs}-{handleKeys}.json
@@ -0,0 +1,157 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// The metadata headers attached to the messages.
+const (
+	HeaderSchema       = config.MessageHeaderMetadataPrefix + "schema"
+	HeaderTable        = config.MessageHeaderMetadataPrefix + "table"
+	HeaderCommitTs     = config.MessageHeaderMetadataPrefix + "commit-ts"
+	HeaderNamespace    = config.MessageHeaderMetadataPrefix + "namespace"
+	HeaderChangefeedID = config.MessageHeaderMetadataPrefix + "changefeed-id"
+	HeaderEventType    = config.MessageHeaderMetadataPrefix + "event-type"
+	// HeaderOriginID is the source ID of the TiDB cluster where the rows of
+	// the message are written originally.
+	HeaderOriginID = config.MessageHeaderMetadataPrefix + "origin-id"
+)
+
+// The values of the event type header.
+const (
+	EventTypeRow       = "row"
+	EventTypeDDL       = "ddl"
+	EventTypeResolved  = "resolved"
+	EventTypeBootstrap = "bootstrap"
+)
+
+// MessageHeader is a key-value pair attached to the message,
+// it's the record header of kafka and the property of pulsar.
+type MessageHeader struct {
+	Key   string
+	Value []byte
+}
+
+// HeadersBuilder attaches the configured headers to the messages.
+type HeadersBuilder struct {
+	enableMetadata bool
+	namespace      []byte
+	changefeedID   []byte
+	// sourceID is the source ID of the upstream cluster.
+	sourceID uint64
+	// staticHeaders are sorted by the key.
+	staticHeaders []MessageHeader
+}
+
+// NewHeadersBuilder return a new HeadersBuilder, it returns nil if no
+// header is configured.
+func NewHeadersBuilder(
+	changefeedID model.ChangeFeedID, sourceID uint64, cfg *config.MessageHeadersConfig,
+) *HeadersBuilder {
+	if cfg == nil || (!util.GetOrZero(cfg.EnableMetadata) && len(cfg.StaticHeaders) == 0) {
+		return nil
+	}
+	b := &HeadersBuilder{
+		enableMetadata: util.GetOrZero(cfg.EnableMetadata),
+		namespace:      []byte(changefeedID.Namespace),
+		changefeedID:   []byte(changefeedID.ID),
+		sourceID:       sourceID,
+	}
+	for key, value := range cfg.StaticHeaders {
+		b.staticHeaders = append(b.staticHeaders, MessageHeader{Key: key, Value: []byte(value)})
+	}
+	sort.Slice(b.staticHeaders, func(i, j int) bool {
+		return b.staticHeaders[i].Key < b.staticHeaders[j].Key
+	})
+	return b
+}
+
+// Attach appends the headers to the message, it's a no-op if the builder is nil.
+func (b *HeadersBuilder) Attach(m *Message) {
+	if b == nil {
+		return
+	}
+	if b.enableMetadata {
+		if m.Schema != nil {
+			m.Headers = append(m.Headers, MessageHeader{Key: HeaderSchema, Value: []byte(*m.Schema)})
+		}
+		if m.Table != nil {
+			m.Headers = append(m.Headers, MessageHeader{Key: HeaderTable, Value: []byte(*m.Table)})
+		}
+		if m.Ts != 0 {
+			m.Headers = append(m.Headers, MessageHeader{
+				Key:   HeaderCommitTs,
+				Value: []byte(strconv.FormatUint(m.Ts, 10)),
+			})
+		}
+		m.Headers = append(m.Headers,
+			MessageHeader{Key: HeaderNamespace, Value: b.namespace},
+			MessageHeader{Key: HeaderChangefeedID, Value: b.changefeedID})
+		if eventType := messageEventType(m.Type); eventType != "" {
+			m.Headers = append(m.Headers, MessageHeader{Key: HeaderEventType, Value: []byte(eventType)})
+		}
+	}
+	m.Headers = append(m.Headers, b.staticHeaders...)
+}
+
+// AttachOrigin appends the origin-id header to the message of the rows from
+// the same origin, zero means the rows are written in the upstream directly.
+// It's a no-op if the metadata headers are disabled.
+func (b *HeadersBuilder) AttachOrigin(m *Message, originID uint64) {
+	if b == nil || !b.enableMetadata {
+		return
+	}
+	if originID == 0 {
+		originID = b.sourceID
+	}
+	m.Headers = append(m.Headers, MessageHeader{
+		Key:   HeaderOriginID,
+		Value: []byte(strconv.FormatUint(originID, 10)),
+	})
+}
+
+// HeadersToMap converts the headers to a map, the latter one wins if
+// there are duplicated keys.
+func HeadersToMap(headers []MessageHeader) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		result[header.Key] = string(header.Value)
+	}
+	return result
+}
+
+func messageEventType(t model.MessageType) string {
+	switch t {
+	case model.MessageTypeRow:
+		return EventTypeRow
+	case model.MessageTypeDDL:
+		return EventTypeDDL
+	case model.MessageTypeResolved:
+		return EventTypeResolved
+	case model.MessageTypeBootstrap:
+		return EventTypeBootstrap
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHeadersBuilder(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	require.Nil(t, NewHeadersBuilder(changefeedID, 1, nil))
+	require.Nil(t, NewHeadersBuilder(changefeedID, 1, &config.MessageHeadersConfig{
+		EnableMetadata: util.AddressOf(false),
+	}))
+
+	// it's safe to attach headers by a nil builder.
+	var b *HeadersBuilder
+	m := &Message{}
+	b.Attach(m)
+	require.Empty(t, m.Headers)
+}
+
+func TestAttachHeaders(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	b := NewHeadersBuilder(changefeedID, 1, &config.MessageHeadersConfig{
+		EnableMetadata: util.AddressOf(true),
+		StaticHeaders:  map[string]string{"region": "us", "env": "prod"},
+	})
+
+	schema, table := "a", "b"
+	m := NewMsg(config.ProtocolCanalJSON, nil, []byte("value"), 1,
+		model.MessageTypeRow, &schema, &table)
+	length := m.Length()
+	b.Attach(m)
+	require.Equal(t, []MessageHeader{
+		{Key: HeaderSchema, Value: []byte("a")},
+		{Key: HeaderTable, Value: []byte("b")},
+		{Key: HeaderCommitTs, Value: []byte("1")},
+		{Key: HeaderNamespace, Value: []byte(model.DefaultNamespace)},
+		{Key: HeaderChangefeedID, Value: []byte("test")},
+		{Key: HeaderEventType, Value: []byte(EventTypeRow)},
+		{Key: "env", Value: []byte("prod")},
+		{Key: "region", Value: []byte("us")},
+	}, m.Headers)
+	require.Greater(t, m.Length(), length)
+
+	// the resolved message has no schema and table.
+	m = NewResolvedMsg(config.ProtocolCanalJSON, nil, []byte("value"), 2)
+	b.Attach(m)
+	require.Equal(t, map[string]string{
+		HeaderCommitTs:     "2",
+		HeaderNamespace:    model.DefaultNamespace,
+		HeaderChangefeedID: "test",
+		HeaderEventType:    EventTypeResolved,
+		"env":              "prod",
+		"region":           "us",
+	}, HeadersToMap(m.Headers))
+}
+
+func TestAttachOrigin(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	var nilBuilder *HeadersBuilder
+	m := NewMsg(config.ProtocolCanalJSON, nil, []byte("value"), 1,
+		model.MessageTypeRow, nil, nil)
+	nilBuilder.AttachOrigin(m, 2)
+	require.Empty(t, m.Headers)
+
+	// the origin is only attached with the metadata headers.
+	b := NewHeadersBuilder(changefeedID, 1, &config.MessageHeadersConfig{
+		StaticHeaders: map[string]string{"env": "prod"},
+	})
+	b.AttachOrigin(m, 2)
+	require.Empty(t, m.Headers)
+
+	b = NewHeadersBuilder(changefeedID, 1, &config.MessageHeadersConfig{
+		EnableMetadata: util.AddressOf(true),
+	})
+	b.AttachOrigin(m, 2)
+	require.Equal(t, []MessageHeader{{Key: HeaderOriginID, Value: []byte("2")}}, m.Headers)
+
+	// the rows written in the upstream directly come from the upstream.
+	m.Headers = nil
+	b.AttachOrigin(m, 0)
+	require.Equal(t, []MessageHeader{{Key: HeaderOriginID, Value: []byte("1")}}, m.Headers)
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// the layout of the DATETIME and TIMESTAMP values of the row changed events.
+const temporalLayout = "2006-01-02 15:04:05.999999"
+
+// TemporalEncoder encodes the DATETIME and TIMESTAMP values by the temporal
+// encoding of the sink.
+type TemporalEncoder struct {
+	mode string
+	// serverTZ is the timezone of the TIMESTAMP values.
+	serverTZ *time.Location
+	// sourceTZ is the timezone of the DATETIME values.
+	sourceTZ *time.Location
+	// outputTZ is the timezone of the "naive-local" and "rfc3339" values.
+	outputTZ *time.Location
+}
+
+// NewTemporalEncoder creates a TemporalEncoder, the TIMESTAMP values are in
+// the serverTZ.
+func NewTemporalEncoder(
+	c *config.TemporalEncodingConfig, serverTZ *time.Location,
+) (*TemporalEncoder, error) {
+	e := &TemporalEncoder{
+		mode:     util.GetOrZero(c.Mode),
+		serverTZ: serverTZ,
+		sourceTZ: serverTZ,
+		outputTZ: serverTZ,
+	}
+	if e.mode == "" {
+		e.mode = config.TemporalEncodingNaiveLocal
+	}
+	if c.SourceTimeZone != nil {
+		tz, err := util.GetTimezone(*c.SourceTimeZone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		e.sourceTZ = tz
+	}
+	if c.TimeZone != nil {
+		tz, err := util.GetTimezone(*c.TimeZone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		e.outputTZ = tz
+	}
+	return e, nil
+}
+
+// EpochMillis returns whether the values are encoded as the milliseconds.
+func (e *TemporalEncoder) EpochMillis() bool {
+	return e != nil && e.mode == config.TemporalEncodingEpochMillis
+}
+
+// Encode returns the encoded value of the column, which is an int64 for the
+// "epoch-millis" mode or a string for the others. The values of the other
+// columns are returned as they're, and so are all values if e is nil.
+// The zero and invalid dates are encoded as 0 for the "epoch-millis" mode
+// and kept as they're for the others, since they're not points in time.
+func (e *TemporalEncoder) Encode(col *model.Column) interface{} {
+	if e == nil {
+		return col.Value
+	}
+	var tz *time.Location
+	switch col.Type {
+	case mysql.TypeTimestamp:
+		tz = e.serverTZ
+	case mysql.TypeDatetime:
+		tz = e.sourceTZ
+	default:
+		return col.Value
+	}
+	value, ok := col.Value.(string)
+	if !ok {
+		return col.Value
+	}
+
+	t, err := time.ParseInLocation(temporalLayout, value, tz)
+	if err != nil {
+		if e.mode == config.TemporalEncodingEpochMillis {
+			return int64(0)
+		}
+		return value
+	}
+	switch e.mode {
+	case config.TemporalEncodingEpochMillis:
+		return t.UnixMilli()
+	case config.TemporalEncodingRFC3339:
+		return t.In(e.outputTZ).Format(withFraction("2006-01-02T15:04:05", value) + "Z07:00")
+	default:
+		return t.In(e.outputTZ).Format(withFraction("2006-01-02 15:04:05", value))
+	}
+}
+
+// withFraction appends the fraction of the seconds to the layout, so the
+// values keep the fractional digits of the origin ones.
+func withFraction(layout string, origin string) string {
+	i := strings.IndexByte(origin, '.')
+	if i < 0 {
+		return layout
+	}
+	return layout + "." + strings.Repeat("0", len(origin)-i-1)
+}
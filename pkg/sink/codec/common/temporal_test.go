@@ -0,0 +1,83 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemporalEncoder(t *testing.T) {
+	t.Parallel()
+
+	serverTZ, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	timestamp := &model.Column{Type: mysql.TypeTimestamp, Value: "2023-01-02 08:04:05.120"}
+	datetime := &model.Column{Type: mysql.TypeDatetime, Value: "2023-01-02 00:04:05"}
+	zero := &model.Column{Type: mysql.TypeDatetime, Value: "0000-00-00 00:00:00"}
+	date := &model.Column{Type: mysql.TypeDate, Value: "2023-01-02"}
+
+	// the nil encoder keeps the values.
+	var e *TemporalEncoder
+	require.False(t, e.EpochMillis())
+	require.Equal(t, timestamp.Value, e.Encode(timestamp))
+
+	e, err = NewTemporalEncoder(&config.TemporalEncodingConfig{
+		Mode:           util.AddressOf(config.TemporalEncodingEpochMillis),
+		SourceTimeZone: util.AddressOf("UTC"),
+	}, serverTZ)
+	require.NoError(t, err)
+	require.True(t, e.EpochMillis())
+	require.Equal(t, time.Date(2023, 1, 2, 0, 4, 5, 120e6, time.UTC).UnixMilli(), e.Encode(timestamp))
+	require.Equal(t, time.Date(2023, 1, 2, 0, 4, 5, 0, time.UTC).UnixMilli(), e.Encode(datetime))
+	require.Equal(t, int64(0), e.Encode(zero))
+	require.Equal(t, date.Value, e.Encode(date))
+	require.Nil(t, e.Encode(&model.Column{Type: mysql.TypeTimestamp}))
+
+	e, err = NewTemporalEncoder(&config.TemporalEncodingConfig{
+		Mode:           util.AddressOf(config.TemporalEncodingRFC3339),
+		SourceTimeZone: util.AddressOf("UTC"),
+		TimeZone:       util.AddressOf("UTC"),
+	}, serverTZ)
+	require.NoError(t, err)
+	require.Equal(t, "2023-01-02T00:04:05.120Z", e.Encode(timestamp))
+	require.Equal(t, "2023-01-02T00:04:05Z", e.Encode(datetime))
+	require.Equal(t, zero.Value, e.Encode(zero))
+
+	// the values are in the timezone of the server by default.
+	e, err = NewTemporalEncoder(&config.TemporalEncodingConfig{
+		Mode: util.AddressOf(config.TemporalEncodingRFC3339),
+	}, serverTZ)
+	require.NoError(t, err)
+	require.Equal(t, "2023-01-02T08:04:05.120+08:00", e.Encode(timestamp))
+	require.Equal(t, "2023-01-02T00:04:05+08:00", e.Encode(datetime))
+
+	e, err = NewTemporalEncoder(&config.TemporalEncodingConfig{
+		SourceTimeZone: util.AddressOf("UTC"),
+	}, serverTZ)
+	require.NoError(t, err)
+	require.Equal(t, "2023-01-02 08:04:05.120", e.Encode(timestamp))
+	require.Equal(t, "2023-01-02 08:04:05", e.Encode(datetime))
+
+	_, err = NewTemporalEncoder(&config.TemporalEncodingConfig{
+		TimeZone: util.AddressOf("Mars/Olympus"),
+	}, serverTZ)
+	require.Error(t, err)
+}
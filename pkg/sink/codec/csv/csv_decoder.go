@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
 	"github.com/pingcap/tidb/br/pkg/lightning/worker"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
@@ -45,11 +46,16 @@ func NewBatchDecoder(ctx context.Context,
 	value []byte,
 ) (codec.RowEventDecoder, error) {
 	var backslashEscape bool
+	escapedBy := codecConfig.EscapeChar
 
 	// if quote is not set in config, we should unespace backslash
-	// when parsing csv columns.
+	// when parsing csv columns, the columns are escaped by the backslash
+	// if the escape character isn't set.
 	if len(codecConfig.Quote) == 0 {
 		backslashEscape = true
+		if len(escapedBy) == 0 {
+			escapedBy = string(config.Backslash)
+		}
 	}
 	cfg := &lconfig.CSVConfig{
 		Separator:       codecConfig.Delimiter,
@@ -57,11 +63,12 @@ func NewBatchDecoder(ctx context.Context,
 		Terminator:      codecConfig.Terminator,
 		Null:            []string{codecConfig.NullString},
 		BackslashEscape: backslashEscape,
+		EscapedBy:       escapedBy,
 	}
 	csvParser, err := mydump.NewCSVParser(ctx, cfg,
 		mydump.NewStringReader(string(value)),
 		int64(lconfig.ReadBlockSize),
-		worker.NewPool(ctx, defaultIOConcurrency, "io"), false, nil)
+		worker.NewPool(ctx, defaultIOConcurrency, "io"), codecConfig.OutputHeader, nil)
 	if err != nil {
 		return nil, err
 	}
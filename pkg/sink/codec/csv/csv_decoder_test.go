@@ -19,6 +19,7 @@ import (
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
@@ -85,3 +86,146 @@ func TestCSVBatchDecoder(t *testing.T) {
 	_, hasNext, _ := decoder.HasNext()
 	require.False(t, hasNext)
 }
+
+func TestCSVBatchDecoderWithHeader(t *testing.T) {
+	csvData := `"I","employee","hr",101,"Smith","Bob"
+"U","employee","hr",101,"Smith","Alice"
+`
+	ctx := context.Background()
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{
+			Schema: "hr",
+			Table:  "employee",
+		},
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("employee"),
+			Columns: []*timodel.ColumnInfo{
+				{
+					Name:      timodel.NewCIStr("Id"),
+					FieldType: *types.NewFieldType(mysql.TypeInt24),
+				},
+				{
+					Name:      timodel.NewCIStr("LastName"),
+					FieldType: *types.NewFieldType(mysql.TypeVarchar),
+				},
+				{
+					Name:      timodel.NewCIStr("FirstName"),
+					FieldType: *types.NewFieldType(mysql.TypeVarchar),
+				},
+			},
+		},
+	}
+	codecConfig := &common.Config{
+		Delimiter:    ",",
+		Quote:        "\"",
+		Terminator:   "\n",
+		NullString:   "\\N",
+		OutputHeader: true,
+	}
+	header := EncodeHeader(codecConfig, tableInfo)
+	require.Equal(t,
+		`"_tidb_op","_tidb_table","_tidb_schema","Id","LastName","FirstName"`+"\n",
+		string(header))
+
+	decoder, err := NewBatchDecoder(ctx, codecConfig, tableInfo, append(header, csvData...))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		event, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.Equal(t, []byte("Smith"), event.Columns[1].Value)
+	}
+	_, hasNext, _ := decoder.HasNext()
+	require.False(t, hasNext)
+}
+
+func TestCSVBatchDecoderWithEscapeChar(t *testing.T) {
+	csvData := `"I","employee","hr",101,"a\"b\\c"` + "\n"
+	ctx := context.Background()
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{
+			Schema: "hr",
+			Table:  "employee",
+		},
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("employee"),
+			Columns: []*timodel.ColumnInfo{
+				{
+					Name:      timodel.NewCIStr("Id"),
+					FieldType: *types.NewFieldType(mysql.TypeInt24),
+				},
+				{
+					Name:      timodel.NewCIStr("Name"),
+					FieldType: *types.NewFieldType(mysql.TypeVarchar),
+				},
+			},
+		},
+	}
+	decoder, err := NewBatchDecoder(ctx, &common.Config{
+		Delimiter:  ",",
+		Quote:      "\"",
+		Terminator: "\n",
+		NullString: "\\N",
+		EscapeChar: "\\",
+	}, tableInfo, []byte(csvData))
+	require.NoError(t, err)
+
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`a"b\c`), event.Columns[1].Value)
+}
+
+func TestCSVBatchDecoderWithTabDelimiter(t *testing.T) {
+	// the tab separated values without quotes are escaped like the output
+	// of SELECT ... INTO OUTFILE, so they can be loaded by LOAD DATA.
+	codecConfig := &common.Config{
+		Delimiter:  "\t",
+		Terminator: "\n",
+		NullString: "\\N",
+	}
+	row := &model.RowChangedEvent{
+		CommitTs: 433305438660591626,
+		Table:    &model.TableName{Schema: "hr", Table: "employee"},
+		Columns: []*model.Column{
+			{Name: "Id", Type: mysql.TypeLong, Value: int64(101)},
+			{Name: "LastName", Type: mysql.TypeVarchar, Value: []byte("Smith\tJr\n\\N")},
+			{Name: "FirstName", Type: mysql.TypeVarchar, Value: nil},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 3, Ft: types.NewFieldType(mysql.TypeVarchar)},
+		},
+	}
+	csvMsg, err := rowChangedEvent2CSVMsg(codecConfig, row)
+	require.NoError(t, err)
+	data := csvMsg.encode()
+	require.Equal(t, "I\temployee\thr\t101\tSmith\\tJr\\n\\\\N\t\\N\n", string(data))
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "hr", Table: "employee"},
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("employee"),
+			Columns: []*timodel.ColumnInfo{
+				{Name: timodel.NewCIStr("Id"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+				{Name: timodel.NewCIStr("LastName"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+				{Name: timodel.NewCIStr("FirstName"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+			},
+		},
+	}
+	decoder, err := NewBatchDecoder(context.Background(), codecConfig, tableInfo, data)
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, []byte("Smith\tJr\n\\N"), event.Columns[1].Value)
+	require.Nil(t, event.Columns[2].Value)
+}
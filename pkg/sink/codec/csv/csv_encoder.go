@@ -15,6 +15,7 @@ package csv
 
 import (
 	"bytes"
+	"strings"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -22,6 +23,49 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 )
 
+// The names of the meta columns in the header row, the order is the same as
+// the one in the csv rows, see csvMessage.encode for details.
+const (
+	headerOperationType = "_tidb_op"
+	headerTableName     = "_tidb_table"
+	headerSchemaName    = "_tidb_schema"
+	headerCommitTs      = "_tidb_commit_ts"
+	headerPhysicalTime  = "_tidb_commit_physical_time"
+	headerIngestionTime = "_tidb_ingestion_time"
+)
+
+// EncodeHeader returns the header row which contains the names of all the csv
+// columns of the given table, it's written at the beginning of each data file.
+func EncodeHeader(config *common.Config, tableInfo *model.TableInfo) []byte {
+	msg := newCSVMessage(config)
+	strBuilder := new(strings.Builder)
+	msg.formatValue(headerOperationType, strBuilder)
+	msg.formatValue(headerTableName, strBuilder)
+	msg.formatValue(headerSchemaName, strBuilder)
+	if config.IncludeCommitTs {
+		msg.formatValue(headerCommitTs, strBuilder)
+	}
+	if config.OutputPhysicalTime {
+		msg.formatValue(headerPhysicalTime, strBuilder)
+		msg.formatValue(headerIngestionTime, strBuilder)
+	}
+	if selected := config.CSVColumns(tableInfo.TableName.Schema, tableInfo.TableName.Table); selected != nil {
+		for _, name := range selected {
+			msg.formatValue(name, strBuilder)
+		}
+	} else {
+		for _, col := range tableInfo.Columns {
+			msg.formatValue(col.Name.O, strBuilder)
+		}
+		// the computed columns are appended after the columns of the table.
+		for _, name := range config.ComputedColumns(tableInfo.TableName.Schema, tableInfo.TableName.Table) {
+			msg.formatValue(name, strBuilder)
+		}
+	}
+	strBuilder.WriteString(config.Terminator)
+	return []byte(strBuilder.String())
+}
+
 // BatchEncoder encodes the events into the byte of a batch into.
 type BatchEncoder struct {
 	valueBuf  *bytes.Buffer
@@ -14,12 +14,16 @@
 package csv
 
 import (
+	"net/url"
 	"testing"
 
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
 )
@@ -130,3 +134,52 @@ func TestCSVAppendRowChangedEventWithCallback(t *testing.T) {
 	msgs[0].Callback()
 	require.Equal(t, 1, count, "expected all callbacks to be called")
 }
+
+func TestCSVEncodeComputedColumns(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.ComputedColumns = []*config.ComputedColumn{
+		{Matcher: []string{"test.*"}, Name: "region", Value: "us-west-1"},
+	}
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=csv")
+	require.NoError(t, err)
+	codecConfig := common.NewConfig(config.ProtocolCsv)
+	require.NoError(t, codecConfig.Apply(sinkURI, replicaConfig))
+	codecConfig.Delimiter = ","
+	codecConfig.Quote = "\""
+	codecConfig.Terminator = "\n"
+	codecConfig.NullString = "\\N"
+
+	tableInfo := model.WrapTableInfo(1, "test", 1, &timodel.TableInfo{
+		Name: timodel.NewCIStr("t"),
+		Columns: []*timodel.ColumnInfo{{
+			ID:        1,
+			Name:      timodel.NewCIStr("id"),
+			FieldType: *types.NewFieldType(mysql.TypeLong),
+			State:     timodel.StatePublic,
+		}},
+	})
+	_, _, colInfos := tableInfo.GetRowColInfos()
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		Columns:  []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: int64(1)}},
+		ColInfos: colInfos,
+	}
+	appender, err := filter.NewColumnAppender(replicaConfig, "default", model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	require.NoError(t, appender.AppendRowChangedEvent(row))
+
+	encoder := newBatchEncoder(codecConfig)
+	err = encoder.AppendTxnEvent(&model.SingleTableTxn{
+		Table: row.Table,
+		Rows:  []*model.RowChangedEvent{row},
+	}, nil)
+	require.NoError(t, err)
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	require.Equal(t, "\"I\",\"t\",\"test\",1,\"us-west-1\"\n", string(msgs[0].Value))
+	require.Equal(t, "\"_tidb_op\",\"_tidb_table\",\"_tidb_schema\",\"id\",\"region\"\n",
+		string(EncodeHeader(codecConfig, tableInfo)))
+}
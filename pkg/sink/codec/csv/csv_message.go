@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/charset"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 // a csv row should at least contain operation-type, table-name, schema-name and one table column
@@ -81,7 +83,11 @@ type csvMessage struct {
 	tableName  string
 	schemaName string
 	commitTs   uint64
-	columns    []any
+	// commitPhysicalTime and ingestionTime are the unix milliseconds of the
+	// commit-ts and the time the row is encoded.
+	commitPhysicalTime int64
+	ingestionTime      int64
+	columns            []any
 	// newRecord indicates whether we encounter a new record.
 	newRecord bool
 }
@@ -98,7 +104,9 @@ func newCSVMessage(config *common.Config) *csvMessage {
 // Col2: Table name, the name of the source table.
 // Col3: Schema name, the name of the source schema.
 // Col4: Commit TS, the commit-ts of the source txn (optional).
-// Col5-n: one or more columns that represent the data to be changed.
+// Col5: Commit physical time, the physical part of commit-ts in milliseconds (optional).
+// Col6: Ingestion time, the time the row is encoded in milliseconds (optional).
+// Col7-n: one or more columns that represent the data to be changed.
 func (c *csvMessage) encode() []byte {
 	strBuilder := new(strings.Builder)
 	c.formatValue(c.opType.String(), strBuilder)
@@ -107,6 +115,10 @@ func (c *csvMessage) encode() []byte {
 	if c.config.IncludeCommitTs {
 		c.formatValue(c.commitTs, strBuilder)
 	}
+	if c.config.OutputPhysicalTime {
+		c.formatValue(c.commitPhysicalTime, strBuilder)
+		c.formatValue(c.ingestionTime, strBuilder)
+	}
 	for _, col := range c.columns {
 		c.formatValue(col, strBuilder)
 	}
@@ -141,6 +153,25 @@ func (c *csvMessage) decode(datums []types.Datum) error {
 	} else {
 		c.commitTs = 0
 	}
+	if c.config.OutputPhysicalTime {
+		if len(datums) < dataColIdx+2 {
+			return cerror.WrapError(cerror.ErrCSVDecodeFailed,
+				errors.New("the csv row should contain the commit physical time and the ingestion time"))
+		}
+		for _, target := range []*int64{&c.commitPhysicalTime, &c.ingestionTime} {
+			value, err := strconv.ParseInt(datums[dataColIdx].GetString(), 10, 64)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrCSVDecodeFailed,
+					fmt.Errorf("the %dth column(%s) of csv row should be a valid unix milliseconds",
+						dataColIdx+1, datums[dataColIdx].GetString()))
+			}
+			*target = value
+			dataColIdx++
+		}
+	} else {
+		c.commitPhysicalTime = 0
+		c.ingestionTime = 0
+	}
 	c.columns = c.columns[:0]
 
 	for i := dataColIdx; i < len(datums); i++ {
@@ -158,42 +189,49 @@ func (c *csvMessage) decode(datums []types.Datum) error {
 // if double-quotes are used to enclose fields, then a double-quote
 // appearing inside a field must be escaped by preceding it with
 // another double quote.
+// If the escape character is set, the quote and the escape character inside
+// a field are escaped by preceding them with the escape character instead.
 func (c *csvMessage) formatWithQuotes(value string, strBuilder *strings.Builder) {
 	quote := c.config.Quote
 
 	strBuilder.WriteString(quote)
-	// replace any quote in csv column with two quotes.
-	strBuilder.WriteString(strings.ReplaceAll(value, quote, quote+quote))
+	if escape := c.config.EscapeChar; len(escape) != 0 {
+		replacer := strings.NewReplacer(escape, escape+escape, quote, escape+quote)
+		strBuilder.WriteString(replacer.Replace(value))
+	} else {
+		// replace any quote in csv column with two quotes.
+		strBuilder.WriteString(strings.ReplaceAll(value, quote, quote+quote))
+	}
 	strBuilder.WriteString(quote)
 }
 
-// formatWithEscapes escapes the csv column if necessary.
+// formatWithEscapes escapes the csv column if necessary. The line breaks and
+// the tabs are escaped as "\n", "\r" and "\t" like MySQL, so the column with
+// the tab delimiter can be loaded by LOAD DATA without post-processing.
 func (c *csvMessage) formatWithEscapes(value string, strBuilder *strings.Builder) {
 	lastPos := 0
 	delimiter := c.config.Delimiter
+	escape := byte(config.Backslash)
+	if len(c.config.EscapeChar) != 0 {
+		escape = c.config.EscapeChar[0]
+	}
 
 	for i := 0; i < len(value); i++ {
 		ch := value[i]
 		isDelimiterStart := strings.HasPrefix(value[i:], delimiter)
-		// if '\r', '\n', '\' or the delimiter (may have multiple characters) are contained in
-		// csv column, we should escape these characters.
-		if ch == config.CR || ch == config.LF || ch == config.Backslash || isDelimiterStart {
+		// if '\r', '\n', the escape character or the delimiter (may have multiple characters)
+		// are contained in csv column, we should escape these characters.
+		if ch == config.CR || ch == config.LF || ch == escape || isDelimiterStart {
 			// write out characters up until this position.
 			strBuilder.WriteString(value[lastPos:i])
-			switch ch {
-			case config.LF:
-				ch = 'n'
-			case config.CR:
-				ch = 'r'
-			}
-			strBuilder.WriteRune(config.Backslash)
-			strBuilder.WriteRune(rune(ch))
+			strBuilder.WriteByte(escape)
+			strBuilder.WriteRune(rune(escapedChar(ch)))
 
 			// escape each characters in delimiter.
 			if isDelimiterStart {
 				for k := 1; k < len(c.config.Delimiter); k++ {
-					strBuilder.WriteRune(config.Backslash)
-					strBuilder.WriteRune(rune(delimiter[k]))
+					strBuilder.WriteByte(escape)
+					strBuilder.WriteRune(rune(escapedChar(delimiter[k])))
 				}
 				lastPos = i + len(delimiter)
 			} else {
@@ -204,6 +242,20 @@ func (c *csvMessage) formatWithEscapes(value string, strBuilder *strings.Builder
 	strBuilder.WriteString(value[lastPos:])
 }
 
+// escapedChar returns the character written after the escape character.
+func escapedChar(ch byte) byte {
+	switch ch {
+	case config.LF:
+		return 'n'
+	case config.CR:
+		return 'r'
+	case '\t':
+		return 't'
+	default:
+		return ch
+	}
+}
+
 // formatValue formats the csv column and appends it to a string builder.
 func (c *csvMessage) formatValue(value any, strBuilder *strings.Builder) {
 	defer func() {
@@ -222,16 +274,35 @@ func (c *csvMessage) formatValue(value any, strBuilder *strings.Builder) {
 
 	switch v := value.(type) {
 	case string:
-		// if quote is configured, format the csv column with quotes,
-		// otherwise escape this csv column.
-		if len(c.config.Quote) != 0 {
+		// if quote is configured, format the csv column with quotes according
+		// to the quoting policy, otherwise escape this csv column.
+		if len(c.config.Quote) == 0 {
+			c.formatWithEscapes(v, strBuilder)
+		} else if c.config.QuotingPolicy != config.QuotingPolicyMinimal || c.needQuotes(v) {
 			c.formatWithQuotes(v, strBuilder)
 		} else {
-			c.formatWithEscapes(v, strBuilder)
+			strBuilder.WriteString(v)
 		}
 	default:
-		strBuilder.WriteString(fmt.Sprintf("%v", v))
+		if len(c.config.Quote) != 0 && c.config.QuotingPolicy == config.QuotingPolicyAlways {
+			c.formatWithQuotes(fmt.Sprintf("%v", v), strBuilder)
+		} else {
+			strBuilder.WriteString(fmt.Sprintf("%v", v))
+		}
+	}
+}
+
+// needQuotes returns whether the csv column must be quoted with the minimal
+// quoting policy, it's true if the column contains any special characters or
+// it can be mistaken for a NULL value.
+func (c *csvMessage) needQuotes(value string) bool {
+	if value == c.config.NullString {
+		return true
+	}
+	if strings.ContainsAny(value, "\r\n"+c.config.Quote+c.config.EscapeChar) {
+		return true
 	}
+	return strings.Contains(value, c.config.Delimiter)
 }
 
 func fromCsvValToColValue(csvConfig *common.Config, csvVal any, ft types.FieldType) (any, error) {
@@ -324,6 +395,8 @@ func fromColValToCsvVal(csvConfig *common.Config, col *model.Column, ft *types.F
 			return nil, cerror.WrapError(cerror.ErrCSVEncodeFailed, err)
 		}
 		return setVar.Name, nil
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		return csvConfig.TemporalEncoder.Encode(col), nil
 	default:
 		return col.Value, nil
 	}
@@ -340,23 +413,27 @@ func rowChangedEvent2CSVMsg(csvConfig *common.Config, e *model.RowChangedEvent)
 		commitTs:   e.CommitTs,
 		newRecord:  true,
 	}
+	if csvConfig.OutputPhysicalTime {
+		csvMsg.commitPhysicalTime = oracle.ExtractPhysical(e.CommitTs)
+		csvMsg.ingestionTime = time.Now().UnixMilli()
+	}
+	// for insert and update operation, we only record the after columns.
+	cols := e.Columns
 	if e.IsDelete() {
 		csvMsg.opType = operationDelete
-		csvMsg.columns, err = rowChangeColumns2CSVColumns(csvConfig, e.PreColumns, e.ColInfos)
-		if err != nil {
-			return nil, err
-		}
+		cols = e.PreColumns
+	} else if e.PreColumns == nil {
+		csvMsg.opType = operationInsert
 	} else {
-		if e.PreColumns == nil {
-			csvMsg.opType = operationInsert
-		} else {
-			csvMsg.opType = operationUpdate
-		}
-		// for insert and update operation, we only record the after columns.
-		csvMsg.columns, err = rowChangeColumns2CSVColumns(csvConfig, e.Columns, e.ColInfos)
-		if err != nil {
-			return nil, err
-		}
+		csvMsg.opType = operationUpdate
+	}
+	if selected := csvConfig.CSVColumns(e.Table.Schema, e.Table.Table); selected != nil {
+		csvMsg.columns, err = selectCSVColumns(csvConfig, selected, cols, e.ColInfos)
+	} else {
+		csvMsg.columns, err = rowChangeColumns2CSVColumns(csvConfig, cols, e.ColInfos)
+	}
+	if err != nil {
+		return nil, err
 	}
 	return csvMsg, nil
 }
@@ -407,6 +484,33 @@ func rowChangeColumns2CSVColumns(csvConfig *common.Config, cols []*model.Column,
 	return csvColumns, nil
 }
 
+// selectCSVColumns converts the selected columns in the given order, the
+// selected columns which don't exist in the row are null.
+func selectCSVColumns(
+	csvConfig *common.Config, selected []string, cols []*model.Column, colInfos []rowcodec.ColInfo,
+) ([]any, error) {
+	offsets := make(map[string]int, len(cols))
+	for i, column := range cols {
+		if column != nil {
+			offsets[strings.ToLower(column.Name)] = i
+		}
+	}
+	csvColumns := make([]any, 0, len(selected))
+	for _, name := range selected {
+		i, ok := offsets[strings.ToLower(name)]
+		if !ok {
+			csvColumns = append(csvColumns, nil)
+			continue
+		}
+		converted, err := fromColValToCsvVal(csvConfig, cols[i], colInfos[i].Ft)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		csvColumns = append(csvColumns, converted)
+	}
+	return csvColumns, nil
+}
+
 func csvColumns2RowChangeColumns(csvConfig *common.Config, csvCols []any, ticols []*timodel.ColumnInfo) ([]*model.Column, error) {
 	cols := make([]*model.Column, 0, len(csvCols))
 	for idx, csvCol := range csvCols {
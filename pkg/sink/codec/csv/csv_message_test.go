@@ -15,18 +15,22 @@ package csv
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/parser/charset"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 type csvTestColumnTuple struct {
@@ -680,6 +684,50 @@ func TestFormatWithQuotes(t *testing.T) {
 		csvMessage.formatWithQuotes(tc.input, strBuilder)
 		require.Equal(t, tc.expected, strBuilder.String(), tc.name)
 	}
+
+	// the quote and escape character are escaped by the escape character.
+	config.EscapeChar = "\\"
+	csvMessage := newCSVMessage(config)
+	strBuilder := new(strings.Builder)
+	csvMessage.formatWithQuotes(`a"b\c`, strBuilder)
+	require.Equal(t, `"a\"b\\c"`, strBuilder.String())
+}
+
+func TestFormatValueWithQuotingPolicy(t *testing.T) {
+	testCases := []struct {
+		policy   string
+		expected string
+	}{
+		{
+			policy:   "",
+			expected: `"abc",123,"a,b",\N,"\N",1.5`,
+		},
+		{
+			policy:   config.QuotingPolicyNonNumeric,
+			expected: `"abc",123,"a,b",\N,"\N",1.5`,
+		},
+		{
+			policy:   config.QuotingPolicyAlways,
+			expected: `"abc","123","a,b",\N,"\N","1.5"`,
+		},
+		{
+			policy:   config.QuotingPolicyMinimal,
+			expected: `abc,123,"a,b",\N,"\N",1.5`,
+		},
+	}
+	for _, tc := range testCases {
+		csvMessage := newCSVMessage(&common.Config{
+			Delimiter:     ",",
+			Quote:         "\"",
+			NullString:    "\\N",
+			QuotingPolicy: tc.policy,
+		})
+		strBuilder := new(strings.Builder)
+		for _, value := range []any{"abc", int64(123), "a,b", nil, "\\N", 1.5} {
+			csvMessage.formatValue(value, strBuilder)
+		}
+		require.Equal(t, tc.expected, strBuilder.String(), tc.policy)
+	}
 }
 
 func TestFormatWithEscape(t *testing.T) {
@@ -725,6 +773,18 @@ func TestFormatWithEscape(t *testing.T) {
 			input:    `abc\def?ghi\r\n`,
 			expected: `abc\\def\?ghi\\r\\n`,
 		},
+		{
+			name:     "string contains the custom escape character",
+			config:   &common.Config{Delimiter: ",", EscapeChar: "^"},
+			input:    "a^b,c\nd\\",
+			expected: `a^^b^,c^nd\`,
+		},
+		{
+			name:     "string contains the tab delimiter",
+			config:   &common.Config{Delimiter: "\t"},
+			input:    "abc\tdef\\N",
+			expected: `abc\tdef\\N`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1058,3 +1118,109 @@ func TestCSVMessageDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestCSVMessageWithPhysicalTime(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := &common.Config{
+		Delimiter:          ",",
+		Quote:              "\"",
+		Terminator:         "\n",
+		NullString:         "\\N",
+		IncludeCommitTs:    true,
+		OutputPhysicalTime: true,
+	}
+	row := &model.RowChangedEvent{
+		CommitTs: 433305438660591626,
+		Table:    &model.TableName{Schema: "hr", Table: "employee"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(101)},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+		},
+	}
+	before := time.Now().UnixMilli()
+	csvMsg, err := rowChangedEvent2CSVMsg(codecConfig, row)
+	require.NoError(t, err)
+	physicalTime := oracle.ExtractPhysical(row.CommitTs)
+	require.Equal(t, physicalTime, csvMsg.commitPhysicalTime)
+	require.GreaterOrEqual(t, csvMsg.ingestionTime, before)
+	require.Equal(t, fmt.Sprintf("\"I\",\"employee\",\"hr\",433305438660591626,%d,%d,101\n",
+		physicalTime, csvMsg.ingestionTime), string(csvMsg.encode()))
+
+	decoded := newCSVMessage(codecConfig)
+	err = decoded.decode([]types.Datum{
+		types.NewStringDatum("I"),
+		types.NewStringDatum("employee"),
+		types.NewStringDatum("hr"),
+		types.NewStringDatum("433305438660591626"),
+		types.NewStringDatum(strconv.FormatInt(physicalTime, 10)),
+		types.NewStringDatum("1690000000000"),
+		types.NewStringDatum("101"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, physicalTime, decoded.commitPhysicalTime)
+	require.Equal(t, int64(1690000000000), decoded.ingestionTime)
+	require.Equal(t, []any{"101"}, decoded.columns)
+
+	err = decoded.decode([]types.Datum{
+		types.NewStringDatum("I"),
+		types.NewStringDatum("employee"),
+		types.NewStringDatum("hr"),
+		types.NewStringDatum("433305438660591626"),
+		types.NewStringDatum("abc"),
+		types.NewStringDatum("1690000000000"),
+		types.NewStringDatum("101"),
+	})
+	require.ErrorContains(t, err, "the 5th column(abc) of csv row should be a valid unix milliseconds")
+}
+
+func TestCSVMessageWithColumnRules(t *testing.T) {
+	t.Parallel()
+
+	tableMatcher, err := tfilter.Parse([]string{"hr.employee"})
+	require.NoError(t, err)
+	codecConfig := &common.Config{
+		Delimiter:  ",",
+		Quote:      "\"",
+		Terminator: "\n",
+		NullString: "\\N",
+		CSVColumnRules: []*common.CSVColumnRule{{
+			TableMatcher: tableMatcher,
+			Columns:      []string{"Name", "id", "dept"},
+		}},
+	}
+	row := &model.RowChangedEvent{
+		CommitTs: 433305438660591626,
+		Table:    &model.TableName{Schema: "hr", Table: "employee"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(101)},
+			{Name: "age", Type: mysql.TypeLong, Value: int64(30)},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("Smith")},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 3, Ft: types.NewFieldType(mysql.TypeVarchar)},
+		},
+	}
+	// the columns are ordered by the rule, the missing column is null and
+	// the other columns are dropped.
+	csvMsg, err := rowChangedEvent2CSVMsg(codecConfig, row)
+	require.NoError(t, err)
+	require.Equal(t, "\"I\",\"employee\",\"hr\",\"Smith\",101,\\N\n", string(csvMsg.encode()))
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "hr", Table: "employee"},
+		TableInfo: &timodel.TableInfo{},
+	}
+	require.Equal(t, "\"_tidb_op\",\"_tidb_table\",\"_tidb_schema\",\"Name\",\"id\",\"dept\"\n",
+		string(EncodeHeader(codecConfig, tableInfo)))
+
+	// the tables which aren't matched output all the columns.
+	row.Table = &model.TableName{Schema: "hr", Table: "salary"}
+	csvMsg, err = rowChangedEvent2CSVMsg(codecConfig, row)
+	require.NoError(t, err)
+	require.Equal(t, "\"I\",\"salary\",\"hr\",101,30,\"Smith\"\n", string(csvMsg.encode()))
+}
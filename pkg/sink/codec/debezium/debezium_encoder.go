@@ -0,0 +1,199 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+// BatchEncoder encodes the events into the debezium format, each row changed
+// event is encoded as a message whose key is the handle key columns, and whose
+// value is the envelope which contains before, after, source, op and ts_ms.
+// The envelopes are encoded without schema, the same as the debezium connectors
+// with `value.converter.schemas.enable=false`.
+type BatchEncoder struct {
+	messages []*common.Message
+
+	// name is the logical name of the source, it's the changefeed ID.
+	name   string
+	tz     *time.Location
+	config *common.Config
+}
+
+// EncodeCheckpointEvent implements the RowEventEncoder interface
+func (e *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
+	// debezium has no such event, the checkpoint event is ignored.
+	return nil, nil
+}
+
+// AppendRowChangedEvent implements the RowEventEncoder interface
+func (e *BatchEncoder) AppendRowChangedEvent(
+	_ context.Context,
+	_ string,
+	event *model.RowChangedEvent,
+	callback func(),
+) error {
+	key, err := e.encodeKey(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	value, err := e.encodeValue(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	m := common.NewMsg(config.ProtocolDebezium, key, value, event.CommitTs,
+		model.MessageTypeRow, &event.Table.Schema, &event.Table.Table)
+	m.Callback = callback
+	m.IncRowsCount()
+
+	if m.Length() > e.config.MaxMessageBytes {
+		log.Error("Single message is too large for debezium",
+			zap.Int("maxMessageBytes", e.config.MaxMessageBytes),
+			zap.Int("length", m.Length()),
+			zap.Any("table", event.Table))
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+	}
+
+	e.messages = append(e.messages, m)
+	return nil
+}
+
+// encodeKey encodes the handle key columns of the row, nil is returned if
+// there is no handle key column.
+func (e *BatchEncoder) encodeKey(event *model.RowChangedEvent) ([]byte, error) {
+	columns := event.Columns
+	if event.IsDelete() {
+		columns = event.PreColumns
+	}
+	key, err := e.newRow(columns, event.ColInfos, true)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || len(key.names) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(key)
+	return data, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+}
+
+func (e *BatchEncoder) encodeValue(event *model.RowChangedEvent) ([]byte, error) {
+	msg := &rowMessage{
+		Source: newSource(e.name, event.Table.Schema, event.Table.Table, event.CommitTs),
+		TsMs:   time.Now().UnixMilli(),
+	}
+
+	var err error
+	switch {
+	case event.IsDelete():
+		msg.Op = opDelete
+		msg.Before, err = e.newRow(event.PreColumns, event.ColInfos,
+			e.config.DeleteOnlyHandleKeyColumns)
+	case event.IsUpdate():
+		msg.Op = opUpdate
+		msg.Before, err = e.newRow(event.PreColumns, event.ColInfos, false)
+		if err == nil {
+			msg.After, err = e.newRow(event.Columns, event.ColInfos, false)
+		}
+	default:
+		msg.Op = opCreate
+		msg.After, err = e.newRow(event.Columns, event.ColInfos, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(msg)
+	return data, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+}
+
+// EncodeDDLEvent implements the RowEventEncoder interface
+func (e *BatchEncoder) EncodeDDLEvent(event *model.DDLEvent) (*common.Message, error) {
+	schema := event.TableInfo.TableName.Schema
+	key, err := json.Marshal(map[string]string{"databaseName": schema})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+	}
+
+	msg := &ddlMessage{
+		Source:       newSource(e.name, schema, event.TableInfo.TableName.Table, event.CommitTs),
+		DatabaseName: schema,
+		DDL:          event.Query,
+		TableChanges: []interface{}{},
+	}
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed, err)
+	}
+	return common.NewDDLMsg(config.ProtocolDebezium, key, value, event), nil
+}
+
+// Build implements the RowEventEncoder interface
+func (e *BatchEncoder) Build() []*common.Message {
+	if len(e.messages) == 0 {
+		return nil
+	}
+
+	result := e.messages
+	e.messages = nil
+	return result
+}
+
+// newBatchEncoder creates a new debezium BatchEncoder.
+func newBatchEncoder(name string, tz *time.Location, config *common.Config) codec.RowEventEncoder {
+	return &BatchEncoder{
+		name:   name,
+		tz:     tz,
+		config: config,
+	}
+}
+
+type batchEncoderBuilder struct {
+	name   string
+	tz     *time.Location
+	config *common.Config
+}
+
+// NewBatchEncoderBuilder creates a debezium batchEncoderBuilder. The TIMESTAMP
+// values are parsed in the timezone of the TiCDC server.
+func NewBatchEncoderBuilder(
+	changefeedID model.ChangeFeedID, c *common.Config,
+) (codec.RowEventEncoderBuilder, error) {
+	tz, err := util.GetTimezone(config.GetGlobalServerConfig().TZ)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &batchEncoderBuilder{
+		name:   changefeedID.ID,
+		tz:     tz,
+		config: c,
+	}, nil
+}
+
+// Build a debezium BatchEncoder
+func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
+	return newBatchEncoder(b.name, b.tz, b.config)
+}
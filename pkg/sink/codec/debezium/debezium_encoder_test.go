@@ -0,0 +1,197 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestEncodeRowChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	tz, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	encoder := newBatchEncoder("test-cf", tz, common.NewConfig(config.ProtocolDebezium))
+
+	enumType := types.NewFieldType(mysql.TypeEnum)
+	enumType.SetElems([]string{"a", "b"})
+	bitType := types.NewFieldType(mysql.TypeBit)
+	bitType.SetFlen(1)
+	datetimeType := types.NewFieldType(mysql.TypeDatetime)
+	datetimeType.SetDecimal(6)
+	colInfos := []rowcodec.ColInfo{
+		{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+		{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+		{ID: 3, Ft: types.NewFieldType(mysql.TypeBlob)},
+		{ID: 4, Ft: enumType},
+		{ID: 5, Ft: bitType},
+		{ID: 6, Ft: types.NewFieldType(mysql.TypeDate)},
+		{ID: 7, Ft: datetimeType},
+		{ID: 8, Ft: types.NewFieldType(mysql.TypeTimestamp)},
+		{ID: 9, Ft: types.NewFieldType(mysql.TypeDuration)},
+		{ID: 10, Ft: types.NewFieldType(mysql.TypeNewDecimal)},
+	}
+	newColumns := func(name string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte(name)},
+			{Name: "data", Type: mysql.TypeBlob, Value: []byte{0x01, 0xff}, Flag: model.BinaryFlag},
+			{Name: "kind", Type: mysql.TypeEnum, Value: uint64(2)},
+			{Name: "flag", Type: mysql.TypeBit, Value: uint64(1)},
+			{Name: "d", Type: mysql.TypeDate, Value: "1970-01-03"},
+			{Name: "dt", Type: mysql.TypeDatetime, Value: "1970-01-01 00:00:01.000002"},
+			{Name: "ts", Type: mysql.TypeTimestamp, Value: "2023-01-01 08:00:00"},
+			{Name: "t", Type: mysql.TypeDuration, Value: "-01:00:00.5"},
+			{Name: "price", Type: mysql.TypeNewDecimal, Value: "1.50"},
+		}
+	}
+	expectedRow := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":    float64(1),
+			"name":  name,
+			"data":  "Af8=",
+			"kind":  "b",
+			"flag":  true,
+			"d":     float64(2),
+			"dt":    float64(1000002),
+			"ts":    "2023-01-01T00:00:00Z",
+			"t":     float64(-3600500000),
+			"price": "1.50",
+		}
+	}
+
+	commitTs := oracle.GoTimeToTS(time.UnixMilli(1672531200000))
+	table := &model.TableName{Schema: "test", Table: "t"}
+	insert := &model.RowChangedEvent{
+		CommitTs: commitTs, Table: table, ColInfos: colInfos,
+		Columns: newColumns("a"),
+	}
+	update := &model.RowChangedEvent{
+		CommitTs: commitTs, Table: table, ColInfos: colInfos,
+		PreColumns: newColumns("a"), Columns: newColumns("b"),
+	}
+	deleteRow := &model.RowChangedEvent{
+		CommitTs: commitTs, Table: table, ColInfos: colInfos,
+		PreColumns: newColumns("b"),
+	}
+	for _, event := range []*model.RowChangedEvent{insert, update, deleteRow} {
+		err = encoder.AppendRowChangedEvent(context.Background(), "", event, nil)
+		require.NoError(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 3)
+	require.Nil(t, encoder.Build())
+
+	expected := []struct {
+		op     string
+		before map[string]interface{}
+		after  map[string]interface{}
+	}{
+		{op: "c", after: expectedRow("a")},
+		{op: "u", before: expectedRow("a"), after: expectedRow("b")},
+		{op: "d", before: expectedRow("b")},
+	}
+	for i, msg := range messages {
+		require.Equal(t, 1, msg.GetRowsCount())
+		require.Equal(t, `{"id":1}`, string(msg.Key))
+
+		var value map[string]interface{}
+		require.NoError(t, json.Unmarshal(msg.Value, &value))
+		require.Equal(t, expected[i].op, value["op"])
+		if expected[i].before == nil {
+			require.Nil(t, value["before"])
+		} else {
+			require.Equal(t, expected[i].before, value["before"])
+		}
+		if expected[i].after == nil {
+			require.Nil(t, value["after"])
+		} else {
+			require.Equal(t, expected[i].after, value["after"])
+		}
+		require.Contains(t, value, "ts_ms")
+
+		source := value["source"].(map[string]interface{})
+		require.Equal(t, "tidb", source["connector"])
+		require.Equal(t, "test-cf", source["name"])
+		require.Equal(t, "test", source["db"])
+		require.Equal(t, "t", source["table"])
+		require.Equal(t, float64(1672531200000), source["ts_ms"])
+		require.Equal(t, float64(commitTs), source["commit_ts"])
+	}
+
+	// the columns are encoded in the order of the table columns.
+	require.Contains(t, string(messages[0].Value),
+		`"after":{"id":1,"name":"a","data":"Af8=","kind":"b","flag":true,`)
+
+	// the delete event only contains the handle key columns.
+	encoder.(*BatchEncoder).config.DeleteOnlyHandleKeyColumns = true
+	err = encoder.AppendRowChangedEvent(context.Background(), "", deleteRow, nil)
+	require.NoError(t, err)
+	messages = encoder.Build()
+	require.Len(t, messages, 1)
+	require.Contains(t, string(messages[0].Value), `"before":{"id":1},"after":null`)
+}
+
+func TestEncodeMessageTooLarge(t *testing.T) {
+	t.Parallel()
+
+	encoder := newBatchEncoder("test-cf", time.UTC,
+		common.NewConfig(config.ProtocolDebezium).WithMaxMessageBytes(100))
+	err := encoder.AppendRowChangedEvent(context.Background(), "", &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "test", Table: "t"},
+		Columns:  []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: int64(1)}},
+	}, nil)
+	require.ErrorContains(t, err, "message is too large")
+}
+
+func TestEncodeDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	encoder := newBatchEncoder("test-cf", time.UTC, common.NewConfig(config.ProtocolDebezium))
+	msg, err := encoder.EncodeDDLEvent(&model.DDLEvent{
+		CommitTs: 1,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t"},
+			TableInfo: &timodel.TableInfo{},
+		},
+		Query: "create table t(id int primary key)",
+		Type:  timodel.ActionCreateTable,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"databaseName":"test"}`, string(msg.Key))
+
+	var value map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg.Value, &value))
+	require.Equal(t, "test", value["databaseName"])
+	require.Equal(t, "create table t(id int primary key)", value["ddl"])
+	require.Equal(t, []interface{}{}, value["tableChanges"])
+
+	checkpoint, err := encoder.EncodeCheckpointEvent(1)
+	require.NoError(t, err)
+	require.Nil(t, checkpoint)
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debezium
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+const (
+	connectorName = "tidb"
+
+	// The operation types of the debezium change events.
+	opCreate = "c"
+	opUpdate = "u"
+	opDelete = "d"
+
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05.999999"
+	zeroDate       = "0000-00-00"
+)
+
+// source is the metadata of the change event, the fields which don't make sense
+// for TiDB, such as the binlog file and position, are kept with zero values to
+// be compatible with the debezium MySQL connector.
+type source struct {
+	Version   string  `json:"version"`
+	Connector string  `json:"connector"`
+	Name      string  `json:"name"`
+	TsMs      int64   `json:"ts_ms"`
+	Snapshot  string  `json:"snapshot"`
+	DB        string  `json:"db"`
+	Table     string  `json:"table,omitempty"`
+	ServerID  int64   `json:"server_id"`
+	GTID      *string `json:"gtid"`
+	File      string  `json:"file"`
+	Pos       int64   `json:"pos"`
+	Row       int     `json:"row"`
+	Thread    *int64  `json:"thread"`
+	Query     *string `json:"query"`
+	// CommitTs is the commit-ts of the transaction in TiDB.
+	CommitTs uint64 `json:"commit_ts"`
+}
+
+func newSource(name string, schema string, table string, commitTs uint64) *source {
+	return &source{
+		Version:   version.ReleaseVersion,
+		Connector: connectorName,
+		Name:      name,
+		TsMs:      oracle.ExtractPhysical(commitTs),
+		Snapshot:  "false",
+		DB:        schema,
+		Table:     table,
+		CommitTs:  commitTs,
+	}
+}
+
+// rowMessage is the value of a debezium data change event, it's the payload
+// of the envelope without the schema.
+type rowMessage struct {
+	Before      *row        `json:"before"`
+	After       *row        `json:"after"`
+	Source      *source     `json:"source"`
+	Op          string      `json:"op"`
+	TsMs        int64       `json:"ts_ms"`
+	Transaction interface{} `json:"transaction"`
+}
+
+// ddlMessage is the value of a debezium schema change event.
+type ddlMessage struct {
+	Source       *source       `json:"source"`
+	DatabaseName string        `json:"databaseName"`
+	SchemaName   *string       `json:"schemaName"`
+	DDL          string        `json:"ddl"`
+	TableChanges []interface{} `json:"tableChanges"`
+}
+
+// row is the columns of a row, which are encoded in the order of the table columns.
+type row struct {
+	names  []string
+	values []interface{}
+}
+
+func (r *row) add(name string, value interface{}) {
+	r.names = append(r.names, name)
+	r.values = append(r.values, value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r *row) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, name := range r.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (e *BatchEncoder) newRow(
+	columns []*model.Column, colInfos []rowcodec.ColInfo, onlyHandleKey bool,
+) (*row, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	r := &row{}
+	for i, col := range columns {
+		if col == nil {
+			continue
+		}
+		if onlyHandleKey && !col.Flag.IsHandleKey() {
+			continue
+		}
+		var ft *types.FieldType
+		if i < len(colInfos) {
+			ft = colInfos[i].Ft
+		}
+		value, err := e.convertColumnValue(col, ft)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDebeziumEncodeFailed,
+				errors.Annotatef(err, "failed to convert the value of column %s", col.Name))
+		}
+		r.add(col.Name, value)
+	}
+	return r, nil
+}
+
+// convertColumnValue converts the column value to the one which is represented
+// the same as the debezium MySQL connector with the default configurations,
+// except that the decimal values are represented as strings.
+func (e *BatchEncoder) convertColumnValue(col *model.Column, ft *types.FieldType) (interface{}, error) {
+	if col.Value == nil {
+		return nil, nil
+	}
+
+	switch col.Type {
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		v, ok := col.Value.([]byte)
+		if !ok {
+			return col.Value, nil
+		}
+		// the binary values are encoded by base64 in JSON.
+		if col.Flag.IsBinary() {
+			return v, nil
+		}
+		return string(v), nil
+	case mysql.TypeEnum, mysql.TypeSet:
+		v, ok := col.Value.(uint64)
+		if !ok || ft == nil {
+			return col.Value, nil
+		}
+		if col.Type == mysql.TypeEnum {
+			enumVar, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return enumVar.Name, nil
+		}
+		setVar, err := types.ParseSetValue(ft.GetElems(), v)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return setVar.Name, nil
+	case mysql.TypeBit:
+		v, ok := col.Value.(uint64)
+		if !ok {
+			return col.Value, nil
+		}
+		// BIT(1) is represented as boolean, and the others are represented as
+		// the bytes in little-endian order.
+		if ft != nil && ft.GetFlen() == 1 {
+			return v != 0, nil
+		}
+		flen := 64
+		if ft != nil && ft.GetFlen() > 0 {
+			flen = ft.GetFlen()
+		}
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, v)
+		return buf[:(flen+7)/8], nil
+	case mysql.TypeDate, mysql.TypeNewDate:
+		v := col.Value.(string)
+		if strings.HasPrefix(v, zeroDate) {
+			return nil, nil
+		}
+		t, err := time.ParseInLocation(dateLayout, v, time.UTC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// the number of days since the epoch.
+		return int32(t.Unix() / 86400), nil
+	case mysql.TypeDatetime:
+		v := col.Value.(string)
+		if strings.HasPrefix(v, zeroDate) {
+			return nil, nil
+		}
+		t, err := time.ParseInLocation(datetimeLayout, v, time.UTC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// the number of milliseconds since the epoch, or the number of
+		// microseconds if the precision is greater than 3.
+		if ft != nil && ft.GetDecimal() > 3 {
+			return t.UnixMicro(), nil
+		}
+		return t.UnixMilli(), nil
+	case mysql.TypeTimestamp:
+		v := col.Value.(string)
+		if strings.HasPrefix(v, zeroDate) {
+			return nil, nil
+		}
+		t, err := time.ParseInLocation(datetimeLayout, v, e.tz)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	case mysql.TypeDuration:
+		d, _, err := types.ParseDuration(nil, col.Value.(string), types.MaxFsp)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// the number of microseconds.
+		return d.Duration.Microseconds(), nil
+	default:
+		return col.Value, nil
+	}
+}
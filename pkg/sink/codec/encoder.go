@@ -24,6 +24,9 @@ import (
 const (
 	// BatchVersion1 represents the version of batch format
 	BatchVersion1 uint64 = 1
+	// BatchVersion2 represents the version of batch format, whose row
+	// messages are self-describing.
+	BatchVersion2 uint64 = 2
 )
 
 // DDLEventBatchEncoder is an abstraction for DDL event encoder.
@@ -50,6 +53,25 @@ type RowEventEncoder interface {
 	MessageBuilder
 }
 
+// RowEventBatchEncoder is implemented by the row event encoders which have a
+// fast path to encode the row changed events of the same table in one call,
+// the lookups of the table are amortized over the events.
+type RowEventBatchEncoder interface {
+	// AppendRowChangedEvents appends the row changed events of the same table,
+	// the callbacks are in the same order as the events. It returns the number
+	// of the events appended before the one which fails to be encoded.
+	AppendRowChangedEvents(ctx context.Context, topic string,
+		events []*model.RowChangedEvent, callbacks []func()) (int, error)
+}
+
+// SchemaCompatibilityChecker is implemented by the encoders which register the
+// schemas of the tables in a schema registry.
+type SchemaCompatibilityChecker interface {
+	// CheckSchemaCompatibility checks whether the new schema of the table
+	// changed by the DDL is compatible with the registered one of the topic.
+	CheckSchemaCompatibility(ctx context.Context, topic string, ddl *model.DDLEvent) error
+}
+
 // ClaimCheckLocationEncoder is an abstraction for claim check encoder.
 type ClaimCheckLocationEncoder interface {
 	NewClaimCheckLocationMessage(origin *common.Message) (*common.Message, error)
@@ -87,3 +109,15 @@ func IsColumnValueEqual(preValue, updatedValue interface{}) bool {
 	// the value type should be the same
 	return preValue == updatedValue
 }
+
+// IsColumnUpdated checks whether the column of the update event is updated,
+// the column is treated as updated if its type is changed.
+func IsColumnUpdated(preColumn, column *model.Column) bool {
+	if preColumn == nil || column == nil {
+		return true
+	}
+	if preColumn.Type != column.Type {
+		return true
+	}
+	return !IsColumnValueEqual(preColumn.Value, column.Value)
+}
@@ -23,7 +23,11 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -40,8 +44,13 @@ type EncoderGroup interface {
 	Run(ctx context.Context) error
 	// AddEvents add events into the group, handled by one of the encoders
 	// all input events should belong to the same topic and partition, this should be guaranteed by the caller
-	AddEvents(ctx context.Context, topic string, partition int32,
+	// the keys of the encoded messages are replaced by the key if it's not nil.
+	AddEvents(ctx context.Context, topic string, partition int32, key []byte,
 		events ...*dmlsink.RowChangeCallbackableEvent) error
+	// AddMessages adds the encoded messages into the group, they're output in
+	// order with the futures of the events without being encoded.
+	AddMessages(ctx context.Context, topic string, partition int32,
+		messages ...*common.Message) error
 	// Output returns a channel produce futures
 	Output() <-chan *future
 }
@@ -54,12 +63,16 @@ type encoderGroup struct {
 	inputCh []chan *future
 	index   uint64
 
+	// deadLetter indicates the events which can not be encoded are collected
+	// into the futures as dead letters instead of failing the group.
+	deadLetter bool
+
 	outputCh chan *future
 }
 
 // NewEncoderGroup creates a new EncoderGroup instance
 func NewEncoderGroup(builder RowEventEncoderBuilder,
-	count int, changefeedID model.ChangeFeedID,
+	count int, changefeedID model.ChangeFeedID, deadLetter bool,
 ) *encoderGroup {
 	if count <= 0 {
 		count = defaultEncoderGroupSize
@@ -73,11 +86,12 @@ func NewEncoderGroup(builder RowEventEncoderBuilder,
 	return &encoderGroup{
 		changefeedID: changefeedID,
 
-		builder:  builder,
-		count:    count,
-		inputCh:  inputCh,
-		index:    0,
-		outputCh: make(chan *future, defaultInputChanSize*count),
+		builder:    builder,
+		count:      count,
+		inputCh:    inputCh,
+		index:      0,
+		deadLetter: deadLetter,
+		outputCh:   make(chan *future, defaultInputChanSize*count),
 	}
 }
 
@@ -112,25 +126,101 @@ func (g *encoderGroup) runEncoder(ctx context.Context, idx int) error {
 		case <-ticker.C:
 			metric.Set(float64(len(inputCh)))
 		case future := <-inputCh:
-			for _, event := range future.events {
-				err := encoder.AppendRowChangedEvent(ctx, future.Topic, event.Event, event.Callback)
-				if err != nil {
-					return errors.Trace(err)
-				}
+			_, span := tracing.StartSpan(future.WithSpanContext(ctx), "codec.encode", g.changefeedID,
+				attribute.String("topic", future.Topic),
+				attribute.Int64("partition", int64(future.Partition)),
+				attribute.Int("events", len(future.events)))
+			var err error
+			if batchEncoder, ok := encoder.(RowEventBatchEncoder); ok {
+				err = g.appendEventsInBatch(ctx, batchEncoder, future)
+			} else {
+				err = g.appendEvents(ctx, encoder, future)
+			}
+			if err != nil {
+				tracing.EndSpan(span, err)
+				return errors.Trace(err)
 			}
 			future.Messages = encoder.Build()
+			span.SetAttributes(attribute.Int("messages", len(future.Messages)))
+			tracing.EndSpan(span, nil)
 			close(future.done)
 		}
 	}
 }
 
+func (g *encoderGroup) appendEvents(
+	ctx context.Context, encoder RowEventEncoder, future *future,
+) error {
+	for _, event := range future.events {
+		err := encoder.AppendRowChangedEvent(ctx, future.Topic, event.Event, event.Callback)
+		if err != nil {
+			if err = g.divertToDeadLetter(future, event, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendEventsInBatch appends the consecutive events of the same table in a
+// batch, the events after the one which fails to be encoded are appended in
+// the next batch if the failed one is diverted to the dead-letter queue.
+func (g *encoderGroup) appendEventsInBatch(
+	ctx context.Context, encoder RowEventBatchEncoder, future *future,
+) error {
+	events := future.events
+	rows := make([]*model.RowChangedEvent, 0, len(events))
+	callbacks := make([]func(), 0, len(events))
+	for len(events) > 0 {
+		n := 1
+		for n < len(events) && *events[n].Event.Table == *events[0].Event.Table {
+			n++
+		}
+		rows, callbacks = rows[:0], callbacks[:0]
+		for _, event := range events[:n] {
+			rows = append(rows, event.Event)
+			callbacks = append(callbacks, event.Callback)
+		}
+		appended, err := encoder.AppendRowChangedEvents(ctx, future.Topic, rows, callbacks)
+		if err != nil {
+			if err = g.divertToDeadLetter(future, events[appended], err); err != nil {
+				return err
+			}
+			n = appended + 1
+		}
+		events = events[n:]
+	}
+	return nil
+}
+
+// divertToDeadLetter diverts the event which can not be encoded to the
+// dead-letter queue, the error is returned if it can't be diverted.
+func (g *encoderGroup) divertToDeadLetter(
+	future *future, event *dmlsink.RowChangeCallbackableEvent, err error,
+) error {
+	if !g.deadLetter || !cerror.IsDeadLetterError(err) {
+		return errors.Trace(err)
+	}
+	log.Warn("row changed event can not be encoded, divert it to the dead-letter queue",
+		zap.String("namespace", g.changefeedID.Namespace),
+		zap.String("changefeed", g.changefeedID.ID),
+		zap.Any("table", event.Event.Table),
+		zap.Uint64("commitTs", event.Event.CommitTs),
+		zap.Error(err))
+	future.DeadLetters = append(future.DeadLetters, &DeadLetter{Event: event, Err: err})
+	return nil
+}
+
 func (g *encoderGroup) AddEvents(
 	ctx context.Context,
 	topic string,
 	partition int32,
+	key []byte,
 	events ...*dmlsink.RowChangeCallbackableEvent,
 ) error {
 	future := newFuture(topic, partition, events...)
+	future.Key = key
+	future.spanContext = trace.SpanContextFromContext(ctx)
 	index := atomic.AddUint64(&g.index, 1) % uint64(g.count)
 	select {
 	case <-ctx.Done():
@@ -147,15 +237,48 @@ func (g *encoderGroup) AddEvents(
 	return nil
 }
 
+func (g *encoderGroup) AddMessages(
+	ctx context.Context,
+	topic string,
+	partition int32,
+	messages ...*common.Message,
+) error {
+	future := newFuture(topic, partition)
+	future.Messages = messages
+	close(future.done)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case g.outputCh <- future:
+	}
+	return nil
+}
+
 func (g *encoderGroup) Output() <-chan *future {
 	return g.outputCh
 }
 
+// DeadLetter is a row changed event which can not be encoded,
+// and the error returned by the encoder.
+type DeadLetter struct {
+	Event *dmlsink.RowChangeCallbackableEvent
+	Err   error
+}
+
 type future struct {
 	Topic     string
 	Partition int32
-	events    []*dmlsink.RowChangeCallbackableEvent
-	Messages  []*common.Message
+	// Key is the key of the messages rendered by the key template, the keys
+	// set by the encoder are used if it's nil.
+	Key      []byte
+	events   []*dmlsink.RowChangeCallbackableEvent
+	Messages []*common.Message
+	// DeadLetters are the events which can not be encoded.
+	DeadLetters []*DeadLetter
+
+	// spanContext is the span context of the batch which the future belongs
+	// to, the spans of the future are in the same trace.
+	spanContext trace.SpanContext
 
 	done chan struct{}
 }
@@ -172,6 +295,27 @@ func newFuture(topic string, partition int32,
 	}
 }
 
+// WithSpanContext returns a copy of ctx carrying the span context of the batch
+// which the future belongs to.
+func (p *future) WithSpanContext(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(ctx, p.spanContext)
+}
+
+// OriginID returns the origin of the rows in the future, false is returned if
+// the rows come from different origins.
+func (p *future) OriginID() (uint64, bool) {
+	if len(p.events) == 0 {
+		return 0, false
+	}
+	originID := p.events[0].Event.OriginID
+	for _, event := range p.events[1:] {
+		if event.Event.OriginID != originID {
+			return 0, false
+		}
+	}
+	return originID, true
+}
+
 // Ready waits until the response is ready, should be called before consuming the future.
 func (p *future) Ready(ctx context.Context) error {
 	select {
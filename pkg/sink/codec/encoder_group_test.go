@@ -0,0 +1,147 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBatchEncoder encodes a message for each row, and records the number of
+// the rows of each batch. The rows whose commit ts is failedTs can't be encoded.
+type mockBatchEncoder struct {
+	failedTs uint64
+	batches  []int
+	messages []*common.Message
+}
+
+func (e *mockBatchEncoder) EncodeCheckpointEvent(uint64) (*common.Message, error) {
+	return nil, nil
+}
+
+func (e *mockBatchEncoder) EncodeDDLEvent(*model.DDLEvent) (*common.Message, error) {
+	return nil, nil
+}
+
+func (e *mockBatchEncoder) AppendRowChangedEvent(
+	_ context.Context, _ string, event *model.RowChangedEvent, callback func(),
+) error {
+	if event.CommitTs == e.failedTs {
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+	}
+	e.messages = append(e.messages, &common.Message{Ts: event.CommitTs, Callback: callback})
+	return nil
+}
+
+func (e *mockBatchEncoder) AppendRowChangedEvents(
+	ctx context.Context, topic string, events []*model.RowChangedEvent, callbacks []func(),
+) (int, error) {
+	e.batches = append(e.batches, len(events))
+	for i, event := range events {
+		if err := e.AppendRowChangedEvent(ctx, topic, event, callbacks[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+func (e *mockBatchEncoder) Build() []*common.Message {
+	messages := e.messages
+	e.messages = nil
+	return messages
+}
+
+func TestAppendEventsInBatch(t *testing.T) {
+	t.Parallel()
+
+	t1 := &model.TableName{Schema: "test", Table: "t1"}
+	t2 := &model.TableName{Schema: "test", Table: "t2"}
+	var events []*dmlsink.RowChangeCallbackableEvent
+	for i, table := range []*model.TableName{t1, t1, t1, t2, t2, t1} {
+		events = append(events, &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{CommitTs: uint64(i + 1), Table: table},
+		})
+	}
+
+	g := NewEncoderGroup(nil, 1, model.DefaultChangeFeedID("test"), true)
+	encoder := &mockBatchEncoder{}
+	f := newFuture("test", 0, events...)
+	require.NoError(t, g.appendEventsInBatch(context.Background(), encoder, f))
+	require.Equal(t, []int{3, 2, 1}, encoder.batches)
+	require.Len(t, encoder.Build(), 6)
+	require.Empty(t, f.DeadLetters)
+
+	// the failed event is diverted, and the rest of the batch is appended.
+	encoder = &mockBatchEncoder{failedTs: 2}
+	f = newFuture("test", 0, events...)
+	require.NoError(t, g.appendEventsInBatch(context.Background(), encoder, f))
+	require.Equal(t, []int{3, 1, 2, 1}, encoder.batches)
+	messages := encoder.Build()
+	require.Len(t, messages, 5)
+	require.Equal(t, uint64(3), messages[1].Ts)
+	require.Len(t, f.DeadLetters, 1)
+	require.Equal(t, events[1], f.DeadLetters[0].Event)
+
+	// the error is returned if the dead-letter queue is disabled.
+	g = NewEncoderGroup(nil, 1, model.DefaultChangeFeedID("test"), false)
+	encoder = &mockBatchEncoder{failedTs: 2}
+	f = newFuture("test", 0, events...)
+	err := g.appendEventsInBatch(context.Background(), encoder, f)
+	require.True(t, cerror.ErrMessageTooLarge.Equal(err))
+}
+
+func TestFutureOriginID(t *testing.T) {
+	t.Parallel()
+
+	_, ok := newFuture("test", 0).OriginID()
+	require.False(t, ok)
+
+	events := []*dmlsink.RowChangeCallbackableEvent{
+		{Event: &model.RowChangedEvent{CommitTs: 1, OriginID: 2}},
+		{Event: &model.RowChangedEvent{CommitTs: 2, OriginID: 2}},
+	}
+	originID, ok := newFuture("test", 0, events...).OriginID()
+	require.True(t, ok)
+	require.Equal(t, uint64(2), originID)
+
+	// the rows come from different origins.
+	events = append(events, &dmlsink.RowChangeCallbackableEvent{
+		Event: &model.RowChangedEvent{CommitTs: 3},
+	})
+	_, ok = newFuture("test", 0, events...).OriginID()
+	require.False(t, ok)
+}
+
+func TestAddMessages(t *testing.T) {
+	t.Parallel()
+
+	g := NewEncoderGroup(nil, 1, model.DefaultChangeFeedID("test"), false)
+	messages := []*common.Message{{Ts: 1}, {Ts: 2}}
+	require.NoError(t, g.AddMessages(context.Background(), "test", 1, messages...))
+
+	// the messages are output without being encoded.
+	f := <-g.Output()
+	require.NoError(t, f.Ready(context.Background()))
+	require.Equal(t, "test", f.Topic)
+	require.Equal(t, int32(1), f.Partition)
+	require.Equal(t, messages, f.Messages)
+	_, ok := f.OriginID()
+	require.False(t, ok)
+}
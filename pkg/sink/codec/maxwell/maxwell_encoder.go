@@ -86,6 +86,10 @@ func (d *BatchEncoder) Build() []*common.Message {
 
 	ret := common.NewMsg(config.ProtocolMaxwell,
 		d.keyBuf.Bytes(), d.valueBuf.Bytes(), 0, model.MessageTypeRow, nil, nil)
+	// the buffers are handed over to the message, and new ones are used for
+	// the next batch.
+	ret.SetPooledKey(d.keyBuf)
+	ret.SetPooledValue(d.valueBuf)
 	ret.SetRowsCount(d.batchSize)
 	if len(d.callbackBuf) != 0 && len(d.callbackBuf) == d.batchSize {
 		callbacks := d.callbackBuf
@@ -102,8 +106,8 @@ func (d *BatchEncoder) Build() []*common.Message {
 
 // reset implements the RowEventEncoder interface
 func (d *BatchEncoder) reset() {
-	d.keyBuf.Reset()
-	d.valueBuf.Reset()
+	d.keyBuf = common.GetBuffer()
+	d.valueBuf = common.GetBuffer()
 	d.batchSize = 0
 	var versionByte [8]byte
 	binary.BigEndian.PutUint64(versionByte[:], codec.BatchVersion1)
@@ -113,8 +117,6 @@ func (d *BatchEncoder) reset() {
 // newBatchEncoder creates a new maxwell BatchEncoder.
 func newBatchEncoder(config *common.Config) codec.RowEventEncoder {
 	batch := &BatchEncoder{
-		keyBuf:      &bytes.Buffer{},
-		valueBuf:    &bytes.Buffer{},
 		callbackBuf: make([]func(), 0),
 		config:      config,
 	}
@@ -136,4 +136,38 @@ func TestMaxwellAppendRowChangedEventWithCallback(t *testing.T) {
 	require.Len(t, msgs, 1, "expected one message")
 	msgs[0].Callback()
 	require.Equal(t, 15, count, "expected all callbacks to be called")
+
+	// the message isn't overwritten by the next batch.
+	value := string(msgs[0].Value)
+	err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+	require.NoError(t, err)
+	next := encoder.Build()
+	require.Len(t, next, 1)
+	require.Equal(t, value, string(msgs[0].Value))
+	msgs[0].Release()
+	next[0].Release()
+}
+
+func BenchmarkMaxwellBatchEncoder(b *testing.B) {
+	encoder := newBatchEncoder(&common.Config{})
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 3, Value: 10}},
+	}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 64; j++ {
+			err := encoder.AppendRowChangedEvent(ctx, "", row, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		for _, m := range encoder.Build() {
+			m.Release()
+		}
+	}
 }
@@ -39,6 +39,8 @@ import (
 type BatchDecoder struct {
 	keyBytes   []byte
 	valueBytes []byte
+	// version is the version of the current batch.
+	version uint64
 
 	nextKey   *internal.MessageKey
 	nextEvent *model.RowChangedEvent
@@ -83,11 +85,12 @@ func (b *BatchDecoder) AddKeyValue(key, value []byte) error {
 	}
 	version := binary.BigEndian.Uint64(key[:8])
 	key = key[8:]
-	if version != codec.BatchVersion1 {
+	if version != codec.BatchVersion1 && version != codec.BatchVersion2 {
 		return cerror.ErrOpenProtocolCodecInvalidData.
 			GenWithStack("unexpected key format version")
 	}
 
+	b.version = version
 	b.keyBytes = key
 	b.valueBytes = value
 
@@ -138,11 +141,11 @@ func (b *BatchDecoder) HasNext() (model.MessageType, bool, error) {
 		value := b.valueBytes[8 : valueLen+8]
 		b.valueBytes = b.valueBytes[valueLen+8:]
 
-		rowMsg := new(messageRow)
-		if err := rowMsg.decode(value); err != nil {
-			return b.nextKey.Type, false, errors.Trace(err)
+		event, err := decodeRow(b.version, b.nextKey, value)
+		if err != nil {
+			return b.nextKey.Type, false, err
 		}
-		b.nextEvent = msgToRowChange(b.nextKey, rowMsg)
+		b.nextEvent = event
 	}
 
 	return b.nextKey.Type, true, nil
@@ -306,7 +309,7 @@ func (b *BatchDecoder) assembleEventFromClaimCheckStorage(ctx context.Context) (
 	}
 
 	version := binary.BigEndian.Uint64(claimCheckM.Key[:8])
-	if version != codec.BatchVersion1 {
+	if version != codec.BatchVersion1 && version != codec.BatchVersion2 {
 		return nil, cerror.ErrOpenProtocolCodecInvalidData.
 			GenWithStack("unexpected key format version")
 	}
@@ -319,15 +322,31 @@ func (b *BatchDecoder) assembleEventFromClaimCheckStorage(ctx context.Context) (
 		return nil, errors.Trace(err)
 	}
 
-	rowMsg := new(messageRow)
 	valueLen := binary.BigEndian.Uint64(claimCheckM.Value[:8])
 	value := claimCheckM.Value[8 : valueLen+8]
-	if err := rowMsg.decode(value); err != nil {
-		return nil, errors.Trace(err)
+	event, err := decodeRow(version, msgKey, value)
+	if err != nil {
+		return nil, err
 	}
-
-	event := msgToRowChange(msgKey, rowMsg)
 	b.nextKey = nil
 
 	return event, nil
 }
+
+// decodeRow decodes the value of a row message by the version of the batch.
+func decodeRow(
+	version uint64, key *internal.MessageKey, value []byte,
+) (*model.RowChangedEvent, error) {
+	if version == codec.BatchVersion2 {
+		rowMsg := new(messageRowV2)
+		if err := rowMsg.decode(value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return msgV2ToRowChange(key, rowMsg), nil
+	}
+	rowMsg := new(messageRow)
+	if err := rowMsg.decode(value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return msgToRowChange(key, rowMsg), nil
+}
@@ -25,6 +25,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	"go.uber.org/zap"
 )
 
@@ -33,21 +34,51 @@ type BatchEncoder struct {
 	messageBuf   []*common.Message
 	callbackBuff []func()
 	curBatchSize int
+	// keyBuf and valueBuf are the pooled buffers of the last batched message,
+	// they're nil if the last message can't be batched with others.
+	keyBuf   *bytes.Buffer
+	valueBuf *bytes.Buffer
 
 	config *common.Config
 }
 
-func (d *BatchEncoder) buildMessageOnlyHandleKeyColumns(e *model.RowChangedEvent) ([]byte, []byte, error) {
-	// set the `largeMessageOnlyHandleKeyColumns` to true to only encode handle key columns.
-	keyMsg, valueMsg, err := rowChangeToMsg(e, d.config, true)
+// batchVersion returns the version of the batches, which is written as the
+// head of the keys.
+func (d *BatchEncoder) batchVersion() uint64 {
+	if d.config.OpenProtocolVersion == common.OpenProtocolVersion2 {
+		return codec.BatchVersion2
+	}
+	return codec.BatchVersion1
+}
+
+// encodeRow encodes the row into the message key and the encoded value by the
+// configured version of the messages.
+func (d *BatchEncoder) encodeRow(
+	e *model.RowChangedEvent, largeMessageOnlyHandleKeyColumns bool,
+) (*internal.MessageKey, []byte, error) {
+	if d.config.OpenProtocolVersion == common.OpenProtocolVersion2 {
+		keyMsg, valueMsg, err := rowChangeToMsgV2(e, d.config, largeMessageOnlyHandleKeyColumns)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		value, err := valueMsg.encode()
+		return keyMsg, value, errors.Trace(err)
+	}
+	keyMsg, valueMsg, err := rowChangeToMsg(e, d.config, largeMessageOnlyHandleKeyColumns)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
-	key, err := keyMsg.Encode()
+	value, err := valueMsg.encode()
+	return keyMsg, value, errors.Trace(err)
+}
+
+func (d *BatchEncoder) buildMessageOnlyHandleKeyColumns(e *model.RowChangedEvent) ([]byte, []byte, error) {
+	// set the `largeMessageOnlyHandleKeyColumns` to true to only encode handle key columns.
+	keyMsg, value, err := d.encodeRow(e, true)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
-	value, err := valueMsg.encode()
+	key, err := keyMsg.Encode()
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -77,7 +108,7 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	keyMsg, valueMsg, err := rowChangeToMsg(e, d.config, false)
+	keyMsg, value, err := d.encodeRow(e, false)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -85,10 +116,6 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	if err != nil {
 		return errors.Trace(err)
 	}
-	value, err := valueMsg.encode()
-	if err != nil {
-		return errors.Trace(err)
-	}
 
 	// for single message that is longer than max-message-bytes
 	// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
@@ -111,6 +138,13 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 			return nil
 		}
 
+		// single message too large, compression enabled, compress it to a new individual message.
+		if d.config.LargeMessageHandle.EnableCompression() {
+			// build previous batched messages
+			d.tryBuildCallback()
+			return d.appendSingleLargeMessage4Compression(key, value, e, callback)
+		}
+
 		// it's must that `LargeMessageHandle == LargeMessageHandleOnlyHandleKeyColumns` here.
 		key, value, err = d.buildMessageOnlyHandleKeyColumns(e)
 		if err != nil {
@@ -118,15 +152,20 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 		}
 	}
 
-	if len(d.messageBuf) == 0 ||
+	if d.keyBuf == nil ||
 		d.curBatchSize >= d.config.MaxBatchSize ||
 		d.messageBuf[len(d.messageBuf)-1].Length()+len(key)+len(value)+16 > d.config.MaxMessageBytes {
 		// Before we create a new message, we should handle the previous callbacks.
 		d.tryBuildCallback()
-		versionHead := make([]byte, 8)
-		binary.BigEndian.PutUint64(versionHead, codec.BatchVersion1)
-		msg := common.NewMsg(config.ProtocolOpen, versionHead, nil,
+		var versionHead [8]byte
+		binary.BigEndian.PutUint64(versionHead[:], d.batchVersion())
+		d.keyBuf = common.GetBuffer()
+		d.keyBuf.Write(versionHead[:])
+		d.valueBuf = common.GetBuffer()
+		msg := common.NewMsg(config.ProtocolOpen, d.keyBuf.Bytes(), nil,
 			0, model.MessageTypeRow, nil, nil)
+		msg.SetPooledKey(d.keyBuf)
+		msg.SetPooledValue(d.valueBuf)
 		d.messageBuf = append(d.messageBuf, msg)
 		d.curBatchSize = 0
 	}
@@ -139,10 +178,12 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
 
 	message := d.messageBuf[len(d.messageBuf)-1]
-	message.Key = append(message.Key, keyLenByte[:]...)
-	message.Key = append(message.Key, key...)
-	message.Value = append(message.Value, valueLenByte[:]...)
-	message.Value = append(message.Value, value...)
+	d.keyBuf.Write(keyLenByte[:])
+	d.keyBuf.Write(key)
+	d.valueBuf.Write(valueLenByte[:])
+	d.valueBuf.Write(value)
+	message.Key = d.keyBuf.Bytes()
+	message.Value = d.valueBuf.Bytes()
 	message.Ts = e.CommitTs
 	message.Schema = &e.Table.Schema
 	message.Table = &e.Table.Table
@@ -175,7 +216,7 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 
 	keyBuf := new(bytes.Buffer)
 	var versionByte [8]byte
-	binary.BigEndian.PutUint64(versionByte[:], codec.BatchVersion1)
+	binary.BigEndian.PutUint64(versionByte[:], d.batchVersion())
 	keyBuf.Write(versionByte[:])
 	keyBuf.Write(keyLenByte[:])
 	keyBuf.Write(key)
@@ -203,7 +244,7 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 
 	keyBuf := new(bytes.Buffer)
 	var versionByte [8]byte
-	binary.BigEndian.PutUint64(versionByte[:], codec.BatchVersion1)
+	binary.BigEndian.PutUint64(versionByte[:], d.batchVersion())
 	keyBuf.Write(versionByte[:])
 	keyBuf.Write(keyLenByte[:])
 	keyBuf.Write(key)
@@ -220,6 +261,8 @@ func (d *BatchEncoder) Build() (messages []*common.Message) {
 	d.tryBuildCallback()
 	ret := d.messageBuf
 	d.messageBuf = make([]*common.Message, 0)
+	// the buffers are owned by the built messages.
+	d.keyBuf, d.valueBuf = nil, nil
 	return ret
 }
 
@@ -237,9 +280,15 @@ func (d *BatchEncoder) tryBuildCallback() {
 	}
 }
 
+// appendSingleMessage appends the message which can't be batched with others.
+func (d *BatchEncoder) appendSingleMessage(message *common.Message) {
+	d.messageBuf = append(d.messageBuf, message)
+	d.keyBuf, d.valueBuf = nil, nil
+}
+
 // NewClaimCheckLocationMessage implement the ClaimCheckLocationEncoder interface.
 func (d *BatchEncoder) NewClaimCheckLocationMessage(origin *common.Message) (*common.Message, error) {
-	keyMsg, valueMsg, err := rowChangeToMsg(origin.Event, d.config, true)
+	keyMsg, value, err := d.encodeRow(origin.Event, true)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -251,11 +300,6 @@ func (d *BatchEncoder) NewClaimCheckLocationMessage(origin *common.Message) (*co
 		return nil, errors.Trace(err)
 	}
 
-	value, err := valueMsg.encode()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	// for single message that is longer than max-message-bytes
 	// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
 	length := len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
@@ -268,7 +312,7 @@ func (d *BatchEncoder) NewClaimCheckLocationMessage(origin *common.Message) (*co
 		return nil, cerror.ErrMessageTooLarge.GenWithStackByArgs()
 	}
 
-	message := newMessage(key, value)
+	message := newMessage(d.batchVersion(), key, value)
 	message.Ts = origin.Ts
 	message.Schema = origin.Schema
 	message.Table = origin.Table
@@ -280,7 +324,7 @@ func (d *BatchEncoder) NewClaimCheckLocationMessage(origin *common.Message) (*co
 }
 
 func (d *BatchEncoder) appendSingleLargeMessage4ClaimCheck(key, value []byte, e *model.RowChangedEvent, callback func()) {
-	message := newMessage(key, value)
+	message := newMessage(d.batchVersion(), key, value)
 	message.Ts = e.CommitTs
 	message.Schema = &e.Table.Schema
 	message.Table = &e.Table.Table
@@ -291,12 +335,46 @@ func (d *BatchEncoder) appendSingleLargeMessage4ClaimCheck(key, value []byte, e
 	if callback != nil {
 		message.Callback = callback
 	}
-	d.messageBuf = append(d.messageBuf, message)
+	d.appendSingleMessage(message)
+}
+
+func (d *BatchEncoder) appendSingleLargeMessage4Compression(
+	key, value []byte, e *model.RowChangedEvent, callback func(),
+) error {
+	message := newMessage(d.batchVersion(), key, value)
+	originLength := message.Length()
+	codec := d.config.LargeMessageHandle.CompressionCodec()
+	if err := common.CompressLargeMessage(message, codec); err != nil {
+		return errors.Trace(err)
+	}
+	length := message.Length()
+	if length > d.config.MaxMessageBytes {
+		log.Warn("Single message is still too large for open-protocol after compression",
+			zap.Int("maxMessageBytes", d.config.MaxMessageBytes),
+			zap.Int("originLength", originLength),
+			zap.Int("length", length),
+			zap.String("compression", codec),
+			zap.Any("table", e.Table))
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+	}
+	log.Warn("open-protocol: message too large, compress the value",
+		zap.Any("table", e.Table), zap.Uint64("commitTs", e.CommitTs),
+		zap.Int("originLength", originLength), zap.Int("length", length))
+
+	message.Ts = e.CommitTs
+	message.Schema = &e.Table.Schema
+	message.Table = &e.Table.Table
+	message.IncRowsCount()
+	if callback != nil {
+		message.Callback = callback
+	}
+	d.appendSingleMessage(message)
+	return nil
 }
 
-func newMessage(key, value []byte) *common.Message {
+func newMessage(version uint64, key, value []byte) *common.Message {
 	versionHead := make([]byte, 8)
-	binary.BigEndian.PutUint64(versionHead, codec.BatchVersion1)
+	binary.BigEndian.PutUint64(versionHead, version)
 	message := common.NewMsg(config.ProtocolOpen, versionHead, nil, 0, model.MessageTypeRow, nil, nil)
 
 	var (
@@ -16,6 +16,7 @@ package open
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/parser/mysql"
@@ -226,6 +227,20 @@ func TestOpenProtocolBatchCodec(t *testing.T) {
 		})
 }
 
+func TestOpenProtocolBatchCodecV2(t *testing.T) {
+	codecConfig := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(8192)
+	codecConfig.MaxBatchSize = 64
+	codecConfig.OpenProtocolVersion = common.OpenProtocolVersion2
+	tester := internal.NewDefaultBatchTester()
+	tester.TestBatchCodec(t, NewBatchEncoderBuilder(codecConfig),
+		func(key []byte, value []byte) (codec.RowEventDecoder, error) {
+			decoder, err := NewBatchDecoder(context.Background(), codecConfig, nil)
+			require.NoError(t, err)
+			err = decoder.AddKeyValue(key, value)
+			return decoder, err
+		})
+}
+
 func TestAppendClaimCheckMessage(t *testing.T) {
 	t.Parallel()
 
@@ -284,3 +299,77 @@ func TestAppendMessageOnlyHandleKeyColumns(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, batchDecoder.nextKey.OnlyHandleKey)
 }
+
+func TestAppendCompressedLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	topic := ""
+	codecConfig := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(300)
+	codecConfig.LargeMessageHandle.LargeMessageHandleOption = config.LargeMessageHandleOptionCompression
+	codecConfig.LargeMessageHandle.LargeMessageCompression = config.CompressionZstd
+	encoder := NewBatchEncoderBuilder(codecConfig).Build()
+
+	event := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{
+			{
+				Name:  "col1",
+				Type:  mysql.TypeVarchar,
+				Value: []byte(strings.Repeat("a", 1024)),
+				Flag:  model.HandleKeyFlag,
+			},
+		},
+	}
+	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, func() {})
+	require.NoError(t, err)
+	// cannot hold this message, compress it to an individual message.
+	err = encoder.AppendRowChangedEvent(ctx, topic, event, func() {})
+	require.NoError(t, err)
+	// the compressed message is not batched with the following one.
+	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, func() {})
+	require.NoError(t, err)
+
+	messages := encoder.Build()
+	require.Len(t, messages, 3)
+	require.False(t, messages[0].Compressed())
+	require.True(t, messages[1].Compressed())
+	require.False(t, messages[2].Compressed())
+	require.LessOrEqual(t, messages[1].Length(), 300)
+
+	value, err := common.Decompress(config.CompressionZstd, messages[1].Value)
+	require.NoError(t, err)
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(messages[1].Key, value)
+	require.NoError(t, err)
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	decoded, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, event.Columns[0].Value, decoded.Columns[0].Value)
+}
+
+func BenchmarkOpenProtocolBatchEncoder(b *testing.B) {
+	codecConfig := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1048576)
+	codecConfig.MaxBatchSize = 64
+	encoder := NewBatchEncoderBuilder(codecConfig).Build()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < codecConfig.MaxBatchSize; j++ {
+			err := encoder.AppendRowChangedEvent(ctx, "", testEvent, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		for _, m := range encoder.Build() {
+			m.Release()
+		}
+	}
+}
@@ -18,19 +18,27 @@ import (
 	"encoding/json"
 	"sort"
 	"strings"
+	"time"
 
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 type messageRow struct {
 	Update     map[string]internal.Column `json:"u,omitempty"`
 	PreColumns map[string]internal.Column `json:"p,omitempty"`
 	Delete     map[string]internal.Column `json:"d,omitempty"`
+
+	// CommitPhysicalTime and IngestionTime are the unix milliseconds of the
+	// commit-ts and the time the row is encoded, only set if configured.
+	CommitPhysicalTime int64 `json:"cpt,omitempty"`
+	IngestionTime      int64 `json:"it,omitempty"`
 }
 
 func (m *messageRow) encode() ([]byte, error) {
@@ -100,29 +108,21 @@ func rowChangeToMsg(
 	e *model.RowChangedEvent,
 	config *common.Config,
 	largeMessageOnlyHandleKeyColumns bool) (*internal.MessageKey, *messageRow, error) {
-	var partition *int64
-	if e.Table.IsPartition {
-		partition = &e.Table.TableID
-	}
-	key := &internal.MessageKey{
-		Ts:            e.CommitTs,
-		Schema:        e.Table.Schema,
-		Table:         e.Table.Table,
-		RowID:         e.RowID,
-		Partition:     partition,
-		Type:          model.MessageTypeRow,
-		OnlyHandleKey: largeMessageOnlyHandleKeyColumns,
-	}
+	key := newRowMessageKey(e, largeMessageOnlyHandleKeyColumns)
 	value := &messageRow{}
+	if config.OutputPhysicalTime {
+		value.CommitPhysicalTime = oracle.ExtractPhysical(e.CommitTs)
+		value.IngestionTime = time.Now().UnixMilli()
+	}
 	if e.IsDelete() {
 		onlyHandleKeyColumns := config.DeleteOnlyHandleKeyColumns || largeMessageOnlyHandleKeyColumns
-		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns, onlyHandleKeyColumns)
+		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns, onlyHandleKeyColumns, config.TemporalEncoder)
 		if onlyHandleKeyColumns && len(value.Delete) == 0 {
 			return nil, nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found handle key columns for the delete event")
 		}
 	} else if e.IsUpdate() {
-		value.Update = rowChangeColumns2CodecColumns(e.Columns, largeMessageOnlyHandleKeyColumns)
-		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns, largeMessageOnlyHandleKeyColumns)
+		value.Update = rowChangeColumns2CodecColumns(e.Columns, largeMessageOnlyHandleKeyColumns, config.TemporalEncoder)
+		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns, largeMessageOnlyHandleKeyColumns, config.TemporalEncoder)
 		if largeMessageOnlyHandleKeyColumns && (len(value.Update) == 0 || len(value.PreColumns) == 0) {
 			return nil, nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found handle key columns for the update event")
 		}
@@ -131,7 +131,7 @@ func rowChangeToMsg(
 		}
 
 	} else {
-		value.Update = rowChangeColumns2CodecColumns(e.Columns, largeMessageOnlyHandleKeyColumns)
+		value.Update = rowChangeColumns2CodecColumns(e.Columns, largeMessageOnlyHandleKeyColumns, config.TemporalEncoder)
 		if largeMessageOnlyHandleKeyColumns && len(value.Update) == 0 {
 			return nil, nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found handle key columns for the insert event")
 		}
@@ -140,7 +140,34 @@ func rowChangeToMsg(
 	return key, value, nil
 }
 
+func newRowMessageKey(e *model.RowChangedEvent, onlyHandleKey bool) *internal.MessageKey {
+	var partition *int64
+	if e.Table.IsPartition {
+		partition = &e.Table.TableID
+	}
+	return &internal.MessageKey{
+		Ts:            e.CommitTs,
+		Schema:        e.Table.Schema,
+		Table:         e.Table.Table,
+		RowID:         e.RowID,
+		Partition:     partition,
+		Type:          model.MessageTypeRow,
+		OnlyHandleKey: onlyHandleKey,
+	}
+}
+
 func msgToRowChange(key *internal.MessageKey, value *messageRow) *model.RowChangedEvent {
+	e := msgKeyToRowChange(key)
+	if len(value.Delete) != 0 {
+		e.PreColumns = codecColumns2RowChangeColumns(value.Delete)
+	} else {
+		e.Columns = codecColumns2RowChangeColumns(value.Update)
+		e.PreColumns = codecColumns2RowChangeColumns(value.PreColumns)
+	}
+	return e
+}
+
+func msgKeyToRowChange(key *internal.MessageKey) *model.RowChangedEvent {
 	e := new(model.RowChangedEvent)
 	// TODO: we lost the startTs from kafka message
 	// startTs-based txn filter is out of work
@@ -154,17 +181,12 @@ func msgToRowChange(key *internal.MessageKey, value *messageRow) *model.RowChang
 		e.Table.TableID = *key.Partition
 		e.Table.IsPartition = true
 	}
-
-	if len(value.Delete) != 0 {
-		e.PreColumns = codecColumns2RowChangeColumns(value.Delete)
-	} else {
-		e.Columns = codecColumns2RowChangeColumns(value.Update)
-		e.PreColumns = codecColumns2RowChangeColumns(value.PreColumns)
-	}
 	return e
 }
 
-func rowChangeColumns2CodecColumns(cols []*model.Column, onlyHandleKeyColumns bool) map[string]internal.Column {
+func rowChangeColumns2CodecColumns(
+	cols []*model.Column, onlyHandleKeyColumns bool, temporal *common.TemporalEncoder,
+) map[string]internal.Column {
 	jsonCols := make(map[string]internal.Column, len(cols))
 	for _, col := range cols {
 		if col == nil {
@@ -175,6 +197,9 @@ func rowChangeColumns2CodecColumns(cols []*model.Column, onlyHandleKeyColumns bo
 		}
 		c := internal.Column{}
 		c.FromRowChangeColumn(col)
+		if col.Type == mysql.TypeTimestamp || col.Type == mysql.TypeDatetime {
+			c.Value = temporal.Encode(col)
+		}
 		jsonCols[col.Name] = c
 	}
 	if len(jsonCols) == 0 {
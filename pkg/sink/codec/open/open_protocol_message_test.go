@@ -15,14 +15,18 @@ package open
 
 import (
 	"testing"
+	"time"
 
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestFormatCol(t *testing.T) {
@@ -321,3 +325,108 @@ func TestRowChanged2MsgOnlyHandleKeyColumns(t *testing.T) {
 	_, _, err = rowChangeToMsg(deleteEventNoHandleKey, config, true)
 	require.Error(t, err, cerror.ErrOpenProtocolCodecInvalidData)
 }
+
+func TestRowChanged2MsgWithPhysicalTime(t *testing.T) {
+	t.Parallel()
+
+	insertEvent := &model.RowChangedEvent{
+		CommitTs: 417318403368288260,
+		Table: &model.TableName{
+			Schema: "schema",
+			Table:  "table",
+		},
+		Columns: []*model.Column{
+			{Name: "id", Flag: model.HandleKeyFlag, Type: mysql.TypeLonglong, Value: 1},
+		},
+	}
+
+	config := common.NewConfig(config.ProtocolOpen)
+	_, value, err := rowChangeToMsg(insertEvent, config, false)
+	require.NoError(t, err)
+	data, err := value.encode()
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"cpt"`)
+	require.NotContains(t, string(data), `"it"`)
+
+	config.OutputPhysicalTime = true
+	before := time.Now().UnixMilli()
+	_, value, err = rowChangeToMsg(insertEvent, config, false)
+	require.NoError(t, err)
+	data, err = value.encode()
+	require.NoError(t, err)
+
+	decoded := new(messageRow)
+	require.NoError(t, decoded.decode(data))
+	require.Equal(t, oracle.ExtractPhysical(insertEvent.CommitTs), decoded.CommitPhysicalTime)
+	require.GreaterOrEqual(t, decoded.IngestionTime, before)
+	require.LessOrEqual(t, decoded.IngestionTime, time.Now().UnixMilli())
+}
+
+func TestRowChanged2MsgV2(t *testing.T) {
+	t.Parallel()
+
+	nameType := types.NewFieldType(mysql.TypeVarchar)
+	nameType.SetFlen(32)
+	nameType.SetCharset("utf8mb4")
+	nameType.SetCollate("utf8mb4_bin")
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			{Name: timodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+			{Name: timodel.NewCIStr("name"), FieldType: *nameType},
+		},
+	}}
+	updateEvent := &model.RowChangedEvent{
+		CommitTs:  1,
+		Table:     &model.TableName{Schema: "test", Table: "t"},
+		TableInfo: tableInfo,
+		PreColumns: []*model.Column{
+			{Name: "id", Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Charset: "utf8mb4", Value: []byte("a")},
+		},
+		Columns: []*model.Column{
+			{Name: "id", Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Charset: "utf8mb4", Value: []byte("b")},
+		},
+	}
+
+	codecConfig := common.NewConfig(config.ProtocolOpen)
+	// the full old values are carried even if only the updated columns are required.
+	codecConfig.OnlyOutputUpdatedColumns = true
+	key, value, err := rowChangeToMsgV2(updateEvent, codecConfig, false)
+	require.NoError(t, err)
+	require.Equal(t, operationUpdate, value.Operation)
+	require.Equal(t, []columnSchema{
+		{Name: "id", Type: mysql.TypeLong, ColumnType: "int(11)", Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "name", Type: mysql.TypeVarchar, ColumnType: "varchar(32)", Charset: "utf8mb4", Collation: "utf8mb4_bin"},
+	}, value.Columns)
+	require.Equal(t, map[string]any{"id": int64(1), "name": "a"}, value.Old)
+	require.Equal(t, map[string]any{"id": int64(1), "name": "b"}, value.Data)
+
+	data, err := value.encode()
+	require.NoError(t, err)
+	decoded := new(messageRowV2)
+	require.NoError(t, decoded.decode(data))
+	event := msgV2ToRowChange(key, decoded)
+	require.Equal(t, updateEvent.PreColumns, event.PreColumns)
+	require.Equal(t, updateEvent.Columns, event.Columns)
+
+	// only the handle key columns are encoded for the large message.
+	_, value, err = rowChangeToMsgV2(updateEvent, codecConfig, true)
+	require.NoError(t, err)
+	require.Len(t, value.Columns, 1)
+	require.Equal(t, map[string]any{"id": int64(1)}, value.Old)
+	require.Equal(t, map[string]any{"id": int64(1)}, value.Data)
+
+	deleteEvent := &model.RowChangedEvent{
+		CommitTs:   1,
+		Table:      &model.TableName{Schema: "test", Table: "t"},
+		PreColumns: updateEvent.PreColumns,
+	}
+	_, value, err = rowChangeToMsgV2(deleteEvent, codecConfig, false)
+	require.NoError(t, err)
+	require.Equal(t, operationDelete, value.Operation)
+	// the type is derived from the column without the table info.
+	require.Equal(t, "varchar", value.Columns[1].ColumnType)
+	require.Nil(t, value.Data)
+	require.Len(t, value.Old, 2)
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package open
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+const (
+	operationInsert = "i"
+	operationUpdate = "u"
+	operationDelete = "d"
+)
+
+// columnSchema describes a column of the version 2 row messages, so the
+// consumers can interpret the values without knowing the table schema.
+type columnSchema struct {
+	Name string `json:"n"`
+	Type byte   `json:"t"`
+	// ColumnType is the full type of the column, such as "varchar(32)".
+	ColumnType string               `json:"ct"`
+	Charset    string               `json:"cs,omitempty"`
+	Collation  string               `json:"co,omitempty"`
+	Flag       model.ColumnFlagType `json:"f"`
+}
+
+// messageRowV2 is the value of the version 2 row messages. The columns are
+// ordered as they're in the table, the values of the new row are in Data and
+// the values of the old row are in Old, both are keyed by the column names.
+type messageRowV2 struct {
+	Operation string         `json:"op"`
+	Columns   []columnSchema `json:"c"`
+	Data      map[string]any `json:"d,omitempty"`
+	Old       map[string]any `json:"o,omitempty"`
+
+	CommitPhysicalTime int64 `json:"cpt,omitempty"`
+	IngestionTime      int64 `json:"it,omitempty"`
+}
+
+func (m *messageRowV2) encode() ([]byte, error) {
+	data, err := json.Marshal(m)
+	return data, cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+func (m *messageRowV2) decode(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return cerror.WrapError(cerror.ErrUnmarshalFailed, decoder.Decode(m))
+}
+
+// rowChangeToMsgV2 converts the row into a version 2 message, the full old
+// values are always carried for the update and delete events.
+func rowChangeToMsgV2(
+	e *model.RowChangedEvent,
+	config *common.Config,
+	largeMessageOnlyHandleKeyColumns bool,
+) (*internal.MessageKey, *messageRowV2, error) {
+	key := newRowMessageKey(e, largeMessageOnlyHandleKeyColumns)
+	value := &messageRowV2{}
+	if config.OutputPhysicalTime {
+		value.CommitPhysicalTime = oracle.ExtractPhysical(e.CommitTs)
+		value.IngestionTime = time.Now().UnixMilli()
+	}
+
+	onlyHandleKeyColumns := largeMessageOnlyHandleKeyColumns
+	columns := e.Columns
+	switch {
+	case e.IsDelete():
+		value.Operation = operationDelete
+		onlyHandleKeyColumns = onlyHandleKeyColumns || config.DeleteOnlyHandleKeyColumns
+		columns = e.PreColumns
+	case e.IsUpdate():
+		value.Operation = operationUpdate
+	default:
+		value.Operation = operationInsert
+	}
+
+	for _, col := range columns {
+		if col == nil || (onlyHandleKeyColumns && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		value.Columns = append(value.Columns, newColumnSchema(e.TableInfo, col))
+	}
+	if len(value.Columns) == 0 && onlyHandleKeyColumns {
+		return nil, nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack(
+			"not found handle key columns for the %s event", eventTypeName(value.Operation))
+	}
+	if value.Operation != operationDelete {
+		value.Data = columnValues(e.Columns, onlyHandleKeyColumns, config.TemporalEncoder)
+	}
+	if value.Operation != operationInsert {
+		value.Old = columnValues(e.PreColumns, onlyHandleKeyColumns, config.TemporalEncoder)
+	}
+	return key, value, nil
+}
+
+func newColumnSchema(tableInfo *model.TableInfo, col *model.Column) columnSchema {
+	schema := columnSchema{
+		Name:    col.Name,
+		Type:    col.Type,
+		Charset: col.Charset,
+		Flag:    col.Flag,
+	}
+	var colInfo *timodel.ColumnInfo
+	if tableInfo != nil && tableInfo.TableInfo != nil {
+		colInfo = timodel.FindColumnInfo(tableInfo.Columns, col.Name)
+	}
+	if colInfo == nil {
+		schema.ColumnType = types.TypeStr(col.Type)
+		if col.Flag.IsUnsigned() {
+			schema.ColumnType += " unsigned"
+		}
+		return schema
+	}
+	schema.ColumnType = colInfo.FieldType.InfoSchemaStr()
+	schema.Charset = colInfo.GetCharset()
+	schema.Collation = colInfo.GetCollate()
+	return schema
+}
+
+func columnValues(
+	cols []*model.Column, onlyHandleKeyColumns bool, temporal *common.TemporalEncoder,
+) map[string]any {
+	values := make(map[string]any, len(cols))
+	for _, col := range cols {
+		if col == nil || (onlyHandleKeyColumns && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		c := internal.Column{}
+		c.FromRowChangeColumn(col)
+		if col.Type == mysql.TypeTimestamp || col.Type == mysql.TypeDatetime {
+			c.Value = temporal.Encode(col)
+		}
+		values[col.Name] = c.Value
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func msgV2ToRowChange(key *internal.MessageKey, value *messageRowV2) *model.RowChangedEvent {
+	e := msgKeyToRowChange(key)
+	if value.Operation != operationDelete {
+		e.Columns = columnValues2RowChangeColumns(value.Columns, value.Data)
+	}
+	if value.Operation != operationInsert {
+		e.PreColumns = columnValues2RowChangeColumns(value.Columns, value.Old)
+	}
+	return e
+}
+
+func columnValues2RowChangeColumns(schemas []columnSchema, values map[string]any) []*model.Column {
+	if len(values) == 0 {
+		return nil
+	}
+	cols := make([]*model.Column, 0, len(schemas))
+	for _, schema := range schemas {
+		value, ok := values[schema.Name]
+		if !ok {
+			continue
+		}
+		c := internal.FormatColumn(internal.Column{
+			Type:  schema.Type,
+			Flag:  schema.Flag,
+			Value: value,
+		})
+		col := c.ToRowChangeColumn(schema.Name)
+		col.Charset = schema.Charset
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+func eventTypeName(operation string) string {
+	switch operation {
+	case operationDelete:
+		return "delete"
+	case operationUpdate:
+		return "update"
+	default:
+		return "insert"
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+)
+
+// A parquet file can't be appended once it's closed, so the rows of a transaction
+// are encoded to an intermediate format by the BatchEncoder, and all the rows of a
+// data file are written to the parquet file by the FileEncoder at once.
+//
+// Each row in the intermediate format starts with the uvarint number of the
+// columns, which are composed as follows:
+// Col1: The operation-type indicator: I, D, U.
+// Col2: Table name, the name of the source table.
+// Col3: Schema name, the name of the source schema.
+// Col4: Commit TS, the commit-ts of the source txn.
+// Col5-n: one or more columns that represent the data to be changed.
+// Every column is a null flag byte followed by the uvarint length and the bytes
+// of the value if it's not null.
+
+const (
+	nullFlag    byte = 0
+	notNullFlag byte = 1
+
+	// metaColumnsCnt is the number of the columns before the data columns.
+	metaColumnsCnt = 4
+)
+
+// BatchEncoder encodes the events into the intermediate format of parquet rows.
+type BatchEncoder struct {
+	valueBuf  *bytes.Buffer
+	callback  func()
+	batchSize int
+	config    *common.Config
+}
+
+// AppendTxnEvent implements the TxnEventEncoder interface
+func (b *BatchEncoder) AppendTxnEvent(
+	e *model.SingleTableTxn,
+	callback func(),
+) error {
+	for _, rowEvent := range e.Rows {
+		if err := b.appendRow(rowEvent); err != nil {
+			return err
+		}
+		b.batchSize++
+	}
+	b.callback = callback
+	return nil
+}
+
+func (b *BatchEncoder) appendRow(e *model.RowChangedEvent) error {
+	op, columns := "I", e.Columns
+	if e.IsDelete() {
+		op, columns = "D", e.PreColumns
+	} else if e.IsUpdate() {
+		op = "U"
+	}
+
+	b.appendUvarint(uint64(len(columns) + metaColumnsCnt))
+	b.appendValue([]byte(op))
+	b.appendValue([]byte(e.Table.Table))
+	b.appendValue([]byte(e.Table.Schema))
+	b.appendValue([]byte(strconv.FormatUint(e.CommitTs, 10)))
+	for i, col := range columns {
+		var ft *types.FieldType
+		if i < len(e.ColInfos) {
+			ft = e.ColInfos[i].Ft
+		}
+		value, err := formatColumnValue(col, ft)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+		}
+		b.appendValue(value)
+	}
+	return nil
+}
+
+func (b *BatchEncoder) appendValue(value []byte) {
+	if value == nil {
+		b.valueBuf.WriteByte(nullFlag)
+		return
+	}
+	b.valueBuf.WriteByte(notNullFlag)
+	b.appendUvarint(uint64(len(value)))
+	b.valueBuf.Write(value)
+}
+
+func (b *BatchEncoder) appendUvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	b.valueBuf.Write(buf[:n])
+}
+
+// formatColumnValue converts the column value to bytes, the result is nil
+// if the value is NULL.
+func formatColumnValue(col *model.Column, ft *types.FieldType) ([]byte, error) {
+	if col == nil || col.Value == nil {
+		return nil, nil
+	}
+
+	switch v := col.Value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'g', -1, 64), nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case uint64:
+		switch col.Type {
+		case mysql.TypeEnum:
+			if ft == nil {
+				break
+			}
+			enumVar, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(enumVar.Name), nil
+		case mysql.TypeSet:
+			if ft == nil {
+				break
+			}
+			setVar, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(setVar.Name), nil
+		}
+		return strconv.AppendUint(nil, v, 10), nil
+	default:
+		return []byte(model.ColumnValueString(col.Value)), nil
+	}
+}
+
+// Build implements the TxnEventEncoder interface
+func (b *BatchEncoder) Build() (messages []*common.Message) {
+	if b.batchSize == 0 {
+		return nil
+	}
+
+	ret := common.NewMsg(config.ProtocolParquet, nil,
+		b.valueBuf.Bytes(), 0, model.MessageTypeRow, nil, nil)
+	ret.SetRowsCount(b.batchSize)
+	ret.Callback = b.callback
+	b.valueBuf = &bytes.Buffer{}
+	b.callback = nil
+	b.batchSize = 0
+
+	return []*common.Message{ret}
+}
+
+// newBatchEncoder creates a new parquet BatchEncoder.
+func newBatchEncoder(config *common.Config) codec.TxnEventEncoder {
+	return &BatchEncoder{
+		config:   config,
+		valueBuf: &bytes.Buffer{},
+	}
+}
+
+type batchEncoderBuilder struct {
+	config *common.Config
+}
+
+// NewTxnEventEncoderBuilder creates a parquet batchEncoderBuilder.
+func NewTxnEventEncoderBuilder(config *common.Config) codec.TxnEventEncoderBuilder {
+	return &batchEncoderBuilder{config: config}
+}
+
+// Build a parquet BatchEncoder
+func (b *batchEncoderBuilder) Build() codec.TxnEventEncoder {
+	return newBatchEncoder(b.config)
+}
@@ -0,0 +1,297 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	pcommon "github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// The names of the meta columns in the parquet files, the order is the same as
+// the one in the intermediate format, see BatchEncoder for details.
+const (
+	columnOperationType = "_tidb_op"
+	columnTableName     = "_tidb_table"
+	columnSchemaName    = "_tidb_schema"
+	columnCommitTs      = "_tidb_commit_ts"
+)
+
+const (
+	parquetRootName = "Parquet_go_root"
+	// parquetPageSize is the size of the data pages in a column chunk.
+	parquetPageSize = 8 * 1024
+)
+
+// column is a column in the parquet file.
+type column struct {
+	tag   *pcommon.Tag
+	parse func(value []byte) (interface{}, error)
+}
+
+// FileEncoder writes the rows encoded by the BatchEncoder to parquet files.
+type FileEncoder struct {
+	config       *common.Config
+	compression  parquet.CompressionCodec
+	rowGroupSize int64
+}
+
+// NewFileEncoder creates a new parquet FileEncoder.
+func NewFileEncoder(
+	codecConfig *common.Config, compression string, rowGroupSize int,
+) (*FileEncoder, error) {
+	e := &FileEncoder{config: codecConfig, rowGroupSize: int64(rowGroupSize)}
+	switch compression {
+	case config.ParquetCompressionNone:
+		e.compression = parquet.CompressionCodec_UNCOMPRESSED
+	case config.ParquetCompressionSnappy, "":
+		e.compression = parquet.CompressionCodec_SNAPPY
+	case config.ParquetCompressionZstd:
+		e.compression = parquet.CompressionCodec_ZSTD
+	default:
+		return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+			"unsupported parquet compression %s", compression)
+	}
+	return e, nil
+}
+
+// Encode returns the content of a parquet file which contains all the rows
+// in the given messages.
+func (e *FileEncoder) Encode(
+	tableInfo *model.TableInfo, msgs []*common.Message,
+) ([]byte, error) {
+	columns := newColumns(tableInfo, e.config.ComputedColumns(
+		tableInfo.TableName.Schema, tableInfo.TableName.Table))
+
+	buf := new(bytes.Buffer)
+	pw, err := writer.NewCSVWriterFromWriter(nil, buf, 1)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	pw.SchemaHandler = newSchemaHandler(columns)
+	pw.Footer.Schema = append(pw.Footer.Schema[:0], pw.SchemaHandler.SchemaElements...)
+	pw.PageSize = parquetPageSize
+	pw.RowGroupSize = e.rowGroupSize
+	pw.CompressionType = e.compression
+
+	for _, msg := range msgs {
+		data := msg.Value
+		for len(data) > 0 {
+			var row []interface{}
+			row, data, err = decodeRow(data, columns)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+			if err = pw.Write(row); err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+		}
+	}
+	if err = pw.WriteStop(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeRow decodes a row in the intermediate format and returns the remaining data.
+func decodeRow(data []byte, columns []*column) ([]interface{}, []byte, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, nil, errors.New("the row is corrupted")
+	}
+	if count != uint64(len(columns)) {
+		return nil, nil, errors.Errorf(
+			"the row has %d columns, but the table has %d columns", count, len(columns))
+	}
+	data = data[n:]
+
+	row := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		if len(data) == 0 {
+			return nil, nil, errors.New("the row is corrupted")
+		}
+		flag := data[0]
+		data = data[1:]
+		if flag == nullFlag {
+			row = append(row, nil)
+			continue
+		}
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return nil, nil, errors.New("the row is corrupted")
+		}
+		value, err := col.parse(data[n : n+int(length)])
+		if err != nil {
+			return nil, nil, errors.Annotatef(err,
+				"failed to parse the value of column %s", col.tag.ExName)
+		}
+		row = append(row, value)
+		data = data[n+int(length):]
+	}
+	return row, data, nil
+}
+
+// Field is a column of the parquet files, all the columns are optional.
+type Field struct {
+	Name string
+	// Type is the physical type of the column.
+	Type parquet.Type
+	// IsString is true if the column is a byte array annotated as UTF8.
+	IsString bool
+}
+
+// Fields returns the columns of the parquet files of the given table in the
+// order they're written, the meta columns and the computed columns are included.
+func Fields(tableInfo *model.TableInfo, computedColumns []string) []Field {
+	columns := newColumns(tableInfo, computedColumns)
+	fields := make([]Field, 0, len(columns))
+	for _, col := range columns {
+		typ, _ := parquet.TypeFromString(col.tag.Type)
+		fields = append(fields, Field{
+			Name:     col.tag.ExName,
+			Type:     typ,
+			IsString: col.tag.ConvertedType == parquet.ConvertedType_UTF8.String(),
+		})
+	}
+	return fields
+}
+
+// newColumns returns the parquet columns of the given table, all the columns
+// are optional since the values may be NULL. The computed columns are strings
+// appended after the columns of the table.
+func newColumns(tableInfo *model.TableInfo, computedColumns []string) []*column {
+	columns := []*column{
+		newColumn(columnOperationType, parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8),
+		newColumn(columnTableName, parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8),
+		newColumn(columnSchemaName, parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8),
+		newColumn(columnCommitTs, parquet.Type_INT64, -1),
+	}
+	for _, col := range tableInfo.Columns {
+		typ, convertedType := getParquetType(&col.FieldType)
+		columns = append(columns, newColumn(col.Name.O, typ, convertedType))
+	}
+	for _, name := range computedColumns {
+		columns = append(columns, newColumn(name, parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8))
+	}
+	return columns
+}
+
+// getParquetType returns the parquet type of the TiDB column type, -1 is returned
+// as the converted type if there is no converted type. The types which can't be
+// represented by the parquet primitive types losslessly are written as strings.
+func getParquetType(ft *types.FieldType) (parquet.Type, parquet.ConvertedType) {
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear:
+		return parquet.Type_INT64, -1
+	case mysql.TypeLonglong:
+		if mysql.HasUnsignedFlag(ft.GetFlag()) {
+			return parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8
+		}
+		return parquet.Type_INT64, -1
+	case mysql.TypeBit:
+		if ft.GetFlen() < 64 {
+			return parquet.Type_INT64, -1
+		}
+		return parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8
+	case mysql.TypeFloat:
+		return parquet.Type_FLOAT, -1
+	case mysql.TypeDouble:
+		return parquet.Type_DOUBLE, -1
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
+		mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if ft.GetCharset() == charset.CharsetBin {
+			return parquet.Type_BYTE_ARRAY, -1
+		}
+		return parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8
+	default:
+		return parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8
+	}
+}
+
+func newColumn(name string, typ parquet.Type, convertedType parquet.ConvertedType) *column {
+	tag := pcommon.NewTag()
+	tag.InName = pcommon.StringToVariableName(name)
+	tag.ExName = name
+	tag.Type = typ.String()
+	if convertedType >= 0 {
+		tag.ConvertedType = convertedType.String()
+	}
+	tag.RepetitionType = parquet.FieldRepetitionType_OPTIONAL
+
+	col := &column{tag: tag}
+	switch typ {
+	case parquet.Type_INT64:
+		col.parse = func(value []byte) (interface{}, error) {
+			return strconv.ParseInt(string(value), 10, 64)
+		}
+	case parquet.Type_FLOAT:
+		col.parse = func(value []byte) (interface{}, error) {
+			v, err := strconv.ParseFloat(string(value), 32)
+			return float32(v), err
+		}
+	case parquet.Type_DOUBLE:
+		col.parse = func(value []byte) (interface{}, error) {
+			return strconv.ParseFloat(string(value), 64)
+		}
+	default:
+		col.parse = func(value []byte) (interface{}, error) {
+			return string(value), nil
+		}
+	}
+	return col
+}
+
+// newSchemaHandler creates the schema handler of the parquet file. The tags are
+// built directly instead of parsing the metadata strings, since the column names
+// may contain the characters which are used as separators in the metadata.
+func newSchemaHandler(columns []*column) *schema.SchemaHandler {
+	schemaList := make([]*parquet.SchemaElement, 0, len(columns)+1)
+	infos := make([]*pcommon.Tag, 0, len(columns)+1)
+
+	root := parquet.NewSchemaElement()
+	root.Name = parquetRootName
+	numChildren := int32(len(columns))
+	root.NumChildren = &numChildren
+	rootRepetition := parquet.FieldRepetitionType_REQUIRED
+	root.RepetitionType = &rootRepetition
+	schemaList = append(schemaList, root)
+
+	rootTag := pcommon.NewTag()
+	rootTag.InName = parquetRootName
+	rootTag.ExName = "parquet_go_root"
+	rootTag.RepetitionType = parquet.FieldRepetitionType_REQUIRED
+	infos = append(infos, rootTag)
+
+	for _, col := range columns {
+		infos = append(infos, col.tag)
+		schemaList = append(schemaList, pcommon.NewSchemaElementFromTagMap(col.tag))
+	}
+	handler := schema.NewSchemaHandlerFromSchemaList(schemaList)
+	handler.Infos = infos
+	handler.CreateInExMap()
+	return handler
+}
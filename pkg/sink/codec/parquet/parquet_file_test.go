@@ -0,0 +1,236 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func newTestTable() (*model.TableInfo, []rowcodec.ColInfo) {
+	idType := types.NewFieldType(mysql.TypeLonglong)
+	nameType := types.NewFieldType(mysql.TypeVarchar)
+	nameType.SetCharset(mysql.DefaultCharset)
+	dataType := types.NewFieldType(mysql.TypeBlob)
+	dataType.SetCharset(charset.CharsetBin)
+	priceType := types.NewFieldType(mysql.TypeDouble)
+	colorType := types.NewFieldType(mysql.TypeEnum)
+	colorType.SetElems([]string{"red", "green"})
+
+	fieldTypes := []*types.FieldType{idType, nameType, dataType, priceType, colorType}
+	names := []string{"id", "name", "data", "price,usd", "color"}
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{}}
+	colInfos := make([]rowcodec.ColInfo, 0, len(names))
+	for i, name := range names {
+		tableInfo.Columns = append(tableInfo.Columns, &timodel.ColumnInfo{
+			ID:        int64(i + 1),
+			Name:      timodel.NewCIStr(name),
+			FieldType: *fieldTypes[i],
+		})
+		colInfos = append(colInfos, rowcodec.ColInfo{ID: int64(i + 1), Ft: fieldTypes[i]})
+	}
+	return tableInfo, colInfos
+}
+
+func TestEncodeParquetFile(t *testing.T) {
+	t.Parallel()
+
+	tableInfo, colInfos := newTestTable()
+	table := &model.TableName{Schema: "test", Table: "t"}
+	newColumns := func(id int64, name string, data []byte, price float64, color uint64) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: id},
+			{Name: "name", Type: mysql.TypeVarchar, Value: name},
+			{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: data},
+			{Name: "price,usd", Type: mysql.TypeDouble, Value: price},
+			{Name: "color", Type: mysql.TypeEnum, Value: color},
+		}
+	}
+	insert := &model.RowChangedEvent{
+		CommitTs: 1, Table: table, ColInfos: colInfos,
+		Columns: newColumns(1, "a", []byte{0xff, 0x00}, 1.5, 1),
+	}
+	update := &model.RowChangedEvent{
+		CommitTs: 2, Table: table, ColInfos: colInfos,
+		PreColumns: newColumns(1, "a", []byte{0xff, 0x00}, 1.5, 1),
+		Columns:    newColumns(1, "b", nil, 2.5, 2),
+	}
+	update.Columns[2].Value = nil
+	deleteRow := &model.RowChangedEvent{
+		CommitTs: 3, Table: table, ColInfos: colInfos,
+		PreColumns: newColumns(1, "b", []byte("x"), 2.5, 2),
+	}
+
+	encoder := NewTxnEventEncoderBuilder(common.NewConfig(config.ProtocolParquet)).Build()
+	var msgs []*common.Message
+	for _, rows := range [][]*model.RowChangedEvent{{insert, update}, {deleteRow}} {
+		err := encoder.AppendTxnEvent(&model.SingleTableTxn{Table: table, Rows: rows}, nil)
+		require.NoError(t, err)
+		built := encoder.Build()
+		require.Len(t, built, 1)
+		require.Equal(t, len(rows), built[0].GetRowsCount())
+		msgs = append(msgs, built...)
+	}
+	require.Nil(t, encoder.Build())
+
+	for _, compression := range []string{
+		config.ParquetCompressionNone,
+		config.ParquetCompressionSnappy,
+		config.ParquetCompressionZstd,
+	} {
+		fileEncoder, err := NewFileEncoder(common.NewConfig(config.ProtocolParquet), compression, 1024*1024)
+		require.NoError(t, err)
+		data, err := fileEncoder.Encode(tableInfo, msgs)
+		require.NoError(t, err)
+
+		file, err := buffer.NewBufferFile(data)
+		require.NoError(t, err)
+		pr, err := reader.NewParquetColumnReader(file, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), pr.GetNumRows())
+		require.Equal(t, []string{
+			"_tidb_op", "_tidb_table", "_tidb_schema", "_tidb_commit_ts",
+			"id", "name", "data", "price,usd", "color",
+		}, exNames(pr))
+
+		expected := [][]interface{}{
+			{"I", "U", "D"},
+			{"t", "t", "t"},
+			{"test", "test", "test"},
+			{int64(1), int64(2), int64(3)},
+			{int64(1), int64(1), int64(1)},
+			{"a", "b", "b"},
+			{string([]byte{0xff, 0x00}), nil, "x"},
+			{1.5, 2.5, 2.5},
+			{"red", "green", "green"},
+		}
+		for i, values := range expected {
+			actual, _, _, err := pr.ReadColumnByIndex(int64(i), 3)
+			require.NoError(t, err)
+			require.Equal(t, values, actual, "column %d", i)
+		}
+		pr.ReadStop()
+	}
+
+	_, err := NewFileEncoder(common.NewConfig(config.ProtocolParquet), "lz4", 1024*1024)
+	require.ErrorContains(t, err, "unsupported parquet compression")
+
+	// the rows don't match the table.
+	fileEncoder, err := NewFileEncoder(common.NewConfig(config.ProtocolParquet), config.ParquetCompressionSnappy, 1024*1024)
+	require.NoError(t, err)
+	tableInfo.Columns = append(tableInfo.Columns, &timodel.ColumnInfo{
+		ID:        6,
+		Name:      timodel.NewCIStr("extra"),
+		FieldType: *types.NewFieldType(mysql.TypeLong),
+	})
+	_, err = fileEncoder.Encode(tableInfo, msgs)
+	require.ErrorContains(t, err, "the row has 9 columns, but the table has 10 columns")
+}
+
+func TestEncodeParquetFileWithComputedColumns(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.ComputedColumns = []*config.ComputedColumn{
+		{Matcher: []string{"test.*"}, Name: "region", Value: "us-west-1"},
+	}
+	sinkURI, err := url.Parse("s3://bucket/prefix?protocol=parquet")
+	require.NoError(t, err)
+	codecConfig := common.NewConfig(config.ProtocolParquet)
+	require.NoError(t, codecConfig.Apply(sinkURI, replicaConfig))
+	appender, err := filter.NewColumnAppender(replicaConfig, "default", model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+
+	tableInfo, colInfos := newTestTable()
+	tableInfo.TableName = model.TableName{Schema: "test", Table: "t"}
+	row := &model.RowChangedEvent{
+		CommitTs: 1, Table: &tableInfo.TableName, ColInfos: colInfos,
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Value: "a"},
+			{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: nil},
+			{Name: "price,usd", Type: mysql.TypeDouble, Value: 1.5},
+			{Name: "color", Type: mysql.TypeEnum, Value: uint64(1)},
+		},
+	}
+	require.NoError(t, appender.AppendRowChangedEvent(row))
+
+	encoder := NewTxnEventEncoderBuilder(codecConfig).Build()
+	err = encoder.AppendTxnEvent(&model.SingleTableTxn{
+		Table: row.Table, Rows: []*model.RowChangedEvent{row},
+	}, nil)
+	require.NoError(t, err)
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+
+	fileEncoder, err := NewFileEncoder(codecConfig, config.ParquetCompressionSnappy, 1024*1024)
+	require.NoError(t, err)
+	data, err := fileEncoder.Encode(tableInfo, msgs)
+	require.NoError(t, err)
+	file, err := buffer.NewBufferFile(data)
+	require.NoError(t, err)
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+	require.Equal(t, []string{
+		"_tidb_op", "_tidb_table", "_tidb_schema", "_tidb_commit_ts",
+		"id", "name", "data", "price,usd", "color", "region",
+	}, exNames(pr))
+	values, _, _, err := pr.ReadColumnByIndex(9, 1)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"us-west-1"}, values)
+
+	fields := Fields(tableInfo, codecConfig.ComputedColumns("test", "t"))
+	require.Equal(t, Field{Name: "region", Type: parquet.Type_BYTE_ARRAY, IsString: true},
+		fields[len(fields)-1])
+}
+
+func exNames(pr *reader.ParquetReader) []string {
+	names := make([]string, 0, len(pr.SchemaHandler.ValueColumns))
+	for i := 1; i < len(pr.SchemaHandler.Infos); i++ {
+		names = append(names, pr.SchemaHandler.Infos[i].ExName)
+	}
+	return names
+}
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	tableInfo, _ := newTestTable()
+	require.Equal(t, []Field{
+		{Name: columnOperationType, Type: parquet.Type_BYTE_ARRAY, IsString: true},
+		{Name: columnTableName, Type: parquet.Type_BYTE_ARRAY, IsString: true},
+		{Name: columnSchemaName, Type: parquet.Type_BYTE_ARRAY, IsString: true},
+		{Name: columnCommitTs, Type: parquet.Type_INT64},
+		{Name: "id", Type: parquet.Type_INT64},
+		{Name: "name", Type: parquet.Type_BYTE_ARRAY, IsString: true},
+		{Name: "data", Type: parquet.Type_BYTE_ARRAY},
+		{Name: "price,usd", Type: parquet.Type_DOUBLE},
+		{Name: "color", Type: parquet.Type_BYTE_ARRAY, IsString: true},
+	}, Fields(tableInfo, nil))
+}
@@ -0,0 +1,386 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
+	"github.com/pingcap/tiflow/pkg/uuid"
+	pparquet "github.com/xitongsys/parquet-go/parquet"
+	"go.uber.org/zap"
+)
+
+const (
+	// deltaLogDir is the directory of the transaction log in the table root.
+	deltaLogDir = "_delta_log"
+	// commitFileNameFormat is the name of the commit file of a table version.
+	commitFileNameFormat = "%020d.json"
+	// maxCommitRetries is the max number of retries if the commit file of
+	// the version has been written by another writer.
+	maxCommitRetries = 10
+
+	minReaderVersion = 1
+	minWriterVersion = 2
+	engineInfo       = "TiCDC"
+)
+
+var commitFileRE = regexp.MustCompile(`^(\d{20})\.json$`)
+
+// Committer commits the parquet data files written by the cloud storage sink
+// to the transaction logs of Delta Lake tables, so the readers see the data
+// files of a flush atomically as a table version.
+//
+// The root of the Delta Lake table is the directory of the table in the sink,
+// which is {schema}/{table}, and the transaction log is in {schema}/{table}/_delta_log.
+// The metadata of the table is committed again if the schema of the table changes.
+// The commits of a table are serialized in the committer, it relies on the fact
+// that a table is replicated by only one capture at the same time.
+type Committer struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	uuid         uuid.Generator
+	// codecConfig is the config of the parquet encoder, the computed columns
+	// of it are in the schemas of the tables.
+	codecConfig *common.Config
+
+	mu     sync.Mutex
+	tables map[string]*tableState
+}
+
+// tableState is the state of the transaction log of a table.
+type tableState struct {
+	// version is the version of the last commit, it's -1 if the table
+	// hasn't been created.
+	version int64
+	// id and schemaString are the ones in the last metadata action.
+	id           string
+	schemaString string
+}
+
+// NewCommitter creates a Committer which writes the transaction logs to the
+// external storage of the cloud storage sink.
+func NewCommitter(
+	changefeedID model.ChangeFeedID, storage storage.ExternalStorage,
+	codecConfig *common.Config,
+) *Committer {
+	return &Committer{
+		changefeedID: changefeedID,
+		storage:      storage,
+		uuid:         uuid.NewGenerator(),
+		codecConfig:  codecConfig,
+		tables:       make(map[string]*tableState),
+	}
+}
+
+// Load commits the data file of the table as a new version of the Delta Lake
+// table, filePath is the path of the data file relative to the sink URI and size
+// is the size of it. It returns after the commit file is written.
+func (c *Committer) Load(
+	ctx context.Context, tableInfo *model.TableInfo, filePath string, size int64,
+) error {
+	root := path.Join(tableInfo.TableName.Schema, tableInfo.TableName.Table)
+	relPath := strings.TrimPrefix(path.Clean(filePath), root+"/")
+	if relPath == path.Clean(filePath) {
+		return errors.Errorf("data file %s is not in the table directory %s", filePath, root)
+	}
+	schemaString, err := buildSchemaString(tableInfo, c.codecConfig.ComputedColumns(
+		tableInfo.TableName.Schema, tableInfo.TableName.Table))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < maxCommitRetries; i++ {
+		state, err := c.getTableState(ctx, root)
+		if err != nil {
+			return err
+		}
+		version := state.version + 1
+		commitFile := path.Join(root, deltaLogDir, fmt.Sprintf(commitFileNameFormat, version))
+		exist, err := c.storage.FileExists(ctx, commitFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if exist {
+			// the version is committed by another writer, reload the state.
+			log.Warn("delta lake commit file already exists, retry the commit",
+				zap.String("namespace", c.changefeedID.Namespace),
+				zap.String("changefeed", c.changefeedID.ID),
+				zap.String("path", commitFile))
+			delete(c.tables, root)
+			continue
+		}
+
+		id := state.id
+		if id == "" {
+			id = c.uuid.NewString()
+		}
+		now := time.Now().UnixMilli()
+		var actions []interface{}
+		if state.version < 0 {
+			actions = append(actions, map[string]interface{}{
+				"protocol": protocolAction{
+					MinReaderVersion: minReaderVersion,
+					MinWriterVersion: minWriterVersion,
+				},
+			})
+		}
+		if state.schemaString != schemaString {
+			actions = append(actions, map[string]interface{}{
+				"metaData": metadataAction{
+					ID:               id,
+					Format:           formatAction{Provider: "parquet", Options: map[string]string{}},
+					SchemaString:     schemaString,
+					PartitionColumns: []string{},
+					Configuration:    map[string]string{},
+					CreatedTime:      now,
+				},
+			})
+		}
+		actions = append(actions,
+			map[string]interface{}{
+				"add": addAction{
+					Path:             escapePath(relPath),
+					PartitionValues:  map[string]string{},
+					Size:             size,
+					ModificationTime: now,
+					DataChange:       true,
+				},
+			},
+			map[string]interface{}{
+				"commitInfo": commitInfoAction{
+					Timestamp:           now,
+					Operation:           "WRITE",
+					OperationParameters: map[string]string{"mode": "Append"},
+					IsBlindAppend:       true,
+					EngineInfo:          engineInfo,
+				},
+			})
+		data, err := encodeActions(actions)
+		if err != nil {
+			return err
+		}
+		if err := c.storage.WriteFile(ctx, commitFile, data); err != nil {
+			return errors.Trace(err)
+		}
+		state.version = version
+		state.id = id
+		state.schemaString = schemaString
+		log.Debug("commit data file to delta lake table success",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("path", filePath),
+			zap.Int64("version", version))
+		return nil
+	}
+	return errors.Errorf("failed to commit data file %s to delta lake table %s "+
+		"after %d retries, the table may be written by another writer",
+		filePath, root, maxCommitRetries)
+}
+
+// getTableState returns the state of the table, it's loaded from the
+// transaction log if it's not cached.
+func (c *Committer) getTableState(ctx context.Context, root string) (*tableState, error) {
+	if state, ok := c.tables[root]; ok {
+		return state, nil
+	}
+	logDir := path.Join(root, deltaLogDir)
+	var versions []int64
+	err := c.storage.WalkDir(ctx, &storage.WalkOption{SubDir: logDir},
+		func(filePath string, _ int64) error {
+			matches := commitFileRE.FindStringSubmatch(path.Base(filePath))
+			if matches == nil {
+				return nil
+			}
+			version, err := strconv.ParseInt(matches[1], 10, 64)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			versions = append(versions, version)
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	state := &tableState{version: -1}
+	for _, version := range versions {
+		if version > state.version {
+			state.version = version
+		}
+	}
+	// find the last metadata action from the latest commit.
+	for version := state.version; version >= 0 && state.id == ""; version-- {
+		commitFile := path.Join(logDir, fmt.Sprintf(commitFileNameFormat, version))
+		data, err := c.storage.ReadFile(ctx, commitFile)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		metadata, err := findMetadata(data)
+		if err != nil {
+			return nil, errors.Annotatef(err, "decode delta lake commit file %s", commitFile)
+		}
+		if metadata != nil {
+			state.id = metadata.ID
+			state.schemaString = metadata.SchemaString
+		}
+	}
+	if state.version >= 0 && state.id == "" {
+		return nil, errors.Errorf("the metadata of delta lake table %s is not found "+
+			"in the commit files", root)
+	}
+	c.tables[root] = state
+	return state, nil
+}
+
+type protocolAction struct {
+	MinReaderVersion int `json:"minReaderVersion"`
+	MinWriterVersion int `json:"minWriterVersion"`
+}
+
+type formatAction struct {
+	Provider string            `json:"provider"`
+	Options  map[string]string `json:"options"`
+}
+
+type metadataAction struct {
+	ID               string            `json:"id"`
+	Format           formatAction      `json:"format"`
+	SchemaString     string            `json:"schemaString"`
+	PartitionColumns []string          `json:"partitionColumns"`
+	Configuration    map[string]string `json:"configuration"`
+	CreatedTime      int64             `json:"createdTime"`
+}
+
+type addAction struct {
+	Path             string            `json:"path"`
+	PartitionValues  map[string]string `json:"partitionValues"`
+	Size             int64             `json:"size"`
+	ModificationTime int64             `json:"modificationTime"`
+	DataChange       bool              `json:"dataChange"`
+}
+
+type commitInfoAction struct {
+	Timestamp           int64             `json:"timestamp"`
+	Operation           string            `json:"operation"`
+	OperationParameters map[string]string `json:"operationParameters"`
+	IsBlindAppend       bool              `json:"isBlindAppend"`
+	EngineInfo          string            `json:"engineInfo"`
+}
+
+type structField struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Nullable bool              `json:"nullable"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type structType struct {
+	Type   string        `json:"type"`
+	Fields []structField `json:"fields"`
+}
+
+// buildSchemaString returns the schema of the table in the format of the
+// Spark struct type, the types are the same as the ones in the parquet files.
+func buildSchemaString(tableInfo *model.TableInfo, computedColumns []string) (string, error) {
+	schema := structType{Type: "struct"}
+	for _, field := range parquet.Fields(tableInfo, computedColumns) {
+		var typ string
+		switch field.Type {
+		case pparquet.Type_INT64:
+			typ = "long"
+		case pparquet.Type_FLOAT:
+			typ = "float"
+		case pparquet.Type_DOUBLE:
+			typ = "double"
+		default:
+			typ = "binary"
+			if field.IsString {
+				typ = "string"
+			}
+		}
+		schema.Fields = append(schema.Fields, structField{
+			Name:     field.Name,
+			Type:     typ,
+			Nullable: true,
+			Metadata: map[string]string{},
+		})
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// encodeActions encodes the actions of a commit, each action is in a line.
+func encodeActions(actions []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, action := range actions {
+		data, err := json.Marshal(action)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// findMetadata returns the metadata action in the commit, nil is returned
+// if there isn't one.
+func findMetadata(data []byte) (*metadataAction, error) {
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var action struct {
+			MetaData *metadataAction `json:"metaData"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if action.MetaData != nil {
+			return action.MetaData, nil
+		}
+	}
+	return nil, nil
+}
+
+// escapePath escapes the segments of the path, since the paths in the add
+// actions are URIs.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
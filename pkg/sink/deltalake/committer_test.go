@@ -0,0 +1,142 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltalake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTableInfo(columns ...string) *model.TableInfo {
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t"},
+		TableInfo: &timodel.TableInfo{},
+	}
+	for i, name := range columns {
+		tableInfo.Columns = append(tableInfo.Columns, &timodel.ColumnInfo{
+			ID:        int64(i + 1),
+			Name:      timodel.NewCIStr(name),
+			FieldType: *types.NewFieldType(mysql.TypeLong),
+		})
+	}
+	return tableInfo
+}
+
+// readCommit returns the actions of the commit file, which are keyed by the
+// action names.
+func readCommit(t *testing.T, dir string, version int) map[string]map[string]interface{} {
+	data, err := os.ReadFile(path.Join(dir, "test/t/_delta_log", fmt.Sprintf("%020d.json", version)))
+	require.NoError(t, err)
+	actions := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		var action map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &action))
+		require.Len(t, action, 1)
+		for name, value := range action {
+			actions[name] = value
+		}
+	}
+	return actions
+}
+
+func TestCommitterLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage, err := util.GetExternalStorageFromURI(ctx, "file://"+dir)
+	require.NoError(t, err)
+	c := NewCommitter(model.DefaultChangeFeedID("test"), storage, common.NewConfig(config.ProtocolParquet))
+
+	// the first commit creates the table.
+	tableInfo := newTestTableInfo("id")
+	require.NoError(t, c.Load(ctx, tableInfo, "test/t/1/dt=2024-05-01/CDC000001.parquet", 100))
+	actions := readCommit(t, dir, 0)
+	require.Len(t, actions, 4)
+	require.Equal(t, map[string]interface{}{
+		"minReaderVersion": float64(1), "minWriterVersion": float64(2),
+	}, actions["protocol"])
+	metadata := actions["metaData"]
+	id := metadata["id"]
+	require.NotEmpty(t, id)
+	require.Equal(t, map[string]interface{}{
+		"provider": "parquet", "options": map[string]interface{}{},
+	}, metadata["format"])
+	schemaString, err := buildSchemaString(tableInfo, nil)
+	require.NoError(t, err)
+	require.Equal(t, schemaString, metadata["schemaString"])
+	require.Contains(t, schemaString, `{"name":"_tidb_commit_ts","type":"long","nullable":true,"metadata":{}}`)
+	require.Contains(t, schemaString, `{"name":"id","type":"long","nullable":true,"metadata":{}}`)
+	schemaString, err = buildSchemaString(tableInfo, []string{"region"})
+	require.NoError(t, err)
+	require.Contains(t, schemaString, `{"name":"region","type":"string","nullable":true,"metadata":{}}`)
+	add := actions["add"]
+	require.Equal(t, "1/dt=2024-05-01/CDC000001.parquet", add["path"])
+	require.Equal(t, float64(100), add["size"])
+	require.Equal(t, true, add["dataChange"])
+	require.Equal(t, "WRITE", actions["commitInfo"]["operation"])
+
+	// the metadata is not committed again if the schema isn't changed.
+	require.NoError(t, c.Load(ctx, tableInfo, "test/t/1/dt=2024-05-01/CDC000002.parquet", 200))
+	actions = readCommit(t, dir, 1)
+	require.Len(t, actions, 2)
+	require.Equal(t, "1/dt=2024-05-01/CDC000002.parquet", actions["add"]["path"])
+
+	// the metadata is committed if the schema is changed.
+	tableInfo = newTestTableInfo("id", "name")
+	require.NoError(t, c.Load(ctx, tableInfo, "test/t/2/CDC000001.parquet", 300))
+	actions = readCommit(t, dir, 2)
+	require.Len(t, actions, 3)
+	require.Equal(t, id, actions["metaData"]["id"])
+	require.Contains(t, actions["metaData"]["schemaString"], `"name":"name"`)
+
+	// the state of the table is loaded from the transaction log after restart.
+	c = NewCommitter(model.DefaultChangeFeedID("test"), storage, common.NewConfig(config.ProtocolParquet))
+	require.NoError(t, c.Load(ctx, tableInfo, "test/t/2/CDC000002.parquet", 400))
+	actions = readCommit(t, dir, 3)
+	require.Len(t, actions, 2)
+
+	// the commit is retried if the version is committed by another writer.
+	require.NoError(t, storage.WriteFile(ctx, "test/t/_delta_log/00000000000000000004.json",
+		[]byte(`{"commitInfo":{"operation":"OPTIMIZE"}}`+"\n")))
+	require.NoError(t, c.Load(ctx, tableInfo, "test/t/2/CDC000003.parquet", 500))
+	actions = readCommit(t, dir, 5)
+	require.Len(t, actions, 2)
+	require.Equal(t, "2/CDC000003.parquet", actions["add"]["path"])
+
+	// the data file must be in the table directory.
+	require.ErrorContains(t, c.Load(ctx, tableInfo, "test/t2/1/CDC000001.parquet", 100),
+		"is not in the table directory")
+}
+
+func TestEscapePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1/dt=2024-05-01/CDC000001.parquet", escapePath("1/dt=2024-05-01/CDC000001.parquet"))
+	require.Equal(t, "1/a%20b/CDC%25.parquet", escapePath("1/a b/CDC%.parquet"))
+}
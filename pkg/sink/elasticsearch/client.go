@@ -0,0 +1,102 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// Client executes the requests by the REST API of Elasticsearch.
+type Client struct {
+	endpoint   string
+	user       string
+	password   string
+	httpClient *http.Client
+}
+
+// bulkResponse is the response of the bulk API, only the fields which are
+// used to check the failed actions are decoded.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Index  string          `json:"_index"`
+		ID     string          `json:"_id"`
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error,omitempty"`
+	} `json:"items"`
+}
+
+// NewClient creates a Client by the config.
+func NewClient(config *Config) *Client {
+	return &Client{
+		endpoint:   config.Endpoint,
+		user:       config.User,
+		password:   config.Password,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Bulk executes the actions by the bulk API, the data is the actions in the
+// newline delimited JSON format. An error is returned if any action fails,
+// except that the document to delete doesn't exist.
+func (c *Client) Bulk(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.endpoint+"/_bulk", bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("elasticsearch bulk request failed, status: %d, body: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result bulkResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if !result.Errors {
+		return nil
+	}
+	for _, item := range result.Items {
+		for action, r := range item {
+			if r.Status < http.StatusMultipleChoices ||
+				(action == "delete" && r.Status == http.StatusNotFound) {
+				continue
+			}
+			return errors.Errorf("elasticsearch bulk %s failed, index: %s, id: %s, status: %d, error: %s",
+				action, r.Index, r.ID, r.Status, string(r.Error))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientBulk(t *testing.T) {
+	t.Parallel()
+
+	var path, contentType, user, body string
+	response := `{"errors":false,"items":[{"index":{"_index":"t","_id":"1","status":201}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		user, _, _ = r.BasicAuth()
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	cfg := NewConfig()
+	cfg.Endpoint = server.URL
+	cfg.User = "elastic"
+	client := NewClient(cfg)
+
+	ctx := context.Background()
+	data := "{\"index\":{\"_index\":\"t\",\"_id\":\"1\"}}\n{\"id\":1}\n"
+	require.NoError(t, client.Bulk(ctx, []byte(data)))
+	require.Equal(t, "/_bulk", path)
+	require.Equal(t, "application/x-ndjson", contentType)
+	require.Equal(t, "elastic", user)
+	require.Equal(t, data, body)
+
+	// the documents to delete may not exist.
+	response = `{"errors":true,"items":[{"delete":{"_index":"t","_id":"2","status":404}}]}`
+	require.NoError(t, client.Bulk(ctx, []byte(data)))
+
+	response = `{"errors":true,"items":[{"index":{"_index":"t","_id":"1","status":201}},` +
+		`{"index":{"_index":"t","_id":"3","status":400,"error":{"type":"mapper_parsing_exception"}}}]}`
+	err := client.Bulk(ctx, []byte(data))
+	require.ErrorContains(t, err, "status: 400")
+	require.ErrorContains(t, err, "mapper_parsing_exception")
+}
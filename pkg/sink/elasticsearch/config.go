@@ -0,0 +1,244 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imdario/mergo"
+	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultIndex        = "{schema}.{table}"
+	defaultWorkerCount  = 8
+	defaultMaxBatchRows = 1000
+	defaultTimeout      = time.Minute
+
+	defaultPort = "9200"
+
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max batch rows.
+	maxMaxBatchRows = 100000
+
+	schemaPlaceholder = "{schema}"
+	tablePlaceholder  = "{table}"
+)
+
+// indexRE is used to match a valid index, the characters which are forbidden
+// in the index names of Elasticsearch are rejected, and the index can't start
+// with '-', '_' or '+'.
+var indexRE = regexp.MustCompile(`^[^\-_+\\/*?"<>| ,#:][^\\/*?"<>| ,#:]*$`)
+
+type urlConfig struct {
+	Index        *string `form:"index"`
+	WorkerCount  *int    `form:"worker-count"`
+	MaxBatchRows *int    `form:"max-batch-rows"`
+	Timeout      *string `form:"timeout"`
+}
+
+// Config is the configs for the Elasticsearch sink.
+type Config struct {
+	// Endpoint is the URL of the REST API, such as "http://127.0.0.1:9200".
+	Endpoint string
+	User     string
+	Password string
+
+	// Index is the index of the tables which aren't matched by the index rules.
+	Index        string
+	IndexRules   []*IndexRule
+	WorkerCount  int
+	MaxBatchRows int
+	Timeout      time.Duration
+}
+
+// IndexRule is the index of the tables matched by the table matcher.
+type IndexRule struct {
+	TableMatcher tfilter.Filter
+	Index        string
+}
+
+// NewConfig returns the default Elasticsearch sink config.
+func NewConfig() *Config {
+	return &Config{
+		Index:        defaultIndex,
+		WorkerCount:  defaultWorkerCount,
+		MaxBatchRows: defaultMaxBatchRows,
+		Timeout:      defaultTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrSinkURIInvalid.GenWithStack("fail to open Elasticsearch sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsElasticsearchScheme(scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStack(
+			"can't create Elasticsearch sink with unsupported scheme: %s", scheme)
+	}
+	c.Endpoint = endpoint(scheme, sinkURI.Host)
+	c.User = sinkURI.User.Username()
+	c.Password, _ = sinkURI.User.Password()
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	urlParameter, err := mergeConfig(replicaConfig, urlParameter)
+	if err != nil {
+		return err
+	}
+	if urlParameter.Index != nil {
+		if err := validateIndex(*urlParameter.Index); err != nil {
+			return err
+		}
+		c.Index = *urlParameter.Index
+	}
+	if err := getPositiveInt("worker-count", urlParameter.WorkerCount,
+		maxWorkerCount, &c.WorkerCount); err != nil {
+		return err
+	}
+	if err := getPositiveInt("max-batch-rows", urlParameter.MaxBatchRows,
+		maxMaxBatchRows, &c.MaxBatchRows); err != nil {
+		return err
+	}
+	if urlParameter.Timeout != nil {
+		timeout, err := time.ParseDuration(*urlParameter.Timeout)
+		if err != nil || timeout <= 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid timeout %s, it must be a positive duration", *urlParameter.Timeout)
+		}
+		c.Timeout = timeout
+	}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.ElasticsearchConfig != nil {
+		return c.applyIndexRules(replicaConfig.Sink.ElasticsearchConfig.IndexRules,
+			replicaConfig.CaseSensitive)
+	}
+	return nil
+}
+
+func (c *Config) applyIndexRules(
+	rules []*config.ElasticsearchIndexRule, caseSensitive bool,
+) error {
+	for _, r := range rules {
+		if len(r.Matcher) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the matcher of the index rule is empty")
+		}
+		if err := validateIndex(r.Index); err != nil {
+			return err
+		}
+		tableMatcher, err := tfilter.Parse(r.Matcher)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		if !caseSensitive {
+			tableMatcher = tfilter.CaseInsensitive(tableMatcher)
+		}
+		c.IndexRules = append(c.IndexRules, &IndexRule{
+			TableMatcher: tableMatcher,
+			Index:        r.Index,
+		})
+	}
+	return nil
+}
+
+// IndexName returns the index of the table, the placeholders of the index of
+// the first matched index rule or the default index are substituted by the
+// schema and table names. The index is in lower case since Elasticsearch
+// doesn't accept the upper case index names.
+func (c *Config) IndexName(schema, table string) string {
+	index := c.Index
+	for _, r := range c.IndexRules {
+		if r.TableMatcher.MatchTable(schema, table) {
+			index = r.Index
+			break
+		}
+	}
+	index = strings.ReplaceAll(index, schemaPlaceholder, schema)
+	index = strings.ReplaceAll(index, tablePlaceholder, table)
+	return strings.ToLower(index)
+}
+
+func validateIndex(index string) error {
+	if !indexRE.MatchString(index) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid index %s, it can't be empty, start with '-', '_' or '+', "+
+				"or contain the characters \\, /, *, ?, \", <, >, |, ' ', ',', # or :", index)
+	}
+	return nil
+}
+
+func mergeConfig(
+	replicaConfig *config.ReplicaConfig,
+	urlParameters *urlConfig,
+) (*urlConfig, error) {
+	dest := &urlConfig{}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.ElasticsearchConfig != nil {
+		eConfig := replicaConfig.Sink.ElasticsearchConfig
+		dest.Index = eConfig.Index
+		dest.WorkerCount = eConfig.WorkerCount
+		dest.MaxBatchRows = eConfig.MaxBatchRows
+	}
+	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	return dest, nil
+}
+
+// endpoint returns the URL of the REST API, the default port is used
+// if the host doesn't contain one.
+func endpoint(scheme, host string) string {
+	protocol := "http"
+	if scheme == sink.ElasticsearchSSLScheme {
+		protocol = "https"
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, defaultPort)
+	}
+	return protocol + "://" + host
+}
+
+func getPositiveInt(name string, value *int, upperLimit int, target *int) error {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	if c <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %d, which must be greater than 0", name, c)
+	}
+	if c > upperLimit {
+		log.Warn(name+" too large",
+			zap.Int("original", c), zap.Int("override", upperLimit))
+		c = upperLimit
+	}
+	*target = c
+	return nil
+}
@@ -16,12 +16,10 @@ package kafka
 import (
 	"context"
 	"errors"
-	"io"
-	"net"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/log"
@@ -32,9 +30,11 @@ import (
 )
 
 type saramaAdminClient struct {
-	brokerEndpoints []string
-	config          *sarama.Config
-	changefeed      model.ChangeFeedID
+	// resolveBrokers is called again when the client is reset,
+	// so that the brokers can be re-discovered after connection failures.
+	resolveBrokers func(ctx context.Context) ([]string, error)
+	config         *sarama.Config
+	changefeed     model.ChangeFeedID
 
 	mu     sync.Mutex
 	client sarama.Client
@@ -44,13 +44,22 @@ type saramaAdminClient struct {
 const (
 	defaultRetryBackoff  = 20
 	defaultRetryMaxTries = 3
+
+	// boundaryMessageReadTimeout is the timeout of reading the boundary
+	// messages of a partition.
+	boundaryMessageReadTimeout = 5 * time.Second
 )
 
 func newAdminClient(
-	brokerEndpoints []string,
+	ctx context.Context,
+	resolveBrokers func(ctx context.Context) ([]string, error),
 	config *sarama.Config,
 	changefeed model.ChangeFeedID,
 ) (ClusterAdminClient, error) {
+	brokerEndpoints, err := resolveBrokers(ctx)
+	if err != nil {
+		return nil, err
+	}
 	client, err := sarama.NewClient(brokerEndpoints, config)
 	if err != nil {
 		return nil, cerror.Trace(err)
@@ -61,16 +70,20 @@ func newAdminClient(
 		return nil, err
 	}
 	return &saramaAdminClient{
-		client:          client,
-		admin:           admin,
-		brokerEndpoints: brokerEndpoints,
-		config:          config,
-		changefeed:      changefeed,
+		client:         client,
+		admin:          admin,
+		resolveBrokers: resolveBrokers,
+		config:         config,
+		changefeed:     changefeed,
 	}, nil
 }
 
-func (a *saramaAdminClient) reset() error {
-	newClient, err := sarama.NewClient(a.brokerEndpoints, a.config)
+func (a *saramaAdminClient) reset(ctx context.Context) error {
+	brokerEndpoints, err := a.resolveBrokers(ctx)
+	if err != nil {
+		return err
+	}
+	newClient, err := sarama.NewClient(brokerEndpoints, a.config)
 	if err != nil {
 		return cerror.Trace(err)
 	}
@@ -102,8 +115,8 @@ func (a *saramaAdminClient) queryClusterWithRetry(ctx context.Context, query fun
 			zap.String("changefeed", a.changefeed.ID),
 			zap.Error(err))
 
-		if errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) {
-			return a.reset()
+		if IsBrokerUnreachable(err) {
+			return a.reset(ctx)
 		}
 		return err
 	}, retry.WithBackoffBaseDelay(defaultRetryBackoff), retry.WithMaxTries(defaultRetryMaxTries))
@@ -268,6 +281,38 @@ func (a *saramaAdminClient) GetTopicsMeta(
 	return result, nil
 }
 
+func (a *saramaAdminClient) GetAllTopicsMeta(
+	ctx context.Context,
+) (map[string]TopicDetail, error) {
+	var (
+		topics map[string]sarama.TopicDetail
+		err    error
+	)
+	query := func() error {
+		topics, err = a.admin.ListTopics()
+		return err
+	}
+
+	err = a.queryClusterWithRetry(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]TopicDetail, len(topics))
+	for name, detail := range topics {
+		// Skip the internal topics, such as `__consumer_offsets`.
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		result[name] = TopicDetail{
+			Name:              name,
+			NumPartitions:     detail.NumPartitions,
+			ReplicationFactor: detail.ReplicationFactor,
+		}
+	}
+	return result, nil
+}
+
 func (a *saramaAdminClient) CreateTopic(
 	ctx context.Context,
 	detail *TopicDetail,
@@ -277,6 +322,13 @@ func (a *saramaAdminClient) CreateTopic(
 		NumPartitions:     detail.NumPartitions,
 		ReplicationFactor: detail.ReplicationFactor,
 	}
+	if len(detail.ConfigEntries) != 0 {
+		request.ConfigEntries = make(map[string]*string, len(detail.ConfigEntries))
+		for name, value := range detail.ConfigEntries {
+			value := value
+			request.ConfigEntries[name] = &value
+		}
+	}
 	query := func() error {
 		err := a.admin.CreateTopic(detail.Name, request, validateOnly)
 		// Ignore the already exists error because it's not harmful.
@@ -288,6 +340,130 @@ func (a *saramaAdminClient) CreateTopic(
 	return a.queryClusterWithRetry(ctx, query)
 }
 
+func (a *saramaAdminClient) DeleteTopics(ctx context.Context, topics []string) error {
+	for _, topic := range topics {
+		topic := topic
+		query := func() error {
+			err := a.admin.DeleteTopic(topic)
+			// Ignore the unknown topic error because the topic is already deleted.
+			if err != nil && !strings.Contains(err.Error(), sarama.ErrUnknownTopicOrPartition.Error()) {
+				return err
+			}
+			return nil
+		}
+		if err := a.queryClusterWithRetry(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *saramaAdminClient) GetBoundaryMessageHeaders(
+	ctx context.Context, topic string,
+) ([]map[string]string, error) {
+	var (
+		partitions []int32
+		offsets    [][2]int64
+	)
+	query := func() error {
+		var err error
+		partitions, err = a.client.Partitions(topic)
+		if err != nil {
+			return err
+		}
+		offsets = make([][2]int64, 0, len(partitions))
+		for _, partition := range partitions {
+			oldest, err := a.client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return err
+			}
+			newest, err := a.client.GetOffset(topic, partition, sarama.OffsetNewest)
+			if err != nil {
+				return err
+			}
+			offsets = append(offsets, [2]int64{oldest, newest})
+		}
+		return nil
+	}
+	if err := a.queryClusterWithRetry(ctx, query); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	consumer, err := sarama.NewConsumerFromClient(a.client)
+	a.mu.Unlock()
+	if err != nil {
+		return nil, cerror.Trace(err)
+	}
+	defer consumer.Close()
+
+	var result []map[string]string
+	for i, partition := range partitions {
+		oldest, newest := offsets[i][0], offsets[i][1]
+		if oldest >= newest {
+			continue
+		}
+		headers, err := readMessageHeaders(ctx, consumer, topic, partition, oldest, newest, 1)
+		if err != nil {
+			return nil, err
+		}
+		// A non-empty partition without readable messages is reported by
+		// empty headers, so it's never taken as written by a changefeed.
+		if len(headers) == 0 {
+			headers = append(headers, map[string]string{})
+		}
+		result = append(result, headers...)
+		// The last offset may be a transaction marker, so the last two
+		// offsets are read to get the latest message.
+		start := newest - 2
+		if start < oldest {
+			start = oldest
+		}
+		headers, err = readMessageHeaders(ctx, consumer, topic, partition, start, newest, 2)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, headers...)
+	}
+	return result, nil
+}
+
+// readMessageHeaders reads at most limit messages in [start, end) of the
+// partition, and returns their headers. The transaction markers are never
+// consumed, so the reading also stops after boundaryMessageReadTimeout.
+func readMessageHeaders(
+	ctx context.Context, consumer sarama.Consumer,
+	topic string, partition int32, start, end int64, limit int,
+) ([]map[string]string, error) {
+	pc, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return nil, cerror.Trace(err)
+	}
+	defer pc.Close()
+
+	timer := time.NewTimer(boundaryMessageReadTimeout)
+	defer timer.Stop()
+	result := make([]map[string]string, 0, limit)
+	for len(result) < limit {
+		select {
+		case <-ctx.Done():
+			return nil, cerror.Trace(ctx.Err())
+		case <-timer.C:
+			return result, nil
+		case msg := <-pc.Messages():
+			headers := make(map[string]string, len(msg.Headers))
+			for _, h := range msg.Headers {
+				headers[string(h.Key)] = string(h.Value)
+			}
+			result = append(result, headers)
+			if msg.Offset >= end-1 {
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
 func (a *saramaAdminClient) Close() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -0,0 +1,134 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+)
+
+const (
+	// AWSMSKIAMSignService is the service name of the signature of the IAM
+	// authentication of Amazon MSK.
+	AWSMSKIAMSignService = "kafka-cluster"
+	// AWSMSKIAMSignAction is the action of the signature of the IAM
+	// authentication of Amazon MSK.
+	AWSMSKIAMSignAction = "kafka-cluster:Connect"
+	// AWSMSKIAMUserAgent is the user agent in the signed requests.
+	AWSMSKIAMUserAgent = "ticdc"
+
+	defaultAWSRoleSessionName = "ticdc"
+	// awsMSKIAMTokenExpiry is the expiry of the tokens of SASL/OAUTHBEARER.
+	awsMSKIAMTokenExpiry = 15 * time.Minute
+)
+
+// AWSMSKIAMSigner presigns the connect requests of the IAM authentication of
+// Amazon MSK by the AWS Signature Version 4. The credentials are resolved by
+// the AWS default credential chain, which refreshes them before they expire.
+type AWSMSKIAMSigner struct {
+	region string
+	signer *v4.Signer
+	now    func() time.Time
+}
+
+// NewAWSMSKIAMSigner creates an AWSMSKIAMSigner, the role is assumed if the
+// role ARN is set.
+func NewAWSMSKIAMSigner(o *security.AWSMSKIAM) (*AWSMSKIAMSigner, error) {
+	cfg := aws.Config{}
+	if o.Region != "" {
+		cfg.Region = aws.String(o.Region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	region := aws.StringValue(sess.Config.Region)
+	if region == "" {
+		return nil, errors.New("AWS region of the MSK cluster is unknown, " +
+			"set sasl-aws-region or the AWS_REGION environment variable")
+	}
+
+	creds := sess.Config.Credentials
+	if o.RoleARN != "" {
+		roleSessionName := o.RoleSessionName
+		if roleSessionName == "" {
+			roleSessionName = defaultAWSRoleSessionName
+		}
+		creds = stscreds.NewCredentials(sess, o.RoleARN,
+			func(p *stscreds.AssumeRoleProvider) {
+				p.RoleSessionName = roleSessionName
+			})
+	}
+	return newAWSMSKIAMSigner(region, creds), nil
+}
+
+func newAWSMSKIAMSigner(region string, creds *credentials.Credentials) *AWSMSKIAMSigner {
+	return &AWSMSKIAMSigner{
+		region: region,
+		signer: v4.NewSigner(creds),
+		now:    time.Now,
+	}
+}
+
+// Presign presigns the connect action on the host, the signed parameters are
+// in the query of the returned URL.
+func (s *AWSMSKIAMSigner) Presign(
+	scheme, host string, expiry time.Duration,
+) (*url.URL, error) {
+	query := url.Values{"Action": {AWSMSKIAMSignAction}}
+	req, err := http.NewRequest(http.MethodGet, (&url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		Path:     "/",
+		RawQuery: query.Encode(),
+	}).String(), nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := s.signer.Presign(req, nil, AWSMSKIAMSignService,
+		s.region, expiry, s.now()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return req.URL, nil
+}
+
+// Token implements sarama.AccessTokenProvider. MSK accepts the presigned URL
+// encoded by base64 as the token of SASL/OAUTHBEARER, since sarama doesn't
+// support the AWS_MSK_IAM mechanism.
+func (s *AWSMSKIAMSigner) Token() (*sarama.AccessToken, error) {
+	u, err := s.Presign("https", "kafka."+s.region+".amazonaws.com", awsMSKIAMTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("User-Agent", AWSMSKIAMUserAgent)
+	u.RawQuery = query.Encode()
+	return &sarama.AccessToken{
+		Token: base64.RawURLEncoding.EncodeToString([]byte(u.String())),
+	}, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAWSMSKIAMSigner() *AWSMSKIAMSigner {
+	signer := newAWSMSKIAMSigner("us-east-1",
+		credentials.NewStaticCredentials("AKID", "SECRET", "TOKEN"))
+	signer.now = func() time.Time {
+		return time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return signer
+}
+
+func TestAWSMSKIAMSignerPresign(t *testing.T) {
+	t.Parallel()
+
+	signer := newTestAWSMSKIAMSigner()
+	u, err := signer.Presign("kafka", "b-1.msk.us-east-1.amazonaws.com", 5*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "b-1.msk.us-east-1.amazonaws.com", u.Host)
+
+	query := u.Query()
+	require.Equal(t, AWSMSKIAMSignAction, query.Get("Action"))
+	require.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	require.Equal(t, "AKID/20230601/us-east-1/kafka-cluster/aws4_request",
+		query.Get("X-Amz-Credential"))
+	require.Equal(t, "20230601T120000Z", query.Get("X-Amz-Date"))
+	require.Equal(t, "300", query.Get("X-Amz-Expires"))
+	require.Equal(t, "host", query.Get("X-Amz-SignedHeaders"))
+	require.Equal(t, "TOKEN", query.Get("X-Amz-Security-Token"))
+	require.NotEmpty(t, query.Get("X-Amz-Signature"))
+
+	// the signature is stable at the same time.
+	u2, err := signer.Presign("kafka", "b-1.msk.us-east-1.amazonaws.com", 5*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, query.Get("X-Amz-Signature"), u2.Query().Get("X-Amz-Signature"))
+}
+
+func TestAWSMSKIAMSignerToken(t *testing.T) {
+	t.Parallel()
+
+	signer := newTestAWSMSKIAMSigner()
+	token, err := signer.Token()
+	require.NoError(t, err)
+
+	data, err := base64.RawURLEncoding.DecodeString(token.Token)
+	require.NoError(t, err)
+	u, err := url.Parse(string(data))
+	require.NoError(t, err)
+	require.Equal(t, "https", u.Scheme)
+	require.Equal(t, "kafka.us-east-1.amazonaws.com", u.Host)
+	require.Equal(t, AWSMSKIAMSignAction, u.Query().Get("Action"))
+	require.Equal(t, AWSMSKIAMUserAgent, u.Query().Get("User-Agent"))
+	require.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	require.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+}
+
+func TestNewAWSMSKIAMSigner(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+
+	// the region is required.
+	_, err := NewAWSMSKIAMSigner(&security.AWSMSKIAM{})
+	require.ErrorContains(t, err, "region")
+
+	// the region is resolved by the environment variables.
+	t.Setenv("AWS_REGION", "us-west-2")
+	signer, err := NewAWSMSKIAMSigner(&security.AWSMSKIAM{})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", signer.region)
+
+	signer, err = NewAWSMSKIAMSigner(&security.AWSMSKIAM{Region: "eu-west-1"})
+	require.NoError(t, err)
+	require.Equal(t, "eu-west-1", signer.region)
+	u, err := signer.Presign("kafka", "b-1.msk.eu-west-1.amazonaws.com", 5*time.Minute)
+	require.NoError(t, err)
+	require.Contains(t, u.Query().Get("X-Amz-Credential"), "AKID/")
+}
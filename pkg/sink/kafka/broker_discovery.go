@@ -0,0 +1,144 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultBrokerPort is used if the port of a broker address is not specified.
+	defaultBrokerPort = "9092"
+
+	// srvService and srvProto build the `_kafka._tcp.<name>` SRV record name
+	// if the user only gives the domain name.
+	srvService = "kafka"
+	srvProto   = "tcp"
+)
+
+// lookupSRV is used to query the DNS SRV records, it's a variable for test purpose.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// parseBrokerEndpoints parses the host part of the sink URI to broker endpoints.
+// IPv6 literals must be enclosed in square brackets, such as `[::1]:9092`,
+// the default port is used if it's not specified.
+// If DNS SRV discovery is enabled, each endpoint is a SRV record name without port.
+func parseBrokerEndpoints(host string, dnsSRV bool) ([]string, error) {
+	endpoints := strings.Split(host, ",")
+	for i, endpoint := range endpoints {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" {
+			return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"empty broker address found in %s", host)
+		}
+		if dnsSRV {
+			if _, _, err := net.SplitHostPort(endpoint); err == nil {
+				return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+					"port should not be set for the DNS SRV record name %s", endpoint)
+			}
+			endpoints[i] = endpoint
+			continue
+		}
+
+		h, port, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			// the port is missing, the whole endpoint is the host.
+			h = endpoint
+			if strings.HasPrefix(h, "[") && strings.HasSuffix(h, "]") {
+				h = h[1 : len(h)-1]
+			} else if strings.Contains(h, ":") {
+				return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+					"invalid broker address %s", endpoint)
+			}
+			port = defaultBrokerPort
+		}
+		if strings.ContainsAny(h, "[]") || h == "" {
+			return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"invalid broker address %s", endpoint)
+		}
+		endpoints[i] = net.JoinHostPort(h, port)
+	}
+	return endpoints, nil
+}
+
+// ResolveBrokerEndpoints returns the addresses used to bootstrap the kafka client.
+// If DNS SRV discovery is enabled, the SRV records are looked up every time this
+// method is called, so the brokers can be re-discovered once the connection failed.
+// The admin clients and the sync producers call it again if the brokers are
+// unreachable, the async producers are recreated with the sink after they fail.
+func (o *Options) ResolveBrokerEndpoints(ctx context.Context) ([]string, error) {
+	if !o.DNSSRVDiscovery {
+		return o.BrokerEndpoints, nil
+	}
+
+	var (
+		result  []string
+		lastErr error
+	)
+	for _, name := range o.BrokerEndpoints {
+		service, proto := srvService, srvProto
+		// the name is already a full SRV record name, such as `_kafka._tcp.example.com`.
+		if strings.HasPrefix(name, "_") {
+			service, proto = "", ""
+		}
+		_, records, err := lookupSRV(ctx, service, proto, name)
+		if err != nil {
+			log.Warn("lookup kafka brokers by DNS SRV record failed",
+				zap.String("name", name), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		for _, record := range records {
+			target := strings.TrimSuffix(record.Target, ".")
+			result = append(result,
+				net.JoinHostPort(target, strconv.Itoa(int(record.Port))))
+		}
+	}
+	if len(result) == 0 {
+		if lastErr != nil {
+			return nil, cerror.WrapError(cerror.ErrKafkaBrokerDiscovery, lastErr)
+		}
+		return nil, cerror.ErrKafkaBrokerDiscovery.GenWithStack(
+			"no broker found by the DNS SRV records %v", o.BrokerEndpoints)
+	}
+	log.Info("kafka brokers discovered by DNS SRV records",
+		zap.Strings("names", o.BrokerEndpoints),
+		zap.Strings("brokers", result))
+	return result, nil
+}
+
+// IsBrokerUnreachable returns true if the error means that the client can't
+// reach the brokers, the brokers should be resolved again in this case.
+func IsBrokerUnreachable(err error) bool {
+	var errs sarama.ProducerErrors
+	if errors.As(err, &errs) && len(errs) > 0 {
+		err = errs[0].Err
+	}
+	var dnsErr *net.DNSError
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) ||
+		errors.Is(err, sarama.ErrOutOfBrokers) || errors.Is(err, sarama.ErrNotConnected) ||
+		errors.As(err, &dnsErr)
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBrokerEndpoints(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		host     string
+		dnsSRV   bool
+		expected []string
+		hasErr   bool
+	}{
+		{host: "127.0.0.1:9092,127.0.0.2:9093", expected: []string{"127.0.0.1:9092", "127.0.0.2:9093"}},
+		{host: "[::1]:9092", expected: []string{"[::1]:9092"}},
+		{host: "[fe80::1%eth0]:9092", expected: []string{"[fe80::1%eth0]:9092"}},
+		{host: "[::1]", expected: []string{"[::1]:9092"}},
+		{host: "kafka-0.example.com", expected: []string{"kafka-0.example.com:9092"}},
+		{host: "127.0.0.1:9092,", hasErr: true},
+		{host: "[::1:9092", hasErr: true},
+		{host: "kafka.example.com", dnsSRV: true, expected: []string{"kafka.example.com"}},
+		{host: "kafka.example.com:9092", dnsSRV: true, hasErr: true},
+	}
+	for _, cs := range cases {
+		endpoints, err := parseBrokerEndpoints(cs.host, cs.dnsSRV)
+		if cs.hasErr {
+			require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err), cs.host)
+			continue
+		}
+		require.NoError(t, err, cs.host)
+		require.Equal(t, cs.expected, endpoints)
+	}
+}
+
+func TestApplyMultiHostSinkURI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		uri      string
+		expected []string
+	}{
+		{"kafka://[::1]:9092,[::2]:9092/t", []string{"[::1]:9092", "[::2]:9092"}},
+		{"kafka://127.0.0.1:9092,[::1]:9092/t", []string{"127.0.0.1:9092", "[::1]:9092"}},
+		{"kafka://[::1]:9092,127.0.0.1/t?partition-num=1", []string{"[::1]:9092", "127.0.0.1:9092"}},
+	}
+	for _, cs := range cases {
+		sinkURI, err := sink.ParseSinkURI(cs.uri)
+		require.NoError(t, err, cs.uri)
+		require.Equal(t, "/t", sinkURI.Path)
+
+		options := NewOptions()
+		err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+		require.NoError(t, err, cs.uri)
+		require.Equal(t, cs.expected, options.BrokerEndpoints)
+	}
+
+	// The broker list is still validated when the options are applied.
+	sinkURI, err := sink.ParseSinkURI("kafka://[::1]:9092,[::2/t")
+	require.NoError(t, err)
+	err = NewOptions().Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err))
+
+	// Only the MQ sink URI supports the broker list.
+	_, err = sink.ParseSinkURI("mysql://[::1]:3306,[::2]:3306/")
+	require.Error(t, err)
+}
+
+func TestResolveBrokerEndpoints(t *testing.T) {
+	originLookupSRV := lookupSRV
+	defer func() {
+		lookupSRV = originLookupSRV
+	}()
+
+	var queried []string
+	lookupSRV = func(
+		_ context.Context, service, proto, name string,
+	) (string, []*net.SRV, error) {
+		queried = append(queried, service+"/"+proto+"/"+name)
+		switch name {
+		case "kafka.example.com", "_kafka._tcp.example.com":
+			return "", []*net.SRV{
+				{Target: "kafka-0.example.com.", Port: 9092},
+				{Target: "kafka-1.example.com.", Port: 9093},
+			}, nil
+		default:
+			return "", nil, errors.New("no such host")
+		}
+	}
+
+	options := NewOptions()
+	sinkURI, err := url.Parse("kafka://kafka.example.com/test?dns-srv-discovery=true")
+	require.NoError(t, err)
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.True(t, options.DNSSRVDiscovery)
+	require.Equal(t, []string{"kafka.example.com"}, options.BrokerEndpoints)
+
+	ctx := context.Background()
+	endpoints, err := options.ResolveBrokerEndpoints(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kafka-0.example.com:9092", "kafka-1.example.com:9093"}, endpoints)
+	require.Equal(t, []string{"kafka/tcp/kafka.example.com"}, queried)
+
+	// the full SRV record name is queried directly, and the failed one is skipped.
+	queried = queried[:0]
+	options.BrokerEndpoints = []string{"unknown.example.com", "_kafka._tcp.example.com"}
+	endpoints, err = options.ResolveBrokerEndpoints(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kafka-0.example.com:9092", "kafka-1.example.com:9093"}, endpoints)
+	require.Equal(t, []string{"kafka/tcp/unknown.example.com", "//_kafka._tcp.example.com"}, queried)
+
+	options.BrokerEndpoints = []string{"unknown.example.com"}
+	_, err = options.ResolveBrokerEndpoints(ctx)
+	require.ErrorContains(t, err, "ErrKafkaBrokerDiscovery")
+
+	// the static endpoints are returned as is.
+	options.DNSSRVDiscovery = false
+	options.BrokerEndpoints = []string{"[::1]:9092"}
+	endpoints, err = options.ResolveBrokerEndpoints(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"[::1]:9092"}, endpoints)
+}
+
+func newMockBrokerForTopic(t *testing.T, brokerID int32, topic string) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, brokerID)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+	return broker
+}
+
+func TestSyncProducerResolveBrokersAgain(t *testing.T) {
+	originLookupSRV := lookupSRV
+	defer func() {
+		lookupSRV = originLookupSRV
+	}()
+
+	topic := "test"
+	oldBroker := newMockBrokerForTopic(t, 1, topic)
+	newBroker := newMockBrokerForTopic(t, 2, topic)
+	defer newBroker.Close()
+
+	var (
+		mu     sync.Mutex
+		target = oldBroker.Addr()
+	)
+	lookupSRV = func(
+		_ context.Context, service, proto, name string,
+	) (string, []*net.SRV, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		host, port, err := net.SplitHostPort(target)
+		require.NoError(t, err)
+		p, err := strconv.Atoi(port)
+		require.NoError(t, err)
+		return "", []*net.SRV{{Target: host + ".", Port: uint16(p)}}, nil
+	}
+
+	o := NewOptions()
+	o.Version = "0.9.0.0"
+	o.ClientID = "sarama-test"
+	o.DNSSRVDiscovery = true
+	o.BrokerEndpoints = []string{"kafka.example.com"}
+	f, err := NewSaramaFactory(o, model.DefaultChangeFeedID("sarama-test"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	producer, err := f.SyncProducer(ctx)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	message := &common.Message{Key: []byte("key"), Value: []byte("value")}
+	require.NoError(t, producer.SendMessage(ctx, topic, 0, message))
+
+	// The brokers are moved, and the SRV records are changed.
+	oldBroker.Close()
+	mu.Lock()
+	target = newBroker.Addr()
+	mu.Unlock()
+
+	require.NoError(t, producer.SendMessage(ctx, topic, 0, message))
+	require.NoError(t, producer.SendMessages(ctx, topic, 1, message))
+	require.Equal(t, newBroker.Addr(), producer.(*saramaSyncProducer).client.Brokers()[0].Addr())
+}
@@ -22,6 +22,9 @@ type TopicDetail struct {
 	Name              string
 	NumPartitions     int32
 	ReplicationFactor int16
+	// ConfigEntries are the topic level configurations, they're only used
+	// to create the topic.
+	ConfigEntries map[string]string
 }
 
 // Broker represents a Kafka broker.
@@ -49,9 +52,19 @@ type ClusterAdminClient interface {
 	GetTopicsMeta(ctx context.Context,
 		topics []string, ignoreTopicError bool) (map[string]TopicDetail, error)
 
+	// GetAllTopicsMeta return all non-internal topics' metadata of the cluster
+	GetAllTopicsMeta(ctx context.Context) (map[string]TopicDetail, error)
+
 	// CreateTopic creates a new topic.
 	CreateTopic(ctx context.Context, detail *TopicDetail, validateOnly bool) error
 
+	// DeleteTopics deletes the topics.
+	DeleteTopics(ctx context.Context, topics []string) error
+
+	// GetBoundaryMessageHeaders returns the headers of the earliest and the
+	// latest messages of each non-empty partition of the topic.
+	GetBoundaryMessageHeaders(ctx context.Context, topic string) ([]map[string]string, error)
+
 	// Close shuts down the admin client.
 	Close()
 }
@@ -65,6 +65,8 @@ type ClusterAdminClientMockImpl struct {
 	controllerID  int
 	brokerConfigs map[string]string
 	topicConfigs  map[string]map[string]string
+	// boundaryHeaders are the headers of the boundary messages of the topics.
+	boundaryHeaders map[string][]map[string]string
 }
 
 // NewClusterAdminClientMockImpl news a ClusterAdminClientMockImpl struct with default configurations.
@@ -88,16 +90,17 @@ func NewClusterAdminClientMockImpl() *ClusterAdminClientMockImpl {
 	topicConfigs[DefaultMockTopicName][MinInsyncReplicasConfigName] = MinInSyncReplicas
 
 	return &ClusterAdminClientMockImpl{
-		topics:        topics,
-		controllerID:  defaultMockControllerID,
-		brokerConfigs: brokerConfigs,
-		topicConfigs:  topicConfigs,
+		topics:          topics,
+		controllerID:    defaultMockControllerID,
+		brokerConfigs:   brokerConfigs,
+		topicConfigs:    topicConfigs,
+		boundaryHeaders: make(map[string][]map[string]string),
 	}
 }
 
 // GetAllBrokers implement the ClusterAdminClient interface
 func (c *ClusterAdminClientMockImpl) GetAllBrokers(context.Context) ([]Broker, error) {
-	return nil, nil
+	return []Broker{{ID: int32(c.controllerID)}}, nil
 }
 
 // GetCoordinator implement the ClusterAdminClient interface
@@ -166,6 +169,17 @@ func (c *ClusterAdminClientMockImpl) GetTopicsMeta(
 	return result, nil
 }
 
+// GetAllTopicsMeta implement the ClusterAdminClient interface
+func (c *ClusterAdminClientMockImpl) GetAllTopicsMeta(
+	_ context.Context,
+) (map[string]TopicDetail, error) {
+	result := make(map[string]TopicDetail, len(c.topics))
+	for topic, details := range c.topics {
+		result[topic] = details.TopicDetail
+	}
+	return result, nil
+}
+
 // CreateTopic adds topic into map.
 func (c *ClusterAdminClientMockImpl) CreateTopic(
 	_ context.Context,
@@ -187,9 +201,38 @@ func (c *ClusterAdminClientMockImpl) CreateTopic(
 	c.topics[detail.Name] = &topicDetail{
 		TopicDetail: *detail,
 	}
+	if len(detail.ConfigEntries) != 0 {
+		c.topicConfigs[detail.Name] = make(map[string]string, len(detail.ConfigEntries))
+		for name, value := range detail.ConfigEntries {
+			c.topicConfigs[detail.Name][name] = value
+		}
+	}
 	return nil
 }
 
+// DeleteTopics implement the ClusterAdminClient interface
+func (c *ClusterAdminClientMockImpl) DeleteTopics(_ context.Context, topics []string) error {
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+	return nil
+}
+
+// GetBoundaryMessageHeaders implement the ClusterAdminClient interface
+func (c *ClusterAdminClientMockImpl) GetBoundaryMessageHeaders(
+	_ context.Context, topic string,
+) ([]map[string]string, error) {
+	return c.boundaryHeaders[topic], nil
+}
+
+// SetBoundaryMessageHeaders sets the headers of the boundary messages of the
+// topic, only used for testing.
+func (c *ClusterAdminClientMockImpl) SetBoundaryMessageHeaders(
+	topic string, headers ...map[string]string,
+) {
+	c.boundaryHeaders[topic] = headers
+}
+
 // DeleteTopic deletes a topic, only used for testing.
 func (c *ClusterAdminClientMockImpl) DeleteTopic(topicName string) {
 	delete(c.topics, topicName)
@@ -15,6 +15,7 @@ package kafka
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -22,6 +23,8 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
@@ -48,7 +51,7 @@ type SyncProducer interface {
 	// of the produced message, or an error if the message failed to produce.
 	SendMessage(ctx context.Context,
 		topic string, partitionNum int32,
-		key []byte, value []byte) error
+		message *common.Message) error
 
 	// SendMessages produces a given set of messages, and returns only when all
 	// messages in the set have either succeeded or failed. Note that messages
@@ -56,7 +59,7 @@ type SyncProducer interface {
 	// SendMessages will return an error.
 	SendMessages(ctx context.Context,
 		topic string, partitionNum int32,
-		key []byte, value []byte) error
+		message *common.Message) error
 
 	// Close shuts down the producer; you must call this function before a producer
 	// object passes out of scope, as it may otherwise leak memory.
@@ -74,19 +77,28 @@ type AsyncProducer interface {
 	Close()
 
 	// AsyncSend is the input channel for the user to write messages to that they
-	// wish to send.
+	// wish to send, the callback of the message is called once it's acknowledged.
 	AsyncSend(ctx context.Context, topic string,
-		partition int32, key []byte, value []byte,
-		callback func()) error
+		partition int32, message *common.Message) error
 
 	// AsyncRunCallback process the messages that has sent to kafka,
 	// and run tha attached callback. the caller should call this
 	// method in a background goroutine
 	AsyncRunCallback(ctx context.Context) error
+
+	// CommitTxn commits the messages sent since the last commit, and then runs
+	// their callbacks. It's a no-op if the producer is not transactional.
+	CommitTxn() error
 }
 
 type saramaSyncProducer struct {
-	id       model.ChangeFeedID
+	id model.ChangeFeedID
+	// resolveBrokers and config are used to recreate the client
+	// if the brokers are unreachable.
+	resolveBrokers func(ctx context.Context) ([]string, error)
+	config         *sarama.Config
+
+	mu       sync.Mutex
 	client   sarama.Client
 	producer sarama.SyncProducer
 }
@@ -94,73 +106,148 @@ type saramaSyncProducer struct {
 func (p *saramaSyncProducer) SendMessage(
 	ctx context.Context,
 	topic string, partitionNum int32,
-	key []byte, value []byte,
+	message *common.Message,
 ) error {
-	_, _, err := p.producer.SendMessage(&sarama.ProducerMessage{
-		Topic:     topic,
-		Key:       sarama.ByteEncoder(key),
-		Value:     sarama.ByteEncoder(value),
-		Partition: partitionNum,
+	return p.sendWithRetry(ctx, func(producer sarama.SyncProducer) error {
+		_, _, err := producer.SendMessage(&sarama.ProducerMessage{
+			Topic:     topic,
+			Key:       sarama.ByteEncoder(message.Key),
+			Value:     sarama.ByteEncoder(message.Value),
+			Headers:   saramaHeaders(message.Headers),
+			Partition: partitionNum,
+		})
+		return err
 	})
-	return err
 }
 
 func (p *saramaSyncProducer) SendMessages(ctx context.Context,
 	topic string, partitionNum int32,
-	key []byte, value []byte,
+	message *common.Message,
 ) error {
+	headers := saramaHeaders(message.Headers)
 	msgs := make([]*sarama.ProducerMessage, partitionNum)
 	for i := 0; i < int(partitionNum); i++ {
 		msgs[i] = &sarama.ProducerMessage{
 			Topic:     topic,
-			Key:       sarama.ByteEncoder(key),
-			Value:     sarama.ByteEncoder(value),
+			Key:       sarama.ByteEncoder(message.Key),
+			Value:     sarama.ByteEncoder(message.Value),
+			Headers:   headers,
 			Partition: int32(i),
 		}
 	}
-	return p.producer.SendMessages(msgs)
+	return p.sendWithRetry(ctx, func(producer sarama.SyncProducer) error {
+		return producer.SendMessages(msgs)
+	})
 }
 
-func (p *saramaSyncProducer) Close() {
-	go func() {
-		// We need to close it asynchronously. Otherwise, we might get stuck
-		// with an unhealthy(i.e. Network jitter, isolation) state of Kafka.
-		// Factory has a background thread to fetch and update the metadata.
-		// If we close the client synchronously, we might get stuck.
-		// Safety:
-		// * If the kafka cluster is running well, it will be closed as soon as possible.
-		// * If there is a problem with the kafka cluster,
-		//   no data will be lost because this is a synchronous client.
-		// * There is a risk of goroutine leakage, but it is acceptable and our main
-		//   goal is not to get stuck with the owner tick.
-		start := time.Now()
-		if err := p.client.Close(); err != nil {
-			log.Warn("Close Kafka DDL client with error",
-				zap.String("namespace", p.id.Namespace),
-				zap.String("changefeed", p.id.ID),
-				zap.Duration("duration", time.Since(start)),
-				zap.Error(err))
-		} else {
-			log.Info("Kafka DDL client closed",
-				zap.String("namespace", p.id.Namespace),
-				zap.String("changefeed", p.id.ID),
-				zap.Duration("duration", time.Since(start)))
+// sendWithRetry sends the messages by the current producer, if the brokers
+// are unreachable, the brokers are resolved again and the messages are resent
+// by a new producer.
+func (p *saramaSyncProducer) sendWithRetry(
+	ctx context.Context, send func(producer sarama.SyncProducer) error,
+) error {
+	return retry.Do(ctx, func() error {
+		p.mu.Lock()
+		producer := p.producer
+		p.mu.Unlock()
+
+		err := send(producer)
+		if err == nil || !IsBrokerUnreachable(err) {
+			return err
 		}
-		start = time.Now()
-		err := p.producer.Close()
-		if err != nil {
-			log.Error("Close Kafka DDL producer with error",
-				zap.String("namespace", p.id.Namespace),
-				zap.String("changefeed", p.id.ID),
-				zap.Duration("duration", time.Since(start)),
-				zap.Error(err))
-		} else {
-			log.Info("Kafka DDL producer closed",
-				zap.String("namespace", p.id.Namespace),
-				zap.String("changefeed", p.id.ID),
-				zap.Duration("duration", time.Since(start)))
+		log.Warn("kafka brokers are unreachable, reset the sync producer",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID),
+			zap.Error(err))
+		if resetErr := p.reset(ctx, producer); resetErr != nil {
+			return resetErr
 		}
-	}()
+		return err
+	}, retry.WithBackoffBaseDelay(defaultRetryBackoff),
+		retry.WithMaxTries(defaultRetryMaxTries),
+		retry.WithIsRetryableErr(IsBrokerUnreachable))
+}
+
+// reset replaces the failed producer with a new one connected to the brokers
+// which are resolved again.
+func (p *saramaSyncProducer) reset(ctx context.Context, failed sarama.SyncProducer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the producer is already reset by others.
+	if p.producer != failed {
+		return nil
+	}
+
+	brokerEndpoints, err := p.resolveBrokers(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := sarama.NewClient(brokerEndpoints, p.config)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return errors.Trace(err)
+	}
+
+	go closeSyncProducer(p.id, p.client, p.producer)
+	p.client = client
+	p.producer = producer
+	log.Info("kafka sync producer is reset",
+		zap.String("namespace", p.id.Namespace),
+		zap.String("changefeed", p.id.ID),
+		zap.Strings("brokers", brokerEndpoints))
+	return nil
+}
+
+func (p *saramaSyncProducer) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// We need to close it asynchronously. Otherwise, we might get stuck
+	// with an unhealthy(i.e. Network jitter, isolation) state of Kafka.
+	// Factory has a background thread to fetch and update the metadata.
+	// If we close the client synchronously, we might get stuck.
+	// Safety:
+	// * If the kafka cluster is running well, it will be closed as soon as possible.
+	// * If there is a problem with the kafka cluster,
+	//   no data will be lost because this is a synchronous client.
+	// * There is a risk of goroutine leakage, but it is acceptable and our main
+	//   goal is not to get stuck with the owner tick.
+	go closeSyncProducer(p.id, p.client, p.producer)
+}
+
+func closeSyncProducer(
+	id model.ChangeFeedID, client sarama.Client, producer sarama.SyncProducer,
+) {
+	start := time.Now()
+	if err := client.Close(); err != nil {
+		log.Warn("Close Kafka DDL client with error",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+	} else {
+		log.Info("Kafka DDL client closed",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID),
+			zap.Duration("duration", time.Since(start)))
+	}
+	start = time.Now()
+	err := producer.Close()
+	if err != nil {
+		log.Error("Close Kafka DDL producer with error",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+	} else {
+		log.Info("Kafka DDL producer closed",
+			zap.String("namespace", id.Namespace),
+			zap.String("changefeed", id.ID),
+			zap.Duration("duration", time.Since(start)))
+	}
 }
 
 type saramaAsyncProducer struct {
@@ -168,6 +255,13 @@ type saramaAsyncProducer struct {
 	producer     sarama.AsyncProducer
 	changefeedID model.ChangeFeedID
 	failpointCh  chan error
+
+	// transactional indicates the producer is transactional, the callbacks of
+	// the messages are held in txnCallbacks until the transaction is committed.
+	// AsyncSend and CommitTxn are called by the same goroutine, so txnCallbacks
+	// is not protected by a lock.
+	transactional bool
+	txnCallbacks  []func()
 }
 
 func (p *saramaAsyncProducer) Close() {
@@ -262,15 +356,33 @@ func (p *saramaAsyncProducer) AsyncRunCallback(
 func (p *saramaAsyncProducer) AsyncSend(ctx context.Context,
 	topic string,
 	partition int32,
-	key []byte,
-	value []byte,
-	callback func(),
+	message *common.Message,
 ) error {
+	// the buffers of the message are released after it's acknowledged,
+	// since the producer holds the Key and Value until then.
+	callback := func() {
+		if message.Callback != nil {
+			message.Callback()
+		}
+		message.Release()
+	}
+	if p.transactional {
+		// Begin a new transaction for the first message after the last commit.
+		if p.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 {
+			if err := p.producer.BeginTxn(); err != nil {
+				return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+			}
+		}
+		p.txnCallbacks = append(p.txnCallbacks, callback)
+		// The callback is run after the transaction is committed.
+		callback = nil
+	}
 	msg := &sarama.ProducerMessage{
 		Topic:     topic,
 		Partition: partition,
-		Key:       sarama.StringEncoder(key),
-		Value:     sarama.ByteEncoder(value),
+		Key:       sarama.StringEncoder(message.Key),
+		Value:     sarama.ByteEncoder(message.Value),
+		Headers:   saramaHeaders(message.Headers),
 		Metadata:  callback,
 	}
 	select {
@@ -280,3 +392,43 @@ func (p *saramaAsyncProducer) AsyncSend(ctx context.Context,
 	}
 	return nil
 }
+
+// CommitTxn commits the transaction which contains the messages sent since the
+// last commit, it blocks until all the messages are flushed and the transaction
+// is committed.
+func (p *saramaAsyncProducer) CommitTxn() error {
+	if !p.transactional ||
+		p.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 {
+		return nil
+	}
+	start := time.Now()
+	if err := p.producer.CommitTxn(); err != nil {
+		log.Error("Commit kafka transaction failed",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.Int("messages", len(p.txnCallbacks)),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	for _, callback := range p.txnCallbacks {
+		callback()
+	}
+	p.txnCallbacks = p.txnCallbacks[:0]
+	return nil
+}
+
+// saramaHeaders converts the message headers to the sarama record headers.
+func saramaHeaders(headers []common.MessageHeader) []sarama.RecordHeader {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make([]sarama.RecordHeader, 0, len(headers))
+	for _, header := range headers {
+		result = append(result, sarama.RecordHeader{
+			Key:   []byte(header.Key),
+			Value: header.Value,
+		})
+	}
+	return result
+}
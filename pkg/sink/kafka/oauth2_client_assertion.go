@@ -0,0 +1,192 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pingcap/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// clientAssertionType is the client assertion type of the private_key_jwt
+	// client authentication, see RFC 7523.
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	// assertionLifetime is the lifetime of a client assertion, a new one is
+	// signed for each token request.
+	assertionLifetime = 5 * time.Minute
+)
+
+// assertionSigner signs the JWT client assertions by the private key.
+type assertionSigner struct {
+	key   crypto.Signer
+	keyID string
+	// alg is the JWS algorithm of the key, such as RS256 and ES256.
+	alg  string
+	hash crypto.Hash
+}
+
+// newAssertionSigner loads the PEM encoded RSA or ECDSA private key.
+func newAssertionSigner(keyPath, keyID string) (*assertionSigner, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data is found in the assertion key %s", keyPath)
+	}
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "parse the assertion key %s failed", keyPath)
+	}
+
+	s := &assertionSigner{keyID: keyID}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		s.key, s.alg, s.hash = k, "RS256", crypto.SHA256
+	case *ecdsa.PrivateKey:
+		s.key = k
+		switch k.Curve {
+		case elliptic.P256():
+			s.alg, s.hash = "ES256", crypto.SHA256
+		case elliptic.P384():
+			s.alg, s.hash = "ES384", crypto.SHA384
+		case elliptic.P521():
+			s.alg, s.hash = "ES512", crypto.SHA512
+		default:
+			return nil, errors.Errorf("unsupported curve %s of the assertion key %s",
+				k.Curve.Params().Name, keyPath)
+		}
+	default:
+		return nil, errors.Errorf("unsupported assertion key %s, "+
+			"only RSA and ECDSA keys are supported", keyPath)
+	}
+	return s, nil
+}
+
+// sign returns a JWT client assertion of the client for the token endpoint.
+func (s *assertionSigner) sign(clientID, tokenURL string, now time.Time) (string, error) {
+	header := map[string]string{"alg": s.alg, "typ": "JWT"}
+	if s.keyID != "" {
+		header["kid"] = s.keyID
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", errors.Trace(err)
+	}
+	claims := map[string]interface{}{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": tokenURL,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(assertionLifetime).Unix(),
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	var digest []byte
+	switch s.hash {
+	case crypto.SHA256:
+		sum := sha256.Sum256([]byte(signingInput))
+		digest = sum[:]
+	case crypto.SHA384:
+		sum := sha512.Sum384([]byte(signingInput))
+		digest = sum[:]
+	default:
+		sum := sha512.Sum512([]byte(signingInput))
+		digest = sum[:]
+	}
+	var signature []byte
+	switch k := s.key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, s.hash, digest)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, ss, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		// the JWS signature of ECDSA is the fixed size concatenation of r and s.
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		ss.FillBytes(signature[size:])
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// assertionTokenSource fetches a new token by the client credentials grant
+// with the private_key_jwt client authentication on each call, the token is
+// cached by the caller.
+type assertionTokenSource struct {
+	ctx    context.Context
+	cfg    *clientcredentials.Config
+	signer *assertionSigner
+}
+
+// Token implements the oauth2.TokenSource interface.
+func (s *assertionTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := s.signer.sign(s.cfg.ClientID, s.cfg.TokenURL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	for k, v := range s.cfg.EndpointParams {
+		params[k] = v
+	}
+	params.Set("client_assertion_type", clientAssertionType)
+	params.Set("client_assertion", assertion)
+	cfg := *s.cfg
+	cfg.EndpointParams = params
+	// the client is authenticated by the assertion in the request body.
+	cfg.AuthStyle = oauth2.AuthStyleInParams
+	return cfg.Token(s.ctx)
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAssertionKey(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "assertion.key")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// verifyAssertion verifies the signature of the JWT by the public key and
+// returns its header and claims.
+func verifyAssertion(
+	t *testing.T, assertion string, key crypto.PublicKey,
+) (map[string]interface{}, map[string]interface{}) {
+	parts := strings.Split(assertion, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		require.NoError(t, rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature))
+	case *ecdsa.PublicKey:
+		require.Len(t, signature, 64)
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		require.True(t, ecdsa.Verify(k, digest[:], r, s))
+	}
+
+	var header, claims map[string]interface{}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &header))
+	data, err = base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &claims))
+	return header, claims
+}
+
+func TestAssertionSigner(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, test := range []struct {
+		key crypto.Signer
+		alg string
+	}{
+		{key: rsaKey, alg: "RS256"},
+		{key: ecKey, alg: "ES256"},
+	} {
+		signer, err := newAssertionSigner(writeAssertionKey(t, test.key), "key-1")
+		require.NoError(t, err)
+		assertion, err := signer.sign("client-id", "http://idp/token", now)
+		require.NoError(t, err)
+
+		header, claims := verifyAssertion(t, assertion, test.key.Public())
+		require.Equal(t, test.alg, header["alg"])
+		require.Equal(t, "key-1", header["kid"])
+		require.Equal(t, "client-id", claims["iss"])
+		require.Equal(t, "client-id", claims["sub"])
+		require.Equal(t, "http://idp/token", claims["aud"])
+		require.NotEmpty(t, claims["jti"])
+		require.EqualValues(t, now.Add(assertionLifetime).Unix(), claims["exp"])
+	}
+
+	// the assertions are never reused.
+	signer, err := newAssertionSigner(writeAssertionKey(t, ecKey), "")
+	require.NoError(t, err)
+	first, err := signer.sign("client-id", "http://idp/token", now)
+	require.NoError(t, err)
+	second, err := signer.sign("client-id", "http://idp/token", now)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	header, _ := verifyAssertion(t, first, ecKey.Public())
+	require.NotContains(t, header, "kid")
+
+	invalidKey := filepath.Join(t.TempDir(), "invalid.key")
+	require.NoError(t, os.WriteFile(invalidKey, []byte("invalid"), 0o600))
+	_, err = newAssertionSigner(invalidKey, "")
+	require.ErrorContains(t, err, "no PEM data")
+	_, err = newAssertionSigner(filepath.Join(t.TempDir(), "not-exist.key"), "")
+	require.Error(t, err)
+}
+
+func TestAssertionTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	var requests, expiresIn int64
+	atomic.StoreInt64(&expiresIn, 3600)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		require.Empty(t, r.PostForm.Get("client_secret"))
+		require.Equal(t, clientAssertionType, r.PostForm.Get("client_assertion_type"))
+		_, claims := verifyAssertion(t, r.PostForm.Get("client_assertion"), key.Public())
+		require.Equal(t, "client-id", claims["iss"])
+
+		n := atomic.AddInt64(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`,
+			n, atomic.LoadInt64(&expiresIn))
+	}))
+	defer server.Close()
+
+	provider, err := newTokenProvider(context.Background(), &Options{
+		SASL: &security.SASL{
+			OAuth2: security.OAuth2{
+				ClientID:         "client-id",
+				TokenURL:         server.URL,
+				GrantType:        "client_credentials",
+				AssertionKeyPath: writeAssertionKey(t, key),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// the token is cached until it's about to expire.
+	token, err := provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token.Token)
+	token, err = provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token.Token)
+	require.EqualValues(t, 1, atomic.LoadInt64(&requests))
+
+	// the token which expires in the refresh window is refreshed.
+	provider, err = newTokenProvider(context.Background(), &Options{
+		SASL: &security.SASL{
+			OAuth2: security.OAuth2{
+				ClientID:         "client-id",
+				TokenURL:         server.URL,
+				AssertionKeyPath: writeAssertionKey(t, key),
+			},
+		},
+	})
+	require.NoError(t, err)
+	atomic.StoreInt64(&expiresIn, int64(tokenRefreshBeforeExpiry/time.Second)/2)
+	token, err = provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token.Token)
+	token, err = provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token-3", token.Token)
+}
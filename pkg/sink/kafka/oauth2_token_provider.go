@@ -16,6 +16,7 @@ package kafka
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
@@ -23,6 +24,11 @@ import (
 	"golang.org/x/oauth2/clientcredentials"
 )
 
+// tokenRefreshBeforeExpiry is the duration before the expiry of the cached
+// access token to fetch a new one, so the brokers never see an expired token
+// even if the token endpoint is slow.
+const tokenRefreshBeforeExpiry = time.Minute
+
 // tsokenProvider is a user-defined callback for generating
 // access tokens for SASL/OAUTHBEARER auth.
 type tokenProvider struct {
@@ -77,14 +83,36 @@ func newTokenProvider(ctx context.Context, o *Options) (sarama.AccessTokenProvid
 		return nil, errors.Trace(err)
 	}
 
-	cfg := clientcredentials.Config{
+	cfg := &clientcredentials.Config{
 		ClientID:       o.SASL.OAuth2.ClientID,
 		ClientSecret:   o.SASL.OAuth2.ClientSecret,
 		TokenURL:       tokenURL.String(),
 		EndpointParams: endpointParams,
 		Scopes:         o.SASL.OAuth2.Scopes,
 	}
+	var source oauth2.TokenSource = &clientCredentialsTokenSource{ctx: ctx, cfg: cfg}
+	if o.SASL.OAuth2.AssertionKeyPath != "" {
+		signer, err := newAssertionSigner(
+			o.SASL.OAuth2.AssertionKeyPath, o.SASL.OAuth2.AssertionKeyID)
+		if err != nil {
+			return nil, err
+		}
+		source = &assertionTokenSource{ctx: ctx, cfg: cfg, signer: signer}
+	}
+	// the token is cached until it's about to expire.
 	return &tokenProvider{
-		tokenSource: cfg.TokenSource(ctx),
+		tokenSource: oauth2.ReuseTokenSourceWithExpiry(nil, source, tokenRefreshBeforeExpiry),
 	}, nil
 }
+
+// clientCredentialsTokenSource fetches a new token by the client credentials
+// grant on each call, the token is cached by the caller.
+type clientCredentialsTokenSource struct {
+	ctx context.Context
+	cfg *clientcredentials.Config
+}
+
+// Token implements the oauth2.TokenSource interface.
+func (s *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	return s.cfg.Token(s.ctx)
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Shopify/sarama"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/imdario/mergo"
 	"github.com/pingcap/errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/security/secret"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +42,21 @@ const (
 	defaultPartitionNum = 3
 )
 
+const (
+	// eventHubsSASLUser is the SASL user of Event Hubs when the connection
+	// string is used as the password.
+	eventHubsSASLUser = "$ConnectionString"
+	// eventHubsMaxMessageBytes is the max size of the produce requests of Event Hubs.
+	// See: https://learn.microsoft.com/en-us/azure/event-hubs/apache-kafka-configurations
+	eventHubsMaxMessageBytes = 1046528
+	// eventHubsMaxBatchMessages caps the messages of a produce request, which
+	// prevents the requests from being throttled by Event Hubs.
+	eventHubsMaxBatchMessages = 1000
+	// eventHubsMetadataRefreshFrequency is less than the idle timeout of the
+	// connections of Event Hubs, which is 240 seconds.
+	eventHubsMetadataRefreshFrequency = 3 * time.Minute
+)
+
 const (
 	// BrokerMessageMaxBytesConfigName specifies the largest record batch size allowed by
 	// Kafka brokers.
@@ -55,6 +72,12 @@ const (
 	// See: https://kafka.apache.org/documentation/#brokerconfigs_min.insync.replicas and
 	// https://kafka.apache.org/documentation/#topicconfigs_min.insync.replicas
 	MinInsyncReplicasConfigName = "min.insync.replicas"
+	// TopicCleanupPolicyConfigName specifies the retention policy of the log
+	// segments of Kafka topics.
+	// See: https://kafka.apache.org/documentation/#topicconfigs_cleanup.policy
+	TopicCleanupPolicyConfigName = "cleanup.policy"
+	// TopicCleanupPolicyCompact retains the latest message of each key.
+	TopicCleanupPolicyCompact = "compact"
 )
 
 const (
@@ -68,6 +91,8 @@ const (
 	SASLTypeGSSAPI = "GSSAPI"
 	// SASLTypeOAuth represents the SASL/OAUTHBEARER mechanism (Kafka 2.0.0+)
 	SASLTypeOAuth = "OAUTHBEARER"
+	// SASLTypeAWSMSKIAM represents the IAM authentication of Amazon MSK.
+	SASLTypeAWSMSKIAM = "AWS_MSK_IAM"
 )
 
 // RequiredAcks is used in Produce Requests to tell the broker how many replica acknowledgements
@@ -131,11 +156,25 @@ type urlConfig struct {
 	Cert                         *string `form:"cert"`
 	Key                          *string `form:"key"`
 	InsecureSkipVerify           *bool   `form:"insecure-skip-verify"`
+	DNSSRVDiscovery              *bool   `form:"dns-srv-discovery"`
+	EnableKafkaTransactions      *bool   `form:"enable-kafka-transactions"`
+	DeadLetterTopic              *string `form:"dead-letter-topic"`
+	DeadLetterStorageURI         *string `form:"dead-letter-storage-uri"`
+	DDLTopic                     *string `form:"ddl-topic"`
+	EventHubs                    *bool   `form:"event-hubs"`
+	EventHubsConnectionString    *string `form:"event-hubs-connection-string"`
+	Linger                       *string `form:"linger"`
+	BatchBytes                   *int    `form:"batch-bytes"`
+	MaxInflight                  *int    `form:"max-inflight"`
+	CompressionLevel             *int    `form:"compression-level"`
 }
 
 // Options stores user specified configurations
 type Options struct {
 	BrokerEndpoints []string
+	// DNSSRVDiscovery indicates that the `BrokerEndpoints` are DNS SRV record names,
+	// the real broker addresses are looked up each time a client is created.
+	DNSSRVDiscovery bool
 
 	// control whether to create topic
 	AutoCreate   bool
@@ -161,6 +200,39 @@ type Options struct {
 	DialTimeout  time.Duration
 	WriteTimeout time.Duration
 	ReadTimeout  time.Duration
+
+	// EnableTransactions indicates that the DML messages are sent by the
+	// idempotent and transactional producer whose transactional ID is
+	// TransactionalID, so the consumers with `isolation.level=read_committed`
+	// only read the committed messages.
+	EnableTransactions bool
+	TransactionalID    string
+
+	// DeadLetterTopic and DeadLetterStorageURI are the destinations of the
+	// events which can not be encoded, at most one of them can be set.
+	DeadLetterTopic      string
+	DeadLetterStorageURI string
+
+	// DDLTopic is the topic which the schema changes are sent to, the DDL
+	// events aren't sent to the data topics if it's set.
+	DDLTopic string
+
+	// EventHubs indicates that the brokers are the Kafka endpoint of Azure
+	// Event Hubs, which doesn't support describing configs and creating topics.
+	EventHubs bool
+
+	// Linger, BatchBytes and MaxInflight tune the batches of the kafka-go
+	// producer. BatchBytes is capped by MaxMessageBytes if it's 0 or larger,
+	// and MaxInflight is unlimited if it's 0.
+	Linger      time.Duration
+	BatchBytes  int
+	MaxInflight int
+	// CompressionLevel is the level of the compression algorithm, it's only
+	// supported by the sarama producer and rejected by Kafka sink v2.
+	CompressionLevel int
+
+	// TopicConfigs are the topic level configurations of the auto-created topics.
+	TopicConfigs map[string]string
 }
 
 // NewOptions returns a default Kafka configuration
@@ -179,6 +251,8 @@ func NewOptions() *Options {
 		DialTimeout:        10 * time.Second,
 		WriteTimeout:       10 * time.Second,
 		ReadTimeout:        10 * time.Second,
+		Linger:             5 * time.Millisecond,
+		CompressionLevel:   sarama.CompressionLevelDefault,
 	}
 }
 
@@ -215,8 +289,6 @@ func (o *Options) SetPartitionNum(realPartitionCount int32) error {
 func (o *Options) Apply(changefeedID model.ChangeFeedID,
 	sinkURI *url.URL, replicaConfig *config.ReplicaConfig,
 ) error {
-	o.BrokerEndpoints = strings.Split(sinkURI.Host, ",")
-
 	var err error
 	req := &http.Request{URL: sinkURI}
 	urlParameter := &urlConfig{}
@@ -226,6 +298,14 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 	if urlParameter, err = mergeConfig(replicaConfig, urlParameter); err != nil {
 		return err
 	}
+
+	if urlParameter.DNSSRVDiscovery != nil {
+		o.DNSSRVDiscovery = *urlParameter.DNSSRVDiscovery
+	}
+	o.BrokerEndpoints, err = parseBrokerEndpoints(sinkURI.Host, o.DNSSRVDiscovery)
+	if err != nil {
+		return err
+	}
 	if urlParameter.PartitionNum != nil {
 		o.PartitionNum = *urlParameter.PartitionNum
 		if o.PartitionNum <= 0 {
@@ -249,6 +329,38 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 		o.Compression = *urlParameter.Compression
 	}
 
+	if urlParameter.CompressionLevel != nil {
+		o.CompressionLevel = *urlParameter.CompressionLevel
+	}
+
+	if urlParameter.Linger != nil && *urlParameter.Linger != "" {
+		a, err := time.ParseDuration(*urlParameter.Linger)
+		if err != nil {
+			return err
+		}
+		if a <= 0 {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"linger should be greater than 0, but got %s", a)
+		}
+		o.Linger = a
+	}
+
+	if urlParameter.BatchBytes != nil {
+		if *urlParameter.BatchBytes <= 0 {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"batch-bytes should be greater than 0, but got %d", *urlParameter.BatchBytes)
+		}
+		o.BatchBytes = *urlParameter.BatchBytes
+	}
+
+	if urlParameter.MaxInflight != nil {
+		if *urlParameter.MaxInflight <= 0 {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"max-inflight should be greater than 0, but got %d", *urlParameter.MaxInflight)
+		}
+		o.MaxInflight = *urlParameter.MaxInflight
+	}
+
 	var kafkaClientID string
 	if urlParameter.KafkaClientID != nil {
 		kafkaClientID = *urlParameter.KafkaClientID
@@ -298,6 +410,40 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 		o.RequiredAcks = r
 	}
 
+	if urlParameter.EnableKafkaTransactions != nil && *urlParameter.EnableKafkaTransactions {
+		// The idempotent producer requires the acknowledgement from all the
+		// in-sync replicas.
+		if o.RequiredAcks != WaitForAll {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"required-acks should be -1 when enable-kafka-transactions is true, but got %d",
+				o.RequiredAcks)
+		}
+		o.EnableTransactions = true
+		o.TransactionalID, err = newKafkaTransactionalID(
+			config.GetGlobalServerConfig().AdvertiseAddr, changefeedID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if urlParameter.DeadLetterTopic != nil {
+		o.DeadLetterTopic = *urlParameter.DeadLetterTopic
+	}
+	if urlParameter.DeadLetterStorageURI != nil {
+		o.DeadLetterStorageURI = *urlParameter.DeadLetterStorageURI
+	}
+	if o.DeadLetterTopic != "" && o.DeadLetterStorageURI != "" {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"dead-letter-topic and dead-letter-storage-uri can not be set at the same time")
+	}
+	if urlParameter.DDLTopic != nil {
+		o.DDLTopic = *urlParameter.DDLTopic
+	}
+	// the topic configurations can only be set in the changefeed config file.
+	if replicaConfig.Sink != nil && replicaConfig.Sink.KafkaConfig != nil {
+		o.TopicConfigs = replicaConfig.Sink.KafkaConfig.TopicConfigs
+	}
+
 	err = o.applySASL(urlParameter, replicaConfig)
 	if err != nil {
 		return err
@@ -308,6 +454,75 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 		return err
 	}
 
+	// MSK only accepts the IAM authentication on the TLS listeners.
+	if o.SASL.SASLMechanism == security.AWSMSKIAMMechanism {
+		if urlParameter.EnableTLS != nil && !*urlParameter.EnableTLS {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"enable-tls can not be false when sasl-mechanism is AWS_MSK_IAM")
+		}
+		o.EnableTLS = true
+	}
+
+	if urlParameter.EventHubs != nil && *urlParameter.EventHubs {
+		if err = o.applyEventHubs(urlParameter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyEventHubs adjusts the options to the quirks of the Kafka endpoint of
+// Azure Event Hubs. It authenticates by SASL/PLAIN over TLS, the user is
+// `$ConnectionString` and the password is the connection string. It neither
+// describes the configs nor creates the topics, so the event hubs must be
+// created in advance.
+func (o *Options) applyEventHubs(urlParameter *urlConfig) error {
+	connectionString := o.SASL.SASLPassword
+	if urlParameter.EventHubsConnectionString != nil &&
+		*urlParameter.EventHubsConnectionString != "" {
+		connectionString = *urlParameter.EventHubsConnectionString
+	}
+	if !strings.Contains(connectionString, "Endpoint=") {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"event-hubs-connection-string must be a connection string of the Event Hubs namespace")
+	}
+	if o.SASL.SASLMechanism != security.UnknownMechanism &&
+		o.SASL.SASLMechanism != security.PlainMechanism {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"Event Hubs only supports SASL mechanism PLAIN, but got %s", o.SASL.SASLMechanism)
+	}
+	if urlParameter.EnableTLS != nil && !*urlParameter.EnableTLS {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"enable-tls can not be false when event-hubs is true")
+	}
+	if urlParameter.AutoCreateTopic != nil && *urlParameter.AutoCreateTopic {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"auto-create-topic can not be true when event-hubs is true, " +
+				"the event hubs should be created in advance")
+	}
+	if o.EnableTransactions {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"enable-kafka-transactions can not be true when event-hubs is true")
+	}
+
+	o.EventHubs = true
+	o.SASL.SASLMechanism = security.PlainMechanism
+	o.SASL.SASLUser = eventHubsSASLUser
+	o.SASL.SASLPassword = connectionString
+	o.EnableTLS = true
+	o.AutoCreate = false
+	// the limit applies to the produce requests, so it caps the batches too.
+	if o.MaxMessageBytes > eventHubsMaxMessageBytes {
+		log.Warn("max-message-bytes is larger than the limit of Event Hubs, "+
+			"use the limit to initialize the Kafka producer",
+			zap.Int("max-message-bytes", o.MaxMessageBytes),
+			zap.Int("limit", eventHubsMaxMessageBytes))
+		o.MaxMessageBytes = eventHubsMaxMessageBytes
+	}
+	if o.MaxMessages == 0 || o.MaxMessages > eventHubsMaxBatchMessages {
+		o.MaxMessages = eventHubsMaxBatchMessages
+	}
 	return nil
 }
 
@@ -345,6 +560,17 @@ func mergeConfig(
 		dest.Cert = fileConifg.Cert
 		dest.Key = fileConifg.Key
 		dest.InsecureSkipVerify = fileConifg.InsecureSkipVerify
+		dest.DNSSRVDiscovery = fileConifg.DNSSRVDiscovery
+		dest.EnableKafkaTransactions = fileConifg.EnableKafkaTransactions
+		dest.DeadLetterTopic = fileConifg.DeadLetterTopic
+		dest.DeadLetterStorageURI = fileConifg.DeadLetterStorageURI
+		dest.DDLTopic = fileConifg.DDLTopic
+		dest.EventHubs = fileConifg.EventHubs
+		dest.EventHubsConnectionString = fileConifg.EventHubsConnectionString
+		dest.Linger = fileConifg.Linger
+		dest.BatchBytes = fileConifg.BatchBytes
+		dest.MaxInflight = fileConifg.MaxInflight
+		dest.CompressionLevel = fileConifg.CompressionLevel
 	}
 	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
 		return nil, err
@@ -352,6 +578,48 @@ func mergeConfig(
 	return dest, nil
 }
 
+// CheckSinkV2Compatibility checks the options which are only supported by the
+// sarama producer. It returns the options ignored by the Kafka sink v2, and an
+// error if any option can't work with it.
+func CheckSinkV2Compatibility(
+	sinkURI *url.URL, replicaConfig *config.ReplicaConfig,
+) ([]string, error) {
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+	}
+	urlParameter, err := mergeConfig(replicaConfig, urlParameter)
+	if err != nil {
+		return nil, err
+	}
+
+	if urlParameter.SASLMechanism != nil {
+		mechanism, err := security.SASLMechanismFromString(*urlParameter.SASLMechanism)
+		if err == nil && mechanism == security.OAuthMechanism {
+			return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"sasl-mechanism %s is not supported by Kafka sink v2", mechanism)
+		}
+	}
+	if urlParameter.EnableKafkaTransactions != nil && *urlParameter.EnableKafkaTransactions {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"enable-kafka-transactions is not supported by Kafka sink v2")
+	}
+	// kafka-go shares the compression codecs in the whole process, so the
+	// level can't be set for a changefeed.
+	if urlParameter.CompressionLevel != nil {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"compression-level is not supported by Kafka sink v2")
+	}
+
+	var ignored []string
+	// kafka-go negotiates the protocol versions with the brokers.
+	if urlParameter.KafkaVersion != nil {
+		ignored = append(ignored, "kafka-version")
+	}
+	return ignored, nil
+}
+
 func (o *Options) applyTLS(params *urlConfig) error {
 	if params.CA != nil && *params.CA != "" {
 		o.Credential.CAPath = *params.CA
@@ -362,7 +630,11 @@ func (o *Options) applyTLS(params *urlConfig) error {
 	}
 
 	if params.Key != nil && *params.Key != "" {
-		o.Credential.KeyPath = *params.Key
+		keyPath, err := secret.ResolveFile(*params.Key)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.Credential.KeyPath = keyPath
 	}
 
 	if o.Credential != nil && !o.Credential.IsEmpty() &&
@@ -405,7 +677,11 @@ func (o *Options) applySASL(urlParameter *urlConfig, replicaConfig *config.Repli
 	}
 
 	if urlParameter.SASLPassword != nil && *urlParameter.SASLPassword != "" {
-		o.SASL.SASLPassword = *urlParameter.SASLPassword
+		password, err := secret.Resolve(*urlParameter.SASLPassword)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.SASL.SASLPassword = password
 	}
 
 	if urlParameter.SASLMechanism != nil && *urlParameter.SASLMechanism != "" {
@@ -442,7 +718,11 @@ func (o *Options) applySASL(urlParameter *urlConfig, replicaConfig *config.Repli
 	}
 
 	if urlParameter.SASLGssAPIPassword != nil && *urlParameter.SASLGssAPIPassword != "" {
-		o.SASL.GSSAPI.Password = *urlParameter.SASLGssAPIPassword
+		password, err := secret.Resolve(*urlParameter.SASLGssAPIPassword)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		o.SASL.GSSAPI.Password = password
 	}
 
 	if urlParameter.SASLGssAPIRealm != nil && *urlParameter.SASLGssAPIRealm != "" {
@@ -468,6 +748,10 @@ func (o *Options) applySASL(urlParameter *urlConfig, replicaConfig *config.Repli
 				return cerror.ErrKafkaInvalidConfig.GenWithStack(
 					"OAuth2 client secret cannot be empty")
 			}
+			clientSecret, err := secret.Resolve(clientSecret)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+			}
 
 			// BASE64 decode the client secret
 			decodedClientSecret, err := base64.StdEncoding.DecodeString(clientSecret)
@@ -488,6 +772,24 @@ func (o *Options) applySASL(urlParameter *urlConfig, replicaConfig *config.Repli
 			o.SASL.OAuth2.TokenURL = tokenURL
 		}
 
+		if replicaConfig.Sink.KafkaConfig.SASLOAuthAssertionKey != nil {
+			assertionKey := *replicaConfig.Sink.KafkaConfig.SASLOAuthAssertionKey
+			if assertionKey == "" {
+				return cerror.ErrKafkaInvalidConfig.GenWithStack(
+					"OAuth2 assertion key cannot be empty")
+			}
+			// the private key may be a reference to the secret store.
+			assertionKeyPath, err := secret.ResolveFile(assertionKey)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+			}
+			o.SASL.OAuth2.AssertionKeyPath = assertionKeyPath
+		}
+
+		if replicaConfig.Sink.KafkaConfig.SASLOAuthAssertionKeyID != nil {
+			o.SASL.OAuth2.AssertionKeyID = *replicaConfig.Sink.KafkaConfig.SASLOAuthAssertionKeyID
+		}
+
 		if o.SASL.OAuth2.IsEnable() {
 			if o.SASL.SASLMechanism != security.OAuthMechanism {
 				return cerror.ErrKafkaInvalidConfig.GenWithStack(
@@ -512,6 +814,24 @@ func (o *Options) applySASL(urlParameter *urlConfig, replicaConfig *config.Repli
 		if replicaConfig.Sink.KafkaConfig.SASLOAuthAudience != nil {
 			o.SASL.OAuth2.Audience = *replicaConfig.Sink.KafkaConfig.SASLOAuthAudience
 		}
+
+		if replicaConfig.Sink.KafkaConfig.SASLAWSRegion != nil {
+			o.SASL.AWSMSKIAM.Region = *replicaConfig.Sink.KafkaConfig.SASLAWSRegion
+		}
+
+		if replicaConfig.Sink.KafkaConfig.SASLAWSRoleARN != nil {
+			o.SASL.AWSMSKIAM.RoleARN = *replicaConfig.Sink.KafkaConfig.SASLAWSRoleARN
+		}
+
+		if replicaConfig.Sink.KafkaConfig.SASLAWSRoleSessionName != nil {
+			o.SASL.AWSMSKIAM.RoleSessionName = *replicaConfig.Sink.KafkaConfig.SASLAWSRoleSessionName
+		}
+
+		if o.SASL.AWSMSKIAM.IsEnable() && o.SASL.SASLMechanism != security.AWSMSKIAMMechanism {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"AWS IAM is only supported with SASL mechanism type AWS_MSK_IAM, but got %s",
+				o.SASL.SASLMechanism)
+		}
 	}
 
 	return nil
@@ -522,6 +842,12 @@ type AutoCreateTopicConfig struct {
 	AutoCreate        bool
 	PartitionNum      int32
 	ReplicationFactor int16
+	// ConfigEntries are the topic level configurations of the created topics.
+	ConfigEntries map[string]string
+	// Overrides returns the partition number and the topic level configurations
+	// which override the above ones for the topic, the partition number isn't
+	// overridden if it's 0.
+	Overrides func(topic string) (int32, map[string]string)
 }
 
 // DeriveTopicConfig derive a `topicConfig` from the `Options`
@@ -530,9 +856,37 @@ func (o *Options) DeriveTopicConfig() *AutoCreateTopicConfig {
 		AutoCreate:        o.AutoCreate,
 		PartitionNum:      o.PartitionNum,
 		ReplicationFactor: o.ReplicationFactor,
+		ConfigEntries:     o.TopicConfigs,
 	}
 }
 
+// TopicDetail returns the detail of the topic to be created.
+func (c *AutoCreateTopicConfig) TopicDetail(topic string) *TopicDetail {
+	detail := &TopicDetail{
+		Name:              topic,
+		NumPartitions:     c.PartitionNum,
+		ReplicationFactor: c.ReplicationFactor,
+	}
+	var overrides map[string]string
+	if c.Overrides != nil {
+		var partitionNum int32
+		partitionNum, overrides = c.Overrides(topic)
+		if partitionNum > 0 {
+			detail.NumPartitions = partitionNum
+		}
+	}
+	if len(c.ConfigEntries) != 0 || len(overrides) != 0 {
+		detail.ConfigEntries = make(map[string]string, len(c.ConfigEntries)+len(overrides))
+		for name, value := range c.ConfigEntries {
+			detail.ConfigEntries[name] = value
+		}
+		for name, value := range overrides {
+			detail.ConfigEntries[name] = value
+		}
+	}
+	return detail
+}
+
 var (
 	validClientID     = regexp.MustCompile(`\A[A-Za-z0-9._-]+\z`)
 	commonInvalidChar = regexp.MustCompile(`[\?:,"]`)
@@ -556,6 +910,23 @@ func NewKafkaClientID(captureAddr string,
 	return
 }
 
+// newKafkaTransactionalID generates the transactional ID of the kafka producer,
+// it must be unique for each capture of the changefeed and keep the same after
+// the capture restarts, so the unfinished transaction of the previous producer
+// is aborted by the broker.
+func newKafkaTransactionalID(
+	captureAddr string, changefeedID model.ChangeFeedID,
+) (string, error) {
+	transactionalID := fmt.Sprintf("TiCDC_txn_%s_%s_%s",
+		captureAddr, changefeedID.Namespace, changefeedID.ID)
+	transactionalID = commonInvalidChar.ReplaceAllString(transactionalID, "_")
+	if !validClientID.MatchString(transactionalID) {
+		return "", cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"invalid kafka transactional ID %s", transactionalID)
+	}
+	return transactionalID, nil
+}
+
 // AdjustOptions adjust the `Options` and `sarama.Config` by condition.
 func AdjustOptions(
 	ctx context.Context,
@@ -568,6 +939,17 @@ func AdjustOptions(
 		return errors.Trace(err)
 	}
 
+	// Event Hubs doesn't support describing the configs, the limits are
+	// applied when the options are built.
+	if options.EventHubs {
+		info, exists := topics[topic]
+		if !exists {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"event hub %s not found, it should be created in advance", topic)
+		}
+		return options.SetPartitionNum(info.NumPartitions)
+	}
+
 	// Only check replicationFactor >= minInsyncReplicas when producer's required acks is -1.
 	// If we don't check it, the producer probably can not send message to the topic.
 	// Because it will wait for the ack from all replicas. But we do not have enough replicas.
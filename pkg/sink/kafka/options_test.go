@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/stretchr/testify/require"
 )
@@ -191,6 +192,320 @@ func TestTimeout(t *testing.T) {
 	require.Equal(t, 2*time.Minute, options.WriteTimeout)
 }
 
+func TestApplyEnableKafkaTransactions(t *testing.T) {
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		EnableKafkaTransactions: aws.Bool(true),
+	}
+
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.True(t, options.EnableTransactions)
+	require.NotEmpty(t, options.TransactionalID)
+
+	transactionalID, err := newKafkaTransactionalID("127.0.0.1:8300", model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	require.Equal(t, "TiCDC_txn_127.0.0.1_8300_default_test", transactionalID)
+
+	saramaConfig, err := NewSaramaConfig(context.Background(), options)
+	require.NoError(t, err)
+	enableSaramaTransactions(saramaConfig, options.TransactionalID)
+	require.NoError(t, saramaConfig.Validate())
+
+	// The transactional producer requires acks from all the in-sync replicas.
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?required-acks=1")
+	require.NoError(t, err)
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "required-acks should be -1")
+}
+
+func TestApplyDDLTopic(t *testing.T) {
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test?ddl-topic=ddl")
+	require.NoError(t, err)
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, "ddl", options.DDLTopic)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		DDLTopic: aws.String("schema-changes"),
+	}
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test")
+	require.NoError(t, err)
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, "schema-changes", options.DDLTopic)
+}
+
+func TestApplyTopicConfigs(t *testing.T) {
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		TopicConfigs: map[string]string{
+			"retention.ms":        "604800000",
+			"min.insync.replicas": "2",
+		},
+	}
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test?partition-num=3")
+	require.NoError(t, err)
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+
+	topicCfg := options.DeriveTopicConfig()
+	detail := topicCfg.TopicDetail("kafka-test")
+	require.Equal(t, int32(3), detail.NumPartitions)
+	require.Equal(t, replicaConfig.Sink.KafkaConfig.TopicConfigs, detail.ConfigEntries)
+
+	// the overrides of the topic take precedence.
+	topicCfg.Overrides = func(topic string) (int32, map[string]string) {
+		if topic != "orders" {
+			return 0, nil
+		}
+		return 12, map[string]string{"retention.ms": "86400000", "cleanup.policy": "delete"}
+	}
+	detail = topicCfg.TopicDetail("orders")
+	require.Equal(t, int32(12), detail.NumPartitions)
+	require.Equal(t, map[string]string{
+		"retention.ms":        "86400000",
+		"min.insync.replicas": "2",
+		"cleanup.policy":      "delete",
+	}, detail.ConfigEntries)
+	detail = topicCfg.TopicDetail("kafka-test")
+	require.Equal(t, int32(3), detail.NumPartitions)
+	require.Equal(t, replicaConfig.Sink.KafkaConfig.TopicConfigs, detail.ConfigEntries)
+	// the global configurations are never modified.
+	require.Equal(t, "604800000", options.TopicConfigs["retention.ms"])
+
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Nil(t, options.DeriveTopicConfig().TopicDetail("kafka-test").ConfigEntries)
+}
+
+func TestApplyDeadLetter(t *testing.T) {
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test?dead-letter-topic=dlq")
+	require.NoError(t, err)
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, "dlq", options.DeadLetterTopic)
+	require.Empty(t, options.DeadLetterStorageURI)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		DeadLetterStorageURI: aws.String("file:///tmp/dlq"),
+	}
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test")
+	require.NoError(t, err)
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Empty(t, options.DeadLetterTopic)
+	require.Equal(t, "file:///tmp/dlq", options.DeadLetterStorageURI)
+
+	// only one of the dead-letter destinations can be set.
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?dead-letter-topic=dlq")
+	require.NoError(t, err)
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "can not be set at the same time")
+}
+
+func TestApplyEventHubs(t *testing.T) {
+	connectionString := "Endpoint=sb://test.servicebus.windows.net/;" +
+		"SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=key"
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		EventHubs:                 aws.Bool(true),
+		EventHubsConnectionString: aws.String(connectionString),
+	}
+	sinkURI, err := url.Parse("kafka://test.servicebus.windows.net:9093/kafka-test")
+	require.NoError(t, err)
+
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.True(t, options.EventHubs)
+	require.True(t, options.EnableTLS)
+	require.False(t, options.AutoCreate)
+	require.Equal(t, security.PlainMechanism, options.SASL.SASLMechanism)
+	require.Equal(t, eventHubsSASLUser, options.SASL.SASLUser)
+	require.Equal(t, connectionString, options.SASL.SASLPassword)
+	require.Equal(t, eventHubsMaxMessageBytes, options.MaxMessageBytes)
+	require.Equal(t, eventHubsMaxBatchMessages, options.MaxMessages)
+
+	saramaConfig, err := NewSaramaConfig(context.Background(), options)
+	require.NoError(t, err)
+	require.True(t, saramaConfig.Net.TLS.Enable)
+	require.True(t, saramaConfig.Net.SASL.Enable)
+	require.Equal(t, eventHubsMetadataRefreshFrequency, saramaConfig.Metadata.RefreshFrequency)
+
+	// the connection string can be set as the sasl password.
+	sinkURI, err = url.Parse("kafka://test.servicebus.windows.net:9093/kafka-test?event-hubs=true&" +
+		"sasl-password=" + url.QueryEscape(connectionString))
+	require.NoError(t, err)
+	options = NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, connectionString, options.SASL.SASLPassword)
+	require.Equal(t, eventHubsMaxMessageBytes, options.MaxMessageBytes)
+
+	// the connection string is required, and the conflicting options are rejected.
+	for _, query := range []string{
+		"event-hubs=true",
+		"event-hubs=true&sasl-password=password",
+		"event-hubs=true&sasl-mechanism=SCRAM-SHA-256&sasl-password=" + url.QueryEscape(connectionString),
+		"event-hubs=true&enable-tls=false&sasl-password=" + url.QueryEscape(connectionString),
+		"event-hubs=true&auto-create-topic=true&sasl-password=" + url.QueryEscape(connectionString),
+	} {
+		sinkURI, err = url.Parse("kafka://test.servicebus.windows.net:9093/kafka-test?" + query)
+		require.NoError(t, err)
+		options = NewOptions()
+		err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+		require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err), query)
+	}
+}
+
+func TestApplyAWSMSKIAM(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		SASLAWSRegion:  aws.String("us-east-1"),
+		SASLAWSRoleARN: aws.String("arn:aws:iam::123456789012:role/ticdc"),
+	}
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9098/kafka-test?sasl-mechanism=aws_msk_iam")
+	require.NoError(t, err)
+
+	options := NewOptions()
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.True(t, options.EnableTLS)
+	require.Equal(t, security.AWSMSKIAMMechanism, options.SASL.SASLMechanism)
+	require.Equal(t, security.AWSMSKIAM{
+		Region:  "us-east-1",
+		RoleARN: "arn:aws:iam::123456789012:role/ticdc",
+	}, options.SASL.AWSMSKIAM)
+
+	// sarama authenticates by the IAM tokens of SASL/OAUTHBEARER.
+	saramaConfig, err := NewSaramaConfig(context.Background(), options)
+	require.NoError(t, err)
+	require.True(t, saramaConfig.Net.SASL.Enable)
+	require.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), saramaConfig.Net.SASL.Mechanism)
+	require.IsType(t, &AWSMSKIAMSigner{}, saramaConfig.Net.SASL.TokenProvider)
+
+	// the IAM options are rejected with the other mechanisms, and TLS can't
+	// be disabled.
+	for _, query := range []string{
+		"sasl-mechanism=plain&sasl-user=user&sasl-password=password",
+		"sasl-mechanism=aws_msk_iam&enable-tls=false",
+	} {
+		sinkURI, err = url.Parse("kafka://127.0.0.1:9098/kafka-test?" + query)
+		require.NoError(t, err)
+		options = NewOptions()
+		err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+		require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err), query)
+	}
+}
+
+func TestApplyProducerTuning(t *testing.T) {
+	t.Parallel()
+
+	options := NewOptions()
+	require.Equal(t, 5*time.Millisecond, options.Linger)
+	require.Equal(t, sarama.CompressionLevelDefault, options.CompressionLevel)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		Linger:      aws.String("20ms"),
+		BatchBytes:  aws.Int(4096),
+		MaxInflight: aws.Int(16),
+	}
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test?compression=zstd&compression-level=3")
+	require.NoError(t, err)
+	err = options.Apply(model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, 20*time.Millisecond, options.Linger)
+	require.Equal(t, 4096, options.BatchBytes)
+	require.Equal(t, 16, options.MaxInflight)
+	require.Equal(t, 3, options.CompressionLevel)
+
+	saramaConfig, err := NewSaramaConfig(context.Background(), options)
+	require.NoError(t, err)
+	require.Equal(t, sarama.CompressionZSTD, saramaConfig.Producer.Compression)
+	require.Equal(t, 3, saramaConfig.Producer.CompressionLevel)
+
+	for _, param := range []string{
+		"linger=0s", "batch-bytes=0", "max-inflight=-1",
+	} {
+		sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?" + param)
+		require.NoError(t, err)
+		err = NewOptions().Apply(model.DefaultChangeFeedID("test"), sinkURI,
+			config.GetDefaultReplicaConfig())
+		require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err), param)
+	}
+}
+
+func TestCheckSinkV2Compatibility(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test?linger=10ms")
+	require.NoError(t, err)
+	ignored, err := CheckSinkV2Compatibility(sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Empty(t, ignored)
+
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?kafka-version=2.4.0")
+	require.NoError(t, err)
+	ignored, err = CheckSinkV2Compatibility(sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, []string{"kafka-version"}, ignored)
+
+	// the compression level of the kafka config is rejected as well.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.KafkaConfig = &config.KafkaConfig{
+		CompressionLevel: aws.Int(3),
+	}
+	_, err = CheckSinkV2Compatibility(sinkURI, replicaConfig)
+	require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err))
+
+	for _, param := range []string{
+		"sasl-mechanism=OAUTHBEARER", "enable-kafka-transactions=true", "compression-level=3",
+	} {
+		sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?" + param)
+		require.NoError(t, err)
+		_, err = CheckSinkV2Compatibility(sinkURI, config.GetDefaultReplicaConfig())
+		require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err), param)
+	}
+}
+
+func TestAdjustConfigEventHubs(t *testing.T) {
+	adminClient := NewClusterAdminClientMockImpl()
+	defer adminClient.Close()
+
+	options := NewOptions()
+	options.EventHubs = true
+	options.MaxMessageBytes = eventHubsMaxMessageBytes
+	ctx := context.Background()
+
+	// the event hub must be created in advance.
+	err := AdjustOptions(ctx, adminClient, options, "not-exist")
+	require.ErrorContains(t, err, "should be created in advance")
+
+	// the configs are not described, so the limit is kept.
+	err = AdjustOptions(ctx, adminClient, options, adminClient.GetDefaultMockTopicName())
+	require.NoError(t, err)
+	require.Equal(t, eventHubsMaxMessageBytes, options.MaxMessageBytes)
+	require.Equal(t, int32(3), options.PartitionNum)
+}
+
 func TestAdjustConfigTopicNotExist(t *testing.T) {
 	adminClient := NewClusterAdminClientMockImpl()
 	defer adminClient.Close()
@@ -68,6 +68,11 @@ func NewSaramaConfig(ctx context.Context, o *Options) (*sarama.Config, error) {
 	config.Producer.Flush.Frequency = time.Duration(0)
 	config.Producer.Flush.MaxMessages = o.MaxMessages
 
+	if o.EventHubs {
+		// Event Hubs closes the connections idle for 240 seconds.
+		config.Metadata.RefreshFrequency = eventHubsMetadataRefreshFrequency
+	}
+
 	config.Net.DialTimeout = o.DialTimeout
 	config.Net.WriteTimeout = o.WriteTimeout
 	config.Net.ReadTimeout = o.ReadTimeout
@@ -94,7 +99,9 @@ func NewSaramaConfig(ctx context.Context, o *Options) (*sarama.Config, error) {
 		config.Producer.Compression = sarama.CompressionNone
 	}
 	if config.Producer.Compression != sarama.CompressionNone {
-		log.Info("Kafka producer uses " + compression + " compression algorithm")
+		log.Info("Kafka producer uses "+compression+" compression algorithm",
+			zap.Int("level", o.CompressionLevel))
+		config.Producer.CompressionLevel = o.CompressionLevel
 	}
 
 	if o.EnableTLS {
@@ -126,6 +133,16 @@ func NewSaramaConfig(ctx context.Context, o *Options) (*sarama.Config, error) {
 	return config, err
 }
 
+// enableSaramaTransactions makes the producer idempotent and transactional.
+func enableSaramaTransactions(config *sarama.Config, transactionalID string) {
+	config.Producer.Idempotent = true
+	config.Producer.Transaction.ID = transactionalID
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	// The idempotent producer requires at most one in-flight request for each
+	// connection to keep the order of the messages.
+	config.Net.MaxOpenRequests = 1
+}
+
 func completeSaramaSASLConfig(ctx context.Context, config *sarama.Config, o *Options) error {
 	if o.SASL != nil && o.SASL.SASLMechanism != "" {
 		config.Net.SASL.Enable = true
@@ -163,6 +180,13 @@ func completeSaramaSASLConfig(ctx context.Context, config *sarama.Config, o *Opt
 				return errors.Trace(err)
 			}
 			config.Net.SASL.TokenProvider = p
+		case SASLTypeAWSMSKIAM:
+			p, err := NewAWSMSKIAMSigner(&o.SASL.AWSMSKIAM)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+			config.Net.SASL.TokenProvider = p
 		}
 	}
 
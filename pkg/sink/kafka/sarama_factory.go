@@ -47,7 +47,7 @@ func (f *saramaFactory) AdminClient(ctx context.Context) (ClusterAdminClient, er
 	if err != nil {
 		return nil, err
 	}
-	return newAdminClient(f.option.BrokerEndpoints, config, f.changefeedID)
+	return newAdminClient(ctx, f.option.ResolveBrokerEndpoints, config, f.changefeedID)
 }
 
 // SyncProducer returns a Sync Producer,
@@ -59,7 +59,11 @@ func (f *saramaFactory) SyncProducer(ctx context.Context) (SyncProducer, error)
 	}
 	config.MetricRegistry = f.registry
 
-	client, err := sarama.NewClient(f.option.BrokerEndpoints, config)
+	brokerEndpoints, err := f.option.ResolveBrokerEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(brokerEndpoints, config)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -69,9 +73,11 @@ func (f *saramaFactory) SyncProducer(ctx context.Context) (SyncProducer, error)
 		return nil, errors.Trace(err)
 	}
 	return &saramaSyncProducer{
-		id:       f.changefeedID,
-		client:   client,
-		producer: p,
+		id:             f.changefeedID,
+		resolveBrokers: f.option.ResolveBrokerEndpoints,
+		config:         config,
+		client:         client,
+		producer:       p,
 	}, nil
 }
 
@@ -86,8 +92,16 @@ func (f *saramaFactory) AsyncProducer(
 		return nil, err
 	}
 	config.MetricRegistry = f.registry
+	// Only the DML messages are sent by the transactional producer.
+	if f.option.EnableTransactions {
+		enableSaramaTransactions(config, f.option.TransactionalID)
+	}
 
-	client, err := sarama.NewClient(f.option.BrokerEndpoints, config)
+	brokerEndpoints, err := f.option.ResolveBrokerEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(brokerEndpoints, config)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -96,10 +110,11 @@ func (f *saramaFactory) AsyncProducer(
 		return nil, errors.Trace(err)
 	}
 	return &saramaAsyncProducer{
-		client:       client,
-		producer:     p,
-		changefeedID: f.changefeedID,
-		failpointCh:  failpointCh,
+		client:        client,
+		producer:      p,
+		changefeedID:  f.changefeedID,
+		failpointCh:   failpointCh,
+		transactional: f.option.EnableTransactions,
 	}, nil
 }
 
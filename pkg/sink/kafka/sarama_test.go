@@ -253,6 +253,48 @@ func TestApplySASL(t *testing.T) {
 			},
 			exceptErr: "OAuth2 is only supported with SASL mechanism type OAUTHBEARER",
 		},
+		{
+			name: "valid OAUTHBEARER SASL with assertion key",
+			URI:  "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0&sasl-mechanism=OAUTHBEARER",
+			replicaConfig: func() *config.ReplicaConfig {
+				cfg := config.GetDefaultReplicaConfig()
+				oauthMechanism := string(security.OAuthMechanism)
+				clientID := "client_id"
+				assertionKey := "/root/assertion.key"
+				assertionKeyID := "key_id"
+				tokenURL := "127.0.0.1:9093/token"
+				cfg.Sink.KafkaConfig = &config.KafkaConfig{
+					SASLMechanism:           &oauthMechanism,
+					SASLOAuthClientID:       &clientID,
+					SASLOAuthAssertionKey:   &assertionKey,
+					SASLOAuthAssertionKeyID: &assertionKeyID,
+					SASLOAuthTokenURL:       &tokenURL,
+				}
+				return cfg
+			},
+			exceptErr: "",
+		},
+		{
+			name: "invalid OAUTHBEARER SASL: both client secret and assertion key",
+			URI:  "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0&sasl-mechanism=OAUTHBEARER",
+			replicaConfig: func() *config.ReplicaConfig {
+				cfg := config.GetDefaultReplicaConfig()
+				oauthMechanism := string(security.OAuthMechanism)
+				clientID := "client_id"
+				clientSecret := "Y2xpZW50X3NlY3JldA==" // base64(client_secret)
+				assertionKey := "/root/assertion.key"
+				tokenURL := "127.0.0.1:9093/token"
+				cfg.Sink.KafkaConfig = &config.KafkaConfig{
+					SASLMechanism:         &oauthMechanism,
+					SASLOAuthClientID:     &clientID,
+					SASLOAuthClientSecret: &clientSecret,
+					SASLOAuthAssertionKey: &assertionKey,
+					SASLOAuthTokenURL:     &tokenURL,
+				}
+				return cfg
+			},
+			exceptErr: "OAuth2 client secret and assertion key can't be both set",
+		},
 	}
 
 	for _, test := range tests {
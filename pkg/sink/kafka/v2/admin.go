@@ -16,6 +16,8 @@ package v2
 import (
 	"context"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -26,26 +28,66 @@ import (
 )
 
 type admin struct {
-	client       Client
-	changefeedID model.ChangeFeedID
+	// resolveBrokers is called again when the brokers are unreachable,
+	// so that the brokers can be re-discovered after connection failures.
+	resolveBrokers func(ctx context.Context) ([]string, error)
+	transport      *kafka.Transport
+	changefeedID   model.ChangeFeedID
+
+	mu     sync.Mutex
+	client Client
 }
 
 func newClusterAdminClient(
 	endpoints []string,
+	resolveBrokers func(ctx context.Context) ([]string, error),
 	transport *kafka.Transport,
 	changefeedID model.ChangeFeedID,
 ) pkafka.ClusterAdminClient {
 	client := newClient(endpoints, transport)
 	return &admin{
-		client:       client,
-		changefeedID: changefeedID,
+		resolveBrokers: resolveBrokers,
+		transport:      transport,
+		client:         client,
+		changefeedID:   changefeedID,
 	}
 }
 
+func (a *admin) getClient() Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.client
+}
+
+// resetIfUnreachable recreates the client with the brokers resolved again if
+// the brokers are unreachable, so the next request is sent to the new brokers.
+func (a *admin) resetIfUnreachable(ctx context.Context, err error) {
+	if a.resolveBrokers == nil || !pkafka.IsBrokerUnreachable(err) {
+		return
+	}
+	brokerEndpoints, resolveErr := a.resolveBrokers(ctx)
+	if resolveErr != nil {
+		log.Warn("resolve kafka brokers failed",
+			zap.String("namespace", a.changefeedID.Namespace),
+			zap.String("changefeed", a.changefeedID.ID),
+			zap.Error(resolveErr))
+		return
+	}
+	a.mu.Lock()
+	a.client = newClient(brokerEndpoints, a.transport)
+	a.mu.Unlock()
+	log.Info("kafka admin client is reset",
+		zap.String("namespace", a.changefeedID.Namespace),
+		zap.String("changefeed", a.changefeedID.ID),
+		zap.Strings("brokers", brokerEndpoints),
+		zap.Error(err))
+}
+
 func (a *admin) clusterMetadata(ctx context.Context) (*kafka.MetadataResponse, error) {
 	// request is not set, so it will return all metadata
-	result, err := a.client.Metadata(ctx, &kafka.MetadataRequest{})
+	result, err := a.getClient().Metadata(ctx, &kafka.MetadataRequest{})
 	if err != nil {
+		a.resetIfUnreachable(ctx, err)
 		return nil, errors.Trace(err)
 	}
 	return result, nil
@@ -90,8 +132,9 @@ func (a *admin) GetBrokerConfig(ctx context.Context, configName string) (string,
 		},
 	}
 
-	resp, err := a.client.DescribeConfigs(ctx, request)
+	resp, err := a.getClient().DescribeConfigs(ctx, request)
 	if err != nil {
+		a.resetIfUnreachable(ctx, err)
 		return "", errors.Trace(err)
 	}
 
@@ -128,8 +171,9 @@ func (a *admin) GetTopicConfig(ctx context.Context, topicName string, configName
 		},
 	}
 
-	resp, err := a.client.DescribeConfigs(ctx, request)
+	resp, err := a.getClient().DescribeConfigs(ctx, request)
 	if err != nil {
+		a.resetIfUnreachable(ctx, err)
 		return "", errors.Trace(err)
 	}
 
@@ -165,10 +209,11 @@ func (a *admin) GetTopicsMeta(
 	topics []string,
 	ignoreTopicError bool,
 ) (map[string]pkafka.TopicDetail, error) {
-	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{
+	resp, err := a.getClient().Metadata(ctx, &kafka.MetadataRequest{
 		Topics: topics,
 	})
 	if err != nil {
+		a.resetIfUnreachable(ctx, err)
 		return nil, errors.Trace(err)
 	}
 
@@ -190,24 +235,54 @@ func (a *admin) GetTopicsMeta(
 	return result, nil
 }
 
+func (a *admin) GetAllTopicsMeta(ctx context.Context) (map[string]pkafka.TopicDetail, error) {
+	resp, err := a.clusterMetadata(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := make(map[string]pkafka.TopicDetail, len(resp.Topics))
+	for _, topic := range resp.Topics {
+		if topic.Internal {
+			continue
+		}
+		if topic.Error != nil {
+			log.Warn("fetch topic meta failed",
+				zap.String("topic", topic.Name), zap.Error(topic.Error))
+			continue
+		}
+		result[topic.Name] = pkafka.TopicDetail{
+			Name:          topic.Name,
+			NumPartitions: int32(len(topic.Partitions)),
+		}
+	}
+	return result, nil
+}
+
 func (a *admin) CreateTopic(
 	ctx context.Context,
 	detail *pkafka.TopicDetail,
 	validateOnly bool,
 ) error {
+	topicConfig := kafka.TopicConfig{
+		Topic:             detail.Name,
+		NumPartitions:     int(detail.NumPartitions),
+		ReplicationFactor: int(detail.ReplicationFactor),
+	}
+	for name, value := range detail.ConfigEntries {
+		topicConfig.ConfigEntries = append(topicConfig.ConfigEntries, kafka.ConfigEntry{
+			ConfigName:  name,
+			ConfigValue: value,
+		})
+	}
 	request := &kafka.CreateTopicsRequest{
-		Topics: []kafka.TopicConfig{
-			{
-				Topic:             detail.Name,
-				NumPartitions:     int(detail.NumPartitions),
-				ReplicationFactor: int(detail.ReplicationFactor),
-			},
-		},
+		Topics:       []kafka.TopicConfig{topicConfig},
 		ValidateOnly: validateOnly,
 	}
 
-	response, err := a.client.CreateTopics(ctx, request)
+	response, err := a.getClient().CreateTopics(ctx, request)
 	if err != nil {
+		a.resetIfUnreachable(ctx, err)
 		return errors.Trace(err)
 	}
 
@@ -220,11 +295,115 @@ func (a *admin) CreateTopic(
 	return nil
 }
 
+func (a *admin) DeleteTopics(ctx context.Context, topics []string) error {
+	response, err := a.getClient().DeleteTopics(ctx, &kafka.DeleteTopicsRequest{
+		Topics: topics,
+	})
+	if err != nil {
+		a.resetIfUnreachable(ctx, err)
+		return errors.Trace(err)
+	}
+
+	for _, err := range response.Errors {
+		if err != nil && !errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
+
+// boundaryMessageMaxBytes is the max bytes fetched to read a boundary message.
+const boundaryMessageMaxBytes = 16 * 1024 * 1024
+
+func (a *admin) GetBoundaryMessageHeaders(
+	ctx context.Context, topic string,
+) ([]map[string]string, error) {
+	meta, err := a.getClient().Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		a.resetIfUnreachable(ctx, err)
+		return nil, errors.Trace(err)
+	}
+	var requests []kafka.OffsetRequest
+	for _, t := range meta.Topics {
+		if t.Error != nil {
+			return nil, errors.Trace(t.Error)
+		}
+		for _, p := range t.Partitions {
+			requests = append(requests, kafka.FirstOffsetOf(p.ID), kafka.LastOffsetOf(p.ID))
+		}
+	}
+	offsets, err := a.getClient().ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err != nil {
+		a.resetIfUnreachable(ctx, err)
+		return nil, errors.Trace(err)
+	}
+
+	var result []map[string]string
+	for _, p := range offsets.Topics[topic] {
+		if p.Error != nil {
+			return nil, errors.Trace(p.Error)
+		}
+		if p.FirstOffset >= p.LastOffset {
+			continue
+		}
+		for _, offset := range []int64{p.FirstOffset, p.LastOffset - 1} {
+			headers, err := a.readMessageHeaders(ctx, topic, p.Partition, offset)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			result = append(result, headers)
+			if p.LastOffset-p.FirstOffset == 1 {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// readMessageHeaders returns the headers of the message at the offset.
+func (a *admin) readMessageHeaders(
+	ctx context.Context, topic string, partition int, offset int64,
+) (map[string]string, error) {
+	resp, err := a.getClient().Fetch(ctx, &kafka.FetchRequest{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		MinBytes:  1,
+		MaxBytes:  boundaryMessageMaxBytes,
+		MaxWait:   time.Second,
+	})
+	if err != nil {
+		a.resetIfUnreachable(ctx, err)
+		return nil, errors.Trace(err)
+	}
+	if resp.Error != nil {
+		return nil, errors.Trace(resp.Error)
+	}
+	for {
+		record, err := resp.Records.ReadRecord()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The batch containing the offset may start before it.
+		if record.Offset < offset {
+			continue
+		}
+		headers := make(map[string]string, len(record.Headers))
+		for _, h := range record.Headers {
+			headers[h.Key] = string(h.Value)
+		}
+		return headers, nil
+	}
+}
+
 func (a *admin) Close() {
 	log.Info("admin client start closing",
 		zap.String("namespace", a.changefeedID.Namespace),
 		zap.String("changefeed", a.changefeedID.ID))
-	client, ok := a.client.(*kafka.Client)
+	client, ok := a.getClient().(*kafka.Client)
 	if !ok {
 		return
 	}
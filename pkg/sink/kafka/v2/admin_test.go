@@ -34,7 +34,7 @@ func newClusterAdminClientWithMock(t *testing.T) (pkafka.ClusterAdminClient, *mo
 	require.NoError(t, err)
 
 	changefeedID := model.DefaultChangeFeedID("changefeed-test")
-	adminClient := newClusterAdminClient([]string{"127.0.0.1:9092"}, transport, changefeedID)
+	adminClient := newClusterAdminClient([]string{"127.0.0.1:9092"}, nil, transport, changefeedID)
 
 	ctrl := gomock.NewController(t)
 	client := mock.NewMockClient(ctrl)
@@ -49,7 +49,7 @@ func TestNewClusterAdminClient(t *testing.T) {
 	require.NoError(t, err)
 
 	changefeedID := model.DefaultChangeFeedID("changefeed-test")
-	adminClient := newClusterAdminClient([]string{"127.0.0.1:9092"}, transport, changefeedID)
+	adminClient := newClusterAdminClient([]string{"127.0.0.1:9092"}, nil, transport, changefeedID)
 	require.NotNil(t, adminClient)
 	require.NotNil(t, adminClient.(*admin).client)
 }
@@ -85,6 +85,36 @@ func TestGetAllBrokers(t *testing.T) {
 	require.Equal(t, int32(1), brokers[0].ID)
 }
 
+func TestAdminResolveBrokersAgain(t *testing.T) {
+	t.Parallel()
+
+	adminClient, client := newClusterAdminClientWithMock(t)
+	resolved := 0
+	adminClient.(*admin).resolveBrokers = func(ctx context.Context) ([]string, error) {
+		resolved++
+		return []string{"127.0.0.2:9092"}, nil
+	}
+
+	// the client is kept if the error is not about the connection.
+	ctx := context.Background()
+	client.EXPECT().Metadata(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("kafka.(*Client).Metadata"))
+	_, err := adminClient.GetAllBrokers(ctx)
+	require.Error(t, err)
+	require.Equal(t, 0, resolved)
+	require.Equal(t, client, adminClient.(*admin).client)
+
+	// the brokers are resolved again if they are unreachable.
+	client.EXPECT().Metadata(gomock.Any(), gomock.Any()).
+		Return(nil, &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host"}})
+	_, err = adminClient.GetAllBrokers(ctx)
+	require.Error(t, err)
+	require.Equal(t, 1, resolved)
+	newClient, ok := adminClient.(*admin).client.(*kafka.Client)
+	require.True(t, ok)
+	require.Equal(t, "127.0.0.2:9092", newClient.Addr.String())
+}
+
 func TestGetCoordinator(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,72 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	pkafka "github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/segmentio/kafka-go/sasl"
+)
+
+const (
+	awsMSKIAMSignVersion = "2020_10_22"
+	awsMSKIAMSignExpiry  = 5 * time.Minute
+)
+
+// awsMSKIAMMechanism implements the AWS_MSK_IAM mechanism, the client sends
+// the signed connect request in JSON, and the broker responds once.
+type awsMSKIAMMechanism struct {
+	signer *pkafka.AWSMSKIAMSigner
+}
+
+func (m awsMSKIAMMechanism) Name() string {
+	return pkafka.SASLTypeAWSMSKIAM
+}
+
+func (m awsMSKIAMMechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	metadata := sasl.MetadataFromContext(ctx)
+	if metadata == nil || metadata.Host == "" {
+		return nil, nil, errors.New("AWS_MSK_IAM SASL handshake needs a host")
+	}
+	u, err := m.signer.Presign("kafka", metadata.Host, awsMSKIAMSignExpiry)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := map[string]string{
+		"version":    awsMSKIAMSignVersion,
+		"host":       u.Host,
+		"user-agent": pkafka.AWSMSKIAMUserAgent,
+		"action":     pkafka.AWSMSKIAMSignAction,
+	}
+	// the keys of the signed parameters are in lower case.
+	for key, values := range u.Query() {
+		payload[strings.ToLower(key)] = values[0]
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return m, data, nil
+}
+
+func (m awsMSKIAMMechanism) Next(ctx context.Context, challenge []byte) (bool, []byte, error) {
+	// the broker responds the authentication result once, the failures are
+	// reported by the error of the handshake.
+	return true, nil, nil
+}
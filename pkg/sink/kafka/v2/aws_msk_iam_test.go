@@ -0,0 +1,67 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/security"
+	pkafka "github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSMSKIAMMechanism(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "TOKEN")
+
+	o := pkafka.NewOptions()
+	o.SASL = &security.SASL{
+		SASLMechanism: security.AWSMSKIAMMechanism,
+		AWSMSKIAM:     security.AWSMSKIAM{Region: "us-east-1"},
+	}
+	mechanism, err := completeSASLConfig(o)
+	require.NoError(t, err)
+	require.Equal(t, "AWS_MSK_IAM", mechanism.Name())
+
+	// the host is required.
+	_, _, err = mechanism.Start(context.Background())
+	require.Error(t, err)
+
+	ctx := sasl.WithMetadata(context.Background(), &sasl.Metadata{
+		Host: "b-1.msk.us-east-1.amazonaws.com",
+		Port: 9098,
+	})
+	stateMachine, data, err := mechanism.Start(ctx)
+	require.NoError(t, err)
+	payload := make(map[string]string)
+	require.NoError(t, json.Unmarshal(data, &payload))
+	require.Equal(t, "2020_10_22", payload["version"])
+	require.Equal(t, "b-1.msk.us-east-1.amazonaws.com", payload["host"])
+	require.Equal(t, "kafka-cluster:Connect", payload["action"])
+	require.Equal(t, "ticdc", payload["user-agent"])
+	require.Equal(t, "AWS4-HMAC-SHA256", payload["x-amz-algorithm"])
+	require.Contains(t, payload["x-amz-credential"], "AKID/")
+	require.Equal(t, "TOKEN", payload["x-amz-security-token"])
+	require.Equal(t, "300", payload["x-amz-expires"])
+	require.NotEmpty(t, payload["x-amz-signature"])
+
+	done, response, err := stateMachine.Next(ctx, []byte(`{"version":"2020_10_22"}`))
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Nil(t, response)
+}
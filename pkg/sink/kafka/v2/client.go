@@ -30,4 +30,11 @@ type Client interface {
 	CreateTopics(
 		ctx context.Context, req *kafka.CreateTopicsRequest,
 	) (*kafka.CreateTopicsResponse, error)
+	DeleteTopics(
+		ctx context.Context, req *kafka.DeleteTopicsRequest,
+	) (*kafka.DeleteTopicsResponse, error)
+	ListOffsets(
+		ctx context.Context, req *kafka.ListOffsetsRequest,
+	) (*kafka.ListOffsetsResponse, error)
+	Fetch(ctx context.Context, req *kafka.FetchRequest) (*kafka.FetchResponse, error)
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	pkafka "github.com/pingcap/tiflow/pkg/sink/kafka"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/segmentio/kafka-go"
@@ -51,6 +52,12 @@ func NewFactory(
 	options *pkafka.Options,
 	changefeedID model.ChangeFeedID,
 ) (pkafka.Factory, error) {
+	// The kafka-go writer is never transactional, the messages would be
+	// visible to the read_committed consumers before the batch is committed.
+	if options.EnableTransactions {
+		return nil, errors.ErrKafkaInvalidConfig.GenWithStack(
+			"enable-kafka-transactions is not supported by Kafka sink v2")
+	}
 	transport, err := newTransport(options)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -156,14 +163,29 @@ func completeSASLConfig(o *pkafka.Options) (sasl.Mechanism, error) {
 		case pkafka.SASLTypeOAuth:
 			return nil, errors.ErrKafkaInvalidConfig.GenWithStack(
 				"OAuth is not yet supported in Kafka sink v2")
+		case pkafka.SASLTypeAWSMSKIAM:
+			signer, err := pkafka.NewAWSMSKIAMSigner(&o.SASL.AWSMSKIAM)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return awsMSKIAMMechanism{signer: signer}, nil
 		}
 	}
 	return nil, nil
 }
 
-func (f *factory) newWriter(async bool) *kafka.Writer {
+func (f *factory) newWriter(ctx context.Context, async bool) (*kafka.Writer, error) {
+	brokerEndpoints, err := f.options.ResolveBrokerEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the batches can't be larger than the messages accepted by the broker.
+	batchBytes := f.options.MaxMessageBytes
+	if f.options.BatchBytes > 0 && f.options.BatchBytes < batchBytes {
+		batchBytes = f.options.BatchBytes
+	}
 	w := &kafka.Writer{
-		Addr:         kafka.TCP(f.options.BrokerEndpoints...),
+		Addr:         kafka.TCP(brokerEndpoints...),
 		Balancer:     newManualPartitioner(),
 		Transport:    f.transport,
 		ReadTimeout:  f.options.ReadTimeout,
@@ -173,7 +195,7 @@ func (f *factory) newWriter(async bool) *kafka.Writer {
 		MaxAttempts:     2,
 		WriteBackoffMin: 10 * time.Millisecond,
 		RequiredAcks:    kafka.RequiredAcks(f.options.RequiredAcks),
-		BatchBytes:      int64(f.options.MaxMessageBytes),
+		BatchBytes:      int64(batchBytes),
 		Async:           async,
 	}
 	f.writer = w
@@ -197,34 +219,56 @@ func (f *factory) newWriter(async bool) *kafka.Writer {
 	log.Info("Kafka producer uses "+f.options.Compression+" compression algorithm",
 		zap.String("namespace", f.changefeedID.Namespace),
 		zap.String("changefeed", f.changefeedID.ID))
-	return w
+	return w, nil
 }
 
-func (f *factory) AdminClient(_ context.Context) (pkafka.ClusterAdminClient, error) {
-	return newClusterAdminClient(f.options.BrokerEndpoints, f.transport, f.changefeedID), nil
+func (f *factory) AdminClient(ctx context.Context) (pkafka.ClusterAdminClient, error) {
+	brokerEndpoints, err := f.options.ResolveBrokerEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newClusterAdminClient(brokerEndpoints,
+		f.options.ResolveBrokerEndpoints, f.transport, f.changefeedID), nil
 }
 
 // SyncProducer creates a sync producer to writer message to kafka
-func (f *factory) SyncProducer(_ context.Context) (pkafka.SyncProducer, error) {
-	w := f.newWriter(false)
-	// set batch size to 1 to make sure the message is sent immediately
-	w.BatchTimeout = time.Millisecond
-	w.BatchSize = 1
+func (f *factory) SyncProducer(ctx context.Context) (pkafka.SyncProducer, error) {
+	w, err := f.newSyncWriter(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return &syncWriter{
 		w:            w,
 		changefeedID: f.changefeedID,
+		newWriter: func(ctx context.Context) (Writer, error) {
+			return f.newSyncWriter(ctx)
+		},
 	}, nil
 }
 
+func (f *factory) newSyncWriter(ctx context.Context) (*kafka.Writer, error) {
+	w, err := f.newWriter(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	// set batch size to 1 to make sure the message is sent immediately
+	w.BatchTimeout = time.Millisecond
+	w.BatchSize = 1
+	return w, nil
+}
+
 // AsyncProducer creates an async producer to writer message to kafka
 func (f *factory) AsyncProducer(
 	ctx context.Context,
 	failpointCh chan error,
 ) (pkafka.AsyncProducer, error) {
-	w := f.newWriter(true)
-	// assume each message is 1KB,
-	// and set batch timeout to 5ms to avoid waste too much time on waiting for messages.
-	w.BatchTimeout = 5 * time.Millisecond
+	w, err := f.newWriter(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	// assume each message is 1KB, and the linger is 5ms by default to avoid
+	// waste too much time on waiting for messages.
+	w.BatchTimeout = f.options.Linger
 	w.BatchSize = int(w.BatchBytes / 1024)
 	aw := &asyncWriter{
 		w:            w,
@@ -232,8 +276,12 @@ func (f *factory) AsyncProducer(
 		failpointCh:  failpointCh,
 		errorsChan:   make(chan error, 1),
 	}
+	if f.options.MaxInflight > 0 {
+		aw.inflight = make(chan struct{}, f.options.MaxInflight)
+	}
 
 	w.Completion = func(messages []kafka.Message, err error) {
+		aw.release(len(messages))
 		if err != nil {
 			select {
 			case <-ctx.Done():
@@ -270,18 +318,22 @@ func (f *factory) MetricsCollector(
 type syncWriter struct {
 	changefeedID model.ChangeFeedID
 	w            Writer
+	// newWriter creates a writer connected to the brokers resolved again,
+	// it's used to replace the writer if the brokers are unreachable.
+	newWriter func(ctx context.Context) (Writer, error)
 }
 
 func (s *syncWriter) SendMessage(
 	ctx context.Context,
 	topic string, partitionNum int32,
-	key []byte, value []byte,
+	message *common.Message,
 ) error {
-	return s.w.WriteMessages(ctx, kafka.Message{
+	return s.writeWithRetry(ctx, kafka.Message{
 		Topic:     topic,
 		Partition: int(partitionNum),
-		Key:       key,
-		Value:     value,
+		Key:       message.Key,
+		Value:     message.Value,
+		Headers:   kafkaHeaders(message.Headers),
 	})
 }
 
@@ -292,20 +344,57 @@ func (s *syncWriter) SendMessage(
 func (s *syncWriter) SendMessages(
 	ctx context.Context,
 	topic string, partitionNum int32,
-	key []byte, value []byte,
+	message *common.Message,
 ) error {
+	headers := kafkaHeaders(message.Headers)
 	msgs := make([]kafka.Message, int(partitionNum))
 	for i := 0; i < int(partitionNum); i++ {
 		msgs[i] = kafka.Message{
 			Topic:     topic,
-			Key:       key,
-			Value:     value,
+			Key:       message.Key,
+			Value:     message.Value,
+			Headers:   headers,
 			Partition: i,
 		}
 	}
+	return s.writeWithRetry(ctx, msgs...)
+}
+
+// writeWithRetry writes the messages, if the brokers are unreachable, the
+// writer is replaced by a new one connected to the brokers resolved again,
+// and the messages are written once more.
+func (s *syncWriter) writeWithRetry(ctx context.Context, msgs ...kafka.Message) error {
+	err := s.w.WriteMessages(ctx, msgs...)
+	if err == nil || s.newWriter == nil || !isBrokerUnreachable(err) {
+		return err
+	}
+	log.Warn("kafka brokers are unreachable, reset the sync producer",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Error(err))
+	w, resetErr := s.newWriter(ctx)
+	if resetErr != nil {
+		return errors.Trace(resetErr)
+	}
+	s.Close()
+	s.w = w
 	return s.w.WriteMessages(ctx, msgs...)
 }
 
+// isBrokerUnreachable returns true if any message failed to be written
+// since the brokers are unreachable.
+func isBrokerUnreachable(err error) bool {
+	var errs kafka.WriteErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			if e != nil {
+				return pkafka.IsBrokerUnreachable(e)
+			}
+		}
+	}
+	return pkafka.IsBrokerUnreachable(err)
+}
+
 // Close shuts down the producer; you must call this function before a producer
 // object passes out of scope, as it may otherwise leak memory.
 // You must call this before calling Close on the underlying client.
@@ -333,6 +422,9 @@ type asyncWriter struct {
 	changefeedID model.ChangeFeedID
 	failpointCh  chan error
 	errorsChan   chan error
+	// inflight limits the messages which are not acknowledged yet, it's nil
+	// if the messages are unlimited.
+	inflight chan struct{}
 }
 
 // Close shuts down the producer and waits for any buffered messages to be
@@ -364,21 +456,49 @@ func (a *asyncWriter) Close() {
 // AsyncSend is the input channel for the user to write messages to that they
 // wish to send.
 func (a *asyncWriter) AsyncSend(ctx context.Context, topic string,
-	partition int32, key []byte, value []byte,
-	callback func(),
+	partition int32, message *common.Message,
 ) error {
 	select {
 	case <-ctx.Done():
 		return errors.Trace(ctx.Err())
 	default:
 	}
-	return a.w.WriteMessages(ctx, kafka.Message{
+	if a.inflight != nil {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case a.inflight <- struct{}{}:
+		}
+	}
+	err := a.w.WriteMessages(ctx, kafka.Message{
 		Topic:      topic,
 		Partition:  int(partition),
-		Key:        key,
-		Value:      value,
-		WriterData: callback,
+		Key:        message.Key,
+		Value:      message.Value,
+		Headers:    kafkaHeaders(message.Headers),
+		WriterData: message.Callback,
 	})
+	if err != nil {
+		// the completion is never called if the message isn't written.
+		a.release(1)
+	}
+	return err
+}
+
+// release releases the in-flight quota of the acknowledged messages.
+func (a *asyncWriter) release(n int) {
+	if a.inflight == nil {
+		return
+	}
+	for i := 0; i < n; i++ {
+		<-a.inflight
+	}
+}
+
+// CommitTxn implement the AsyncProducer interface, the kafka-go writer
+// is never transactional, which is rejected by NewFactory.
+func (a *asyncWriter) CommitTxn() error {
+	return nil
 }
 
 // AsyncRunCallback process the messages that has sent to kafka,
@@ -406,3 +526,15 @@ func (a *asyncWriter) AsyncRunCallback(ctx context.Context) error {
 		return errors.WrapError(errors.ErrKafkaAsyncSendMessage, err)
 	}
 }
+
+// kafkaHeaders converts the message headers to the kafka-go headers.
+func kafkaHeaders(headers []common.MessageHeader) []kafka.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make([]kafka.Header, 0, len(headers))
+	for _, header := range headers {
+		result = append(result, kafka.Header{Key: header.Key, Value: header.Value})
+	}
+	return result
+}
@@ -16,13 +16,17 @@ package v2
 import (
 	"context"
 	"crypto/tls"
+	"net"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	pkafka "github.com/pingcap/tiflow/pkg/sink/kafka"
 	v2mock "github.com/pingcap/tiflow/pkg/sink/kafka/v2/mock"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -66,12 +70,22 @@ func TestSyncProducer(t *testing.T) {
 	require.False(t, p.w.(*kafka.Writer).Async)
 }
 
+func TestNewFactoryWithTransactions(t *testing.T) {
+	t.Parallel()
+
+	o := newOptions4Test()
+	o.EnableTransactions = true
+	_, err := NewFactory(o, model.DefaultChangeFeedID("kafka-go-sink"))
+	require.True(t, cerror.ErrKafkaInvalidConfig.Equal(err))
+}
+
 func TestCompression(t *testing.T) {
 	t.Parallel()
 
 	o := newOptions4Test()
 	factory := newFactory4Test(o, t)
-	factory.newWriter(false)
+	_, err := factory.newWriter(context.Background(), false)
+	require.NoError(t, err)
 	cases := []struct {
 		compression string
 		expected    kafka.Compression
@@ -85,7 +99,8 @@ func TestCompression(t *testing.T) {
 	}
 	for _, cs := range cases {
 		o.Compression = cs.compression
-		w := factory.newWriter(false)
+		w, err := factory.newWriter(context.Background(), false)
+		require.NoError(t, err)
 		require.Equal(t, cs.expected, w.Compression)
 	}
 }
@@ -150,6 +165,54 @@ func TestAsyncCompletion(t *testing.T) {
 	require.Equal(t, 2, acked)
 }
 
+func TestAsyncProducerTuning(t *testing.T) {
+	t.Parallel()
+
+	o := newOptions4Test()
+	o.Linger = 20 * time.Millisecond
+	o.BatchBytes = 4096
+	o.MaxInflight = 2
+	factory := newFactory4Test(o, t)
+	async, err := factory.AsyncProducer(context.Background(), make(chan error, 1))
+	require.NoError(t, err)
+	asyncP := async.(*asyncWriter)
+	w := asyncP.w.(*kafka.Writer)
+	require.Equal(t, 20*time.Millisecond, w.BatchTimeout)
+	require.Equal(t, int64(4096), w.BatchBytes)
+	require.Equal(t, 4, w.BatchSize)
+	require.Equal(t, 2, cap(asyncP.inflight))
+
+	// the batches can't be larger than the max message bytes.
+	o = newOptions4Test()
+	o.BatchBytes = o.MaxMessageBytes + 1
+	factory = newFactory4Test(o, t)
+	async, err = factory.AsyncProducer(context.Background(), make(chan error, 1))
+	require.NoError(t, err)
+	require.Equal(t, int64(o.MaxMessageBytes), async.(*asyncWriter).w.(*kafka.Writer).BatchBytes)
+	require.Nil(t, async.(*asyncWriter).inflight)
+}
+
+func TestAsyncWriterMaxInflight(t *testing.T) {
+	mw := v2mock.NewMockWriter(gomock.NewController(t))
+	w := &asyncWriter{w: mw, inflight: make(chan struct{}, 2)}
+	message := &common.Message{Key: []byte{'1'}, Value: []byte{}, Callback: func() {}}
+
+	mw.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	require.NoError(t, w.AsyncSend(context.Background(), "topic", 1, message))
+	require.NoError(t, w.AsyncSend(context.Background(), "topic", 1, message))
+
+	// the sending is blocked until the messages are acknowledged.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := w.AsyncSend(ctx, "topic", 1, message)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	w.release(1)
+	mw.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(errors.New("fake"))
+	require.Error(t, w.AsyncSend(context.Background(), "topic", 1, message))
+	require.Equal(t, 1, len(w.inflight))
+}
+
 func TestNewMetricsCollector(t *testing.T) {
 	require.NotNil(t, NewMetricsCollector(model.DefaultChangeFeedID("1"), util.RoleOwner, nil))
 }
@@ -211,9 +274,15 @@ func TestSyncWriterSendMessage(t *testing.T) {
 		DoAndReturn(func(ctx context.Context, msgs ...kafka.Message) error {
 			require.Equal(t, 1, len(msgs))
 			require.Equal(t, 3, msgs[0].Partition)
+			require.Equal(t, []kafka.Header{{Key: "env", Value: []byte("prod")}}, msgs[0].Headers)
 			return errors.New("fake")
 		})
-	require.NotNil(t, w.SendMessage(context.Background(), "topic", 3, []byte{'1'}, []byte{}))
+	message := &common.Message{
+		Key:     []byte{'1'},
+		Value:   []byte{},
+		Headers: []common.MessageHeader{{Key: "env", Value: []byte("prod")}},
+	}
+	require.NotNil(t, w.SendMessage(context.Background(), "topic", 3, message))
 }
 
 func TestSyncWriterSendMessages(t *testing.T) {
@@ -224,7 +293,34 @@ func TestSyncWriterSendMessages(t *testing.T) {
 			require.Equal(t, 3, len(msgs))
 			return errors.New("fake")
 		})
-	require.NotNil(t, w.SendMessages(context.Background(), "topic", 3, []byte{'1'}, []byte{}))
+	message := &common.Message{Key: []byte{'1'}, Value: []byte{}}
+	require.NotNil(t, w.SendMessages(context.Background(), "topic", 3, message))
+}
+
+func TestSyncWriterResolveBrokersAgain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	oldWriter := v2mock.NewMockWriter(ctrl)
+	newWriter := v2mock.NewMockWriter(ctrl)
+	w := &syncWriter{
+		w: oldWriter,
+		newWriter: func(ctx context.Context) (Writer, error) {
+			return newWriter, nil
+		},
+	}
+	message := &common.Message{Key: []byte{'1'}, Value: []byte{}}
+
+	// the writer is replaced once the brokers are unreachable.
+	oldWriter.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).
+		Return(kafka.WriteErrors{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}})
+	oldWriter.EXPECT().Close().Return(nil)
+	newWriter.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, w.SendMessage(context.Background(), "topic", 0, message))
+	require.Equal(t, newWriter, w.w)
+
+	// other errors are returned directly.
+	newWriter.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(kafka.MessageSizeTooLarge)
+	require.ErrorIs(t, w.SendMessages(context.Background(), "topic", 1, message), kafka.MessageSizeTooLarge)
+	require.Equal(t, newWriter, w.w)
 }
 
 func TestSyncWriterClose(t *testing.T) {
@@ -244,14 +340,14 @@ func TestAsyncWriterAsyncSend(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	callback := func() {}
+	message := &common.Message{Key: []byte{'1'}, Value: []byte{}, Callback: func() {}}
 	mw.EXPECT().WriteMessages(gomock.Any(), gomock.Any()).Return(nil)
-	err := w.AsyncSend(ctx, "topic", 1, []byte{'1'}, []byte{}, callback)
+	err := w.AsyncSend(ctx, "topic", 1, message)
 	require.NoError(t, err)
 
 	cancel()
 
-	err = w.AsyncSend(ctx, "topic", 1, []byte{'1'}, []byte{}, callback)
+	err = w.AsyncSend(ctx, "topic", 1, message)
 	require.ErrorIs(t, err, context.Canceled)
 }
 
@@ -50,6 +50,21 @@ func (mr *MockClientMockRecorder) CreateTopics(ctx, req interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTopics", reflect.TypeOf((*MockClient)(nil).CreateTopics), ctx, req)
 }
 
+// DeleteTopics mocks base method.
+func (m *MockClient) DeleteTopics(ctx context.Context, req *kafka.DeleteTopicsRequest) (*kafka.DeleteTopicsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTopics", ctx, req)
+	ret0, _ := ret[0].(*kafka.DeleteTopicsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTopics indicates an expected call of DeleteTopics.
+func (mr *MockClientMockRecorder) DeleteTopics(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTopics", reflect.TypeOf((*MockClient)(nil).DeleteTopics), ctx, req)
+}
+
 // DescribeConfigs mocks base method.
 func (m *MockClient) DescribeConfigs(ctx context.Context, req *kafka.DescribeConfigsRequest) (*kafka.DescribeConfigsResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,36 @@ func (mr *MockClientMockRecorder) DescribeConfigs(ctx, req interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeConfigs", reflect.TypeOf((*MockClient)(nil).DescribeConfigs), ctx, req)
 }
 
+// Fetch mocks base method.
+func (m *MockClient) Fetch(ctx context.Context, req *kafka.FetchRequest) (*kafka.FetchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, req)
+	ret0, _ := ret[0].(*kafka.FetchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockClientMockRecorder) Fetch(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockClient)(nil).Fetch), ctx, req)
+}
+
+// ListOffsets mocks base method.
+func (m *MockClient) ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOffsets", ctx, req)
+	ret0, _ := ret[0].(*kafka.ListOffsetsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOffsets indicates an expected call of ListOffsets.
+func (mr *MockClientMockRecorder) ListOffsets(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOffsets", reflect.TypeOf((*MockClient)(nil).ListOffsets), ctx, req)
+}
+
 // Metadata mocks base method.
 func (m *MockClient) Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
 	m.ctrl.T.Helper()
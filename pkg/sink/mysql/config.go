@@ -38,6 +38,16 @@ const (
 	txnModeOptimistic  = "optimistic"
 	txnModePessimistic = "pessimistic"
 
+	// ConflictStrategyOverwrite overwrites the existing rows by REPLACE.
+	ConflictStrategyOverwrite = "overwrite"
+	// ConflictStrategyError writes the rows by INSERT, the conflicting rows fail the transaction.
+	ConflictStrategyError = "error"
+	// ConflictStrategyIgnore writes the rows by INSERT IGNORE, the existing rows are kept.
+	ConflictStrategyIgnore = "ignore"
+	// ConflictStrategyTimestampWins only overwrites the existing rows whose
+	// timestamp column is not newer than the written rows.
+	ConflictStrategyTimestampWins = "timestamp-wins"
+
 	// DefaultWorkerCount is the default number of workers.
 	DefaultWorkerCount = 16
 	// DefaultMaxTxnRow is the default max number of rows in a transaction.
@@ -77,6 +87,13 @@ const (
 
 	// defaultcachePrepStmts is the default value of cachePrepStmts
 	defaultCachePrepStmts = true
+
+	// defaultMetaMaxOpenConns is the default connection limit of the pool
+	// writing the syncpoints, which are written one by one.
+	defaultMetaMaxOpenConns = 2
+	// defaultPoolHealthCheckInterval is the default interval of probing the
+	// connections of the pools.
+	defaultPoolHealthCheckInterval = 30 * time.Second
 )
 
 type urlConfig struct {
@@ -96,6 +113,13 @@ type urlConfig struct {
 	EnableBatchDML               *bool   `form:"batch-dml-enable"`
 	EnableMultiStatement         *bool   `form:"multi-stmt-enable"`
 	EnableCachePreparedStatement *bool   `form:"cache-prep-stmts"`
+	ConflictStrategy             *string `form:"conflict-strategy"`
+	ConflictTimestampColumn      *string `form:"conflict-timestamp-column"`
+	EnableAsyncDDL               *bool   `form:"async-ddl-enable"`
+	EnableBatchUpsert            *bool   `form:"batch-upsert-enable"`
+	DMLMaxOpenConns              *int    `form:"dml-max-open-conns"`
+	MetaMaxOpenConns             *int    `form:"meta-max-open-conns"`
+	PoolHealthCheckInterval      *string `form:"pool-health-check-interval"`
 }
 
 // Config is the configs for MySQL backend.
@@ -123,23 +147,46 @@ type Config struct {
 	BatchDMLEnable  bool
 	MultiStmtEnable bool
 	CachePrepStmts  bool
+
+	// ConflictStrategy decides how the conflicting rows are written in safe mode.
+	ConflictStrategy string
+	// ConflictTimestampColumn is only used by the timestamp-wins strategy.
+	ConflictTimestampColumn string
+	// AsyncDDLEnable executes the time-consuming DDLs in the background.
+	AsyncDDLEnable bool
+	// BatchUpsertEnable writes the inserted and updated rows by multi-row
+	// INSERT ... ON DUPLICATE KEY UPDATE statements if BatchDMLEnable is true.
+	BatchUpsertEnable bool
+
+	// DMLMaxOpenConns limits the connections of the pool writing the rows, the
+	// pool has worker-count + 1 connections if it's 0.
+	DMLMaxOpenConns int
+	// MetaMaxOpenConns limits the connections of the pool writing the
+	// syncpoints, so they're never queued behind the rows.
+	MetaMaxOpenConns int
+	// PoolHealthCheckInterval is the interval of probing the connections of
+	// the pools, the probe is disabled if it's 0.
+	PoolHealthCheckInterval time.Duration
 }
 
 // NewConfig returns the default mysql backend config.
 func NewConfig() *Config {
 	return &Config{
-		WorkerCount:            DefaultWorkerCount,
-		MaxTxnRow:              DefaultMaxTxnRow,
-		MaxMultiUpdateRowCount: defaultMaxMultiUpdateRowCount,
-		MaxMultiUpdateRowSize:  defaultMaxMultiUpdateRowSize,
-		tidbTxnMode:            defaultTiDBTxnMode,
-		ReadTimeout:            defaultReadTimeout,
-		WriteTimeout:           defaultWriteTimeout,
-		DialTimeout:            defaultDialTimeout,
-		SafeMode:               defaultSafeMode,
-		BatchDMLEnable:         defaultBatchDMLEnable,
-		MultiStmtEnable:        defaultMultiStmtEnable,
-		CachePrepStmts:         defaultCachePrepStmts,
+		WorkerCount:             DefaultWorkerCount,
+		MaxTxnRow:               DefaultMaxTxnRow,
+		MaxMultiUpdateRowCount:  defaultMaxMultiUpdateRowCount,
+		MaxMultiUpdateRowSize:   defaultMaxMultiUpdateRowSize,
+		tidbTxnMode:             defaultTiDBTxnMode,
+		ReadTimeout:             defaultReadTimeout,
+		WriteTimeout:            defaultWriteTimeout,
+		DialTimeout:             defaultDialTimeout,
+		SafeMode:                defaultSafeMode,
+		BatchDMLEnable:          defaultBatchDMLEnable,
+		MultiStmtEnable:         defaultMultiStmtEnable,
+		CachePrepStmts:          defaultCachePrepStmts,
+		ConflictStrategy:        ConflictStrategyOverwrite,
+		MetaMaxOpenConns:        defaultMetaMaxOpenConns,
+		PoolHealthCheckInterval: defaultPoolHealthCheckInterval,
 	}
 }
 
@@ -198,6 +245,14 @@ func (c *Config) Apply(
 	getBatchDMLEnable(urlParameter, &c.BatchDMLEnable)
 	getMultiStmtEnable(urlParameter, &c.MultiStmtEnable)
 	getCachePrepStmts(urlParameter, &c.CachePrepStmts)
+	if err = getConflictStrategy(urlParameter, &c.ConflictStrategy, &c.ConflictTimestampColumn); err != nil {
+		return err
+	}
+	getAsyncDDLEnable(urlParameter, &c.AsyncDDLEnable)
+	getBatchUpsertEnable(urlParameter, &c.BatchUpsertEnable)
+	if err = getPoolConfig(urlParameter, c); err != nil {
+		return err
+	}
 	c.EnableOldValue = replicaConfig.EnableOldValue
 	c.ForceReplicate = replicaConfig.ForceReplicate
 	c.SourceID = replicaConfig.Sink.TiDBSourceID
@@ -228,6 +283,13 @@ func mergeConfig(
 		dest.EnableBatchDML = mConfig.EnableBatchDML
 		dest.EnableMultiStatement = mConfig.EnableMultiStatement
 		dest.EnableCachePreparedStatement = mConfig.EnableCachePreparedStatement
+		dest.ConflictStrategy = mConfig.ConflictStrategy
+		dest.ConflictTimestampColumn = mConfig.ConflictTimestampColumn
+		dest.EnableAsyncDDL = mConfig.EnableAsyncDDL
+		dest.EnableBatchUpsert = mConfig.EnableBatchUpsert
+		dest.DMLMaxOpenConns = mConfig.DMLMaxOpenConns
+		dest.MetaMaxOpenConns = mConfig.MetaMaxOpenConns
+		dest.PoolHealthCheckInterval = mConfig.PoolHealthCheckInterval
 	}
 	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
 		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
@@ -444,3 +506,81 @@ func getCachePrepStmts(values *urlConfig, cachePrepStmts *bool) {
 		*cachePrepStmts = *values.EnableCachePreparedStatement
 	}
 }
+
+func getAsyncDDLEnable(values *urlConfig, asyncDDLEnable *bool) {
+	if values.EnableAsyncDDL != nil {
+		*asyncDDLEnable = *values.EnableAsyncDDL
+	}
+}
+
+func getBatchUpsertEnable(values *urlConfig, batchUpsertEnable *bool) {
+	if values.EnableBatchUpsert != nil {
+		*batchUpsertEnable = *values.EnableBatchUpsert
+	}
+}
+
+// getPoolConfig gets the limits of the pools, it must be called after the
+// worker count is got.
+func getPoolConfig(values *urlConfig, c *Config) error {
+	if values.DMLMaxOpenConns != nil {
+		n := *values.DMLMaxOpenConns
+		if n < c.WorkerCount+1 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid dml-max-open-conns %d, which must be at least "+
+					"worker-count + 1 (%d)", n, c.WorkerCount+1))
+		}
+		c.DMLMaxOpenConns = n
+	}
+	if values.MetaMaxOpenConns != nil {
+		n := *values.MetaMaxOpenConns
+		if n <= 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid meta-max-open-conns %d, which must be greater than 0", n))
+		}
+		c.MetaMaxOpenConns = n
+	}
+	if values.PoolHealthCheckInterval != nil {
+		d, err := time.ParseDuration(*values.PoolHealthCheckInterval)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if d < 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid pool-health-check-interval %s, which must not be negative", d))
+		}
+		c.PoolHealthCheckInterval = d
+	}
+	return nil
+}
+
+// DMLPoolSize returns the connection limit of the pool writing the rows. An
+// extra connection is required by the prepared statements.
+func (c *Config) DMLPoolSize() int {
+	if c.DMLMaxOpenConns > 0 {
+		return c.DMLMaxOpenConns
+	}
+	return c.WorkerCount + 1
+}
+
+func getConflictStrategy(values *urlConfig, strategy *string, timestampColumn *string) error {
+	if values.ConflictStrategy == nil || len(*values.ConflictStrategy) == 0 {
+		return nil
+	}
+	s := strings.ToLower(*values.ConflictStrategy)
+	switch s {
+	case ConflictStrategyOverwrite, ConflictStrategyError, ConflictStrategyIgnore:
+	case ConflictStrategyTimestampWins:
+		if values.ConflictTimestampColumn == nil || len(*values.ConflictTimestampColumn) == 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("conflict-timestamp-column is required by the conflict-strategy %s", s))
+		}
+		*timestampColumn = *values.ConflictTimestampColumn
+	default:
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid conflict-strategy %s, which must be one of %s, %s, %s and %s",
+				s, ConflictStrategyOverwrite, ConflictStrategyError,
+				ConflictStrategyIgnore, ConflictStrategyTimestampWins))
+	}
+	*strategy = s
+	return nil
+}
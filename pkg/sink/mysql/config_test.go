@@ -241,6 +241,17 @@ func TestParseSinkURIOverride(t *testing.T) {
 		checker: func(sp *Config) {
 			require.EqualValues(t, sp.CachePrepStmts, false)
 		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?conflict-strategy=Ignore",
+		checker: func(sp *Config) {
+			require.Equal(t, ConflictStrategyIgnore, sp.ConflictStrategy)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?conflict-strategy=timestamp-wins&conflict-timestamp-column=updated_at",
+		checker: func(sp *Config) {
+			require.Equal(t, ConflictStrategyTimestampWins, sp.ConflictStrategy)
+			require.Equal(t, "updated_at", sp.ConflictTimestampColumn)
+		},
 	}}
 	var uri *url.URL
 	var err error
@@ -278,6 +289,12 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?write-timeout=badduration",
 		"mysql://127.0.0.1:3306/?read-timeout=badduration",
 		"mysql://127.0.0.1:3306/?timeout=badduration",
+		"mysql://127.0.0.1:3306/?conflict-strategy=badstrategy",
+		"mysql://127.0.0.1:3306/?conflict-strategy=timestamp-wins",
+		"mysql://127.0.0.1:3306/?worker-count=4&dml-max-open-conns=4",
+		"mysql://127.0.0.1:3306/?meta-max-open-conns=0",
+		"mysql://127.0.0.1:3306/?pool-health-check-interval=badduration",
+		"mysql://127.0.0.1:3306/?pool-health-check-interval=-1s",
 	}
 	var uri *url.URL
 	var err error
@@ -426,6 +443,9 @@ func TestMergeConfig(t *testing.T) {
 		EnableBatchDML:               aws.Bool(true),
 		EnableMultiStatement:         aws.Bool(true),
 		EnableCachePreparedStatement: aws.Bool(true),
+		ConflictStrategy:             aws.String("error"),
+		EnableAsyncDDL:               aws.Bool(true),
+		EnableBatchUpsert:            aws.Bool(true),
 	}
 	c := NewConfig()
 	err = c.Apply("Asia/Shanghai", model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
@@ -442,6 +462,9 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, true, c.BatchDMLEnable)
 	require.Equal(t, true, c.MultiStmtEnable)
 	require.Equal(t, true, c.CachePrepStmts)
+	require.Equal(t, ConflictStrategyError, c.ConflictStrategy)
+	require.True(t, c.AsyncDDLEnable)
+	require.True(t, c.BatchUpsertEnable)
 
 	uri = "mysql://topic?" +
 		"worker-count=13&" +
@@ -489,3 +512,33 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, true, c.MultiStmtEnable)
 	require.Equal(t, true, c.CachePrepStmts)
 }
+
+func TestApplyPoolConfig(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:3306/?worker-count=4")
+	require.NoError(t, err)
+	c := NewConfig()
+	err = c.Apply("UTC", model.DefaultChangeFeedID("test"), sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, 5, c.DMLPoolSize())
+	require.Equal(t, defaultMetaMaxOpenConns, c.MetaMaxOpenConns)
+	require.Equal(t, defaultPoolHealthCheckInterval, c.PoolHealthCheckInterval)
+
+	// the url parameters override the config file.
+	sinkURI, err = url.Parse("mysql://127.0.0.1:3306/?worker-count=4" +
+		"&dml-max-open-conns=8&pool-health-check-interval=0s")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.MySQLConfig = &config.MySQLConfig{
+		DMLMaxOpenConns:         aws.Int(16),
+		MetaMaxOpenConns:        aws.Int(3),
+		PoolHealthCheckInterval: aws.String("1m"),
+	}
+	c = NewConfig()
+	err = c.Apply("UTC", model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.Equal(t, 8, c.DMLPoolSize())
+	require.Equal(t, 3, c.MetaMaxOpenConns)
+	require.Zero(t, c.PoolHealthCheckInterval)
+}
@@ -21,6 +21,7 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
 
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/errors"
@@ -29,6 +30,7 @@ import (
 	tmysql "github.com/pingcap/tidb/parser/mysql"
 	dmutils "github.com/pingcap/tiflow/dm/pkg/conn"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security/secret"
 	"go.uber.org/zap"
 )
 
@@ -258,6 +260,11 @@ func GenBasicDSN(sinkURI *url.URL, cfg *Config) (*dmysql.Config, error) {
 		username = "root"
 	}
 	password, _ := sinkURI.User.Password()
+	// the password may be a reference to the secret store.
+	password, err := secret.Resolve(password)
+	if err != nil {
+		return nil, err
+	}
 
 	hostName := sinkURI.Hostname()
 	port := sinkURI.Port()
@@ -267,7 +274,6 @@ func GenBasicDSN(sinkURI *url.URL, cfg *Config) (*dmysql.Config, error) {
 
 	// This will handle the IPv6 address format.
 	var dsn *dmysql.Config
-	var err error
 	host := net.JoinHostPort(hostName, port)
 	dsnStr := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, host, cfg.TLS)
 	if dsn, err = dmysql.ParseDSN(dsnStr); err != nil {
@@ -348,3 +354,59 @@ func QueryMaxAllowedPacket(ctx context.Context, db *sql.DB) (int64, error) {
 	}
 	return maxAllowedPacket.Int64, nil
 }
+
+// dmlPrivileges are the privileges required to write the rows to the downstream.
+var dmlPrivileges = []string{"INSERT", "UPDATE", "DELETE"}
+
+// QueryMissingDMLPrivileges returns the privileges which are required to write
+// the rows but aren't granted to the current user. The grants on any database
+// are counted, and nothing is reported if the user is granted some roles since
+// the privileges of the roles aren't resolved.
+func QueryMissingDMLPrivileges(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SHOW GRANTS FOR CURRENT_USER()")
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	defer rows.Close()
+
+	granted := make(map[string]struct{})
+	hasRole := false
+	for rows.Next() {
+		var grant string
+		if err := rows.Scan(&grant); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		grant = strings.ToUpper(grant)
+		end := strings.Index(grant, " ON ")
+		if !strings.HasPrefix(grant, "GRANT ") || end < 0 {
+			// such as "GRANT `role`@`%` TO `user`@`%`".
+			hasRole = hasRole || strings.HasPrefix(grant, "GRANT ")
+			continue
+		}
+		for _, privilege := range strings.Split(grant[len("GRANT "):end], ",") {
+			// strip the columns of the column-level privileges.
+			if i := strings.Index(privilege, "("); i >= 0 {
+				privilege = privilege[:i]
+			}
+			privilege = strings.TrimSpace(privilege)
+			if privilege == "ALL" || privilege == "ALL PRIVILEGES" {
+				return nil, nil
+			}
+			granted[privilege] = struct{}{}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	if hasRole {
+		return nil, nil
+	}
+
+	var missing []string
+	for _, privilege := range dmlPrivileges {
+		if _, ok := granted[privilege]; !ok {
+			missing = append(missing, privilege)
+		}
+	}
+	return missing, nil
+}
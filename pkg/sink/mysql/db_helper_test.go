@@ -14,10 +14,13 @@
 package mysql
 
 import (
+	"context"
 	"encoding/base64"
 	"net/url"
+	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -74,3 +77,62 @@ func TestDecodePassword(t *testing.T) {
 		require.Equal(t, c.want, c.password)
 	}
 }
+
+func TestQueryMissingDMLPrivileges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		grants  []string
+		missing []string
+	}{
+		{
+			name:   "all privileges",
+			grants: []string{"GRANT ALL PRIVILEGES ON *.* TO 'root'@'%' WITH GRANT OPTION"},
+		},
+		{
+			name: "dml privileges on databases",
+			grants: []string{
+				"GRANT USAGE ON *.* TO 'cdc'@'%'",
+				"GRANT SELECT,INSERT,UPDATE ON `test`.* TO 'cdc'@'%'",
+				"GRANT DELETE ON `test`.* TO 'cdc'@'%'",
+			},
+		},
+		{
+			name: "column privileges",
+			grants: []string{
+				"GRANT USAGE ON *.* TO 'cdc'@'%'",
+				"GRANT INSERT (`a`, `b`), UPDATE (`a`) ON `test`.`t` TO 'cdc'@'%'",
+			},
+			missing: []string{"DELETE"},
+		},
+		{
+			name:    "read only",
+			grants:  []string{"GRANT SELECT ON *.* TO 'cdc'@'%'"},
+			missing: []string{"INSERT", "UPDATE", "DELETE"},
+		},
+		{
+			name: "roles",
+			grants: []string{
+				"GRANT USAGE ON *.* TO 'cdc'@'%'",
+				"GRANT `writer`@`%` TO `cdc`@`%`",
+			},
+		},
+	}
+	for _, tc := range tests {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		rows := sqlmock.NewRows([]string{"Grants"})
+		for _, grant := range tc.grants {
+			rows.AddRow(grant)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta("SHOW GRANTS FOR CURRENT_USER()")).WillReturnRows(rows)
+		mock.ExpectClose()
+
+		missing, err := QueryMissingDMLPrivileges(context.Background(), db)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.missing, missing, tc.name)
+		require.NoError(t, db.Close())
+		require.NoError(t, mock.ExpectationsWereMet(), tc.name)
+	}
+}
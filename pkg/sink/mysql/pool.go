@@ -0,0 +1,121 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// DMLPoolName is the name of the pool writing the rows.
+	DMLPoolName = "dml"
+	// MetaPoolName is the name of the pool writing the syncpoints.
+	MetaPoolName = "meta"
+)
+
+// Pool is a connection pool of the downstream with a connection limit. The
+// rows and the syncpoints are written by separate pools, so the syncpoints
+// never wait for the connections held by the big batches of the rows. The
+// connections are probed in the background, so the broken ones are dropped
+// before they're used.
+type Pool struct {
+	*sql.DB
+
+	name    string
+	healthy atomic.Bool
+
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewPool limits the open and idle connections of the db to maxOpenConns,
+// and probes the connections every probeInterval if it's positive.
+func NewPool(
+	ctx context.Context, name string, db *sql.DB,
+	maxOpenConns int, probeInterval time.Duration,
+) *Pool {
+	db.SetMaxIdleConns(maxOpenConns)
+	db.SetMaxOpenConns(maxOpenConns)
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{DB: db, name: name, cancel: cancel}
+	p.healthy.Store(true)
+	if probeInterval > 0 {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.probe(ctx, probeInterval)
+		}()
+	}
+	log.Info("MySQL connection pool is created",
+		zap.String("pool", name),
+		zap.Int("maxOpenConns", maxOpenConns),
+		zap.Duration("probeInterval", probeInterval))
+	return p
+}
+
+// probe pings the downstream every interval, a broken connection found by
+// the ping is discarded by the driver.
+func (p *Pool) probe(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := p.PingContext(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if p.healthy.Swap(false) {
+				log.Warn("MySQL connection pool is unhealthy",
+					zap.String("pool", p.name), zap.Error(err))
+			}
+			continue
+		}
+		if !p.healthy.Swap(true) {
+			log.Info("MySQL connection pool is healthy again", zap.String("pool", p.name))
+		}
+	}
+}
+
+// Healthy returns false if the last probe failed.
+func (p *Pool) Healthy() bool {
+	return p.healthy.Load()
+}
+
+// Close stops the probe and closes the db, it can be called multiple times.
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() {
+		p.cancel()
+		p.wg.Wait()
+		p.closeErr = p.DB.Close()
+	})
+	return p.closeErr
+}
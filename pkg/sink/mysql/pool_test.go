@@ -0,0 +1,46 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolProbe(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	mock.ExpectPing().WillReturnError(errors.New("broken pipe"))
+	mock.ExpectPing()
+	mock.ExpectClose()
+
+	pool := NewPool(context.Background(), MetaPoolName, db, 2, 10*time.Millisecond)
+	require.Equal(t, 2, pool.Stats().MaxOpenConnections)
+	require.True(t, pool.Healthy())
+	require.Eventually(t, func() bool {
+		return !pool.Healthy()
+	}, 5*time.Second, time.Millisecond)
+	require.Eventually(t, pool.Healthy, 5*time.Second, time.Millisecond)
+
+	require.NoError(t, pool.Close())
+	// the pool can be closed by each backend sharing it.
+	require.NoError(t, pool.Close())
+}
@@ -15,7 +15,6 @@ package observer
 
 import (
 	"context"
-	"net/url"
 	"strings"
 	"sync"
 
@@ -65,7 +64,7 @@ func NewObserver(
 			opt(options)
 		}
 
-		sinkURI, err := url.Parse(sinkURIStr)
+		sinkURI, err := sink.ParseSinkURI(sinkURIStr)
 		if err != nil {
 			return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 		}
@@ -0,0 +1,215 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/auth"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTenant    = "public"
+	defaultNamespace = "default"
+
+	partitionSuffix = "-partition-"
+)
+
+// AdminClient is a client of the pulsar admin REST API, it's used to manage the topics.
+type AdminClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewAdminClient creates a new AdminClient by the admin URL of the config,
+// the authentication of the config is applied to the requests.
+func NewAdminClient(config *Config) (*AdminClient, error) {
+	if config.AdminURL == "" {
+		return nil, errors.New("admin url of the pulsar config is empty")
+	}
+	httpClient := &http.Client{Timeout: config.OperationTimeout}
+	// the admin API is accessed without authentication if no method is configured.
+	if a, err := setupAuthentication(config); err == nil {
+		provider, ok := a.(auth.Provider)
+		if !ok {
+			return nil, errors.Errorf("unsupported pulsar authentication %T", a)
+		}
+		if err := provider.Init(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := provider.WithTransport(http.DefaultTransport); err != nil {
+			return nil, errors.Trace(err)
+		}
+		httpClient.Transport = provider
+	}
+	return &AdminClient{
+		url:        strings.TrimSuffix(config.AdminURL, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+// CreateTopic creates the topic with the partition number, the topic is
+// non-partitioned if partitionNum is 0. It's a no-op if the topic exists.
+func (c *AdminClient) CreateTopic(ctx context.Context, topic string, partitionNum int32) error {
+	path, err := topicRESTPath(topic)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	if partitionNum > 0 {
+		path += "/partitions"
+		body = []byte(fmt.Sprint(partitionNum))
+	}
+	status, err := c.do(ctx, http.MethodPut, path, body, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict {
+		log.Info("pulsar topic already exists", zap.String("topic", topic))
+		return nil
+	}
+	log.Info("pulsar topic created", zap.String("topic", topic),
+		zap.Int32("partitionNum", partitionNum))
+	return nil
+}
+
+// GetPartitionNum returns the partition number of the topic,
+// 0 means the topic is non-partitioned.
+func (c *AdminClient) GetPartitionNum(ctx context.Context, topic string) (int32, error) {
+	path, err := topicRESTPath(topic)
+	if err != nil {
+		return 0, err
+	}
+	var metadata struct {
+		Partitions int32 `json:"partitions"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, path+"/partitions", nil, &metadata); err != nil {
+		return 0, err
+	}
+	return metadata.Partitions, nil
+}
+
+// SetRetention sets the retention policy of the topic.
+func (c *AdminClient) SetRetention(
+	ctx context.Context, topic string, retentionTime time.Duration, retentionSizeMB int64,
+) error {
+	path, err := topicRESTPath(topic)
+	if err != nil {
+		return err
+	}
+	minutes := int64(-1)
+	if retentionTime > 0 {
+		minutes = int64(retentionTime / time.Minute)
+	}
+	body, err := json.Marshal(map[string]int64{
+		"retentionTimeInMinutes": minutes,
+		"retentionSizeInMB":      retentionSizeMB,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.do(ctx, http.MethodPost, path+"/retention", body, nil)
+	return err
+}
+
+// do sends the request to the admin API and decodes the response into result
+// if it's not nil. The status conflict is returned without error, so that the
+// caller can tell whether the resource exists.
+func (c *AdminClient) do(
+	ctx context.Context, method, path string, body []byte, result interface{},
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return resp.StatusCode, nil
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, errors.Errorf("pulsar admin request %s %s failed, status: %d, body: %s",
+			method, path, resp.StatusCode, string(data))
+	}
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return resp.StatusCode, errors.Trace(err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// topicRESTPath returns the path of the topic in the admin API, the topic can be
+// a full name such as "persistent://tenant/namespace/topic", or "tenant/namespace/topic",
+// or a short name which belongs to the default namespace "public/default".
+func topicRESTPath(topic string) (string, error) {
+	domain := "persistent"
+	name := topic
+	if i := strings.Index(topic, "://"); i >= 0 {
+		domain, name = topic[:i], topic[i+len("://"):]
+	}
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		parts = []string{defaultTenant, defaultNamespace, parts[0]}
+	case 3:
+	default:
+		return "", errors.Errorf("invalid pulsar topic name %s", topic)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return "", errors.Errorf("invalid pulsar topic name %s", topic)
+		}
+	}
+	return fmt.Sprintf("/admin/v2/%s/%s", domain, strings.Join(parts, "/")), nil
+}
+
+// PartitionTopic returns the name of the partition of the partitioned topic.
+func PartitionTopic(topic string, partition int32) string {
+	return fmt.Sprintf("%s%s%d", topic, partitionSuffix, partition)
+}
+
+// GetPartitionNum returns the partition number of the topic by the client,
+// 0 means the topic is non-partitioned.
+func GetPartitionNum(client pulsar.Client, topic string) (int32, error) {
+	partitions, err := client.TopicPartitions(topic)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	// the partitions of a non-partitioned topic is the topic itself.
+	if len(partitions) == 1 && !strings.Contains(partitions[0], partitionSuffix) {
+		return 0, nil
+	}
+	return int32(len(partitions)), nil
+}
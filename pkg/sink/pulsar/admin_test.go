@@ -0,0 +1,93 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicRESTPath(t *testing.T) {
+	cases := []struct {
+		topic    string
+		expected string
+	}{
+		{"test", "/admin/v2/persistent/public/default/test"},
+		{"tenant/ns/test", "/admin/v2/persistent/tenant/ns/test"},
+		{"persistent://tenant/ns/test", "/admin/v2/persistent/tenant/ns/test"},
+		{"non-persistent://tenant/ns/test", "/admin/v2/non-persistent/tenant/ns/test"},
+		{"ns/test", ""},
+		{"persistent://tenant//test", ""},
+	}
+	for _, c := range cases {
+		path, err := topicRESTPath(c.topic)
+		if c.expected == "" {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, path)
+		}
+	}
+}
+
+func TestAdminClient(t *testing.T) {
+	requests := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests[r.Method+" "+r.URL.Path] = string(body)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/admin/v2/persistent/public/default/exists":
+			w.WriteHeader(http.StatusConflict)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"partitions":3}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	admin, err := NewAdminClient(&Config{
+		AdminURL:            server.URL + "/",
+		AuthenticationToken: "token",
+		OperationTimeout:    time.Second,
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, admin.CreateTopic(ctx, "test", 3))
+	assert.Equal(t, "3", requests["PUT /admin/v2/persistent/public/default/test/partitions"])
+	assert.NoError(t, admin.CreateTopic(ctx, "exists", 0))
+	assert.NoError(t, admin.SetRetention(ctx, "test", 2*time.Hour, -1))
+	assert.JSONEq(t, `{"retentionTimeInMinutes":120,"retentionSizeInMB":-1}`,
+		requests["POST /admin/v2/persistent/public/default/test/retention"])
+
+	partitionNum, err := admin.GetPartitionNum(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), partitionNum)
+
+	_, err = NewAdminClient(&Config{})
+	assert.Error(t, err)
+}
+
+func TestPartitionTopic(t *testing.T) {
+	assert.Equal(t, "persistent://public/default/test-partition-1",
+		PartitionTopic("persistent://public/default/test", 1))
+}
@@ -175,6 +175,17 @@ type Config struct {
 	// Protocol The message protocol type input to pulsar, pulsar currently supports canal-json, canal, maxwell
 	Protocol config.Protocol
 
+	// AdminURL the URL of the pulsar admin REST API
+	AdminURL string
+	// AutoCreateTopic create the topics which don't exist by the admin API
+	AutoCreateTopic bool
+	// TopicPartitionNum the partition number of the auto-created topics, 0 means non-partitioned
+	TopicPartitionNum int32
+	// TopicRetentionTime the retention time of the auto-created topics, 0 means not set
+	TopicRetentionTime time.Duration
+	// TopicRetentionSizeMB the retention size of the auto-created topics, nil means not set
+	TopicRetentionSizeMB *int64
+
 	// parse the sinkURI
 	u *url.URL
 }
@@ -206,32 +217,88 @@ func (c *Config) applyOAuth(params url.Values) {
 	// Go client use Oauth2 authentication
 	// https://pulsar.apache.org/docs/2.10.x/security-oauth2/#authentication-types
 	// pulsar client now support type as client_credentials only
+	oauth2 := newOAuth2(params.Get(OAuth2IssuerURL), params.Get(OAuth2Audience),
+		params.Get(OAuth2PrivateKey), params.Get(OAuth2ClientID), params.Get(OAuth2Scope))
+	if oauth2 != nil {
+		c.OAuth2 = oauth2
+	}
+}
 
-	s := params.Get(OAuth2IssuerURL)
-	if len(s) > 0 {
-		c.OAuth2[auth.ConfigParamIssuerURL] = s
+// newOAuth2 returns the parameters of the OAuth2 authentication,
+// it returns nil if any of the issuer URL, audience and private key is empty.
+func newOAuth2(issuerURL, audience, privateKey, clientID, scope string) map[string]string {
+	if issuerURL == "" || audience == "" || privateKey == "" {
+		return nil
 	}
-	s = params.Get(OAuth2Audience)
-	if len(s) > 0 {
-		c.OAuth2[auth.ConfigParamAudience] = s
+	oauth2 := map[string]string{
+		auth.ConfigParamType:      auth.ConfigParamTypeClientCredentials,
+		auth.ConfigParamIssuerURL: issuerURL,
+		auth.ConfigParamAudience:  audience,
+		auth.ConfigParamKeyFile:   privateKey,
 	}
-	s = params.Get(OAuth2Scope)
-	if len(s) > 0 {
-		c.OAuth2[auth.ConfigParamScope] = s
+	if clientID != "" {
+		oauth2[auth.ConfigParamClientID] = clientID
 	}
-	s = params.Get(OAuth2PrivateKey)
-	if len(s) > 0 {
-		c.OAuth2[auth.ConfigParamKeyFile] = s
+	if scope != "" {
+		oauth2[auth.ConfigParamScope] = scope
 	}
-	s = params.Get(OAuth2ClientID)
-	if len(s) > 0 {
-		c.OAuth2[auth.ConfigParamClientID] = s
+	return oauth2
+}
+
+// applySinkConfig applies the pulsar config of the changefeed, which can be
+// overridden by the parameters of the sink URI.
+func (c *Config) applySinkConfig(pulsarConfig *config.PulsarConfig) error {
+	if pulsarConfig == nil {
+		return nil
+	}
+	if pulsarConfig.AuthenticationToken != nil {
+		c.AuthenticationToken = *pulsarConfig.AuthenticationToken
+	}
+	if pulsarConfig.TokenFromFile != nil {
+		c.TokenFromFile = *pulsarConfig.TokenFromFile
 	}
-	if len(c.OAuth2) >= 4 {
-		c.OAuth2[auth.ConfigParamType] = auth.ConfigParamTypeClientCredentials
-	} else {
-		c.OAuth2 = make(map[string]string)
+	if pulsarConfig.BasicUserName != nil {
+		c.BasicUserName = *pulsarConfig.BasicUserName
 	}
+	if pulsarConfig.BasicPassword != nil {
+		c.BasicPassword = *pulsarConfig.BasicPassword
+	}
+	if o := pulsarConfig.OAuth2; o != nil {
+		c.OAuth2 = newOAuth2(o.OAuth2IssuerURL, o.OAuth2Audience,
+			o.OAuth2PrivateKey, o.OAuth2ClientID, o.OAuth2Scope)
+	}
+	if pulsarConfig.ConnectionTimeout != nil {
+		d, err := time.ParseDuration(*pulsarConfig.ConnectionTimeout)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		c.ConnectionTimeout = d
+	}
+	if pulsarConfig.OperationTimeout != nil {
+		d, err := time.ParseDuration(*pulsarConfig.OperationTimeout)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		c.OperationTimeout = d
+	}
+	if pulsarConfig.AdminURL != nil {
+		c.AdminURL = *pulsarConfig.AdminURL
+	}
+	if pulsarConfig.AutoCreateTopic != nil {
+		c.AutoCreateTopic = *pulsarConfig.AutoCreateTopic
+	}
+	if pulsarConfig.TopicPartitionNum != nil {
+		c.TopicPartitionNum = *pulsarConfig.TopicPartitionNum
+	}
+	if pulsarConfig.TopicRetentionTime != nil {
+		d, err := time.ParseDuration(*pulsarConfig.TopicRetentionTime)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		c.TopicRetentionTime = d
+	}
+	c.TopicRetentionSizeMB = pulsarConfig.TopicRetentionSizeMB
+	return nil
 }
 
 // Apply apply
@@ -372,8 +439,9 @@ func (c *Config) Apply(sinkURI *url.URL) error {
 	return nil
 }
 
-// NewPulsarConfig new pulsar config
-func NewPulsarConfig(sinkURI *url.URL) (*Config, error) {
+// NewPulsarConfig new pulsar config, the parameters of the sink URI take
+// precedence over the pulsar config of the changefeed.
+func NewPulsarConfig(sinkURI *url.URL, pulsarConfig *config.PulsarConfig) (*Config, error) {
 	c := &Config{
 		u:                       sinkURI,
 		ConnectionTimeout:       defaultConnectionTimeout,
@@ -385,7 +453,12 @@ func NewPulsarConfig(sinkURI *url.URL) (*Config, error) {
 		SendTimeout:     defaultSendTimeout,
 		ProducerMode:    defaultProducerModeBatch,
 	}
-	err := c.Apply(sinkURI)
+	err := c.applySinkConfig(pulsarConfig)
+	if err != nil {
+		log.L().Error("NewPulsarConfig failed", zap.Error(err))
+		return nil, err
+	}
+	err = c.Apply(sinkURI)
 	if err != nil {
 		log.L().Error("NewPulsarConfig failed", zap.Error(err))
 		return nil, err
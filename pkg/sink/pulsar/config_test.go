@@ -19,6 +19,9 @@ import (
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/auth"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,7 +53,7 @@ func TestPulsarConfig(t *testing.T) {
 			}
 
 			// Call function under test
-			config, err := NewPulsarConfig(sink)
+			config, err := NewPulsarConfig(sink, nil)
 
 			// Assert error value
 			if tt.wantErr {
@@ -75,7 +78,7 @@ func TestPulsarConfig(t *testing.T) {
 
 func TestGetBrokerURL(t *testing.T) {
 	sink, _ := url.Parse("pulsar://localhost:6650/test")
-	config, _ := NewPulsarConfig(sink)
+	config, _ := NewPulsarConfig(sink, nil)
 
 	assert.Equal(t, config.GetBrokerURL(), "pulsar://localhost:6650")
 }
@@ -83,17 +86,65 @@ func TestGetBrokerURL(t *testing.T) {
 func TestGetSinkURI(t *testing.T) {
 	sink, _ := url.Parse("pulsar://127.0.0.1:6650/persistent://tenant/namespace/test-topic" +
 		"?max-message-bytes=5000&compression=lz4")
-	config, _ := NewPulsarConfig(sink)
+	config, _ := NewPulsarConfig(sink, nil)
 
 	assert.Equal(t, config.GetSinkURI(), sink)
 }
 
 func TestGetDefaultTopicName(t *testing.T) {
 	sink, _ := url.Parse("pulsar://localhost:6650/test")
-	config, _ := NewPulsarConfig(sink)
+	config, _ := NewPulsarConfig(sink, nil)
 	assert.Equal(t, config.GetDefaultTopicName(), "test")
 
 	sink, _ = url.Parse("pulsar://127.0.0.1:6650/persistent://tenant/namespace/test-topic")
-	config, _ = NewPulsarConfig(sink)
+	config, _ = NewPulsarConfig(sink, nil)
 	assert.Equal(t, config.GetDefaultTopicName(), "persistent://tenant/namespace/test-topic")
 }
+
+func TestApplySinkConfig(t *testing.T) {
+	pulsarConfig := &config.PulsarConfig{
+		AuthenticationToken: util.AddressOf("token"),
+		OAuth2: &config.PulsarOAuth2{
+			OAuth2IssuerURL:  "https://issuer",
+			OAuth2Audience:   "audience",
+			OAuth2PrivateKey: "/tmp/key.json",
+		},
+		ConnectionTimeout:  util.AddressOf("3s"),
+		OperationTimeout:   util.AddressOf("1m"),
+		AdminURL:           util.AddressOf("http://127.0.0.1:8080"),
+		AutoCreateTopic:    util.AddressOf(true),
+		TopicPartitionNum:  util.AddressOf(int32(3)),
+		TopicRetentionTime: util.AddressOf("24h"),
+	}
+	sink, _ := url.Parse("pulsar://127.0.0.1:6650/test")
+	c, err := NewPulsarConfig(sink, pulsarConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", c.AuthenticationToken)
+	assert.Equal(t, 3*time.Second, c.ConnectionTimeout)
+	assert.Equal(t, time.Minute, c.OperationTimeout)
+	assert.Equal(t, "http://127.0.0.1:8080", c.AdminURL)
+	assert.True(t, c.AutoCreateTopic)
+	assert.Equal(t, int32(3), c.TopicPartitionNum)
+	assert.Equal(t, 24*time.Hour, c.TopicRetentionTime)
+	assert.Nil(t, c.TopicRetentionSizeMB)
+	assert.Equal(t, map[string]string{
+		auth.ConfigParamType:      auth.ConfigParamTypeClientCredentials,
+		auth.ConfigParamIssuerURL: "https://issuer",
+		auth.ConfigParamAudience:  "audience",
+		auth.ConfigParamKeyFile:   "/tmp/key.json",
+	}, c.OAuth2)
+
+	// the parameters of the sink URI take precedence.
+	sink, _ = url.Parse("pulsar://127.0.0.1:6650/test?connection-timeout=10" +
+		"&oauth2-issuer-url=https://issuer2&oauth2-audience=audience2" +
+		"&oauth2-private-key=/tmp/key2.json&oauth2-client-id=client")
+	c, err = NewPulsarConfig(sink, pulsarConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, c.ConnectionTimeout)
+	assert.Equal(t, "https://issuer2", c.OAuth2[auth.ConfigParamIssuerURL])
+	assert.Equal(t, "client", c.OAuth2[auth.ConfigParamClientID])
+
+	pulsarConfig.OperationTimeout = util.AddressOf("abc")
+	_, err = NewPulsarConfig(sink, pulsarConfig)
+	assert.Error(t, err)
+}
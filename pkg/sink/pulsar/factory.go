@@ -72,7 +72,7 @@ func setupAuthentication(config *Config) (pulsar.Authentication, error) {
 		return pulsar.NewAuthenticationTokenFromFile(config.TokenFromFile), nil
 	} else if len(config.BasicUserName) > 0 && len(config.BasicPassword) > 0 {
 		return pulsar.NewAuthenticationBasic(config.BasicUserName, config.BasicPassword)
-	} else if len(config.OAuth2) >= 5 {
+	} else if len(config.OAuth2) > 0 {
 		return pulsar.NewAuthenticationOAuth2(config.OAuth2), nil
 	} else if len(config.AuthTLSCertificatePath) > 0 && len(config.AuthTLSPrivateKeyPath) > 0 {
 		return pulsar.NewAuthenticationTLS(config.AuthTLSCertificatePath, config.AuthTLSPrivateKeyPath), nil
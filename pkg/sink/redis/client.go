@@ -0,0 +1,178 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// Client executes the commands on a connection to Redis by the RESP protocol.
+// It's not thread-safe, each worker of the sink owns a client.
+type Client struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+	timeout time.Duration
+}
+
+// NewClient connects to the Redis server, the connection is authenticated
+// and the database is selected if they're set in the config.
+func NewClient(ctx context.Context, config *Config) (*Client, error) {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	var conn net.Conn
+	var err error
+	if config.TLS {
+		host, _, _ := net.SplitHostPort(config.Addr)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", config.Addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", config.Addr)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &Client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		writer:  bufio.NewWriter(conn),
+		timeout: config.Timeout,
+	}
+
+	var cmds [][]string
+	if config.Password != "" {
+		if config.User != "" {
+			cmds = append(cmds, []string{"AUTH", config.User, config.Password})
+		} else {
+			cmds = append(cmds, []string{"AUTH", config.Password})
+		}
+	}
+	if config.DB != 0 {
+		cmds = append(cmds, []string{"SELECT", strconv.Itoa(config.DB)})
+	}
+	if err := c.Pipeline(ctx, cmds); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Pipeline sends the commands in a batch and reads all their replies,
+// the first error reply is returned if any command fails.
+func (c *Client) Pipeline(ctx context.Context, cmds [][]string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, cmd := range cmds {
+		c.writer.WriteString("*" + strconv.Itoa(len(cmd)) + "\r\n")
+		for _, arg := range cmd {
+			c.writer.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+			c.writer.WriteString(arg)
+			c.writer.WriteString("\r\n")
+		}
+	}
+	if err := c.writer.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+
+	var firstErr error
+	for i := range cmds {
+		replyErr, err := c.readReply()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if replyErr != "" && firstErr == nil {
+			firstErr = errors.Errorf("redis command %s failed: %s", cmds[i][0], replyErr)
+		}
+	}
+	return firstErr
+}
+
+// readReply reads a reply, the message of the error reply is returned.
+func (c *Client) readReply() (string, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 {
+		return "", errors.New("empty redis reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return "", nil
+	case '-':
+		return line[1:], nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if n < 0 {
+			return "", nil
+		}
+		// skip the bulk string and the trailing CRLF.
+		_, err = io.CopyN(io.Discard, c.reader, int64(n+2))
+		return "", errors.Trace(err)
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		var firstErr string
+		for i := 0; i < n; i++ {
+			replyErr, err := c.readReply()
+			if err != nil {
+				return "", err
+			}
+			if firstErr == "" {
+				firstErr = replyErr
+			}
+		}
+		return firstErr, nil
+	default:
+		return "", errors.Errorf("unknown redis reply %s", line)
+	}
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return errors.Trace(c.conn.Close())
+}
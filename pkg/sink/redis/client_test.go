@@ -0,0 +1,132 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockServer is a Redis server which records the commands, and replies an
+// error for the commands whose first argument is "ERR".
+type mockServer struct {
+	sync.Mutex
+	listener net.Listener
+	cmds     []string
+	wg       sync.WaitGroup
+}
+
+func newMockServer(t *testing.T) *mockServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &mockServer{listener: listener}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer conn.Close()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *mockServer) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			if _, err := reader.ReadString('\n'); err != nil {
+				return
+			}
+			arg, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			args = append(args, strings.TrimSuffix(arg, "\r\n"))
+		}
+		s.Lock()
+		s.cmds = append(s.cmds, strings.Join(args, " "))
+		s.Unlock()
+		reply := "+OK\r\n"
+		if len(args) > 1 && args[1] == "ERR" {
+			reply = "-ERR wrong number of arguments\r\n"
+		} else if args[0] == "HSET" {
+			reply = ":2\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *mockServer) close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func TestClientPipeline(t *testing.T) {
+	t.Parallel()
+
+	server := newMockServer(t)
+	defer server.close()
+
+	cfg := NewConfig()
+	cfg.Addr = server.listener.Addr().String()
+	cfg.Password = "123456"
+	cfg.DB = 1
+	ctx := context.Background()
+	client, err := NewClient(ctx, cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Pipeline(ctx, [][]string{
+		{"DEL", "t:1"},
+		{"HSET", "t:1", "id", "1", "name", "a"},
+	}))
+	err = client.Pipeline(ctx, [][]string{
+		{"SET", "ERR"},
+		{"PUBLISH", "channel", "t:1"},
+	})
+	require.ErrorContains(t, err, "redis command SET failed: ERR wrong number of arguments")
+	// the connection can be used after the error replies.
+	require.NoError(t, client.Pipeline(ctx, [][]string{{"DEL", "t:2"}}))
+	require.NoError(t, client.Close())
+
+	server.Lock()
+	require.Equal(t, []string{
+		"AUTH 123456", "SELECT 1", "DEL t:1", "HSET t:1 id 1 name a",
+		"SET ERR", "PUBLISH channel t:1", "DEL t:2",
+	}, server.cmds)
+	server.Unlock()
+}
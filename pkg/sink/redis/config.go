@@ -0,0 +1,270 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imdario/mergo"
+	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// ModeSnapshot writes the rows as the values of their keys, and deletes
+	// the keys of the deleted rows.
+	ModeSnapshot = "snapshot"
+	// ModeInvalidate publishes the keys of the changed rows to the channel.
+	ModeInvalidate = "invalidate"
+
+	// ValueFormatHash writes the rows as hashes whose fields are the columns.
+	ValueFormatHash = "hash"
+	// ValueFormatJSON writes the rows as strings in the JSON format.
+	ValueFormatJSON = "json"
+
+	defaultKey          = "{schema}:{table}:{pk}"
+	defaultChannel      = "ticdc-invalidation"
+	defaultWorkerCount  = 8
+	defaultMaxBatchRows = 1000
+	defaultTimeout      = 10 * time.Second
+
+	defaultPort = "6379"
+
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max batch rows.
+	maxMaxBatchRows = 100000
+)
+
+type urlConfig struct {
+	Mode         *string `form:"mode"`
+	ValueFormat  *string `form:"value-format"`
+	Key          *string `form:"key"`
+	Channel      *string `form:"channel"`
+	WorkerCount  *int    `form:"worker-count"`
+	MaxBatchRows *int    `form:"max-batch-rows"`
+	Timeout      *string `form:"timeout"`
+}
+
+// Config is the configs for the Redis sink.
+type Config struct {
+	// Addr is the address of the Redis server, such as "127.0.0.1:6379".
+	Addr     string
+	TLS      bool
+	User     string
+	Password string
+	DB       int
+
+	Mode        string
+	ValueFormat string
+	// Key is the key template of the tables which aren't matched by the key rules.
+	Key          KeyTemplate
+	KeyRules     []*KeyRule
+	Channel      string
+	WorkerCount  int
+	MaxBatchRows int
+	Timeout      time.Duration
+}
+
+// KeyRule is the key template of the tables matched by the table matcher.
+type KeyRule struct {
+	TableMatcher tfilter.Filter
+	Key          KeyTemplate
+}
+
+// NewConfig returns the default Redis sink config.
+func NewConfig() *Config {
+	return &Config{
+		Mode:         ModeSnapshot,
+		ValueFormat:  ValueFormatHash,
+		Key:          defaultKey,
+		Channel:      defaultChannel,
+		WorkerCount:  defaultWorkerCount,
+		MaxBatchRows: defaultMaxBatchRows,
+		Timeout:      defaultTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrSinkURIInvalid.GenWithStack("fail to open Redis sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsRedisScheme(scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStack(
+			"can't create Redis sink with unsupported scheme: %s", scheme)
+	}
+	c.Addr = sinkURI.Host
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		c.Addr = net.JoinHostPort(c.Addr, defaultPort)
+	}
+	c.TLS = scheme == sink.RedisSSLScheme
+	c.User = sinkURI.User.Username()
+	c.Password, _ = sinkURI.User.Password()
+	// the database number is the path of the sink URI, such as "redis://127.0.0.1/1".
+	if db := strings.Trim(sinkURI.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil || n < 0 {
+			return cerror.ErrSinkURIInvalid.GenWithStack(
+				"invalid database %s, it must be a non-negative integer", db)
+		}
+		c.DB = n
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	urlParameter, err := mergeConfig(replicaConfig, urlParameter)
+	if err != nil {
+		return err
+	}
+	if urlParameter.Mode != nil {
+		switch mode := strings.ToLower(*urlParameter.Mode); mode {
+		case ModeSnapshot, ModeInvalidate:
+			c.Mode = mode
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid mode %s, it must be %s or %s",
+				*urlParameter.Mode, ModeSnapshot, ModeInvalidate)
+		}
+	}
+	if urlParameter.ValueFormat != nil {
+		switch format := strings.ToLower(*urlParameter.ValueFormat); format {
+		case ValueFormatHash, ValueFormatJSON:
+			c.ValueFormat = format
+		default:
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid value-format %s, it must be %s or %s",
+				*urlParameter.ValueFormat, ValueFormatHash, ValueFormatJSON)
+		}
+	}
+	if urlParameter.Key != nil {
+		key := KeyTemplate(*urlParameter.Key)
+		if err := key.Validate(); err != nil {
+			return err
+		}
+		c.Key = key
+	}
+	if urlParameter.Channel != nil {
+		if *urlParameter.Channel == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack("the channel can't be empty")
+		}
+		c.Channel = *urlParameter.Channel
+	}
+	if err := getPositiveInt("worker-count", urlParameter.WorkerCount,
+		maxWorkerCount, &c.WorkerCount); err != nil {
+		return err
+	}
+	if err := getPositiveInt("max-batch-rows", urlParameter.MaxBatchRows,
+		maxMaxBatchRows, &c.MaxBatchRows); err != nil {
+		return err
+	}
+	if urlParameter.Timeout != nil {
+		timeout, err := time.ParseDuration(*urlParameter.Timeout)
+		if err != nil || timeout <= 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid timeout %s, it must be a positive duration", *urlParameter.Timeout)
+		}
+		c.Timeout = timeout
+	}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.RedisConfig != nil {
+		return c.applyKeyRules(replicaConfig.Sink.RedisConfig.KeyRules,
+			replicaConfig.CaseSensitive)
+	}
+	return nil
+}
+
+func (c *Config) applyKeyRules(rules []*config.RedisKeyRule, caseSensitive bool) error {
+	for _, r := range rules {
+		if len(r.Matcher) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the matcher of the key rule is empty")
+		}
+		key := KeyTemplate(r.Key)
+		if err := key.Validate(); err != nil {
+			return err
+		}
+		tableMatcher, err := tfilter.Parse(r.Matcher)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+		}
+		if !caseSensitive {
+			tableMatcher = tfilter.CaseInsensitive(tableMatcher)
+		}
+		c.KeyRules = append(c.KeyRules, &KeyRule{TableMatcher: tableMatcher, Key: key})
+	}
+	return nil
+}
+
+// MatchKey returns the key template of the first matched key rule,
+// or the default one if there is no matched one.
+func (c *Config) MatchKey(schema, table string) KeyTemplate {
+	for _, r := range c.KeyRules {
+		if r.TableMatcher.MatchTable(schema, table) {
+			return r.Key
+		}
+	}
+	return c.Key
+}
+
+func mergeConfig(
+	replicaConfig *config.ReplicaConfig,
+	urlParameters *urlConfig,
+) (*urlConfig, error) {
+	dest := &urlConfig{}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.RedisConfig != nil {
+		rConfig := replicaConfig.Sink.RedisConfig
+		dest.Mode = rConfig.Mode
+		dest.ValueFormat = rConfig.ValueFormat
+		dest.Key = rConfig.Key
+		dest.Channel = rConfig.Channel
+		dest.WorkerCount = rConfig.WorkerCount
+		dest.MaxBatchRows = rConfig.MaxBatchRows
+	}
+	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	return dest, nil
+}
+
+func getPositiveInt(name string, value *int, upperLimit int, target *int) error {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	if c <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %d, which must be greater than 0", name, c)
+	}
+	if c > upperLimit {
+		log.Warn(name+" too large",
+			zap.Int("original", c), zap.Int("override", upperLimit))
+		c = upperLimit
+	}
+	*target = c
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	schemaPlaceholder = "schema"
+	tablePlaceholder  = "table"
+	pkPlaceholder     = "pk"
+)
+
+// placeholderRE is used to match all placeholders in the key template.
+var placeholderRE = regexp.MustCompile(`\{([A-Za-z0-9_$]+)\}`)
+
+// KeyTemplate is the template of the keys of the rows, such as
+// "{schema}:{table}:{pk}" or "user:{id}".
+type KeyTemplate string
+
+// Validate checks whether the key template is valid or not. It must contain
+// a placeholder, otherwise all the rows have the same key.
+func (k KeyTemplate) Validate() error {
+	if !placeholderRE.MatchString(string(k)) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid key %s, it must contain a placeholder such as {pk}", string(k))
+	}
+	return nil
+}
+
+// Substitute converts the key template to the key of the row whose column
+// values are cols. "{pk}" is substituted by the handle key values joined by ':',
+// and the column names are matched case-insensitively, the placeholder of a
+// column which is NULL or not found in the row is substituted for 'null'.
+func (k KeyTemplate) Substitute(
+	table *model.TableName, cols []*model.Column,
+) (string, error) {
+	var err error
+	key := placeholderRE.ReplaceAllStringFunc(string(k), func(placeholder string) string {
+		switch name := placeholder[1 : len(placeholder)-1]; name {
+		case schemaPlaceholder:
+			return table.Schema
+		case tablePlaceholder:
+			return table.Table
+		case pkPlaceholder:
+			var values []string
+			for _, col := range cols {
+				if col != nil && col.Flag.IsHandleKey() {
+					values = append(values, model.ColumnValueString(col.Value))
+				}
+			}
+			if len(values) == 0 {
+				err = cerror.ErrSinkInvalidConfig.GenWithStack(
+					"table %s has no primary key or not null unique key for {pk}",
+					table.String())
+			}
+			return strings.Join(values, ":")
+		default:
+			for _, col := range cols {
+				if col != nil && strings.EqualFold(col.Name, name) {
+					return model.ColumnValueString(col.Value)
+				}
+			}
+			return model.ColumnValueString(nil)
+		}
+	})
+	return key, err
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyTemplateSubstitute(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "tenant", Value: []byte("a"), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "Name", Value: "b"},
+		{Name: "age", Value: nil},
+	}
+	for template, expected := range map[KeyTemplate]string{
+		"{schema}:{table}:{pk}":  "test:t:1:a",
+		"user:{name}:{age}":      "user:b:null",
+		"user:{unknown}:{table}": "user:null:t",
+	} {
+		key, err := template.Substitute(table, cols)
+		require.NoError(t, err)
+		require.Equal(t, expected, key)
+	}
+
+	// the tables without handle keys can't use {pk}.
+	_, err := KeyTemplate("{pk}").Substitute(table, cols[2:])
+	require.ErrorContains(t, err, "no primary key")
+}
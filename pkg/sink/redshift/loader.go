@@ -0,0 +1,280 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/redshiftdataapiservice"
+	"github.com/aws/aws-sdk-go/service/redshiftdataapiservice/redshiftdataapiserviceiface"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = 500 * time.Millisecond
+
+	// The names of the meta columns of the staging table, they're the same
+	// as the ones in the header row of the csv data files.
+	columnOperationType = "_tidb_op"
+	columnTableName     = "_tidb_table"
+	columnSchemaName    = "_tidb_schema"
+	columnCommitTs      = "_tidb_commit_ts"
+	columnPhysicalTime  = "_tidb_commit_physical_time"
+	columnIngestionTime = "_tidb_ingestion_time"
+	columnRowNumber     = "_tidb_row_number"
+
+	stagingTableSuffix = "_ticdc_stage"
+)
+
+// Loader loads the csv data files written by the cloud storage sink into
+// Redshift. Each data file is copied into a temporary staging table, then the
+// latest change of each row is merged into the target table. All the statements
+// of a data file are executed in a transaction by the Redshift Data API.
+type Loader struct {
+	changefeedID model.ChangeFeedID
+	client       redshiftdataapiserviceiface.RedshiftDataAPIServiceAPI
+	config       *config.RedshiftConfig
+	csvConfig    *common.Config
+	compression  string
+	// location is the s3 URI of the sink, such as "s3://bucket/prefix".
+	location     string
+	pollInterval time.Duration
+}
+
+// NewLoader creates a Loader for the cloud storage sink whose sink URI is
+// sinkURI, the data files are encoded by csvConfig and compressed by compression.
+func NewLoader(
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	cfg *config.RedshiftConfig,
+	csvConfig *common.Config,
+	compression string,
+) (*Loader, error) {
+	awsConfig := aws.NewConfig()
+	if region := util.GetOrZero(cfg.Region); region != "" {
+		awsConfig = awsConfig.WithRegion(region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newLoader(changefeedID, redshiftdataapiservice.New(sess),
+		sinkURI, cfg, csvConfig, compression), nil
+}
+
+func newLoader(
+	changefeedID model.ChangeFeedID,
+	client redshiftdataapiserviceiface.RedshiftDataAPIServiceAPI,
+	sinkURI *url.URL,
+	cfg *config.RedshiftConfig,
+	csvConfig *common.Config,
+	compression string,
+) *Loader {
+	return &Loader{
+		changefeedID: changefeedID,
+		client:       client,
+		config:       cfg,
+		csvConfig:    csvConfig,
+		compression:  compression,
+		location:     fmt.Sprintf("s3://%s/%s", sinkURI.Host, strings.Trim(sinkURI.Path, "/")),
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Load loads the data file of the table into Redshift, filePath is the path of
+// the data file relative to the sink URI. It returns after the statements finish.
+func (l *Loader) Load(
+	ctx context.Context, tableInfo *model.TableInfo, filePath string, _ int64,
+) error {
+	start := time.Now()
+	sqls := l.buildStatements(tableInfo, l.location+"/"+path.Clean(filePath))
+	input := &redshiftdataapiservice.BatchExecuteStatementInput{
+		Database:      l.config.Database,
+		DbUser:        l.config.DBUser,
+		SecretArn:     l.config.SecretARN,
+		Sqls:          aws.StringSlice(sqls),
+		StatementName: aws.String(fmt.Sprintf("ticdc-%s-%s", l.changefeedID.Namespace, l.changefeedID.ID)),
+	}
+	if util.GetOrZero(l.config.ClusterIdentifier) != "" {
+		input.ClusterIdentifier = l.config.ClusterIdentifier
+	} else {
+		input.WorkgroupName = l.config.WorkgroupName
+	}
+	output, err := l.client.BatchExecuteStatementWithContext(ctx, input)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := l.waitStatement(ctx, aws.StringValue(output.Id)); err != nil {
+		return errors.Annotatef(err, "load data file %s into redshift", filePath)
+	}
+	log.Debug("load data file into redshift success",
+		zap.String("namespace", l.changefeedID.Namespace),
+		zap.String("changefeed", l.changefeedID.ID),
+		zap.String("path", filePath),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// waitStatement polls the status of the statement until it finishes.
+func (l *Loader) waitStatement(ctx context.Context, id string) error {
+	ticker := time.NewTicker(l.pollInterval)
+	defer ticker.Stop()
+	for {
+		output, err := l.client.DescribeStatementWithContext(ctx,
+			&redshiftdataapiservice.DescribeStatementInput{Id: aws.String(id)})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch aws.StringValue(output.Status) {
+		case redshiftdataapiservice.StatusStringFinished:
+			return nil
+		case redshiftdataapiservice.StatusStringFailed, redshiftdataapiservice.StatusStringAborted:
+			return errors.Errorf("redshift statement %s is %s: %s",
+				id, aws.StringValue(output.Status), aws.StringValue(output.Error))
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// buildStatements returns the statements which load the data file into the
+// target table. The rows of the tables without handle key are appended, and
+// the deleted rows are ignored since they can't be identified.
+func (l *Loader) buildStatements(tableInfo *model.TableInfo, location string) []string {
+	schema := util.GetOrZero(l.config.Schema)
+	if schema == "" {
+		schema = tableInfo.TableName.Schema
+	}
+	target := quoteIdentifier(schema) + "." + quoteIdentifier(tableInfo.TableName.Table)
+	stage := quoteIdentifier(tableInfo.TableName.Table + stagingTableSuffix)
+
+	var columns, keys []string
+	for _, col := range tableInfo.Columns {
+		name := quoteIdentifier(col.Name.O)
+		columns = append(columns, name)
+		if flag := tableInfo.ColumnsFlag[col.ID]; flag.IsHandleKey() {
+			keys = append(keys, name)
+		}
+	}
+	columnList := strings.Join(columns, ", ")
+
+	sqls := []string{
+		fmt.Sprintf("CREATE TEMP TABLE %s AS SELECT %s, %s FROM %s WHERE 1 = 0",
+			stage, strings.Join(l.metaColumns(), ", "), columnList, target),
+		l.buildCopy(stage, location),
+	}
+	if len(keys) == 0 {
+		sqls = append(sqls, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s <> 'D'",
+			target, columnList, columnList, stage, quoteIdentifier(columnOperationType)))
+	} else {
+		conditions := make([]string, 0, len(keys))
+		for _, key := range keys {
+			conditions = append(conditions, fmt.Sprintf("%s.%s = %s.%s", target, key, stage, key))
+		}
+		sqls = append(sqls,
+			fmt.Sprintf("DELETE FROM %s USING %s WHERE %s",
+				target, stage, strings.Join(conditions, " AND ")),
+			// the deleted rows are ordered after the other ones with the same
+			// commit ts, since the update event may be split into DELETE and INSERT.
+			fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM (SELECT *, ROW_NUMBER() OVER ("+
+				"PARTITION BY %s ORDER BY %s DESC, CASE WHEN %s = 'D' THEN 1 ELSE 0 END) AS %s "+
+				"FROM %s) AS s WHERE %s = 1 AND %s <> 'D'",
+				target, columnList, columnList,
+				strings.Join(keys, ", "), quoteIdentifier(columnCommitTs),
+				quoteIdentifier(columnOperationType), quoteIdentifier(columnRowNumber),
+				stage, quoteIdentifier(columnRowNumber), quoteIdentifier(columnOperationType)))
+	}
+	return append(sqls, "DROP TABLE "+stage)
+}
+
+// metaColumns returns the expressions of the meta columns in the staging table,
+// the order is the same as the one in the csv rows.
+func (l *Loader) metaColumns() []string {
+	columns := []string{
+		castNull("VARCHAR(1)", columnOperationType),
+		castNull("VARCHAR(256)", columnTableName),
+		castNull("VARCHAR(256)", columnSchemaName),
+	}
+	if l.csvConfig.IncludeCommitTs {
+		columns = append(columns, castNull("BIGINT", columnCommitTs))
+	}
+	if l.csvConfig.OutputPhysicalTime {
+		columns = append(columns,
+			castNull("BIGINT", columnPhysicalTime),
+			castNull("BIGINT", columnIngestionTime))
+	}
+	return columns
+}
+
+func (l *Loader) buildCopy(stage, location string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "COPY %s FROM %s IAM_ROLE %s", stage,
+		quoteLiteral(location), quoteLiteral(util.GetOrZero(l.config.IAMRole)))
+	if util.GetOrZero(l.config.Region) != "" {
+		fmt.Fprintf(&b, " REGION %s", quoteLiteral(*l.config.Region))
+	}
+	// the columns are escaped by backslashes if the quote is not set.
+	if l.csvConfig.Quote != "" {
+		fmt.Fprintf(&b, " FORMAT AS CSV QUOTE AS %s", quoteLiteral(l.csvConfig.Quote))
+	} else {
+		b.WriteString(" ESCAPE")
+	}
+	fmt.Fprintf(&b, " DELIMITER AS %s NULL AS %s",
+		quoteLiteral(l.csvConfig.Delimiter), quoteLiteral(l.csvConfig.NullString))
+	if l.csvConfig.OutputHeader {
+		b.WriteString(" IGNOREHEADER 1")
+	}
+	switch l.compression {
+	case config.FileCompressionGzip:
+		b.WriteString(" GZIP")
+	case config.FileCompressionZstd:
+		b.WriteString(" ZSTD")
+	}
+	b.WriteString(" DATEFORMAT 'auto' TIMEFORMAT 'auto'")
+	return b.String()
+}
+
+func castNull(tp, name string) string {
+	return fmt.Sprintf("CAST(NULL AS %s) AS %s", tp, quoteIdentifier(name))
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes the string literal, the backslash is an escape
+// character in the string literals of Redshift.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redshift
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/redshiftdataapiservice"
+	"github.com/aws/aws-sdk-go/service/redshiftdataapiservice/redshiftdataapiserviceiface"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDataAPI struct {
+	redshiftdataapiserviceiface.RedshiftDataAPIServiceAPI
+
+	input    *redshiftdataapiservice.BatchExecuteStatementInput
+	statuses []string
+}
+
+func (m *mockDataAPI) BatchExecuteStatementWithContext(
+	_ aws.Context, input *redshiftdataapiservice.BatchExecuteStatementInput, _ ...request.Option,
+) (*redshiftdataapiservice.BatchExecuteStatementOutput, error) {
+	m.input = input
+	return &redshiftdataapiservice.BatchExecuteStatementOutput{Id: aws.String("id")}, nil
+}
+
+func (m *mockDataAPI) DescribeStatementWithContext(
+	_ aws.Context, _ *redshiftdataapiservice.DescribeStatementInput, _ ...request.Option,
+) (*redshiftdataapiservice.DescribeStatementOutput, error) {
+	status := m.statuses[0]
+	m.statuses = m.statuses[1:]
+	return &redshiftdataapiservice.DescribeStatementOutput{
+		Status: aws.String(status),
+		Error:  aws.String("syntax error"),
+	}, nil
+}
+
+func newTestLoader(t *testing.T, client *mockDataAPI) *Loader {
+	sinkURI, err := url.Parse("s3://bucket/prefix/?protocol=csv")
+	require.NoError(t, err)
+	csvConfig := common.NewConfig(config.ProtocolCsv)
+	csvConfig.Delimiter = ","
+	csvConfig.Quote = "\""
+	csvConfig.NullString = "\\N"
+	csvConfig.IncludeCommitTs = true
+	l := newLoader(model.DefaultChangeFeedID("test"), client, sinkURI,
+		&config.RedshiftConfig{
+			ClusterIdentifier: util.AddressOf("cluster"),
+			Database:          util.AddressOf("dev"),
+			IAMRole:           util.AddressOf("arn:aws:iam::123456789012:role/copy"),
+			Schema:            util.AddressOf("ods"),
+		}, csvConfig, config.FileCompressionGzip)
+	l.pollInterval = time.Millisecond
+	return l
+}
+
+func newTestTableInfo(withKey bool) *model.TableInfo {
+	idFlag := model.BinaryFlag
+	var indexColumns [][]int
+	if withKey {
+		idFlag |= model.HandleKeyFlag | model.PrimaryKeyFlag
+		indexColumns = [][]int{{0}}
+	}
+	info := model.BuildTiDBTableInfo([]*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: idFlag},
+		{Name: "name", Type: mysql.TypeVarchar},
+	}, indexColumns)
+	info.Name = timodel.NewCIStr("t")
+	for i, col := range info.Columns {
+		col.ID = int64(i + 1)
+	}
+	return model.WrapTableInfo(1, "test", 1, info)
+}
+
+func TestBuildStatements(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLoader(t, nil)
+	location := "s3://bucket/prefix/test/t/1/CDC000001.csv.gz"
+	create := `CREATE TEMP TABLE "t_ticdc_stage" AS SELECT CAST(NULL AS VARCHAR(1)) AS "_tidb_op", ` +
+		`CAST(NULL AS VARCHAR(256)) AS "_tidb_table", CAST(NULL AS VARCHAR(256)) AS "_tidb_schema", ` +
+		`CAST(NULL AS BIGINT) AS "_tidb_commit_ts", "id", "name" FROM "ods"."t" WHERE 1 = 0`
+	copyStmt := `COPY "t_ticdc_stage" FROM 's3://bucket/prefix/test/t/1/CDC000001.csv.gz' ` +
+		`IAM_ROLE 'arn:aws:iam::123456789012:role/copy' FORMAT AS CSV QUOTE AS '"' ` +
+		`DELIMITER AS ',' NULL AS '\\N' GZIP DATEFORMAT 'auto' TIMEFORMAT 'auto'`
+	require.Equal(t, []string{
+		create,
+		copyStmt,
+		`DELETE FROM "ods"."t" USING "t_ticdc_stage" WHERE "ods"."t"."id" = "t_ticdc_stage"."id"`,
+		`INSERT INTO "ods"."t" ("id", "name") SELECT "id", "name" FROM (SELECT *, ROW_NUMBER() OVER (` +
+			`PARTITION BY "id" ORDER BY "_tidb_commit_ts" DESC, CASE WHEN "_tidb_op" = 'D' THEN 1 ELSE 0 END) ` +
+			`AS "_tidb_row_number" FROM "t_ticdc_stage") AS s WHERE "_tidb_row_number" = 1 AND "_tidb_op" <> 'D'`,
+		`DROP TABLE "t_ticdc_stage"`,
+	}, l.buildStatements(newTestTableInfo(true), location))
+
+	// the rows of the table without handle key are appended.
+	require.Equal(t, []string{
+		create,
+		copyStmt,
+		`INSERT INTO "ods"."t" ("id", "name") SELECT "id", "name" FROM "t_ticdc_stage" WHERE "_tidb_op" <> 'D'`,
+		`DROP TABLE "t_ticdc_stage"`,
+	}, l.buildStatements(newTestTableInfo(false), location))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := &mockDataAPI{statuses: []string{
+		redshiftdataapiservice.StatusStringSubmitted,
+		redshiftdataapiservice.StatusStringStarted,
+		redshiftdataapiservice.StatusStringFinished,
+	}}
+	l := newTestLoader(t, client)
+	require.NoError(t, l.Load(ctx, newTestTableInfo(true), "test/t/1/CDC000001.csv.gz", 0))
+	require.Equal(t, "cluster", aws.StringValue(client.input.ClusterIdentifier))
+	require.Equal(t, "dev", aws.StringValue(client.input.Database))
+	require.Len(t, client.input.Sqls, 5)
+	require.Contains(t, aws.StringValue(client.input.Sqls[1]),
+		"'s3://bucket/prefix/test/t/1/CDC000001.csv.gz'")
+	require.Empty(t, client.statuses)
+
+	client.statuses = []string{redshiftdataapiservice.StatusStringFailed}
+	err := l.Load(ctx, newTestTableInfo(true), "test/t/1/CDC000002.csv.gz", 0)
+	require.ErrorContains(t, err, "syntax error")
+}
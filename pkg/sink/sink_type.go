@@ -61,12 +61,40 @@ const (
 	AzblobScheme = "azblob"
 	// AzureScheme is an alias for "azblob"
 	AzureScheme = "azure"
+	// WebHDFSScheme indicates the scheme is HDFS, which is accessed by the
+	// WebHDFS REST API.
+	WebHDFSScheme = "webhdfs"
+	// WebHDFSSSLScheme indicates the scheme is HDFS, which is accessed by the
+	// WebHDFS REST API over https.
+	WebHDFSSSLScheme = "swebhdfs"
+	// HDFSScheme is an alias for "webhdfs".
+	HDFSScheme = "hdfs"
+	// OSSScheme indicates the scheme is Alibaba Cloud OSS.
+	OSSScheme = "oss"
+	// COSScheme indicates the scheme is Tencent Cloud COS.
+	COSScheme = "cos"
 	// CloudStorageNoopScheme indicates the scheme is noop.
 	CloudStorageNoopScheme = "noop"
 	// PulsarScheme  indicates the scheme is pulsar
 	PulsarScheme = "pulsar"
 	// PulsarSSLScheme indicates the scheme is pulsar+ssl
 	PulsarSSLScheme = "pulsar+ssl"
+	// ClickHouseScheme indicates the scheme is clickhouse.
+	ClickHouseScheme = "clickhouse"
+	// ClickHouseSSLScheme indicates the scheme is clickhouse+ssl.
+	ClickHouseSSLScheme = "clickhouse+ssl"
+	// ElasticsearchScheme indicates the scheme is elasticsearch.
+	ElasticsearchScheme = "elasticsearch"
+	// ElasticsearchSSLScheme indicates the scheme is elasticsearch+ssl.
+	ElasticsearchSSLScheme = "elasticsearch+ssl"
+	// RedisScheme indicates the scheme is redis.
+	RedisScheme = "redis"
+	// RedisSSLScheme indicates the scheme is rediss.
+	RedisSSLScheme = "rediss"
+	// HTTPScheme indicates the scheme is http.
+	HTTPScheme = "http"
+	// HTTPSScheme indicates the scheme is https.
+	HTTPSScheme = "https"
 )
 
 // IsMQScheme returns true if the scheme belong to mq scheme.
@@ -83,5 +111,37 @@ func IsMySQLCompatibleScheme(scheme string) bool {
 // IsStorageScheme returns true if the scheme belong to storage scheme.
 func IsStorageScheme(scheme string) bool {
 	return scheme == FileScheme || scheme == S3Scheme || scheme == GCSScheme ||
-		scheme == GSScheme || scheme == AzblobScheme || scheme == AzureScheme || scheme == CloudStorageNoopScheme
+		scheme == GSScheme || scheme == AzblobScheme || scheme == AzureScheme || scheme == CloudStorageNoopScheme ||
+		IsWebHDFSScheme(scheme) || IsObjectStorageScheme(scheme)
+}
+
+// IsObjectStorageScheme returns true if the scheme belong to the object
+// storages which are accessed by their native APIs rather than by BR.
+func IsObjectStorageScheme(scheme string) bool {
+	return scheme == OSSScheme || scheme == COSScheme
+}
+
+// IsWebHDFSScheme returns true if the scheme belong to the WebHDFS scheme.
+func IsWebHDFSScheme(scheme string) bool {
+	return scheme == WebHDFSScheme || scheme == WebHDFSSSLScheme || scheme == HDFSScheme
+}
+
+// IsClickHouseScheme returns true if the scheme belong to clickhouse scheme.
+func IsClickHouseScheme(scheme string) bool {
+	return scheme == ClickHouseScheme || scheme == ClickHouseSSLScheme
+}
+
+// IsElasticsearchScheme returns true if the scheme belong to elasticsearch scheme.
+func IsElasticsearchScheme(scheme string) bool {
+	return scheme == ElasticsearchScheme || scheme == ElasticsearchSSLScheme
+}
+
+// IsRedisScheme returns true if the scheme belong to redis scheme.
+func IsRedisScheme(scheme string) bool {
+	return scheme == RedisScheme || scheme == RedisSSLScheme
+}
+
+// IsWebhookScheme returns true if the scheme belong to webhook scheme.
+func IsWebhookScheme(scheme string) bool {
+	return scheme == HTTPScheme || scheme == HTTPSScheme
 }
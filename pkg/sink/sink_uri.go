@@ -0,0 +1,51 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"net/url"
+	"strings"
+)
+
+// ParseSinkURI parses the sink URI. Different from url.Parse, the host of
+// the MQ sink URI can be a comma-separated broker list which contains IPv6
+// literals, such as `kafka://[::1]:9092,[::2]:9092/topic`. The broker list
+// is kept as it is in the Host of the returned URL.
+func ParseSinkURI(rawURI string) (*url.URL, error) {
+	uri, err := url.Parse(rawURI)
+	if err == nil {
+		return uri, nil
+	}
+
+	scheme, rest, ok := strings.Cut(rawURI, "://")
+	if !ok || !IsMQScheme(strings.ToLower(scheme)) {
+		return nil, err
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	host := rest[:end]
+	if !strings.Contains(host, ",") || strings.Contains(host, "@") {
+		return nil, err
+	}
+	// Parse the URI without the broker list, and put the broker list back,
+	// the brokers are validated when the MQ options are applied.
+	uri, parseErr := url.Parse(scheme + "://" + rest[end:])
+	if parseErr != nil {
+		return nil, err
+	}
+	uri.Host = host
+	return uri, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+// maxErrorBodySize is the max size of the response body in the error message.
+const maxErrorBodySize = 1024
+
+// Client posts the batches to the endpoint, the failed requests are retried
+// with the exponential backoff.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// statusError is returned if the status code of the response isn't 2xx.
+type statusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("webhook request failed, status: %d, body: %s", e.statusCode, e.body)
+}
+
+// NewClient creates a Client by the config.
+func NewClient(config *Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Post posts the data to the endpoint, it returns nil only if the endpoint
+// responds 2xx. The 4xx responses except 408 and 429 aren't retried since
+// the request is rejected by the endpoint.
+func (c *Client) Post(ctx context.Context, contentType string, data []byte) error {
+	return retry.Do(ctx, func() error {
+		err := c.post(ctx, contentType, data)
+		if err != nil {
+			log.Warn("webhook request failed, it may be retried",
+				zap.String("endpoint", c.config.Endpoint), zap.Error(err))
+		}
+		return err
+	}, retry.WithMaxTries(uint64(c.config.MaxRetries)+1),
+		retry.WithBackoffBaseDelay(c.config.RetryBackoff.Milliseconds()),
+		retry.WithBackoffMaxDelay(c.config.MaxRetryBackoff.Milliseconds()),
+		retry.WithIsRetryableErr(isRetryable))
+}
+
+func (c *Client) post(ctx context.Context, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.config.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if c.config.User != "" {
+		req.SetBasicAuth(c.config.User, c.config.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return errors.Trace(err)
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return errors.Trace(&statusError{
+		statusCode: resp.StatusCode,
+		body:       strings.TrimSpace(string(body)),
+	})
+}
+
+func isRetryable(err error) bool {
+	if errors.Cause(err) == context.Canceled {
+		return false
+	}
+	statusErr, ok := errors.Cause(err).(*statusError)
+	if !ok {
+		return true
+	}
+	switch {
+	case statusErr.statusCode == http.StatusRequestTimeout,
+		statusErr.statusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return statusErr.statusCode >= http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPost(t *testing.T) {
+	t.Parallel()
+
+	var requests int64
+	var status int64 = http.StatusOK
+	var contentType, auth, user, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("X-Token")
+		user, _, _ = r.BasicAuth()
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(int(atomic.LoadInt64(&status)))
+	}))
+	defer server.Close()
+
+	cfg := NewConfig()
+	cfg.Endpoint = server.URL
+	cfg.User = "user"
+	cfg.Headers = map[string]string{"X-Token": "abc"}
+	cfg.MaxRetries = 2
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetryBackoff = time.Millisecond
+	client := NewClient(cfg)
+
+	ctx := context.Background()
+	require.NoError(t, client.Post(ctx, "application/json", []byte("{}")))
+	require.Equal(t, int64(1), atomic.LoadInt64(&requests))
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, "abc", auth)
+	require.Equal(t, "user", user)
+	require.Equal(t, "{}", body)
+
+	// the server errors are retried until the max retries are reached.
+	atomic.StoreInt64(&requests, 0)
+	atomic.StoreInt64(&status, http.StatusServiceUnavailable)
+	err := client.Post(ctx, "application/json", []byte("{}"))
+	require.ErrorContains(t, err, "status: 503")
+	require.Equal(t, int64(3), atomic.LoadInt64(&requests))
+
+	// the rejected requests aren't retried.
+	atomic.StoreInt64(&requests, 0)
+	atomic.StoreInt64(&status, http.StatusBadRequest)
+	err = client.Post(ctx, "application/json", []byte("{}"))
+	require.ErrorContains(t, err, "status: 400")
+	require.Equal(t, int64(1), atomic.LoadInt64(&requests))
+}
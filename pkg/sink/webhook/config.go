@@ -0,0 +1,228 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/imdario/mergo"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWorkerCount     = 4
+	defaultMaxBatchRows    = 1000
+	defaultMaxRetries      = 10
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultMaxRetryBackoff = 10 * time.Second
+	defaultTimeout         = 30 * time.Second
+
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max batch rows.
+	maxMaxBatchRows = 100000
+)
+
+// sinkParameters are the parameters of the sink URI which are consumed by
+// TiCDC, they're removed from the endpoint. The other parameters are kept,
+// such as the token which is verified by the endpoint.
+var sinkParameters = []string{
+	config.ProtocolKey,
+	"enable-tidb-extension",
+	"max-message-bytes",
+	"only-output-updated-columns",
+	"output-physical-time",
+	"canal-json-flat",
+	"worker-count",
+	"max-batch-rows",
+	"max-retries",
+	"retry-backoff",
+	"max-retry-backoff",
+	"timeout",
+}
+
+type urlConfig struct {
+	WorkerCount     *int    `form:"worker-count"`
+	MaxBatchRows    *int    `form:"max-batch-rows"`
+	MaxRetries      *int    `form:"max-retries"`
+	RetryBackoff    *string `form:"retry-backoff"`
+	MaxRetryBackoff *string `form:"max-retry-backoff"`
+	Timeout         *string `form:"timeout"`
+}
+
+// Config is the configs for the webhook sink.
+type Config struct {
+	// Endpoint is the URL which the batches are posted to.
+	Endpoint string
+	User     string
+	Password string
+	Headers  map[string]string
+
+	WorkerCount  int
+	MaxBatchRows int
+	// MaxRetries is the max number of retries of a failed request,
+	// zero means the failed requests aren't retried.
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	Timeout         time.Duration
+}
+
+// NewConfig returns the default webhook sink config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:     defaultWorkerCount,
+		MaxBatchRows:    defaultMaxBatchRows,
+		MaxRetries:      defaultMaxRetries,
+		RetryBackoff:    defaultRetryBackoff,
+		MaxRetryBackoff: defaultMaxRetryBackoff,
+		Timeout:         defaultTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrSinkURIInvalid.GenWithStack("fail to open webhook sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsWebhookScheme(scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStack(
+			"can't create webhook sink with unsupported scheme: %s", scheme)
+	}
+	c.Endpoint = endpoint(sinkURI)
+	c.User = sinkURI.User.Username()
+	c.Password, _ = sinkURI.User.Password()
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	urlParameter, err := mergeConfig(replicaConfig, urlParameter)
+	if err != nil {
+		return err
+	}
+	if err := getPositiveInt("worker-count", urlParameter.WorkerCount,
+		maxWorkerCount, &c.WorkerCount); err != nil {
+		return err
+	}
+	if err := getPositiveInt("max-batch-rows", urlParameter.MaxBatchRows,
+		maxMaxBatchRows, &c.MaxBatchRows); err != nil {
+		return err
+	}
+	if urlParameter.MaxRetries != nil {
+		if *urlParameter.MaxRetries < 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid max-retries %d, which must be greater than or equal to 0",
+				*urlParameter.MaxRetries)
+		}
+		c.MaxRetries = *urlParameter.MaxRetries
+	}
+	if err := getDuration("retry-backoff", urlParameter.RetryBackoff, &c.RetryBackoff); err != nil {
+		return err
+	}
+	if err := getDuration("max-retry-backoff", urlParameter.MaxRetryBackoff,
+		&c.MaxRetryBackoff); err != nil {
+		return err
+	}
+	if c.MaxRetryBackoff < c.RetryBackoff {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"max-retry-backoff %s must be greater than or equal to retry-backoff %s",
+			c.MaxRetryBackoff, c.RetryBackoff)
+	}
+	if err := getDuration("timeout", urlParameter.Timeout, &c.Timeout); err != nil {
+		return err
+	}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.WebhookConfig != nil {
+		for name := range replicaConfig.Sink.WebhookConfig.Headers {
+			if strings.TrimSpace(name) == "" {
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"the name of the webhook header can't be empty")
+			}
+		}
+		c.Headers = replicaConfig.Sink.WebhookConfig.Headers
+	}
+	return nil
+}
+
+func mergeConfig(
+	replicaConfig *config.ReplicaConfig,
+	urlParameters *urlConfig,
+) (*urlConfig, error) {
+	dest := &urlConfig{}
+	if replicaConfig.Sink != nil && replicaConfig.Sink.WebhookConfig != nil {
+		wConfig := replicaConfig.Sink.WebhookConfig
+		dest.WorkerCount = wConfig.WorkerCount
+		dest.MaxBatchRows = wConfig.MaxBatchRows
+		dest.MaxRetries = wConfig.MaxRetries
+		dest.RetryBackoff = wConfig.RetryBackoff
+		dest.MaxRetryBackoff = wConfig.MaxRetryBackoff
+	}
+	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	return dest, nil
+}
+
+// endpoint returns the sink URI without the user info and the sink parameters.
+func endpoint(sinkURI *url.URL) string {
+	u := *sinkURI
+	u.User = nil
+	query := u.Query()
+	for _, key := range sinkParameters {
+		query.Del(key)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func getPositiveInt(name string, value *int, upperLimit int, target *int) error {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	if c <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %d, which must be greater than 0", name, c)
+	}
+	if c > upperLimit {
+		log.Warn(name+" too large",
+			zap.Int("original", c), zap.Int("override", upperLimit))
+		c = upperLimit
+	}
+	*target = c
+	return nil
+}
+
+func getDuration(name string, value *string, target *time.Duration) error {
+	if value == nil {
+		return nil
+	}
+	d, err := time.ParseDuration(*value)
+	if err != nil || d <= 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"invalid %s %s, it must be a positive duration", name, *value)
+	}
+	*target = d
+	return nil
+}
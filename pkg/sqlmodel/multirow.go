@@ -226,9 +226,12 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 
 	var buf strings.Builder
 	buf.Grow(1024)
-	if tp == DMLReplace {
+	switch tp {
+	case DMLReplace:
 		buf.WriteString("REPLACE INTO ")
-	} else {
+	case DMLInsertIgnore:
+		buf.WriteString("INSERT IGNORE INTO ")
+	default:
 		buf.WriteString("INSERT INTO ")
 	}
 	buf.WriteString(first.targetTable.QuoteString())
@@ -293,3 +296,53 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 	}
 	return buf.String(), args
 }
+
+// GenInsertIfNewerSQL generates the INSERT ... ON DUPLICATE KEY UPDATE SQL and
+// its arguments, the existing row is only overwritten if the value of its
+// timestampColumn is not greater than the new one.
+// Input `changes` should have same target table and same modifiable columns,
+// otherwise the behaviour is undefined.
+func GenInsertIfNewerSQL(timestampColumn string, changes ...*RowChange) (string, []interface{}) {
+	sql, args := GenInsertSQL(DMLInsert, changes...)
+	if len(changes) == 0 {
+		return sql, args
+	}
+
+	first := changes[0]
+	columnNames := make([]string, 0, len(first.sourceTableInfo.Columns))
+	for _, col := range first.sourceTableInfo.Columns {
+		if isGenerated(first.targetTableInfo.Columns, col.Name) {
+			continue
+		}
+		columnNames = append(columnNames, col.Name.O)
+	}
+	return sql + GenOnDuplicateUpdateIfNewer(columnNames, timestampColumn), args
+}
+
+// GenOnDuplicateUpdateIfNewer generates the ON DUPLICATE KEY UPDATE clause
+// which only updates the columns if the value of the timestampColumn of the
+// new row is not less than the existing one. The timestampColumn is assigned
+// at last, because MySQL evaluates the assignments from left to right.
+func GenOnDuplicateUpdateIfNewer(columnNames []string, timestampColumn string) string {
+	var buf strings.Builder
+	buf.WriteString(" ON DUPLICATE KEY UPDATE ")
+	tsName := quotes.QuoteName(timestampColumn)
+	cond := "VALUES(" + tsName + ") >= " + tsName
+	writtenFirstCol := false
+	for _, name := range columnNames {
+		if strings.EqualFold(name, timestampColumn) {
+			continue
+		}
+		if writtenFirstCol {
+			buf.WriteByte(',')
+		}
+		writtenFirstCol = true
+		colName := quotes.QuoteName(name)
+		buf.WriteString(colName + "=IF(" + cond + ",VALUES(" + colName + ")," + colName + ")")
+	}
+	if writtenFirstCol {
+		buf.WriteByte(',')
+	}
+	buf.WriteString(tsName + "=IF(" + cond + ",VALUES(" + tsName + ")," + tsName + ")")
+	return buf.String()
+}
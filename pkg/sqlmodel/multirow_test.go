@@ -232,4 +232,13 @@ func TestGenInsertMultiRows(t *testing.T) {
 	sql, args = GenInsertSQL(DMLInsertOnDuplicateUpdate, change1, change2)
 	require.Equal(t, "INSERT INTO `db`.`tb` (`c`,`c2`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `c`=VALUES(`c`),`c2`=VALUES(`c2`)", sql)
 	require.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, args = GenInsertSQL(DMLInsertIgnore, change1, change2)
+	require.Equal(t, "INSERT IGNORE INTO `db`.`tb` (`c`,`c2`) VALUES (?,?),(?,?)", sql)
+	require.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, args = GenInsertIfNewerSQL("c2", change1, change2)
+	require.Equal(t, "INSERT INTO `db`.`tb` (`c`,`c2`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE "+
+		"`c`=IF(VALUES(`c2`) >= `c2`,VALUES(`c`),`c`),`c2`=IF(VALUES(`c2`) >= `c2`,VALUES(`c2`),`c2`)", sql)
+	require.Equal(t, []interface{}{1, 2, 3, 4}, args)
 }
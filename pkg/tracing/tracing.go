@@ -0,0 +1,87 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	serviceName = "ticdc"
+	// instrumentationName is the name of the tracer of the sink pipeline.
+	instrumentationName = "github.com/pingcap/tiflow/cdc/sink"
+)
+
+// Init sets the global tracer provider, which exports the spans to the OTLP
+// collector by the config. The returned function flushes the pending spans
+// and stops the provider. The spans are dropped if the tracing is disabled.
+func Init(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	if cfg == nil || !cfg.Enable {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(
+		otlpgrpc.WithInsecure(),
+		otlpgrpc.WithEndpoint(cfg.Endpoint),
+	))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Info("tracing is enabled",
+		zap.String("endpoint", cfg.Endpoint),
+		zap.Float64("sampleRatio", cfg.SampleRatio))
+	return func(ctx context.Context) error {
+		return errors.Trace(provider.Shutdown(ctx))
+	}, nil
+}
+
+// StartSpan starts a span of the sink pipeline, the span is attached with the
+// changefeed and the given attributes.
+func StartSpan(
+	ctx context.Context, name string, changefeed model.ChangeFeedID, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("namespace", changefeed.Namespace),
+		attribute.String("changefeed", changefeed.ID))
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span, the error is recorded if it's not nil.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitWithTracingDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), &config.TracingConfig{Enable: false})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	// the spans aren't recorded by the default tracer provider.
+	_, span := StartSpan(context.Background(), "test", model.DefaultChangeFeedID("test"))
+	require.False(t, span.IsRecording())
+	EndSpan(span, nil)
+}
+
+func TestStartAndEndSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	defer func() {
+		require.NoError(t, provider.Shutdown(context.Background()))
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	}()
+
+	changefeed := model.DefaultChangeFeedID("test")
+	ctx, parent := StartSpan(context.Background(), "parent", changefeed,
+		attribute.Int("events", 10))
+	_, child := StartSpan(ctx, "child", changefeed)
+	EndSpan(child, errors.New("injected error"))
+	EndSpan(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].StatusCode)
+	require.Equal(t, "injected error", spans[0].StatusMessage)
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+
+	require.Equal(t, "parent", spans[1].Name)
+	require.Equal(t, codes.Unset, spans[1].StatusCode)
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int("events", 10),
+		attribute.String("namespace", changefeed.Namespace),
+		attribute.String("changefeed", changefeed.ID),
+	}, spans[1].Attributes)
+}
@@ -0,0 +1,320 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"unsafe"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// sizeOfEmptyColumn is the size of the column struct.
+const sizeOfEmptyColumn = int(unsafe.Sizeof(model.Column{}))
+
+// rowMessage is the JSON representation of a row passed to and returned by
+// the transformer. The schema and the table are read-only, the changes of
+// them are ignored.
+type rowMessage struct {
+	Schema     string           `json:"schema"`
+	Table      string           `json:"table"`
+	CommitTs   uint64           `json:"commit_ts"`
+	Columns    []*columnMessage `json:"columns,omitempty"`
+	PreColumns []*columnMessage `json:"pre_columns,omitempty"`
+	// Drop and Error are only set by the transformer, the row is dropped if
+	// Drop is true and the changefeed fails if Error isn't empty.
+	Drop  bool   `json:"drop,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// columnMessage is the JSON representation of a column. ID is the position of
+// the column in the row passed to the transformer, it must be kept for the
+// existing columns, even if they're renamed, and be omitted for the new ones.
+// The values of the binary columns are encoded in base64.
+type columnMessage struct {
+	ID    *int        `json:"id,omitempty"`
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Flag  uint64      `json:"flag"`
+	Value interface{} `json:"value"`
+}
+
+// encodeRow encodes the row to the JSON message passed to the transformer.
+func encodeRow(row *model.RowChangedEvent) ([]byte, error) {
+	msg := &rowMessage{
+		Schema:     row.Table.Schema,
+		Table:      row.Table.Table,
+		CommitTs:   row.CommitTs,
+		Columns:    encodeColumns(row.Columns),
+		PreColumns: encodeColumns(row.PreColumns),
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	return data, nil
+}
+
+func encodeColumns(cols []*model.Column) []*columnMessage {
+	if len(cols) == 0 {
+		return nil
+	}
+	msgs := make([]*columnMessage, len(cols))
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		id := i
+		value := col.Value
+		if b, ok := value.([]byte); ok {
+			if col.Flag.IsBinary() {
+				value = base64.StdEncoding.EncodeToString(b)
+			} else {
+				value = string(b)
+			}
+		}
+		msgs[i] = &columnMessage{
+			ID:    &id,
+			Name:  col.Name,
+			Type:  types.TypeStr(col.Type),
+			Flag:  uint64(col.Flag),
+			Value: value,
+		}
+	}
+	return msgs
+}
+
+// decodeRow decodes the JSON message returned by the transformer to the row
+// in place. It returns false if the row is dropped.
+func decodeRow(data []byte, row *model.RowChangedEvent) (bool, error) {
+	msg := &rowMessage{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(msg); err != nil {
+		return false, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	if msg.Error != "" {
+		return false, cerror.ErrTransformerFailed.GenWithStack(
+			"transform the row of table %s: %s", row.Table.String(), msg.Error)
+	}
+	if msg.Drop {
+		return false, nil
+	}
+
+	// The columns and the pre-columns share the column infos and the index
+	// columns, so they must have the same layout if both of them exist.
+	layout := msg.Columns
+	if len(layout) == 0 {
+		layout = msg.PreColumns
+	} else if len(msg.PreColumns) != 0 && !sameLayout(msg.Columns, msg.PreColumns) {
+		return false, cerror.ErrTransformerFailed.GenWithStack(
+			"the columns and the pre-columns of table %s have different layouts",
+			row.Table.String())
+	}
+
+	columns, err := decodeColumns(msg.Columns, row.Columns)
+	if err != nil {
+		return false, err
+	}
+	preColumns, err := decodeColumns(msg.PreColumns, row.PreColumns)
+	if err != nil {
+		return false, err
+	}
+	row.ColInfos = remapColInfos(layout, row.ColInfos)
+	row.IndexColumns = remapIndexColumns(layout, row.IndexColumns)
+	row.Columns = columns
+	row.PreColumns = preColumns
+	return true, nil
+}
+
+func sameLayout(a, b []*columnMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if (a[i] == nil) != (b[i] == nil) {
+			return false
+		}
+		if a[i] == nil {
+			continue
+		}
+		if a[i].Name != b[i].Name || originID(a[i]) != originID(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// originID returns the position of the column in the row passed to the
+// transformer, -1 is returned for the new columns.
+func originID(msg *columnMessage) int {
+	if msg == nil || msg.ID == nil {
+		return -1
+	}
+	return *msg.ID
+}
+
+func decodeColumns(msgs []*columnMessage, origin []*model.Column) ([]*model.Column, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+	cols := make([]*model.Column, len(msgs))
+	for i, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		col := &model.Column{
+			Name: msg.Name,
+			Type: types.StrToType(msg.Type),
+			Flag: model.ColumnFlagType(msg.Flag),
+		}
+		if id := originID(msg); id >= 0 {
+			if id >= len(origin) || origin[id] == nil {
+				return nil, cerror.ErrTransformerFailed.GenWithStack(
+					"column %s refers to an unknown column %d", msg.Name, id)
+			}
+			col.Charset = origin[id].Charset
+			col.Default = origin[id].Default
+		}
+		if col.Type == mysql.TypeUnspecified {
+			return nil, cerror.ErrTransformerFailed.GenWithStack(
+				"column %s has an unknown type %s", msg.Name, msg.Type)
+		}
+		value, err := decodeValue(col, msg.Value)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+		}
+		col.Value = value
+		col.ApproximateBytes = sizeOfEmptyColumn + sizeOfValue(value)
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// decodeValue converts the JSON value to the value of the column type, which
+// is the same as the value of the mounted column.
+func decodeValue(col *model.Column, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case bool:
+		if v {
+			s = "1"
+		} else {
+			s = "0"
+		}
+	default:
+		return nil, cerror.ErrTransformerFailed.GenWithStack(
+			"column %s has an invalid value %v", col.Name, value)
+	}
+
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong,
+		mysql.TypeLonglong, mysql.TypeYear:
+		if col.Flag.IsUnsigned() {
+			return strconv.ParseUint(s, 10, 64)
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case mysql.TypeBit, mysql.TypeEnum, mysql.TypeSet:
+		return strconv.ParseUint(s, 10, 64)
+	case mysql.TypeFloat:
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	case mysql.TypeDouble:
+		return strconv.ParseFloat(s, 64)
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if col.Flag.IsBinary() {
+			return base64.StdEncoding.DecodeString(s)
+		}
+		return []byte(s), nil
+	default:
+		// the decimal, temporal and JSON values are strings.
+		return s, nil
+	}
+}
+
+func sizeOfValue(value interface{}) int {
+	switch v := value.(type) {
+	case []byte:
+		return len(v)
+	case string:
+		return len(v)
+	case nil:
+		return 0
+	default:
+		return 8
+	}
+}
+
+// remapColInfos rebuilds the column infos by the layout of the transformed
+// columns, the new columns get the field types of their column types.
+func remapColInfos(layout []*columnMessage, colInfos []rowcodec.ColInfo) []rowcodec.ColInfo {
+	if len(colInfos) == 0 {
+		return colInfos
+	}
+	result := make([]rowcodec.ColInfo, len(layout))
+	for i, msg := range layout {
+		if id := originID(msg); id >= 0 && id < len(colInfos) {
+			result[i] = colInfos[id]
+			continue
+		}
+		tp := mysql.TypeNull
+		if msg != nil {
+			tp = types.StrToType(msg.Type)
+		}
+		result[i] = rowcodec.ColInfo{ID: -1, Ft: types.NewFieldType(tp)}
+	}
+	return result
+}
+
+// remapIndexColumns rebuilds the index columns by the layout of the
+// transformed columns, the indexes having dropped columns are removed.
+func remapIndexColumns(layout []*columnMessage, indexColumns [][]int) [][]int {
+	if len(indexColumns) == 0 {
+		return indexColumns
+	}
+	positions := make(map[int]int, len(layout))
+	for i, msg := range layout {
+		if id := originID(msg); id >= 0 {
+			positions[id] = i
+		}
+	}
+	result := make([][]int, 0, len(indexColumns))
+	for _, index := range indexColumns {
+		remapped := make([]int, 0, len(index))
+		for _, id := range index {
+			if pos, ok := positions[id]; ok {
+				remapped = append(remapped, pos)
+			}
+		}
+		if len(remapped) == len(index) {
+			result = append(result, remapped)
+		}
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeRow(t *testing.T) {
+	t.Parallel()
+
+	row := newTestRow("t")
+	row.Columns = append(row.Columns,
+		&model.Column{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte{0xff}},
+		nil)
+	data, err := encodeRow(row)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema": "test", "table": "t", "commit_ts": 1,
+		"columns": [
+			{"id": 0, "name": "id", "type": "int", "flag": 2, "value": 1},
+			{"id": 1, "name": "name", "type": "varchar", "flag": 0, "value": "tidb"},
+			{"id": 2, "name": "data", "type": "text", "flag": 1, "value": "/w=="},
+			null
+		]
+	}`, string(data))
+}
+
+func TestDecodeRow(t *testing.T) {
+	t.Parallel()
+
+	newRow := func() *model.RowChangedEvent {
+		row := newTestRow("t")
+		row.PreColumns = newTestRow("t").Columns
+		row.ColInfos = []rowcodec.ColInfo{
+			{ID: 1, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+		}
+		row.IndexColumns = [][]int{{0}, {1}}
+		return row
+	}
+
+	// rename the name column, drop the id column and add a new column.
+	row := newRow()
+	keep, err := decodeRow([]byte(`{
+		"columns": [
+			{"id": 1, "name": "user_name", "type": "varchar", "value": "tikv"},
+			{"name": "level", "type": "bigint", "flag": 128, "value": 18446744073709551615},
+			{"name": "data", "type": "blob", "flag": 1, "value": "/w=="}
+		],
+		"pre_columns": [
+			{"id": 1, "name": "user_name", "type": "varchar", "value": "tidb"},
+			{"name": "level", "type": "bigint", "flag": 128, "value": null},
+			{"name": "data", "type": "blob", "flag": 1, "value": null}
+		]
+	}`), row)
+	require.NoError(t, err)
+	require.True(t, keep)
+	require.Equal(t, []*model.Column{
+		{Name: "user_name", Type: mysql.TypeVarchar, Value: []byte("tikv")},
+		{Name: "level", Type: mysql.TypeLonglong, Flag: model.UnsignedFlag, Value: uint64(18446744073709551615)},
+		{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte{0xff}},
+	}, trimApproximateBytes(row.Columns))
+	require.Nil(t, row.PreColumns[1].Value)
+	require.Len(t, row.ColInfos, 3)
+	require.Equal(t, int64(2), row.ColInfos[0].ID)
+	require.Equal(t, mysql.TypeLonglong, row.ColInfos[1].Ft.GetType())
+	require.Equal(t, [][]int{{0}}, row.IndexColumns)
+
+	keep, err = decodeRow([]byte(`{"drop": true}`), newRow())
+	require.NoError(t, err)
+	require.False(t, keep)
+
+	_, err = decodeRow([]byte(`{"error": "bad row"}`), newRow())
+	require.ErrorContains(t, err, "bad row")
+
+	_, err = decodeRow([]byte(`{
+		"columns": [{"id": 0, "name": "id", "type": "int", "value": 1}],
+		"pre_columns": [{"id": 1, "name": "name", "type": "varchar", "value": "tidb"}]
+	}`), newRow())
+	require.ErrorContains(t, err, "different layouts")
+
+	_, err = decodeRow([]byte(`{
+		"columns": [{"id": 5, "name": "id", "type": "int", "value": 1}]
+	}`), newRow())
+	require.ErrorContains(t, err, "unknown column")
+
+	_, err = decodeRow([]byte(`{
+		"columns": [{"id": 0, "name": "id", "type": "int", "value": "one"}]
+	}`), newRow())
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"context"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+// Transformer modifies or drops the row changed events after they are mounted
+// and before they are written to the sink and the redo log. The
+// implementations must be safe for concurrent use, since the rows are mounted
+// by multiple workers.
+type Transformer interface {
+	// Transform modifies the row in place, false is returned if the row is
+	// dropped.
+	Transform(ctx context.Context, row *model.RowChangedEvent) (bool, error)
+	// Close releases the resources of the transformer.
+	Close() error
+}
+
+// New creates a Transformer by the transformer in the sink config, nil is
+// returned if there is no transformer configured.
+func New(ctx context.Context, cfg *config.ReplicaConfig) (Transformer, error) {
+	if cfg.Sink == nil || cfg.Sink.Transformer == nil {
+		return nil, nil
+	}
+	return newWASMTransformer(ctx, cfg.Sink.Transformer, cfg.CaseSensitive)
+}
+
+// ResolveChecksum sets the checksum of the WASM module of the transformer if
+// it's not set, or checks the module against it otherwise. It's called when
+// the changefeed is created or updated, and the module is read from the
+// server handling the request.
+func ResolveChecksum(cfg *config.ReplicaConfig) error {
+	if cfg.Sink == nil || cfg.Sink.Transformer == nil {
+		return nil
+	}
+	binary, err := readWASMModule(cfg.Sink.Transformer)
+	if err != nil {
+		return err
+	}
+	cfg.Sink.Transformer.WASMChecksum = wasmChecksum(binary)
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync"
+
+	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"go.uber.org/zap"
+)
+
+// The functions and the memory exported by the WASM module. The module
+// receives the JSON encoded row by
+//
+//	ptr := alloc(len)                // allocates len bytes in the memory
+//	result := transform(ptr, len)    // transforms the row at [ptr, ptr+len)
+//
+// The result packs the pointer and the length of the transformed row as
+// (ptr << 32) | len, and the row is dropped if the length is 0. The optional
+// free(ptr, len) is called to release the input and the output.
+const (
+	wasmMemory    = "memory"
+	wasmAlloc     = "alloc"
+	wasmFree      = "free"
+	wasmTransform = "transform"
+	// wasmInitialize is the start function of the WASI reactor modules.
+	wasmInitialize = "_initialize"
+)
+
+// wasmTransformer transforms the rows by a WASM module. The module can't
+// access the file system, the network or the environment of the TiCDC
+// servers, and an instance of it only handles one row at a time, so the
+// instances are pooled for the mounter workers.
+type wasmTransformer struct {
+	// tableMatcher is nil if the rows of all tables are transformed.
+	tableMatcher tfilter.Filter
+	runtime      wazero.Runtime
+	compiled     wazero.CompiledModule
+	hasFree      bool
+
+	mu        sync.Mutex
+	instances []api.Module
+}
+
+func newWASMTransformer(
+	ctx context.Context, cfg *config.TransformerConfig, caseSensitive bool,
+) (*wasmTransformer, error) {
+	t := &wasmTransformer{}
+	if len(cfg.Matcher) > 0 {
+		tf, err := tfilter.Parse(cfg.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, cfg.Matcher)
+		}
+		if !caseSensitive {
+			tf = tfilter.CaseInsensitive(tf)
+		}
+		t.tableMatcher = tf
+	}
+
+	binary, err := readWASMModule(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// the running functions are stopped once the changefeed is stopped.
+	t.runtime = wazero.NewRuntimeWithConfig(ctx,
+		wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, t.runtime); err != nil {
+		t.closeRuntime()
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	t.compiled, err = t.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		t.closeRuntime()
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+
+	functions := t.compiled.ExportedFunctions()
+	for _, name := range []string{wasmAlloc, wasmTransform} {
+		if _, ok := functions[name]; !ok {
+			t.closeRuntime()
+			return nil, cerror.ErrTransformerFailed.GenWithStack(
+				"the wasm module %s doesn't export the %s function", cfg.WASMFile, name)
+		}
+	}
+	if _, ok := t.compiled.ExportedMemories()[wasmMemory]; !ok {
+		t.closeRuntime()
+		return nil, cerror.ErrTransformerFailed.GenWithStack(
+			"the wasm module %s doesn't export the memory", cfg.WASMFile)
+	}
+	_, t.hasFree = functions[wasmFree]
+
+	// instantiate a module eagerly to report the errors of the start function
+	// when the changefeed is created.
+	mod, err := t.instantiate(ctx)
+	if err != nil {
+		t.closeRuntime()
+		return nil, err
+	}
+	t.release(mod)
+	return t, nil
+}
+
+// readWASMModule reads the WASM module of the transformer from the local file
+// system. The module must match the checksum if it's set, so that all the
+// servers run the same module for the changefeed.
+func readWASMModule(cfg *config.TransformerConfig) ([]byte, error) {
+	binary, err := os.ReadFile(cfg.WASMFile)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	if cfg.WASMChecksum != "" {
+		if checksum := wasmChecksum(binary); checksum != cfg.WASMChecksum {
+			return nil, cerror.ErrTransformerFailed.GenWithStack(
+				"the sha256 %s of the wasm module %s doesn't match the wasm-sha256 %s of the changefeed",
+				checksum, cfg.WASMFile, cfg.WASMChecksum)
+		}
+	}
+	return binary, nil
+}
+
+func wasmChecksum(binary []byte) string {
+	sum := sha256.Sum256(binary)
+	return hex.EncodeToString(sum[:])
+}
+
+// Transform implements Transformer.
+func (t *wasmTransformer) Transform(ctx context.Context, row *model.RowChangedEvent) (bool, error) {
+	if t.tableMatcher != nil && !t.tableMatcher.MatchTable(row.Table.Schema, row.Table.Table) {
+		return true, nil
+	}
+	input, err := encodeRow(row)
+	if err != nil {
+		return false, err
+	}
+
+	mod, err := t.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	output, err := t.call(ctx, mod, input)
+	if err != nil {
+		// the instance may be in a broken state after a failed call.
+		_ = mod.Close(ctx)
+		return false, err
+	}
+	t.release(mod)
+
+	if output == nil {
+		return false, nil
+	}
+	return decodeRow(output, row)
+}
+
+// call passes the input to the transform function of the module and returns
+// a copy of the output, nil is returned if the row is dropped.
+func (t *wasmTransformer) call(ctx context.Context, mod api.Module, input []byte) ([]byte, error) {
+	results, err := mod.ExportedFunction(wasmAlloc).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	inputPtr := uint32(results[0])
+	if !mod.Memory().Write(inputPtr, input) {
+		return nil, cerror.ErrTransformerFailed.GenWithStack(
+			"the input at %d is out of the memory", inputPtr)
+	}
+
+	results, err = mod.ExportedFunction(wasmTransform).Call(
+		ctx, uint64(inputPtr), uint64(len(input)))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+	var output []byte
+	if outputLen > 0 {
+		buf, ok := mod.Memory().Read(outputPtr, outputLen)
+		if !ok {
+			return nil, cerror.ErrTransformerFailed.GenWithStack(
+				"the output at %d with %d bytes is out of the memory", outputPtr, outputLen)
+		}
+		// buf is a view of the memory, which is reused by the next call.
+		output = append([]byte(nil), buf...)
+	}
+
+	if t.hasFree {
+		free := mod.ExportedFunction(wasmFree)
+		if _, err := free.Call(ctx, uint64(inputPtr), uint64(len(input))); err != nil {
+			return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+		}
+		if outputLen > 0 && outputPtr != inputPtr {
+			if _, err := free.Call(ctx, uint64(outputPtr), uint64(outputLen)); err != nil {
+				return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+			}
+		}
+	}
+	return output, nil
+}
+
+func (t *wasmTransformer) instantiate(ctx context.Context) (api.Module, error) {
+	// the instances are anonymous, so that there can be many of them.
+	mod, err := t.runtime.InstantiateModule(ctx, t.compiled,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions(wasmInitialize))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	return mod, nil
+}
+
+func (t *wasmTransformer) acquire(ctx context.Context) (api.Module, error) {
+	t.mu.Lock()
+	if n := len(t.instances); n > 0 {
+		mod := t.instances[n-1]
+		t.instances = t.instances[:n-1]
+		t.mu.Unlock()
+		return mod, nil
+	}
+	t.mu.Unlock()
+	return t.instantiate(ctx)
+}
+
+func (t *wasmTransformer) release(mod api.Module) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.instances = append(t.instances, mod)
+}
+
+// Close implements Transformer.
+func (t *wasmTransformer) Close() error {
+	t.mu.Lock()
+	t.instances = nil
+	t.mu.Unlock()
+	return t.closeRuntime()
+}
+
+// closeRuntime closes the runtime and all the module instances of it.
+func (t *wasmTransformer) closeRuntime() error {
+	if err := t.runtime.Close(context.Background()); err != nil {
+		log.Warn("failed to close the wasm runtime", zap.Error(err))
+		return cerror.WrapError(cerror.ErrTransformerFailed, err)
+	}
+	return nil
+}
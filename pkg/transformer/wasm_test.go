@@ -0,0 +1,209 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transformer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// identityBody returns the input as the output, (ptr << 32) | len.
+	identityBody = []byte{
+		0x00,       // no locals
+		0x20, 0x00, // local.get 0
+		0xad,       // i64.extend_i32_u
+		0x42, 0x20, // i64.const 32
+		0x86,       // i64.shl
+		0x20, 0x01, // local.get 1
+		0xad, // i64.extend_i32_u
+		0x84, // i64.or
+		0x0b, // end
+	}
+	// dropBody drops all rows by returning 0.
+	dropBody = []byte{
+		0x00,       // no locals
+		0x42, 0x00, // i64.const 0
+		0x0b, // end
+	}
+)
+
+// wasmModule builds a module exporting the memory, an alloc function which
+// bumps a global pointer, and the transform function of the body.
+func wasmModule(transformBody []byte) []byte {
+	allocBody := []byte{
+		0x00,       // no locals
+		0x23, 0x00, // global.get 0
+		0x23, 0x00, // global.get 0
+		0x20, 0x00, // local.get 0
+		0x6a,       // i32.add
+		0x24, 0x00, // global.set 0
+		0x0b, // end
+	}
+	code := []byte{0x02, byte(len(allocBody))}
+	code = append(code, allocBody...)
+	code = append(code, byte(len(transformBody)))
+	code = append(code, transformBody...)
+
+	module := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // magic and version
+		// types: (i32) -> i32, (i32, i32) -> i64
+		0x01, 0x0c, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e,
+		// functions: alloc, transform
+		0x03, 0x03, 0x02, 0x00, 0x01,
+		// memory: 1 page
+		0x05, 0x03, 0x01, 0x00, 0x01,
+		// globals: mutable i32 = 1024
+		0x06, 0x07, 0x01, 0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b,
+		// exports: memory, alloc, transform
+		0x07, 0x1e, 0x03,
+		0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+		0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+		0x09, 't', 'r', 'a', 'n', 's', 'f', 'o', 'r', 'm', 0x00, 0x01,
+	}
+	module = append(module, 0x0a, byte(len(code)))
+	return append(module, code...)
+}
+
+func newTestReplicaConfig(t *testing.T, module []byte, matcher []string) *config.ReplicaConfig {
+	file := filepath.Join(t.TempDir(), "transformer.wasm")
+	require.NoError(t, os.WriteFile(file, module, 0o600))
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.Transformer = &config.TransformerConfig{
+		Matcher:  matcher,
+		WASMFile: file,
+	}
+	return cfg
+}
+
+func newTestRow(table string) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "test", Table: table},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("tidb")},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	tr, err := New(ctx, cfg)
+	require.NoError(t, err)
+	require.Nil(t, tr)
+
+	tr, err = New(ctx, newTestReplicaConfig(t, wasmModule(identityBody), nil))
+	require.NoError(t, err)
+	require.NotNil(t, tr)
+	require.NoError(t, tr.Close())
+
+	// the module must export the functions.
+	_, err = New(ctx, newTestReplicaConfig(t, []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	}, nil))
+	require.ErrorContains(t, err, "doesn't export the alloc function")
+
+	_, err = New(ctx, newTestReplicaConfig(t, []byte("not a wasm module"), nil))
+	require.Error(t, err)
+}
+
+func TestResolveChecksum(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	require.NoError(t, ResolveChecksum(config.GetDefaultReplicaConfig()))
+
+	cfg := newTestReplicaConfig(t, wasmModule(identityBody), nil)
+	require.NoError(t, ResolveChecksum(cfg))
+	checksum := cfg.Sink.Transformer.WASMChecksum
+	require.Len(t, checksum, 64)
+	tr, err := New(ctx, cfg)
+	require.NoError(t, err)
+	require.NoError(t, tr.Close())
+
+	// another module is refused by the checksum.
+	require.NoError(t, os.WriteFile(cfg.Sink.Transformer.WASMFile, wasmModule(dropBody), 0o600))
+	_, err = New(ctx, cfg)
+	require.ErrorContains(t, err, "doesn't match the wasm-sha256")
+	require.ErrorContains(t, ResolveChecksum(cfg), "doesn't match the wasm-sha256")
+	require.Equal(t, checksum, cfg.Sink.Transformer.WASMChecksum)
+
+	// the module is missing.
+	cfg.Sink.Transformer.WASMFile = filepath.Join(t.TempDir(), "missing.wasm")
+	_, err = New(ctx, cfg)
+	require.ErrorContains(t, err, "ErrTransformerFailed")
+}
+
+func TestWASMTransform(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tr, err := New(ctx, newTestReplicaConfig(t, wasmModule(identityBody), nil))
+	require.NoError(t, err)
+	defer tr.Close()
+
+	// the rows are transformed concurrently by the mounter workers.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			row := newTestRow("t")
+			keep, err := tr.Transform(ctx, row)
+			require.NoError(t, err)
+			require.True(t, keep)
+			require.Equal(t, newTestRow("t").Columns, trimApproximateBytes(row.Columns))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWASMTransformDrop(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tr, err := New(ctx, newTestReplicaConfig(t, wasmModule(dropBody), []string{"test.t1"}))
+	require.NoError(t, err)
+	defer tr.Close()
+
+	keep, err := tr.Transform(ctx, newTestRow("t1"))
+	require.NoError(t, err)
+	require.False(t, keep)
+
+	// the rows of the unmatched tables are kept as they're.
+	row := newTestRow("t2")
+	keep, err = tr.Transform(ctx, row)
+	require.NoError(t, err)
+	require.True(t, keep)
+	require.Equal(t, newTestRow("t2"), row)
+}
+
+func trimApproximateBytes(cols []*model.Column) []*model.Column {
+	for _, col := range cols {
+		col.ApproximateBytes = 0
+	}
+	return cols
+}
@@ -33,6 +33,8 @@ const (
 	EnsureGCServiceResuming = "-resuming-"
 	// EnsureGCServiceInitializing is a tag of GC service id for changefeed initialization
 	EnsureGCServiceInitializing = "-initializing-"
+	// EnsureGCServiceSavepoint is a tag of GC service id for savepoints
+	EnsureGCServiceSavepoint = "-savepoint-"
 )
 
 // EnsureChangefeedStartTsSafety checks if the startTs less than the minimum of
@@ -77,6 +79,37 @@ func UndoEnsureChangefeedStartTsSafety(
 	return nil
 }
 
+// EnsureSavepointSafety holds the service GC safepoint of a savepoint at
+// (checkpointTs - 1) for TTL seconds, since the changefeeds created from the
+// savepoint do a snapshot read at it. It fails if the data at checkpointTs has
+// been GCed.
+func EnsureSavepointSafety(
+	ctx context.Context, pdCli pd.Client,
+	gcServiceIDPrefix string,
+	namespace, name string,
+	TTL int64, checkpointTs uint64,
+) error {
+	gcSafepoint := checkpointTs - 1
+	minServiceGCTs, err := SetServiceGCSafepoint(
+		ctx, pdCli, gcServiceIDPrefix+namespace+"_"+name, TTL, gcSafepoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if gcSafepoint < minServiceGCTs {
+		return cerrors.ErrSnapshotLostByGC.GenWithStackByArgs(checkpointTs, minServiceGCTs)
+	}
+	return nil
+}
+
+// RemoveSavepointSafety removes the service GC safepoint of a savepoint.
+func RemoveSavepointSafety(
+	ctx context.Context, pdCli pd.Client,
+	gcServiceIDPrefix string,
+	namespace, name string,
+) error {
+	return RemoveServiceGCSafepoint(ctx, pdCli, gcServiceIDPrefix+namespace+"_"+name)
+}
+
 // PD leader switch may happen, so just gcServiceMaxRetries it.
 // The default PD election timeout is 3 seconds. Triple the timeout as
 // retry time to make sure PD leader can be elected during retry.
@@ -91,6 +91,29 @@ func TestCheckSafetyOfStartTs(t *testing.T) {
 			"because start-ts 50 is earlier than or equal to GC safepoint at 60")
 }
 
+func TestSavepointSafety(t *testing.T) {
+	t.Parallel()
+
+	pdCli := &mockPdClientForServiceGCSafePoint{serviceSafePoint: make(map[string]uint64)}
+	ctx := context.Background()
+
+	pdCli.UpdateServiceGCSafePoint(ctx, "service1", 10, 60) //nolint:errcheck
+	err := EnsureSavepointSafety(ctx, pdCli,
+		"ticdc-savepoint-", model.DefaultNamespace, "savepoint1", 10, 60)
+	require.Regexp(t, "ErrSnapshotLostByGC", err)
+
+	err = EnsureSavepointSafety(ctx, pdCli,
+		"ticdc-savepoint-", model.DefaultNamespace, "savepoint2", 10, 61)
+	require.Nil(t, err)
+	require.Equal(t, uint64(60), pdCli.serviceSafePoint["ticdc-savepoint-default_savepoint2"])
+
+	err = RemoveSavepointSafety(ctx, pdCli,
+		"ticdc-savepoint-", model.DefaultNamespace, "savepoint2")
+	require.Nil(t, err)
+	require.Equal(t, uint64(math.MaxUint64),
+		pdCli.serviceSafePoint["ticdc-savepoint-default_savepoint2"])
+}
+
 type mockPdClientForServiceGCSafePoint struct {
 	pd.Client
 	serviceSafePoint   map[string]uint64
@@ -27,6 +27,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/pkg/errors"
@@ -62,15 +63,39 @@ func GetExternalStorage(
 	opts *storage.BackendOptions,
 	retryer request.Retryer,
 ) (storage.ExternalStorage, error) {
-	backEnd, err := storage.ParseBackend(uri, opts)
+	uri, auth, err := extractCloudAuth(uri)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-
-	ret, err := storage.New(ctx, backEnd, &storage.ExternalStorageOptions{
-		SendCredentials: false,
-		S3Retryer:       retryer,
-	})
+	var ret storage.ExternalStorage
+	// WebHDFS, OSS and COS aren't supported by BR.
+	switch {
+	case isWebHDFSURI(uri):
+		ret, err = newWebHDFSStorage(uri)
+	case isObjectStorageURI(uri):
+		ret, err = newObjectStorage(uri)
+	default:
+		var backEnd *backuppb.StorageBackend
+		backEnd, err = storage.ParseBackend(uri, opts)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if auth.sasToken != "" {
+			ret, err = newAzblobSASStorage(backEnd.GetAzureBlobStorage(), auth.sasToken)
+			break
+		}
+		storageOpts := &storage.ExternalStorageOptions{
+			SendCredentials: false,
+			S3Retryer:       retryer,
+		}
+		if auth.impersonateServiceAccount != "" {
+			storageOpts.HTTPClient, err = newGCSImpersonatedClient(ctx, auth.impersonateServiceAccount)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ret, err = storage.New(ctx, backEnd, storageOpts)
+	}
 	if err != nil {
 		retErr := errors.ErrFailToCreateExternalStorage.Wrap(errors.Trace(err))
 		return nil, retErr.GenWithStackByArgs("creating ExternalStorage for s3")
@@ -209,6 +234,22 @@ func (s *extStorageWithTimeout) Rename(
 	return s.ExternalStorage.Rename(ctx, oldFileName, newFileName)
 }
 
+// ProbeExternalStorage writes an empty probe file with the given name and
+// deletes it, to check whether the storage is writable. The failure of the
+// deletion is only logged, since the storage has been proven writable.
+func ProbeExternalStorage(ctx context.Context, storage storage.ExternalStorage, name string) error {
+	if err := storage.WriteFile(ctx, name, []byte{}); err != nil {
+		return errors.Trace(err)
+	}
+	if err := storage.DeleteFile(ctx, name); err != nil {
+		log.Warn("delete the probe file failed",
+			zap.String("storage", storage.URI()),
+			zap.String("filename", name),
+			zap.Error(err))
+	}
+	return nil
+}
+
 // IsNotExistInExtStorage checks if the error is caused by the file not exist in external storage.
 func IsNotExistInExtStorage(err error) bool {
 	if err == nil {
@@ -0,0 +1,303 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	gcsStorage "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security/secret"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+)
+
+const (
+	// gcsImpersonateServiceAccountKey is the parameter of the gcs storage URI,
+	// the storage is accessed as the service account, which is impersonated
+	// by the default credentials, such as the GKE workload identity.
+	gcsImpersonateServiceAccountKey = "impersonate-service-account"
+	// azblobSASTokenKey is the parameter of the azure blob storage URI, the
+	// storage is accessed by the SAS token. It can be a secret reference, the
+	// token is resolved again by every request, so the rotated token is used.
+	azblobSASTokenKey = "sas-token"
+)
+
+// cloudAuth is the authentications of the cloud storages which aren't
+// supported by BR, their parameters are removed from the storage URI.
+type cloudAuth struct {
+	impersonateServiceAccount string
+	sasToken                  string
+}
+
+// extractCloudAuth removes the parameters of the cloud authentications from
+// the storage URI.
+func extractCloudAuth(uri string) (string, *cloudAuth, error) {
+	auth := &cloudAuth{}
+	if !strings.Contains(uri, gcsImpersonateServiceAccountKey) &&
+		!strings.Contains(uri, azblobSASTokenKey) {
+		return uri, auth, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+	query := u.Query()
+	auth.impersonateServiceAccount = query.Get(gcsImpersonateServiceAccountKey)
+	auth.sasToken = query.Get(azblobSASTokenKey)
+	query.Del(gcsImpersonateServiceAccountKey)
+	query.Del(azblobSASTokenKey)
+	u.RawQuery = query.Encode()
+
+	scheme := strings.ToLower(u.Scheme)
+	if auth.impersonateServiceAccount != "" && scheme != "gcs" && scheme != "gs" {
+		return "", nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"%s is only supported by the gcs storage, but the scheme is %s",
+			gcsImpersonateServiceAccountKey, scheme)
+	}
+	if auth.sasToken != "" && scheme != "azure" && scheme != "azblob" {
+		return "", nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"%s is only supported by the azure blob storage, but the scheme is %s",
+			azblobSASTokenKey, scheme)
+	}
+	return u.String(), auth, nil
+}
+
+// newGCSImpersonatedClient returns the http client which authenticates the
+// requests as the service account. The access tokens are refreshed before
+// they expire.
+func newGCSImpersonatedClient(
+	ctx context.Context, serviceAccount string,
+) (*http.Client, error) {
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: serviceAccount,
+		Scopes:          []string{gcsStorage.ScopeReadWrite},
+	})
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+	return oauth2.NewClient(context.Background(), ts), nil
+}
+
+// sasTokenPolicy sets the SAS token to the query of every request.
+type sasTokenPolicy struct {
+	token string
+}
+
+func (p *sasTokenPolicy) Do(req *policy.Request) (*http.Response, error) {
+	token, err := secret.Resolve(p.token)
+	if err != nil {
+		return nil, err
+	}
+	sas, err := url.ParseQuery(strings.TrimPrefix(token, "?"))
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	raw := req.Raw()
+	query := raw.URL.Query()
+	for key, values := range sas {
+		query[key] = values
+	}
+	raw.URL.RawQuery = query.Encode()
+	return req.Next()
+}
+
+// azblobSASStorage is the azure blob storage which is accessed by the SAS token.
+type azblobSASStorage struct {
+	options         *backuppb.AzureBlobStorage
+	containerClient azblob.ContainerClient
+}
+
+func newAzblobSASStorage(
+	options *backuppb.AzureBlobStorage, sasToken string,
+) (*azblobSASStorage, error) {
+	if options == nil {
+		return nil, errors.ErrFailToCreateExternalStorage.GenWithStack("azure blob config not found")
+	}
+	endpoint := strings.TrimSuffix(options.Endpoint, "/")
+	if endpoint == "" {
+		accountName := options.AccountName
+		if accountName == "" {
+			accountName = os.Getenv("AZURE_STORAGE_ACCOUNT")
+		}
+		if accountName == "" {
+			return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+				"the account name of the azure blob storage is empty")
+		}
+		endpoint = "https://" + accountName + ".blob.core.windows.net"
+	}
+	client, err := azblob.NewContainerClientWithNoCredential(
+		endpoint+"/"+options.Bucket, &azblob.ClientOptions{
+			PerCallOptions: []policy.Policy{&sasTokenPolicy{token: sasToken}},
+		})
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+	return &azblobSASStorage{options: options, containerClient: client}, nil
+}
+
+func (s *azblobSASStorage) withPrefix(name string) string {
+	return path.Join(s.options.Prefix, name)
+}
+
+// WriteFile writes a complete file to storage.
+func (s *azblobSASStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	client := s.containerClient.NewBlockBlobClient(s.withPrefix(name))
+	resp, err := client.UploadBufferToBlockBlob(ctx, data, azblob.HighLevelUploadToBlockBlobOption{})
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// ReadFile reads a complete file from storage.
+func (s *azblobSASStorage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	client := s.containerClient.NewBlockBlobClient(s.withPrefix(name))
+	resp, err := client.Download(ctx, nil)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	defer resp.RawResponse.Body.Close()
+	data, err := io.ReadAll(resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3}))
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return data, nil
+}
+
+// FileExists return true if file exists.
+func (s *azblobSASStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	client := s.containerClient.NewBlockBlobClient(s.withPrefix(name))
+	_, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		if IsNotExistInExtStorage(err) {
+			return false, nil
+		}
+		return false, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return true, nil
+}
+
+// DeleteFile delete the file in storage.
+func (s *azblobSASStorage) DeleteFile(ctx context.Context, name string) error {
+	client := s.containerClient.NewBlockBlobClient(s.withPrefix(name))
+	if _, err := client.Delete(ctx, nil); err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return nil
+}
+
+// Open a Reader by file path, the whole file is read into the memory.
+func (s *azblobSASStorage) Open(ctx context.Context, name string) (storage.ExternalFileReader, error) {
+	data, err := s.ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &bytesFileReader{Reader: bytes.NewReader(data)}, nil
+}
+
+// WalkDir traverse all the files in a dir.
+func (s *azblobSASStorage) WalkDir(
+	ctx context.Context, opt *storage.WalkOption, fn func(path string, size int64) error,
+) error {
+	if opt == nil {
+		opt = &storage.WalkOption{}
+	}
+	prefix := path.Join(s.options.Prefix, opt.SubDir)
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	prefix += opt.ObjPrefix
+
+	listOption := &azblob.ContainerListBlobFlatSegmentOptions{Prefix: &prefix}
+	for {
+		pager := s.containerClient.ListBlobsFlat(listOption)
+		if !pager.NextPage(ctx) {
+			if err := pager.Err(); err != nil {
+				return errors.WrapError(errors.ErrExternalStorageAPI, err)
+			}
+			return nil
+		}
+		page := pager.PageResponse()
+		for _, blob := range page.Segment.BlobItems {
+			name := strings.TrimPrefix(*blob.Name, s.options.Prefix)
+			if err := fn(strings.TrimPrefix(name, "/"), *blob.Properties.ContentLength); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if page.NextMarker == nil || *page.NextMarker == "" {
+			return nil
+		}
+		listOption.Marker = page.NextMarker
+	}
+}
+
+// URI returns the base path as a URI, the SAS token isn't included.
+func (s *azblobSASStorage) URI() string {
+	return "azure://" + s.options.Bucket + "/" + s.options.Prefix
+}
+
+// Create opens a file writer by path, the file is written when it's closed.
+func (s *azblobSASStorage) Create(_ context.Context, name string) (storage.ExternalFileWriter, error) {
+	return &bufferedFileWriter{storage: s, name: name}, nil
+}
+
+// Rename file name from oldFileName to newFileName.
+func (s *azblobSASStorage) Rename(ctx context.Context, oldFileName, newFileName string) error {
+	data, err := s.ReadFile(ctx, oldFileName)
+	if err != nil {
+		return err
+	}
+	if err := s.WriteFile(ctx, newFileName, data); err != nil {
+		return err
+	}
+	return s.DeleteFile(ctx, oldFileName)
+}
+
+type bytesFileReader struct {
+	*bytes.Reader
+}
+
+// Close implements the io.Closer interface.
+func (*bytesFileReader) Close() error {
+	return nil
+}
+
+type bufferedFileWriter struct {
+	storage storage.ExternalStorage
+	name    string
+	buf     bytes.Buffer
+}
+
+// Write writes to the buffer.
+func (w *bufferedFileWriter) Write(_ context.Context, p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Close writes the buffered data as a whole file.
+func (w *bufferedFileWriter) Close(ctx context.Context) error {
+	return w.storage.WriteFile(ctx, w.name, w.buf.Bytes())
+}
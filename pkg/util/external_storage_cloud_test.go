@@ -0,0 +1,124 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCloudAuth(t *testing.T) {
+	t.Parallel()
+
+	uri, auth, err := extractCloudAuth("s3://bucket/prefix?region=us-west-2")
+	require.NoError(t, err)
+	require.Equal(t, "s3://bucket/prefix?region=us-west-2", uri)
+	require.Equal(t, &cloudAuth{}, auth)
+
+	uri, auth, err = extractCloudAuth(
+		"gcs://bucket/prefix?impersonate-service-account=cdc@project.iam.gserviceaccount.com")
+	require.NoError(t, err)
+	require.Equal(t, "gcs://bucket/prefix", uri)
+	require.Equal(t, "cdc@project.iam.gserviceaccount.com", auth.impersonateServiceAccount)
+
+	uri, auth, err = extractCloudAuth(
+		"azure://container/prefix?account-name=cdc&sas-token=sv%3D2021-08-06%26sig%3Dabc")
+	require.NoError(t, err)
+	require.Equal(t, "azure://container/prefix?account-name=cdc", uri)
+	require.Equal(t, "sv=2021-08-06&sig=abc", auth.sasToken)
+
+	_, _, err = extractCloudAuth("s3://bucket/prefix?impersonate-service-account=cdc")
+	require.Regexp(t, ".*only supported by the gcs storage.*", err)
+	_, _, err = extractCloudAuth("gcs://bucket/prefix?sas-token=sig%3Dabc")
+	require.Regexp(t, ".*only supported by the azure blob storage.*", err)
+}
+
+// fakeBlobServer stores the blobs in the memory, it only accepts the requests
+// which carry the expected signature.
+type fakeBlobServer struct {
+	mu        sync.Mutex
+	signature string
+	blobs     map[string][]byte
+}
+
+func (s *fakeBlobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Query().Get("sig") != s.signature {
+		w.Header().Set("x-ms-error-code", "AuthenticationFailed")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.blobs[r.URL.Path] = data
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead:
+		if _, ok := s.blobs[r.URL.Path]; !ok {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(s.blobs, r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestAzblobSASStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := &fakeBlobServer{signature: "abc", blobs: make(map[string][]byte)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "sas-token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sv=2021-08-06&sig=abc\n"), 0o600))
+	uri := "azure://container/prefix?endpoint=" + ts.URL +
+		"&sas-token=${secret:file:" + tokenFile + "}"
+
+	s, err := GetExternalStorageFromURI(ctx, uri)
+	require.NoError(t, err)
+	require.Equal(t, "azure://container/prefix", s.URI())
+
+	require.NoError(t, s.WriteFile(ctx, "a.json", []byte("value")))
+	require.Equal(t, []byte("value"), server.blobs["/container/prefix/a.json"])
+	exists, err := s.FileExists(ctx, "a.json")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.NoError(t, s.DeleteFile(ctx, "a.json"))
+	exists, err = s.FileExists(ctx, "a.json")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// the requests are rejected without the valid signature.
+	_, err = GetExternalStorageFromURI(ctx,
+		"azure://container/prefix?endpoint="+ts.URL+"&sas-token=sig%3Dinvalid")
+	require.Regexp(t, ".*AuthenticationFailed.*", err)
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// cosSignatureExpiry is how long the signature of a request is valid.
+	cosSignatureExpiry = time.Hour
+)
+
+// cosMetadataEndpoint is the endpoint of the temporary access keys of the CAM
+// roles attached to the CVM instances.
+var cosMetadataEndpoint = "http://metadata.tencentyun.com/latest/meta-data/cam/security-credentials/"
+
+var cosFlavor = &objectStorageFlavor{
+	name:         "COS",
+	headerPrefix: "x-cos-",
+	accessKeyEnvs: [3]string{
+		"TENCENTCLOUD_SECRET_ID",
+		"TENCENTCLOUD_SECRET_KEY",
+		"TENCENTCLOUD_SESSION_TOKEN",
+	},
+	regionEndpoint: func(region string) string {
+		return "https://cos." + region + ".myqcloud.com"
+	},
+	fetchRoleCredentials: fetchCOSRoleCredentials,
+	sign:                 signCOSRequest,
+	presign:              presignCOSRequest,
+	copySource: func(s *objectStorage, key string) string {
+		return s.endpoint.Host + "/" + url.PathEscape(key)
+	},
+}
+
+func fetchCOSRoleCredentials(
+	ctx context.Context, client *http.Client, role string,
+) (*objectCredentials, error) {
+	var resp struct {
+		Code         string `json:"Code"`
+		TmpSecretID  string `json:"TmpSecretId"`
+		TmpSecretKey string `json:"TmpSecretKey"`
+		Token        string `json:"Token"`
+		ExpiredTime  int64  `json:"ExpiredTime"`
+	}
+	if err := fetchRoleCredentials(ctx, client, cosMetadataEndpoint+role, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "Success" {
+		return nil, errors.ErrExternalStorageAPI.GenWithStack(
+			"fetch the temporary access key of the CAM role %s failed: %s", role, resp.Code)
+	}
+	return &objectCredentials{
+		accessKeyID:     resp.TmpSecretID,
+		accessKeySecret: resp.TmpSecretKey,
+		securityToken:   resp.Token,
+		expiration:      time.Unix(resp.ExpiredTime, 0),
+	}, nil
+}
+
+// cosEscape escapes the string like encodeURIComponent, as required by the
+// signature of COS.
+func cosEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// cosFormatPairs returns the sorted names and the formatted pairs of the
+// parameters or the headers signed by COS.
+func cosFormatPairs(pairs map[string]string) (string, string) {
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		formatted = append(formatted, name+"="+cosEscape(pairs[name]))
+	}
+	return strings.Join(names, ";"), strings.Join(formatted, "&")
+}
+
+func hmacSHA1Hex(key, data string) string {
+	mac := hmac.New(sha1.New, []byte(key))
+	_, _ = mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// cosSignature returns the signed headers, the signed parameters and the
+// signature of the request, the host, the date and the headers of COS are signed.
+func cosSignature(req *http.Request, cred *objectCredentials, keyTime string) (string, string, string) {
+	params := make(map[string]string)
+	for name, values := range req.URL.Query() {
+		params[strings.ToLower(cosEscape(name))] = values[0]
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "date" || strings.HasPrefix(name, "x-cos-") {
+			headers[cosEscape(name)] = values[0]
+		}
+	}
+	paramList, formattedParams := cosFormatPairs(params)
+	headerList, formattedHeaders := cosFormatPairs(headers)
+
+	httpString := strings.ToLower(req.Method) + "\n" + req.URL.Path + "\n" +
+		formattedParams + "\n" + formattedHeaders + "\n"
+	digest := sha1.Sum([]byte(httpString))
+	stringToSign := "sha1\n" + keyTime + "\n" + hex.EncodeToString(digest[:]) + "\n"
+	signature := hmacSHA1Hex(hmacSHA1Hex(cred.accessKeySecret, keyTime), stringToSign)
+	return headerList, paramList, signature
+}
+
+func cosKeyTime(now time.Time, expiry time.Duration) string {
+	return fmt.Sprintf("%d;%d", now.Unix(), now.Add(expiry).Unix())
+}
+
+// signCOSRequest signs the request by the signature of COS in the header.
+func signCOSRequest(req *http.Request, _, _ string, cred *objectCredentials, now time.Time) {
+	keyTime := cosKeyTime(now, cosSignatureExpiry)
+	headerList, paramList, signature := cosSignature(req, cred, keyTime)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"q-sign-algorithm=sha1&q-ak=%s&q-sign-time=%s&q-key-time=%s"+
+			"&q-header-list=%s&q-url-param-list=%s&q-signature=%s",
+		cred.accessKeyID, keyTime, keyTime, headerList, paramList, signature))
+}
+
+// presignCOSRequest signs the request by the signature of COS in the query.
+func presignCOSRequest(
+	req *http.Request, _, _ string, cred *objectCredentials, now time.Time, expiry time.Duration,
+) {
+	keyTime := cosKeyTime(now, expiry)
+	headerList, paramList, signature := cosSignature(req, cred, keyTime)
+	query := req.URL.Query()
+	query.Set("q-sign-algorithm", "sha1")
+	query.Set("q-ak", cred.accessKeyID)
+	query.Set("q-sign-time", keyTime)
+	query.Set("q-key-time", keyTime)
+	query.Set("q-header-list", headerList)
+	query.Set("q-url-param-list", paramList)
+	query.Set("q-signature", signature)
+	if cred.securityToken != "" {
+		query.Set("x-cos-security-token", cred.securityToken)
+	}
+	req.URL.RawQuery = query.Encode()
+}
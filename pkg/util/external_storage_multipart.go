@@ -0,0 +1,132 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// minMultipartUploadPartSize is the min size of the parts except the last one
+// of a S3 multi-part upload.
+const minMultipartUploadPartSize = 5 * 1024 * 1024
+
+// WriteFileConcurrently writes a complete file to the storage like WriteFile.
+// If the storage is S3 and the concurrency is greater than 1, the file is split
+// into at most concurrency parts, which are uploaded concurrently by a S3
+// multi-part upload. Files smaller than two parts are always written by
+// WriteFile.
+func WriteFileConcurrently(
+	ctx context.Context, extStorage storage.ExternalStorage,
+	name string, data []byte, concurrency int,
+) error {
+	if s, ok := extStorage.(*extStorageWithTimeout); ok {
+		timeoutCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+		return WriteFileConcurrently(timeoutCtx, s.ExternalStorage, name, data, concurrency)
+	}
+
+	s3Storage, ok := extStorage.(*storage.S3Storage)
+	if !ok || concurrency <= 1 || len(data) < 2*minMultipartUploadPartSize {
+		return extStorage.WriteFile(ctx, name, data)
+	}
+	return multipartUpload(ctx, s3Storage, name, data, concurrency)
+}
+
+func multipartUpload(
+	ctx context.Context, s *storage.S3Storage,
+	name string, data []byte, concurrency int,
+) error {
+	svc := s.GetS3APIHandle()
+	options := s.GetOptions()
+	bucket := aws.String(options.Bucket)
+	key := aws.String(options.Prefix + name)
+
+	input := &s3.CreateMultipartUploadInput{Bucket: bucket, Key: key}
+	if options.Acl != "" {
+		input = input.SetACL(options.Acl)
+	}
+	if options.Sse != "" {
+		input = input.SetServerSideEncryption(options.Sse)
+	}
+	if options.SseKmsKeyId != "" {
+		input = input.SetSSEKMSKeyId(options.SseKmsKeyId)
+	}
+	if options.StorageClass != "" {
+		input = input.SetStorageClass(options.StorageClass)
+	}
+	upload, err := svc.CreateMultipartUploadWithContext(ctx, input)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	partSize := (len(data) + concurrency - 1) / concurrency
+	if partSize < minMultipartUploadPartSize {
+		partSize = minMultipartUploadPartSize
+	}
+	parts := make([]*s3.CompletedPart, (len(data)+partSize-1)/partSize)
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := range parts {
+		i := i
+		start, end := i*partSize, (i+1)*partSize
+		if end > len(data) {
+			end = len(data)
+		}
+		eg.Go(func() error {
+			partNumber := aws.Int64(int64(i + 1))
+			output, err := svc.UploadPartWithContext(egCtx, &s3.UploadPartInput{
+				Body:          bytes.NewReader(data[start:end]),
+				Bucket:        bucket,
+				Key:           key,
+				PartNumber:    partNumber,
+				UploadId:      upload.UploadId,
+				ContentLength: aws.Int64(int64(end - start)),
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			parts[i] = &s3.CompletedPart{ETag: output.ETag, PartNumber: partNumber}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		// Abort the upload, otherwise the uploaded parts are kept and charged.
+		_, abortErr := svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   bucket,
+			Key:      key,
+			UploadId: upload.UploadId,
+		})
+		if abortErr != nil {
+			log.Warn("failed to abort the multi-part upload",
+				zap.String("name", name), zap.Error(abortErr))
+		}
+		return err
+	}
+
+	_, err = svc.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          bucket,
+		Key:             key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return errors.Trace(err)
+}
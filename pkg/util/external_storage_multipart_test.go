@@ -0,0 +1,153 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// mockS3API records the objects written by PutObject and multi-part uploads.
+type mockS3API struct {
+	s3iface.S3API
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[int64][]byte
+	aborted bool
+	failOn  int64
+}
+
+func (m *mockS3API) PutObjectWithContext(
+	_ aws.Context, input *s3.PutObjectInput, _ ...request.Option,
+) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[*input.Key] = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *mockS3API) WaitUntilObjectExistsWithContext(
+	_ aws.Context, _ *s3.HeadObjectInput, _ ...request.WaiterOption,
+) error {
+	return nil
+}
+
+func (m *mockS3API) CreateMultipartUploadWithContext(
+	_ aws.Context, _ *s3.CreateMultipartUploadInput, _ ...request.Option,
+) (*s3.CreateMultipartUploadOutput, error) {
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (m *mockS3API) UploadPartWithContext(
+	_ aws.Context, input *s3.UploadPartInput, _ ...request.Option,
+) (*s3.UploadPartOutput, error) {
+	if *input.PartNumber == m.failOn {
+		return nil, errors.New("upload part failed")
+	}
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parts[*input.PartNumber] = data
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprint(*input.PartNumber))}, nil
+}
+
+func (m *mockS3API) CompleteMultipartUploadWithContext(
+	_ aws.Context, input *s3.CompleteMultipartUploadInput, _ ...request.Option,
+) (*s3.CompleteMultipartUploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var data []byte
+	for i, part := range input.MultipartUpload.Parts {
+		if *part.PartNumber != int64(i+1) {
+			return nil, errors.New("parts are out of order")
+		}
+		data = append(data, m.parts[*part.PartNumber]...)
+	}
+	m.objects[*input.Key] = data
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (m *mockS3API) AbortMultipartUploadWithContext(
+	_ aws.Context, _ *s3.AbortMultipartUploadInput, _ ...request.Option,
+) (*s3.AbortMultipartUploadOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestWriteFileConcurrently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := &mockS3API{objects: map[string][]byte{}, parts: map[int64][]byte{}}
+	extStorage := &extStorageWithTimeout{
+		ExternalStorage: storage.NewS3StorageForTest(svc,
+			&backuppb.S3{Bucket: "bucket", Prefix: "redo/"}),
+		timeout: time.Minute,
+	}
+
+	// Small files are written by a single request.
+	small := bytes.Repeat([]byte{'a'}, minMultipartUploadPartSize)
+	require.NoError(t, WriteFileConcurrently(ctx, extStorage, "small", small, 4))
+	require.Equal(t, small, svc.objects["redo/small"])
+	require.Len(t, svc.parts, 0)
+
+	// Large files are split into parts of at least the min part size.
+	large := make([]byte, 3*minMultipartUploadPartSize+1)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	require.NoError(t, WriteFileConcurrently(ctx, extStorage, "large", large, 8))
+	require.Equal(t, large, svc.objects["redo/large"])
+	require.Len(t, svc.parts, 4)
+
+	// The upload is aborted if any part fails.
+	svc.parts = map[int64][]byte{}
+	svc.failOn = 2
+	err := WriteFileConcurrently(ctx, extStorage, "failed", large, 2)
+	require.ErrorContains(t, err, "upload part failed")
+	require.True(t, svc.aborted)
+	require.NotContains(t, svc.objects, "redo/failed")
+
+	// Other storages always use WriteFile.
+	localStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, WriteFileConcurrently(ctx, localStorage, "large", large, 8))
+	data, err := localStorage.ReadFile(ctx, "large")
+	require.NoError(t, err)
+	require.Equal(t, large, data)
+}
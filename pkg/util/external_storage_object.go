@@ -0,0 +1,638 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security/secret"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// objectStorageEndpointKey is the parameter of the OSS and COS storage
+	// URIs, it's the endpoint of the service without the bucket, e.g.
+	// https://oss-cn-hangzhou.aliyuncs.com.
+	objectStorageEndpointKey = "endpoint"
+	// objectStorageRegionKey is the parameter of the OSS and COS storage URIs,
+	// the default endpoint of the region is used if the endpoint isn't set.
+	objectStorageRegionKey = "region"
+	// objectStorageAccessKeyKey and objectStorageSecretAccessKeyKey are the
+	// parameters of the access key, they can be secret references.
+	objectStorageAccessKeyKey       = "access-key"
+	objectStorageSecretAccessKeyKey = "secret-access-key"
+	// objectStorageSecurityTokenKey is the parameter of the STS token of the
+	// temporary access key, it can be a secret reference, so a rotated token
+	// is used by the following requests.
+	objectStorageSecurityTokenKey = "security-token"
+	// objectStorageRoleNameKey is the parameter of the role attached to the
+	// instance, the STS temporary access key of the role is fetched from the
+	// instance metadata service and refreshed before it expires.
+	objectStorageRoleNameKey = "role-name"
+
+	// objectStoragePartSize is the size of the parts of a multipart upload,
+	// the smaller files are uploaded by a single request.
+	objectStoragePartSize = 5 * 1024 * 1024
+	// objectStorageListMaxKeys is the max number of objects listed by a request.
+	objectStorageListMaxKeys = 1000
+	// objectCredentialsRefreshAhead is how long before the expiration the
+	// temporary access key is refreshed.
+	objectCredentialsRefreshAhead = 5 * time.Minute
+)
+
+// objectCredentials is the access key of an object storage, the security
+// token and the expiration are only set for a temporary access key.
+type objectCredentials struct {
+	accessKeyID     string
+	accessKeySecret string
+	securityToken   string
+	expiration      time.Time
+}
+
+// objectCredentialsProvider provides the access key of every request.
+type objectCredentialsProvider interface {
+	retrieve(ctx context.Context) (*objectCredentials, error)
+}
+
+// staticObjectCredentials is the access key in the storage URI or the
+// environment variables.
+type staticObjectCredentials struct {
+	accessKeyID     string
+	accessKeySecret string
+	securityToken   string
+}
+
+func (c *staticObjectCredentials) retrieve(_ context.Context) (*objectCredentials, error) {
+	cred := &objectCredentials{}
+	for _, pair := range []struct {
+		dst   *string
+		value string
+	}{
+		{&cred.accessKeyID, c.accessKeyID},
+		{&cred.accessKeySecret, c.accessKeySecret},
+		{&cred.securityToken, c.securityToken},
+	} {
+		value, err := secret.Resolve(pair.value)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+		*pair.dst = value
+	}
+	return cred, nil
+}
+
+// roleObjectCredentials is the temporary access key of the role attached to
+// the instance, it's cached until it's about to expire.
+type roleObjectCredentials struct {
+	fetch func(ctx context.Context) (*objectCredentials, error)
+
+	mu     sync.Mutex
+	cached *objectCredentials
+}
+
+func (c *roleObjectCredentials) retrieve(ctx context.Context) (*objectCredentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached != nil && time.Until(c.cached.expiration) > objectCredentialsRefreshAhead {
+		return c.cached, nil
+	}
+	cred, err := c.fetch(ctx)
+	if err != nil {
+		// the cached access key is still usable before it expires.
+		if c.cached != nil && time.Now().Before(c.cached.expiration) {
+			log.Warn("refresh the temporary access key of the object storage failed",
+				zap.Time("expiration", c.cached.expiration), zap.Error(err))
+			return c.cached, nil
+		}
+		return nil, err
+	}
+	c.cached = cred
+	return cred, nil
+}
+
+// fetchRoleCredentials gets the temporary access key of the role from the
+// instance metadata service.
+func fetchRoleCredentials(ctx context.Context, client *http.Client, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return errors.ErrExternalStorageAPI.GenWithStack(
+			"fetch the temporary access key from %s failed with status %d: %s",
+			endpoint, resp.StatusCode, string(data))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return nil
+}
+
+// objectStorageFlavor is what differs between the object storages, whose
+// APIs are otherwise similar to S3.
+type objectStorageFlavor struct {
+	name string
+	// headerPrefix is the prefix of the headers specific to the storage.
+	headerPrefix string
+	// accessKeyEnvs are the environment variables of the access key id, the
+	// access key secret and the security token.
+	accessKeyEnvs [3]string
+	// regionEndpoint returns the default endpoint of the region.
+	regionEndpoint func(region string) string
+	// fetchRoleCredentials gets the temporary access key of the role.
+	fetchRoleCredentials func(ctx context.Context, client *http.Client, role string) (*objectCredentials, error)
+	// sign adds the authorization of the request.
+	sign func(req *http.Request, bucket, key string, cred *objectCredentials, now time.Time)
+	// presign adds the authorization of the request to its query, which is
+	// valid for the expiry.
+	presign func(req *http.Request, bucket, key string, cred *objectCredentials, now time.Time, expiry time.Duration)
+	// copySource returns the value of the copy source header of the object.
+	copySource func(s *objectStorage, key string) string
+}
+
+var objectStorageFlavors = map[string]*objectStorageFlavor{
+	sink.OSSScheme: ossFlavor,
+	sink.COSScheme: cosFlavor,
+}
+
+// objectStorage is the object storage which is accessed by its native API,
+// since the S3 compatible APIs of OSS and COS differ from S3 in the multipart
+// upload and the temporary access keys.
+type objectStorage struct {
+	scheme      string
+	flavor      *objectStorageFlavor
+	bucket      string
+	prefix      string
+	endpoint    *url.URL
+	credentials objectCredentialsProvider
+	client      *http.Client
+}
+
+// isObjectStorageURI returns true if the storage URI is accessed by the native
+// API of the object storage.
+func isObjectStorageURI(uri string) bool {
+	scheme, _, ok := strings.Cut(uri, "://")
+	return ok && sink.IsObjectStorageScheme(strings.ToLower(scheme))
+}
+
+func newObjectStorage(uri string) (*objectStorage, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	flavor, ok := objectStorageFlavors[scheme]
+	if !ok {
+		return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"the object storage %s is not supported", scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"the bucket is empty in the %s storage URI", flavor.name)
+	}
+	query := u.Query()
+	endpoint := query.Get(objectStorageEndpointKey)
+	if endpoint == "" {
+		region := query.Get(objectStorageRegionKey)
+		if region == "" {
+			return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+				"either %s or %s must be set in the %s storage URI",
+				objectStorageEndpointKey, objectStorageRegionKey, flavor.name)
+		}
+		endpoint = flavor.regionEndpoint(region)
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+
+	s := &objectStorage{
+		scheme: scheme,
+		flavor: flavor,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		// the bucket is accessed by the virtual hosted style.
+		endpoint: &url.URL{Scheme: endpointURL.Scheme, Host: u.Host + "." + endpointURL.Host},
+		client:   &http.Client{},
+	}
+	if role := query.Get(objectStorageRoleNameKey); role != "" {
+		s.credentials = &roleObjectCredentials{
+			fetch: func(ctx context.Context) (*objectCredentials, error) {
+				return flavor.fetchRoleCredentials(ctx, s.client, role)
+			},
+		}
+	} else if accessKey := query.Get(objectStorageAccessKeyKey); accessKey != "" {
+		s.credentials = &staticObjectCredentials{
+			accessKeyID:     accessKey,
+			accessKeySecret: query.Get(objectStorageSecretAccessKeyKey),
+			securityToken:   query.Get(objectStorageSecurityTokenKey),
+		}
+	} else if accessKey := os.Getenv(flavor.accessKeyEnvs[0]); accessKey != "" {
+		s.credentials = &staticObjectCredentials{
+			accessKeyID:     accessKey,
+			accessKeySecret: os.Getenv(flavor.accessKeyEnvs[1]),
+			securityToken:   os.Getenv(flavor.accessKeyEnvs[2]),
+		}
+	} else {
+		return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"neither %s nor %s is set in the %s storage URI, and %s is empty",
+			objectStorageAccessKeyKey, objectStorageRoleNameKey, flavor.name, flavor.accessKeyEnvs[0])
+	}
+	return s, nil
+}
+
+func (s *objectStorage) key(name string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, name), "/")
+}
+
+// encodeObjectQuery encodes the query, the sub-resources without values, such
+// as "uploads", are encoded without "=".
+func encodeObjectQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var buf strings.Builder
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(url.QueryEscape(key))
+			if value != "" {
+				buf.WriteByte('=')
+				buf.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+	return buf.String()
+}
+
+// do sends the signed request of the object, the response is closed if an
+// error is returned.
+func (s *objectStorage) do(
+	ctx context.Context, method, key string, query url.Values, header http.Header, body []byte,
+) (*http.Response, error) {
+	cred, err := s.credentials.retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u := *s.endpoint
+	u.Path = "/" + key
+	u.RawQuery = encodeObjectQuery(query)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	now := time.Now().UTC()
+	req.Header.Set("Date", now.Format(http.TimeFormat))
+	if cred.securityToken != "" {
+		req.Header.Set(s.flavor.headerPrefix+"security-token", cred.securityToken)
+	}
+	s.flavor.sign(req, s.bucket, key, cred, now)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		return nil, s.responseError(resp, method, key)
+	}
+	return resp, nil
+}
+
+// objectErrorResponse is the error returned by the object storage.
+type objectErrorResponse struct {
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+	RequestID string `xml:"RequestId"`
+}
+
+// responseError converts the failed response to an error, the error of an
+// object which doesn't exist is recognized by IsNotExistInExtStorage.
+func (s *objectStorage) responseError(resp *http.Response, method, key string) error {
+	data, _ := io.ReadAll(resp.Body)
+	var errResp objectErrorResponse
+	_ = xml.Unmarshal(data, &errResp)
+	if resp.StatusCode == http.StatusNotFound ||
+		errResp.Code == "NoSuchKey" || errResp.Code == "NoSuchBucket" {
+		return errors.WrapError(errors.ErrExternalStorageAPI,
+			&os.PathError{Op: method, Path: key, Err: os.ErrNotExist})
+	}
+	return errors.ErrExternalStorageAPI.GenWithStack(
+		"%s %s %s failed with status %d, code %s, request id %s: %s",
+		s.flavor.name, method, key, resp.StatusCode, errResp.Code, errResp.RequestID, errResp.Message)
+}
+
+func decodeObjectResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return nil
+}
+
+// WriteFile writes a complete file to storage.
+func (s *objectStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.key(name), nil, nil, data)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// ReadFile reads a complete file from storage.
+func (s *objectStorage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.key(name), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return data, nil
+}
+
+// FileExists return true if file exists.
+func (s *objectStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.key(name), nil, nil, nil)
+	if err != nil {
+		if IsNotExistInExtStorage(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_ = resp.Body.Close()
+	return true, nil
+}
+
+// DeleteFile delete the file in storage.
+func (s *objectStorage) DeleteFile(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.key(name), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// Open a Reader by file path, the whole file is read into the memory.
+func (s *objectStorage) Open(ctx context.Context, name string) (storage.ExternalFileReader, error) {
+	data, err := s.ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &bytesFileReader{Reader: bytes.NewReader(data)}, nil
+}
+
+type objectListResult struct {
+	IsTruncated bool   `xml:"IsTruncated"`
+	NextMarker  string `xml:"NextMarker"`
+	Contents    []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// WalkDir traverse all the files in a dir.
+func (s *objectStorage) WalkDir(
+	ctx context.Context, opt *storage.WalkOption, fn func(path string, size int64) error,
+) error {
+	if opt == nil {
+		opt = &storage.WalkOption{}
+	}
+	prefix := path.Join(s.prefix, opt.SubDir)
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	prefix += opt.ObjPrefix
+
+	marker := ""
+	for {
+		query := url.Values{
+			"prefix":   []string{prefix},
+			"max-keys": []string{strconv.Itoa(objectStorageListMaxKeys)},
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return err
+		}
+		var result objectListResult
+		if err := decodeObjectResponse(resp, &result); err != nil {
+			return err
+		}
+		for _, content := range result.Contents {
+			name := strings.TrimPrefix(content.Key, s.prefix)
+			if err := fn(strings.TrimPrefix(name, "/"), content.Size); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if !result.IsTruncated || len(result.Contents) == 0 {
+			return nil
+		}
+		marker = result.NextMarker
+		if marker == "" {
+			marker = result.Contents[len(result.Contents)-1].Key
+		}
+	}
+}
+
+// URI returns the base path as a URI, the access key isn't included.
+func (s *objectStorage) URI() string {
+	return s.scheme + "://" + s.bucket + "/" + s.prefix
+}
+
+// presignGetURL returns the URL of the file signed in the query, so the file
+// can be got without the access key until the URL expires. The URL signed by a
+// temporary access key expires when the access key expires.
+func (s *objectStorage) presignGetURL(
+	ctx context.Context, name string, expiry time.Duration,
+) (string, error) {
+	cred, err := s.credentials.retrieve(ctx)
+	if err != nil {
+		return "", err
+	}
+	u := *s.endpoint
+	u.Path = "/" + s.key(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	s.flavor.presign(req, s.bucket, s.key(name), cred, time.Now().UTC(), expiry)
+	return req.URL.String(), nil
+}
+
+// Create opens a file writer by path, the file is uploaded by parts if it's
+// larger than a part.
+func (s *objectStorage) Create(_ context.Context, name string) (storage.ExternalFileWriter, error) {
+	return &objectFileWriter{storage: s, key: s.key(name)}, nil
+}
+
+// Rename file name from oldFileName to newFileName by copying the object on
+// the server side.
+func (s *objectStorage) Rename(ctx context.Context, oldFileName, newFileName string) error {
+	header := http.Header{}
+	header.Set(s.flavor.headerPrefix+"copy-source", s.flavor.copySource(s, s.key(oldFileName)))
+	resp, err := s.do(ctx, http.MethodPut, s.key(newFileName), nil, header, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return s.DeleteFile(ctx, oldFileName)
+}
+
+type objectPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type objectCompleteMultipartUpload struct {
+	XMLName xml.Name     `xml:"CompleteMultipartUpload"`
+	Parts   []objectPart `xml:"Part"`
+}
+
+// objectFileWriter uploads the file by a multipart upload once the written
+// data exceeds a part, otherwise the file is uploaded when it's closed.
+type objectFileWriter struct {
+	storage  *objectStorage
+	key      string
+	buf      bytes.Buffer
+	uploadID string
+	parts    []objectPart
+}
+
+// Write writes to the buffer and uploads the filled parts.
+func (w *objectFileWriter) Write(ctx context.Context, p []byte) (int, error) {
+	n, _ := w.buf.Write(p)
+	for w.buf.Len() >= objectStoragePartSize {
+		if err := w.uploadPart(ctx, w.buf.Next(objectStoragePartSize)); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (w *objectFileWriter) uploadPart(ctx context.Context, data []byte) error {
+	if w.uploadID == "" {
+		resp, err := w.storage.do(ctx, http.MethodPost, w.key,
+			url.Values{"uploads": []string{""}}, nil, nil)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			UploadID string `xml:"UploadId"`
+		}
+		if err := decodeObjectResponse(resp, &result); err != nil {
+			return err
+		}
+		w.uploadID = result.UploadID
+	}
+	partNumber := len(w.parts) + 1
+	resp, err := w.storage.do(ctx, http.MethodPut, w.key, url.Values{
+		"partNumber": []string{strconv.Itoa(partNumber)},
+		"uploadId":   []string{w.uploadID},
+	}, nil, data)
+	if err != nil {
+		w.abort(ctx)
+		return err
+	}
+	_ = resp.Body.Close()
+	w.parts = append(w.parts, objectPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+	return nil
+}
+
+// abort aborts the multipart upload, so the uploaded parts are removed.
+func (w *objectFileWriter) abort(ctx context.Context) {
+	resp, err := w.storage.do(ctx, http.MethodDelete, w.key,
+		url.Values{"uploadId": []string{w.uploadID}}, nil, nil)
+	if err != nil {
+		log.Warn("abort the multipart upload failed",
+			zap.String("storage", w.storage.URI()),
+			zap.String("key", w.key),
+			zap.String("uploadID", w.uploadID),
+			zap.Error(err))
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// Close uploads the rest of the data and completes the multipart upload.
+func (w *objectFileWriter) Close(ctx context.Context) error {
+	if w.uploadID == "" {
+		resp, err := w.storage.do(ctx, http.MethodPut, w.key, nil, nil, w.buf.Bytes())
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return nil
+	}
+	if w.buf.Len() > 0 {
+		if err := w.uploadPart(ctx, w.buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	body, err := xml.Marshal(&objectCompleteMultipartUpload{Parts: w.parts})
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	resp, err := w.storage.do(ctx, http.MethodPost, w.key,
+		url.Values{"uploadId": []string{w.uploadID}}, nil, body)
+	if err != nil {
+		w.abort(ctx)
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
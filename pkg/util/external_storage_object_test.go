@@ -0,0 +1,428 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectServer stores the objects of a bucket in the memory, the objects
+// are listed by pages of two objects.
+type fakeObjectServer struct {
+	mu           sync.Mutex
+	bucket       string
+	headerPrefix string
+	// authorization is the prefix of the expected authorization.
+	authorization string
+	token         string
+	objects       map[string][]byte
+	uploads       map[string]map[int][]byte
+	// multipartUploads is the number of the completed multipart uploads.
+	multipartUploads int
+}
+
+func (s *fakeObjectServer) writeError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (s *fakeObjectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(r.Host, s.bucket+".") {
+		s.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if !strings.Contains(r.Header.Get("Authorization"), s.authorization) ||
+		r.Header.Get(s.headerPrefix+"security-token") != s.token {
+		s.writeError(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		var keys []string
+		for k := range s.objects {
+			if strings.HasPrefix(k, query.Get("prefix")) && k > query.Get("marker") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		truncated := len(keys) > 2
+		if truncated {
+			keys = keys[:2]
+		}
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "<ListBucketResult><IsTruncated>%t</IsTruncated>", truncated)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "<Contents><Key>%s</Key><Size>%d</Size></Contents>", k, len(s.objects[k]))
+		}
+		buf.WriteString("</ListBucketResult>")
+		_, _ = w.Write(buf.Bytes())
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID := fmt.Sprintf("upload-%d", len(s.uploads))
+		s.uploads[uploadID] = make(map[int][]byte)
+		_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		var partNumber int
+		_, _ = fmt.Sscan(query.Get("partNumber"), &partNumber)
+		s.uploads[query.Get("uploadId")][partNumber] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, partNumber))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete objectCompleteMultipartUpload
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			s.writeError(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+		parts := s.uploads[query.Get("uploadId")]
+		var data []byte
+		for i, part := range complete.Parts {
+			if part.PartNumber != i+1 || part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+				s.writeError(w, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			data = append(data, parts[part.PartNumber]...)
+		}
+		delete(s.uploads, query.Get("uploadId"))
+		s.objects[key] = data
+		s.multipartUploads++
+	case r.Method == http.MethodPut:
+		if source := r.Header.Get(s.headerPrefix + "copy-source"); source != "" {
+			// the source is the bucket or its host followed by the key.
+			source = source[strings.Index(source, s.bucket)+len(s.bucket):]
+			source, _ = url.PathUnescape(source[strings.Index(source, "/")+1:])
+			data, ok := s.objects[source]
+			if !ok {
+				s.writeError(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			s.objects[key] = data
+			return
+		}
+		s.objects[key] = body
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, http.StatusBadRequest, "InvalidRequest")
+	}
+}
+
+// newFakeObjectStorage creates the storage whose requests of all the virtual
+// hosts are sent to the fake server.
+func newFakeObjectStorage(t *testing.T, uri string, ts *httptest.Server) *objectStorage {
+	s, err := newObjectStorage(uri)
+	require.NoError(t, err)
+	addr := ts.Listener.Addr().String()
+	s.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	return s
+}
+
+func testObjectStorage(t *testing.T, s storage.ExternalStorage, server *fakeObjectServer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, s.WriteFile(ctx, "test/t1/1/CDC000001.csv", []byte("value")))
+	require.Equal(t, []byte("value"), server.objects["cdc/prefix/test/t1/1/CDC000001.csv"])
+	data, err := s.ReadFile(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), data)
+	exists, err := s.FileExists(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = s.FileExists(ctx, "test/t1/1/CDC000002.csv")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = s.ReadFile(ctx, "test/t1/1/CDC000002.csv")
+	require.True(t, IsNotExistInExtStorage(err))
+
+	// the small file is uploaded by a single request.
+	writer, err := s.Create(ctx, "test/t1/meta/schema_1_0000000001.json")
+	require.NoError(t, err)
+	_, err = writer.Write(ctx, []byte("schema"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close(ctx))
+	require.Equal(t, 0, server.multipartUploads)
+
+	// the large file is uploaded by parts.
+	large := bytes.Repeat([]byte("0123456789"), objectStoragePartSize/10*2+1)
+	writer, err = s.Create(ctx, "test/t2/1/CDC000001.csv")
+	require.NoError(t, err)
+	for i := 0; i < len(large); i += 1024 * 1024 {
+		end := i + 1024*1024
+		if end > len(large) {
+			end = len(large)
+		}
+		_, err = writer.Write(ctx, large[i:end])
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close(ctx))
+	require.Equal(t, 1, server.multipartUploads)
+	require.Empty(t, server.uploads)
+	data, err = s.ReadFile(ctx, "test/t2/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.Equal(t, large, data)
+
+	walk := func(opt *storage.WalkOption) map[string]int64 {
+		files := make(map[string]int64)
+		require.NoError(t, s.WalkDir(ctx, opt, func(path string, size int64) error {
+			files[path] = size
+			return nil
+		}))
+		return files
+	}
+	require.Equal(t, map[string]int64{
+		"test/t1/1/CDC000001.csv":               5,
+		"test/t1/meta/schema_1_0000000001.json": 6,
+		"test/t2/1/CDC000001.csv":               int64(len(large)),
+	}, walk(nil))
+	require.Equal(t, map[string]int64{
+		"test/t1/meta/schema_1_0000000001.json": 6,
+	}, walk(&storage.WalkOption{ObjPrefix: "test/t1/meta/schema_"}))
+	require.Equal(t, map[string]int64{
+		"test/t2/1/CDC000001.csv": int64(len(large)),
+	}, walk(&storage.WalkOption{SubDir: "test", ObjPrefix: "t2"}))
+	require.Empty(t, walk(&storage.WalkOption{SubDir: "other"}))
+
+	require.NoError(t, s.Rename(ctx, "test/t1/meta/schema_1_0000000001.json", "test/t1/1/CDC000001.csv"))
+	data, err = s.ReadFile(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.Equal(t, []byte("schema"), data)
+	exists, err = s.FileExists(ctx, "test/t1/meta/schema_1_0000000001.json")
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoError(t, s.DeleteFile(ctx, "test/t1/1/CDC000001.csv"))
+	exists, err = s.FileExists(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestOSSStorage(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeObjectServer{
+		bucket:        "bucket",
+		headerPrefix:  "x-oss-",
+		authorization: "OSS id:",
+		token:         "token",
+		objects:       make(map[string][]byte),
+		uploads:       make(map[string]map[int][]byte),
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	s := newFakeObjectStorage(t, "oss://bucket/cdc/prefix/?region=cn-hangzhou&"+
+		"access-key=id&secret-access-key=secret&security-token=token", ts)
+	require.Equal(t, "oss://bucket/cdc/prefix", s.URI())
+	require.Equal(t, "bucket.oss-cn-hangzhou.aliyuncs.com", s.endpoint.Host)
+	s.endpoint.Scheme = "http"
+	testObjectStorage(t, s, server)
+}
+
+func TestCOSStorage(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeObjectServer{
+		bucket:        "bucket-1250000000",
+		headerPrefix:  "x-cos-",
+		authorization: "q-ak=id&",
+		objects:       make(map[string][]byte),
+		uploads:       make(map[string]map[int][]byte),
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	s := newFakeObjectStorage(t, "cos://bucket-1250000000/cdc/prefix?"+
+		"endpoint=http://cos.ap-guangzhou.myqcloud.com&access-key=id&secret-access-key=secret", ts)
+	require.Equal(t, "cos://bucket-1250000000/cdc/prefix", s.URI())
+	require.Equal(t, "bucket-1250000000.cos.ap-guangzhou.myqcloud.com", s.endpoint.Host)
+	testObjectStorage(t, s, server)
+}
+
+func TestObjectStorageURI(t *testing.T) {
+	t.Parallel()
+
+	_, err := newObjectStorage("oss://bucket/prefix?access-key=id")
+	require.Regexp(t, ".*either endpoint or region must be set.*", err)
+	_, err = newObjectStorage("cos:///prefix?region=ap-guangzhou")
+	require.Regexp(t, ".*the bucket is empty.*", err)
+	_, err = newObjectStorage("cos://bucket/prefix?region=ap-guangzhou")
+	require.Regexp(t, ".*neither access-key nor role-name is set.*", err)
+}
+
+func TestSignOSSRequest(t *testing.T) {
+	t.Parallel()
+
+	// the example of the V1 signature in the document of OSS.
+	req, err := http.NewRequest(http.MethodPut, "https://oss-example.oss-cn-hangzhou.aliyuncs.com/nelson", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-MD5", "ODBGOERFMDMzQTczRUY3NUE3NzA5QzdFNUYzMDQxNEM=")
+	req.Header.Set("Content-Type", "text/html")
+	req.Header.Set("Date", "Thu, 17 Nov 2005 18:49:58 GMT")
+	req.Header.Set("X-OSS-Meta-Author", "foo@bar.com")
+	req.Header.Set("X-OSS-Magic", "abracadabra")
+	signOSSRequest(req, "oss-example", "nelson", &objectCredentials{
+		accessKeyID:     "44CF9590006BF252F707",
+		accessKeySecret: "OtxrzxIsfpFjA7SwPzILwy8Bw21TLhquhboDYROV",
+	}, time.Time{})
+	require.Equal(t, "OSS 44CF9590006BF252F707:26NBxoKdsyly4EDv6inkoDft/yA=", req.Header.Get("Authorization"))
+}
+
+func TestRoleObjectCredentials(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		fetched  int
+		failed   bool
+		lifetime = time.Hour
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fetched++
+		require.Equal(t, "/role", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Code":         "Success",
+			"TmpSecretId":  fmt.Sprintf("id-%d", fetched),
+			"TmpSecretKey": "secret",
+			"Token":        "token",
+			"ExpiredTime":  time.Now().Add(lifetime).Unix(),
+		})
+	}))
+	defer ts.Close()
+
+	origin := cosMetadataEndpoint
+	cosMetadataEndpoint = ts.URL + "/"
+	defer func() { cosMetadataEndpoint = origin }()
+
+	ctx := context.Background()
+	cred := &roleObjectCredentials{fetch: func(ctx context.Context) (*objectCredentials, error) {
+		return fetchCOSRoleCredentials(ctx, http.DefaultClient, "role")
+	}}
+	c, err := cred.retrieve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "id-1", c.accessKeyID)
+	require.Equal(t, "token", c.securityToken)
+	// the cached access key is used until it's about to expire.
+	c, err = cred.retrieve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "id-1", c.accessKeyID)
+
+	cred.cached.expiration = time.Now().Add(time.Minute)
+	c, err = cred.retrieve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "id-2", c.accessKeyID)
+
+	// the cached access key is used if it fails to be refreshed.
+	mu.Lock()
+	failed = true
+	mu.Unlock()
+	cred.cached.expiration = time.Now().Add(time.Minute)
+	c, err = cred.retrieve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "id-2", c.accessKeyID)
+	cred.cached.expiration = time.Now().Add(-time.Minute)
+	_, err = cred.retrieve(ctx)
+	require.Error(t, err)
+}
+
+func TestPresignGetURL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	expiry := 10 * time.Minute
+	for _, tc := range []struct {
+		uri    string
+		params []string
+	}{
+		{
+			uri:    "oss://bucket/prefix?endpoint=https://oss-cn-hangzhou.aliyuncs.com&access-key=ak&secret-access-key=sk&security-token=token",
+			params: []string{"OSSAccessKeyId", "Expires", "Signature", "security-token"},
+		},
+		{
+			uri: "cos://bucket/prefix?region=ap-shanghai&access-key=ak&secret-access-key=sk&security-token=token",
+			params: []string{
+				"q-sign-algorithm", "q-ak", "q-sign-time", "q-key-time",
+				"q-header-list", "q-url-param-list", "q-signature", "x-cos-security-token",
+			},
+		},
+	} {
+		s, err := newObjectStorage(tc.uri)
+		require.NoError(t, err)
+		require.True(t, IsPresignSupported(s))
+		rawURL, err := PresignGetURL(ctx, s, "2023-01-01/a.json", expiry)
+		require.NoError(t, err)
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		require.Equal(t, s.endpoint.Host, u.Host)
+		require.Equal(t, "/prefix/2023-01-01/a.json", u.Path)
+		for _, param := range tc.params {
+			require.True(t, u.Query().Has(param), param)
+		}
+	}
+
+	// the signature in the query is the same as the signature in the header.
+	req, err := http.NewRequest(http.MethodGet, "https://bucket.oss-cn-hangzhou.aliyuncs.com/key", nil)
+	require.NoError(t, err)
+	cred := &objectCredentials{accessKeyID: "ak", accessKeySecret: "sk"}
+	now := time.Unix(1700000000, 0)
+	presignOSSRequest(req, "bucket", "key", cred, now, expiry)
+	expires := req.URL.Query().Get("Expires")
+	require.Equal(t, "1700000600", expires)
+	headerReq, err := http.NewRequest(http.MethodGet, "https://bucket.oss-cn-hangzhou.aliyuncs.com/key", nil)
+	require.NoError(t, err)
+	headerReq.Header.Set("Date", expires)
+	signOSSRequest(headerReq, "bucket", "key", cred, now)
+	require.Equal(t, "OSS ak:"+req.URL.Query().Get("Signature"), headerReq.Header.Get("Authorization"))
+
+	local, err := GetExternalStorageFromURI(ctx, "file://"+t.TempDir())
+	require.NoError(t, err)
+	require.False(t, IsPresignSupported(local))
+	_, err = PresignGetURL(ctx, local, "a.json", expiry)
+	require.Error(t, err)
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ossMetadataEndpoint is the endpoint of the temporary access keys of the RAM
+// roles attached to the ECS instances.
+var ossMetadataEndpoint = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+
+// ossSubResources are the query parameters included in the signature of OSS.
+var ossSubResources = map[string]struct{}{
+	"uploads":        {},
+	"uploadId":       {},
+	"partNumber":     {},
+	"security-token": {},
+}
+
+var ossFlavor = &objectStorageFlavor{
+	name:         "OSS",
+	headerPrefix: "x-oss-",
+	accessKeyEnvs: [3]string{
+		"ALIBABA_CLOUD_ACCESS_KEY_ID",
+		"ALIBABA_CLOUD_ACCESS_KEY_SECRET",
+		"ALIBABA_CLOUD_SECURITY_TOKEN",
+	},
+	regionEndpoint: func(region string) string {
+		if !strings.HasPrefix(region, "oss-") {
+			region = "oss-" + region
+		}
+		return "https://" + region + ".aliyuncs.com"
+	},
+	fetchRoleCredentials: fetchOSSRoleCredentials,
+	sign:                 signOSSRequest,
+	presign:              presignOSSRequest,
+	copySource: func(s *objectStorage, key string) string {
+		return "/" + s.bucket + "/" + url.PathEscape(key)
+	},
+}
+
+func fetchOSSRoleCredentials(
+	ctx context.Context, client *http.Client, role string,
+) (*objectCredentials, error) {
+	var resp struct {
+		Code            string `json:"Code"`
+		AccessKeyID     string `json:"AccessKeyId"`
+		AccessKeySecret string `json:"AccessKeySecret"`
+		SecurityToken   string `json:"SecurityToken"`
+		Expiration      string `json:"Expiration"`
+	}
+	if err := fetchRoleCredentials(ctx, client, ossMetadataEndpoint+role, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "Success" {
+		return nil, errors.ErrExternalStorageAPI.GenWithStack(
+			"fetch the temporary access key of the RAM role %s failed: %s", role, resp.Code)
+	}
+	expiration, err := time.Parse(time.RFC3339, resp.Expiration)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return &objectCredentials{
+		accessKeyID:     resp.AccessKeyID,
+		accessKeySecret: resp.AccessKeySecret,
+		securityToken:   resp.SecurityToken,
+		expiration:      expiration,
+	}, nil
+}
+
+// ossCanonicalizedResource returns the resource of the request signed by OSS.
+func ossCanonicalizedResource(req *http.Request, bucket, key string) string {
+	resource := "/" + bucket + "/" + key
+	var subResources []string
+	for name, values := range req.URL.Query() {
+		if _, ok := ossSubResources[name]; !ok {
+			continue
+		}
+		if values[0] == "" {
+			subResources = append(subResources, name)
+		} else {
+			subResources = append(subResources, name+"="+values[0])
+		}
+	}
+	if len(subResources) > 0 {
+		sort.Strings(subResources)
+		resource += "?" + strings.Join(subResources, "&")
+	}
+	return resource
+}
+
+func hmacSHA1Base64(key, data string) string {
+	mac := hmac.New(sha1.New, []byte(key))
+	_, _ = mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signOSSRequest signs the request by the V1 signature of OSS.
+func signOSSRequest(req *http.Request, bucket, key string, cred *objectCredentials, _ time.Time) {
+	var headers []string
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-oss-") {
+			headers = append(headers, name+":"+strings.TrimSpace(values[0])+"\n")
+		}
+	}
+	sort.Strings(headers)
+
+	stringToSign := req.Method + "\n" +
+		req.Header.Get("Content-MD5") + "\n" +
+		req.Header.Get("Content-Type") + "\n" +
+		req.Header.Get("Date") + "\n" +
+		strings.Join(headers, "") + ossCanonicalizedResource(req, bucket, key)
+	req.Header.Set("Authorization",
+		"OSS "+cred.accessKeyID+":"+hmacSHA1Base64(cred.accessKeySecret, stringToSign))
+}
+
+// presignOSSRequest signs the request by the V1 signature of OSS in the query,
+// the security token is a sub-resource of the signed URL.
+func presignOSSRequest(
+	req *http.Request, bucket, key string, cred *objectCredentials, now time.Time, expiry time.Duration,
+) {
+	expires := strconv.FormatInt(now.Add(expiry).Unix(), 10)
+	query := req.URL.Query()
+	if cred.securityToken != "" {
+		query.Set("security-token", cred.securityToken)
+		req.URL.RawQuery = query.Encode()
+	}
+	stringToSign := req.Method + "\n\n\n" + expires + "\n" + ossCanonicalizedResource(req, bucket, key)
+	query.Set("OSSAccessKeyId", cred.accessKeyID)
+	query.Set("Expires", expires)
+	query.Set("Signature", hmacSHA1Base64(cred.accessKeySecret, stringToSign))
+	req.URL.RawQuery = query.Encode()
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+// IsPresignSupported returns true if the URLs of the files in the storage can
+// be presigned, only S3, OSS and COS are supported.
+func IsPresignSupported(extStorage storage.ExternalStorage) bool {
+	if s, ok := extStorage.(*extStorageWithTimeout); ok {
+		extStorage = s.ExternalStorage
+	}
+	switch extStorage.(type) {
+	case *storage.S3Storage, *objectStorage:
+		return true
+	default:
+		return false
+	}
+}
+
+// PresignGetURL returns a URL to get the file without the credentials of the
+// storage, the URL expires after the expiry. The URL signed by a temporary
+// access key can't outlive the access key.
+func PresignGetURL(
+	ctx context.Context, extStorage storage.ExternalStorage,
+	name string, expiry time.Duration,
+) (string, error) {
+	if s, ok := extStorage.(*extStorageWithTimeout); ok {
+		timeoutCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+		return PresignGetURL(timeoutCtx, s.ExternalStorage, name, expiry)
+	}
+
+	switch s := extStorage.(type) {
+	case *storage.S3Storage:
+		options := s.GetOptions()
+		req, _ := s.GetS3APIHandle().GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(options.Bucket),
+			Key:    aws.String(options.Prefix + name),
+		})
+		url, err := req.Presign(expiry)
+		if err != nil {
+			return "", errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+		return url, nil
+	case *objectStorage:
+		return s.presignGetURL(ctx, name, expiry)
+	default:
+		return "", errors.ErrExternalStorageAPI.GenWithStack(
+			"presigning the URL isn't supported by the storage %s", extStorage.URI())
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+)
+
+const (
+	// webhdfsUserKey is the parameter of the WebHDFS storage URI, the storage
+	// is accessed as the user by the simple authentication of Hadoop. The
+	// HADOOP_USER_NAME environment variable is used if it's not set.
+	webhdfsUserKey = "user"
+	// webhdfsPathPrefix is the path prefix of the WebHDFS REST API.
+	webhdfsPathPrefix = "/webhdfs/v1"
+	// webhdfsMaxRedirects is the max number of redirects of a request, the
+	// name node redirects the requests reading or writing a file to a data node.
+	webhdfsMaxRedirects = 3
+)
+
+// webhdfsStorage is the HDFS storage which is accessed by the WebHDFS REST API
+// of the name node, so neither the hadoop client nor an S3 gateway is required.
+// The port of the storage URI should be the http port of the name node, e.g.
+// webhdfs://namenode:9870/path?user=hdfs.
+type webhdfsStorage struct {
+	scheme   string
+	endpoint *url.URL
+	root     string
+	user     string
+	client   *http.Client
+}
+
+// isWebHDFSURI returns true if the storage URI is accessed by WebHDFS.
+func isWebHDFSURI(uri string) bool {
+	scheme, _, ok := strings.Cut(uri, "://")
+	return ok && sink.IsWebHDFSScheme(strings.ToLower(scheme))
+}
+
+func newWebHDFSStorage(uri string) (*webhdfsStorage, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFailToCreateExternalStorage, err)
+	}
+	if u.Host == "" {
+		return nil, errors.ErrFailToCreateExternalStorage.GenWithStack(
+			"the host of the name node is empty in the WebHDFS storage URI")
+	}
+	scheme := strings.ToLower(u.Scheme)
+	endpoint := &url.URL{Scheme: "http", Host: u.Host}
+	if scheme == sink.WebHDFSSSLScheme {
+		endpoint.Scheme = "https"
+	}
+	user := u.Query().Get(webhdfsUserKey)
+	if user == "" {
+		user = os.Getenv("HADOOP_USER_NAME")
+	}
+	return &webhdfsStorage{
+		scheme:   scheme,
+		endpoint: endpoint,
+		root:     path.Join("/", u.Path),
+		user:     user,
+		client: &http.Client{
+			// the redirects are followed by do, since the body of the request
+			// is only sent to the data node.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// webhdfsRemoteException is the error returned by the WebHDFS REST API.
+type webhdfsRemoteException struct {
+	RemoteException struct {
+		Exception string `json:"exception"`
+		Message   string `json:"message"`
+	} `json:"RemoteException"`
+}
+
+type webhdfsFileStatus struct {
+	PathSuffix string `json:"pathSuffix"`
+	Type       string `json:"type"`
+	Length     int64  `json:"length"`
+}
+
+type webhdfsBoolean struct {
+	Boolean bool `json:"boolean"`
+}
+
+func (s *webhdfsStorage) absPath(name string) string {
+	return path.Join(s.root, name)
+}
+
+// do sends the request of the operation on the file to the name node. The
+// request redirected to a data node is sent again with the body, so the body
+// isn't sent to the name node. The response is closed if an error is returned.
+func (s *webhdfsStorage) do(
+	ctx context.Context, method, name, op string, params url.Values, body []byte,
+) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = webhdfsPathPrefix + s.absPath(name)
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("op", op)
+	if s.user != "" {
+		query.Set("user.name", s.user)
+	}
+	u.RawQuery = query.Encode()
+
+	target := u.String()
+	for i := 0; ; i++ {
+		var reader io.Reader
+		if i > 0 && body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, target, reader)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+		switch {
+		case resp.StatusCode == http.StatusTemporaryRedirect && i < webhdfsMaxRedirects:
+			target = resp.Header.Get("Location")
+			_ = resp.Body.Close()
+		case resp.StatusCode >= http.StatusMultipleChoices:
+			defer resp.Body.Close()
+			return nil, webhdfsError(resp, op, name)
+		default:
+			return resp, nil
+		}
+	}
+}
+
+// webhdfsError converts the failed response to an error, the error of a file
+// which doesn't exist is recognized by IsNotExistInExtStorage.
+func webhdfsError(resp *http.Response, op, name string) error {
+	data, _ := io.ReadAll(resp.Body)
+	var remote webhdfsRemoteException
+	_ = json.Unmarshal(data, &remote)
+	if resp.StatusCode == http.StatusNotFound ||
+		remote.RemoteException.Exception == "FileNotFoundException" {
+		return errors.WrapError(errors.ErrExternalStorageAPI,
+			&os.PathError{Op: op, Path: name, Err: os.ErrNotExist})
+	}
+	message := remote.RemoteException.Message
+	if message == "" {
+		message = string(data)
+	}
+	return errors.ErrExternalStorageAPI.GenWithStack(
+		"WebHDFS %s %s failed with status %d: %s", op, name, resp.StatusCode, message)
+}
+
+func decodeWebHDFSResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return nil
+}
+
+// WriteFile writes a complete file to storage, the parent directories are
+// created if they don't exist.
+func (s *webhdfsStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, name, "CREATE",
+		url.Values{"overwrite": []string{"true"}}, data)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// ReadFile reads a complete file from storage.
+func (s *webhdfsStorage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, name, "OPEN", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return data, nil
+}
+
+// FileExists return true if file exists.
+func (s *webhdfsStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodGet, name, "GETFILESTATUS", nil, nil)
+	if err != nil {
+		if IsNotExistInExtStorage(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_ = resp.Body.Close()
+	return true, nil
+}
+
+// DeleteFile delete the file in storage.
+func (s *webhdfsStorage) DeleteFile(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, name, "DELETE", nil, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// Open a Reader by file path, the whole file is read into the memory.
+func (s *webhdfsStorage) Open(ctx context.Context, name string) (storage.ExternalFileReader, error) {
+	data, err := s.ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &bytesFileReader{Reader: bytes.NewReader(data)}, nil
+}
+
+// WalkDir traverse all the files in a dir. The directories are listed from the
+// deepest one containing the object prefix.
+func (s *webhdfsStorage) WalkDir(
+	ctx context.Context, opt *storage.WalkOption, fn func(path string, size int64) error,
+) error {
+	if opt == nil {
+		opt = &storage.WalkOption{}
+	}
+	prefix := opt.SubDir
+	if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	prefix += opt.ObjPrefix
+	dir := prefix[:strings.LastIndex(prefix, "/")+1]
+	return s.walkDir(ctx, strings.TrimSuffix(dir, "/"), prefix, fn)
+}
+
+func (s *webhdfsStorage) walkDir(
+	ctx context.Context, dir, prefix string, fn func(path string, size int64) error,
+) error {
+	resp, err := s.do(ctx, http.MethodGet, dir, "LISTSTATUS", nil, nil)
+	if err != nil {
+		if IsNotExistInExtStorage(err) {
+			return nil
+		}
+		return err
+	}
+	var list struct {
+		FileStatuses struct {
+			FileStatus []webhdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	if err := decodeWebHDFSResponse(resp, &list); err != nil {
+		return err
+	}
+	for _, status := range list.FileStatuses.FileStatus {
+		name := path.Join(dir, status.PathSuffix)
+		if status.Type == "DIRECTORY" {
+			if strings.HasPrefix(name+"/", prefix) || strings.HasPrefix(prefix, name+"/") {
+				if err := s.walkDir(ctx, name, prefix, fn); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(name, prefix) {
+			if err := fn(name, status.Length); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// URI returns the base path as a URI.
+func (s *webhdfsStorage) URI() string {
+	return s.scheme + "://" + s.endpoint.Host + s.root
+}
+
+// Create opens a file writer by path, the file is written when it's closed.
+func (s *webhdfsStorage) Create(_ context.Context, name string) (storage.ExternalFileWriter, error) {
+	return &bufferedFileWriter{storage: s, name: name}, nil
+}
+
+// Rename file name from oldFileName to newFileName, newFileName is replaced
+// if it exists.
+func (s *webhdfsStorage) Rename(ctx context.Context, oldFileName, newFileName string) error {
+	params := url.Values{"destination": []string{s.absPath(newFileName)}}
+	// HDFS doesn't rename a file to an existing one, so the existing one is
+	// deleted and the file is renamed again.
+	for i := 0; i < 2; i++ {
+		resp, err := s.do(ctx, http.MethodPut, oldFileName, "RENAME", params, nil)
+		if err != nil {
+			return err
+		}
+		var renamed webhdfsBoolean
+		if err := decodeWebHDFSResponse(resp, &renamed); err != nil {
+			return err
+		}
+		if renamed.Boolean {
+			return nil
+		}
+		if i == 0 {
+			if err := s.DeleteFile(ctx, newFileName); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.ErrExternalStorageAPI.GenWithStack(
+		"failed to rename %s to %s in WebHDFS", oldFileName, newFileName)
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebHDFSServer stores the files in the memory. The requests reading or
+// writing a file are redirected to itself as the data node, the data sent to
+// the name node is rejected.
+type fakeWebHDFSServer struct {
+	mu    sync.Mutex
+	user  string
+	files map[string][]byte
+}
+
+func (s *fakeWebHDFSServer) writeException(w http.ResponseWriter, status int, exception string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"RemoteException": map[string]string{"exception": exception, "message": exception},
+	})
+}
+
+func (s *fakeWebHDFSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := r.URL.Query()
+	if query.Get("user.name") != s.user {
+		s.writeException(w, http.StatusForbidden, "AccessControlException")
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/webhdfs/v1")
+	dataNode := query.Get("datanode") == "true"
+	if (query.Get("op") == "CREATE" || query.Get("op") == "OPEN") && !dataNode {
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			s.writeException(w, http.StatusBadRequest, "IllegalArgumentException")
+			return
+		}
+		query.Set("datanode", "true")
+		w.Header().Set("Location", "http://"+r.Host+r.URL.Path+"?"+query.Encode())
+		w.WriteHeader(http.StatusTemporaryRedirect)
+		return
+	}
+
+	switch query.Get("op") {
+	case "CREATE":
+		s.files[name], _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	case "OPEN":
+		data, ok := s.files[name]
+		if !ok {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		_, _ = w.Write(data)
+	case "GETFILESTATUS":
+		if _, ok := s.files[name]; !ok {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		_, _ = w.Write([]byte(`{"FileStatus":{"type":"FILE"}}`))
+	case "LISTSTATUS":
+		children := make(map[string]map[string]interface{})
+		for file, data := range s.files {
+			if !strings.HasPrefix(file, name+"/") {
+				continue
+			}
+			suffix, rest, isDir := strings.Cut(strings.TrimPrefix(file, name+"/"), "/")
+			if isDir && rest != "" {
+				children[suffix] = map[string]interface{}{"pathSuffix": suffix, "type": "DIRECTORY"}
+			} else {
+				children[suffix] = map[string]interface{}{
+					"pathSuffix": suffix, "type": "FILE", "length": len(data),
+				}
+			}
+		}
+		if len(children) == 0 {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		statuses := make([]map[string]interface{}, 0, len(children))
+		for _, status := range children {
+			statuses = append(statuses, status)
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i]["pathSuffix"].(string) < statuses[j]["pathSuffix"].(string)
+		})
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"FileStatuses": map[string]interface{}{"FileStatus": statuses},
+		})
+	case "DELETE":
+		_, ok := s.files[name]
+		delete(s.files, name)
+		_ = json.NewEncoder(w).Encode(map[string]bool{"boolean": ok})
+	case "RENAME":
+		data, ok := s.files[name]
+		destination := path.Clean(query.Get("destination"))
+		if _, exists := s.files[destination]; !ok || exists {
+			_ = json.NewEncoder(w).Encode(map[string]bool{"boolean": false})
+			return
+		}
+		delete(s.files, name)
+		s.files[destination] = data
+		_ = json.NewEncoder(w).Encode(map[string]bool{"boolean": true})
+	default:
+		s.writeException(w, http.StatusBadRequest, "UnsupportedOperationException")
+	}
+}
+
+func TestWebHDFSStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := &fakeWebHDFSServer{user: "hdfs", files: make(map[string][]byte)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	s, err := GetExternalStorageFromURI(ctx, "webhdfs://"+host+"/cdc/prefix?user=hdfs&protocol=csv")
+	require.NoError(t, err)
+	require.Equal(t, "webhdfs://"+host+"/cdc/prefix", s.URI())
+
+	require.NoError(t, s.WriteFile(ctx, "test/t1/1/CDC000001.csv", []byte("value")))
+	require.Equal(t, []byte("value"), server.files["/cdc/prefix/test/t1/1/CDC000001.csv"])
+	data, err := s.ReadFile(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), data)
+	exists, err := s.FileExists(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = s.FileExists(ctx, "test/t1/1/CDC000002.csv")
+	require.NoError(t, err)
+	require.False(t, exists)
+	_, err = s.ReadFile(ctx, "test/t1/1/CDC000002.csv")
+	require.True(t, IsNotExistInExtStorage(err))
+
+	writer, err := s.Create(ctx, "test/t1/meta/schema_1_0000000001.json")
+	require.NoError(t, err)
+	_, err = writer.Write(ctx, []byte("schema"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close(ctx))
+	require.NoError(t, s.WriteFile(ctx, "test/t2/1/CDC000001.csv", []byte("value2")))
+
+	walk := func(opt *storage.WalkOption) map[string]int64 {
+		files := make(map[string]int64)
+		require.NoError(t, s.WalkDir(ctx, opt, func(path string, size int64) error {
+			files[path] = size
+			return nil
+		}))
+		return files
+	}
+	require.Equal(t, map[string]int64{
+		"test/t1/1/CDC000001.csv":               5,
+		"test/t1/meta/schema_1_0000000001.json": 6,
+		"test/t2/1/CDC000001.csv":               6,
+	}, walk(nil))
+	require.Equal(t, map[string]int64{
+		"test/t1/meta/schema_1_0000000001.json": 6,
+	}, walk(&storage.WalkOption{ObjPrefix: "test/t1/meta/schema_"}))
+	require.Equal(t, map[string]int64{
+		"test/t2/1/CDC000001.csv": 6,
+	}, walk(&storage.WalkOption{SubDir: "test", ObjPrefix: "t2"}))
+	require.Empty(t, walk(&storage.WalkOption{SubDir: "other"}))
+
+	// the existing file is replaced by the renamed one.
+	require.NoError(t, s.Rename(ctx, "test/t2/1/CDC000001.csv", "test/t1/1/CDC000001.csv"))
+	data, err = s.ReadFile(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2"), data)
+	require.NoError(t, s.DeleteFile(ctx, "test/t1/1/CDC000001.csv"))
+	exists, err = s.FileExists(ctx, "test/t1/1/CDC000001.csv")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// the requests of other users are rejected.
+	_, err = GetExternalStorageFromURI(ctx, "hdfs://"+host+"/cdc/prefix?user=other")
+	require.Regexp(t, ".*AccessControlException.*", err)
+}
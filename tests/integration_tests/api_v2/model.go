@@ -208,6 +208,7 @@ type EventFilterRule struct {
 	IgnoreUpdateNewValueExpr string `json:"ignore_update_new_value_expr"`
 	IgnoreUpdateOldValueExpr string `json:"ignore_update_old_value_expr"`
 	IgnoreDeleteValueExpr    string `json:"ignore_delete_value_expr"`
+	KeepValueExpr            string `json:"keep_value_expr"`
 }
 
 // MySQLReplicationRules is a set of rules based on MySQL's replication tableFilter.
@@ -278,6 +279,10 @@ type ConsistentConfig struct {
 	FlushIntervalInMs int64  `json:"flush_interval"`
 	Storage           string `json:"storage"`
 	UseFileBackend    bool   `json:"use_file_backend"`
+	FlushWorkerNum    int    `json:"flush_worker_num"`
+	FlushConcurrency  int    `json:"flush_concurrency"`
+	Compression       string `json:"compression,omitempty"`
+	EncryptionKey     string `json:"encryption_key,omitempty"`
 }
 
 // ChangefeedSchedulerConfig is per changefeed scheduler settings.
@@ -37,7 +37,7 @@ groups=(
 	["G03"]='row_format drop_many_tables processor_stop_delay partition_table'
 	["G04"]='foreign_key ddl_puller_lag ddl_only_block_related_table changefeed_auto_stop'
 	["G05"]='charset_gbk ddl_manager multi_source'
-	["G06"]='sink_retry changefeed_error ddl_sequence resourcecontrol'
+	["G06"]='sink_retry sink_failpoint_api changefeed_error ddl_sequence resourcecontrol'
 	["G07"]='kv_client_stream_reconnect cdc split_region'
 	["G08"]='server_config_compatibility processor_err_chan changefeed_reconstruct multi_capture'
 	["G09"]='gc_safepoint changefeed_pause_resume cli savepoint'